@@ -349,6 +349,7 @@ Gitea or set your environment appropriately.`, "")
 	repoName := os.Getenv(models.EnvRepoName)
 	pusherID, _ := strconv.ParseInt(os.Getenv(models.EnvPusherID), 10, 64)
 	pusherName := os.Getenv(models.EnvPusherName)
+	isDeployKey, _ := strconv.ParseBool(os.Getenv(models.EnvIsDeployKey))
 
 	hookOptions := private.HookOptions{
 		UserName:                        pusherName,
@@ -357,6 +358,7 @@ Gitea or set your environment appropriately.`, "")
 		GitObjectDirectory:              os.Getenv(private.GitObjectDirectory),
 		GitQuarantinePath:               os.Getenv(private.GitQuarantinePath),
 		GitPushOptions:                  pushOptions(),
+		IsDeployKey:                     isDeployKey,
 	}
 	oldCommitIDs := make([]string, hookBatchSize)
 	newCommitIDs := make([]string, hookBatchSize)
@@ -383,7 +385,7 @@ Gitea or set your environment appropriately.`, "")
 		oldCommitIDs[count] = string(fields[0])
 		newCommitIDs[count] = string(fields[1])
 		refFullNames[count] = string(fields[2])
-		if refFullNames[count] == git.BranchPrefix+"master" && newCommitIDs[count] != git.EmptySHA && count == total {
+		if refFullNames[count] == git.BranchPrefix+"master" && !git.IsEmptyCommitID(newCommitIDs[count]) && count == total {
 			masterPushed = true
 		}
 		count++
@@ -402,6 +404,9 @@ Gitea or set your environment appropriately.`, "")
 			}
 			wasEmpty = wasEmpty || resp.RepoWasEmpty
 			results = append(results, resp.Results...)
+			hookPrintMessages(resp.Messages)
+			// the push options are applied once
+			hookOptions.GitPushOptions = nil
 			count = 0
 		}
 	}
@@ -435,6 +440,7 @@ Gitea or set your environment appropriately.`, "")
 	}
 	wasEmpty = wasEmpty || resp.RepoWasEmpty
 	results = append(results, resp.Results...)
+	hookPrintMessages(resp.Messages)
 
 	fmt.Fprintf(out, "Processed %d references in total\n", total)
 
@@ -470,13 +476,31 @@ func hookPrintResults(results []private.HookPostReceiveBranchResult) {
 	}
 }
 
+func hookPrintMessages(messages []string) {
+	if len(messages) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "")
+	for _, message := range messages {
+		fmt.Fprintln(os.Stderr, message)
+	}
+	fmt.Fprintln(os.Stderr, "")
+	os.Stderr.Sync()
+}
+
 func pushOptions() map[string]string {
 	opts := make(map[string]string)
 	if pushCount, err := strconv.Atoi(os.Getenv(private.GitPushOptionCount)); err == nil {
 		for idx := 0; idx < pushCount; idx++ {
 			opt := os.Getenv(fmt.Sprintf("GIT_PUSH_OPTION_%d", idx))
 			kv := strings.SplitN(opt, "=", 2)
-			if len(kv) == 2 {
+			if len(kv) != 2 {
+				continue
+			}
+			// topics can be given more than once, they can't contain commas
+			if kv[0] == private.GitPushOptionTopic && opts[kv[0]] != "" {
+				opts[kv[0]] += "," + kv[1]
+			} else {
 				opts[kv[0]] = kv[1]
 			}
 		}
@@ -676,7 +700,7 @@ Gitea or set your environment appropriately.`, "")
 		if err != nil {
 			return err
 		}
-		if rs.OldOID != git.EmptySHA {
+		if !git.IsEmptyCommitID(rs.OldOID) {
 			err = writeDataPktLine(os.Stdout, []byte("option old-oid "+rs.OldOID))
 			if err != nil {
 				return err
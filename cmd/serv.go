@@ -233,6 +233,10 @@ func runServ(c *cli.Context) error {
 		}
 		return fail("Internal Server Error", "%s", err.Error())
 	}
+	// The repository is served by the old name of its renamed owner
+	if !strings.EqualFold(results.OwnerName, username) {
+		repoPath = strings.ToLower(results.OwnerName) + "/" + rr[1]
+	}
 	os.Setenv(models.EnvRepoIsWiki, strconv.FormatBool(results.IsWiki))
 	os.Setenv(models.EnvRepoName, results.RepoName)
 	os.Setenv(models.EnvRepoUsername, results.OwnerName)
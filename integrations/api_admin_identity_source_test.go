@@ -0,0 +1,173 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/login"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/auth/source/ldap"
+
+	"github.com/markbates/goth"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIAdminIdentitySources(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	createSource := func(t *testing.T, opts *api.CreateIdentitySourceOption, status int) *api.IdentitySource {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/admin/identity-sources?token="+token, opts)
+		resp := session.MakeRequest(t, req, status)
+		if status != http.StatusCreated {
+			return nil
+		}
+		var source api.IdentitySource
+		DecodeJSON(t, resp, &source)
+		return &source
+	}
+
+	t.Run("Validation", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		// the configuration of the type is required
+		createSource(t, &api.CreateIdentitySourceOption{Name: "smtp", Type: api.IdentitySourceSMTP}, http.StatusUnprocessableEntity)
+		// only the configuration of the type may be given
+		createSource(t, &api.CreateIdentitySourceOption{
+			Name: "smtp",
+			Type: api.IdentitySourceSMTP,
+			PAM:  &api.IdentitySourcePAMConfig{ServiceName: "gitea"},
+		}, http.StatusUnprocessableEntity)
+		createSource(t, &api.CreateIdentitySourceOption{
+			Name: "smtp",
+			Type: api.IdentitySourceSMTP,
+			SMTP: &api.IdentitySourceSMTPConfig{Host: "smtp.example.com", Port: 0},
+		}, http.StatusUnprocessableEntity)
+		createSource(t, &api.CreateIdentitySourceOption{
+			Name: "ldap",
+			Type: api.IdentitySourceLDAP,
+			LDAP: &api.IdentitySourceLDAPConfig{Host: "ldap.example.com", Port: 389, SecurityProtocol: "tls"},
+		}, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("CRUD", func(t *testing.T) {
+		defer PrintCurrentTest(t)()
+
+		ldapSource := createSource(t, &api.CreateIdentitySourceOption{
+			Name: "ldap",
+			Type: api.IdentitySourceLDAP,
+			LDAP: &api.IdentitySourceLDAPConfig{
+				Host:          "ldap.example.com",
+				Port:          636,
+				BindDN:        "uid=gitea,ou=service,dc=planetexpress,dc=com",
+				BindPassword:  "password",
+				UserBase:      "ou=people,dc=planetexpress,dc=com",
+				Filter:        "(&(objectClass=inetOrgPerson)(uid=%s))",
+				AttributeMail: "mail",
+			},
+		}, http.StatusCreated)
+		assert.Equal(t, api.IdentitySourceLDAP, ldapSource.Type)
+		assert.True(t, ldapSource.IsActive)
+		if assert.NotNil(t, ldapSource.LDAP) {
+			assert.Equal(t, "unencrypted", ldapSource.LDAP.SecurityProtocol)
+			assert.Empty(t, ldapSource.LDAP.BindPassword)
+		}
+
+		// the bind password is kept if none is given
+		req := NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/admin/identity-sources/%d?token=%s", ldapSource.ID, token), &api.EditIdentitySourceOption{
+			LDAP: &api.IdentitySourceLDAPConfig{
+				Host:             "ldap.example.com",
+				Port:             636,
+				SecurityProtocol: "ldaps",
+				BindDN:           "uid=gitea,ou=service,dc=planetexpress,dc=com",
+				UserBase:         "ou=people,dc=planetexpress,dc=com",
+				Filter:           "(&(objectClass=inetOrgPerson)(uid=%s))",
+				AttributeMail:    "mail",
+			},
+		})
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, ldapSource)
+		assert.Equal(t, "ldaps", ldapSource.LDAP.SecurityProtocol)
+		source, err := login.GetSourceByID(ldapSource.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "password", source.Cfg.(*ldap.Source).BindPassword)
+
+		// the type can't be changed
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/admin/identity-sources/%d?token=%s", ldapSource.ID, token), &api.EditIdentitySourceOption{
+			SMTP: &api.IdentitySourceSMTPConfig{Host: "smtp.example.com", Port: 587},
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		oauthSource := createSource(t, &api.CreateIdentitySourceOption{
+			Name: "gitlab-api",
+			Type: api.IdentitySourceOAuth2,
+			OAuth2: &api.IdentitySourceOAuth2Config{
+				Provider:     "gitlab",
+				ClientID:     "client",
+				ClientSecret: "secret",
+			},
+		}, http.StatusCreated)
+		if assert.NotNil(t, oauthSource.OAuth2) {
+			assert.Empty(t, oauthSource.OAuth2.ClientSecret)
+		}
+		// the name is unique
+		createSource(t, &api.CreateIdentitySourceOption{
+			Name: "gitlab-api",
+			Type: api.IdentitySourceOAuth2,
+			OAuth2: &api.IdentitySourceOAuth2Config{
+				Provider:     "gitlab",
+				ClientID:     "client",
+				ClientSecret: "secret",
+			},
+		}, http.StatusUnprocessableEntity)
+
+		req = NewRequest(t, "GET", "/api/v1/admin/identity-sources?token="+token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var sources []*api.IdentitySource
+		DecodeJSON(t, resp, &sources)
+		assert.Len(t, sources, 2)
+
+		req = NewRequestf(t, "GET", "/api/v1/admin/identity-sources/%d?token=%s", oauthSource.ID, token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, oauthSource)
+		assert.Equal(t, "gitlab", oauthSource.OAuth2.Provider)
+
+		// a source in use can only be deleted by moving its users to another source
+		user := db.AssertExistsAndLoadBean(t, &models.User{Name: "user2"}).(*models.User)
+		user.LoginType = login.LDAP
+		user.LoginSource = ldapSource.ID
+		assert.NoError(t, models.UpdateUserCols(user, "login_type", "login_source"))
+
+		req = NewRequestf(t, "DELETE", "/api/v1/admin/identity-sources/%d?token=%s", ldapSource.ID, token)
+		resp = session.MakeRequest(t, req, http.StatusConflict)
+		assert.Contains(t, resp.Body.String(), "used by 1 users")
+
+		req = NewRequestf(t, "DELETE", "/api/v1/admin/identity-sources/%d?force=true&token=%s", ldapSource.ID, token)
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		req = NewRequestf(t, "DELETE", "/api/v1/admin/identity-sources/%d?force=true&migrate_to=%d&token=%s", ldapSource.ID, oauthSource.ID, token)
+		session.MakeRequest(t, req, http.StatusNoContent)
+		db.AssertNotExistsBean(t, &login.Source{ID: ldapSource.ID})
+		db.AssertExistsAndLoadBean(t, &models.User{ID: user.ID, LoginType: login.OAuth2, LoginSource: oauthSource.ID})
+
+		// deactivating an OAuth2 source unregisters its provider immediately
+		_, err = goth.GetProvider(oauthSource.Name)
+		assert.NoError(t, err)
+		isActive := false
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/admin/identity-sources/%d?token=%s", oauthSource.ID, token), &api.EditIdentitySourceOption{
+			IsActive: &isActive,
+		})
+		session.MakeRequest(t, req, http.StatusOK)
+		_, err = goth.GetProvider(oauthSource.Name)
+		assert.Error(t, err)
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -208,3 +209,178 @@ func TestAPIEditUser(t *testing.T) {
 	user2 = db.AssertExistsAndLoadBean(t, &models.User{LoginName: "user2"}).(*models.User)
 	assert.True(t, user2.IsRestricted)
 }
+
+func TestAPIAdminAnonymizeUser(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	// user2 owns repositories
+	req := NewRequestf(t, "POST", "/api/v1/admin/users/user2/anonymize?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "POST", "/api/v1/admin/users/user8/anonymize?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiUser api.User
+	DecodeJSON(t, resp, &apiUser)
+	assert.EqualValues(t, 8, apiUser.ID)
+	assert.Equal(t, "former-user-8", apiUser.UserName)
+	db.AssertNotExistsBean(t, &models.EmailAddress{UID: 8})
+
+	// neither the former nor the new name signs in, and the account can't be edited
+	for _, name := range []string{"user8", "former-user-8"} {
+		req = NewRequest(t, "GET", "/api/v1/user")
+		req.SetBasicAuth(name, userPassword)
+		MakeRequest(t, req, http.StatusUnauthorized)
+	}
+	bFalse := false
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/users/former-user-8?token="+token, api.EditUserOption{
+		LoginName:     "former-user-8",
+		ProhibitLogin: &bFalse,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestf(t, "POST", "/api/v1/admin/users/former-user-8/anonymize?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPIAdminStatistics(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "POST", "/api/v1/admin/stats/refresh?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var refreshed api.Statistics
+	DecodeJSON(t, resp, &refreshed)
+	assert.EqualValues(t, db.GetCount(t, &models.Repository{}), refreshed.Repositories)
+	assert.False(t, refreshed.AsOf.IsZero())
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/stats?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var stats api.Statistics
+	DecodeJSON(t, resp, &stats)
+	assert.Equal(t, refreshed.Repositories, stats.Repositories)
+	assert.True(t, refreshed.AsOf.Equal(stats.AsOf))
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/admin/stats?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIAdminDeleteInactiveUsersDryRun(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "POST", "/api/v1/admin/cron/delete-inactive-users?dry_run=true&token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var report api.DeleteInactiveUsersReport
+	DecodeJSON(t, resp, &report)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 1, report.Deleted)
+	if assert.Len(t, report.Users, 1) {
+		assert.Equal(t, "user9", report.Users[0].Name)
+	}
+	db.AssertExistsAndLoadBean(t, &models.User{Name: "user9"})
+}
+
+func TestAPIAdminGitConfigTemplate(t *testing.T) {
+	defer prepareTestEnv(t)()
+	defer func(config map[string]string, unset []string) {
+		setting.Git.RepoConfig = config
+		setting.Git.RepoConfigUnset = unset
+	}(setting.Git.RepoConfig, setting.Git.RepoConfigUnset)
+	setting.Git.RepoConfig = map[string]string{"receive.fsckObjects": "true"}
+	setting.Git.RepoConfigUnset = []string{"transfer.hideRefs"}
+
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "GET", "/api/v1/admin/git-config?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var template api.GitConfigTemplate
+	DecodeJSON(t, resp, &template)
+	assert.Equal(t, map[string]string{"receive.fsckObjects": "true"}, template.Config)
+	assert.Equal(t, []string{"transfer.hideRefs"}, template.Unset)
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/admin/git-config?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestf(t, "POST", "/api/v1/admin/git-config/sync?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIAdminNotices(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/admin/notices?type=repository&q=description2&token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var notices []*api.Notice
+	DecodeJSON(t, resp, &notices)
+	if assert.Len(t, notices, 1) {
+		assert.EqualValues(t, 2, notices[0].ID)
+		assert.Equal(t, "repository", notices[0].Type)
+		assert.EqualValues(t, 1, notices[0].Count)
+	}
+	assert.Equal(t, "1", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/notices?type=unknown&token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/admin/notices?token="+token, &api.DeleteNoticesOption{})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequestWithJSON(t, "DELETE", "/api/v1/admin/notices?token="+token, &api.DeleteNoticesOption{IDs: []int64{1, 3}})
+	session.MakeRequest(t, req, http.StatusNoContent)
+	db.AssertNotExistsBean(t, &models.Notice{ID: 1})
+	db.AssertExistsAndLoadBean(t, &models.Notice{ID: 2})
+	db.AssertNotExistsBean(t, &models.Notice{ID: 3})
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/admin/notices?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
+
+func TestAPIAdminCreateUsers(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/admin/users/bulk?token="+token, &api.CreateUsersOption{
+		Users: []*api.CreateUsersEntry{
+			{Username: "bulkuser1", Email: "bulkuser1@example.com", Password: "bulkpassword1", Visibility: "private"},
+			{Username: "user2", Email: "bulkuser2@example.com", Password: "bulkpassword2"},
+			{Username: "bulkuser3", Email: "bulkuser3@example.com"},
+			{Username: "bulkuser4", Email: "bulkuser4@example.com", SourceID: 1, LoginName: "bulkuser4"},
+		},
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var results []*api.CreateUsersResult
+	DecodeJSON(t, resp, &results)
+	if assert.Len(t, results, 4) {
+		assert.Equal(t, "created", results[0].Status)
+		if assert.NotNil(t, results[0].User) {
+			assert.Equal(t, "bulkuser1", results[0].User.UserName)
+			assert.Equal(t, "private", results[0].User.Visibility)
+		}
+		assert.Equal(t, "conflict", results[1].Status)
+		assert.Equal(t, "invalid", results[2].Status)
+		assert.Equal(t, "invalid", results[3].Status, "the login source does not exist")
+	}
+	db.AssertExistsAndLoadBean(t, &models.User{Name: "bulkuser1", MustChangePassword: true})
+	db.AssertNotExistsBean(t, &models.User{Name: "bulkuser3"})
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/users/bulk?token="+token, &api.CreateUsersOption{})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
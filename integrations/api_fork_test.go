@@ -8,7 +8,11 @@ import (
 	"net/http"
 	"testing"
 
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCreateForkNoLogin(t *testing.T) {
@@ -16,3 +20,39 @@ func TestCreateForkNoLogin(t *testing.T) {
 	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks", &api.CreateForkOption{})
 	MakeRequest(t, req, http.StatusUnauthorized)
 }
+
+func TestCreateForkWithName(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	name := "repo1-fork"
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks?token="+token, &api.CreateForkOption{
+		Name: &name,
+	})
+	resp := session.MakeRequest(t, req, http.StatusAccepted)
+	var fork api.Repository
+	DecodeJSON(t, resp, &fork)
+	assert.Equal(t, "user4/repo1-fork", fork.FullName)
+	assert.True(t, fork.Fork)
+
+	// a user only has one fork of a repository
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks?token="+token, &api.CreateForkOption{})
+	session.MakeRequest(t, req, http.StatusConflict)
+
+	// once the fork is transferred away it can be forked again
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: fork.ID}).(*models.Repository)
+	assert.NoError(t, repo.GetOwner())
+	assert.NoError(t, models.TransferOwnership(repo.Owner, "user5", repo))
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks?token="+token, &api.CreateForkOption{
+		Name: &name,
+	})
+	session.MakeRequest(t, req, http.StatusAccepted)
+
+	base := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.Equal(t, 2, base.NumForks)
+	count, err := models.CountReposWithWrongNumForks()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
@@ -206,3 +206,40 @@ func TestAPIModifyOrgLabels(t *testing.T) {
 	resp = session.MakeRequest(t, req, http.StatusNoContent)
 
 }
+
+func TestAPICopyLabels(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 2}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/labels/copy?token=%s", owner.Name, repo.Name, token)
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.CopyLabelsOption{Source: "user2/repo1"})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var copied api.CopiedLabels
+	DecodeJSON(t, resp, &copied)
+	if assert.Len(t, copied.Created, 2) {
+		assert.Equal(t, "label1", copied.Created[0].Name)
+		db.AssertExistsAndLoadBean(t, &models.Label{ID: copied.Created[0].ID, RepoID: repo.ID})
+	}
+	assert.Empty(t, copied.Skipped)
+
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	copied = api.CopiedLabels{}
+	DecodeJSON(t, resp, &copied)
+	assert.Empty(t, copied.Created)
+	assert.Len(t, copied.Skipped, 2)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CopyLabelsOption{Source: "user2/does-not-exist"})
+	session.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.CopyLabelsOption{Source: "repo1"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// the doer must be able to write the issues of the target repository
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/labels/copy?token=%s", token), &api.CopyLabelsOption{Source: "user2/repo1"})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -73,6 +73,22 @@ func TestAPIIssuesMilestone(t *testing.T) {
 	assert.Len(t, apiMilestones, 1)
 	assert.Equal(t, int64(2), apiMilestones[0].ID)
 
+	// the milestones of the fixtures are due at the epoch, the new one has no due date
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/milestones?state=%s&overdue=%s&token=%s", owner.Name, repo.Name, "all", "true", token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiMilestones)
+	assert.Len(t, apiMilestones, 3)
+	for _, m := range apiMilestones {
+		assert.True(t, m.IsOverdue)
+	}
+
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/milestones?state=%s&overdue=%s&token=%s", owner.Name, repo.Name, "all", "false", token))
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiMilestones)
+	assert.Len(t, apiMilestones, 1)
+	assert.Equal(t, "wow", apiMilestones[0].Title)
+	assert.False(t, apiMilestones[0].IsOverdue)
+
 	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/%s/%s/milestones/%d?token=%s", owner.Name, repo.Name, apiMilestone.ID, token))
 	resp = session.MakeRequest(t, req, http.StatusNoContent)
 }
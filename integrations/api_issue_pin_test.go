@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPinIssue(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "POST", "/api/v1/repos/%s/%s/issues/4/pin?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/%s/issues/1/pin?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequestf(t, "PATCH", "/api/v1/repos/%s/%s/issues/1/pin/1?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	req = NewRequestf(t, "PATCH", "/api/v1/repos/%s/%s/issues/1/pin/4?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/issues/1?token=%s", owner.Name, repo.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiIssue api.Issue
+	DecodeJSON(t, resp, &apiIssue)
+	assert.EqualValues(t, 1, apiIssue.PinOrder)
+
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/%s/issues/1/unpin?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	db.AssertExistsAndLoadBean(t, &models.Issue{RepoID: repo.ID, Index: 4, PinOrder: 1})
+	req = NewRequestf(t, "PATCH", "/api/v1/repos/%s/%s/issues/1/pin/1?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// a reader can't pin issues
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/%s/issues/1/pin?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIIssueReferences(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	token := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+	createIssue := func(repo, title, body string) *api.Issue {
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/%s/issues?token=%s", repo, token), &api.CreateIssueOption{
+			Title: title,
+			Body:  body,
+		})
+		resp := MakeRequest(t, req, http.StatusCreated)
+		issue := new(api.Issue)
+		DecodeJSON(t, resp, issue)
+		return issue
+	}
+
+	target := createIssue("repo1", "target", "")
+	public := createIssue("repo1", "public", fmt.Sprintf("fixes #%d and mentions it again: #%d", target.Index, target.Index))
+	private := createIssue("repo2", "private", fmt.Sprintf("mentions user2/repo1#%d", target.Index))
+
+	listReferences := func(token, repo string, index int64, list string) []*api.IssueReference {
+		req := NewRequestf(t, "GET", "/api/v1/repos/user2/%s/issues/%d/%s?token=%s", repo, index, list, token)
+		resp := MakeRequest(t, req, http.StatusOK)
+		var refs []*api.IssueReference
+		DecodeJSON(t, resp, &refs)
+		return refs
+	}
+
+	refs := listReferences(token, "repo1", target.Index, "references")
+	if assert.Len(t, refs, 2) {
+		assert.EqualValues(t, "issue", refs[0].Type)
+		assert.False(t, refs[0].Private)
+		assert.EqualValues(t, "user2/repo1", refs[0].Repository.FullName)
+		assert.EqualValues(t, public.Index, refs[0].Index)
+		assert.EqualValues(t, public.Title, refs[0].Title)
+		assert.EqualValues(t, public.HTMLURL, refs[0].HTMLURL)
+		assert.False(t, refs[0].Closes)
+		assert.EqualValues(t, "user2/repo2", refs[1].Repository.FullName)
+		assert.EqualValues(t, private.Index, refs[1].Index)
+	}
+
+	refs = listReferences(token, "repo2", private.Index, "referencing")
+	if assert.Len(t, refs, 1) {
+		assert.EqualValues(t, "user2/repo1", refs[0].Repository.FullName)
+		assert.EqualValues(t, target.Index, refs[0].Index)
+	}
+
+	// references from repositories the user can't read are omitted
+	otherToken := getTokenForLoggedInUser(t, loginUser(t, "user4"))
+	refs = listReferences(otherToken, "repo1", target.Index, "references")
+	if assert.Len(t, refs, 1) {
+		assert.EqualValues(t, public.Index, refs[0].Index)
+	}
+
+	// or listed without any details
+	setting.Repository.Issue.PrivateReferenceStubs = true
+	defer func() {
+		setting.Repository.Issue.PrivateReferenceStubs = false
+	}()
+	refs = listReferences(otherToken, "repo1", target.Index, "references")
+	if assert.Len(t, refs, 2) {
+		assert.False(t, refs[0].Private)
+		assert.EqualValues(t, public.Index, refs[0].Index)
+		assert.True(t, refs[1].Private)
+		assert.EqualValues(t, "issue", refs[1].Type)
+		assert.Nil(t, refs[1].Repository)
+		assert.Zero(t, refs[1].Index)
+		assert.Empty(t, refs[1].Title)
+	}
+}
@@ -73,3 +73,30 @@ func TestAPIIssueSubscriptions(t *testing.T) {
 	session.MakeRequest(t, req, http.StatusOK)
 	testSubscription(issue5, true)
 }
+
+func TestAPIUserIssueSubscriptions(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/user/subscriptions/issues?repo=user2/repo2&state=all&token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var subscriptions []*api.IssueSubscription
+	DecodeJSON(t, resp, &subscriptions)
+	assert.Len(t, subscriptions, 2)
+	assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+
+	req = NewRequestf(t, "DELETE", "/api/v1/user/subscriptions/issues?token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/user/subscriptions/issues?repo=user2/repo2&kind=implicit&token=%s", token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+
+	req = NewRequestf(t, "GET", "/api/v1/user/subscriptions/issues?repo=user2/repo2&state=all&token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &subscriptions)
+	if assert.Len(t, subscriptions, 1) {
+		assert.True(t, subscriptions[0].Explicit)
+	}
+}
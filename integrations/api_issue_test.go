@@ -8,12 +8,14 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 	"testing"
 	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	api "code.gitea.io/gitea/modules/structs"
+	visit_service "code.gitea.io/gitea/services/visit"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -68,6 +70,32 @@ func TestAPIListIssues(t *testing.T) {
 	}
 }
 
+func TestAPIExportIssues(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	link, _ := url.Parse(fmt.Sprintf("/api/v1/repos/%s/%s/issues/export", owner.Name, repo.Name))
+	count := db.GetCount(t, &models.Issue{RepoID: repo.ID})
+
+	link.RawQuery = url.Values{"token": {token}, "state": {"all"}, "include_comments": {"true"}}.Encode()
+	resp := session.MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	var apiIssues []*api.ExportedIssue
+	DecodeJSON(t, resp, &apiIssues)
+	assert.Len(t, apiIssues, count)
+
+	link.RawQuery = url.Values{"token": {token}, "state": {"all"}, "format": {"csv"}}.Encode()
+	resp = session.MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusOK)
+	assert.Equal(t, "text/csv; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Len(t, strings.Split(strings.TrimSpace(resp.Body.String()), "\n"), count+1)
+
+	link.RawQuery = url.Values{"token": {token}, "format": {"xml"}}.Encode()
+	session.MakeRequest(t, NewRequest(t, "GET", link.String()), http.StatusUnprocessableEntity)
+}
+
 func TestAPICreateIssue(t *testing.T) {
 	defer prepareTestEnv(t)()
 	const body, title = "apiTestBody", "apiTestTitle"
@@ -322,3 +350,151 @@ func TestAPISearchIssuesWithLabels(t *testing.T) {
 	DecodeJSON(t, resp, &apiIssues)
 	assert.Len(t, apiIssues, 2)
 }
+
+func TestAPICreateIssueWithForm(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+		_, err := createFileInBranch(owner, repo, ".gitea/ISSUE_TEMPLATE/bug.yaml", repo.DefaultBranch, `name: Bug Report
+description: File a bug report
+body:
+  - type: input
+    id: version
+    attributes:
+      label: Version
+    validations:
+      required: true
+  - type: dropdown
+    id: database
+    attributes:
+      label: Database
+      options:
+        - SQLite
+        - MySQL
+`)
+		assert.NoError(t, err)
+
+		session := loginUser(t, owner.Name)
+		token := getTokenForLoggedInUser(t, session)
+
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/issue_forms/bug/schema?token=%s", owner.Name, repo.Name, token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var form api.IssueTemplate
+		DecodeJSON(t, resp, &form)
+		assert.Equal(t, "bug.yaml", form.FileName)
+		assert.Len(t, form.Fields, 2)
+		assert.EqualValues(t, "version", form.Fields[0].ID)
+		assert.True(t, form.Fields[0].Validations.Required)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/issue_forms/feature/schema?token=%s", owner.Name, repo.Name, token)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues?token=%s", owner.Name, repo.Name, token)
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueOption{
+			Title:    "missing version",
+			Template: "bug.yaml",
+			Fields:   map[string][]string{"database": {"PostgreSQL"}},
+		})
+		resp = session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+		assert.Contains(t, resp.Body.String(), "version: is required")
+		assert.Contains(t, resp.Body.String(), `database: \"PostgreSQL\" is not a valid option`)
+
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueOption{
+			Title:    "form issue",
+			Template: "bug.yaml",
+			Fields:   map[string][]string{"version": {"1.16.0"}, "database": {"MySQL"}},
+		})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var apiIssue api.Issue
+		DecodeJSON(t, resp, &apiIssue)
+		assert.Equal(t, "### Version\n\n1.16.0\n\n### Database\n\nMySQL", apiIssue.Body)
+	})
+}
+
+func TestAPICreateIssueWithTemplate(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+		_, err := createFileInBranch(owner, repo, ".gitea/ISSUE_TEMPLATE/bug.md", repo.DefaultBranch, `---
+name: Bug Report
+about: File a bug report
+title: "[Bug]"
+labels: ["label1", "missing"]
+assignees: ["user2", "user8"]
+---
+Describe the bug
+`)
+		assert.NoError(t, err)
+
+		// the template is applied to the issues of users who can't set labels and assignees themselves
+		session := loginUser(t, "user4")
+		token := getTokenForLoggedInUser(t, session)
+		urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/issues?token=%s", owner.Name, repo.Name, token)
+		req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueOption{
+			Title:    "crash",
+			Body:     "it crashed",
+			Template: "bug",
+		})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var apiIssue api.Issue
+		DecodeJSON(t, resp, &apiIssue)
+		assert.Equal(t, "[Bug] crash", apiIssue.Title)
+		if assert.Len(t, apiIssue.Labels, 1) {
+			assert.Equal(t, "label1", apiIssue.Labels[0].Name)
+		}
+		if assert.Len(t, apiIssue.Assignees, 1) {
+			assert.Equal(t, "user2", apiIssue.Assignees[0].UserName)
+		}
+		assert.Equal(t, []string{
+			"label of the issue template does not exist: missing",
+			"assignee of the issue template cannot be assigned: user8",
+		}, apiIssue.Warnings)
+		db.AssertExistsAndLoadBean(t, &models.Comment{IssueID: apiIssue.ID, Type: models.CommentTypeLabel, LabelID: 1})
+
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateIssueOption{
+			Title:    "crash",
+			Template: "missing",
+		})
+		session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestAPIListIssuesChangedSinceVisit(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	link := fmt.Sprintf("/api/v1/repos/%s/%s/issues?state=all&changed_since_visit=true", owner.Name, repo.Name)
+
+	// anonymous users have no visits
+	MakeRequest(t, NewRequest(t, "GET", link), http.StatusUnprocessableEntity)
+
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	var apiIssues []*api.Issue
+	listIssues := func() {
+		resp := session.MakeRequest(t, NewRequest(t, "GET", link+"&token="+token), http.StatusOK)
+		DecodeJSON(t, resp, &apiIssues)
+	}
+
+	// all issues are new to users who have never visited the repository
+	listIssues()
+	assert.Len(t, apiIssues, db.GetCount(t, &models.Issue{RepoID: repo.ID}))
+
+	// and still during the first visit
+	session.MakeRequest(t, NewRequest(t, "GET", "/"+repo.FullName()), http.StatusOK)
+	visit_service.Flush()
+	listIssues()
+	assert.Len(t, apiIssues, db.GetCount(t, &models.Issue{RepoID: repo.ID}))
+
+	// only the issues updated since an older visit are listed
+	_, err := db.GetEngine(db.DefaultContext).Where("user_id = ? AND repo_id = ?", owner.ID, repo.ID).
+		Cols("visited_unix").Update(&models.RepoVisit{VisitedUnix: 1579194700})
+	assert.NoError(t, err)
+	listIssues()
+	for _, apiIssue := range apiIssues {
+		assert.True(t, apiIssue.Updated.Unix() > 1579194700)
+	}
+	assert.NotEmpty(t, apiIssues)
+	assert.Less(t, len(apiIssues), db.GetCount(t, &models.Issue{RepoID: repo.ID}))
+}
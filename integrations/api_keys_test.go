@@ -68,6 +68,11 @@ func TestCreateReadOnlyDeployKey(t *testing.T) {
 		Content: rawKeyBody.Key,
 		Mode:    models.AccessModeRead,
 	})
+
+	// the change is noticed with the fingerprint of the key, never its content
+	notice := db.AssertExistsAndLoadBean(t, &models.Notice{Type: models.NoticeSecurity}).(*models.Notice)
+	assert.Contains(t, notice.Description, newDeployKey.Fingerprint)
+	assert.NotContains(t, notice.Description, "AAAAB3NzaC1yc2E")
 }
 
 func TestCreateReadWriteDeployKey(t *testing.T) {
@@ -95,6 +100,61 @@ func TestCreateReadWriteDeployKey(t *testing.T) {
 	})
 }
 
+func TestDeployKeyCommitStatus(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{Name: "repo1"}).(*models.Repository)
+	repoOwner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	session := loginUser(t, repoOwner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	keysURL := fmt.Sprintf("/api/v1/repos/%s/%s/keys?token=%s", repoOwner.Name, repo.Name, token)
+	req := NewRequestWithJSON(t, "POST", keysURL, api.CreateKeyOption{
+		Title:    "ci",
+		Key:      "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC4cn+iXnA4KvcQYSV88vGn0Yi91vG47t1P7okprVmhNTkipNRIHWr6WdCO4VDr/cvsRkuVJAsLO2enwjGWWueOO6BodiBgyAOZ/5t5nJNMCNuLGT5UIo/RI1b0WRQwxEZTRjt6mFNw6lH14wRd8ulsr9toSWBPMOGWoYs1PDeDL0JuTjL+tr1SZi/EyxCngpYszKdXllJEHyI79KQgeD0Vt3pTrkbNVTOEcCNqZePSVmUH8X8Vhugz3bnE0/iE9Pb5fkWO9c4AnM1FgI/8Bvp27Fw2ShryIXuR6kKvUqhVMTuOSDHwu6A8jLE5Owt3GAYugDpDYuwTVNGrHLXKpPzrGGPE/jPmaLCMZcsdkec95dYeU3zKODEm8UQZFhmJmDeWVJ36nGrGZHL4J5aTTaeFUJmmXDaJYiJ+K2/ioKgXqnXvltu0A9R8/LGy4nrTJRr4JMLuJFoUXvGm1gXQ70w2LSpk6yl71RNC0hCtsBe8BP8IhYCM0EP5jh7eCMQZNvM= nocomment\n",
+		ReadOnly: true,
+		Statuses: true,
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var newDeployKey api.DeployKey
+	DecodeJSON(t, resp, &newDeployKey)
+	assert.True(t, newDeployKey.Statuses)
+	assert.Len(t, newDeployKey.StatusesToken, 40)
+	keyToken := newDeployKey.StatusesToken
+
+	// the token is not returned again
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/keys/%d?token=%s", repoOwner.Name, repo.Name, newDeployKey.ID, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &newDeployKey)
+	assert.Empty(t, newDeployKey.StatusesToken)
+
+	commitID := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/statuses/%s?token=%s", repoOwner.Name, repo.Name, commitID, keyToken), api.CreateStatusOption{
+		State:   api.CommitStatusSuccess,
+		Context: "ci/build",
+	})
+	resp = MakeRequest(t, req, http.StatusCreated)
+	var status api.CommitStatus
+	DecodeJSON(t, resp, &status)
+	assert.Equal(t, "ci", status.Creator.UserName)
+	db.AssertExistsAndLoadBean(t, &models.CommitStatus{RepoID: repo.ID, SHA: commitID, CreatorID: models.DeployKeyUserID, CreatorName: "ci"})
+
+	// the key may read its repository
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/statuses/%s?token=%s", repoOwner.Name, repo.Name, commitID, keyToken)
+	MakeRequest(t, req, http.StatusOK)
+
+	// but nothing else
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/%s/issues?token=%s", repoOwner.Name, repo.Name, keyToken), api.CreateIssueOption{
+		Title: "issue by a deploy key",
+	})
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestf(t, "GET", "/api/v1/user?token=%s", keyToken)
+	MakeRequest(t, req, http.StatusForbidden)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo2/statuses/%s?token=%s", commitID, keyToken), api.CreateStatusOption{
+		State: api.CommitStatusSuccess,
+	})
+	MakeRequest(t, req, http.StatusNotFound)
+}
+
 func TestCreateUserKey(t *testing.T) {
 	defer prepareTestEnv(t)()
 	user := db.AssertExistsAndLoadBean(t, &models.User{Name: "user1"}).(*models.User)
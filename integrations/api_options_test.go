@@ -0,0 +1,62 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoInitOptions(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	var names []string
+	req := NewRequest(t, "GET", "/api/v1/options/license")
+	resp := MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &names)
+	assert.Equal(t, models.Licenses, names)
+	assert.Contains(t, names, "MIT")
+
+	req = NewRequest(t, "GET", "/api/v1/options/label")
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &names)
+	assert.Contains(t, names, "Default")
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/options/unknown"), http.StatusNotFound)
+
+	req = NewRequest(t, "GET", "/api/v1/options/license/mit")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), "MIT License")
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/options/license/no-such-license"), http.StatusNotFound)
+}
+
+func TestAPICreateRepoInitFileCaseInsensitive(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/user/repos?token="+token, &api.CreateRepoOption{
+		Name:     "init-file-case",
+		AutoInit: true,
+		License:  "mit",
+	})
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/user/repos?token="+token, &api.CreateRepoOption{
+		Name:     "init-file-missing",
+		AutoInit: true,
+		License:  "no-such-license",
+	})
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	db.AssertNotExistsBean(t, &models.Repository{OwnerID: 2, LowerName: "init-file-missing"})
+}
@@ -102,6 +102,87 @@ func TestAPIOrgEdit(t *testing.T) {
 	})
 }
 
+func TestAPIOrgRequirePrivateRepos(t *testing.T) {
+	onGiteaRun(t, func(*testing.T, *url.URL) {
+		token := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+		adminToken := getTokenForLoggedInUser(t, loginUser(t, "user1"))
+
+		allowPublicRepos := false
+		req := NewRequestWithJSON(t, "PATCH", "/api/v1/orgs/user3?token="+token, &api.EditOrgOption{
+			FullName:         "User Three",
+			AllowPublicRepos: &allowPublicRepos,
+		})
+		resp := MakeRequest(t, req, http.StatusOK)
+		var apiOrg api.Organization
+		DecodeJSON(t, resp, &apiOrg)
+		assert.False(t, apiOrg.AllowPublicRepos)
+
+		// the public repository from before the policy is listed
+		req = NewRequest(t, "GET", "/api/v1/orgs/user3/visibility_violations?token="+token)
+		resp = MakeRequest(t, req, http.StatusOK)
+		var apiRepos []*api.Repository
+		DecodeJSON(t, resp, &apiRepos)
+		if assert.Len(t, apiRepos, 1) {
+			assert.Equal(t, "repo21", apiRepos[0].Name)
+		}
+
+		req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos?token="+token, &api.CreateRepoOption{Name: "public-repo"})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos?token="+token, &api.CreateRepoOption{Name: "public-repo", OverrideVisibilityPolicy: true})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+		req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos?token="+token, &api.CreateRepoOption{Name: "private-repo", Private: true})
+		MakeRequest(t, req, http.StatusCreated)
+
+		isPrivate := false
+		req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/private-repo?token="+token, &api.EditRepoOption{Private: &isPrivate})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+		// site admins can override the policy explicitly
+		req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/repos?token="+adminToken, &api.CreateRepoOption{Name: "public-repo", OverrideVisibilityPolicy: true})
+		MakeRequest(t, req, http.StatusCreated)
+		req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/user3/private-repo?token="+adminToken, &api.EditRepoOption{Private: &isPrivate, OverrideVisibilityPolicy: true})
+		MakeRequest(t, req, http.StatusOK)
+
+		// public repositories can't be forked into the organization
+		org3 := "user3"
+		req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/forks?token="+token, &api.CreateForkOption{Organization: &org3})
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+}
+
+func TestAPIOrgListAccessibleRepos(t *testing.T) {
+	defer prepareTestEnv(t)()
+	token := getTokenForLoggedInUser(t, loginUser(t, "user2"))
+
+	req := NewRequest(t, "GET", "/api/v1/orgs/user3/repos/accessible?user=user4&token="+token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var apiRepos []*api.AccessibleRepository
+	DecodeJSON(t, resp, &apiRepos)
+	if assert.Len(t, apiRepos, 1) {
+		assert.Equal(t, "repo3", apiRepos[0].Repository.Name)
+		assert.Equal(t, "write", apiRepos[0].Permission)
+		assert.Equal(t, []*api.RepositoryAccessPath{{TeamID: 2, TeamName: "team1", Permission: "write"}}, apiRepos[0].Paths)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/repos/accessible?team=Owners&limit=1&token="+token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+	DecodeJSON(t, resp, &apiRepos)
+	if assert.Len(t, apiRepos, 1) {
+		assert.Equal(t, "owner", apiRepos[0].Permission)
+	}
+
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/repos/accessible?token="+token)
+	MakeRequest(t, req, http.StatusUnprocessableEntity)
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/repos/accessible?team=missing&token="+token)
+	MakeRequest(t, req, http.StatusNotFound)
+
+	// only owners of the organization can audit the access
+	token = getTokenForLoggedInUser(t, loginUser(t, "user4"))
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/repos/accessible?user=user4&token="+token)
+	MakeRequest(t, req, http.StatusForbidden)
+}
+
 func TestAPIOrgEditBadVisibility(t *testing.T) {
 	onGiteaRun(t, func(*testing.T, *url.URL) {
 		session := loginUser(t, "user1")
@@ -179,4 +179,85 @@ func TestAPIEditPull(t *testing.T) {
 		Base: "not-exist",
 	})
 	session.MakeRequest(t, req, 404)
+
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		Base: "develop",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// nobody is allowed to merge into the protected branch
+	assert.NoError(t, db.Insert(db.DefaultContext, &models.ProtectedBranch{RepoID: repo10.ID, BranchName: "master", EnableMergeWhitelist: true}))
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		Base: "master",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+	pr := db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pull.ID, BaseBranch: "feature/1"}).(*models.PullRequest)
+	db.AssertExistsAndLoadBean(t, &models.Comment{IssueID: pr.IssueID, Type: models.CommentTypeChangeTargetBranch, OldRef: "master", NewRef: "feature/1"})
+}
+
+func TestAPIDraftPull(t *testing.T) {
+	defer prepareTestEnv(t)()
+	repo10 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 10}).(*models.Repository)
+	owner10 := db.AssertExistsAndLoadBean(t, &models.User{ID: repo10.OwnerID}).(*models.User)
+
+	session := loginUser(t, owner10.Name)
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls?token=%s", owner10.Name, repo10.Name, token), &api.CreatePullRequestOption{
+		Head:  "develop",
+		Base:  "master",
+		Title: "create a draft pr",
+		Draft: true,
+	})
+	pull := new(api.PullRequest)
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.True(t, pull.Draft)
+	assert.False(t, pull.Mergeable)
+
+	listPulls := func(draft bool) []*api.PullRequest {
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls?state=all&draft=%t&token=%s", owner10.Name, repo10.Name, draft, token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var pulls []*api.PullRequest
+		DecodeJSON(t, resp, &pulls)
+		return pulls
+	}
+	if pulls := listPulls(true); assert.Len(t, pulls, 1) {
+		assert.EqualValues(t, pull.Index, pulls[0].Index)
+	}
+	for _, p := range listPulls(false) {
+		assert.NotEqual(t, pull.Index, p.Index)
+	}
+
+	// drafts can't be merged
+	req = NewRequestWithJSON(t, http.MethodPost, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", owner10.Name, repo10.Name, pull.Index, token), &forms.MergePullRequestForm{
+		Do: string(models.MergeStyleMerge),
+	})
+	session.MakeRequest(t, req, http.StatusMethodNotAllowed)
+
+	// adding a work in progress prefix keeps the pull request a draft, marking it as ready removes the prefix
+	prefix := setting.Repository.PullRequest.WorkInProgressPrefixes[0]
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		Title: prefix + " create a draft pr",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.True(t, pull.Draft)
+
+	ready := false
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		Draft: &ready,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.False(t, pull.Draft)
+	assert.Equal(t, "create a draft pr", pull.Title)
+	db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pull.ID}, db.Cond("is_draft = ?", false))
+
+	draft := true
+	req = NewRequestWithJSON(t, http.MethodPatch, fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d?token=%s", owner10.Name, repo10.Name, pull.Index, token), &api.EditPullRequestOption{
+		Draft: &draft,
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, pull)
+	assert.True(t, pull.Draft)
 }
@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullViewedFiles(t *testing.T) {
+	defer prepareTestEnv(t)()
+	pr := db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: pr.BaseRepoID}).(*models.Repository)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	viewedURL := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/viewed-files?token=%s", repo.OwnerName, repo.Name, pr.Index, token)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls/%d/files?token=%s", repo.OwnerName, repo.Name, pr.Index, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var files []*api.ChangedFile
+	DecodeJSON(t, resp, &files)
+	if !assert.NotEmpty(t, files) {
+		return
+	}
+	for _, file := range files {
+		assert.False(t, file.Viewed)
+	}
+
+	req = NewRequestWithJSON(t, "PUT", viewedURL, &api.UpdatePullViewedFilesOption{
+		Files: []api.PullViewedFileOption{{Path: files[0].Filename, Viewed: true}},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var viewed []*api.PullViewedFile
+	DecodeJSON(t, resp, &viewed)
+	if assert.Len(t, viewed, 1) {
+		assert.Equal(t, files[0].Filename, viewed[0].Path)
+		assert.True(t, viewed[0].Viewed)
+		assert.False(t, viewed[0].ChangedSinceViewed)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/pulls/%d/files?token=%s", repo.OwnerName, repo.Name, pr.Index, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &files)
+	assert.True(t, files[0].Viewed)
+
+	// files not changed by the pull request can't be marked as viewed
+	req = NewRequestWithJSON(t, "PUT", viewedURL, &api.UpdatePullViewedFilesOption{
+		Files: []api.PullViewedFileOption{{Path: "does-not-exist.txt", Viewed: true}},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PUT", viewedURL, &api.UpdatePullViewedFilesOption{
+		Files: []api.PullViewedFileOption{{Path: files[0].Filename, Viewed: false}},
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &viewed)
+	assert.Empty(t, viewed)
+}
@@ -7,12 +7,15 @@ package integrations
 import (
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -230,3 +233,56 @@ func TestAPIDeleteReleaseByTagName(t *testing.T) {
 	req = NewRequestf(t, http.MethodDelete, fmt.Sprintf("/api/v1/repos/%s/%s/tags/release-tag?token=%s", owner.Name, repo.Name, token))
 	_ = session.MakeRequest(t, req, http.StatusNoContent)
 }
+
+func TestAPICreateReleaseAttachmentFromURL(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	content := []byte("release attachment fetched from a URL")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.LowerName)
+	token := getTokenForLoggedInUser(t, session)
+	assetsURL := fmt.Sprintf("/api/v1/repos/%s/%s/releases/1/assets", owner.Name, repo.Name)
+
+	// local networks are not allowed by default
+	req := NewRequestf(t, "POST", "%s?token=%s&source_url=%s", assetsURL, token, url.QueryEscape(server.URL+"/asset.bin"))
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "POST", "%s?token=%s&source_url=%s", assetsURL, token, url.QueryEscape("file:///etc/passwd"))
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	oldAllowLocalNetworks := setting.Migrations.AllowLocalNetworks
+	setting.Migrations.AllowLocalNetworks = true
+	defer func() {
+		setting.Migrations.AllowLocalNetworks = oldAllowLocalNetworks
+	}()
+
+	req = NewRequestf(t, "POST", "%s?token=%s&source_url=%s", assetsURL, token, url.QueryEscape(server.URL+"/asset.bin"))
+	resp := session.MakeRequest(t, req, http.StatusAccepted)
+	var task api.ReleaseAttachmentTask
+	DecodeJSON(t, resp, &task)
+	assert.Equal(t, "asset.bin", task.Name)
+	assert.Equal(t, server.URL+"/asset.bin", task.SourceURL)
+
+	// wait for the task to finish
+	for i := 0; i < 50 && task.Status != "finished" && task.Status != "failed"; i++ {
+		time.Sleep(100 * time.Millisecond)
+		req = NewRequestf(t, "GET", "%s/tasks/%d?token=%s", assetsURL, task.ID, token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &task)
+	}
+	assert.Equal(t, "finished", task.Status, task.Message)
+	assert.Equal(t, "application/octet-stream", task.ContentType)
+	assert.EqualValues(t, len(content), task.FetchedSize)
+	if assert.NotNil(t, task.Attachment) {
+		assert.Equal(t, "asset.bin", task.Attachment.Name)
+		assert.EqualValues(t, len(content), task.Attachment.Size)
+		db.AssertExistsAndLoadBean(t, &models.Attachment{ID: task.Attachment.ID, ReleaseID: 1})
+	}
+}
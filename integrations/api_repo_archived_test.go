@@ -0,0 +1,83 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIArchivedRepoRejectsChanges(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 51}).(*models.Repository)
+	assert.True(t, repo.IsArchived)
+
+	session := loginUser(t, repo.OwnerName)
+	token := getTokenForLoggedInUser(t, session)
+	repoURL := fmt.Sprintf("/api/v1/repos/%s/%s", repo.OwnerName, repo.Name)
+
+	title := "changed"
+	description := "changed"
+	cases := []struct {
+		method string
+		path   string
+		body   interface{}
+	}{
+		{"PATCH", "", &api.EditRepoOption{Description: &description}},
+		{"POST", "/issues", &api.CreateIssueOption{Title: title}},
+		{"PATCH", "/issues/1", &api.EditIssueOption{Title: title}},
+		{"POST", "/issues/1/comments", &api.CreateIssueCommentOption{Body: title}},
+		{"POST", "/issues/1/labels", &api.IssueLabelsOption{Labels: []int64{1}}},
+		{"POST", "/issues/1/reactions", &api.EditReactionOption{Reaction: "+1"}},
+		{"POST", "/issues/1/pin", nil},
+		{"POST", "/labels", &api.CreateLabelOption{Name: title, Color: "#123456"}},
+		{"POST", "/milestones", &api.CreateMilestoneOption{Title: title}},
+		{"POST", "/releases", &api.CreateReleaseOption{TagName: "v1.0"}},
+		{"PUT", "/topics/archived", nil},
+		{"POST", "/hooks", &api.CreateHookOption{Type: "gitea", Config: api.CreateHookOptionConfig{"content_type": "json", "url": "http://example.com/"}}},
+		{"POST", "/keys", &api.CreateKeyOption{Title: title, Key: "ssh-rsa AAAA"}},
+		{"PUT", "/collaborators/user2", &api.AddCollaboratorOption{}},
+		{"PUT", "/metadata/key", &api.SetRepoMetadataOption{Value: title}},
+	}
+	for _, c := range cases {
+		req := NewRequestWithJSON(t, c.method, repoURL+c.path+"?token="+token, c.body)
+		session.MakeRequest(t, req, http.StatusForbidden)
+	}
+	db.AssertNotExistsBean(t, &models.Issue{RepoID: repo.ID, Title: title})
+	db.AssertNotExistsBean(t, &models.Label{RepoID: repo.ID, Name: title})
+	db.AssertNotExistsBean(t, &models.Milestone{RepoID: repo.ID, Name: title})
+
+	// reading and watching an archived repository keeps working
+	req := NewRequestf(t, "GET", "%s/issues/1?token=%s", repoURL, token)
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequestf(t, "PUT", "%s/subscription?token=%s", repoURL, token)
+	session.MakeRequest(t, req, http.StatusOK)
+
+	// the web settings only allow to unarchive or delete it
+	req = NewRequestWithValues(t, "POST", fmt.Sprintf("/%s/%s/settings", repo.OwnerName, repo.Name), map[string]string{
+		"_csrf":     GetCSRF(t, session, fmt.Sprintf("/%s/%s/settings", repo.OwnerName, repo.Name)),
+		"action":    "update",
+		"repo_name": repo.Name,
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	archived := false
+	req = NewRequestWithJSON(t, "PATCH", repoURL+"?token="+token, &api.EditRepoOption{Archived: &archived, Description: &description})
+	session.MakeRequest(t, req, http.StatusOK)
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: 51}).(*models.Repository)
+	assert.False(t, repo.IsArchived)
+	assert.Equal(t, description, repo.Description)
+
+	req = NewRequestWithJSON(t, "POST", repoURL+"/labels?token="+token, &api.CreateLabelOption{Name: title, Color: "#123456"})
+	session.MakeRequest(t, req, http.StatusCreated)
+}
@@ -0,0 +1,93 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCloneToken(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		urlStr := fmt.Sprintf("/api/v1/repos/user2/repo1/clone-token?token=%s", token)
+
+		req := NewRequestWithJSON(t, "POST", urlStr, &api.CreateRepoCloneTokenOption{Permission: "read"})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+		var readToken api.RepoCloneToken
+		DecodeJSON(t, resp, &readToken)
+		assert.Equal(t, "read", readToken.Permission)
+		assert.NotEmpty(t, readToken.Token)
+
+		// the URL is composed with the configured app URL, the test server listens elsewhere
+		cloneURL, err := url.Parse(fmt.Sprintf("%suser2/repo1.git", u.String()))
+		assert.NoError(t, err)
+		cloneURL.User = url.UserPassword("user2", readToken.Token)
+
+		dstPath, err := os.MkdirTemp("", "clone-token")
+		assert.NoError(t, err)
+		defer util.RemoveAll(dstPath)
+		t.Run("Clone", doGitClone(dstPath, cloneURL))
+		t.Run("PushWithReadToken", func(t *testing.T) {
+			assert.NoError(t, os.WriteFile(dstPath+"/clone-token.txt", []byte("content"), 0644))
+			assert.NoError(t, git.AddChanges(dstPath, true))
+			signature := git.Signature{Email: "user2@example.com", Name: "User Two", When: time.Now()}
+			assert.NoError(t, git.CommitChanges(dstPath, git.CommitChangesOptions{
+				Committer: &signature,
+				Author:    &signature,
+				Message:   "clone token",
+			}))
+			doGitPushTestRepositoryFail(dstPath, "origin", "master")(t)
+		})
+
+		// the token does not give access to other repositories
+		otherURL, _ := url.Parse(fmt.Sprintf("%suser2/repo16.git", u.String()))
+		otherURL.User = url.UserPassword("user2", readToken.Token)
+		t.Run("CloneOtherRepo", doGitCloneFail(otherURL))
+
+		req = NewRequestWithJSON(t, "POST", urlStr, &api.CreateRepoCloneTokenOption{Permission: "write"})
+		resp = session.MakeRequest(t, req, http.StatusCreated)
+		var writeToken api.RepoCloneToken
+		DecodeJSON(t, resp, &writeToken)
+		assert.Equal(t, "write", writeToken.Permission)
+		cloneURL.User = url.UserPassword("user2", writeToken.Token)
+		t.Run("PushWithWriteToken", func(t *testing.T) {
+			_, err := git.NewCommand("remote", "set-url", "origin", cloneURL.String()).RunInDir(dstPath)
+			assert.NoError(t, err)
+			doGitPushTestRepository(dstPath, "origin", "master")(t)
+		})
+
+		req = NewRequest(t, "GET", urlStr)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		var tokens []*api.RepoCloneToken
+		DecodeJSON(t, resp, &tokens)
+		assert.Len(t, tokens, 2)
+		assert.Empty(t, tokens[0].Token)
+
+		// revoked tokens can not be used anymore
+		req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/repos/user2/repo1/clone-token/%d?token=%s", readToken.ID, token))
+		session.MakeRequest(t, req, http.StatusNoContent)
+		cloneURL.User = url.UserPassword("user2", readToken.Token)
+		t.Run("CloneWithRevokedToken", doGitCloneFail(cloneURL))
+
+		// users who can only read the repository can not create tokens to write to it
+		session = loginUser(t, "user4")
+		token = getTokenForLoggedInUser(t, session)
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/clone-token?token=%s", token),
+			&api.CreateRepoCloneTokenOption{Permission: "write"})
+		session.MakeRequest(t, req, http.StatusForbidden)
+	})
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICodeOwnersErrors(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		user2 := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		repo1 := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+		session := loginUser(t, user2.Name)
+		token := getTokenForLoggedInUser(t, session)
+
+		// The repository has no CODEOWNERS file yet
+		req := NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/codeowners/errors?token=%s", token)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		_, err := createFileInBranch(user2, repo1, ".gitea/CODEOWNERS", repo1.DefaultBranch, "*.md @user2\ndocs/[bad @user2\n*.go @nonexistent\n")
+		assert.NoError(t, err)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/codeowners/errors?token=%s", token)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+		var codeOwnersErrors api.CodeOwnersErrors
+		DecodeJSON(t, resp, &codeOwnersErrors)
+		if assert.Len(t, codeOwnersErrors.Errors, 2) {
+			assert.Equal(t, 2, codeOwnersErrors.Errors[0].Line)
+			assert.Equal(t, 1, codeOwnersErrors.Errors[0].Column)
+			assert.Equal(t, "Invalid pattern", codeOwnersErrors.Errors[0].Kind)
+			assert.Equal(t, ".gitea/CODEOWNERS", codeOwnersErrors.Errors[0].Path)
+			assert.Equal(t, 3, codeOwnersErrors.Errors[1].Line)
+			assert.Equal(t, 6, codeOwnersErrors.Errors[1].Column)
+			assert.Equal(t, "Unknown owner", codeOwnersErrors.Errors[1].Kind)
+			assert.Equal(t, "*.go @nonexistent", codeOwnersErrors.Errors[1].Source)
+		}
+	})
+}
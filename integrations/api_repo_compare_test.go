@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoCompare(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master...branch2?patch=true&token=%s", user.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiCompare api.Compare
+	DecodeJSON(t, resp, &apiCompare)
+
+	assert.Equal(t, 2, apiCompare.TotalCommits)
+	assert.Len(t, apiCompare.Commits, 2)
+	if assert.Len(t, apiCompare.Files, 1) {
+		file := apiCompare.Files[0]
+		assert.Equal(t, "README.md", file.Filename)
+		assert.Equal(t, "modified", file.Status)
+		assert.Equal(t, 4, file.Additions)
+		assert.Equal(t, 1, file.Deletions)
+		assert.Contains(t, file.Patch, "+++ b/README.md")
+		assert.False(t, file.PatchTruncated)
+	}
+
+	// the patch is cut off at the limit
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master...branch2?patch=true&limit=10&token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiCompare)
+	if assert.Len(t, apiCompare.Files, 1) {
+		assert.Len(t, apiCompare.Files[0].Patch, 10)
+		assert.True(t, apiCompare.Files[0].PatchTruncated)
+	}
+
+	// files not touched by the comparison are filtered out
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master...branch2?files=nonexistent.txt&token=%s", user.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiCompare)
+	assert.Empty(t, apiCompare.Files)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master...nonexistent?token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/compare/master?token=%s", user.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoDetachFork(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// user13/repo11 is a fork of user12/repo10 with an open pull request to it
+	fork := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 11}).(*models.Repository)
+	base := db.AssertExistsAndLoadBean(t, &models.Repository{ID: fork.ForkID}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: fork.OwnerID}).(*models.User)
+	urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/detach-fork", owner.Name, fork.Name)
+
+	// only repository admins may detach a fork
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestWithJSON(t, "POST", urlStr+"?token="+token, &api.DetachForkOption{})
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, owner.Name)
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", urlStr+"?token="+token, &api.DetachForkOption{})
+	session.MakeRequest(t, req, http.StatusConflict)
+	assert.True(t, db.AssertExistsAndLoadBean(t, &models.Repository{ID: fork.ID}).(*models.Repository).IsFork)
+
+	// the fork has its own objects, it is detached at once
+	req = NewRequestWithJSON(t, "POST", urlStr+"?token="+token, &api.DetachForkOption{Force: true})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var detach api.RepoDetachFork
+	DecodeJSON(t, resp, &detach)
+	assert.Equal(t, "finished", detach.Status, detach.Message)
+	assert.Equal(t, "finished", detach.Stage)
+	assert.Equal(t, base.FullName(), detach.FormerBase)
+	assert.Equal(t, setting.AppURL+fmt.Sprintf("api/v1/repos/%s/%s/detach-fork/%d", owner.Name, fork.Name, detach.ID), detach.URL)
+
+	fork = db.AssertExistsAndLoadBean(t, &models.Repository{ID: fork.ID}).(*models.Repository)
+	assert.False(t, fork.IsFork)
+	assert.EqualValues(t, 0, fork.ForkID)
+	assert.Equal(t, base.NumForks-1, db.AssertExistsAndLoadBean(t, &models.Repository{ID: base.ID}).(*models.Repository).NumForks)
+	db.AssertExistsAndLoadBean(t, &models.Action{OpType: models.ActionDetachFork, RepoID: fork.ID, Content: base.FullName()})
+
+	req = NewRequestf(t, "GET", "%s/%d?token=%s", urlStr, detach.ID, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &detach)
+	assert.Equal(t, "finished", detach.Status)
+
+	req = NewRequestWithJSON(t, "POST", urlStr+"?token="+token, &api.DetachForkOption{Force: true})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "GET", "%s/%d?token=%s", urlStr, 999999, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
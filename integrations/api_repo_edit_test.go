@@ -38,9 +38,10 @@ func getRepoEditOptionFromRepo(repo *models.Repository) *api.EditRepoOption {
 		config := unit.ExternalTrackerConfig()
 		hasIssues = true
 		externalTracker = &api.ExternalTracker{
-			ExternalTrackerURL:    config.ExternalTrackerURL,
-			ExternalTrackerFormat: config.ExternalTrackerFormat,
-			ExternalTrackerStyle:  config.ExternalTrackerStyle,
+			ExternalTrackerURL:           config.ExternalTrackerURL,
+			ExternalTrackerFormat:        config.ExternalTrackerFormat,
+			ExternalTrackerStyle:         config.ExternalTrackerStyle,
+			ExternalTrackerRegexpPattern: config.ExternalTrackerRegexpPattern,
 		}
 	}
 	hasWiki := false
@@ -174,6 +175,9 @@ func TestAPIRepoEdit(t *testing.T) {
 		assert.Equal(t, *repoEditOption.Private, *repo1editedOption.Private)
 		assert.Equal(t, *repoEditOption.HasWiki, *repo1editedOption.HasWiki)
 
+		// repo1 is archived now and can only be edited while unarchiving it
+		*repoEditOption.Archived = false
+
 		//Test editing repo1 to use internal issue and wiki (default)
 		*repoEditOption.HasIssues = true
 		repoEditOption.ExternalTracker = nil
@@ -343,3 +347,46 @@ func TestAPIRepoEdit(t *testing.T) {
 		session.MakeRequest(t, req, http.StatusForbidden)
 	})
 }
+
+func TestAPIRepoEditMergeCommitAuthorStyle(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	url := fmt.Sprintf("/api/v1/repos/user2/repo1?token=%s", token)
+
+	hasPullRequests := true
+	style := "pr-author"
+	req := NewRequestWithJSON(t, "PATCH", url, &api.EditRepoOption{HasPullRequests: &hasPullRequests, MergeCommitAuthorStyle: &style})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.Equal(t, "pr-author", repo.MergeCommitAuthorStyle)
+
+	style = "committer"
+	req = NewRequestWithJSON(t, "PATCH", url, &api.EditRepoOption{HasPullRequests: &hasPullRequests, MergeCommitAuthorStyle: &style})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPIRepoEditMergeTrailers(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	url := fmt.Sprintf("/api/v1/repos/user2/repo1?token=%s", token)
+
+	hasPullRequests := true
+	enabled := true
+	req := NewRequestWithJSON(t, "PATCH", url, &api.EditRepoOption{HasPullRequests: &hasPullRequests, AddReviewedByTrailers: &enabled})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var repo api.Repository
+	DecodeJSON(t, resp, &repo)
+	assert.True(t, repo.AddReviewedByTrailers)
+	assert.False(t, repo.AddCoAuthorTrailers)
+
+	req = NewRequestWithJSON(t, "PATCH", url, &api.EditRepoOption{HasPullRequests: &hasPullRequests, AddCoAuthorTrailers: &enabled})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &repo)
+	assert.True(t, repo.AddReviewedByTrailers)
+	assert.True(t, repo.AddCoAuthorTrailers)
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoEditExternalTrackerValidation(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/" + owner.Name + "/" + repo.Name + "?token=" + token
+
+	req := NewRequestWithJSON(t, "PATCH", urlStr, &api.EditRepoOption{
+		ExternalTracker: &api.ExternalTracker{
+			ExternalTrackerURL:    "https://tracker.example.com",
+			ExternalTrackerFormat: "https://tracker.example.com/issues/{id}",
+		},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", urlStr, &api.EditRepoOption{
+		ExternalTracker: &api.ExternalTracker{
+			ExternalTrackerURL:           "https://tracker.example.com",
+			ExternalTrackerFormat:        "https://tracker.example.com/issues/{index}",
+			ExternalTrackerStyle:         "regexp",
+			ExternalTrackerRegexpPattern: `TICKET:(\d+`,
+		},
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "PATCH", urlStr, &api.EditRepoOption{
+		ExternalTracker: &api.ExternalTracker{
+			ExternalTrackerURL:           "https://tracker.example.com",
+			ExternalTrackerFormat:        "https://tracker.example.com/issues/{index}",
+			ExternalTrackerStyle:         "regexp",
+			ExternalTrackerRegexpPattern: `TICKET:(\d+)`,
+		},
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiRepo api.Repository
+	DecodeJSON(t, resp, &apiRepo)
+	if assert.NotNil(t, apiRepo.ExternalTracker) {
+		assert.Equal(t, "regexp", apiRepo.ExternalTracker.ExternalTrackerStyle)
+		assert.Equal(t, `TICKET:(\d+)`, apiRepo.ExternalTracker.ExternalTrackerRegexpPattern)
+	}
+}
+
+func TestAPIRepoPreviewExternalTracker(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := "/api/v1/repos/" + owner.Name + "/" + repo.Name + "/settings/external_tracker/preview?token=" + token
+
+	// repo1 has no external tracker, so a format is required
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.ExternalTrackerPreviewOption{
+		Message: "fixes TICKET:123",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.ExternalTrackerPreviewOption{
+		Message:               "fixes TICKET:123",
+		ExternalTrackerFormat: "https://tracker.example.com/{user}/{index}",
+		ExternalTrackerStyle:  "regexp",
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.ExternalTrackerPreviewOption{
+		Message:                      "fixes TICKET:123 and TICKET:456",
+		ExternalTrackerFormat:        "https://tracker.example.com/{user}/{index}",
+		ExternalTrackerStyle:         "regexp",
+		ExternalTrackerRegexpPattern: `TICKET:(\d+)`,
+	})
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var preview api.ExternalTrackerPreview
+	DecodeJSON(t, resp, &preview)
+	assert.Contains(t, preview.HTML, "https://tracker.example.com/"+owner.Name+"/123")
+	assert.Equal(t, []*api.ExternalTrackerPreviewLink{
+		{Text: "TICKET:123", URL: "https://tracker.example.com/" + owner.Name + "/123"},
+		{Text: "TICKET:456", URL: "https://tracker.example.com/" + owner.Name + "/456"},
+	}, preview.Links)
+
+	// only repository admins can preview the settings
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/"+owner.Name+"/"+repo.Name+"/settings/external_tracker/preview?token="+token, &api.ExternalTrackerPreviewOption{
+		Message: "fixes TICKET:123",
+	})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
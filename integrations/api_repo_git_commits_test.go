@@ -6,6 +6,7 @@ package integrations
 
 import (
 	"net/http"
+	"strconv"
 	"testing"
 
 	"code.gitea.io/gitea/models"
@@ -74,6 +75,56 @@ func TestAPIReposGitCommitList(t *testing.T) {
 	compareCommitFiles(t, []string{"readme.md"}, apiData[2].Files)
 }
 
+func TestAPIReposGitCommitListFiltered(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	// Login as User2.
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	kases := []struct {
+		query string
+		shas  []string
+	}{
+		// user2 authored two of the commits with its activated email
+		{"author=user2", []string{"69554a64c1e6030f051e5c3f94bfbd773cd6a324", "5099b81332712fe655e34e8dd63574f503f61811"}},
+		{"author=user21@example.com", []string{"27566bd5738fc8b4e3fef3c5e72cce608537bd95"}},
+		{"author=User2", []string{"69554a64c1e6030f051e5c3f94bfbd773cd6a324", "5099b81332712fe655e34e8dd63574f503f61811"}},
+		{"path=readme.md&since=2017-08-06T17:56:00Z", []string{"69554a64c1e6030f051e5c3f94bfbd773cd6a324", "27566bd5738fc8b4e3fef3c5e72cce608537bd95"}},
+		{"author=user2@example.com&since=2017-08-06T17:56:00Z&until=2017-08-06T17:58:00Z", []string{}},
+		{"author=user2&until=2017-08-06T17:58:00Z", []string{"5099b81332712fe655e34e8dd63574f503f61811"}},
+		{"path=unknown", []string{}},
+	}
+	for _, kase := range kases {
+		req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token=%s&%s", user.Name, token, kase.query)
+		resp := session.MakeRequest(t, req, http.StatusOK)
+
+		var apiData []api.Commit
+		DecodeJSON(t, resp, &apiData)
+		shas := make([]string, 0, len(apiData))
+		for _, commit := range apiData {
+			shas = append(shas, commit.CommitMeta.SHA)
+		}
+		assert.Equal(t, kase.shas, shas, kase.query)
+		assert.Equal(t, strconv.Itoa(len(kase.shas)), resp.Header().Get("X-Total-Count"), kase.query)
+	}
+
+	// resolved users are still included without the files
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token=%s&stat=false&author=user2", user.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var apiData []api.Commit
+	DecodeJSON(t, resp, &apiData)
+	if assert.Len(t, apiData, 2) {
+		assert.Empty(t, apiData[0].Files)
+		if assert.NotNil(t, apiData[0].Author) {
+			assert.Equal(t, user.Name, apiData[0].Author.UserName)
+		}
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo16/commits?token=%s&since=yesterday", user.Name, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
 func TestAPIReposGitCommitListPage2Empty(t *testing.T) {
 	defer prepareTestEnv(t)()
 	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
@@ -132,3 +183,36 @@ func TestDownloadCommitDiffOrPatch(t *testing.T) {
 		resp.Body.String())
 
 }
+
+func TestAPIApplyCommit(t *testing.T) {
+	defer prepareTestEnv(t)()
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	// The changes of the commit are already on master
+	req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/"+commitID+"/cherry-pick?token="+token, &api.ApplyCommitOption{})
+	resp := session.MakeRequest(t, req, http.StatusConflict)
+	var conflict api.ApplyCommitConflict
+	DecodeJSON(t, resp, &conflict)
+	assert.Equal(t, []string{"README.md"}, conflict.Files)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/"+commitID+"/revert?token="+token, &api.ApplyCommitOption{
+		NewBranchName: "revert-initial-commit",
+	})
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiCommit api.Commit
+	DecodeJSON(t, resp, &apiCommit)
+	assert.Equal(t, "Revert \"Initial commit\"\n\nThis reverts commit "+commitID+".\n", apiCommit.RepoCommit.Message)
+	if assert.Len(t, apiCommit.Parents, 1) {
+		assert.Equal(t, commitID, apiCommit.Parents[0].SHA)
+	}
+
+	// Users without write access can't apply commits
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/git/commits/"+commitID+"/revert?token="+token, &api.ApplyCommitOption{})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
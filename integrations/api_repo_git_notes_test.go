@@ -5,6 +5,7 @@
 package integrations
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
@@ -38,3 +39,34 @@ func TestAPIReposGitNotes(t *testing.T) {
 		assert.Equal(t, "This is a test note\n", apiData.Message)
 	})
 }
+
+func TestAPIReposGitNotesSetAndDelete(t *testing.T) {
+	onGiteaRun(t, func(*testing.T, *url.URL) {
+		user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		session := loginUser(t, user.Name)
+		token := getTokenForLoggedInUser(t, session)
+
+		commitID := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+		// anonymous users can't modify notes
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/repo1/git/notes/%s", user.Name, commitID), &api.SetNoteOption{Message: "anonymous"})
+		MakeRequest(t, req, http.StatusUnauthorized)
+
+		req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/%s/repo1/git/notes/%s?token=%s", user.Name, commitID, token), &api.SetNoteOption{Message: "Updated note\n"})
+		resp := session.MakeRequest(t, req, http.StatusCreated)
+
+		var apiData api.Note
+		DecodeJSON(t, resp, &apiData)
+		assert.Equal(t, "Updated note\n", apiData.Message)
+		assert.Equal(t, user.GitName(), apiData.Commit.RepoCommit.Author.Name)
+
+		req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/repo1/git/notes/%s?token=%s", user.Name, commitID, token)
+		session.MakeRequest(t, req, http.StatusNoContent)
+
+		req = NewRequestf(t, "GET", "/api/v1/repos/%s/repo1/git/notes/%s?token=%s", user.Name, commitID, token)
+		session.MakeRequest(t, req, http.StatusNotFound)
+
+		req = NewRequestf(t, "DELETE", "/api/v1/repos/%s/repo1/git/notes/%s?token=%s", user.Name, commitID, token)
+		session.MakeRequest(t, req, http.StatusNotFound)
+	})
+}
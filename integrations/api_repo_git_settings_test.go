@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoGitSettings(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	session := loginUser(t, owner.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git_settings?token=%s", owner.Name, repo.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var settings api.RepoGitSettings
+	DecodeJSON(t, resp, &settings)
+	assert.False(t, settings.DisableDumbHTTP)
+	assert.Empty(t, settings.DefaultCloneFilter)
+
+	req = NewRequestf(t, "GET", "/%s/%s.git/HEAD", owner.Name, repo.Name)
+	MakeRequest(t, req, http.StatusOK)
+
+	invalid := "sparse:oid=master"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/"+owner.Name+"/"+repo.Name+"/git_settings?token="+token, &api.EditRepoGitSettingsOption{
+		DefaultCloneFilter: &invalid,
+	})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	disabled, filter := true, "blob:none"
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/"+owner.Name+"/"+repo.Name+"/git_settings?token="+token, &api.EditRepoGitSettingsOption{
+		DisableDumbHTTP:    &disabled,
+		DefaultCloneFilter: &filter,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &settings)
+	assert.True(t, settings.DisableDumbHTTP)
+	assert.Equal(t, "blob:none", settings.DefaultCloneFilter)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s?token=%s", owner.Name, repo.Name, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var apiRepo api.Repository
+	DecodeJSON(t, resp, &apiRepo)
+	assert.Equal(t, "blob:none", apiRepo.CloneFilter)
+
+	req = NewRequestf(t, "GET", "/%s/%s.git/HEAD", owner.Name, repo.Name)
+	MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/repos/"+owner.Name+"/"+repo.Name+"/git_settings?token="+token, &api.EditRepoGitSettingsOption{
+		ResetToDefaults: true,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &settings)
+	assert.False(t, settings.DisableDumbHTTP)
+	assert.Empty(t, settings.DefaultCloneFilter)
+
+	// only repository admins can edit the settings
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/git_settings?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoHealthCheck(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	// only repository admins may check the health
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "POST", "/api/v1/repos/%s/%s/health-check?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, owner.Name)
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "POST", "/api/v1/repos/%s/%s/health-check?token=%s", owner.Name, repo.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusAccepted)
+	var check api.RepoHealthCheck
+	DecodeJSON(t, resp, &check)
+	assert.Equal(t, setting.AppURL+fmt.Sprintf("api/v1/repos/%s/%s/health-check/%d", owner.Name, repo.Name, check.ID), check.URL)
+
+	// wait for the health check to finish
+	statusURL := "/" + strings.TrimPrefix(check.URL, setting.AppURL)
+	for i := 0; i < 50 && check.Status != "finished" && check.Status != "failed"; i++ {
+		time.Sleep(100 * time.Millisecond)
+		req = NewRequestf(t, "GET", "%s?token=%s", statusURL, token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &check)
+	}
+	assert.Equal(t, "finished", check.Status, check.Message)
+	if assert.Len(t, check.Results, 4) {
+		assert.Equal(t, "fsck", check.Results[0].Name)
+		assert.True(t, check.Results[0].OK, check.Results[0].Message)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/health-check/%d?token=%s", owner.Name, repo.Name, 999999, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -0,0 +1,96 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoListPermissions(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+
+	// only repository admins may list the permissions
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "GET", "/api/v1/repos/%s/%s/permissions?token=%s", owner.Name, repo.Name, token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/permissions?token=%s", owner.Name, repo.Name, token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var permissions api.RepoPermissions
+	DecodeJSON(t, resp, &permissions)
+	assert.Empty(t, permissions.DeployKeys)
+	if assert.Len(t, permissions.Users, 2) {
+		assert.Equal(t, "user2", permissions.Users[0].User.UserName)
+		assert.Equal(t, "owner", permissions.Users[0].Permission)
+		assert.Len(t, permissions.Users[0].Sources, 3)
+
+		assert.Equal(t, "user4", permissions.Users[1].User.UserName)
+		assert.Equal(t, "write", permissions.Users[1].Permission)
+		if assert.Len(t, permissions.Users[1].Sources, 1) {
+			assert.Equal(t, "team", permissions.Users[1].Sources[0].Type)
+			assert.Equal(t, "team1", permissions.Users[1].Sources[0].Team.Name)
+		}
+	}
+}
+
+func TestAPIRepoGetCollaboratorPermission(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	// users other than repository admins may only get their own permission
+	session := loginUser(t, "user4")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/collaborators/user2/permission?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/collaborators/user4/permission?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var permission api.RepoCollaboratorPermission
+	DecodeJSON(t, resp, &permission)
+	assert.Equal(t, "user4", permission.User.UserName)
+	assert.Equal(t, "write", permission.Permission)
+	if assert.Len(t, permission.Sources, 1) {
+		assert.Equal(t, "team", permission.Sources[0].Type)
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user5/repo4/collaborators/user4/permission?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	permission = api.RepoCollaboratorPermission{}
+	DecodeJSON(t, resp, &permission)
+	assert.Equal(t, "write", permission.Permission)
+	assert.Equal(t, "write", permission.Units["repo.code"])
+	if assert.Len(t, permission.Sources, 1) {
+		assert.Equal(t, "collaborator", permission.Sources[0].Type)
+	}
+
+	// the owners of a repository are admins
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/collaborators/user2/permission?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	permission = api.RepoCollaboratorPermission{}
+	DecodeJSON(t, resp, &permission)
+	assert.Equal(t, "admin", permission.Permission)
+	assert.Equal(t, "admin", permission.Units["repo.code"])
+	assert.Len(t, permission.Sources, 3)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/collaborators/user4/permission?token=%s", token)
+	session.MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user3/repo3/collaborators/nonexistent/permission?token=%s", token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+}
@@ -50,9 +50,28 @@ func TestAPIRepoTags(t *testing.T) {
 			assert.EqualValues(t, newTag.Message, tag.Message)
 			assert.EqualValues(t, "nice!\nand some text", tag.Message)
 			assert.EqualValues(t, newTag.Commit.SHA, tag.Commit.SHA)
+			// the tag is annotated but not signed
+			assert.NotNil(t, tag.Tagger)
+			if assert.NotNil(t, tag.Verification) {
+				assert.False(t, tag.Verification.Verified)
+			}
+		} else {
+			assert.Nil(t, tag.Tagger)
+			assert.Nil(t, tag.Verification)
 		}
 	}
 
+	// tags can be sorted by version and are paginated
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/tags?sort=semver&limit=1&token=%s", user.Name, repoName, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &tags)
+	assert.Equal(t, "2", resp.Header().Get("X-Total-Count"))
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "v1.1", tags[0].Name)
+	}
+	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/tags?sort=name&token=%s", user.Name, repoName, token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
 	// get created tag
 	req = NewRequestf(t, "GET", "/api/v1/repos/%s/%s/tags/%s?token=%s", user.Name, repoName, newTag.Name, token)
 	resp = session.MakeRequest(t, req, http.StatusOK)
@@ -290,6 +290,46 @@ func TestAPIOrgRepos(t *testing.T) {
 	}
 }
 
+func TestAPIOrgReposSort(t *testing.T) {
+	defer prepareTestEnv(t)()
+	for id, size := range map[int64]int64{3: 1000, 5: 2000, 32: 3000} {
+		repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: id}).(*models.Repository)
+		repo.Size = size
+		assert.NoError(t, models.UpdateRepositoryCols(repo, "size"))
+	}
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	listNames := func(query string, expectedStatus int) []string {
+		req := NewRequestf(t, "GET", "/api/v1/orgs/user3/repos?token=%s&%s", token, query)
+		resp := session.MakeRequest(t, req, expectedStatus)
+		if expectedStatus != http.StatusOK {
+			return nil
+		}
+		assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+		var apiRepos []*api.Repository
+		DecodeJSON(t, resp, &apiRepos)
+		names := make([]string, 0, len(apiRepos))
+		for _, repo := range apiRepos {
+			assert.Nil(t, repo.LastPushedAt)
+			names = append(names, repo.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, []string{"repo3", "repo5", "repo21"}, listNames("", http.StatusOK))
+	assert.Equal(t, []string{"repo21", "repo5", "repo3"}, listNames("sort=size&order=desc", http.StatusOK))
+	assert.Equal(t, []string{"repo3", "repo5", "repo21"}, listNames("sort=size", http.StatusOK))
+	assert.Equal(t, "repo3", listNames("sort=open_pulls&order=desc", http.StatusOK)[0])
+	assert.Equal(t, "repo21", listNames("sort=open_issues", http.StatusOK)[0])
+	assert.Equal(t, []string{"repo21"}, listNames("sort=size&order=desc&limit=1", http.StatusOK))
+	req := NewRequestf(t, "GET", "/api/v1/orgs/user3/repos?token=%s&sort=size&limit=1", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Header().Get("Link"), `rel="next"`)
+	listNames("sort=stars", http.StatusUnprocessableEntity)
+	listNames("sort=size&order=up", http.StatusUnprocessableEntity)
+}
+
 func TestAPIGetRepoByIDUnauthorized(t *testing.T) {
 	defer prepareTestEnv(t)()
 	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
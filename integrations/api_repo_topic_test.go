@@ -156,3 +156,56 @@ func TestAPIRepoTopic(t *testing.T) {
 	res = session.MakeRequest(t, req, http.StatusForbidden)
 
 }
+
+func TestAPITopicRepos(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/topics/golang/repos")
+	res := MakeRequest(t, req, http.StatusOK)
+	var repos []*api.Repository
+	DecodeJSON(t, res, &repos)
+	assert.EqualValues(t, "2", res.Header().Get("x-total-count"))
+	assert.Len(t, repos, 2)
+
+	req = NewRequest(t, "GET", "/api/v1/topics/golang/repos?sort=updated&limit=1")
+	res = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, res, &repos)
+	assert.Len(t, repos, 1)
+
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/topics/golang/repos?sort=name"), http.StatusUnprocessableEntity)
+	MakeRequest(t, NewRequest(t, "GET", "/api/v1/topics/kubernets/repos"), http.StatusNotFound)
+}
+
+func TestAPIAdminTopics(t *testing.T) {
+	defer prepareTestEnv(t)()
+	// user1 is an admin user
+	session := loginUser(t, "user1")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "PATCH", "/api/v1/admin/topics/database?token="+token, &api.RenameTopicOption{Name: "golang"})
+	session.MakeRequest(t, req, http.StatusConflict)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/topics/kubernets?token="+token, &api.RenameTopicOption{Name: "kubernetes"})
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/topics/database?token="+token, &api.RenameTopicOption{Name: "Databases"})
+	res := session.MakeRequest(t, req, http.StatusOK)
+	var topic *api.TopicResponse
+	DecodeJSON(t, res, &topic)
+	assert.EqualValues(t, 2, topic.ID)
+	assert.EqualValues(t, "databases", topic.Name)
+
+	req = NewRequestWithJSON(t, "POST", "/api/v1/admin/topics/databases/merge?token="+token, &api.MergeTopicOption{Target: "golang"})
+	res = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, res, &topic)
+	assert.EqualValues(t, 1, topic.ID)
+	db.AssertNotExistsBean(t, &models.Topic{ID: 2})
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.ElementsMatch(t, []string{"golang", "SQL"}, repo.Topics)
+
+	// only admins can manage topics
+	session = loginUser(t, "user2")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "PATCH", "/api/v1/admin/topics/golang?token="+token, &api.RenameTopicOption{Name: "go"})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
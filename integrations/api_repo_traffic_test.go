@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIRepoTraffic(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequest(t, "GET", "/user2/repo1")
+	session.MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/user2/repo1")
+	MakeRequest(t, req, http.StatusOK)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/traffic/views?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusOK)
+	var views api.RepoViewTraffic
+	DecodeJSON(t, resp, &views)
+	assert.Len(t, views.Views, 14)
+	// other tests may have viewed the repository today as well
+	assert.GreaterOrEqual(t, views.Count, int64(2))
+	assert.GreaterOrEqual(t, views.Uniques, int64(2))
+	assert.Equal(t, views.Count, views.Views[13].Count)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/traffic/clones?per=week&token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var clones api.RepoCloneTraffic
+	DecodeJSON(t, resp, &clones)
+	assert.GreaterOrEqual(t, len(clones.Clones), 2)
+
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/traffic/clones?per=month&token=%s", token)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only repository admins can see the traffic
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestf(t, "GET", "/api/v1/repos/user2/repo1/traffic/views?token=%s", token)
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -61,4 +61,16 @@ func TestAPIExposedSettings(t *testing.T) {
 		MaxFiles:     setting.Attachment.MaxFiles,
 		MaxSize:      setting.Attachment.MaxSize,
 	}, attachment)
+
+	ssh := new(api.GeneralSSHSettings)
+	req = NewRequest(t, "GET", "/api/v1/settings/ssh")
+	resp = MakeRequest(t, req, http.StatusOK)
+
+	DecodeJSON(t, resp, &ssh)
+	assert.EqualValues(t, &api.GeneralSSHSettings{
+		Disabled:                  setting.SSH.Disabled,
+		MinimumKeySizeCheck:       setting.SSH.MinimumKeySizeCheck,
+		MinimumKeySizeCheckOnAuth: setting.SSH.MinimumKeySizeCheckOnAuth,
+		MinimumKeySizes:           setting.SSH.MinimumKeySizes,
+	}, ssh)
 }
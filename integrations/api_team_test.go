@@ -113,6 +113,96 @@ func TestAPITeam(t *testing.T) {
 	req = NewRequestf(t, "DELETE", "/api/v1/teams/%d?token="+token, teamID)
 	session.MakeRequest(t, req, http.StatusNoContent)
 	db.AssertNotExistsBean(t, &models.Team{ID: teamID})
+
+	// Create team with an access mode per unit.
+	teamToCreate = &api.CreateTeamOption{
+		Name:       "team2",
+		Permission: "read",
+		Units:      []string{"repo.code", "repo.wiki"},
+		UnitsMap:   map[string]string{"repo.wiki": "write"},
+	}
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/%s/teams?token=%s", org.Name, token), teamToCreate)
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiTeam)
+	assert.EqualValues(t, map[string]string{"repo.code": "read", "repo.wiki": "write"}, apiTeam.UnitsMap)
+
+	// Refuse unknown units and access modes.
+	teamToEdit = &api.EditTeamOption{Name: "team2", UnitsMap: map[string]string{"repo.wiki": "owner"}}
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/teams/%d?token=%s", apiTeam.ID, token), teamToEdit)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+	teamToEdit = &api.EditTeamOption{Name: "team2", UnitsMap: map[string]string{"repo.unknown": "read"}}
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/teams/%d?token=%s", apiTeam.ID, token), teamToEdit)
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+}
+
+func TestAPITeamReviewNotification(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	session := loginUser(t, user.Name)
+	token := getTokenForLoggedInUser(t, session)
+
+	org := db.AssertExistsAndLoadBean(t, &models.User{ID: 6}).(*models.User)
+
+	// Create team, the review notification defaults to emailing all members.
+	teamToCreate := &api.CreateTeamOption{
+		Name:       "reviewers",
+		Permission: "write",
+		Units:      []string{"repo.code", "repo.pulls"},
+	}
+	req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/orgs/%s/teams?token=%s", org.Name, token), teamToCreate)
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var apiTeam api.Team
+	DecodeJSON(t, resp, &apiTeam)
+	assert.Equal(t, models.TeamReviewNotificationEmailAll, apiTeam.ReviewNotification)
+	teamID := apiTeam.ID
+
+	// Edit review notification.
+	webhookOnly := models.TeamReviewNotificationWebhookOnly
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/teams/%d?token=%s", teamID, token), &api.EditTeamOption{ReviewNotification: &webhookOnly})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiTeam)
+	assert.Equal(t, models.TeamReviewNotificationWebhookOnly, apiTeam.ReviewNotification)
+	db.AssertExistsAndLoadBean(t, &models.Team{ID: teamID, ReviewNotification: models.TeamReviewNotificationWebhookOnly})
+
+	invalid := "sms"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/teams/%d?token=%s", teamID, token), &api.EditTeamOption{ReviewNotification: &invalid})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// Set, replace and delete the review hook.
+	req = NewRequestf(t, "GET", "/api/v1/teams/%d/review_hook?token=%s", teamID, token)
+	session.MakeRequest(t, req, http.StatusNotFound)
+
+	hookOption := &api.CreateHookOption{
+		Type: "slack",
+		Config: api.CreateHookOptionConfig{
+			"url":          "http://example.com/slack",
+			"content_type": "json",
+			"channel":      "#reviews",
+		},
+		Active: true,
+	}
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/teams/%d/review_hook?token=%s", teamID, token), hookOption)
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	var apiHook api.Hook
+	DecodeJSON(t, resp, &apiHook)
+	assert.Equal(t, "slack", apiHook.Type)
+	assert.Equal(t, []string{"pull_request"}, apiHook.Events)
+	firstHookID := apiHook.ID
+
+	req = NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/teams/%d/review_hook?token=%s", teamID, token), hookOption)
+	resp = session.MakeRequest(t, req, http.StatusCreated)
+	DecodeJSON(t, resp, &apiHook)
+	db.AssertNotExistsBean(t, &models.Webhook{ID: firstHookID})
+
+	req = NewRequestf(t, "GET", "/api/v1/teams/%d/review_hook?token=%s", teamID, token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &apiHook)
+	assert.Equal(t, "#reviews", apiHook.Config["channel"])
+
+	req = NewRequestf(t, "DELETE", "/api/v1/teams/%d/review_hook?token=%s", teamID, token)
+	session.MakeRequest(t, req, http.StatusNoContent)
+	db.AssertNotExistsBean(t, &models.Webhook{TeamID: teamID})
 }
 
 func checkTeamResponse(t *testing.T, apiTeam *api.Team, name, description string, includesAllRepositories bool, permission string, units []string) {
@@ -11,6 +11,8 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
 )
 
 // TestAPICreateAndDeleteToken tests that token that was just created can be deleted
@@ -62,3 +64,44 @@ func TestAPIDeleteMissingToken(t *testing.T) {
 	req = AddBasicAuthHeader(req, user.Name)
 	MakeRequest(t, req, http.StatusNotFound)
 }
+
+// TestAPIUnusedTokens tests the admin report of unused tokens
+func TestAPIUnusedTokens(t *testing.T) {
+	defer prepareTestEnv(t)()
+	admin := db.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+
+	// using a token records its last use
+	token := getTokenForLoggedInUser(t, loginUser(t, admin.Name))
+	req := NewRequestf(t, "GET", "/api/v1/users/%s/tokens?token=%s", admin.Name, token)
+	resp := MakeRequest(t, req, http.StatusOK)
+	var tokens []*api.AccessToken
+	DecodeJSON(t, resp, &tokens)
+	for _, apiToken := range tokens {
+		if apiToken.TokenLastEight == token[len(token)-8:] {
+			assert.NotNil(t, apiToken.LastUsed)
+		} else {
+			assert.Nil(t, apiToken.LastUsed)
+		}
+	}
+
+	req = NewRequestf(t, "GET", "/api/v1/admin/tokens/unused?days=30&token=%s", token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &tokens)
+	assert.EqualValues(t, "3", resp.Header().Get("X-Total-Count"))
+	if assert.Len(t, tokens, 3) {
+		assert.NotNil(t, tokens[0].Owner)
+	}
+
+	MakeRequest(t, NewRequestf(t, "GET", "/api/v1/admin/tokens/unused?days=-1&token=%s", token), http.StatusUnprocessableEntity)
+
+	req = NewRequestf(t, "DELETE", "/api/v1/admin/tokens/unused?days=30&token=%s", token)
+	MakeRequest(t, req, http.StatusNoContent)
+	db.AssertNotExistsBean(t, &models.AccessToken{ID: 1})
+	db.AssertNotExistsBean(t, &models.AccessToken{ID: 3})
+
+	// the token which has just been used is kept
+	req = NewRequestf(t, "GET", "/api/v1/admin/tokens/unused?token=%s", token)
+	resp = MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &tokens)
+	assert.Empty(t, tokens)
+}
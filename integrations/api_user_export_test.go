@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"archive/zip"
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserExport(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req := NewRequestf(t, "POST", "/api/v1/user/export?token=%s", token)
+	resp := session.MakeRequest(t, req, http.StatusAccepted)
+	var export api.UserExport
+	DecodeJSON(t, resp, &export)
+
+	// only one export per day
+	req = NewRequestf(t, "POST", "/api/v1/user/export?token=%s", token)
+	resp = session.MakeRequest(t, req, http.StatusTooManyRequests)
+	assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+
+	// wait for the export to finish
+	var exports []*api.UserExport
+	for i := 0; i < 50; i++ {
+		req = NewRequestf(t, "GET", "/api/v1/user/export?token=%s", token)
+		resp = session.MakeRequest(t, req, http.StatusOK)
+		DecodeJSON(t, resp, &exports)
+		if assert.Len(t, exports, 1) && (exports[0].Status == "finished" || exports[0].Status == "failed") {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Equal(t, export.ID, exports[0].ID)
+	assert.Equal(t, "finished", exports[0].Status, exports[0].Message)
+	assert.NotEmpty(t, exports[0].DownloadURL)
+
+	// the signed link is enough to download the archive
+	downloadURL := "/" + strings.TrimPrefix(exports[0].DownloadURL, setting.AppURL)
+	resp = MakeRequest(t, NewRequest(t, "GET", downloadURL), http.StatusOK)
+	archive, err := zip.NewReader(bytes.NewReader(resp.Body.Bytes()), int64(resp.Body.Len()))
+	assert.NoError(t, err)
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	for _, name := range []string{"profile.json", "emails.json", "ssh_keys.json", "gpg_keys.json", "repositories.json", "issues.json",
+		"pull_requests.json", "comments.json", "starred.json", "followers.json", "following.json", "activity.json"} {
+		assert.Contains(t, files, name)
+	}
+	if f, ok := files["profile.json"]; ok {
+		r, err := f.Open()
+		assert.NoError(t, err)
+		var profile api.User
+		assert.NoError(t, json.NewDecoder(r).Decode(&profile))
+		r.Close()
+		assert.Equal(t, "user2", profile.UserName)
+	}
+
+	MakeRequest(t, NewRequest(t, "GET", strings.Replace(downloadURL, "signature=", "signature=0", 1)), http.StatusNotFound)
+}
@@ -0,0 +1,57 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIUserProfileReadme(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	req := NewRequest(t, "GET", "/api/v1/users/user2/profile_readme")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo, err := repository.CreateRepository(user, user, models.CreateRepoOptions{
+		Name:     ".profile",
+		AutoInit: true,
+		Readme:   "Default",
+	})
+	assert.NoError(t, err)
+
+	req = NewRequest(t, "GET", "/api/v1/users/user2/profile_readme")
+	resp := MakeRequest(t, req, http.StatusOK)
+	var readme api.ProfileReadme
+	DecodeJSON(t, resp, &readme)
+	assert.Equal(t, ".profile", readme.Repository)
+	assert.Equal(t, "README.md", readme.FileName)
+	assert.Contains(t, readme.Content, "# .profile")
+	assert.Contains(t, readme.HTML, "<h1")
+
+	req = NewRequest(t, "GET", "/user2")
+	resp = MakeRequest(t, req, http.StatusOK)
+	assert.Contains(t, resp.Body.String(), `id="profile-readme"`)
+
+	// a private profile repository is only shown to users who can read it
+	repo.IsPrivate = true
+	assert.NoError(t, models.UpdateRepository(repo, true))
+
+	req = NewRequest(t, "GET", "/api/v1/users/user2/profile_readme")
+	MakeRequest(t, req, http.StatusNotFound)
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	req = NewRequest(t, "GET", "/api/v1/users/user2/profile_readme?token="+token)
+	session.MakeRequest(t, req, http.StatusOK)
+}
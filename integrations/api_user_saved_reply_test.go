@@ -0,0 +1,81 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPISavedReplies(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+
+	req := NewRequestWithJSON(t, "POST", "/api/v1/user/saved_replies?token="+token, &api.CreateSavedReplyOption{
+		Title: "Thanks",
+		Body:  "Thanks for the **report**!",
+	})
+	resp := session.MakeRequest(t, req, http.StatusCreated)
+	var reply api.SavedReply
+	DecodeJSON(t, resp, &reply)
+	assert.Equal(t, "Thanks", reply.Title)
+	assert.Equal(t, "Thanks for the **report**!", reply.Body)
+	assert.EqualValues(t, 1, reply.Position)
+	db.AssertExistsAndLoadBean(t, &models.SavedReply{ID: reply.ID, OwnerID: 2})
+
+	newTitle := "Thank you"
+	req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/user/saved_replies/%d?token=%s", reply.ID, token), &api.EditSavedReplyOption{
+		Title: &newTitle,
+	})
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	DecodeJSON(t, resp, &reply)
+	assert.Equal(t, "Thank you", reply.Title)
+
+	req = NewRequest(t, "GET", "/api/v1/user/saved_replies?token="+token)
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var replies []*api.SavedReply
+	DecodeJSON(t, resp, &replies)
+	assert.Len(t, replies, 1)
+
+	// organization replies can only be created by owners but are listed for all members
+	req = NewRequestWithJSON(t, "POST", "/api/v1/orgs/user3/saved_replies?token="+token, &api.CreateSavedReplyOption{
+		Title: "Org",
+		Body:  "Org reply",
+	})
+	session.MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequest(t, "GET", "/user/saved_replies")
+	resp = session.MakeRequest(t, req, http.StatusOK)
+	var available []struct {
+		Title string `json:"title"`
+		Owner string `json:"owner"`
+	}
+	DecodeJSON(t, resp, &available)
+	if assert.Len(t, available, 2) {
+		assert.Equal(t, "user2", available[0].Owner)
+		assert.Equal(t, "user3", available[1].Owner)
+	}
+
+	// other users can't access the saved replies
+	session4 := loginUser(t, "user4")
+	token4 := getTokenForLoggedInUser(t, session4)
+	req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/user/saved_replies/%d?token=%s", reply.ID, token4))
+	session4.MakeRequest(t, req, http.StatusNotFound)
+	req = NewRequest(t, "GET", "/api/v1/orgs/user3/saved_replies?token="+token4)
+	session4.MakeRequest(t, req, http.StatusForbidden)
+
+	req = NewRequest(t, "DELETE", fmt.Sprintf("/api/v1/user/saved_replies/%d?token=%s", reply.ID, token))
+	session.MakeRequest(t, req, http.StatusNoContent)
+	db.AssertNotExistsBean(t, &models.SavedReply{ID: reply.ID})
+}
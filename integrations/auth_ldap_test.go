@@ -11,6 +11,8 @@ import (
 	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/services/auth"
 
 	"github.com/stretchr/testify/assert"
@@ -97,10 +99,10 @@ func getLDAPServerHost() string {
 	return host
 }
 
-func addAuthSourceLDAP(t *testing.T, sshKeyAttribute string) {
+func addAuthSourceLDAP(t *testing.T, sshKeyAttribute string, groupTeamMapParams ...string) {
 	session := loginUser(t, "user1")
 	csrf := GetCSRF(t, session, "/admin/auths/new")
-	req := NewRequestWithValues(t, "POST", "/admin/auths/new", map[string]string{
+	values := map[string]string{
 		"_csrf":                    csrf,
 		"type":                     "2",
 		"name":                     "ldap",
@@ -119,7 +121,12 @@ func addAuthSourceLDAP(t *testing.T, sshKeyAttribute string) {
 		"attribute_ssh_public_key": sshKeyAttribute,
 		"is_sync_enabled":          "on",
 		"is_active":                "on",
-	})
+	}
+	// pairs of form field names and values
+	for i := 0; i+1 < len(groupTeamMapParams); i += 2 {
+		values[groupTeamMapParams[i]] = groupTeamMapParams[i+1]
+	}
+	req := NewRequestWithValues(t, "POST", "/admin/auths/new", values)
 	session.MakeRequest(t, req, http.StatusFound)
 }
 
@@ -249,6 +256,42 @@ func TestLDAPUserSync(t *testing.T) {
 	}
 }
 
+func TestLDAPGroupTeamSync(t *testing.T) {
+	if skipLDAPTests() {
+		t.Skip()
+		return
+	}
+	defer prepareTestEnv(t)()
+	addAuthSourceLDAP(t, "",
+		"group_member_uid", "member",
+		"user_uid", "dn",
+		"group_team_map", `{"cn=ship_crew,ou=people,dc=planetexpress,dc=com": {"org26": ["team11"]}, "cn=admin_staff,ou=people,dc=planetexpress,dc=com": {"non-existent": ["non-existent"]}}`,
+		"group_team_map_removal", "on",
+	)
+	auth.SyncExternalUsers(context.Background(), true)
+
+	team := db.AssertExistsAndLoadBean(t, &models.Team{ID: 11}).(*models.Team)
+	for _, u := range gitLDAPUsers {
+		user, err := models.GetUserByName(u.UserName)
+		assert.NoError(t, err)
+		isMember, err := models.IsTeamMember(team.OrgID, team.ID, user.ID)
+		assert.NoError(t, err)
+		// the members of the ship crew are the non admin users
+		assert.Equal(t, !u.IsAdmin, isMember, u.UserName)
+	}
+	// unknown organizations in the mapping are reported to the admins
+	db.AssertExistsAndLoadBean(t, &models.Notice{Type: models.NoticeTask}, db.Cond("description LIKE ?", "%organization non-existent does not exist%"))
+
+	// members which are not in the group anymore are removed
+	professor, err := models.GetUserByName("professor")
+	assert.NoError(t, err)
+	assert.NoError(t, models.AddTeamMembers(team, []int64{professor.ID}))
+	auth.SyncExternalUsers(context.Background(), true)
+	isMember, err := models.IsTeamMember(team.OrgID, team.ID, professor.ID)
+	assert.NoError(t, err)
+	assert.False(t, isMember)
+}
+
 func TestLDAPUserSigninFailed(t *testing.T) {
 	if skipLDAPTests() {
 		t.Skip()
@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitWikiOnlyWriter(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		permission, wikiPermission := "read", "write"
+		req := NewRequestWithJSON(t, "PUT", fmt.Sprintf("/api/v1/repos/user2/repo1/collaborators/user4?token=%s", token), &api.AddCollaboratorOption{
+			Permission:     &permission,
+			WikiPermission: &wikiPermission,
+		})
+		session.MakeRequest(t, req, http.StatusNoContent)
+
+		commitChange := func(t *testing.T, dstPath string) {
+			assert.NoError(t, os.WriteFile(filepath.Join(dstPath, "wiki-writer.md"), []byte("# Written by a wiki writer\n"), 0644))
+			assert.NoError(t, git.AddChanges(dstPath, true))
+			signature := git.Signature{
+				Email: "user4@example.com",
+				Name:  "User Four",
+				When:  time.Now(),
+			}
+			assert.NoError(t, git.CommitChanges(dstPath, git.CommitChangesOptions{
+				Committer: &signature,
+				Author:    &signature,
+				Message:   "Change by a wiki writer",
+			}))
+		}
+
+		t.Run("PushToWiki", func(t *testing.T) {
+			dstPath, err := os.MkdirTemp("", "wiki_writer_wiki")
+			assert.NoError(t, err)
+			defer util.RemoveAll(dstPath)
+
+			wikiURL, _ := url.Parse(fmt.Sprintf("%suser2/repo1.wiki.git", u.String()))
+			wikiURL.User = url.UserPassword("user4", userPassword)
+			assert.NoError(t, git.CloneWithArgs(context.Background(), wikiURL.String(), dstPath, allowLFSFilters(), git.CloneRepoOptions{}))
+			commitChange(t, dstPath)
+			doGitPushTestRepository(dstPath, "origin", "master")(t)
+		})
+
+		t.Run("PushToCode", func(t *testing.T) {
+			dstPath, err := os.MkdirTemp("", "wiki_writer_code")
+			assert.NoError(t, err)
+			defer util.RemoveAll(dstPath)
+
+			codeURL, _ := url.Parse(fmt.Sprintf("%suser2/repo1.git", u.String()))
+			codeURL.User = url.UserPassword("user4", userPassword)
+			doGitClone(dstPath, codeURL)(t)
+			commitChange(t, dstPath)
+			doGitPushTestRepositoryFail(dstPath, "origin", "master")(t)
+		})
+	})
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	mirror_service "code.gitea.io/gitea/services/mirror"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirrorConvert(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	upstream := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	upstreamPath := models.RepoPath(user.Name, upstream.Name)
+
+	repo, err := repository.CreateRepository(user, user, models.CreateRepoOptions{
+		Name:     "test_convert_mirror",
+		AutoInit: true,
+		Readme:   "Default",
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, mirror_service.ConvertToMirror(repo, upstreamPath, upstreamPath, 0))
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository)
+	assert.True(t, repo.IsMirror)
+	assert.Equal(t, upstreamPath, repo.OriginalURL)
+	db.AssertExistsAndLoadBean(t, &models.Mirror{RepoID: repo.ID})
+
+	assert.True(t, mirror_service.SyncPullMirror(context.Background(), repo.ID))
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	assert.True(t, gitRepo.IsBranchExist("branch2"))
+	gitRepo.Close()
+
+	assert.NoError(t, mirror_service.ConvertToNormal(repo))
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository)
+	assert.False(t, repo.IsMirror)
+	db.AssertNotExistsBean(t, &models.Mirror{RepoID: repo.ID})
+	_, err = git.NewCommand("remote", "get-url", "origin").RunInDir(repo.RepoPath())
+	assert.Error(t, err)
+}
+
+func TestAPIRepoConvert(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	session := loginUser(t, "user2")
+	token := getTokenForLoggedInUser(t, session)
+	urlStr := fmt.Sprintf("/api/v1/repos/user2/repo1/convert?token=%s", token)
+
+	req := NewRequestWithJSON(t, "POST", urlStr, &api.ConvertRepoOption{To: "normal"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.ConvertRepoOption{To: "mirror"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	req = NewRequestWithJSON(t, "POST", urlStr, &api.ConvertRepoOption{To: "fork"})
+	session.MakeRequest(t, req, http.StatusUnprocessableEntity)
+
+	// only the owner may convert a repository
+	session = loginUser(t, "user4")
+	token = getTokenForLoggedInUser(t, session)
+	req = NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/repos/user2/repo1/convert?token=%s", token), &api.ConvertRepoOption{To: "normal"})
+	session.MakeRequest(t, req, http.StatusForbidden)
+}
@@ -149,6 +149,83 @@ func TestPullSquash(t *testing.T) {
 	})
 }
 
+func TestPullMergeCommitAuthorStyle(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		baseRepo := db.AssertExistsAndLoadBean(t, &models.Repository{OwnerName: "user2", Name: "repo1"}).(*models.Repository)
+		setMergeCommitAuthorStyle := func(style models.MergeCommitAuthorStyle) {
+			unit, err := baseRepo.GetUnit(models.UnitTypePullRequests)
+			assert.NoError(t, err)
+			unit.PullRequestsConfig().MergeCommitAuthorStyle = style
+			assert.NoError(t, models.UpdateRepoUnit(unit))
+		}
+		headCommit := func() *git.Commit {
+			gitRepo, err := git.OpenRepository(baseRepo.RepoPath())
+			assert.NoError(t, err)
+			defer gitRepo.Close()
+			commit, err := gitRepo.GetBranchCommit("master")
+			assert.NoError(t, err)
+			return commit
+		}
+
+		session := loginUser(t, "user1")
+		mergerSession := loginUser(t, "user2")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+
+		// the pull request author authors the merge commit, the merger commits it
+		setMergeCommitAuthorStyle(models.MergeCommitAuthorPRAuthor)
+		testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+		resp := testPullCreate(t, session, "user1", "repo1", "master", "This is a pull title")
+		elem := strings.Split(test.RedirectURL(resp), "/")
+		testPullMerge(t, mergerSession, elem[1], elem[2], elem[4], models.MergeStyleMerge)
+		commit := headCommit()
+		assert.Equal(t, "user1@example.com", commit.Author.Email)
+		assert.Equal(t, "user2@example.com", commit.Committer.Email)
+
+		// the merger authors and commits the squashed commit
+		setMergeCommitAuthorStyle(models.MergeCommitAuthorMerger)
+		testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited again)\n")
+		resp = testPullCreate(t, session, "user1", "repo1", "master", "This is another pull title")
+		elem = strings.Split(test.RedirectURL(resp), "/")
+		testPullMerge(t, mergerSession, elem[1], elem[2], elem[4], models.MergeStyleSquash)
+		commit = headCommit()
+		assert.Equal(t, "user2@example.com", commit.Author.Email)
+		assert.Equal(t, "user2@example.com", commit.Committer.Email)
+	})
+}
+
+func TestPullMergeTrailers(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		baseRepo := db.AssertExistsAndLoadBean(t, &models.Repository{OwnerName: "user2", Name: "repo1"}).(*models.Repository)
+		unit, err := baseRepo.GetUnit(models.UnitTypePullRequests)
+		assert.NoError(t, err)
+		unit.PullRequestsConfig().AddReviewedByTrailers = true
+		unit.PullRequestsConfig().AddCoAuthorTrailers = true
+		unit.PullRequestsConfig().MergeCommitAuthorStyle = models.MergeCommitAuthorMerger
+		assert.NoError(t, models.UpdateRepoUnit(unit))
+
+		// the author of the squashed commits keeps its email private
+		author := db.AssertExistsAndLoadBean(t, &models.User{Name: "user1"}).(*models.User)
+		author.KeepEmailPrivate = true
+		assert.NoError(t, models.UpdateUserCols(author, "keep_email_private"))
+
+		session := loginUser(t, "user1")
+		mergerSession := loginUser(t, "user2")
+		testRepoFork(t, session, "user2", "repo1", "user1", "repo1")
+		testEditFile(t, session, "user1", "repo1", "master", "README.md", "Hello, World (Edited)\n")
+		resp := testPullCreate(t, session, "user1", "repo1", "master", "This is a pull title")
+		elem := strings.Split(test.RedirectURL(resp), "/")
+		testPullMerge(t, mergerSession, elem[1], elem[2], elem[4], models.MergeStyleSquash)
+
+		gitRepo, err := git.OpenRepository(baseRepo.RepoPath())
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		commit, err := gitRepo.GetBranchCommit("master")
+		assert.NoError(t, err)
+		assert.Contains(t, commit.CommitMessage, "\n\nCo-authored-by: "+author.NewGitSig().String()+"\n")
+		assert.NotContains(t, commit.CommitMessage, "user1@example.com")
+	})
+}
+
 func TestPullCleanUpAfterMerge(t *testing.T) {
 	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
 		session := loginUser(t, "user1")
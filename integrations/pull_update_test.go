@@ -5,6 +5,8 @@
 package integrations
 
 import (
+	"context"
+	"fmt"
 	"net/http"
 	"net/url"
 	"testing"
@@ -12,8 +14,11 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/queue"
 	"code.gitea.io/gitea/modules/repofiles"
 	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/services/forms"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 
@@ -76,6 +81,100 @@ func TestAPIPullUpdateByRebase(t *testing.T) {
 	})
 }
 
+func TestAPIPullMergeOutdated(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		org26 := db.AssertExistsAndLoadBean(t, &models.User{ID: 26}).(*models.User)
+		pr := createOutdatedPR(t, user, org26)
+		assert.NoError(t, pr.LoadBaseRepo())
+		assert.NoError(t, pr.LoadIssue())
+		queue.GetManager().FlushAll(context.Background(), 5*time.Second)
+
+		protectBranch := &models.ProtectedBranch{
+			RepoID:                pr.BaseRepoID,
+			BranchName:            "master",
+			BlockOnOutdatedBranch: true,
+		}
+		assert.NoError(t, models.UpdateProtectBranch(pr.BaseRepo, protectBranch, models.WhitelistOptions{}))
+
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		urlStr := fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge?token=%s", pr.BaseRepo.OwnerName, pr.BaseRepo.Name, pr.Issue.Index, token)
+		form := &forms.MergePullRequestForm{Do: string(models.MergeStyleMerge)}
+		session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, form), http.StatusMethodNotAllowed)
+
+		// the head branch is updated before merging
+		protectBranch.AutoUpdateOnMerge = true
+		assert.NoError(t, models.UpdateProtectBranch(pr.BaseRepo, protectBranch, models.WhitelistOptions{}))
+		session.MakeRequest(t, NewRequestWithJSON(t, "POST", urlStr, form), http.StatusOK)
+
+		pr = db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: pr.ID}).(*models.PullRequest)
+		assert.True(t, pr.HasMerged)
+	})
+}
+
+func TestPullSyncDismissOnlyOnContentChange(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+		org26 := db.AssertExistsAndLoadBean(t, &models.User{ID: 26}).(*models.User)
+		reviewer := db.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+		pr := createOutdatedPR(t, user, org26)
+		assert.NoError(t, pr.LoadBaseRepo())
+		assert.NoError(t, pr.LoadHeadRepo())
+		assert.NoError(t, pr.LoadIssue())
+		queue.GetManager().FlushAll(context.Background(), 5*time.Second)
+
+		protectBranch := &models.ProtectedBranch{
+			RepoID:                     pr.BaseRepoID,
+			BranchName:                 "master",
+			DismissStaleApprovals:      true,
+			DismissOnlyOnContentChange: true,
+		}
+		assert.NoError(t, models.UpdateProtectBranch(pr.BaseRepo, protectBranch, models.WhitelistOptions{}))
+
+		headGitRepo, err := git.OpenRepository(pr.HeadRepo.RepoPath())
+		assert.NoError(t, err)
+		defer headGitRepo.Close()
+		headCommitID, err := headGitRepo.GetBranchCommitID(pr.HeadBranch)
+		assert.NoError(t, err)
+		approval, err := models.CreateReview(models.CreateReviewOptions{
+			Type:     models.ReviewTypeApprove,
+			Issue:    pr.Issue,
+			Reviewer: reviewer,
+			Official: true,
+			CommitID: headCommitID,
+		})
+		assert.NoError(t, err)
+
+		// rebasing onto the base branch doesn't change the diff and keeps the approval
+		session := loginUser(t, "user2")
+		token := getTokenForLoggedInUser(t, session)
+		req := NewRequestf(t, "POST", "/api/v1/repos/%s/%s/pulls/%d/update?style=rebase&token="+token, pr.BaseRepo.OwnerName, pr.BaseRepo.Name, pr.Issue.Index)
+		session.MakeRequest(t, req, http.StatusOK)
+		queue.GetManager().FlushAll(context.Background(), 5*time.Second)
+
+		approval = db.AssertExistsAndLoadBean(t, &models.Review{ID: approval.ID}).(*models.Review)
+		assert.False(t, approval.Stale)
+		db.AssertNotExistsBean(t, &models.Review{IssueID: pr.IssueID, ReviewerID: reviewer.ID, Type: models.ReviewTypeRequest})
+
+		// changing the content dismisses the approval and asks the approver to review again
+		_, err = repofiles.CreateOrUpdateRepoFile(pr.HeadRepo, user, &repofiles.UpdateRepoFileOptions{
+			TreePath:  "File_C",
+			Message:   "Add File C",
+			Content:   "File C",
+			IsNewFile: true,
+			OldBranch: pr.HeadBranch,
+			NewBranch: pr.HeadBranch,
+		})
+		assert.NoError(t, err)
+		queue.GetManager().FlushAll(context.Background(), 5*time.Second)
+
+		approval = db.AssertExistsAndLoadBean(t, &models.Review{ID: approval.ID}).(*models.Review)
+		assert.True(t, approval.Stale)
+		db.AssertExistsAndLoadBean(t, &models.Review{IssueID: pr.IssueID, ReviewerID: reviewer.ID, Type: models.ReviewTypeRequest})
+	})
+}
+
 func createOutdatedPR(t *testing.T, actor, forkOrg *models.User) *models.PullRequest {
 	baseRepo, err := repo_service.CreateRepository(actor, actor, models.CreateRepoOptions{
 		Name:        "repo-pr-update",
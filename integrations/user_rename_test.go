@@ -0,0 +1,63 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package integrations
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func renameUser(t *testing.T, userID int64, newName string) {
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: userID}).(*models.User)
+	assert.NoError(t, models.ChangeUserName(user, newName))
+	user.Name = newName
+	user.LowerName = strings.ToLower(newName)
+	assert.NoError(t, models.UpdateUserCols(user, "name", "lower_name"))
+}
+
+func TestRenamedOwnerRedirect(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	renameUser(t, 2, "user2-renamed")
+
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1")
+	resp := MakeRequest(t, req, http.StatusMovedPermanently)
+	assert.Equal(t, "/api/v1/repos/user2-renamed/repo1", resp.Header().Get("Location"))
+
+	req = NewRequest(t, "GET", "/user2/repo1/issues?state=closed")
+	resp = MakeRequest(t, req, http.StatusMovedPermanently)
+	assert.Equal(t, "/user2-renamed/repo1/issues?state=closed", resp.Header().Get("Location"))
+
+	// git fetches with the old name are served
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-upload-pack")
+	MakeRequest(t, req, http.StatusOK)
+
+	defer func(serve bool) { setting.Repository.ServeRenamedOwnerGit = serve }(setting.Repository.ServeRenamedOwnerGit)
+	setting.Repository.ServeRenamedOwnerGit = false
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-upload-pack")
+	MakeRequest(t, req, http.StatusFound)
+}
+
+func TestCaseOnlyUserRename(t *testing.T) {
+	defer prepareTestEnv(t)()
+
+	renameUser(t, 2, "User2")
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.Equal(t, "User2", repo.OwnerName)
+
+	// the directory isn't moved and no redirect is needed
+	req := NewRequest(t, "GET", "/api/v1/repos/user2/repo1")
+	MakeRequest(t, req, http.StatusOK)
+	req = NewRequest(t, "GET", "/user2/repo1.git/info/refs?service=git-upload-pack")
+	MakeRequest(t, req, http.StatusOK)
+}
@@ -53,6 +53,8 @@ const (
 	ActionPublishRelease                                  // 24
 	ActionPullReviewDismissed                             // 25
 	ActionPullRequestReadyForReview                       // 26
+	ActionDetachFork                                      // 27
+	ActionAuthorCommitRepo                                // 28
 )
 
 // Action represents user operation type and other information to
@@ -293,6 +295,23 @@ func (a *Action) GetIssueContent() string {
 	return issue.Content
 }
 
+// NotifyAuthors adds the actions to the feeds of their acting users only, like the actions crediting
+// the authors of commits pushed by someone else, which the watchers know from the action of the pusher.
+func NotifyAuthors(actions ...*Action) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	for _, act := range actions {
+		act.UserID = act.ActUserID
+		if _, err := sess.InsertOne(act); err != nil {
+			return fmt.Errorf("insert new action: %v", err)
+		}
+	}
+	return sess.Commit()
+}
+
 // GetFeedsOptions options for retrieving feeds
 type GetFeedsOptions struct {
 	RequestedUser   *User  // the user we want activity for
@@ -7,12 +7,17 @@ package models
 
 import (
 	"fmt"
+	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // NoticeType describes the notice type
@@ -23,14 +28,40 @@ const (
 	NoticeRepository NoticeType = iota + 1
 	// NoticeTask type
 	NoticeTask
+	// NoticeSecurity type
+	NoticeSecurity
 )
 
+var noticeTypeNames = map[NoticeType]string{
+	NoticeRepository: "repository",
+	NoticeTask:       "task",
+	NoticeSecurity:   "security",
+}
+
+// String returns the name of the notice type
+func (t NoticeType) String() string {
+	return noticeTypeNames[t]
+}
+
+// NoticeTypeFromString returns the notice type of a name, or 0 if the name is unknown
+func NoticeTypeFromString(name string) NoticeType {
+	for t, n := range noticeTypeNames {
+		if n == name {
+			return t
+		}
+	}
+	return 0
+}
+
 // Notice represents a system notice for admin.
+// Identical notices created within the deduplication window are counted in one notice.
 type Notice struct {
-	ID          int64 `xorm:"pk autoincr"`
-	Type        NoticeType
-	Description string             `xorm:"TEXT"`
-	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	ID           int64 `xorm:"pk autoincr"`
+	Type         NoticeType
+	Description  string             `xorm:"TEXT"`
+	Count        int64              `xorm:"NOT NULL DEFAULT 1"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"INDEX created"`
+	LastSeenUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
 }
 
 func init() {
@@ -51,9 +82,28 @@ func createNotice(e db.Engine, tp NoticeType, desc string, args ...interface{})
 	if len(args) > 0 {
 		desc = fmt.Sprintf(desc, args...)
 	}
+	now := timeutil.TimeStampNow()
+
+	if window := setting.Admin.NoticeDeduplicationWindow; window > 0 {
+		existing := new(Notice)
+		has, err := e.Where("type = ? AND last_seen_unix >= ?", tp, now.AddDuration(-window)).
+			And(builder.Eq{"description": desc}).
+			Desc("last_seen_unix").
+			Get(existing)
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = e.Exec("UPDATE `notice` SET `count` = `count` + 1, last_seen_unix = ? WHERE id = ?", now, existing.ID)
+			return err
+		}
+	}
+
 	n := &Notice{
-		Type:        tp,
-		Description: desc,
+		Type:         tp,
+		Description:  desc,
+		Count:        1,
+		LastSeenUnix: now,
 	}
 	_, err := e.Insert(n)
 	return err
@@ -107,7 +157,7 @@ func Notices(page, pageSize int) ([]*Notice, error) {
 	notices := make([]*Notice, 0, pageSize)
 	return notices, db.GetEngine(db.DefaultContext).
 		Limit(pageSize, (page-1)*pageSize).
-		Desc("created_unix").
+		Desc("last_seen_unix", "id").
 		Find(&notices)
 }
 
@@ -132,6 +182,64 @@ func DeleteNotices(start, end int64) error {
 	return err
 }
 
+// FindNoticesOptions represents the options to find and delete notices
+type FindNoticesOptions struct {
+	db.ListOptions
+	IDs     []int64
+	Type    NoticeType
+	Keyword string
+	// notices last seen since or before a time
+	SinceUnix  timeutil.TimeStamp
+	BeforeUnix timeutil.TimeStamp
+}
+
+func (opts *FindNoticesOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.IDs) > 0 {
+		cond = cond.And(builder.In("id", opts.IDs))
+	}
+	if opts.Type > 0 {
+		cond = cond.And(builder.Eq{"type": opts.Type})
+	}
+	if opts.Keyword != "" {
+		cond = cond.And(builder.Like{"UPPER(description)", strings.ToUpper(opts.Keyword)})
+	}
+	if opts.SinceUnix > 0 {
+		cond = cond.And(builder.Gte{"last_seen_unix": opts.SinceUnix})
+	}
+	if opts.BeforeUnix > 0 {
+		cond = cond.And(builder.Lt{"last_seen_unix": opts.BeforeUnix})
+	}
+	return cond
+}
+
+// FindNotices returns the notices matching the options, the most recently seen first, and their total count
+func FindNotices(opts *FindNoticesOptions) ([]*Notice, int64, error) {
+	sess := db.GetEngine(db.DefaultContext).Where(opts.toConds())
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+	notices := make([]*Notice, 0, opts.PageSize)
+	count, err := sess.Desc("last_seen_unix", "id").FindAndCount(&notices)
+	return notices, count, err
+}
+
+// DeleteNoticesByOptions deletes the notices matching the options and returns their number.
+// Options without any condition match all notices.
+func DeleteNoticesByOptions(opts *FindNoticesOptions) (int64, error) {
+	return db.GetEngine(db.DefaultContext).Where(opts.toConds()).Delete(new(Notice))
+}
+
+// DeleteOldNotices deletes the notices which have not been seen for longer than olderThan
+func DeleteOldNotices(olderThan time.Duration) error {
+	if olderThan <= 0 {
+		return nil
+	}
+
+	_, err := DeleteNoticesByOptions(&FindNoticesOptions{BeforeUnix: timeutil.TimeStampNow().AddDuration(-olderThan)})
+	return err
+}
+
 // DeleteNoticesByIDs deletes notices by given IDs.
 func DeleteNoticesByIDs(ids []int64) error {
 	if len(ids) == 0 {
@@ -6,8 +6,12 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +47,35 @@ func TestCreateRepositoryNotice(t *testing.T) {
 	db.AssertExistsAndLoadBean(t, noticeBean)
 }
 
+func TestCreateNotice_Deduplication(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	defer func(window time.Duration) {
+		setting.Admin.NoticeDeduplicationWindow = window
+	}(setting.Admin.NoticeDeduplicationWindow)
+	setting.Admin.NoticeDeduplicationWindow = time.Hour
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, CreateNotice(NoticeTask, "task %s failed", "foo"))
+	}
+	notice := db.AssertExistsAndLoadBean(t, &Notice{Type: NoticeTask, Description: "task foo failed"}).(*Notice)
+	assert.EqualValues(t, 3, notice.Count)
+	assert.NotZero(t, notice.LastSeenUnix)
+
+	// notices of another type or outside of the window are not counted
+	assert.NoError(t, CreateNotice(NoticeRepository, "task foo failed"))
+	assert.EqualValues(t, 2, db.GetCount(t, &Notice{Description: "task foo failed"}))
+
+	notice.LastSeenUnix = timeutil.TimeStampNow().AddDuration(-2 * time.Hour)
+	_, err := db.GetEngine(db.DefaultContext).ID(notice.ID).Cols("last_seen_unix").Update(notice)
+	assert.NoError(t, err)
+	assert.NoError(t, CreateNotice(NoticeTask, "task foo failed"))
+	assert.EqualValues(t, 3, db.GetCount(t, &Notice{Description: "task foo failed"}))
+
+	setting.Admin.NoticeDeduplicationWindow = 0
+	assert.NoError(t, CreateNotice(NoticeTask, "task foo failed"))
+	assert.EqualValues(t, 4, db.GetCount(t, &Notice{Description: "task foo failed"}))
+}
+
 // TODO TestRemoveAllWithNotice
 
 func TestCountNotices(t *testing.T) {
@@ -112,3 +145,46 @@ func TestDeleteNoticesByIDs(t *testing.T) {
 	db.AssertExistsAndLoadBean(t, &Notice{ID: 2})
 	db.AssertNotExistsBean(t, &Notice{ID: 3})
 }
+
+func TestFindNotices(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.NoError(t, CreateNotice(NoticeTask, "Task failed: Description2"))
+	notices, count, err := FindNotices(&FindNoticesOptions{Keyword: "description2"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, notices, 2) {
+		assert.Equal(t, NoticeTask, notices[0].Type)
+		assert.EqualValues(t, 2, notices[1].ID)
+	}
+
+	_, count, err = FindNotices(&FindNoticesOptions{Type: NoticeRepository})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+
+	notices, count, err = FindNotices(&FindNoticesOptions{SinceUnix: timeutil.TimeStampNow().AddDuration(-time.Minute)})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, notices, 1)
+
+	notices, count, err = FindNotices(&FindNoticesOptions{ListOptions: db.ListOptions{Page: 2, PageSize: 3}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+	assert.Len(t, notices, 1)
+}
+
+func TestDeleteNoticesByOptions(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	count, err := DeleteNoticesByOptions(&FindNoticesOptions{IDs: []int64{1, 2}, Keyword: "description2"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	db.AssertExistsAndLoadBean(t, &Notice{ID: 1})
+	db.AssertNotExistsBean(t, &Notice{ID: 2})
+
+	assert.NoError(t, CreateNotice(NoticeTask, "recent"))
+	assert.NoError(t, DeleteOldNotices(time.Hour))
+	db.AssertNotExistsBean(t, &Notice{ID: 1})
+	db.AssertNotExistsBean(t, &Notice{ID: 3})
+	db.AssertExistsAndLoadBean(t, &Notice{Description: "recent"})
+}
@@ -41,7 +41,10 @@ type ProtectedBranch struct {
 	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	AutoUpdateOnMerge             bool     `xorm:"NOT NULL DEFAULT false"`
 	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
+	DismissOnlyOnContentChange    bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireCodeOwnerApproval      bool     `xorm:"NOT NULL DEFAULT false"`
 	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
 	ProtectedFilePatterns         string   `xorm:"TEXT"`
 	UnprotectedFilePatterns       string   `xorm:"TEXT"`
@@ -215,9 +218,10 @@ func (protectBranch *ProtectedBranch) MergeBlockedByOfficialReviewRequests(pr *P
 	return has
 }
 
-// MergeBlockedByOutdatedBranch returns true if merge is blocked by an outdated head branch
+// MergeBlockedByOutdatedBranch returns true if merge is blocked by an outdated head branch,
+// which isn't updated on merge
 func (protectBranch *ProtectedBranch) MergeBlockedByOutdatedBranch(pr *PullRequest) bool {
-	return protectBranch.BlockOnOutdatedBranch && pr.CommitsBehind > 0
+	return protectBranch.BlockOnOutdatedBranch && !protectBranch.AutoUpdateOnMerge && pr.CommitsBehind > 0
 }
 
 // GetProtectedFilePatterns parses a semicolon separated list of protected file patterns and returns a glob.Glob slice
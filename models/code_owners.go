@@ -0,0 +1,291 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// CodeOwnersErrorKind is the kind of an invalid entry of a CODEOWNERS file
+type CodeOwnersErrorKind string
+
+// The kinds of invalid entries of a CODEOWNERS file
+const (
+	CodeOwnersErrorInvalidPattern    CodeOwnersErrorKind = "Invalid pattern"
+	CodeOwnersErrorInvalidOwner      CodeOwnersErrorKind = "Invalid owner"
+	CodeOwnersErrorUnknownOwner      CodeOwnersErrorKind = "Unknown owner"
+	CodeOwnersErrorInaccessibleOwner CodeOwnersErrorKind = "Inaccessible owner"
+)
+
+// CodeOwnersError is an invalid entry of a CODEOWNERS file, Line and Column start at 1
+type CodeOwnersError struct {
+	Line    int
+	Column  int
+	Kind    CodeOwnersErrorKind
+	Source  string
+	Message string
+}
+
+// CodeOwnerRule is a rule of a CODEOWNERS file, the files matching its pattern are owned by its users and teams
+type CodeOwnerRule struct {
+	Line    int
+	Pattern string
+	Users   []*User
+	Teams   []*Team
+
+	matcher gitignore.Pattern
+}
+
+// Match returns whether the rule matches the file
+func (rule *CodeOwnerRule) Match(file string) bool {
+	return rule.matcher.Match(strings.Split(strings.Trim(file, "/"), "/"), false) == gitignore.Exclude
+}
+
+// CodeOwners are the rules of the CODEOWNERS file of a repository
+type CodeOwners struct {
+	Path   string
+	Rules  []*CodeOwnerRule
+	Errors []*CodeOwnersError
+}
+
+// Match returns the rule which decides the owners of the file, later rules take precedence over earlier ones.
+// It returns nil if no rule matches the file.
+func (codeOwners *CodeOwners) Match(file string) *CodeOwnerRule {
+	for i := len(codeOwners.Rules) - 1; i >= 0; i-- {
+		if codeOwners.Rules[i].Match(file) {
+			return codeOwners.Rules[i]
+		}
+	}
+	return nil
+}
+
+// OwnersOf returns the users and teams owning at least one of the files
+func (codeOwners *CodeOwners) OwnersOf(files []string) ([]*User, []*Team) {
+	var users []*User
+	var teams []*Team
+	userIDs := make(map[int64]bool)
+	teamIDs := make(map[int64]bool)
+	for _, file := range files {
+		rule := codeOwners.Match(file)
+		if rule == nil {
+			continue
+		}
+		for _, user := range rule.Users {
+			if !userIDs[user.ID] {
+				userIDs[user.ID] = true
+				users = append(users, user)
+			}
+		}
+		for _, team := range rule.Teams {
+			if !teamIDs[team.ID] {
+				teamIDs[team.ID] = true
+				teams = append(teams, team)
+			}
+		}
+	}
+	return users, teams
+}
+
+// codeOwnersField is a whitespace separated field of a line of a CODEOWNERS file
+type codeOwnersField struct {
+	value  string
+	column int
+}
+
+// splitCodeOwnersLine splits a line into its fields, dropping a trailing comment
+func splitCodeOwnersLine(line string) []codeOwnersField {
+	var fields []codeOwnersField
+	start := -1
+	for i := 0; i <= len(line); i++ {
+		if i < len(line) && line[i] == '#' && start < 0 {
+			break
+		}
+		if i == len(line) || line[i] == ' ' || line[i] == '\t' {
+			if start >= 0 {
+				fields = append(fields, codeOwnersField{value: line[start:i], column: start + 1})
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	return fields
+}
+
+// validateCodeOwnersPattern returns why the pattern is invalid, or "" if it is valid
+func validateCodeOwnersPattern(pattern string) string {
+	if strings.HasPrefix(pattern, "!") {
+		return "negated patterns are not supported"
+	}
+	for _, segment := range strings.Split(strings.Trim(pattern, "/"), "/") {
+		if segment == "**" {
+			continue
+		}
+		if strings.Contains(segment, "**") {
+			return "\"**\" has to be a complete path segment"
+		}
+		if _, err := filepath.Match(segment, ""); err != nil {
+			return err.Error()
+		}
+	}
+	return ""
+}
+
+// codeOwnersResolver resolves the owners of a CODEOWNERS file and checks they can review pull requests of the repository
+type codeOwnersResolver struct {
+	repo      *Repository
+	reviewers map[int64]bool
+	teams     map[int64]bool
+}
+
+func newCodeOwnersResolver(repo *Repository) (*codeOwnersResolver, error) {
+	if err := repo.GetOwner(); err != nil {
+		return nil, err
+	}
+	resolver := &codeOwnersResolver{
+		repo:      repo,
+		reviewers: map[int64]bool{repo.OwnerID: !repo.Owner.IsOrganization()},
+		teams:     make(map[int64]bool),
+	}
+
+	reviewers, err := repo.GetReviewers(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("GetReviewers: %v", err)
+	}
+	for _, reviewer := range reviewers {
+		resolver.reviewers[reviewer.ID] = true
+	}
+
+	teams, err := repo.GetReviewerTeams()
+	if err != nil {
+		return nil, fmt.Errorf("GetReviewerTeams: %v", err)
+	}
+	for _, team := range teams {
+		resolver.teams[team.ID] = true
+	}
+	return resolver, nil
+}
+
+func invalidCodeOwner(kind CodeOwnersErrorKind, format string, args ...interface{}) *CodeOwnersError {
+	return &CodeOwnersError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// resolve returns the user or the team of an owner, or the kind and the message of the error if the owner is invalid
+func (resolver *codeOwnersResolver) resolve(owner string) (*User, *Team, *CodeOwnersError, error) {
+	if !strings.HasPrefix(owner, "@") {
+		if !strings.Contains(owner, "@") {
+			return nil, nil, invalidCodeOwner(CodeOwnersErrorInvalidOwner, "%s is neither a @user, a @org/team nor an email address", owner), nil
+		}
+		user, err := GetUserByEmail(owner)
+		if err != nil {
+			if IsErrUserNotExist(err) {
+				return nil, nil, invalidCodeOwner(CodeOwnersErrorUnknownOwner, "no user has the email address %s", owner), nil
+			}
+			return nil, nil, nil, err
+		}
+		return resolver.checkUser(owner, user)
+	}
+
+	name := owner[1:]
+	if idx := strings.IndexByte(name, '/'); idx >= 0 {
+		orgName, teamName := name[:idx], name[idx+1:]
+		if !resolver.repo.Owner.IsOrganization() || !strings.EqualFold(orgName, resolver.repo.Owner.Name) {
+			return nil, nil, invalidCodeOwner(CodeOwnersErrorInvalidOwner, "%s is not a team of the organization owning the repository", owner), nil
+		}
+		team, err := GetTeam(resolver.repo.OwnerID, teamName)
+		if err != nil {
+			if IsErrTeamNotExist(err) {
+				return nil, nil, invalidCodeOwner(CodeOwnersErrorUnknownOwner, "team %s does not exist", owner), nil
+			}
+			return nil, nil, nil, err
+		}
+		if !resolver.teams[team.ID] {
+			return nil, nil, invalidCodeOwner(CodeOwnersErrorInaccessibleOwner, "team %s can't read the repository", owner), nil
+		}
+		return nil, team, nil, nil
+	}
+
+	user, err := GetUserByName(name)
+	if err != nil {
+		if IsErrUserNotExist(err) {
+			return nil, nil, invalidCodeOwner(CodeOwnersErrorUnknownOwner, "user %s does not exist", owner), nil
+		}
+		return nil, nil, nil, err
+	}
+	return resolver.checkUser(owner, user)
+}
+
+func (resolver *codeOwnersResolver) checkUser(owner string, user *User) (*User, *Team, *CodeOwnersError, error) {
+	if user.IsOrganization() {
+		return nil, nil, invalidCodeOwner(CodeOwnersErrorInvalidOwner, "%s is an organization, only users and teams can own files", owner), nil
+	}
+	if !resolver.reviewers[user.ID] {
+		return nil, nil, invalidCodeOwner(CodeOwnersErrorInaccessibleOwner, "user %s can't read the repository", owner), nil
+	}
+	return user, nil, nil, nil
+}
+
+// ParseCodeOwners parses the content of the CODEOWNERS file of a repository.
+// Each line consists of a gitignore style pattern followed by its owners: @user, @org/team or an email address.
+// Invalid patterns and owners are skipped and returned as errors of the CodeOwners.
+func ParseCodeOwners(repo *Repository, path, content string) (*CodeOwners, error) {
+	resolver, err := newCodeOwnersResolver(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	codeOwners := &CodeOwners{Path: path}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		fields := splitCodeOwnersLine(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern := fields[0]
+		if reason := validateCodeOwnersPattern(pattern.value); reason != "" {
+			codeOwners.Errors = append(codeOwners.Errors, &CodeOwnersError{
+				Line:    i + 1,
+				Column:  pattern.column,
+				Kind:    CodeOwnersErrorInvalidPattern,
+				Source:  line,
+				Message: fmt.Sprintf("%s: %s", pattern.value, reason),
+			})
+			continue
+		}
+
+		rule := &CodeOwnerRule{
+			Line:    i + 1,
+			Pattern: pattern.value,
+			matcher: gitignore.ParsePattern(pattern.value, nil),
+		}
+		for _, field := range fields[1:] {
+			user, team, ownerErr, err := resolver.resolve(field.value)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case ownerErr != nil:
+				ownerErr.Line = i + 1
+				ownerErr.Column = field.column
+				ownerErr.Source = line
+				codeOwners.Errors = append(codeOwners.Errors, ownerErr)
+			case user != nil:
+				rule.Users = append(rule.Users, user)
+			case team != nil:
+				rule.Teams = append(rule.Teams, team)
+			}
+		}
+		// A rule without owners is kept, it unsets the owners of the files matched by earlier rules
+		codeOwners.Rules = append(codeOwners.Rules, rule)
+	}
+	return codeOwners, nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCodeOwners(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+
+	content := `# comment
+*          @user3/owners  # the owners own everything
+/docs/     @user3/team1 user4@example.com
+*.go       @user5 @user2 @nonexistent @user3/nonexistent @user2/team nobody
+docs/[bad  @user2
+!*.md      @user2
+*.txt
+`
+	codeOwners, err := ParseCodeOwners(repo, "CODEOWNERS", content)
+	assert.NoError(t, err)
+	assert.Equal(t, "CODEOWNERS", codeOwners.Path)
+	if assert.Len(t, codeOwners.Rules, 4) {
+		assert.Equal(t, "*", codeOwners.Rules[0].Pattern)
+		assert.Equal(t, 2, codeOwners.Rules[0].Line)
+		if assert.Len(t, codeOwners.Rules[0].Teams, 1) {
+			assert.EqualValues(t, 1, codeOwners.Rules[0].Teams[0].ID)
+		}
+		if assert.Len(t, codeOwners.Rules[1].Users, 1) {
+			assert.EqualValues(t, 4, codeOwners.Rules[1].Users[0].ID)
+		}
+		if assert.Len(t, codeOwners.Rules[2].Users, 1) {
+			assert.EqualValues(t, 2, codeOwners.Rules[2].Users[0].ID)
+		}
+		assert.Empty(t, codeOwners.Rules[3].Users)
+		assert.Empty(t, codeOwners.Rules[3].Teams)
+	}
+
+	type expectedError struct {
+		line, column int
+		kind         CodeOwnersErrorKind
+	}
+	expected := []expectedError{
+		{4, 12, CodeOwnersErrorInaccessibleOwner},
+		{4, 26, CodeOwnersErrorUnknownOwner},
+		{4, 39, CodeOwnersErrorUnknownOwner},
+		{4, 58, CodeOwnersErrorInvalidOwner},
+		{4, 70, CodeOwnersErrorInvalidOwner},
+		{5, 1, CodeOwnersErrorInvalidPattern},
+		{6, 1, CodeOwnersErrorInvalidPattern},
+	}
+	if assert.Len(t, codeOwners.Errors, len(expected)) {
+		for i, e := range expected {
+			assert.Equal(t, e.line, codeOwners.Errors[i].Line, "error %d", i)
+			assert.Equal(t, e.column, codeOwners.Errors[i].Column, "error %d", i)
+			assert.Equal(t, e.kind, codeOwners.Errors[i].Kind, "error %d", i)
+		}
+	}
+
+	// the last matching rule wins
+	assert.Equal(t, 2, codeOwners.Match("README.md").Line)
+	assert.Equal(t, 3, codeOwners.Match("docs/README.md").Line)
+	assert.Equal(t, 4, codeOwners.Match("docs/main.go").Line)
+	assert.Equal(t, 7, codeOwners.Match("docs/notes.txt").Line)
+
+	users, teams := codeOwners.OwnersOf([]string{"README.md", "docs/install.md", "cmd/main.go", "notes.txt"})
+	if assert.Len(t, users, 2) {
+		assert.EqualValues(t, 4, users[0].ID)
+		assert.EqualValues(t, 2, users[1].ID)
+	}
+	if assert.Len(t, teams, 2) {
+		assert.EqualValues(t, 1, teams[0].ID)
+		assert.EqualValues(t, 2, teams[1].ID)
+	}
+}
@@ -33,6 +33,8 @@ type CommitStatus struct {
 	Context     string                `xorm:"TEXT"`
 	Creator     *User                 `xorm:"-"`
 	CreatorID   int64
+	// CreatorName is the name of the deploy key which created the status, if any
+	CreatorName string
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -125,7 +127,9 @@ func (status *CommitStatus) loadAttributes(e db.Engine) (err error) {
 			return fmt.Errorf("getRepositoryByID [%d]: %v", status.RepoID, err)
 		}
 	}
-	if status.Creator == nil && status.CreatorID > 0 {
+	if status.Creator == nil && status.CreatorID == DeployKeyUserID {
+		status.Creator = NewDeployKeyUser(status.CreatorName)
+	} else if status.Creator == nil && status.CreatorID > 0 {
 		status.Creator, err = getUserByID(e, status.CreatorID)
 		if err != nil {
 			return fmt.Errorf("getUserByID [%d]: %v", status.CreatorID, err)
@@ -307,6 +311,9 @@ func NewCommitStatus(opts NewCommitStatusOptions) error {
 	opts.CommitStatus.TargetURL = strings.TrimSpace(opts.CommitStatus.TargetURL)
 	opts.CommitStatus.SHA = opts.SHA
 	opts.CommitStatus.CreatorID = opts.Creator.ID
+	if opts.Creator.IsDeployKey() {
+		opts.CommitStatus.CreatorName = opts.Creator.Name
+	}
 	opts.CommitStatus.RepoID = opts.Repo.ID
 	opts.CommitStatus.Index = idx
 	log.Debug("NewCommitStatus[%s, %s]: %d", repoPath, opts.SHA, opts.CommitStatus.Index)
@@ -93,6 +93,10 @@ func MainTest(m *testing.M, pathToGiteaRoot string, fixtureFiles ...string) {
 
 	setting.RepoArchive.Storage.Path = filepath.Join(setting.AppDataPath, "repo-archive")
 
+	setting.RepoBackup.Storage.Path = filepath.Join(setting.AppDataPath, "repo-backup")
+
+	setting.UserExport.Storage.Path = filepath.Join(setting.AppDataPath, "user-export")
+
 	if err = storage.Init(); err != nil {
 		fatalTestError("storage.Init: %v\n", err)
 	}
@@ -7,6 +7,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"code.gitea.io/gitea/modules/git"
 )
@@ -71,6 +72,23 @@ func (err ErrNameCharsNotAllowed) Error() string {
 	return fmt.Sprintf("User name is invalid [%s]: must be valid alpha or numeric or dash(-_) or dot characters", err.Name)
 }
 
+// ErrNameTooLong represents a "name exceeds the maximum length" error.
+type ErrNameTooLong struct {
+	Kind      string
+	Name      string
+	MaxLength int
+}
+
+// IsErrNameTooLong checks if an error is an ErrNameTooLong.
+func IsErrNameTooLong(err error) bool {
+	_, ok := err.(ErrNameTooLong)
+	return ok
+}
+
+func (err ErrNameTooLong) Error() string {
+	return fmt.Sprintf("%s is too long [name: %s]: must not be longer than %d characters", err.Kind, err.Name, err.MaxLength)
+}
+
 // ErrSSHDisabled represents an "SSH disabled" error.
 type ErrSSHDisabled struct{}
 
@@ -399,6 +417,28 @@ func (err ErrKeyUnableVerify) Error() string {
 	return fmt.Sprintf("Unable to verify key content [result: %s]", err.Result)
 }
 
+// ErrKeyPolicyViolation represents a "KeyPolicyViolation" kind of error:
+// the key type is not allowed, or the key is shorter than the minimum size of its type.
+type ErrKeyPolicyViolation struct {
+	KeyType      string
+	Length       int
+	MinLength    int
+	AllowedTypes []string
+}
+
+// IsErrKeyPolicyViolation checks if an error is a ErrKeyPolicyViolation.
+func IsErrKeyPolicyViolation(err error) bool {
+	_, ok := err.(ErrKeyPolicyViolation)
+	return ok
+}
+
+func (err ErrKeyPolicyViolation) Error() string {
+	if err.MinLength == 0 {
+		return fmt.Sprintf("%s keys are not allowed, allowed key types are: %s", err.KeyType, strings.Join(err.AllowedTypes, ", "))
+	}
+	return fmt.Sprintf("%s key has %d bits, but %s keys must have at least %d bits", err.KeyType, err.Length, err.KeyType, err.MinLength)
+}
+
 // ErrKeyNotExist represents a "KeyNotExist" kind of error.
 type ErrKeyNotExist struct {
 	ID int64
@@ -698,6 +738,21 @@ func (err ErrLastOrgOwner) Error() string {
 	return fmt.Sprintf("user is the last member of owner team [uid: %d]", err.UID)
 }
 
+// ErrPublicRepoNotAllowed represents a "PublicRepoNotAllowed" kind of error.
+type ErrPublicRepoNotAllowed struct {
+	OrgName string
+}
+
+// IsErrPublicRepoNotAllowed checks if an error is a ErrPublicRepoNotAllowed.
+func IsErrPublicRepoNotAllowed(err error) bool {
+	_, ok := err.(ErrPublicRepoNotAllowed)
+	return ok
+}
+
+func (err ErrPublicRepoNotAllowed) Error() string {
+	return fmt.Sprintf("organization requires its repositories to be private [org: %s]", err.OrgName)
+}
+
 //.____   ____________________
 //|    |  \_   _____/   _____/
 //|    |   |    __) \_____  \
@@ -801,6 +856,38 @@ func (err ErrRepoNotExist) Error() string {
 		err.ID, err.UID, err.OwnerName, err.Name)
 }
 
+// ErrRepoIsArchived represents a "RepoIsArchived" kind of error.
+type ErrRepoIsArchived struct {
+	Repo *Repository
+}
+
+// IsErrRepoIsArchived checks if an error is a ErrRepoIsArchived.
+func IsErrRepoIsArchived(err error) bool {
+	_, ok := err.(ErrRepoIsArchived)
+	return ok
+}
+
+func (err ErrRepoIsArchived) Error() string {
+	return fmt.Sprintf("repository is archived [id: %d, name: %s]", err.Repo.ID, err.Repo.FullName())
+}
+
+// ErrForkHasDiverged represents a "ForkHasDiverged" kind of error.
+type ErrForkHasDiverged struct {
+	Branch string
+	Ahead  int
+	Behind int
+}
+
+// IsErrForkHasDiverged checks if an error is a ErrForkHasDiverged.
+func IsErrForkHasDiverged(err error) bool {
+	_, ok := err.(ErrForkHasDiverged)
+	return ok
+}
+
+func (err ErrForkHasDiverged) Error() string {
+	return fmt.Sprintf("fork has diverged from its base repository [branch: %s, ahead: %d, behind: %d]", err.Branch, err.Ahead, err.Behind)
+}
+
 // ErrNoPendingRepoTransfer is an error type for repositories without a pending
 // transfer request
 type ErrNoPendingRepoTransfer struct {
@@ -867,6 +954,76 @@ func (err ErrRepoFilesAlreadyExist) Error() string {
 	return fmt.Sprintf("repository files already exist [uname: %s, name: %s]", err.Uname, err.Name)
 }
 
+// ErrObjectFormatNotAllowed represents a "ObjectFormatNotAllowed" kind of error.
+type ErrObjectFormatNotAllowed struct {
+	ObjectFormat string
+}
+
+// IsErrObjectFormatNotAllowed checks if an error is an ErrObjectFormatNotAllowed.
+func IsErrObjectFormatNotAllowed(err error) bool {
+	_, ok := err.(ErrObjectFormatNotAllowed)
+	return ok
+}
+
+func (err ErrObjectFormatNotAllowed) Error() string {
+	return fmt.Sprintf("repositories can't be created with the object format [format: %s]", err.ObjectFormat)
+}
+
+// ErrInitFileNotExist represents a "InitFileNotExist" kind of error.
+type ErrInitFileNotExist struct {
+	Type        string
+	Name        string
+	Suggestions []string
+}
+
+// IsErrInitFileNotExist checks if an error is an ErrInitFileNotExist.
+func IsErrInitFileNotExist(err error) bool {
+	_, ok := err.(ErrInitFileNotExist)
+	return ok
+}
+
+func (err ErrInitFileNotExist) Error() string {
+	if len(err.Suggestions) == 0 {
+		return fmt.Sprintf("%s does not exist [name: %s]", err.Type, err.Name)
+	}
+	return fmt.Sprintf("%s does not exist [name: %s], did you mean: %s", err.Type, err.Name, strings.Join(err.Suggestions, ", "))
+}
+
+// ErrInvalidExternalTracker represents a "InvalidExternalTracker" kind of error.
+type ErrInvalidExternalTracker struct {
+	// Field is the invalid setting: url, format, style or regexp_pattern
+	Field  string
+	Reason string
+}
+
+// IsErrInvalidExternalTracker checks if an error is an ErrInvalidExternalTracker.
+func IsErrInvalidExternalTracker(err error) bool {
+	_, ok := err.(ErrInvalidExternalTracker)
+	return ok
+}
+
+func (err ErrInvalidExternalTracker) Error() string {
+	return fmt.Sprintf("invalid external tracker %s: %s", err.Field, err.Reason)
+}
+
+// ErrObjectFormatMismatch represents a "ObjectFormatMismatch" kind of error.
+type ErrObjectFormatMismatch struct {
+	BaseRepoName string
+	BaseFormat   string
+	HeadRepoName string
+	HeadFormat   string
+}
+
+// IsErrObjectFormatMismatch checks if an error is an ErrObjectFormatMismatch.
+func IsErrObjectFormatMismatch(err error) bool {
+	_, ok := err.(ErrObjectFormatMismatch)
+	return ok
+}
+
+func (err ErrObjectFormatMismatch) Error() string {
+	return fmt.Sprintf("repositories use different object formats [base: %s (%s), head: %s (%s)]", err.BaseRepoName, err.BaseFormat, err.HeadRepoName, err.HeadFormat)
+}
+
 // ErrForkAlreadyExist represents a "ForkAlreadyExist" kind of error.
 type ErrForkAlreadyExist struct {
 	Uname    string
@@ -900,6 +1057,23 @@ func (err ErrRepoRedirectNotExist) Error() string {
 	return fmt.Sprintf("repository redirect does not exist [uid: %d, name: %s]", err.OwnerID, err.RepoName)
 }
 
+// ErrPushCreateRepoNotAllowed represents a "PushCreateRepoNotAllowed" kind of error.
+type ErrPushCreateRepoNotAllowed struct {
+	OwnerName string
+	RepoName  string
+	Reason    string
+}
+
+// IsErrPushCreateRepoNotAllowed checks if an error is an ErrPushCreateRepoNotAllowed.
+func IsErrPushCreateRepoNotAllowed(err error) bool {
+	_, ok := err.(ErrPushCreateRepoNotAllowed)
+	return ok
+}
+
+func (err ErrPushCreateRepoNotAllowed) Error() string {
+	return fmt.Sprintf("cannot push-create repository %s/%s: %s", err.OwnerName, err.RepoName, err.Reason)
+}
+
 // ErrInvalidCloneAddr represents a "InvalidCloneAddr" kind of error.
 type ErrInvalidCloneAddr struct {
 	Host               string
@@ -1233,6 +1407,192 @@ func (err ErrNotAllowedToMerge) Error() string {
 	return fmt.Sprintf("not allowed to merge [reason: %s]", err.Reason)
 }
 
+// ErrPullRequestOutdated represents an error that a pull request can't be merged because its head branch
+// is behind its base branch, which requires pull requests to be up to date.
+type ErrPullRequestOutdated struct {
+	ID     int64
+	Behind int
+}
+
+// IsErrPullRequestOutdated checks if an error is an ErrPullRequestOutdated.
+func IsErrPullRequestOutdated(err error) bool {
+	_, ok := err.(ErrPullRequestOutdated)
+	return ok
+}
+
+func (err ErrPullRequestOutdated) Error() string {
+	return fmt.Sprintf("not allowed to merge, the head branch is %d commits behind the base branch [id: %d]", err.Behind, err.ID)
+}
+
+// ErrPullRequestIsDraft represents an error that a pull request can't be merged because it is a draft.
+type ErrPullRequestIsDraft struct {
+	ID    int64
+	Index int64
+}
+
+// IsErrPullRequestIsDraft checks if an error is an ErrPullRequestIsDraft.
+func IsErrPullRequestIsDraft(err error) bool {
+	_, ok := err.(ErrPullRequestIsDraft)
+	return ok
+}
+
+func (err ErrPullRequestIsDraft) Error() string {
+	return fmt.Sprintf("not allowed to merge, the pull request is a draft [id: %d, index: %d]", err.ID, err.Index)
+}
+
+// ErrMergeQueueDisabled represents an error that the merge queue of a repository is not enabled.
+type ErrMergeQueueDisabled struct {
+	RepoID int64
+}
+
+// IsErrMergeQueueDisabled checks if an error is an ErrMergeQueueDisabled.
+func IsErrMergeQueueDisabled(err error) bool {
+	_, ok := err.(ErrMergeQueueDisabled)
+	return ok
+}
+
+func (err ErrMergeQueueDisabled) Error() string {
+	return fmt.Sprintf("merge queue is not enabled [repo_id: %d]", err.RepoID)
+}
+
+// ErrPullRequestAlreadyQueued represents an error that a pull request is already in the merge queue.
+type ErrPullRequestAlreadyQueued struct {
+	ID int64
+}
+
+// IsErrPullRequestAlreadyQueued checks if an error is an ErrPullRequestAlreadyQueued.
+func IsErrPullRequestAlreadyQueued(err error) bool {
+	_, ok := err.(ErrPullRequestAlreadyQueued)
+	return ok
+}
+
+func (err ErrPullRequestAlreadyQueued) Error() string {
+	return fmt.Sprintf("pull request is already in the merge queue [id: %d]", err.ID)
+}
+
+// ErrMergeQueueEntryNotExist represents an error that a pull request is not in the merge queue.
+type ErrMergeQueueEntryNotExist struct {
+	PullID int64
+}
+
+// IsErrMergeQueueEntryNotExist checks if an error is an ErrMergeQueueEntryNotExist.
+func IsErrMergeQueueEntryNotExist(err error) bool {
+	_, ok := err.(ErrMergeQueueEntryNotExist)
+	return ok
+}
+
+func (err ErrMergeQueueEntryNotExist) Error() string {
+	return fmt.Sprintf("merge queue entry does not exist [pull_id: %d]", err.PullID)
+}
+
+// ErrRepoBackupNotExist represents an error that a repository backup run does not exist.
+type ErrRepoBackupNotExist struct {
+	ID int64
+}
+
+// IsErrRepoBackupNotExist checks if an error is an ErrRepoBackupNotExist.
+func IsErrRepoBackupNotExist(err error) bool {
+	_, ok := err.(ErrRepoBackupNotExist)
+	return ok
+}
+
+func (err ErrRepoBackupNotExist) Error() string {
+	return fmt.Sprintf("repository backup does not exist [id: %d]", err.ID)
+}
+
+// ErrRepoBackupAlreadyQueued represents an error that a backup of a repository is already pending or running.
+type ErrRepoBackupAlreadyQueued struct {
+	RepoID int64
+}
+
+// IsErrRepoBackupAlreadyQueued checks if an error is an ErrRepoBackupAlreadyQueued.
+func IsErrRepoBackupAlreadyQueued(err error) bool {
+	_, ok := err.(ErrRepoBackupAlreadyQueued)
+	return ok
+}
+
+func (err ErrRepoBackupAlreadyQueued) Error() string {
+	return fmt.Sprintf("a backup of the repository is already pending or running [repo_id: %d]", err.RepoID)
+}
+
+// ErrRepoBackupNotFailed represents an error that a repository backup run which did not fail should be retried.
+type ErrRepoBackupNotFailed struct {
+	ID int64
+}
+
+// IsErrRepoBackupNotFailed checks if an error is an ErrRepoBackupNotFailed.
+func IsErrRepoBackupNotFailed(err error) bool {
+	_, ok := err.(ErrRepoBackupNotFailed)
+	return ok
+}
+
+func (err ErrRepoBackupNotFailed) Error() string {
+	return fmt.Sprintf("only failed repository backups can be retried [id: %d]", err.ID)
+}
+
+// ErrRepoMetadataNotExist represents an error that a repository has no metadata with a key.
+type ErrRepoMetadataNotExist struct {
+	RepoID int64
+	Key    string
+}
+
+// IsErrRepoMetadataNotExist checks if an error is an ErrRepoMetadataNotExist.
+func IsErrRepoMetadataNotExist(err error) bool {
+	_, ok := err.(ErrRepoMetadataNotExist)
+	return ok
+}
+
+func (err ErrRepoMetadataNotExist) Error() string {
+	return fmt.Sprintf("repository metadata does not exist [repo_id: %d, key: %s]", err.RepoID, err.Key)
+}
+
+// ErrRepoMetadataInvalidKey represents an error that a key can not be used for repository metadata.
+type ErrRepoMetadataInvalidKey struct {
+	Key string
+}
+
+// IsErrRepoMetadataInvalidKey checks if an error is an ErrRepoMetadataInvalidKey.
+func IsErrRepoMetadataInvalidKey(err error) bool {
+	_, ok := err.(ErrRepoMetadataInvalidKey)
+	return ok
+}
+
+func (err ErrRepoMetadataInvalidKey) Error() string {
+	return fmt.Sprintf("invalid repository metadata key [key: %s]", err.Key)
+}
+
+// ErrRepoMetadataPreconditionFailed represents an error that repository metadata was changed since it was read.
+type ErrRepoMetadataPreconditionFailed struct {
+	RepoID int64
+	Key    string
+}
+
+// IsErrRepoMetadataPreconditionFailed checks if an error is an ErrRepoMetadataPreconditionFailed.
+func IsErrRepoMetadataPreconditionFailed(err error) bool {
+	_, ok := err.(ErrRepoMetadataPreconditionFailed)
+	return ok
+}
+
+func (err ErrRepoMetadataPreconditionFailed) Error() string {
+	return fmt.Sprintf("repository metadata does not match the expected version [repo_id: %d, key: %s]", err.RepoID, err.Key)
+}
+
+// ErrRepoMetadataQuotaExceeded represents an error that the metadata of a repository exceeds its quota.
+type ErrRepoMetadataQuotaExceeded struct {
+	RepoID int64
+	Reason string
+}
+
+// IsErrRepoMetadataQuotaExceeded checks if an error is an ErrRepoMetadataQuotaExceeded.
+func IsErrRepoMetadataQuotaExceeded(err error) bool {
+	_, ok := err.(ErrRepoMetadataQuotaExceeded)
+	return ok
+}
+
+func (err ErrRepoMetadataQuotaExceeded) Error() string {
+	return fmt.Sprintf("repository metadata quota exceeded: %s [repo_id: %d]", err.Reason, err.RepoID)
+}
+
 // ErrTagAlreadyExists represents an error that tag with such name already exists.
 type ErrTagAlreadyExists struct {
 	TagName string
@@ -1296,6 +1656,38 @@ func (err ErrCommitIDDoesNotMatch) Error() string {
 	return fmt.Sprintf("file CommitID does not match [given: %s, expected: %s]", err.GivenCommitID, err.CurrentCommitID)
 }
 
+// ErrCommitApplyConflict represents a "CommitApplyConflict" kind of error.
+type ErrCommitApplyConflict struct {
+	CommitID string
+	Branch   string
+	Files    []string
+}
+
+// IsErrCommitApplyConflict checks if an error is a ErrCommitApplyConflict.
+func IsErrCommitApplyConflict(err error) bool {
+	_, ok := err.(ErrCommitApplyConflict)
+	return ok
+}
+
+func (err ErrCommitApplyConflict) Error() string {
+	return fmt.Sprintf("commit can not be applied cleanly [commit: %s, branch: %s, files: %v]", err.CommitID, err.Branch, err.Files)
+}
+
+// ErrCommitApplyMergeCommit represents a "CommitApplyMergeCommit" kind of error.
+type ErrCommitApplyMergeCommit struct {
+	CommitID string
+}
+
+// IsErrCommitApplyMergeCommit checks if an error is a ErrCommitApplyMergeCommit.
+func IsErrCommitApplyMergeCommit(err error) bool {
+	_, ok := err.(ErrCommitApplyMergeCommit)
+	return ok
+}
+
+func (err ErrCommitApplyMergeCommit) Error() string {
+	return fmt.Sprintf("merge commits can not be cherry-picked or reverted [commit: %s]", err.CommitID)
+}
+
 // ErrSHAOrCommitIDNotProvided represents a "SHAOrCommitIDNotProvided" kind of error.
 type ErrSHAOrCommitIDNotProvided struct{}
 
@@ -1372,6 +1764,39 @@ func (err ErrIssueIsClosed) Error() string {
 	return fmt.Sprintf("issue is closed [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
 }
 
+// ErrIssueMaxPinReached represents a "IssueMaxPinReached" kind of error.
+type ErrIssueMaxPinReached struct {
+	RepoID int64
+	IsPull bool
+}
+
+// IsErrIssueMaxPinReached checks if an error is a ErrIssueMaxPinReached.
+func IsErrIssueMaxPinReached(err error) bool {
+	_, ok := err.(ErrIssueMaxPinReached)
+	return ok
+}
+
+func (err ErrIssueMaxPinReached) Error() string {
+	return fmt.Sprintf("the maximum number of pinned issues is reached [repo_id: %d, is_pull: %t]", err.RepoID, err.IsPull)
+}
+
+// ErrIssueNotPinned represents a "IssueNotPinned" kind of error.
+type ErrIssueNotPinned struct {
+	ID     int64
+	RepoID int64
+	Index  int64
+}
+
+// IsErrIssueNotPinned checks if an error is a ErrIssueNotPinned.
+func IsErrIssueNotPinned(err error) bool {
+	_, ok := err.(ErrIssueNotPinned)
+	return ok
+}
+
+func (err ErrIssueNotPinned) Error() string {
+	return fmt.Sprintf("issue is not pinned [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
+}
+
 // ErrIssueLabelTemplateLoad represents a "ErrIssueLabelTemplateLoad" kind of error.
 type ErrIssueLabelTemplateLoad struct {
 	TemplateFile  string
@@ -1627,6 +2052,24 @@ func (err ErrPullRequestHasMerged) Error() string {
 		err.ID, err.IssueID, err.HeadRepoID, err.BaseRepoID, err.HeadBranch, err.BaseBranch)
 }
 
+// ErrPullRequestHeadRefMissing represents a "PullRequestHeadRefMissing"-error
+type ErrPullRequestHeadRefMissing struct {
+	ID         int64
+	BaseRepoID int64
+	Index      int64
+}
+
+// IsErrPullRequestHeadRefMissing checks if an error is a ErrPullRequestHeadRefMissing.
+func IsErrPullRequestHeadRefMissing(err error) bool {
+	_, ok := err.(ErrPullRequestHeadRefMissing)
+	return ok
+}
+
+func (err ErrPullRequestHeadRefMissing) Error() string {
+	return fmt.Sprintf("head ref of pull request was cleaned up and can't be restored [id: %d, base_repo_id: %d, index: %d]",
+		err.ID, err.BaseRepoID, err.Index)
+}
+
 // _________                                       __
 // \_   ___ \  ____   _____   _____   ____   _____/  |_
 // /    \  \/ /  _ \ /     \ /     \_/ __ \ /    \   __\
@@ -42,6 +42,9 @@ type GPGKey struct {
 	CanEncryptComms   bool
 	CanEncryptStorage bool
 	CanCertify        bool
+
+	// ExpiryNotifiedUnix is set once the owner has been warned that the key is about to expire
+	ExpiryNotifiedUnix timeutil.TimeStamp
 }
 
 func init() {
@@ -61,6 +64,63 @@ func (key *GPGKey) AfterLoad(session *xorm.Session) {
 	}
 }
 
+// HasExpiry returns true if the key expires at some point
+func (key *GPGKey) HasExpiry() bool {
+	// keys without expiry have either no or the zero time as expiry
+	return key.ExpiredUnix > 0
+}
+
+// IsExpiredAt returns true if the key has expired at the given time
+func (key *GPGKey) IsExpiredAt(t time.Time) bool {
+	return key.HasExpiry() && !t.Before(key.ExpiredUnix.AsTime())
+}
+
+// IsExpired returns true if the key has expired
+func (key *GPGKey) IsExpired() bool {
+	return key.IsExpiredAt(time.Now())
+}
+
+// CanSignCommits returns true if the key or one of its subkeys can sign commits
+func (key *GPGKey) CanSignCommits() bool {
+	if key.CanSign {
+		return true
+	}
+	for _, sk := range key.SubsKey {
+		if sk.CanSign {
+			return true
+		}
+	}
+	return false
+}
+
+// VerifiableEmails returns the activated email addresses of the owner of the key,
+// whose commits can be verified with it
+func (key *GPGKey) VerifiableEmails() ([]string, error) {
+	userEmails, err := GetEmailAddresses(key.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	emails := make([]string, 0, len(userEmails))
+	for _, e := range userEmails {
+		if !e.IsActivated {
+			continue
+		}
+		// a verified key can verify all activated email addresses of its owner
+		if key.Verified {
+			emails = append(emails, e.Email)
+			continue
+		}
+		for _, ke := range key.Emails {
+			if strings.EqualFold(ke.Email, e.Email) {
+				emails = append(emails, e.Email)
+				break
+			}
+		}
+	}
+	return emails, nil
+}
+
 // ListGPGKeys returns a list of public keys belongs to given user.
 func ListGPGKeys(uid int64, listOptions db.ListOptions) ([]*GPGKey, error) {
 	return listGPGKeys(db.GetEngine(db.DefaultContext), uid, listOptions)
@@ -99,6 +159,24 @@ func GetGPGKeysByKeyID(keyID string) ([]*GPGKey, error) {
 	return keys, db.GetEngine(db.DefaultContext).Where("key_id=?", keyID).Find(&keys)
 }
 
+// FindGPGKeysExpiringBefore returns the primary keys expiring between now and the given deadline,
+// whose owners have not been warned about it yet
+func FindGPGKeysExpiringBefore(deadline time.Time) ([]*GPGKey, error) {
+	keys := make([]*GPGKey, 0, 10)
+	return keys, db.GetEngine(db.DefaultContext).
+		Where("primary_key_id = '' AND expiry_notified_unix = 0").
+		And("expired_unix > ? AND expired_unix <= ?", timeutil.TimeStampNow(), deadline.Unix()).
+		Asc("expired_unix").
+		Find(&keys)
+}
+
+// SetGPGKeyExpiryNotified records that the owner of the key has been warned about its expiry
+func SetGPGKeyExpiryNotified(key *GPGKey) error {
+	key.ExpiryNotifiedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(db.DefaultContext).ID(key.ID).Cols("expiry_notified_unix").Update(key)
+	return err
+}
+
 // GPGKeyToEntity retrieve the imported key and the traducted entity
 func GPGKeyToEntity(k *GPGKey) (*openpgp.Entity, error) {
 	impKey, err := GetGPGImportByKeyID(k.KeyID)
@@ -65,6 +65,9 @@ const (
 	BadDefaultSignature = "gpg.error.probable_bad_default_signature"
 	// NoKeyFound is used as the reason when no key can be found to verify the signature.
 	NoKeyFound = "gpg.error.no_gpg_keys_found"
+	// ExpiredKey is used as the reason when the signature is verified by a key that had already
+	// expired when the signature was made.
+	ExpiredKey = "gpg.error.expired_key"
 )
 
 // ParseCommitsWithSignature checks if signaute of commits are corresponding to users gpg keys.
@@ -87,15 +90,25 @@ func ParseCommitsWithSignature(oldCommits []*UserCommit, repository *Repository)
 
 // ParseCommitWithSignature check if signature is good against keystore.
 func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
+	return parseObjectWithSignature(c.ID, c.Committer, c.Signature, c.GetRepositoryDefaultPublicGPGKey)
+}
+
+// ParseTagWithSignature check if the signature of an annotated tag is good against keystore,
+// the tagger takes the place of the committer.
+func ParseTagWithSignature(t *git.Tag) *CommitVerification {
+	return parseObjectWithSignature(t.ID, t.Tagger, t.Signature, t.GetRepositoryDefaultPublicGPGKey)
+}
+
+func parseObjectWithSignature(id git.SHA1, signer *git.Signature, signature *git.CommitGPGSignature, getDefaultGPGSettings func(forceUpdate bool) (*git.GPGSettings, error)) *CommitVerification {
 	var committer *User
-	if c.Committer != nil {
+	if signer != nil {
 		var err error
 		// Find Committer account
-		committer, err = GetUserByEmail(c.Committer.Email) // This finds the user by primary email or activated email so commit will not be valid if email is not
-		if err != nil {                                    // Skipping not user for committer
+		committer, err = GetUserByEmail(signer.Email) // This finds the user by primary email or activated email so commit will not be valid if email is not
+		if err != nil {                               // Skipping not user for committer
 			committer = &User{
-				Name:  c.Committer.Name,
-				Email: c.Committer.Email,
+				Name:  signer.Name,
+				Email: signer.Email,
 			}
 			// We can expect this to often be an ErrUserNotExist. in the case
 			// it is not, however, it is important to log it.
@@ -112,7 +125,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 	}
 
 	// If no signature just report the committer
-	if c.Signature == nil {
+	if signature == nil {
 		return &CommitVerification{
 			CommittingUser: committer,
 			Verified:       false,                         // Default value
@@ -121,7 +134,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 	}
 
 	// Parsing signature
-	sig, err := extractSignature(c.Signature.Signature)
+	sig, err := extractSignature(signature.Signature)
 	if err != nil { // Skipping failed to extract sign
 		log.Error("SignatureRead err: %v", err)
 		return &CommitVerification{
@@ -143,7 +156,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 	// First check if the sig has a keyID and if so just look at that
 	if commitVerification := hashAndVerifyForKeyID(
 		sig,
-		c.Signature.Payload,
+		signature.Payload,
 		committer,
 		keyID,
 		setting.AppName,
@@ -170,7 +183,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		committerEmailAddresses, _ := GetEmailAddresses(committer.ID)
 		activated := false
 		for _, e := range committerEmailAddresses {
-			if e.IsActivated && strings.EqualFold(e.Email, c.Committer.Email) {
+			if e.IsActivated && strings.EqualFold(e.Email, signer.Email) {
 				activated = true
 				break
 			}
@@ -182,11 +195,11 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 			email := ""
 			if k.Verified && activated {
 				canValidate = true
-				email = c.Committer.Email
+				email = signer.Email
 			}
 			if !canValidate {
 				for _, e := range k.Emails {
-					if e.IsActivated && strings.EqualFold(e.Email, c.Committer.Email) {
+					if e.IsActivated && strings.EqualFold(e.Email, signer.Email) {
 						canValidate = true
 						email = e.Email
 						break
@@ -197,7 +210,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 				continue // Skip this key
 			}
 
-			commitVerification := hashAndVerifyWithSubKeysCommitVerification(sig, c.Signature.Payload, k, committer, committer, email)
+			commitVerification := hashAndVerifyWithSubKeysCommitVerification(sig, signature.Payload, k, committer, committer, email)
 			if commitVerification != nil {
 				return commitVerification
 			}
@@ -214,7 +227,7 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 		if err := gpgSettings.LoadPublicKeyContent(); err != nil {
 			log.Error("Error getting default signing key: %s %v", gpgSettings.KeyID, err)
-		} else if commitVerification := verifyWithGPGSettings(&gpgSettings, sig, c.Signature.Payload, committer, keyID); commitVerification != nil {
+		} else if commitVerification := verifyWithGPGSettings(&gpgSettings, sig, signature.Payload, committer, keyID); commitVerification != nil {
 			if commitVerification.Reason == BadSignature {
 				defaultReason = BadSignature
 			} else {
@@ -223,13 +236,13 @@ func ParseCommitWithSignature(c *git.Commit) *CommitVerification {
 		}
 	}
 
-	defaultGPGSettings, err := c.GetRepositoryDefaultPublicGPGKey(false)
+	defaultGPGSettings, err := getDefaultGPGSettings(false)
 	if err != nil {
 		log.Error("Error getting default public gpg key: %v", err)
 	} else if defaultGPGSettings == nil {
-		log.Warn("Unable to get defaultGPGSettings for unattached commit: %s", c.ID.String())
+		log.Warn("Unable to get defaultGPGSettings for unattached commit: %s", id.String())
 	} else if defaultGPGSettings.Sign {
-		if commitVerification := verifyWithGPGSettings(defaultGPGSettings, sig, c.Signature.Payload, committer, keyID); commitVerification != nil {
+		if commitVerification := verifyWithGPGSettings(defaultGPGSettings, sig, signature.Payload, committer, keyID); commitVerification != nil {
 			if commitVerification.Reason == BadSignature {
 				defaultReason = BadSignature
 			} else {
@@ -367,6 +380,16 @@ func hashAndVerifyWithSubKeysCommitVerification(sig *packet.Signature, payload s
 	}
 
 	if key != nil {
+		if key.IsExpiredAt(sig.CreationTime) {
+			return &CommitVerification{
+				CommittingUser: committer,
+				Verified:       false,
+				Reason:         ExpiredKey,
+				SigningUser:    signer,
+				SigningKey:     key,
+				SigningEmail:   email,
+			}
+		}
 		return &CommitVerification{ // Everything is ok
 			CommittingUser: committer,
 			Verified:       true,
@@ -390,3 +390,35 @@ epiDVQ==
 		assert.Equal(t, time.Unix(1586105389, 0), expire)
 	}
 }
+
+func TestGPGKeyExpiry(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	now := time.Now()
+	neverExpires := &GPGKey{OwnerID: 2, KeyID: "0000000000000001", ExpiredUnix: timeutil.TimeStamp(time.Time{}.Unix()), CanSign: true}
+	expiresSoon := &GPGKey{OwnerID: 2, KeyID: "0000000000000002", ExpiredUnix: timeutil.TimeStamp(now.Add(24 * time.Hour).Unix()), CanSign: true}
+	expiresLater := &GPGKey{OwnerID: 2, KeyID: "0000000000000003", ExpiredUnix: timeutil.TimeStamp(now.Add(60 * 24 * time.Hour).Unix()), CanSign: true}
+	expired := &GPGKey{OwnerID: 2, KeyID: "0000000000000004", ExpiredUnix: timeutil.TimeStamp(now.Add(-24 * time.Hour).Unix()), CanSign: true}
+	for _, key := range []*GPGKey{neverExpires, expiresSoon, expiresLater, expired} {
+		_, err := db.GetEngine(db.DefaultContext).Insert(key)
+		assert.NoError(t, err)
+	}
+
+	assert.False(t, neverExpires.HasExpiry())
+	assert.False(t, neverExpires.IsExpired())
+	assert.True(t, expiresSoon.HasExpiry())
+	assert.False(t, expiresSoon.IsExpired())
+	assert.True(t, expiresSoon.IsExpiredAt(now.Add(48*time.Hour)))
+	assert.True(t, expired.IsExpired())
+
+	keys, err := FindGPGKeysExpiringBefore(now.Add(14 * 24 * time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.Equal(t, expiresSoon.KeyID, keys[0].KeyID)
+	}
+
+	assert.NoError(t, SetGPGKeyExpiryNotified(keys[0]))
+	keys, err = FindGPGKeysExpiringBefore(now.Add(14 * 24 * time.Hour))
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -30,7 +31,7 @@ import (
 // Issue represents an issue or pull request of repository.
 type Issue struct {
 	ID               int64       `xorm:"pk autoincr"`
-	RepoID           int64       `xorm:"INDEX UNIQUE(repo_index)"`
+	RepoID           int64       `xorm:"INDEX UNIQUE(repo_index) INDEX(repo_pull_closed)"`
 	Repo             *Repository `xorm:"-"`
 	Index            int64       `xorm:"UNIQUE(repo_index)"` // Index in one repository.
 	PosterID         int64       `xorm:"INDEX"`
@@ -47,10 +48,10 @@ type Issue struct {
 	Priority         int
 	AssigneeID       int64        `xorm:"-"`
 	Assignee         *User        `xorm:"-"`
-	IsClosed         bool         `xorm:"INDEX"`
-	IsRead           bool         `xorm:"-"`
-	IsPull           bool         `xorm:"INDEX"` // Indicates whether is a pull request or not.
+	IsPull           bool         `xorm:"INDEX INDEX(repo_pull_closed)"` // Indicates whether is a pull request or not.
 	PullRequest      *PullRequest `xorm:"-"`
+	IsClosed         bool         `xorm:"INDEX INDEX(repo_pull_closed)"`
+	IsRead           bool         `xorm:"-"`
 	NumComments      int
 	Ref              string
 
@@ -70,6 +71,9 @@ type Issue struct {
 	// with write access
 	IsLocked bool `xorm:"NOT NULL DEFAULT false"`
 
+	// PinOrder is the position of the issue among the pinned issues of its repository, 0 if it is not pinned
+	PinOrder int `xorm:"NOT NULL DEFAULT 0"`
+
 	// For view issue page.
 	ShowTag CommentTag `xorm:"-"`
 }
@@ -628,23 +632,33 @@ func (issue *Issue) doChangeStatus(e db.Engine, doer *User, isMergePull bool) (*
 		issue.ClosedUnix = 0
 	}
 
-	if err := updateIssueCols(e, issue, "is_closed", "closed_unix"); err != nil {
+	// the status is only changed by one of concurrent changes, the counters are updated by it alone
+	count, err := e.ID(issue.ID).Where("is_closed = ?", !issue.IsClosed).Cols("is_closed", "closed_unix").Update(issue)
+	if err != nil {
 		return nil, err
+	} else if count == 0 {
+		if issue.IsPull {
+			return nil, ErrPullWasClosed{ID: issue.ID}
+		}
+		return nil, ErrIssueWasClosed{ID: issue.ID}
 	}
 
 	// Update issue count of labels
-	if err := issue.getLabels(e); err != nil {
+	labelIDs := make([]int64, 0, 5)
+	if err := e.Table("issue_label").Where("issue_id = ?", issue.ID).Cols("label_id").Find(&labelIDs); err != nil {
 		return nil, err
 	}
-	for idx := range issue.Labels {
-		if err := updateLabelCols(e, issue.Labels[idx], "num_issues", "num_closed_issue"); err != nil {
-			return nil, err
-		}
+	numClosedIssues := 1
+	if !issue.IsClosed {
+		numClosedIssues = -1
+	}
+	if err := updateLabelIssueCounters(e, 0, numClosedIssues, labelIDs...); err != nil {
+		return nil, err
 	}
 
 	// Update issue count of milestone
 	if issue.MilestoneID > 0 {
-		if err := updateMilestoneCounters(e, issue.MilestoneID); err != nil {
+		if err := updateMilestoneIssueCounters(e, issue.MilestoneID, 0, numClosedIssues); err != nil {
 			return nil, err
 		}
 	}
@@ -723,6 +737,9 @@ func (issue *Issue) ChangeTitle(doer *User, oldTitle string) (err error) {
 	if _, err = createComment(db.GetEngine(ctx), opts); err != nil {
 		return fmt.Errorf("createComment: %v", err)
 	}
+	if err = issue.syncPullRequestDraft(db.GetEngine(ctx), oldTitle); err != nil {
+		return err
+	}
 	if err = issue.addCrossReferences(db.GetEngine(ctx), doer, true); err != nil {
 		return err
 	}
@@ -730,6 +747,25 @@ func (issue *Issue) ChangeTitle(doer *User, oldTitle string) (err error) {
 	return committer.Commit()
 }
 
+// syncPullRequestDraft marks the pull request of the issue as a draft when a work in progress prefix is added
+// to its title, and as ready for review when the prefix is removed
+func (issue *Issue) syncPullRequestDraft(e db.Engine, oldTitle string) error {
+	if !issue.IsPull {
+		return nil
+	}
+	isDraft := HasWorkInProgressPrefix(issue.Title)
+	if isDraft == HasWorkInProgressPrefix(oldTitle) {
+		return nil
+	}
+	if _, err := e.Where("issue_id = ?", issue.ID).Cols("is_draft").Update(&PullRequest{IsDraft: isDraft}); err != nil {
+		return fmt.Errorf("update is_draft: %v", err)
+	}
+	if issue.PullRequest != nil {
+		issue.PullRequest.IsDraft = isDraft
+	}
+	return nil
+}
+
 // ChangeRef changes the branch of this issue, as the given user.
 func (issue *Issue) ChangeRef(doer *User, oldRef string) (err error) {
 	ctx, committer, err := db.TxContext()
@@ -908,7 +944,7 @@ func newIssue(e db.Engine, doer *User, opts NewIssueOptions) (err error) {
 	}
 
 	if opts.Issue.MilestoneID > 0 {
-		if err := updateMilestoneCounters(e, opts.Issue.MilestoneID); err != nil {
+		if err := updateMilestoneIssueCounters(e, opts.Issue.MilestoneID, 1, closedIssueCount(opts.Issue.IsClosed)); err != nil {
 			return err
 		}
 
@@ -1133,6 +1169,7 @@ type IssuesOptions struct {
 	IsClosed           util.OptionalBool
 	IsPull             util.OptionalBool
 	LabelIDs           []int64
+	LabelScopes        []string // issues need a label of each of the scopes
 	IncludedLabelNames []string
 	ExcludedLabelNames []string
 	IncludeMilestones  []string
@@ -1143,6 +1180,34 @@ type IssuesOptions struct {
 	// prioritize issues from this repo
 	PriorityRepoID int64
 	IsArchived     util.OptionalBool
+	// list the pinned issues first in their pinned order, before sorting by SortType
+	PinnedFirst bool
+}
+
+// IssueSortTypes are the sort types which can be chosen for the issue and pull request lists of a repository
+var IssueSortTypes = []string{"latest", "oldest", "recentupdate", "leastupdate", "mostcomment", "leastcomment", "priority", "nearduedate", "farduedate"}
+
+// IsValidIssueSortType returns true if sortType is one of IssueSortTypes
+func IsValidIssueSortType(sortType string) bool {
+	return util.IsStringInSlice(sortType, IssueSortTypes)
+}
+
+// DefaultIssueSortType returns the sort type of the issue and pull request lists of repo if none is given explicitly.
+// The preference of the user takes precedence over the default of the repository, which takes precedence over
+// the default of the instance.
+func DefaultIssueSortType(repo *Repository, user *User) string {
+	if user != nil && IsValidIssueSortType(user.IssueSortType) {
+		return user.IssueSortType
+	}
+	if unit, err := repo.GetUnit(UnitTypeIssues); err == nil {
+		if sortType := unit.IssuesConfig().DefaultSortType; IsValidIssueSortType(sortType) {
+			return sortType
+		}
+	}
+	if IsValidIssueSortType(setting.Repository.Issue.DefaultSortType) {
+		return setting.Repository.Issue.DefaultSortType
+	}
+	return "latest"
 }
 
 // sortIssuesSession sort an issues-related session based on the provided
@@ -1150,7 +1215,7 @@ type IssuesOptions struct {
 func sortIssuesSession(sess *xorm.Session, sortType string, priorityRepoID int64) {
 	switch sortType {
 	case "oldest":
-		sess.Asc("issue.created_unix")
+		sess.Asc("issue.created_unix").Asc("issue.id")
 	case "recentupdate":
 		sess.Desc("issue.updated_unix")
 	case "leastupdate":
@@ -1270,6 +1335,10 @@ func (opts *IssuesOptions) setupSession(sess *xorm.Session) {
 		}
 	}
 
+	for _, scope := range opts.LabelScopes {
+		sess.In("issue.id", buildLabelScopeIssueIDsCondition(scope))
+	}
+
 	if len(opts.IncludedLabelNames) > 0 {
 		sess.In("issue.id", BuildLabelNamesIssueIDsCondition(opts.IncludedLabelNames))
 	}
@@ -1369,6 +1438,9 @@ func Issues(opts *IssuesOptions) ([]*Issue, error) {
 
 	sess.Join("INNER", "repository", "`issue`.repo_id = `repository`.id")
 	opts.setupSession(sess)
+	if opts.PinnedFirst {
+		sess.OrderBy("CASE WHEN issue.pin_order = 0 THEN 1 ELSE 0 END, issue.pin_order")
+	}
 	sortIssuesSession(sess, opts.SortType, opts.PriorityRepoID)
 
 	issues := make([]*Issue, 0, opts.ListOptions.PageSize)
@@ -1583,18 +1655,32 @@ func getIssueStatsChunk(opts *IssueStatsOptions, issueIDs []int64) (*IssueStats,
 	}
 
 	var err error
-	stats.OpenCount, err = countSession(opts).
-		And("issue.is_closed = ?", false).
-		Count(new(Issue))
-	if err != nil {
-		return stats, err
-	}
-	stats.ClosedCount, err = countSession(opts).
-		And("issue.is_closed = ?", true).
-		Count(new(Issue))
+	stats.OpenCount, stats.ClosedCount, err = countIssuesByState(countSession(opts))
 	return stats, err
 }
 
+// countIssuesByState counts the open and closed issues matched by the session in a single query
+func countIssuesByState(sess *xorm.Session) (numOpen, numClosed int64, err error) {
+	counts := make([]*struct {
+		IsClosed bool
+		Count    int64
+	}, 0, 2)
+	if err := sess.GroupBy("issue.is_closed").
+		Select("issue.is_closed AS is_closed, COUNT(*) AS count").
+		Table("issue").
+		Find(&counts); err != nil {
+		return 0, 0, err
+	}
+	for _, c := range counts {
+		if c.IsClosed {
+			numClosed = c.Count
+		} else {
+			numOpen = c.Count
+		}
+	}
+	return numOpen, numClosed, nil
+}
+
 // UserIssueStatsOptions contains parameters accepted by GetUserIssueStats.
 type UserIssueStatsOptions struct {
 	UserID      int64
@@ -1635,130 +1721,100 @@ func GetUserIssueStats(opts UserIssueStatsOptions) (*IssueStats, error) {
 		return s
 	}
 
-	switch opts.FilterMode {
-	case FilterModeAll:
-		stats.OpenCount, err = applyReposCondition(sess(cond), opts.UserRepoIDs).
-			And("issue.is_closed = ?", false).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-		stats.ClosedCount, err = applyReposCondition(sess(cond), opts.UserRepoIDs).
-			And("issue.is_closed = ?", true).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-	case FilterModeAssign:
-		stats.OpenCount, err = applyAssigneeCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", false).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-		stats.ClosedCount, err = applyAssigneeCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", true).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-	case FilterModeCreate:
-		stats.OpenCount, err = applyPosterCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", false).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-		stats.ClosedCount, err = applyPosterCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", true).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-	case FilterModeMention:
-		stats.OpenCount, err = applyMentionedCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", false).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-		stats.ClosedCount, err = applyMentionedCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", true).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-	case FilterModeReviewRequested:
-		stats.OpenCount, err = applyReviewRequestedCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", false).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-		stats.ClosedCount, err = applyReviewRequestedCondition(sess(cond), opts.UserID).
-			And("issue.is_closed = ?", true).
-			Count(new(Issue))
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	cond = cond.And(builder.Eq{"issue.is_closed": opts.IsClosed})
-	stats.AssignCount, err = applyAssigneeCondition(sess(cond), opts.UserID).Count(new(Issue))
-	if err != nil {
+	// The counts of all filter modes except review requested are taken in one query. The assignee and
+	// mention joins match at most one row per issue, so they don't change the number of rows counted.
+	reposCond := "0 = 1"
+	if len(opts.UserRepoIDs) > 0 {
+		reposCond = "issue.repo_id IN (" + joinInt64s(opts.UserRepoIDs) + ")"
+	}
+	counts := make([]*struct {
+		IsClosed              bool
+		YourRepositoriesCount int64
+		AssignCount           int64
+		CreateCount           int64
+		MentionCount          int64
+	}, 0, 2)
+	if err = sess(cond).
+		Join("LEFT", "issue_assignees", "issue.id = issue_assignees.issue_id AND issue_assignees.assignee_id = ?", opts.UserID).
+		Join("LEFT", "issue_user", "issue.id = issue_user.issue_id AND issue_user.is_mentioned = ? AND issue_user.uid = ?", true, opts.UserID).
+		GroupBy("issue.is_closed").
+		Select(fmt.Sprintf("issue.is_closed AS is_closed, "+
+			"COUNT(CASE WHEN %s THEN 1 END) AS your_repositories_count, "+
+			"COUNT(issue_assignees.id) AS assign_count, "+
+			"COUNT(CASE WHEN issue.poster_id = %d THEN 1 END) AS create_count, "+
+			"COUNT(issue_user.id) AS mention_count", reposCond, opts.UserID)).
+		Table("issue").
+		Find(&counts); err != nil {
 		return nil, err
 	}
 
-	stats.CreateCount, err = applyPosterCondition(sess(cond), opts.UserID).Count(new(Issue))
+	reviewRequestedOpen, reviewRequestedClosed, err := countIssuesByState(applyReviewRequestedCondition(sess(cond), opts.UserID))
 	if err != nil {
 		return nil, err
 	}
 
-	stats.MentionCount, err = applyMentionedCondition(sess(cond), opts.UserID).Count(new(Issue))
-	if err != nil {
-		return nil, err
-	}
+	for _, c := range counts {
+		var count int64
+		switch opts.FilterMode {
+		case FilterModeAll:
+			count = c.YourRepositoriesCount
+		case FilterModeAssign:
+			count = c.AssignCount
+		case FilterModeCreate:
+			count = c.CreateCount
+		case FilterModeMention:
+			count = c.MentionCount
+		}
+		if c.IsClosed {
+			stats.ClosedCount = count
+		} else {
+			stats.OpenCount = count
+		}
 
-	stats.YourRepositoriesCount, err = applyReposCondition(sess(cond), opts.UserRepoIDs).Count(new(Issue))
-	if err != nil {
-		return nil, err
+		if c.IsClosed == opts.IsClosed {
+			stats.YourRepositoriesCount = c.YourRepositoriesCount
+			stats.AssignCount = c.AssignCount
+			stats.CreateCount = c.CreateCount
+			stats.MentionCount = c.MentionCount
+		}
 	}
 
-	stats.ReviewRequestedCount, err = applyReviewRequestedCondition(sess(cond), opts.UserID).Count(new(Issue))
-	if err != nil {
-		return nil, err
+	if opts.FilterMode == FilterModeReviewRequested {
+		stats.OpenCount, stats.ClosedCount = reviewRequestedOpen, reviewRequestedClosed
+	}
+	if opts.IsClosed {
+		stats.ReviewRequestedCount = reviewRequestedClosed
+	} else {
+		stats.ReviewRequestedCount = reviewRequestedOpen
 	}
 
 	return stats, nil
 }
 
-// GetRepoIssueStats returns number of open and closed repository issues by given filter mode.
-func GetRepoIssueStats(repoID, uid int64, filterMode int, isPull bool) (numOpen, numClosed int64) {
-	countSession := func(isClosed, isPull bool, repoID int64) *xorm.Session {
-		sess := db.GetEngine(db.DefaultContext).
-			Where("is_closed = ?", isClosed).
-			And("is_pull = ?", isPull).
-			And("repo_id = ?", repoID)
-
-		return sess
+// joinInt64s formats the numbers as a comma separated list for an IN condition
+func joinInt64s(ids []int64) string {
+	strs := make([]string, len(ids))
+	for i, id := range ids {
+		strs[i] = strconv.FormatInt(id, 10)
 	}
+	return strings.Join(strs, ",")
+}
 
-	openCountSession := countSession(false, isPull, repoID)
-	closedCountSession := countSession(true, isPull, repoID)
+// GetRepoIssueStats returns number of open and closed repository issues by given filter mode.
+func GetRepoIssueStats(repoID, uid int64, filterMode int, isPull bool) (numOpen, numClosed int64) {
+	sess := db.GetEngine(db.DefaultContext).
+		Where("issue.repo_id = ?", repoID).
+		And("issue.is_pull = ?", isPull)
 
 	switch filterMode {
 	case FilterModeAssign:
-		applyAssigneeCondition(openCountSession, uid)
-		applyAssigneeCondition(closedCountSession, uid)
+		applyAssigneeCondition(sess, uid)
 	case FilterModeCreate:
-		applyPosterCondition(openCountSession, uid)
-		applyPosterCondition(closedCountSession, uid)
+		applyPosterCondition(sess, uid)
 	}
 
-	openResult, _ := openCountSession.Count(new(Issue))
-	closedResult, _ := closedCountSession.Count(new(Issue))
-
-	return openResult, closedResult
+	numOpen, numClosed, _ = countIssuesByState(sess)
+	return numOpen, numClosed
 }
 
 // SearchIssueIDsByKeyword search issues on database
@@ -1846,6 +1902,9 @@ func UpdateIssueByAPI(issue *Issue, doer *User) (statusChangeComment *Comment, t
 		if err != nil {
 			return nil, false, fmt.Errorf("createComment: %v", err)
 		}
+		if err := issue.syncPullRequestDraft(sess, currentIssue.Title); err != nil {
+			return nil, false, err
+		}
 	}
 
 	if currentIssue.IsClosed != issue.IsClosed {
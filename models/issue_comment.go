@@ -103,6 +103,10 @@ const (
 	CommentTypeProjectBoard
 	// Dismiss Review
 	CommentTypeDismissReview
+	// 33 Added to the merge queue
+	CommentTypeMergeQueueAdd
+	// 34 Removed from the merge queue, the content holds the reason if it was evicted
+	CommentTypeMergeQueueRemove
 )
 
 // CommentTag defines comment tag type
@@ -168,7 +172,7 @@ type Comment struct {
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 
 	// Reference issue in commit message
-	CommitSHA string `xorm:"VARCHAR(40)"`
+	CommitSHA string `xorm:"VARCHAR(64)"`
 
 	Attachments []*Attachment `xorm:"-"`
 	Reactions   ReactionList  `xorm:"-"`
@@ -918,8 +922,8 @@ func CreateComment(opts *CreateCommentOptions) (comment *Comment, err error) {
 	return comment, nil
 }
 
-// CreateRefComment creates a commit reference comment to issue.
-func CreateRefComment(doer *User, repo *Repository, issue *Issue, content, commitSHA string) error {
+// CreateRefComment creates a reference comment to issue for a commit in commitRepo.
+func CreateRefComment(doer *User, repo, commitRepo *Repository, issue *Issue, content, commitSHA string, action references.XRefAction) error {
 	if len(commitSHA) == 0 {
 		return fmt.Errorf("cannot create reference with empty commit SHA")
 	}
@@ -943,6 +947,8 @@ func CreateRefComment(doer *User, repo *Repository, issue *Issue, content, commi
 		Issue:     issue,
 		CommitSHA: commitSHA,
 		Content:   content,
+		RefRepoID: commitRepo.ID,
+		RefAction: action,
 	})
 	return err
 }
@@ -0,0 +1,146 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+)
+
+// issueCounterExpr returns the expression adding delta to the counter column, the counter does not go below zero
+func issueCounterExpr(column string, delta int) string {
+	if delta >= 0 {
+		return fmt.Sprintf("COALESCE(%s, 0) + %d", column, delta)
+	}
+	return fmt.Sprintf("CASE WHEN %[1]s > %[2]d THEN %[1]s - %[2]d ELSE 0 END", column, -delta)
+}
+
+// updateIssueCounters adds numIssues and numClosedIssues to the issue counters of the labels or milestones
+// of the ids, the table is the one of the bean. The issue and its labels or milestone must be changed in
+// the same transaction, and only once by concurrent changes, for the counters to stay right.
+func updateIssueCounters(e db.Engine, bean interface{}, numIssues, numClosedIssues int, ids ...int64) error {
+	if len(ids) == 0 || (numIssues == 0 && numClosedIssues == 0) {
+		return nil
+	}
+	_, err := e.In("id", ids).
+		SetExpr("num_issues", issueCounterExpr("num_issues", numIssues)).
+		SetExpr("num_closed_issues", issueCounterExpr("num_closed_issues", numClosedIssues)).
+		Update(bean)
+	return err
+}
+
+// updateLabelIssueCounters adds numIssues and numClosedIssues to the issue counters of the labels
+func updateLabelIssueCounters(e db.Engine, numIssues, numClosedIssues int, labelIDs ...int64) error {
+	return updateIssueCounters(e, new(Label), numIssues, numClosedIssues, labelIDs...)
+}
+
+// updateMilestoneIssueCounters adds numIssues and numClosedIssues to the issue counters of the milestone
+// and updates its completeness
+func updateMilestoneIssueCounters(e db.Engine, milestoneID int64, numIssues, numClosedIssues int) error {
+	if err := updateIssueCounters(e, new(Milestone), numIssues, numClosedIssues, milestoneID); err != nil {
+		return err
+	}
+	return updateMilestoneCompleteness(e, milestoneID)
+}
+
+// closedIssueCount returns the number of closed issues an issue counts for
+func closedIssueCount(isClosed bool) int {
+	if isClosed {
+		return 1
+	}
+	return 0
+}
+
+// IssueCounterDrift is the difference between the stored issue counters of labels or milestones and their
+// actual numbers of issues
+type IssueCounterDrift struct {
+	// Count is the number of labels or milestones whose counters are wrong
+	Count int64
+	// Issues is the sum of the differences of their numbers of issues
+	Issues int64
+	// ClosedIssues is the sum of the differences of their numbers of closed issues
+	ClosedIssues int64
+
+	ids []int64
+}
+
+type issueCounters struct {
+	ID                    int64
+	NumIssues             int64
+	NumClosedIssues       int64
+	ActualNumIssues       int64
+	ActualNumClosedIssues int64
+}
+
+func getIssueCounterDrift(e db.Engine, query string, args ...interface{}) (*IssueCounterDrift, error) {
+	counters := make([]*issueCounters, 0, 10)
+	if err := e.SQL(query, args...).Find(&counters); err != nil {
+		return nil, err
+	}
+
+	drift := &IssueCounterDrift{}
+	for _, c := range counters {
+		if c.NumIssues == c.ActualNumIssues && c.NumClosedIssues == c.ActualNumClosedIssues {
+			continue
+		}
+		drift.Count++
+		drift.Issues += abs(c.NumIssues - c.ActualNumIssues)
+		drift.ClosedIssues += abs(c.NumClosedIssues - c.ActualNumClosedIssues)
+		drift.ids = append(drift.ids, c.ID)
+	}
+	return drift, nil
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// GetLabelIssueCounterDrift returns how far the issue counters of the labels are from their numbers of issues
+func GetLabelIssueCounterDrift() (*IssueCounterDrift, error) {
+	return getIssueCounterDrift(db.GetEngine(db.DefaultContext), "SELECT label.id, label.num_issues, label.num_closed_issues, "+
+		"(SELECT COUNT(*) FROM `issue_label` WHERE issue_label.label_id = label.id) AS actual_num_issues, "+
+		"(SELECT COUNT(*) FROM `issue_label` INNER JOIN `issue` ON issue_label.issue_id = issue.id WHERE issue_label.label_id = label.id AND issue.is_closed = ?) AS actual_num_closed_issues "+
+		"FROM `label`", true)
+}
+
+// GetMilestoneIssueCounterDrift returns how far the issue counters of the milestones are from their numbers of issues
+func GetMilestoneIssueCounterDrift() (*IssueCounterDrift, error) {
+	return getIssueCounterDrift(db.GetEngine(db.DefaultContext), "SELECT milestone.id, milestone.num_issues, milestone.num_closed_issues, "+
+		"(SELECT COUNT(*) FROM `issue` WHERE issue.milestone_id = milestone.id) AS actual_num_issues, "+
+		"(SELECT COUNT(*) FROM `issue` WHERE issue.milestone_id = milestone.id AND issue.is_closed = ?) AS actual_num_closed_issues "+
+		"FROM `milestone`", true)
+}
+
+// FixLabelIssueCounters recounts the issues of the labels with wrong counters
+func FixLabelIssueCounters() (int64, error) {
+	drift, err := GetLabelIssueCounterDrift()
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range drift.ids {
+		if err := updateLabelCounters(db.GetEngine(db.DefaultContext), id); err != nil {
+			return 0, err
+		}
+	}
+	return drift.Count, nil
+}
+
+// FixMilestoneIssueCounters recounts the issues of the milestones with wrong counters
+func FixMilestoneIssueCounters() (int64, error) {
+	drift, err := GetMilestoneIssueCounterDrift()
+	if err != nil {
+		return 0, err
+	}
+	for _, id := range drift.ids {
+		if err := updateMilestoneCounters(db.GetEngine(db.DefaultContext), id); err != nil {
+			return 0, err
+		}
+	}
+	return drift.Count, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sync"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueCounters(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	// issue 2 has the labels 1 and 4 and the milestone 1
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+
+	_, err := issue.ChangeStatus(doer, true)
+	assert.NoError(t, err)
+	db.AssertExistsAndLoadBean(t, &Label{ID: 1, NumIssues: 2, NumClosedIssues: 1})
+	db.AssertExistsAndLoadBean(t, &Milestone{ID: 1, NumIssues: 1, NumClosedIssues: 1, Completeness: 100})
+	CheckConsistencyFor(t, &Label{}, &Milestone{})
+
+	label := db.AssertExistsAndLoadBean(t, &Label{ID: 2}).(*Label)
+	assert.NoError(t, NewIssueLabel(issue, label, doer))
+	db.AssertExistsAndLoadBean(t, &Label{ID: 2, NumIssues: 2, NumClosedIssues: 2})
+	assert.NoError(t, DeleteIssueLabel(issue, label, doer))
+	db.AssertExistsAndLoadBean(t, &Label{ID: 2, NumIssues: 1, NumClosedIssues: 1})
+	CheckConsistencyFor(t, &Label{})
+
+	issue.MilestoneID = 2
+	assert.NoError(t, ChangeMilestoneAssign(issue, doer, 1))
+	db.AssertExistsAndLoadBean(t, &Milestone{ID: 1}, "num_issues = 0 AND num_closed_issues = 0 AND completeness = 0")
+	db.AssertExistsAndLoadBean(t, &Milestone{ID: 2, NumIssues: 1, NumClosedIssues: 1, Completeness: 100})
+	// an outdated milestone does not change the counters again
+	assert.NoError(t, ChangeMilestoneAssign(issue, doer, 1))
+	CheckConsistencyFor(t, &Milestone{})
+
+	_, err = issue.ChangeStatus(doer, false)
+	assert.NoError(t, err)
+	db.AssertExistsAndLoadBean(t, &Label{ID: 1, NumIssues: 2}, "num_closed_issues = 0")
+	db.AssertExistsAndLoadBean(t, &Milestone{ID: 2, NumIssues: 1}, "num_closed_issues = 0 AND completeness = 0")
+	CheckConsistencyFor(t, &Label{}, &Milestone{})
+}
+
+func TestIssueCounters_ConcurrentClose(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i := range errs {
+		// each close has its own copy of the open issue
+		issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 2}).(*Issue)
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, errs[i] = issue.ChangeStatus(doer, true)
+		}(i)
+	}
+	wg.Wait()
+
+	closed := 0
+	for _, err := range errs {
+		if err == nil {
+			closed++
+		} else {
+			// issue 2 is a pull request
+			assert.True(t, IsErrPullWasClosed(err), "unexpected error: %v", err)
+		}
+	}
+	assert.Equal(t, 1, closed)
+	db.AssertExistsAndLoadBean(t, &Label{ID: 1, NumIssues: 2, NumClosedIssues: 1})
+	db.AssertExistsAndLoadBean(t, &Milestone{ID: 1, NumIssues: 1, NumClosedIssues: 1})
+	CheckConsistencyFor(t, &Label{}, &Milestone{}, &Repository{ID: 1})
+}
+
+func TestUpdateIssueCounters_NotNegative(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	// label 1 has no closed issues
+	assert.NoError(t, updateLabelIssueCounters(db.GetEngine(db.DefaultContext), -3, -1, 1))
+	db.AssertExistsAndLoadBean(t, &Label{ID: 1}, "num_issues = 0 AND num_closed_issues = 0")
+}
+
+func TestIssueCounterDrift(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	drift, err := GetLabelIssueCounterDrift()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, drift.Count)
+
+	_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE `label` SET num_issues = 5, num_closed_issues = 2 WHERE id = 1")
+	assert.NoError(t, err)
+	_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE `milestone` SET num_issues = 0 WHERE id IN (1, 3)")
+	assert.NoError(t, err)
+
+	drift, err = GetLabelIssueCounterDrift()
+	assert.NoError(t, err)
+	assert.Equal(t, &IssueCounterDrift{Count: 1, Issues: 3, ClosedIssues: 2, ids: []int64{1}}, drift)
+	drift, err = GetMilestoneIssueCounterDrift()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, drift.Count)
+	assert.EqualValues(t, 2, drift.Issues)
+	assert.EqualValues(t, 0, drift.ClosedIssues)
+
+	fixed, err := FixLabelIssueCounters()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, fixed)
+	fixed, err = FixMilestoneIssueCounters()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, fixed)
+	CheckConsistencyFor(t, &Label{}, &Milestone{})
+}
@@ -31,6 +31,7 @@ type Label struct {
 	Name            string
 	Description     string
 	Color           string `xorm:"VARCHAR(7)"`
+	Exclusive       bool   `xorm:"NOT NULL DEFAULT false"`
 	NumIssues       int
 	NumClosedIssues int
 	CreatedUnix     timeutil.TimeStamp `xorm:"INDEX created"`
@@ -49,16 +50,25 @@ func init() {
 	db.RegisterModel(new(IssueLabel))
 }
 
+// LabelTemplate represents a label of a label template file
+type LabelTemplate struct {
+	Name        string
+	Color       string
+	Description string
+	Exclusive   bool
+}
+
 // GetLabelTemplateFile loads the label template file by given name,
-// then parses and returns a list of name-color pairs and optionally description.
-func GetLabelTemplateFile(name string) ([][3]string, error) {
+// then parses and returns a list of labels with a name, a color, optionally a description
+// and whether the label is exclusive in its scope.
+func GetLabelTemplateFile(name string) ([]*LabelTemplate, error) {
 	data, err := GetRepoInitFile("label", name)
 	if err != nil {
 		return nil, ErrIssueLabelTemplateLoad{name, fmt.Errorf("GetRepoInitFile: %v", err)}
 	}
 
 	lines := strings.Split(string(data), "\n")
-	list := make([][3]string, 0, len(lines))
+	list := make([]*LabelTemplate, 0, len(lines))
 	for i := 0; i < len(lines); i++ {
 		line := strings.TrimSpace(lines[i])
 		if len(line) == 0 {
@@ -81,18 +91,46 @@ func GetLabelTemplateFile(name string) ([][3]string, error) {
 		}
 
 		var description string
+		var exclusive bool
 
 		if len(parts) > 1 {
 			description = strings.TrimSpace(parts[1])
+			// An exclusive label ends with an additional "; exclusive" field
+			if idx := strings.LastIndexByte(description, ';'); idx >= 0 && strings.TrimSpace(description[idx+1:]) == "exclusive" {
+				exclusive = true
+				description = strings.TrimSpace(description[:idx])
+			}
 		}
 
-		fields[1] = strings.TrimSpace(fields[1])
-		list = append(list, [3]string{fields[1], color, description})
+		list = append(list, &LabelTemplate{
+			Name:        strings.TrimSpace(fields[1]),
+			Color:       color,
+			Description: description,
+			Exclusive:   exclusive,
+		})
 	}
 
 	return list, nil
 }
 
+// Scope returns the scope of the label, which is the part of its name before the last "/",
+// e.g. "priority" for "priority/high". Labels without "/" have no scope.
+func (label *Label) Scope() string {
+	if idx := strings.LastIndexByte(label.Name, '/'); idx > 0 {
+		return label.Name[:idx]
+	}
+	return ""
+}
+
+// ExclusiveScope returns the scope of the label if it is exclusive, an issue can only have one
+// label of an exclusive scope.
+func (label *Label) ExclusiveScope() string {
+	if !label.Exclusive {
+		return ""
+	}
+	return label.Scope()
+}
+
 // CalOpenIssues sets the number of open issues of a label based on the already stored number of closed issues.
 func (label *Label) CalOpenIssues() {
 	label.NumOpenIssues = label.NumIssues - label.NumClosedIssues
@@ -204,7 +242,7 @@ func loadLabels(labelTemplate string) ([]string, error) {
 
 	labels := make([]string, len(list))
 	for i := 0; i < len(list); i++ {
-		labels[i] = list[i][0]
+		labels[i] = list[i].Name
 	}
 	return labels, nil
 }
@@ -224,9 +262,10 @@ func initializeLabels(e db.Engine, id int64, labelTemplate string, isOrg bool) e
 	labels := make([]*Label, len(list))
 	for i := 0; i < len(list); i++ {
 		labels[i] = &Label{
-			Name:        list[i][0],
-			Description: list[i][2],
-			Color:       list[i][1],
+			Name:        list[i].Name,
+			Description: list[i].Description,
+			Color:       list[i].Color,
+			Exclusive:   list[i].Exclusive,
 		}
 		if isOrg {
 			labels[i].OrgID = id
@@ -284,7 +323,9 @@ func UpdateLabel(l *Label) error {
 	if !LabelColorPattern.MatchString(l.Color) {
 		return fmt.Errorf("bad color code: %s", l.Color)
 	}
-	return updateLabelCols(db.GetEngine(db.DefaultContext), l, "name", "description", "color")
+	// the counters are updated along with the issues of the label
+	_, err := db.GetEngine(db.DefaultContext).ID(l.ID).Cols("name", "description", "color", "exclusive").Update(l)
+	return err
 }
 
 // DeleteLabel delete a label
@@ -420,14 +461,52 @@ func GetLabelIDsInRepoByNames(repoID int64, labelNames []string) ([]int64, error
 		Find(&labelIDs)
 }
 
-// BuildLabelNamesIssueIDsCondition returns a builder where get issue ids match label names
+// labelScopeWildcard is the suffix of a label name which stands for all labels of a scope, e.g. "priority/*"
+const labelScopeWildcard = "/*"
+
+// SplitLabelScopes splits label names into the plain names and the scopes of the names which end with "/*"
+func SplitLabelScopes(labelNames []string) (names, scopes []string) {
+	for _, name := range labelNames {
+		if scope := strings.TrimSuffix(name, labelScopeWildcard); scope != name {
+			if scope != "" {
+				scopes = append(scopes, scope)
+			}
+			continue
+		}
+		names = append(names, name)
+	}
+	return names, scopes
+}
+
+// labelScopeCond returns the condition matching the labels of a scope
+func labelScopeCond(scope string) builder.Cond {
+	return builder.Like{"label.name", scope + "/%"}
+}
+
+// buildLabelScopeIssueIDsCondition returns a builder where get issue ids which have a label of the scope
+func buildLabelScopeIssueIDsCondition(scope string) *builder.Builder {
+	return builder.Select("issue_label.issue_id").
+		From("issue_label").
+		InnerJoin("label", "label.id = issue_label.label_id").
+		Where(labelScopeCond(scope)).
+		GroupBy("issue_label.issue_id")
+}
+
+// BuildLabelNamesIssueIDsCondition returns a builder where get issue ids match label names,
+// names ending with "/*" match all labels of their scope
 func BuildLabelNamesIssueIDsCondition(labelNames []string) *builder.Builder {
+	names, scopes := SplitLabelScopes(labelNames)
+	cond := builder.NewCond()
+	if len(names) > 0 {
+		cond = cond.Or(builder.In("label.name", names))
+	}
+	for _, scope := range scopes {
+		cond = cond.Or(labelScopeCond(scope))
+	}
 	return builder.Select("issue_label.issue_id").
 		From("issue_label").
 		InnerJoin("label", "label.id = issue_label.label_id").
-		Where(
-			builder.In("label.name", labelNames),
-		).
+		Where(cond).
 		GroupBy("issue_label.issue_id")
 }
 
@@ -619,21 +698,22 @@ func GetLabelsByIssueID(issueID int64) ([]*Label, error) {
 	return getLabelsByIssueID(db.GetEngine(db.DefaultContext), issueID)
 }
 
-func updateLabelCols(e db.Engine, l *Label, cols ...string) error {
-	_, err := e.ID(l.ID).
+// updateLabelCounters recounts NumIssues and NumClosedIssues
+func updateLabelCounters(e db.Engine, id int64) error {
+	_, err := e.ID(id).
 		SetExpr("num_issues",
 			builder.Select("count(*)").From("issue_label").
-				Where(builder.Eq{"label_id": l.ID}),
+				Where(builder.Eq{"label_id": id}),
 		).
 		SetExpr("num_closed_issues",
 			builder.Select("count(*)").From("issue_label").
 				InnerJoin("issue", "issue_label.issue_id = issue.id").
 				Where(builder.Eq{
-					"issue_label.label_id": l.ID,
+					"issue_label.label_id": id,
 					"issue.is_closed":      true,
 				}),
 		).
-		Cols(cols...).Update(l)
+		Update(new(Label))
 	return err
 }
 
@@ -661,9 +741,35 @@ func HasIssueLabel(issueID, labelID int64) bool {
 	return hasIssueLabel(db.GetEngine(db.DefaultContext), issueID, labelID)
 }
 
+// removeDuplicateExclusiveIssueLabels removes the labels of the issue which are in the same exclusive scope as label,
+// the removals are recorded right before the addition of label so they are shown as one change.
+func removeDuplicateExclusiveIssueLabels(e db.Engine, issue *Issue, label *Label, doer *User) error {
+	scope := label.ExclusiveScope()
+	if scope == "" {
+		return nil
+	}
+
+	labels, err := getLabelsByIssueID(e, issue.ID)
+	if err != nil {
+		return err
+	}
+	for _, issueLabel := range labels {
+		if issueLabel.ID != label.ID && issueLabel.ExclusiveScope() == scope {
+			if err := deleteIssueLabel(e, issue, issueLabel, doer); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // newIssueLabel this function creates a new label it does not check if the label is valid for the issue
 // YOU MUST CHECK THIS BEFORE THIS FUNCTION
 func newIssueLabel(e db.Engine, issue *Issue, label *Label, doer *User) (err error) {
+	if err = removeDuplicateExclusiveIssueLabels(e, issue, label, doer); err != nil {
+		return err
+	}
+
 	if _, err = e.Insert(&IssueLabel{
 		IssueID: issue.ID,
 		LabelID: label.ID,
@@ -687,7 +793,7 @@ func newIssueLabel(e db.Engine, issue *Issue, label *Label, doer *User) (err err
 		return err
 	}
 
-	return updateLabelCols(e, label, "num_issues", "num_closed_issue")
+	return updateLabelIssueCounters(e, 1, closedIssueCount(issue.IsClosed), label.ID)
 }
 
 // NewIssueLabel creates a new issue-label relation.
@@ -788,7 +894,7 @@ func deleteIssueLabel(e db.Engine, issue *Issue, label *Label, doer *User) (err
 		return err
 	}
 
-	return updateLabelCols(e, label, "num_issues", "num_closed_issue")
+	return updateLabelIssueCounters(e, -1, -closedIssueCount(issue.IsClosed), label.ID)
 }
 
 // DeleteIssueLabel deletes issue-label relation.
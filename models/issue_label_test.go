@@ -345,6 +345,55 @@ func TestNewIssueLabels(t *testing.T) {
 	CheckConsistencyFor(t, &Issue{}, &Label{})
 }
 
+func TestLabel_Scope(t *testing.T) {
+	assert.Equal(t, "", (&Label{Name: "bug"}).Scope())
+	assert.Equal(t, "priority", (&Label{Name: "priority/high"}).Scope())
+	assert.Equal(t, "area/ui", (&Label{Name: "area/ui/forms"}).Scope())
+	assert.Equal(t, "", (&Label{Name: "priority/high"}).ExclusiveScope())
+	assert.Equal(t, "priority", (&Label{Name: "priority/high", Exclusive: true}).ExclusiveScope())
+}
+
+func TestSplitLabelScopes(t *testing.T) {
+	names, scopes := SplitLabelScopes([]string{"bug", "priority/*", "priority/high", "/*"})
+	assert.EqualValues(t, []string{"bug", "priority/high"}, names)
+	assert.EqualValues(t, []string{"priority"}, scopes)
+}
+
+func TestNewIssueLabelExclusive(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	issue := db.AssertExistsAndLoadBean(t, &Issue{ID: 1}).(*Issue)
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	low := &Label{RepoID: issue.RepoID, Name: "priority/low", Color: "#00ff00", Exclusive: true}
+	high := &Label{RepoID: issue.RepoID, Name: "priority/high", Color: "#ff0000", Exclusive: true}
+	other := &Label{RepoID: issue.RepoID, Name: "priority/other", Color: "#0000ff"}
+	assert.NoError(t, NewLabels(low, high, other))
+
+	assert.NoError(t, NewIssueLabel(issue, low, doer))
+	assert.NoError(t, NewIssueLabel(issue, other, doer))
+	assert.NoError(t, NewIssueLabel(issue, high, doer))
+
+	// only the exclusive labels of the scope replace each other
+	db.AssertNotExistsBean(t, &IssueLabel{IssueID: issue.ID, LabelID: low.ID})
+	db.AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: high.ID})
+	db.AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: other.ID})
+	db.AssertExistsAndLoadBean(t, &IssueLabel{IssueID: issue.ID, LabelID: 1})
+	db.AssertExistsAndLoadBean(t, &Comment{
+		Type:     CommentTypeLabel,
+		PosterID: doer.ID,
+		IssueID:  issue.ID,
+		LabelID:  low.ID,
+	})
+
+	issues, err := Issues(&IssuesOptions{RepoIDs: []int64{issue.RepoID}, LabelScopes: []string{"priority"}})
+	assert.NoError(t, err)
+	if assert.Len(t, issues, 1) {
+		assert.EqualValues(t, issue.ID, issues[0].ID)
+	}
+
+	CheckConsistencyFor(t, &Issue{}, &Label{})
+}
+
 func TestDeleteIssueLabel(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	testSuccess := func(labelID, issueID, doerID int64) {
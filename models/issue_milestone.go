@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/builder"
 	"xorm.io/xorm"
@@ -68,11 +69,18 @@ func (m *Milestone) AfterLoad() {
 	}
 
 	m.DeadlineString = m.DeadlineUnix.Format("2006-01-02")
+	m.IsOverdue = m.isOverdue()
+}
+
+// isOverdue checks if an open milestone is past its due date or a closed one was closed after it
+func (m *Milestone) isOverdue() bool {
+	if m.DeadlineUnix.Year() == 9999 {
+		return false
+	}
 	if m.IsClosed {
-		m.IsOverdue = m.ClosedDateUnix >= m.DeadlineUnix
-	} else {
-		m.IsOverdue = timeutil.TimeStampNow() >= m.DeadlineUnix
+		return m.ClosedDateUnix >= m.DeadlineUnix
 	}
+	return timeutil.TimeStampNow() >= m.DeadlineUnix
 }
 
 // State returns string representation of milestone status.
@@ -92,6 +100,7 @@ func NewMilestone(m *Milestone) (err error) {
 	}
 
 	m.Name = strings.TrimSpace(m.Name)
+	m.IsOverdue = m.isOverdue()
 
 	if _, err = sess.Insert(m); err != nil {
 		return err
@@ -159,6 +168,7 @@ func UpdateMilestone(m *Milestone, oldIsClosed bool) error {
 	if m.IsClosed && !oldIsClosed {
 		m.ClosedDateUnix = timeutil.TimeStampNow()
 	}
+	m.IsOverdue = m.isOverdue()
 
 	if err := updateMilestone(sess, m); err != nil {
 		return err
@@ -176,14 +186,12 @@ func UpdateMilestone(m *Milestone, oldIsClosed bool) error {
 
 func updateMilestone(e db.Engine, m *Milestone) error {
 	m.Name = strings.TrimSpace(m.Name)
-	_, err := e.ID(m.ID).AllCols().Update(m)
-	if err != nil {
-		return err
-	}
-	return updateMilestoneCounters(e, m.ID)
+	// the counters are updated along with the issues of the milestone
+	_, err := e.ID(m.ID).AllCols().Omit("num_issues", "num_closed_issues", "completeness").Update(m)
+	return err
 }
 
-// updateMilestoneCounters calculates NumIssues, NumClosesIssues and Completeness
+// updateMilestoneCounters recounts NumIssues, NumClosesIssues and Completeness
 func updateMilestoneCounters(e db.Engine, id int64) error {
 	_, err := e.ID(id).
 		SetExpr("num_issues", builder.Select("count(*)").From("issue").Where(
@@ -199,7 +207,11 @@ func updateMilestoneCounters(e db.Engine, id int64) error {
 	if err != nil {
 		return err
 	}
-	_, err = e.Exec("UPDATE `milestone` SET completeness=100*num_closed_issues/(CASE WHEN num_issues > 0 THEN num_issues ELSE 1 END) WHERE id=?",
+	return updateMilestoneCompleteness(e, id)
+}
+
+func updateMilestoneCompleteness(e db.Engine, id int64) error {
+	_, err := e.Exec("UPDATE `milestone` SET completeness=100*num_closed_issues/(CASE WHEN num_issues > 0 THEN num_issues ELSE 1 END) WHERE id=?",
 		id,
 	)
 	return err
@@ -264,18 +276,26 @@ func changeMilestoneStatus(e db.Engine, m *Milestone, isClosed bool) error {
 }
 
 func changeMilestoneAssign(e *xorm.Session, doer *User, issue *Issue, oldMilestoneID int64) error {
-	if err := updateIssueCols(e, issue, "milestone_id"); err != nil {
+	// the milestone is only changed by one of concurrent changes, the counters are updated by it alone
+	var cond builder.Cond = builder.Eq{"milestone_id": oldMilestoneID}
+	if oldMilestoneID == 0 {
+		cond = builder.Or(cond, builder.IsNull{"milestone_id"})
+	}
+	count, err := e.ID(issue.ID).Where(cond).Cols("milestone_id").Update(issue)
+	if err != nil {
 		return err
+	} else if count == 0 {
+		return nil
 	}
 
 	if oldMilestoneID > 0 {
-		if err := updateMilestoneCounters(e, oldMilestoneID); err != nil {
+		if err := updateMilestoneIssueCounters(e, oldMilestoneID, -1, -closedIssueCount(issue.IsClosed)); err != nil {
 			return err
 		}
 	}
 
 	if issue.MilestoneID > 0 {
-		if err := updateMilestoneCounters(e, issue.MilestoneID); err != nil {
+		if err := updateMilestoneIssueCounters(e, issue.MilestoneID, 1, closedIssueCount(issue.IsClosed)); err != nil {
 			return err
 		}
 	}
@@ -343,6 +363,9 @@ func DeleteMilestoneByRepoID(repoID, id int64) error {
 	if _, err = sess.ID(m.ID).Delete(new(Milestone)); err != nil {
 		return err
 	}
+	if _, err = sess.Delete(&MilestoneReminder{MilestoneID: m.ID}); err != nil {
+		return err
+	}
 
 	numMilestones, err := countRepoMilestones(sess, repo.ID)
 	if err != nil {
@@ -382,6 +405,7 @@ type GetMilestonesOption struct {
 	RepoID   int64
 	State    api.StateType
 	Name     string
+	Overdue  util.OptionalBool
 	SortType string
 }
 
@@ -405,6 +429,19 @@ func (opts GetMilestonesOption) toCond() builder.Cond {
 		cond = cond.And(builder.Like{"name", opts.Name})
 	}
 
+	if !opts.Overdue.IsNone() {
+		// same as Milestone.isOverdue, milestones without a due date are far in the future
+		overdue := builder.Or(
+			builder.And(builder.Eq{"is_closed": false}, builder.Lte{"deadline_unix": timeutil.TimeStampNow()}),
+			builder.And(builder.Eq{"is_closed": true}, builder.Expr("closed_date_unix >= deadline_unix")),
+		)
+		if opts.Overdue.IsTrue() {
+			cond = cond.And(overdue)
+		} else {
+			cond = cond.And(builder.Not{overdue})
+		}
+	}
+
 	return cond
 }
 
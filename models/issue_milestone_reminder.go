@@ -0,0 +1,107 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MilestoneReminderType is the trigger of a reminder about the due date of a milestone
+type MilestoneReminderType int
+
+const (
+	// MilestoneReminderDueSoon reminds a few days before the due date
+	MilestoneReminderDueSoon MilestoneReminderType = iota + 1
+	// MilestoneReminderDue reminds on the due date
+	MilestoneReminderDue
+)
+
+// MilestoneReminder records that a user has been reminded about the due date of a milestone.
+// The due date is part of the key so users are reminded again when it is moved.
+type MilestoneReminder struct {
+	ID           int64                 `xorm:"pk autoincr"`
+	RepoID       int64                 `xorm:"INDEX NOT NULL"`
+	MilestoneID  int64                 `xorm:"UNIQUE(s) NOT NULL"`
+	UserID       int64                 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Type         MilestoneReminderType `xorm:"UNIQUE(s) NOT NULL"`
+	DeadlineUnix timeutil.TimeStamp    `xorm:"UNIQUE(s) NOT NULL"`
+	CreatedUnix  timeutil.TimeStamp    `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(MilestoneReminder))
+}
+
+// FindOpenMilestonesDueBetween returns the open milestones with a due date after from and up to to
+func FindOpenMilestonesDueBetween(from, to timeutil.TimeStamp) (MilestoneList, error) {
+	milestones := make(MilestoneList, 0, 10)
+	return milestones, db.GetEngine(db.DefaultContext).
+		Where(builder.Eq{"is_closed": false}).
+		And(builder.Gt{"deadline_unix": from}).
+		And(builder.Lte{"deadline_unix": to}).
+		Asc("deadline_unix").
+		Find(&milestones)
+}
+
+// GetOpenIssuesByAssignee returns the open issues and pull requests of the milestone grouped by their assignees
+func (m *Milestone) GetOpenIssuesByAssignee() (map[int64]IssueList, error) {
+	assignees := make([]*IssueAssignees, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).
+		Join("INNER", "issue", "issue.id = issue_assignees.issue_id").
+		Where(builder.Eq{"issue.milestone_id": m.ID, "issue.is_closed": false}).
+		Asc("issue_assignees.issue_id").
+		Find(&assignees); err != nil {
+		return nil, err
+	}
+	if len(assignees) == 0 {
+		return nil, nil
+	}
+
+	issueIDs := make([]int64, 0, len(assignees))
+	for _, a := range assignees {
+		issueIDs = append(issueIDs, a.IssueID)
+	}
+	issues, err := GetIssuesByIDs(issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	issueMap := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		issueMap[issue.ID] = issue
+	}
+
+	byAssignee := make(map[int64]IssueList, len(assignees))
+	for _, a := range assignees {
+		if issue, ok := issueMap[a.IssueID]; ok {
+			byAssignee[a.AssigneeID] = append(byAssignee[a.AssigneeID], issue)
+		}
+	}
+	return byAssignee, nil
+}
+
+// IsMilestoneReminderSent checks if the user has been reminded about the current due date of the milestone
+func IsMilestoneReminderSent(m *Milestone, userID int64, tp MilestoneReminderType) (bool, error) {
+	return db.GetEngine(db.DefaultContext).Exist(&MilestoneReminder{
+		MilestoneID:  m.ID,
+		UserID:       userID,
+		Type:         tp,
+		DeadlineUnix: m.DeadlineUnix,
+	})
+}
+
+// SetMilestoneReminderSent records that the user has been reminded about the current due date of the milestone
+func SetMilestoneReminderSent(m *Milestone, userID int64, tp MilestoneReminderType) error {
+	_, err := db.GetEngine(db.DefaultContext).Insert(&MilestoneReminder{
+		RepoID:       m.RepoID,
+		MilestoneID:  m.ID,
+		UserID:       userID,
+		Type:         tp,
+		DeadlineUnix: m.DeadlineUnix,
+	})
+	return err
+}
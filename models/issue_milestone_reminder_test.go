@@ -0,0 +1,62 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMilestoneReminders(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	now := timeutil.TimeStampNow()
+	m := db.AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	m.DeadlineUnix = now.AddDuration(time.Hour)
+	assert.NoError(t, UpdateMilestone(m, m.IsClosed))
+
+	milestones, err := FindOpenMilestonesDueBetween(now, now.AddDuration(24*time.Hour))
+	assert.NoError(t, err)
+	if assert.Len(t, milestones, 1) {
+		assert.EqualValues(t, 1, milestones[0].ID)
+	}
+	milestones, err = FindOpenMilestonesDueBetween(now.AddDuration(24*time.Hour), now.AddDuration(72*time.Hour))
+	assert.NoError(t, err)
+	assert.Len(t, milestones, 0)
+
+	// issue 2 is the open issue of milestone1
+	_, err = db.GetEngine(db.DefaultContext).Insert(&IssueAssignees{AssigneeID: 2, IssueID: 2})
+	assert.NoError(t, err)
+	byAssignee, err := m.GetOpenIssuesByAssignee()
+	assert.NoError(t, err)
+	if assert.Len(t, byAssignee, 1) && assert.Len(t, byAssignee[2], 1) {
+		assert.EqualValues(t, 2, byAssignee[2][0].ID)
+	}
+
+	sent, err := IsMilestoneReminderSent(m, 2, MilestoneReminderDue)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+	assert.NoError(t, SetMilestoneReminderSent(m, 2, MilestoneReminderDue))
+	sent, err = IsMilestoneReminderSent(m, 2, MilestoneReminderDue)
+	assert.NoError(t, err)
+	assert.True(t, sent)
+	sent, err = IsMilestoneReminderSent(m, 2, MilestoneReminderDueSoon)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	// moving the due date reminds again
+	m.DeadlineUnix = now.AddDuration(2 * time.Hour)
+	sent, err = IsMilestoneReminderSent(m, 2, MilestoneReminderDue)
+	assert.NoError(t, err)
+	assert.False(t, sent)
+
+	assert.NoError(t, DeleteMilestoneByRepoID(1, 1))
+	db.AssertNotExistsBean(t, &MilestoneReminder{MilestoneID: 1})
+}
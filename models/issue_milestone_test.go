@@ -7,11 +7,13 @@ package models
 import (
 	"sort"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 	"xorm.io/builder"
@@ -157,6 +159,41 @@ func TestGetMilestones(t *testing.T) {
 	})
 }
 
+func TestGetMilestonesOverdue(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// milestone1 is due in the future, milestone2 has no due date, milestone3 was closed after its due date
+	future := db.AssertExistsAndLoadBean(t, &Milestone{ID: 1}).(*Milestone)
+	future.DeadlineUnix = timeutil.TimeStampNow().AddDuration(time.Hour)
+	assert.NoError(t, UpdateMilestone(future, future.IsClosed))
+	assert.False(t, future.IsOverdue)
+	noDeadline := db.AssertExistsAndLoadBean(t, &Milestone{ID: 2}).(*Milestone)
+	noDeadline.DeadlineUnix = timeutil.TimeStamp(time.Date(9999, 12, 31, 0, 0, 0, 0, time.Local).Unix())
+	assert.NoError(t, UpdateMilestone(noDeadline, noDeadline.IsClosed))
+	assert.False(t, noDeadline.IsOverdue)
+	_, err := db.GetEngine(db.DefaultContext).ID(3).Cols("deadline_unix", "closed_date_unix").
+		Update(&Milestone{DeadlineUnix: 100, ClosedDateUnix: 200})
+	assert.NoError(t, err)
+
+	test := func(overdue util.OptionalBool, expected ...int64) {
+		milestones, _, err := GetMilestones(GetMilestonesOption{
+			RepoID:   1,
+			State:    api.StateAll,
+			Overdue:  overdue,
+			SortType: "id",
+		})
+		assert.NoError(t, err)
+		ids := make([]int64, 0, len(milestones))
+		for _, m := range milestones {
+			ids = append(ids, m.ID)
+			assert.Equal(t, overdue.IsTrue(), m.IsOverdue)
+		}
+		assert.Equal(t, expected, ids)
+	}
+	test(util.OptionalBoolTrue, 3)
+	test(util.OptionalBoolFalse, 1, 2)
+}
+
 func TestUpdateMilestone(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
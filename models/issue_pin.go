@@ -0,0 +1,134 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+)
+
+// MaxPinnedIssues is the number of issues, and separately of pull requests, which can be pinned per repository
+const MaxPinnedIssues = 3
+
+// IsPinned returns true if the issue is pinned to the top of the issue list of its repository
+func (issue *Issue) IsPinned() bool {
+	return issue.PinOrder > 0
+}
+
+func countPinnedIssues(e db.Engine, repoID int64, isPull bool) (int64, error) {
+	return e.Where("repo_id = ? AND is_pull = ? AND pin_order > 0", repoID, isPull).Count(new(Issue))
+}
+
+// CountPinnedIssues returns the number of pinned issues or pull requests of a repository
+func CountPinnedIssues(repoID int64, isPull bool) (int64, error) {
+	return countPinnedIssues(db.GetEngine(db.DefaultContext), repoID, isPull)
+}
+
+// GetPinnedIssues returns the pinned issues or pull requests of a repository in their pinned order
+func GetPinnedIssues(repoID int64, isPull bool) (IssueList, error) {
+	issues := make(IssueList, 0, MaxPinnedIssues)
+	return issues, db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND is_pull = ? AND pin_order > 0", repoID, isPull).
+		OrderBy("pin_order ASC").
+		Find(&issues)
+}
+
+func updateIssuePinOrder(e db.Engine, issue *Issue) error {
+	_, err := e.ID(issue.ID).Cols("pin_order").NoAutoTime().Update(issue)
+	return err
+}
+
+// Pin pins the issue after the already pinned issues of its repository.
+// Issues and pull requests are pinned separately, up to MaxPinnedIssues each.
+func (issue *Issue) Pin() error {
+	if issue.IsPinned() {
+		return nil
+	}
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := countPinnedIssues(sess, issue.RepoID, issue.IsPull)
+	if err != nil {
+		return err
+	}
+	if count >= MaxPinnedIssues {
+		return ErrIssueMaxPinReached{RepoID: issue.RepoID, IsPull: issue.IsPull}
+	}
+
+	issue.PinOrder = int(count) + 1
+	if err := updateIssuePinOrder(sess, issue); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// Unpin unpins the issue and moves the issues pinned after it up
+func (issue *Issue) Unpin() error {
+	if !issue.IsPinned() {
+		return nil
+	}
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("UPDATE `issue` SET pin_order = pin_order - 1 WHERE repo_id = ? AND is_pull = ? AND pin_order > ?",
+		issue.RepoID, issue.IsPull, issue.PinOrder); err != nil {
+		return err
+	}
+	issue.PinOrder = 0
+	if err := updateIssuePinOrder(sess, issue); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// MovePin moves a pinned issue to the given position, starting at 1, among the pinned issues of its repository.
+// A position after the last pinned issue moves it to the end.
+func (issue *Issue) MovePin(position int) error {
+	if !issue.IsPinned() {
+		return ErrIssueNotPinned{ID: issue.ID, RepoID: issue.RepoID, Index: issue.Index}
+	}
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := countPinnedIssues(sess, issue.RepoID, issue.IsPull)
+	if err != nil {
+		return err
+	}
+	if position < 1 {
+		position = 1
+	} else if position > int(count) {
+		position = int(count)
+	}
+	if position == issue.PinOrder {
+		return nil
+	}
+
+	if position < issue.PinOrder {
+		_, err = sess.Exec("UPDATE `issue` SET pin_order = pin_order + 1 WHERE repo_id = ? AND is_pull = ? AND pin_order >= ? AND pin_order < ?",
+			issue.RepoID, issue.IsPull, position, issue.PinOrder)
+	} else {
+		_, err = sess.Exec("UPDATE `issue` SET pin_order = pin_order - 1 WHERE repo_id = ? AND is_pull = ? AND pin_order > ? AND pin_order <= ?",
+			issue.RepoID, issue.IsPull, issue.PinOrder, position)
+	}
+	if err != nil {
+		return err
+	}
+	issue.PinOrder = position
+	if err := updateIssuePinOrder(sess, issue); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
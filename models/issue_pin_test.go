@@ -0,0 +1,84 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssuePin(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	pinnedIndexes := func(isPull bool) []int64 {
+		issues, err := GetPinnedIssues(1, isPull)
+		assert.NoError(t, err)
+		indexes := make([]int64, 0, len(issues))
+		for _, issue := range issues {
+			indexes = append(indexes, issue.Index)
+		}
+		return indexes
+	}
+	getIssue := func(index int64) *Issue {
+		issue, err := GetIssueByIndex(1, index)
+		assert.NoError(t, err)
+		return issue
+	}
+
+	issues := []*Issue{getIssue(1), getIssue(4)}
+	for i := int64(0); i < 2; i++ {
+		issue := &Issue{RepoID: 1, Index: 100 + i, PosterID: 1, Title: "pin"}
+		assert.NoError(t, db.Insert(db.DefaultContext, issue))
+		issues = append(issues, issue)
+	}
+	for _, issue := range issues[:MaxPinnedIssues] {
+		assert.NoError(t, issue.Pin())
+	}
+	assert.Equal(t, []int64{1, 4, 100}, pinnedIndexes(false))
+	assert.True(t, IsErrIssueMaxPinReached(issues[3].Pin()))
+
+	// pull requests are pinned separately
+	assert.NoError(t, getIssue(2).Pin())
+	assert.Equal(t, []int64{2}, pinnedIndexes(true))
+
+	assert.NoError(t, getIssue(100).MovePin(1))
+	assert.Equal(t, []int64{100, 1, 4}, pinnedIndexes(false))
+	assert.NoError(t, getIssue(100).MovePin(MaxPinnedIssues))
+	assert.Equal(t, []int64{1, 4, 100}, pinnedIndexes(false))
+
+	// closing a pinned issue keeps it pinned
+	_, err := getIssue(1).ChangeStatus(&User{ID: 1}, true)
+	assert.NoError(t, err)
+	assert.True(t, getIssue(1).IsPinned())
+
+	assert.NoError(t, getIssue(1).Unpin())
+	assert.Equal(t, []int64{4, 100}, pinnedIndexes(false))
+	assert.EqualValues(t, 1, getIssue(4).PinOrder)
+	assert.True(t, IsErrIssueNotPinned(getIssue(1).MovePin(1)))
+	assert.NoError(t, issues[3].Pin())
+	assert.Equal(t, []int64{4, 100, 101}, pinnedIndexes(false))
+}
+
+func TestIssuesPinnedFirst(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	issue, err := GetIssueByIndex(1, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, issue.Pin())
+
+	issues, err := Issues(&IssuesOptions{RepoIDs: []int64{1}, SortType: "newest", PinnedFirst: true})
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, issues) {
+		assert.EqualValues(t, issue.ID, issues[0].ID)
+	}
+	issues, err = Issues(&IssuesOptions{RepoIDs: []int64{1}, SortType: "newest"})
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, issues) {
+		assert.NotEqual(t, issue.ID, issues[0].ID)
+	}
+}
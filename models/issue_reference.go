@@ -0,0 +1,141 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// IssueReference is an issue, pull request or commit referencing an issue or referenced by it.
+// All the active cross reference comments between the two are merged into one reference.
+type IssueReference struct {
+	Repo *Repository
+	// Issue is nil for commits
+	Issue     *Issue
+	CommitSHA string
+	// Closes is true if any of the merged references closes the issue
+	Closes      bool
+	CreatedUnix timeutil.TimeStamp
+}
+
+// FindIssueReferences returns the issues, pull requests and commits referencing the issue, the oldest first
+func FindIssueReferences(issue *Issue) ([]*IssueReference, error) {
+	comments := make(CommentList, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).
+		Where(builder.Eq{"issue_id": issue.ID}).
+		And(builder.In("type", CommentTypeIssueRef, CommentTypeCommentRef, CommentTypePullRef, CommentTypeCommitRef)).
+		And(builder.Neq{"ref_action": references.XRefActionNeutered}).
+		Asc("id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+	return issueReferencesFromComments(comments, func(c *Comment) int64 { return c.RefIssueID })
+}
+
+// FindIssuesReferencedBy returns the issues and pull requests referenced by the issue or its comments, the oldest first
+func FindIssuesReferencedBy(issue *Issue) ([]*IssueReference, error) {
+	comments := make(CommentList, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).
+		Where(builder.Eq{"ref_repo_id": issue.RepoID, "ref_issue_id": issue.ID}).
+		And(builder.In("type", CommentTypeIssueRef, CommentTypeCommentRef, CommentTypePullRef)).
+		And(builder.Neq{"ref_action": references.XRefActionNeutered}).
+		Asc("id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+	return issueReferencesFromComments(comments, func(c *Comment) int64 { return c.IssueID })
+}
+
+// issueReferencesFromComments merges the reference comments into one reference per issue or commit,
+// refIssueID returns the ID of the issue on the other side of the reference
+func issueReferencesFromComments(comments CommentList, refIssueID func(*Comment) int64) ([]*IssueReference, error) {
+	issueIDs := make([]int64, 0, len(comments))
+	repoIDs := make([]int64, 0, len(comments))
+	for _, c := range comments {
+		if c.Type == CommentTypeCommitRef {
+			repoIDs = append(repoIDs, c.RefRepoID)
+		} else {
+			issueIDs = append(issueIDs, refIssueID(c))
+		}
+	}
+
+	issues, err := GetIssuesByIDs(issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := IssueList(issues).LoadRepositories(); err != nil {
+		return nil, err
+	}
+	issueMap := make(map[int64]*Issue, len(issues))
+	for _, issue := range issues {
+		issueMap[issue.ID] = issue
+	}
+	repoMap, err := GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*IssueReference, 0, len(comments))
+	refMap := make(map[string]*IssueReference, len(comments))
+	for _, c := range comments {
+		ref := &IssueReference{
+			Closes:      c.RefAction == references.XRefActionCloses,
+			CreatedUnix: c.CreatedUnix,
+		}
+		var key string
+		if c.Type == CommentTypeCommitRef {
+			if c.RefRepoID == 0 {
+				ref.Repo = commitRefRepo(c)
+			} else {
+				ref.Repo = repoMap[c.RefRepoID]
+			}
+			if ref.Repo == nil {
+				continue
+			}
+			ref.CommitSHA = c.CommitSHA
+			key = fmt.Sprintf("commit:%d:%s", ref.Repo.ID, c.CommitSHA)
+		} else {
+			if ref.Issue = issueMap[refIssueID(c)]; ref.Issue == nil {
+				continue
+			}
+			ref.Repo = ref.Issue.Repo
+			key = fmt.Sprintf("issue:%d", ref.Issue.ID)
+		}
+
+		if merged, ok := refMap[key]; ok {
+			merged.Closes = merged.Closes || ref.Closes
+			continue
+		}
+		refMap[key] = ref
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
+// commitRefRepo returns the repository of a commit reference created before the repository was recorded
+// in the comment, taken from the link to the commit in its content
+func commitRefRepo(c *Comment) *Repository {
+	m := regexp.MustCompile(`^<a href="` + regexp.QuoteMeta(setting.AppSubURL) + `/([^/"]+)/([^/"]+)/commit/`).FindStringSubmatch(c.Content)
+	if m == nil {
+		return nil
+	}
+	repo, err := GetRepositoryByOwnerAndName(m[1], m[2])
+	if err != nil {
+		if !IsErrRepoNotExist(err) {
+			log.Error("GetRepositoryByOwnerAndName(%s, %s): %v", m[1], m[2], err)
+		}
+		return nil
+	}
+	return repo
+}
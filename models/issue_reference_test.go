@@ -0,0 +1,85 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/references"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindIssueReferences(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+
+	itarget := testCreateIssue(t, 1, 2, "title1", "content1", false)
+
+	// PR closing the issue and a comment on it reopening it are merged
+	pr := testCreateIssue(t, 1, 2, "title2", fmt.Sprintf("closes #%d", itarget.Index), true)
+	testCreateComment(t, 1, 2, pr.ID, fmt.Sprintf("reopens #%d", itarget.Index))
+
+	i := testCreateIssue(t, 1, 2, "title3", fmt.Sprintf("mentions #%d", itarget.Index), false)
+
+	// Neutered references are ignored
+	title := fmt.Sprintf("title4, mentions #%d", itarget.Index)
+	neutered := testCreateIssue(t, 1, 2, title, "content4", false)
+	neutered.Title = "title4"
+	assert.NoError(t, neutered.ChangeTitle(doer, title))
+
+	sha := "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+	content := fmt.Sprintf(`<a href="%s/commit/%s">closes #%d</a>`, repo.Link(), sha, itarget.Index)
+	assert.NoError(t, CreateRefComment(doer, repo, repo, itarget, content, sha, references.XRefActionCloses))
+
+	// Commit references created before the repository was recorded
+	legacySHA := "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6"
+	_, err := CreateComment(&CreateCommentOptions{
+		Type:      CommentTypeCommitRef,
+		Doer:      doer,
+		Repo:      repo,
+		Issue:     itarget,
+		CommitSHA: legacySHA,
+		Content:   fmt.Sprintf(`<a href="%s/commit/%s">mentions #%d</a>`, repo.Link(), legacySHA, itarget.Index),
+	})
+	assert.NoError(t, err)
+
+	refs, err := FindIssueReferences(itarget)
+	assert.NoError(t, err)
+	if assert.Len(t, refs, 4) {
+		assert.Equal(t, pr.ID, refs[0].Issue.ID)
+		assert.True(t, refs[0].Closes)
+		assert.Equal(t, i.ID, refs[1].Issue.ID)
+		assert.False(t, refs[1].Closes)
+		for _, ref := range refs[:2] {
+			assert.Equal(t, repo.ID, ref.Repo.ID)
+			assert.Empty(t, ref.CommitSHA)
+		}
+
+		assert.Nil(t, refs[2].Issue)
+		assert.Equal(t, sha, refs[2].CommitSHA)
+		assert.True(t, refs[2].Closes)
+		assert.Nil(t, refs[3].Issue)
+		assert.Equal(t, legacySHA, refs[3].CommitSHA)
+		assert.False(t, refs[3].Closes)
+		for _, ref := range refs[2:] {
+			assert.Equal(t, repo.ID, ref.Repo.ID)
+		}
+	}
+
+	refs, err = FindIssuesReferencedBy(pr)
+	assert.NoError(t, err)
+	if assert.Len(t, refs, 1) {
+		assert.Equal(t, itarget.ID, refs[0].Issue.ID)
+		assert.True(t, refs[0].Closes)
+	}
+
+	refs, err = FindIssuesReferencedBy(neutered)
+	assert.NoError(t, err)
+	assert.Empty(t, refs)
+}
@@ -5,6 +5,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"sync"
@@ -12,7 +13,12 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
+	"xorm.io/xorm"
+	"xorm.io/xorm/contexts"
 )
 
 func TestIssue_ReplaceLabels(t *testing.T) {
@@ -286,6 +292,91 @@ func TestGetUserIssueStats(t *testing.T) {
 	}
 }
 
+func TestGetRepoIssueStats(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	numOpen, numClosed := GetRepoIssueStats(1, 1, FilterModeAll, false)
+	assert.EqualValues(t, 1, numOpen)
+	assert.EqualValues(t, 1, numClosed)
+
+	numOpen, numClosed = GetRepoIssueStats(1, 1, FilterModeAll, true)
+	assert.EqualValues(t, 3, numOpen)
+	assert.EqualValues(t, 0, numClosed)
+
+	numOpen, numClosed = GetRepoIssueStats(1, 1, FilterModeAssign, false)
+	assert.EqualValues(t, 1, numOpen)
+	assert.EqualValues(t, 0, numClosed)
+
+	numOpen, numClosed = GetRepoIssueStats(1, 2, FilterModeCreate, false)
+	assert.EqualValues(t, 0, numOpen)
+	assert.EqualValues(t, 1, numClosed)
+}
+
+// issueQueryCounter counts the queries run by the engine while it is enabled
+type issueQueryCounter struct {
+	enabled bool
+	count   int
+}
+
+func (c *issueQueryCounter) BeforeProcess(hook *contexts.ContextHook) (context.Context, error) {
+	if c.enabled {
+		c.count++
+	}
+	return hook.Ctx, nil
+}
+
+func (c *issueQueryCounter) AfterProcess(*contexts.ContextHook) error {
+	return nil
+}
+
+var (
+	issueQueries         = &issueQueryCounter{}
+	addIssueQueryCounter sync.Once
+)
+
+// countIssueQueries returns the number of queries run by f
+func countIssueQueries(f func()) int {
+	addIssueQueryCounter.Do(func() {
+		db.GetEngine(db.DefaultContext).(*xorm.Engine).AddHook(issueQueries)
+	})
+	issueQueries.count = 0
+	issueQueries.enabled = true
+	f()
+	issueQueries.enabled = false
+	return issueQueries.count
+}
+
+func TestIssueStatsQueryCount(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.Equal(t, 1, countIssueQueries(func() {
+		GetRepoIssueStats(1, 1, FilterModeAssign, false)
+	}))
+	assert.Equal(t, 1, countIssueQueries(func() {
+		_, err := GetIssueStats(&IssueStatsOptions{RepoID: 1, IsPull: util.OptionalBoolFalse})
+		assert.NoError(t, err)
+	}))
+	// one query for the review requested counts and one for all the others
+	assert.Equal(t, 2, countIssueQueries(func() {
+		_, err := GetUserIssueStats(UserIssueStatsOptions{UserID: 2, UserRepoIDs: []int64{1, 2}, FilterMode: FilterModeAll})
+		assert.NoError(t, err)
+	}))
+}
+
+func BenchmarkGetUserIssueStats(b *testing.B) {
+	assert.NoError(b, db.PrepareTestDatabase())
+	opts := UserIssueStatsOptions{UserID: 2, UserRepoIDs: []int64{1, 2}, FilterMode: FilterModeAll}
+	b.ResetTimer()
+	queries := countIssueQueries(func() {
+		for i := 0; i < b.N; i++ {
+			if _, err := GetUserIssueStats(opts); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.ReportMetric(float64(queries)/float64(b.N), "queries/op")
+}
+
 func TestIssue_loadTotalTimes(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	ms, err := GetIssueByID(2)
@@ -421,6 +512,35 @@ func TestIssue_ResolveMentions(t *testing.T) {
 	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{18})
 }
 
+func TestDefaultIssueSortType(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defer func(sortType string) {
+		setting.Repository.Issue.DefaultSortType = sortType
+	}(setting.Repository.Issue.DefaultSortType)
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	setting.Repository.Issue.DefaultSortType = "invalid"
+	assert.Equal(t, "latest", DefaultIssueSortType(repo, nil))
+
+	setting.Repository.Issue.DefaultSortType = "oldest"
+	assert.Equal(t, "oldest", DefaultIssueSortType(repo, nil))
+
+	unit, err := repo.GetUnit(UnitTypeIssues)
+	assert.NoError(t, err)
+	unit.IssuesConfig().DefaultSortType = "mostcomment"
+	assert.Equal(t, "mostcomment", DefaultIssueSortType(repo, nil))
+	assert.Equal(t, "mostcomment", DefaultIssueSortType(repo, user))
+
+	user.IssueSortType = "unknown"
+	assert.Equal(t, "mostcomment", DefaultIssueSortType(repo, user))
+
+	user.IssueSortType = "priority"
+	assert.Equal(t, "priority", DefaultIssueSortType(repo, user))
+}
+
 func TestResourceIndex(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -5,8 +5,13 @@
 package models
 
 import (
+	"context"
+
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // IssueWatch is connection request for receiving issue notification.
@@ -131,3 +136,137 @@ func removeIssueWatchersByRepoID(e db.Engine, userID, repoID int64) error {
 		Delete(new(IssueWatch))
 	return err
 }
+
+// IssueSubscriptionKind selects issue subscriptions by how the user subscribed
+type IssueSubscriptionKind int
+
+const (
+	// IssueSubscriptionAll selects explicit and implicit subscriptions
+	IssueSubscriptionAll IssueSubscriptionKind = iota
+	// IssueSubscriptionExplicit selects the issues the user subscribed to
+	IssueSubscriptionExplicit
+	// IssueSubscriptionImplicit selects the issues the user is subscribed to by posting or commenting
+	IssueSubscriptionImplicit
+)
+
+// IssueSubscription is an issue a user is subscribed to
+type IssueSubscription struct {
+	Issue *Issue
+	// Explicit is false if the user is subscribed by posting or commenting
+	Explicit bool
+}
+
+// FindIssueSubscriptionsOptions represents the options to find the issue subscriptions of a user
+type FindIssueSubscriptionsOptions struct {
+	db.ListOptions
+	UserID   int64
+	RepoID   int64
+	IsClosed util.OptionalBool
+	Kind     IssueSubscriptionKind
+}
+
+func explicitIssueSubscriptionCond(userID int64) builder.Cond {
+	return builder.In("issue.id", builder.Select("issue_id").From("issue_watch").
+		Where(builder.Eq{"user_id": userID, "is_watching": true}))
+}
+
+// implicitIssueSubscriptionCond matches the issues the user participates in, unless the user unsubscribed explicitly
+func implicitIssueSubscriptionCond(userID int64) builder.Cond {
+	return builder.NotIn("issue.id", builder.Select("issue_id").From("issue_watch").
+		Where(builder.Eq{"user_id": userID})).
+		And(builder.Eq{"issue.poster_id": userID}.
+			Or(builder.In("issue.id", builder.Select("issue_id").From("comment").
+				Where(builder.Eq{"poster_id": userID}.
+					And(builder.In("type", CommentTypeComment, CommentTypeCode, CommentTypeReview))))))
+}
+
+func (opts *FindIssueSubscriptionsOptions) toCond() builder.Cond {
+	var cond builder.Cond
+	switch opts.Kind {
+	case IssueSubscriptionExplicit:
+		cond = explicitIssueSubscriptionCond(opts.UserID)
+	case IssueSubscriptionImplicit:
+		cond = implicitIssueSubscriptionCond(opts.UserID)
+	default:
+		cond = builder.Or(explicitIssueSubscriptionCond(opts.UserID), implicitIssueSubscriptionCond(opts.UserID))
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"issue.repo_id": opts.RepoID})
+	}
+	if !opts.IsClosed.IsNone() {
+		cond = cond.And(builder.Eq{"issue.is_closed": opts.IsClosed.IsTrue()})
+	}
+	return cond
+}
+
+// FindIssueSubscriptions returns the issues a user is subscribed to in the repositories the user can access,
+// most recently updated first, and their total count. Subscriptions by watching a repository are not included.
+func FindIssueSubscriptions(user *User, opts *FindIssueSubscriptionsOptions) ([]*IssueSubscription, int64, error) {
+	e := db.GetEngine(db.DefaultContext)
+	cond := opts.toCond().And(builder.In("issue.repo_id",
+		builder.Select("id").From("repository").Where(accessibleRepositoryCondition(user))))
+
+	count, err := e.Where(cond).Count(new(Issue))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	sess := e.Where(cond).Desc("issue.updated_unix").Asc("issue.id")
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+	issues := make(IssueList, 0, opts.PageSize)
+	if err := sess.Find(&issues); err != nil {
+		return nil, 0, err
+	}
+	if len(issues) == 0 {
+		return []*IssueSubscription{}, count, nil
+	}
+
+	explicitIDs := make([]int64, 0, len(issues))
+	if err := e.Table("issue_watch").Cols("issue_id").
+		Where(builder.Eq{"user_id": opts.UserID, "is_watching": true}).
+		And(builder.In("issue_id", issues.getIssueIDs())).
+		Find(&explicitIDs); err != nil {
+		return nil, 0, err
+	}
+
+	subscriptions := make([]*IssueSubscription, 0, len(issues))
+	for _, issue := range issues {
+		subscriptions = append(subscriptions, &IssueSubscription{
+			Issue:    issue,
+			Explicit: util.IsInt64InSlice(issue.ID, explicitIDs),
+		})
+	}
+	return subscriptions, count, nil
+}
+
+// UnsubscribeIssues unsubscribes a user from the issues of a repository selected by the kind of the subscription.
+// Explicit subscriptions are turned into explicit unsubscriptions, so participating doesn't subscribe the user again.
+func UnsubscribeIssues(userID, repoID int64, kind IssueSubscriptionKind) error {
+	return db.WithTx(func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+		if kind != IssueSubscriptionImplicit {
+			if _, err := e.Where(builder.Eq{"user_id": userID, "is_watching": true}).
+				And(builder.In("issue_id", builder.Select("id").From("issue").Where(builder.Eq{"repo_id": repoID}))).
+				Cols("is_watching", "updated_unix").
+				Update(&IssueWatch{IsWatching: false}); err != nil {
+				return err
+			}
+		}
+		if kind != IssueSubscriptionExplicit {
+			now := timeutil.TimeStampNow()
+			sql, args, err := builder.Select("id").From("issue").
+				Where(implicitIssueSubscriptionCond(userID).And(builder.Eq{"issue.repo_id": repoID})).ToSQL()
+			if err != nil {
+				return err
+			}
+			insertArgs := append([]interface{}{userID, false, now, now}, args...)
+			if _, err := e.Exec(append([]interface{}{"INSERT INTO issue_watch (user_id, issue_id, is_watching, created_unix, updated_unix) " +
+				"SELECT ?, id, ?, ?, ? FROM (" + sql + ") implicit"}, insertArgs...)...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -63,3 +63,58 @@ func TestGetIssueWatchers(t *testing.T) {
 	// Issue has one watcher
 	assert.Len(t, iws, 1)
 }
+
+func TestFindIssueSubscriptions(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	subscriptions, count, err := FindIssueSubscriptions(user, &FindIssueSubscriptionsOptions{UserID: 2, RepoID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, subscriptions, 2) {
+		explicit := map[int64]bool{}
+		for _, subscription := range subscriptions {
+			explicit[subscription.Issue.ID] = subscription.Explicit
+		}
+		assert.Equal(t, map[int64]bool{4: false, 7: true}, explicit)
+	}
+
+	subscriptions, count, err = FindIssueSubscriptions(user, &FindIssueSubscriptionsOptions{UserID: 2, RepoID: 2, Kind: IssueSubscriptionImplicit})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, subscriptions, 1) {
+		assert.EqualValues(t, 4, subscriptions[0].Issue.ID)
+	}
+
+	// issue 2 is explicitly not watched
+	_, count, err = FindIssueSubscriptions(user, &FindIssueSubscriptionsOptions{UserID: 2, RepoID: 1, Kind: IssueSubscriptionExplicit})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestUnsubscribeIssues(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	assert.NoError(t, UnsubscribeIssues(2, 2, IssueSubscriptionImplicit))
+	subscriptions, _, err := FindIssueSubscriptions(user, &FindIssueSubscriptionsOptions{UserID: 2, RepoID: 2})
+	assert.NoError(t, err)
+	if assert.Len(t, subscriptions, 1) {
+		assert.EqualValues(t, 7, subscriptions[0].Issue.ID)
+		assert.True(t, subscriptions[0].Explicit)
+	}
+	iw := db.AssertExistsAndLoadBean(t, &IssueWatch{UserID: 2, IssueID: 4}).(*IssueWatch)
+	assert.False(t, iw.IsWatching)
+
+	assert.NoError(t, UnsubscribeIssues(2, 2, IssueSubscriptionAll))
+	_, count, err := FindIssueSubscriptions(user, &FindIssueSubscriptionsOptions{UserID: 2, RepoID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	iw = db.AssertExistsAndLoadBean(t, &IssueWatch{UserID: 2, IssueID: 7}).(*IssueWatch)
+	assert.False(t, iw.IsWatching)
+
+	// subscriptions in other repositories are kept
+	_, count, err = FindIssueSubscriptions(user, &FindIssueSubscriptionsOptions{UserID: 2, RepoID: 1})
+	assert.NoError(t, err)
+	assert.NotZero(t, count)
+}
@@ -142,7 +142,12 @@ func (issue *Issue) getCrossReferences(e db.Engine, ctx *crossReferencesContext,
 		err       error
 	)
 
-	allrefs := append(references.FindAllIssueReferences(plaincontent), references.FindAllIssueReferencesMarkdown(mdcontent)...)
+	if err := ctx.OrigIssue.loadRepo(e); err != nil {
+		return nil, err
+	}
+	closeKeywords, reopenKeywords := ctx.OrigIssue.Repo.issueKeywords(e)
+	allrefs := append(references.FindAllIssueReferencesWithKeywords(plaincontent, closeKeywords, reopenKeywords),
+		references.FindAllIssueReferencesMarkdownWithKeywords(mdcontent, closeKeywords, reopenKeywords)...)
 
 	for _, ref := range allrefs {
 		if ref.Owner == "" && ref.Name == "" {
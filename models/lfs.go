@@ -85,6 +85,11 @@ func (repo *Repository) GetLFSMetaObjectByOid(oid string) (*LFSMetaObject, error
 	return m, nil
 }
 
+// ExistLFSMetaObjectByOid returns true if any repository references the LFS object with the given OID
+func ExistLFSMetaObjectByOid(oid string) (bool, error) {
+	return db.GetEngine(db.DefaultContext).Where("oid=?", oid).Exist(new(LFSMetaObject))
+}
+
 // RemoveLFSMetaObjectByOid removes a LFSMetaObject entry from database by its OID.
 // It may return ErrLFSObjectNotExist or a database error.
 func (repo *Repository) RemoveLFSMetaObjectByOid(oid string) (int64, error) {
@@ -211,10 +211,15 @@ func (source *Source) SkipVerify() bool {
 	return ok && skipVerifiable.IsSkipVerify()
 }
 
+// IsSourceNameUsed returns true if a LoginSource with the given name exists.
+func IsSourceNameUsed(name string) (bool, error) {
+	return db.GetEngine(db.DefaultContext).Where("name=?", name).Exist(new(Source))
+}
+
 // CreateSource inserts a LoginSource in the DB if not already
 // existing with the given name.
 func CreateSource(source *Source) error {
-	has, err := db.GetEngine(db.DefaultContext).Where("name=?", source.Name).Exist(new(Source))
+	has, err := IsSourceNameUsed(source.Name)
 	if err != nil {
 		return err
 	} else if has {
@@ -336,6 +341,15 @@ func UpdateSource(source *Source) error {
 		return err
 	}
 
+	// a deactivated or renamed source must not stay registered under its original name
+	if originalLoginSource != nil && originalLoginSource.IsActive && (!source.IsActive || originalLoginSource.Name != source.Name) {
+		if registerableSource, ok := originalLoginSource.Cfg.(RegisterableSource); ok {
+			if err := registerableSource.UnregisterSource(); err != nil {
+				log.Error("UpdateSource: Error while unregistering %s: %v", originalLoginSource.Name, err)
+			}
+		}
+	}
+
 	if !source.IsActive {
 		return nil
 	}
@@ -398,6 +412,10 @@ func (err ErrSourceAlreadyExist) Error() string {
 // ErrSourceInUse represents a "SourceInUse" kind of error.
 type ErrSourceInUse struct {
 	ID int64
+	// Users is the number of users authenticated by the source
+	Users int64
+	// ExternalLogins is the number of external accounts linked through the source
+	ExternalLogins int64
 }
 
 // IsErrSourceInUse checks if an error is a ErrSourceInUse.
@@ -407,5 +425,5 @@ func IsErrSourceInUse(err error) bool {
 }
 
 func (err ErrSourceInUse) Error() string {
-	return fmt.Sprintf("login source is still used by some users [id: %d]", err.ID)
+	return fmt.Sprintf("login source is still used by %d users and %d linked accounts [id: %d]", err.Users, err.ExternalLogins, err.ID)
 }
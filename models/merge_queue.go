@@ -0,0 +1,160 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MergeQueueRefPrefix is the prefix of the refs the merge queue pushes its test merges to.
+// They are kept out of refs/heads, so that they are neither listed nor can be pushed to as branches.
+const MergeQueueRefPrefix = "refs/merge-queue/"
+
+// MergeQueueEntry represents a pull request waiting in the merge queue of its base branch.
+// Entries are processed in the order of their IDs.
+type MergeQueueEntry struct {
+	ID         int64        `xorm:"pk autoincr"`
+	RepoID     int64        `xorm:"INDEX(s) NOT NULL"`
+	BaseBranch string       `xorm:"INDEX(s) NOT NULL"`
+	PullID     int64        `xorm:"UNIQUE NOT NULL"`
+	Pull       *PullRequest `xorm:"-"`
+	DoerID     int64        `xorm:"NOT NULL"`
+	Doer       *User        `xorm:"-"`
+	MergeStyle MergeStyle   `xorm:"VARCHAR(30)"`
+	Message    string       `xorm:"TEXT"`
+
+	// the commits the test merge was created from and the test merge itself, empty while not tested yet
+	BaseCommitID string `xorm:"VARCHAR(40)"`
+	HeadCommitID string `xorm:"VARCHAR(40)"`
+	TestCommitID string `xorm:"VARCHAR(40) INDEX"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(MergeQueueEntry))
+}
+
+// TestRef returns the full name of the ref the test merge of the entry is pushed to
+func (e *MergeQueueEntry) TestRef() string {
+	return fmt.Sprintf("%s%s/pr-%d", MergeQueueRefPrefix, e.BaseBranch, e.PullID)
+}
+
+// IsTesting returns true if a test merge of the entry was created
+func (e *MergeQueueEntry) IsTesting() bool {
+	return len(e.TestCommitID) > 0
+}
+
+// LoadPullRequest loads the pull request of the entry
+func (e *MergeQueueEntry) LoadPullRequest() (err error) {
+	if e.Pull == nil {
+		e.Pull, err = GetPullRequestByID(e.PullID)
+	}
+	return
+}
+
+// LoadDoer loads the user who added the pull request to the merge queue
+func (e *MergeQueueEntry) LoadDoer() (err error) {
+	if e.Doer == nil {
+		e.Doer, err = GetUserByID(e.DoerID)
+	}
+	return
+}
+
+// Position returns the position of the entry in the merge queue of its base branch, starting at 1
+func (e *MergeQueueEntry) Position() (int64, error) {
+	count, err := db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND base_branch = ? AND id < ?", e.RepoID, e.BaseBranch, e.ID).
+		Count(new(MergeQueueEntry))
+	return count + 1, err
+}
+
+// UpdateCols updates specific fields of the entry
+func (e *MergeQueueEntry) UpdateCols(cols ...string) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(e.ID).Cols(cols...).Update(e)
+	return err
+}
+
+// InsertMergeQueueEntry adds a pull request to the end of the merge queue of its base branch
+func InsertMergeQueueEntry(e *MergeQueueEntry) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if has, err := sess.Exist(&MergeQueueEntry{PullID: e.PullID}); err != nil {
+		return err
+	} else if has {
+		return ErrPullRequestAlreadyQueued{ID: e.PullID}
+	}
+	if _, err := sess.Insert(e); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// GetMergeQueueEntryByPullID returns the merge queue entry of a pull request
+func GetMergeQueueEntryByPullID(pullID int64) (*MergeQueueEntry, error) {
+	e := new(MergeQueueEntry)
+	has, err := db.GetEngine(db.DefaultContext).Where("pull_id = ?", pullID).Get(e)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrMergeQueueEntryNotExist{PullID: pullID}
+	}
+	return e, nil
+}
+
+// GetMergeQueueEntryByTestCommit returns the merge queue entry of a repository which is tested by the given commit,
+// it returns nil if there is none.
+func GetMergeQueueEntryByTestCommit(repoID int64, commitID string) (*MergeQueueEntry, error) {
+	e := new(MergeQueueEntry)
+	has, err := db.GetEngine(db.DefaultContext).Where("repo_id = ? AND test_commit_id = ?", repoID, commitID).Get(e)
+	if err != nil || !has {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetMergeQueueHead returns the first entry of the merge queue of a branch, it returns nil if the queue is empty
+func GetMergeQueueHead(repoID int64, baseBranch string) (*MergeQueueEntry, error) {
+	e := new(MergeQueueEntry)
+	has, err := db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND base_branch = ?", repoID, baseBranch).
+		Asc("id").
+		Get(e)
+	if err != nil || !has {
+		return nil, err
+	}
+	return e, nil
+}
+
+// GetMergeQueueEntries returns the entries of the merge queue of a branch in their order
+func GetMergeQueueEntries(repoID int64, baseBranch string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 10)
+	return entries, db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND base_branch = ?", repoID, baseBranch).
+		Asc("id").
+		Find(&entries)
+}
+
+// GetMergeQueueBranches returns one entry for every branch which has a non-empty merge queue
+func GetMergeQueueBranches() ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 10)
+	return entries, db.GetEngine(db.DefaultContext).
+		Distinct("repo_id", "base_branch").
+		Find(&entries)
+}
+
+// DeleteMergeQueueEntry removes an entry from the merge queue
+func DeleteMergeQueueEntry(e *MergeQueueEntry) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(e.ID).Delete(new(MergeQueueEntry))
+	return err
+}
@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeQueue(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	head, err := GetMergeQueueHead(1, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, head)
+
+	first := &MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 2, DoerID: 2, MergeStyle: MergeStyleMerge}
+	assert.NoError(t, InsertMergeQueueEntry(first))
+	second := &MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 5, DoerID: 2, MergeStyle: MergeStyleSquash}
+	assert.NoError(t, InsertMergeQueueEntry(second))
+
+	err = InsertMergeQueueEntry(&MergeQueueEntry{RepoID: 1, BaseBranch: "master", PullID: 2, DoerID: 1, MergeStyle: MergeStyleMerge})
+	assert.True(t, IsErrPullRequestAlreadyQueued(err))
+
+	head, err = GetMergeQueueHead(1, "master")
+	assert.NoError(t, err)
+	if assert.NotNil(t, head) {
+		assert.EqualValues(t, first.ID, head.ID)
+		assert.Equal(t, "refs/merge-queue/master/pr-2", head.TestRef())
+		assert.False(t, head.IsTesting())
+	}
+
+	position, err := second.Position()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, position)
+
+	assert.NoError(t, DeleteMergeQueueEntry(first))
+	_, err = GetMergeQueueEntryByPullID(2)
+	assert.True(t, IsErrMergeQueueEntryNotExist(err))
+
+	position, err = second.Position()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, position)
+}
@@ -193,6 +193,68 @@ func InsertPullRequests(prs ...*PullRequest) error {
 	return sess.Commit()
 }
 
+// UpdateMigratedIssue updates an issue migrated before with its current state on the original service
+func UpdateMigratedIssue(issue *Issue) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.ID(issue.ID).NoAutoTime().
+		Cols("name", "content", "is_closed", "is_locked", "closed_unix", "updated_unix").
+		Update(issue); err != nil {
+		return err
+	}
+	if err := issue.updateClosedNum(sess); err != nil {
+		return err
+	}
+	if issue.MilestoneID > 0 {
+		if err := updateMilestoneCounters(sess, issue.MilestoneID); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// UpdateMigratedPullRequest updates the merge information of a pull request migrated before
+func UpdateMigratedPullRequest(pr *PullRequest) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(pr.ID).NoAutoTime().
+		Cols("has_merged", "merged_unix", "merged_commit_id", "merger_id").
+		Update(pr)
+	return err
+}
+
+// GetMigratedComment returns the comment inserted by an earlier migration which has the same issue, poster and
+// creation time as the given one, the original service doesn't provide another key. It returns nil if there is none.
+func GetMigratedComment(c *Comment) (*Comment, error) {
+	comment := new(Comment)
+	has, err := db.GetEngine(db.DefaultContext).
+		Where("issue_id = ? AND type = ? AND poster_id = ? AND original_author_id = ? AND created_unix = ?",
+			c.IssueID, c.Type, c.PosterID, c.OriginalAuthorID, c.CreatedUnix).
+		Get(comment)
+	if err != nil || !has {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// UpdateMigratedComment updates the content of a comment migrated before
+func UpdateMigratedComment(c *Comment) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(c.ID).NoAutoTime().Cols("content", "updated_unix").Update(c)
+	return err
+}
+
+// IsMigratedReviewExist returns true if a review with the same issue, reviewer, type and
+// creation time as the given one was inserted by an earlier migration
+func IsMigratedReviewExist(r *Review) (bool, error) {
+	return db.GetEngine(db.DefaultContext).
+		Where("issue_id = ? AND type = ? AND reviewer_id = ? AND original_author_id = ? AND created_unix = ?",
+			r.IssueID, r.Type, r.ReviewerID, r.OriginalAuthorID, r.CreatedUnix).
+		Exist(new(Review))
+}
+
 // InsertReleases migrates release
 func InsertReleases(rels ...*Release) error {
 	sess := db.NewSession(db.DefaultContext)
@@ -0,0 +1,48 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMigratedComment(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	migrated := &Comment{
+		IssueID:          1,
+		Type:             CommentTypeComment,
+		OriginalAuthor:   "migrated",
+		OriginalAuthorID: 42,
+		Content:          "migrated comment",
+		CreatedUnix:      946684900,
+		UpdatedUnix:      946684900,
+	}
+	assert.NoError(t, InsertIssueComments([]*Comment{migrated}))
+
+	comment, err := GetMigratedComment(&Comment{IssueID: 1, Type: CommentTypeComment, OriginalAuthorID: 42, CreatedUnix: 946684900})
+	assert.NoError(t, err)
+	if assert.NotNil(t, comment) {
+		assert.EqualValues(t, migrated.ID, comment.ID)
+	}
+
+	comment, err = GetMigratedComment(&Comment{IssueID: 1, Type: CommentTypeComment, OriginalAuthorID: 42, CreatedUnix: 946684901})
+	assert.NoError(t, err)
+	assert.Nil(t, comment)
+}
+
+func TestUpdateMigratedComment(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	comment := db.AssertExistsAndLoadBean(t, &Comment{ID: 2}).(*Comment)
+	comment.Content = "edited upstream"
+	comment.UpdatedUnix = 946684900
+	assert.NoError(t, UpdateMigratedComment(comment))
+
+	db.AssertExistsAndLoadBean(t, &Comment{ID: 2, Content: "edited upstream", UpdatedUnix: 946684900})
+}
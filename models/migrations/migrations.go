@@ -352,6 +352,98 @@ var migrations = []Migration{
 	NewMigration("Add issue content history table", addTableIssueContentHistory),
 	// v199 -> v200
 	NewMigration("Add remote version table", addRemoteVersionTable),
+	// v200 -> v201
+	NewMigration("Add review notification settings to team", addTeamReviewNotificationSettings),
+	// v201 -> v202
+	NewMigration("Add secret rotation columns to webhook", addWebhookSecretRotationColumns),
+	// v202 -> v203
+	NewMigration("Add push rule table", addPushRuleTable),
+	// v203 -> v204
+	NewMigration("Add saved reply table", addSavedReplyTable),
+	// v204 -> v205
+	NewMigration("Add auto watch preference to user", addAutoWatchUserColumn),
+	// v205 -> v206
+	NewMigration("Add repository cleanup task table", addRepoCleanupTaskTable),
+	// v206 -> v207
+	NewMigration("Add object format to repository and widen commit ID columns", addObjectFormatAndWidenCommitIDColumns),
+	// v207 -> v208
+	NewMigration("Add exclusive to label", addExclusiveToLabel),
+	// v208 -> v209
+	NewMigration("Add require code owner approval to protected branch", addRequireCodeOwnerApprovalToProtectedBranch),
+	// v209 -> v210
+	NewMigration("Add index on follow_id to follow", addFollowIDIndexToFollow),
+	// v210 -> v211
+	NewMigration("Add auto update on merge to protected branch", addAutoUpdateOnMergeToProtectedBranch),
+	// v211 -> v212
+	NewMigration("Add last digest to webhook", addLastDigestToWebhook),
+	// v212 -> v213
+	NewMigration("Add is draft to pull request", addIsDraftToPullRequest),
+	// v213 -> v214
+	NewMigration("Add last used to access token", addLastUsedToAccessToken),
+	// v214 -> v215
+	NewMigration("Add signature algorithm to webhook", addSignatureAlgorithmToWebhook),
+	// v215 -> v216
+	NewMigration("Add dismiss only on content change to protected branch", addDismissOnlyOnContentChangeToProtectedBranch),
+	// v216 -> v217
+	NewMigration("Add issue sort type preference to user", addIssueSortTypeUserColumn),
+	// v217 -> v218
+	NewMigration("Add migration progress to task", addMigrationProgressToTask),
+	// v218 -> v219
+	NewMigration("Add merge queue entry table", addMergeQueueEntryTable),
+	// v219 -> v220
+	NewMigration("Add expiry notified to gpg key", addExpiryNotifiedToGPGKey),
+	// v220 -> v221
+	NewMigration("Add repo metadata table", addRepoMetadataTable),
+	// v221 -> v222
+	NewMigration("Add stale branch cleanup table", addStaleBranchCleanupTable),
+	// v222 -> v223
+	NewMigration("Add head ref commit id to pull request", addHeadRefCommitIDToPullRequest),
+	// v223 -> v224
+	NewMigration("Add repo backup table", addRepoBackupTable),
+	// v224 -> v225
+	NewMigration("Add pin order to issue", addPinOrderToIssue),
+	// v225 -> v226
+	NewMigration("Add repo git settings table", addRepoGitSettingsTable),
+	// v226 -> v227
+	NewMigration("Add created from to repository", addCreatedFromToRepository),
+	// v227 -> v228
+	NewMigration("Add statuses capability to deploy keys", addStatusesCapabilityToDeployKey),
+	// v228 -> v229
+	NewMigration("Add repo traffic table", addRepoTrafficTable),
+	// v229 -> v230
+	NewMigration("Add require private repos to user", addRequirePrivateReposToUser),
+	// v230 -> v231
+	NewMigration("Add pull review file state table", addPullReviewFileStateTable),
+	// v231 -> v232
+	NewMigration("Add repo_id, is_pull and is_closed index to issue", addRepoPullClosedIndexToIssue),
+	// v232 -> v233
+	NewMigration("Add milestone reminder table and mute milestone reminders to user", addMilestoneReminders),
+	// v233 -> v234
+	NewMigration("Add access mode to team units and wiki access mode to collaborations", addUnitAccessModes),
+	// v234 -> v235
+	NewMigration("Add repo license table", addRepoLicenseTable),
+	// v235 -> v236
+	NewMigration("Add service account and inactivity columns to user", addUserInactivityColumns),
+	// v236 -> v237
+	NewMigration("Add count and last seen columns to notice", addNoticeCountAndLastSeen),
+	// v237 -> v238
+	NewMigration("Add repo visit table", addRepoVisitTable),
+	// v238 -> v239
+	NewMigration("Add scan secrets column to push rule", addPushRuleScanSecrets),
+	// v239 -> v240
+	NewMigration("Add pushed unix column to repository", addRepositoryPushedUnix),
+	// v240 -> v241
+	NewMigration("Add size updated unix column to repository", addRepositorySizeUpdatedUnix),
+	// v241 -> v242
+	NewMigration("Add repo clone token table", addRepoCloneTokenTable),
+	// v242 -> v243
+	NewMigration("Add path column to repo archiver", addRepoArchiverPath),
+	// v243 -> v244
+	NewMigration("Add user status updated index to notification", addUserStatusUpdatedIndexToNotification),
+	// v244 -> v245
+	NewMigration("Add anonymized unix column to user", addUserAnonymizedUnix),
+	// v245 -> v246
+	NewMigration("Convert webhook branch filter to a list of globs", convertWebhookBranchFilterToList),
 }
 
 // GetCurrentDBVersion returns the current db version
@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addTeamReviewNotificationSettings(x *xorm.Engine) error {
+	type Team struct {
+		ReviewNotification string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'email_all'"`
+	}
+
+	type Webhook struct {
+		TeamID int64 `xorm:"INDEX"`
+	}
+
+	if err := x.Sync2(new(Team)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(Webhook)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addWebhookSecretRotationColumns(x *xorm.Engine) error {
+	type Webhook struct {
+		PreviousSecret            string `xorm:"TEXT"`
+		PreviousSecretExpiresUnix timeutil.TimeStamp
+		SecretRotatedUnix         timeutil.TimeStamp
+	}
+
+	if err := x.Sync2(new(Webhook)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
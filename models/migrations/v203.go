@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addSavedReplyTable(x *xorm.Engine) error {
+	type SavedReply struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		Title       string             `xorm:"NOT NULL"`
+		Body        string             `xorm:"LONGTEXT NOT NULL"`
+		Position    int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(SavedReply)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
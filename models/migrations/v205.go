@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoCleanupTaskTable(x *xorm.Engine) error {
+	type RepoCleanupTask struct {
+		ID              int64              `xorm:"pk autoincr"`
+		RepoID          int64              `xorm:"INDEX"`
+		RepoName        string             `xorm:"NOT NULL DEFAULT ''"`
+		Storage         string             `xorm:"VARCHAR(50) NOT NULL"`
+		Path            string             `xorm:"VARCHAR(1024) NOT NULL"`
+		Status          int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Attempts        int                `xorm:"NOT NULL DEFAULT 0"`
+		LastError       string             `xorm:"TEXT"`
+		NextAttemptUnix timeutil.TimeStamp `xorm:"INDEX"`
+		CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(RepoCleanupTask)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+func addObjectFormatAndWidenCommitIDColumns(x *xorm.Engine) error {
+	type Repository struct {
+		ObjectFormat string `xorm:"VARCHAR(10) NOT NULL DEFAULT 'sha1'"`
+	}
+
+	if err := x.Sync2(new(Repository)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// For SQLITE, varchar or char will always be represented as TEXT
+	if x.Dialect().URI().DBType == schemas.SQLITE {
+		return nil
+	}
+
+	// sha256 object IDs have 64 hex characters instead of 40
+	for _, col := range []struct {
+		table, column string
+	}{
+		{"release", "sha1"},
+		{"repo_archiver", "commit_id"},
+		{"review", "commit_id"},
+		{"repo_indexer_status", "commit_sha"},
+		{"comment", "commit_sha"},
+		{"pull_request", "merge_base"},
+		{"pull_request", "merged_commit_id"},
+	} {
+		if err := modifyColumn(x, col.table, &schemas.Column{
+			Name: col.column,
+			SQLType: schemas.SQLType{
+				Name: "VARCHAR",
+			},
+			Length:   64,
+			Nullable: true,
+		}); err != nil {
+			return fmt.Errorf("modify column %s.%s: %v", col.table, col.column, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addFollowIDIndexToFollow(x *xorm.Engine) error {
+	// The follow unique index of existing tables leads with user_id, the composite follow_user
+	// index created in the order of the fields covers the lookups by follow_id
+	type Follow struct {
+		ID          int64              `xorm:"pk autoincr"`
+		FollowID    int64              `xorm:"UNIQUE(follow) INDEX(follow_user)"`
+		UserID      int64              `xorm:"UNIQUE(follow) INDEX(follow_user) INDEX"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	if err := x.Sync2(new(Follow)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm"
+)
+
+func addIsDraftToPullRequest(x *xorm.Engine) error {
+	type PullRequest struct {
+		IsDraft bool `xorm:"INDEX NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(PullRequest)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// Pull requests marked as work in progress by their title become drafts
+	type Issue struct {
+		ID    int64
+		Title string `xorm:"name"`
+	}
+	const batchSize = 100
+	for start := 0; ; start += batchSize {
+		issues := make([]*Issue, 0, batchSize)
+		if err := x.Table("issue").Select("id, name").Where("is_pull = ?", true).
+			OrderBy("id").Limit(batchSize, start).Find(&issues); err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			break
+		}
+
+		draftIssueIDs := make([]int64, 0, len(issues))
+		for _, issue := range issues {
+			for _, prefix := range setting.Repository.PullRequest.WorkInProgressPrefixes {
+				if strings.HasPrefix(strings.ToUpper(issue.Title), prefix) {
+					draftIssueIDs = append(draftIssueIDs, issue.ID)
+					break
+				}
+			}
+		}
+		if len(draftIssueIDs) == 0 {
+			continue
+		}
+		if _, err := x.Table("pull_request").In("issue_id", draftIssueIDs).
+			Update(map[string]interface{}{"is_draft": true}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
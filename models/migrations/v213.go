@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addLastUsedToAccessToken(x *xorm.Engine) error {
+	type AccessToken struct {
+		LastUsedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		LastUsedIP   string             `xorm:"VARCHAR(64)"`
+	}
+
+	if err := x.Sync2(new(AccessToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// updated_unix used to be bumped on every use of the token
+	_, err := x.Exec("UPDATE access_token SET last_used_unix = updated_unix WHERE updated_unix > created_unix")
+	return err
+}
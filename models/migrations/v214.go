@@ -0,0 +1,22 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addSignatureAlgorithmToWebhook(x *xorm.Engine) error {
+	type Webhook struct {
+		SignatureAlgorithm string `xorm:"VARCHAR(16) NOT NULL DEFAULT 'sha256'"`
+	}
+
+	if err := x.Sync2(new(Webhook)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addDismissOnlyOnContentChangeToProtectedBranch(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		DismissOnlyOnContentChange bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(ProtectedBranch)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// approvals used to be kept when the diff of the pull request did not change
+	_, err := x.Exec("UPDATE protected_branch SET dismiss_only_on_content_change = ? WHERE dismiss_stale_approvals = ?", true, true)
+	return err
+}
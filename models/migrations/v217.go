@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMigrationProgressToTask(x *xorm.Engine) error {
+	type Task struct {
+		MigratedIssueIndex int64
+		MigratedPullIndex  int64
+		MigratedReleaseTag string
+		LastSyncUnix       timeutil.TimeStamp
+	}
+
+	if err := x.Sync2(new(Task)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// finished migrations are synchronized with everything updated after they started
+	_, err := x.Exec("UPDATE task SET last_sync_unix = start_time WHERE type = ? AND status = ?", 0, 4)
+	return err
+}
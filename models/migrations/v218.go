@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMergeQueueEntryTable(x *xorm.Engine) error {
+	type MergeQueueEntry struct {
+		ID           int64  `xorm:"pk autoincr"`
+		RepoID       int64  `xorm:"INDEX(s) NOT NULL"`
+		BaseBranch   string `xorm:"INDEX(s) NOT NULL"`
+		PullID       int64  `xorm:"UNIQUE NOT NULL"`
+		DoerID       int64  `xorm:"NOT NULL"`
+		MergeStyle   string `xorm:"VARCHAR(30)"`
+		Message      string `xorm:"TEXT"`
+		BaseCommitID string `xorm:"VARCHAR(40)"`
+		HeadCommitID string `xorm:"VARCHAR(40)"`
+		TestCommitID string `xorm:"VARCHAR(40) INDEX"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(MergeQueueEntry)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
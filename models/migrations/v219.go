@@ -0,0 +1,24 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addExpiryNotifiedToGPGKey(x *xorm.Engine) error {
+	type GPGKey struct {
+		ExpiryNotifiedUnix timeutil.TimeStamp
+	}
+
+	if err := x.Sync2(new(GPGKey)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
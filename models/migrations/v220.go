@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoMetadataTable(x *xorm.Engine) error {
+	type RepoMetadata struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Key         string             `xorm:"'metadata_key' UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Value       string             `xorm:"LONGTEXT"`
+		Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+		Version     int64              `xorm:"NOT NULL DEFAULT 1"`
+		UpdatedByID int64              `xorm:"NOT NULL DEFAULT 0"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(RepoMetadata)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addStaleBranchCleanupTable(x *xorm.Engine) error {
+	type StaleBranchCleanup struct {
+		ID            int64 `xorm:"pk autoincr"`
+		RepoID        int64 `xorm:"UNIQUE"`
+		Enabled       bool  `xorm:"NOT NULL DEFAULT false"`
+		Merged        bool  `xorm:"NOT NULL DEFAULT false"`
+		OlderThanDays int   `xorm:"NOT NULL DEFAULT 0"`
+		DryRun        bool  `xorm:"NOT NULL DEFAULT true"`
+		DoerID        int64 `xorm:"NOT NULL DEFAULT 0"`
+
+		LastRunUnix     timeutil.TimeStamp
+		LastRunDryRun   bool     `xorm:"NOT NULL DEFAULT false"`
+		LastRunBranches []string `xorm:"JSON TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(StaleBranchCleanup)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoBackupTable(x *xorm.Engine) error {
+	type RepoBackup struct {
+		ID              int64              `xorm:"pk autoincr"`
+		RepoID          int64              `xorm:"INDEX NOT NULL"`
+		Status          int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Path            string             `xorm:"VARCHAR(1024) NOT NULL DEFAULT ''"`
+		Attempts        int                `xorm:"NOT NULL DEFAULT 0"`
+		LastError       string             `xorm:"TEXT"`
+		NextAttemptUnix timeutil.TimeStamp `xorm:"INDEX"`
+		Size            int64              `xorm:"NOT NULL DEFAULT 0"`
+		Duration        int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type Repository struct {
+		IsBackupEnabled bool `xorm:"NOT NULL DEFAULT true"`
+	}
+
+	if err := x.Sync2(new(RepoBackup)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(Repository)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoGitSettingsTable(x *xorm.Engine) error {
+	type RepoGitSettings struct {
+		ID                 int64 `xorm:"pk autoincr"`
+		RepoID             int64 `xorm:"UNIQUE"`
+		DisableDumbHTTP    *bool
+		DefaultCloneFilter *string `xorm:"VARCHAR(255)"`
+		FullCloneMaxSize   *int64
+		CreatedUnix        timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix        timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(RepoGitSettings)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addCreatedFromToRepository(x *xorm.Engine) error {
+	type Repository struct {
+		CreatedFrom string `xorm:"VARCHAR(20) INDEX NOT NULL DEFAULT 'created'"`
+	}
+
+	if err := x.Sync2(new(Repository)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	// adopted and pushed-to-create repositories can't be told apart from created ones anymore
+	if _, err := x.Exec("UPDATE repository SET created_from = ? WHERE original_url <> ''", "migrated"); err != nil {
+		return err
+	}
+	if _, err := x.Exec("UPDATE repository SET created_from = ? WHERE template_id > 0", "generated"); err != nil {
+		return err
+	}
+	_, err := x.Exec("UPDATE repository SET created_from = ? WHERE is_fork = ?", "forked", true)
+	return err
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addStatusesCapabilityToDeployKey(x *xorm.Engine) error {
+	type DeployKey struct {
+		CanWriteStatuses       bool `xorm:"NOT NULL DEFAULT false"`
+		StatusesTokenHash      string
+		StatusesTokenSalt      string
+		StatusesTokenLastEight string `xorm:"INDEX"`
+	}
+
+	type CommitStatus struct {
+		CreatorName string
+	}
+
+	if err := x.Sync2(new(DeployKey)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	if err := x.Sync2(new(CommitStatus)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
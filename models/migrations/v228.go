@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoTrafficTable(x *xorm.Engine) error {
+	type RepoTraffic struct {
+		ID        int64              `xorm:"pk autoincr"`
+		RepoID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Kind      int                `xorm:"UNIQUE(s) NOT NULL"`
+		Period    int                `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		StartUnix timeutil.TimeStamp `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Count     int64              `xorm:"NOT NULL DEFAULT 0"`
+		Uniques   int64              `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(RepoTraffic)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
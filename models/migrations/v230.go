@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addPullReviewFileStateTable(x *xorm.Engine) error {
+	type PullReviewFileState struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		PullID      int64              `xorm:"INDEX(s) NOT NULL"`
+		UserID      int64              `xorm:"INDEX(s) NOT NULL"`
+		TreePath    string             `xorm:"TEXT NOT NULL"`
+		CommitSHA   string             `xorm:"VARCHAR(40) NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	if err := x.Sync2(new(PullReviewFileState)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoPullClosedIndexToIssue(x *xorm.Engine) error {
+	// All indexed columns of the issue are listed as Sync2 drops the indexes it doesn't know,
+	// the columns of repo_pull_closed are ordered like the fields
+	type Issue struct {
+		ID               int64              `xorm:"pk autoincr"`
+		RepoID           int64              `xorm:"INDEX UNIQUE(repo_index) INDEX(repo_pull_closed)"`
+		Index            int64              `xorm:"UNIQUE(repo_index)"`
+		PosterID         int64              `xorm:"INDEX"`
+		OriginalAuthorID int64              `xorm:"index"`
+		MilestoneID      int64              `xorm:"INDEX"`
+		IsPull           bool               `xorm:"INDEX INDEX(repo_pull_closed)"`
+		IsClosed         bool               `xorm:"INDEX INDEX(repo_pull_closed)"`
+		DeadlineUnix     timeutil.TimeStamp `xorm:"INDEX"`
+		CreatedUnix      timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix      timeutil.TimeStamp `xorm:"INDEX updated"`
+		ClosedUnix       timeutil.TimeStamp `xorm:"INDEX"`
+	}
+
+	if err := x.Sync2(new(Issue)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
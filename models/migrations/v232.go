@@ -0,0 +1,34 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addMilestoneReminders(x *xorm.Engine) error {
+	type MilestoneReminder struct {
+		ID           int64              `xorm:"pk autoincr"`
+		RepoID       int64              `xorm:"INDEX NOT NULL"`
+		MilestoneID  int64              `xorm:"UNIQUE(s) NOT NULL"`
+		UserID       int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Type         int                `xorm:"UNIQUE(s) NOT NULL"`
+		DeadlineUnix timeutil.TimeStamp `xorm:"UNIQUE(s) NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type User struct {
+		MuteMilestoneReminders bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(MilestoneReminder), new(User)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addUnitAccessModes(x *xorm.Engine) error {
+	type TeamUnit struct {
+		AccessMode int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type Collaboration struct {
+		WikiMode int `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(TeamUnit), new(Collaboration)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
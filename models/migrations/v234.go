@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoLicenseTable(x *xorm.Engine) error {
+	type RepoLicense struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Path        string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		License     string             `xorm:"VARCHAR(100) UNIQUE(s) INDEX NOT NULL"`
+		BlobID      string             `xorm:"VARCHAR(64)"`
+		CommitID    string             `xorm:"VARCHAR(64)"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX CREATED"`
+	}
+
+	if err := x.Sync2(new(RepoLicense)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
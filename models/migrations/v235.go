@@ -0,0 +1,26 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addUserInactivityColumns(x *xorm.Engine) error {
+	type User struct {
+		IsServiceAccount          bool               `xorm:"NOT NULL DEFAULT false"`
+		InactivityWarnedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		InactivityDeactivatedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(User)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
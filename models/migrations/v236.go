@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addNoticeCountAndLastSeen(x *xorm.Engine) error {
+	type Notice struct {
+		Count        int64              `xorm:"NOT NULL DEFAULT 1"`
+		LastSeenUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(Notice)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+
+	if _, err := x.Exec("UPDATE `notice` SET last_seen_unix = created_unix WHERE created_unix IS NOT NULL"); err != nil {
+		return fmt.Errorf("update last_seen_unix: %v", err)
+	}
+	return nil
+}
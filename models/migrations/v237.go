@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoVisitTable(x *xorm.Engine) error {
+	type RepoVisit struct {
+		ID                  int64              `xorm:"pk autoincr"`
+		UserID              int64              `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID              int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		VisitedUnix         timeutil.TimeStamp `xorm:"NOT NULL"`
+		PreviousVisitedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	if err := x.Sync2(new(RepoVisit)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
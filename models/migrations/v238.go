@@ -0,0 +1,22 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addPushRuleScanSecrets(x *xorm.Engine) error {
+	type PushRule struct {
+		ScanSecrets bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync2(new(PushRule)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
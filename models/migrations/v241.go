@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addRepoCloneTokenTable(x *xorm.Engine) error {
+	type RepoCloneToken struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"INDEX NOT NULL"`
+		UID            int64  `xorm:"INDEX NOT NULL"`
+		Mode           int    `xorm:"NOT NULL"`
+		TokenHash      string `xorm:"UNIQUE"`
+		TokenSalt      string
+		TokenLastEight string `xorm:"INDEX token_last_eight"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	if err := x.Sync2(new(RepoCloneToken)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
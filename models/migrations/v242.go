@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"xorm.io/xorm"
+)
+
+func addRepoArchiverPath(x *xorm.Engine) error {
+	// the path joins the unique index, which Sync2 recreates
+	type RepoArchiver struct {
+		ID          int64 `xorm:"pk autoincr"`
+		RepoID      int64 `xorm:"index unique(s)"`
+		Type        int   `xorm:"unique(s)"`
+		Status      int
+		CommitID    string `xorm:"VARCHAR(64) unique(s)"`
+		Path        string `xorm:"VARCHAR(255) unique(s) NOT NULL DEFAULT ''"`
+		CreatedUnix int64  `xorm:"INDEX NOT NULL created"`
+	}
+
+	if err := x.Sync2(new(RepoArchiver)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
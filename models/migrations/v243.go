@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func addUserStatusUpdatedIndexToNotification(x *xorm.Engine) error {
+	// All indexed columns of the notification are listed as Sync2 drops the indexes it doesn't know,
+	// the columns of u_s_uu are ordered like the fields
+	type Notification struct {
+		ID          int64  `xorm:"pk autoincr"`
+		UserID      int64  `xorm:"INDEX INDEX(u_s_uu) NOT NULL"`
+		RepoID      int64  `xorm:"INDEX NOT NULL"`
+		Status      uint8  `xorm:"SMALLINT INDEX INDEX(u_s_uu) NOT NULL"`
+		Source      uint8  `xorm:"SMALLINT INDEX NOT NULL"`
+		IssueID     int64  `xorm:"INDEX NOT NULL"`
+		CommitID    string `xorm:"INDEX"`
+		CommentID   int64
+		UpdatedBy   int64              `xorm:"INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated INDEX INDEX(u_s_uu) NOT NULL"`
+	}
+
+	if err := x.Sync2(new(Notification)); err != nil {
+		return fmt.Errorf("Sync2: %v", err)
+	}
+	return nil
+}
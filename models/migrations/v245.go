@@ -0,0 +1,70 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package migrations
+
+import (
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/xorm"
+)
+
+func convertWebhookBranchFilterToList(x *xorm.Engine) error {
+	type Webhook struct {
+		ID     int64
+		Events string
+	}
+
+	var last int64
+	batchSize := setting.Database.IterateBufferSize
+	sess := x.NewSession()
+	defer sess.Close()
+	for {
+		if err := sess.Begin(); err != nil {
+			return err
+		}
+		results := make([]Webhook, 0, batchSize)
+		if err := sess.Where("id > ?", last).OrderBy("id").Limit(batchSize).Find(&results); err != nil {
+			return err
+		}
+		if len(results) == 0 {
+			break
+		}
+		last = results[len(results)-1].ID
+
+		for _, res := range results {
+			if res.Events == "" {
+				continue
+			}
+			// the other fields of the events are kept as they are
+			events := make(map[string]interface{})
+			if err := json.Unmarshal([]byte(res.Events), &events); err != nil {
+				return err
+			}
+			raw, ok := events["branch_filter"]
+			if !ok {
+				continue
+			}
+
+			// the single glob becomes the only branch filter
+			delete(events, "branch_filter")
+			if filter, _ := raw.(string); filter != "" {
+				events["branch_filters"] = []string{filter}
+			}
+			bytes, err := json.Marshal(events)
+			if err != nil {
+				return err
+			}
+			if _, err := sess.Exec("UPDATE webhook SET events = ? WHERE id = ?", string(bytes), res.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := sess.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -47,10 +47,10 @@ const (
 // Notification represents a notification
 type Notification struct {
 	ID     int64 `xorm:"pk autoincr"`
-	UserID int64 `xorm:"INDEX NOT NULL"`
+	UserID int64 `xorm:"INDEX INDEX(u_s_uu) NOT NULL"`
 	RepoID int64 `xorm:"INDEX NOT NULL"`
 
-	Status NotificationStatus `xorm:"SMALLINT INDEX NOT NULL"`
+	Status NotificationStatus `xorm:"SMALLINT INDEX INDEX(u_s_uu) NOT NULL"`
 	Source NotificationSource `xorm:"SMALLINT INDEX NOT NULL"`
 
 	IssueID   int64  `xorm:"INDEX NOT NULL"`
@@ -65,7 +65,7 @@ type Notification struct {
 	User       *User       `xorm:"-"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX NOT NULL"`
-	UpdatedUnix timeutil.TimeStamp `xorm:"updated INDEX NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated INDEX INDEX(u_s_uu) NOT NULL"`
 }
 
 func init() {
@@ -378,6 +378,7 @@ func CountUnread(user *User) int64 {
 	return countUnread(db.GetEngine(db.DefaultContext), user.ID)
 }
 
+// countUnread only reads the user_id, status and updated_unix index of the notifications
 func countUnread(e db.Engine, userID int64) int64 {
 	exist, err := e.Where("user_id = ?", userID).And("status = ?", NotificationStatusUnread).Count(new(Notification))
 	if err != nil {
@@ -484,14 +485,31 @@ func (n *Notification) APIURL() string {
 type NotificationList []*Notification
 
 // LoadAttributes load Repo Issue User and Comment if not loaded
-func (nl NotificationList) LoadAttributes() (err error) {
-	for i := 0; i < len(nl); i++ {
-		err = nl[i].LoadAttributes()
-		if err != nil {
-			return
-		}
+func (nl NotificationList) LoadAttributes() error {
+	return nl.loadAttributes(db.GetEngine(db.DefaultContext))
+}
+
+// loadAttributes loads the attributes of all the notifications with a constant number of queries,
+// whichever repositories and issues they are about
+func (nl NotificationList) loadAttributes(e db.Engine) error {
+	_, failures, err := nl.loadRepos(e)
+	if err != nil {
+		return err
+	} else if len(failures) > 0 {
+		n := nl[failures[0]]
+		return fmt.Errorf("getRepositoryByID [%d]: %v", n.RepoID, ErrRepoNotExist{ID: n.RepoID})
 	}
-	return
+	if failures, err = nl.loadIssues(e); err != nil {
+		return err
+	} else if len(failures) > 0 {
+		n := nl[failures[0]]
+		return fmt.Errorf("getIssueByID [%d]: %v", n.IssueID, ErrIssueNotExist{ID: n.IssueID})
+	}
+	if err = nl.loadUsers(e); err != nil {
+		return err
+	}
+	_, err = nl.loadComments(e)
+	return err
 }
 
 func (nl NotificationList) getPendingRepoIDs() []int64 {
@@ -509,6 +527,10 @@ func (nl NotificationList) getPendingRepoIDs() []int64 {
 
 // LoadRepos loads repositories from database
 func (nl NotificationList) LoadRepos() (RepositoryList, []int, error) {
+	return nl.loadRepos(db.GetEngine(db.DefaultContext))
+}
+
+func (nl NotificationList) loadRepos(e db.Engine) (RepositoryList, []int, error) {
 	if len(nl) == 0 {
 		return RepositoryList{}, []int{}, nil
 	}
@@ -521,32 +543,17 @@ func (nl NotificationList) LoadRepos() (RepositoryList, []int, error) {
 		if left < limit {
 			limit = left
 		}
-		rows, err := db.GetEngine(db.DefaultContext).
-			In("id", repoIDs[:limit]).
-			Rows(new(Repository))
-		if err != nil {
+		if err := e.In("id", repoIDs[:limit]).Find(&repos); err != nil {
 			return nil, nil, err
 		}
-
-		for rows.Next() {
-			var repo Repository
-			err = rows.Scan(&repo)
-			if err != nil {
-				rows.Close()
-				return nil, nil, err
-			}
-
-			repos[repo.ID] = &repo
-		}
-		_ = rows.Close()
-
 		left -= limit
 		repoIDs = repoIDs[limit:]
 	}
 
 	failed := []int{}
 
-	reposList := make(RepositoryList, 0, len(repoIDs))
+	reposList := make(RepositoryList, 0, len(repos))
+	listed := make(map[int64]struct{}, len(repos))
 	for i, notification := range nl {
 		if notification.Repository == nil {
 			notification.Repository = repos[notification.RepoID]
@@ -556,14 +563,8 @@ func (nl NotificationList) LoadRepos() (RepositoryList, []int, error) {
 			failed = append(failed, i)
 			continue
 		}
-		var found bool
-		for _, r := range reposList {
-			if r.ID == notification.RepoID {
-				found = true
-				break
-			}
-		}
-		if !found {
+		if _, ok := listed[notification.RepoID]; !ok {
+			listed[notification.RepoID] = struct{}{}
 			reposList = append(reposList, notification.Repository)
 		}
 	}
@@ -573,7 +574,7 @@ func (nl NotificationList) LoadRepos() (RepositoryList, []int, error) {
 func (nl NotificationList) getPendingIssueIDs() []int64 {
 	ids := make(map[int64]struct{}, len(nl))
 	for _, notification := range nl {
-		if notification.Issue != nil {
+		if notification.Issue != nil || notification.IssueID == 0 {
 			continue
 		}
 		if _, ok := ids[notification.IssueID]; !ok {
@@ -583,8 +584,12 @@ func (nl NotificationList) getPendingIssueIDs() []int64 {
 	return keysInt64(ids)
 }
 
-// LoadIssues loads issues from database
+// LoadIssues loads issues from database, with the pull requests of the pulls
 func (nl NotificationList) LoadIssues() ([]int, error) {
+	return nl.loadIssues(db.GetEngine(db.DefaultContext))
+}
+
+func (nl NotificationList) loadIssues(e db.Engine) ([]int, error) {
 	if len(nl) == 0 {
 		return []int{}, nil
 	}
@@ -597,31 +602,16 @@ func (nl NotificationList) LoadIssues() ([]int, error) {
 		if left < limit {
 			limit = left
 		}
-		rows, err := db.GetEngine(db.DefaultContext).
-			In("id", issueIDs[:limit]).
-			Rows(new(Issue))
-		if err != nil {
+		if err := e.In("id", issueIDs[:limit]).Find(&issues); err != nil {
 			return nil, err
 		}
-
-		for rows.Next() {
-			var issue Issue
-			err = rows.Scan(&issue)
-			if err != nil {
-				rows.Close()
-				return nil, err
-			}
-
-			issues[issue.ID] = &issue
-		}
-		_ = rows.Close()
-
 		left -= limit
 		issueIDs = issueIDs[limit:]
 	}
 
 	failures := []int{}
 
+	loaded := make(IssueList, 0, len(issues))
 	for i, notification := range nl {
 		if notification.Issue == nil {
 			notification.Issue = issues[notification.IssueID]
@@ -633,11 +623,64 @@ func (nl NotificationList) LoadIssues() ([]int, error) {
 				continue
 			}
 			notification.Issue.Repo = notification.Repository
+			loaded = append(loaded, notification.Issue)
+		}
+	}
+	if err := loaded.loadPullRequests(e); err != nil {
+		return nil, err
+	}
+	for _, issue := range loaded {
+		if issue.PullRequest != nil {
+			issue.PullRequest.Issue = issue
 		}
 	}
 	return failures, nil
 }
 
+func (nl NotificationList) getPendingUserIDs() []int64 {
+	ids := make(map[int64]struct{}, len(nl))
+	for _, notification := range nl {
+		if notification.User != nil {
+			continue
+		}
+		if _, ok := ids[notification.UserID]; !ok {
+			ids[notification.UserID] = struct{}{}
+		}
+	}
+	return keysInt64(ids)
+}
+
+func (nl NotificationList) loadUsers(e db.Engine) error {
+	userIDs := nl.getPendingUserIDs()
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	users := make(map[int64]*User, len(userIDs))
+	left := len(userIDs)
+	for left > 0 {
+		limit := defaultMaxInSize
+		if left < limit {
+			limit = left
+		}
+		if err := e.In("id", userIDs[:limit]).Find(&users); err != nil {
+			return err
+		}
+		left -= limit
+		userIDs = userIDs[limit:]
+	}
+
+	for _, notification := range nl {
+		if notification.User == nil {
+			notification.User = users[notification.UserID]
+			if notification.User == nil {
+				return fmt.Errorf("getUserByID [%d]: %v", notification.UserID, ErrUserNotExist{UID: notification.UserID})
+			}
+		}
+	}
+	return nil
+}
+
 // Without returns the notification list without the failures
 func (nl NotificationList) Without(failures []int) NotificationList {
 	if len(failures) == 0 {
@@ -671,6 +714,10 @@ func (nl NotificationList) getPendingCommentIDs() []int64 {
 
 // LoadComments loads comments from database
 func (nl NotificationList) LoadComments() ([]int, error) {
+	return nl.loadComments(db.GetEngine(db.DefaultContext))
+}
+
+func (nl NotificationList) loadComments(e db.Engine) ([]int, error) {
 	if len(nl) == 0 {
 		return []int{}, nil
 	}
@@ -683,25 +730,9 @@ func (nl NotificationList) LoadComments() ([]int, error) {
 		if left < limit {
 			limit = left
 		}
-		rows, err := db.GetEngine(db.DefaultContext).
-			In("id", commentIDs[:limit]).
-			Rows(new(Comment))
-		if err != nil {
+		if err := e.In("id", commentIDs[:limit]).Find(&comments); err != nil {
 			return nil, err
 		}
-
-		for rows.Next() {
-			var comment Comment
-			err = rows.Scan(&comment)
-			if err != nil {
-				rows.Close()
-				return nil, err
-			}
-
-			comments[comment.ID] = &comment
-		}
-		_ = rows.Close()
-
 		left -= limit
 		commentIDs = commentIDs[limit:]
 	}
@@ -60,6 +60,58 @@ func TestNotification_GetIssue(t *testing.T) {
 	assert.EqualValues(t, notf.IssueID, issue.ID)
 }
 
+func TestNotificationList_LoadAttributesQueryCount(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// loadPage replaces the notifications of user 5 with a page of 20, about the issues and repositories
+	// they are created for, and returns the number of queries loading the page with its attributes
+	loadPage := func(issueIDs, repoIDs []int64) int {
+		_, err := db.GetEngine(db.DefaultContext).Delete(&Notification{UserID: 5})
+		assert.NoError(t, err)
+		for i := 0; i < 20; i++ {
+			n := &Notification{UserID: 5, Status: NotificationStatusUnread, UpdatedBy: 2}
+			if i%2 == 0 {
+				issue := db.AssertExistsAndLoadBean(t, &Issue{ID: issueIDs[i/2%len(issueIDs)]}).(*Issue)
+				n.Source, n.IssueID, n.RepoID = NotificationSourceIssue, issue.ID, issue.RepoID
+				if issue.IsPull {
+					n.Source = NotificationSourcePullRequest
+				}
+			} else {
+				n.Source, n.RepoID = NotificationSourceRepository, repoIDs[i/2%len(repoIDs)]
+			}
+			_, err = db.GetEngine(db.DefaultContext).Insert(n)
+			assert.NoError(t, err)
+		}
+
+		return countIssueQueries(func() {
+			nl, err := GetNotifications(&FindNotificationOptions{
+				ListOptions: db.ListOptions{Page: 1, PageSize: 20},
+				UserID:      5,
+				Status:      []NotificationStatus{NotificationStatusUnread},
+			})
+			assert.NoError(t, err)
+			assert.NoError(t, nl.LoadAttributes())
+			if assert.Len(t, nl, 20) {
+				for _, n := range nl {
+					assert.NotNil(t, n.Repository)
+					assert.NotNil(t, n.User)
+					if n.IssueID != 0 {
+						assert.Equal(t, n.Repository, n.Issue.Repo)
+						assert.Equal(t, n.Issue.IsPull, n.Issue.PullRequest != nil)
+					}
+				}
+			}
+		})
+	}
+
+	// the notifications, the repositories, the issues, the pull requests and the users
+	const queries = 5
+	// issues 2 and 3 of repository 1 are pull requests
+	assert.Equal(t, queries, loadPage([]int64{1, 2, 3, 5, 11}, []int64{1}))
+	// issues 8 and 9 of the repositories 10 and 48 are pull requests
+	assert.Equal(t, queries, loadPage([]int64{1, 4, 6, 8, 9, 10, 13, 14, 15}, []int64{11, 12, 13, 14, 15, 16, 17, 18, 19, 20}))
+}
+
 func TestGetNotificationCount(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	user := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
@@ -138,6 +138,7 @@ func CreateOrganization(org, owner *User) (err error) {
 		return ErrUserNotAllowedCreateOrg{}
 	}
 
+	org.Name = NormalizeName(org.Name)
 	if err = IsUsableUsername(org.Name); err != nil {
 		return err
 	}
@@ -293,6 +294,7 @@ func deleteOrg(e *xorm.Session, u *User) error {
 		&OrgUser{OrgID: u.ID},
 		&TeamUser{OrgID: u.ID},
 		&TeamUnit{OrgID: u.ID},
+		&SavedReply{OwnerID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -392,6 +394,32 @@ func CanCreateOrgRepo(orgID, uid int64) (bool, error) {
 		Exist(new(Team))
 }
 
+// CheckPublicRepoAllowed returns ErrPublicRepoNotAllowed when owner is an organization which requires
+// its repositories to be private, unless doer is a site admin who overrides the policy
+func CheckPublicRepoAllowed(doer, owner *User, override bool) error {
+	if !owner.IsOrganization() || !owner.RequirePrivateRepos {
+		return nil
+	}
+	if override && doer != nil && doer.IsAdmin {
+		return nil
+	}
+	return ErrPublicRepoNotAllowed{OrgName: owner.Name}
+}
+
+// GetPublicOrgRepositories returns the public repositories of an organization and their total count,
+// these violate the policy of an organization which requires private repositories
+func GetPublicOrgRepositories(orgID int64, listOpts db.ListOptions) (RepositoryList, int64, error) {
+	cond := builder.Eq{"owner_id": orgID, "is_private": false}
+	count, err := db.GetEngine(db.DefaultContext).Where(cond).Count(new(Repository))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repos := make(RepositoryList, 0, listOpts.PageSize)
+	sess := db.GetEngine(db.DefaultContext).Where(cond).OrderBy("lower_name")
+	return repos, count, db.SetSessionPagination(sess, &listOpts).Find(&repos)
+}
+
 // GetOrgUserMaxAuthorizeLevel returns highest authorize level of user in an organization
 func (org *User) GetOrgUserMaxAuthorizeLevel(uid int64) (AccessMode, error) {
 	var authorize AccessMode
@@ -648,25 +676,28 @@ func AddOrgUser(orgID, uid int64) error {
 		return err
 	}
 
+	if err := addOrgUser(sess, orgID, uid); err != nil {
+		if err := sess.Rollback(); err != nil {
+			log.Error("AddOrgUser: sess.Rollback: %v", err)
+		}
+		return err
+	}
+
+	return sess.Commit()
+}
+
+func addOrgUser(e db.Engine, orgID, uid int64) error {
 	ou := &OrgUser{
 		UID:      uid,
 		OrgID:    orgID,
 		IsPublic: setting.Service.DefaultOrgMemberVisible,
 	}
 
-	if _, err := sess.Insert(ou); err != nil {
-		if err := sess.Rollback(); err != nil {
-			log.Error("AddOrgUser: sess.Rollback: %v", err)
-		}
-		return err
-	} else if _, err = sess.Exec("UPDATE `user` SET num_members = num_members + 1 WHERE id = ?", orgID); err != nil {
-		if err := sess.Rollback(); err != nil {
-			log.Error("AddOrgUser: sess.Rollback: %v", err)
-		}
+	if _, err := e.Insert(ou); err != nil {
 		return err
 	}
-
-	return sess.Commit()
+	_, err := e.Exec("UPDATE `user` SET num_members = num_members + 1 WHERE id = ?", orgID)
+	return err
 }
 
 func removeOrgUser(sess *xorm.Session, orgID, userID int64) error {
@@ -833,7 +864,8 @@ type AccessibleReposEnvironment interface {
 	CountRepos() (int64, error)
 	RepoIDs(page, pageSize int) ([]int64, error)
 	Repos(page, pageSize int) ([]*Repository, error)
-	MirrorRepos() ([]*Repository, error)
+	CountMirrorRepos() (int64, error)
+	MirrorRepos(page, pageSize int) ([]*Repository, error)
 	AddKeyword(keyword string)
 	SetSort(SearchOrderBy)
 }
@@ -956,20 +988,37 @@ func (env *accessibleReposEnv) Repos(page, pageSize int) ([]*Repository, error)
 		Find(&repos)
 }
 
-func (env *accessibleReposEnv) MirrorRepoIDs() ([]int64, error) {
-	repoIDs := make([]int64, 0, 10)
+func (env *accessibleReposEnv) CountMirrorRepos() (int64, error) {
+	repoCount, err := env.e.
+		Join("INNER", "team_repo", "`team_repo`.repo_id=`repository`.id AND `repository`.is_mirror=?", true).
+		Where(env.cond()).
+		Distinct("`repository`.id").
+		Count(&Repository{})
+	if err != nil {
+		return 0, fmt.Errorf("count user mirror repositories in organization: %v", err)
+	}
+	return repoCount, nil
+}
+
+func (env *accessibleReposEnv) MirrorRepoIDs(page, pageSize int) ([]int64, error) {
+	if page <= 0 {
+		page = 1
+	}
+
+	repoIDs := make([]int64, 0, pageSize)
 	return repoIDs, env.e.
 		Table("repository").
 		Join("INNER", "team_repo", "`team_repo`.repo_id=`repository`.id AND `repository`.is_mirror=?", true).
 		Where(env.cond()).
-		GroupBy("`repository`.id, `repository`.updated_unix").
+		GroupBy("`repository`.id,`repository`."+strings.Fields(string(env.orderBy))[0]).
 		OrderBy(string(env.orderBy)).
+		Limit(pageSize, (page-1)*pageSize).
 		Cols("`repository`.id").
 		Find(&repoIDs)
 }
 
-func (env *accessibleReposEnv) MirrorRepos() ([]*Repository, error) {
-	repoIDs, err := env.MirrorRepoIDs()
+func (env *accessibleReposEnv) MirrorRepos(page, pageSize int) ([]*Repository, error) {
+	repoIDs, err := env.MirrorRepoIDs(page, pageSize)
 	if err != nil {
 		return nil, fmt.Errorf("MirrorRepoIDs: %v", err)
 	}
@@ -981,6 +1030,7 @@ func (env *accessibleReposEnv) MirrorRepos() ([]*Repository, error) {
 
 	return repos, env.e.
 		In("`repository`.id", repoIDs).
+		OrderBy(string(env.orderBy)).
 		Find(&repos)
 }
 
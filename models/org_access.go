@@ -0,0 +1,142 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// RepoAccessPath is a team or a collaboration by which a user has access to a repository of an organization
+type RepoAccessPath struct {
+	// Team is nil for a collaboration
+	Team *Team
+	Mode AccessMode
+}
+
+// AccessibleRepo is a repository of an organization a team or a user has access to
+type AccessibleRepo struct {
+	*Repository
+	Mode  AccessMode
+	Paths []*RepoAccessPath
+}
+
+// GetTeamAccessibleRepos returns the repositories the team has access to and their total count,
+// the owners team has access to all the repositories of the organization
+func GetTeamAccessibleRepos(t *Team, listOpts db.ListOptions) ([]*AccessibleRepo, int64, error) {
+	e := db.GetEngine(db.DefaultContext)
+	cond := builder.NewCond().And(builder.Eq{"repository.owner_id": t.OrgID})
+	if !t.IsOwnerTeam() {
+		cond = cond.And(builder.In("repository.id", builder.Select("repo_id").From("team_repo").Where(builder.Eq{"team_id": t.ID})))
+	}
+	count, err := e.Where(cond).Count(new(Repository))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repos := make(RepositoryList, 0, listOpts.PageSize)
+	if err := db.SetSessionPagination(e.Where(cond).OrderBy("lower_name"), &listOpts).Find(&repos); err != nil {
+		return nil, 0, err
+	}
+
+	mode := t.Authorize
+	if t.IsOwnerTeam() {
+		mode = AccessModeOwner
+	}
+	accessible := make([]*AccessibleRepo, 0, len(repos))
+	for _, repo := range repos {
+		accessible = append(accessible, &AccessibleRepo{
+			Repository: repo,
+			Mode:       mode,
+			Paths:      []*RepoAccessPath{{Team: t, Mode: mode}},
+		})
+	}
+	return accessible, count, nil
+}
+
+// GetUserAccessibleOrgRepos returns the repositories of the organization the user has access to, as a member
+// of its teams or as a collaborator, and their total count
+func GetUserAccessibleOrgRepos(orgID, userID int64, listOpts db.ListOptions) ([]*AccessibleRepo, int64, error) {
+	e := db.GetEngine(db.DefaultContext)
+	cond := builder.Eq{"repository.owner_id": orgID, "access.user_id": userID}
+	count, err := e.Join("INNER", "access", "access.repo_id = repository.id").Where(cond).Count(new(Repository))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	repos := make(RepositoryList, 0, listOpts.PageSize)
+	sess := e.Join("INNER", "access", "access.repo_id = repository.id").Where(cond).OrderBy("repository.lower_name")
+	if err := db.SetSessionPagination(sess, &listOpts).Find(&repos); err != nil {
+		return nil, 0, err
+	}
+	if len(repos) == 0 {
+		return []*AccessibleRepo{}, count, nil
+	}
+	repoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		repoIDs = append(repoIDs, repo.ID)
+	}
+
+	accesses := make([]*Access, 0, len(repos))
+	if err := e.Where("user_id = ?", userID).In("repo_id", repoIDs).Find(&accesses); err != nil {
+		return nil, 0, err
+	}
+	modes := make(map[int64]AccessMode, len(accesses))
+	for _, a := range accesses {
+		modes[a.RepoID] = a.Mode
+	}
+
+	teams := make([]*Team, 0, 5)
+	if err := e.Join("INNER", "team_user", "team_user.team_id = team.id").
+		Where("team_user.uid = ? AND team.org_id = ?", userID, orgID).
+		OrderBy("team.lower_name").
+		Find(&teams); err != nil {
+		return nil, 0, err
+	}
+	teamRepos := make(map[int64]map[int64]bool, len(repos))
+	if len(teams) > 0 {
+		teamIDs := make([]int64, 0, len(teams))
+		for _, t := range teams {
+			teamIDs = append(teamIDs, t.ID)
+		}
+		trs := make([]*TeamRepo, 0, len(repos))
+		if err := e.In("team_id", teamIDs).In("repo_id", repoIDs).Find(&trs); err != nil {
+			return nil, 0, err
+		}
+		for _, tr := range trs {
+			if teamRepos[tr.RepoID] == nil {
+				teamRepos[tr.RepoID] = make(map[int64]bool)
+			}
+			teamRepos[tr.RepoID][tr.TeamID] = true
+		}
+	}
+
+	collaborations := make([]*Collaboration, 0, len(repos))
+	if err := e.Where("user_id = ?", userID).In("repo_id", repoIDs).Find(&collaborations); err != nil {
+		return nil, 0, err
+	}
+	collaborationModes := make(map[int64]AccessMode, len(collaborations))
+	for _, c := range collaborations {
+		collaborationModes[c.RepoID] = c.Mode
+	}
+
+	accessible := make([]*AccessibleRepo, 0, len(repos))
+	for _, repo := range repos {
+		ar := &AccessibleRepo{Repository: repo, Mode: modes[repo.ID]}
+		for _, t := range teams {
+			if t.IsOwnerTeam() {
+				ar.Paths = append(ar.Paths, &RepoAccessPath{Team: t, Mode: AccessModeOwner})
+			} else if teamRepos[repo.ID][t.ID] {
+				ar.Paths = append(ar.Paths, &RepoAccessPath{Team: t, Mode: t.Authorize})
+			}
+		}
+		if mode, ok := collaborationModes[repo.ID]; ok {
+			ar.Paths = append(ar.Paths, &RepoAccessPath{Mode: mode})
+		}
+		accessible = append(accessible, ar)
+	}
+	return accessible, count, nil
+}
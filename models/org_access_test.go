@@ -0,0 +1,77 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetTeamAccessibleRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// the owners team has access to all the repositories of the organization
+	owners := db.AssertExistsAndLoadBean(t, &Team{ID: 1}).(*Team)
+	repos, count, err := GetTeamAccessibleRepos(owners, db.ListOptions{Page: 1, PageSize: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	if assert.Len(t, repos, 2) {
+		// ordered by name, repository 32 is named repo21
+		assert.EqualValues(t, 32, repos[0].ID)
+		assert.EqualValues(t, 3, repos[1].ID)
+		assert.Equal(t, AccessModeOwner, repos[0].Mode)
+		assert.Equal(t, []*RepoAccessPath{{Team: owners, Mode: AccessModeOwner}}, repos[0].Paths)
+	}
+
+	team := db.AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+	repos, count, err = GetTeamAccessibleRepos(team, db.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 3, repos[0].ID)
+		assert.Equal(t, AccessModeWrite, repos[0].Mode)
+	}
+}
+
+func TestGetUserAccessibleOrgRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repos, count, err := GetUserAccessibleOrgRepos(3, 2, db.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	if assert.Len(t, repos, 3) {
+		assert.EqualValues(t, 3, repos[1].ID)
+		assert.Equal(t, AccessModeOwner, repos[1].Mode)
+		// user 2 is in the owners team and team1, and collaborates on repository 3
+		if assert.Len(t, repos[1].Paths, 3) {
+			assert.EqualValues(t, 1, repos[1].Paths[0].Team.ID)
+			assert.Equal(t, AccessModeOwner, repos[1].Paths[0].Mode)
+			assert.EqualValues(t, 2, repos[1].Paths[1].Team.ID)
+			assert.Equal(t, AccessModeWrite, repos[1].Paths[1].Mode)
+			assert.Nil(t, repos[1].Paths[2].Team)
+			assert.Equal(t, AccessModeWrite, repos[1].Paths[2].Mode)
+		}
+	}
+
+	repos, count, err = GetUserAccessibleOrgRepos(3, 4, db.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 3, repos[0].ID)
+		assert.Equal(t, AccessModeWrite, repos[0].Mode)
+		if assert.Len(t, repos[0].Paths, 1) {
+			assert.EqualValues(t, 2, repos[0].Paths[0].Team.ID)
+		}
+	}
+
+	// user 5 has no access to the repositories of the organization
+	repos, count, err = GetUserAccessibleOrgRepos(3, 5, db.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	assert.Empty(t, repos)
+}
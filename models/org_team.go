@@ -21,6 +21,24 @@ import (
 
 const ownerTeamName = "Owners"
 
+const (
+	// TeamReviewNotificationEmailAll indicates that every member of the team is emailed when the team is requested to review
+	TeamReviewNotificationEmailAll = "email_all"
+	// TeamReviewNotificationEmailNone indicates that no member of the team is emailed when the team is requested to review
+	TeamReviewNotificationEmailNone = "email_none"
+	// TeamReviewNotificationWebhookOnly indicates that only the team's review webhook is called when the team is requested to review
+	TeamReviewNotificationWebhookOnly = "webhook_only"
+)
+
+// IsValidTeamReviewNotification checks if the given string is a valid team review notification preference
+func IsValidTeamReviewNotification(preference string) bool {
+	switch preference {
+	case TeamReviewNotificationEmailAll, TeamReviewNotificationEmailNone, TeamReviewNotificationWebhookOnly:
+		return true
+	}
+	return false
+}
+
 // Team represents a organization team.
 type Team struct {
 	ID                      int64 `xorm:"pk autoincr"`
@@ -36,6 +54,7 @@ type Team struct {
 	Units                   []*TeamUnit `xorm:"-"`
 	IncludesAllRepositories bool        `xorm:"NOT NULL DEFAULT false"`
 	CanCreateOrgRepo        bool        `xorm:"NOT NULL DEFAULT false"`
+	ReviewNotification      string      `xorm:"VARCHAR(20) NOT NULL DEFAULT 'email_all'"`
 }
 
 func init() {
@@ -140,6 +159,15 @@ func (t *Team) GetUnitNames() (res []string) {
 	return
 }
 
+// GetUnitsMap returns the team units names mapped to the access mode of the team to them
+func (t *Team) GetUnitsMap() map[string]string {
+	m := make(map[string]string, len(t.Units))
+	for _, u := range t.Units {
+		m[Units[u.Type].NameKey] = t.UnitAccessMode(u.Type).String()
+	}
+	return m
+}
+
 // HasWriteAccess returns true if team has at least write level access mode.
 func (t *Team) HasWriteAccess() bool {
 	return t.Authorize >= AccessModeWrite
@@ -452,6 +480,44 @@ func (t *Team) unitEnabled(e db.Engine, tp UnitType) bool {
 	return false
 }
 
+// UnitAccessMode returns the access mode of the team to the given unit type
+func (t *Team) UnitAccessMode(tp UnitType) AccessMode {
+	return t.unitAccessMode(db.GetEngine(db.DefaultContext), tp)
+}
+
+// UnitAccessModeOverride returns the access mode set for the unit type in the team,
+// none if the team's access mode applies to it
+func (t *Team) UnitAccessModeOverride(tp UnitType) AccessMode {
+	if err := t.GetUnits(); err != nil {
+		log.Warn("Error loading team (ID: %d) units: %s", t.ID, err.Error())
+	}
+
+	for _, unit := range t.Units {
+		if unit.Type == tp {
+			return unit.AccessMode
+		}
+	}
+	return AccessModeNone
+}
+
+// unitAccessMode returns the access mode of the team to the unit type. Units can only be given
+// their own access mode in read and write teams, administrators have the same access to all units.
+func (t *Team) unitAccessMode(e db.Engine, tp UnitType) AccessMode {
+	if err := t.getUnits(e); err != nil {
+		log.Warn("Error loading team (ID: %d) units: %s", t.ID, err.Error())
+	}
+
+	for _, unit := range t.Units {
+		if unit.Type == tp {
+			if unit.AccessMode == AccessModeNone || t.Authorize >= AccessModeAdmin {
+				return t.Authorize
+			}
+			return unit.AccessMode
+		}
+	}
+	return AccessModeNone
+}
+
 // IsUsableTeamName tests if a name could be as team name
 func IsUsableTeamName(name string) error {
 	switch name {
@@ -481,6 +547,10 @@ func NewTeam(t *Team) (err error) {
 		return ErrOrgNotExist{t.OrgID, ""}
 	}
 
+	if len(t.ReviewNotification) == 0 {
+		t.ReviewNotification = TeamReviewNotificationEmailAll
+	}
+
 	t.LowerName = strings.ToLower(t.Name)
 	has, err = db.GetEngine(db.DefaultContext).
 		Where("org_id=?", t.OrgID).
@@ -642,7 +712,7 @@ func UpdateTeam(t *Team, authChanged, includeAllChanged bool) (err error) {
 	}
 
 	if _, err = sess.ID(t.ID).Cols("name", "lower_name", "description",
-		"can_create_org_repo", "authorize", "includes_all_repositories").Update(t); err != nil {
+		"can_create_org_repo", "authorize", "includes_all_repositories", "review_notification").Update(t); err != nil {
 		return fmt.Errorf("update: %v", err)
 	}
 
@@ -657,7 +727,7 @@ func UpdateTeam(t *Team, authChanged, includeAllChanged bool) (err error) {
 			Delete(new(TeamUnit)); err != nil {
 			return err
 		}
-		if _, err = sess.Cols("org_id", "team_id", "type").Insert(&t.Units); err != nil {
+		if _, err = sess.Cols("org_id", "team_id", "type", "access_mode").Insert(&t.Units); err != nil {
 			errRollback := sess.Rollback()
 			if errRollback != nil {
 				log.Error("UpdateTeam sess.Rollback: %v", errRollback)
@@ -726,6 +796,11 @@ func DeleteTeam(t *Team) error {
 		return err
 	}
 
+	// Delete team review webhook.
+	if err := deleteWebhooksByTeamID(sess, t.ID); err != nil {
+		return err
+	}
+
 	// Delete team.
 	if _, err := sess.ID(t.ID).Delete(new(Team)); err != nil {
 		return err
@@ -944,6 +1019,143 @@ func RemoveTeamMember(team *Team, userID int64) error {
 	return sess.Commit()
 }
 
+// AddTeamMembers adds the given users to the team at once. Unlike adding them one by one
+// accesses to the team repositories are recalculated only once per repository.
+func AddTeamMembers(team *Team, userIDs []int64) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	added := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		isMember, err := isTeamMember(sess, team.OrgID, team.ID, userID)
+		if err != nil {
+			return err
+		} else if isMember {
+			continue
+		}
+
+		isOrgMember, err := isOrganizationMember(sess, team.OrgID, userID)
+		if err != nil {
+			return err
+		} else if !isOrgMember {
+			if err := addOrgUser(sess, team.OrgID, userID); err != nil {
+				return err
+			}
+		}
+
+		if _, err := sess.Insert(&TeamUser{
+			UID:    userID,
+			OrgID:  team.OrgID,
+			TeamID: team.ID,
+		}); err != nil {
+			return err
+		}
+		added = append(added, userID)
+	}
+	if len(added) == 0 {
+		return sess.Commit()
+	}
+
+	if _, err := sess.Incr("num_members", len(added)).ID(team.ID).Update(new(Team)); err != nil {
+		return err
+	}
+	team.NumMembers += len(added)
+
+	// Give access to team repositories.
+	if err := team.getRepositories(sess); err != nil {
+		return err
+	}
+	for _, repo := range team.Repos {
+		if err := repo.recalculateTeamAccesses(sess, 0); err != nil {
+			return err
+		}
+		if setting.Service.AutoWatchNewRepos {
+			for _, userID := range added {
+				if err := watchRepo(sess, userID, repo.ID, true); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return sess.Commit()
+}
+
+// RemoveTeamMembers removes the given users from the team at once. Unlike removing them one by one
+// accesses to the team repositories are recalculated only once per repository.
+func RemoveTeamMembers(team *Team, userIDs []int64) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	removed := make([]int64, 0, len(userIDs))
+	for _, userID := range userIDs {
+		isMember, err := isTeamMember(sess, team.OrgID, team.ID, userID)
+		if err != nil {
+			return err
+		} else if isMember {
+			removed = append(removed, userID)
+		}
+	}
+	if len(removed) == 0 {
+		return sess.Commit()
+	}
+
+	// Check if the users to delete are the last members in owner team.
+	if team.IsOwnerTeam() && team.NumMembers <= len(removed) {
+		return ErrLastOrgOwner{UID: removed[0]}
+	}
+
+	if _, err := sess.Where("team_id = ?", team.ID).In("uid", removed).Delete(new(TeamUser)); err != nil {
+		return err
+	}
+	team.NumMembers -= len(removed)
+	if _, err := sess.ID(team.ID).Cols("num_members").Update(team); err != nil {
+		return err
+	}
+
+	// Delete access to team repositories.
+	if err := team.getRepositories(sess); err != nil {
+		return err
+	}
+	for _, repo := range team.Repos {
+		if err := repo.recalculateTeamAccesses(sess, 0); err != nil {
+			return err
+		}
+		for _, userID := range removed {
+			// Remove watches from now unaccessible
+			if err := repo.reconsiderWatches(sess, userID); err != nil {
+				return err
+			}
+			// Remove issue assignments from now unaccessible
+			if err := repo.reconsiderIssueAssignees(sess, userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Remove the users from the organization if they are not a member of any other team.
+	for _, userID := range removed {
+		if count, err := sess.Count(&TeamUser{
+			UID:   userID,
+			OrgID: team.OrgID,
+		}); err != nil {
+			return err
+		} else if count == 0 {
+			if err := removeOrgUser(sess, team.OrgID, userID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return sess.Commit()
+}
+
 // IsUserInTeams returns if a user in some teams
 func IsUserInTeams(userID int64, teamIDs []int64) (bool, error) {
 	return isUserInTeams(db.GetEngine(db.DefaultContext), userID, teamIDs)
@@ -1033,6 +1245,8 @@ type TeamUnit struct {
 	OrgID  int64    `xorm:"INDEX"`
 	TeamID int64    `xorm:"UNIQUE(s)"`
 	Type   UnitType `xorm:"UNIQUE(s)"`
+	// AccessMode overrides the access mode of the team to the unit, none means the team's access mode
+	AccessMode AccessMode `xorm:"NOT NULL DEFAULT 0"`
 }
 
 // Unit returns Unit
@@ -346,6 +346,47 @@ func TestRemoveTeamMember(t *testing.T) {
 	assert.True(t, IsErrLastOrgOwner(err))
 }
 
+func TestAddTeamMembers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	team := db.AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+	numMembers := team.NumMembers
+	assert.NoError(t, AddTeamMembers(team, []int64{2, 5, 8}))
+	assert.Equal(t, numMembers+2, team.NumMembers)
+	CheckConsistencyFor(t, &Team{ID: team.ID}, &User{ID: team.OrgID})
+
+	assert.NoError(t, team.GetRepositories(&SearchTeamOptions{}))
+	for _, userID := range []int64{5, 8} {
+		db.AssertExistsAndLoadBean(t, &TeamUser{UID: userID, TeamID: team.ID})
+		db.AssertExistsAndLoadBean(t, &OrgUser{UID: userID, OrgID: team.OrgID})
+		user := db.AssertExistsAndLoadBean(t, &User{ID: userID}).(*User)
+		for _, repo := range team.Repos {
+			mode, err := AccessLevel(user, repo)
+			assert.NoError(t, err)
+			assert.GreaterOrEqual(t, int(mode), int(team.Authorize))
+		}
+	}
+}
+
+func TestRemoveTeamMembers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	team := db.AssertExistsAndLoadBean(t, &Team{ID: 2}).(*Team)
+	assert.NoError(t, AddTeamMembers(team, []int64{5}))
+	assert.NoError(t, RemoveTeamMembers(team, []int64{4, 5, db.NonexistentID}))
+	CheckConsistencyFor(t, &Team{ID: team.ID}, &User{ID: team.OrgID})
+	db.AssertNotExistsBean(t, &TeamUser{UID: 4, TeamID: team.ID})
+	db.AssertNotExistsBean(t, &TeamUser{UID: 5, TeamID: team.ID})
+	// the users are not a member of any other team of the organization
+	db.AssertNotExistsBean(t, &OrgUser{UID: 4, OrgID: team.OrgID})
+	db.AssertNotExistsBean(t, &OrgUser{UID: 5, OrgID: team.OrgID})
+
+	team = db.AssertExistsAndLoadBean(t, &Team{ID: 1}).(*Team)
+	err := RemoveTeamMembers(team, []int64{2, 4})
+	assert.True(t, IsErrLastOrgOwner(err))
+	db.AssertExistsAndLoadBean(t, &TeamUser{UID: 2, TeamID: team.ID})
+}
+
 func TestHasTeamRepo(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
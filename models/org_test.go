@@ -559,8 +559,11 @@ func TestAccessibleReposEnv_MirrorRepos(t *testing.T) {
 	testSuccess := func(userID int64, expectedRepoIDs []int64) {
 		env, err := org.AccessibleReposEnv(userID)
 		assert.NoError(t, err)
-		repos, err := env.MirrorRepos()
+		repos, err := env.MirrorRepos(1, 100)
 		assert.NoError(t, err)
+		count, err := env.CountMirrorRepos()
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(expectedRepoIDs), count)
 		expectedRepos := make([]*Repository, len(expectedRepoIDs))
 		for i, repoID := range expectedRepoIDs {
 			expectedRepos[i] = db.AssertExistsAndLoadBean(t,
@@ -658,3 +661,38 @@ func TestGetUsersWhoCanCreateOrgRepo(t *testing.T) {
 	assert.Len(t, users, 1)
 	assert.EqualValues(t, 5, users[0].ID)
 }
+
+func TestRequirePrivateRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	admin := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	org := db.AssertExistsAndLoadBean(t, &User{ID: 3}).(*User)
+	assert.NoError(t, CheckPublicRepoAllowed(user2, org, false))
+
+	org.RequirePrivateRepos = true
+	assert.NoError(t, UpdateUserCols(org, "require_private_repos"))
+	assert.True(t, IsErrPublicRepoNotAllowed(CheckPublicRepoAllowed(user2, org, false)))
+	assert.True(t, IsErrPublicRepoNotAllowed(CheckPublicRepoAllowed(user2, org, true)))
+	assert.True(t, IsErrPublicRepoNotAllowed(CheckPublicRepoAllowed(admin, org, false)))
+	assert.NoError(t, CheckPublicRepoAllowed(admin, org, true))
+	assert.NoError(t, CheckPublicRepoAllowed(user2, user2, false))
+
+	// the public repository from before the policy is kept and listed
+	repos, count, err := GetPublicOrgRepositories(org.ID, db.ListOptions{Page: 1, PageSize: 10})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 32, repos[0].ID)
+		assert.NoError(t, UpdateRepository(repos[0], true))
+	}
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	repo.IsPrivate = false
+	assert.True(t, IsErrPublicRepoNotAllowed(UpdateRepository(repo, true)))
+	db.AssertExistsAndLoadBean(t, &Repository{ID: 3, IsPrivate: true})
+
+	assert.NoError(t, UpdateRepositoryOverridingVisibilityPolicy(repo, true))
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	assert.False(t, repo.IsPrivate)
+}
@@ -72,10 +72,14 @@ type PullRequest struct {
 	HeadCommitID    string `xorm:"-"`
 	BaseBranch      string
 	ProtectedBranch *ProtectedBranch `xorm:"-"`
-	MergeBase       string           `xorm:"VARCHAR(40)"`
+	MergeBase       string           `xorm:"VARCHAR(64)"`
+	// HeadRefCommitID is the commit the head ref pointed at when it was cleaned up
+	HeadRefCommitID string `xorm:"VARCHAR(64)"`
+
+	IsDraft bool `xorm:"INDEX NOT NULL DEFAULT false"`
 
 	HasMerged      bool               `xorm:"INDEX"`
-	MergedCommitID string             `xorm:"VARCHAR(40)"`
+	MergedCommitID string             `xorm:"VARCHAR(64)"`
 	MergerID       int64              `xorm:"INDEX"`
 	Merger         *User              `xorm:"-"`
 	MergedUnix     timeutil.TimeStamp `xorm:"updated INDEX"`
@@ -382,6 +386,32 @@ const (
 	MergeStyleRebaseUpdate MergeStyle = "rebase-update-only"
 )
 
+// MergeCommitAuthorStyle represents who authors the commit created by merging a pull request,
+// the other one of the merger and the pull request author is the committer
+type MergeCommitAuthorStyle string
+
+const (
+	// MergeCommitAuthorDefault merge commits are authored by the merger, squashed commits by the pull request author
+	MergeCommitAuthorDefault MergeCommitAuthorStyle = ""
+	// MergeCommitAuthorMerger merge and squashed commits are authored by the merger
+	MergeCommitAuthorMerger MergeCommitAuthorStyle = "merger"
+	// MergeCommitAuthorPRAuthor merge and squashed commits are authored by the pull request author
+	MergeCommitAuthorPRAuthor MergeCommitAuthorStyle = "pr-author"
+)
+
+// IsValid returns whether the merge commit author style is known
+func (style MergeCommitAuthorStyle) IsValid() bool {
+	return style == MergeCommitAuthorDefault || style == MergeCommitAuthorMerger || style == MergeCommitAuthorPRAuthor
+}
+
+// IsPRAuthor returns whether the commit created by merging a pull request with the merge style is authored by the pull request author
+func (style MergeCommitAuthorStyle) IsPRAuthor(mergeStyle MergeStyle) bool {
+	if style == MergeCommitAuthorDefault {
+		return mergeStyle == MergeStyleSquash
+	}
+	return style == MergeCommitAuthorPRAuthor
+}
+
 // SetMerged sets a pull request to merged and closes the corresponding issue
 func (pr *PullRequest) SetMerged() (bool, error) {
 	if pr.HasMerged {
@@ -477,6 +507,9 @@ func NewPullRequest(repo *Repository, issue *Issue, labelIDs []int64, uuids []st
 	pr.Index = issue.Index
 	pr.BaseRepo = repo
 	pr.IssueID = issue.ID
+	if HasWorkInProgressPrefix(issue.Title) {
+		pr.IsDraft = true
+	}
 	if _, err = sess.Insert(pr); err != nil {
 		return fmt.Errorf("insert pull repo: %v", err)
 	}
@@ -506,6 +539,39 @@ func GetUnmergedPullRequest(headRepoID, baseRepoID int64, headBranch, baseBranch
 	return pr, nil
 }
 
+// GetClosedPullRequestIndexes returns the indexes of the github flow pull requests of a base repository
+// which have been closed or merged before the given time.
+func GetClosedPullRequestIndexes(baseRepoID int64, closedBefore timeutil.TimeStamp) ([]int64, error) {
+	indexes := make([]int64, 0, 10)
+	return indexes, db.GetEngine(db.DefaultContext).
+		Table("pull_request").
+		Join("INNER", "issue", "issue.id=pull_request.issue_id").
+		Where("pull_request.base_repo_id=? AND pull_request.flow=? AND issue.is_closed=? AND issue.closed_unix<?",
+			baseRepoID, PullRequestFlowGithub, true, closedBefore).
+		Cols("pull_request.`index`").
+		Find(&indexes)
+}
+
+// SetPullRequestHeadRefCommitIDs records the commits the head refs of pull requests of a base repository,
+// identified by their indexes, pointed at before they are cleaned up.
+func SetPullRequestHeadRefCommitIDs(baseRepoID int64, commitIDs map[int64]string) error {
+	ctx, committer, err := db.TxContext()
+	if err != nil {
+		return err
+	}
+	defer committer.Close()
+
+	sess := db.GetEngine(ctx)
+	for index, commitID := range commitIDs {
+		if _, err := sess.Where("base_repo_id=? AND `index`=?", baseRepoID, index).
+			Cols("head_ref_commit_id").NoAutoTime().
+			Update(&PullRequest{HeadRefCommitID: commitID}); err != nil {
+			return err
+		}
+	}
+	return committer.Commit()
+}
+
 // GetLatestPullRequestByHeadInfo returns the latest pull request (regardless of its status)
 // by given head information (repo and branch).
 func GetLatestPullRequestByHeadInfo(repoID int64, branch string) (*PullRequest, error) {
@@ -626,8 +692,11 @@ func (pr *PullRequest) UpdateColsIfNotMerged(cols ...string) error {
 	return err
 }
 
-// IsWorkInProgress determine if the Pull Request is a Work In Progress by its title
+// IsWorkInProgress determine if the Pull Request is a draft or a Work In Progress by its title
 func (pr *PullRequest) IsWorkInProgress() bool {
+	if pr.IsDraft {
+		return true
+	}
 	if err := pr.LoadIssue(); err != nil {
 		log.Error("LoadIssue: %v", err)
 		return false
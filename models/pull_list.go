@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/xorm"
 )
@@ -22,6 +23,7 @@ type PullRequestsOptions struct {
 	SortType    string
 	Labels      []string
 	MilestoneID int64
+	IsDraft     util.OptionalBool
 }
 
 func listPullRequestStatement(baseRepoID int64, opts *PullRequestsOptions) (*xorm.Session, error) {
@@ -44,6 +46,10 @@ func listPullRequestStatement(baseRepoID int64, opts *PullRequestsOptions) (*xor
 		sess.And("issue.milestone_id=?", opts.MilestoneID)
 	}
 
+	if !opts.IsDraft.IsNone() {
+		sess.And("pull_request.is_draft=?", opts.IsDraft.IsTrue())
+	}
+
 	return sess, nil
 }
 
@@ -69,6 +75,16 @@ func GetUnmergedPullRequestsByBaseInfo(repoID int64, branch string) ([]*PullRequ
 		Find(&prs)
 }
 
+// CountUnmergedPullRequestsFromFork returns the number of pull requests from a fork to its base repository
+// that are open and have not been merged.
+func CountUnmergedPullRequestsFromFork(forkID, baseRepoID int64) (int64, error) {
+	return db.GetEngine(db.DefaultContext).
+		Where("head_repo_id=? AND base_repo_id=? AND has_merged=? AND issue.is_closed=?",
+			forkID, baseRepoID, false, false).
+		Join("INNER", "issue", "issue.id=pull_request.issue_id").
+		Count(new(PullRequest))
+}
+
 // GetPullRequestIDsByCheckStatus returns all pull requests according the special checking status.
 func GetPullRequestIDsByCheckStatus(status PullRequestStatus) ([]int64, error) {
 	prs := make([]int64, 0, 10)
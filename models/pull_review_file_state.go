@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// PullReviewFileState represents a file of a pull request a user marked as viewed
+type PullReviewFileState struct {
+	ID       int64  `xorm:"pk autoincr"`
+	RepoID   int64  `xorm:"INDEX NOT NULL"`
+	PullID   int64  `xorm:"INDEX(s) NOT NULL"`
+	UserID   int64  `xorm:"INDEX(s) NOT NULL"`
+	TreePath string `xorm:"TEXT NOT NULL"`
+	// CommitSHA is the head commit of the pull request when the file was marked as viewed
+	CommitSHA   string             `xorm:"VARCHAR(40) NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(PullReviewFileState))
+}
+
+// GetPullReviewFileStates returns the files of a pull request a user marked as viewed, ordered by path
+func GetPullReviewFileStates(pullID, userID int64) ([]*PullReviewFileState, error) {
+	return getPullReviewFileStates(db.GetEngine(db.DefaultContext), pullID, userID)
+}
+
+func getPullReviewFileStates(e db.Engine, pullID, userID int64) ([]*PullReviewFileState, error) {
+	states := make([]*PullReviewFileState, 0, 10)
+	return states, e.Where("pull_id = ? AND user_id = ?", pullID, userID).
+		Asc("tree_path").
+		Find(&states)
+}
+
+// UpdatePullReviewFileStates marks the files of a pull request as viewed at a head commit or unmarks them
+func UpdatePullReviewFileStates(pr *PullRequest, userID int64, commitSHA string, viewed map[string]bool) error {
+	return db.WithTx(func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+		states, err := getPullReviewFileStates(e, pr.ID, userID)
+		if err != nil {
+			return err
+		}
+		existing := make(map[string]*PullReviewFileState, len(states))
+		for _, state := range states {
+			existing[state.TreePath] = state
+		}
+
+		for treePath, isViewed := range viewed {
+			state, has := existing[treePath]
+			switch {
+			case !isViewed && has:
+				if _, err := e.ID(state.ID).Delete(new(PullReviewFileState)); err != nil {
+					return err
+				}
+			case isViewed && has:
+				state.CommitSHA = commitSHA
+				if _, err := e.ID(state.ID).Cols("commit_sha").Update(state); err != nil {
+					return err
+				}
+			case isViewed:
+				if _, err := e.Insert(&PullReviewFileState{
+					RepoID:    pr.BaseRepoID,
+					PullID:    pr.ID,
+					UserID:    userID,
+					TreePath:  treePath,
+					CommitSHA: commitSHA,
+				}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdatePullReviewFileStates(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	pr := db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+
+	assert.NoError(t, UpdatePullReviewFileStates(pr, 2, "1111111111111111111111111111111111111111", map[string]bool{
+		"README.md": true,
+		"main.go":   true,
+		"other.go":  false,
+	}))
+	states, err := GetPullReviewFileStates(pr.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, states, 2) {
+		assert.Equal(t, "README.md", states[0].TreePath)
+		assert.Equal(t, "main.go", states[1].TreePath)
+		assert.Equal(t, pr.BaseRepoID, states[0].RepoID)
+	}
+
+	assert.NoError(t, UpdatePullReviewFileStates(pr, 2, "2222222222222222222222222222222222222222", map[string]bool{
+		"README.md": false,
+		"main.go":   true,
+	}))
+	states, err = GetPullReviewFileStates(pr.ID, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, states, 1) {
+		assert.Equal(t, "main.go", states[0].TreePath)
+		assert.Equal(t, "2222222222222222222222222222222222222222", states[0].CommitSHA)
+	}
+
+	// the states are per user
+	states, err = GetPullReviewFileStates(pr.ID, 1)
+	assert.NoError(t, err)
+	assert.Empty(t, states)
+}
@@ -8,6 +8,9 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -91,6 +94,30 @@ func TestPullRequestsOldest(t *testing.T) {
 	}
 }
 
+func TestPullRequestsDraft(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	pr := db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr.IsDraft = true
+	assert.NoError(t, pr.UpdateCols("is_draft"))
+
+	prs, count, err := PullRequests(1, &PullRequestsOptions{
+		State:   "open",
+		IsDraft: util.OptionalBoolTrue,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, prs, 1) {
+		assert.EqualValues(t, 2, prs[0].ID)
+	}
+
+	_, count, err = PullRequests(1, &PullRequestsOptions{
+		State:   "open",
+		IsDraft: util.OptionalBoolFalse,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+}
+
 func TestGetUnmergedPullRequest(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	pr, err := GetUnmergedPullRequest(1, 1, "branch2", "master", PullRequestFlowGithub)
@@ -113,6 +140,17 @@ func TestGetUnmergedPullRequestsByHeadInfo(t *testing.T) {
 	}
 }
 
+func TestCountUnmergedPullRequestsFromFork(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	count, err := CountUnmergedPullRequestsFromFork(11, 10)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	count, err = CountUnmergedPullRequestsFromFork(10, 11)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
 func TestGetUnmergedPullRequestsByBaseInfo(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	prs, err := GetUnmergedPullRequestsByBaseInfo(1, "master")
@@ -224,6 +262,30 @@ func TestPullRequest_IsWorkInProgress(t *testing.T) {
 	assert.True(t, pr.IsWorkInProgress())
 }
 
+func TestPullRequest_IsWorkInProgress_Draft(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	pr := db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	pr.LoadIssue()
+	pr.IsDraft = true
+	assert.True(t, pr.IsWorkInProgress())
+
+	// changing the title syncs the flag
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	issue := pr.Issue
+	issue.PullRequest = pr
+	oldTitle := issue.Title
+	issue.Title = "WIP: " + oldTitle
+	assert.NoError(t, issue.ChangeTitle(doer, oldTitle))
+	db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2, IsDraft: true})
+
+	issue.Title = oldTitle
+	assert.NoError(t, issue.ChangeTitle(doer, "WIP: "+oldTitle))
+	assert.False(t, pr.IsDraft)
+	assert.False(t, pr.IsWorkInProgress())
+	db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2}, db.Cond("is_draft = ?", false))
+}
+
 func TestPullRequest_GetWorkInProgressPrefixWorkInProgress(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -272,3 +334,46 @@ func TestPullRequest_GetDefaultMergeMessage_ExternalTracker(t *testing.T) {
 	pr.HeadRepoID = 2
 	assert.Equal(t, "Merge pull request 'issue3' (!3) from user2/repo1:branch2 into master", pr.GetDefaultMergeMessage())
 }
+
+func TestGetClosedPullRequestIndexes(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	indexes, err := GetClosedPullRequestIndexes(1, timeutil.TimeStampNow())
+	assert.NoError(t, err)
+	assert.Empty(t, indexes)
+
+	_, err = db.GetEngine(db.DefaultContext).ID(2).Cols("is_closed", "closed_unix").
+		Update(&Issue{IsClosed: true, ClosedUnix: 1000})
+	assert.NoError(t, err)
+
+	indexes, err = GetClosedPullRequestIndexes(1, timeutil.TimeStampNow())
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2}, indexes)
+
+	indexes, err = GetClosedPullRequestIndexes(1, 1000)
+	assert.NoError(t, err)
+	assert.Empty(t, indexes)
+}
+
+func TestSetPullRequestHeadRefCommitIDs(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.NoError(t, SetPullRequestHeadRefCommitIDs(1, map[int64]string{2: "4a357436d925b5c974181ff12a994538ddc5a269"}))
+	pr := db.AssertExistsAndLoadBean(t, &PullRequest{BaseRepoID: 1, Index: 2}).(*PullRequest)
+	assert.Equal(t, "4a357436d925b5c974181ff12a994538ddc5a269", pr.HeadRefCommitID)
+	pr = db.AssertExistsAndLoadBean(t, &PullRequest{ID: 2}).(*PullRequest)
+	assert.Empty(t, pr.HeadRefCommitID)
+}
+
+func TestMergeCommitAuthorStyle_IsPRAuthor(t *testing.T) {
+	assert.False(t, MergeCommitAuthorDefault.IsPRAuthor(MergeStyleMerge))
+	assert.True(t, MergeCommitAuthorDefault.IsPRAuthor(MergeStyleSquash))
+	assert.False(t, MergeCommitAuthorMerger.IsPRAuthor(MergeStyleRebaseMerge))
+	assert.False(t, MergeCommitAuthorMerger.IsPRAuthor(MergeStyleSquash))
+	assert.True(t, MergeCommitAuthorPRAuthor.IsPRAuthor(MergeStyleMerge))
+	assert.True(t, MergeCommitAuthorPRAuthor.IsPRAuthor(MergeStyleSquash))
+
+	assert.True(t, MergeCommitAuthorDefault.IsValid())
+	assert.True(t, MergeCommitAuthorPRAuthor.IsValid())
+	assert.False(t, MergeCommitAuthorStyle("committer").IsValid())
+}
@@ -20,9 +20,10 @@ import (
 	"strconv"
 	"strings"
 	"time"
-	"unicode/utf8"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/emoji"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
@@ -184,6 +185,29 @@ func ToTrustModel(model string) TrustModelType {
 	return DefaultTrustModel
 }
 
+// RepoCreatedFrom represents how a repository came to exist
+type RepoCreatedFrom string
+
+// all kinds of RepoCreatedFrom
+const (
+	RepoCreatedFromCreated   RepoCreatedFrom = "created"          // created empty or initialized by Gitea
+	RepoCreatedFromMigrated  RepoCreatedFrom = "migrated"         // migrated or mirrored from another service
+	RepoCreatedFromForked    RepoCreatedFrom = "forked"           // forked from another repository
+	RepoCreatedFromGenerated RepoCreatedFrom = "generated"        // generated from a template repository
+	RepoCreatedFromAdopted   RepoCreatedFrom = "adopted"          // adopted from a git repository already on disk
+	RepoCreatedFromPushed    RepoCreatedFrom = "pushed-to-create" // created by the first push to it
+)
+
+// ToRepoCreatedFrom returns the kind of repository creation named s
+func ToRepoCreatedFrom(s string) (RepoCreatedFrom, bool) {
+	switch createdFrom := RepoCreatedFrom(strings.ToLower(strings.TrimSpace(s))); createdFrom {
+	case RepoCreatedFromCreated, RepoCreatedFromMigrated, RepoCreatedFromForked,
+		RepoCreatedFromGenerated, RepoCreatedFromAdopted, RepoCreatedFromPushed:
+		return createdFrom, true
+	}
+	return "", false
+}
+
 // Repository represents a git repository.
 type Repository struct {
 	ID                  int64 `xorm:"pk autoincr"`
@@ -236,17 +260,29 @@ type Repository struct {
 	Size                            int64              `xorm:"NOT NULL DEFAULT 0"`
 	CodeIndexerStatus               *RepoIndexerStatus `xorm:"-"`
 	StatsIndexerStatus              *RepoIndexerStatus `xorm:"-"`
+	GitSettings                     *RepoGitSettings   `xorm:"-"`
 	IsFsckEnabled                   bool               `xorm:"NOT NULL DEFAULT true"`
+	IsBackupEnabled                 bool               `xorm:"NOT NULL DEFAULT true"`
 	CloseIssuesViaCommitInAnyBranch bool               `xorm:"NOT NULL DEFAULT false"`
 	Topics                          []string           `xorm:"TEXT JSON"`
 
 	TrustModel TrustModelType
 
+	// ObjectFormat is the hash algorithm of the git repository, it can't be changed after creation
+	ObjectFormat git.ObjectFormat `xorm:"VARCHAR(10) NOT NULL DEFAULT 'sha1'"`
+
+	// CreatedFrom is how the repository came to exist, the origin is kept in ForkID, TemplateID or OriginalURL
+	CreatedFrom RepoCreatedFrom `xorm:"VARCHAR(20) INDEX NOT NULL DEFAULT 'created'"`
+
 	// Avatar: ID(10-20)-md5(32) - must fit into 64 symbols
 	Avatar string `xorm:"VARCHAR(64)"`
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	// PushedUnix is the time of the last push to a branch or tag, 0 if the repository has never been pushed to
+	PushedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	// SizeUpdatedUnix is the time the size was last calculated, 0 if it has never been
+	SizeUpdatedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 }
 
 func init() {
@@ -284,6 +320,15 @@ func (repo *Repository) IsBeingCreated() bool {
 	return repo.IsBeingMigrated()
 }
 
+// CheckNotArchived returns an ErrRepoIsArchived if the repository is archived
+// and therefore must not be changed.
+func (repo *Repository) CheckNotArchived() error {
+	if repo.IsArchived {
+		return ErrRepoIsArchived{Repo: repo}
+	}
+	return nil
+}
+
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (repo *Repository) AfterLoad() {
 	// FIXME: use models migration to solve all at once.
@@ -318,7 +363,7 @@ func (repo *Repository) HTMLURL() string {
 // CommitLink make link to by commit full ID
 // note: won't check whether it's an right id
 func (repo *Repository) CommitLink(commitID string) (result string) {
-	if commitID == "" || commitID == "0000000000000000000000000000000000000000" {
+	if git.IsEmptyCommitID(commitID) {
 		result = ""
 	} else {
 		result = repo.HTMLURL() + "/commit/" + commitID
@@ -326,6 +371,20 @@ func (repo *Repository) CommitLink(commitID string) (result string) {
 	return
 }
 
+// CheckSameObjectFormat returns ErrObjectFormatMismatch if the repositories use different object formats,
+// as commits can't be exchanged between them.
+func CheckSameObjectFormat(base, head *Repository) error {
+	if base.ObjectFormat.Name() != head.ObjectFormat.Name() {
+		return ErrObjectFormatMismatch{
+			BaseRepoName: base.FullName(),
+			BaseFormat:   base.ObjectFormat.Name(),
+			HeadRepoName: head.FullName(),
+			HeadFormat:   head.ObjectFormat.Name(),
+		}
+	}
+	return nil
+}
+
 // APIURL returns the repository API URL
 func (repo *Repository) APIURL() string {
 	return setting.AppURL + "api/v1/repos/" + repo.FullName()
@@ -490,6 +549,9 @@ func (repo *Repository) ComposeMetas() map[string]string {
 			switch unit.ExternalTrackerConfig().ExternalTrackerStyle {
 			case markup.IssueNameStyleAlphanumeric:
 				metas["style"] = markup.IssueNameStyleAlphanumeric
+			case markup.IssueNameStyleRegexp:
+				metas["style"] = markup.IssueNameStyleRegexp
+				metas["regexp"] = unit.ExternalTrackerConfig().ExternalTrackerRegexpPattern
 			default:
 				metas["style"] = markup.IssueNameStyleNumeric
 			}
@@ -733,23 +795,38 @@ func (repo *Repository) IsOwnedBy(userID int64) bool {
 	return repo.OwnerID == userID
 }
 
-func (repo *Repository) updateSize(e db.Engine) error {
+func (repo *Repository) computeSize(e db.Engine) (int64, error) {
 	size, err := util.GetDirectorySize(repo.RepoPath())
 	if err != nil {
-		return fmt.Errorf("updateSize: %v", err)
+		return 0, fmt.Errorf("updateSize: %v", err)
 	}
 
 	lfsSize, err := e.Where("repository_id = ?", repo.ID).SumInt(new(LFSMetaObject), "size")
 	if err != nil {
-		return fmt.Errorf("updateSize: GetLFSMetaObjects: %v", err)
+		return 0, fmt.Errorf("updateSize: GetLFSMetaObjects: %v", err)
 	}
+	return size + lfsSize, nil
+}
 
-	repo.Size = size + lfsSize
-	_, err = e.ID(repo.ID).Cols("size").NoAutoTime().Update(repo)
+// ComputeSize returns the size of the repository and its LFS objects without updating it
+func (repo *Repository) ComputeSize() (int64, error) {
+	return repo.computeSize(db.GetEngine(db.DefaultContext))
+}
+
+func (repo *Repository) updateSize(e db.Engine) error {
+	size, err := repo.computeSize(e)
+	if err != nil {
+		return err
+	}
+
+	repo.Size = size
+	repo.SizeUpdatedUnix = timeutil.TimeStampNow()
+	_, err = e.ID(repo.ID).Cols("size", "size_updated_unix").NoAutoTime().Update(repo)
 	return err
 }
 
-// UpdateSize updates the repository size, calculating it using util.GetDirectorySize
+// UpdateSize updates the repository size, calculating it using util.GetDirectorySize.
+// It walks the whole repository, changes of the repository should queue the update instead.
 func (repo *Repository) UpdateSize(ctx context.Context) error {
 	return repo.updateSize(db.GetEngine(ctx))
 }
@@ -872,6 +949,11 @@ func (repo *Repository) DescriptionHTML() template.HTML {
 	return template.HTML(markup.Sanitize(string(desc)))
 }
 
+// DescriptionText returns the description as plain text with the emoji shortcodes replaced by the emoji.
+func (repo *Repository) DescriptionText() string {
+	return emoji.ReplaceAliases(repo.Description)
+}
+
 // ReadBy sets repo to be visited by given user.
 func (repo *Repository) ReadBy(userID int64) error {
 	return setRepoNotificationStatusReadIfUnread(db.GetEngine(db.DefaultContext), userID, repo.ID)
@@ -899,6 +981,20 @@ type CloneLink struct {
 	SSH   string
 	HTTPS string
 	Git   string
+	// filter spec recommended to clone the repository, empty for none
+	Filter string
+	// API endpoint creating the clone tokens HTTPSToken URLs are composed with, empty if there are none
+	TokenURL string
+}
+
+// HTTPSToken returns the HTTPS clone URL authenticating as the user with the clone token
+func (cl *CloneLink) HTTPSToken(username, token string) string {
+	u, err := url.Parse(cl.HTTPS)
+	if err != nil {
+		return cl.HTTPS
+	}
+	u.User = url.UserPassword(username, token)
+	return u.String()
 }
 
 // ComposeHTTPSCloneURL returns HTTPS clone URL based on given owner and repository name.
@@ -935,6 +1031,12 @@ func (repo *Repository) cloneLink(isWiki bool) *CloneLink {
 		cl.SSH = fmt.Sprintf("%s@%s:%s/%s.git", sshUser, sshDomain, repo.OwnerName, repoName)
 	}
 	cl.HTTPS = ComposeHTTPSCloneURL(repo.OwnerName, repoName)
+	if !isWiki && repo.GitSettings != nil {
+		cl.Filter = repo.GitSettings.CloneFilter()
+	}
+	if !isWiki && !setting.Repository.DisableHTTPGit {
+		cl.TokenURL = fmt.Sprintf("%sapi/v1/repos/%s/%s/clone-token", setting.AppURL, url.PathEscape(repo.OwnerName), url.PathEscape(repo.Name))
+	}
 	return cl
 }
 
@@ -989,6 +1091,11 @@ type CreateRepoOptions struct {
 	Status         RepositoryStatus
 	TrustModel     TrustModelType
 	MirrorInterval string
+	ObjectFormat   git.ObjectFormat
+	CreatedFrom    RepoCreatedFrom
+	// OverrideVisibilityPolicy lets a site admin create a public repository
+	// in an organization which requires private repositories
+	OverrideVisibilityPolicy bool
 }
 
 // ForkRepoOptions contains the fork repository options
@@ -996,6 +1103,9 @@ type ForkRepoOptions struct {
 	BaseRepo    *Repository
 	Name        string
 	Description string
+	// OverrideVisibilityPolicy lets a site admin fork a public repository
+	// into an organization which requires private repositories
+	OverrideVisibilityPolicy bool
 }
 
 // GetRepoInitFile returns repository init files
@@ -1027,6 +1137,83 @@ func GetRepoInitFile(tp, name string) ([]byte, error) {
 	}
 }
 
+// RepoInitFileNames returns the sorted names of the repository init files of a type,
+// nil for an unknown type
+func RepoInitFileNames(tp string) []string {
+	switch tp {
+	case "readme":
+		return Readmes
+	case "gitignore":
+		return Gitignores
+	case "license":
+		return Licenses
+	case "label":
+		names := make([]string, 0, len(LabelTemplates))
+		for name := range LabelTemplates {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return names
+	default:
+		return nil
+	}
+}
+
+// maxInitFileSuggestions limits the close matches listed by ErrInitFileNotExist
+const maxInitFileSuggestions = 5
+
+// ResolveRepoInitFileName returns the canonical name of a repository init file, ignoring case.
+// ErrInitFileNotExist lists close matches when there is no such file.
+func ResolveRepoInitFileName(tp, name string) (string, error) {
+	names := RepoInitFileNames(tp)
+	for _, n := range names {
+		if n == name {
+			return n, nil
+		}
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, name) {
+			return n, nil
+		}
+	}
+
+	lowerName := strings.ToLower(name)
+	suggestions := make([]string, 0, maxInitFileSuggestions)
+	for _, n := range names {
+		lowerN := strings.ToLower(n)
+		if strings.Contains(lowerN, lowerName) || strings.Contains(lowerName, lowerN) ||
+			editDistance(lowerN, lowerName) <= 2 {
+			suggestions = append(suggestions, n)
+			if len(suggestions) == maxInitFileSuggestions {
+				break
+			}
+		}
+	}
+	return "", ErrInitFileNotExist{Type: tp, Name: name, Suggestions: suggestions}
+}
+
+// editDistance returns the Levenshtein distance of a and b
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = util.Min(util.Min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
 var (
 	reservedRepoNames    = []string{".", ".."}
 	reservedRepoPatterns = []string{"*.git", "*.wiki", "*.rss", "*.atom"}
@@ -1038,11 +1225,16 @@ func IsUsableRepoName(name string) error {
 		// Note: usually this error is normally caught up earlier in the UI
 		return ErrNameCharsNotAllowed{Name: name}
 	}
-	return isUsableName(reservedRepoNames, reservedRepoPatterns, name)
+	return isUsableName(reservedRepoNames, reservedRepoPatterns, "repository name", name, setting.Repository.MaxNameLength)
 }
 
 // CreateRepository creates a repository for the user/organization.
 func CreateRepository(ctx context.Context, doer, u *User, repo *Repository, overwriteOrAdopt bool) (err error) {
+	if repo.CreatedFrom == "" {
+		repo.CreatedFrom = RepoCreatedFromCreated
+	}
+	repo.Name = NormalizeName(repo.Name)
+	repo.LowerName = strings.ToLower(repo.Name)
 	if err = IsUsableRepoName(repo.Name); err != nil {
 		return err
 	}
@@ -1068,6 +1260,10 @@ func CreateRepository(ctx context.Context, doer, u *User, repo *Repository, over
 		}
 	}
 
+	if repo.ObjectFormat == "" {
+		repo.ObjectFormat = git.ObjectFormatSHA1
+	}
+
 	if _, err = db.GetEngine(ctx).Insert(repo); err != nil {
 		return err
 	}
@@ -1159,6 +1355,17 @@ func CreateRepository(ctx context.Context, doer, u *User, repo *Repository, over
 	return nil
 }
 
+// DaemonExportFile returns the path of the git-daemon-export-ok file of the repository
+func (repo *Repository) DaemonExportFile() string {
+	return path.Join(repo.RepoPath(), `git-daemon-export-ok`)
+}
+
+// IsDaemonExported returns whether the repository should be exported by git-daemon,
+// the owner of the repository must be loaded
+func (repo *Repository) IsDaemonExported() bool {
+	return !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePublic
+}
+
 // CheckDaemonExportOK creates/removes git-daemon-export-ok for git-daemon...
 func (repo *Repository) CheckDaemonExportOK(ctx context.Context) error {
 	e := db.GetEngine(ctx)
@@ -1167,7 +1374,7 @@ func (repo *Repository) CheckDaemonExportOK(ctx context.Context) error {
 	}
 
 	// Create/Remove git-daemon-export-ok for git-daemon...
-	daemonExportFile := path.Join(repo.RepoPath(), `git-daemon-export-ok`)
+	daemonExportFile := repo.DaemonExportFile()
 
 	isExist, err := util.IsExist(daemonExportFile)
 	if err != nil {
@@ -1175,7 +1382,7 @@ func (repo *Repository) CheckDaemonExportOK(ctx context.Context) error {
 		return err
 	}
 
-	isPublic := !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePublic
+	isPublic := repo.IsDaemonExported()
 	if !isPublic && isExist {
 		if err = util.Remove(daemonExportFile); err != nil {
 			log.Error("Failed to remove %s: %v", daemonExportFile, err)
@@ -1242,7 +1449,7 @@ func DecrementRepoForkNum(ctx context.Context, repoID int64) error {
 // ChangeRepositoryName changes all corresponding setting from old repository name to new one.
 func ChangeRepositoryName(doer *User, repo *Repository, newRepoName string) (err error) {
 	oldRepoName := repo.Name
-	newRepoName = strings.ToLower(newRepoName)
+	newRepoName = strings.ToLower(NormalizeName(newRepoName))
 	if err = IsUsableRepoName(newRepoName); err != nil {
 		return err
 	}
@@ -1303,21 +1510,14 @@ func GetRepositoriesByForkID(forkID int64) ([]*Repository, error) {
 func updateRepository(e db.Engine, repo *Repository, visibilityChanged bool) (err error) {
 	repo.LowerName = strings.ToLower(repo.Name)
 
-	if utf8.RuneCountInString(repo.Description) > 255 {
-		repo.Description = string([]rune(repo.Description)[:255])
-	}
-	if utf8.RuneCountInString(repo.Website) > 255 {
-		repo.Website = string([]rune(repo.Website)[:255])
-	}
+	repo.Description = util.TruncateGraphemes(repo.Description, 255)
+	repo.Website = util.TruncateGraphemes(repo.Website, 255)
 
-	if _, err = e.ID(repo.ID).AllCols().Update(repo); err != nil {
+	// the size is calculated asynchronously, the one of the loaded repository may be outdated
+	if _, err = e.ID(repo.ID).AllCols().Omit("size", "size_updated_unix").Update(repo); err != nil {
 		return fmt.Errorf("update: %v", err)
 	}
 
-	if err = repo.updateSize(e); err != nil {
-		log.Error("Failed to update size for repository: %v", err)
-	}
-
 	if visibilityChanged {
 		if err = repo.getOwner(e); err != nil {
 			return fmt.Errorf("getOwner: %v", err)
@@ -1349,7 +1549,12 @@ func updateRepository(e db.Engine, repo *Repository, visibilityChanged bool) (er
 			return fmt.Errorf("getRepositoriesByForkID: %v", err)
 		}
 		for i := range forkRepos {
-			forkRepos[i].IsPrivate = repo.IsPrivate || repo.Owner.Visibility == api.VisibleTypePrivate
+			if err = forkRepos[i].getOwner(e); err != nil {
+				return fmt.Errorf("getOwner: %v", err)
+			}
+			// Forks in organizations which require private repositories stay private
+			forkRepos[i].IsPrivate = repo.IsPrivate || repo.Owner.Visibility == api.VisibleTypePrivate ||
+				forkRepos[i].Owner.RequirePrivateRepos
 			if err = updateRepository(e, forkRepos[i], true); err != nil {
 				return fmt.Errorf("updateRepository[%d]: %v", forkRepos[i].ID, err)
 			}
@@ -1364,14 +1569,31 @@ func UpdateRepositoryCtx(ctx context.Context, repo *Repository, visibilityChange
 	return updateRepository(db.GetEngine(ctx), repo, visibilityChanged)
 }
 
-// UpdateRepository updates a repository
+// UpdateRepository updates a repository, a private repository of an organization which
+// requires private repositories can't become public
 func UpdateRepository(repo *Repository, visibilityChanged bool) (err error) {
+	return updateRepositoryWithPolicy(repo, visibilityChanged, true)
+}
+
+// UpdateRepositoryOverridingVisibilityPolicy updates a repository like UpdateRepository,
+// but it can become public even if its organization requires private repositories
+func UpdateRepositoryOverridingVisibilityPolicy(repo *Repository, visibilityChanged bool) (err error) {
+	return updateRepositoryWithPolicy(repo, visibilityChanged, false)
+}
+
+func updateRepositoryWithPolicy(repo *Repository, visibilityChanged, enforcePolicy bool) (err error) {
 	sess := db.NewSession(db.DefaultContext)
 	defer sess.Close()
 	if err = sess.Begin(); err != nil {
 		return err
 	}
 
+	if visibilityChanged && enforcePolicy {
+		if err = checkRepoVisibilityPolicy(sess, repo); err != nil {
+			return err
+		}
+	}
+
 	if err = updateRepository(sess, repo, visibilityChanged); err != nil {
 		return fmt.Errorf("updateRepository: %v", err)
 	}
@@ -1379,6 +1601,27 @@ func UpdateRepository(repo *Repository, visibilityChanged bool) (err error) {
 	return sess.Commit()
 }
 
+// checkRepoVisibilityPolicy returns ErrPublicRepoNotAllowed when a private repository of an organization
+// which requires private repositories becomes public. Public repositories created before the policy are kept.
+func checkRepoVisibilityPolicy(e db.Engine, repo *Repository) error {
+	if repo.IsPrivate {
+		return nil
+	}
+	if err := repo.getOwner(e); err != nil {
+		return fmt.Errorf("getOwner: %v", err)
+	}
+	if !repo.Owner.IsOrganization() || !repo.Owner.RequirePrivateRepos {
+		return nil
+	}
+	wasPrivate, err := e.Where("id = ? AND is_private = ?", repo.ID, true).Exist(new(Repository))
+	if err != nil {
+		return err
+	} else if wasPrivate {
+		return ErrPublicRepoNotAllowed{OrgName: repo.Owner.Name}
+	}
+	return nil
+}
+
 // UpdateRepositoryOwnerNames updates repository owner_names (this should only be used when the ownerName has changed case)
 func UpdateRepositoryOwnerNames(ownerID int64, ownerName string) error {
 	if ownerID == 0 {
@@ -1405,6 +1648,12 @@ func UpdateRepositoryUpdatedTime(repoID int64, updateTime time.Time) error {
 	return err
 }
 
+// UpdateRepositoryPushedTime updates a repository's pushed and updated times
+func UpdateRepositoryPushedTime(repoID int64, pushTime time.Time) error {
+	_, err := db.GetEngine(db.DefaultContext).Exec("UPDATE repository SET pushed_unix = ?, updated_unix = ? WHERE id = ?", pushTime.Unix(), pushTime.Unix(), repoID)
+	return err
+}
+
 // UpdateRepositoryUnits updates a repository's units
 func UpdateRepositoryUnits(repo *Repository, units []RepoUnit, deleteUnitTypes []UnitType) (err error) {
 	sess := db.NewSession(db.DefaultContext)
@@ -1512,15 +1761,26 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		&LFSLock{RepoID: repoID},
 		&LanguageStat{RepoID: repoID},
 		&Milestone{RepoID: repoID},
+		&MilestoneReminder{RepoID: repoID},
+		&MergeQueueEntry{RepoID: repoID},
 		&Mirror{RepoID: repoID},
 		&Notification{RepoID: repoID},
 		&ProtectedBranch{RepoID: repoID},
+		&RepoLicense{RepoID: repoID},
+		&RepoMetadata{RepoID: repoID},
+		&StaleBranchCleanup{RepoID: repoID},
 		&ProtectedTag{RepoID: repoID},
 		&PullRequest{BaseRepoID: repoID},
+		&PullReviewFileState{RepoID: repoID},
 		&PushMirror{RepoID: repoID},
+		&PushRule{RepoID: repoID},
 		&Release{RepoID: repoID},
+		&RepoCloneToken{RepoID: repoID},
+		&RepoGitSettings{RepoID: repoID},
 		&RepoIndexerStatus{RepoID: repoID},
 		&RepoRedirect{RedirectRepoID: repoID},
+		&RepoTraffic{RepoID: repoID},
+		&RepoVisit{RepoID: repoID},
 		&RepoUnit{RepoID: repoID},
 		&Star{RepoID: repoID},
 		&Task{RepoID: repoID},
@@ -1547,7 +1807,7 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 	}
 
 	if repo.IsFork {
-		if _, err := sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=?", repo.ForkID); err != nil {
+		if _, err := sess.Exec("UPDATE `repository` SET num_forks=num_forks-1 WHERE id=? AND num_forks>0", repo.ForkID); err != nil {
 			return fmt.Errorf("decrease fork count: %v", err)
 		}
 	}
@@ -1556,10 +1816,8 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
-	if len(repo.Topics) > 0 {
-		if err := removeTopicsFromRepo(sess, repo.ID); err != nil {
-			return err
-		}
+	if err := removeTopicsFromRepo(sess, repo.ID); err != nil {
+		return err
 	}
 
 	projects, _, err := getProjects(sess, ProjectSearchOptions{
@@ -1614,10 +1872,9 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
-	if repo.NumForks > 0 {
-		if _, err = sess.Exec("UPDATE `repository` SET fork_id=0,is_fork=? WHERE fork_id=?", false, repo.ID); err != nil {
-			log.Error("reset 'fork_id' and 'is_fork': %v", err)
-		}
+	// num_forks might be out of date, so always detach the forks
+	if _, err = sess.Exec("UPDATE `repository` SET fork_id=0,is_fork=? WHERE fork_id=?", false, repo.ID); err != nil {
+		log.Error("reset 'fork_id' and 'is_fork': %v", err)
 	}
 
 	// Get all attachments with both issue_id and release_id are zero
@@ -1639,61 +1896,81 @@ func DeleteRepository(doer *User, uid, repoID int64) error {
 		return err
 	}
 
-	if err = sess.Commit(); err != nil {
-		return err
-	}
-
-	sess.Close()
-
 	// We should always delete the files after the database transaction succeed. If
 	// we delete the file but the database rollback, the repository will be broken.
+	// So the files are queued within the transaction and removed once it has been
+	// committed, failed removals are retried later by the cleanup cron task.
+	repoName := org.Name + "/" + repo.Name
+	newTask := func(storageName, path string) *RepoCleanupTask {
+		return &RepoCleanupTask{RepoID: repoID, RepoName: repoName, Storage: storageName, Path: path}
+	}
 
-	// Remove repository files.
-	repoPath := repo.RepoPath()
-	removeAllWithNotice(db.GetEngine(db.DefaultContext), "Delete repository files", repoPath)
-
-	// Remove wiki files
+	cleanupTasks := []*RepoCleanupTask{newTask(RepoCleanupStorageLocal, repo.RepoPath())}
 	if repo.HasWiki() {
-		removeAllWithNotice(db.GetEngine(db.DefaultContext), "Delete repository wiki", repo.WikiPath())
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageLocal, repo.WikiPath()))
 	}
-
-	// Remove archives
-	for i := range archivePaths {
-		removeStorageWithNotice(db.GetEngine(db.DefaultContext), storage.RepoArchives, "Delete repo archive file", archivePaths[i])
+	for _, p := range archivePaths {
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageRepoArchives, p))
 	}
-
-	// Remove lfs objects
-	for i := range lfsPaths {
-		removeStorageWithNotice(db.GetEngine(db.DefaultContext), storage.LFS, "Delete orphaned LFS file", lfsPaths[i])
+	for _, p := range lfsPaths {
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageLFS, p))
 	}
-
-	// Remove issue attachment files.
-	for i := range attachmentPaths {
-		RemoveStorageWithNotice(storage.Attachments, "Delete issue attachment", attachmentPaths[i])
+	for _, p := range attachmentPaths {
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageAttachments, p))
 	}
-
-	// Remove release attachment files.
-	for i := range releaseAttachments {
-		RemoveStorageWithNotice(storage.Attachments, "Delete release attachment", releaseAttachments[i])
+	for _, p := range releaseAttachments {
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageAttachments, p))
 	}
-
-	// Remove attachment with no issue_id and release_id.
-	for i := range newAttachmentPaths {
-		RemoveStorageWithNotice(storage.Attachments, "Delete issue attachment", attachmentPaths[i])
+	for _, p := range newAttachmentPaths {
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageAttachments, p))
 	}
-
 	if len(repo.Avatar) > 0 {
-		if err := storage.RepoAvatars.Delete(repo.CustomAvatarRelativePath()); err != nil {
-			return fmt.Errorf("Failed to remove %s: %v", repo.Avatar, err)
-		}
+		cleanupTasks = append(cleanupTasks, newTask(RepoCleanupStorageRepoAvatars, repo.CustomAvatarRelativePath()))
+	}
+	if err := insertRepoCleanupTasks(sess, cleanupTasks); err != nil {
+		return fmt.Errorf("insertRepoCleanupTasks: %v", err)
 	}
 
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	sess.Close()
+
+	runRepoCleanupTasks(db.GetEngine(db.DefaultContext), cleanupTasks)
+
 	return nil
 }
 
 // GetRepositoryByOwnerAndName returns the repository by given ownername and reponame.
+// The repository of a renamed owner is found by the old name of the owner too.
 func GetRepositoryByOwnerAndName(ownerName, repoName string) (*Repository, error) {
-	return getRepositoryByOwnerAndName(db.GetEngine(db.DefaultContext), ownerName, repoName)
+	repo, _, err := LookupRepositoryByOwnerAndName(ownerName, repoName)
+	return repo, err
+}
+
+// LookupRepositoryByOwnerAndName returns the repository by given ownername and reponame, following
+// the redirect of a renamed owner. redirected tells whether ownerName is an old name of the owner.
+func LookupRepositoryByOwnerAndName(ownerName, repoName string) (repo *Repository, redirected bool, err error) {
+	e := db.GetEngine(db.DefaultContext)
+	repo, err = getRepositoryByOwnerAndName(e, ownerName, repoName)
+	if !IsErrRepoNotExist(err) {
+		return repo, false, err
+	}
+
+	// A redirect only exists while no user has the old name
+	repo = new(Repository)
+	has, err := e.Table("repository").Select("repository.*").
+		Join("INNER", "user_redirect", "user_redirect.redirect_user_id = repository.owner_id").
+		Where("repository.lower_name = ?", strings.ToLower(repoName)).
+		And("user_redirect.lower_name = ?", strings.ToLower(ownerName)).
+		Get(repo)
+	if err != nil {
+		return nil, false, err
+	} else if !has {
+		return nil, false, ErrRepoNotExist{0, 0, ownerName, repoName}
+	}
+	return repo, true, nil
 }
 
 func getRepositoryByOwnerAndName(e db.Engine, ownerName, repoName string) (*Repository, error) {
@@ -1782,13 +2059,19 @@ func GetUserRepositories(opts *SearchRepoOptions) ([]*Repository, int64, error)
 	return repos, count, db.SetSessionPagination(sess, opts).Find(&repos)
 }
 
-// GetUserMirrorRepositories returns a list of mirror repositories of given user.
-func GetUserMirrorRepositories(userID int64) ([]*Repository, error) {
-	repos := make([]*Repository, 0, 10)
-	return repos, db.GetEngine(db.DefaultContext).
-		Where("owner_id = ?", userID).
-		And("is_mirror = ?", true).
-		Find(&repos)
+// GetUserMirrorRepositories returns a page of the mirror repositories of given user
+// and the total number of them.
+func GetUserMirrorRepositories(userID int64, listOptions db.ListOptions, orderBy SearchOrderBy) ([]*Repository, int64, error) {
+	opts := &SearchRepoOptions{
+		ListOptions: listOptions,
+		OrderBy:     orderBy,
+	}
+	cond := builder.Eq{
+		"`repository`.owner_id":  userID,
+		"`repository`.is_mirror": true,
+	}
+	repos, count, err := SearchRepositoryByCondition(opts, cond, false)
+	return repos, count, err
 }
 
 func getRepositoryCount(e db.Engine, u *User) (int64, error) {
@@ -2037,6 +2320,8 @@ func (repo *Repository) SetArchiveRepoState(isArchived bool) (err error) {
 //      \/                   \/
 
 // HasForkedRepo checks if given user has already forked a repository with given ID.
+// Only the forks the user currently owns count, a fork which was transferred away
+// belongs to its new owner and the user may fork the repository again.
 func HasForkedRepo(ownerID, repoID int64) (*Repository, bool) {
 	repo := new(Repository)
 	has, _ := db.GetEngine(db.DefaultContext).
@@ -2045,6 +2330,35 @@ func HasForkedRepo(ownerID, repoID int64) (*Repository, bool) {
 	return repo, has
 }
 
+const wrongNumForksCond = "num_forks != (SELECT COUNT(*) FROM `repository` fork WHERE fork.fork_id = `repository`.id)"
+
+// CountReposWithWrongNumForks counts the repositories whose num_forks doesn't match their forks
+func CountReposWithWrongNumForks() (int64, error) {
+	return db.GetEngine(db.DefaultContext).Where(wrongNumForksCond).Count(new(Repository))
+}
+
+// FixReposWithWrongNumForks recalculates the num_forks of the repositories from their forks
+func FixReposWithWrongNumForks() (int64, error) {
+	// MySQL can't update a table from a subquery on the same table, so count the forks first
+	ids := make([]int64, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).Table("repository").Where(wrongNumForksCond).Cols("id").Find(&ids); err != nil {
+		return 0, err
+	}
+
+	var fixed int64
+	for _, id := range ids {
+		count, err := db.GetEngine(db.DefaultContext).Where("fork_id = ?", id).Count(new(Repository))
+		if err != nil {
+			return fixed, err
+		}
+		if _, err := db.GetEngine(db.DefaultContext).Exec("UPDATE `repository` SET num_forks=? WHERE id=?", count, id); err != nil {
+			return fixed, err
+		}
+		fixed++
+	}
+	return fixed, nil
+}
+
 // CopyLFS copies LFS data from one repo to another
 func CopyLFS(ctx context.Context, newRepo, oldRepo *Repository) error {
 	var lfsObjects []*LFSMetaObject
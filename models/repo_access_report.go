@@ -0,0 +1,200 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// AccessSourceType describes where the access of a user to a repository comes from
+type AccessSourceType string
+
+const (
+	// AccessSourceOwner the user owns the repository
+	AccessSourceOwner AccessSourceType = "owner"
+	// AccessSourceCollaborator the user is a collaborator of the repository
+	AccessSourceCollaborator AccessSourceType = "collaborator"
+	// AccessSourceTeam the user is a member of a team with access to the repository
+	AccessSourceTeam AccessSourceType = "team"
+	// AccessSourceOrgOwner the user is a member of the owners team of the organization owning the repository
+	AccessSourceOrgOwner AccessSourceType = "org_owner"
+	// AccessSourceSiteAdmin the user is a site administrator
+	AccessSourceSiteAdmin AccessSourceType = "site_admin"
+)
+
+// AccessSource is one of the reasons why a user has access to a repository
+type AccessSource struct {
+	Type     AccessSourceType
+	Mode     AccessMode
+	WikiMode AccessMode // only set for collaborators which may write to the wiki with a lower mode
+	Team     *Team      // only set for team and organization owner sources
+}
+
+// RepoUserAccess is the access of a user to a repository together with all the sources of it
+type RepoUserAccess struct {
+	User    *User
+	Mode    AccessMode
+	Sources []*AccessSource
+}
+
+// GetUserAccesses returns every user with explicit access to the repository and the sources of their access.
+// Site administrators and users which can only read a public repository are not listed.
+func (repo *Repository) GetUserAccesses() ([]*RepoUserAccess, error) {
+	return repo.getUserAccesses(db.GetEngine(db.DefaultContext))
+}
+
+func (repo *Repository) getUserAccesses(e db.Engine) ([]*RepoUserAccess, error) {
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+
+	accessMap := make(map[int64]*RepoUserAccess, 10)
+	addSource := func(uid int64, source *AccessSource) {
+		ua, ok := accessMap[uid]
+		if !ok {
+			ua = &RepoUserAccess{}
+			accessMap[uid] = ua
+		}
+		if source != nil {
+			ua.Sources = append(ua.Sources, source)
+			ua.Mode = maxAccessMode(ua.Mode, source.Mode)
+		}
+	}
+
+	if !repo.Owner.IsOrganization() {
+		addSource(repo.OwnerID, &AccessSource{Type: AccessSourceOwner, Mode: AccessModeOwner})
+	}
+
+	collaborations := make([]*Collaboration, 0, 10)
+	if err := e.Where("repo_id = ?", repo.ID).Find(&collaborations); err != nil {
+		return nil, err
+	}
+	for _, c := range collaborations {
+		source := &AccessSource{Type: AccessSourceCollaborator, Mode: c.Mode}
+		if c.WikiMode > c.Mode {
+			source.WikiMode = c.WikiMode
+		}
+		addSource(c.UserID, source)
+	}
+
+	if repo.Owner.IsOrganization() {
+		// The owners team has access to all repositories of the organization even without a team_repo entry.
+		teams := make(map[int64]*Team, 5)
+		if err := e.Where("org_id = ?", repo.OwnerID).
+			And(builder.Eq{"lower_name": ownerTeamName}.
+				Or(builder.In("id", builder.Select("team_id").From("team_repo").Where(builder.Eq{"repo_id": repo.ID})))).
+			Find(&teams); err != nil {
+			return nil, err
+		}
+
+		if len(teams) > 0 {
+			teamIDs := make([]int64, 0, len(teams))
+			for id := range teams {
+				teamIDs = append(teamIDs, id)
+			}
+			teamUsers := make([]*TeamUser, 0, 10)
+			if err := e.In("team_id", teamIDs).Find(&teamUsers); err != nil {
+				return nil, err
+			}
+			for _, tu := range teamUsers {
+				t := teams[tu.TeamID]
+				if t.IsOwnerTeam() {
+					addSource(tu.UID, &AccessSource{Type: AccessSourceOrgOwner, Mode: AccessModeOwner, Team: t})
+				} else {
+					addSource(tu.UID, &AccessSource{Type: AccessSourceTeam, Mode: t.Authorize, Team: t})
+				}
+			}
+		}
+	}
+
+	// The access table holds the effective access of everyone but the real owner.
+	accesses := make([]*Access, 0, 10)
+	if err := e.Where("repo_id = ?", repo.ID).Find(&accesses); err != nil {
+		return nil, err
+	}
+	for _, a := range accesses {
+		addSource(a.UserID, nil)
+		accessMap[a.UserID].Mode = maxAccessMode(accessMap[a.UserID].Mode, a.Mode)
+	}
+
+	userIDs := make([]int64, 0, len(accessMap))
+	for uid := range accessMap {
+		userIDs = append(userIDs, uid)
+	}
+	users := make([]*User, 0, len(userIDs))
+	if len(userIDs) > 0 {
+		if err := e.In("id", userIDs).Find(&users); err != nil {
+			return nil, err
+		}
+	}
+
+	userAccesses := make([]*RepoUserAccess, 0, len(users))
+	for _, u := range users {
+		ua := accessMap[u.ID]
+		ua.User = u
+		userAccesses = append(userAccesses, ua)
+	}
+	sort.Slice(userAccesses, func(i, j int) bool {
+		return userAccesses[i].User.LowerName < userAccesses[j].User.LowerName
+	})
+	return userAccesses, nil
+}
+
+// GetUserAccessSources returns the sources of the access of the user to the repository, there are none if the
+// user can only read a public repository
+func (repo *Repository) GetUserAccessSources(u *User) ([]*AccessSource, error) {
+	return repo.getUserAccessSources(db.GetEngine(db.DefaultContext), u)
+}
+
+func (repo *Repository) getUserAccessSources(e db.Engine, u *User) ([]*AccessSource, error) {
+	if err := repo.getOwner(e); err != nil {
+		return nil, err
+	}
+
+	sources := make([]*AccessSource, 0, 3)
+	if u.IsAdmin {
+		sources = append(sources, &AccessSource{Type: AccessSourceSiteAdmin, Mode: AccessModeOwner})
+	}
+	if !repo.Owner.IsOrganization() && repo.OwnerID == u.ID {
+		sources = append(sources, &AccessSource{Type: AccessSourceOwner, Mode: AccessModeOwner})
+	}
+
+	c, err := repo.getCollaboration(e, u.ID)
+	if err != nil {
+		return nil, err
+	}
+	if c != nil {
+		source := &AccessSource{Type: AccessSourceCollaborator, Mode: c.Mode}
+		if c.WikiMode > c.Mode {
+			source.WikiMode = c.WikiMode
+		}
+		sources = append(sources, source)
+	}
+
+	if repo.Owner.IsOrganization() {
+		teams := make([]*Team, 0, 5)
+		if err := e.Join("INNER", "team_user", "team_user.team_id = team.id").
+			Where("team.org_id = ?", repo.OwnerID).
+			And("team_user.uid = ?", u.ID).
+			And(builder.Eq{"team.lower_name": ownerTeamName}.
+				Or(builder.In("team.id", builder.Select("team_id").From("team_repo").Where(builder.Eq{"repo_id": repo.ID})))).
+			OrderBy("team.id").
+			Find(&teams); err != nil {
+			return nil, err
+		}
+		for _, t := range teams {
+			if t.IsOwnerTeam() {
+				sources = append(sources, &AccessSource{Type: AccessSourceOrgOwner, Mode: AccessModeOwner, Team: t})
+			} else {
+				sources = append(sources, &AccessSource{Type: AccessSourceTeam, Mode: t.Authorize, Team: t})
+			}
+		}
+	}
+	return sources, nil
+}
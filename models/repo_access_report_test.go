@@ -0,0 +1,90 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_GetUserAccesses(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// user repository
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	accesses, err := repo.GetUserAccesses()
+	assert.NoError(t, err)
+	if assert.Len(t, accesses, 1) {
+		assert.EqualValues(t, 2, accesses[0].User.ID)
+		assert.Equal(t, AccessModeOwner, accesses[0].Mode)
+		if assert.Len(t, accesses[0].Sources, 1) {
+			assert.Equal(t, AccessSourceOwner, accesses[0].Sources[0].Type)
+		}
+	}
+
+	// organization repository
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	accesses, err = repo.GetUserAccesses()
+	assert.NoError(t, err)
+	if assert.Len(t, accesses, 2) {
+		assert.EqualValues(t, 2, accesses[0].User.ID)
+		assert.Equal(t, AccessModeOwner, accesses[0].Mode)
+		sources := make(map[AccessSourceType]AccessMode, len(accesses[0].Sources))
+		for _, source := range accesses[0].Sources {
+			sources[source.Type] = source.Mode
+		}
+		assert.Equal(t, map[AccessSourceType]AccessMode{
+			AccessSourceCollaborator: AccessModeWrite,
+			AccessSourceTeam:         AccessModeWrite,
+			AccessSourceOrgOwner:     AccessModeOwner,
+		}, sources)
+
+		assert.EqualValues(t, 4, accesses[1].User.ID)
+		assert.Equal(t, AccessModeWrite, accesses[1].Mode)
+		if assert.Len(t, accesses[1].Sources, 1) {
+			assert.Equal(t, AccessSourceTeam, accesses[1].Sources[0].Type)
+			assert.EqualValues(t, 2, accesses[1].Sources[0].Team.ID)
+		}
+	}
+}
+
+func TestRepository_GetUserAccessSources(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// organization repository
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	user2 := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	sources, err := repo.GetUserAccessSources(user2)
+	assert.NoError(t, err)
+	types := make([]AccessSourceType, 0, len(sources))
+	for _, source := range sources {
+		types = append(types, source.Type)
+	}
+	assert.ElementsMatch(t, []AccessSourceType{AccessSourceCollaborator, AccessSourceOrgOwner, AccessSourceTeam}, types)
+
+	// user repository
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	sources, err = repo.GetUserAccessSources(user2)
+	assert.NoError(t, err)
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, AccessSourceOwner, sources[0].Type)
+	}
+
+	admin := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	sources, err = repo.GetUserAccessSources(admin)
+	assert.NoError(t, err)
+	if assert.Len(t, sources, 1) {
+		assert.Equal(t, AccessSourceSiteAdmin, sources[0].Type)
+	}
+
+	// users which can only read a public repository have no sources
+	user4 := db.AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	sources, err = repo.GetUserAccessSources(user4)
+	assert.NoError(t, err)
+	assert.Empty(t, sources)
+}
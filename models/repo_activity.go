@@ -360,3 +360,108 @@ func releasesForActivityStatement(repoID int64, fromTime time.Time) *xorm.Sessio
 		And("release.is_draft = ?", false).
 		And("release.created_unix >= ?", fromTime.Unix())
 }
+
+// ActivityDigest summarizes the activity of a repository during a period
+type ActivityDigest struct {
+	IssuesOpened       int64
+	IssuesClosed       int64
+	PullRequestsOpened int64
+	PullRequestsMerged int64
+	// Contributors are ordered by the number of their actions, most active first
+	Contributors []*ActivityDigestContributor
+}
+
+// ActivityDigestContributor is a user with the number of their actions in the activity feed of a repository
+type ActivityDigestContributor struct {
+	User    *User
+	Actions int64
+}
+
+// IsEmpty returns whether there was no activity at all during the period
+func (digest *ActivityDigest) IsEmpty() bool {
+	return digest.IssuesOpened == 0 && digest.IssuesClosed == 0 && digest.PullRequestsOpened == 0 &&
+		digest.PullRequestsMerged == 0 && len(digest.Contributors) == 0
+}
+
+// GetActivityDigest returns the digest of the activity of a repository from since until before until.
+// Every figure is computed by a single grouped query over the rows of the repository.
+func GetActivityDigest(repoID int64, since, until time.Time) (*ActivityDigest, error) {
+	e := db.GetEngine(db.DefaultContext)
+	digest := &ActivityDigest{}
+
+	issueCounts := make([]*struct {
+		IsPull bool
+		Count  int64
+	}, 0, 2)
+	if err := e.Table("issue").
+		Select("is_pull, COUNT(*) AS `count`").
+		Where("repo_id = ? AND created_unix >= ? AND created_unix < ?", repoID, since.Unix(), until.Unix()).
+		GroupBy("is_pull").
+		Find(&issueCounts); err != nil {
+		return nil, fmt.Errorf("count opened issues: %v", err)
+	}
+	for _, c := range issueCounts {
+		if c.IsPull {
+			digest.PullRequestsOpened = c.Count
+		} else {
+			digest.IssuesOpened = c.Count
+		}
+	}
+
+	closed, err := e.Table("issue").
+		Where("repo_id = ? AND is_pull = ? AND is_closed = ?", repoID, false, true).
+		And("closed_unix >= ? AND closed_unix < ?", since.Unix(), until.Unix()).
+		Count()
+	if err != nil {
+		return nil, fmt.Errorf("count closed issues: %v", err)
+	}
+	digest.IssuesClosed = closed
+
+	merged, err := e.Table("pull_request").
+		Where("base_repo_id = ? AND has_merged = ?", repoID, true).
+		And("merged_unix >= ? AND merged_unix < ?", since.Unix(), until.Unix()).
+		Count()
+	if err != nil {
+		return nil, fmt.Errorf("count merged pull requests: %v", err)
+	}
+	digest.PullRequestsMerged = merged
+
+	// An action is stored once for every user whose feed shows it, the copy of the actor counts it once
+	actionCounts := make([]*struct {
+		ActUserID int64
+		Count     int64
+	}, 0, 10)
+	if err := e.Table("action").
+		Select("act_user_id, COUNT(*) AS `count`").
+		Where("repo_id = ? AND user_id = act_user_id AND is_deleted = ?", repoID, false).
+		And("created_unix >= ? AND created_unix < ?", since.Unix(), until.Unix()).
+		GroupBy("act_user_id").
+		OrderBy("COUNT(*) DESC, act_user_id ASC").
+		Find(&actionCounts); err != nil {
+		return nil, fmt.Errorf("count actions: %v", err)
+	}
+	if len(actionCounts) == 0 {
+		return digest, nil
+	}
+
+	userIDs := make([]int64, 0, len(actionCounts))
+	for _, c := range actionCounts {
+		userIDs = append(userIDs, c.ActUserID)
+	}
+	users, err := GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("GetUsersByIDs: %v", err)
+	}
+	usersByID := make(map[int64]*User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+	for _, c := range actionCounts {
+		user, ok := usersByID[c.ActUserID]
+		if !ok {
+			user = NewGhostUser()
+		}
+		digest.Contributors = append(digest.Contributors, &ActivityDigestContributor{User: user, Actions: c.Count})
+	}
+	return digest, nil
+}
@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
+)
+
+func TestGetActivityDigest(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	since := time.Unix(0, 0)
+	until := time.Now()
+	digest, err := GetActivityDigest(1, since, until)
+	assert.NoError(t, err)
+
+	issues := db.GetCount(t, &Issue{RepoID: 1}, builder.Eq{"is_pull": false})
+	pulls := db.GetCount(t, &Issue{RepoID: 1}, builder.Eq{"is_pull": true})
+	assert.EqualValues(t, issues, digest.IssuesOpened)
+	assert.EqualValues(t, pulls, digest.PullRequestsOpened)
+	assert.False(t, digest.IsEmpty())
+
+	// an empty window has no activity
+	digest, err = GetActivityDigest(1, until, until)
+	assert.NoError(t, err)
+	assert.True(t, digest.IsEmpty())
+	assert.Empty(t, digest.Contributors)
+}
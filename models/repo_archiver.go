@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/timeutil"
 )
@@ -29,7 +30,8 @@ type RepoArchiver struct {
 	Repo        *Repository     `xorm:"-"`
 	Type        git.ArchiveType `xorm:"unique(s)"`
 	Status      RepoArchiverStatus
-	CommitID    string             `xorm:"VARCHAR(40) unique(s)"`
+	CommitID    string             `xorm:"VARCHAR(64) unique(s)"`
+	Path        string             `xorm:"VARCHAR(255) unique(s) NOT NULL DEFAULT ''"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL created"`
 }
 
@@ -56,15 +58,18 @@ func (archiver *RepoArchiver) LoadRepo() (*Repository, error) {
 	return &repo, nil
 }
 
-// RelativePath returns relative path
+// RelativePath returns relative path, the archives of directories are told apart by the hash of their path
 func (archiver *RepoArchiver) RelativePath() (string, error) {
+	if archiver.Path != "" {
+		return fmt.Sprintf("%d/%s/%s-%s.%s", archiver.RepoID, archiver.CommitID[:2], archiver.CommitID, base.EncodeSha1(archiver.Path), archiver.Type.String()), nil
+	}
 	return fmt.Sprintf("%d/%s/%s.%s", archiver.RepoID, archiver.CommitID[:2], archiver.CommitID, archiver.Type.String()), nil
 }
 
-// GetRepoArchiver get an archiver
-func GetRepoArchiver(ctx context.Context, repoID int64, tp git.ArchiveType, commitID string) (*RepoArchiver, error) {
+// GetRepoArchiver get an archiver, the path is the archived directory or empty for the whole repository
+func GetRepoArchiver(ctx context.Context, repoID int64, tp git.ArchiveType, commitID, path string) (*RepoArchiver, error) {
 	var archiver RepoArchiver
-	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).And("`type`=?", tp).And("commit_id=?", commitID).Get(&archiver)
+	has, err := db.GetEngine(ctx).Where("repo_id=?", repoID).And("`type`=?", tp).And("commit_id=?", commitID).And("path=?", path).Get(&archiver)
 	if err != nil {
 		return nil, err
 	}
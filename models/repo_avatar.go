@@ -25,6 +25,11 @@ func (repo *Repository) CustomAvatarRelativePath() string {
 	return repo.Avatar
 }
 
+// ExistRepoAvatar returns true if any repository uses the custom avatar file with the given path
+func ExistRepoAvatar(avatar string) (bool, error) {
+	return db.GetEngine(db.DefaultContext).Where("avatar=?", avatar).Exist(new(Repository))
+}
+
 // generateRandomAvatar generates a random avatar for repository.
 func (repo *Repository) generateRandomAvatar(e db.Engine) error {
 	idToString := fmt.Sprintf("%d", repo.ID)
@@ -0,0 +1,293 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoBackupStatus represents the status of a repository backup run
+type RepoBackupStatus int
+
+// enumerates all the statuses of repository backup runs
+const (
+	RepoBackupPending   RepoBackupStatus = iota // 0 waits for its first attempt or a retry
+	RepoBackupRunning                           // 1
+	RepoBackupSucceeded                         // 2
+	RepoBackupFailed                            // 3 gave up after too many attempts
+)
+
+// String returns the name of the status as used by the API
+func (status RepoBackupStatus) String() string {
+	switch status {
+	case RepoBackupPending:
+		return "pending"
+	case RepoBackupRunning:
+		return "running"
+	case RepoBackupSucceeded:
+		return "succeeded"
+	case RepoBackupFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("unknown(%d)", int(status))
+}
+
+// ToRepoBackupStatus returns the status of the given name and false if there is none
+func ToRepoBackupStatus(name string) (RepoBackupStatus, bool) {
+	for _, status := range []RepoBackupStatus{RepoBackupPending, RepoBackupRunning, RepoBackupSucceeded, RepoBackupFailed} {
+		if status.String() == name {
+			return status, true
+		}
+	}
+	return -1, false
+}
+
+const (
+	// RepoBackupMaxAttempts is the number of attempts after which a backup run is marked as failed
+	RepoBackupMaxAttempts = 5
+
+	repoBackupInitialBackoff = 5 * time.Minute
+	repoBackupMaxBackoff     = 6 * time.Hour
+)
+
+// RepoBackup represents a run of the scheduled backup of a repository to the repository backup storage.
+// Its files are stored below Path: the git bundle of the repository and of its wiki and a JSON dump of its metadata.
+type RepoBackup struct {
+	ID              int64              `xorm:"pk autoincr"`
+	RepoID          int64              `xorm:"INDEX NOT NULL"`
+	Repo            *Repository        `xorm:"-"`
+	Status          RepoBackupStatus   `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Path            string             `xorm:"VARCHAR(1024) NOT NULL DEFAULT ''"`
+	Attempts        int                `xorm:"NOT NULL DEFAULT 0"`
+	LastError       string             `xorm:"TEXT"`
+	NextAttemptUnix timeutil.TimeStamp `xorm:"INDEX"`
+	// Size is the total size of the stored files in bytes
+	Size int64 `xorm:"NOT NULL DEFAULT 0"`
+	// Duration is the time the last attempt took in milliseconds
+	Duration    int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoBackup))
+}
+
+// LoadRepo loads the repository of the backup run
+func (b *RepoBackup) LoadRepo() (err error) {
+	if b.Repo == nil {
+		b.Repo, err = GetRepositoryByID(b.RepoID)
+	}
+	return
+}
+
+// repoBackupBackoff returns the delay before the next attempt after the given number of failed attempts
+func repoBackupBackoff(attempts int) time.Duration {
+	backoff := repoBackupInitialBackoff
+	for i := 1; i < attempts && backoff < repoBackupMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > repoBackupMaxBackoff {
+		return repoBackupMaxBackoff
+	}
+	return backoff
+}
+
+// MarkRunning marks a backup run as started
+func (b *RepoBackup) MarkRunning() error {
+	b.Status = RepoBackupRunning
+	b.Attempts++
+	_, err := db.GetEngine(db.DefaultContext).ID(b.ID).Cols("status", "attempts").Update(b)
+	return err
+}
+
+// MarkSucceeded records the size and duration of a successful backup run
+func (b *RepoBackup) MarkSucceeded(size int64, duration time.Duration) error {
+	b.Status = RepoBackupSucceeded
+	b.Size = size
+	b.Duration = duration.Milliseconds()
+	b.LastError = ""
+	_, err := db.GetEngine(db.DefaultContext).ID(b.ID).Cols("status", "size", "duration", "last_error").Update(b)
+	return err
+}
+
+// MarkFailed records a failed attempt of a backup run and schedules a retry with an increasing backoff.
+// An admin notice is created for every failed attempt.
+func (b *RepoBackup) MarkFailed(runErr error, duration time.Duration) error {
+	b.Status = RepoBackupPending
+	b.LastError = runErr.Error()
+	b.Duration = duration.Milliseconds()
+	b.NextAttemptUnix = timeutil.TimeStamp(time.Now().Add(repoBackupBackoff(b.Attempts)).Unix())
+	if b.Attempts >= RepoBackupMaxAttempts {
+		b.Status = RepoBackupFailed
+	}
+
+	repoName := fmt.Sprintf("[%d]", b.RepoID)
+	if b.Repo != nil {
+		repoName = b.Repo.FullName()
+	}
+	desc := fmt.Sprintf("Backup of repository %s failed (attempt %d of %d): %v", repoName, b.Attempts, RepoBackupMaxAttempts, runErr)
+	if err := CreateRepositoryNotice(desc); err != nil {
+		return fmt.Errorf("CreateRepositoryNotice: %v", err)
+	}
+
+	_, err := db.GetEngine(db.DefaultContext).ID(b.ID).Cols("status", "last_error", "duration", "next_attempt_unix").Update(b)
+	return err
+}
+
+// CreateRepoBackup adds a pending backup run of a repository, its files are stored below "<repo id>/<run id>"
+func CreateRepoBackup(repoID int64) (*RepoBackup, error) {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	b := &RepoBackup{
+		RepoID:          repoID,
+		Status:          RepoBackupPending,
+		NextAttemptUnix: timeutil.TimeStampNow(),
+	}
+	if _, err := sess.Insert(b); err != nil {
+		return nil, err
+	}
+	b.Path = fmt.Sprintf("%d/%d", repoID, b.ID)
+	if _, err := sess.ID(b.ID).Cols("path").Update(b); err != nil {
+		return nil, err
+	}
+	return b, sess.Commit()
+}
+
+// HasUnfinishedRepoBackup returns true if a backup run of the repository is pending or running
+func HasUnfinishedRepoBackup(repoID int64) (bool, error) {
+	return db.GetEngine(db.DefaultContext).
+		Where("repo_id = ?", repoID).
+		In("status", RepoBackupPending, RepoBackupRunning).
+		Exist(new(RepoBackup))
+}
+
+// GetDueRepoBackups returns up to limit pending backup runs which are due, the oldest first
+func GetDueRepoBackups(limit int) ([]*RepoBackup, error) {
+	backups := make([]*RepoBackup, 0, limit)
+	return backups, db.GetEngine(db.DefaultContext).
+		Where("status = ? AND next_attempt_unix <= ?", RepoBackupPending, timeutil.TimeStampNow()).
+		OrderBy("id ASC").
+		Limit(limit).
+		Find(&backups)
+}
+
+// ResetRunningRepoBackups makes the runs interrupted by a shutdown pending again
+func ResetRunningRepoBackups() error {
+	_, err := db.GetEngine(db.DefaultContext).Where("status = ?", RepoBackupRunning).Cols("status").
+		Update(&RepoBackup{Status: RepoBackupPending})
+	return err
+}
+
+// FindRepoBackupsOptions represents the options to find repository backup runs
+type FindRepoBackupsOptions struct {
+	db.ListOptions
+	RepoID int64
+	// Status filters the runs by status if it is not negative
+	Status RepoBackupStatus
+}
+
+func (opts FindRepoBackupsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Status >= 0 {
+		cond = cond.And(builder.Eq{"status": opts.Status})
+	}
+	return cond
+}
+
+// FindRepoBackups returns the repository backup runs matching the options, the newest first
+func FindRepoBackups(opts FindRepoBackupsOptions) (RepoBackupList, int64, error) {
+	sess := db.GetEngine(db.DefaultContext).Where(opts.toConds())
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	backups := make([]*RepoBackup, 0, opts.PageSize)
+	count, err := sess.OrderBy("id DESC").FindAndCount(&backups)
+	return backups, count, err
+}
+
+// RepoBackupList is a list of repository backup runs
+type RepoBackupList []*RepoBackup
+
+// LoadRepos loads the repositories of the runs, it stays nil for the runs of deleted repositories
+func (backups RepoBackupList) LoadRepos() error {
+	repoIDs := make([]int64, 0, len(backups))
+	for _, b := range backups {
+		repoIDs = append(repoIDs, b.RepoID)
+	}
+	repos, err := GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return err
+	}
+	for _, b := range backups {
+		b.Repo = repos[b.RepoID]
+	}
+	return nil
+}
+
+// GetExpiredRepoBackups returns the successful backup runs of a repository beyond the newest retention ones
+// and all its failed runs older than the oldest retained successful one
+func GetExpiredRepoBackups(repoID int64, retention int) ([]*RepoBackup, error) {
+	succeeded := make([]*RepoBackup, 0, retention+1)
+	if err := db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND status = ?", repoID, RepoBackupSucceeded).
+		OrderBy("id DESC").
+		Find(&succeeded); err != nil {
+		return nil, err
+	}
+	if len(succeeded) <= retention {
+		return nil, nil
+	}
+
+	oldestKept := succeeded[retention-1]
+	expired := succeeded[retention:]
+	failed := make([]*RepoBackup, 0, 5)
+	if err := db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND status = ? AND id < ?", repoID, RepoBackupFailed, oldestKept.ID).
+		Find(&failed); err != nil {
+		return nil, err
+	}
+	return append(expired, failed...), nil
+}
+
+// DeleteRepoBackup deletes the record of a repository backup run
+func DeleteRepoBackup(id int64) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(id).Delete(new(RepoBackup))
+	return err
+}
+
+// RetryRepoBackup schedules a failed repository backup run to be retried immediately
+func RetryRepoBackup(b *RepoBackup) error {
+	b.Status = RepoBackupPending
+	b.Attempts = 0
+	b.NextAttemptUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(db.DefaultContext).ID(b.ID).Cols("status", "attempts", "next_attempt_unix").Update(b)
+	return err
+}
+
+// GetRepoBackupByID returns the repository backup run with the given ID
+func GetRepoBackupByID(id int64) (*RepoBackup, error) {
+	b := new(RepoBackup)
+	has, err := db.GetEngine(db.DefaultContext).ID(id).Get(b)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoBackupNotExist{ID: id}
+	}
+	return b, nil
+}
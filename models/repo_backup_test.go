@@ -0,0 +1,69 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoBackupBackoff(t *testing.T) {
+	assert.Equal(t, 5*time.Minute, repoBackupBackoff(1))
+	assert.Equal(t, 10*time.Minute, repoBackupBackoff(2))
+	assert.Equal(t, 40*time.Minute, repoBackupBackoff(4))
+	assert.Equal(t, 6*time.Hour, repoBackupBackoff(20))
+}
+
+func TestRepoBackupLifecycle(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	b, err := CreateRepoBackup(1)
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("1/%d", b.ID), b.Path)
+	has, err := HasUnfinishedRepoBackup(1)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	due, err := GetDueRepoBackups(10)
+	assert.NoError(t, err)
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, b.ID, due[0].ID)
+	}
+
+	notices := CountNotices()
+	for i := 1; i <= RepoBackupMaxAttempts; i++ {
+		assert.NoError(t, b.MarkRunning())
+		assert.NoError(t, b.MarkFailed(errors.New("storage unavailable"), time.Second))
+	}
+	b = db.AssertExistsAndLoadBean(t, &RepoBackup{ID: b.ID}).(*RepoBackup)
+	assert.Equal(t, RepoBackupFailed, b.Status)
+	assert.Equal(t, "storage unavailable", b.LastError)
+	assert.EqualValues(t, RepoBackupMaxAttempts, CountNotices()-notices)
+
+	// a failed backup is not picked up again until it is retried
+	due, err = GetDueRepoBackups(10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+	assert.NoError(t, RetryRepoBackup(b))
+	due, err = GetDueRepoBackups(10)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	assert.NoError(t, b.MarkRunning())
+	assert.NoError(t, b.MarkSucceeded(1024, time.Second))
+	b = db.AssertExistsAndLoadBean(t, &RepoBackup{ID: b.ID}).(*RepoBackup)
+	assert.Equal(t, RepoBackupSucceeded, b.Status)
+	assert.EqualValues(t, 1024, b.Size)
+	assert.Empty(t, b.LastError)
+	has, err = HasUnfinishedRepoBackup(1)
+	assert.NoError(t, err)
+	assert.False(t, has)
+}
@@ -0,0 +1,286 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// RepoCleanupTaskStatus represents the status of a repository cleanup task
+type RepoCleanupTaskStatus int
+
+// enumerates all the statuses of repository cleanup tasks
+const (
+	RepoCleanupTaskPending RepoCleanupTaskStatus = iota // 0 will be retried
+	RepoCleanupTaskFailed                               // 1 gave up after too many attempts
+)
+
+// The storages the files of a repository cleanup task can be located in,
+// RepoCleanupStorageLocal is a directory of the local file system.
+const (
+	RepoCleanupStorageLocal        = "local"
+	RepoCleanupStorageAttachments  = "attachments"
+	RepoCleanupStorageLFS          = "lfs"
+	RepoCleanupStorageRepoAvatars  = "repo-avatars"
+	RepoCleanupStorageRepoArchives = "repo-archive"
+)
+
+const (
+	// RepoCleanupTaskMaxAttempts is the number of attempts after which a cleanup task is marked as failed
+	RepoCleanupTaskMaxAttempts = 10
+
+	repoCleanupTaskInitialBackoff = time.Minute
+	repoCleanupTaskMaxBackoff     = 24 * time.Hour
+)
+
+// RepoCleanupTask represents a file or a directory left behind by a deleted repository which still has to be removed.
+// Tasks are removed once the deletion succeeds, failed deletions are retried with an increasing backoff.
+type RepoCleanupTask struct {
+	ID              int64                 `xorm:"pk autoincr"`
+	RepoID          int64                 `xorm:"INDEX"`
+	RepoName        string                `xorm:"NOT NULL DEFAULT ''"`
+	Storage         string                `xorm:"VARCHAR(50) NOT NULL"`
+	Path            string                `xorm:"VARCHAR(1024) NOT NULL"`
+	Status          RepoCleanupTaskStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Attempts        int                   `xorm:"NOT NULL DEFAULT 0"`
+	LastError       string                `xorm:"TEXT"`
+	NextAttemptUnix timeutil.TimeStamp    `xorm:"INDEX"`
+	CreatedUnix     timeutil.TimeStamp    `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp    `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoCleanupTask))
+}
+
+// IsFailed returns true if the task won't be retried anymore
+func (task *RepoCleanupTask) IsFailed() bool {
+	return task.Status == RepoCleanupTaskFailed
+}
+
+func repoCleanupObjectStorage(name string) storage.ObjectStorage {
+	switch name {
+	case RepoCleanupStorageAttachments:
+		return storage.Attachments
+	case RepoCleanupStorageLFS:
+		return storage.LFS
+	case RepoCleanupStorageRepoAvatars:
+		return storage.RepoAvatars
+	case RepoCleanupStorageRepoArchives:
+		return storage.RepoArchives
+	}
+	return nil
+}
+
+func (task *RepoCleanupTask) remove() error {
+	if task.Storage == RepoCleanupStorageLocal {
+		return util.RemoveAll(task.Path)
+	}
+	bucket := repoCleanupObjectStorage(task.Storage)
+	if bucket == nil {
+		return fmt.Errorf("unknown storage %q", task.Storage)
+	}
+	return bucket.Delete(task.Path)
+}
+
+// isInUse checks right before the removal whether the files of the task have been taken over again since it was
+// queued: a repository created or adopted at the same path, or an LFS object or attachment uploaded again.
+func (task *RepoCleanupTask) isInUse(e db.Engine) (bool, error) {
+	switch task.Storage {
+	case RepoCleanupStorageLocal:
+		rel, err := filepath.Rel(setting.RepoRootPath, task.Path)
+		if err != nil {
+			return false, nil
+		}
+		parts := strings.Split(filepath.ToSlash(rel), "/")
+		if len(parts) != 2 || !strings.HasSuffix(parts[1], ".git") {
+			return false, nil
+		}
+		repoName := strings.TrimSuffix(strings.TrimSuffix(parts[1], ".git"), ".wiki")
+		if _, err := getRepositoryByOwnerAndName(e, parts[0], repoName); err != nil {
+			if IsErrRepoNotExist(err) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	case RepoCleanupStorageLFS:
+		oid := strings.ReplaceAll(task.Path, "/", "")
+		return e.Exist(&LFSMetaObject{Pointer: lfs.Pointer{Oid: oid}})
+	case RepoCleanupStorageAttachments:
+		return e.Exist(&Attachment{UUID: path.Base(task.Path)})
+	}
+	return false, nil
+}
+
+// repoCleanupTaskBackoff returns the delay before the next attempt after the given number of failed attempts
+func repoCleanupTaskBackoff(attempts int) time.Duration {
+	backoff := repoCleanupTaskInitialBackoff
+	for i := 1; i < attempts && backoff < repoCleanupTaskMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > repoCleanupTaskMaxBackoff {
+		return repoCleanupTaskMaxBackoff
+	}
+	return backoff
+}
+
+// run removes the files of the task, the task is deleted on success and rescheduled otherwise
+func (task *RepoCleanupTask) run(e db.Engine) error {
+	inUse, removeErr := task.isInUse(e)
+	if removeErr == nil {
+		if inUse {
+			log.Info("Keep %s [%s] of deleted repository %s as it is in use again", task.Storage, task.Path, task.RepoName)
+		} else {
+			removeErr = task.remove()
+		}
+	}
+	if removeErr == nil {
+		_, err := e.ID(task.ID).Delete(new(RepoCleanupTask))
+		return err
+	}
+
+	task.Attempts++
+	task.LastError = removeErr.Error()
+	task.NextAttemptUnix = timeutil.TimeStamp(time.Now().Add(repoCleanupTaskBackoff(task.Attempts)).Unix())
+	log.Warn("Remove %s [%s] of deleted repository %s (attempt %d): %v", task.Storage, task.Path, task.RepoName, task.Attempts, removeErr)
+	if task.Attempts >= RepoCleanupTaskMaxAttempts {
+		task.Status = RepoCleanupTaskFailed
+		desc := fmt.Sprintf("Remove %s [%s] of deleted repository %s gave up after %d attempts: %v", task.Storage, task.Path, task.RepoName, task.Attempts, removeErr)
+		if err := createNotice(e, NoticeRepository, desc); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+	}
+	_, err := e.ID(task.ID).Cols("status", "attempts", "last_error", "next_attempt_unix").Update(task)
+	return err
+}
+
+func runRepoCleanupTasks(e db.Engine, tasks []*RepoCleanupTask) {
+	for _, task := range tasks {
+		if err := task.run(e); err != nil {
+			log.Error("Unable to update repository cleanup task %d: %v", task.ID, err)
+		}
+	}
+}
+
+func insertRepoCleanupTasks(e db.Engine, tasks []*RepoCleanupTask) error {
+	now := timeutil.TimeStampNow()
+	for _, task := range tasks {
+		task.Status = RepoCleanupTaskPending
+		task.NextAttemptUnix = now
+		// Insert one by one so the IDs are set for the first attempt after the transaction
+		if _, err := e.Insert(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueRepoCleanupTasks adds the given tasks to the cleanup queue unless a task for the same path is already queued,
+// it returns the number of added tasks.
+func EnqueueRepoCleanupTasks(tasks []*RepoCleanupTask) (int, error) {
+	toInsert := make([]*RepoCleanupTask, 0, len(tasks))
+	for _, task := range tasks {
+		has, err := db.GetEngine(db.DefaultContext).
+			Where("storage=? AND path=?", task.Storage, task.Path).
+			Exist(new(RepoCleanupTask))
+		if err != nil {
+			return 0, err
+		} else if !has {
+			toInsert = append(toInsert, task)
+		}
+	}
+	return len(toInsert), insertRepoCleanupTasks(db.GetEngine(db.DefaultContext), toInsert)
+}
+
+// ProcessRepoCleanupTasks runs all pending repository cleanup tasks which are due
+func ProcessRepoCleanupTasks(ctx context.Context) error {
+	const batchSize = 100
+	e := db.GetEngine(db.DefaultContext)
+	for {
+		tasks := make([]*RepoCleanupTask, 0, batchSize)
+		if err := e.Where("status=? AND next_attempt_unix<=?", RepoCleanupTaskPending, timeutil.TimeStampNow()).
+			OrderBy("id ASC").
+			Limit(batchSize).
+			Find(&tasks); err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			select {
+			case <-ctx.Done():
+				return ErrCancelledf("before removing %s [%s] of deleted repository %s", task.Storage, task.Path, task.RepoName)
+			default:
+			}
+			// Failed tasks are rescheduled into the future, so they are not found again by the next batch
+			if err := task.run(e); err != nil {
+				return fmt.Errorf("update repository cleanup task %d: %v", task.ID, err)
+			}
+		}
+		if len(tasks) < batchSize {
+			return nil
+		}
+	}
+}
+
+// FindRepoCleanupTasksOptions represents the options to find repository cleanup tasks
+type FindRepoCleanupTasksOptions struct {
+	db.ListOptions
+	// Status filters the tasks by status if it is not negative
+	Status RepoCleanupTaskStatus
+}
+
+func (opts FindRepoCleanupTasksOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.Status >= 0 {
+		cond = cond.And(builder.Eq{"status": opts.Status})
+	}
+	return cond
+}
+
+// FindRepoCleanupTasks returns the repository cleanup tasks matching the options, the oldest first
+func FindRepoCleanupTasks(opts FindRepoCleanupTasksOptions) ([]*RepoCleanupTask, int64, error) {
+	sess := db.GetEngine(db.DefaultContext).Where(opts.toConds())
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	tasks := make([]*RepoCleanupTask, 0, opts.PageSize)
+	count, err := sess.OrderBy("id ASC").FindAndCount(&tasks)
+	return tasks, count, err
+}
+
+// RetryRepoCleanupTask schedules a pending or failed repository cleanup task to be retried immediately
+func RetryRepoCleanupTask(id int64) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(id).Cols("status", "attempts", "next_attempt_unix").
+		Update(&RepoCleanupTask{Status: RepoCleanupTaskPending, NextAttemptUnix: timeutil.TimeStampNow()})
+	return err
+}
+
+// RetryFailedRepoCleanupTasks schedules all failed repository cleanup tasks to be retried immediately,
+// it returns the number of rescheduled tasks.
+func RetryFailedRepoCleanupTasks() (int64, error) {
+	return db.GetEngine(db.DefaultContext).Where("status=?", RepoCleanupTaskFailed).Cols("status", "attempts", "next_attempt_unix").
+		Update(&RepoCleanupTask{Status: RepoCleanupTaskPending, NextAttemptUnix: timeutil.TimeStampNow()})
+}
+
+// DeleteRepoCleanupTask drops a repository cleanup task without removing its files
+func DeleteRepoCleanupTask(id int64) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(id).Delete(new(RepoCleanupTask))
+	return err
+}
@@ -0,0 +1,130 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/lfs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoCleanupTaskBackoff(t *testing.T) {
+	assert.Equal(t, time.Minute, repoCleanupTaskBackoff(1))
+	assert.Equal(t, 2*time.Minute, repoCleanupTaskBackoff(2))
+	assert.Equal(t, 8*time.Minute, repoCleanupTaskBackoff(4))
+	assert.Equal(t, 24*time.Hour, repoCleanupTaskBackoff(20))
+}
+
+func TestDeleteRepositoryRemovesFiles(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	assert.NoError(t, repo.GetOwner())
+	repoPath := repo.RepoPath()
+	exist, err := util.IsExist(repoPath)
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	assert.NoError(t, DeleteRepository(&User{ID: 1}, repo.OwnerID, repo.ID))
+
+	exist, err = util.IsExist(repoPath)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	db.AssertNotExistsBean(t, &RepoCleanupTask{RepoID: repo.ID})
+}
+
+func TestProcessRepoCleanupTasks(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	dir := filepath.Join(t.TempDir(), "deleted.git")
+	assert.NoError(t, os.MkdirAll(dir, 0755))
+
+	ok := &RepoCleanupTask{RepoName: "user2/deleted", Storage: RepoCleanupStorageLocal, Path: dir}
+	broken := &RepoCleanupTask{RepoName: "user2/deleted", Storage: "unknown", Path: "some/file"}
+	n, err := EnqueueRepoCleanupTasks([]*RepoCleanupTask{ok, broken})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+
+	// A path which is already queued is not added twice
+	n, err = EnqueueRepoCleanupTasks([]*RepoCleanupTask{{Storage: RepoCleanupStorageLocal, Path: dir}})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, n)
+
+	assert.NoError(t, ProcessRepoCleanupTasks(context.Background()))
+	exist, err := util.IsExist(dir)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	db.AssertNotExistsBean(t, &RepoCleanupTask{ID: ok.ID})
+
+	task := db.AssertExistsAndLoadBean(t, &RepoCleanupTask{ID: broken.ID}).(*RepoCleanupTask)
+	assert.Equal(t, RepoCleanupTaskPending, task.Status)
+	assert.EqualValues(t, 1, task.Attempts)
+	assert.NotEmpty(t, task.LastError)
+	assert.Greater(t, int64(task.NextAttemptUnix), int64(timeutil.TimeStampNow()))
+
+	// The task is not due yet
+	assert.NoError(t, ProcessRepoCleanupTasks(context.Background()))
+	task = db.AssertExistsAndLoadBean(t, &RepoCleanupTask{ID: broken.ID}).(*RepoCleanupTask)
+	assert.EqualValues(t, 1, task.Attempts)
+
+	for i := task.Attempts; i < RepoCleanupTaskMaxAttempts; i++ {
+		assert.NoError(t, RetryRepoCleanupTask(task.ID))
+		_, err = db.GetEngine(db.DefaultContext).ID(task.ID).Cols("attempts").Update(&RepoCleanupTask{Attempts: i})
+		assert.NoError(t, err)
+		assert.NoError(t, ProcessRepoCleanupTasks(context.Background()))
+	}
+	task = db.AssertExistsAndLoadBean(t, &RepoCleanupTask{ID: broken.ID}).(*RepoCleanupTask)
+	assert.True(t, task.IsFailed())
+	assert.EqualValues(t, RepoCleanupTaskMaxAttempts, task.Attempts)
+
+	tasks, count, err := FindRepoCleanupTasks(FindRepoCleanupTasksOptions{Status: RepoCleanupTaskFailed})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, tasks, 1)
+
+	retried, err := RetryFailedRepoCleanupTasks()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, retried)
+	task = db.AssertExistsAndLoadBean(t, &RepoCleanupTask{ID: broken.ID}).(*RepoCleanupTask)
+	assert.Equal(t, RepoCleanupTaskPending, task.Status)
+	assert.EqualValues(t, 0, task.Attempts)
+
+	assert.NoError(t, DeleteRepoCleanupTask(task.ID))
+	db.AssertNotExistsBean(t, &RepoCleanupTask{ID: task.ID})
+}
+
+func TestProcessRepoCleanupTasksKeepsReusedFiles(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// a repository has been created at the path of the deleted one
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, repo.GetOwner())
+	recreated := &RepoCleanupTask{RepoName: repo.FullName(), Storage: RepoCleanupStorageLocal, Path: repo.RepoPath()}
+
+	// the LFS object has been uploaded again
+	meta := &LFSMetaObject{Pointer: lfs.Pointer{Oid: "3c2ad4d7fb45e6e7a88e2da95a5fe3a4b10de56a40d4cf22b18eb1beab8a8b5d", Size: 4}, RepositoryID: repo.ID}
+	_, err := db.GetEngine(db.DefaultContext).Insert(meta)
+	assert.NoError(t, err)
+	reuploaded := &RepoCleanupTask{RepoName: "user2/deleted", Storage: RepoCleanupStorageLFS, Path: meta.RelativePath()}
+
+	_, err = EnqueueRepoCleanupTasks([]*RepoCleanupTask{recreated, reuploaded})
+	assert.NoError(t, err)
+	assert.NoError(t, ProcessRepoCleanupTasks(context.Background()))
+
+	exist, err := util.IsExist(repo.RepoPath())
+	assert.NoError(t, err)
+	assert.True(t, exist)
+	db.AssertNotExistsBean(t, &RepoCleanupTask{ID: recreated.ID})
+	db.AssertNotExistsBean(t, &RepoCleanupTask{ID: reuploaded.ID})
+}
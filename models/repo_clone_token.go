@@ -0,0 +1,142 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/login"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+)
+
+// RepoCloneTokenPrefix starts all clone tokens, it tells them apart from access tokens
+const RepoCloneTokenPrefix = "gtc_"
+
+// RepoCloneToken is a short-lived token a user clones, and may push to, a single repository over HTTP(S) with
+type RepoCloneToken struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	UID    int64 `xorm:"INDEX NOT NULL"`
+	// Mode is the highest access the token grants, the permission of the user to the repository still applies
+	Mode           AccessMode `xorm:"NOT NULL"`
+	Token          string     `xorm:"-"`
+	TokenHash      string     `xorm:"UNIQUE"` // sha256 of token
+	TokenSalt      string
+	TokenLastEight string `xorm:"INDEX token_last_eight"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoCloneToken))
+}
+
+// IsExpired returns true if the token can not be used anymore
+func (t *RepoCloneToken) IsExpired() bool {
+	return t.ExpiresUnix <= timeutil.TimeStampNow()
+}
+
+// ErrRepoCloneTokenNotExist represents a "RepoCloneTokenNotExist" kind of error.
+type ErrRepoCloneTokenNotExist struct {
+	ID int64
+}
+
+// IsErrRepoCloneTokenNotExist checks if an error is a ErrRepoCloneTokenNotExist.
+func IsErrRepoCloneTokenNotExist(err error) bool {
+	_, ok := err.(ErrRepoCloneTokenNotExist)
+	return ok
+}
+
+func (err ErrRepoCloneTokenNotExist) Error() string {
+	return fmt.Sprintf("clone token does not exist [id: %d]", err.ID)
+}
+
+// IsRepoCloneToken returns true if the token looks like a clone token
+func IsRepoCloneToken(token string) bool {
+	return strings.HasPrefix(token, RepoCloneTokenPrefix)
+}
+
+// NewRepoCloneToken creates a clone token of the user to the repository granting at most the mode,
+// it expires after the configured expiration. The expired tokens of all users are deleted at the same time.
+func NewRepoCloneToken(repoID, uid int64, mode AccessMode) (*RepoCloneToken, error) {
+	salt, err := util.RandomString(10)
+	if err != nil {
+		return nil, err
+	}
+	t := &RepoCloneToken{
+		RepoID:      repoID,
+		UID:         uid,
+		Mode:        mode,
+		Token:       RepoCloneTokenPrefix + base.EncodeSha1(gouuid.New().String()),
+		TokenSalt:   salt,
+		ExpiresUnix: timeutil.TimeStampNow().AddDuration(setting.Repository.CloneTokenExpiration),
+	}
+	t.TokenHash = login.HashToken(t.Token, t.TokenSalt)
+	t.TokenLastEight = t.Token[len(t.Token)-8:]
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+	if _, err := sess.Where("expires_unix <= ?", timeutil.TimeStampNow()).Delete(new(RepoCloneToken)); err != nil {
+		return nil, err
+	}
+	if _, err := sess.Insert(t); err != nil {
+		return nil, err
+	}
+	return t, sess.Commit()
+}
+
+// GetRepoCloneToken returns the unexpired clone token with the given value
+func GetRepoCloneToken(token string) (*RepoCloneToken, error) {
+	// the prefix and a SHA1 sum of 40 hexadecimal bytes
+	if !IsRepoCloneToken(token) || len(token) != len(RepoCloneTokenPrefix)+40 {
+		return nil, ErrRepoCloneTokenNotExist{}
+	}
+
+	tokens := make([]*RepoCloneToken, 0, 1)
+	if err := db.GetEngine(db.DefaultContext).
+		Where("token_last_eight = ? AND expires_unix > ?", token[len(token)-8:], timeutil.TimeStampNow()).
+		Find(&tokens); err != nil {
+		return nil, err
+	}
+	for _, t := range tokens {
+		tempHash := login.HashToken(token, t.TokenSalt)
+		if subtle.ConstantTimeCompare([]byte(t.TokenHash), []byte(tempHash)) == 1 {
+			return t, nil
+		}
+	}
+	return nil, ErrRepoCloneTokenNotExist{}
+}
+
+// GetRepoCloneTokens returns the unexpired clone tokens of the user to the repository
+func GetRepoCloneTokens(repoID, uid int64) ([]*RepoCloneToken, error) {
+	tokens := make([]*RepoCloneToken, 0, 5)
+	return tokens, db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND uid = ? AND expires_unix > ?", repoID, uid, timeutil.TimeStampNow()).
+		Desc("id").
+		Find(&tokens)
+}
+
+// DeleteRepoCloneToken revokes the clone token of the user to the repository
+func DeleteRepoCloneToken(repoID, uid, id int64) error {
+	cnt, err := db.GetEngine(db.DefaultContext).ID(id).Delete(&RepoCloneToken{RepoID: repoID, UID: uid})
+	if err != nil {
+		return err
+	} else if cnt != 1 {
+		return ErrRepoCloneTokenNotExist{ID: id}
+	}
+	return nil
+}
@@ -17,10 +17,12 @@ import (
 
 // Collaboration represent the relation between an individual and a repository.
 type Collaboration struct {
-	ID          int64              `xorm:"pk autoincr"`
-	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	Mode        AccessMode         `xorm:"DEFAULT 2 NOT NULL"`
+	ID     int64      `xorm:"pk autoincr"`
+	RepoID int64      `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	UserID int64      `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Mode   AccessMode `xorm:"DEFAULT 2 NOT NULL"`
+	// WikiMode raises the access mode to the wiki above Mode, e.g. to let readers of the code edit the wiki
+	WikiMode    AccessMode         `xorm:"NOT NULL DEFAULT 0"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -29,6 +31,13 @@ func init() {
 	db.RegisterModel(new(Collaboration))
 }
 
+// applyWikiMode raises the access mode to the wiki, if it is enabled, to the wiki access mode of the collaboration
+func (c *Collaboration) applyWikiMode(unitsMode map[UnitType]AccessMode) {
+	if mode, ok := unitsMode[UnitTypeWiki]; ok && mode < c.WikiMode {
+		unitsMode[UnitTypeWiki] = c.WikiMode
+	}
+}
+
 func (repo *Repository) addCollaborator(e db.Engine, u *User) error {
 	collaboration := &Collaboration{
 		RepoID: repo.ID,
@@ -188,6 +197,21 @@ func (repo *Repository) ChangeCollaborationAccessMode(uid int64, mode AccessMode
 	return sess.Commit()
 }
 
+// ChangeCollaborationWikiAccessMode sets the wiki access mode of the collaboration,
+// only write access or none to use the access mode of the collaboration are valid.
+func (repo *Repository) ChangeCollaborationWikiAccessMode(uid int64, mode AccessMode) error {
+	// Discard invalid input
+	if mode != AccessModeNone && mode != AccessModeWrite {
+		return nil
+	}
+
+	_, err := db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND user_id = ?", repo.ID, uid).
+		Cols("wiki_mode").
+		Update(&Collaboration{WikiMode: mode})
+	return err
+}
+
 // DeleteCollaboration removes collaboration relation between the user and repository.
 func (repo *Repository) DeleteCollaboration(uid int64) (err error) {
 	collaboration := &Collaboration{
@@ -70,12 +70,19 @@ func (gt GiteaTemplate) Globs() []glob.Glob {
 
 // GenerateTopics generates topics from a template repository
 func GenerateTopics(ctx context.Context, templateRepo, generateRepo *Repository) error {
-	for _, topic := range templateRepo.Topics {
-		if _, err := addTopicByNameToRepo(db.GetEngine(ctx), generateRepo.ID, topic); err != nil {
+	e := db.GetEngine(ctx)
+	topicNames := make([]string, 0, 25)
+	if err := e.Table("topic").Cols("name").
+		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
+		Where("repo_topic.repo_id = ?", templateRepo.ID).Find(&topicNames); err != nil {
+		return err
+	}
+	for _, topic := range topicNames {
+		if _, err := addTopicByNameToRepo(e, generateRepo.ID, topic); err != nil {
 			return err
 		}
 	}
-	return nil
+	return updateRepoTopicsCache(e, generateRepo.ID)
 }
 
 // GenerateGitHooks generates git hooks from a template repository
@@ -0,0 +1,88 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoGitSettings represents the git protocol settings of a repository,
+// each of them overrides the default of the instance unless it is nil
+type RepoGitSettings struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE"`
+	// only serve the smart HTTP protocol
+	DisableDumbHTTP *bool
+	// filter spec recommended to clone the repository, e.g. blob:none, empty for none
+	DefaultCloneFilter *string `xorm:"VARCHAR(255)"`
+	// size in MB above which clones must be partial or shallow, 0 allows full clones of any size
+	FullCloneMaxSize *int64
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoGitSettings))
+}
+
+// IsDumbHTTPDisabled returns true if the repository is only served by the smart HTTP protocol
+func (s *RepoGitSettings) IsDumbHTTPDisabled() bool {
+	if s.DisableDumbHTTP != nil {
+		return *s.DisableDumbHTTP
+	}
+	return setting.Repository.DisableDumbHTTP
+}
+
+// CloneFilter returns the filter spec recommended to clone the repository, empty for none
+func (s *RepoGitSettings) CloneFilter() string {
+	if s.DefaultCloneFilter != nil {
+		return *s.DefaultCloneFilter
+	}
+	return setting.Repository.DefaultCloneFilter
+}
+
+// FullCloneMaxBytes returns the size in bytes above which clones must be partial or shallow, 0 for no limit
+func (s *RepoGitSettings) FullCloneMaxBytes() int64 {
+	size := setting.Repository.FullCloneMaxSize
+	if s.FullCloneMaxSize != nil {
+		size = *s.FullCloneMaxSize
+	}
+	if size < 0 {
+		return 0
+	}
+	return size * 1024 * 1024
+}
+
+// GetRepoGitSettings returns the git protocol settings of a repository,
+// the repository follows the defaults of the instance if it has none
+func GetRepoGitSettings(repoID int64) (*RepoGitSettings, error) {
+	s := &RepoGitSettings{RepoID: repoID}
+	if _, err := db.GetEngine(db.DefaultContext).Where("repo_id=?", repoID).Get(s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// UpdateRepoGitSettings creates or updates the git protocol settings of a repository
+func UpdateRepoGitSettings(s *RepoGitSettings) error {
+	e := db.GetEngine(db.DefaultContext)
+	if s.ID == 0 {
+		_, err := e.Insert(s)
+		return err
+	}
+	_, err := e.ID(s.ID).AllCols().Update(s)
+	return err
+}
+
+// LoadGitSettings loads the git protocol settings of the repository
+func (repo *Repository) LoadGitSettings() (err error) {
+	if repo.GitSettings == nil {
+		repo.GitSettings, err = GetRepoGitSettings(repo.ID)
+	}
+	return err
+}
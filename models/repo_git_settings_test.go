@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoGitSettings(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defer func(dumb bool, filter string, size int64) {
+		setting.Repository.DisableDumbHTTP = dumb
+		setting.Repository.DefaultCloneFilter = filter
+		setting.Repository.FullCloneMaxSize = size
+	}(setting.Repository.DisableDumbHTTP, setting.Repository.DefaultCloneFilter, setting.Repository.FullCloneMaxSize)
+	setting.Repository.DisableDumbHTTP = true
+	setting.Repository.DefaultCloneFilter = "blob:none"
+	setting.Repository.FullCloneMaxSize = 10
+
+	settings, err := GetRepoGitSettings(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, settings.ID)
+	assert.True(t, settings.IsDumbHTTPDisabled())
+	assert.Equal(t, "blob:none", settings.CloneFilter())
+	assert.EqualValues(t, 10*1024*1024, settings.FullCloneMaxBytes())
+
+	disabled, filter, size := false, "", int64(0)
+	settings.DisableDumbHTTP = &disabled
+	settings.DefaultCloneFilter = &filter
+	settings.FullCloneMaxSize = &size
+	assert.NoError(t, UpdateRepoGitSettings(settings))
+	assert.NotZero(t, settings.ID)
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, repo.LoadGitSettings())
+	assert.False(t, repo.GitSettings.IsDumbHTTPDisabled())
+	assert.Empty(t, repo.GitSettings.CloneFilter())
+	assert.Zero(t, repo.GitSettings.FullCloneMaxBytes())
+	assert.Empty(t, repo.CloneLink().Filter)
+
+	repo.GitSettings.DefaultCloneFilter = nil
+	assert.NoError(t, UpdateRepoGitSettings(repo.GitSettings))
+	repo.GitSettings = nil
+	assert.NoError(t, repo.LoadGitSettings())
+	assert.Equal(t, "blob:none", repo.CloneLink().Filter)
+	assert.Empty(t, repo.WikiCloneLink().Filter)
+}
@@ -19,6 +19,8 @@ const (
 	RepoIndexerTypeCode RepoIndexerType = iota // 0
 	// RepoIndexerTypeStats repository stats indexer
 	RepoIndexerTypeStats // 1
+	// RepoIndexerTypeLicenses repository license detection
+	RepoIndexerTypeLicenses // 2
 )
 
 // RepoIndexerStatus status of a repo's entry in the repo indexer
@@ -26,7 +28,7 @@ const (
 type RepoIndexerStatus struct {
 	ID          int64           `xorm:"pk autoincr"`
 	RepoID      int64           `xorm:"INDEX(s)"`
-	CommitSha   string          `xorm:"VARCHAR(40)"`
+	CommitSha   string          `xorm:"VARCHAR(64)"`
 	IndexerType RepoIndexerType `xorm:"INDEX(s) NOT NULL DEFAULT 0"`
 }
 
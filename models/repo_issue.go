@@ -4,7 +4,10 @@
 
 package models
 
-import "code.gitea.io/gitea/modules/setting"
+import (
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+)
 
 // ___________.__             ___________                     __
 // \__    ___/|__| _____   ___\__    ___/___________    ____ |  | __ ___________
@@ -33,6 +36,21 @@ func (repo *Repository) IsTimetrackerEnabled() bool {
 	return u.IssuesConfig().EnableTimetracker
 }
 
+// IssueKeywords returns the keywords closing and reopening referenced issues of the repository,
+// which replace the additional keywords of the instance if not empty
+func (repo *Repository) IssueKeywords() (closeKeywords, reopenKeywords []string) {
+	return repo.issueKeywords(db.GetEngine(db.DefaultContext))
+}
+
+func (repo *Repository) issueKeywords(e db.Engine) (closeKeywords, reopenKeywords []string) {
+	u, err := repo.getUnit(e, UnitTypeIssues)
+	if err != nil {
+		return nil, nil
+	}
+	cfg := u.IssuesConfig()
+	return cfg.CloseKeywords, cfg.ReopenKeywords
+}
+
 // AllowOnlyContributorsToTrackTime returns value of IssuesConfig or the default value
 func (repo *Repository) AllowOnlyContributorsToTrackTime() bool {
 	var u *RepoUnit
@@ -0,0 +1,86 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoLicense is a license detected in a license file of the default branch of a repository
+type RepoLicense struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Path        string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	License     string             `xorm:"VARCHAR(100) UNIQUE(s) INDEX NOT NULL"`
+	BlobID      string             `xorm:"VARCHAR(64)"`
+	CommitID    string             `xorm:"VARCHAR(64)"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX CREATED"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoLicense))
+}
+
+// RepoLicenseList defines a list of detected licenses
+type RepoLicenseList []*RepoLicense
+
+// Names returns the sorted names of the licenses without duplicates
+func (licenses RepoLicenseList) Names() []string {
+	names := make([]string, 0, len(licenses))
+	seen := make(map[string]bool, len(licenses))
+	for _, l := range licenses {
+		if !seen[l.License] {
+			seen[l.License] = true
+			names = append(names, l.License)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (repo *Repository) getLicenses(e db.Engine) (RepoLicenseList, error) {
+	licenses := make(RepoLicenseList, 0, 2)
+	return licenses, e.Where(builder.Eq{"repo_id": repo.ID}).Asc("path", "license").Find(&licenses)
+}
+
+// GetLicenses returns the licenses detected in the license files of the repository
+func (repo *Repository) GetLicenses() (RepoLicenseList, error) {
+	return repo.getLicenses(db.GetEngine(db.DefaultContext))
+}
+
+// UpdateLicenses replaces the licenses detected in the license files of the repository
+// with the licenses detected at the commit
+func (repo *Repository) UpdateLicenses(commitID string, licenses RepoLicenseList) error {
+	sess := db.NewSession(db.DefaultContext)
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+	defer sess.Close()
+
+	if _, err := sess.Delete(&RepoLicense{RepoID: repo.ID}); err != nil {
+		return err
+	}
+	for _, l := range licenses {
+		l.ID = 0
+		l.RepoID = repo.ID
+		l.CommitID = commitID
+	}
+	if len(licenses) > 0 {
+		if _, err := sess.Insert(&licenses); err != nil {
+			return err
+		}
+	}
+
+	if err := repo.updateIndexerStatus(sess, RepoIndexerTypeLicenses, commitID); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateLicenses(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.NoError(t, repo.UpdateLicenses("commit1", RepoLicenseList{
+		{Path: "LICENSE-MIT", License: "MIT", BlobID: "blob1"},
+		{Path: "LICENSE-APACHE", License: "Apache-2.0", BlobID: "blob2"},
+		{Path: "COPYING", License: "MIT", BlobID: "blob3"},
+	}))
+
+	licenses, err := repo.GetLicenses()
+	assert.NoError(t, err)
+	assert.Len(t, licenses, 3)
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, licenses.Names())
+
+	status, err := repo.GetIndexerStatus(RepoIndexerTypeLicenses)
+	assert.NoError(t, err)
+	assert.Equal(t, "commit1", status.CommitSha)
+
+	repos, count, err := SearchRepositoryByName(&SearchRepoOptions{AllPublic: true, License: "MIT"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	// The licenses of the previous commit are replaced
+	assert.NoError(t, repo.UpdateLicenses("commit2", RepoLicenseList{
+		{Path: "LICENSE", License: "other", BlobID: "blob4"},
+	}))
+	licenses, err = repo.GetLicenses()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"other"}, licenses.Names())
+	assert.Equal(t, "commit2", licenses[0].CommitID)
+
+	_, count, err = SearchRepositoryByName(&SearchRepoOptions{AllPublic: true, License: "MIT"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
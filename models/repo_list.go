@@ -179,6 +179,10 @@ type SearchRepoOptions struct {
 	// True -> include just has milestones
 	// False -> include just has no milestone
 	HasMilestones util.OptionalBool
+	// only include repositories which came to exist this way, empty for any
+	CreatedFrom RepoCreatedFrom
+	// only include repositories with this license detected, empty for any
+	License string
 	// LowerNames represents valid lower names to restrict to
 	LowerNames []string
 }
@@ -206,8 +210,25 @@ const (
 	SearchOrderByStarsReverse          SearchOrderBy = "num_stars DESC"
 	SearchOrderByForks                 SearchOrderBy = "num_forks ASC"
 	SearchOrderByForksReverse          SearchOrderBy = "num_forks DESC"
+	SearchOrderByOpenIssues            SearchOrderBy = "num_issues - num_closed_issues ASC"
+	SearchOrderByOpenIssuesReverse     SearchOrderBy = "num_issues - num_closed_issues DESC"
+	SearchOrderByOpenPulls             SearchOrderBy = "num_pulls - num_closed_pulls ASC"
+	SearchOrderByOpenPullsReverse      SearchOrderBy = "num_pulls - num_closed_pulls DESC"
 )
 
+// Strings for sorting pull mirrors, they restrict the result to pull mirrors
+const (
+	SearchOrderByMirrorNextUpdate        SearchOrderBy = "`mirror`.next_update_unix ASC"
+	SearchOrderByMirrorNextUpdateReverse SearchOrderBy = "`mirror`.next_update_unix DESC"
+	SearchOrderByMirrorRecentUpdated     SearchOrderBy = "`mirror`.updated_unix DESC"
+	// The update time of a mirror only changes on a successful sync, so failing mirrors are the least updated
+	SearchOrderByMirrorFailingFirst SearchOrderBy = "`mirror`.updated_unix ASC"
+)
+
+func (s SearchOrderBy) isMirrorOrder() bool {
+	return strings.Contains(string(s), "`mirror`.")
+}
+
 // SearchRepositoryCondition creates a query condition according search repository options
 func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 	cond := builder.NewCond()
@@ -238,12 +259,12 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 
 	// Restrict to starred repositories
 	if opts.StarredByID > 0 {
-		cond = cond.And(builder.In("id", builder.Select("repo_id").From("star").Where(builder.Eq{"uid": opts.StarredByID})))
+		cond = cond.And(builder.In("`repository`.id", builder.Select("repo_id").From("star").Where(builder.Eq{"uid": opts.StarredByID})))
 	}
 
 	// Restrict to watched repositories
 	if opts.WatchedByID > 0 {
-		cond = cond.And(builder.In("id", builder.Select("repo_id").From("watch").Where(builder.Eq{"user_id": opts.WatchedByID})))
+		cond = cond.And(builder.In("`repository`.id", builder.Select("repo_id").From("watch").Where(builder.Eq{"user_id": opts.WatchedByID})))
 	}
 
 	// Restrict repositories to those the OwnerID owns or contributes to as per opts.Collaborate
@@ -320,7 +341,7 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 			Where(subQueryCond).
 			GroupBy("repo_topic.repo_id")
 
-		keywordCond := builder.In("id", subQuery)
+		keywordCond := builder.In("`repository`.id", subQuery)
 		if !opts.TopicOnly {
 			likes := builder.NewCond()
 			for _, v := range strings.Split(opts.Keyword, ",") {
@@ -346,6 +367,16 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(accessibleRepositoryCondition(opts.Actor))
 	}
 
+	if opts.CreatedFrom != "" {
+		cond = cond.And(builder.Eq{"created_from": string(opts.CreatedFrom)})
+	}
+
+	if opts.License != "" {
+		cond = cond.And(builder.In("id", builder.Select("repo_id").
+			From("repo_license").
+			Where(builder.Eq{"license": opts.License})))
+	}
+
 	if opts.Archived != util.OptionalBoolNone {
 		cond = cond.And(builder.Eq{"is_archived": opts.Archived == util.OptionalBoolTrue})
 	}
@@ -412,9 +443,17 @@ func searchRepositoryByCondition(opts *SearchRepoOptions, cond builder.Cond) (*x
 
 	sess := db.NewSession(db.DefaultContext)
 
+	// Sorting by the mirror timestamps joins the mirror table so its indexes can be used
+	joinMirror := func() {
+		if opts.OrderBy.isMirrorOrder() {
+			sess.Join("INNER", "mirror", "`mirror`.repo_id = `repository`.id")
+		}
+	}
+
 	var count int64
 	if opts.PageSize > 0 {
 		var err error
+		joinMirror()
 		count, err = sess.
 			Where(cond).
 			Count(new(Repository))
@@ -424,6 +463,7 @@ func searchRepositoryByCondition(opts *SearchRepoOptions, cond builder.Cond) (*x
 		}
 	}
 
+	joinMirror()
 	sess.Where(cond).OrderBy(opts.OrderBy.String())
 	if opts.PageSize > 0 {
 		sess.Limit(opts.PageSize, (opts.Page-1)*opts.PageSize)
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
@@ -356,3 +357,97 @@ func TestSearchRepositoryByTopicName(t *testing.T) {
 		})
 	}
 }
+
+func TestSearchRepositoryByCreatedFrom(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	_, err := db.GetEngine(db.DefaultContext).ID(1).Cols("created_from").Update(&Repository{CreatedFrom: RepoCreatedFromMigrated})
+	assert.NoError(t, err)
+
+	repos, count, err := SearchRepositoryByName(&SearchRepoOptions{AllPublic: true, CreatedFrom: RepoCreatedFromMigrated})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, repos, 1) {
+		assert.EqualValues(t, 1, repos[0].ID)
+	}
+
+	_, count, err = SearchRepositoryByName(&SearchRepoOptions{AllPublic: true, CreatedFrom: RepoCreatedFromAdopted})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	createdFrom, ok := ToRepoCreatedFrom(" Pushed-To-Create")
+	assert.True(t, ok)
+	assert.Equal(t, RepoCreatedFromPushed, createdFrom)
+	_, ok = ToRepoCreatedFrom("cloned")
+	assert.False(t, ok)
+}
+
+func TestGetUserMirrorRepositories(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// repository 25 was synced more recently but is scheduled later than repository 26
+	for _, m := range []struct {
+		repoID                      int64
+		updatedUnix, nextUpdateUnix timeutil.TimeStamp
+	}{
+		{25, 300, 900},
+		{26, 100, 500},
+	} {
+		mirror := &Mirror{RepoID: m.repoID}
+		_, err := db.GetEngine(db.DefaultContext).Insert(mirror)
+		assert.NoError(t, err)
+		mirror.UpdatedUnix = m.updatedUnix
+		mirror.NextUpdateUnix = m.nextUpdateUnix
+		assert.NoError(t, UpdateMirror(mirror))
+	}
+
+	testCases := []struct {
+		orderBy SearchOrderBy
+		page    int
+		repoID  int64
+	}{
+		{SearchOrderByMirrorNextUpdate, 1, 26},
+		{SearchOrderByMirrorNextUpdate, 2, 25},
+		{SearchOrderByMirrorRecentUpdated, 1, 25},
+		{SearchOrderByMirrorFailingFirst, 1, 26},
+	}
+	for _, testCase := range testCases {
+		repos, count, err := GetUserMirrorRepositories(20, db.ListOptions{Page: testCase.page, PageSize: 1}, testCase.orderBy)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 2, count)
+		if assert.Len(t, repos, 1) {
+			assert.EqualValues(t, testCase.repoID, repos[0].ID)
+		}
+	}
+
+	// Sorting by the mirror timestamps only returns repositories with a mirror
+	repos, count, err := SearchRepository(&SearchRepoOptions{
+		ListOptions: db.ListOptions{Page: 1, PageSize: 10},
+		Private:     true,
+		OrderBy:     SearchOrderByMirrorNextUpdate,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, repos, 2) {
+		assert.EqualValues(t, 26, repos[0].ID)
+		assert.EqualValues(t, 25, repos[1].ID)
+	}
+}
+
+func TestSearchRepositoryOrderByOpenIssues(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repos, count, err := SearchRepository(&SearchRepoOptions{
+		ListOptions: db.ListOptions{Page: 1, PageSize: 10},
+		OwnerID:     2,
+		Collaborate: util.OptionalBoolFalse,
+		Private:     true,
+		OrderBy:     SearchOrderByOpenIssuesReverse,
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, count)
+	for i := 1; i < len(repos); i++ {
+		assert.GreaterOrEqual(t, repos[i-1].NumOpenIssues, repos[i].NumOpenIssues)
+	}
+	assert.EqualValues(t, 1, repos[0].NumOpenIssues)
+}
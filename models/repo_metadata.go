@@ -0,0 +1,197 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoMetadataMaxValueSize is the maximum size in bytes of a single repository metadata value
+const RepoMetadataMaxValueSize = 64 * 1024
+
+var repoMetadataKeyPattern = regexp.MustCompile(`^[a-zA-Z0-9_][a-zA-Z0-9_.-]{0,254}$`)
+
+// IsValidRepoMetadataKey checks if a key can be used for repository metadata
+func IsValidRepoMetadataKey(key string) bool {
+	return repoMetadataKeyPattern.MatchString(key)
+}
+
+// RepoMetadata represents an opaque value stored under a key for a repository
+type RepoMetadata struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	Key         string             `xorm:"'metadata_key' UNIQUE(s) VARCHAR(255) NOT NULL"`
+	Value       string             `xorm:"LONGTEXT"`
+	Size        int64              `xorm:"NOT NULL DEFAULT 0"`
+	Version     int64              `xorm:"NOT NULL DEFAULT 1"`
+	UpdatedByID int64              `xorm:"NOT NULL DEFAULT 0"`
+	UpdatedBy   *User              `xorm:"-"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoMetadata))
+}
+
+// ETag returns the entity tag of the current value of the entry
+func (m *RepoMetadata) ETag() string {
+	return fmt.Sprintf(`"%d-%d"`, m.ID, m.Version)
+}
+
+// MatchETag returns true if the value of an If-Match header matches the entity tag of the entry
+func (m *RepoMetadata) MatchETag(ifMatch string) bool {
+	for _, tag := range strings.Split(ifMatch, ",") {
+		if tag = strings.TrimSpace(tag); tag == "*" || tag == m.ETag() {
+			return true
+		}
+	}
+	return false
+}
+
+// LoadUpdatedBy loads the user who last updated the entry
+func (m *RepoMetadata) LoadUpdatedBy() (err error) {
+	if m.UpdatedBy == nil {
+		m.UpdatedBy, err = GetUserByID(m.UpdatedByID)
+		if IsErrUserNotExist(err) {
+			m.UpdatedBy = NewGhostUser()
+			err = nil
+		}
+	}
+	return
+}
+
+func getRepoMetadata(e db.Engine, repoID int64, key string) (*RepoMetadata, error) {
+	m := new(RepoMetadata)
+	has, err := e.Where("repo_id = ? AND metadata_key = ?", repoID, key).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoMetadataNotExist{RepoID: repoID, Key: key}
+	}
+	return m, nil
+}
+
+// GetRepoMetadata returns the metadata entry of a repository with the given key
+func GetRepoMetadata(repoID int64, key string) (*RepoMetadata, error) {
+	return getRepoMetadata(db.GetEngine(db.DefaultContext), repoID, key)
+}
+
+// ListRepoMetadata returns the metadata entries of a repository ordered by their keys
+func ListRepoMetadata(repoID int64, listOptions db.ListOptions) ([]*RepoMetadata, int64, error) {
+	sess := db.GetEngine(db.DefaultContext).Where("repo_id = ?", repoID).Asc("metadata_key")
+	if listOptions.Page != 0 {
+		sess = db.SetSessionPagination(sess, &listOptions)
+	}
+	entries := make([]*RepoMetadata, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&entries)
+	return entries, count, err
+}
+
+// SetRepoMetadata creates or replaces the value of a metadata entry of a repository.
+// If ifMatch is not empty the entry must exist and its entity tag must match it.
+func SetRepoMetadata(repoID int64, key, value string, doer *User, ifMatch string) (*RepoMetadata, error) {
+	if !IsValidRepoMetadataKey(key) {
+		return nil, ErrRepoMetadataInvalidKey{Key: key}
+	}
+	size := int64(len(value))
+	if size > RepoMetadataMaxValueSize {
+		return nil, ErrRepoMetadataQuotaExceeded{RepoID: repoID, Reason: fmt.Sprintf("value is larger than %d bytes", RepoMetadataMaxValueSize)}
+	}
+
+	ctx, committer, err := db.TxContext()
+	if err != nil {
+		return nil, err
+	}
+	defer committer.Close()
+	sess := db.GetEngine(ctx)
+
+	m, err := getRepoMetadata(sess, repoID, key)
+	if err != nil && !IsErrRepoMetadataNotExist(err) {
+		return nil, err
+	}
+	exists := err == nil
+	if ifMatch != "" && (!exists || !m.MatchETag(ifMatch)) {
+		return nil, ErrRepoMetadataPreconditionFailed{RepoID: repoID, Key: key}
+	}
+
+	maxKeys := setting.Repository.Metadata.MaxKeys
+	if !exists && maxKeys > 0 {
+		count, err := sess.Where("repo_id = ?", repoID).Count(new(RepoMetadata))
+		if err != nil {
+			return nil, err
+		} else if count >= int64(maxKeys) {
+			return nil, ErrRepoMetadataQuotaExceeded{RepoID: repoID, Reason: fmt.Sprintf("repository already has %d keys", count)}
+		}
+	}
+	if maxTotalSize := setting.Repository.Metadata.MaxTotalSize; maxTotalSize > 0 {
+		total, err := sess.Where("repo_id = ?", repoID).SumInt(new(RepoMetadata), "size")
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			total -= m.Size
+		}
+		if total+size > maxTotalSize {
+			return nil, ErrRepoMetadataQuotaExceeded{RepoID: repoID, Reason: fmt.Sprintf("total size of all values would exceed %d bytes", maxTotalSize)}
+		}
+	}
+
+	if exists {
+		version := m.Version
+		m.Value = value
+		m.Size = size
+		m.Version = version + 1
+		m.UpdatedByID = doer.ID
+		// only update the version which was read to not lose a concurrent update
+		affected, err := sess.ID(m.ID).Where("version = ?", version).Cols("value", "size", "version", "updated_by_id").Update(m)
+		if err != nil {
+			return nil, err
+		} else if affected == 0 {
+			return nil, ErrRepoMetadataPreconditionFailed{RepoID: repoID, Key: key}
+		}
+	} else {
+		m = &RepoMetadata{
+			RepoID:      repoID,
+			Key:         key,
+			Value:       value,
+			Size:        size,
+			Version:     1,
+			UpdatedByID: doer.ID,
+		}
+		if _, err := sess.Insert(m); err != nil {
+			return nil, err
+		}
+	}
+	m.UpdatedBy = doer
+
+	return m, committer.Commit()
+}
+
+// DeleteRepoMetadata deletes a metadata entry of a repository.
+// If ifMatch is not empty the entity tag of the entry must match it.
+func DeleteRepoMetadata(repoID int64, key, ifMatch string) error {
+	sess := db.GetEngine(db.DefaultContext)
+	m, err := getRepoMetadata(sess, repoID, key)
+	if err != nil {
+		return err
+	}
+	if ifMatch != "" && !m.MatchETag(ifMatch) {
+		return ErrRepoMetadataPreconditionFailed{RepoID: repoID, Key: key}
+	}
+
+	affected, err := sess.ID(m.ID).Where("version = ?", m.Version).Delete(new(RepoMetadata))
+	if err != nil {
+		return err
+	} else if affected == 0 {
+		return ErrRepoMetadataPreconditionFailed{RepoID: repoID, Key: key}
+	}
+	return nil
+}
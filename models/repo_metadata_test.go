@@ -0,0 +1,81 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoMetadata(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	_, err := SetRepoMetadata(1, "../secret", "value", doer, "")
+	assert.True(t, IsErrRepoMetadataInvalidKey(err))
+
+	m, err := SetRepoMetadata(1, "last-deployed", "abc", doer, "")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, m.Version)
+	etag := m.ETag()
+
+	_, err = SetRepoMetadata(1, "other", "x", doer, etag)
+	assert.True(t, IsErrRepoMetadataPreconditionFailed(err))
+
+	m, err = SetRepoMetadata(1, "last-deployed", "def", doer, etag)
+	assert.NoError(t, err)
+	assert.NotEqual(t, etag, m.ETag())
+
+	// the old entity tag does not match anymore
+	_, err = SetRepoMetadata(1, "last-deployed", "ghi", doer, etag)
+	assert.True(t, IsErrRepoMetadataPreconditionFailed(err))
+	assert.True(t, IsErrRepoMetadataPreconditionFailed(DeleteRepoMetadata(1, "last-deployed", etag)))
+
+	m, err = GetRepoMetadata(1, "last-deployed")
+	assert.NoError(t, err)
+	assert.Equal(t, "def", m.Value)
+
+	entries, count, err := ListRepoMetadata(1, db.ListOptions{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Len(t, entries, 1)
+
+	assert.NoError(t, DeleteRepoMetadata(1, "last-deployed", m.ETag()))
+	_, err = GetRepoMetadata(1, "last-deployed")
+	assert.True(t, IsErrRepoMetadataNotExist(err))
+}
+
+func TestRepoMetadataQuota(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	doer := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	defer func(maxKeys int, maxTotalSize int64) {
+		setting.Repository.Metadata.MaxKeys = maxKeys
+		setting.Repository.Metadata.MaxTotalSize = maxTotalSize
+	}(setting.Repository.Metadata.MaxKeys, setting.Repository.Metadata.MaxTotalSize)
+	setting.Repository.Metadata.MaxKeys = 2
+	setting.Repository.Metadata.MaxTotalSize = 10
+
+	_, err := SetRepoMetadata(1, "too-large", strings.Repeat("x", RepoMetadataMaxValueSize+1), doer, "")
+	assert.True(t, IsErrRepoMetadataQuotaExceeded(err))
+
+	_, err = SetRepoMetadata(1, "a", "12345", doer, "")
+	assert.NoError(t, err)
+	_, err = SetRepoMetadata(1, "b", "123456", doer, "")
+	assert.True(t, IsErrRepoMetadataQuotaExceeded(err))
+	_, err = SetRepoMetadata(1, "b", "12345", doer, "")
+	assert.NoError(t, err)
+	_, err = SetRepoMetadata(1, "c", "", doer, "")
+	assert.True(t, IsErrRepoMetadataQuotaExceeded(err))
+
+	// replacing a value only counts the new size
+	_, err = SetRepoMetadata(1, "a", "1234", doer, "")
+	assert.NoError(t, err)
+}
@@ -6,6 +6,7 @@
 package models
 
 import (
+	"context"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -127,3 +128,23 @@ func InsertMirror(mirror *Mirror) error {
 	_, err := db.GetEngine(db.DefaultContext).Insert(mirror)
 	return err
 }
+
+// ConvertRepositoryToMirror inserts the mirror and marks its repository as a mirror
+func ConvertRepositoryToMirror(ctx context.Context, m *Mirror) error {
+	e := db.GetEngine(ctx)
+	if _, err := e.Insert(m); err != nil {
+		return err
+	}
+	m.Repo.IsMirror = true
+	return updateRepositoryCols(e, m.Repo, "is_mirror", "original_url")
+}
+
+// ConvertMirrorToRepository deletes the mirror of a repository and marks it as a normal repository
+func ConvertMirrorToRepository(ctx context.Context, repo *Repository) error {
+	e := db.GetEngine(ctx)
+	if _, err := e.Delete(&Mirror{RepoID: repo.ID}); err != nil {
+		return err
+	}
+	repo.IsMirror = false
+	return updateRepositoryCols(e, repo, "is_mirror")
+}
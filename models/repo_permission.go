@@ -138,6 +138,25 @@ func (p *Permission) ColorFormat(s fmt.State) {
 	log.ColorFprintf(s, format, args...)
 }
 
+// GetDeployKeyRepoPermission returns the permissions of the fake user acting for the deploy key
+// to the repository, it may only read the code of the repository of the key
+func GetDeployKeyRepoPermission(repo *Repository, key *DeployKey) (perm Permission, err error) {
+	perm.UnitsMode = make(map[UnitType]AccessMode)
+	if key.RepoID != repo.ID {
+		return
+	}
+	if err = repo.getUnits(db.GetEngine(db.DefaultContext)); err != nil {
+		return
+	}
+	perm.Units = repo.Units
+	for _, u := range repo.Units {
+		if u.Type == UnitTypeCode {
+			perm.UnitsMode[UnitTypeCode] = AccessModeRead
+		}
+	}
+	return
+}
+
 // GetUserRepoPermission returns the user permissions to the repository
 func GetUserRepoPermission(repo *Repository, user *User) (Permission, error) {
 	return getUserRepoPermission(db.GetEngine(db.DefaultContext), repo, user)
@@ -165,13 +184,14 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 		return
 	}
 
-	var isCollaborator bool
+	var collaboration *Collaboration
 	if user != nil {
-		isCollaborator, err = repo.isCollaborator(e, user.ID)
+		collaboration, err = repo.getCollaboration(e, user.ID)
 		if err != nil {
 			return perm, err
 		}
 	}
+	isCollaborator := collaboration != nil
 
 	if err = repo.getOwner(e); err != nil {
 		return
@@ -212,6 +232,13 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 		return
 	}
 	if !repo.Owner.IsOrganization() {
+		if isCollaborator && collaboration.WikiMode > perm.AccessMode {
+			perm.UnitsMode = make(map[UnitType]AccessMode)
+			for _, u := range repo.Units {
+				perm.UnitsMode[u.Type] = perm.AccessMode
+			}
+			collaboration.applyWikiMode(perm.UnitsMode)
+		}
 		return
 	}
 
@@ -222,6 +249,7 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 		for _, u := range repo.Units {
 			perm.UnitsMode[u.Type] = perm.AccessMode
 		}
+		collaboration.applyWikiMode(perm.UnitsMode)
 	}
 
 	// get units mode from teams
@@ -242,10 +270,9 @@ func getUserRepoPermission(e db.Engine, repo *Repository, user *User) (perm Perm
 	for _, u := range repo.Units {
 		var found bool
 		for _, team := range teams {
-			if team.unitEnabled(e, u.Type) {
-				m := perm.UnitsMode[u.Type]
-				if m < team.Authorize {
-					perm.UnitsMode[u.Type] = team.Authorize
+			if mode := team.unitAccessMode(e, u.Type); mode > AccessModeNone {
+				if perm.UnitsMode[u.Type] < mode {
+					perm.UnitsMode[u.Type] = mode
 				}
 				found = true
 			}
@@ -256,3 +256,90 @@ func TestRepoPermissionPrivateOrgRepo(t *testing.T) {
 		assert.True(t, perm.CanWrite(unit.Type))
 	}
 }
+
+func TestRepoPermissionDeployKey(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// private repo
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	assert.NoError(t, repo.getUnits(db.GetEngine(db.DefaultContext)))
+
+	// the key may only read the code of its own repository
+	perm, err := GetDeployKeyRepoPermission(repo, &DeployKey{RepoID: repo.ID, CanWriteStatuses: true})
+	assert.NoError(t, err)
+	assert.True(t, perm.HasAccess())
+	for _, unit := range repo.Units {
+		assert.Equal(t, unit.Type == UnitTypeCode, perm.CanRead(unit.Type))
+		assert.False(t, perm.CanWrite(unit.Type))
+	}
+
+	perm, err = GetDeployKeyRepoPermission(repo, &DeployKey{RepoID: 1, CanWriteStatuses: true})
+	assert.NoError(t, err)
+	assert.False(t, perm.HasAccess())
+}
+
+func TestRepoPermissionWikiAccessMode(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// private non-organization repo
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.NoError(t, repo.AddCollaborator(user))
+	assert.NoError(t, repo.ChangeCollaborationAccessMode(user.ID, AccessModeRead))
+	assert.NoError(t, repo.ChangeCollaborationWikiAccessMode(user.ID, AccessModeWrite))
+
+	perm, err := GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.HasAccess())
+	assert.True(t, perm.CanWrite(UnitTypeWiki))
+	assert.True(t, perm.CanRead(UnitTypeCode))
+	assert.False(t, perm.CanWrite(UnitTypeCode))
+	assert.False(t, perm.CanWrite(UnitTypeIssues))
+
+	assert.NoError(t, repo.ChangeCollaborationWikiAccessMode(user.ID, AccessModeNone))
+	perm, err = GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanRead(UnitTypeWiki))
+	assert.False(t, perm.CanWrite(UnitTypeWiki))
+
+	// private organization repo
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 3}).(*Repository)
+	user = db.AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	assert.NoError(t, repo.AddCollaborator(user))
+	assert.NoError(t, repo.ChangeCollaborationAccessMode(user.ID, AccessModeRead))
+	assert.NoError(t, repo.ChangeCollaborationWikiAccessMode(user.ID, AccessModeWrite))
+	perm, err = GetUserRepoPermission(repo, user)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeWiki))
+	assert.True(t, perm.CanRead(UnitTypeCode))
+	assert.False(t, perm.CanWrite(UnitTypeCode))
+
+	// team with read access to the code and write access to the wiki
+	team := &Team{
+		OrgID:     repo.OwnerID,
+		Name:      "wiki-writers",
+		Authorize: AccessModeRead,
+		Units: []*TeamUnit{
+			{OrgID: repo.OwnerID, Type: UnitTypeCode},
+			{OrgID: repo.OwnerID, Type: UnitTypeWiki, AccessMode: AccessModeWrite},
+		},
+	}
+	assert.NoError(t, NewTeam(team))
+	assert.NoError(t, team.AddRepository(repo))
+	member := db.AssertExistsAndLoadBean(t, &User{ID: 20}).(*User)
+	assert.NoError(t, AddTeamMember(team, member.ID))
+	assert.Equal(t, map[string]string{"repo.code": "read", "repo.wiki": "write"}, team.GetUnitsMap())
+
+	perm, err = GetUserRepoPermission(repo, member)
+	assert.NoError(t, err)
+	assert.True(t, perm.CanWrite(UnitTypeWiki))
+	assert.True(t, perm.CanRead(UnitTypeCode))
+	assert.False(t, perm.CanWrite(UnitTypeCode))
+	assert.False(t, perm.CanRead(UnitTypeIssues))
+
+	// administrators have the same access to all units
+	team.Authorize = AccessModeAdmin
+	assert.Equal(t, AccessModeAdmin, team.UnitAccessMode(UnitTypeWiki))
+	assert.Equal(t, AccessModeAdmin, team.UnitAccessMode(UnitTypeCode))
+	assert.Equal(t, AccessModeNone, team.UnitAccessMode(UnitTypeIssues))
+}
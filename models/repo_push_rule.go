@@ -0,0 +1,170 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/gobwas/glob"
+)
+
+// PushRule represents the rules every push to a repository has to follow
+type PushRule struct {
+	ID                       int64              `xorm:"pk autoincr"`
+	RepoID                   int64              `xorm:"UNIQUE"`
+	ForbiddenPaths           []string           `xorm:"JSON TEXT"`
+	MaxFileSize              int64              `xorm:"NOT NULL DEFAULT 0"`
+	MaxPathLength            int                `xorm:"NOT NULL DEFAULT 0"`
+	ForbiddenCommitterEmails []string           `xorm:"JSON TEXT"`
+	ScanSecrets              bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix              timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix              timeutil.TimeStamp `xorm:"updated"`
+
+	forbiddenPathGlobs  []glob.Glob `xorm:"-"`
+	forbiddenEmailGlobs []glob.Glob `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(PushRule))
+}
+
+// GetPushRule returns the push rule of a repository, or an empty one if the repository has none
+func GetPushRule(repoID int64) (*PushRule, error) {
+	rule := &PushRule{RepoID: repoID}
+	if _, err := db.GetEngine(db.DefaultContext).Where("repo_id=?", repoID).Get(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdatePushRule creates or updates the push rule of a repository
+func UpdatePushRule(rule *PushRule) error {
+	for _, patterns := range [][]string{rule.ForbiddenPaths, rule.ForbiddenCommitterEmails} {
+		if _, err := compilePushRuleGlobs(patterns); err != nil {
+			return err
+		}
+	}
+
+	e := db.GetEngine(db.DefaultContext)
+	if rule.ID == 0 {
+		_, err := e.Insert(rule)
+		return err
+	}
+	_, err := e.ID(rule.ID).AllCols().Update(rule)
+	return err
+}
+
+// GetEffectivePushRule returns the push rule of a repository combined with the instance wide push rules
+func GetEffectivePushRule(repoID int64) (*PushRule, error) {
+	rule, err := GetPushRule(repoID)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := setting.Repository.PushRules
+	rule.ForbiddenPaths = append(append([]string{}, defaults.ForbiddenPaths...), rule.ForbiddenPaths...)
+	rule.ForbiddenCommitterEmails = append(append([]string{}, defaults.ForbiddenCommitterEmails...), rule.ForbiddenCommitterEmails...)
+	if defaults.MaxFileSize > 0 && (rule.MaxFileSize <= 0 || defaults.MaxFileSize < rule.MaxFileSize) {
+		rule.MaxFileSize = defaults.MaxFileSize
+	}
+	if defaults.MaxPathLength > 0 && (rule.MaxPathLength <= 0 || defaults.MaxPathLength < rule.MaxPathLength) {
+		rule.MaxPathLength = defaults.MaxPathLength
+	}
+	rule.ScanSecrets = rule.ScanSecrets || defaults.ScanSecrets
+	return rule, nil
+}
+
+// IsEmpty returns true if the push rule does not restrict pushes at all
+func (rule *PushRule) IsEmpty() bool {
+	return len(rule.ForbiddenPaths) == 0 && len(rule.ForbiddenCommitterEmails) == 0 &&
+		rule.MaxFileSize <= 0 && rule.MaxPathLength <= 0 && !rule.ScanSecrets
+}
+
+// IsForbiddenPath returns true if the file path matches one of the forbidden path patterns.
+// Patterns without a slash are matched against the file name only.
+func (rule *PushRule) IsForbiddenPath(treePath string) bool {
+	if rule.forbiddenPathGlobs == nil {
+		rule.forbiddenPathGlobs = mustCompilePushRuleGlobs(rule.ForbiddenPaths)
+	}
+	for i, g := range rule.forbiddenPathGlobs {
+		if strings.Contains(rule.ForbiddenPaths[i], "/") {
+			if g.Match(treePath) {
+				return true
+			}
+		} else if g.Match(path.Base(treePath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsForbiddenCommitterEmail returns true if the email matches one of the forbidden committer email patterns
+func (rule *PushRule) IsForbiddenCommitterEmail(email string) bool {
+	if rule.forbiddenEmailGlobs == nil {
+		rule.forbiddenEmailGlobs = mustCompilePushRuleGlobs(rule.ForbiddenCommitterEmails)
+	}
+	email = strings.ToLower(email)
+	for _, g := range rule.forbiddenEmailGlobs {
+		if g.Match(email) {
+			return true
+		}
+	}
+	return false
+}
+
+func compilePushRuleGlobs(patterns []string) ([]glob.Glob, error) {
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(strings.TrimSpace(pattern), '/')
+		if err != nil {
+			return nil, ErrInvalidPushRulePattern{Pattern: pattern, Err: err}
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+// mustCompilePushRuleGlobs compiles the patterns, an invalid pattern never matches
+func mustCompilePushRuleGlobs(patterns []string) []glob.Glob {
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, pattern := range patterns {
+		g, err := glob.Compile(strings.TrimSpace(pattern), '/')
+		if err != nil {
+			log.Error("Invalid push rule pattern %q: %v", pattern, err)
+			g = neverMatchGlob{}
+		}
+		globs = append(globs, g)
+	}
+	return globs
+}
+
+type neverMatchGlob struct{}
+
+func (neverMatchGlob) Match(string) bool {
+	return false
+}
+
+// ErrInvalidPushRulePattern represents an invalid glob pattern in a push rule
+type ErrInvalidPushRulePattern struct {
+	Pattern string
+	Err     error
+}
+
+// IsErrInvalidPushRulePattern checks if an error is an ErrInvalidPushRulePattern
+func IsErrInvalidPushRulePattern(err error) bool {
+	_, ok := err.(ErrInvalidPushRulePattern)
+	return ok
+}
+
+func (err ErrInvalidPushRulePattern) Error() string {
+	return fmt.Sprintf("invalid push rule pattern [pattern: %s]: %v", err.Pattern, err.Err)
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushRule(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	rule, err := GetPushRule(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, rule.ID)
+	assert.True(t, rule.IsEmpty())
+
+	rule.ForbiddenPaths = []string{"id_rsa", "*.pem", "secrets/**"}
+	rule.MaxFileSize = 1024
+	rule.ForbiddenCommitterEmails = []string{"*@localhost"}
+	assert.NoError(t, UpdatePushRule(rule))
+	assert.NotZero(t, rule.ID)
+
+	rule, err = GetPushRule(1)
+	assert.NoError(t, err)
+	assert.NotZero(t, rule.ID)
+	assert.EqualValues(t, 1024, rule.MaxFileSize)
+
+	assert.True(t, rule.IsForbiddenPath("id_rsa"))
+	assert.True(t, rule.IsForbiddenPath("home/.ssh/id_rsa"))
+	assert.True(t, rule.IsForbiddenPath("certs/server.pem"))
+	assert.True(t, rule.IsForbiddenPath("secrets/a/b.txt"))
+	assert.False(t, rule.IsForbiddenPath("docs/secrets/b.txt"))
+	assert.False(t, rule.IsForbiddenPath("id_rsa.pub"))
+	assert.True(t, rule.IsForbiddenCommitterEmail("Root@Localhost"))
+	assert.False(t, rule.IsForbiddenCommitterEmail("user2@example.com"))
+
+	rule.ForbiddenPaths = []string{"[invalid"}
+	assert.True(t, IsErrInvalidPushRulePattern(UpdatePushRule(rule)))
+}
+
+func TestGetEffectivePushRule(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defer func() {
+		setting.Repository.PushRules.ForbiddenPaths = []string{}
+		setting.Repository.PushRules.MaxFileSize = 0
+		setting.Repository.PushRules.ScanSecrets = false
+	}()
+	setting.Repository.PushRules.ForbiddenPaths = []string{"*.key"}
+	setting.Repository.PushRules.MaxFileSize = 2048
+
+	assert.NoError(t, UpdatePushRule(&PushRule{RepoID: 1, ForbiddenPaths: []string{"*.pem"}, MaxFileSize: 4096, MaxPathLength: 255}))
+
+	rule, err := GetEffectivePushRule(1)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"*.key", "*.pem"}, rule.ForbiddenPaths)
+	assert.EqualValues(t, 2048, rule.MaxFileSize)
+	assert.EqualValues(t, 255, rule.MaxPathLength)
+	assert.False(t, rule.ScanSecrets)
+
+	rule, err = GetEffectivePushRule(2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"*.key"}, rule.ForbiddenPaths)
+	assert.EqualValues(t, 2048, rule.MaxFileSize)
+	assert.EqualValues(t, 0, rule.MaxPathLength)
+
+	setting.Repository.PushRules.ScanSecrets = true
+	rule, err = GetEffectivePushRule(1)
+	assert.NoError(t, err)
+	assert.True(t, rule.ScanSecrets)
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// StaleBranchCleanup represents the scheduled deletion of the stale branches of a repository
+type StaleBranchCleanup struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE"`
+	Enabled bool  `xorm:"NOT NULL DEFAULT false"`
+	// only delete branches merged into the default branch
+	Merged bool `xorm:"NOT NULL DEFAULT false"`
+	// only delete branches whose tip is older, 0 means any age
+	OlderThanDays int `xorm:"NOT NULL DEFAULT 0"`
+	// the next run only records the branches it would delete
+	DryRun bool `xorm:"NOT NULL DEFAULT true"`
+	// the user who configured the cleanup, branches are deleted in their name
+	DoerID int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	LastRunUnix     timeutil.TimeStamp
+	LastRunDryRun   bool     `xorm:"NOT NULL DEFAULT false"`
+	LastRunBranches []string `xorm:"JSON TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(StaleBranchCleanup))
+}
+
+// OlderThan returns the minimum age of the tip of a branch to be deleted
+func (c *StaleBranchCleanup) OlderThan() time.Duration {
+	return time.Duration(c.OlderThanDays) * 24 * time.Hour
+}
+
+// HasCriteria returns true if the cleanup does not delete all branches
+func (c *StaleBranchCleanup) HasCriteria() bool {
+	return c.Merged || c.OlderThanDays > 0
+}
+
+// GetStaleBranchCleanup returns the stale branch cleanup of a repository,
+// or a disabled one doing a dry run first if the repository has none
+func GetStaleBranchCleanup(repoID int64) (*StaleBranchCleanup, error) {
+	cleanup := &StaleBranchCleanup{RepoID: repoID, DryRun: true}
+	if _, err := db.GetEngine(db.DefaultContext).Where("repo_id=?", repoID).Get(cleanup); err != nil {
+		return nil, err
+	}
+	return cleanup, nil
+}
+
+// UpdateStaleBranchCleanup creates or updates the stale branch cleanup of a repository
+func UpdateStaleBranchCleanup(cleanup *StaleBranchCleanup) error {
+	e := db.GetEngine(db.DefaultContext)
+	if cleanup.ID == 0 {
+		_, err := e.Insert(cleanup)
+		return err
+	}
+	_, err := e.ID(cleanup.ID).AllCols().Update(cleanup)
+	return err
+}
+
+// FindEnabledStaleBranchCleanups returns the stale branch cleanups of all repositories which enabled them
+func FindEnabledStaleBranchCleanups() ([]*StaleBranchCleanup, error) {
+	cleanups := make([]*StaleBranchCleanup, 0, 10)
+	return cleanups, db.GetEngine(db.DefaultContext).Where("enabled=?", true).Asc("id").Find(&cleanups)
+}
+
+// UpdateStaleBranchCleanupRun records a run of the cleanup, a dry run is only done once
+func UpdateStaleBranchCleanupRun(cleanup *StaleBranchCleanup, branches []string) error {
+	cleanup.LastRunUnix = timeutil.TimeStampNow()
+	cleanup.LastRunDryRun = cleanup.DryRun
+	cleanup.LastRunBranches = branches
+	cleanup.DryRun = false
+	_, err := db.GetEngine(db.DefaultContext).ID(cleanup.ID).
+		Cols("last_run_unix", "last_run_dry_run", "last_run_branches", "dry_run").
+		Update(cleanup)
+	return err
+}
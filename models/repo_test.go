@@ -10,10 +10,13 @@ import (
 	"fmt"
 	"image"
 	"image/png"
+	"strings"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -115,6 +118,44 @@ func TestUpdateRepositoryVisibilityChanged(t *testing.T) {
 	assert.True(t, act.IsPrivate)
 }
 
+func TestUpdateRepositoryTruncatesDescription(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo, err := GetRepositoryByID(1)
+	assert.NoError(t, err)
+	// the emoji sequence crossing the limit is removed as a whole
+	repo.Description = strings.Repeat("説明", 126) + "ab\U0001F468\u200d\U0001F469\u200d\U0001F467"
+	assert.NoError(t, UpdateRepository(repo, false))
+
+	repo, err = GetRepositoryByID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.Repeat("説明", 126)+"ab", repo.Description)
+}
+
+func TestUpdateRepositoryKeepsSize(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo, err := GetRepositoryByID(1)
+	assert.NoError(t, err)
+	assert.NoError(t, repo.UpdateSize(db.DefaultContext))
+	assert.NotZero(t, repo.SizeUpdatedUnix)
+	size := repo.Size
+
+	// the size calculated in the background is not overwritten by updates of a repository loaded before
+	repo.Size, repo.SizeUpdatedUnix = size+1, 0
+	repo.Description = "updated"
+	assert.NoError(t, UpdateRepository(repo, false))
+	updated := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, "updated", updated.Description)
+	assert.Equal(t, size, updated.Size)
+	assert.NotZero(t, updated.SizeUpdatedUnix)
+}
+
+func TestRepository_DescriptionText(t *testing.T) {
+	repo := &Repository{Description: "Launch :rocket: \u202bשלום\u202c :not-an-emoji:"}
+	assert.Equal(t, "Launch 🚀 \u202bשלום\u202c :not-an-emoji:", repo.DescriptionText())
+}
+
 func TestGetUserFork(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -134,6 +175,32 @@ func TestGetUserFork(t *testing.T) {
 	assert.Nil(t, repo)
 }
 
+func TestFixReposWithWrongNumForks(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	count, err := CountReposWithWrongNumForks()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+
+	// repo10 has one fork, repo1 has none
+	_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE `repository` SET num_forks=? WHERE id IN (?, ?)", 3, 1, 10)
+	assert.NoError(t, err)
+	count, err = CountReposWithWrongNumForks()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+
+	fixed, err := FixReposWithWrongNumForks()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, fixed)
+	db.AssertExistsAndLoadBean(t, &Repository{ID: 10, NumForks: 1})
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Zero(t, repo.NumForks)
+
+	count, err = CountReposWithWrongNumForks()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
 func TestRepoAPIURL(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 10}).(*Repository)
@@ -222,3 +289,81 @@ func TestRepoGetReviewerTeams(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, teams, 2)
 }
+
+func TestIsUsableRepoName(t *testing.T) {
+	assert.NoError(t, IsUsableRepoName("usable"))
+	assert.True(t, IsErrNameReserved(IsUsableRepoName("..")))
+	assert.True(t, IsErrNamePatternNotAllowed(IsUsableRepoName("repo.wiki")))
+	assert.True(t, IsErrNameCharsNotAllowed(IsUsableRepoName("has space")))
+	assert.True(t, IsErrNameCharsNotAllowed(IsUsableRepoName("r\u0435po")))
+
+	defer func(maxLength int) { setting.Repository.MaxNameLength = maxLength }(setting.Repository.MaxNameLength)
+	setting.Repository.MaxNameLength = 10
+	assert.NoError(t, IsUsableRepoName(strings.Repeat("a", 10)))
+	err := IsUsableRepoName(strings.Repeat("a", 11))
+	assert.True(t, IsErrNameTooLong(err))
+	assert.Contains(t, err.Error(), "10 characters")
+	assert.Equal(t, "repository name", err.(ErrNameTooLong).Kind)
+}
+
+func TestResolveRepoInitFileName(t *testing.T) {
+	defer func(licenses []string) { Licenses = licenses }(Licenses)
+	Licenses = []string{"Apache-2.0", "MIT", "MIT-0", "GPL-3.0-only"}
+
+	name, err := ResolveRepoInitFileName("license", "MIT")
+	assert.NoError(t, err)
+	assert.Equal(t, "MIT", name)
+
+	name, err = ResolveRepoInitFileName("license", "apache-2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "Apache-2.0", name)
+
+	_, err = ResolveRepoInitFileName("license", "gpl-3.0")
+	assert.True(t, IsErrInitFileNotExist(err))
+	assert.Equal(t, []string{"GPL-3.0-only"}, err.(ErrInitFileNotExist).Suggestions)
+
+	_, err = ResolveRepoInitFileName("license", "MTI")
+	assert.True(t, IsErrInitFileNotExist(err))
+	assert.Equal(t, []string{"MIT"}, err.(ErrInitFileNotExist).Suggestions)
+
+	_, err = ResolveRepoInitFileName("unknown", "MIT")
+	assert.True(t, IsErrInitFileNotExist(err))
+	assert.Nil(t, RepoInitFileNames("unknown"))
+}
+
+func TestLookupRepositoryByOwnerAndName(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo, redirected, err := LookupRepositoryByOwnerAndName("User2", "Repo1")
+	assert.NoError(t, err)
+	assert.False(t, redirected)
+	assert.EqualValues(t, 1, repo.ID)
+
+	assert.NoError(t, newUserRedirect(db.GetEngine(db.DefaultContext), 2, "olduser2", "user2"))
+	repo, redirected, err = LookupRepositoryByOwnerAndName("OldUser2", "repo1")
+	assert.NoError(t, err)
+	assert.True(t, redirected)
+	assert.EqualValues(t, 1, repo.ID)
+	assert.Equal(t, "user2", repo.OwnerName)
+
+	repo, err = GetRepositoryByOwnerAndName("olduser2", "repo1")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, repo.ID)
+
+	_, _, err = LookupRepositoryByOwnerAndName("olduser2", "repo-does-not-exist")
+	assert.True(t, IsErrRepoNotExist(err))
+	_, _, err = LookupRepositoryByOwnerAndName("olduser1", "repo1")
+	assert.True(t, IsErrRepoNotExist(err))
+}
+
+func TestUpdateRepositoryPushedTime(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Zero(t, repo.PushedUnix)
+
+	pushed := time.Unix(1640000000, 0)
+	assert.NoError(t, UpdateRepositoryPushedTime(1, pushed))
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.EqualValues(t, pushed.Unix(), repo.PushedUnix)
+	assert.EqualValues(t, pushed.Unix(), repo.UpdatedUnix)
+}
@@ -0,0 +1,165 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoTrafficKind is the kind of traffic counted for a repository
+type RepoTrafficKind int
+
+const (
+	// RepoTrafficClone counts the clones and fetches over HTTP and SSH
+	RepoTrafficClone RepoTrafficKind = iota + 1
+	// RepoTrafficView counts the views of the home page of the repository
+	RepoTrafficView
+)
+
+// RepoTrafficPeriod is the period a row of repository traffic counts
+type RepoTrafficPeriod int
+
+const (
+	// RepoTrafficDay counts the traffic of a day
+	RepoTrafficDay RepoTrafficPeriod = iota
+	// RepoTrafficWeek counts the traffic of a week starting on Monday
+	RepoTrafficWeek
+)
+
+// RepoTraffic represents the traffic of a kind of a repository during a day or a week.
+// Uniques approximates the number of distinct users or addresses.
+type RepoTraffic struct {
+	ID        int64              `xorm:"pk autoincr"`
+	RepoID    int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Kind      RepoTrafficKind    `xorm:"UNIQUE(s) NOT NULL"`
+	Period    RepoTrafficPeriod  `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	StartUnix timeutil.TimeStamp `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Count     int64              `xorm:"NOT NULL DEFAULT 0"`
+	Uniques   int64              `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoTraffic))
+}
+
+// RepoTrafficDayStart returns the start of the day of t in UTC
+func RepoTrafficDayStart(t time.Time) timeutil.TimeStamp {
+	y, m, d := t.UTC().Date()
+	return timeutil.TimeStamp(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix())
+}
+
+// RepoTrafficWeekStart returns the start of the week of t in UTC, weeks start on Monday
+func RepoTrafficWeekStart(t time.Time) timeutil.TimeStamp {
+	t = t.UTC()
+	return RepoTrafficDayStart(t.AddDate(0, 0, -(int(t.Weekday())+6)%7))
+}
+
+// addRepoTraffic adds the count of t to its row, the uniques of the row become the larger ones
+func addRepoTraffic(e db.Engine, t *RepoTraffic) error {
+	existing := &RepoTraffic{}
+	has, err := e.Where("repo_id = ? AND kind = ? AND period = ? AND start_unix = ?", t.RepoID, t.Kind, t.Period, t.StartUnix).Get(existing)
+	if err != nil {
+		return err
+	} else if !has {
+		_, err = e.Insert(&RepoTraffic{
+			RepoID:    t.RepoID,
+			Kind:      t.Kind,
+			Period:    t.Period,
+			StartUnix: t.StartUnix,
+			Count:     t.Count,
+			Uniques:   t.Uniques,
+		})
+		return err
+	}
+
+	existing.Count += t.Count
+	if t.Uniques > existing.Uniques {
+		existing.Uniques = t.Uniques
+	}
+	_, err = e.ID(existing.ID).Cols("count", "uniques").Update(existing)
+	return err
+}
+
+// AddRepoTraffic adds the counts of the traffic to the stored traffic at once
+func AddRepoTraffic(traffic []*RepoTraffic) error {
+	return db.WithTx(func(ctx context.Context) error {
+		for _, t := range traffic {
+			if err := addRepoTraffic(db.GetEngine(ctx), t); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetRepoTraffic returns the daily traffic of a kind of a repository since the given day, oldest first
+func GetRepoTraffic(repoID int64, kind RepoTrafficKind, since timeutil.TimeStamp) ([]*RepoTraffic, error) {
+	traffic := make([]*RepoTraffic, 0, 14)
+	return traffic, db.GetEngine(db.DefaultContext).
+		Where("repo_id = ? AND kind = ? AND period = ? AND start_unix >= ?", repoID, kind, RepoTrafficDay, since).
+		Asc("start_unix").
+		Find(&traffic)
+}
+
+// RollUpRepoTraffic adds the daily traffic before the given day to the weekly traffic and deletes it.
+// The uniques of a week are the sum of the uniques of its days.
+func RollUpRepoTraffic(before timeutil.TimeStamp) error {
+	return db.WithTx(func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+
+		days := make([]*RepoTraffic, 0, 50)
+		if err := e.Where("period = ? AND start_unix < ?", RepoTrafficDay, before).Find(&days); err != nil {
+			return err
+		}
+
+		type weekKey struct {
+			repoID int64
+			kind   RepoTrafficKind
+			start  timeutil.TimeStamp
+		}
+		weeks := make(map[weekKey]*RepoTraffic)
+		for _, day := range days {
+			key := weekKey{day.RepoID, day.Kind, RepoTrafficWeekStart(day.StartUnix.AsTime())}
+			week, ok := weeks[key]
+			if !ok {
+				week = &RepoTraffic{RepoID: key.repoID, Kind: key.kind, Period: RepoTrafficWeek, StartUnix: key.start}
+				weeks[key] = week
+			}
+			week.Count += day.Count
+			week.Uniques += day.Uniques
+		}
+
+		for _, week := range weeks {
+			existing := &RepoTraffic{}
+			has, err := e.Where("repo_id = ? AND kind = ? AND period = ? AND start_unix = ?", week.RepoID, week.Kind, week.Period, week.StartUnix).Get(existing)
+			if err != nil {
+				return err
+			} else if !has {
+				if _, err := e.Insert(week); err != nil {
+					return err
+				}
+				continue
+			}
+			existing.Count += week.Count
+			existing.Uniques += week.Uniques
+			if _, err := e.ID(existing.ID).Cols("count", "uniques").Update(existing); err != nil {
+				return err
+			}
+		}
+
+		_, err := e.Where("period = ? AND start_unix < ?", RepoTrafficDay, before).Delete(new(RepoTraffic))
+		return err
+	})
+}
+
+// DeleteRepoTrafficBefore deletes the traffic of all repositories which started before the given time
+func DeleteRepoTrafficBefore(before timeutil.TimeStamp) error {
+	_, err := db.GetEngine(db.DefaultContext).Where("start_unix < ?", before).Delete(new(RepoTraffic))
+	return err
+}
@@ -0,0 +1,64 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoTrafficWeekStart(t *testing.T) {
+	// Wednesday
+	wednesday := time.Date(2021, time.December, 8, 15, 4, 5, 0, time.UTC)
+	assert.EqualValues(t, time.Date(2021, time.December, 8, 0, 0, 0, 0, time.UTC).Unix(), RepoTrafficDayStart(wednesday))
+	assert.EqualValues(t, time.Date(2021, time.December, 6, 0, 0, 0, 0, time.UTC).Unix(), RepoTrafficWeekStart(wednesday))
+	// Sunday still belongs to the week which started on Monday
+	sunday := time.Date(2021, time.December, 12, 23, 0, 0, 0, time.UTC)
+	assert.EqualValues(t, time.Date(2021, time.December, 6, 0, 0, 0, 0, time.UTC).Unix(), RepoTrafficWeekStart(sunday))
+}
+
+func TestAddRepoTraffic(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	day := RepoTrafficDayStart(time.Now())
+	assert.NoError(t, AddRepoTraffic([]*RepoTraffic{
+		{RepoID: 1, Kind: RepoTrafficClone, StartUnix: day, Count: 3, Uniques: 2},
+		{RepoID: 1, Kind: RepoTrafficView, StartUnix: day, Count: 5, Uniques: 1},
+	}))
+	assert.NoError(t, AddRepoTraffic([]*RepoTraffic{
+		{RepoID: 1, Kind: RepoTrafficClone, StartUnix: day, Count: 2, Uniques: 3},
+	}))
+
+	traffic, err := GetRepoTraffic(1, RepoTrafficClone, day)
+	assert.NoError(t, err)
+	if assert.Len(t, traffic, 1) {
+		assert.EqualValues(t, 5, traffic[0].Count)
+		assert.EqualValues(t, 3, traffic[0].Uniques)
+	}
+}
+
+func TestRollUpRepoTraffic(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	monday := time.Date(2021, time.December, 6, 0, 0, 0, 0, time.UTC)
+	assert.NoError(t, AddRepoTraffic([]*RepoTraffic{
+		{RepoID: 1, Kind: RepoTrafficClone, StartUnix: RepoTrafficDayStart(monday), Count: 3, Uniques: 2},
+		{RepoID: 1, Kind: RepoTrafficClone, StartUnix: RepoTrafficDayStart(monday.AddDate(0, 0, 1)), Count: 4, Uniques: 1},
+		{RepoID: 1, Kind: RepoTrafficClone, StartUnix: RepoTrafficDayStart(monday.AddDate(0, 0, 7)), Count: 1, Uniques: 1},
+	}))
+
+	assert.NoError(t, RollUpRepoTraffic(RepoTrafficDayStart(monday.AddDate(0, 0, 7))))
+	db.AssertExistsAndLoadBean(t, &RepoTraffic{RepoID: 1, Kind: RepoTrafficClone, Period: RepoTrafficWeek, StartUnix: RepoTrafficDayStart(monday), Count: 7, Uniques: 3})
+	// the zero RepoTrafficDay can't be used as a condition of a bean
+	db.AssertNotExistsBean(t, &RepoTraffic{RepoID: 1, StartUnix: RepoTrafficDayStart(monday), Count: 3})
+	db.AssertExistsAndLoadBean(t, &RepoTraffic{RepoID: 1, StartUnix: RepoTrafficDayStart(monday.AddDate(0, 0, 7)), Count: 1})
+
+	assert.NoError(t, DeleteRepoTrafficBefore(RepoTrafficDayStart(monday.AddDate(0, 0, 7))))
+	db.AssertNotExistsBean(t, &RepoTraffic{RepoID: 1, Period: RepoTrafficWeek})
+}
@@ -6,11 +6,14 @@ package models
 
 import (
 	"fmt"
+	"regexp"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/login"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/validation"
 
 	"xorm.io/xorm"
 	"xorm.io/xorm/convert"
@@ -62,6 +65,34 @@ type ExternalTrackerConfig struct {
 	ExternalTrackerURL    string
 	ExternalTrackerFormat string
 	ExternalTrackerStyle  string
+	// ExternalTrackerRegexpPattern defines the references of the regexp style,
+	// its first group is the index
+	ExternalTrackerRegexpPattern string
+}
+
+// Validate checks the URL, the URL format and the numbering style of the external tracker
+func (cfg *ExternalTrackerConfig) Validate() error {
+	if !validation.IsValidExternalURL(cfg.ExternalTrackerURL) {
+		return ErrInvalidExternalTracker{Field: "url", Reason: "must be an absolute http or https URL"}
+	}
+	if len(cfg.ExternalTrackerFormat) != 0 && !validation.IsValidExternalTrackerURLFormat(cfg.ExternalTrackerFormat) {
+		return ErrInvalidExternalTracker{Field: "format", Reason: "must be an absolute http or https URL using the placeholders {user}, {repo} and {index}"}
+	}
+
+	switch cfg.ExternalTrackerStyle {
+	case "", markup.IssueNameStyleNumeric, markup.IssueNameStyleAlphanumeric:
+	case markup.IssueNameStyleRegexp:
+		pattern, err := regexp.Compile(cfg.ExternalTrackerRegexpPattern)
+		if err != nil {
+			return ErrInvalidExternalTracker{Field: "regexp_pattern", Reason: err.Error()}
+		}
+		if cfg.ExternalTrackerRegexpPattern == "" || pattern.MatchString("") {
+			return ErrInvalidExternalTracker{Field: "regexp_pattern", Reason: "must not match empty text"}
+		}
+	default:
+		return ErrInvalidExternalTracker{Field: "style", Reason: "must be numeric, alphanumeric or regexp"}
+	}
+	return nil
 }
 
 // FromDB fills up a ExternalTrackerConfig from serialized format.
@@ -79,6 +110,10 @@ type IssuesConfig struct {
 	EnableTimetracker                bool
 	AllowOnlyContributorsToTrackTime bool
 	EnableDependencies               bool
+	DefaultSortType                  string
+	// CloseKeywords and ReopenKeywords replace the additional keywords of the instance if not empty
+	CloseKeywords  []string
+	ReopenKeywords []string
 }
 
 // FromDB fills up a IssuesConfig from serialized format.
@@ -102,6 +137,10 @@ type PullRequestsConfig struct {
 	AutodetectManualMerge         bool
 	DefaultDeleteBranchAfterMerge bool
 	DefaultMergeStyle             MergeStyle
+	EnableMergeQueue              bool
+	MergeCommitAuthorStyle        MergeCommitAuthorStyle
+	AddReviewedByTrailers         bool
+	AddCoAuthorTrailers           bool
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
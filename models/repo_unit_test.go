@@ -0,0 +1,68 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExternalTrackerConfig_Validate(t *testing.T) {
+	kases := []struct {
+		config ExternalTrackerConfig
+		field  string
+	}{
+		{ExternalTrackerConfig{ExternalTrackerURL: "https://tracker.example.com"}, ""},
+		{ExternalTrackerConfig{ExternalTrackerURL: "tracker.example.com"}, "url"},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:    "https://tracker.example.com",
+			ExternalTrackerFormat: "https://tracker.example.com/{user}/{repo}/{index}",
+			ExternalTrackerStyle:  "alphanumeric",
+		}, ""},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:    "https://tracker.example.com",
+			ExternalTrackerFormat: "https://tracker.example.com/{issue}",
+		}, "format"},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:    "https://tracker.example.com",
+			ExternalTrackerFormat: "https://tracker.example.com/issues",
+		}, "format"},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:   "https://tracker.example.com",
+			ExternalTrackerStyle: "hexadecimal",
+		}, "style"},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:           "https://tracker.example.com",
+			ExternalTrackerFormat:        "https://tracker.example.com/{index}",
+			ExternalTrackerStyle:         "regexp",
+			ExternalTrackerRegexpPattern: `TICKET:(\d+)`,
+		}, ""},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:           "https://tracker.example.com",
+			ExternalTrackerStyle:         "regexp",
+			ExternalTrackerRegexpPattern: `TICKET:(\d+`,
+		}, "regexp_pattern"},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:           "https://tracker.example.com",
+			ExternalTrackerStyle:         "regexp",
+			ExternalTrackerRegexpPattern: `(\d*)`,
+		}, "regexp_pattern"},
+		{ExternalTrackerConfig{
+			ExternalTrackerURL:   "https://tracker.example.com",
+			ExternalTrackerStyle: "regexp",
+		}, "regexp_pattern"},
+	}
+	for _, kase := range kases {
+		err := kase.config.Validate()
+		if kase.field == "" {
+			assert.NoError(t, err, kase.config)
+			continue
+		}
+		if assert.True(t, IsErrInvalidExternalTracker(err), kase.config) {
+			assert.Equal(t, kase.field, err.(ErrInvalidExternalTracker).Field)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// RepoVisitSessionGap is the time without visits to a repository after which a user's next visit starts a new session
+const RepoVisitSessionGap = time.Hour
+
+// RepoVisit represents the visits of a user to the home page of a repository
+type RepoVisit struct {
+	ID     int64 `xorm:"pk autoincr"`
+	UserID int64 `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	// VisitedUnix is the latest visit
+	VisitedUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+	// PreviousVisitedUnix is the latest visit before the session of the latest visit
+	PreviousVisitedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoVisit))
+}
+
+// LastVisit returns the latest visit before the current session, during which the changes since then stay new
+func (v *RepoVisit) LastVisit(now time.Time) timeutil.TimeStamp {
+	if now.Sub(v.VisitedUnix.AsTime()) < RepoVisitSessionGap {
+		return v.PreviousVisitedUnix
+	}
+	return v.VisitedUnix
+}
+
+// addRepoVisit records the visit, starting a new session if the previous visit is older than the session gap
+func addRepoVisit(e db.Engine, v *RepoVisit) error {
+	existing := &RepoVisit{}
+	has, err := e.Where("user_id = ? AND repo_id = ?", v.UserID, v.RepoID).Get(existing)
+	if err != nil {
+		return err
+	} else if !has {
+		_, err = e.Insert(&RepoVisit{
+			UserID:      v.UserID,
+			RepoID:      v.RepoID,
+			VisitedUnix: v.VisitedUnix,
+		})
+		return err
+	}
+	if v.VisitedUnix <= existing.VisitedUnix {
+		return nil
+	}
+	if v.VisitedUnix.AsTime().Sub(existing.VisitedUnix.AsTime()) >= RepoVisitSessionGap {
+		existing.PreviousVisitedUnix = existing.VisitedUnix
+	}
+	existing.VisitedUnix = v.VisitedUnix
+	_, err = e.ID(existing.ID).Cols("visited_unix", "previous_visited_unix").Update(existing)
+	return err
+}
+
+// AddRepoVisits records the latest visits of users to repositories at once
+func AddRepoVisits(visits []*RepoVisit) error {
+	return db.WithTx(func(ctx context.Context) error {
+		for _, v := range visits {
+			if err := addRepoVisit(db.GetEngine(ctx), v); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetRepoLastVisit returns the latest visit of the user to the repository before the current session,
+// 0 if the user has never visited it
+func GetRepoLastVisit(userID, repoID int64) (timeutil.TimeStamp, error) {
+	v := &RepoVisit{}
+	has, err := db.GetEngine(db.DefaultContext).Where("user_id = ? AND repo_id = ?", userID, repoID).Get(v)
+	if err != nil || !has {
+		return 0, err
+	}
+	return v.LastVisit(time.Now()), nil
+}
+
+// GetReposWithNewActivity returns the IDs of the repositories which have been pushed to, or whose issues or pull
+// requests have been updated, since the latest visit of the user. Repositories never visited are not included.
+func GetReposWithNewActivity(userID int64, repos []*Repository) (map[int64]bool, error) {
+	res := make(map[int64]bool)
+	if len(repos) == 0 {
+		return res, nil
+	}
+	repoIDs := make([]int64, len(repos))
+	for i, repo := range repos {
+		repoIDs[i] = repo.ID
+	}
+
+	e := db.GetEngine(db.DefaultContext)
+	visits := make([]*RepoVisit, 0, len(repos))
+	if err := e.Where("user_id = ?", userID).In("repo_id", repoIDs).Find(&visits); err != nil {
+		return nil, err
+	}
+	if len(visits) == 0 {
+		return res, nil
+	}
+	visited := make(map[int64]timeutil.TimeStamp, len(visits))
+	for _, v := range visits {
+		visited[v.RepoID] = v.VisitedUnix
+	}
+
+	var updates []struct {
+		RepoID  int64
+		Updated timeutil.TimeStamp
+	}
+	if err := e.Table("issue").
+		Select("repo_id, MAX(updated_unix) AS updated").
+		Where(builder.In("repo_id", repoIDs)).
+		GroupBy("repo_id").
+		Find(&updates); err != nil {
+		return nil, err
+	}
+	issuesUpdated := make(map[int64]timeutil.TimeStamp, len(updates))
+	for _, u := range updates {
+		issuesUpdated[u.RepoID] = u.Updated
+	}
+
+	for _, repo := range repos {
+		visit, ok := visited[repo.ID]
+		if ok && (repo.UpdatedUnix > visit || issuesUpdated[repo.ID] > visit) {
+			res[repo.ID] = true
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddRepoVisits(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// the fixture issues of repo 1 were last updated at 1579194806
+	visited := timeutil.TimeStamp(1579194700)
+	repos := []*Repository{
+		db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository),
+		db.AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository),
+	}
+	assert.NoError(t, AddRepoVisits([]*RepoVisit{{UserID: 2, RepoID: 1, VisitedUnix: visited}}))
+	newActivity, err := GetReposWithNewActivity(2, repos)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{1: true}, newActivity)
+
+	// visits less than the session gap apart belong to the same session
+	assert.NoError(t, AddRepoVisits([]*RepoVisit{{UserID: 2, RepoID: 1, VisitedUnix: visited + 300}}))
+	v := db.AssertExistsAndLoadBean(t, &RepoVisit{UserID: 2, RepoID: 1}).(*RepoVisit)
+	assert.EqualValues(t, visited+300, v.VisitedUnix)
+	assert.Zero(t, v.PreviousVisitedUnix)
+	assert.Zero(t, v.LastVisit(v.VisitedUnix.AsTime().Add(10*time.Minute)))
+	assert.Equal(t, v.VisitedUnix, v.LastVisit(v.VisitedUnix.AsTime().Add(2*RepoVisitSessionGap)))
+	newActivity, err = GetReposWithNewActivity(2, repos)
+	assert.NoError(t, err)
+	assert.Empty(t, newActivity)
+
+	// a visit after the session gap starts a new session, older visits are ignored
+	next := visited + 300 + timeutil.TimeStamp(2*RepoVisitSessionGap/time.Second)
+	assert.NoError(t, AddRepoVisits([]*RepoVisit{
+		{UserID: 2, RepoID: 1, VisitedUnix: next},
+		{UserID: 2, RepoID: 1, VisitedUnix: visited},
+	}))
+	v = db.AssertExistsAndLoadBean(t, &RepoVisit{UserID: 2, RepoID: 1}).(*RepoVisit)
+	assert.Equal(t, next, v.VisitedUnix)
+	assert.EqualValues(t, visited+300, v.PreviousVisitedUnix)
+
+	lastVisit, err := GetRepoLastVisit(2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, next, lastVisit)
+	lastVisit, err = GetRepoLastVisit(2, 2)
+	assert.NoError(t, err)
+	assert.Zero(t, lastVisit)
+}
@@ -6,8 +6,10 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 )
@@ -26,6 +28,42 @@ const (
 	RepoWatchModeAuto // 3
 )
 
+// AutoWatchPreference specifies when repositories are watched automatically for a user
+type AutoWatchPreference int8
+
+const (
+	// AutoWatchDefault follows AutoWatchOnChanges of the instance
+	AutoWatchDefault AutoWatchPreference = iota // 0
+	// AutoWatchNever never watch automatically
+	AutoWatchNever // 1
+	// AutoWatchOnContribution watch on push and pull request creation
+	AutoWatchOnContribution // 2
+	// AutoWatchOnParticipation watch on contributions and comments
+	AutoWatchOnParticipation // 3
+)
+
+var autoWatchPreferenceNames = map[AutoWatchPreference]string{
+	AutoWatchDefault:         "default",
+	AutoWatchNever:           "never",
+	AutoWatchOnContribution:  "contribution",
+	AutoWatchOnParticipation: "participation",
+}
+
+// String returns the name of the preference
+func (p AutoWatchPreference) String() string {
+	return autoWatchPreferenceNames[p]
+}
+
+// ParseAutoWatchPreference returns the preference with the given name
+func ParseAutoWatchPreference(name string) (AutoWatchPreference, bool) {
+	for p, n := range autoWatchPreferenceNames {
+		if n == name {
+			return p, true
+		}
+	}
+	return AutoWatchDefault, false
+}
+
 // Watch is connection request for receiving repository notification.
 type Watch struct {
 	ID          int64              `xorm:"pk autoincr"`
@@ -133,6 +171,34 @@ func WatchRepo(userID, repoID int64, watch bool) (err error) {
 	return watchRepo(db.GetEngine(db.DefaultContext), userID, repoID, watch)
 }
 
+// watchDefaultRepos makes a new user watch the repositories of setting.Service.DefaultWatchedRepos.
+// Repositories which don't exist or which the user can't read, e.g. private ones, are skipped.
+func watchDefaultRepos(e db.Engine, u *User) error {
+	for _, fullName := range setting.Service.DefaultWatchedRepos {
+		names := strings.SplitN(fullName, "/", 2)
+		repo, err := getRepositoryByOwnerAndName(e, names[0], names[1])
+		if err != nil {
+			if IsErrRepoNotExist(err) {
+				log.Warn("Default watched repository %s does not exist", fullName)
+				continue
+			}
+			return err
+		}
+
+		perm, err := getUserRepoPermission(e, repo, u)
+		if err != nil {
+			return err
+		}
+		if !perm.HasAccess() {
+			continue
+		}
+		if err := watchRepo(e, u.ID, repo.ID, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func getWatchers(e db.Engine, repoID int64) ([]*Watch, error) {
 	watches := make([]*Watch, 0, 10)
 	return watches, e.Where("`watch`.repo_id=?", repoID).
@@ -302,10 +368,24 @@ func NotifyWatchersActions(acts []*Action) error {
 	return sess.Commit()
 }
 
+// watchIfAuto watches a repository in auto mode according to the preference of the user,
+// isWrite is true for contributions and false for other participation like comments
 func watchIfAuto(e db.Engine, userID, repoID int64, isWrite bool) error {
-	if !isWrite || !setting.Service.AutoWatchOnChanges {
+	var autoWatch int8
+	if _, err := e.Table(new(User)).ID(userID).Cols("auto_watch").Get(&autoWatch); err != nil {
+		return err
+	}
+	preference := AutoWatchPreference(autoWatch)
+	if preference == AutoWatchDefault {
+		preference = AutoWatchNever
+		if setting.Service.AutoWatchOnChanges {
+			preference = AutoWatchOnContribution
+		}
+	}
+	if preference == AutoWatchNever || (!isWrite && preference != AutoWatchOnParticipation) {
 		return nil
 	}
+
 	watch, err := getWatch(e, userID, repoID)
 	if err != nil {
 		return err
@@ -316,7 +396,25 @@ func watchIfAuto(e db.Engine, userID, repoID int64, isWrite bool) error {
 	return watchRepoMode(e, watch, RepoWatchModeAuto)
 }
 
-// WatchIfAuto subscribes to repo if AutoWatchOnChanges is set
+// WatchIfAuto subscribes to repo according to the auto watch preference of the user
 func WatchIfAuto(userID, repoID int64, isWrite bool) error {
 	return watchIfAuto(db.GetEngine(db.DefaultContext), userID, repoID, isWrite)
 }
+
+// RemoveAutoWatches unwatches all repositories the user watches because of auto watch
+func RemoveAutoWatches(userID int64) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Exec("UPDATE `repository` SET num_watches = num_watches - 1 WHERE id IN (SELECT repo_id FROM `watch` WHERE user_id = ? AND mode = ?)",
+		userID, RepoWatchModeAuto); err != nil {
+		return err
+	}
+	if _, err := sess.Where("user_id = ? AND mode = ?", userID, RepoWatchModeAuto).Delete(new(Watch)); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
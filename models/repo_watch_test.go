@@ -167,6 +167,45 @@ func TestWatchIfAuto(t *testing.T) {
 	assert.Len(t, watchers, prevCount)
 }
 
+func TestWatchIfAutoPreference(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	setting.Service.AutoWatchOnChanges = true
+	defer func() {
+		setting.Service.AutoWatchOnChanges = false
+	}()
+
+	setPreference := func(preference AutoWatchPreference) {
+		_, err := db.GetEngine(db.DefaultContext).ID(12).Cols("auto_watch").Update(&User{AutoWatch: preference})
+		assert.NoError(t, err)
+	}
+
+	// Never overrides the instance setting
+	setPreference(AutoWatchNever)
+	assert.NoError(t, WatchIfAuto(12, 1, true))
+	assert.False(t, IsWatching(12, 1))
+
+	// Contributions don't watch on comments
+	setPreference(AutoWatchOnContribution)
+	assert.NoError(t, WatchIfAuto(12, 1, false))
+	assert.False(t, IsWatching(12, 1))
+
+	setPreference(AutoWatchOnParticipation)
+	assert.NoError(t, WatchIfAuto(12, 1, false))
+	assert.True(t, IsWatching(12, 1))
+	assert.NoError(t, WatchIfAuto(12, 2, true))
+	assert.True(t, IsWatching(12, 2))
+	assert.NoError(t, WatchRepo(12, 3, true))
+
+	numWatches := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository).NumWatches
+
+	// Only the auto watches are removed
+	assert.NoError(t, RemoveAutoWatches(12))
+	assert.False(t, IsWatching(12, 1))
+	assert.False(t, IsWatching(12, 2))
+	assert.True(t, IsWatching(12, 3))
+	db.AssertExistsAndLoadBean(t, &Repository{ID: 1, NumWatches: numWatches - 1})
+}
+
 func TestWatchRepoMode(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -187,3 +226,21 @@ func TestWatchRepoMode(t *testing.T) {
 	assert.NoError(t, WatchRepoMode(12, 1, RepoWatchModeNone))
 	db.AssertCount(t, &Watch{UserID: 12, RepoID: 1}, 0)
 }
+
+func TestCreateUserWatchesDefaultRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defer func(repos []string) {
+		setting.Service.DefaultWatchedRepos = repos
+	}(setting.Service.DefaultWatchedRepos)
+	// user2/repo2 is private, user2/missing does not exist
+	setting.Service.DefaultWatchedRepos = []string{"user2/repo1", "user2/repo2", "user2/missing"}
+
+	numWatches := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository).NumWatches
+	user := &User{Name: "watcher", Email: "watcher@example.com", Passwd: "password"}
+	assert.NoError(t, CreateUser(user))
+
+	db.AssertExistsAndLoadBean(t, &Watch{UserID: user.ID, RepoID: 1, Mode: RepoWatchModeNormal})
+	db.AssertExistsAndLoadBean(t, &Repository{ID: 1, NumWatches: numWatches + 1})
+	db.AssertNotExistsBean(t, &Watch{UserID: user.ID, RepoID: 2})
+}
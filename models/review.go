@@ -65,7 +65,7 @@ type Review struct {
 	Content          string `xorm:"TEXT"`
 	// Official is a review made by an assigned approver (counts towards approval)
 	Official  bool   `xorm:"NOT NULL DEFAULT false"`
-	CommitID  string `xorm:"VARCHAR(40)"`
+	CommitID  string `xorm:"VARCHAR(64)"`
 	Stale     bool   `xorm:"NOT NULL DEFAULT false"`
 	Dismissed bool   `xorm:"NOT NULL DEFAULT false"`
 
@@ -533,6 +533,25 @@ func getReviewByIssueIDAndUserID(e db.Engine, issueID, userID int64) (*Review, e
 	return review, nil
 }
 
+// HasApprovalByAnyOf returns whether the latest review of one of the users approves the pull request.
+// Stale approvals don't count if excludeStale is set.
+func HasApprovalByAnyOf(issueID int64, userIDs []int64, excludeStale bool) (bool, error) {
+	e := db.GetEngine(db.DefaultContext)
+	for _, userID := range userIDs {
+		review, err := getReviewByIssueIDAndUserID(e, issueID, userID)
+		if err != nil {
+			if IsErrReviewNotExist(err) {
+				continue
+			}
+			return false, err
+		}
+		if review.Type == ReviewTypeApprove && !review.Dismissed && !(excludeStale && review.Stale) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // GetTeamReviewerByIssueIDAndTeamID get the latest review requst of reviewer team for a pull request
 func GetTeamReviewerByIssueIDAndTeamID(issueID, teamID int64) (review *Review, err error) {
 	return getTeamReviewerByIssueIDAndTeamID(db.GetEngine(db.DefaultContext), issueID, teamID)
@@ -562,6 +581,13 @@ func MarkReviewsAsStale(issueID int64) (err error) {
 	return
 }
 
+// MarkApprovalsAsStale marks the approvals of an issue which have not been dismissed as stale
+func MarkApprovalsAsStale(issueID int64) (err error) {
+	_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE `review` SET stale=? WHERE issue_id=? AND type=? AND dismissed=?", true, issueID, ReviewTypeApprove, false)
+
+	return
+}
+
 // MarkReviewsAsNotStale marks existing reviews as not stale for a giving commit SHA
 func MarkReviewsAsNotStale(issueID int64, commitID string) (err error) {
 	_, err = db.GetEngine(db.DefaultContext).Exec("UPDATE `review` SET stale=? WHERE issue_id=? AND commit_id=?", false, issueID, commitID)
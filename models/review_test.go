@@ -143,6 +143,23 @@ func TestGetReviewersByIssueID(t *testing.T) {
 	}
 }
 
+func TestHasApprovalByAnyOf(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// user 1 only commented and the latest review of user 2 requests changes
+	approved, err := HasApprovalByAnyOf(3, []int64{1, 2}, false)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+
+	// the approval of user 4 is stale
+	approved, err = HasApprovalByAnyOf(3, []int64{2, 4}, false)
+	assert.NoError(t, err)
+	assert.True(t, approved)
+	approved, err = HasApprovalByAnyOf(3, []int64{2, 4}, true)
+	assert.NoError(t, err)
+	assert.False(t, approved)
+}
+
 func TestDismissReview(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -198,3 +215,13 @@ func TestDismissReview(t *testing.T) {
 	assert.True(t, approveReviewExample.Dismissed)
 
 }
+
+func TestMarkApprovalsAsStale(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.NoError(t, MarkApprovalsAsStale(2))
+	approval := db.AssertExistsAndLoadBean(t, &Review{ID: 1}).(*Review)
+	assert.True(t, approval.Stale)
+	pending := db.AssertExistsAndLoadBean(t, &Review{ID: 4}).(*Review)
+	assert.False(t, pending.Stale)
+}
@@ -0,0 +1,172 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// SavedReply represents a canned comment of a user, or of an organization shared with its members.
+// The body is stored as written and rendered as markdown when it is used.
+type SavedReply struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OwnerID     int64              `xorm:"INDEX NOT NULL"`
+	Title       string             `xorm:"NOT NULL"`
+	Body        string             `xorm:"LONGTEXT NOT NULL"`
+	Position    int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(SavedReply))
+}
+
+// GetSavedReplies returns the saved replies of a user or an organization
+func GetSavedReplies(ownerID int64) ([]*SavedReply, error) {
+	replies := make([]*SavedReply, 0, 10)
+	return replies, db.GetEngine(db.DefaultContext).
+		Where("owner_id=?", ownerID).
+		OrderBy("position ASC, id ASC").
+		Find(&replies)
+}
+
+// GetAvailableSavedReplies returns the saved replies of a user and of the organizations the user is a member of
+func GetAvailableSavedReplies(u *User) ([]*SavedReply, error) {
+	replies := make([]*SavedReply, 0, 10)
+	return replies, db.GetEngine(db.DefaultContext).
+		Where(builder.Or(
+			builder.Eq{"owner_id": u.ID},
+			builder.In("owner_id", builder.Select("org_id").From("org_user").Where(builder.Eq{"uid": u.ID})),
+		)).
+		OrderBy("position ASC, id ASC").
+		Find(&replies)
+}
+
+// GetSavedReplyByID returns the saved reply of a user or an organization by its ID
+func GetSavedReplyByID(ownerID, id int64) (*SavedReply, error) {
+	reply := new(SavedReply)
+	has, err := db.GetEngine(db.DefaultContext).Where("id=? AND owner_id=?", id, ownerID).Get(reply)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrSavedReplyNotExist{ID: id}
+	}
+	return reply, nil
+}
+
+func checkSavedReplyBody(body string) error {
+	if maxLength := setting.Service.MaxSavedReplyLength; maxLength > 0 && utf8.RuneCountInString(body) > maxLength {
+		return ErrSavedReplyTooLong{MaxLength: maxLength}
+	}
+	return nil
+}
+
+// CreateSavedReply creates a saved reply, a reply without position is added after all existing replies
+func CreateSavedReply(reply *SavedReply) error {
+	if err := checkSavedReplyBody(reply.Body); err != nil {
+		return err
+	}
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	count, err := sess.Where("owner_id=?", reply.OwnerID).Count(new(SavedReply))
+	if err != nil {
+		return err
+	}
+	if limit := setting.Service.MaxSavedReplies; limit > 0 && count >= int64(limit) {
+		return ErrSavedReplyLimitReached{Limit: limit}
+	}
+
+	if reply.Position == 0 {
+		var maxPosition int
+		if _, err := sess.Table("saved_reply").Where("owner_id=?", reply.OwnerID).
+			Select("COALESCE(MAX(position), 0)").Get(&maxPosition); err != nil {
+			return err
+		}
+		reply.Position = maxPosition + 1
+	}
+
+	if _, err := sess.Insert(reply); err != nil {
+		return err
+	}
+	return sess.Commit()
+}
+
+// UpdateSavedReply updates the title, body and position of a saved reply
+func UpdateSavedReply(reply *SavedReply) error {
+	if err := checkSavedReplyBody(reply.Body); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(db.DefaultContext).ID(reply.ID).Cols("title", "body", "position").Update(reply)
+	return err
+}
+
+// DeleteSavedReply deletes a saved reply of a user or an organization
+func DeleteSavedReply(ownerID, id int64) error {
+	deleted, err := db.GetEngine(db.DefaultContext).Delete(&SavedReply{ID: id, OwnerID: ownerID})
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrSavedReplyNotExist{ID: id}
+	}
+	return nil
+}
+
+// ErrSavedReplyNotExist represents a "SavedReplyNotExist" kind of error.
+type ErrSavedReplyNotExist struct {
+	ID int64
+}
+
+// IsErrSavedReplyNotExist checks if an error is a ErrSavedReplyNotExist.
+func IsErrSavedReplyNotExist(err error) bool {
+	_, ok := err.(ErrSavedReplyNotExist)
+	return ok
+}
+
+func (err ErrSavedReplyNotExist) Error() string {
+	return fmt.Sprintf("saved reply does not exist [id: %d]", err.ID)
+}
+
+// ErrSavedReplyLimitReached represents a "SavedReplyLimitReached" kind of error.
+type ErrSavedReplyLimitReached struct {
+	Limit int
+}
+
+// IsErrSavedReplyLimitReached checks if an error is a ErrSavedReplyLimitReached.
+func IsErrSavedReplyLimitReached(err error) bool {
+	_, ok := err.(ErrSavedReplyLimitReached)
+	return ok
+}
+
+func (err ErrSavedReplyLimitReached) Error() string {
+	return fmt.Sprintf("the maximum number of %d saved replies has been reached", err.Limit)
+}
+
+// ErrSavedReplyTooLong represents a "SavedReplyTooLong" kind of error.
+type ErrSavedReplyTooLong struct {
+	MaxLength int
+}
+
+// IsErrSavedReplyTooLong checks if an error is a ErrSavedReplyTooLong.
+func IsErrSavedReplyTooLong(err error) bool {
+	_, ok := err.(ErrSavedReplyTooLong)
+	return ok
+}
+
+func (err ErrSavedReplyTooLong) Error() string {
+	return fmt.Sprintf("the body of a saved reply must not be longer than %d characters", err.MaxLength)
+}
@@ -0,0 +1,75 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSavedReply(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	first := &SavedReply{OwnerID: 2, Title: "Thanks", Body: "Thanks for the **report**!"}
+	assert.NoError(t, CreateSavedReply(first))
+	assert.EqualValues(t, 1, first.Position)
+	second := &SavedReply{OwnerID: 2, Title: "Duplicate", Body: "Duplicate of #1"}
+	assert.NoError(t, CreateSavedReply(second))
+	assert.EqualValues(t, 2, second.Position)
+
+	// organization 3 is shared with its member user 2
+	assert.NoError(t, CreateSavedReply(&SavedReply{OwnerID: 3, Title: "Org", Body: "Org reply"}))
+	assert.NoError(t, CreateSavedReply(&SavedReply{OwnerID: 4, Title: "Other", Body: "Other reply"}))
+
+	replies, err := GetSavedReplies(2)
+	assert.NoError(t, err)
+	assert.Len(t, replies, 2)
+
+	user2 := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	replies, err = GetAvailableSavedReplies(user2)
+	assert.NoError(t, err)
+	assert.Len(t, replies, 3)
+
+	second.Position = 0
+	second.Body = "Duplicate of #2"
+	assert.NoError(t, UpdateSavedReply(second))
+	replies, err = GetSavedReplies(2)
+	assert.NoError(t, err)
+	if assert.Len(t, replies, 2) {
+		assert.EqualValues(t, second.ID, replies[0].ID)
+		assert.Equal(t, "Duplicate of #2", replies[0].Body)
+	}
+
+	_, err = GetSavedReplyByID(4, first.ID)
+	assert.True(t, IsErrSavedReplyNotExist(err))
+	assert.True(t, IsErrSavedReplyNotExist(DeleteSavedReply(4, first.ID)))
+	assert.NoError(t, DeleteSavedReply(2, first.ID))
+	db.AssertNotExistsBean(t, &SavedReply{ID: first.ID})
+}
+
+func TestSavedReplyLimits(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defer func(maxReplies, maxLength int) {
+		setting.Service.MaxSavedReplies = maxReplies
+		setting.Service.MaxSavedReplyLength = maxLength
+	}(setting.Service.MaxSavedReplies, setting.Service.MaxSavedReplyLength)
+	setting.Service.MaxSavedReplies = 1
+	setting.Service.MaxSavedReplyLength = 10
+
+	assert.True(t, IsErrSavedReplyTooLong(CreateSavedReply(&SavedReply{OwnerID: 2, Title: "Long", Body: strings.Repeat("a", 11)})))
+
+	reply := &SavedReply{OwnerID: 2, Title: "Short", Body: "short"}
+	assert.NoError(t, CreateSavedReply(reply))
+	assert.True(t, IsErrSavedReplyLimitReached(CreateSavedReply(&SavedReply{OwnerID: 2, Title: "Second", Body: "second"})))
+
+	reply.Body = strings.Repeat("a", 11)
+	assert.True(t, IsErrSavedReplyTooLong(UpdateSavedReply(reply)))
+}
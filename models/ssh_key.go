@@ -89,6 +89,16 @@ func addKey(e db.Engine, key *PublicKey) (err error) {
 
 // AddPublicKey adds new public key to database and authorized_keys file.
 func AddPublicKey(ownerID int64, name, content string, loginSourceID int64) (*PublicKey, error) {
+	return addPublicKey(ownerID, name, content, loginSourceID, true)
+}
+
+// AddPublicKeyWithoutAuthorizedKeys adds new public key to database only.
+// The caller has to rewrite the authorized_keys file once it added all its keys.
+func AddPublicKeyWithoutAuthorizedKeys(ownerID int64, name, content string, loginSourceID int64) (*PublicKey, error) {
+	return addPublicKey(ownerID, name, content, loginSourceID, false)
+}
+
+func addPublicKey(ownerID int64, name, content string, loginSourceID int64, appendToFile bool) (*PublicKey, error) {
 	log.Trace(content)
 
 	fingerprint, err := calcFingerprint(content)
@@ -125,7 +135,11 @@ func AddPublicKey(ownerID int64, name, content string, loginSourceID int64) (*Pu
 		Type:          KeyTypeUser,
 		LoginSourceID: loginSourceID,
 	}
-	if err = addKey(sess, key); err != nil {
+	if !appendToFile {
+		if _, err = sess.Insert(key); err != nil {
+			return nil, err
+		}
+	} else if err = addKey(sess, key); err != nil {
 		return nil, fmt.Errorf("addKey: %v", err)
 	}
 
@@ -134,8 +148,12 @@ func AddPublicKey(ownerID int64, name, content string, loginSourceID int64) (*Pu
 
 // GetPublicKeyByID returns public key by given ID.
 func GetPublicKeyByID(keyID int64) (*PublicKey, error) {
+	return getPublicKeyByID(db.GetEngine(db.DefaultContext), keyID)
+}
+
+func getPublicKeyByID(e db.Engine, keyID int64) (*PublicKey, error) {
 	key := new(PublicKey)
-	has, err := db.GetEngine(db.DefaultContext).
+	has, err := e.
 		ID(keyID).
 		Get(key)
 	if err != nil {
@@ -353,7 +371,7 @@ func DeletePublicKey(doer *User, id int64) (err error) {
 		return RewriteAllPrincipalKeys()
 	}
 
-	return RewriteAllPublicKeys()
+	return removeAuthorizedKeysFromFile(key)
 }
 
 // deleteKeysMarkedForDeletion returns true if ssh keys needs update
@@ -407,6 +425,11 @@ func AddPublicKeysBySource(usr *User, s *login.Source, sshPublicKeys []string) b
 			marshalled = marshalled[:len(marshalled)-1]
 			sshKeyName := fmt.Sprintf("%s-%s", s.Name, ssh.FingerprintSHA256(out))
 
+			if err := CheckPublicKeyPolicy(marshalled); err != nil {
+				log.Warn("AddPublicKeysBySource[%s]: Skipping Public SSH Key for user %s: %v", sshKeyName, usr.Name, err)
+				continue
+			}
+
 			if _, err := AddPublicKey(usr.ID, sshKeyName, marshalled, s.ID); err != nil {
 				if IsErrKeyAlreadyExist(err) {
 					log.Trace("AddPublicKeysBySource[%s]: Public SSH Key %s already exists for user", sshKeyName, usr.Name)
@@ -44,6 +44,68 @@ const (
 
 var sshOpLocker sync.Mutex
 
+// authorizedKeysLockTimeout is how long we wait for another process to release the
+// authorized_keys lock before giving up.
+const authorizedKeysLockTimeout = 30 * time.Second
+
+// lockAuthorizedKeysFile takes the in-process lock and an advisory lock on a lock file next to
+// fPath, so that concurrent gitea processes (e.g. the web server and `gitea admin regenerate keys`)
+// do not interleave appends with full rewrites. The operating system releases the advisory lock
+// when its process dies, so a crashed process never blocks key changes. The returned function
+// releases both locks.
+func lockAuthorizedKeysFile(fPath string) (func(), error) {
+	sshOpLocker.Lock()
+
+	lockPath := fPath + ".lock"
+	f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		sshOpLocker.Unlock()
+		return nil, err
+	}
+
+	deadline := time.Now().Add(authorizedKeysLockTimeout)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			_ = f.Close()
+			sshOpLocker.Unlock()
+			return nil, fmt.Errorf("lock %s: %v", lockPath, err)
+		}
+		if locked {
+			return func() {
+				if err := unlockFile(f); err != nil {
+					log.Error("Unable to unlock authorized_keys lock file %s: %v", lockPath, err)
+				}
+				_ = f.Close()
+				sshOpLocker.Unlock()
+			}, nil
+		}
+
+		if time.Now().After(deadline) {
+			_ = f.Close()
+			sshOpLocker.Unlock()
+			return nil, fmt.Errorf("timed out waiting for authorized_keys lock file %s", lockPath)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// ensureSSHRootPath makes sure that setting.SSH.RootPath exists
+func ensureSSHRootPath() error {
+	if setting.SSH.RootPath != "" {
+		// First of ensure that the RootPath is present, and if not make it with 0700 permissions
+		// This of course doesn't guarantee that this is the right directory for authorized_keys
+		// but at least if it's supposed to be this directory and it doesn't exist and we're the
+		// right user it will at least be created properly.
+		err := os.MkdirAll(setting.SSH.RootPath, 0o700)
+		if err != nil {
+			log.Error("Unable to MkdirAll(%s): %v", setting.SSH.RootPath, err)
+			return err
+		}
+	}
+	return nil
+}
+
 // AuthorizedStringForKey creates the authorized keys string appropriate for the provided key
 func AuthorizedStringForKey(key *PublicKey) string {
 	sb := &strings.Builder{}
@@ -65,22 +127,17 @@ func appendAuthorizedKeysToFile(keys ...*PublicKey) error {
 		return nil
 	}
 
-	sshOpLocker.Lock()
-	defer sshOpLocker.Unlock()
-
-	if setting.SSH.RootPath != "" {
-		// First of ensure that the RootPath is present, and if not make it with 0700 permissions
-		// This of course doesn't guarantee that this is the right directory for authorized_keys
-		// but at least if it's supposed to be this directory and it doesn't exist and we're the
-		// right user it will at least be created properly.
-		err := os.MkdirAll(setting.SSH.RootPath, 0o700)
-		if err != nil {
-			log.Error("Unable to MkdirAll(%s): %v", setting.SSH.RootPath, err)
-			return err
-		}
+	if err := ensureSSHRootPath(); err != nil {
+		return err
 	}
 
 	fPath := filepath.Join(setting.SSH.RootPath, "authorized_keys")
+	unlock, err := lockAuthorizedKeysFile(fPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	f, err := os.OpenFile(fPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
 	if err != nil {
 		return err
@@ -114,6 +171,77 @@ func appendAuthorizedKeysToFile(keys ...*PublicKey) error {
 	return nil
 }
 
+// removeAuthorizedKeysFromFile removes the provided SSH keys from the authorized_keys file,
+// leaving every other line untouched. Unlike rewriteAllPublicKeys this does not need to
+// iterate over all keys in the database, so it can be called right after the keys were deleted.
+func removeAuthorizedKeysFromFile(keys ...*PublicKey) error {
+	// Don't need to rewrite this file if builtin SSH server is enabled.
+	if setting.SSH.StartBuiltinServer || !setting.SSH.CreateAuthorizedKeysFile {
+		return nil
+	}
+
+	removed := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.Type == KeyTypePrincipal {
+			continue
+		}
+		removed[strings.TrimSpace(key.Content)] = true
+	}
+	if len(removed) == 0 {
+		return nil
+	}
+
+	fPath := filepath.Join(setting.SSH.RootPath, "authorized_keys")
+	unlock, err := lockAuthorizedKeysFile(fPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.Open(fPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	tmpPath := fPath + ".tmp"
+	t, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		t.Close()
+		if err := util.Remove(tmpPath); err != nil && !os.IsNotExist(err) {
+			log.Warn("Unable to remove temporary authorized keys file: %s: Error: %v", tmpPath, err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, tplCommentPrefix) && scanner.Scan() {
+			keyLine := scanner.Text()
+			if idx := strings.LastIndex(keyLine, ",no-pty "); idx >= 0 && removed[strings.TrimSpace(keyLine[idx+len(",no-pty "):])] {
+				continue
+			}
+			line += "\n" + keyLine
+		}
+		if _, err = t.WriteString(line + "\n"); err != nil {
+			return err
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	f.Close()
+	t.Close()
+	return util.Rename(tmpPath, fPath)
+}
+
 // RewriteAllPublicKeys removes any authorized key and rewrite all keys from database again.
 // Note: db.GetEngine(db.DefaultContext).Iterate does not get latest data after insert/delete, so we have to call this function
 // outside any session scope independently.
@@ -127,22 +255,17 @@ func rewriteAllPublicKeys(e db.Engine) error {
 		return nil
 	}
 
-	sshOpLocker.Lock()
-	defer sshOpLocker.Unlock()
-
-	if setting.SSH.RootPath != "" {
-		// First of ensure that the RootPath is present, and if not make it with 0700 permissions
-		// This of course doesn't guarantee that this is the right directory for authorized_keys
-		// but at least if it's supposed to be this directory and it doesn't exist and we're the
-		// right user it will at least be created properly.
-		err := os.MkdirAll(setting.SSH.RootPath, 0o700)
-		if err != nil {
-			log.Error("Unable to MkdirAll(%s): %v", setting.SSH.RootPath, err)
-			return err
-		}
+	if err := ensureSSHRootPath(); err != nil {
+		return err
 	}
 
 	fPath := filepath.Join(setting.SSH.RootPath, "authorized_keys")
+	unlock, err := lockAuthorizedKeysFile(fPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	tmpPath := fPath + ".tmp"
 	t, err := os.OpenFile(tmpPath, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0o600)
 	if err != nil {
@@ -5,11 +5,17 @@
 package models
 
 import (
+	"crypto/subtle"
 	"fmt"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/login"
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
 	"xorm.io/builder"
 	"xorm.io/xorm"
 )
@@ -34,6 +40,13 @@ type DeployKey struct {
 
 	Mode AccessMode `xorm:"NOT NULL DEFAULT 1"`
 
+	// CanWriteStatuses allows the key to post commit statuses through the API with its statuses token
+	CanWriteStatuses       bool   `xorm:"NOT NULL DEFAULT false"`
+	StatusesToken          string `xorm:"-"` // only set when the token has just been generated
+	StatusesTokenHash      string
+	StatusesTokenSalt      string
+	StatusesTokenLastEight string `xorm:"INDEX"`
+
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
 	HasRecentActivity bool               `xorm:"-"`
@@ -88,18 +101,37 @@ func checkDeployKey(e db.Engine, keyID, repoID int64, name string) error {
 	return nil
 }
 
+// generateStatusesToken generates the token with which the key posts commit statuses
+func (key *DeployKey) generateStatusesToken() error {
+	salt, err := util.RandomString(10)
+	if err != nil {
+		return err
+	}
+	key.StatusesToken = base.EncodeSha1(gouuid.New().String())
+	key.StatusesTokenSalt = salt
+	key.StatusesTokenHash = login.HashToken(key.StatusesToken, salt)
+	key.StatusesTokenLastEight = key.StatusesToken[len(key.StatusesToken)-8:]
+	return nil
+}
+
 // addDeployKey adds new key-repo relation.
-func addDeployKey(e *xorm.Session, keyID, repoID int64, name, fingerprint string, mode AccessMode) (*DeployKey, error) {
+func addDeployKey(e *xorm.Session, keyID, repoID int64, name, fingerprint string, mode AccessMode, canWriteStatuses bool) (*DeployKey, error) {
 	if err := checkDeployKey(e, keyID, repoID, name); err != nil {
 		return nil, err
 	}
 
 	key := &DeployKey{
-		KeyID:       keyID,
-		RepoID:      repoID,
-		Name:        name,
-		Fingerprint: fingerprint,
-		Mode:        mode,
+		KeyID:            keyID,
+		RepoID:           repoID,
+		Name:             name,
+		Fingerprint:      fingerprint,
+		Mode:             mode,
+		CanWriteStatuses: canWriteStatuses,
+	}
+	if canWriteStatuses {
+		if err := key.generateStatusesToken(); err != nil {
+			return nil, err
+		}
 	}
 	_, err := e.Insert(key)
 	return key, err
@@ -114,7 +146,9 @@ func HasDeployKey(keyID, repoID int64) bool {
 }
 
 // AddDeployKey add new deploy key to database and authorized_keys file.
-func AddDeployKey(repoID int64, name, content string, readOnly bool) (*DeployKey, error) {
+// If canWriteStatuses is set the returned key holds the token to post commit statuses,
+// it can not be retrieved later.
+func AddDeployKey(repoID int64, name, content string, readOnly, canWriteStatuses bool) (*DeployKey, error) {
 	fingerprint, err := calcFingerprint(content)
 	if err != nil {
 		return nil, err
@@ -154,7 +188,7 @@ func AddDeployKey(repoID int64, name, content string, readOnly bool) (*DeployKey
 		}
 	}
 
-	key, err := addDeployKey(sess, pkey.ID, repoID, name, pkey.Fingerprint, accessMode)
+	key, err := addDeployKey(sess, pkey.ID, repoID, name, pkey.Fingerprint, accessMode, canWriteStatuses)
 	if err != nil {
 		return nil, err
 	}
@@ -178,6 +212,28 @@ func getDeployKeyByID(e db.Engine, id int64) (*DeployKey, error) {
 	return key, nil
 }
 
+// GetDeployKeyByStatusesToken returns the deploy key which may post commit statuses with the given token.
+func GetDeployKeyByStatusesToken(token string) (*DeployKey, error) {
+	// the tokens are SHA1 sums like the access tokens
+	if len(token) != 40 {
+		return nil, ErrDeployKeyNotExist{0, 0, 0}
+	}
+
+	var keys []*DeployKey
+	if err := db.GetEngine(db.DefaultContext).
+		Where("can_write_statuses = ? AND statuses_token_last_eight = ?", true, token[len(token)-8:]).
+		Find(&keys); err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		tempHash := login.HashToken(token, key.StatusesTokenSalt)
+		if subtle.ConstantTimeCompare([]byte(key.StatusesTokenHash), []byte(tempHash)) == 1 {
+			return key, nil
+		}
+	}
+	return nil, ErrDeployKeyNotExist{0, 0, 0}
+}
+
 // GetDeployKeyByRepo returns deploy key by given public key ID and repository ID.
 func GetDeployKeyByRepo(keyID, repoID int64) (*DeployKey, error) {
 	return getDeployKeyByRepo(db.GetEngine(db.DefaultContext), keyID, repoID)
@@ -256,12 +312,16 @@ func deleteDeployKey(sess db.Engine, doer *User, id int64) error {
 	if err != nil {
 		return err
 	} else if !has {
+		pkey, err := getPublicKeyByID(sess, key.KeyID)
+		if err != nil {
+			return err
+		}
 		if err = deletePublicKeys(sess, key.KeyID); err != nil {
 			return err
 		}
 
-		// after deleted the public keys, should rewrite the public keys file
-		if err = rewriteAllPublicKeys(sess); err != nil {
+		// after deleted the public keys, should remove them from the public keys file
+		if err = removeAuthorizedKeysFromFile(pkey); err != nil {
 			return err
 		}
 	}
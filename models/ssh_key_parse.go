@@ -15,6 +15,7 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -172,14 +173,24 @@ func CheckPublicKeyString(content string) (_ string, err error) {
 	// remove any unnecessary whitespace now
 	content = strings.TrimSpace(content)
 
+	if err := CheckPublicKeyPolicy(content); err != nil {
+		return "", err
+	}
+	return content, nil
+}
+
+// CheckPublicKeyPolicy checks the type and the size of a public key line against [ssh.minimum_key_sizes].
+// It returns an ErrKeyPolicyViolation naming the violated rule if the key is not allowed.
+func CheckPublicKeyPolicy(content string) error {
 	if !setting.SSH.MinimumKeySizeCheck {
-		return content, nil
+		return nil
 	}
 
 	var (
 		fnName  string
 		keyType string
 		length  int
+		err     error
 	)
 	if setting.SSH.StartBuiltinServer {
 		fnName = "SSHNativeParsePublicKey"
@@ -189,16 +200,23 @@ func CheckPublicKeyString(content string) (_ string, err error) {
 		keyType, length, err = SSHKeyGenParsePublicKey(content)
 	}
 	if err != nil {
-		return "", fmt.Errorf("%s: %v", fnName, err)
+		return fmt.Errorf("%s: %v", fnName, err)
 	}
 	log.Trace("Key info [native: %v]: %s-%d", setting.SSH.StartBuiltinServer, keyType, length)
 
-	if minLen, found := setting.SSH.MinimumKeySizes[keyType]; found && length >= minLen {
-		return content, nil
-	} else if found && length < minLen {
-		return "", fmt.Errorf("key length is not enough: got %d, needs %d", length, minLen)
+	minLen, found := setting.SSH.MinimumKeySizes[keyType]
+	if !found {
+		allowedTypes := make([]string, 0, len(setting.SSH.MinimumKeySizes))
+		for allowedType := range setting.SSH.MinimumKeySizes {
+			allowedTypes = append(allowedTypes, allowedType)
+		}
+		sort.Strings(allowedTypes)
+		return ErrKeyPolicyViolation{KeyType: keyType, Length: length, AllowedTypes: allowedTypes}
+	}
+	if length < minLen {
+		return ErrKeyPolicyViolation{KeyType: keyType, Length: length, MinLength: minLen}
 	}
-	return "", fmt.Errorf("key type is not allowed: %s", keyType)
+	return nil
 }
 
 // SSHNativeParsePublicKey extracts the key type and length using the golang SSH library.
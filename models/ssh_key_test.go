@@ -6,6 +6,8 @@
 package models
 
 import (
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -156,6 +158,35 @@ AAAAC3NzaC1lZDI1NTE5AAAAICV0MGX/W9IvLA4FXpIuUcdDcbj5KX4syHgsTy7soVgf
 	}
 }
 
+func Test_CheckPublicKeyPolicy(t *testing.T) {
+	oldCheck, oldSizes, oldBuiltin := setting.SSH.MinimumKeySizeCheck, setting.SSH.MinimumKeySizes, setting.SSH.StartBuiltinServer
+	defer func() {
+		setting.SSH.MinimumKeySizeCheck, setting.SSH.MinimumKeySizes, setting.SSH.StartBuiltinServer = oldCheck, oldSizes, oldBuiltin
+	}()
+	setting.SSH.MinimumKeySizeCheck = true
+	setting.SSH.MinimumKeySizes = map[string]int{"rsa": 3072, "ed25519": 256}
+	setting.SSH.StartBuiltinServer = true
+
+	rsa2048 := "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDMZXh+1OBUwSH9D45wTaxErQIN9IoC9xl7MKJkqvTvv6O5RR9YW/IK9FbfjXgXsppYGhsCZo1hFOOsXHMnfOORqu/xMDx4yPuyvKpw4LePEcg4TDipaDFuxbWOqc/BUZRZcXu41QAWfDLrInwsltWZHSeG7hjhpacl4FrVv9V1pS6Oc5Q1NxxEzTzuNLS/8diZrTm/YAQQ/+B+mzWI3zEtF4miZjjAljWd1LTBPvU23d29DcBmmFahcZ441XZsTeAwGxG/Q6j8NgNXj9WxMeWwxXV2jeAX/EBSpZrCVlCQ1yJswT6xCp8TuBnTiGWYMBNTbOZvPC4e0WI2/yZW/s5F nocomment"
+	err := CheckPublicKeyPolicy(rsa2048)
+	assert.True(t, IsErrKeyPolicyViolation(err))
+	assert.EqualError(t, err, "rsa key has 2048 bits, but rsa keys must have at least 3072 bits")
+
+	ecdsa := "ecdsa-sha2-nistp256 AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBFQacN3PrOll7PXmN5B/ZNVahiUIqI05nbBlZk1KXsO3d06ktAWqbNflv2vEmA38bTFTfJ2sbn2B5ksT52cDDbA= nocomment"
+	err = CheckPublicKeyPolicy(ecdsa)
+	assert.True(t, IsErrKeyPolicyViolation(err))
+	assert.EqualError(t, err, "ecdsa keys are not allowed, allowed key types are: ed25519, rsa")
+
+	_, err = CheckPublicKeyString(ecdsa)
+	assert.True(t, IsErrKeyPolicyViolation(err))
+
+	setting.SSH.MinimumKeySizes["ecdsa"] = 256
+	assert.NoError(t, CheckPublicKeyPolicy(ecdsa))
+
+	setting.SSH.MinimumKeySizeCheck = false
+	assert.NoError(t, CheckPublicKeyPolicy(rsa2048))
+}
+
 func Test_calcFingerprint(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -190,3 +221,60 @@ func Test_calcFingerprint(t *testing.T) {
 		})
 	}
 }
+
+func Test_removeAuthorizedKeysFromFile(t *testing.T) {
+	oldRootPath := setting.SSH.RootPath
+	oldStartBuiltinServer := setting.SSH.StartBuiltinServer
+	oldCreateAuthorizedKeysFile := setting.SSH.CreateAuthorizedKeysFile
+	defer func() {
+		setting.SSH.RootPath = oldRootPath
+		setting.SSH.StartBuiltinServer = oldStartBuiltinServer
+		setting.SSH.CreateAuthorizedKeysFile = oldCreateAuthorizedKeysFile
+	}()
+
+	setting.SSH.RootPath = t.TempDir()
+	setting.SSH.StartBuiltinServer = false
+	setting.SSH.CreateAuthorizedKeysFile = true
+
+	keep := &PublicKey{ID: 1, OwnerID: 1, Type: KeyTypeUser, Content: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIKeep"}
+	remove := &PublicKey{ID: 2, OwnerID: 2, Type: KeyTypeUser, Content: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIRemove"}
+	external := "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABexternal user@host\n"
+
+	assert.NoError(t, appendAuthorizedKeysToFile(keep, remove))
+
+	fPath := filepath.Join(setting.SSH.RootPath, "authorized_keys")
+	f, err := os.OpenFile(fPath, os.O_WRONLY|os.O_APPEND, 0o600)
+	assert.NoError(t, err)
+	_, err = f.WriteString(external)
+	assert.NoError(t, err)
+	assert.NoError(t, f.Close())
+
+	assert.NoError(t, removeAuthorizedKeysFromFile(remove))
+
+	content, err := os.ReadFile(fPath)
+	assert.NoError(t, err)
+	assert.Equal(t, AuthorizedStringForKey(keep)+external, string(content))
+}
+
+func Test_lockAuthorizedKeysFile(t *testing.T) {
+	fPath := filepath.Join(t.TempDir(), "authorized_keys")
+
+	// a lock file left behind by a crashed process does not block
+	assert.NoError(t, os.WriteFile(fPath+".lock", []byte("1\n"), 0o600))
+	unlock, err := lockAuthorizedKeysFile(fPath)
+	assert.NoError(t, err)
+
+	// while the lock is held, it cannot be taken through another file
+	f, err := os.OpenFile(fPath+".lock", os.O_RDWR, 0o600)
+	assert.NoError(t, err)
+	defer f.Close()
+	locked, err := tryLockFile(f)
+	assert.NoError(t, err)
+	assert.False(t, locked)
+
+	unlock()
+	locked, err = tryLockFile(f)
+	assert.NoError(t, err)
+	assert.True(t, locked)
+	assert.NoError(t, unlockFile(f))
+}
@@ -35,6 +35,12 @@ type Task struct {
 	PayloadContent string             `xorm:"TEXT"`
 	Message        string             `xorm:"TEXT"` // if task failed, saved the error reason
 	Created        timeutil.TimeStamp `xorm:"created"`
+
+	// progress of a migration, to resume it after a failure
+	MigratedIssueIndex int64
+	MigratedPullIndex  int64
+	MigratedReleaseTag string
+	LastSyncUnix       timeutil.TimeStamp // start of the last successful migration or synchronization
 }
 
 func init() {
@@ -146,6 +152,271 @@ func (task *Task) MigrateConfig() (*migration.MigrateOptions, error) {
 	return nil, fmt.Errorf("Task type is %s, not Migrate Repo", task.Type.Name())
 }
 
+// ReleaseAttachmentFetch is the payload of a task fetching a release attachment from a URL
+type ReleaseAttachmentFetch struct {
+	ReleaseID          int64
+	Name               string
+	SourceURL          string // stripped of credentials
+	SourceURLEncrypted string
+	ContentType        string
+	TotalSize          int64 // -1 if unknown
+	FetchedSize        int64
+	AttachmentID       int64 // set once the attachment is created
+}
+
+// ReleaseAttachmentFetch returns the payload of a task fetching a release attachment
+func (task *Task) ReleaseAttachmentFetch() (*ReleaseAttachmentFetch, error) {
+	if task.Type != structs.TaskTypeFetchReleaseAttachment {
+		return nil, fmt.Errorf("Task type is %s, not Fetch Release Attachment", task.Type.Name())
+	}
+	var fetch ReleaseAttachmentFetch
+	if err := json.Unmarshal([]byte(task.PayloadContent), &fetch); err != nil {
+		return nil, err
+	}
+	return &fetch, nil
+}
+
+// SetReleaseAttachmentFetch sets the payload of a task fetching a release attachment, it is not saved
+func (task *Task) SetReleaseAttachmentFetch(fetch *ReleaseAttachmentFetch) error {
+	bs, err := json.Marshal(fetch)
+	if err != nil {
+		return err
+	}
+	task.PayloadContent = string(bs)
+	return nil
+}
+
+// RepoHealthCheckResult is the result of one of the checks of a repository health check
+type RepoHealthCheckResult struct {
+	Name    string
+	OK      bool
+	Message string
+}
+
+// RepoHealthCheck is the payload of a task checking the health of a repository
+type RepoHealthCheck struct {
+	Healthy bool
+	Results []*RepoHealthCheckResult
+}
+
+// RepoHealthCheck returns the payload of a task checking the health of a repository
+func (task *Task) RepoHealthCheck() (*RepoHealthCheck, error) {
+	if task.Type != structs.TaskTypeRepoHealthCheck {
+		return nil, fmt.Errorf("Task type is %s, not Repository Health Check", task.Type.Name())
+	}
+	check := &RepoHealthCheck{}
+	if task.PayloadContent == "" {
+		return check, nil
+	}
+	if err := json.Unmarshal([]byte(task.PayloadContent), check); err != nil {
+		return nil, err
+	}
+	return check, nil
+}
+
+// SetRepoHealthCheck sets the payload of a task checking the health of a repository, it is not saved
+func (task *Task) SetRepoHealthCheck(check *RepoHealthCheck) error {
+	bs, err := json.Marshal(check)
+	if err != nil {
+		return err
+	}
+	task.PayloadContent = string(bs)
+	return nil
+}
+
+// GetRepoHealthCheckTask returns the task checking the health of a repository by its id and the id of the repository
+func GetRepoHealthCheckTask(repoID, id int64) (*Task, error) {
+	task := Task{
+		ID:     id,
+		RepoID: repoID,
+		Type:   structs.TaskTypeRepoHealthCheck,
+	}
+	has, err := db.GetEngine(db.DefaultContext).Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{id, repoID, task.Type}
+	}
+	return &task, nil
+}
+
+// GetPendingRepoHealthCheckTask returns the queued or running task checking the health of a repository
+func GetPendingRepoHealthCheckTask(repoID int64) (*Task, error) {
+	task := Task{
+		RepoID: repoID,
+		Type:   structs.TaskTypeRepoHealthCheck,
+	}
+	has, err := db.GetEngine(db.DefaultContext).
+		In("status", structs.TaskStatusQueue, structs.TaskStatusRunning).
+		Desc("id").
+		Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{0, repoID, task.Type}
+	}
+	return &task, nil
+}
+
+// The stages of a task detaching a fork from its base repository
+const (
+	RepoDetachForkStageQueued     = "queued"
+	RepoDetachForkStageRepacking  = "repacking"
+	RepoDetachForkStageConverting = "converting"
+	RepoDetachForkStageFinished   = "finished"
+)
+
+// RepoDetachFork is the payload of a task detaching a fork from its base repository
+type RepoDetachFork struct {
+	// FormerBaseID and FormerBase are the id and the full name of the base repository when the task was added
+	FormerBaseID int64
+	FormerBase   string
+	Stage        string
+}
+
+// RepoDetachFork returns the payload of a task detaching a fork from its base repository
+func (task *Task) RepoDetachFork() (*RepoDetachFork, error) {
+	if task.Type != structs.TaskTypeRepoDetachFork {
+		return nil, fmt.Errorf("Task type is %s, not Repository Detach Fork", task.Type.Name())
+	}
+	detach := &RepoDetachFork{}
+	if task.PayloadContent == "" {
+		return detach, nil
+	}
+	if err := json.Unmarshal([]byte(task.PayloadContent), detach); err != nil {
+		return nil, err
+	}
+	return detach, nil
+}
+
+// SetRepoDetachFork sets the payload of a task detaching a fork from its base repository, it is not saved
+func (task *Task) SetRepoDetachFork(detach *RepoDetachFork) error {
+	bs, err := json.Marshal(detach)
+	if err != nil {
+		return err
+	}
+	task.PayloadContent = string(bs)
+	return nil
+}
+
+// GetRepoDetachForkTask returns the task detaching a fork by its id and the id of the fork
+func GetRepoDetachForkTask(repoID, id int64) (*Task, error) {
+	task := Task{
+		ID:     id,
+		RepoID: repoID,
+		Type:   structs.TaskTypeRepoDetachFork,
+	}
+	has, err := db.GetEngine(db.DefaultContext).Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{id, repoID, task.Type}
+	}
+	return &task, nil
+}
+
+// GetPendingRepoDetachForkTask returns the queued or running task detaching a fork
+func GetPendingRepoDetachForkTask(repoID int64) (*Task, error) {
+	task := Task{
+		RepoID: repoID,
+		Type:   structs.TaskTypeRepoDetachFork,
+	}
+	has, err := db.GetEngine(db.DefaultContext).
+		In("status", structs.TaskStatusQueue, structs.TaskStatusRunning).
+		Desc("id").
+		Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{0, repoID, task.Type}
+	}
+	return &task, nil
+}
+
+// GetReleaseAttachmentTask returns the task fetching a release attachment by its id and the id of its repository
+func GetReleaseAttachmentTask(repoID, id int64) (*Task, error) {
+	task := Task{
+		ID:     id,
+		RepoID: repoID,
+		Type:   structs.TaskTypeFetchReleaseAttachment,
+	}
+	has, err := db.GetEngine(db.DefaultContext).Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{id, repoID, task.Type}
+	}
+	return &task, nil
+}
+
+// UserExport is the payload of a task exporting the data of a user
+type UserExport struct {
+	Path   string // path of the archive in the user export storage, set once it is stored
+	Size   int64
+	Purged bool // the archive has been deleted from the storage after the retention period
+}
+
+// UserExport returns the payload of a task exporting the data of a user
+func (task *Task) UserExport() (*UserExport, error) {
+	if task.Type != structs.TaskTypeUserExport {
+		return nil, fmt.Errorf("Task type is %s, not User Export", task.Type.Name())
+	}
+	export := &UserExport{}
+	if task.PayloadContent == "" {
+		return export, nil
+	}
+	if err := json.Unmarshal([]byte(task.PayloadContent), export); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// SetUserExport sets the payload of a task exporting the data of a user, it is not saved
+func (task *Task) SetUserExport(export *UserExport) error {
+	bs, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+	task.PayloadContent = string(bs)
+	return nil
+}
+
+// FindUserExportTasks returns the tasks exporting the data of a user, the newest first
+func FindUserExportTasks(userID int64) ([]*Task, error) {
+	tasks := make([]*Task, 0, 5)
+	return tasks, db.GetEngine(db.DefaultContext).
+		Where("owner_id = ? AND type = ?", userID, structs.TaskTypeUserExport).
+		Desc("id").
+		Find(&tasks)
+}
+
+// GetLatestUserExportTask returns the newest task exporting the data of a user created after the given time
+// which has not failed
+func GetLatestUserExportTask(userID int64, since timeutil.TimeStamp) (*Task, error) {
+	task := Task{
+		OwnerID: userID,
+		Type:    structs.TaskTypeUserExport,
+	}
+	has, err := db.GetEngine(db.DefaultContext).
+		Where("created >= ? AND status <> ?", since, structs.TaskStatusFailed).
+		Desc("id").
+		Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{0, 0, task.Type}
+	}
+	return &task, nil
+}
+
+// FindFinishedUserExportTasks returns the finished tasks exporting the data of users which ended before the given time
+func FindFinishedUserExportTasks(before timeutil.TimeStamp) ([]*Task, error) {
+	tasks := make([]*Task, 0, 10)
+	return tasks, db.GetEngine(db.DefaultContext).
+		Where("type = ? AND status = ? AND end_time < ?", structs.TaskTypeUserExport, structs.TaskStatusFinished, before).
+		Find(&tasks)
+}
+
 // ErrTaskDoesNotExist represents a "TaskDoesNotExist" kind of error.
 type ErrTaskDoesNotExist struct {
 	ID     int64
@@ -170,7 +441,8 @@ func GetMigratingTask(repoID int64) (*Task, error) {
 		RepoID: repoID,
 		Type:   structs.TaskTypeMigrateRepo,
 	}
-	has, err := db.GetEngine(db.DefaultContext).Get(&task)
+	// The migrate type is the zero value, which is ignored by Get
+	has, err := db.GetEngine(db.DefaultContext).Where("type = ?", task.Type).Get(&task)
 	if err != nil {
 		return nil, err
 	} else if !has {
@@ -186,7 +458,8 @@ func GetMigratingTaskByID(id, doerID int64) (*Task, *migration.MigrateOptions, e
 		DoerID: doerID,
 		Type:   structs.TaskTypeMigrateRepo,
 	}
-	has, err := db.GetEngine(db.DefaultContext).Get(&task)
+	// The migrate type is the zero value, which is ignored by Get
+	has, err := db.GetEngine(db.DefaultContext).Where("type = ?", task.Type).Get(&task)
 	if err != nil {
 		return nil, nil, err
 	} else if !has {
@@ -221,6 +494,18 @@ func FindTasks(opts FindTaskOptions) ([]*Task, error) {
 	return tasks, err
 }
 
+// GetTaskByID returns the task with the given id
+func GetTaskByID(id int64) (*Task, error) {
+	var task Task
+	has, err := db.GetEngine(db.DefaultContext).ID(id).Get(&task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTaskDoesNotExist{ID: id}
+	}
+	return &task, nil
+}
+
 // CreateTask creates a task on database
 func CreateTask(task *Task) error {
 	return createTask(db.GetEngine(db.DefaultContext), task)
@@ -235,6 +520,7 @@ func createTask(e db.Engine, task *Task) error {
 func FinishMigrateTask(task *Task) error {
 	task.Status = structs.TaskStatusFinished
 	task.EndTime = timeutil.TimeStampNow()
+	task.LastSyncUnix = task.StartTime
 
 	// delete credentials when we're done, they're a liability.
 	conf, err := task.MigrateConfig()
@@ -258,7 +544,7 @@ func FinishMigrateTask(task *Task) error {
 	if err := sess.Begin(); err != nil {
 		return err
 	}
-	if _, err := sess.ID(task.ID).Cols("status", "end_time", "payload_content").Update(task); err != nil {
+	if _, err := sess.ID(task.ID).Cols("status", "end_time", "payload_content", "last_sync_unix").Update(task); err != nil {
 		return err
 	}
 
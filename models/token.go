@@ -19,6 +19,7 @@ import (
 
 	gouuid "github.com/google/uuid"
 	lru "github.com/hashicorp/golang-lru"
+	"xorm.io/builder"
 )
 
 var successfulAccessTokenCache *lru.Cache
@@ -35,14 +36,19 @@ type AccessToken struct {
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"INDEX updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	LastUsedIP        string             `xorm:"VARCHAR(64)"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
 }
 
+// accessTokenLastUsedInterval is the minimal interval between two updates of the last use of a token
+const accessTokenLastUsedInterval = 60 // seconds
+
 // AfterLoad is invoked from XORM after setting the values of all fields of this object.
 func (t *AccessToken) AfterLoad() {
-	t.HasUsed = t.UpdatedUnix > t.CreatedUnix
-	t.HasRecentActivity = t.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
+	t.HasUsed = t.LastUsedUnix > 0
+	t.HasRecentActivity = t.LastUsedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
 }
 
 func init() {
@@ -180,6 +186,25 @@ func UpdateAccessToken(t *AccessToken) error {
 	return err
 }
 
+// UpdateAccessTokenLastUsed records that the token has just been used, from the given IP if
+// recording it is enabled. To avoid a write per request this happens at most once a minute.
+func UpdateAccessTokenLastUsed(t *AccessToken, ip string) error {
+	now := timeutil.TimeStampNow()
+	if now-t.LastUsedUnix < accessTokenLastUsedInterval {
+		return nil
+	}
+	cols := []string{"last_used_unix"}
+	t.LastUsedUnix = now
+	if setting.RecordTokenLastUsedIP {
+		t.LastUsedIP = ip
+		cols = append(cols, "last_used_ip")
+	}
+	// the condition keeps concurrent requests from all writing the same row
+	_, err := db.GetEngine(db.DefaultContext).ID(t.ID).Cols(cols...).NoAutoTime().
+		Where("last_used_unix <= ?", now-accessTokenLastUsedInterval).Update(t)
+	return err
+}
+
 // CountAccessTokens count access tokens belongs to given user by options
 func CountAccessTokens(opts ListAccessTokensOptions) (int64, error) {
 	sess := db.GetEngine(db.DefaultContext).Where("uid=?", opts.UserID)
@@ -189,6 +214,34 @@ func CountAccessTokens(opts ListAccessTokensOptions) (int64, error) {
 	return sess.Count(&AccessToken{})
 }
 
+// FindUnusedAccessTokensOptions contain options to find the tokens which have not been used for a while
+type FindUnusedAccessTokensOptions struct {
+	db.ListOptions
+	UnusedSince timeutil.TimeStamp
+}
+
+func (opts *FindUnusedAccessTokensOptions) toConds() builder.Cond {
+	// tokens which have never been used count from their creation
+	return builder.Lt{"last_used_unix": opts.UnusedSince}.And(builder.Lt{"created_unix": opts.UnusedSince})
+}
+
+// FindUnusedAccessTokens returns the tokens of all users which have not been used since the given time
+func FindUnusedAccessTokens(opts FindUnusedAccessTokensOptions) ([]*AccessToken, int64, error) {
+	sess := db.GetEngine(db.DefaultContext).Where(opts.toConds()).Asc("last_used_unix", "id")
+	if opts.Page != 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	tokens := make([]*AccessToken, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&tokens)
+	return tokens, count, err
+}
+
+// DeleteUnusedAccessTokens deletes the tokens of all users which have not been used since the given time
+func DeleteUnusedAccessTokens(unusedSince timeutil.TimeStamp) (int64, error) {
+	opts := FindUnusedAccessTokensOptions{UnusedSince: unusedSince}
+	return db.GetEngine(db.DefaultContext).Where(opts.toConds()).Delete(&AccessToken{})
+}
+
 // DeleteAccessTokenByID deletes access token by given ID.
 func DeleteAccessTokenByID(id, userID int64) error {
 	cnt, err := db.GetEngine(db.DefaultContext).ID(id).Delete(&AccessToken{
@@ -6,8 +6,12 @@ package models
 
 import (
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -114,6 +118,70 @@ func TestUpdateAccessToken(t *testing.T) {
 	db.AssertExistsAndLoadBean(t, token)
 }
 
+func TestUpdateAccessTokenLastUsed(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	defer func(record bool) {
+		setting.RecordTokenLastUsedIP = record
+	}(setting.RecordTokenLastUsedIP)
+
+	token, err := GetAccessTokenBySHA("4c6f36e6cf498e2a448662f915d932c09c5a146c")
+	assert.NoError(t, err)
+	assert.False(t, token.HasUsed)
+
+	assert.NoError(t, UpdateAccessTokenLastUsed(token, "127.0.0.1"))
+	token = db.AssertExistsAndLoadBean(t, &AccessToken{ID: token.ID}).(*AccessToken)
+	assert.True(t, token.HasUsed)
+	assert.True(t, token.HasRecentActivity)
+	assert.Empty(t, token.LastUsedIP)
+	lastUsed := token.LastUsedUnix
+
+	// further uses within a minute are not recorded
+	setting.RecordTokenLastUsedIP = true
+	assert.NoError(t, UpdateAccessTokenLastUsed(token, "127.0.0.2"))
+	token = db.AssertExistsAndLoadBean(t, &AccessToken{ID: token.ID}).(*AccessToken)
+	assert.Equal(t, lastUsed, token.LastUsedUnix)
+	assert.Empty(t, token.LastUsedIP)
+
+	token.LastUsedUnix -= 60
+	assert.NoError(t, UpdateAccessToken(token))
+	assert.NoError(t, UpdateAccessTokenLastUsed(token, "127.0.0.2"))
+	token = db.AssertExistsAndLoadBean(t, &AccessToken{ID: token.ID}).(*AccessToken)
+	assert.GreaterOrEqual(t, int64(token.LastUsedUnix), int64(lastUsed))
+	assert.Equal(t, "127.0.0.2", token.LastUsedIP)
+}
+
+func TestFindUnusedAccessTokens(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	token, err := GetAccessTokenBySHA("4c6f36e6cf498e2a448662f915d932c09c5a146c")
+	assert.NoError(t, err)
+	assert.NoError(t, UpdateAccessTokenLastUsed(token, ""))
+
+	since := timeutil.TimeStampNow().AddDuration(-24 * time.Hour)
+	tokens, count, err := FindUnusedAccessTokens(FindUnusedAccessTokensOptions{UnusedSince: since})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, tokens, 2) {
+		assert.EqualValues(t, 1, tokens[0].ID)
+		assert.EqualValues(t, 3, tokens[1].ID)
+	}
+
+	tokens, count, err = FindUnusedAccessTokens(FindUnusedAccessTokensOptions{
+		ListOptions: db.ListOptions{Page: 1, PageSize: 1},
+		UnusedSince: since,
+	})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.Len(t, tokens, 1)
+
+	deleted, err := DeleteUnusedAccessTokens(since)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, deleted)
+	db.AssertNotExistsBean(t, &AccessToken{ID: 1})
+	db.AssertNotExistsBean(t, &AccessToken{ID: 3})
+	db.AssertExistsAndLoadBean(t, &AccessToken{ID: 2})
+}
+
 func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
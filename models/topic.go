@@ -53,6 +53,25 @@ func (err ErrTopicNotExist) Error() string {
 	return fmt.Sprintf("topic is not exist [name: %s]", err.Name)
 }
 
+// ErrTopicAlreadyExist represents an error that a topic already exists
+type ErrTopicAlreadyExist struct {
+	Name string
+}
+
+// IsErrTopicAlreadyExist checks if an error is an ErrTopicAlreadyExist.
+func IsErrTopicAlreadyExist(err error) bool {
+	_, ok := err.(ErrTopicAlreadyExist)
+	return ok
+}
+
+// Error implements error interface
+func (err ErrTopicAlreadyExist) Error() string {
+	return fmt.Sprintf("topic already exists [name: %s]", err.Name)
+}
+
+// MaxTopicsPerRepo is the maximum number of topics of a repository
+const MaxTopicsPerRepo = 25
+
 // ValidateTopic checks a topic by length and match pattern rules
 func ValidateTopic(topic string) bool {
 	return len(topic) <= 35 && topicPattern.MatchString(topic)
@@ -245,16 +264,7 @@ func AddTopic(repoID int64, topicName string) (*Topic, error) {
 		return nil, err
 	}
 
-	topicNames := make([]string, 0, 25)
-	if err := sess.Select("name").Table("topic").
-		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
-		Where("repo_topic.repo_id = ?", repoID).Desc("topic.repo_count").Find(&topicNames); err != nil {
-		return nil, err
-	}
-
-	if _, err := sess.ID(repoID).Cols("topics").Update(&Repository{
-		Topics: topicNames,
-	}); err != nil {
+	if err := updateRepoTopicsCache(sess, repoID); err != nil {
 		return nil, err
 	}
 
@@ -263,7 +273,13 @@ func AddTopic(repoID int64, topicName string) (*Topic, error) {
 
 // DeleteTopic removes a topic name from a repository (if it has it)
 func DeleteTopic(repoID int64, topicName string) (*Topic, error) {
-	topic, err := GetRepoTopicByName(repoID, topicName)
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	topic, err := getRepoTopicByName(sess, repoID, topicName)
 	if err != nil {
 		return nil, err
 	}
@@ -272,9 +288,15 @@ func DeleteTopic(repoID int64, topicName string) (*Topic, error) {
 		return nil, nil
 	}
 
-	err = removeTopicFromRepo(db.GetEngine(db.DefaultContext), repoID, topic)
+	if err := removeTopicFromRepo(sess, repoID, topic); err != nil {
+		return nil, err
+	}
+
+	if err := updateRepoTopicsCache(sess, repoID); err != nil {
+		return nil, err
+	}
 
-	return topic, err
+	return topic, sess.Commit()
 }
 
 // SaveTopics save topics to a repository
@@ -339,18 +361,210 @@ func SaveTopics(repoID int64, topicNames ...string) error {
 		}
 	}
 
-	topicNames = make([]string, 0, 25)
-	if err := sess.Table("topic").Cols("name").
+	if err := updateRepoTopicsCache(sess, repoID); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// updateRepoTopicsCache updates the topics column of the repository from its repo_topic records.
+// The repo_topic records are authoritative, the column only caches the topic names for rendering.
+func updateRepoTopicsCache(e db.Engine, repoID int64) error {
+	topicNames := make([]string, 0, 25)
+	if err := e.Table("topic").Cols("name").
 		Join("INNER", "repo_topic", "repo_topic.topic_id = topic.id").
 		Where("repo_topic.repo_id = ?", repoID).Desc("topic.repo_count").Find(&topicNames); err != nil {
 		return err
 	}
 
-	if _, err := sess.ID(repoID).Cols("topics").Update(&Repository{
+	_, err := e.ID(repoID).Cols("topics").NoAutoTime().Update(&Repository{
 		Topics: topicNames,
-	}); err != nil {
+	})
+	return err
+}
+
+func getTopicRepoIDs(e db.Engine, topicID int64) ([]int64, error) {
+	repoIDs := make([]int64, 0, 10)
+	return repoIDs, e.Table("repo_topic").Cols("repo_id").Where("topic_id = ?", topicID).Find(&repoIDs)
+}
+
+// RenameTopic renames a topic for all repositories,
+// use MergeTopics if a topic with the new name already exists.
+func RenameTopic(oldName, newName string) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
 		return err
 	}
 
+	var topic Topic
+	if has, err := sess.Where("name = ?", oldName).Get(&topic); err != nil {
+		return err
+	} else if !has {
+		return ErrTopicNotExist{oldName}
+	}
+	if has, err := sess.Where("name = ?", newName).Exist(new(Topic)); err != nil {
+		return err
+	} else if has {
+		return ErrTopicAlreadyExist{newName}
+	}
+
+	topic.Name = newName
+	if _, err := sess.ID(topic.ID).Cols("name").Update(&topic); err != nil {
+		return err
+	}
+
+	repoIDs, err := getTopicRepoIDs(sess, topic.ID)
+	if err != nil {
+		return err
+	}
+	for _, repoID := range repoIDs {
+		if err := updateRepoTopicsCache(sess, repoID); err != nil {
+			return err
+		}
+	}
+
 	return sess.Commit()
 }
+
+// MergeTopics moves all repositories of the source topic to the target topic and deletes the source topic.
+func MergeTopics(sourceName, targetName string) (*Topic, error) {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return nil, err
+	}
+
+	var source, target Topic
+	if has, err := sess.Where("name = ?", sourceName).Get(&source); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTopicNotExist{sourceName}
+	}
+	if has, err := sess.Where("name = ?", targetName).Get(&target); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTopicNotExist{targetName}
+	}
+	if source.ID == target.ID {
+		return &target, nil
+	}
+
+	repoIDs, err := getTopicRepoIDs(sess, source.ID)
+	if err != nil {
+		return nil, err
+	}
+	targetRepoIDs, err := getTopicRepoIDs(sess, target.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// repositories which already have the target topic only lose the source topic
+	if len(targetRepoIDs) > 0 {
+		if _, err := sess.Where("topic_id = ?", source.ID).In("repo_id", targetRepoIDs).Delete(new(RepoTopic)); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := sess.Where("topic_id = ?", source.ID).Cols("topic_id").Update(&RepoTopic{TopicID: target.ID}); err != nil {
+		return nil, err
+	}
+	if _, err := sess.ID(source.ID).Delete(new(Topic)); err != nil {
+		return nil, err
+	}
+
+	count, err := sess.Where("topic_id = ?", target.ID).Count(new(RepoTopic))
+	if err != nil {
+		return nil, err
+	}
+	target.RepoCount = int(count)
+	if _, err := sess.ID(target.ID).Cols("repo_count").Update(&target); err != nil {
+		return nil, err
+	}
+
+	for _, repoID := range repoIDs {
+		if err := updateRepoTopicsCache(sess, repoID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &target, sess.Commit()
+}
+
+// CountTopicsWithWrongRepoCount counts the topics whose repo_count doesn't match their repo_topic records
+func CountTopicsWithWrongRepoCount() (int64, error) {
+	return db.GetEngine(db.DefaultContext).
+		Where("repo_count != (SELECT COUNT(*) FROM repo_topic WHERE repo_topic.topic_id = topic.id)").
+		Count(new(Topic))
+}
+
+// FixTopicsWithWrongRepoCount recalculates the repo_count of all topics from their repo_topic records
+func FixTopicsWithWrongRepoCount() (int64, error) {
+	res, err := db.GetEngine(db.DefaultContext).
+		Exec("UPDATE topic SET repo_count = (SELECT COUNT(*) FROM repo_topic WHERE repo_topic.topic_id = topic.id) " +
+			"WHERE repo_count != (SELECT COUNT(*) FROM repo_topic WHERE repo_topic.topic_id = topic.id)")
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// findReposWithInconsistentTopics returns the IDs of the repositories whose topics column doesn't match their topics
+func findReposWithInconsistentTopics() ([]int64, error) {
+	type repoTopicName struct {
+		RepoID int64
+		Name   string
+	}
+	names := make([]*repoTopicName, 0, 50)
+	if err := db.GetEngine(db.DefaultContext).Table("repo_topic").
+		Join("INNER", "topic", "topic.id = repo_topic.topic_id").
+		Select("repo_topic.repo_id, topic.name").
+		Find(&names); err != nil {
+		return nil, err
+	}
+	repoTopics := make(map[int64]map[string]struct{}, len(names))
+	for _, name := range names {
+		if repoTopics[name.RepoID] == nil {
+			repoTopics[name.RepoID] = make(map[string]struct{})
+		}
+		repoTopics[name.RepoID][name.Name] = struct{}{}
+	}
+
+	repoIDs := make([]int64, 0, 10)
+	err := db.Iterate(db.DefaultContext, new(Repository), builder.NewCond(), func(idx int, bean interface{}) error {
+		repo := bean.(*Repository)
+		topics := repoTopics[repo.ID]
+		consistent := len(repo.Topics) == len(topics)
+		for _, name := range repo.Topics {
+			if _, ok := topics[name]; !ok {
+				consistent = false
+				break
+			}
+		}
+		if !consistent {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+		return nil
+	})
+	return repoIDs, err
+}
+
+// CountReposWithInconsistentTopics counts the repositories whose topics column doesn't match their topics
+func CountReposWithInconsistentTopics() (int64, error) {
+	repoIDs, err := findReposWithInconsistentTopics()
+	return int64(len(repoIDs)), err
+}
+
+// FixReposWithInconsistentTopics updates the topics column of the repositories which don't match their topics
+func FixReposWithInconsistentTopics() (int64, error) {
+	repoIDs, err := findReposWithInconsistentTopics()
+	if err != nil {
+		return 0, err
+	}
+	for _, repoID := range repoIDs {
+		if err := updateRepoTopicsCache(db.GetEngine(db.DefaultContext), repoID); err != nil {
+			return 0, err
+		}
+	}
+	return int64(len(repoIDs)), nil
+}
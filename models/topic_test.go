@@ -77,3 +77,83 @@ func TestTopicValidator(t *testing.T) {
 	assert.False(t, ValidateTopic("-fifth-test-topic"))
 	assert.False(t, ValidateTopic("sixth-go-project-topic-with-excess-length"))
 }
+
+func TestDeleteTopic(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.NoError(t, SaveTopics(1, "golang", "database", "sql"))
+	topic, err := DeleteTopic(1, "database")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "database", topic.Name)
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.ElementsMatch(t, []string{"golang", "SQL"}, repo.Topics)
+	db.AssertExistsAndLoadBean(t, &Topic{ID: 2, RepoCount: 0})
+}
+
+func TestRenameTopic(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	assert.NoError(t, SaveTopics(1, "golang", "database", "sql"))
+	assert.True(t, IsErrTopicNotExist(RenameTopic("kubernets", "kubernetes")))
+	assert.True(t, IsErrTopicAlreadyExist(RenameTopic("database", "golang")))
+
+	assert.NoError(t, RenameTopic("database", "databases"))
+	db.AssertExistsAndLoadBean(t, &Topic{ID: 2, Name: "databases"})
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.ElementsMatch(t, []string{"golang", "databases", "SQL"}, repo.Topics)
+}
+
+func TestMergeTopics(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// repo 1 has both topics, repo 33 only the merged one
+	assert.NoError(t, SaveTopics(1, "golang", "database", "sql"))
+	assert.NoError(t, SaveTopics(33, "golang", "graphql"))
+	assert.NoError(t, SaveTopics(33, "database", "graphql"))
+
+	_, err := MergeTopics("database", "kubernetes")
+	assert.True(t, IsErrTopicNotExist(err))
+
+	topic, err := MergeTopics("database", "golang")
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, topic.ID)
+	assert.EqualValues(t, 2, topic.RepoCount)
+	db.AssertNotExistsBean(t, &Topic{ID: 2})
+	db.AssertNotExistsBean(t, &RepoTopic{TopicID: 2})
+	db.AssertExistsAndLoadBean(t, &RepoTopic{RepoID: 1, TopicID: 1})
+	db.AssertExistsAndLoadBean(t, &RepoTopic{RepoID: 33, TopicID: 1})
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.ElementsMatch(t, []string{"golang", "SQL"}, repo.Topics)
+	repo = db.AssertExistsAndLoadBean(t, &Repository{ID: 33}).(*Repository)
+	assert.ElementsMatch(t, []string{"golang", "graphql"}, repo.Topics)
+}
+
+func TestFixTopicsConsistency(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// the topics of the repositories aren't cached in the fixtures
+	count, err := CountReposWithInconsistentTopics()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	fixed, err := FixReposWithInconsistentTopics()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, fixed)
+	count, err = CountReposWithInconsistentTopics()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 2}).(*Repository)
+	assert.ElementsMatch(t, []string{"topicname1", "topicname2"}, repo.Topics)
+
+	// topicname2 is counted twice but only has one repository
+	count, err = CountTopicsWithWrongRepoCount()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	_, err = FixTopicsWithWrongRepoCount()
+	assert.NoError(t, err)
+	count, err = CountTopicsWithWrongRepoCount()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+	db.AssertExistsAndLoadBean(t, &Topic{ID: 6, RepoCount: 1})
+}
@@ -35,6 +35,7 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
+	"golang.org/x/text/unicode/norm"
 
 	"xorm.io/builder"
 	"xorm.io/xorm"
@@ -139,6 +140,15 @@ type User struct {
 	AllowImportLocal        bool // Allow migrate repository by local path
 	AllowCreateOrganization bool `xorm:"DEFAULT true"`
 	ProhibitLogin           bool `xorm:"NOT NULL DEFAULT false"`
+	// IsServiceAccount exempts the user from the deactivation of inactive users
+	IsServiceAccount bool `xorm:"NOT NULL DEFAULT false"`
+
+	// Deactivation of inactive users
+	InactivityWarnedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	InactivityDeactivatedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+
+	// Anonymization replacing the deletion of the user
+	AnonymizedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 
 	// Avatar
 	Avatar          string `xorm:"VARCHAR(2048) NOT NULL"`
@@ -159,11 +169,16 @@ type User struct {
 	MembersIsPublic           map[int64]bool      `xorm:"-"`
 	Visibility                structs.VisibleType `xorm:"NOT NULL DEFAULT 0"`
 	RepoAdminChangeTeamAccess bool                `xorm:"NOT NULL DEFAULT false"`
+	RequirePrivateRepos       bool                `xorm:"NOT NULL DEFAULT false"`
 
 	// Preferences
-	DiffViewStyle       string `xorm:"NOT NULL DEFAULT ''"`
-	Theme               string `xorm:"NOT NULL DEFAULT ''"`
-	KeepActivityPrivate bool   `xorm:"NOT NULL DEFAULT false"`
+	DiffViewStyle       string              `xorm:"NOT NULL DEFAULT ''"`
+	Theme               string              `xorm:"NOT NULL DEFAULT ''"`
+	KeepActivityPrivate bool                `xorm:"NOT NULL DEFAULT false"`
+	AutoWatch           AutoWatchPreference `xorm:"NOT NULL DEFAULT 0"`
+	IssueSortType       string              `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	// MuteMilestoneReminders disables the reminders about the due dates of milestones
+	MuteMilestoneReminders bool `xorm:"NOT NULL DEFAULT false"`
 }
 
 func init() {
@@ -333,11 +348,12 @@ func (u *User) GenerateEmailActivateCode(email string) string {
 	return code
 }
 
-// GetFollowers returns range of user's followers.
+// GetFollowers returns range of user's followers, the latest followers first.
 func (u *User) GetFollowers(listOptions db.ListOptions) ([]*User, error) {
 	sess := db.GetEngine(db.DefaultContext).
+		Join("INNER", "follow", "`user`.id=follow.user_id").
 		Where("follow.follow_id=?", u.ID).
-		Join("LEFT", "follow", "`user`.id=follow.user_id")
+		Desc("follow.id")
 
 	if listOptions.Page != 0 {
 		sess = db.SetSessionPagination(sess, &listOptions)
@@ -355,11 +371,12 @@ func (u *User) IsFollowing(followID int64) bool {
 	return IsFollowing(u.ID, followID)
 }
 
-// GetFollowing returns range of user's following.
+// GetFollowing returns range of user's following, the latest followed users first.
 func (u *User) GetFollowing(listOptions db.ListOptions) ([]*User, error) {
 	sess := db.GetEngine(db.DefaultContext).
+		Join("INNER", "follow", "`user`.id=follow.follow_id").
 		Where("follow.user_id=?", u.ID).
-		Join("LEFT", "follow", "`user`.id=follow.follow_id")
+		Desc("follow.id")
 
 	if listOptions.Page != 0 {
 		sess = db.SetSessionPagination(sess, &listOptions)
@@ -653,9 +670,10 @@ func (u *User) GetActiveAccessRepoIDs(units ...UnitType) ([]int64, error) {
 	return append(ids, ids2...), nil
 }
 
-// GetMirrorRepositories returns mirror repositories that user owns, including private repositories.
-func (u *User) GetMirrorRepositories() ([]*Repository, error) {
-	return GetUserMirrorRepositories(u.ID)
+// GetMirrorRepositories returns a page of the mirror repositories that user owns, including private repositories,
+// and the total number of them.
+func (u *User) GetMirrorRepositories(listOptions db.ListOptions, orderBy SearchOrderBy) ([]*Repository, int64, error) {
+	return GetUserMirrorRepositories(u.ID, listOptions, orderBy)
 }
 
 // GetOwnedOrganizations returns all organizations that user owns.
@@ -763,6 +781,24 @@ func NewGhostUser() *User {
 	}
 }
 
+// DeployKeyUserID is the ID of the fake users acting for deploy keys
+const DeployKeyUserID = -2
+
+// NewDeployKeyUser creates and returns a fake user acting for the deploy key with the given name
+func NewDeployKeyUser(name string) *User {
+	return &User{
+		ID:        DeployKeyUserID,
+		Name:      name,
+		LowerName: strings.ToLower(name),
+		IsActive:  true,
+	}
+}
+
+// IsDeployKey check if user is a fake user acting for a deploy key
+func (u *User) IsDeployKey() bool {
+	return u.ID == DeployKeyUserID
+}
+
 // NewReplaceUser creates and returns a fake user for external user
 func NewReplaceUser(name string) *User {
 	return &User{
@@ -820,17 +856,31 @@ var (
 		"user",
 	}
 
-	reservedUserPatterns = []string{"*.keys", "*.gpg", "*.rss", "*.atom"}
+	reservedUserPatterns = []string{"*.keys", "*.gpg", "*.rss", "*.atom", AnonymizedUserNamePrefix + "*"}
 )
 
+// NormalizeName returns the NFC normalized form of a user, organization or repository name,
+// so that visually identical names are stored and compared identically. Confusable and
+// mixed-script names need no extra check: alphaDashDotPattern only accepts ASCII letters,
+// digits, dashes, underscores and dots, so any other script is rejected by
+// IsUsableUsername and IsUsableRepoName.
+func NormalizeName(name string) string {
+	return norm.NFC.String(name)
+}
+
 // isUsableName checks if name is reserved or pattern of name is not allowed
 // based on given reserved names and patterns.
 // Names are exact match, patterns can be prefix or suffix match with placeholder '*'.
-func isUsableName(names, patterns []string, name string) error {
+// kind names the kind of name in errors, e.g. "user name".
+func isUsableName(names, patterns []string, kind, name string, maxLength int) error {
 	name = strings.TrimSpace(strings.ToLower(name))
-	if utf8.RuneCountInString(name) == 0 {
+	length := utf8.RuneCountInString(name)
+	if length == 0 {
 		return ErrNameEmpty
 	}
+	if maxLength > 0 && length > maxLength {
+		return ErrNameTooLong{Kind: kind, Name: name, MaxLength: maxLength}
+	}
 
 	for i := range names {
 		if name == names[i] {
@@ -855,7 +905,7 @@ func IsUsableUsername(name string) error {
 		// Note: usually this error is normally caught up earlier in the UI
 		return ErrNameCharsNotAllowed{Name: name}
 	}
-	return isUsableName(reservedUsernames, reservedUserPatterns, name)
+	return isUsableName(reservedUsernames, reservedUserPatterns, "user name", name, setting.Service.MaxUserNameLength)
 }
 
 // CreateUserOverwriteOptions are an optional options who overwrite system defaults on user creation
@@ -865,7 +915,85 @@ type CreateUserOverwriteOptions struct {
 
 // CreateUser creates record of a new user.
 func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err error) {
-	if err = IsUsableUsername(u.Name); err != nil {
+	if len(overwriteDefault) == 0 {
+		overwriteDefault = []*CreateUserOverwriteOptions{nil}
+	}
+	if err = prepareNewUser(u, overwriteDefault[0]); err != nil {
+		return err
+	}
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+
+	if err = checkNewUserConflicts(sess, u); err != nil {
+		return err
+	}
+	if err = insertNewUser(sess, u); err != nil {
+		return err
+	}
+
+	return sess.Commit()
+}
+
+// createUsersBatchSize is the number of users CreateUsers creates in one transaction
+const createUsersBatchSize = 50
+
+// CreateUsers creates records of new users in batches, each in one transaction.
+// It returns an error for each user which could not be created: the users which are invalid or conflict
+// with existing users are skipped, the other users of a batch which failed are not created.
+func CreateUsers(users []*User, overwriteDefaults []*CreateUserOverwriteOptions) []error {
+	errs := make([]error, len(users))
+	for start := 0; start < len(users); start += createUsersBatchSize {
+		end := start + createUsersBatchSize
+		if end > len(users) {
+			end = len(users)
+		}
+		if err := createUsersBatch(users[start:end], overwriteDefaults[start:end], errs[start:end]); err != nil {
+			log.Error("Unable to create the users %d to %d: %v", start, end-1, err)
+			for i := start; i < end; i++ {
+				if errs[i] == nil {
+					users[i].ID = 0
+					errs[i] = err
+				}
+			}
+		}
+	}
+	return errs
+}
+
+func createUsersBatch(users []*User, overwriteDefaults []*CreateUserOverwriteOptions, errs []error) error {
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	for i, u := range users {
+		if errs[i] = prepareNewUser(u, overwriteDefaults[i]); errs[i] != nil {
+			continue
+		}
+		if err := checkNewUserConflicts(sess, u); err != nil {
+			if !IsErrUserAlreadyExist(err) && !IsErrEmailAlreadyUsed(err) {
+				return err
+			}
+			errs[i] = err
+			continue
+		}
+		if err := insertNewUser(sess, u); err != nil {
+			return err
+		}
+	}
+
+	return sess.Commit()
+}
+
+// prepareNewUser validates a new user and sets its defaults, without accessing the database
+func prepareNewUser(u *User, overwriteDefault *CreateUserOverwriteOptions) error {
+	u.Name = NormalizeName(u.Name)
+	if err := IsUsableUsername(u.Name); err != nil {
 		return err
 	}
 
@@ -878,36 +1006,33 @@ func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err e
 	u.Theme = setting.UI.DefaultTheme
 
 	// overwrite defaults if set
-	if len(overwriteDefault) != 0 && overwriteDefault[0] != nil {
-		u.Visibility = overwriteDefault[0].Visibility
-	}
-
-	sess := db.NewSession(db.DefaultContext)
-	defer sess.Close()
-	if err = sess.Begin(); err != nil {
-		return err
+	if overwriteDefault != nil {
+		u.Visibility = overwriteDefault.Visibility
 	}
 
 	// validate data
+	return validateUser(u)
+}
 
-	if err := validateUser(u); err != nil {
-		return err
-	}
-
-	isExist, err := isUserExist(sess, 0, u.Name)
+// checkNewUserConflicts checks that the name and the email address of a new user are not used yet
+func checkNewUserConflicts(e db.Engine, u *User) error {
+	isExist, err := isUserExist(e, 0, u.Name)
 	if err != nil {
 		return err
 	} else if isExist {
 		return ErrUserAlreadyExist{u.Name}
 	}
 
-	isExist, err = isEmailUsed(sess, u.Email)
+	isExist, err = isEmailUsed(e, u.Email)
 	if err != nil {
 		return err
 	} else if isExist {
 		return ErrEmailAlreadyUsed{u.Email}
 	}
+	return nil
+}
 
+func insertNewUser(e db.Engine, u *User) (err error) {
 	// prepare for database
 
 	u.LowerName = strings.ToLower(u.Name)
@@ -921,16 +1046,16 @@ func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err e
 
 	// save changes to database
 
-	if err = deleteUserRedirect(sess, u.Name); err != nil {
+	if err = deleteUserRedirect(e, u.Name); err != nil {
 		return err
 	}
 
-	if _, err = sess.Insert(u); err != nil {
+	if _, err = e.Insert(u); err != nil {
 		return err
 	}
 
 	// insert email address
-	if _, err := sess.Insert(&EmailAddress{
+	if _, err := e.Insert(&EmailAddress{
 		UID:         u.ID,
 		Email:       u.Email,
 		LowerEmail:  strings.ToLower(u.Email),
@@ -940,7 +1065,7 @@ func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err e
 		return err
 	}
 
-	return sess.Commit()
+	return watchDefaultRepos(e, u)
 }
 
 func countUsers(e db.Engine) int64 {
@@ -977,7 +1102,8 @@ func getVerifyUser(code string) (user *User) {
 func VerifyUserActiveCode(code string) (user *User) {
 	minutes := setting.Service.ActiveCodeLives
 
-	if user = getVerifyUser(code); user != nil {
+	// anonymized users can never be activated again nor reset their password
+	if user = getVerifyUser(code); user != nil && !user.IsAnonymized() {
 		// time limit code
 		prefix := code[:base.TimeLimitCodeLength]
 		data := fmt.Sprintf("%d%s%s%s%s", user.ID, user.Email, user.LowerName, user.Passwd, user.Rands)
@@ -993,7 +1119,7 @@ func VerifyUserActiveCode(code string) (user *User) {
 func VerifyActiveEmailCode(code, email string) *EmailAddress {
 	minutes := setting.Service.ActiveCodeLives
 
-	if user := getVerifyUser(code); user != nil {
+	if user := getVerifyUser(code); user != nil && !user.IsAnonymized() {
 		// time limit code
 		prefix := code[:base.TimeLimitCodeLength]
 		data := fmt.Sprintf("%d%s%s%s%s", user.ID, email, user.LowerName, user.Passwd, user.Rands)
@@ -1011,6 +1137,7 @@ func VerifyActiveEmailCode(code, email string) *EmailAddress {
 // ChangeUserName changes all corresponding setting from old user name to new one.
 func ChangeUserName(u *User, newUserName string) (err error) {
 	oldUserName := u.Name
+	newUserName = NormalizeName(newUserName)
 	if err = IsUsableUsername(newUserName); err != nil {
 		return err
 	}
@@ -1021,17 +1148,23 @@ func ChangeUserName(u *User, newUserName string) (err error) {
 		return err
 	}
 
-	isExist, err := isUserExist(sess, 0, newUserName)
+	isExist, err := isUserExist(sess, u.ID, newUserName)
 	if err != nil {
 		return err
 	} else if isExist {
 		return ErrUserAlreadyExist{newUserName}
 	}
 
-	if _, err = sess.Exec("UPDATE `repository` SET owner_name=? WHERE owner_name=?", newUserName, oldUserName); err != nil {
+	if _, err = sess.Exec("UPDATE `repository` SET owner_name=? WHERE owner_id=?", newUserName, u.ID); err != nil {
 		return fmt.Errorf("Change repo owner name: %v", err)
 	}
 
+	// Names are looked up case-insensitively and user directories are lower case,
+	// so a change of the case neither moves the directory nor needs a redirect
+	if strings.EqualFold(oldUserName, newUserName) {
+		return sess.Commit()
+	}
+
 	// Do not fail if directory does not exist
 	if err = util.Rename(UserPath(oldUserName), UserPath(newUserName)); err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("Rename user directory: %v", err)
@@ -1195,6 +1328,7 @@ func deleteUser(e db.Engine, u *User) error {
 
 	if err = deleteBeans(e,
 		&AccessToken{UID: u.ID},
+		&RepoCloneToken{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Access{UserID: u.ID},
 		&Watch{UserID: u.ID},
@@ -1209,6 +1343,10 @@ func deleteUser(e db.Engine, u *User) error {
 		&TeamUser{UID: u.ID},
 		&Collaboration{UserID: u.ID},
 		&Stopwatch{UserID: u.ID},
+		&SavedReply{OwnerID: u.ID},
+		&PullReviewFileState{UserID: u.ID},
+		&MilestoneReminder{UserID: u.ID},
+		&RepoVisit{UserID: u.ID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %v", err)
 	}
@@ -1241,17 +1379,11 @@ func deleteUser(e db.Engine, u *User) error {
 	}
 
 	// ***** START: PublicKey *****
+	// Note: the authorized_keys and authorized_principals files are updated by the callers
+	// once the deletion has been committed
 	if _, err = e.Delete(&PublicKey{OwnerID: u.ID}); err != nil {
 		return fmt.Errorf("deletePublicKeys: %v", err)
 	}
-	err = rewriteAllPublicKeys(e)
-	if err != nil {
-		return err
-	}
-	err = rewriteAllPrincipalKeys(e)
-	if err != nil {
-		return err
-	}
 	// ***** END: PublicKey *****
 
 	// ***** START: GPGPublicKey *****
@@ -1310,6 +1442,21 @@ func deleteUser(e db.Engine, u *User) error {
 // but issues/comments/pulls will be kept and shown as someone has been deleted,
 // unless the user is younger than USER_DELETE_WITH_COMMENTS_MAX_DAYS.
 func DeleteUser(u *User) (err error) {
+	keys := make([]*PublicKey, 0, 5)
+	if err = db.GetEngine(db.DefaultContext).Where("owner_id = ?", u.ID).Find(&keys); err != nil {
+		return fmt.Errorf("find public keys: %v", err)
+	}
+
+	if err = deleteUserWithoutRewritingKeys(u); err != nil {
+		return err
+	}
+
+	return removeAuthorizedKeys(keys)
+}
+
+// deleteUserWithoutRewritingKeys deletes the user but leaves updating the
+// authorized_keys and authorized_principals files to the caller
+func deleteUserWithoutRewritingKeys(u *User) (err error) {
 	if u.IsOrganization() {
 		return fmt.Errorf("%s is an organization not a user", u.Name)
 	}
@@ -1328,42 +1475,137 @@ func DeleteUser(u *User) (err error) {
 	return sess.Commit()
 }
 
+// removeAuthorizedKeys removes the deleted keys from the authorized_keys and authorized_principals files
+func removeAuthorizedKeys(keys []*PublicKey) error {
+	for _, key := range keys {
+		if key.Type == KeyTypePrincipal {
+			if err := RewriteAllPrincipalKeys(); err != nil {
+				return err
+			}
+			break
+		}
+	}
+	return removeAuthorizedKeysFromFile(keys...)
+}
+
+// InactiveUserDeletion describes what DeleteInactiveUsers did or would do with an inactive user
+type InactiveUserDeletion struct {
+	User *User
+	// SkipReason is set if the user is kept because it owns repositories or belongs to organizations
+	SkipReason string
+}
+
+// DeleteInactiveUsersResult is the report of DeleteInactiveUsers
+type DeleteInactiveUsersResult struct {
+	Users   []*InactiveUserDeletion
+	Deleted int
+	Skipped int
+}
+
+// Reasons why DeleteInactiveUsers keeps an inactive user
+const (
+	InactiveUserSkipOwnsRepos = "owns repositories"
+	InactiveUserSkipHasOrgs   = "belongs to organizations"
+)
+
+// inactiveUserSkipReason returns why an inactive user can't be deleted, or an empty string if it can
+func inactiveUserSkipReason(e db.Engine, u *User) (string, error) {
+	count, err := getRepositoryCount(e, u)
+	if err != nil {
+		return "", fmt.Errorf("GetRepositoryCount: %v", err)
+	} else if count > 0 {
+		return InactiveUserSkipOwnsRepos, nil
+	}
+
+	count, err = u.getOrganizationCount(e)
+	if err != nil {
+		return "", fmt.Errorf("GetOrganizationCount: %v", err)
+	} else if count > 0 {
+		return InactiveUserSkipHasOrgs, nil
+	}
+	return "", nil
+}
+
 // DeleteInactiveUsers deletes all inactive users and email addresses.
-func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration) (err error) {
+// In dry run mode nothing is deleted and the result lists what would be deleted.
+// The result covers the users handled before the context was cancelled.
+func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration, dryRun bool) (result *DeleteInactiveUsersResult, err error) {
 	users := make([]*User, 0, 10)
+	sess := db.GetEngine(db.DefaultContext).Where("is_active = ? AND type = ?", false, UserTypeIndividual)
 	if olderThan > 0 {
-		if err = db.GetEngine(db.DefaultContext).
-			Where("is_active = ? and created_unix < ?", false, time.Now().Add(-olderThan).Unix()).
-			Find(&users); err != nil {
-			return fmt.Errorf("get all inactive users: %v", err)
+		sess = sess.And("created_unix < ?", time.Now().Add(-olderThan).Unix())
+	}
+	if err = sess.Asc("id").Find(&users); err != nil {
+		return nil, fmt.Errorf("get all inactive users: %v", err)
+	}
+
+	result = &DeleteInactiveUsersResult{
+		Users: make([]*InactiveUserDeletion, 0, len(users)),
+	}
+
+	// The keys of all deleted users are removed from the authorized_keys and
+	// authorized_principals files at once after all deletions
+	deletedKeys := make([]*PublicKey, 0, len(users))
+	defer func() {
+		if len(deletedKeys) == 0 {
+			return
 		}
-	} else {
-		if err = db.GetEngine(db.DefaultContext).
-			Where("is_active = ?", false).
-			Find(&users); err != nil {
-			return fmt.Errorf("get all inactive users: %v", err)
+		if removeErr := removeAuthorizedKeys(deletedKeys); removeErr != nil {
+			log.Error("removeAuthorizedKeys: %v", removeErr)
+			if err == nil {
+				err = removeErr
+			}
 		}
-	}
-	// FIXME: should only update authorized_keys file once after all deletions.
+	}()
+
 	for _, u := range users {
 		select {
 		case <-ctx.Done():
-			return ErrCancelledf("Before delete inactive user %s", u.Name)
+			return result, ErrCancelledf("Before delete inactive user %s, %d users deleted and %d skipped", u.Name, result.Deleted, result.Skipped)
 		default:
 		}
-		if err = DeleteUser(u); err != nil {
-			// Ignore users that were set inactive by admin.
-			if IsErrUserOwnRepos(err) || IsErrUserHasOrgs(err) {
-				continue
+
+		deletion := &InactiveUserDeletion{User: u}
+		if dryRun {
+			if deletion.SkipReason, err = inactiveUserSkipReason(db.GetEngine(db.DefaultContext), u); err != nil {
+				return result, err
+			}
+		} else {
+			keys := make([]*PublicKey, 0, 5)
+			if err = db.GetEngine(db.DefaultContext).Where("owner_id = ?", u.ID).Find(&keys); err != nil {
+				return result, fmt.Errorf("find public keys: %v", err)
+			}
+			if err = deleteUserWithoutRewritingKeys(u); err != nil {
+				// Ignore users that were set inactive by admin.
+				switch {
+				case IsErrUserOwnRepos(err):
+					deletion.SkipReason = InactiveUserSkipOwnsRepos
+				case IsErrUserHasOrgs(err):
+					deletion.SkipReason = InactiveUserSkipHasOrgs
+				default:
+					return result, err
+				}
+				err = nil
+			} else {
+				deletedKeys = append(deletedKeys, keys...)
 			}
-			return err
 		}
+
+		if deletion.SkipReason != "" {
+			result.Skipped++
+		} else {
+			result.Deleted++
+		}
+		result.Users = append(result.Users, deletion)
 	}
 
+	if dryRun {
+		return result, nil
+	}
 	_, err = db.GetEngine(db.DefaultContext).
 		Where("is_activated = ?", false).
 		Delete(new(EmailAddress))
-	return err
+	return result, err
 }
 
 // UserPath returns the path absolute path of user repositories.
@@ -1545,8 +1787,17 @@ func GetUserByEmail(email string) (*User, error) {
 	return GetUserByEmailContext(db.DefaultContext, email)
 }
 
-// GetUserByEmailContext returns the user object by given e-mail if exists with db context
+// GetUserByEmailContext returns the user object by given e-mail if exists with db context,
+// anonymized users are never found by their former email addresses nor their no-reply address
 func GetUserByEmailContext(ctx context.Context, email string) (*User, error) {
+	user, err := getUserByEmail(ctx, email)
+	if err == nil && user.IsAnonymized() {
+		return nil, ErrUserNotExist{0, email, 0}
+	}
+	return user, err
+}
+
+func getUserByEmail(ctx context.Context, email string) (*User, error) {
 	if len(email) == 0 {
 		return nil, ErrUserNotExist{0, email, 0}
 	}
@@ -1718,13 +1969,19 @@ func SearchUsers(opts *SearchUserOptions) (users []*User, _ int64, _ error) {
 	return users, count, sessQuery.Find(&users)
 }
 
-// GetStarredRepos returns the repos starred by a particular user
-func GetStarredRepos(userID int64, private bool, listOptions db.ListOptions) ([]*Repository, error) {
-	sess := db.GetEngine(db.DefaultContext).Where("star.uid=?", userID).
-		Join("LEFT", "star", "`repository`.id=`star`.repo_id")
-	if !private {
-		sess = sess.And("is_private=?", false)
+// userRepoListCondition returns the condition for the repositories of a list of a user that are visible to the doer
+func userRepoListCondition(cond builder.Cond, doer *User) builder.Cond {
+	if doer == nil || !doer.IsAdmin {
+		cond = cond.And(accessibleRepositoryCondition(doer))
 	}
+	return cond
+}
+
+// GetStarredRepos returns the repos starred by a particular user that are visible to the doer
+func GetStarredRepos(userID int64, doer *User, listOptions db.ListOptions) ([]*Repository, error) {
+	sess := db.GetEngine(db.DefaultContext).
+		Join("INNER", "star", "`repository`.id=`star`.repo_id").
+		Where(userRepoListCondition(builder.Eq{"`star`.uid": userID}, doer))
 
 	if listOptions.Page != 0 {
 		sess = db.SetSessionPagination(sess, &listOptions)
@@ -1737,14 +1994,14 @@ func GetStarredRepos(userID int64, private bool, listOptions db.ListOptions) ([]
 	return repos, sess.Find(&repos)
 }
 
-// GetWatchedRepos returns the repos watched by a particular user
-func GetWatchedRepos(userID int64, private bool, listOptions db.ListOptions) ([]*Repository, int64, error) {
-	sess := db.GetEngine(db.DefaultContext).Where("watch.user_id=?", userID).
-		And("`watch`.mode<>?", RepoWatchModeDont).
-		Join("LEFT", "watch", "`repository`.id=`watch`.repo_id")
-	if !private {
-		sess = sess.And("is_private=?", false)
-	}
+// GetWatchedRepos returns the repos watched by a particular user that are visible to the doer
+func GetWatchedRepos(userID int64, doer *User, listOptions db.ListOptions) ([]*Repository, int64, error) {
+	sess := db.GetEngine(db.DefaultContext).
+		Join("INNER", "watch", "`repository`.id=`watch`.repo_id").
+		Where(userRepoListCondition(builder.And(
+			builder.Eq{"`watch`.user_id": userID},
+			builder.Neq{"`watch`.mode": RepoWatchModeDont},
+		), doer))
 
 	if listOptions.Page != 0 {
 		sess = db.SetSessionPagination(sess, &listOptions)
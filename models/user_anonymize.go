@@ -0,0 +1,138 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/login"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// AnonymizedUserNamePrefix starts the names of the anonymized users, it is followed by their ID
+const AnonymizedUserNamePrefix = "former-user-"
+
+// IsAnonymized returns true if the user has been anonymized, the account can never be used again
+func (u *User) IsAnonymized() bool {
+	return u.AnonymizedUnix > 0
+}
+
+// AnonymizeUser is the alternative to deleting a user which keeps the attribution of its issues, pull requests
+// and comments: the user is renamed to former-user-<id>, its personal data, keys and external logins are removed
+// and it can't sign in anymore. Like deleting, anonymizing is refused if the user owns repositories or belongs
+// to organizations.
+func AnonymizeUser(u *User) (err error) {
+	if u.IsOrganization() {
+		return fmt.Errorf("%s is an organization not a user", u.Name)
+	}
+
+	keys := make([]*PublicKey, 0, 5)
+	if err = db.GetEngine(db.DefaultContext).Where("owner_id = ?", u.ID).Find(&keys); err != nil {
+		return fmt.Errorf("find public keys: %v", err)
+	}
+
+	oldName, oldAvatar := u.Name, u.CustomAvatarRelativePath()
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err = sess.Begin(); err != nil {
+		return err
+	}
+	if err = anonymizeUser(sess, u); err != nil {
+		return err
+	}
+	if err = sess.Commit(); err != nil {
+		return err
+	}
+
+	// Note: There are something just cannot be roll back,
+	//	so just keep error logs of those operations.
+	if err = util.RemoveAll(UserPath(oldName)); err != nil {
+		log.Error("Failed to RemoveAll %s: %v", UserPath(oldName), err)
+	}
+	if len(oldAvatar) > 0 {
+		if err = storage.Avatars.Delete(oldAvatar); err != nil {
+			log.Error("Failed to remove %s: %v", oldAvatar, err)
+		}
+	}
+	return removeAuthorizedKeys(keys)
+}
+
+func anonymizeUser(e db.Engine, u *User) error {
+	count, err := getRepositoryCount(e, u)
+	if err != nil {
+		return fmt.Errorf("GetRepositoryCount: %v", err)
+	} else if count > 0 {
+		return ErrUserOwnRepos{UID: u.ID}
+	}
+	count, err = u.getOrganizationCount(e)
+	if err != nil {
+		return fmt.Errorf("GetOrganizationCount: %v", err)
+	} else if count > 0 {
+		return ErrUserHasOrgs{UID: u.ID}
+	}
+
+	name := fmt.Sprintf("%s%d", AnonymizedUserNamePrefix, u.ID)
+	if exist, err := isUserExist(e, u.ID, name); err != nil {
+		return err
+	} else if exist {
+		return ErrUserAlreadyExist{name}
+	}
+
+	// the old name doesn't redirect to the pseudonym
+	if err = deleteBeans(e,
+		&AccessToken{UID: u.ID},
+		&RepoCloneToken{UID: u.ID},
+		&EmailAddress{UID: u.ID},
+		&UserOpenID{UID: u.ID},
+		&UserRedirect{RedirectUserID: u.ID},
+		&PublicKey{OwnerID: u.ID},
+		&login.TwoFactor{UID: u.ID},
+		&login.U2FRegistration{UserID: u.ID},
+		&login.OAuth2Grant{UserID: u.ID},
+	); err != nil {
+		return fmt.Errorf("deleteBeans: %v", err)
+	}
+
+	keys, err := listGPGKeys(e, u.ID, db.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("ListGPGKeys: %v", err)
+	}
+	for _, key := range keys {
+		if _, err = e.Delete(&GPGKeyImport{KeyID: key.KeyID}); err != nil {
+			return fmt.Errorf("deleteGPGKeyImports: %v", err)
+		}
+	}
+	if _, err = e.Delete(&GPGKey{OwnerID: u.ID}); err != nil {
+		return fmt.Errorf("deleteGPGKeys: %v", err)
+	}
+
+	if err = removeAllAccountLinks(e, u); err != nil {
+		return fmt.Errorf("ExternalLoginUser: %v", err)
+	}
+
+	if u.Rands, err = GetUserSalt(); err != nil {
+		return err
+	}
+	if u.Salt, err = GetUserSalt(); err != nil {
+		return err
+	}
+	u.Name, u.LowerName = name, name
+	u.FullName, u.Email, u.KeepEmailPrivate = "", "", true
+	u.Location, u.Website, u.Description = "", "", ""
+	u.Avatar, u.AvatarEmail, u.UseCustomAvatar = "", "", false
+	u.Passwd, u.MustChangePassword = "", false
+	u.LoginType, u.LoginSource, u.LoginName = login.Plain, 0, ""
+	u.IsActive, u.IsAdmin, u.ProhibitLogin = false, false, true
+	u.AnonymizedUnix = timeutil.TimeStampNow()
+	_, err = e.ID(u.ID).Cols("name", "lower_name", "full_name", "email", "keep_email_private",
+		"location", "website", "description", "avatar", "avatar_email", "use_custom_avatar",
+		"passwd", "must_change_password", "rands", "salt", "login_type", "login_source", "login_name",
+		"is_active", "is_admin", "prohibit_login", "anonymized_unix").Update(u)
+	return err
+}
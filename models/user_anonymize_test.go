@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnonymizeUser(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	err := AnonymizeUser(user)
+	assert.True(t, IsErrUserOwnRepos(err))
+
+	user = db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	assert.NoError(t, AnonymizeUser(user))
+	user = db.AssertExistsAndLoadBean(t, &User{ID: 1, LowerName: "former-user-1", ProhibitLogin: true}).(*User)
+	assert.True(t, user.IsAnonymized())
+	assert.False(t, user.IsActive)
+	assert.Empty(t, user.Email)
+	assert.Empty(t, user.FullName)
+	assert.Empty(t, user.Passwd)
+	db.AssertNotExistsBean(t, &EmailAddress{UID: 1})
+	db.AssertNotExistsBean(t, &AccessToken{UID: 1})
+	db.AssertNotExistsBean(t, &UserRedirect{RedirectUserID: 1})
+	// the issues stay attributed to the pseudonym
+	db.AssertExistsAndLoadBean(t, &Issue{ID: 1, PosterID: 1})
+
+	// the former and no-reply email addresses don't find the user, nor do activation codes
+	_, err = GetUserByEmail("user1@example.com")
+	assert.True(t, IsErrUserNotExist(err))
+	_, err = GetUserByEmail("former-user-1@" + setting.Service.NoReplyAddress)
+	assert.True(t, IsErrUserNotExist(err))
+	assert.Nil(t, VerifyUserActiveCode(user.GenerateEmailActivateCode(user.Email)))
+
+	// the pseudonyms are reserved
+	assert.True(t, IsErrNamePatternNotAllowed(IsUsableUsername("former-user-5")))
+}
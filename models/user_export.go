@@ -0,0 +1,61 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"code.gitea.io/gitea/models/db"
+
+	"xorm.io/builder"
+)
+
+// FindUserExportIssues returns the issues and pull requests a user has authored
+// in the repositories the user can still access, the oldest first
+func FindUserExportIssues(u *User) (IssueList, error) {
+	issues := make(IssueList, 0, 10)
+	return issues, db.GetEngine(db.DefaultContext).
+		Where(builder.Eq{"poster_id": u.ID}).
+		And(builder.In("repo_id", AccessibleRepoIDsQuery(u))).
+		Asc("id").
+		Find(&issues)
+}
+
+// FindUserExportComments returns the comments a user has written on issues and pull requests
+// in the repositories the user can still access, the oldest first
+func FindUserExportComments(u *User) (CommentList, error) {
+	comments := make(CommentList, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).
+		Join("INNER", "issue", "issue.id = comment.issue_id").
+		Where(builder.Eq{"comment.poster_id": u.ID}).
+		And(builder.In("comment.type", CommentTypeComment, CommentTypeCode)).
+		And(builder.In("issue.repo_id", AccessibleRepoIDsQuery(u))).
+		Asc("comment.id").
+		Find(&comments); err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		c.Poster = u
+	}
+	return comments, nil
+}
+
+// FindUserExportActions returns the activity performed by a user in the repositories the user can still access,
+// the newest first
+func FindUserExportActions(u *User) ([]*Action, error) {
+	cond, err := activityQueryCondition(GetFeedsOptions{
+		RequestedUser:   u,
+		Actor:           u,
+		IncludePrivate:  true,
+		OnlyPerformedBy: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	actions := make([]*Action, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).Where(cond).Desc("created_unix").Find(&actions); err != nil {
+		return nil, err
+	}
+	return actions, ActionList(actions).LoadAttributes()
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindUserExportIssues(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// issue 6 is in a private repository user 1 can't access
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	issues, err := FindUserExportIssues(user)
+	assert.NoError(t, err)
+	ids := make([]int64, 0, len(issues))
+	for _, issue := range issues {
+		ids = append(ids, issue.ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3, 11}, ids)
+}
+
+func TestFindUserExportComments(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+	comments, err := FindUserExportComments(user)
+	assert.NoError(t, err)
+	if assert.Len(t, comments, 1) {
+		assert.EqualValues(t, 3, comments[0].ID)
+		assert.Equal(t, user, comments[0].Poster)
+	}
+}
@@ -7,13 +7,18 @@ package models
 import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // Follow represents relations of user and his/her followers.
+// FollowID is declared first, so that the composite follow_user index on (follow_id, user_id) covers
+// listing the followers of a user. Listing the users a user follows is covered by the follow unique
+// index on (user_id, follow_id) of existing tables and by the user_id index of new ones.
 type Follow struct {
 	ID          int64              `xorm:"pk autoincr"`
-	UserID      int64              `xorm:"UNIQUE(follow)"`
-	FollowID    int64              `xorm:"UNIQUE(follow)"`
+	FollowID    int64              `xorm:"UNIQUE(follow) INDEX(follow_user)"`
+	UserID      int64              `xorm:"UNIQUE(follow) INDEX(follow_user) INDEX"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 }
 
@@ -27,6 +32,35 @@ func IsFollowing(userID, followID int64) bool {
 	return has
 }
 
+// GetFollowRelations returns which of the users are followed by the doer and which of them follow the doer,
+// both are looked up with a single query.
+func GetFollowRelations(doerID int64, userIDs []int64) (isFollowing, followsDoer map[int64]bool, err error) {
+	isFollowing = make(map[int64]bool, len(userIDs))
+	followsDoer = make(map[int64]bool, len(userIDs))
+	if doerID <= 0 || len(userIDs) == 0 {
+		return isFollowing, followsDoer, nil
+	}
+
+	follows := make([]*Follow, 0, 2*len(userIDs))
+	if err := db.GetEngine(db.DefaultContext).
+		Where(builder.Or(
+			builder.Eq{"user_id": doerID}.And(builder.In("follow_id", userIDs)),
+			builder.Eq{"follow_id": doerID}.And(builder.In("user_id", userIDs)),
+		)).
+		Find(&follows); err != nil {
+		return nil, nil, err
+	}
+	for _, follow := range follows {
+		if follow.UserID == doerID {
+			isFollowing[follow.FollowID] = true
+		}
+		if follow.FollowID == doerID {
+			followsDoer[follow.UserID] = true
+		}
+	}
+	return isFollowing, followsDoer, nil
+}
+
 // FollowUser marks someone be another's follower.
 func FollowUser(userID, followID int64) (err error) {
 	if userID == followID || IsFollowing(userID, followID) {
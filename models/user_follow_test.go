@@ -48,3 +48,39 @@ func TestUnfollowUser(t *testing.T) {
 
 	CheckConsistencyFor(t, &User{})
 }
+
+func TestGetFollowRelations(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// user 2 follows user 8, users 4 and 8 follow user 2
+	isFollowing, followsDoer, err := GetFollowRelations(2, []int64{4, 5, 8})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]bool{8: true}, isFollowing)
+	assert.Equal(t, map[int64]bool{4: true, 8: true}, followsDoer)
+
+	isFollowing, followsDoer, err = GetFollowRelations(0, []int64{4, 5, 8})
+	assert.NoError(t, err)
+	assert.Empty(t, isFollowing)
+	assert.Empty(t, followsDoer)
+}
+
+func TestGetFollowersAndFollowing(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+
+	// the latest followers come first
+	followers, err := user.GetFollowers(db.ListOptions{Page: 1, PageSize: 1})
+	assert.NoError(t, err)
+	if assert.Len(t, followers, 1) {
+		assert.EqualValues(t, 8, followers[0].ID)
+	}
+	followers, err = user.GetFollowers(db.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, followers, user.NumFollowers)
+
+	following, err := user.GetFollowing(db.ListOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, following, user.NumFollowing) {
+		assert.EqualValues(t, 8, following[0].ID)
+	}
+}
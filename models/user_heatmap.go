@@ -50,7 +50,8 @@ func getUserHeatmapData(user *User, team *Team, doer *User) ([]*UserHeatmapData,
 		Actor:          doer,
 		IncludePrivate: true, // don't filter by private, as we already filter by repo access
 		IncludeDeleted: true,
-		// * Heatmaps for individual users only include actions that the user themself did.
+		// * Heatmaps for individual users only include actions that the user themself did, like
+		//   authoring commits pushed by someone else, which is not counted again for the pusher.
 		// * For organizations actions by all users that were made in owned
 		//   repositories are counted.
 		OnlyPerformedBy: !user.IsOrganization(),
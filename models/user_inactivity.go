@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package models
+
+import (
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// LastActivityUnix returns the last time the user signed in, was created or was deactivated as inactive.
+// Users reactivated by an admin are inactive since their deactivation.
+func (u *User) LastActivityUnix() timeutil.TimeStamp {
+	last := u.CreatedUnix
+	if u.LastLoginUnix > last {
+		last = u.LastLoginUnix
+	}
+	if u.InactivityDeactivatedUnix > last {
+		last = u.InactivityDeactivatedUnix
+	}
+	return last
+}
+
+// inactivityPolicyCond is the condition of the users the deactivation of inactive users applies to
+func inactivityPolicyCond() builder.Cond {
+	return builder.Eq{
+		"type":               UserTypeIndividual,
+		"is_active":          true,
+		"is_admin":           false,
+		"is_service_account": false,
+	}
+}
+
+// unixBeforeCond matches the times before the given one, including the times which were never set
+func unixBeforeCond(col string, before timeutil.TimeStamp) builder.Cond {
+	return builder.Or(builder.Lt{col: before}, builder.IsNull{col})
+}
+
+// FindUsersInactiveSince returns the users who can sign in and have not been active since the given time
+func FindUsersInactiveSince(since timeutil.TimeStamp) ([]*User, error) {
+	users := make([]*User, 0, 10)
+	return users, db.GetEngine(db.DefaultContext).
+		Where(inactivityPolicyCond()).
+		And(builder.Eq{"prohibit_login": false}).
+		And(unixBeforeCond("created_unix", since)).
+		And(unixBeforeCond("last_login_unix", since)).
+		And(builder.Lt{"inactivity_deactivated_unix": since}).
+		Asc("id").
+		Find(&users)
+}
+
+// FindUsersDeactivatedAsInactiveBefore returns the users deactivated as inactive before the given time
+// who have not been reactivated since
+func FindUsersDeactivatedAsInactiveBefore(before timeutil.TimeStamp) ([]*User, error) {
+	users := make([]*User, 0, 10)
+	return users, db.GetEngine(db.DefaultContext).
+		Where(inactivityPolicyCond()).
+		And(builder.Eq{"prohibit_login": true}).
+		And(builder.Gt{"inactivity_deactivated_unix": 0}).
+		And(builder.Lt{"inactivity_deactivated_unix": before}).
+		And(builder.Or(builder.IsNull{"last_login_unix"}, builder.Expr("last_login_unix < inactivity_deactivated_unix"))).
+		Asc("id").
+		Find(&users)
+}
+
+// IsSoleOwnerOfOrgs checks if the user is the only owner of any organization
+func (u *User) IsSoleOwnerOfOrgs() (bool, error) {
+	return db.GetEngine(db.DefaultContext).
+		Table("team").
+		Join("INNER", "team_user", "team_user.team_id = team.id").
+		Where(builder.Eq{
+			"team_user.uid":    u.ID,
+			"team.lower_name":  strings.ToLower(ownerTeamName),
+			"team.num_members": 1,
+		}).
+		Exist()
+}
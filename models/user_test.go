@@ -5,6 +5,7 @@
 package models
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -210,6 +211,40 @@ func TestDeleteUser(t *testing.T) {
 	assert.Error(t, DeleteUser(org))
 }
 
+func TestDeleteInactiveUsers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// user 5 is kept because it owns a repository
+	_, err := db.GetEngine(db.DefaultContext).ID(5).Cols("is_active").Update(&User{IsActive: false})
+	assert.NoError(t, err)
+
+	result, err := DeleteInactiveUsers(context.Background(), 0, true)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Deleted)
+	assert.Equal(t, 1, result.Skipped)
+	if assert.Len(t, result.Users, 2) {
+		assert.EqualValues(t, 5, result.Users[0].User.ID)
+		assert.Equal(t, InactiveUserSkipOwnsRepos, result.Users[0].SkipReason)
+		assert.EqualValues(t, 9, result.Users[1].User.ID)
+		assert.Empty(t, result.Users[1].SkipReason)
+	}
+	db.AssertExistsAndLoadBean(t, &User{ID: 9})
+
+	result, err = DeleteInactiveUsers(context.Background(), 0, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Deleted)
+	assert.Equal(t, 1, result.Skipped)
+	db.AssertExistsAndLoadBean(t, &User{ID: 5})
+	db.AssertNotExistsBean(t, &User{ID: 9})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result, err = DeleteInactiveUsers(ctx, 0, false)
+	assert.True(t, IsErrCancelled(err))
+	assert.Empty(t, result.Users)
+	db.AssertExistsAndLoadBean(t, &User{ID: 5})
+}
+
 func TestEmailNotificationPreferences(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -391,6 +426,36 @@ func TestCreateUser_Issue5882(t *testing.T) {
 	}
 }
 
+func TestCreateUsers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	passwd := ".//.;1;;//.,-=_"
+	users := []*User{
+		{Name: "bulk1", Email: "bulk1@example.com", Passwd: passwd},
+		{Name: "user2", Email: "bulk2@example.com", Passwd: passwd},
+		{Name: "bulk3", Email: "user2@example.com", Passwd: passwd},
+		{Name: "bulk1", Email: "bulk4@example.com", Passwd: passwd},
+		{Name: "bulk5", Email: "invalid\r\n", Passwd: passwd},
+		{Name: "bulk6", Email: "bulk6@example.com", Passwd: passwd},
+	}
+	overwriteDefaults := make([]*CreateUserOverwriteOptions, len(users))
+	overwriteDefaults[5] = &CreateUserOverwriteOptions{Visibility: structs.VisibleTypePrivate}
+
+	errs := CreateUsers(users, overwriteDefaults)
+	assert.NoError(t, errs[0])
+	assert.True(t, IsErrUserAlreadyExist(errs[1]))
+	assert.True(t, IsErrEmailAlreadyUsed(errs[2]))
+	assert.True(t, IsErrUserAlreadyExist(errs[3]), "duplicates within a request are conflicts")
+	assert.True(t, IsErrEmailInvalid(errs[4]))
+	assert.NoError(t, errs[5])
+
+	db.AssertExistsAndLoadBean(t, &User{ID: users[0].ID, Name: "bulk1"})
+	db.AssertExistsAndLoadBean(t, &EmailAddress{UID: users[0].ID, Email: "bulk1@example.com", IsPrimary: true})
+	db.AssertExistsAndLoadBean(t, &User{ID: users[5].ID, Name: "bulk6", Visibility: structs.VisibleTypePrivate})
+	db.AssertNotExistsBean(t, &User{Name: "bulk3"})
+	db.AssertNotExistsBean(t, &User{Email: "bulk4@example.com"})
+}
+
 func TestGetUserIDsByNames(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 
@@ -430,6 +495,13 @@ func TestAddLdapSSHPublicKeys(t *testing.T) {
 	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
 	s := &login.Source{ID: 1}
 
+	// the key policy is tested by TestAddLdapSSHPublicKeysPolicy, the DSA keys would be skipped by default
+	oldValue := setting.SSH.MinimumKeySizeCheck
+	setting.SSH.MinimumKeySizeCheck = false
+	defer func() {
+		setting.SSH.MinimumKeySizeCheck = oldValue
+	}()
+
 	testCases := []struct {
 		keyString   string
 		number      int
@@ -492,6 +564,31 @@ ssh-dss AAAAB3NzaC1kc3MAAACBAOChCC7lf6Uo9n7BmZ6M8St19PZf4Tn59NriyboW2x/DZuYAz3ib
 	}
 }
 
+func TestAddLdapSSHPublicKeysPolicy(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	s := &login.Source{ID: 30}
+
+	oldCheck, oldBuiltin := setting.SSH.MinimumKeySizeCheck, setting.SSH.StartBuiltinServer
+	setting.SSH.MinimumKeySizeCheck = true
+	setting.SSH.StartBuiltinServer = true
+	defer func() {
+		setting.SSH.MinimumKeySizeCheck, setting.SSH.StartBuiltinServer = oldCheck, oldBuiltin
+	}()
+
+	rsaKey := "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABgQC4cn+iXnA4KvcQYSV88vGn0Yi91vG47t1P7okprVmhNTkipNRIHWr6WdCO4VDr/cvsRkuVJAsLO2enwjGWWueOO6BodiBgyAOZ/5t5nJNMCNuLGT5UIo/RI1b0WRQwxEZTRjt6mFNw6lH14wRd8ulsr9toSWBPMOGWoYs1PDeDL0JuTjL+tr1SZi/EyxCngpYszKdXllJEHyI79KQgeD0Vt3pTrkbNVTOEcCNqZePSVmUH8X8Vhugz3bnE0/iE9Pb5fkWO9c4AnM1FgI/8Bvp27Fw2ShryIXuR6kKvUqhVMTuOSDHwu6A8jLE5Owt3GAYugDpDYuwTVNGrHLXKpPzrGGPE/jPmaLCMZcsdkec95dYeU3zKODEm8UQZFhmJmDeWVJ36nGrGZHL4J5aTTaeFUJmmXDaJYiJ+K2/ioKgXqnXvltu0A9R8/LGy4nrTJRr4JMLuJFoUXvGm1gXQ70w2LSpk6yl71RNC0hCtsBe8BP8IhYCM0EP5jh7eCMQZNvM="
+	dsaKey := "ssh-dss AAAAB3NzaC1kc3MAAACBAOChCC7lf6Uo9n7BmZ6M8St19PZf4Tn59NriyboW2x/DZuYAz3ibZ2OkQ3S0SqDIa0HXSEJ1zaExQdmbO+Ux/wsytWZmCczWOVsaszBZSl90q8UnWlSH6P+/YA+RWJm5SFtuV9PtGIhyZgoNuz5kBQ7K139wuQsecdKktISwTakzAAAAFQCzKsO2JhNKlL+wwwLGOcLffoAmkwAAAIBpK7/3xvduajLBD/9vASqBQIHrgK2J+wiQnIb/Wzy0UsVmvfn8A+udRbBo+csM8xrSnlnlJnjkJS3qiM5g+eTwsLIV1IdKPEwmwB+VcP53Cw6lSyWyJcvhFb0N6s08NZysLzvj0N+ZC/FnhKTLzIyMtkHf/IrPCwlM+pV/M/96YgAAAIEAqQcGn9CKgzgPaguIZooTAOQdvBLMI5y0bQjOW6734XOpqQGf/Kra90wpoasLKZjSYKNPjE+FRUOrStLrxcNs4BeVKhy2PYTRnybfYVk1/dmKgH6P1YSRONsGKvTsH6c5IyCRG0ncCgYeF8tXppyd642982daopE7zQ/NPAnJfag="
+
+	// DSA keys are not allowed by default and are skipped
+	assert.True(t, AddPublicKeysBySource(user, s, []string{rsaKey + " nocomment\n" + dsaKey + " nocomment"}))
+	keys, err := ListPublicKeysBySource(user.ID, s.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.Equal(t, rsaKey, keys[0].Content)
+	}
+}
+
 func TestUpdateUser(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
@@ -511,3 +608,82 @@ func TestUpdateUser(t *testing.T) {
 	user.Email = "no mail@mail.org"
 	assert.Error(t, UpdateUser(user))
 }
+
+func TestIsUsableUsername(t *testing.T) {
+	assert.NoError(t, IsUsableUsername("usable"))
+	assert.True(t, IsErrNameReserved(IsUsableUsername("admin")))
+
+	defer func(maxLength int) { setting.Service.MaxUserNameLength = maxLength }(setting.Service.MaxUserNameLength)
+	setting.Service.MaxUserNameLength = 5
+	assert.NoError(t, IsUsableUsername("abcde"))
+	err := IsUsableUsername("abcdef")
+	assert.True(t, IsErrNameTooLong(err))
+	assert.Equal(t, "user name", err.(ErrNameTooLong).Kind)
+
+	// confusable and mixed-script names are rejected by the character check
+	assert.True(t, IsErrNameCharsNotAllowed(IsUsableUsername("\u0430dmin")))
+	assert.True(t, IsErrNameCharsNotAllowed(IsUsableUsername("us\u0435r")))
+}
+
+func TestNormalizeName(t *testing.T) {
+	assert.Equal(t, "caf\u00e9", NormalizeName("cafe\u0301"))
+	assert.Equal(t, "user2", NormalizeName("user2"))
+}
+
+func TestGetStarredAndWatchedRepos(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// repository 40 is a public repository of a private organization
+	assert.NoError(t, StarRepo(2, 40, true))
+	for _, repoID := range []int64{2, 4, 40} {
+		assert.NoError(t, WatchRepo(2, repoID, true))
+	}
+
+	admin := db.AssertExistsAndLoadBean(t, &User{ID: 1}).(*User)
+	user2 := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	user4 := db.AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	user5 := db.AssertExistsAndLoadBean(t, &User{ID: 5}).(*User)
+
+	repoIDs := func(repos []*Repository) []int64 {
+		ids := make([]int64, 0, len(repos))
+		for _, repo := range repos {
+			ids = append(ids, repo.ID)
+		}
+		return ids
+	}
+
+	for _, tc := range []struct {
+		doer     *User
+		expected []int64
+	}{
+		{nil, []int64{4}},
+		{user5, []int64{4}},
+		// user 4 is a collaborator of repository 40
+		{user4, []int64{4, 40}},
+		{user2, []int64{2, 4}},
+		{admin, []int64{2, 4, 40}},
+	} {
+		starred, err := GetStarredRepos(2, tc.doer, db.ListOptions{})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, tc.expected, repoIDs(starred))
+
+		watched, total, err := GetWatchedRepos(2, tc.doer, db.ListOptions{})
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(tc.expected), total)
+		assert.ElementsMatch(t, tc.expected, repoIDs(watched))
+	}
+}
+
+func TestChangeUserNameCaseOnly(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := db.AssertExistsAndLoadBean(t, &User{ID: 2}).(*User)
+	assert.NoError(t, ChangeUserName(user, "User2"))
+
+	repo := db.AssertExistsAndLoadBean(t, &Repository{ID: 1}).(*Repository)
+	assert.Equal(t, "User2", repo.OwnerName)
+	db.AssertNotExistsBean(t, &UserRedirect{LowerName: "user2"})
+
+	other := db.AssertExistsAndLoadBean(t, &User{ID: 4}).(*User)
+	assert.True(t, IsErrUserAlreadyExist(ChangeUserName(other, "USER2")))
+}
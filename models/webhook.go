@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
+	"github.com/gobwas/glob"
 	gouuid "github.com/google/uuid"
 	"xorm.io/builder"
 )
@@ -80,6 +81,25 @@ func IsValidHookContentType(name string) bool {
 	return ok
 }
 
+// HookSignatureAlgorithm is the HMAC algorithm used for the X-Gitea-Signature and X-Gogs-Signature headers
+type HookSignatureAlgorithm string
+
+const (
+	// HookSignatureSHA256 signs the payload with HMAC-SHA256
+	HookSignatureSHA256 HookSignatureAlgorithm = "sha256"
+	// HookSignatureSHA1 signs the payload with HMAC-SHA1, for receivers which only support the legacy signature
+	HookSignatureSHA1 HookSignatureAlgorithm = "sha1"
+)
+
+// IsValidHookSignatureAlgorithm returns true if given name is a valid hook signature algorithm.
+func IsValidHookSignatureAlgorithm(name string) bool {
+	switch HookSignatureAlgorithm(name) {
+	case HookSignatureSHA256, HookSignatureSHA1:
+		return true
+	}
+	return false
+}
+
 // HookEvents is a set of web hook events
 type HookEvents struct {
 	Create               bool `json:"create"`
@@ -100,18 +120,73 @@ type HookEvents struct {
 	PullRequestSync      bool `json:"pull_request_sync"`
 	Repository           bool `json:"repository"`
 	Release              bool `json:"release"`
+	Digest               bool `json:"digest"`
+	DeployKey            bool `json:"deploy_key"`
+	PublicKey            bool `json:"public_key"`
 }
 
 // HookEvent represents events that will delivery hook.
 type HookEvent struct {
-	PushOnly       bool   `json:"push_only"`
-	SendEverything bool   `json:"send_everything"`
-	ChooseEvents   bool   `json:"choose_events"`
-	BranchFilter   string `json:"branch_filter"`
+	PushOnly       bool `json:"push_only"`
+	SendEverything bool `json:"send_everything"`
+	ChooseEvents   bool `json:"choose_events"`
+
+	// BranchFilters are the globs matching the branches of the events, see MatchBranchFilters
+	BranchFilters []string `json:"branch_filters"`
+
+	// DigestWindowDays is the number of days summarized by each digest, DefaultDigestWindowDays if not set
+	DigestWindowDays int  `json:"digest_window_days"`
+	DigestSendEmpty  bool `json:"digest_send_empty"`
 
 	HookEvents `json:"events"`
 }
 
+// BranchFilterExcludePrefix starts the branch filters excluding the branches they match
+const BranchFilterExcludePrefix = "!"
+
+// ParseBranchFilters splits the branch filters separated by spaces
+func ParseBranchFilters(s string) []string {
+	return strings.Fields(s)
+}
+
+// MatchBranchFilters returns true if the events of the branch pass the filters: the branch must match one of
+// the include globs, or there are none, and none of the exclude globs, which start with "!".
+// No filters, or the single "*", let all the branches pass.
+func MatchBranchFilters(filters []string, branch string) bool {
+	hasInclude, included := false, false
+	for _, filter := range filters {
+		exclude := strings.HasPrefix(filter, BranchFilterExcludePrefix)
+		g, err := glob.Compile(strings.TrimPrefix(filter, BranchFilterExcludePrefix))
+		if err != nil {
+			// should not really happen as the branch filters are validated
+			log.Error("MatchBranchFilters: invalid filter %q: %v", filter, err)
+			return false
+		}
+		if exclude {
+			if g.Match(branch) {
+				return false
+			}
+			continue
+		}
+		hasInclude = true
+		included = included || g.Match(branch)
+	}
+	return !hasInclude || included
+}
+
+// MatchBranch returns true if the events of the branch pass the branch filters of the webhook
+func (e *HookEvent) MatchBranch(branch string) bool {
+	return MatchBranchFilters(e.BranchFilters, branch)
+}
+
+// BranchFiltersString returns the branch filters separated by spaces, as they are edited
+func (e *HookEvent) BranchFiltersString() string {
+	return strings.Join(e.BranchFilters, " ")
+}
+
+// DefaultDigestWindowDays is the number of days summarized by a digest if the webhook doesn't set it
+const DefaultDigestWindowDays = 7
+
 // HookType is the type of a webhook
 type HookType = string
 
@@ -144,17 +219,30 @@ type Webhook struct {
 	ID              int64 `xorm:"pk autoincr"`
 	RepoID          int64 `xorm:"INDEX"` // An ID of 0 indicates either a default or system webhook
 	OrgID           int64 `xorm:"INDEX"`
+	TeamID          int64 `xorm:"INDEX"` // Only set for the review request webhook of a team
 	IsSystemWebhook bool
 	URL             string `xorm:"url TEXT"`
 	HTTPMethod      string `xorm:"http_method"`
 	ContentType     HookContentType
 	Secret          string `xorm:"TEXT"`
 	Events          string `xorm:"TEXT"`
-	*HookEvent      `xorm:"-"`
-	IsActive        bool       `xorm:"INDEX"`
-	Type            HookType   `xorm:"VARCHAR(16) 'type'"`
-	Meta            string     `xorm:"TEXT"` // store hook-specific attributes
-	LastStatus      HookStatus // Last delivery status
+
+	// SignatureAlgorithm selects the signature sent in X-Gitea-Signature and X-Gogs-Signature,
+	// X-Hub-Signature (SHA1) and X-Hub-Signature-256 (SHA256) are always both sent
+	SignatureAlgorithm HookSignatureAlgorithm `xorm:"VARCHAR(16) NOT NULL DEFAULT 'sha256'"`
+
+	// PreviousSecret is still used to sign deliveries until PreviousSecretExpiresUnix after a rotation
+	PreviousSecret            string `xorm:"TEXT"`
+	PreviousSecretExpiresUnix timeutil.TimeStamp
+	SecretRotatedUnix         timeutil.TimeStamp
+
+	*HookEvent `xorm:"-"`
+	IsActive   bool       `xorm:"INDEX"`
+	Type       HookType   `xorm:"VARCHAR(16) 'type'"`
+	Meta       string     `xorm:"TEXT"` // store hook-specific attributes
+	LastStatus HookStatus // Last delivery status
+
+	LastDigestUnix timeutil.TimeStamp
 
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
@@ -305,6 +393,34 @@ func (w *Webhook) HasRepositoryEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Repository)
 }
 
+// HasDigestEvent returns if hook enabled digest event.
+// Digests are periodic summaries, so they have to be chosen explicitly and are not part of everything.
+func (w *Webhook) HasDigestEvent() bool {
+	return w.ChooseEvents && w.HookEvents.Digest
+}
+
+// HasDeployKeyEvent returns if hook enabled deploy key event.
+func (w *Webhook) HasDeployKeyEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.DeployKey)
+}
+
+// HasPublicKeyEvent returns if hook enabled public key event.
+// The SSH keys of users are not related to repositories, so only system webhooks receive the event.
+func (w *Webhook) HasPublicKeyEvent() bool {
+	return w.IsSystemWebhook && (w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.PublicKey))
+}
+
+// DigestWindow returns the period summarized by each digest of the webhook
+func (w *Webhook) DigestWindow() time.Duration {
+	days := w.DigestWindowDays
+	if days <= 0 {
+		days = DefaultDigestWindowDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
 // EventCheckers returns event checkers
 func (w *Webhook) EventCheckers() []struct {
 	Has  func() bool
@@ -334,6 +450,9 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasPullRequestSyncEvent, HookEventPullRequestSync},
 		{w.HasRepositoryEvent, HookEventRepository},
 		{w.HasReleaseEvent, HookEventRelease},
+		{w.HasDigestEvent, HookEventDigest},
+		{w.HasDeployKeyEvent, HookEventDeployKey},
+		{w.HasPublicKeyEvent, HookEventPublicKey},
 	}
 }
 
@@ -395,6 +514,13 @@ func GetWebhookByOrgID(orgID, id int64) (*Webhook, error) {
 	})
 }
 
+// GetWebhookByTeamID returns the review request webhook of a team.
+func GetWebhookByTeamID(teamID int64) (*Webhook, error) {
+	return getWebhook(&Webhook{
+		TeamID: teamID,
+	})
+}
+
 // ListWebhookOptions are options to filter webhooks on ListWebhooksByOpts
 type ListWebhookOptions struct {
 	db.ListOptions
@@ -450,7 +576,7 @@ func GetDefaultWebhooks() ([]*Webhook, error) {
 func getDefaultWebhooks(e db.Engine) ([]*Webhook, error) {
 	webhooks := make([]*Webhook, 0, 5)
 	return webhooks, e.
-		Where("repo_id=? AND org_id=? AND is_system_webhook=?", 0, 0, false).
+		Where("repo_id=? AND org_id=? AND team_id=? AND is_system_webhook=?", 0, 0, 0, false).
 		Find(&webhooks)
 }
 
@@ -458,7 +584,7 @@ func getDefaultWebhooks(e db.Engine) ([]*Webhook, error) {
 func GetSystemOrDefaultWebhook(id int64) (*Webhook, error) {
 	webhook := &Webhook{ID: id}
 	has, err := db.GetEngine(db.DefaultContext).
-		Where("repo_id=? AND org_id=?", 0, 0).
+		Where("repo_id=? AND org_id=? AND team_id=?", 0, 0, 0).
 		Get(webhook)
 	if err != nil {
 		return nil, err
@@ -476,7 +602,7 @@ func GetSystemWebhooks() ([]*Webhook, error) {
 func getSystemWebhooks(e db.Engine) ([]*Webhook, error) {
 	webhooks := make([]*Webhook, 0, 5)
 	return webhooks, e.
-		Where("repo_id=? AND org_id=? AND is_system_webhook=?", 0, 0, true).
+		Where("repo_id=? AND org_id=? AND team_id=? AND is_system_webhook=?", 0, 0, 0, true).
 		Find(&webhooks)
 }
 
@@ -486,6 +612,66 @@ func UpdateWebhook(w *Webhook) error {
 	return err
 }
 
+// webhookSecretLength is the length of secrets generated by RotateWebhookSecret
+const webhookSecretLength = 40
+
+// RotateWebhookSecret replaces the secret of the webhook with a new random one and returns it.
+// During the grace period deliveries are signed with both the new and the previous secret.
+func RotateWebhookSecret(w *Webhook, gracePeriod time.Duration) (string, error) {
+	secret, err := util.RandomString(webhookSecretLength)
+	if err != nil {
+		return "", err
+	}
+
+	now := timeutil.TimeStampNow()
+	w.PreviousSecret = ""
+	w.PreviousSecretExpiresUnix = 0
+	if gracePeriod > 0 && len(w.Secret) > 0 {
+		w.PreviousSecret = w.Secret
+		w.PreviousSecretExpiresUnix = now.AddDuration(gracePeriod)
+	}
+	w.Secret = secret
+	w.SecretRotatedUnix = now
+
+	_, err = db.GetEngine(db.DefaultContext).ID(w.ID).
+		Cols("secret", "previous_secret", "previous_secret_expires_unix", "secret_rotated_unix").
+		Update(w)
+	return secret, err
+}
+
+// ActivePreviousSecret returns the previous secret of the webhook if its grace period has not expired yet
+func (w *Webhook) ActivePreviousSecret() string {
+	if len(w.PreviousSecret) == 0 || w.PreviousSecretExpiresUnix <= timeutil.TimeStampNow() {
+		return ""
+	}
+	return w.PreviousSecret
+}
+
+// GetDigestWebhooks returns the active repository and organization webhooks subscribed to the digest event
+func GetDigestWebhooks() ([]*Webhook, error) {
+	webhooks := make([]*Webhook, 0, 10)
+	if err := db.GetEngine(db.DefaultContext).
+		Where(builder.Eq{"is_active": true}.And(builder.Gt{"repo_id": 0}.Or(builder.Gt{"org_id": 0}))).
+		And(builder.Like{"events", `"digest":true`}).
+		Find(&webhooks); err != nil {
+		return nil, err
+	}
+
+	digestHooks := webhooks[:0]
+	for _, w := range webhooks {
+		if w.HasDigestEvent() {
+			digestHooks = append(digestHooks, w)
+		}
+	}
+	return digestHooks, nil
+}
+
+// UpdateWebhookLastDigest updates the time of the last digest of webhook.
+func UpdateWebhookLastDigest(w *Webhook) error {
+	_, err := db.GetEngine(db.DefaultContext).ID(w.ID).Cols("last_digest_unix").Update(w)
+	return err
+}
+
 // UpdateWebhookLastStatus updates last status of webhook.
 func UpdateWebhookLastStatus(w *Webhook) error {
 	_, err := db.GetEngine(db.DefaultContext).ID(w.ID).Cols("last_status").Update(w)
@@ -528,6 +714,30 @@ func DeleteWebhookByOrgID(orgID, id int64) error {
 	})
 }
 
+// DeleteWebhookByTeamID deletes webhook of team by given ID.
+func DeleteWebhookByTeamID(teamID, id int64) error {
+	return deleteWebhook(&Webhook{
+		ID:     id,
+		TeamID: teamID,
+	})
+}
+
+func deleteWebhooksByTeamID(e db.Engine, teamID int64) error {
+	ws := make([]*Webhook, 0, 1)
+	if err := e.Where("team_id=?", teamID).Find(&ws); err != nil {
+		return err
+	}
+	for _, w := range ws {
+		if _, err := e.ID(w.ID).Delete(new(Webhook)); err != nil {
+			return err
+		}
+		if _, err := e.Delete(&HookTask{HookID: w.ID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // DeleteDefaultSystemWebhook deletes an admin-configured default or system webhook (where Org and Repo ID both 0)
 func DeleteDefaultSystemWebhook(id int64) error {
 	sess := db.NewSession(db.DefaultContext)
@@ -537,7 +747,7 @@ func DeleteDefaultSystemWebhook(id int64) error {
 	}
 
 	count, err := sess.
-		Where("repo_id=? AND org_id=?", 0, 0).
+		Where("repo_id=? AND org_id=? AND team_id=?", 0, 0, 0).
 		Delete(&Webhook{ID: id})
 	if err != nil {
 		return err
@@ -601,6 +811,9 @@ const (
 	HookEventPullRequestSync           HookEventType = "pull_request_sync"
 	HookEventRepository                HookEventType = "repository"
 	HookEventRelease                   HookEventType = "release"
+	HookEventDigest                    HookEventType = "digest"
+	HookEventDeployKey                 HookEventType = "deploy_key"
+	HookEventPublicKey                 HookEventType = "public_key"
 )
 
 // Event returns the HookEventType as an event string
@@ -631,6 +844,12 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventDigest:
+		return "digest"
+	case HookEventDeployKey:
+		return "deploy_key"
+	case HookEventPublicKey:
+		return "public_key"
 	}
 	return ""
 }
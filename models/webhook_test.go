@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/json"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
@@ -28,6 +29,13 @@ func TestIsValidHookContentType(t *testing.T) {
 	assert.False(t, IsValidHookContentType("invalid"))
 }
 
+func TestIsValidHookSignatureAlgorithm(t *testing.T) {
+	assert.True(t, IsValidHookSignatureAlgorithm("sha256"))
+	assert.True(t, IsValidHookSignatureAlgorithm("sha1"))
+	assert.False(t, IsValidHookSignatureAlgorithm(""))
+	assert.False(t, IsValidHookSignatureAlgorithm("md5"))
+}
+
 func TestWebhook_History(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	webhook := db.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
@@ -70,7 +78,7 @@ func TestWebhook_EventsArray(t *testing.T) {
 		"issues", "issue_assign", "issue_label", "issue_milestone", "issue_comment",
 		"pull_request", "pull_request_assign", "pull_request_label", "pull_request_milestone",
 		"pull_request_comment", "pull_request_review_approved", "pull_request_review_rejected",
-		"pull_request_review_comment", "pull_request_sync", "repository", "release",
+		"pull_request_review_comment", "pull_request_sync", "repository", "release", "deploy_key",
 	},
 		(&Webhook{
 			HookEvent: &HookEvent{SendEverything: true},
@@ -84,6 +92,29 @@ func TestWebhook_EventsArray(t *testing.T) {
 	)
 }
 
+func TestMatchBranchFilters(t *testing.T) {
+	for _, c := range []struct {
+		filters string
+		branch  string
+		match   bool
+	}{
+		{"", "master", true},
+		{"*", "feature/7791", true},
+		{"{master,feature*}", "feature/7791", true},
+		{"{master,feature*}", "fix_weird_bug", false},
+		{"releases/* !releases/*-rc", "releases/1.16", true},
+		{"releases/* !releases/*-rc", "releases/1.16-rc", false},
+		{"releases/* !releases/*-rc", "master", false},
+		{"master releases/*", "releases/1.16", true},
+		{"!releases/*-rc", "master", true},
+		{"!releases/*-rc", "releases/1.16-rc", false},
+		{"!master !develop", "develop", false},
+		{"[a-", "master", false},
+	} {
+		assert.Equal(t, c.match, MatchBranchFilters(ParseBranchFilters(c.filters), c.branch), "%q %q", c.filters, c.branch)
+	}
+}
+
 func TestCreateWebhook(t *testing.T) {
 	hook := &Webhook{
 		RepoID:      3,
@@ -190,6 +221,110 @@ func TestDeleteWebhookByOrgID(t *testing.T) {
 	assert.True(t, IsErrWebhookNotExist(err))
 }
 
+func TestTeamWebhook(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	_, err := GetWebhookByTeamID(1)
+	assert.True(t, IsErrWebhookNotExist(err))
+
+	hook := &Webhook{
+		TeamID:      1,
+		URL:         "www.example.com/unit_test",
+		ContentType: ContentTypeJSON,
+		Events:      `{"push_only":false,"send_everything":false,"choose_events":true,"events":{"pull_request":true}}`,
+	}
+	assert.NoError(t, CreateWebhook(hook))
+
+	loaded, err := GetWebhookByTeamID(1)
+	assert.NoError(t, err)
+	assert.Equal(t, hook.ID, loaded.ID)
+
+	// team hooks are neither default nor system hooks
+	hooks, err := GetDefaultWebhooks()
+	assert.NoError(t, err)
+	for _, h := range hooks {
+		assert.NotEqual(t, hook.ID, h.ID)
+	}
+
+	assert.True(t, IsErrWebhookNotExist(DeleteWebhookByTeamID(2, hook.ID)))
+	assert.NoError(t, DeleteWebhookByTeamID(1, hook.ID))
+	db.AssertNotExistsBean(t, &Webhook{ID: hook.ID})
+}
+
+func TestRotateWebhookSecret(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	hook := db.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+
+	// without an existing secret there is nothing to keep during the grace period
+	secret, err := RotateWebhookSecret(hook, time.Hour)
+	assert.NoError(t, err)
+	assert.Len(t, secret, webhookSecretLength)
+	assert.Empty(t, hook.ActivePreviousSecret())
+
+	newSecret, err := RotateWebhookSecret(hook, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret, newSecret)
+
+	hook = db.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	assert.Equal(t, newSecret, hook.Secret)
+	assert.Equal(t, secret, hook.ActivePreviousSecret())
+	assert.NotZero(t, hook.SecretRotatedUnix)
+
+	// without a grace period the previous secret is dropped immediately
+	_, err = RotateWebhookSecret(hook, 0)
+	assert.NoError(t, err)
+	hook = db.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	assert.Empty(t, hook.PreviousSecret)
+	assert.Empty(t, hook.ActivePreviousSecret())
+
+	// an expired previous secret is no longer used
+	hook.PreviousSecret = "expired"
+	hook.PreviousSecretExpiresUnix = timeutil.TimeStampNow().Add(-1)
+	assert.Empty(t, hook.ActivePreviousSecret())
+}
+
+func TestGetDigestWebhooks(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	hooks, err := GetDigestWebhooks()
+	assert.NoError(t, err)
+	assert.Empty(t, hooks)
+
+	hook := db.AssertExistsAndLoadBean(t, &Webhook{ID: 1}).(*Webhook)
+	hook.HookEvent = &HookEvent{
+		ChooseEvents:     true,
+		DigestWindowDays: 14,
+		HookEvents:       HookEvents{Digest: true},
+	}
+	assert.NoError(t, hook.UpdateEvent())
+	assert.NoError(t, UpdateWebhook(hook))
+
+	hooks, err = GetDigestWebhooks()
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 1) {
+		assert.EqualValues(t, 1, hooks[0].ID)
+		assert.Equal(t, 14*24*time.Hour, hooks[0].DigestWindow())
+	}
+
+	// a hook sending everything does not opt into digests
+	hook.HookEvent.SendEverything = true
+	hook.HookEvent.ChooseEvents = false
+	assert.False(t, hook.HasDigestEvent())
+}
+
+func TestWebhook_HasKeyEvents(t *testing.T) {
+	hook := &Webhook{HookEvent: &HookEvent{SendEverything: true}}
+	assert.True(t, hook.HasDeployKeyEvent())
+	// the SSH keys of users are only sent to system webhooks
+	assert.False(t, hook.HasPublicKeyEvent())
+
+	hook.IsSystemWebhook = true
+	assert.True(t, hook.HasPublicKeyEvent())
+
+	hook.HookEvent = &HookEvent{ChooseEvents: true, HookEvents: HookEvents{PublicKey: true}}
+	assert.False(t, hook.HasDeployKeyEvent())
+	assert.True(t, hook.HasPublicKeyEvent())
+}
+
 func TestHookTasks(t *testing.T) {
 	assert.NoError(t, db.PrepareTestDatabase())
 	hookTasks, err := HookTasks(1, 1)
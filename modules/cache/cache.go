@@ -16,7 +16,8 @@ import (
 )
 
 var (
-	conn mc.Cache
+	conn           mc.Cache
+	lastCommitConn mc.Cache
 )
 
 func newCache(cacheConfig setting.Cache) (mc.Cache, error) {
@@ -37,6 +38,16 @@ func NewContext() error {
 		}
 	}
 
+	if lastCommitConn == nil && setting.CacheService.LastCommit.Enabled && setting.CacheService.LastCommit.Size > 0 {
+		if lastCommitConn, err = newCache(setting.Cache{
+			Adapter:  "twoqueue",
+			Conn:     strconv.Itoa(setting.CacheService.LastCommit.Size),
+			Interval: setting.CacheService.Interval,
+		}); err != nil {
+			return err
+		}
+	}
+
 	return err
 }
 
@@ -45,6 +56,15 @@ func GetCache() mc.Cache {
 	return conn
 }
 
+// GetLastCommitCache returns the cache of the last commits of tree entries,
+// a dedicated cache of limited size if one is configured
+func GetLastCommitCache() mc.Cache {
+	if lastCommitConn != nil {
+		return lastCommitConn
+	}
+	return conn
+}
+
 // GetString returns the key value from cache with callback when no key exists in cache
 func GetString(key string, getFunc func() (string, error)) (string, error) {
 	if conn == nil || setting.CacheService.TTL == 0 {
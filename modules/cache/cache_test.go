@@ -40,6 +40,28 @@ func TestGetCache(t *testing.T) {
 	assert.NotNil(t, GetCache())
 }
 
+func TestGetLastCommitCache(t *testing.T) {
+	createTestCache()
+	assert.Equal(t, GetCache(), GetLastCommitCache())
+
+	oldLastCommit := setting.CacheService.LastCommit
+	defer func() {
+		setting.CacheService.LastCommit = oldLastCommit
+		lastCommitConn = nil
+	}()
+	setting.CacheService.LastCommit.Enabled = true
+	setting.CacheService.LastCommit.Size = 2
+	assert.NoError(t, NewContext())
+
+	lastCommitCache := GetLastCommitCache()
+	assert.NotEqual(t, GetCache(), lastCommitCache)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, lastCommitCache.Put(fmt.Sprintf("key%d", i), "value", 60))
+	}
+	assert.Nil(t, lastCommitCache.Get("key0"))
+	assert.Equal(t, "value", lastCommitCache.Get("key2"))
+}
+
 func TestGetString(t *testing.T) {
 	createTestCache()
 
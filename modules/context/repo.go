@@ -9,6 +9,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"path"
 	"strings"
@@ -16,8 +17,8 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -80,11 +81,29 @@ func (r *Repository) CanCreateBranch() bool {
 	return r.Permission.CanWrite(models.UnitTypeCode) && r.Repository.CanCreateBranch()
 }
 
-// RepoMustNotBeArchived checks if a repo is archived
+// RepoMustNotBeArchived checks if a repo is archived. Pages are reported as
+// not found, changes are rejected with 403 Forbidden.
 func RepoMustNotBeArchived() func(ctx *Context) {
 	return func(ctx *Context) {
-		if ctx.Repo.Repository.IsArchived {
-			ctx.NotFound("IsArchived", fmt.Errorf(ctx.Tr("repo.archive.title")))
+		if err := ctx.Repo.Repository.CheckNotArchived(); err != nil {
+			if ctx.Req.Method == http.MethodGet || ctx.Req.Method == http.MethodHead {
+				ctx.NotFound("IsArchived", fmt.Errorf(ctx.Tr("repo.archive.title")))
+				return
+			}
+			ctx.Error(http.StatusForbidden, err.Error())
+		}
+	}
+}
+
+// RepoMustNotBeArchivedForWrite rejects changes to an archived repo with
+// 403 Forbidden while still allowing its pages to be viewed.
+func RepoMustNotBeArchivedForWrite() func(ctx *Context) {
+	return func(ctx *Context) {
+		if ctx.Req.Method == http.MethodGet || ctx.Req.Method == http.MethodHead {
+			return
+		}
+		if err := ctx.Repo.Repository.CheckNotArchived(); err != nil {
+			ctx.Error(http.StatusForbidden, err.Error())
 		}
 	}
 }
@@ -310,14 +329,22 @@ func EarlyResponseForGoGetMeta(ctx *Context) {
 
 // RedirectToRepo redirect to a differently-named repository
 func RedirectToRepo(ctx *Context, redirectRepoID int64) {
-	ownerName := ctx.Params(":username")
-	previousRepoName := ctx.Params(":reponame")
-
 	repo, err := models.GetRepositoryByID(redirectRepoID)
 	if err != nil {
 		ctx.ServerError("GetRepositoryByID", err)
 		return
 	}
+	redirectToRepo(ctx, repo, http.StatusFound)
+}
+
+// RedirectToRenamedOwnerRepo permanently redirects to the repository found by the old name of its owner
+func RedirectToRenamedOwnerRepo(ctx *Context, repo *models.Repository) {
+	redirectToRepo(ctx, repo, http.StatusMovedPermanently)
+}
+
+func redirectToRepo(ctx *Context, repo *models.Repository, status int) {
+	ownerName := ctx.Params(":username")
+	previousRepoName := ctx.Params(":reponame")
 
 	redirectPath := strings.Replace(
 		ctx.Req.URL.Path,
@@ -328,7 +355,7 @@ func RedirectToRepo(ctx *Context, redirectRepoID int64) {
 	if ctx.Req.URL.RawQuery != "" {
 		redirectPath += "?" + ctx.Req.URL.RawQuery
 	}
-	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath))
+	ctx.Redirect(path.Join(setting.AppSubURL, redirectPath), status)
 }
 
 func repoAssignment(ctx *Context, repo *models.Repository) {
@@ -415,6 +442,13 @@ func RepoAssignment(ctx *Context) (cancel context.CancelFunc) {
 		owner, err = models.GetUserByName(userName)
 		if err != nil {
 			if models.IsErrUserNotExist(err) {
+				if repo, redirected, err := models.LookupRepositoryByOwnerAndName(userName, repoName); err == nil && redirected {
+					RedirectToRenamedOwnerRepo(ctx, repo)
+					return
+				} else if err != nil && !models.IsErrRepoNotExist(err) {
+					ctx.ServerError("LookupRepositoryByOwnerAndName", err)
+					return
+				}
 				if ctx.FormString("go-get") == "1" {
 					EarlyResponseForGoGetMeta(ctx)
 					return
@@ -498,6 +532,10 @@ func RepoAssignment(ctx *Context) (cancel context.CancelFunc) {
 	ctx.Data["ExposeAnonSSH"] = setting.SSH.ExposeAnonymous
 	ctx.Data["DisableHTTP"] = setting.Repository.DisableHTTPGit
 	ctx.Data["RepoSearchEnabled"] = setting.Indexer.RepoIndexerEnabled
+	if err := repo.LoadGitSettings(); err != nil {
+		ctx.ServerError("LoadGitSettings", err)
+		return
+	}
 	ctx.Data["CloneLink"] = repo.CloneLink()
 	ctx.Data["WikiCloneLink"] = repo.WikiCloneLink()
 
@@ -695,7 +733,7 @@ func getRefName(ctx *Context, pathType RepoRefType) string {
 		}
 		// For legacy and API support only full commit sha
 		parts := strings.Split(path, "/")
-		if len(parts) > 0 && len(parts[0]) == 40 {
+		if len(parts) > 0 && git.IsFullCommitID(parts[0]) {
 			ctx.Repo.TreePath = strings.Join(parts[1:], "/")
 			return parts[0]
 		}
@@ -911,12 +949,8 @@ func UnitTypes() func(ctx *Context) {
 // IssueTemplatesFromDefaultBranch checks for issue templates in the repo's default branch
 func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 	var issueTemplates []api.IssueTemplate
-	if ctx.Repo.Commit == nil {
-		var err error
-		ctx.Repo.Commit, err = ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
-		if err != nil {
-			return issueTemplates
-		}
+	if !ctx.loadDefaultBranchCommit() {
+		return issueTemplates
 	}
 
 	for _, dirName := range IssueTemplateDirCandidates {
@@ -929,39 +963,16 @@ func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 			return issueTemplates
 		}
 		for _, entry := range entries {
-			if strings.HasSuffix(entry.Name(), ".md") {
-				if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
-					log.Debug("Issue template is too large: %s", entry.Name())
-					continue
-				}
-				r, err := entry.Blob().DataAsync()
-				if err != nil {
-					log.Debug("DataAsync: %v", err)
-					continue
-				}
-				closed := false
-				defer func() {
-					if !closed {
-						_ = r.Close()
-					}
-				}()
-				data, err := io.ReadAll(r)
-				if err != nil {
-					log.Debug("ReadAll: %v", err)
-					continue
-				}
-				_ = r.Close()
-				var it api.IssueTemplate
-				content, err := markdown.ExtractMetadata(string(data), &it)
-				if err != nil {
-					log.Debug("ExtractMetadata: %v", err)
-					continue
-				}
-				it.Content = content
-				it.FileName = entry.Name()
-				if it.Valid() {
-					issueTemplates = append(issueTemplates, it)
-				}
+			if !strings.HasSuffix(entry.Name(), ".md") && !issue_template.IsFormFile(entry.Name()) {
+				continue
+			}
+			it, err := issueTemplateFromEntry(entry)
+			if err != nil {
+				log.Debug("issue template %s: %v", entry.Name(), err)
+				continue
+			}
+			if it.Valid() {
+				issueTemplates = append(issueTemplates, *it)
 			}
 		}
 		if len(issueTemplates) > 0 {
@@ -970,3 +981,56 @@ func (ctx *Context) IssueTemplatesFromDefaultBranch() []api.IssueTemplate {
 	}
 	return issueTemplates
 }
+
+// IssueTemplateFromDefaultBranch returns the issue template with the given file name from the repo's
+// default branch. The extension may be omitted. It returns nil if there is no such template.
+func (ctx *Context) IssueTemplateFromDefaultBranch(name string) (*api.IssueTemplate, error) {
+	if name == "" || strings.Contains(name, "/") || !ctx.loadDefaultBranchCommit() {
+		return nil, nil
+	}
+
+	candidates := []string{name}
+	if path.Ext(name) == "" {
+		candidates = []string{name + ".yaml", name + ".yml", name + ".md"}
+	}
+	for _, dirName := range IssueTemplateDirCandidates {
+		for _, candidate := range candidates {
+			entry, err := ctx.Repo.Commit.GetTreeEntryByPath(path.Join(dirName, candidate))
+			if err != nil {
+				if git.IsErrNotExist(err) {
+					continue
+				}
+				return nil, err
+			}
+			return issueTemplateFromEntry(entry)
+		}
+	}
+	return nil, nil
+}
+
+func (ctx *Context) loadDefaultBranchCommit() bool {
+	if ctx.Repo.Commit == nil {
+		var err error
+		ctx.Repo.Commit, err = ctx.Repo.GitRepo.GetBranchCommit(ctx.Repo.Repository.DefaultBranch)
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func issueTemplateFromEntry(entry *git.TreeEntry) (*api.IssueTemplate, error) {
+	if entry.Blob().Size() >= setting.UI.MaxDisplayFileSize {
+		return nil, fmt.Errorf("issue template is too large")
+	}
+	r, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return issue_template.Unmarshal(entry.Name(), data)
+}
@@ -13,7 +13,9 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/login"
+	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/structs"
 	api "code.gitea.io/gitea/modules/structs"
@@ -125,7 +127,10 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 		BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
 		BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+		AutoUpdateOnMerge:             bp.AutoUpdateOnMerge,
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
+		DismissOnlyOnContentChange:    bp.DismissOnlyOnContentChange,
+		RequireCodeOwnerApproval:      bp.RequireCodeOwnerApproval,
 		RequireSignedCommits:          bp.RequireSignedCommits,
 		ProtectedFilePatterns:         bp.ProtectedFilePatterns,
 		UnprotectedFilePatterns:       bp.UnprotectedFilePatterns,
@@ -136,7 +141,7 @@ func ToBranchProtection(bp *models.ProtectedBranch) *api.BranchProtection {
 
 // ToTag convert a git.Tag to an api.Tag
 func ToTag(repo *models.Repository, t *git.Tag) *api.Tag {
-	return &api.Tag{
+	tag := &api.Tag{
 		Name:       t.Name,
 		Message:    strings.TrimSpace(t.Message),
 		ID:         t.ID.String(),
@@ -144,18 +149,54 @@ func ToTag(repo *models.Repository, t *git.Tag) *api.Tag {
 		ZipballURL: util.URLJoin(repo.HTMLURL(), "archive", t.Name+".zip"),
 		TarballURL: util.URLJoin(repo.HTMLURL(), "archive", t.Name+".tar.gz"),
 	}
+	if git.ObjectType(t.Type) == git.ObjectTag {
+		tag.Tagger = ToCommitUser(t.Tagger)
+		tag.Verification = ToTagVerification(repo, t)
+	}
+	return tag
 }
 
 // ToVerification convert a git.Commit.Signature to an api.PayloadCommitVerification
 func ToVerification(c *git.Commit) *api.PayloadCommitVerification {
-	verif := models.ParseCommitWithSignature(c)
+	return toPayloadCommitVerification(models.ParseCommitWithSignature(c), c.Signature)
+}
+
+// ToTagVerification convert the signature of an annotated git.Tag to an api.PayloadCommitVerification,
+// the trust status of the signer follows the trust model of the repository.
+// The results are cached by the SHA of the tag.
+func ToTagVerification(repo *models.Repository, t *git.Tag) *api.PayloadCommitVerification {
+	key := fmt.Sprintf("tag-verification-%d-%s-%s", repo.ID, repo.GetTrustModel(), t.ID)
+	data, err := cache.GetString(key, func() (string, error) {
+		verif := models.ParseTagWithSignature(t)
+		if err := models.CalculateTrustStatus(verif, repo, nil); err != nil {
+			return "", err
+		}
+		tagVerification := toPayloadCommitVerification(verif, t.Signature)
+		tagVerification.TrustStatus = verif.TrustStatus
+		data, err := json.Marshal(tagVerification)
+		return string(data), err
+	})
+	if err != nil {
+		log.Error("ToTagVerification [repo_id: %d, tag: %s]: %v", repo.ID, t.ID, err)
+		return nil
+	}
+
+	tagVerification := new(api.PayloadCommitVerification)
+	if err := json.Unmarshal([]byte(data), tagVerification); err != nil {
+		log.Error("Unmarshal tag verification [repo_id: %d, tag: %s]: %v", repo.ID, t.ID, err)
+		return nil
+	}
+	return tagVerification
+}
+
+func toPayloadCommitVerification(verif *models.CommitVerification, sig *git.CommitGPGSignature) *api.PayloadCommitVerification {
 	commitVerification := &api.PayloadCommitVerification{
 		Verified: verif.Verified,
 		Reason:   verif.Reason,
 	}
-	if c.Signature != nil {
-		commitVerification.Signature = c.Signature.Signature
-		commitVerification.Payload = c.Signature.Payload
+	if sig != nil {
+		commitVerification.Signature = sig.Signature
+		commitVerification.Payload = sig.Payload
 	}
 	if verif.SigningUser != nil {
 		commitVerification.Signer = &structs.PayloadUser{
@@ -217,6 +258,37 @@ func ToGPGKey(key *models.GPGKey) *api.GPGKey {
 	}
 }
 
+// ToGPGKeyStatus convert models.GPGKey to api.GPGKeyStatus
+func ToGPGKeyStatus(key *models.GPGKey, verifiableEmails []string) *api.GPGKeyStatus {
+	signingKeyIDs := make([]string, 0, len(key.SubsKey)+1)
+	if key.CanSign {
+		signingKeyIDs = append(signingKeyIDs, key.KeyID)
+	}
+	for _, k := range key.SubsKey {
+		if k.CanSign {
+			signingKeyIDs = append(signingKeyIDs, k.KeyID)
+		}
+	}
+
+	status := &api.GPGKeyStatus{
+		ID:               key.ID,
+		KeyID:            key.KeyID,
+		SigningKeyIDs:    signingKeyIDs,
+		Expired:          key.IsExpired(),
+		Verified:         key.Verified,
+		VerifiableEmails: verifiableEmails,
+	}
+	if key.HasExpiry() {
+		expires := key.ExpiredUnix.AsTime()
+		status.Expires = &expires
+		if !status.Expired {
+			days := int64(time.Until(expires) / (24 * time.Hour))
+			status.ExpiresInDays = &days
+		}
+	}
+	return status
+}
+
 // ToGPGKeyEmail convert models.EmailAddress to api.GPGKeyEmail
 func ToGPGKeyEmail(email *models.EmailAddress) *api.GPGKeyEmail {
 	return &api.GPGKeyEmail{
@@ -238,17 +310,30 @@ func ToHook(repoLink string, w *models.Webhook) *api.Hook {
 		config["icon_url"] = s.IconURL
 		config["color"] = s.Color
 	}
-
-	return &api.Hook{
-		ID:      w.ID,
-		Type:    string(w.Type),
-		URL:     fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
-		Active:  w.IsActive,
-		Config:  config,
-		Events:  w.EventsArray(),
-		Updated: w.UpdatedUnix.AsTime(),
-		Created: w.CreatedUnix.AsTime(),
+	if w.Type == models.GITEA || w.Type == models.GOGS {
+		config["signature_algorithm"] = string(w.SignatureAlgorithm)
 	}
+
+	hook := &api.Hook{
+		ID:            w.ID,
+		Type:          string(w.Type),
+		URL:           fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		Active:        w.IsActive,
+		Config:        config,
+		Events:        w.EventsArray(),
+		Updated:       w.UpdatedUnix.AsTime(),
+		Created:       w.CreatedUnix.AsTime(),
+		BranchFilters: w.BranchFilters,
+	}
+	if w.SecretRotatedUnix > 0 {
+		rotated := w.SecretRotatedUnix.AsTime()
+		hook.SecretRotated = &rotated
+	}
+	if w.HasDigestEvent() {
+		hook.DigestWindowDays = int(w.DigestWindow().Hours() / 24)
+		hook.DigestSendEmpty = w.DigestSendEmpty
+	}
+	return hook
 }
 
 // ToGitHook convert git.Hook to api.GitHook
@@ -263,14 +348,16 @@ func ToGitHook(h *git.Hook) *api.GitHook {
 // ToDeployKey convert models.DeployKey to api.DeployKey
 func ToDeployKey(apiLink string, key *models.DeployKey) *api.DeployKey {
 	return &api.DeployKey{
-		ID:          key.ID,
-		KeyID:       key.KeyID,
-		Key:         key.Content,
-		Fingerprint: key.Fingerprint,
-		URL:         fmt.Sprintf("%s%d", apiLink, key.ID),
-		Title:       key.Name,
-		Created:     key.CreatedUnix.AsTime(),
-		ReadOnly:    key.Mode == models.AccessModeRead, // All deploy keys are read-only.
+		ID:            key.ID,
+		KeyID:         key.KeyID,
+		Key:           key.Content,
+		Fingerprint:   key.Fingerprint,
+		URL:           fmt.Sprintf("%s%d", apiLink, key.ID),
+		Title:         key.Name,
+		Created:       key.CreatedUnix.AsTime(),
+		ReadOnly:      key.Mode == models.AccessModeRead, // All deploy keys are read-only.
+		Statuses:      key.CanWriteStatuses,
+		StatusesToken: key.StatusesToken,
 	}
 }
 
@@ -286,6 +373,7 @@ func ToOrganization(org *models.User) *api.Organization {
 		Location:                  org.Location,
 		Visibility:                org.Visibility.String(),
 		RepoAdminChangeTeamAccess: org.RepoAdminChangeTeamAccess,
+		AllowPublicRepos:          !org.RequirePrivateRepos,
 	}
 }
 
@@ -303,6 +391,8 @@ func ToTeam(team *models.Team) *api.Team {
 		CanCreateOrgRepo:        team.CanCreateOrgRepo,
 		Permission:              team.Authorize.String(),
 		Units:                   team.GetUnitNames(),
+		UnitsMap:                team.GetUnitsMap(),
+		ReviewNotification:      team.ReviewNotification,
 	}
 }
 
@@ -339,6 +429,22 @@ func ToTopicResponse(topic *models.Topic) *api.TopicResponse {
 	}
 }
 
+// ToAccessToken convert from models.AccessToken to api.AccessToken, the token itself is not included
+func ToAccessToken(t *models.AccessToken) *api.AccessToken {
+	apiToken := &api.AccessToken{
+		ID:             t.ID,
+		Name:           t.Name,
+		TokenLastEight: t.TokenLastEight,
+		Created:        t.CreatedUnix.AsTime(),
+		LastUsedIP:     t.LastUsedIP,
+	}
+	if t.HasUsed {
+		lastUsed := t.LastUsedUnix.AsTime()
+		apiToken.LastUsed = &lastUsed
+	}
+	return apiToken
+}
+
 // ToOAuth2Application convert from login.OAuth2Application to api.OAuth2Application
 func ToOAuth2Application(app *login.OAuth2Application) *api.OAuth2Application {
 	return &api.OAuth2Application{
@@ -362,3 +468,126 @@ func ToLFSLock(l *models.LFSLock) *api.LFSLock {
 		},
 	}
 }
+
+// ToPushRules convert a PushRule to api.PushRules
+func ToPushRules(rule *models.PushRule) *api.PushRules {
+	forbiddenPaths := rule.ForbiddenPaths
+	if forbiddenPaths == nil {
+		forbiddenPaths = []string{}
+	}
+	forbiddenCommitterEmails := rule.ForbiddenCommitterEmails
+	if forbiddenCommitterEmails == nil {
+		forbiddenCommitterEmails = []string{}
+	}
+	return &api.PushRules{
+		ForbiddenPaths:           forbiddenPaths,
+		MaxFileSize:              rule.MaxFileSize,
+		MaxPathLength:            rule.MaxPathLength,
+		ForbiddenCommitterEmails: forbiddenCommitterEmails,
+		ScanSecrets:              rule.ScanSecrets,
+	}
+}
+
+// ToStaleBranchCleanup convert a StaleBranchCleanup to api.StaleBranchCleanup
+func ToStaleBranchCleanup(cleanup *models.StaleBranchCleanup) *api.StaleBranchCleanup {
+	lastRunBranches := cleanup.LastRunBranches
+	if lastRunBranches == nil {
+		lastRunBranches = []string{}
+	}
+	apiCleanup := &api.StaleBranchCleanup{
+		Enabled:         cleanup.Enabled,
+		Merged:          cleanup.Merged,
+		OlderThanDays:   cleanup.OlderThanDays,
+		DryRun:          cleanup.DryRun,
+		LastRunDryRun:   cleanup.LastRunDryRun,
+		LastRunBranches: lastRunBranches,
+	}
+	if cleanup.LastRunUnix > 0 {
+		lastRun := cleanup.LastRunUnix.AsTime()
+		apiCleanup.LastRun = &lastRun
+	}
+	return apiCleanup
+}
+
+// ToRepoGitSettings convert a RepoGitSettings to api.RepoGitSettings
+func ToRepoGitSettings(s *models.RepoGitSettings) *api.RepoGitSettings {
+	return &api.RepoGitSettings{
+		DisableDumbHTTP:    s.IsDumbHTTPDisabled(),
+		DefaultCloneFilter: s.CloneFilter(),
+		FullCloneMaxSize:   s.FullCloneMaxBytes() / 1024 / 1024,
+	}
+}
+
+// ToRepoTrafficPeriods convert RepoTraffic to api.RepoTrafficPeriod and sums them up
+func ToRepoTrafficPeriods(traffic []*models.RepoTraffic) (periods []*api.RepoTrafficPeriod, count, uniques int64) {
+	periods = make([]*api.RepoTrafficPeriod, len(traffic))
+	for i, t := range traffic {
+		periods[i] = &api.RepoTrafficPeriod{
+			Timestamp: t.StartUnix.AsTime().UTC(),
+			Count:     t.Count,
+			Uniques:   t.Uniques,
+		}
+		count += t.Count
+		uniques += t.Uniques
+	}
+	return periods, count, uniques
+}
+
+// ToRepoMetadata convert a RepoMetadata to api.RepoMetadata
+func ToRepoMetadata(m *models.RepoMetadata, doer *models.User) *api.RepoMetadata {
+	return &api.RepoMetadata{
+		Key:       m.Key,
+		Value:     m.Value,
+		ETag:      m.ETag(),
+		UpdatedBy: ToUser(m.UpdatedBy, doer),
+		Updated:   m.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToRepoBackup convert a RepoBackup to api.RepoBackup
+func ToRepoBackup(b *models.RepoBackup) *api.RepoBackup {
+	result := &api.RepoBackup{
+		ID:        b.ID,
+		RepoID:    b.RepoID,
+		Status:    b.Status.String(),
+		Path:      b.Path,
+		Attempts:  b.Attempts,
+		LastError: b.LastError,
+		Size:      b.Size,
+		Duration:  b.Duration,
+		Created:   b.CreatedUnix.AsTime(),
+		Updated:   b.UpdatedUnix.AsTime(),
+	}
+	if b.Repo != nil {
+		result.RepoFullName = b.Repo.FullName()
+	}
+	if b.Status == models.RepoBackupPending {
+		next := b.NextAttemptUnix.AsTime()
+		result.NextAttempt = &next
+	}
+	return result
+}
+
+// ToNotice convert a Notice to api.Notice
+func ToNotice(n *models.Notice) *api.Notice {
+	return &api.Notice{
+		ID:          n.ID,
+		Type:        n.Type.String(),
+		Description: n.Description,
+		Count:       n.Count,
+		Created:     n.CreatedUnix.AsTime(),
+		LastSeen:    n.LastSeenUnix.AsTime(),
+	}
+}
+
+// ToSavedReply convert a SavedReply to api.SavedReply
+func ToSavedReply(reply *models.SavedReply) *api.SavedReply {
+	return &api.SavedReply{
+		ID:       reply.ID,
+		Title:    reply.Title,
+		Body:     reply.Body,
+		Position: reply.Position,
+		Created:  reply.CreatedUnix.AsTime(),
+		Updated:  reply.UpdatedUnix.AsTime(),
+	}
+}
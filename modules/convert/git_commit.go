@@ -69,8 +69,8 @@ func ToPayloadCommit(repo *models.Repository, c *git.Commit) *api.PayloadCommit
 	}
 }
 
-// ToCommit convert a git.Commit to api.Commit
-func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]*models.User) (*api.Commit, error) {
+// ToCommit convert a git.Commit to api.Commit, the files affected by the commit are only listed with stat
+func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]*models.User, stat bool) (*api.Commit, error) {
 
 	var apiAuthor, apiCommitter *api.User
 
@@ -132,16 +132,19 @@ func ToCommit(repo *models.Repository, commit *git.Commit, userCache map[string]
 	}
 
 	// Retrieve files affected by the commit
-	fileStatus, err := git.GetCommitFileStatus(repo.RepoPath(), commit.ID.String())
-	if err != nil {
-		return nil, err
-	}
-	affectedFileList := make([]*api.CommitAffectedFiles, 0, len(fileStatus.Added)+len(fileStatus.Removed)+len(fileStatus.Modified))
-	for _, files := range [][]string{fileStatus.Added, fileStatus.Removed, fileStatus.Modified} {
-		for _, filename := range files {
-			affectedFileList = append(affectedFileList, &api.CommitAffectedFiles{
-				Filename: filename,
-			})
+	var affectedFileList []*api.CommitAffectedFiles
+	if stat {
+		fileStatus, err := git.GetCommitFileStatus(repo.RepoPath(), commit.ID.String())
+		if err != nil {
+			return nil, err
+		}
+		affectedFileList = make([]*api.CommitAffectedFiles, 0, len(fileStatus.Added)+len(fileStatus.Removed)+len(fileStatus.Modified))
+		for _, files := range [][]string{fileStatus.Added, fileStatus.Removed, fileStatus.Modified} {
+			for _, filename := range files {
+				affectedFileList = append(affectedFileList, &api.CommitAffectedFiles{
+					Filename: filename,
+				})
+			}
 		}
 	}
 
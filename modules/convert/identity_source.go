@@ -0,0 +1,130 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/login"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/auth/source/ldap"
+	"code.gitea.io/gitea/services/auth/source/oauth2"
+	"code.gitea.io/gitea/services/auth/source/pam"
+	"code.gitea.io/gitea/services/auth/source/smtp"
+)
+
+// IdentitySourceTypes maps the types of identity sources of the API to the login types
+var IdentitySourceTypes = map[string]login.Type{
+	api.IdentitySourceLDAP:   login.LDAP,
+	api.IdentitySourceDLDAP:  login.DLDAP,
+	api.IdentitySourceSMTP:   login.SMTP,
+	api.IdentitySourcePAM:    login.PAM,
+	api.IdentitySourceOAuth2: login.OAuth2,
+	api.IdentitySourceSSPI:   login.SSPI,
+}
+
+// LDAPSecurityProtocols maps the LDAP security protocols of the API to the ones of LDAP sources
+var LDAPSecurityProtocols = map[string]ldap.SecurityProtocol{
+	"unencrypted": ldap.SecurityProtocolUnencrypted,
+	"ldaps":       ldap.SecurityProtocolLDAPS,
+	"starttls":    ldap.SecurityProtocolStartTLS,
+}
+
+// ToIdentitySource converts login.Source to api.IdentitySource, secrets are left out
+func ToIdentitySource(source *login.Source) *api.IdentitySource {
+	result := &api.IdentitySource{
+		ID:            source.ID,
+		Name:          source.Name,
+		IsActive:      source.IsActive,
+		IsSyncEnabled: source.IsSyncEnabled,
+		Created:       source.CreatedUnix.AsTime(),
+		Updated:       source.UpdatedUnix.AsTime(),
+	}
+	for name, typ := range IdentitySourceTypes {
+		if typ == source.Type {
+			result.Type = name
+			break
+		}
+	}
+
+	switch cfg := source.Cfg.(type) {
+	case *ldap.Source:
+		result.LDAP = toIdentitySourceLDAPConfig(cfg)
+	case *smtp.Source:
+		result.SMTP = &api.IdentitySourceSMTPConfig{
+			Auth:           cfg.Auth,
+			Host:           cfg.Host,
+			Port:           cfg.Port,
+			AllowedDomains: cfg.AllowedDomains,
+			ForceSMTPS:     cfg.ForceSMTPS,
+			SkipVerify:     cfg.SkipVerify,
+			HeloHostname:   cfg.HeloHostname,
+			DisableHelo:    cfg.DisableHelo,
+			SkipLocalTwoFA: cfg.SkipLocalTwoFA,
+		}
+	case *pam.Source:
+		result.PAM = &api.IdentitySourcePAMConfig{
+			ServiceName:    cfg.ServiceName,
+			EmailDomain:    cfg.EmailDomain,
+			SkipLocalTwoFA: cfg.SkipLocalTwoFA,
+		}
+	case *oauth2.Source:
+		result.OAuth2 = &api.IdentitySourceOAuth2Config{
+			Provider:                      cfg.Provider,
+			ClientID:                      cfg.ClientID,
+			OpenIDConnectAutoDiscoveryURL: cfg.OpenIDConnectAutoDiscoveryURL,
+			IconURL:                       cfg.IconURL,
+			SkipLocalTwoFA:                cfg.SkipLocalTwoFA,
+		}
+		if cfg.CustomURLMapping != nil {
+			result.OAuth2.CustomURLMapping = &api.IdentitySourceOAuth2URLs{
+				AuthURL:    cfg.CustomURLMapping.AuthURL,
+				TokenURL:   cfg.CustomURLMapping.TokenURL,
+				ProfileURL: cfg.CustomURLMapping.ProfileURL,
+				EmailURL:   cfg.CustomURLMapping.EmailURL,
+				Tenant:     cfg.CustomURLMapping.Tenant,
+			}
+		}
+	}
+	return result
+}
+
+func toIdentitySourceLDAPConfig(cfg *ldap.Source) *api.IdentitySourceLDAPConfig {
+	result := &api.IdentitySourceLDAPConfig{
+		Host:                  cfg.Host,
+		Port:                  cfg.Port,
+		SkipVerify:            cfg.SkipVerify,
+		BindDN:                cfg.BindDN,
+		UserBase:              cfg.UserBase,
+		UserDN:                cfg.UserDN,
+		AttributeUsername:     cfg.AttributeUsername,
+		AttributeName:         cfg.AttributeName,
+		AttributeSurname:      cfg.AttributeSurname,
+		AttributeMail:         cfg.AttributeMail,
+		AttributeSSHPublicKey: cfg.AttributeSSHPublicKey,
+		AttributeAvatar:       cfg.AttributeAvatar,
+		AttributesInBind:      cfg.AttributesInBind,
+		SearchPageSize:        int(cfg.SearchPageSize),
+		Filter:                cfg.Filter,
+		AdminFilter:           cfg.AdminFilter,
+		RestrictedFilter:      cfg.RestrictedFilter,
+		GroupsEnabled:         cfg.GroupsEnabled,
+		GroupDN:               cfg.GroupDN,
+		GroupFilter:           cfg.GroupFilter,
+		GroupMemberUID:        cfg.GroupMemberUID,
+		UserUID:               cfg.UserUID,
+		AllowDeactivateAll:    cfg.AllowDeactivateAll,
+		SkipLocalTwoFA:        cfg.SkipLocalTwoFA,
+		GroupTeamMapRemoval:   cfg.GroupTeamMapRemoval,
+	}
+	if mapping, err := ldap.ParseGroupTeamMap(cfg.GroupTeamMap); err == nil && len(mapping) > 0 {
+		result.GroupTeamMap = mapping
+	}
+	for name, protocol := range LDAPSecurityProtocols {
+		if protocol == cfg.SecurityProtocol {
+			result.SecurityProtocol = name
+			break
+		}
+	}
+	return result
+}
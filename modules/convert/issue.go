@@ -44,6 +44,7 @@ func ToAPIIssue(issue *models.Issue) *api.Issue {
 		Labels:   ToLabelList(issue.Labels, issue.Repo, issue.Repo.Owner),
 		State:    issue.State(),
 		IsLocked: issue.IsLocked,
+		PinOrder: issue.PinOrder,
 		Comments: issue.NumComments,
 		Created:  issue.CreatedUnix.AsTime(),
 		Updated:  issue.UpdatedUnix.AsTime(),
@@ -103,6 +104,43 @@ func ToAPIIssueList(il models.IssueList) []*api.Issue {
 	return result
 }
 
+// ToIssueReference converts an IssueReference to API format, only the type, the action
+// and the time are kept for private references
+func ToIssueReference(ref *models.IssueReference, private bool) *api.IssueReference {
+	apiRef := &api.IssueReference{
+		Type:    "commit",
+		Private: private,
+		Closes:  ref.Closes,
+		Created: ref.CreatedUnix.AsTime(),
+	}
+	if ref.Issue != nil {
+		apiRef.Type = "issue"
+		if ref.Issue.IsPull {
+			apiRef.Type = "pull"
+		}
+	}
+	if private {
+		return apiRef
+	}
+
+	apiRef.Repository = &api.RepositoryMeta{
+		ID:       ref.Repo.ID,
+		Name:     ref.Repo.Name,
+		Owner:    ref.Repo.OwnerName,
+		FullName: ref.Repo.FullName(),
+	}
+	if ref.Issue != nil {
+		apiRef.Index = ref.Issue.Index
+		apiRef.Title = ref.Issue.Title
+		apiRef.State = ref.Issue.State()
+		apiRef.HTMLURL = ref.Issue.HTMLURL()
+	} else {
+		apiRef.SHA = ref.CommitSHA
+		apiRef.HTMLURL = ref.Repo.CommitLink(ref.CommitSHA)
+	}
+	return apiRef
+}
+
 // ToTrackedTime converts TrackedTime to API format
 func ToTrackedTime(t *models.TrackedTime) (apiT *api.TrackedTime) {
 	apiT = &api.TrackedTime{
@@ -180,6 +218,7 @@ func ToLabel(label *models.Label, repo *models.Repository, org *models.User) *ap
 		Name:        label.Name,
 		Color:       strings.TrimLeft(label.Color, "#"),
 		Description: label.Description,
+		Exclusive:   label.Exclusive,
 	}
 
 	// calculate URL
@@ -226,6 +265,7 @@ func ToAPIMilestone(m *models.Milestone) *api.Milestone {
 	}
 	if m.DeadlineUnix.Year() < 9999 {
 		apiMilestone.Deadline = m.DeadlineUnix.AsTimePtr()
+		apiMilestone.IsOverdue = m.IsOverdue
 	}
 	return apiMilestone
 }
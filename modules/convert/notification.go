@@ -52,8 +52,7 @@ func ToNotificationThread(n *models.Notification) *api.NotificationThread {
 				result.Subject.LatestCommentHTMLURL = comment.HTMLURL()
 			}
 
-			pr, _ := n.Issue.GetPullRequest()
-			if pr != nil && pr.HasMerged {
+			if err := n.Issue.LoadPullRequest(); err == nil && n.Issue.PullRequest != nil && n.Issue.PullRequest.HasMerged {
 				result.Subject.State = "merged"
 			}
 		}
@@ -61,6 +61,7 @@ func ToAPIPullRequest(pr *models.PullRequest, doer *models.User) *api.PullReques
 		State:     apiIssue.State,
 		IsLocked:  apiIssue.IsLocked,
 		Comments:  apiIssue.Comments,
+		Draft:     pr.IsWorkInProgress(),
 		HTMLURL:   pr.Issue.HTMLURL(),
 		DiffURL:   pr.Issue.DiffURL(),
 		PatchURL:  pr.Issue.PatchURL(),
@@ -186,6 +187,15 @@ func ToAPIPullRequest(pr *models.PullRequest, doer *models.User) *api.PullReques
 		mergeable := !(pr.Status == models.PullRequestStatusConflict || pr.Status == models.PullRequestStatusError) && !pr.IsWorkInProgress()
 		apiPullRequest.Mergeable = mergeable
 	}
+	if !pr.HasMerged {
+		if err := pr.LoadProtectedBranch(); err != nil {
+			log.Error("LoadProtectedBranch[%d]: %v", pr.ID, err)
+			return nil
+		}
+		if pr.ProtectedBranch != nil && pr.ProtectedBranch.MergeBlockedByOutdatedBranch(pr) {
+			apiPullRequest.MergeBlockedReason = api.MergeBlockedByOutdatedBranch
+		}
+	}
 	if pr.HasMerged {
 		apiPullRequest.Merged = pr.MergedUnix.AsTimePtr()
 		apiPullRequest.MergedCommitID = &pr.MergedCommitID
@@ -194,3 +204,19 @@ func ToAPIPullRequest(pr *models.PullRequest, doer *models.User) *api.PullReques
 
 	return apiPullRequest
 }
+
+// ToMergeQueueEntry converts an entry of a merge queue at the given position to api format
+func ToMergeQueueEntry(e *models.MergeQueueEntry, position int64, doer *models.User) *api.MergeQueueEntry {
+	result := &api.MergeQueueEntry{
+		Position:   position,
+		BaseBranch: e.BaseBranch,
+		MergeStyle: string(e.MergeStyle),
+		Enqueuer:   ToUser(e.Doer, doer),
+		TestCommit: e.TestCommitID,
+		Created:    e.CreatedUnix.AsTime(),
+	}
+	if e.IsTesting() {
+		result.TestRef = e.TestRef()
+	}
+	return result
+}
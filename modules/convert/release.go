@@ -46,3 +46,29 @@ func ToReleaseAttachment(a *models.Attachment) *api.Attachment {
 		DownloadURL:   a.DownloadURL(),
 	}
 }
+
+// ToReleaseAttachmentTask converts a task fetching a release attachment to api.ReleaseAttachmentTask,
+// attach is the fetched attachment and may be nil
+func ToReleaseAttachmentTask(t *models.Task, fetch *models.ReleaseAttachmentFetch, attach *models.Attachment) *api.ReleaseAttachmentTask {
+	apiTask := &api.ReleaseAttachmentTask{
+		ID:          t.ID,
+		Status:      t.Status.Name(),
+		Message:     t.Message,
+		SourceURL:   fetch.SourceURL,
+		Name:        fetch.Name,
+		ContentType: fetch.ContentType,
+		FetchedSize: fetch.FetchedSize,
+		TotalSize:   fetch.TotalSize,
+		Created:     t.Created.AsTime(),
+	}
+	if !t.StartTime.IsZero() {
+		apiTask.Started = t.StartTime.AsTimePtr()
+	}
+	if !t.EndTime.IsZero() {
+		apiTask.Finished = t.EndTime.AsTimePtr()
+	}
+	if attach != nil {
+		apiTask.Attachment = ToReleaseAttachment(attach)
+	}
+	return apiTask
+}
@@ -5,8 +5,13 @@
 package convert
 
 import (
+	"fmt"
+	"time"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // ToRepo converts a Repository to api.Repository
@@ -17,6 +22,9 @@ func ToRepo(repo *models.Repository, mode models.AccessMode) *api.Repository {
 func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool) *api.Repository {
 	var parent *api.Repository
 
+	if err := repo.LoadGitSettings(); err != nil {
+		log.Error("LoadGitSettings: %v", err)
+	}
 	cloneLink := repo.CloneLink()
 	permission := &api.Permission{
 		Admin: mode >= models.AccessModeAdmin,
@@ -44,6 +52,9 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 			EnableTimeTracker:                config.EnableTimetracker,
 			AllowOnlyContributorsToTrackTime: config.AllowOnlyContributorsToTrackTime,
 			EnableIssueDependencies:          config.EnableDependencies,
+			DefaultSortType:                  config.DefaultSortType,
+			CloseKeywords:                    config.CloseKeywords,
+			ReopenKeywords:                   config.ReopenKeywords,
 		}
 	} else if unit, err := repo.GetUnit(models.UnitTypeExternalTracker); err == nil {
 		config := unit.ExternalTrackerConfig()
@@ -52,6 +63,8 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 			ExternalTrackerURL:    config.ExternalTrackerURL,
 			ExternalTrackerFormat: config.ExternalTrackerFormat,
 			ExternalTrackerStyle:  config.ExternalTrackerStyle,
+
+			ExternalTrackerRegexpPattern: config.ExternalTrackerRegexpPattern,
 		}
 	}
 	hasWiki := false
@@ -72,6 +85,10 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 	allowRebaseMerge := false
 	allowSquash := false
 	defaultMergeStyle := models.MergeStyleMerge
+	enableMergeQueue := false
+	mergeCommitAuthorStyle := models.MergeCommitAuthorDefault
+	addReviewedByTrailers := false
+	addCoAuthorTrailers := false
 	if unit, err := repo.GetUnit(models.UnitTypePullRequests); err == nil {
 		config := unit.PullRequestsConfig()
 		hasPullRequests = true
@@ -81,6 +98,10 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		allowRebaseMerge = config.AllowRebaseMerge
 		allowSquash = config.AllowSquash
 		defaultMergeStyle = config.GetDefaultMergeStyle()
+		enableMergeQueue = config.EnableMergeQueue
+		mergeCommitAuthorStyle = config.MergeCommitAuthorStyle
+		addReviewedByTrailers = config.AddReviewedByTrailers
+		addCoAuthorTrailers = config.AddCoAuthorTrailers
 	}
 	hasProjects := false
 	if _, err := repo.GetUnit(models.UnitTypeProjects); err == nil {
@@ -93,6 +114,13 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 
 	numReleases, _ := models.GetReleaseCountByRepoID(repo.ID, models.FindReleasesOptions{IncludeDrafts: false, IncludeTags: false})
 
+	licenses := make([]string, 0, 1)
+	if repoLicenses, err := repo.GetLicenses(); err != nil {
+		log.Error("GetLicenses: %v", err)
+	} else {
+		licenses = repoLicenses.Names()
+	}
+
 	mirrorInterval := ""
 	if repo.IsMirror {
 		if err := repo.GetMirror(); err == nil {
@@ -100,12 +128,26 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		}
 	}
 
+	var lastPushedAt *time.Time
+	if repo.PushedUnix > 0 {
+		t := repo.PushedUnix.AsTime()
+		lastPushedAt = &t
+	}
+
+	var sizeUpdatedAt *time.Time
+	if repo.SizeUpdatedUnix > 0 {
+		t := repo.SizeUpdatedUnix.AsTime()
+		sizeUpdatedAt = &t
+	}
+
 	return &api.Repository{
 		ID:                        repo.ID,
 		Owner:                     ToUserWithAccessMode(repo.Owner, mode),
 		Name:                      repo.Name,
 		FullName:                  repo.FullName(),
 		Description:               repo.Description,
+		DescriptionText:           repo.DescriptionText(),
+		SizeUpdatedAt:             sizeUpdatedAt,
 		Private:                   repo.IsPrivate,
 		Template:                  repo.IsTemplate,
 		Empty:                     repo.IsEmpty,
@@ -117,6 +159,8 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		HTMLURL:                   repo.HTMLURL(),
 		SSHURL:                    cloneLink.SSH,
 		CloneURL:                  cloneLink.HTTPS,
+		CloneFilter:               cloneLink.Filter,
+		CloneTokenURL:             cloneLink.TokenURL,
 		OriginalURL:               repo.SanitizedOriginalURL(),
 		Website:                   repo.Website,
 		Stars:                     repo.NumStars,
@@ -128,6 +172,7 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		DefaultBranch:             repo.DefaultBranch,
 		Created:                   repo.CreatedUnix.AsTime(),
 		Updated:                   repo.UpdatedUnix.AsTime(),
+		LastPushedAt:              lastPushedAt,
 		Permissions:               permission,
 		HasIssues:                 hasIssues,
 		ExternalTracker:           externalTracker,
@@ -142,8 +187,198 @@ func innerToRepo(repo *models.Repository, mode models.AccessMode, isParent bool)
 		AllowRebaseMerge:          allowRebaseMerge,
 		AllowSquash:               allowSquash,
 		DefaultMergeStyle:         string(defaultMergeStyle),
+		EnableMergeQueue:          enableMergeQueue,
+		MergeCommitAuthorStyle:    string(mergeCommitAuthorStyle),
+		AddReviewedByTrailers:     addReviewedByTrailers,
+		AddCoAuthorTrailers:       addCoAuthorTrailers,
 		AvatarURL:                 repo.AvatarLink(),
 		Internal:                  !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
 		MirrorInterval:            mirrorInterval,
+		ObjectFormat:              repo.ObjectFormat.Name(),
+		CreatedFrom:               string(repo.CreatedFrom),
+		TemplateID:                repo.TemplateID,
+		OriginalServiceType:       repo.OriginalServiceType.Name(),
+		Licenses:                  licenses,
+	}
+}
+
+// ToMigrationTask converts a task migrating a repository to api.MigrationTask
+func ToMigrationTask(t *models.Task) *api.MigrationTask {
+	apiTask := &api.MigrationTask{
+		ID:                 t.ID,
+		Status:             t.Status.Name(),
+		Message:            t.Message,
+		MigratedIssueIndex: t.MigratedIssueIndex,
+		MigratedPullIndex:  t.MigratedPullIndex,
+		MigratedReleaseTag: t.MigratedReleaseTag,
+		Created:            t.Created.AsTime(),
+	}
+	if !t.StartTime.IsZero() {
+		apiTask.Started = t.StartTime.AsTimePtr()
+	}
+	if !t.EndTime.IsZero() {
+		apiTask.Finished = t.EndTime.AsTimePtr()
+	}
+	if !t.LastSyncUnix.IsZero() {
+		apiTask.LastSync = t.LastSyncUnix.AsTimePtr()
+	}
+	return apiTask
+}
+
+// ToRepoHealthCheck converts a task checking the health of a repository to api.RepoHealthCheck
+func ToRepoHealthCheck(repo *models.Repository, t *models.Task, check *models.RepoHealthCheck) *api.RepoHealthCheck {
+	apiCheck := &api.RepoHealthCheck{
+		ID:      t.ID,
+		Status:  t.Status.Name(),
+		Message: t.Message,
+		Healthy: check.Healthy,
+		Results: make([]*api.RepoHealthCheckResult, 0, len(check.Results)),
+		URL:     util.URLJoin(repo.APIURL(), "health-check", fmt.Sprint(t.ID)),
+		Created: t.Created.AsTime(),
+	}
+	for _, result := range check.Results {
+		apiCheck.Results = append(apiCheck.Results, &api.RepoHealthCheckResult{
+			Name:    result.Name,
+			OK:      result.OK,
+			Message: result.Message,
+		})
+	}
+	if !t.StartTime.IsZero() {
+		apiCheck.Started = t.StartTime.AsTimePtr()
+	}
+	if !t.EndTime.IsZero() {
+		apiCheck.Finished = t.EndTime.AsTimePtr()
+	}
+	return apiCheck
+}
+
+// ToRepoPermissions converts the accesses of users and deploy keys to a repository to api.RepoPermissions
+func ToRepoPermissions(doer *models.User, accesses []*models.RepoUserAccess, keys []*models.DeployKey) *api.RepoPermissions {
+	permissions := &api.RepoPermissions{
+		Users:      make([]*api.RepoUserPermission, 0, len(accesses)),
+		DeployKeys: make([]*api.RepoDeployKeyPermission, 0, len(keys)),
+	}
+	for _, access := range accesses {
+		apiAccess := &api.RepoUserPermission{
+			User:       ToUser(access.User, doer),
+			Permission: access.Mode.String(),
+			Restricted: access.User.IsRestricted,
+			Sources:    make([]*api.RepoPermissionSource, 0, len(access.Sources)),
+		}
+		for _, source := range access.Sources {
+			apiAccess.Sources = append(apiAccess.Sources, toRepoPermissionSource(source))
+		}
+		permissions.Users = append(permissions.Users, apiAccess)
+	}
+	for _, key := range keys {
+		permissions.DeployKeys = append(permissions.DeployKeys, &api.RepoDeployKeyPermission{
+			ID:          key.ID,
+			Title:       key.Name,
+			Fingerprint: key.Fingerprint,
+			Permission:  key.Mode.String(),
+		})
+	}
+	return permissions
+}
+
+// ToRepoCollaboratorPermission converts the effective permission of a user to a repository and the sources of it
+// to api.RepoCollaboratorPermission
+func ToRepoCollaboratorPermission(doer, u *models.User, repo *models.Repository, perm models.Permission, sources []*models.AccessSource) *api.RepoCollaboratorPermission {
+	// the owners of a repository are reported as admins
+	role := func(mode models.AccessMode) string {
+		if mode > models.AccessModeAdmin {
+			mode = models.AccessModeAdmin
+		}
+		return mode.String()
+	}
+	mode := perm.AccessMode
+	if mode < models.AccessModeRead && perm.HasAccess() {
+		// the units of public organization repositories can be read without access to the repository
+		mode = models.AccessModeRead
+	}
+
+	apiPerm := &api.RepoCollaboratorPermission{
+		User:       ToUser(u, doer),
+		Permission: role(mode),
+		Units:      make(map[string]string, len(repo.Units)),
+		Restricted: u.IsRestricted,
+		Sources:    make([]*api.RepoPermissionSource, 0, len(sources)),
+	}
+	for _, unit := range repo.Units {
+		apiPerm.Units[unit.Unit().NameKey] = role(perm.UnitAccessMode(unit.Type))
+	}
+	for _, source := range sources {
+		apiPerm.Sources = append(apiPerm.Sources, toRepoPermissionSource(source))
+	}
+	return apiPerm
+}
+
+func toRepoPermissionSource(source *models.AccessSource) *api.RepoPermissionSource {
+	apiSource := &api.RepoPermissionSource{
+		Type:       string(source.Type),
+		Permission: source.Mode.String(),
+		Team:       ToTeam(source.Team),
+	}
+	if source.WikiMode > models.AccessModeNone {
+		apiSource.WikiPermission = source.WikiMode.String()
+	}
+	return apiSource
+}
+
+// ToRepoDetachFork converts a task detaching a fork from its base repository to api.RepoDetachFork
+func ToRepoDetachFork(repo *models.Repository, t *models.Task, detach *models.RepoDetachFork) *api.RepoDetachFork {
+	apiDetach := &api.RepoDetachFork{
+		ID:         t.ID,
+		Status:     t.Status.Name(),
+		Message:    t.Message,
+		Stage:      detach.Stage,
+		FormerBase: detach.FormerBase,
+		URL:        util.URLJoin(repo.APIURL(), "detach-fork", fmt.Sprint(t.ID)),
+		Created:    t.Created.AsTime(),
+	}
+	if !t.StartTime.IsZero() {
+		apiDetach.Started = t.StartTime.AsTimePtr()
+	}
+	if !t.EndTime.IsZero() {
+		apiDetach.Finished = t.EndTime.AsTimePtr()
+	}
+	return apiDetach
+}
+
+// ToRepoCloneToken converts a RepoCloneToken to api.RepoCloneToken, the clone URL is only set for new tokens
+func ToRepoCloneToken(repo *models.Repository, user *models.User, t *models.RepoCloneToken) *api.RepoCloneToken {
+	apiToken := &api.RepoCloneToken{
+		ID:             t.ID,
+		Token:          t.Token,
+		TokenLastEight: t.TokenLastEight,
+		Permission:     t.Mode.String(),
+		Created:        t.CreatedUnix.AsTime(),
+		Expires:        t.ExpiresUnix.AsTime(),
+	}
+	if t.Token != "" {
+		apiToken.CloneURL = repo.CloneLink().HTTPSToken(user.Name, t.Token)
+	}
+	return apiToken
+}
+
+// ToAccessibleRepo converts an organization repository a team or a user has access to to api.AccessibleRepository
+func ToAccessibleRepo(repo *models.AccessibleRepo) *api.AccessibleRepository {
+	apiRepo := &api.AccessibleRepository{
+		Repository: ToRepo(repo.Repository, models.AccessModeOwner),
+		Permission: repo.Mode.String(),
+		Paths:      make([]*api.RepositoryAccessPath, 0, len(repo.Paths)),
+	}
+	for _, p := range repo.Paths {
+		path := &api.RepositoryAccessPath{
+			Collaboration: p.Team == nil,
+			Permission:    p.Mode.String(),
+		}
+		if p.Team != nil {
+			path.TeamID = p.Team.ID
+			path.TeamName = p.Team.Name
+			path.IncludesAllRepositories = p.Team.IncludesAllRepositories
+		}
+		apiRepo.Paths = append(apiRepo.Paths, path)
 	}
+	return apiRepo
 }
@@ -22,7 +22,9 @@ func ToCommitStatus(status *models.CommitStatus) *api.CommitStatus {
 		Context:     status.Context,
 	}
 
-	if status.CreatorID != 0 {
+	if status.CreatorID == models.DeployKeyUserID {
+		apiStatus.Creator = ToUser(models.NewDeployKeyUser(status.CreatorName), nil)
+	} else if status.CreatorID != 0 {
 		creator, _ := models.GetUserByID(status.CreatorID)
 		apiStatus.Creator = ToUser(creator, nil)
 	}
@@ -77,6 +77,7 @@ func toUser(user *models.User, signed, authed bool) *api.User {
 		result.Language = user.Language
 		result.IsActive = user.IsActive
 		result.ProhibitLogin = user.ProhibitLogin
+		result.IsServiceAccount = user.IsServiceAccount
 	}
 	return result
 }
@@ -93,5 +94,28 @@ func User2UserSettings(user *models.User) api.UserSettings {
 		HideEmail:     user.KeepEmailPrivate,
 		HideActivity:  user.KeepActivityPrivate,
 		DiffViewStyle: user.DiffViewStyle,
+		AutoWatch:     user.AutoWatch.String(),
+		IssueSortType: user.IssueSortType,
+
+		MuteMilestoneReminders: user.MuteMilestoneReminders,
+	}
+}
+
+// ToUserExport converts a task exporting the data of a user to api.UserExport, the download link is not set
+func ToUserExport(t *models.Task, export *models.UserExport) *api.UserExport {
+	apiExport := &api.UserExport{
+		ID:      t.ID,
+		Status:  t.Status.Name(),
+		Message: t.Message,
+		Size:    export.Size,
+		Purged:  export.Purged,
+		Created: t.Created.AsTime(),
+	}
+	if !t.StartTime.IsZero() {
+		apiExport.Started = t.StartTime.AsTimePtr()
+	}
+	if !t.EndTime.IsZero() {
+		apiExport.Finished = t.EndTime.AsTimePtr()
 	}
+	return apiExport
 }
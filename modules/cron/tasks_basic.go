@@ -12,8 +12,14 @@ import (
 	"code.gitea.io/gitea/modules/migrations"
 	repository_service "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/services/auth"
+	digest_service "code.gitea.io/gitea/services/digest"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	stats_service "code.gitea.io/gitea/services/stats"
+	traffic_service "code.gitea.io/gitea/services/traffic"
 )
 
 func registerUpdateMirrorTask() {
@@ -71,6 +77,20 @@ func registerArchiveCleanup() {
 	})
 }
 
+func registerDeleteOldUserExports() {
+	RegisterTaskFatal("delete_old_user_exports", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: true,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 7 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		return task.DeleteOldUserExports(ctx, olderThanConfig.OlderThan)
+	})
+}
+
 func registerSyncExternalUsers() {
 	RegisterTaskFatal("sync_external_users", &UpdateExistingConfig{
 		BaseConfig: BaseConfig{
@@ -126,15 +146,92 @@ func registerCleanupHookTaskTable() {
 	})
 }
 
+func registerRepoCleanupTasks() {
+	RegisterTaskFatal("repo_cleanup_tasks", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 5m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return models.ProcessRepoCleanupTasks(ctx)
+	})
+}
+
+func registerSendWebhookDigests() {
+	RegisterTaskFatal("send_webhook_digests", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      false,
+		Schedule:        "@every 1h",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return digest_service.SendDigests(ctx)
+	})
+}
+
+func registerProcessMergeQueues() {
+	RegisterTaskFatal("process_merge_queues", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return pull_service.ProcessMergeQueues(ctx)
+	})
+}
+
+func registerRetryRepoBackups() {
+	RegisterTaskFatal("retry_repo_backups", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return repo_service.ProcessRepoBackups(ctx)
+	})
+}
+
+func registerRefreshStatistics() {
+	RegisterTaskFatal("refresh_statistics", &BaseConfig{
+		Enabled:         true,
+		RunAtStart:      true,
+		Schedule:        "@every 10m",
+		NoSuccessNotice: true,
+	}, func(_ context.Context, _ *models.User, _ Config) error {
+		stats_service.Refresh()
+		return nil
+	})
+}
+
+func registerAggregateRepoTraffic() {
+	RegisterTaskFatal("aggregate_repo_traffic", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return traffic_service.Aggregate(ctx)
+	})
+}
+
 func initBasicTasks() {
 	registerUpdateMirrorTask()
 	registerRepoHealthCheck()
 	registerCheckRepoStats()
 	registerArchiveCleanup()
+	registerDeleteOldUserExports()
 	registerSyncExternalUsers()
 	registerDeletedBranchesCleanup()
 	if !setting.Repository.DisableMigrations {
 		registerUpdateMigrationPosterID()
 	}
 	registerCleanupHookTaskTable()
+	registerRepoCleanupTasks()
+	registerRetryRepoBackups()
+	registerProcessMergeQueues()
+	registerRefreshStatistics()
+	if setting.Repository.Traffic.Enabled {
+		registerAggregateRepoTraffic()
+	}
+	if !setting.DisableWebhooks {
+		registerSendWebhookDigests()
+	}
 }
@@ -6,15 +6,31 @@ package cron
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
+	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/mailer"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
+const (
+	deleteInactiveUsersTask = "delete_inactive_accounts"
+	syncRepoGitConfigTask   = "sync_repo_git_config"
+)
+
+// ErrTaskAlreadyRunning is returned if a task is started while it is running
+var ErrTaskAlreadyRunning = errors.New("task is already running")
+
 func registerDeleteInactiveUsers() {
-	RegisterTaskFatal("delete_inactive_accounts", &OlderThanConfig{
+	RegisterTaskFatal(deleteInactiveUsersTask, &OlderThanConfig{
 		BaseConfig: BaseConfig{
 			Enabled:    false,
 			RunAtStart: false,
@@ -23,7 +39,52 @@ func registerDeleteInactiveUsers() {
 		OlderThan: 0 * time.Second,
 	}, func(ctx context.Context, _ *models.User, config Config) error {
 		olderThanConfig := config.(*OlderThanConfig)
-		return models.DeleteInactiveUsers(ctx, olderThanConfig.OlderThan)
+		result, err := models.DeleteInactiveUsers(ctx, olderThanConfig.OlderThan, false)
+		if result != nil {
+			log.Info("Deleted %d inactive users, skipped %d", result.Deleted, result.Skipped)
+		}
+		return err
+	})
+}
+
+// DeleteInactiveUsers runs the delete_inactive_accounts task with its configured age limit and
+// returns the report of the deleted and skipped users. In dry run mode nothing is deleted.
+func DeleteInactiveUsers(ctx context.Context, dryRun bool) (*models.DeleteInactiveUsersResult, error) {
+	task := GetTask(deleteInactiveUsersTask)
+	if task == nil {
+		return nil, fmt.Errorf("task %s is not registered", deleteInactiveUsersTask)
+	}
+	if !dryRun {
+		if !taskStatusTable.StartIfNotRunning(task.Name) {
+			return nil, ErrTaskAlreadyRunning
+		}
+		defer taskStatusTable.Stop(task.Name)
+		task.lock.Lock()
+		task.ExecTimes++
+		task.lock.Unlock()
+	}
+	return models.DeleteInactiveUsers(ctx, task.config.(*OlderThanConfig).OlderThan, dryRun)
+}
+
+func registerDeactivateInactiveUsers() {
+	type DeactivateInactiveUsersConfig struct {
+		BaseConfig
+		InactiveFor  time.Duration
+		NotifyBefore time.Duration
+		DeleteAfter  time.Duration
+	}
+	RegisterTaskFatal("deactivate_inactive_users", &DeactivateInactiveUsersConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		InactiveFor:  180 * 24 * time.Hour,
+		NotifyBefore: 30 * 24 * time.Hour,
+		DeleteAfter:  0,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		deactivateConfig := config.(*DeactivateInactiveUsersConfig)
+		return user_service.DeactivateInactiveUsers(ctx, deactivateConfig.InactiveFor, deactivateConfig.NotifyBefore, deactivateConfig.DeleteAfter)
 	})
 }
 
@@ -87,6 +148,29 @@ func registerRepositoryUpdateHook() {
 	})
 }
 
+func registerSyncRepositoryGitConfig() {
+	RegisterTaskFatal(syncRepoGitConfigTask, &BaseConfig{
+		Enabled:    false,
+		RunAtStart: false,
+		Schedule:   "@every 72h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return repo_module.SyncRepositoryGitConfig(ctx)
+	})
+}
+
+// SyncRepositoryGitConfig starts the sync_repo_git_config task in the background
+func SyncRepositoryGitConfig(doer *models.User) error {
+	task := GetTask(syncRepoGitConfigTask)
+	if task == nil {
+		return fmt.Errorf("task %s is not registered", syncRepoGitConfigTask)
+	}
+	if taskStatusTable.IsRunning(task.Name) {
+		return ErrTaskAlreadyRunning
+	}
+	go task.RunWithUser(doer, nil)
+	return nil
+}
+
 func registerReinitMissingRepositories() {
 	RegisterTaskFatal("reinit_missing_repos", &BaseConfig{
 		Enabled:    false,
@@ -131,6 +215,34 @@ func registerDeleteOldActions() {
 	})
 }
 
+func registerDeleteOldNotices() {
+	RegisterTaskFatal("delete_old_notices", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		OlderThan: 30 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		return models.DeleteOldNotices(olderThanConfig.OlderThan)
+	})
+}
+
+func registerCleanupPullHeadRefs() {
+	RegisterTaskFatal("cleanup_pull_head_refs", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 168h",
+		},
+		OlderThan: 180 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		return pull_service.CleanupAllHeadRefs(ctx, olderThanConfig.OlderThan)
+	})
+}
+
 func registerUpdateGiteaChecker() {
 	type UpdateCheckerConfig struct {
 		BaseConfig
@@ -149,16 +261,80 @@ func registerUpdateGiteaChecker() {
 	})
 }
 
+func registerGPGKeyExpiryNotifications() {
+	type GPGKeyExpiryConfig struct {
+		BaseConfig
+		NotifyBefore time.Duration
+	}
+	RegisterTaskFatal("gpg_key_expiry_notifications", &GPGKeyExpiryConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		NotifyBefore: 14 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		gpgKeyExpiryConfig := config.(*GPGKeyExpiryConfig)
+		return mailer.SendGPGKeyExpiryMails(ctx, gpgKeyExpiryConfig.NotifyBefore)
+	})
+}
+
+func registerMilestoneReminders() {
+	type MilestoneReminderConfig struct {
+		BaseConfig
+		NotifyBefore time.Duration
+	}
+	RegisterTaskFatal("milestone_reminders", &MilestoneReminderConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		NotifyBefore: 3 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *models.User, config Config) error {
+		milestoneReminderConfig := config.(*MilestoneReminderConfig)
+		return issue_service.SendMilestoneReminders(ctx, milestoneReminderConfig.NotifyBefore)
+	})
+}
+
+func registerStaleBranchesCleanup() {
+	RegisterTaskFatal("stale_branches_cleanup", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return repo_service.CleanupStaleBranches(ctx)
+	})
+}
+
+func registerBackupRepositories() {
+	RegisterTaskFatal("backup_repositories", &BaseConfig{
+		Enabled:    false,
+		RunAtStart: false,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *models.User, _ Config) error {
+		return repo_service.BackupRepositories(ctx)
+	})
+}
+
 func initExtendedTasks() {
 	registerDeleteInactiveUsers()
+	registerDeactivateInactiveUsers()
 	registerDeleteRepositoryArchives()
 	registerGarbageCollectRepositories()
 	registerRewriteAllPublicKeys()
 	registerRewriteAllPrincipalKeys()
 	registerRepositoryUpdateHook()
+	registerSyncRepositoryGitConfig()
 	registerReinitMissingRepositories()
 	registerDeleteMissingRepositories()
 	registerRemoveRandomAvatars()
 	registerDeleteOldActions()
+	registerDeleteOldNotices()
+	registerCleanupPullHeadRefs()
 	registerUpdateGiteaChecker()
+	registerGPGKeyExpiryNotifications()
+	registerMilestoneReminders()
+	registerStaleBranchesCleanup()
+	registerBackupRepositories()
 }
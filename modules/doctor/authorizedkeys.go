@@ -40,11 +40,18 @@ func checkAuthorizedKeys(logger log.Logger, autofix bool) error {
 	defer f.Close()
 
 	linesInAuthorizedKeys := map[string]bool{}
+	// giteaKeyLines are the lines in the file that are managed by gitea
+	giteaKeyLines := []string{}
 
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.HasPrefix(line, tplCommentPrefix) {
+			if scanner.Scan() {
+				line = scanner.Text()
+				giteaKeyLines = append(giteaKeyLines, line)
+				linesInAuthorizedKeys[line] = true
+			}
 			continue
 		}
 		linesInAuthorizedKeys[line] = true
@@ -57,15 +64,30 @@ func checkAuthorizedKeys(logger log.Logger, autofix bool) error {
 		logger.Critical("Unable to regenerate authorized_keys file. ERROR: %v", err)
 		return fmt.Errorf("Unable to regenerate authorized_keys file. ERROR: %v", err)
 	}
+	linesInDatabase := map[string]bool{}
+	missing := 0
 	scanner = bufio.NewScanner(regenerated)
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.HasPrefix(line, tplCommentPrefix) {
 			continue
 		}
-		if ok := linesInAuthorizedKeys[line]; ok {
-			continue
+		linesInDatabase[line] = true
+		if ok := linesInAuthorizedKeys[line]; !ok {
+			missing++
+		}
+	}
+
+	// keys that are still in the file but were removed from the database
+	stale := 0
+	for _, line := range giteaKeyLines {
+		if !linesInDatabase[line] {
+			stale++
 		}
+	}
+
+	if missing > 0 || stale > 0 {
+		logger.Info("authorized_keys file %q is missing %d keys and contains %d keys not in the database", fPath, missing, stale)
 		if !autofix {
 			logger.Critical(
 				"authorized_keys file %q is out of date.\nRegenerate it with:\n\t\"%s\"\nor\n\t\"%s\"",
@@ -141,6 +141,27 @@ func checkDBConsistency(logger log.Logger, autofix bool) error {
 			Fixer:        models.FixIssueLabelWithOutsideLabels,
 			FixedMessage: "Removed",
 		},
+		// find topics whose repository count doesn't match their repositories
+		{
+			Name:         "Topics with wrong repository count",
+			Counter:      models.CountTopicsWithWrongRepoCount,
+			Fixer:        models.FixTopicsWithWrongRepoCount,
+			FixedMessage: "Fixed",
+		},
+		// find repositories whose cached topics don't match their topics
+		{
+			Name:         "Repositories with outdated topics",
+			Counter:      models.CountReposWithInconsistentTopics,
+			Fixer:        models.FixReposWithInconsistentTopics,
+			FixedMessage: "Fixed",
+		},
+		// find repositories whose fork count doesn't match their forks
+		{
+			Name:         "Repositories with wrong fork count",
+			Counter:      models.CountReposWithWrongNumForks,
+			Fixer:        models.FixReposWithWrongNumForks,
+			FixedMessage: "Fixed",
+		},
 	}
 
 	// TODO: function to recalc all counters
@@ -0,0 +1,55 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+)
+
+func checkIssueCounters(logger log.Logger, autofix bool) error {
+	for _, c := range []struct {
+		name  string
+		drift func() (*models.IssueCounterDrift, error)
+		fix   func() (int64, error)
+	}{
+		{"labels", models.GetLabelIssueCounterDrift, models.FixLabelIssueCounters},
+		{"milestones", models.GetMilestoneIssueCounterDrift, models.FixMilestoneIssueCounters},
+	} {
+		drift, err := c.drift()
+		if err != nil {
+			logger.Critical("Error: %v whilst counting the issues of the %s", err, c.name)
+			return err
+		}
+		if drift.Count == 0 {
+			logger.Info("The issue counters of all %s are right", c.name)
+			continue
+		}
+
+		if !autofix {
+			logger.Warn("Found %d %s with wrong issue counters, off by %d issues and %d closed issues in total",
+				drift.Count, c.name, drift.Issues, drift.ClosedIssues)
+			continue
+		}
+		fixed, err := c.fix()
+		if err != nil {
+			logger.Critical("Error: %v whilst recounting the issues of the %s", err, c.name)
+			return err
+		}
+		logger.Info("Recounted the issues of %d %s, their counters were off by %d issues and %d closed issues in total",
+			fixed, c.name, drift.Issues, drift.ClosedIssues)
+	}
+	return nil
+}
+
+func init() {
+	Register(&Check{
+		Title:     "Recount the issues of labels and milestones",
+		Name:      "recount-issue-counters",
+		IsDefault: false,
+		Run:       checkIssueCounters,
+		Priority:  3,
+	})
+}
@@ -71,6 +71,48 @@ func checkHooks(logger log.Logger, autofix bool) error {
 	return nil
 }
 
+func checkGitConfig(logger log.Logger, autofix bool) error {
+	if len(setting.Git.RepoConfig) == 0 && len(setting.Git.RepoConfigUnset) == 0 {
+		logger.Info("No git config template configured in [git.repo_config] or REPO_CONFIG_UNSET")
+		return nil
+	}
+
+	numRepos := 0
+	numNeedUpdate := 0
+	if err := iterateRepositories(func(repo *models.Repository) error {
+		numRepos++
+		results, err := repository.CheckGitConfigTemplate(git.DefaultContext, repo.RepoPath())
+		if err != nil {
+			logger.Critical("Unable to check git config for repo %-v. ERROR: %v", repo, err)
+			return fmt.Errorf("Unable to check git config for repo %-v. ERROR: %v", repo, err)
+		}
+		if len(results) == 0 {
+			return nil
+		}
+		numNeedUpdate++
+		for _, result := range results {
+			logger.Warn("%s: %s", repo.FullName(), result)
+		}
+		if autofix {
+			if err := repository.ApplyGitConfigTemplate(git.DefaultContext, repo.RepoPath()); err != nil {
+				logger.Critical("Unable to apply git config for %-v. ERROR: %v", repo, err)
+				return fmt.Errorf("Unable to apply git config for %-v. ERROR: %v", repo, err)
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Critical("Errors noted whilst checking git config.")
+		return err
+	}
+
+	if autofix {
+		logger.Info("Applied the git config template to %d of %d repositories.", numNeedUpdate, numRepos)
+	} else {
+		logger.Info("Checked %d repositories, %d need updates.", numRepos, numNeedUpdate)
+	}
+	return nil
+}
+
 func checkUserStarNum(logger log.Logger, autofix bool) error {
 	if err := models.DoctorUserStarNum(); err != nil {
 		logger.Critical("Unable update User Stars numbers")
@@ -197,6 +239,13 @@ func init() {
 		Run:       checkHooks,
 		Priority:  6,
 	})
+	Register(&Check{
+		Title:     "Check if the git config of repositories matches the template of [git.repo_config]",
+		Name:      "git-config",
+		IsDefault: false,
+		Run:       checkGitConfig,
+		Priority:  6,
+	})
 	Register(&Check{
 		Title:     "Recalculate Stars number for all user",
 		Name:      "recalculate-stars-number",
@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/repository"
+)
+
+func listAllUnadoptedRepositories() ([]string, error) {
+	const pageSize = 50
+	var repoNames []string
+	for page := 1; ; page++ {
+		names, count, err := repository.ListUnadoptedRepositories("", &db.ListOptions{Page: page, PageSize: pageSize})
+		if err != nil {
+			return nil, err
+		}
+		repoNames = append(repoNames, names...)
+		if len(names) < pageSize || len(repoNames) >= count {
+			return repoNames, nil
+		}
+	}
+}
+
+func checkRepositoryDirs(logger log.Logger, autofix bool) error {
+	repos, err := repository.FindRepositoriesWithMissingGitDir(context.Background())
+	if err != nil {
+		logger.Critical("Unable to find the repositories whose git directory is missing: %v", err)
+		return err
+	}
+	for _, repo := range repos {
+		if !autofix {
+			logger.Warn("Git directory of repository %s is missing: %s", repo.FullName(), repo.RepoPath())
+			continue
+		}
+		if err := repository.ReinitMissingRepository(repo); err != nil {
+			logger.Critical("Unable to recreate the git directory of repository %s: %v", repo.FullName(), err)
+			return err
+		}
+		logger.Info("Recreated the git directory of repository %s as an empty repository, its content has to be pushed again", repo.FullName())
+	}
+
+	// The inverse: git directories without a repository, these can be adopted or deleted by the site admin
+	unadopted, err := listAllUnadoptedRepositories()
+	if err != nil {
+		logger.Critical("Unable to list the unadopted repositories: %v", err)
+		return err
+	}
+	for _, name := range unadopted {
+		logger.Warn("Git directory of %s has no repository, it can be adopted or deleted in the site administration", name)
+	}
+
+	if len(repos) == 0 && len(unadopted) == 0 {
+		logger.Info("All repositories have a git directory and all git directories belong to a repository")
+	} else if !autofix {
+		logger.Warn("%d repositories have lost their git directory and %d git directories are unadopted", len(repos), len(unadopted))
+	}
+	return nil
+}
+
+func init() {
+	Register(&Check{
+		Title:     "Check for repositories whose git directory is missing and for unadopted git directories",
+		Name:      "repository-dirs",
+		IsDefault: false,
+		Run:       checkRepositoryDirs,
+		Priority:  7,
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+// checkName returns a description of the problems with the name, or an empty string if there are none
+func checkName(name string, maxLength int) string {
+	switch {
+	case name != models.NormalizeName(name):
+		return "is not NFC normalized"
+	case maxLength > 0 && utf8.RuneCountInString(name) > maxLength:
+		return fmt.Sprintf("is longer than %d characters", maxLength)
+	}
+	return ""
+}
+
+func checkNames(logger log.Logger, autofix bool) error {
+	var count int
+	if err := db.Iterate(
+		db.DefaultContext,
+		new(models.User),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			u := bean.(*models.User)
+			if problem := checkName(u.Name, setting.Service.MaxUserNameLength); problem != "" {
+				logger.Warn("User name %q (ID: %d) %s", u.Name, u.ID, problem)
+				count++
+			}
+			return nil
+		},
+	); err != nil {
+		logger.Critical("Unable to iterate across users: %v", err)
+		return err
+	}
+
+	if err := iterateRepositories(func(repo *models.Repository) error {
+		if problem := checkName(repo.Name, setting.Repository.MaxNameLength); problem != "" {
+			logger.Warn("Repository name %q (ID: %d, owner: %s) %s", repo.Name, repo.ID, repo.OwnerName, problem)
+			count++
+		}
+		return nil
+	}); err != nil {
+		logger.Critical("Unable to iterate across repositories: %v", err)
+		return err
+	}
+
+	if count > 0 {
+		logger.Warn("%d user or repository names are too long or not normalized and should be renamed", count)
+	} else {
+		logger.Info("All user and repository names are valid")
+	}
+	return nil
+}
+
+func init() {
+	Register(&Check{
+		Title:     "Check for user and repository names that are too long or not normalized",
+		Name:      "check-names",
+		IsDefault: false,
+		Run:       checkNames,
+		Priority:  7,
+	})
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+// isOrphanedRepoObject returns true if no repository references the object of the given storage anymore
+type isOrphanedRepoObject func(p string) (bool, error)
+
+func isOrphanedAttachment(p string) (bool, error) {
+	exist, err := models.ExistAttachmentsByUUID(path.Base(p))
+	return !exist, err
+}
+
+func isOrphanedLFSObject(p string) (bool, error) {
+	exist, err := models.ExistLFSMetaObjectByOid(strings.ReplaceAll(p, "/", ""))
+	return !exist, err
+}
+
+func isOrphanedRepoAvatar(p string) (bool, error) {
+	exist, err := models.ExistRepoAvatar(p)
+	return !exist, err
+}
+
+func isOrphanedRepoArchive(p string) (bool, error) {
+	// Archives are stored as <repo id>/<commit id prefix>/<commit id>.<type>
+	repoID, err := strconv.ParseInt(strings.SplitN(p, "/", 2)[0], 10, 64)
+	if err != nil {
+		return false, nil
+	}
+	if _, err := models.GetRepositoryByID(repoID); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, nil
+}
+
+func findOrphanedRepoObjects(logger log.Logger, storageName string, bucket storage.ObjectStorage, isOrphaned isOrphanedRepoObject) ([]*models.RepoCleanupTask, error) {
+	var tasks []*models.RepoCleanupTask
+	if err := bucket.IterateObjects(func(p string, obj storage.Object) error {
+		defer obj.Close()
+
+		p = filepath.ToSlash(p)
+		orphaned, err := isOrphaned(p)
+		if err != nil {
+			return err
+		}
+		if orphaned {
+			tasks = append(tasks, &models.RepoCleanupTask{Storage: storageName, Path: p})
+		}
+		return nil
+	}); err != nil {
+		logger.Error("Unable to iterate the %s storage: %v", storageName, err)
+		return nil, err
+	}
+	return tasks, nil
+}
+
+func checkOrphanedRepoStorage(logger log.Logger, autofix bool) error {
+	if err := storage.Init(); err != nil {
+		logger.Error("storage.Init failed: %v", err)
+		return err
+	}
+
+	checks := []struct {
+		name       string
+		bucket     storage.ObjectStorage
+		isOrphaned isOrphanedRepoObject
+	}{
+		{models.RepoCleanupStorageAttachments, storage.Attachments, isOrphanedAttachment},
+		{models.RepoCleanupStorageLFS, storage.LFS, isOrphanedLFSObject},
+		{models.RepoCleanupStorageRepoAvatars, storage.RepoAvatars, isOrphanedRepoAvatar},
+		{models.RepoCleanupStorageRepoArchives, storage.RepoArchives, isOrphanedRepoArchive},
+	}
+
+	var orphans []*models.RepoCleanupTask
+	for _, check := range checks {
+		tasks, err := findOrphanedRepoObjects(logger, check.name, check.bucket, check.isOrphaned)
+		if err != nil {
+			return err
+		}
+		if len(tasks) > 0 {
+			logger.Info("%d orphaned files found in the %s storage", len(tasks), check.name)
+		}
+		orphans = append(orphans, tasks...)
+	}
+
+	_, failed, err := models.FindRepoCleanupTasks(models.FindRepoCleanupTasksOptions{Status: models.RepoCleanupTaskFailed})
+	if err != nil {
+		logger.Error("Unable to find the failed repository cleanup tasks: %v", err)
+		return err
+	}
+
+	if !autofix {
+		if len(orphans) > 0 || failed > 0 {
+			logger.Warn("%d orphaned files of deleted repositories found and %d removals have failed", len(orphans), failed)
+		}
+		return nil
+	}
+
+	enqueued, err := models.EnqueueRepoCleanupTasks(orphans)
+	if err != nil {
+		logger.Error("Unable to enqueue the removal of orphaned files: %v", err)
+		return err
+	}
+	retried, err := models.RetryFailedRepoCleanupTasks()
+	if err != nil {
+		logger.Error("Unable to retry the failed repository cleanup tasks: %v", err)
+		return err
+	}
+	if enqueued > 0 || retried > 0 {
+		logger.Info("%d removals of orphaned files enqueued and %d failed removals rescheduled", enqueued, retried)
+	}
+	return nil
+}
+
+func init() {
+	Register(&Check{
+		Title:                      "Enqueue the removal of orphaned files of deleted repositories",
+		Name:                       "orphaned-repo-storage",
+		IsDefault:                  false,
+		Run:                        checkOrphanedRepoStorage,
+		AbortIfFailed:              false,
+		SkipDatabaseInitialization: false,
+		Priority:                   1,
+	})
+}
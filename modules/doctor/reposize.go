@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// repoSizeProgressInterval is the number of repositories after which the progress is logged
+const repoSizeProgressInterval = 100
+
+func checkRepoSizes(logger log.Logger, autofix bool) error {
+	total := models.CountRepositories(true)
+	numRepos := 0
+	numReposWrong := 0
+	err := iterateRepositories(func(repo *models.Repository) error {
+		numRepos++
+		if numRepos%repoSizeProgressInterval == 0 {
+			logger.Info("Checked the sizes of %d / %d repositories", numRepos, total)
+		}
+
+		size, err := repo.ComputeSize()
+		if err != nil {
+			logger.Warn("Unable to calculate the size of %s: %v", repo.FullName(), err)
+			return nil
+		}
+		if size == repo.Size && repo.SizeUpdatedUnix > 0 {
+			return nil
+		}
+		numReposWrong++
+		if !autofix {
+			logger.Info("%s: size should be %d but is %d", repo.FullName(), size, repo.Size)
+			return nil
+		}
+		if err := repo.UpdateSize(db.DefaultContext); err != nil {
+			logger.Critical("Failed to update the size of %s: %v", repo.FullName(), err)
+			return fmt.Errorf("Failed to update the size of %s: %v", repo.FullName(), err)
+		}
+		return nil
+	})
+
+	if autofix {
+		logger.Info("%d repository sizes recalculated of %d repositories", numReposWrong, numRepos)
+	} else if numReposWrong > 0 {
+		logger.Warn("%d repositories with outdated sizes of %d repositories", numReposWrong, numRepos)
+	} else if err == nil {
+		logger.Info("The sizes of all %d repositories are up to date", numRepos)
+	}
+	return err
+}
+
+func init() {
+	Register(&Check{
+		Title:     "Recalculate the sizes of repositories",
+		Name:      "recalculate-repo-sizes",
+		IsDefault: false,
+		Run:       checkRepoSizes,
+		Priority:  7,
+	})
+}
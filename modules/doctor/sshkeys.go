@@ -0,0 +1,62 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package doctor
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+func checkSSHKeyPolicy(logger log.Logger, autofix bool) error {
+	if !setting.SSH.MinimumKeySizeCheck {
+		logger.Info("MINIMUM_KEY_SIZE_CHECK is disabled, all key types and sizes are allowed")
+		return nil
+	}
+
+	var count int
+	if err := db.Iterate(
+		db.DefaultContext,
+		new(models.PublicKey),
+		builder.Neq{"type": models.KeyTypePrincipal},
+		func(idx int, bean interface{}) error {
+			key := bean.(*models.PublicKey)
+			if err := models.CheckPublicKeyPolicy(key.Content); err != nil {
+				if key.Type == models.KeyTypeDeploy {
+					logger.Warn("Deploy key %q (ID: %d, fingerprint: %s): %v", key.Name, key.ID, key.Fingerprint, err)
+				} else {
+					logger.Warn("Key %q (ID: %d, owner ID: %d, fingerprint: %s): %v", key.Name, key.ID, key.OwnerID, key.Fingerprint, err)
+				}
+				count++
+			}
+			return nil
+		},
+	); err != nil {
+		logger.Critical("Unable to iterate across public keys: %v", err)
+		return err
+	}
+
+	if count == 0 {
+		logger.Info("All public keys comply with [ssh.minimum_key_sizes]")
+	} else if setting.SSH.MinimumKeySizeCheckOnAuth {
+		logger.Warn("%d public keys violate [ssh.minimum_key_sizes] and are refused when used to authenticate", count)
+	} else {
+		logger.Warn("%d public keys violate [ssh.minimum_key_sizes] but can still be used, unless MINIMUM_KEY_SIZE_CHECK_ON_AUTH is enabled", count)
+	}
+	return nil
+}
+
+func init() {
+	Register(&Check{
+		Title:     "Check for public keys violating the allowed SSH key types and sizes",
+		Name:      "ssh-key-policy",
+		IsDefault: false,
+		Run:       checkSSHKeyPolicy,
+		Priority:  7,
+	})
+}
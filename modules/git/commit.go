@@ -190,6 +190,59 @@ func (c *Commit) CommitsByRange(page, pageSize int) ([]*Commit, error) {
 	return c.repo.commitsByRange(c.ID, page, pageSize)
 }
 
+// CommitsFilter limits the commits listed before a revision
+type CommitsFilter struct {
+	// Authors are matched as fixed strings against "name <email>" of the author, any of them matches
+	Authors []string
+	// Path limits the commits to the ones touching it
+	Path string
+	// Since and Until are dates understood by git
+	Since, Until string
+}
+
+func (f *CommitsFilter) arguments() []string {
+	args := make([]string, 0, len(f.Authors)+5)
+	if len(f.Authors) > 0 {
+		args = append(args, "--fixed-strings", "--regexp-ignore-case")
+		for _, author := range f.Authors {
+			args = append(args, "--author="+author)
+		}
+	}
+	if len(f.Since) > 0 {
+		args = append(args, "--since="+f.Since)
+	}
+	if len(f.Until) > 0 {
+		args = append(args, "--until="+f.Until)
+	}
+	if len(f.Path) > 0 {
+		args = append(args, "--", f.Path)
+	}
+	return args
+}
+
+// FilteredCommitsCount returns the number of commits before current revision matching the filter
+func (c *Commit) FilteredCommitsCount(filter CommitsFilter) (int64, error) {
+	stdout, err := NewCommand("rev-list", "--count", c.ID.String()).
+		AddArguments(filter.arguments()...).
+		RunInDir(c.repo.Path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+}
+
+// FilteredCommitsByRange returns the specific page of the commits before current revision matching the filter
+func (c *Commit) FilteredCommitsByRange(filter CommitsFilter, page, pageSize int) ([]*Commit, error) {
+	stdout, err := NewCommand("log", c.ID.String(), "--skip="+strconv.Itoa((page-1)*pageSize),
+		"--max-count="+strconv.Itoa(pageSize), prettyLogFormat).
+		AddArguments(filter.arguments()...).
+		RunInDirBytes(c.repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	return c.repo.parsePrettyFormatLogToList(stdout)
+}
+
 // CommitsBefore returns all the commits before current revision
 func (c *Commit) CommitsBefore() ([]*Commit, error) {
 	return c.repo.getCommitsBefore(c.ID)
@@ -20,6 +20,60 @@ func TestCommitsCount(t *testing.T) {
 	assert.Equal(t, int64(3), commitsCount)
 }
 
+func TestFilteredCommits(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	commit, err := repo.GetCommit("feaf4ba6bc635fec442f46ddd4512416ec43c2c2")
+	assert.NoError(t, err)
+
+	kases := []struct {
+		filter CommitsFilter
+		ids    []string
+	}{
+		{CommitsFilter{Authors: []string{"<TRIS.git@shoddynet.org>"}}, []string{
+			"37991dec2c8e592043f47155ce4808d4580f9123",
+			"6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1",
+			"8006ff9adbf0cb94da7dad9e537e53817f9fa5c0",
+		}},
+		{CommitsFilter{Authors: []string{"<me@silverwind.io>", "Example User"}}, []string{
+			"feaf4ba6bc635fec442f46ddd4512416ec43c2c2",
+			"8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2",
+			"95bb4d39648ee7e325106df01a621c530863a653",
+		}},
+		{CommitsFilter{Path: "foo"}, []string{
+			"37991dec2c8e592043f47155ce4808d4580f9123",
+			"6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1",
+			"8006ff9adbf0cb94da7dad9e537e53817f9fa5c0",
+		}},
+		{CommitsFilter{Path: "foo", Since: "2018-04-18T04:10:00Z", Until: "2018-04-19T00:00:00Z"}, []string{
+			"6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1",
+		}},
+		{CommitsFilter{Authors: []string{"<tris.git@shoddynet.org>"}, Path: "file1.txt"}, []string{}},
+	}
+	for _, kase := range kases {
+		count, err := commit.FilteredCommitsCount(kase.filter)
+		assert.NoError(t, err)
+		assert.EqualValues(t, len(kase.ids), count, kase.filter)
+
+		commits, err := commit.FilteredCommitsByRange(kase.filter, 1, 10)
+		assert.NoError(t, err)
+		ids := make([]string, 0, len(commits))
+		for _, c := range commits {
+			ids = append(ids, c.ID.String())
+		}
+		assert.Equal(t, kase.ids, ids, kase.filter)
+	}
+
+	commits, err := commit.FilteredCommitsByRange(CommitsFilter{Path: "foo"}, 2, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, commits, 1) {
+		assert.Equal(t, "8006ff9adbf0cb94da7dad9e537e53817f9fa5c0", commits[0].ID.String())
+	}
+}
+
 func TestGetFullCommitID(t *testing.T) {
 	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
 
@@ -186,6 +186,13 @@ func Init(ctx context.Context) error {
 		}
 	}
 
+	if CheckGitVersionAtLeast("2.22") == nil {
+		// allow partial clones
+		if err := checkAndSetConfig("uploadpack.allowFilter", "true", true); err != nil {
+			return err
+		}
+	}
+
 	if CheckGitVersionAtLeast("2.29") == nil {
 		// set support for AGit flow
 		if err := checkAndAddConfig("receive.procReceiveRefs", "refs/for"); err != nil {
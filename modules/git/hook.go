@@ -100,10 +100,25 @@ func (h *Hook) Update() error {
 	if err != nil {
 		return err
 	}
+	// an existing file keeps its mode, git skips hooks it cannot execute
+	if err := ensureExecutable(h.path); err != nil {
+		return err
+	}
 	h.IsActive = true
 	return nil
 }
 
+func ensureExecutable(filename string) error {
+	fileInfo, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+	if (fileInfo.Mode() & 0100) > 0 {
+		return nil
+	}
+	return os.Chmod(filename, fileInfo.Mode()|0100)
+}
+
 // ListHooks returns a list of Git hooks of given repository.
 func ListHooks(repoPath string) (_ []*Hook, err error) {
 	if !isDir(path.Join(repoPath, "hooks")) {
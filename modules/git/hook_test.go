@@ -0,0 +1,44 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHook_Update(t *testing.T) {
+	repoPath := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(repoPath, "hooks", "pre-receive.d"), os.ModePerm))
+
+	// an existing custom hook which is not executable
+	hookPath := filepath.Join(repoPath, "hooks", "pre-receive.d", "pre-receive")
+	assert.NoError(t, os.WriteFile(hookPath, []byte("#!/bin/sh\n"), 0644))
+
+	hook, err := GetHook(repoPath, "pre-receive")
+	assert.NoError(t, err)
+	assert.True(t, hook.IsActive)
+
+	hook.Content = "#!/bin/sh\r\nexit 1\r\n"
+	assert.NoError(t, hook.Update())
+	data, err := os.ReadFile(hookPath)
+	assert.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\nexit 1\n", string(data))
+	fileInfo, err := os.Stat(hookPath)
+	assert.NoError(t, err)
+	assert.NotZero(t, fileInfo.Mode()&0100)
+
+	hook.Content = " "
+	assert.NoError(t, hook.Update())
+	assert.False(t, hook.IsActive)
+	assert.NoFileExists(t, hookPath)
+
+	_, err = GetHook(repoPath, "post-update")
+	assert.Equal(t, ErrNotValidHook, err)
+	assert.NoFileExists(t, filepath.Join(repoPath, "hooks", "post-update.d", "post-update"))
+}
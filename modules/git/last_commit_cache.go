@@ -7,10 +7,26 @@ package git
 import (
 	"crypto/sha256"
 	"fmt"
+	"sync/atomic"
 
 	"code.gitea.io/gitea/modules/log"
 )
 
+var lastCommitCacheHits, lastCommitCacheMisses int64
+
+// LastCommitCacheStats returns how many lookups of last commits hit and missed the cache since start
+func LastCommitCacheStats() (hits, misses int64) {
+	return atomic.LoadInt64(&lastCommitCacheHits), atomic.LoadInt64(&lastCommitCacheMisses)
+}
+
+func countLastCommitCacheLookup(hit bool) {
+	if hit {
+		atomic.AddInt64(&lastCommitCacheHits, 1)
+	} else {
+		atomic.AddInt64(&lastCommitCacheMisses, 1)
+	}
+}
+
 // Cache represents a caching interface
 type Cache interface {
 	// Put puts value into cache with key and expire time.
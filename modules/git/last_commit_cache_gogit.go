@@ -42,7 +42,9 @@ func NewLastCommitCache(repoPath string, gitRepo *Repository, ttl func() int64,
 // Get get the last commit information by commit id and entry path
 func (c *LastCommitCache) Get(ref, entryPath string) (interface{}, error) {
 	v := c.cache.Get(c.getCacheKey(c.repoPath, ref, entryPath))
-	if vs, ok := v.(string); ok {
+	vs, ok := v.(string)
+	countLastCommitCacheLookup(ok)
+	if ok {
 		log.Debug("LastCommitCache hit level 1: [%s:%s:%s]", ref, entryPath, vs)
 		if commit, ok := c.commitCache[vs]; ok {
 			log.Debug("LastCommitCache hit level 2: [%s:%s:%s]", ref, entryPath, vs)
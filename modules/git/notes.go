@@ -4,6 +4,18 @@
 
 package git
 
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
 // NotesRef is the git ref where Gitea will look for git-notes data.
 // The value ("refs/notes/commits") is the default ref used by git-notes.
 const NotesRef = "refs/notes/commits"
@@ -13,3 +25,66 @@ type Note struct {
 	Message []byte
 	Commit  *Commit
 }
+
+// notesUpdateRetries is the number of times a note update is retried when
+// another process holds the lock on NotesRef.
+const notesUpdateRetries = 5
+
+// SetNote creates or overwrites the git-notes data for a given commit,
+// recording the change on NotesRef with the provided signature.
+func SetNote(ctx context.Context, repo *Repository, commitID string, message []byte, sig *Signature) error {
+	log.Trace("Setting git note for the commit %q in the repository %q", commitID, repo.Path)
+	return updateNote(ctx, repo, commitID, sig, bytes.NewReader(message), "notes", "--ref", NotesRef, "add", "-f", "--allow-empty", "-F", "-", commitID)
+}
+
+// RemoveNote removes the git-notes data for a given commit,
+// recording the change on NotesRef with the provided signature.
+func RemoveNote(ctx context.Context, repo *Repository, commitID string, sig *Signature) error {
+	log.Trace("Removing git note for the commit %q in the repository %q", commitID, repo.Path)
+	return updateNote(ctx, repo, commitID, sig, nil, "notes", "--ref", NotesRef, "remove", commitID)
+}
+
+func updateNote(ctx context.Context, repo *Repository, commitID string, sig *Signature, stdin io.ReadSeeker, args ...string) error {
+	commitTimeStr := time.Now().Format(time.RFC3339)
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME="+sig.Name,
+		"GIT_AUTHOR_EMAIL="+sig.Email,
+		"GIT_AUTHOR_DATE="+commitTimeStr,
+		"GIT_COMMITTER_NAME="+sig.Name,
+		"GIT_COMMITTER_EMAIL="+sig.Email,
+		"GIT_COMMITTER_DATE="+commitTimeStr,
+	)
+
+	var err error
+	for i := 0; i < notesUpdateRetries; i++ {
+		if stdin != nil {
+			if _, err = stdin.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+		}
+		stderr := new(strings.Builder)
+		err = NewCommandContext(ctx, args...).
+			SetDescription(fmt.Sprintf("updateNote: %s", repo.Path)).
+			RunInDirTimeoutEnvFullPipeline(env, -1, repo.Path, nil, stderr, stdin)
+		if err == nil {
+			return nil
+		}
+
+		errMsg := stderr.String()
+		switch {
+		case strings.Contains(errMsg, "has no note"), strings.Contains(errMsg, "Failed to resolve"):
+			return ErrNotExist{ID: commitID}
+		case strings.Contains(errMsg, "cannot lock ref"), strings.Contains(errMsg, "but expected"):
+			// Another process updated NotesRef concurrently, try again on top of the new value
+			log.Debug("Concurrent update of %q in %q, retrying: %s", NotesRef, repo.Path, errMsg)
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(time.Duration(i+1) * 50 * time.Millisecond):
+			}
+			continue
+		}
+		return ConcatenateError(err, errMsg)
+	}
+	return fmt.Errorf("unable to update %s after %d attempts: %w", NotesRef, notesUpdateRetries, err)
+}
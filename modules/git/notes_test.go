@@ -9,6 +9,8 @@ import (
 	"path/filepath"
 	"testing"
 
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,3 +53,30 @@ func TestGetNonExistentNotes(t *testing.T) {
 	assert.Error(t, err)
 	assert.IsType(t, ErrNotExist{}, err)
 }
+
+func TestSetAndRemoveNote(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	clonedPath, err := cloneRepo(bareRepo1Path, testReposDir, "repo1_TestSetAndRemoveNote")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+
+	repo, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	sig := &Signature{Name: "Gitea", Email: "gitea@fake.local"}
+	commitID := "95bb4d39648ee7e325106df01a621c530863a653"
+
+	assert.NoError(t, SetNote(context.Background(), repo, commitID, []byte("First note\n"), sig))
+	assert.NoError(t, SetNote(context.Background(), repo, commitID, []byte("Second note\n"), sig))
+
+	note := Note{}
+	assert.NoError(t, GetNote(context.Background(), repo, commitID, &note))
+	assert.Equal(t, []byte("Second note\n"), note.Message)
+	assert.Equal(t, "Gitea", note.Commit.Author.Name)
+
+	assert.NoError(t, RemoveNote(context.Background(), repo, commitID, sig))
+	err = RemoveNote(context.Background(), repo, commitID, sig)
+	assert.Error(t, err)
+	assert.IsType(t, ErrNotExist{}, err)
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ObjectFormat represents the hash algorithm used to name the objects of a repository
+type ObjectFormat string
+
+// enumerates all the supported object formats
+const (
+	ObjectFormatSHA1   ObjectFormat = "sha1"
+	ObjectFormatSHA256 ObjectFormat = "sha256"
+)
+
+// EmptySHA256 defines empty git SHA of a repository using the sha256 object format
+const EmptySHA256 = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// ObjectFormatSHA256MinGitVersion is the minimal git version which can create sha256 repositories
+const ObjectFormatSHA256MinGitVersion = "2.29"
+
+var fullCommitIDPattern = regexp.MustCompile(`^(?:[0-9a-f]{40}|[0-9a-f]{64})$`)
+
+// ToObjectFormat returns the object format named s, an empty name is the default sha1 format
+func ToObjectFormat(s string) (ObjectFormat, error) {
+	switch ObjectFormat(strings.ToLower(strings.TrimSpace(s))) {
+	case "", ObjectFormatSHA1:
+		return ObjectFormatSHA1, nil
+	case ObjectFormatSHA256:
+		return ObjectFormatSHA256, nil
+	}
+	return "", fmt.Errorf("unknown object format: %s", s)
+}
+
+// Name returns the name of the object format, repositories without known format use sha1
+func (f ObjectFormat) Name() string {
+	if f == "" {
+		return string(ObjectFormatSHA1)
+	}
+	return string(f)
+}
+
+// FullLength returns the length of a full hex object ID of the format
+func (f ObjectFormat) FullLength() int {
+	if f == ObjectFormatSHA256 {
+		return 64
+	}
+	return 40
+}
+
+// EmptyID returns the all zero ID which git uses for missing objects, e.g. the old ID of a created ref
+func (f ObjectFormat) EmptyID() string {
+	if f == ObjectFormatSHA256 {
+		return EmptySHA256
+	}
+	return EmptySHA
+}
+
+// IsEmptyCommitID returns true if id is empty or the all zero ID of any object format
+func IsEmptyCommitID(id string) bool {
+	return id == "" || id == EmptySHA || id == EmptySHA256
+}
+
+// IsFullCommitID returns true if id is a full hex object ID of any object format
+func IsFullCommitID(id string) bool {
+	return fullCommitIDPattern.MatchString(id)
+}
+
+// InitRepositoryWithObjectFormat initializes a new Git repository using the given object format,
+// the default sha1 format is used if it is empty.
+func InitRepositoryWithObjectFormat(repoPath string, bare bool, objectFormat ObjectFormat) error {
+	if err := os.MkdirAll(repoPath, os.ModePerm); err != nil {
+		return err
+	}
+
+	cmd := NewCommand("init")
+	if bare {
+		cmd.AddArguments("--bare")
+	}
+	// Only pass the format when needed as older git versions don't know the option
+	if objectFormat == ObjectFormatSHA256 {
+		if err := CheckGitVersionAtLeast(ObjectFormatSHA256MinGitVersion); err != nil {
+			return err
+		}
+		cmd.AddArguments("--object-format=" + string(objectFormat))
+	}
+	_, err := cmd.RunInDir(repoPath)
+	return err
+}
+
+// GetObjectFormatOfRepo returns the object format of the repository at repoPath
+func GetObjectFormatOfRepo(repoPath string) (ObjectFormat, error) {
+	if CheckGitVersionAtLeast(ObjectFormatSHA256MinGitVersion) != nil {
+		// Older git versions can only handle sha1 repositories
+		return ObjectFormatSHA1, nil
+	}
+	stdout, err := NewCommand("rev-parse", "--show-object-format").RunInDir(repoPath)
+	if err != nil {
+		return "", err
+	}
+	return ToObjectFormat(stdout)
+}
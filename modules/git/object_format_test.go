@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObjectFormatHelpers(t *testing.T) {
+	for _, name := range []string{"", "sha1", "SHA1"} {
+		f, err := ToObjectFormat(name)
+		assert.NoError(t, err)
+		assert.Equal(t, ObjectFormatSHA1, f)
+	}
+	f, err := ToObjectFormat("sha256")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectFormatSHA256, f)
+	_, err = ToObjectFormat("md5")
+	assert.Error(t, err)
+
+	assert.Equal(t, "sha1", ObjectFormat("").Name())
+	assert.Equal(t, 40, ObjectFormatSHA1.FullLength())
+	assert.Equal(t, 64, ObjectFormatSHA256.FullLength())
+	assert.Len(t, ObjectFormatSHA1.EmptyID(), 40)
+	assert.Len(t, ObjectFormatSHA256.EmptyID(), 64)
+
+	assert.True(t, IsEmptyCommitID(""))
+	assert.True(t, IsEmptyCommitID(EmptySHA))
+	assert.True(t, IsEmptyCommitID(EmptySHA256))
+	assert.False(t, IsEmptyCommitID("2c54faec6c45d31c1abfaecdab471eac6633738a"))
+
+	assert.True(t, IsFullCommitID("2c54faec6c45d31c1abfaecdab471eac6633738a"))
+	assert.True(t, IsFullCommitID("6a1e3a5c1d2b4f0e2f3a8f9c0d6b7e5a4c3b2a19181716151413121110090807"))
+	assert.False(t, IsFullCommitID("2c54faec"))
+	assert.False(t, IsFullCommitID("2c54faec6c45d31c1abfaecdab471eac6633738a00"))
+}
+
+func TestInitRepositoryWithObjectFormat(t *testing.T) {
+	if CheckGitVersionAtLeast(ObjectFormatSHA256MinGitVersion) != nil {
+		t.Skip("git is too old to create sha256 repositories")
+	}
+
+	tmpDir := t.TempDir()
+	for _, f := range []ObjectFormat{ObjectFormatSHA1, ObjectFormatSHA256} {
+		repoPath := filepath.Join(tmpDir, f.Name()+".git")
+		assert.NoError(t, InitRepositoryWithObjectFormat(repoPath, true, f))
+		format, err := GetObjectFormatOfRepo(repoPath)
+		assert.NoError(t, err)
+		assert.Equal(t, f, format)
+	}
+}
@@ -64,17 +64,7 @@ func IsRepoURLAccessible(url string) bool {
 
 // InitRepository initializes a new Git repository.
 func InitRepository(repoPath string, bare bool) error {
-	err := os.MkdirAll(repoPath, os.ModePerm)
-	if err != nil {
-		return err
-	}
-
-	cmd := NewCommand("init")
-	if bare {
-		cmd.AddArguments("--bare")
-	}
-	_, err = cmd.RunInDir(repoPath)
-	return err
+	return InitRepositoryWithObjectFormat(repoPath, bare, "")
 }
 
 // IsEmpty Check if repository is empty.
@@ -111,6 +111,23 @@ func GetBranchesByPath(path string, skip, limit int) ([]*Branch, int, error) {
 	return branches, countAll, nil
 }
 
+// GetMergedBranches returns the names of all branches whose tip is reachable from the given branch,
+// including the branch itself
+func (repo *Repository) GetMergedBranches(target string) ([]string, error) {
+	stdout, err := NewCommand("for-each-ref", "--merged="+BranchPrefix+target, "--format=%(refname)", BranchPrefix).RunInDir(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	branches := make([]string, 0, 10)
+	for _, line := range strings.Split(stdout, "\n") {
+		if strings.HasPrefix(line, BranchPrefix) {
+			branches = append(branches, strings.TrimPrefix(line, BranchPrefix))
+		}
+	}
+	return branches, nil
+}
+
 // DeleteBranchOptions Option(s) for delete branch
 type DeleteBranchOptions struct {
 	Force bool
@@ -39,6 +39,21 @@ func TestRepository_GetBranches(t *testing.T) {
 	assert.ElementsMatch(t, []string{}, branches)
 }
 
+func TestRepository_GetMergedBranches(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	bareRepo1, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	branches, err := bareRepo1.GetMergedBranches("master")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"master"}, branches)
+
+	branches, err = bareRepo1.GetMergedBranches("branch2")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"branch2"}, branches)
+}
+
 func BenchmarkRepository_GetBranches(b *testing.B) {
 	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
 	bareRepo1, err := OpenRepository(bareRepo1Path)
@@ -157,6 +157,40 @@ func (repo *Repository) GetDiffNumChangedFiles(base, head string, directComparis
 	return w.numLines, nil
 }
 
+// GetChangedFiles returns the names of the files changed on head since its merge base with base
+func (repo *Repository) GetChangedFiles(base, head string) ([]string, error) {
+	stdout, err := NewCommand("diff", "-z", "--name-only", base+"..."+head).RunInDirBytes(repo.Path)
+	if err != nil && strings.Contains(err.Error(), "no merge base") {
+		// git >= 2.28 now returns an error if base and head have become unrelated.
+		stdout, err = NewCommand("diff", "-z", "--name-only", base, head).RunInDirBytes(repo.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(string(stdout), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// GetFilesChangedBetween returns the names of the files changed between two commits
+func (repo *Repository) GetFilesChangedBetween(base, head string) ([]string, error) {
+	stdout, err := NewCommand("diff", "-z", "--name-only", base+".."+head).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, file := range strings.Split(string(stdout), "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
 // GetDiffShortStat counts number of changed files, number of additions and deletions
 func (repo *Repository) GetDiffShortStat(base, head string) (numFiles, totalAdditions, totalDeletions int, err error) {
 	numFiles, totalAdditions, totalDeletions, err = GetDiffShortStat(repo.Path, base+"..."+head)
@@ -253,6 +287,100 @@ func (repo *Repository) GetPatch(base, head string, w io.Writer) error {
 	return err
 }
 
+// DiffFileStat represents the changes made to a single file between two revisions
+type DiffFileStat struct {
+	Name      string
+	OldName   string
+	Status    string
+	Additions int
+	Deletions int
+	IsBinary  bool
+}
+
+var diffFileStatuses = map[byte]string{
+	'A': "added",
+	'C': "copied",
+	'D': "deleted",
+	'M': "modified",
+	'R': "renamed",
+	'T': "changed",
+}
+
+// GetDiffFileStats returns the changed files between base and head, optionally limited to the given paths
+func (repo *Repository) GetDiffFileStats(base, head string, paths ...string) ([]*DiffFileStat, error) {
+	args := []string{"diff", "-z", "-M", "--name-status", base, head, "--"}
+	stdout, err := NewCommand(append(args, paths...)...).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]*DiffFileStat, 0, 10)
+	fields := bytes.Split(bytes.TrimSuffix(stdout, []byte{'\x00'}), []byte{'\x00'})
+	for i := 0; i < len(fields) && len(fields[i]) > 0; i++ {
+		status := fields[i]
+		stat := &DiffFileStat{Status: diffFileStatuses[status[0]]}
+		if stat.Status == "" {
+			stat.Status = "modified"
+		}
+		if status[0] == 'R' || status[0] == 'C' {
+			if i+2 >= len(fields) {
+				return nil, fmt.Errorf("unexpected output of diff --name-status: %q", stdout)
+			}
+			stat.OldName = string(fields[i+1])
+			stat.Name = string(fields[i+2])
+			i += 2
+		} else {
+			if i+1 >= len(fields) {
+				return nil, fmt.Errorf("unexpected output of diff --name-status: %q", stdout)
+			}
+			stat.Name = string(fields[i+1])
+			i++
+		}
+		stats = append(stats, stat)
+	}
+
+	// --numstat lists the files in the same order as --name-status
+	args = []string{"diff", "-z", "-M", "--numstat", base, head, "--"}
+	stdout, err = NewCommand(append(args, paths...)...).RunInDirBytes(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	fields = bytes.Split(bytes.TrimSuffix(stdout, []byte{'\x00'}), []byte{'\x00'})
+	idx := 0
+	for i := 0; i < len(fields) && idx < len(stats); i++ {
+		parts := bytes.SplitN(fields[i], []byte{'\t'}, 3)
+		if len(parts) != 3 {
+			return nil, fmt.Errorf("unexpected output of diff --numstat: %q", stdout)
+		}
+		if len(parts[2]) == 0 {
+			// renames and copies are followed by the old and the new name
+			i += 2
+		}
+
+		stat := stats[idx]
+		idx++
+		if string(parts[0]) == "-" {
+			stat.IsBinary = true
+			continue
+		}
+		if stat.Additions, err = strconv.Atoi(string(parts[0])); err != nil {
+			return nil, fmt.Errorf("unable to parse additions %q: %v", parts[0], err)
+		}
+		if stat.Deletions, err = strconv.Atoi(string(parts[1])); err != nil {
+			return nil, fmt.Errorf("unable to parse deletions %q: %v", parts[1], err)
+		}
+	}
+	return stats, nil
+}
+
+// GetDiffForPaths generates and returns patch data between given revisions limited to the given paths
+func (repo *Repository) GetDiffForPaths(base, head string, w io.Writer, paths ...string) error {
+	args := []string{"diff", "-p", "-M", base, head, "--"}
+	return NewCommand(append(args, paths...)...).
+		RunInDirPipeline(repo.Path, w, nil)
+}
+
 // GetDiffFromMergeBase generates and return patch data from merge base to head
 func (repo *Repository) GetDiffFromMergeBase(base, head string, w io.Writer) error {
 	stderr := new(bytes.Buffer)
@@ -263,3 +391,29 @@ func (repo *Repository) GetDiffFromMergeBase(base, head string, w io.Writer) err
 	}
 	return err
 }
+
+// GetPatchID returns the stable patch-id of the diff from the merge base of base and head to head,
+// or an empty string if there is no difference. Diffs which only differ in line numbers have the same
+// patch-id, so it stays the same when head is rebased without changing its content.
+// Git before 2.39 also ignores whitespace changes.
+func (repo *Repository) GetPatchID(base, head string) (string, error) {
+	diff := new(bytes.Buffer)
+	if err := repo.GetDiffFromMergeBase(base, head, diff); err != nil {
+		return "", err
+	}
+
+	mode := "--stable"
+	if CheckGitVersionAtLeast("2.39") == nil {
+		mode = "--verbatim"
+	}
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if err := NewCommand("patch-id", mode).RunInDirFullPipeline(repo.Path, stdout, stderr, diff); err != nil {
+		return "", ConcatenateError(err, stderr.String())
+	}
+	fields := strings.Fields(stdout.String())
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
@@ -31,3 +31,94 @@ func TestGetFormatPatch(t *testing.T) {
 	assert.Regexp(t, "^From 8d92fc95", patch)
 	assert.Contains(t, patch, "Subject: [PATCH] Add file2.txt")
 }
+
+func TestGetDiffFileStats(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	stats, err := repo.GetDiffFileStats("8d92fc95^", "8d92fc95")
+	assert.NoError(t, err)
+	if assert.Len(t, stats, 1) {
+		assert.Equal(t, "file2.txt", stats[0].Name)
+		assert.Equal(t, "added", stats[0].Status)
+		assert.Equal(t, 1, stats[0].Additions)
+		assert.Equal(t, 0, stats[0].Deletions)
+	}
+
+	stats, err = repo.GetDiffFileStats("8d92fc95^", "8d92fc95", "nonexistent.txt")
+	assert.NoError(t, err)
+	assert.Empty(t, stats)
+
+	var patch bytes.Buffer
+	assert.NoError(t, repo.GetDiffForPaths("8d92fc95^", "8d92fc95", &patch, "file2.txt"))
+	assert.Contains(t, patch.String(), "+++ b/file2.txt")
+}
+
+func TestGetChangedFiles(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	files, err := repo.GetChangedFiles("8d92fc95^", "8d92fc95")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"file2.txt"}, files)
+
+	files, err = repo.GetChangedFiles("8d92fc95", "8d92fc95")
+	assert.NoError(t, err)
+	assert.Empty(t, files)
+}
+
+func TestGetFilesChangedBetween(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	repo, err := OpenRepository(bareRepo1Path)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	files, err := repo.GetFilesChangedBetween("8d92fc95^", "8d92fc95")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"file2.txt"}, files)
+
+	// unlike GetChangedFiles the commits are compared directly
+	files, err = repo.GetFilesChangedBetween("8d92fc95", "8d92fc95^")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"file2.txt"}, files)
+}
+
+func TestGetPatchID(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	clonedPath, err := cloneRepo(bareRepo1Path, testReposDir, "repo1_TestGetPatchID")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+	repo, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer repo.Close()
+
+	// branch2 adds branch2/branch2.txt on top of 8d92fc95
+	patchID, err := repo.GetPatchID("feaf4ba6", "5c80b0245c1c6f8343fa418ec374b13b5d4ee658")
+	assert.NoError(t, err)
+	assert.Len(t, patchID, 40)
+
+	// the same change rebased onto master keeps its patch-id
+	env := []string{"GIT_AUTHOR_NAME=Gitea", "GIT_AUTHOR_EMAIL=gitea@example.com", "GIT_COMMITTER_NAME=Gitea", "GIT_COMMITTER_EMAIL=gitea@example.com"}
+	_, err = NewCommand("checkout", "-b", "rebased", "feaf4ba6").RunInDir(clonedPath)
+	assert.NoError(t, err)
+	_, err = NewCommand("cherry-pick", "5c80b0245c1c6f8343fa418ec374b13b5d4ee658").RunInDirWithEnv(clonedPath, env)
+	assert.NoError(t, err)
+	rebasedPatchID, err := repo.GetPatchID("feaf4ba6", "rebased")
+	assert.NoError(t, err)
+	assert.Equal(t, patchID, rebasedPatchID)
+
+	// a different change has a different patch-id
+	otherPatchID, err := repo.GetPatchID("feaf4ba6", "2839944139e0de9737a044f78b0e4b40d989a9e3")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, otherPatchID)
+	assert.NotEqual(t, patchID, otherPatchID)
+
+	// no change has no patch-id
+	emptyPatchID, err := repo.GetPatchID("feaf4ba6", "8d92fc957a4d7cfd98bc375f0b7bb189a0d6c9f2")
+	assert.NoError(t, err)
+	assert.Empty(t, emptyPatchID)
+}
@@ -0,0 +1,45 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+// isExitCode checks if err is caused by a git command exiting with code
+func isExitCode(err error, code int) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == code
+}
+
+// GetConfigValues returns all the values of a key in a git config file, none if the key isn't set
+func GetConfigValues(ctx context.Context, configPath, key string) ([]string, error) {
+	stdout, err := NewCommandContext(ctx, "config", "--file", configPath, "--get-all", key).Run()
+	if err != nil {
+		if isExitCode(err, 1) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return strings.Split(strings.TrimRight(stdout, "\n"), "\n"), nil
+}
+
+// SetConfigValue sets a key in a git config file to a single value, replacing all the values it had
+func SetConfigValue(ctx context.Context, configPath, key, value string) error {
+	_, err := NewCommandContext(ctx, "config", "--file", configPath, "--replace-all", key, value).Run()
+	return err
+}
+
+// UnsetConfigValues removes all the values of a key from a git config file, it is not an error if the key isn't set
+func UnsetConfigValues(ctx context.Context, configPath, key string) error {
+	_, err := NewCommandContext(ctx, "config", "--file", configPath, "--unset-all", key).Run()
+	if err != nil && isExitCode(err, 5) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigValues(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config")
+	ctx := context.Background()
+
+	values, err := GetConfigValues(ctx, configPath, "transfer.hideRefs")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+	assert.NoError(t, UnsetConfigValues(ctx, configPath, "transfer.hideRefs"))
+
+	_, err = NewCommand("config", "--file", configPath, "--add", "transfer.hideRefs", "refs/pull").Run()
+	assert.NoError(t, err)
+	_, err = NewCommand("config", "--file", configPath, "--add", "transfer.hideRefs", "refs/keep-around").Run()
+	assert.NoError(t, err)
+	values, err = GetConfigValues(ctx, configPath, "transfer.hideRefs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"refs/pull", "refs/keep-around"}, values)
+
+	assert.NoError(t, SetConfigValue(ctx, configPath, "transfer.hideRefs", "refs/heads/secret"))
+	values, err = GetConfigValues(ctx, configPath, "transfer.hideRefs")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"refs/heads/secret"}, values)
+
+	assert.NoError(t, UnsetConfigValues(ctx, configPath, "transfer.hideRefs"))
+	values, err = GetConfigValues(ctx, configPath, "transfer.hideRefs")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
@@ -160,36 +160,57 @@ func (repo *Repository) GetTag(name string) (*Tag, error) {
 	return tag, nil
 }
 
-// GetTagInfos returns all tag infos of the repository.
-func (repo *Repository) GetTagInfos(page, pageSize int) ([]*Tag, int, error) {
-	// TODO this a slow implementation, makes one git command per tag
-	stdout, err := NewCommand("tag").RunInDir(repo.Path)
+// TagSortType represents the order in which tags are listed
+type TagSortType string
+
+const (
+	// TagSortDate lists the newest tags first, by the date of the tag or of the commit of lightweight tags
+	TagSortDate TagSortType = "date"
+	// TagSortSemver lists the highest versions first, pre-releases come before their release
+	TagSortSemver TagSortType = "semver"
+)
+
+// GetTagInfos returns the tags of the repository on the given page in the given order, all tags if page is 0.
+// Only the tags of the page are loaded, the rest of the tags are only listed by git.
+func (repo *Repository) GetTagInfos(page, pageSize int, sortType TagSortType) ([]*Tag, int, error) {
+	var cmd *Command
+	switch sortType {
+	case TagSortSemver:
+		cmd = NewCommand("-c", "versionsort.suffix=-", "for-each-ref", "--sort=-v:refname")
+	default:
+		cmd = NewCommand("for-each-ref", "--sort=-creatordate")
+	}
+	stdout, err := cmd.AddArguments("--format=%(objectname) %(refname:strip=2)", TagPrefix).RunInDir(repo.Path)
 	if err != nil {
 		return nil, 0, err
 	}
 
-	tagNames := strings.Split(strings.TrimRight(stdout, "\n"), "\n")
-	tagsTotal := len(tagNames)
+	refs := strings.Split(strings.TrimRight(stdout, "\n"), "\n")
+	if len(refs) == 1 && refs[0] == "" {
+		refs = refs[:0]
+	}
+	tagsTotal := len(refs)
 
 	if page != 0 {
-		tagNames = util.PaginateSlice(tagNames, page, pageSize).([]string)
+		refs = util.PaginateSlice(refs, page, pageSize).([]string)
 	}
 
-	var tags = make([]*Tag, 0, len(tagNames))
-	for _, tagName := range tagNames {
-		tagName = strings.TrimSpace(tagName)
-		if len(tagName) == 0 {
-			continue
+	var tags = make([]*Tag, 0, len(refs))
+	for _, ref := range refs {
+		fields := strings.SplitN(ref, " ", 2)
+		if len(fields) != 2 {
+			return nil, tagsTotal, fmt.Errorf("unexpected tag ref: %q", ref)
 		}
-
-		tag, err := repo.GetTag(tagName)
+		id, err := NewIDFromString(fields[0])
+		if err != nil {
+			return nil, tagsTotal, err
+		}
+		tag, err := repo.getTag(id, fields[1])
 		if err != nil {
 			return nil, tagsTotal, err
 		}
-		tag.Name = tagName
 		tags = append(tags, tag)
 	}
-	sortTagsByTime(tags)
 	return tags, tagsTotal, nil
 }
 
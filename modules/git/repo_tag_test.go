@@ -18,7 +18,7 @@ func TestRepository_GetTags(t *testing.T) {
 	assert.NoError(t, err)
 	defer bareRepo1.Close()
 
-	tags, total, err := bareRepo1.GetTagInfos(0, 0)
+	tags, total, err := bareRepo1.GetTagInfos(0, 0, TagSortDate)
 	assert.NoError(t, err)
 	assert.Len(t, tags, 1)
 	assert.Equal(t, len(tags), total)
@@ -27,6 +27,44 @@ func TestRepository_GetTags(t *testing.T) {
 	assert.EqualValues(t, "tag", tags[0].Type)
 }
 
+func TestRepository_GetTagInfosSorted(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+
+	clonedPath, err := cloneRepo(bareRepo1Path, testReposDir, "repo1_TestRepository_GetTagInfosSorted")
+	assert.NoError(t, err)
+	defer util.RemoveAll(clonedPath)
+
+	bareRepo1, err := OpenRepository(clonedPath)
+	assert.NoError(t, err)
+	defer bareRepo1.Close()
+
+	for _, name := range []string{"v1.2.0", "v1.10.0", "v1.10.0-rc1", "v1.9.1"} {
+		assert.NoError(t, bareRepo1.CreateTag(name, "6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1"))
+	}
+
+	tags, total, err := bareRepo1.GetTagInfos(1, 2, TagSortSemver)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, total)
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "v1.10.0", tags[0].Name)
+		assert.Equal(t, "v1.10.0-rc1", tags[1].Name)
+	}
+
+	tags, _, err = bareRepo1.GetTagInfos(2, 2, TagSortSemver)
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 2) {
+		assert.Equal(t, "v1.9.1", tags[0].Name)
+		assert.Equal(t, "v1.2.0", tags[1].Name)
+	}
+
+	// the annotated tag "test" is the newest tag
+	tags, _, err = bareRepo1.GetTagInfos(1, 1, TagSortDate)
+	assert.NoError(t, err)
+	if assert.Len(t, tags, 1) {
+		assert.Equal(t, "test", tags[0].Name)
+	}
+}
+
 func TestRepository_GetTag(t *testing.T) {
 	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
 
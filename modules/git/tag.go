@@ -6,7 +6,6 @@ package git
 
 import (
 	"bytes"
-	"sort"
 	"strings"
 )
 
@@ -30,6 +29,14 @@ func (tag *Tag) Commit() (*Commit, error) {
 	return tag.repo.getCommit(tag.Object)
 }
 
+// GetRepositoryDefaultPublicGPGKey returns the default public key for this tag
+func (tag *Tag) GetRepositoryDefaultPublicGPGKey(forceUpdate bool) (*GPGSettings, error) {
+	if tag.repo == nil {
+		return nil, nil
+	}
+	return tag.repo.GetDefaultPublicGPGKey(forceUpdate)
+}
+
 // Parse commit information from the (uncompressed) raw
 // data from the commit object.
 // \n\n separate headers from message
@@ -84,23 +91,3 @@ l:
 	}
 	return tag, nil
 }
-
-type tagSorter []*Tag
-
-func (ts tagSorter) Len() int {
-	return len([]*Tag(ts))
-}
-
-func (ts tagSorter) Less(i, j int) bool {
-	return []*Tag(ts)[i].Tagger.When.After([]*Tag(ts)[j].Tagger.When)
-}
-
-func (ts tagSorter) Swap(i, j int) {
-	[]*Tag(ts)[i], []*Tag(ts)[j] = []*Tag(ts)[j], []*Tag(ts)[i]
-}
-
-// sortTagsByTime
-func sortTagsByTime(tags []*Tag) {
-	sorter := tagSorter(tags)
-	sort.Sort(sorter)
-}
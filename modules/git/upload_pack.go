@@ -0,0 +1,74 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+)
+
+// filterSpecPattern matches the object filters of partial clones accepted by the clone filter settings
+var filterSpecPattern = regexp.MustCompile(`^(blob:none|blob:limit=[0-9]+[kmg]?|tree:[0-9]+|object:type=(blob|tree|commit|tag))$`)
+
+// IsValidFilterSpec returns true if filter is a supported filter spec of "git clone --filter"
+func IsValidFilterSpec(filter string) bool {
+	return filterSpecPattern.MatchString(filter)
+}
+
+// UploadPackRequest summarizes the request of a client to git upload-pack
+type UploadPackRequest struct {
+	Wants  int
+	Haves  int
+	Filter string
+	// the client requested a shallow clone or fetch
+	Deepen bool
+}
+
+// IsFullClone returns true if the client requests all objects reachable from its wants:
+// it has none of them yet and requested neither a filter nor a shallow history
+func (r *UploadPackRequest) IsFullClone() bool {
+	return r.Wants > 0 && r.Haves == 0 && len(r.Filter) == 0 && !r.Deepen
+}
+
+// ParseUploadPackRequest reads the pkt-lines of an upload-pack request of protocol version 0, 1 or 2
+func ParseUploadPackRequest(r io.Reader) (*UploadPackRequest, error) {
+	req := &UploadPackRequest{}
+	header := make([]byte, 4)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if err == io.EOF {
+				return req, nil
+			}
+			return nil, err
+		}
+		length, err := strconv.ParseUint(string(header), 16, 16)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pkt-line length %q", header)
+		}
+		if length < 4 {
+			// flush, delimiter or response end packet
+			continue
+		}
+		line := make([]byte, length-4)
+		if _, err := io.ReadFull(r, line); err != nil {
+			return nil, err
+		}
+		line = bytes.TrimSuffix(line, []byte{'\n'})
+
+		switch {
+		case bytes.HasPrefix(line, []byte("want ")):
+			req.Wants++
+		case bytes.HasPrefix(line, []byte("have ")):
+			req.Haves++
+		case bytes.HasPrefix(line, []byte("filter ")):
+			req.Filter = string(line[len("filter "):])
+		case bytes.HasPrefix(line, []byte("deepen")), bytes.HasPrefix(line, []byte("shallow ")):
+			req.Deepen = true
+		}
+	}
+}
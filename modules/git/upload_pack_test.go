@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package git
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidFilterSpec(t *testing.T) {
+	for _, filter := range []string{"blob:none", "blob:limit=1024", "blob:limit=1m", "tree:0", "object:type=commit"} {
+		assert.True(t, IsValidFilterSpec(filter), filter)
+	}
+	for _, filter := range []string{"", "blob", "blob:limit=", "tree:x", "sparse:oid=master", "blob:none\n"} {
+		assert.False(t, IsValidFilterSpec(filter), filter)
+	}
+}
+
+func TestParseUploadPackRequest(t *testing.T) {
+	const want = "0032want 1e3b1e5ab8c30fd7a9f1f9e3c3db1b2eb0dbbf84\n"
+	const have = "0032have 2e3b1e5ab8c30fd7a9f1f9e3c3db1b2eb0dbbf84\n"
+
+	kases := []struct {
+		body      string
+		fullClone bool
+	}{
+		// protocol version 0
+		{want + want + "0000" + "0009done\n", true},
+		{want + "0000" + have + "0009done\n", false},
+		{want + "0015filter blob:none\n" + "0000" + "0009done\n", false},
+		{want + "000cdeepen 1" + "0000" + "0009done\n", false},
+		// protocol version 2
+		{"0012command=fetch\n0001" + want + "0009done\n0000", true},
+		{"0012command=fetch\n0001" + want + "0015filter blob:none\n0009done\n0000", false},
+		{"0014command=ls-refs\n00010009peel\n0000", false},
+	}
+	for _, kase := range kases {
+		req, err := ParseUploadPackRequest(strings.NewReader(kase.body))
+		assert.NoError(t, err)
+		assert.Equal(t, kase.fullClone, req.IsFullClone(), kase.body)
+	}
+
+	req, err := ParseUploadPackRequest(strings.NewReader(want + "0015filter blob:none\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, req.Wants)
+	assert.Equal(t, "blob:none", req.Filter)
+
+	_, err = ParseUploadPackRequest(strings.NewReader("zzzz"))
+	assert.Error(t, err)
+	_, err = ParseUploadPackRequest(strings.NewReader(want[:20]))
+	assert.Error(t, err)
+}
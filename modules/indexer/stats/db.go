@@ -24,11 +24,6 @@ func (db *DBIndexer) Index(id int64) error {
 		return nil
 	}
 
-	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeStats)
-	if err != nil {
-		return err
-	}
-
 	gitRepo, err := git.OpenRepository(repo.RepoPath())
 	if err != nil {
 		return err
@@ -46,6 +41,19 @@ func (db *DBIndexer) Index(id int64) error {
 		return err
 	}
 
+	if err := indexLanguageStats(repo, gitRepo, commitID); err != nil {
+		return err
+	}
+	return indexLicenses(repo, gitRepo, commitID)
+}
+
+// indexLanguageStats calculates and saves the language statistics of the commit of the default branch
+func indexLanguageStats(repo *models.Repository, gitRepo *git.Repository, commitID string) error {
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeStats)
+	if err != nil {
+		return err
+	}
+
 	// Do not recalculate stats if already calculated for this commit
 	if status.CommitSha == commitID {
 		return nil
@@ -9,6 +9,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
 )
 
 // Indexer defines an interface to index repository stats
@@ -47,39 +48,43 @@ func populateRepoIndexer() {
 		return
 	}
 
-	var maxRepoID int64
-	if maxRepoID, err = db.GetMaxID("repository"); err != nil {
-		log.Fatal("System error: %v", err)
-	}
-
-	// start with the maximum existing repo ID and work backwards, so that we
-	// don't include repos that are created after gitea starts; such repos will
-	// already be added to the indexer, and we don't need to add them again.
-	for maxRepoID > 0 {
-		select {
-		case <-isShutdown:
-			log.Info("Repository Stats Indexer population shutdown before completion")
-			return
-		default:
-		}
-		ids, err := models.GetUnindexedRepos(models.RepoIndexerTypeStats, maxRepoID, 0, 50)
-		if err != nil {
-			log.Error("populateRepoIndexer: %v", err)
-			return
-		} else if len(ids) == 0 {
-			break
+	// repos whose licenses have not been detected yet are added too, the stats
+	// indexer detects them along with the language statistics
+	for _, indexerType := range []models.RepoIndexerType{models.RepoIndexerTypeStats, models.RepoIndexerTypeLicenses} {
+		var maxRepoID int64
+		if maxRepoID, err = db.GetMaxID("repository"); err != nil {
+			log.Fatal("System error: %v", err)
 		}
-		for _, id := range ids {
+
+		// start with the maximum existing repo ID and work backwards, so that we
+		// don't include repos that are created after gitea starts; such repos will
+		// already be added to the indexer, and we don't need to add them again.
+		for maxRepoID > 0 {
 			select {
 			case <-isShutdown:
 				log.Info("Repository Stats Indexer population shutdown before completion")
 				return
 			default:
 			}
-			if err := statsQueue.Push(id); err != nil {
-				log.Error("statsQueue.Push: %v", err)
+			ids, err := models.GetUnindexedRepos(indexerType, maxRepoID, 0, 50)
+			if err != nil {
+				log.Error("populateRepoIndexer: %v", err)
+				return
+			} else if len(ids) == 0 {
+				break
+			}
+			for _, id := range ids {
+				select {
+				case <-isShutdown:
+					log.Info("Repository Stats Indexer population shutdown before completion")
+					return
+				default:
+				}
+				if err := statsQueue.Push(id); err != nil && err != queue.ErrAlreadyInQueue {
+					log.Error("statsQueue.Push: %v", err)
+				}
+				maxRepoID = id - 1
 			}
-			maxRepoID = id - 1
 		}
 	}
 	log.Info("Done (re)populating the repo stats indexer with existing repositories")
@@ -0,0 +1,98 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"io"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/license"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// maxLicenseFileSize is the size of the largest license file read for the detection of its licenses,
+// larger files are Other
+const maxLicenseFileSize = 256 * 1024
+
+// indexLicenses detects and saves the licenses of the license files in the root directory of the commit
+// of the default branch. The licenses of the files whose blobs are unchanged are kept without reading them.
+func indexLicenses(repo *models.Repository, gitRepo *git.Repository, commitID string) error {
+	status, err := repo.GetIndexerStatus(models.RepoIndexerTypeLicenses)
+	if err != nil {
+		return err
+	}
+	if status.CommitSha == commitID {
+		return nil
+	}
+
+	oldLicenses, err := repo.GetLicenses()
+	if err != nil {
+		return err
+	}
+	oldLicensesByPath := make(map[string]models.RepoLicenseList, len(oldLicenses))
+	for _, l := range oldLicenses {
+		oldLicensesByPath[l.Path] = append(oldLicensesByPath[l.Path], l)
+	}
+
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return err
+	}
+	entries, err := commit.Tree.ListEntries()
+	if err != nil {
+		return err
+	}
+
+	licenses := make(models.RepoLicenseList, 0, 2)
+	for _, entry := range entries {
+		if !(entry.IsRegular() || entry.IsExecutable()) || !license.IsLicenseFile(entry.Name()) {
+			continue
+		}
+		blobID := entry.ID.String()
+
+		if old, ok := oldLicensesByPath[entry.Name()]; ok && old[0].BlobID == blobID {
+			licenses = append(licenses, old...)
+			continue
+		}
+
+		names := []string{license.Other}
+		if entry.Size() <= maxLicenseFileSize {
+			if names, err = detectLicenses(entry.Blob()); err != nil {
+				log.Error("Unable to detect the licenses of %s in %s: %v", entry.Name(), repo.RepoPath(), err)
+				return err
+			}
+		}
+		for _, name := range names {
+			licenses = append(licenses, &models.RepoLicense{
+				Path:    entry.Name(),
+				License: name,
+				BlobID:  blobID,
+			})
+		}
+	}
+
+	if err := repo.UpdateLicenses(commitID, licenses); err != nil {
+		log.Error("Unable to update licenses for ID %s for default branch %s in %s. Error: %v", commitID, repo.DefaultBranch, repo.RepoPath(), err)
+		return err
+	}
+
+	log.Debug("DBIndexer completed licenses for ID %s for default branch %s in %s. licenses count: %d", commitID, repo.DefaultBranch, repo.RepoPath(), len(licenses))
+	return nil
+}
+
+func detectLicenses(blob *git.Blob) ([]string, error) {
+	rd, err := blob.DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+
+	content, err := io.ReadAll(io.LimitReader(rd, maxLicenseFileSize))
+	if err != nil {
+		return nil, err
+	}
+	return license.Detect(content), nil
+}
@@ -0,0 +1,327 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/modules/markup/markdown"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"gopkg.in/yaml.v2"
+)
+
+// noResponse is written for fields which have been left empty
+const noResponse = "_No response_"
+
+var validFieldIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// IsFormFile returns whether the file name refers to a YAML issue form
+func IsFormFile(name string) bool {
+	ext := strings.ToLower(path.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// Unmarshal parses an issue template, either a markdown file with YAML front matter or a YAML issue form
+func Unmarshal(filename string, content []byte) (*api.IssueTemplate, error) {
+	it := &api.IssueTemplate{}
+	if !IsFormFile(filename) {
+		body, err := markdown.ExtractMetadata(string(content), it)
+		if err != nil {
+			return nil, err
+		}
+		it.Content = body
+		it.Fields = nil
+		it.FileName = path.Base(filename)
+		return it, nil
+	}
+
+	if err := yaml.Unmarshal(content, it); err != nil {
+		return nil, err
+	}
+	if it.About == "" {
+		// issue forms describe themselves with "description" rather than "about"
+		var meta struct {
+			Description string `yaml:"description"`
+		}
+		if err := yaml.Unmarshal(content, &meta); err != nil {
+			return nil, err
+		}
+		it.About = meta.Description
+	}
+	it.FileName = path.Base(filename)
+	if err := Validate(it); err != nil {
+		return nil, err
+	}
+	return it, nil
+}
+
+// Validate checks the schema of an issue form
+func Validate(it *api.IssueTemplate) error {
+	if !it.Valid() {
+		return fmt.Errorf("'name' and 'description' are required")
+	}
+	if len(it.Fields) == 0 {
+		return fmt.Errorf("'body' must contain at least one field")
+	}
+
+	ids := make(map[string]bool, len(it.Fields))
+	for idx, field := range it.Fields {
+		if field == nil {
+			return fmt.Errorf("body[%d]: field is empty", idx)
+		}
+		switch field.Type {
+		case api.IssueFormFieldTypeMarkdown:
+			if strings.TrimSpace(field.Attributes.Value) == "" {
+				return fmt.Errorf("body[%d]: 'value' is required for markdown fields", idx)
+			}
+			continue
+		case api.IssueFormFieldTypeTextarea, api.IssueFormFieldTypeInput:
+		case api.IssueFormFieldTypeDropdown, api.IssueFormFieldTypeCheckboxes:
+			if len(field.Attributes.Options) == 0 {
+				return fmt.Errorf("body[%d]: 'options' are required for %s fields", idx, field.Type)
+			}
+			for i, option := range field.Attributes.Options {
+				if option == nil || strings.TrimSpace(option.Label) == "" {
+					return fmt.Errorf("body[%d]: option %d must have a label", idx, i)
+				}
+			}
+		default:
+			return fmt.Errorf("body[%d]: unknown field type %q", idx, field.Type)
+		}
+
+		if !validFieldIDPattern.MatchString(field.ID) {
+			return fmt.Errorf("body[%d]: 'id' is required and may only contain alphanumeric characters, '-' and '_'", idx)
+		}
+		if ids[field.ID] {
+			return fmt.Errorf("body[%d]: 'id' %q is used more than once", idx, field.ID)
+		}
+		ids[field.ID] = true
+		if strings.TrimSpace(field.Attributes.Label) == "" {
+			return fmt.Errorf("body[%d]: 'label' is required", idx)
+		}
+	}
+	return nil
+}
+
+// InvalidField describes why the value submitted for a form field was rejected
+type InvalidField struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// ErrInvalidFormValues represents a "InvalidFormValues" kind of error.
+type ErrInvalidFormValues struct {
+	Fields []*InvalidField
+}
+
+// IsErrInvalidFormValues checks if an error is a ErrInvalidFormValues.
+func IsErrInvalidFormValues(err error) bool {
+	_, ok := err.(ErrInvalidFormValues)
+	return ok
+}
+
+func (err ErrInvalidFormValues) Error() string {
+	reasons := make([]string, 0, len(err.Fields))
+	for _, field := range err.Fields {
+		reasons = append(reasons, fmt.Sprintf("%s: %s", field.ID, field.Reason))
+	}
+	return fmt.Sprintf("invalid issue form values [%s]", strings.Join(reasons, "; "))
+}
+
+func findOption(field *api.IssueFormField, label string) *api.IssueFormFieldOption {
+	for _, option := range field.Attributes.Options {
+		if option.Label == label {
+			return option
+		}
+	}
+	return nil
+}
+
+func nonEmpty(values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if strings.TrimSpace(value) != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
+
+// ValidateValues checks the submitted values, keyed by field id, against the issue form.
+// Dropdowns and checkboxes take the labels of the selected options.
+func ValidateValues(it *api.IssueTemplate, values map[string][]string) error {
+	var invalid []*InvalidField
+	known := make(map[string]bool, len(it.Fields))
+	for _, field := range it.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		known[field.ID] = true
+		submitted := nonEmpty(values[field.ID])
+
+		switch field.Type {
+		case api.IssueFormFieldTypeInput, api.IssueFormFieldTypeTextarea:
+			if len(submitted) > 1 {
+				invalid = append(invalid, &InvalidField{ID: field.ID, Reason: "only a single value is allowed"})
+			} else if len(submitted) == 0 && field.Validations.Required {
+				invalid = append(invalid, &InvalidField{ID: field.ID, Reason: "is required"})
+			}
+		case api.IssueFormFieldTypeDropdown:
+			if len(submitted) > 1 && !field.Attributes.Multiple {
+				invalid = append(invalid, &InvalidField{ID: field.ID, Reason: "only a single option may be selected"})
+				continue
+			}
+			if len(submitted) == 0 && field.Validations.Required {
+				invalid = append(invalid, &InvalidField{ID: field.ID, Reason: "is required"})
+				continue
+			}
+			for _, value := range submitted {
+				if findOption(field, value) == nil {
+					invalid = append(invalid, &InvalidField{ID: field.ID, Reason: fmt.Sprintf("%q is not a valid option", value)})
+				}
+			}
+		case api.IssueFormFieldTypeCheckboxes:
+			checked := make(map[string]bool, len(submitted))
+			for _, value := range submitted {
+				if findOption(field, value) == nil {
+					invalid = append(invalid, &InvalidField{ID: field.ID, Reason: fmt.Sprintf("%q is not a valid option", value)})
+				}
+				checked[value] = true
+			}
+			if len(submitted) == 0 && field.Validations.Required {
+				invalid = append(invalid, &InvalidField{ID: field.ID, Reason: "is required"})
+			}
+			for _, option := range field.Attributes.Options {
+				if option.Required && !checked[option.Label] {
+					invalid = append(invalid, &InvalidField{ID: field.ID, Reason: fmt.Sprintf("%q must be checked", option.Label)})
+				}
+			}
+		}
+	}
+
+	for id := range values {
+		if !known[id] {
+			invalid = append(invalid, &InvalidField{ID: id, Reason: "unknown field"})
+		}
+	}
+
+	if len(invalid) > 0 {
+		return ErrInvalidFormValues{Fields: invalid}
+	}
+	return nil
+}
+
+func fieldHeading(field *api.IssueFormField) string {
+	if label := strings.TrimSpace(field.Attributes.Label); label != "" {
+		return label
+	}
+	return field.ID
+}
+
+// RenderToMarkdown renders the submitted values as markdown, one "### <label>" section per field,
+// so that the body can be parsed back with ParseMarkdown
+func RenderToMarkdown(it *api.IssueTemplate, values map[string][]string) string {
+	var sb strings.Builder
+	for _, field := range it.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		submitted := nonEmpty(values[field.ID])
+
+		sb.WriteString("### ")
+		sb.WriteString(fieldHeading(field))
+		sb.WriteString("\n\n")
+		switch {
+		case field.Type == api.IssueFormFieldTypeCheckboxes:
+			checked := make(map[string]bool, len(submitted))
+			for _, value := range submitted {
+				checked[value] = true
+			}
+			for _, option := range field.Attributes.Options {
+				if checked[option.Label] {
+					sb.WriteString("- [x] ")
+				} else {
+					sb.WriteString("- [ ] ")
+				}
+				sb.WriteString(option.Label)
+				sb.WriteString("\n")
+			}
+		case len(submitted) == 0:
+			sb.WriteString(noResponse)
+			sb.WriteString("\n")
+		case field.Type == api.IssueFormFieldTypeTextarea && field.Attributes.Render != "":
+			sb.WriteString("```")
+			sb.WriteString(field.Attributes.Render)
+			sb.WriteString("\n")
+			sb.WriteString(strings.TrimRight(submitted[0], "\n"))
+			sb.WriteString("\n```\n")
+		default:
+			sb.WriteString(strings.TrimSpace(strings.Join(submitted, ", ")))
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n")
+}
+
+// ParseMarkdown extracts the values of the form fields from an issue body rendered by RenderToMarkdown
+func ParseMarkdown(it *api.IssueTemplate, body string) map[string][]string {
+	byHeading := make(map[string]*api.IssueFormField, len(it.Fields))
+	for _, field := range it.Fields {
+		if field.Type != api.IssueFormFieldTypeMarkdown {
+			byHeading[fieldHeading(field)] = field
+		}
+	}
+
+	sections := make(map[*api.IssueFormField][]string)
+	var current *api.IssueFormField
+	for _, line := range strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		if strings.HasPrefix(line, "### ") {
+			if field, ok := byHeading[strings.TrimSpace(line[4:])]; ok {
+				current = field
+				continue
+			}
+		}
+		if current != nil {
+			sections[current] = append(sections[current], line)
+		}
+	}
+
+	values := make(map[string][]string, len(sections))
+	for field, lines := range sections {
+		content := strings.TrimSpace(strings.Join(lines, "\n"))
+		if content == noResponse {
+			continue
+		}
+		switch field.Type {
+		case api.IssueFormFieldTypeCheckboxes:
+			for _, line := range lines {
+				if strings.HasPrefix(line, "- [x] ") {
+					values[field.ID] = append(values[field.ID], strings.TrimSpace(line[6:]))
+				}
+			}
+		case api.IssueFormFieldTypeDropdown:
+			if field.Attributes.Multiple {
+				values[field.ID] = strings.Split(content, ", ")
+			} else {
+				values[field.ID] = []string{content}
+			}
+		default:
+			if field.Attributes.Render != "" && strings.HasPrefix(content, "```") && strings.HasSuffix(content, "```") {
+				content = strings.TrimSuffix(content, "```")
+				if idx := strings.IndexByte(content, '\n'); idx >= 0 {
+					content = content[idx+1:]
+				}
+				content = strings.TrimSuffix(content, "\n")
+			}
+			values[field.ID] = []string{content}
+		}
+	}
+	return values
+}
@@ -0,0 +1,142 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package template
+
+import (
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const bugReport = `name: Bug Report
+description: File a bug report
+title: "[Bug]: "
+labels: ["bug"]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to fill out this bug report!
+  - type: input
+    id: version
+    attributes:
+      label: Gitea version
+    validations:
+      required: true
+  - type: textarea
+    id: logs
+    attributes:
+      label: Log output
+      render: shell
+  - type: dropdown
+    id: database
+    attributes:
+      label: Database
+      options:
+        - SQLite
+        - MySQL
+    validations:
+      required: true
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow this project's Code of Conduct
+          required: true
+        - label: I searched for existing issues
+`
+
+func TestUnmarshal(t *testing.T) {
+	it, err := Unmarshal("bug.yaml", []byte(bugReport))
+	assert.NoError(t, err)
+	assert.Equal(t, "Bug Report", it.Name)
+	assert.Equal(t, "File a bug report", it.About)
+	assert.Equal(t, "bug.yaml", it.FileName)
+	assert.Equal(t, []string{"bug"}, it.Labels)
+	assert.True(t, it.IsForm())
+	assert.Len(t, it.Fields, 5)
+	assert.Equal(t, api.IssueFormFieldTypeDropdown, it.Fields[3].Type)
+	assert.Equal(t, "MySQL", it.Fields[3].Attributes.Options[1].Label)
+	assert.True(t, it.Fields[4].Attributes.Options[0].Required)
+	assert.False(t, it.Fields[4].Attributes.Options[1].Required)
+
+	it, err = Unmarshal("feature.md", []byte("---\nname: Feature\nabout: Request a feature\nassignees: [user2]\n---\nDescribe it"))
+	assert.NoError(t, err)
+	assert.False(t, it.IsForm())
+	assert.Equal(t, []string{"user2"}, it.Assignees)
+	assert.Equal(t, "Describe it", it.Content)
+
+	for _, content := range []string{
+		"name: Broken\ndescription: no fields\n",
+		"name: Broken\ndescription: bad type\nbody:\n  - type: radio\n    id: a\n    attributes:\n      label: A\n",
+		"name: Broken\ndescription: no id\nbody:\n  - type: input\n    attributes:\n      label: A\n",
+		"name: Broken\ndescription: duplicate id\nbody:\n  - type: input\n    id: a\n    attributes:\n      label: A\n  - type: input\n    id: a\n    attributes:\n      label: B\n",
+		"name: Broken\ndescription: no options\nbody:\n  - type: dropdown\n    id: a\n    attributes:\n      label: A\n",
+	} {
+		_, err = Unmarshal("broken.yml", []byte(content))
+		assert.Error(t, err, content)
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	it, err := Unmarshal("bug.yaml", []byte(bugReport))
+	assert.NoError(t, err)
+
+	err = ValidateValues(it, map[string][]string{
+		"logs":     {"panic"},
+		"database": {"PostgreSQL"},
+		"terms":    {"I searched for existing issues"},
+		"unknown":  {"value"},
+	})
+	assert.True(t, IsErrInvalidFormValues(err))
+	invalid := make(map[string]string)
+	for _, field := range err.(ErrInvalidFormValues).Fields {
+		invalid[field.ID] = field.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"version":  "is required",
+		"database": `"PostgreSQL" is not a valid option`,
+		"terms":    `"I agree to follow this project's Code of Conduct" must be checked`,
+		"unknown":  "unknown field",
+	}, invalid)
+
+	err = ValidateValues(it, map[string][]string{
+		"version":  {"1.16.0"},
+		"database": {"SQLite", "MySQL"},
+		"terms":    {"I agree to follow this project's Code of Conduct"},
+	})
+	assert.True(t, IsErrInvalidFormValues(err))
+
+	assert.NoError(t, ValidateValues(it, map[string][]string{
+		"version":  {"1.16.0"},
+		"database": {"SQLite"},
+		"terms":    {"I agree to follow this project's Code of Conduct"},
+	}))
+}
+
+func TestRenderAndParseMarkdown(t *testing.T) {
+	it, err := Unmarshal("bug.yaml", []byte(bugReport))
+	assert.NoError(t, err)
+
+	values := map[string][]string{
+		"version":  {"1.16.0"},
+		"logs":     {"2021/10/01 panic: oops\n\tat main.go:1"},
+		"database": {"SQLite"},
+		"terms":    {"I agree to follow this project's Code of Conduct"},
+	}
+	body := RenderToMarkdown(it, values)
+	assert.Equal(t, "### Gitea version\n\n1.16.0\n\n"+
+		"### Log output\n\n```shell\n2021/10/01 panic: oops\n\tat main.go:1\n```\n\n"+
+		"### Database\n\nSQLite\n\n"+
+		"### Code of Conduct\n\n- [x] I agree to follow this project's Code of Conduct\n- [ ] I searched for existing issues", body)
+	assert.Equal(t, values, ParseMarkdown(it, body))
+
+	delete(values, "logs")
+	body = RenderToMarkdown(it, values)
+	assert.Contains(t, body, "### Log output\n\n_No response_\n\n")
+	assert.Equal(t, values, ParseMarkdown(it, body))
+}
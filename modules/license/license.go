@@ -0,0 +1,211 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"hash/fnv"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/options"
+)
+
+const (
+	// Other is the license of license files which don't match any known license
+	Other = "other"
+
+	// threshold is the minimum similarity of a license file to a known license
+	threshold = 0.9
+)
+
+var (
+	licenseFileRe       = regexp.MustCompile(`(?i)^(?:(?:un)?licen[cs]e|copying)(?:[-_.][^/]*)?$`)
+	namedLicenseFileRe  = regexp.MustCompile(`(?i)^[^/]+[-_.]licen[cs]e(?:\.(?:md|markdown|txt|rst))?$`)
+	nonLicenseFileExtRe = regexp.MustCompile(`(?i)\.(?:go|c|h|js|ts|py|rb|java|json|ya?ml|toml|xml|html?|css|sh|spdx|sig|asc)$`)
+)
+
+// IsLicenseFile checks if the file name in the root directory of a repository is the name of a license file
+func IsLicenseFile(name string) bool {
+	if nonLicenseFileExtRe.MatchString(name) {
+		return false
+	}
+	return licenseFileRe.MatchString(name) || namedLicenseFileRe.MatchString(name)
+}
+
+// shingles is the sorted set of the hashes of the pairs of consecutive words of a text
+type shingles []uint64
+
+func newShingles(content string) shingles {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 1 {
+		words = append(words, "")
+	}
+
+	set := make(map[uint64]struct{}, len(words))
+	h := fnv.New64a()
+	for i := 0; i+1 < len(words); i++ {
+		h.Reset()
+		_, _ = h.Write([]byte(words[i] + " " + words[i+1]))
+		set[h.Sum64()] = struct{}{}
+	}
+
+	s := make(shingles, 0, len(set))
+	for v := range set {
+		s = append(s, v)
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s
+}
+
+// intersect returns the number of shingles contained in both sets
+func (s shingles) intersect(other shingles) int {
+	var n int
+	for i, j := 0, 0; i < len(s) && j < len(other); {
+		switch {
+		case s[i] < other[j]:
+			i++
+		case s[i] > other[j]:
+			j++
+		default:
+			n++
+			i++
+			j++
+		}
+	}
+	return n
+}
+
+// difference returns the shingles which are not contained in the other set
+func (s shingles) difference(other shingles) shingles {
+	res := make(shingles, 0, len(s))
+	for i, j := 0, 0; i < len(s); {
+		if j < len(other) && other[j] < s[i] {
+			j++
+		} else if j < len(other) && other[j] == s[i] {
+			i++
+			j++
+		} else {
+			res = append(res, s[i])
+			i++
+		}
+	}
+	return res
+}
+
+// union returns the shingles contained in either set
+func (s shingles) union(other shingles) shingles {
+	res := make(shingles, 0, len(s)+len(other))
+	i, j := 0, 0
+	for i < len(s) && j < len(other) {
+		switch {
+		case s[i] < other[j]:
+			res = append(res, s[i])
+			i++
+		case s[i] > other[j]:
+			res = append(res, other[j])
+			j++
+		default:
+			res = append(res, s[i])
+			i++
+			j++
+		}
+	}
+	res = append(res, s[i:]...)
+	return append(res, other[j:]...)
+}
+
+type knownLicense struct {
+	name     string
+	shingles shingles
+}
+
+var (
+	knownLicenses     []*knownLicense
+	knownLicensesOnce sync.Once
+)
+
+// loadKnownLicenses loads the license texts shipped for the initialization of repositories,
+// licenses with identical texts are sorted by the length of their names to prefer the plain ones
+// like GPL-3.0-only over GPL-3.0-or-later
+func loadKnownLicenses() []*knownLicense {
+	knownLicensesOnce.Do(func() {
+		names, err := options.Dir("license")
+		if err != nil {
+			log.Error("Unable to list the licenses: %v", err)
+			return
+		}
+		sort.Slice(names, func(i, j int) bool {
+			if len(names[i]) != len(names[j]) {
+				return len(names[i]) < len(names[j])
+			}
+			return names[i] < names[j]
+		})
+
+		knownLicenses = make([]*knownLicense, 0, len(names))
+		for _, name := range names {
+			data, err := options.License(name)
+			if err != nil {
+				log.Error("Unable to read the license %s: %v", name, err)
+				continue
+			}
+			if s := newShingles(string(data)); len(s) > 0 {
+				knownLicenses = append(knownLicenses, &knownLicense{name: name, shingles: s})
+			}
+		}
+	})
+	return knownLicenses
+}
+
+// Detect returns the names of the known licenses in the content of a license file.
+// The licenses whose texts are contained in the file are picked greedily by the part of the file
+// they cover, skipping the licenses with less than half of their texts left uncovered by the picked
+// ones, which are variants or parts of them. The file is a match if it is similar enough to the
+// texts of the picked licenses together, files without a match are Other.
+func Detect(content []byte) []string {
+	file := newShingles(string(content))
+	if len(file) == 0 {
+		return []string{Other}
+	}
+
+	candidates := make([]*knownLicense, 0, 4)
+	for _, l := range loadKnownLicenses() {
+		if float64(file.intersect(l.shingles)) >= threshold*float64(len(l.shingles)) {
+			candidates = append(candidates, l)
+		}
+	}
+
+	remaining := file
+	var matched shingles
+	names := make([]string, 0, len(candidates))
+	for {
+		var best *knownLicense
+		var bestCovered int
+		for _, l := range candidates {
+			covered := remaining.intersect(l.shingles)
+			if covered > bestCovered && 2*covered >= len(l.shingles) {
+				best, bestCovered = l, covered
+			}
+		}
+		if best == nil {
+			break
+		}
+		remaining = remaining.difference(best.shingles)
+		matched = matched.union(best.shingles)
+		names = append(names, best.name)
+	}
+
+	// The similarity is the Sørensen–Dice coefficient of the shingles
+	if len(names) == 0 || 2*float64(file.intersect(matched)) < threshold*float64(len(file)+len(matched)) {
+		return []string{Other}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package license
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/options"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	setting.StaticRootPath = "../../"
+	os.Exit(m.Run())
+}
+
+func TestIsLicenseFile(t *testing.T) {
+	for _, name := range []string{"LICENSE", "license.md", "LICENCE.txt", "COPYING", "COPYING.LESSER", "LICENSE-MIT", "MIT-LICENSE", "UNLICENSE"} {
+		assert.True(t, IsLicenseFile(name), name)
+	}
+	for _, name := range []string{"README.md", "license.go", "licenses.json", "licensed.txt", "copyright.sh"} {
+		assert.False(t, IsLicenseFile(name), name)
+	}
+}
+
+func TestDetect(t *testing.T) {
+	mit, err := os.ReadFile("../../LICENSE")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MIT"}, Detect(mit))
+
+	apache, err := options.License("Apache-2.0")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Apache-2.0"}, Detect(apache))
+
+	gpl, err := options.License("GPL-3.0-or-later")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"GPL-3.0-only"}, Detect(gpl))
+
+	dual := "This project is dual licensed under the MIT and the Apache 2.0 licenses.\n\n" + string(mit) + "\n\n" + string(apache)
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, Detect([]byte(dual)))
+
+	modified := strings.Replace(string(mit), "free of charge", "for a fee", 1) + "\nYou may not use the Software to train any model.\n"
+	assert.Equal(t, []string{"MIT"}, Detect([]byte(modified)))
+
+	assert.Equal(t, []string{Other}, Detect([]byte("All rights reserved. Do not copy, modify or distribute this software without permission.")))
+	assert.Equal(t, []string{Other}, Detect(nil))
+}
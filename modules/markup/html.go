@@ -33,6 +33,7 @@ import (
 const (
 	IssueNameStyleNumeric      = "numeric"
 	IssueNameStyleAlphanumeric = "alphanumeric"
+	IssueNameStyleRegexp       = "regexp"
 )
 
 var (
@@ -801,24 +802,38 @@ func issueIndexPatternProcessor(ctx *RenderContext, node *html.Node) {
 		return
 	}
 	var (
-		found bool
-		ref   *references.RenderizableReference
+		found   bool
+		ref     *references.RenderizableReference
+		pattern *regexp.Regexp
 	)
 
+	_, exttrack := ctx.Metas["format"]
+	alphanum := ctx.Metas["style"] == IssueNameStyleAlphanumeric
+	if exttrack && ctx.Metas["style"] == IssueNameStyleRegexp {
+		var err error
+		if pattern, err = regexp.Compile(ctx.Metas["regexp"]); err != nil {
+			return
+		}
+	}
+	// Only numeric references of external trackers are rendered like the ones of local issues
+	customRefs := exttrack && (alphanum || pattern != nil)
+
 	next := node.NextSibling
 	for node != nil && node != next {
-		_, exttrack := ctx.Metas["format"]
-		alphanum := ctx.Metas["style"] == IssueNameStyleAlphanumeric
-
 		// Repos with external issue trackers might still need to reference local PRs
 		// We need to concern with the first one that shows up in the text, whichever it is
-		found, ref = references.FindRenderizableReferenceNumeric(node.Data, exttrack && alphanum)
-		if exttrack && alphanum {
-			if found2, ref2 := references.FindRenderizableReferenceAlphanumeric(node.Data); found2 {
-				if !found || ref2.RefLocation.Start < ref.RefLocation.Start {
-					found = true
-					ref = ref2
-				}
+		found, ref = references.FindRenderizableReferenceNumeric(node.Data, customRefs)
+		if customRefs {
+			var found2 bool
+			var ref2 *references.RenderizableReference
+			if pattern != nil {
+				found2, ref2 = references.FindRenderizableReferenceRegexp(node.Data, pattern)
+			} else {
+				found2, ref2 = references.FindRenderizableReferenceAlphanumeric(node.Data)
+			}
+			if found2 && (!found || ref2.RefLocation.Start < ref.RefLocation.Start) {
+				found = true
+				ref = ref2
 			}
 		}
 		if !found {
@@ -853,7 +868,7 @@ func issueIndexPatternProcessor(ctx *RenderContext, node *html.Node) {
 
 		// Decorate action keywords if actionable
 		var keyword *html.Node
-		if references.IsXrefActionable(ref, exttrack, alphanum) {
+		if references.IsXrefActionable(ref, exttrack, customRefs) {
 			keyword = createKeyword(node.Data[ref.ActionLocation.Start:ref.ActionLocation.End])
 		} else {
 			keyword = &html.Node{
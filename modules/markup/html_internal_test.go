@@ -52,6 +52,14 @@ var alphanumericMetas = map[string]string{
 	"style":  IssueNameStyleAlphanumeric,
 }
 
+var regexpMetas = map[string]string{
+	"format": "https://someurl.com/{user}/{repo}/{index}",
+	"user":   "someUser",
+	"repo":   "someRepo",
+	"style":  IssueNameStyleRegexp,
+	"regexp": `\bTICKET:(\d+)\b`,
+}
+
 // these values should match the Repo const above
 var localMetas = map[string]string{
 	"user": "gogits",
@@ -195,6 +203,20 @@ func TestRender_IssueIndexPattern4(t *testing.T) {
 	test("test issue ABCDEFGHIJ-1234567890", "test issue %s", "ABCDEFGHIJ-1234567890")
 }
 
+func TestRender_IssueIndexPatternRegexp(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+
+	// regexp: the first group of the pattern is the index, the whole match is the link text
+	test := func(s, expected string) {
+		testRenderIssueIndexPattern(t, s, expected, &RenderContext{Metas: regexpMetas})
+	}
+	test("TICKET:1234 test", link("https://someurl.com/someUser/someRepo/1234", "ref-issue ref-external-issue", "TICKET:1234")+" test")
+	test("fixes TICKET:12", "fixes "+link("https://someurl.com/someUser/someRepo/12", "ref-issue ref-external-issue", "TICKET:12"))
+	test("TICKET:abc #12", "TICKET:abc #12")
+	test("no reference", "no reference")
+}
+
 func testRenderIssueIndexPattern(t *testing.T, input, expected string, ctx *RenderContext) {
 	if ctx.URLPrefix == "" {
 		ctx.URLPrefix = AppSubURL
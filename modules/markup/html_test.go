@@ -319,6 +319,24 @@ func TestRender_emoji(t *testing.T) {
 		`<p>:not exist:</p>`)
 }
 
+func TestRenderDescriptionHTML(t *testing.T) {
+	setting.AppURL = AppURL
+	setting.AppSubURL = AppSubURL
+
+	test := func(input, expected string) {
+		buffer, err := RenderDescriptionHTML(&RenderContext{URLPrefix: AppSubURL}, input)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, Sanitize(buffer))
+	}
+
+	test("Launch :rocket:", `Launch <span class="emoji" aria-label="rocket">🚀</span>`)
+	test("日本語の説明 :smile:", `日本語の説明 <span class="emoji" aria-label="grinning face with smiling eyes">😄</span>`)
+	// the directionality marks of mixed right-to-left text are kept
+	test("\u202bשלום\u202c :+1: hello\u200f", "\u202bשלום\u202c <span class=\"emoji\" aria-label=\"thumbs up\">👍</span> hello\u200f")
+	test("\u2067עברית\u2069 https://example.com", "\u2067עברית\u2069 "+
+		`<a href="https://example.com" target="_blank" rel="noopener noreferrer nofollow">https://example.com</a>`)
+}
+
 func TestRender_ShortLinks(t *testing.T) {
 	setting.AppURL = AppURL
 	setting.AppSubURL = AppSubURL
@@ -5,7 +5,8 @@
 package metrics
 
 import (
-	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	stats_service "code.gitea.io/gitea/services/stats"
 
 	"github.com/prometheus/client_golang/prometheus"
 )
@@ -27,6 +28,7 @@ type Collector struct {
 	IssuesByLabel      *prometheus.Desc
 	IssuesByRepository *prometheus.Desc
 	Labels             *prometheus.Desc
+	LastCommitCache    *prometheus.Desc
 	LoginSources       *prometheus.Desc
 	Milestones         *prometheus.Desc
 	Mirrors            *prometheus.Desc
@@ -38,6 +40,7 @@ type Collector struct {
 	Releases           *prometheus.Desc
 	Repositories       *prometheus.Desc
 	Stars              *prometheus.Desc
+	StatisticsAsOf     *prometheus.Desc
 	Teams              *prometheus.Desc
 	UpdateTasks        *prometheus.Desc
 	Users              *prometheus.Desc
@@ -109,6 +112,11 @@ func NewCollector() Collector {
 			"Number of Labels",
 			nil, nil,
 		),
+		LastCommitCache: prometheus.NewDesc(
+			namespace+"last_commit_cache_lookups_total",
+			"Number of lookups of the last commits of tree entries, by result",
+			[]string{"result"}, nil,
+		),
 		LoginSources: prometheus.NewDesc(
 			namespace+"loginsources",
 			"Number of LoginSources",
@@ -179,6 +187,11 @@ func NewCollector() Collector {
 			"Number of Users",
 			nil, nil,
 		),
+		StatisticsAsOf: prometheus.NewDesc(
+			namespace+"statistics_as_of_timestamp_seconds",
+			"Unix time at which the other numbers were counted",
+			nil, nil,
+		),
 		Watches: prometheus.NewDesc(
 			namespace+"watches",
 			"Number of Watches",
@@ -206,6 +219,7 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.IssuesOpen
 	ch <- c.IssuesClosed
 	ch <- c.Labels
+	ch <- c.LastCommitCache
 	ch <- c.LoginSources
 	ch <- c.Milestones
 	ch <- c.Mirrors
@@ -217,6 +231,7 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.Releases
 	ch <- c.Repositories
 	ch <- c.Stars
+	ch <- c.StatisticsAsOf
 	ch <- c.Teams
 	ch <- c.UpdateTasks
 	ch <- c.Users
@@ -226,7 +241,7 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 
 // Collect returns the metrics with values
 func (c Collector) Collect(ch chan<- prometheus.Metric) {
-	stats := models.GetStatistic()
+	stats := stats_service.Get()
 
 	ch <- prometheus.MustNewConstMetric(
 		c.Accesses,
@@ -294,6 +309,19 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Label),
 	)
+	hits, misses := git.LastCommitCacheStats()
+	ch <- prometheus.MustNewConstMetric(
+		c.LastCommitCache,
+		prometheus.CounterValue,
+		float64(hits),
+		"hit",
+	)
+	ch <- prometheus.MustNewConstMetric(
+		c.LastCommitCache,
+		prometheus.CounterValue,
+		float64(misses),
+		"miss",
+	)
 	ch <- prometheus.MustNewConstMetric(
 		c.LoginSources,
 		prometheus.GaugeValue,
@@ -349,6 +377,11 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Star),
 	)
+	ch <- prometheus.MustNewConstMetric(
+		c.StatisticsAsOf,
+		prometheus.GaugeValue,
+		float64(stats.AsOf.Unix()),
+	)
 	ch <- prometheus.MustNewConstMetric(
 		c.Teams,
 		prometheus.GaugeValue,
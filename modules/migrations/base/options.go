@@ -5,7 +5,11 @@
 
 package base
 
-import "code.gitea.io/gitea/modules/structs"
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/structs"
+)
 
 // MigrateOptions defines the way a repository gets migrated
 // this is for internal usage by migrations module and func who interact with it
@@ -39,4 +43,10 @@ type MigrateOptions struct {
 	ReleaseAssets   bool
 	MigrateToRepoID int64
 	MirrorInterval  string `json:"mirror_interval"`
+
+	// Resume continues a failed migration into MigrateToRepoID, entities which were migrated already are skipped
+	Resume bool `json:"-"`
+	// SyncSince synchronizes the repository MigrateToRepoID migrated before,
+	// only entities which were updated since then are migrated
+	SyncSince time.Time `json:"-"`
 }
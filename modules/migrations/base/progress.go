@@ -0,0 +1,18 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package base
+
+// MigrateProgress represents the entities migrated last
+type MigrateProgress struct {
+	IssueIndex       int64
+	PullRequestIndex int64
+	ReleaseTag       string
+}
+
+// ProgressRecorder records the progress of a migration, so a failed migration can be resumed
+type ProgressRecorder func(progress MigrateProgress)
+
+// NilProgressRecorder represents a recorder which keeps nothing
+func NilProgressRecorder(MigrateProgress) {}
@@ -16,6 +16,27 @@ var (
 	ErrRepoNotCreated = errors.New("repository is not created yet")
 )
 
+// ErrMigrationIncomplete represents a migration which failed after the repository has been created,
+// the repository is kept so the migration can be resumed
+type ErrMigrationIncomplete struct {
+	Err error
+}
+
+// IsErrMigrationIncomplete checks if an error is a ErrMigrationIncomplete
+func IsErrMigrationIncomplete(err error) bool {
+	_, ok := err.(*ErrMigrationIncomplete)
+	return ok
+}
+
+func (err *ErrMigrationIncomplete) Error() string {
+	return err.Err.Error()
+}
+
+// Unwrap returns the error which interrupted the migration
+func (err *ErrMigrationIncomplete) Unwrap() error {
+	return err.Err
+}
+
 // IsRateLimitError returns true if the err is github.RateLimitError
 func IsRateLimitError(err error) bool {
 	_, ok := err.(*github.RateLimitError)
@@ -12,6 +12,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -62,7 +63,12 @@ func (f *GiteaDownloaderFactory) New(ctx context.Context, opts base.MigrateOptio
 
 	log.Trace("Create gitea downloader. BaseURL: %s RepoName: %s", baseURL, repoNameSpace)
 
-	return NewGiteaDownloader(ctx, baseURL, repoPath, opts.AuthUsername, opts.AuthPassword, opts.AuthToken)
+	downloader, err := NewGiteaDownloader(ctx, baseURL, repoPath, opts.AuthUsername, opts.AuthPassword, opts.AuthToken)
+	if err != nil {
+		return nil, err
+	}
+	downloader.since = opts.SyncSince
+	return downloader, nil
 }
 
 // GitServiceType returns the type of git service
@@ -79,6 +85,7 @@ type GiteaDownloader struct {
 	repoName   string
 	pagination bool
 	maxPerPage int
+	since      time.Time // only issues, pull requests and comments updated since then are downloaded if set
 }
 
 // NewGiteaDownloader creates a gitea Downloader via gitea API
@@ -403,6 +410,9 @@ func (g *GiteaDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, err
 		return nil, false, fmt.Errorf("error while listing issues: %v", err)
 	}
 	for _, issue := range issues {
+		if issue.Updated.Before(g.since) {
+			continue
+		}
 
 		var labels = make([]*base.Label, 0, len(issue.Labels))
 		for i := range issue.Labels {
@@ -455,8 +465,71 @@ func (g *GiteaDownloader) GetIssues(page, perPage int) ([]*base.Issue, bool, err
 	return allIssues, isEnd, nil
 }
 
+// SupportGetRepoComments return true if it can get all comments of the repository at once,
+// which is only done to synchronize the comments updated since the last migration
+func (g *GiteaDownloader) SupportGetRepoComments() bool {
+	return !g.since.IsZero()
+}
+
+// getRepoComments returns the comments of all issues and pull requests of the repository updated since g.since
+func (g *GiteaDownloader) getRepoComments(opts base.GetCommentOptions) ([]*base.Comment, bool, error) {
+	if opts.PageSize > g.maxPerPage {
+		opts.PageSize = g.maxPerPage
+	}
+
+	comments, _, err := g.client.ListRepoIssueComments(g.repoOwner, g.repoName, gitea_sdk.ListIssueCommentOptions{
+		ListOptions: gitea_sdk.ListOptions{
+			PageSize: opts.PageSize,
+			Page:     opts.Page,
+		},
+		Since: g.since,
+	})
+	if err != nil {
+		return nil, false, fmt.Errorf("error while listing comments (page: %d, pagesize: %d). Error: %v", opts.Page, opts.PageSize, err)
+	}
+
+	var allComments = make([]*base.Comment, 0, len(comments))
+	for _, comment := range comments {
+		issueURL := comment.IssueURL
+		if len(issueURL) == 0 {
+			issueURL = comment.PRURL
+		}
+		index, err := strconv.ParseInt(issueURL[strings.LastIndex(issueURL, "/")+1:], 10, 64)
+		if err != nil {
+			log.Warn("Unable to find the issue of comment %d during synchronizing %s/%s: %s", comment.ID, g.repoOwner, g.repoName, issueURL)
+			continue
+		}
+
+		reactions, err := g.getCommentReactions(comment.ID)
+		if err != nil {
+			log.Warn("Unable to load comment reactions during synchronizing comment %d of %s/%s. Error: %v", comment.ID, g.repoOwner, g.repoName, err)
+		}
+
+		allComments = append(allComments, &base.Comment{
+			IssueIndex:  index,
+			PosterID:    comment.Poster.ID,
+			PosterName:  comment.Poster.UserName,
+			PosterEmail: comment.Poster.Email,
+			Content:     comment.Body,
+			Created:     comment.Created,
+			Updated:     comment.Updated,
+			Reactions:   reactions,
+		})
+	}
+
+	isEnd := len(comments) < opts.PageSize
+	if !g.pagination {
+		isEnd = len(comments) == 0
+	}
+	return allComments, isEnd, nil
+}
+
 // GetComments returns comments according issueNumber
 func (g *GiteaDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Comment, bool, error) {
+	if opts.Context == nil {
+		return g.getRepoComments(opts)
+	}
+
 	var allComments = make([]*base.Comment, 0, g.maxPerPage)
 
 	for i := 1; ; i++ {
@@ -467,10 +540,13 @@ func (g *GiteaDownloader) GetComments(opts base.GetCommentOptions) ([]*base.Comm
 		default:
 		}
 
-		comments, _, err := g.client.ListIssueComments(g.repoOwner, g.repoName, opts.Context.ForeignID(), gitea_sdk.ListIssueCommentOptions{ListOptions: gitea_sdk.ListOptions{
-			PageSize: g.maxPerPage,
-			Page:     i,
-		}})
+		comments, _, err := g.client.ListIssueComments(g.repoOwner, g.repoName, opts.Context.ForeignID(), gitea_sdk.ListIssueCommentOptions{
+			ListOptions: gitea_sdk.ListOptions{
+				PageSize: g.maxPerPage,
+				Page:     i,
+			},
+			Since: g.since,
+		})
 		if err != nil {
 			return nil, false, fmt.Errorf("error while listing comments for issue #%d. Error: %v", opts.Context.ForeignID(), err)
 		}
@@ -522,6 +598,9 @@ func (g *GiteaDownloader) GetPullRequests(page, perPage int) ([]*base.PullReques
 		return nil, false, fmt.Errorf("error while listing pull requests (page: %d, pagesize: %d). Error: %v", page, perPage, err)
 	}
 	for _, pr := range prs {
+		if pr.Updated != nil && pr.Updated.Before(g.since) {
+			continue
+		}
 		var milestone string
 		if pr.Milestone != nil {
 			milestone = pr.Milestone.Title
@@ -27,6 +27,7 @@ import (
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/uri"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/pull"
 
 	gouuid "github.com/google/uuid"
@@ -51,6 +52,7 @@ type GiteaLocalUploader struct {
 	userMap        map[int64]int64 // external user id mapping to user id
 	prCache        map[int64]*models.PullRequest
 	gitServiceType structs.GitServiceType
+	resume         bool // entities migrated before are skipped or updated
 }
 
 // NewGiteaLocalUploader creates an gitea Uploader via gitea API v1
@@ -92,6 +94,8 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 		return err
 	}
 
+	g.resume = opts.Resume || !opts.SyncSince.IsZero()
+
 	var r *models.Repository
 	if opts.MigrateToRepoID <= 0 {
 		r, err = repo_module.CreateRepository(g.doer, owner, models.CreateRepoOptions{
@@ -102,6 +106,7 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 			IsPrivate:      opts.Private,
 			IsMirror:       opts.Mirror,
 			Status:         models.RepositoryBeingMigrated,
+			CreatedFrom:    models.RepoCreatedFromMigrated,
 		})
 	} else {
 		r, err = models.GetRepositoryByID(opts.MigrateToRepoID)
@@ -109,6 +114,18 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 	if err != nil {
 		return err
 	}
+
+	if !opts.SyncSince.IsZero() {
+		g.repo = r
+		if err = g.fetchGitData(repo.CloneURL); err != nil {
+			return err
+		}
+		if g.gitRepo, err = git.OpenRepository(r.RepoPath()); err != nil {
+			return err
+		}
+		return g.loadMigrated()
+	}
+
 	r.DefaultBranch = repo.DefaultBranch
 
 	r, err = repository.MigrateRepositoryGitData(g.ctx, owner, r, base.MigrateOptions{
@@ -131,7 +148,44 @@ func (g *GiteaLocalUploader) CreateRepo(repo *base.Repository, opts base.Migrate
 		return err
 	}
 	g.gitRepo, err = git.OpenRepository(r.RepoPath())
-	return err
+	if err != nil || !g.resume {
+		return err
+	}
+	return g.loadMigrated()
+}
+
+// fetchGitData fetches the tags and the heads of the pull requests a repository which is synchronized
+// needs for new releases and pull requests, its branches are left as they are
+func (g *GiteaLocalUploader) fetchGitData(cloneURL string) error {
+	if _, err := git.NewCommandContext(g.ctx, "fetch", "--tags", cloneURL, "+refs/pull/*/head:refs/pull/*/head").
+		SetDescription(fmt.Sprintf("GiteaLocalUploader.fetchGitData: %s", g.repo.FullName())).
+		RunInDirTimeout(time.Duration(setting.Git.Timeout.Migrate)*time.Second, g.repo.RepoPath()); err != nil {
+		return util.NewStringURLSanitizedError(err, cloneURL, true)
+	}
+	return nil
+}
+
+// loadMigrated loads the labels and milestones migrated before, so they are neither duplicated nor lost on issues
+func (g *GiteaLocalUploader) loadMigrated() error {
+	labels, err := models.GetLabelsByRepoID(g.repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	for _, lb := range labels {
+		g.labels.Store(lb.Name, lb)
+	}
+
+	milestones, _, err := models.GetMilestones(models.GetMilestonesOption{
+		RepoID: g.repo.ID,
+		State:  structs.StateAll,
+	})
+	if err != nil {
+		return err
+	}
+	for _, ms := range milestones {
+		g.milestones.Store(ms.Name, ms.ID)
+	}
+	return nil
 }
 
 // Close closes this uploader
@@ -159,6 +213,10 @@ func (g *GiteaLocalUploader) CreateTopics(topics ...string) error {
 func (g *GiteaLocalUploader) CreateMilestones(milestones ...*base.Milestone) error {
 	var mss = make([]*models.Milestone, 0, len(milestones))
 	for _, milestone := range milestones {
+		if _, ok := g.milestones.Load(milestone.Title); ok && g.resume {
+			continue
+		}
+
 		var deadline timeutil.TimeStamp
 		if milestone.Deadline != nil {
 			deadline = timeutil.TimeStamp(milestone.Deadline.Unix())
@@ -194,6 +252,9 @@ func (g *GiteaLocalUploader) CreateMilestones(milestones ...*base.Milestone) err
 		}
 		mss = append(mss, &ms)
 	}
+	if len(mss) == 0 {
+		return nil
+	}
 
 	err := models.InsertMilestones(mss...)
 	if err != nil {
@@ -210,6 +271,9 @@ func (g *GiteaLocalUploader) CreateMilestones(milestones ...*base.Milestone) err
 func (g *GiteaLocalUploader) CreateLabels(labels ...*base.Label) error {
 	var lbs = make([]*models.Label, 0, len(labels))
 	for _, label := range labels {
+		if _, ok := g.labels.Load(label.Name); ok && g.resume {
+			continue
+		}
 		lbs = append(lbs, &models.Label{
 			RepoID:      g.repo.ID,
 			Name:        label.Name,
@@ -232,6 +296,22 @@ func (g *GiteaLocalUploader) CreateLabels(labels ...*base.Label) error {
 func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 	var rels = make([]*models.Release, 0, len(releases))
 	for _, release := range releases {
+		if g.resume {
+			rel, err := models.GetRelease(g.repo.ID, release.TagName)
+			if err != nil && !models.IsErrReleaseNotExist(err) {
+				return err
+			}
+			if rel != nil {
+				if !rel.IsTag {
+					continue
+				}
+				// the tag was synchronized without a release before, it is replaced by the release now
+				if err := models.DeleteReleaseByID(rel.ID); err != nil {
+					return err
+				}
+			}
+		}
+
 		if release.Created.IsZero() {
 			if !release.Published.IsZero() {
 				release.Created = release.Published
@@ -335,6 +415,9 @@ func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 
 		rels = append(rels, &rel)
 	}
+	if len(rels) == 0 {
+		return nil
+	}
 
 	return models.InsertReleases(rels...)
 }
@@ -348,6 +431,16 @@ func (g *GiteaLocalUploader) SyncTags() error {
 func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 	var iss = make([]*models.Issue, 0, len(issues))
 	for _, issue := range issues {
+		if g.resume {
+			migrated, err := g.updateMigratedIssue(issue.Number, issue.Title, issue.Content, issue.State, issue.IsLocked, issue.Updated, issue.Closed)
+			if err != nil {
+				return err
+			}
+			if migrated {
+				continue
+			}
+		}
+
 		var labels []*models.Label
 		for _, label := range issue.Labels {
 			lb, ok := g.labels.Load(label.Name)
@@ -460,6 +553,34 @@ func (g *GiteaLocalUploader) CreateIssues(issues ...*base.Issue) error {
 	return nil
 }
 
+// updateMigratedIssue updates the issue or pull request with the index if it was migrated before
+func (g *GiteaLocalUploader) updateMigratedIssue(index int64, title, content, state string, isLocked bool, updated time.Time, closed *time.Time) (bool, error) {
+	issue, err := models.GetIssueByIndex(g.repo.ID, index)
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	issue.Title = title
+	issue.Content = content
+	issue.IsClosed = state == "closed"
+	issue.IsLocked = isLocked
+	if !updated.IsZero() {
+		issue.UpdatedUnix = timeutil.TimeStamp(updated.Unix())
+	}
+	issue.ClosedUnix = 0
+	if issue.IsClosed && closed != nil {
+		issue.ClosedUnix = timeutil.TimeStamp(closed.Unix())
+	}
+	if err := models.UpdateMigratedIssue(issue); err != nil {
+		return false, err
+	}
+	g.issues.Store(issue.Index, issue)
+	return true, nil
+}
+
 // CreateComments creates comments of issues
 func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 	var cms = make([]*models.Comment, 0, len(comments))
@@ -470,6 +591,11 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 			var err error
 			issue, err = models.GetIssueByIndex(g.repo.ID, comment.IssueIndex)
 			if err != nil {
+				// comments of a synchronized repository can belong to issues which are not migrated
+				if g.resume && models.IsErrIssueNotExist(err) {
+					log.Warn("Skip comment on missing issue #%d of %s", comment.IssueIndex, g.repo.FullName())
+					continue
+				}
 				return err
 			}
 			g.issues.Store(comment.IssueIndex, issue)
@@ -513,6 +639,23 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 			cm.OriginalAuthorID = comment.PosterID
 		}
 
+		if g.resume {
+			migrated, err := models.GetMigratedComment(&cm)
+			if err != nil {
+				return err
+			}
+			if migrated != nil {
+				if migrated.Content != cm.Content {
+					migrated.Content = cm.Content
+					migrated.UpdatedUnix = cm.UpdatedUnix
+					if err := models.UpdateMigratedComment(migrated); err != nil {
+						return err
+					}
+				}
+				continue
+			}
+		}
+
 		// add reactions
 		for _, reaction := range comment.Reactions {
 			userid, ok := g.userMap[reaction.UserID]
@@ -553,6 +696,16 @@ func (g *GiteaLocalUploader) CreateComments(comments ...*base.Comment) error {
 func (g *GiteaLocalUploader) CreatePullRequests(prs ...*base.PullRequest) error {
 	var gprs = make([]*models.PullRequest, 0, len(prs))
 	for _, pr := range prs {
+		if g.resume {
+			migrated, err := g.updateMigratedPullRequest(pr)
+			if err != nil {
+				return err
+			}
+			if migrated {
+				continue
+			}
+		}
+
 		gpr, err := g.newPullRequest(pr)
 		if err != nil {
 			return err
@@ -581,6 +734,9 @@ func (g *GiteaLocalUploader) CreatePullRequests(prs ...*base.PullRequest) error
 
 		gprs = append(gprs, gpr)
 	}
+	if len(gprs) == 0 {
+		return nil
+	}
 	if err := models.InsertPullRequests(gprs...); err != nil {
 		return err
 	}
@@ -591,6 +747,30 @@ func (g *GiteaLocalUploader) CreatePullRequests(prs ...*base.PullRequest) error
 	return nil
 }
 
+// updateMigratedPullRequest updates the pull request if it was migrated before
+func (g *GiteaLocalUploader) updateMigratedPullRequest(pr *base.PullRequest) (bool, error) {
+	migrated, err := g.updateMigratedIssue(pr.Number, pr.Title, pr.Content, pr.State, pr.IsLocked, pr.Updated, pr.Closed)
+	if err != nil || !migrated || !pr.Merged {
+		return migrated, err
+	}
+
+	issue, _ := g.issues.Load(pr.Number)
+	gpr, err := models.GetPullRequestByIssueIDWithNoAttributes(issue.(*models.Issue).ID)
+	if err != nil {
+		return false, err
+	}
+	if gpr.HasMerged {
+		return true, nil
+	}
+	gpr.HasMerged = true
+	gpr.MergedCommitID = pr.MergeCommitSHA
+	gpr.MergerID = g.doer.ID
+	if pr.MergedTime != nil {
+		gpr.MergedUnix = timeutil.TimeStamp(pr.MergedTime.Unix())
+	}
+	return true, models.UpdateMigratedPullRequest(gpr)
+}
+
 func (g *GiteaLocalUploader) newPullRequest(pr *base.PullRequest) (*models.PullRequest, error) {
 	var labels []*models.Label
 	for _, label := range pr.Labels {
@@ -878,6 +1058,16 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 			cm.OriginalAuthorID = review.ReviewerID
 		}
 
+		if g.resume {
+			exist, err := models.IsMigratedReviewExist(&cm)
+			if err != nil {
+				return err
+			}
+			if exist {
+				continue
+			}
+		}
+
 		// get pr
 		pr, ok := g.prCache[issue.ID]
 		if !ok {
@@ -951,6 +1141,9 @@ func (g *GiteaLocalUploader) CreateReviews(reviews ...*base.Review) error {
 
 		cms = append(cms, &cm)
 	}
+	if len(cms) == 0 {
+		return nil
+	}
 
 	return models.InsertReviews(cms)
 }
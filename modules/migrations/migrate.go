@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/matchlist"
 	"code.gitea.io/gitea/modules/migrations/base"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 )
 
@@ -89,7 +90,7 @@ func IsMigrateURLAllowed(remoteURL string, doer *models.User) error {
 			return &models.ErrInvalidCloneAddr{Host: u.Host, NotResolvedIP: true}
 		}
 		for _, addr := range addrList {
-			if isIPPrivate(addr) || !addr.IsGlobalUnicast() {
+			if !IsIPAllowed(addr) {
 				return &models.ErrInvalidCloneAddr{Host: u.Host, PrivateNet: addr.String(), IsPermissionDenied: true}
 			}
 		}
@@ -98,8 +99,10 @@ func IsMigrateURLAllowed(remoteURL string, doer *models.User) error {
 	return nil
 }
 
-// MigrateRepository migrate repository according MigrateOptions
-func MigrateRepository(ctx context.Context, doer *models.User, ownerName string, opts base.MigrateOptions, messenger base.Messenger) (*models.Repository, error) {
+// MigrateRepository migrate repository according MigrateOptions.
+// If a migration from another Gitea instance fails after the repository has been created, the repository is kept
+// and an ErrMigrationIncomplete is returned, the migration can be resumed with opts.Resume then.
+func MigrateRepository(ctx context.Context, doer *models.User, ownerName string, opts base.MigrateOptions, messenger base.Messenger, recorder base.ProgressRecorder) (*models.Repository, error) {
 	err := IsMigrateURLAllowed(opts.CloneAddr, doer)
 	if err != nil {
 		return nil, err
@@ -118,13 +121,18 @@ func MigrateRepository(ctx context.Context, doer *models.User, ownerName string,
 	var uploader = NewGiteaLocalUploader(ctx, doer, ownerName, opts.RepoName)
 	uploader.gitServiceType = opts.GitServiceType
 
-	if err := migrateRepository(downloader, uploader, opts, messenger); err != nil {
-		if err1 := uploader.Rollback(); err1 != nil {
-			log.Error("rollback failed: %v", err1)
-		}
+	if err := migrateRepository(downloader, uploader, opts, messenger, recorder); err != nil {
 		if err2 := models.CreateRepositoryNotice(fmt.Sprintf("Migrate repository from %s failed: %v", opts.OriginalURL, err)); err2 != nil {
 			log.Error("create respotiry notice failed: ", err2)
 		}
+		// a resumed or synchronized repository is never rolled back,
+		// a migration from gitea can be resumed once the git data is there
+		if opts.Resume || !opts.SyncSince.IsZero() || (opts.GitServiceType == structs.GiteaService && uploader.gitRepo != nil) {
+			return uploader.repo, &ErrMigrationIncomplete{Err: err}
+		}
+		if err1 := uploader.Rollback(); err1 != nil {
+			log.Error("rollback failed: %v", err1)
+		}
 		return nil, err
 	}
 	return uploader.repo, nil
@@ -167,10 +175,14 @@ func newDownloader(ctx context.Context, ownerName string, opts base.MigrateOptio
 // migrateRepository will download information and then upload it to Uploader, this is a simple
 // process for small repository. For a big repository, save all the data to disk
 // before upload is better
-func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts base.MigrateOptions, messenger base.Messenger) error {
+func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts base.MigrateOptions, messenger base.Messenger, recorder base.ProgressRecorder) error {
 	if messenger == nil {
 		messenger = base.NilMessenger
 	}
+	if recorder == nil {
+		recorder = base.NilProgressRecorder
+	}
+	var progress base.MigrateProgress
 
 	repo, err := downloader.GetRepoInfo()
 	if err != nil {
@@ -278,6 +290,8 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 			if err = uploader.CreateReleases(releases[:relBatchSize]...); err != nil {
 				return err
 			}
+			progress.ReleaseTag = releases[relBatchSize-1].TagName
+			recorder(progress)
 			releases = releases[relBatchSize:]
 		}
 
@@ -345,6 +359,11 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 				}
 			}
 
+			if len(issues) > 0 {
+				progress.IssueIndex = issues[len(issues)-1].Number
+				recorder(progress)
+			}
+
 			if isEnd {
 				break
 			}
@@ -429,6 +448,11 @@ func migrateRepository(downloader base.Downloader, uploader base.Uploader, opts
 				}
 			}
 
+			if len(prs) > 0 {
+				progress.PullRequestIndex = prs[len(prs)-1].Number
+				recorder(progress)
+			}
+
 			if isEnd {
 				break
 			}
@@ -475,6 +499,11 @@ func Init() error {
 	return nil
 }
 
+// IsIPAllowed checks if the server may connect to ip to fetch remote content on behalf of a user
+func IsIPAllowed(ip net.IP) bool {
+	return setting.Migrations.AllowLocalNetworks || (!isIPPrivate(ip) && ip.IsGlobalUnicast())
+}
+
 // TODO: replace with `ip.IsPrivate()` if min go version is bumped to 1.17
 func isIPPrivate(ip net.IP) bool {
 	if ip4 := ip.To4(); ip4 != nil {
@@ -177,6 +177,20 @@ func (a *actionNotifier) NotifyTransferRepository(doer *models.User, repo *model
 	}
 }
 
+func (a *actionNotifier) NotifyDetachFork(doer *models.User, repo *models.Repository, formerBaseName string) {
+	if err := models.NotifyWatchers(&models.Action{
+		ActUserID: doer.ID,
+		ActUser:   doer,
+		OpType:    models.ActionDetachFork,
+		RepoID:    repo.ID,
+		Repo:      repo,
+		IsPrivate: repo.IsPrivate,
+		Content:   formerBaseName,
+	}); err != nil {
+		log.Error("NotifyWatchers: %v", err)
+	}
+}
+
 func (a *actionNotifier) NotifyCreateRepository(doer *models.User, u *models.User, repo *models.Repository) {
 	if err := models.NotifyWatchers(&models.Action{
 		ActUserID: doer.ID,
@@ -326,6 +340,48 @@ func (a *actionNotifier) NotifyPushCommits(pusher *models.User, repo *models.Rep
 	}); err != nil {
 		log.Error("notifyWatchers: %v", err)
 	}
+
+	if opType == models.ActionCommitRepo {
+		notifyCommitAuthors(pusher, repo, opts, commits)
+	}
+}
+
+// notifyCommitAuthors credits the authors of pushed commits other than the pusher with the commits they authored,
+// in their own feeds and contributions
+func notifyCommitAuthors(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
+	groups, err := commits.GroupByAuthor()
+	if err != nil {
+		log.Error("GroupByAuthor: %v", err)
+		return
+	}
+
+	actions := make([]*models.Action, 0, len(groups))
+	for _, group := range groups {
+		if group.Author.ID == pusher.ID || group.Author.IsOrganization() {
+			continue
+		}
+		data, err := json.Marshal(group.Commits)
+		if err != nil {
+			log.Error("Marshal: %v", err)
+			return
+		}
+		actions = append(actions, &models.Action{
+			ActUserID: group.Author.ID,
+			ActUser:   group.Author,
+			OpType:    models.ActionAuthorCommitRepo,
+			Content:   string(data),
+			RepoID:    repo.ID,
+			Repo:      repo,
+			RefName:   opts.RefFullName,
+			IsPrivate: repo.IsPrivate,
+		})
+	}
+	if len(actions) == 0 {
+		return
+	}
+	if err := models.NotifyAuthors(actions...); err != nil {
+		log.Error("NotifyAuthors: %v", err)
+	}
 }
 
 func (a *actionNotifier) NotifyCreateRef(doer *models.User, repo *models.Repository, refType, refFullName string) {
@@ -11,6 +11,9 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repository"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -46,3 +49,49 @@ func TestRenameRepoAction(t *testing.T) {
 	db.AssertExistsAndLoadBean(t, actionBean)
 	models.CheckConsistencyFor(t, &models.Action{})
 }
+
+func TestPushCommitsCreditsAuthors(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	pusher := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	repo.Owner = pusher
+
+	commits := repository.NewPushCommits()
+	commits.Commits = []*repository.PushCommit{
+		{Sha1: "abcdef1", AuthorEmail: "user4@example.com", Message: "authored by user4"},
+		{Sha1: "abcdef2", AuthorEmail: "user2@example.com", Message: "authored by the pusher"},
+		{Sha1: "abcdef3", AuthorEmail: "nobody@example.com", Message: "authored without an account"},
+	}
+	commits.HeadCommit = commits.Commits[0]
+	commits.Len = 3
+	opts := &repository.PushUpdateOptions{
+		PusherID:    pusher.ID,
+		RefFullName: git.BranchPrefix + "master",
+		OldCommitID: "1111111111111111111111111111111111111111",
+		NewCommitID: "2222222222222222222222222222222222222222",
+	}
+
+	NewNotifier().NotifyPushCommits(pusher, repo, opts, commits)
+
+	db.AssertExistsAndLoadBean(t, &models.Action{OpType: models.ActionCommitRepo, UserID: 2, ActUserID: 2, RepoID: 1})
+	authored := db.AssertExistsAndLoadBean(t, &models.Action{OpType: models.ActionAuthorCommitRepo, UserID: 4, ActUserID: 4, RepoID: 1}).(*models.Action)
+	assert.Contains(t, authored.Content, "abcdef1")
+	assert.NotContains(t, authored.Content, "abcdef2")
+	// the pusher is not credited twice and the watchers are not notified of the authored commits
+	assert.EqualValues(t, 1, db.GetCount(t, &models.Action{OpType: models.ActionAuthorCommitRepo}))
+	models.CheckConsistencyFor(t, &models.Action{})
+
+	// the authored commits count for the author, unless they keep their activity private
+	author := db.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	heatmap, err := models.GetUserHeatmapDataByUser(author, author)
+	assert.NoError(t, err)
+	assert.Len(t, heatmap, 1)
+	author.KeepActivityPrivate = true
+	heatmap, err = models.GetUserHeatmapDataByUser(author, pusher)
+	assert.NoError(t, err)
+	assert.Empty(t, heatmap)
+	feeds, err := models.GetFeeds(models.GetFeedsOptions{RequestedUser: author, Actor: pusher, OnlyPerformedBy: true})
+	assert.NoError(t, err)
+	assert.Empty(t, feeds)
+}
@@ -19,12 +19,14 @@ type Notifier interface {
 	NotifyForkRepository(doer *models.User, oldRepo, repo *models.Repository)
 	NotifyRenameRepository(doer *models.User, repo *models.Repository, oldRepoName string)
 	NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string)
+	NotifyDetachFork(doer *models.User, repo *models.Repository, formerBaseName string)
 
 	NotifyNewIssue(issue *models.Issue, mentions []*models.User)
 	NotifyIssueChangeStatus(*models.User, *models.Issue, *models.Comment, bool)
 	NotifyIssueChangeMilestone(doer *models.User, issue *models.Issue, oldMilestoneID int64)
 	NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment)
 	NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment)
+	NotifyPullTeamReviewRequest(doer *models.User, issue *models.Issue, team *models.Team, isRequest bool, comment *models.Comment)
 	NotifyIssueChangeContent(doer *models.User, issue *models.Issue, oldContent string)
 	NotifyIssueClearLabels(doer *models.User, issue *models.Issue)
 	NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string)
@@ -38,8 +40,10 @@ type Notifier interface {
 	NotifyPullRequestReview(pr *models.PullRequest, review *models.Review, comment *models.Comment, mentions []*models.User)
 	NotifyPullRequestCodeComment(pr *models.PullRequest, comment *models.Comment, mentions []*models.User)
 	NotifyPullRequestChangeTargetBranch(doer *models.User, pr *models.PullRequest, oldBranch string)
+	NotifyPullRequestReadyForReview(doer *models.User, pr *models.PullRequest)
 	NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, comment *models.Comment)
 	NotifyPullRevieweDismiss(doer *models.User, review *models.Review, comment *models.Comment)
+	NotifyPullRequestMergeQueueEvicted(doer *models.User, pr *models.PullRequest, comment *models.Comment)
 
 	NotifyCreateIssueComment(doer *models.User, repo *models.Repository,
 		issue *models.Issue, comment *models.Comment, mentions []*models.User)
@@ -59,4 +63,9 @@ type Notifier interface {
 	NotifySyncDeleteRef(doer *models.User, repo *models.Repository, refType, refFullName string)
 
 	NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository)
+
+	NotifyAddDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey)
+	NotifyDeleteDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey)
+	NotifyAddPublicKey(doer, owner *models.User, key *models.PublicKey)
+	NotifyDeletePublicKey(doer, owner *models.User, key *models.PublicKey)
 }
@@ -58,6 +58,10 @@ func (*NullNotifier) NotifyPullRequestSynchronized(doer *models.User, pr *models
 func (*NullNotifier) NotifyPullRequestChangeTargetBranch(doer *models.User, pr *models.PullRequest, oldBranch string) {
 }
 
+// NotifyPullRequestReadyForReview places a place holder function
+func (*NullNotifier) NotifyPullRequestReadyForReview(doer *models.User, pr *models.PullRequest) {
+}
+
 // NotifyPullRequestPushCommits notifies when push commits to pull request's head branch
 func (*NullNotifier) NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
 }
@@ -66,6 +70,10 @@ func (*NullNotifier) NotifyPullRequestPushCommits(doer *models.User, pr *models.
 func (*NullNotifier) NotifyPullRevieweDismiss(doer *models.User, review *models.Review, comment *models.Comment) {
 }
 
+// NotifyPullRequestMergeQueueEvicted places a place holder function
+func (*NullNotifier) NotifyPullRequestMergeQueueEvicted(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
+}
+
 // NotifyUpdateComment places a place holder function
 func (*NullNotifier) NotifyUpdateComment(doer *models.User, c *models.Comment, oldContent string) {
 }
@@ -102,6 +110,10 @@ func (*NullNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *models.
 func (*NullNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *models.User, isRequest bool, comment *models.Comment) {
 }
 
+// NotifyPullTeamReviewRequest places a place holder function
+func (*NullNotifier) NotifyPullTeamReviewRequest(doer *models.User, issue *models.Issue, team *models.Team, isRequest bool, comment *models.Comment) {
+}
+
 // NotifyIssueClearLabels places a place holder function
 func (*NullNotifier) NotifyIssueClearLabels(doer *models.User, issue *models.Issue) {
 }
@@ -155,6 +167,10 @@ func (*NullNotifier) NotifyRenameRepository(doer *models.User, repo *models.Repo
 func (*NullNotifier) NotifyTransferRepository(doer *models.User, repo *models.Repository, oldOwnerName string) {
 }
 
+// NotifyDetachFork places a place holder function
+func (*NullNotifier) NotifyDetachFork(doer *models.User, repo *models.Repository, formerBaseName string) {
+}
+
 // NotifySyncPushCommits places a place holder function
 func (*NullNotifier) NotifySyncPushCommits(pusher *models.User, repo *models.Repository, opts *repository.PushUpdateOptions, commits *repository.PushCommits) {
 }
@@ -170,3 +186,19 @@ func (*NullNotifier) NotifySyncDeleteRef(doer *models.User, repo *models.Reposit
 // NotifyRepoPendingTransfer places a place holder function
 func (*NullNotifier) NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository) {
 }
+
+// NotifyAddDeployKey places a place holder function
+func (*NullNotifier) NotifyAddDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey) {
+}
+
+// NotifyDeleteDeployKey places a place holder function
+func (*NullNotifier) NotifyDeleteDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey) {
+}
+
+// NotifyAddPublicKey places a place holder function
+func (*NullNotifier) NotifyAddPublicKey(doer, owner *models.User, key *models.PublicKey) {
+}
+
+// NotifyDeletePublicKey places a place holder function
+func (*NullNotifier) NotifyDeletePublicKey(doer, owner *models.User, key *models.PublicKey) {
+}
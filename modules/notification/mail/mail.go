@@ -79,9 +79,18 @@ func (m *mailNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.I
 		return
 	}
 	if issue.IsPull && models.HasWorkInProgressPrefix(oldTitle) && !issue.PullRequest.IsWorkInProgress() {
-		if err := mailer.MailParticipants(issue, doer, models.ActionPullRequestReadyForReview, nil); err != nil {
-			log.Error("MailParticipants: %v", err)
-		}
+		issue.PullRequest.Issue = issue
+		m.NotifyPullRequestReadyForReview(doer, issue.PullRequest)
+	}
+}
+
+func (m *mailNotifier) NotifyPullRequestReadyForReview(doer *models.User, pr *models.PullRequest) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	if err := mailer.MailParticipants(pr.Issue, doer, models.ActionPullRequestReadyForReview, nil); err != nil {
+		log.Error("MailParticipants: %v", err)
 	}
 }
 
@@ -130,6 +139,28 @@ func (m *mailNotifier) NotifyPullReviewRequest(doer *models.User, issue *models.
 	}
 }
 
+func (m *mailNotifier) NotifyPullTeamReviewRequest(doer *models.User, issue *models.Issue, team *models.Team, isRequest bool, comment *models.Comment) {
+	if !isRequest || team.ReviewNotification != models.TeamReviewNotificationEmailAll {
+		return
+	}
+
+	// members' own email preferences still apply
+	reviewers := make([]*models.User, 0, len(team.Members))
+	for _, member := range team.Members {
+		if member.ID != doer.ID && member.ID != issue.PosterID && member.EmailNotifications() == models.EmailNotificationsEnabled {
+			reviewers = append(reviewers, member)
+		}
+	}
+	if len(reviewers) == 0 {
+		return
+	}
+
+	ct := fmt.Sprintf("Requested to review %s.", issue.HTMLURL())
+	if err := mailer.SendIssueAssignedMail(issue, doer, ct, comment, reviewers); err != nil {
+		log.Error("Error in SendIssueAssignedMail for issue[%d] to team[%d]: %v", issue.ID, team.ID, err)
+	}
+}
+
 func (m *mailNotifier) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
 	if err := pr.LoadIssue(); err != nil {
 		log.Error("pr.LoadIssue: %v", err)
@@ -170,6 +201,12 @@ func (m *mailNotifier) NotifyPullRevieweDismiss(doer *models.User, review *model
 	}
 }
 
+func (m *mailNotifier) NotifyPullRequestMergeQueueEvicted(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
+	if err := mailer.MailParticipantsComment(comment, models.ActionCommentPull, pr.Issue, nil); err != nil {
+		log.Error("MailParticipantsComment: %v", err)
+	}
+}
+
 func (m *mailNotifier) NotifyNewRelease(rel *models.Release) {
 	if err := rel.LoadAttributes(); err != nil {
 		log.Error("NotifyNewRelease: %v", err)
@@ -101,6 +101,13 @@ func NotifyPullRequestChangeTargetBranch(doer *models.User, pr *models.PullReque
 	}
 }
 
+// NotifyPullRequestReadyForReview notifies when a draft pull request was marked as ready for review
+func NotifyPullRequestReadyForReview(doer *models.User, pr *models.PullRequest) {
+	for _, notifier := range notifiers {
+		notifier.NotifyPullRequestReadyForReview(doer, pr)
+	}
+}
+
 // NotifyPullRequestPushCommits notifies when push commits to pull request's head branch
 func NotifyPullRequestPushCommits(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
 	for _, notifier := range notifiers {
@@ -115,6 +122,13 @@ func NotifyPullRevieweDismiss(doer *models.User, review *models.Review, comment
 	}
 }
 
+// NotifyPullRequestMergeQueueEvicted notifies when a pull request was evicted from the merge queue
+func NotifyPullRequestMergeQueueEvicted(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyPullRequestMergeQueueEvicted(doer, pr, comment)
+	}
+}
+
 // NotifyUpdateComment notifies update comment to notifiers
 func NotifyUpdateComment(doer *models.User, c *models.Comment, oldContent string) {
 	for _, notifier := range notifiers {
@@ -178,6 +192,13 @@ func NotifyPullReviewRequest(doer *models.User, issue *models.Issue, reviewer *m
 	}
 }
 
+// NotifyPullTeamReviewRequest notifies Request Review change for a team, team.Members must be loaded
+func NotifyPullTeamReviewRequest(doer *models.User, issue *models.Issue, team *models.Team, isRequest bool, comment *models.Comment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyPullTeamReviewRequest(doer, issue, team, isRequest, comment)
+	}
+}
+
 // NotifyIssueClearLabels notifies clear labels to notifiers
 func NotifyIssueClearLabels(doer *models.User, issue *models.Issue) {
 	for _, notifier := range notifiers {
@@ -228,6 +249,13 @@ func NotifyTransferRepository(doer *models.User, repo *models.Repository, newOwn
 	}
 }
 
+// NotifyDetachFork notifies detaching a fork from its base repository to notifiers
+func NotifyDetachFork(doer *models.User, repo *models.Repository, formerBaseName string) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDetachFork(doer, repo, formerBaseName)
+	}
+}
+
 // NotifyDeleteRepository notifies delete repository to notifiers
 func NotifyDeleteRepository(doer *models.User, repo *models.Repository) {
 	for _, notifier := range notifiers {
@@ -297,3 +325,31 @@ func NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Reposit
 		notifier.NotifyRepoPendingTransfer(doer, newOwner, repo)
 	}
 }
+
+// NotifyAddDeployKey notifies adding a deploy key to a repository to notifiers
+func NotifyAddDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey) {
+	for _, notifier := range notifiers {
+		notifier.NotifyAddDeployKey(doer, repo, key)
+	}
+}
+
+// NotifyDeleteDeployKey notifies deleting a deploy key of a repository to notifiers
+func NotifyDeleteDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteDeployKey(doer, repo, key)
+	}
+}
+
+// NotifyAddPublicKey notifies adding an SSH key to a user to notifiers
+func NotifyAddPublicKey(doer, owner *models.User, key *models.PublicKey) {
+	for _, notifier := range notifiers {
+		notifier.NotifyAddPublicKey(doer, owner, key)
+	}
+}
+
+// NotifyDeletePublicKey notifies deleting an SSH key of a user to notifiers
+func NotifyDeletePublicKey(doer, owner *models.User, key *models.PublicKey) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeletePublicKey(doer, owner, key)
+	}
+}
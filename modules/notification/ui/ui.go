@@ -100,13 +100,17 @@ func (ns *notificationService) NotifyIssueChangeTitle(doer *models.User, issue *
 		return
 	}
 	if issue.IsPull && models.HasWorkInProgressPrefix(oldTitle) && !issue.PullRequest.IsWorkInProgress() {
-		_ = ns.issueQueue.Push(issueNotificationOpts{
-			IssueID:              issue.ID,
-			NotificationAuthorID: doer.ID,
-		})
+		ns.NotifyPullRequestReadyForReview(doer, issue.PullRequest)
 	}
 }
 
+func (ns *notificationService) NotifyPullRequestReadyForReview(doer *models.User, pr *models.PullRequest) {
+	_ = ns.issueQueue.Push(issueNotificationOpts{
+		IssueID:              pr.IssueID,
+		NotificationAuthorID: doer.ID,
+	})
+}
+
 func (ns *notificationService) NotifyMergePullRequest(pr *models.PullRequest, doer *models.User) {
 	_ = ns.issueQueue.Push(issueNotificationOpts{
 		IssueID:              pr.Issue.ID,
@@ -200,6 +204,21 @@ func (ns *notificationService) NotifyPullRevieweDismiss(doer *models.User, revie
 	_ = ns.issueQueue.Push(opts)
 }
 
+func (ns *notificationService) NotifyPullRequestMergeQueueEvicted(doer *models.User, pr *models.PullRequest, comment *models.Comment) {
+	_ = ns.issueQueue.Push(issueNotificationOpts{
+		IssueID:              pr.IssueID,
+		NotificationAuthorID: doer.ID,
+		CommentID:            comment.ID,
+	})
+	// the user who added the pull request to the queue didn't cause the eviction
+	_ = ns.issueQueue.Push(issueNotificationOpts{
+		IssueID:              pr.IssueID,
+		NotificationAuthorID: doer.ID,
+		ReceiverID:           doer.ID,
+		CommentID:            comment.ID,
+	})
+}
+
 func (ns *notificationService) NotifyIssueChangeAssignee(doer *models.User, issue *models.Issue, assignee *models.User, removed bool, comment *models.Comment) {
 	if !removed {
 		var opts = issueNotificationOpts{
@@ -232,6 +251,19 @@ func (ns *notificationService) NotifyPullReviewRequest(doer *models.User, issue
 	}
 }
 
+func (ns *notificationService) NotifyPullTeamReviewRequest(doer *models.User, issue *models.Issue, team *models.Team, isRequest bool, comment *models.Comment) {
+	if !isRequest {
+		return
+	}
+
+	for _, member := range team.Members {
+		if member.ID == issue.PosterID {
+			continue
+		}
+		ns.NotifyPullReviewRequest(doer, issue, member, isRequest, comment)
+	}
+}
+
 func (ns *notificationService) NotifyRepoPendingTransfer(doer, newOwner *models.User, repo *models.Repository) {
 	if err := models.CreateRepoTransferNotification(doer, newOwner, repo); err != nil {
 		log.Error("NotifyRepoPendingTransfer: %v", err)
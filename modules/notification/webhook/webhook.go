@@ -122,6 +122,53 @@ func (m *webhookNotifier) NotifyDeleteRepository(doer *models.User, repo *models
 	}
 }
 
+func (m *webhookNotifier) notifyDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey, action api.HookKeyAction) {
+	if err := webhook_services.PrepareWebhooks(repo, models.HookEventDeployKey, &api.DeployKeyPayload{
+		Action: action,
+		Key: &api.PayloadKey{
+			ID:          key.ID,
+			Title:       key.Name,
+			Fingerprint: key.Fingerprint,
+		},
+		ReadOnly:   key.IsReadOnly(),
+		Repository: convert.ToRepo(repo, models.AccessModeOwner),
+		Sender:     convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks [repo_id: %d]: %v", repo.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyAddDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey) {
+	m.notifyDeployKey(doer, repo, key, api.HookKeyAdded)
+}
+
+func (m *webhookNotifier) NotifyDeleteDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey) {
+	m.notifyDeployKey(doer, repo, key, api.HookKeyRemoved)
+}
+
+func (m *webhookNotifier) notifyPublicKey(doer, owner *models.User, key *models.PublicKey, action api.HookKeyAction) {
+	if err := webhook_services.PrepareSystemWebhooks(models.HookEventPublicKey, &api.PublicKeyPayload{
+		Action: action,
+		Key: &api.PayloadKey{
+			ID:          key.ID,
+			Title:       key.Name,
+			Fingerprint: key.Fingerprint,
+		},
+		Owner:  convert.ToUser(owner, nil),
+		Sender: convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareSystemWebhooks [key_id: %d]: %v", key.ID, err)
+	}
+}
+
+func (m *webhookNotifier) NotifyAddPublicKey(doer, owner *models.User, key *models.PublicKey) {
+	m.notifyPublicKey(doer, owner, key, api.HookKeyAdded)
+}
+
+func (m *webhookNotifier) NotifyDeletePublicKey(doer, owner *models.User, key *models.PublicKey) {
+	m.notifyPublicKey(doer, owner, key, api.HookKeyRemoved)
+}
+
 func (m *webhookNotifier) NotifyMigrateRepository(doer *models.User, u *models.User, repo *models.Repository) {
 	// Add to hook queue for created repo after session commit.
 	if err := webhook_services.PrepareWebhooks(repo, models.HookEventRepository, &api.RepositoryPayload{
@@ -180,6 +227,41 @@ func (m *webhookNotifier) NotifyIssueChangeAssignee(doer *models.User, issue *mo
 	}
 }
 
+func (m *webhookNotifier) NotifyPullTeamReviewRequest(doer *models.User, issue *models.Issue, team *models.Team, isRequest bool, comment *models.Comment) {
+	if !isRequest || team.ReviewNotification != models.TeamReviewNotificationWebhookOnly {
+		return
+	}
+
+	w, err := models.GetWebhookByTeamID(team.ID)
+	if err != nil {
+		if !models.IsErrWebhookNotExist(err) {
+			log.Error("GetWebhookByTeamID [team: %d]: %v", team.ID, err)
+		}
+		return
+	}
+	if !w.IsActive {
+		return
+	}
+
+	if err := issue.LoadPullRequest(); err != nil {
+		log.Error("LoadPullRequest failed: %v", err)
+		return
+	}
+	issue.PullRequest.Issue = issue
+
+	mode, _ := models.AccessLevelUnit(doer, issue.Repo, models.UnitTypePullRequests)
+	if err := webhook_services.PrepareWebhook(w, issue.Repo, models.HookEventPullRequest, &api.PullRequestPayload{
+		Action:        api.HookIssueReviewRequested,
+		Index:         issue.Index,
+		PullRequest:   convert.ToAPIPullRequest(issue.PullRequest, nil),
+		Repository:    convert.ToRepo(issue.Repo, mode),
+		Sender:        convert.ToUser(doer, nil),
+		RequestedTeam: convert.ToTeam(team),
+	}); err != nil {
+		log.Error("PrepareWebhook [team: %d]: %v", team.ID, err)
+	}
+}
+
 func (m *webhookNotifier) NotifyIssueChangeTitle(doer *models.User, issue *models.Issue, oldTitle string) {
 	mode, _ := models.AccessLevel(issue.Poster, issue.Repo)
 	var err error
@@ -201,6 +283,9 @@ func (m *webhookNotifier) NotifyIssueChangeTitle(doer *models.User, issue *model
 			Repository:  convert.ToRepo(issue.Repo, mode),
 			Sender:      convert.ToUser(doer, nil),
 		})
+		if err == nil && models.HasWorkInProgressPrefix(oldTitle) && !issue.PullRequest.IsWorkInProgress() {
+			m.NotifyPullRequestReadyForReview(doer, issue.PullRequest)
+		}
 	} else {
 		err = webhook_services.PrepareWebhooks(issue.Repo, models.HookEventIssues, &api.IssuePayload{
 			Action: api.HookIssueEdited,
@@ -652,6 +737,32 @@ func (m *webhookNotifier) NotifyPullRequestChangeTargetBranch(doer *models.User,
 	}
 }
 
+func (m *webhookNotifier) NotifyPullRequestReadyForReview(doer *models.User, pr *models.PullRequest) {
+	if err := pr.LoadIssue(); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		log.Error("LoadPoster: %v", err)
+		return
+	}
+
+	mode, _ := models.AccessLevel(pr.Issue.Poster, pr.Issue.Repo)
+	if err := webhook_services.PrepareWebhooks(pr.Issue.Repo, models.HookEventPullRequest, &api.PullRequestPayload{
+		Action:      api.HookIssueReadyForReview,
+		Index:       pr.Issue.Index,
+		PullRequest: convert.ToAPIPullRequest(pr, nil),
+		Repository:  convert.ToRepo(pr.Issue.Repo, mode),
+		Sender:      convert.ToUser(doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
 func (m *webhookNotifier) NotifyPullRequestReview(pr *models.PullRequest, review *models.Review, comment *models.Comment, mentions []*models.User) {
 	var reviewHookType models.HookEventType
 
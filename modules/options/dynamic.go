@@ -91,6 +91,11 @@ func Labels(name string) ([]byte, error) {
 	return fileFromDir(path.Join("label", name))
 }
 
+// SecretScan reads the content of a specific secret scan rule set from static or custom path.
+func SecretScan(name string) ([]byte, error) {
+	return fileFromDir(path.Join("secretscan", name))
+}
+
 // fileFromDir is a helper to read files from static or custom path.
 func fileFromDir(name string) ([]byte, error) {
 	customPath := path.Join(setting.CustomPath, "options", name)
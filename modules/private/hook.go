@@ -30,8 +30,11 @@ type GitPushOptions map[string]string
 
 // GitPushOptions keys
 const (
-	GitPushOptionRepoPrivate  = "repo.private"
-	GitPushOptionRepoTemplate = "repo.template"
+	GitPushOptionRepoPrivate     = "repo.private"
+	GitPushOptionRepoTemplate    = "repo.template"
+	GitPushOptionRepoDescription = "repo.description"
+	GitPushOptionTopic           = "topic"
+	GitPushOptionSecretsSkip     = "secrets.skip"
 )
 
 // Bool checks for a key in the map and parses as a boolean
@@ -70,6 +73,7 @@ type SSHLogOption struct {
 type HookPostReceiveResult struct {
 	Results      []HookPostReceiveBranchResult
 	RepoWasEmpty bool
+	Messages     []string // printed back to the pusher, e.g. about the push options
 	Err          string
 }
 
@@ -0,0 +1,114 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mc "gitea.com/go-chi/cache"
+)
+
+// Result is the state of a bucket after taking a request from it
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is the time when the bucket is full again
+	Reset time.Time
+	// RetryAfter is the time until the next request is allowed, zero if the request was allowed
+	RetryAfter time.Duration
+}
+
+// Limiter implements token buckets which are refilled with limit requests per period.
+// The buckets are kept in a cache to share them across processes, taking a request
+// is not atomic across processes so concurrent requests may exceed a limit slightly.
+type Limiter struct {
+	cache  mc.Cache
+	prefix string
+	mu     sync.Mutex
+	now    func() time.Time
+}
+
+// NewLimiter returns a limiter keeping its buckets in the cache with keys starting with prefix
+func NewLimiter(cache mc.Cache, prefix string) *Limiter {
+	return &Limiter{
+		cache:  cache,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// bucket is the state of a bucket as stored in the cache
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b bucket) String() string {
+	return fmt.Sprintf("%s|%d", strconv.FormatFloat(b.tokens, 'f', -1, 64), b.last.UnixNano())
+}
+
+func parseBucket(v interface{}) (bucket, bool) {
+	var s string
+	switch v := v.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return bucket{}, false
+	}
+	fields := strings.SplitN(s, "|", 2)
+	if len(fields) != 2 {
+		return bucket{}, false
+	}
+	tokens, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return bucket{}, false
+	}
+	last, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil {
+		return bucket{}, false
+	}
+	return bucket{tokens: tokens, last: time.Unix(0, last)}, true
+}
+
+// Take takes a request from the bucket with the given key which holds up to limit requests
+// and is refilled completely in period
+func (l *Limiter) Take(key string, limit int, period time.Duration) (*Result, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	key = l.prefix + key
+	rate := float64(limit) / float64(period) // requests per nanosecond
+
+	b, ok := parseBucket(l.cache.Get(key))
+	if !ok || b.last.After(now) {
+		b = bucket{tokens: float64(limit), last: now}
+	}
+	b.tokens = math.Min(float64(limit), b.tokens+float64(now.Sub(b.last))*rate)
+	b.last = now
+
+	res := &Result{Limit: limit}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+	res.Remaining = int(b.tokens)
+	res.Reset = now.Add(time.Duration((float64(limit) - b.tokens) / rate))
+
+	if err := l.cache.Put(key, b.String(), int64(math.Ceil(period.Seconds()))); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
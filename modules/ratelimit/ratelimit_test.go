@@ -0,0 +1,56 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ratelimit
+
+import (
+	"testing"
+	"time"
+
+	mc "gitea.com/go-chi/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter_Take(t *testing.T) {
+	cache, err := mc.NewCacher(mc.Options{Adapter: "memory", Interval: 60})
+	assert.NoError(t, err)
+
+	now := time.Unix(1600000000, 0)
+	l := NewLimiter(cache, "test:")
+	l.now = func() time.Time { return now }
+
+	for i := 2; i >= 0; i-- {
+		res, err := l.Take("a", 3, time.Minute)
+		assert.NoError(t, err)
+		assert.True(t, res.Allowed)
+		assert.Equal(t, 3, res.Limit)
+		assert.Equal(t, i, res.Remaining)
+	}
+	res, err := l.Take("a", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+	assert.Equal(t, 20*time.Second, res.RetryAfter)
+	assert.Equal(t, now.Add(time.Minute), res.Reset)
+
+	// the buckets are independent
+	res, err = l.Take("b", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+
+	// one request is refilled every 20 seconds
+	now = now.Add(20 * time.Second)
+	res, err = l.Take("a", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 0, res.Remaining)
+
+	// the bucket is not filled beyond its limit
+	now = now.Add(time.Hour)
+	res, err = l.Take("a", 3, time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, res.Allowed)
+	assert.Equal(t, 2, res.Remaining)
+	assert.Equal(t, now.Add(20*time.Second), res.Reset)
+}
@@ -46,6 +46,9 @@ var (
 	issueCloseKeywordsPat, issueReopenKeywordsPat *regexp.Regexp
 	issueKeywordsOnce                             sync.Once
 
+	// repoKeywordsPats caches the keywords patterns of repositories overriding the additional keywords
+	repoKeywordsPats sync.Map
+
 	giteaHostInit         sync.Once
 	giteaHost             string
 	giteaIssuePullPattern *regexp.Regexp
@@ -147,10 +150,51 @@ func parseKeywords(words []string) []string {
 func newKeywords() {
 	issueKeywordsOnce.Do(func() {
 		// Delay initialization until after the settings module is initialized
-		doNewKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords)
+		doNewKeywords(
+			combineKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.AdditionalCloseKeywords),
+			combineKeywords(setting.Repository.PullRequest.ReopenKeywords, setting.Repository.PullRequest.AdditionalReopenKeywords),
+		)
 	})
 }
 
+func combineKeywords(keywords, additional []string) []string {
+	combined := make([]string, 0, len(keywords)+len(additional))
+	combined = append(combined, keywords...)
+	return append(combined, additional...)
+}
+
+// keywordsPatterns holds the patterns matching the keywords that close or reopen a referenced issue
+type keywordsPatterns struct {
+	close, reopen *regexp.Regexp
+}
+
+// getKeywordsPatterns returns the keywords patterns for the given additional keywords,
+// which replace the additional keywords of the instance if not empty.
+func getKeywordsPatterns(closeKeywords, reopenKeywords []string) *keywordsPatterns {
+	newKeywords()
+	if len(closeKeywords) == 0 && len(reopenKeywords) == 0 {
+		return &keywordsPatterns{close: issueCloseKeywordsPat, reopen: issueReopenKeywordsPat}
+	}
+
+	key := strings.Join(closeKeywords, ",") + "|" + strings.Join(reopenKeywords, ",")
+	if pats, ok := repoKeywordsPats.Load(key); ok {
+		return pats.(*keywordsPatterns)
+	}
+
+	if len(closeKeywords) == 0 {
+		closeKeywords = setting.Repository.PullRequest.AdditionalCloseKeywords
+	}
+	if len(reopenKeywords) == 0 {
+		reopenKeywords = setting.Repository.PullRequest.AdditionalReopenKeywords
+	}
+	pats := &keywordsPatterns{
+		close:  makeKeywordsPat(combineKeywords(setting.Repository.PullRequest.CloseKeywords, closeKeywords)),
+		reopen: makeKeywordsPat(combineKeywords(setting.Repository.PullRequest.ReopenKeywords, reopenKeywords)),
+	}
+	repoKeywordsPats.Store(key, pats)
+	return pats
+}
+
 func doNewKeywords(close []string, reopen []string) {
 	issueCloseKeywordsPat = makeKeywordsPat(close)
 	issueReopenKeywordsPat = makeKeywordsPat(reopen)
@@ -230,12 +274,18 @@ func FindFirstMentionBytes(content []byte) (bool, RefSpan) {
 // FindAllIssueReferencesMarkdown strips content from markdown markup
 // and returns a list of unvalidated references found in it.
 func FindAllIssueReferencesMarkdown(content string) []IssueReference {
-	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content))
+	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content, nil))
+}
+
+// FindAllIssueReferencesMarkdownWithKeywords is like FindAllIssueReferencesMarkdown, but with the
+// additional close and reopen keywords of the instance replaced by the given ones if they are not empty.
+func FindAllIssueReferencesMarkdownWithKeywords(content string, closeKeywords, reopenKeywords []string) []IssueReference {
+	return rawToIssueReferenceList(findAllIssueReferencesMarkdown(content, getKeywordsPatterns(closeKeywords, reopenKeywords)))
 }
 
-func findAllIssueReferencesMarkdown(content string) []*rawReference {
+func findAllIssueReferencesMarkdown(content string, pats *keywordsPatterns) []*rawReference {
 	bcontent, links := mdstripper.StripMarkdownBytes([]byte(content))
-	return findAllIssueReferencesBytes(bcontent, links)
+	return findAllIssueReferencesBytes(bcontent, links, pats)
 }
 
 func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byte) {
@@ -306,6 +356,16 @@ func convertFullHTMLReferencesToShortRefs(re *regexp.Regexp, contentBytes *[]byt
 
 // FindAllIssueReferences returns a list of unvalidated references found in a string.
 func FindAllIssueReferences(content string) []IssueReference {
+	return findAllIssueReferences(content, nil)
+}
+
+// FindAllIssueReferencesWithKeywords is like FindAllIssueReferences, but with the additional
+// close and reopen keywords of the instance replaced by the given ones if they are not empty.
+func FindAllIssueReferencesWithKeywords(content string, closeKeywords, reopenKeywords []string) []IssueReference {
+	return findAllIssueReferences(content, getKeywordsPatterns(closeKeywords, reopenKeywords))
+}
+
+func findAllIssueReferences(content string, pats *keywordsPatterns) []IssueReference {
 	// Need to convert fully qualified html references to local system to #/! short codes
 	contentBytes := []byte(content)
 	if re := getGiteaIssuePullPattern(); re != nil {
@@ -313,7 +373,7 @@ func FindAllIssueReferences(content string) []IssueReference {
 	} else {
 		log.Debug("No GiteaIssuePullPattern pattern")
 	}
-	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}))
+	return rawToIssueReferenceList(findAllIssueReferencesBytes(contentBytes, []string{}, pats))
 }
 
 // FindRenderizableReferenceNumeric returns the first unvalidated reference found in a string.
@@ -324,7 +384,7 @@ func FindRenderizableReferenceNumeric(content string, prOnly bool) (bool, *Rende
 			return false, nil
 		}
 	}
-	r := getCrossReference(util.StringToReadOnlyBytes(content), match[2], match[3], false, prOnly)
+	r := getCrossReference(util.StringToReadOnlyBytes(content), match[2], match[3], false, prOnly, nil)
 	if r == nil {
 		return false, nil
 	}
@@ -347,7 +407,7 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 		return false, nil
 	}
 
-	action, location := findActionKeywords([]byte(content), match[2])
+	action, location := findActionKeywords([]byte(content), match[2], nil)
 
 	return true, &RenderizableReference{
 		Issue:          string(content[match[2]:match[3]]),
@@ -358,8 +418,31 @@ func FindRenderizableReferenceAlphanumeric(content string) (bool, *RenderizableR
 	}
 }
 
+// FindRenderizableReferenceRegexp returns the first reference matched by the custom pattern of an external
+// issue tracker. The first capture group is the issue, or the whole match if the pattern has no groups.
+func FindRenderizableReferenceRegexp(content string, pattern *regexp.Regexp) (bool, *RenderizableReference) {
+	match := pattern.FindStringSubmatchIndex(content)
+	if match == nil || match[0] == match[1] {
+		return false, nil
+	}
+
+	issue := content[match[0]:match[1]]
+	if len(match) >= 4 && match[2] >= 0 && match[2] < match[3] {
+		issue = content[match[2]:match[3]]
+	}
+	action, location := findActionKeywords([]byte(content), match[0], nil)
+
+	return true, &RenderizableReference{
+		Issue:          issue,
+		RefLocation:    &RefSpan{Start: match[0], End: match[1]},
+		Action:         action,
+		ActionLocation: location,
+		IsPull:         false,
+	}
+}
+
 // FindAllIssueReferencesBytes returns a list of unvalidated references found in a byte slice.
-func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference {
+func findAllIssueReferencesBytes(content []byte, links []string, pats *keywordsPatterns) []*rawReference {
 
 	ret := make([]*rawReference, 0, 10)
 	pos := 0
@@ -373,7 +456,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, pats); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -391,7 +474,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 		if match == nil {
 			break
 		}
-		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false); ref != nil {
+		if ref := getCrossReference(content, match[2]+pos, match[3]+pos, false, false, pats); ref != nil {
 			ret = append(ret, ref)
 		}
 		notrail := spaceTrimmedPattern.FindSubmatchIndex(content[match[2]+pos : match[3]+pos])
@@ -425,7 +508,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 			}
 			// Note: closing/reopening keywords not supported with URLs
 			bytes := []byte(parts[1] + "/" + parts[2] + sep + parts[4])
-			if ref := getCrossReference(bytes, 0, len(bytes), true, false); ref != nil {
+			if ref := getCrossReference(bytes, 0, len(bytes), true, false, pats); ref != nil {
 				ref.refLocation = nil
 				ret = append(ret, ref)
 			}
@@ -467,7 +550,7 @@ func findAllIssueReferencesBytes(content []byte, links []string) []*rawReference
 	return ret
 }
 
-func getCrossReference(content []byte, start, end int, fromLink bool, prOnly bool) *rawReference {
+func getCrossReference(content []byte, start, end int, fromLink bool, prOnly bool, pats *keywordsPatterns) *rawReference {
 	sep := bytes.IndexAny(content[start:end], "#!")
 	if sep < 0 {
 		return nil
@@ -487,7 +570,7 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 			// Markdown links must specify owner/repo
 			return nil
 		}
-		action, location := findActionKeywords(content, start)
+		action, location := findActionKeywords(content, start, pats)
 		return &rawReference{
 			index:          index,
 			action:         action,
@@ -505,7 +588,7 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 	if !validNamePattern.MatchString(owner) || !validNamePattern.MatchString(name) {
 		return nil
 	}
-	action, location := findActionKeywords(content, start)
+	action, location := findActionKeywords(content, start, pats)
 	return &rawReference{
 		index:          index,
 		owner:          owner,
@@ -518,17 +601,19 @@ func getCrossReference(content []byte, start, end int, fromLink bool, prOnly boo
 	}
 }
 
-func findActionKeywords(content []byte, start int) (XRefAction, *RefSpan) {
-	newKeywords()
+func findActionKeywords(content []byte, start int, pats *keywordsPatterns) (XRefAction, *RefSpan) {
+	if pats == nil {
+		pats = getKeywordsPatterns(nil, nil)
+	}
 	var m []int
-	if issueCloseKeywordsPat != nil {
-		m = issueCloseKeywordsPat.FindSubmatchIndex(content[:start])
+	if pats.close != nil {
+		m = pats.close.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionCloses, &RefSpan{Start: m[2], End: m[3]}
 		}
 	}
-	if issueReopenKeywordsPat != nil {
-		m = issueReopenKeywordsPat.FindSubmatchIndex(content[:start])
+	if pats.reopen != nil {
+		m = pats.reopen.FindSubmatchIndex(content[:start])
 		if m != nil {
 			return XRefActionReopens, &RefSpan{Start: m[2], End: m[3]}
 		}
@@ -287,7 +287,7 @@ func testFixtures(t *testing.T, fixtures []testFixture, context string) {
 		expref := rawToIssueReferenceList(expraw)
 		refs := FindAllIssueReferencesMarkdown(fixture.input)
 		assert.EqualValues(t, expref, refs, "[%s] Failed to parse: {%s}", context, fixture.input)
-		rawrefs := findAllIssueReferencesMarkdown(fixture.input)
+		rawrefs := findAllIssueReferencesMarkdown(fixture.input, nil)
 		assert.EqualValues(t, expraw, rawrefs, "[%s] Failed to parse: {%s}", context, fixture.input)
 	}
 
@@ -461,6 +461,57 @@ func TestCustomizeCloseKeywords(t *testing.T) {
 	doNewKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords)
 }
 
+func TestAdditionalCloseKeywords(t *testing.T) {
+	prevURL := setting.AppURL
+	setting.AppURL = "https://gitea.com:3000/"
+	prevClose, prevReopen := setting.Repository.PullRequest.AdditionalCloseKeywords, setting.Repository.PullRequest.AdditionalReopenKeywords
+	setting.Repository.PullRequest.AdditionalCloseKeywords = []string{"fecha", "corrige"}
+	setting.Repository.PullRequest.AdditionalReopenKeywords = []string{"reabre"}
+
+	issueKeywordsOnce.Do(func() {})
+	doNewKeywords(
+		combineKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.AdditionalCloseKeywords),
+		combineKeywords(setting.Repository.PullRequest.ReopenKeywords, setting.Repository.PullRequest.AdditionalReopenKeywords),
+	)
+
+	for _, test := range []struct {
+		input          string
+		closeKeywords  []string
+		reopenKeywords []string
+		expected       IssueReference
+	}{
+		// additional keywords of the instance
+		{"Fecha #2", nil, nil, IssueReference{Index: 2, Action: XRefActionCloses}},
+		{"corrige: user6/repo6#300", nil, nil, IssueReference{Index: 300, Owner: "user6", Name: "repo6", Action: XRefActionCloses}},
+		{"Reabre #2", nil, nil, IssueReference{Index: 2, Action: XRefActionReopens}},
+		{"schließt: #2", nil, nil, IssueReference{Index: 2, Action: XRefActionNone}},
+		// keywords of the repository replace the additional keywords of the instance
+		{"schließt: #2", []string{"schließt", "behebt"}, nil, IssueReference{Index: 2, Action: XRefActionCloses}},
+		{"Schließt #2", []string{"schließt", "behebt"}, nil, IssueReference{Index: 2, Action: XRefActionCloses}},
+		{"BEHEBT user6/repo6#300", []string{"schließt", "behebt"}, nil, IssueReference{Index: 300, Owner: "user6", Name: "repo6", Action: XRefActionCloses}},
+		{"abschließt #2", []string{"schließt", "behebt"}, nil, IssueReference{Index: 2, Action: XRefActionNone}},
+		{"fecha #2", []string{"schließt", "behebt"}, nil, IssueReference{Index: 2, Action: XRefActionNone}},
+		{"Closes: #2", []string{"schließt", "behebt"}, nil, IssueReference{Index: 2, Action: XRefActionCloses}},
+		{"reabre #2", []string{"schließt", "behebt"}, nil, IssueReference{Index: 2, Action: XRefActionReopens}},
+		{"Öffnet: #2", []string{"schließt"}, []string{"öffnet"}, IssueReference{Index: 2, Action: XRefActionReopens}},
+		{"reabre #2", []string{"schließt"}, []string{"öffnet"}, IssueReference{Index: 2, Action: XRefActionNone}},
+	} {
+		refs := FindAllIssueReferencesWithKeywords(test.input, test.closeKeywords, test.reopenKeywords)
+		if assert.Len(t, refs, 1, "Failed to parse: {%s}", test.input) {
+			assert.Equal(t, test.expected, refs[0], "Failed to parse: {%s}", test.input)
+		}
+		refs = FindAllIssueReferencesMarkdownWithKeywords(test.input, test.closeKeywords, test.reopenKeywords)
+		if assert.Len(t, refs, 1, "Failed to parse: {%s}", test.input) {
+			assert.Equal(t, test.expected, refs[0], "Failed to parse: {%s}", test.input)
+		}
+	}
+
+	// Restore default settings
+	setting.Repository.PullRequest.AdditionalCloseKeywords, setting.Repository.PullRequest.AdditionalReopenKeywords = prevClose, prevReopen
+	doNewKeywords(setting.Repository.PullRequest.CloseKeywords, setting.Repository.PullRequest.ReopenKeywords)
+	setting.AppURL = prevURL
+}
+
 func TestParseCloseKeywords(t *testing.T) {
 	// Test parsing of CloseKeywords and ReopenKeywords
 	assert.Len(t, parseKeywords([]string{""}), 0)
@@ -124,6 +124,8 @@ func changeIssueStatus(repo *models.Repository, issue *models.Issue, doer *model
 
 // UpdateIssuesCommit checks if issues are manipulated by commit message.
 func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*repository.PushCommit, branchName string) error {
+	closeKeywords, reopenKeywords := repo.IssueKeywords()
+
 	// Commits are appended in the reverse order.
 	for i := len(commits) - 1; i >= 0; i-- {
 		c := commits[i]
@@ -137,7 +139,7 @@ func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*r
 		var refRepo *models.Repository
 		var refIssue *models.Issue
 		var err error
-		for _, ref := range references.FindAllIssueReferences(c.Message) {
+		for _, ref := range references.FindAllIssueReferencesWithKeywords(c.Message, closeKeywords, reopenKeywords) {
 
 			// issue is from another repo
 			if len(ref.Owner) > 0 && len(ref.Name) > 0 {
@@ -175,8 +177,14 @@ func UpdateIssuesCommit(doer *models.User, repo *models.Repository, commits []*r
 				continue
 			}
 
+			// Only record the action if it can take effect, like for references from issues
+			refAction := ref.Action
+			if refIssue.IsPull || !canclose {
+				refAction = references.XRefActionNone
+			}
+
 			message := fmt.Sprintf(`<a href="%s/commit/%s">%s</a>`, repo.Link(), c.Sha1, html.EscapeString(strings.SplitN(c.Message, "\n", 2)[0]))
-			if err = models.CreateRefComment(doer, refRepo, refIssue, message, c.Sha1); err != nil {
+			if err = models.CreateRefComment(doer, refRepo, repo, refIssue, message, c.Sha1, refAction); err != nil {
 				return err
 			}
 
@@ -0,0 +1,239 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+)
+
+// ApplyCommitOptions holds the options to cherry-pick or revert a commit onto a branch
+type ApplyCommitOptions struct {
+	CommitID  string
+	Revert    bool
+	OldBranch string
+	NewBranch string
+	Message   string
+	Author    *IdentityOptions
+	Committer *IdentityOptions
+	Dates     *CommitDateOptions
+	Signoff   bool
+}
+
+// applyCommitErrorSuffices are the suffices of the errors of git apply which name a conflicting file
+var applyCommitErrorSuffices = []string{
+	": already exists in index",
+	": does not exist in index",
+	": does not match index",
+	": patch does not apply",
+}
+
+// changedFiles returns the files changed by the commit
+func (t *TemporaryUploadRepository) changedFiles(commitID string) ([]string, error) {
+	stdout, err := git.NewCommand("diff-tree", "--no-commit-id", "--name-only", "-r", "-z", "--root", commitID).RunInDir(t.basePath)
+	if err != nil {
+		return nil, fmt.Errorf("Unable to get the changed files of %s in temporary repo for: %s Error: %v", commitID, t.repo.FullName(), err)
+	}
+	var files []string
+	for _, file := range strings.Split(stdout, "\x00") {
+		if file != "" {
+			files = append(files, file)
+		}
+	}
+	return files, nil
+}
+
+// applyCommitToIndex applies the changes of the commit, or their reverse, to the index.
+// It returns the conflicting files if the changes don't apply cleanly.
+func (t *TemporaryUploadRepository) applyCommitToIndex(commitID string, reverse bool) ([]string, error) {
+	diffArgs := []string{"diff-tree", "-p", "--binary", "--full-index", "--no-commit-id", "--root"}
+	if reverse {
+		diffArgs = append(diffArgs, "-R")
+	}
+	diffArgs = append(diffArgs, commitID)
+
+	patchReader, patchWriter := io.Pipe()
+	defer patchReader.Close()
+	go func() {
+		stderr := new(bytes.Buffer)
+		err := git.NewCommand(diffArgs...).RunInDirPipeline(t.basePath, patchWriter, stderr)
+		if err != nil {
+			err = fmt.Errorf("git diff-tree: %v - %s", err, stderr)
+		}
+		_ = patchWriter.CloseWithError(err)
+	}()
+
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	if err := git.NewCommand("apply", "--cached", "-").RunInDirFullPipeline(t.basePath, stdout, stderr, patchReader); err != nil {
+		const prefix = "error: patch failed:"
+		const errorPrefix = "error: "
+
+		conflictMap := map[string]bool{}
+		conflicts := make([]string, 0, 5)
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			line := scanner.Text()
+			var file string
+			if strings.HasPrefix(line, prefix) {
+				file = strings.TrimSpace(strings.Split(line[len(prefix):], ":")[0])
+			} else if strings.HasPrefix(line, errorPrefix) {
+				for _, suffix := range applyCommitErrorSuffices {
+					if strings.HasSuffix(line, suffix) {
+						file = strings.TrimSpace(strings.TrimSuffix(line[len(errorPrefix):], suffix))
+						break
+					}
+				}
+			}
+			if file != "" && !conflictMap[file] {
+				conflictMap[file] = true
+				conflicts = append(conflicts, file)
+			}
+		}
+		if len(conflicts) > 0 {
+			return conflicts, nil
+		}
+		log.Error("Unable to apply %s to the index of temporary repo: %s (%s) Error: %v\nstdout: %s\nstderr: %s", commitID, t.repo.FullName(), t.basePath, err, stdout, stderr)
+		return nil, fmt.Errorf("Unable to apply %s to the index of temporary repo for: %s Error: %v", commitID, t.repo.FullName(), err)
+	}
+	return nil, nil
+}
+
+// ApplyCommit cherry-picks or reverts a commit of the repository onto a branch and returns the new commit.
+// Merge commits are not supported. LFS pointers are applied like any other file, the objects they point to
+// already belong to the repository.
+func ApplyCommit(repo *models.Repository, doer *models.User, opts *ApplyCommitOptions) (*git.Commit, error) {
+	// If no branch name is set, assume the repo's default branch
+	if opts.OldBranch == "" {
+		opts.OldBranch = repo.DefaultBranch
+	}
+	if opts.NewBranch == "" {
+		opts.NewBranch = opts.OldBranch
+	}
+
+	// oldBranch must exist for this operation
+	if _, err := repo_module.GetBranch(repo, opts.OldBranch); err != nil {
+		return nil, err
+	}
+
+	// A NewBranch can be specified for the commit to be created in a new branch.
+	// Check to make sure the branch does not already exist, otherwise we can't proceed.
+	if opts.NewBranch != opts.OldBranch {
+		newBranch, err := repo_module.GetBranch(repo, opts.NewBranch)
+		if err != nil && !git.IsErrBranchNotExist(err) {
+			return nil, err
+		}
+		if newBranch != nil {
+			return nil, models.ErrBranchAlreadyExists{
+				BranchName: opts.NewBranch,
+			}
+		}
+	}
+
+	t, err := NewTemporaryUploadRepository(repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(opts.OldBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+
+	commit, err := t.GetCommit(opts.CommitID)
+	if err != nil {
+		return nil, err
+	}
+	if commit.ParentCount() > 1 {
+		return nil, models.ErrCommitApplyMergeCommit{CommitID: commit.ID.String()}
+	}
+
+	changedFiles, err := t.changedFiles(commit.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	// If we aren't branching to a new branch, make sure user can commit the changed files to the given branch
+	if opts.NewBranch == opts.OldBranch {
+		if len(changedFiles) == 0 {
+			if err := VerifyBranchProtection(repo, doer, opts.OldBranch, ""); err != nil {
+				return nil, err
+			}
+		}
+		for _, file := range changedFiles {
+			if err := VerifyBranchProtection(repo, doer, opts.OldBranch, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(changedFiles) > 0 {
+		conflicts, err := t.applyCommitToIndex(commit.ID.String(), opts.Revert)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			return nil, models.ErrCommitApplyConflict{
+				CommitID: commit.ID.String(),
+				Branch:   opts.OldBranch,
+				Files:    conflicts,
+			}
+		}
+	}
+
+	message := strings.TrimSpace(opts.Message)
+	if message == "" {
+		if opts.Revert {
+			message = fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", commit.Summary(), commit.ID.String())
+		} else {
+			message = fmt.Sprintf("%s\n\n(cherry picked from commit %s)", strings.TrimSpace(commit.CommitMessage), commit.ID.String())
+		}
+	}
+
+	// A cherry-picked commit keeps its author unless another one is given
+	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+	authorDate, committerDate := time.Now(), time.Now()
+	if !opts.Revert && (opts.Author == nil || opts.Author.Email == "") {
+		author = &models.User{
+			FullName: commit.Author.Name,
+			Email:    commit.Author.Email,
+		}
+		authorDate = commit.Author.When
+	}
+	if opts.Dates != nil {
+		if !opts.Dates.Author.IsZero() {
+			authorDate = opts.Dates.Author
+		}
+		if !opts.Dates.Committer.IsZero() {
+			committerDate = opts.Dates.Committer
+		}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return nil, err
+	}
+	commitHash, err := t.CommitTreeWithDate(author, committer, treeHash, message, opts.Signoff, authorDate, committerDate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Then push this tree to NewBranch
+	if err := t.Push(doer, commitHash, opts.NewBranch); err != nil {
+		return nil, err
+	}
+
+	return t.GetCommit(commitHash)
+}
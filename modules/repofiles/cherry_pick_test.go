@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repofiles
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyCommitToIndex(t *testing.T) {
+	db.PrepareTestEnv(t)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	const commitID = "65f1bf27bc3bf70f64657658635e66094edbcb4d"
+
+	tmpRepo, err := NewTemporaryUploadRepository(repo)
+	assert.NoError(t, err)
+	defer tmpRepo.Close()
+	assert.NoError(t, tmpRepo.Clone(repo.DefaultBranch))
+	assert.NoError(t, tmpRepo.SetDefaultIndex())
+
+	files, err := tmpRepo.changedFiles(commitID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, files)
+
+	// The changes of the commit are already on the branch
+	conflicts, err := tmpRepo.applyCommitToIndex(commitID, false)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"README.md"}, conflicts)
+
+	conflicts, err = tmpRepo.applyCommitToIndex(commitID, true)
+	assert.NoError(t, err)
+	assert.Empty(t, conflicts)
+	treeHash, err := tmpRepo.WriteTree()
+	assert.NoError(t, err)
+	// Reverting the root commit removes all files
+	emptyTree, err := git.NewCommand("hash-object", "-t", "tree", "/dev/null").RunInDir(tmpRepo.basePath)
+	assert.NoError(t, err)
+	assert.Equal(t, emptyTree[:len(emptyTree)-1], treeHash)
+}
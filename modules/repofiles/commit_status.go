@@ -9,6 +9,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
+	pull_service "code.gitea.io/gitea/services/pull"
 )
 
 // CreateCommitStatus creates a new CommitStatus given a bunch of parameters
@@ -37,5 +38,8 @@ func CreateCommitStatus(repo *models.Repository, creator *models.User, sha strin
 		return fmt.Errorf("NewCommitStatus[repo_id: %d, user_id: %d, sha: %s]: %v", repo.ID, creator.ID, sha, err)
 	}
 
+	// the commit might be the test merge of a merge queue waiting for its status checks
+	pull_service.AddMergeQueueStatusCheckTask(repo.ID, sha)
+
 	return nil
 }
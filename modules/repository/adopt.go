@@ -43,9 +43,11 @@ func AdoptRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mode
 		OriginalServiceType:             opts.GitServiceType,
 		IsPrivate:                       opts.IsPrivate,
 		IsFsckEnabled:                   !opts.IsMirror,
+		IsBackupEnabled:                 true,
 		CloseIssuesViaCommitInAnyBranch: setting.Repository.DefaultCloseIssuesViaCommitsInAnyBranch,
 		Status:                          opts.Status,
 		IsEmpty:                         !opts.AutoInit,
+		CreatedFrom:                     models.RepoCreatedFromAdopted,
 	}
 
 	if err := db.WithTx(func(ctx context.Context) error {
@@ -62,6 +64,13 @@ func AdoptRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mode
 			}
 		}
 
+		if repo.ObjectFormat, err = git.GetObjectFormatOfRepo(repoPath); err != nil {
+			return fmt.Errorf("GetObjectFormatOfRepo: %v", err)
+		}
+		if repo.ObjectFormat != git.ObjectFormatSHA1 {
+			return models.ErrObjectFormatNotAllowed{ObjectFormat: string(repo.ObjectFormat)}
+		}
+
 		if err := models.CreateRepository(ctx, doer, u, repo, true); err != nil {
 			return err
 		}
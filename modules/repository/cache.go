@@ -23,6 +23,17 @@ func getRefName(fullRefName string) string {
 	return ""
 }
 
+// UseLastCommitCache returns whether the last commits of the tree entries of a branch are cached
+func UseLastCommitCache(repo *models.Repository, branchName string, commitsCount int64) bool {
+	if !setting.CacheService.LastCommit.Enabled {
+		return false
+	}
+	if setting.CacheService.LastCommit.PrecomputeDefaultBranch && branchName == repo.DefaultBranch {
+		return true
+	}
+	return commitsCount >= setting.CacheService.LastCommit.CommitsCount
+}
+
 // CacheRef cachhe last commit information of the branch or the tag
 func CacheRef(ctx context.Context, repo *models.Repository, gitRepo *git.Repository, fullRefName string) error {
 	if !setting.CacheService.LastCommit.Enabled {
@@ -34,15 +45,17 @@ func CacheRef(ctx context.Context, repo *models.Repository, gitRepo *git.Reposit
 		return err
 	}
 
-	commitsCount, err := cache.GetInt64(repo.GetCommitsCountCacheKey(getRefName(fullRefName), true), commit.CommitsCount)
-	if err != nil {
-		return err
-	}
-	if commitsCount < setting.CacheService.LastCommit.CommitsCount {
-		return nil
+	if !setting.CacheService.LastCommit.PrecomputeDefaultBranch || fullRefName != git.BranchPrefix+repo.DefaultBranch {
+		commitsCount, err := cache.GetInt64(repo.GetCommitsCountCacheKey(getRefName(fullRefName), true), commit.CommitsCount)
+		if err != nil {
+			return err
+		}
+		if commitsCount < setting.CacheService.LastCommit.CommitsCount {
+			return nil
+		}
 	}
 
-	commitCache := git.NewLastCommitCache(repo.FullName(), gitRepo, setting.LastCommitCacheTTLSeconds, cache.GetCache())
+	commitCache := git.NewLastCommitCache(repo.FullName(), gitRepo, setting.LastCommitCacheTTLSeconds, cache.GetLastCommitCache())
 
 	return commitCache.CacheCommit(ctx, commit)
 }
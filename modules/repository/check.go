@@ -53,6 +53,88 @@ func GitFsck(ctx context.Context, timeout time.Duration, args []string) error {
 	return nil
 }
 
+// The names of the checks of a repository health check
+const (
+	RepoHealthCheckFsck         = "fsck"
+	RepoHealthCheckHooks        = "hooks"
+	RepoHealthCheckDaemonExport = "daemon_export"
+	RepoHealthCheckSize         = "size"
+)
+
+// repoSizeTolerance is the percentage the recorded size of a repository may differ from its actual size,
+// repoSizeMinTolerance the difference in bytes which is always tolerated
+const (
+	repoSizeTolerance    = 10
+	repoSizeMinTolerance = 1024 * 1024
+)
+
+// CheckRepositoryHealth runs git fsck bounded by the timeout, checks the hooks, the git-daemon-export-ok file
+// and the recorded size of a repository without changing anything. Every failed check is recorded as a notice.
+func CheckRepositoryHealth(ctx context.Context, repo *models.Repository, timeout time.Duration) ([]*models.RepoHealthCheckResult, error) {
+	if err := repo.GetOwner(); err != nil {
+		return nil, fmt.Errorf("GetOwner: %v", err)
+	}
+	repoPath := repo.RepoPath()
+	results := make([]*models.RepoHealthCheckResult, 0, 4)
+
+	fsck := &models.RepoHealthCheckResult{Name: RepoHealthCheckFsck, OK: true}
+	if err := git.Fsck(ctx, repoPath, timeout); err != nil {
+		fsck.OK = false
+		fsck.Message = err.Error()
+	}
+	results = append(results, fsck)
+
+	hooks := &models.RepoHealthCheckResult{Name: RepoHealthCheckHooks, OK: true}
+	if problems, err := CheckDelegateHooks(repoPath); err != nil {
+		hooks.OK = false
+		hooks.Message = err.Error()
+	} else if len(problems) > 0 {
+		hooks.OK = false
+		hooks.Message = strings.Join(problems, "\n")
+	}
+	results = append(results, hooks)
+
+	daemonExport := &models.RepoHealthCheckResult{Name: RepoHealthCheckDaemonExport, OK: true}
+	isExist, err := util.IsExist(repo.DaemonExportFile())
+	if err != nil {
+		daemonExport.OK = false
+		daemonExport.Message = err.Error()
+	} else if isExported := repo.IsDaemonExported(); isExist != isExported {
+		daemonExport.OK = false
+		if isExported {
+			daemonExport.Message = "git-daemon-export-ok is missing although the repository is public"
+		} else {
+			daemonExport.Message = "git-daemon-export-ok exists although the repository is not public"
+		}
+	}
+	results = append(results, daemonExport)
+
+	size := &models.RepoHealthCheckResult{Name: RepoHealthCheckSize, OK: true}
+	actualSize, err := repo.ComputeSize()
+	if err != nil {
+		return nil, err
+	}
+	tolerance := actualSize * repoSizeTolerance / 100
+	if tolerance < repoSizeMinTolerance {
+		tolerance = repoSizeMinTolerance
+	}
+	if diff := actualSize - repo.Size; diff > tolerance || -diff > tolerance {
+		size.OK = false
+		size.Message = fmt.Sprintf("the recorded size %d differs from the actual size %d", repo.Size, actualSize)
+	}
+	results = append(results, size)
+
+	for _, result := range results {
+		if result.OK {
+			continue
+		}
+		if err := models.CreateRepositoryNotice("Health check %s of repository %s failed: %s", result.Name, repo.FullName(), result.Message); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+	}
+	return results, nil
+}
+
 // GitGcRepos calls 'git gc' to remove unnecessary files and optimize the local repository
 func GitGcRepos(ctx context.Context, timeout time.Duration, args ...string) error {
 	log.Trace("Doing: GitGcRepos")
@@ -94,13 +176,9 @@ func GitGcRepos(ctx context.Context, timeout time.Duration, args ...string) erro
 			}
 
 			// Now update the size of the repository
-			if err := repo.UpdateSize(db.DefaultContext); err != nil {
-				log.Error("Updating size as part of garbage collection failed for %v. Stdout: %s\nError: %v", repo, stdout, err)
-				desc := fmt.Sprintf("Updating size as part of garbage collection failed for %s. Stdout: %s\nError: %v", repo.RepoPath(), stdout, err)
-				if err = models.CreateRepositoryNotice(desc); err != nil {
-					log.Error("CreateRepositoryNotice: %v", err)
-				}
-				return fmt.Errorf("Updating size as part of garbage collection failed in repo: %s: Error: %v", repo.FullName(), err)
+			if err := QueueUpdateSize(repo); err != nil {
+				log.Error("Queueing the size update as part of garbage collection failed for %v: %v", repo, err)
+				return fmt.Errorf("Queueing the size update as part of garbage collection failed in repo: %s: Error: %v", repo.FullName(), err)
 			}
 
 			return nil
@@ -175,6 +253,27 @@ func DeleteMissingRepositories(ctx context.Context, doer *models.User) error {
 	return nil
 }
 
+// FindRepositoriesWithMissingGitDir returns all repository records that lost Git files.
+func FindRepositoriesWithMissingGitDir(ctx context.Context) ([]*models.Repository, error) {
+	return gatherMissingRepoRecords(ctx)
+}
+
+// CountRepositoriesWithMissingGitDir returns the number of repository records that lost Git files.
+func CountRepositoriesWithMissingGitDir(ctx context.Context) (int, error) {
+	repos, err := gatherMissingRepoRecords(ctx)
+	return len(repos), err
+}
+
+// ReinitMissingRepository recreates the lost Git files of a repository as an empty bare repository
+// with the hooks, so its owner can push the content again.
+func ReinitMissingRepository(repo *models.Repository) error {
+	if err := checkInitRepository(repo.OwnerName, repo.Name, repo.ObjectFormat); err != nil {
+		return err
+	}
+	repo.IsEmpty = true
+	return models.UpdateRepositoryCols(repo, "is_empty")
+}
+
 // ReinitMissingRepositories reinitializes all repository records that lost Git files.
 func ReinitMissingRepositories(ctx context.Context) error {
 	repos, err := gatherMissingRepoRecords(ctx)
@@ -193,7 +292,7 @@ func ReinitMissingRepositories(ctx context.Context) error {
 		default:
 		}
 		log.Trace("Initializing %d/%d...", repo.OwnerID, repo.ID)
-		if err := git.InitRepository(repo.RepoPath(), true); err != nil {
+		if err := ReinitMissingRepository(repo); err != nil {
 			log.Error("Unable (re)initialize repository %d at %s. Error: %v", repo.ID, repo.RepoPath(), err)
 			if err2 := models.CreateRepositoryNotice("InitRepository [%d]: %v", repo.ID, err); err2 != nil {
 				log.Error("CreateRepositoryNotice: %v", err2)
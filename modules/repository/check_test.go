@@ -0,0 +1,82 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReinitMissingRepository(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repos, err := FindRepositoriesWithMissingGitDir(context.Background())
+	assert.NoError(t, err)
+	if !assert.NotEmpty(t, repos) {
+		return
+	}
+	count, err := CountRepositoriesWithMissingGitDir(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, len(repos), count)
+
+	repo := repos[0]
+	assert.NoError(t, ReinitMissingRepository(repo))
+
+	isDir, err := util.IsDir(repo.RepoPath())
+	assert.NoError(t, err)
+	assert.True(t, isDir)
+	results, err := CheckDelegateHooks(repo.RepoPath())
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	assert.True(t, db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository).IsEmpty)
+
+	count, err = CountRepositoriesWithMissingGitDir(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, len(repos)-1, count)
+
+	// The git directory exists now
+	assert.True(t, models.IsErrRepoFilesAlreadyExist(ReinitMissingRepository(repo)))
+}
+
+func TestCheckRepositoryHealth(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	// the recorded size is way off
+	repo.Size = 1 << 40
+	notices := models.CountNotices()
+
+	results, err := CheckRepositoryHealth(context.Background(), repo, time.Minute)
+	assert.NoError(t, err)
+	if !assert.Len(t, results, 4) {
+		return
+	}
+	assert.Equal(t, RepoHealthCheckFsck, results[0].Name)
+	assert.True(t, results[0].OK, results[0].Message)
+	assert.Equal(t, RepoHealthCheckHooks, results[1].Name)
+	assert.Equal(t, RepoHealthCheckDaemonExport, results[2].Name)
+	assert.Equal(t, RepoHealthCheckSize, results[3].Name)
+	assert.False(t, results[3].OK)
+	assert.Contains(t, results[3].Message, "1099511627776")
+
+	var failed int64
+	for _, result := range results {
+		if !result.OK {
+			failed++
+		}
+	}
+	assert.Equal(t, notices+failed, models.CountNotices())
+
+	// nothing was changed
+	assert.EqualValues(t, 1<<40, repo.Size)
+	db.AssertNotExistsBean(t, &models.Repository{ID: 1, Size: 1 << 40})
+}
@@ -129,6 +129,52 @@ func (pc *PushCommits) ToAPIPayloadCommits(repoPath, repoLink string) ([]*api.Pa
 	return commits, headCommit, nil
 }
 
+// AuthorCommits are the commits of a push authored by the same user
+type AuthorCommits struct {
+	Author  *models.User
+	Commits *PushCommits
+}
+
+// GroupByAuthor groups the commits by the users their author e-mails belong to, in the order of the
+// first commit of each author. The commits whose author has no account are left out.
+func (pc *PushCommits) GroupByAuthor() ([]*AuthorCommits, error) {
+	if pc.emailUsers == nil {
+		pc.emailUsers = make(map[string]*models.User)
+	}
+	groups := make([]*AuthorCommits, 0, 2)
+	byAuthor := make(map[int64]*AuthorCommits)
+	unknownEmails := make(map[string]bool)
+	for _, commit := range pc.Commits {
+		if unknownEmails[commit.AuthorEmail] {
+			continue
+		}
+		author, ok := pc.emailUsers[commit.AuthorEmail]
+		if !ok {
+			var err error
+			author, err = models.GetUserByEmail(commit.AuthorEmail)
+			if err != nil {
+				if !models.IsErrUserNotExist(err) {
+					return nil, err
+				}
+				unknownEmails[commit.AuthorEmail] = true
+				continue
+			}
+			pc.emailUsers[commit.AuthorEmail] = author
+		}
+
+		group, ok := byAuthor[author.ID]
+		if !ok {
+			group = &AuthorCommits{Author: author, Commits: NewPushCommits()}
+			group.Commits.HeadCommit = commit
+			byAuthor[author.ID] = group
+			groups = append(groups, group)
+		}
+		group.Commits.Commits = append(group.Commits.Commits, commit)
+		group.Commits.Len++
+	}
+	return groups, nil
+}
+
 // AvatarLink tries to match user in database with e-mail
 // in order to show custom avatar, and falls back to general avatar link.
 func (pc *PushCommits) AvatarLink(email string) string {
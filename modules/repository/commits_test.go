@@ -203,3 +203,27 @@ func TestListToPushCommits(t *testing.T) {
 }
 
 // TODO TestPushUpdate
+
+func TestPushCommits_GroupByAuthor(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	pushCommits := NewPushCommits()
+	pushCommits.Commits = []*PushCommit{
+		{Sha1: "abcdef1", AuthorEmail: "user4@example.com"},
+		{Sha1: "abcdef2", AuthorEmail: "user2@example.com"},
+		{Sha1: "abcdef3", AuthorEmail: "nobody@example.com"},
+		{Sha1: "abcdef4", AuthorEmail: "User4@example.com"},
+	}
+	pushCommits.Len = 4
+
+	groups, err := pushCommits.GroupByAuthor()
+	assert.NoError(t, err)
+	if assert.Len(t, groups, 2) {
+		assert.EqualValues(t, 4, groups[0].Author.ID)
+		assert.Equal(t, []*PushCommit{pushCommits.Commits[0], pushCommits.Commits[3]}, groups[0].Commits.Commits)
+		assert.Equal(t, 2, groups[0].Commits.Len)
+		assert.Equal(t, pushCommits.Commits[0], groups[0].Commits.HeadCommit)
+		assert.EqualValues(t, 2, groups[1].Author.ID)
+		assert.Equal(t, 1, groups[1].Commits.Len)
+	}
+}
@@ -29,6 +29,25 @@ func CreateRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mod
 		opts.DefaultBranch = setting.Repository.DefaultBranch
 	}
 
+	if opts.ObjectFormat == "" {
+		opts.ObjectFormat = git.ObjectFormatSHA1
+	}
+	// Object IDs are parsed as sha1 throughout the git module, so no other format can be offered yet
+	if opts.ObjectFormat != git.ObjectFormatSHA1 {
+		return nil, models.ErrObjectFormatNotAllowed{ObjectFormat: string(opts.ObjectFormat)}
+	}
+
+	if !opts.IsPrivate {
+		if err := models.CheckPublicRepoAllowed(doer, u, opts.OverrideVisibilityPolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	// Check if the init files exist before anything is created, and use their canonical names
+	if err := resolveInitFileNames(&opts); err != nil {
+		return nil, err
+	}
+
 	// Check if label template exist
 	if len(opts.IssueLabels) > 0 {
 		if _, err := models.GetLabelTemplateFile(opts.IssueLabels); err != nil {
@@ -47,11 +66,14 @@ func CreateRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mod
 		OriginalServiceType:             opts.GitServiceType,
 		IsPrivate:                       opts.IsPrivate,
 		IsFsckEnabled:                   !opts.IsMirror,
+		IsBackupEnabled:                 true,
 		IsTemplate:                      opts.IsTemplate,
 		CloseIssuesViaCommitInAnyBranch: setting.Repository.DefaultCloseIssuesViaCommitsInAnyBranch,
 		Status:                          opts.Status,
 		IsEmpty:                         !opts.AutoInit,
 		TrustModel:                      opts.TrustModel,
+		ObjectFormat:                    opts.ObjectFormat,
+		CreatedFrom:                     opts.CreatedFrom,
 	}
 
 	var rollbackRepo *models.Repository
@@ -130,3 +152,34 @@ func CreateRepository(doer, u *models.User, opts models.CreateRepoOptions) (*mod
 
 	return repo, nil
 }
+
+// resolveInitFileNames replaces the init file names of the options by their canonical names
+func resolveInitFileNames(opts *models.CreateRepoOptions) (err error) {
+	if len(opts.IssueLabels) > 0 {
+		if opts.IssueLabels, err = models.ResolveRepoInitFileName("label", opts.IssueLabels); err != nil {
+			return err
+		}
+	}
+	if !opts.AutoInit {
+		return nil
+	}
+
+	if opts.Readme, err = models.ResolveRepoInitFileName("readme", opts.Readme); err != nil {
+		return err
+	}
+	if len(opts.Gitignores) > 0 {
+		names := strings.Split(opts.Gitignores, ",")
+		for i := range names {
+			if names[i], err = models.ResolveRepoInitFileName("gitignore", names[i]); err != nil {
+				return err
+			}
+		}
+		opts.Gitignores = strings.Join(names, ",")
+	}
+	if len(opts.License) > 0 {
+		if opts.License, err = models.ResolveRepoInitFileName("license", opts.License); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/structs"
 
 	"github.com/stretchr/testify/assert"
@@ -144,3 +145,25 @@ func TestIncludesAllRepositoriesTeams(t *testing.T) {
 	}
 	assert.NoError(t, models.DeleteOrganization(org), "DeleteOrganization")
 }
+
+func TestCreateRepositoryObjectFormat(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	// sha256 object IDs can't be handled yet
+	_, err := CreateRepository(user, user, models.CreateRepoOptions{
+		Name:         "sha256-repo",
+		ObjectFormat: git.ObjectFormatSHA256,
+	})
+	assert.True(t, models.IsErrObjectFormatNotAllowed(err))
+	db.AssertNotExistsBean(t, &models.Repository{OwnerID: user.ID, LowerName: "sha256-repo"})
+
+	repo, err := CreateRepository(user, user, models.CreateRepoOptions{Name: "sha1-repo"})
+	assert.NoError(t, err)
+	assert.Equal(t, git.ObjectFormatSHA1, repo.ObjectFormat)
+
+	sha256Repo := &models.Repository{Name: "sha256-repo", ObjectFormat: git.ObjectFormatSHA256}
+	assert.True(t, models.IsErrObjectFormatMismatch(models.CheckSameObjectFormat(repo, sha256Repo)))
+	assert.NoError(t, models.CheckSameObjectFormat(repo, repo))
+}
@@ -7,6 +7,8 @@ package repository
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -33,17 +35,25 @@ func ForkRepository(doer, owner *models.User, opts models.ForkRepoOptions) (_ *m
 	}
 
 	repo := &models.Repository{
-		OwnerID:       owner.ID,
-		Owner:         owner,
-		OwnerName:     owner.Name,
-		Name:          opts.Name,
-		LowerName:     strings.ToLower(opts.Name),
-		Description:   opts.Description,
-		DefaultBranch: opts.BaseRepo.DefaultBranch,
-		IsPrivate:     opts.BaseRepo.IsPrivate || opts.BaseRepo.Owner.Visibility == structs.VisibleTypePrivate,
-		IsEmpty:       opts.BaseRepo.IsEmpty,
-		IsFork:        true,
-		ForkID:        opts.BaseRepo.ID,
+		OwnerID:         owner.ID,
+		Owner:           owner,
+		OwnerName:       owner.Name,
+		Name:            opts.Name,
+		LowerName:       strings.ToLower(opts.Name),
+		Description:     opts.Description,
+		DefaultBranch:   opts.BaseRepo.DefaultBranch,
+		IsPrivate:       opts.BaseRepo.IsPrivate || opts.BaseRepo.Owner.Visibility == structs.VisibleTypePrivate,
+		IsEmpty:         opts.BaseRepo.IsEmpty,
+		IsFork:          true,
+		ForkID:          opts.BaseRepo.ID,
+		ObjectFormat:    opts.BaseRepo.ObjectFormat,
+		IsBackupEnabled: true,
+		CreatedFrom:     models.RepoCreatedFromForked,
+	}
+	if !repo.IsPrivate {
+		if err := models.CheckPublicRepoAllowed(doer, owner, opts.OverrideVisibilityPolicy); err != nil {
+			return nil, err
+		}
 	}
 
 	oldRepoPath := opts.BaseRepo.RepoPath()
@@ -105,6 +115,20 @@ func ForkRepository(doer, owner *models.User, opts models.ForkRepoOptions) (_ *m
 			return fmt.Errorf("git clone: %v", err)
 		}
 
+		// A fork must use the object format of its base repository, otherwise pull requests between them are impossible
+		objectFormat, err := git.GetObjectFormatOfRepo(repoPath)
+		if err != nil {
+			return fmt.Errorf("GetObjectFormatOfRepo: %v", err)
+		}
+		if objectFormat.Name() != repo.ObjectFormat.Name() {
+			return models.ErrObjectFormatMismatch{
+				BaseRepoName: opts.BaseRepo.FullName(),
+				BaseFormat:   repo.ObjectFormat.Name(),
+				HeadRepoName: repo.FullName(),
+				HeadFormat:   objectFormat.Name(),
+			}
+		}
+
 		if err := repo.CheckDaemonExportOK(ctx); err != nil {
 			return fmt.Errorf("checkDaemonExportOK: %v", err)
 		}
@@ -119,6 +143,9 @@ func ForkRepository(doer, owner *models.User, opts models.ForkRepoOptions) (_ *m
 		if err = createDelegateHooks(repoPath); err != nil {
 			return fmt.Errorf("createDelegateHooks: %v", err)
 		}
+		if err = ApplyGitConfigTemplate(ctx, repoPath); err != nil {
+			return fmt.Errorf("ApplyGitConfigTemplate: %v", err)
+		}
 		return nil
 	})
 	needsRollbackInPanic = false
@@ -169,3 +196,50 @@ func ConvertForkToNormalRepository(repo *models.Repository) error {
 
 	return err
 }
+
+// alternatesPath returns the path of the file listing the object directories a repository borrows objects from
+func alternatesPath(repoPath string) string {
+	return filepath.Join(repoPath, "objects", "info", "alternates")
+}
+
+// HasAlternates returns whether a repository borrows objects from other repositories,
+// like a fork sharing the object storage of its base repository
+func HasAlternates(repo *models.Repository) (bool, error) {
+	return util.IsFile(alternatesPath(repo.RepoPath()))
+}
+
+// MaterializeAlternates copies the objects a repository borrows from other repositories into its own
+// object storage and stops borrowing them, so deleting the other repositories cannot corrupt it
+func MaterializeAlternates(ctx context.Context, repo *models.Repository) error {
+	repoPath := repo.RepoPath()
+	alternates := alternatesPath(repoPath)
+	content, err := os.ReadFile(alternates)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	// without --local the pack contains the reachable objects of the alternates too
+	if stdout, err := git.NewCommandContext(ctx, "repack", "-a", "-d").
+		SetDescription(fmt.Sprintf("MaterializeAlternates(git repack): %s", repo.FullName())).
+		RunInDirTimeout(-1, repoPath); err != nil {
+		log.Error("Unable to repack repository %-v: %v\nStdout: %s", repo, err, stdout)
+		return fmt.Errorf("git repack: %v", err)
+	}
+
+	if err := util.Remove(alternates); err != nil {
+		return err
+	}
+	if stdout, err := git.NewCommandContext(ctx, "fsck", "--connectivity-only", "--no-dangling").
+		SetDescription(fmt.Sprintf("MaterializeAlternates(git fsck): %s", repo.FullName())).
+		RunInDirTimeout(-1, repoPath); err != nil {
+		log.Error("Repository %-v is missing objects without its alternates: %v\nStdout: %s", repo, err, stdout)
+		if err := os.WriteFile(alternates, content, 0644); err != nil {
+			log.Error("Unable to restore the alternates of repository %-v: %v", repo, err)
+		}
+		return fmt.Errorf("git fsck: %v", err)
+	}
+	return nil
+}
@@ -5,10 +5,14 @@
 package repository
 
 import (
+	"context"
 	"testing"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -28,3 +32,30 @@ func TestForkRepository(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, models.IsErrForkAlreadyExist(err))
 }
+
+func TestMaterializeAlternates(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	// repo11 is a fork of repo10, recreate it sharing the objects of repo10
+	base := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 10}).(*models.Repository)
+	fork := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 11}).(*models.Repository)
+	assert.NoError(t, util.RemoveAll(fork.RepoPath()))
+	_, err := git.NewCommand("clone", "--bare", "--shared", base.RepoPath(), fork.RepoPath()).RunInDir("")
+	assert.NoError(t, err)
+	hasAlternates, err := HasAlternates(fork)
+	assert.NoError(t, err)
+	assert.True(t, hasAlternates)
+
+	assert.NoError(t, MaterializeAlternates(context.Background(), fork))
+	hasAlternates, err = HasAlternates(fork)
+	assert.NoError(t, err)
+	assert.False(t, hasAlternates)
+
+	// the fork does not need the base any more
+	assert.NoError(t, util.RemoveAll(base.RepoPath()))
+	_, err = git.NewCommand("fsck", "--no-dangling").RunInDir(fork.RepoPath())
+	assert.NoError(t, err)
+
+	// nothing is done for repositories with their own objects
+	assert.NoError(t, MaterializeAlternates(context.Background(), fork))
+}
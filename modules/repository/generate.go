@@ -170,7 +170,7 @@ func generateRepoCommit(repo, templateRepo, generateRepo *models.Repository, tmp
 		}
 	}
 
-	if err := git.InitRepository(tmpDir, false); err != nil {
+	if err := git.InitRepositoryWithObjectFormat(tmpDir, false, repo.ObjectFormat); err != nil {
 		return err
 	}
 
@@ -241,17 +241,20 @@ func GenerateGitContent(ctx context.Context, templateRepo, generateRepo *models.
 // GenerateRepository generates a repository from a template
 func GenerateRepository(ctx context.Context, doer, owner *models.User, templateRepo *models.Repository, opts models.GenerateRepoOptions) (_ *models.Repository, err error) {
 	generateRepo := &models.Repository{
-		OwnerID:       owner.ID,
-		Owner:         owner,
-		OwnerName:     owner.Name,
-		Name:          opts.Name,
-		LowerName:     strings.ToLower(opts.Name),
-		Description:   opts.Description,
-		IsPrivate:     opts.Private,
-		IsEmpty:       !opts.GitContent || templateRepo.IsEmpty,
-		IsFsckEnabled: templateRepo.IsFsckEnabled,
-		TemplateID:    templateRepo.ID,
-		TrustModel:    templateRepo.TrustModel,
+		OwnerID:         owner.ID,
+		Owner:           owner,
+		OwnerName:       owner.Name,
+		Name:            opts.Name,
+		LowerName:       strings.ToLower(opts.Name),
+		Description:     opts.Description,
+		IsPrivate:       opts.Private,
+		IsEmpty:         !opts.GitContent || templateRepo.IsEmpty,
+		IsFsckEnabled:   templateRepo.IsFsckEnabled,
+		IsBackupEnabled: true,
+		TemplateID:      templateRepo.ID,
+		TrustModel:      templateRepo.TrustModel,
+		ObjectFormat:    templateRepo.ObjectFormat,
+		CreatedFrom:     models.RepoCreatedFromGenerated,
 	}
 
 	if err = models.CreateRepository(ctx, doer, owner, generateRepo, false); err != nil {
@@ -271,7 +274,7 @@ func GenerateRepository(ctx context.Context, doer, owner *models.User, templateR
 		}
 	}
 
-	if err = checkInitRepository(owner.Name, generateRepo.Name); err != nil {
+	if err = checkInitRepository(owner.Name, generateRepo.Name, generateRepo.ObjectFormat); err != nil {
 		return generateRepo, err
 	}
 
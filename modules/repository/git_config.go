@@ -0,0 +1,105 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+// gitConfigTemplateKeys returns the keys set by the git config template in a stable order
+func gitConfigTemplateKeys() []string {
+	keys := make([]string, 0, len(setting.Git.RepoConfig))
+	for key := range setting.Git.RepoConfig {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// ApplyGitConfigTemplate sets the keys of the git config template in the git config of a repository
+// and removes the keys the template unsets
+func ApplyGitConfigTemplate(ctx context.Context, repoPath string) error {
+	configPath := models.GitConfigPath(repoPath)
+	for _, key := range gitConfigTemplateKeys() {
+		if err := git.SetConfigValue(ctx, configPath, key, setting.Git.RepoConfig[key]); err != nil {
+			return fmt.Errorf("unable to set %s: %v", key, err)
+		}
+	}
+	for _, key := range setting.Git.RepoConfigUnset {
+		if err := git.UnsetConfigValues(ctx, configPath, key); err != nil {
+			return fmt.Errorf("unable to unset %s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// CheckGitConfigTemplate returns the differences between the git config of a repository and the git config template
+func CheckGitConfigTemplate(ctx context.Context, repoPath string) ([]string, error) {
+	configPath := models.GitConfigPath(repoPath)
+	results := make([]string, 0, 2)
+	for _, key := range gitConfigTemplateKeys() {
+		values, err := git.GetConfigValues(ctx, configPath, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) != 1 || values[0] != setting.Git.RepoConfig[key] {
+			results = append(results, fmt.Sprintf("%s is %q instead of %q", key, values, setting.Git.RepoConfig[key]))
+		}
+	}
+	for _, key := range setting.Git.RepoConfigUnset {
+		values, err := git.GetConfigValues(ctx, configPath, key)
+		if err != nil {
+			return nil, err
+		}
+		if len(values) > 0 {
+			results = append(results, fmt.Sprintf("%s is %q instead of unset", key, values))
+		}
+	}
+	return results, nil
+}
+
+// SyncRepositoryGitConfig applies the git config template to all repositories
+func SyncRepositoryGitConfig(ctx context.Context) error {
+	log.Trace("Doing: SyncRepositoryGitConfig")
+
+	if len(setting.Git.RepoConfig) == 0 && len(setting.Git.RepoConfigUnset) == 0 {
+		log.Trace("Finished: SyncRepositoryGitConfig, the template is empty")
+		return nil
+	}
+
+	if err := db.Iterate(
+		db.DefaultContext,
+		new(models.Repository),
+		builder.Gt{"id": 0},
+		func(idx int, bean interface{}) error {
+			repo := bean.(*models.Repository)
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before sync git config for %s", repo.FullName())
+			default:
+			}
+
+			if err := ApplyGitConfigTemplate(ctx, repo.RepoPath()); err != nil {
+				return fmt.Errorf("SyncRepositoryGitConfig[%s]: %v", repo.FullName(), err)
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+
+	log.Trace("Finished: SyncRepositoryGitConfig")
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitConfigTemplate(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	defer func(config map[string]string, unset []string) {
+		setting.Git.RepoConfig = config
+		setting.Git.RepoConfigUnset = unset
+	}(setting.Git.RepoConfig, setting.Git.RepoConfigUnset)
+	setting.Git.RepoConfig = map[string]string{"receive.fsckObjects": "true"}
+	setting.Git.RepoConfigUnset = []string{"transfer.hideRefs"}
+
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	repo, err := CreateRepository(user, user, models.CreateRepoOptions{Name: "git-config-repo"})
+	assert.NoError(t, err)
+
+	ctx := git.DefaultContext
+	configPath := repo.GitConfigPath()
+	values, err := git.GetConfigValues(ctx, configPath, "receive.fsckObjects")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"true"}, values)
+	results, err := CheckGitConfigTemplate(ctx, repo.RepoPath())
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+
+	assert.NoError(t, git.SetConfigValue(ctx, configPath, "receive.fsckObjects", "false"))
+	assert.NoError(t, git.SetConfigValue(ctx, configPath, "transfer.hideRefs", "refs/pull"))
+	results, err = CheckGitConfigTemplate(ctx, repo.RepoPath())
+	assert.NoError(t, err)
+	assert.Len(t, results, 2)
+
+	assert.NoError(t, ApplyGitConfigTemplate(ctx, repo.RepoPath()))
+	results, err = CheckGitConfigTemplate(ctx, repo.RepoPath())
+	assert.NoError(t, err)
+	assert.Empty(t, results)
+	values, err = git.GetConfigValues(ctx, configPath, "transfer.hideRefs")
+	assert.NoError(t, err)
+	assert.Empty(t, values)
+}
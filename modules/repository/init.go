@@ -36,12 +36,16 @@ func prepareRepoCommit(ctx context.Context, repo *models.Repository, tmpDir, rep
 		"GIT_COMMITTER_DATE="+commitTimeStr,
 	)
 
-	// Clone to temporary path and do the init commit.
-	if stdout, err := git.NewCommand("clone", repoPath, tmpDir).
-		SetDescription(fmt.Sprintf("prepareRepoCommit (git clone): %s to %s", repoPath, tmpDir)).
-		RunInDirWithEnv("", env); err != nil {
-		log.Error("Failed to clone from %v into %s: stdout: %s\nError: %v", repo, tmpDir, stdout, err)
-		return fmt.Errorf("git clone: %v", err)
+	// Init the temporary path with the object format of the repository and do the init commit,
+	// cloning the still empty repository would always use the default sha1 format.
+	if err := git.InitRepositoryWithObjectFormat(tmpDir, false, repo.ObjectFormat); err != nil {
+		return fmt.Errorf("git init: %v", err)
+	}
+	if stdout, err := git.NewCommand("remote", "add", "origin", repoPath).
+		SetDescription(fmt.Sprintf("prepareRepoCommit (git remote add): %s to %s", repoPath, tmpDir)).
+		RunInDirWithEnv(tmpDir, env); err != nil {
+		log.Error("Failed to add remote %v to %s: stdout: %s\nError: %v", repo, tmpDir, stdout, err)
+		return fmt.Errorf("git remote add: %v", err)
 	}
 
 	// README
@@ -172,7 +176,7 @@ func initRepoCommit(tmpPath string, repo *models.Repository, u *models.User, def
 	return nil
 }
 
-func checkInitRepository(owner, name string) (err error) {
+func checkInitRepository(owner, name string, objectFormat git.ObjectFormat) (err error) {
 	// Somehow the directory could exist.
 	repoPath := models.RepoPath(owner, name)
 	isExist, err := util.IsExist(repoPath)
@@ -188,10 +192,12 @@ func checkInitRepository(owner, name string) (err error) {
 	}
 
 	// Init git bare new repository.
-	if err = git.InitRepository(repoPath, true); err != nil {
+	if err = git.InitRepositoryWithObjectFormat(repoPath, true, objectFormat); err != nil {
 		return fmt.Errorf("git.InitRepository: %v", err)
 	} else if err = createDelegateHooks(repoPath); err != nil {
 		return fmt.Errorf("createDelegateHooks: %v", err)
+	} else if err = ApplyGitConfigTemplate(git.DefaultContext, repoPath); err != nil {
+		return fmt.Errorf("ApplyGitConfigTemplate: %v", err)
 	}
 	return nil
 }
@@ -284,7 +290,7 @@ func adoptRepository(ctx context.Context, repoPath string, u *models.User, repo
 
 // InitRepository initializes README and .gitignore if needed.
 func initRepository(ctx context.Context, repoPath string, u *models.User, repo *models.Repository, opts models.CreateRepoOptions) (err error) {
-	if err = checkInitRepository(repo.OwnerName, repo.Name); err != nil {
+	if err = checkInitRepository(repo.OwnerName, repo.Name, repo.ObjectFormat); err != nil {
 		return err
 	}
 
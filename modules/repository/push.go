@@ -24,12 +24,12 @@ type PushUpdateOptions struct {
 
 // IsNewRef return true if it's a first-time push to a branch, tag or etc.
 func (opts PushUpdateOptions) IsNewRef() bool {
-	return opts.OldCommitID == git.EmptySHA
+	return git.IsEmptyCommitID(opts.OldCommitID)
 }
 
 // IsDelRef return true if it's a deletion to a branch or tag
 func (opts PushUpdateOptions) IsDelRef() bool {
-	return opts.NewCommitID == git.EmptySHA
+	return git.IsEmptyCommitID(opts.NewCommitID)
 }
 
 // IsUpdateRef return true if it's an update operation
@@ -73,6 +73,9 @@ func MigrateRepositoryGitData(ctx context.Context, u *models.User, repo *models.
 	}); err != nil {
 		return repo, fmt.Errorf("Clone: %v", err)
 	}
+	if err = ApplyGitConfigTemplate(ctx, repoPath); err != nil {
+		return repo, fmt.Errorf("ApplyGitConfigTemplate: %v", err)
+	}
 
 	if opts.Wiki {
 		wikiPath := models.WikiPath(u.Name, opts.RepoName)
@@ -212,28 +215,37 @@ func cleanUpMigrateGitConfig(configPath string) error {
 
 // CleanUpMigrateInfo finishes migrating repository and/or wiki with things that don't need to be done for mirrors.
 func CleanUpMigrateInfo(repo *models.Repository) (*models.Repository, error) {
+	if err := CleanUpMigrateGitInfo(repo); err != nil {
+		return repo, err
+	}
+	return repo, models.UpdateRepository(repo, false)
+}
+
+// CleanUpMigrateGitInfo creates the hooks of the repository and removes the remotes
+// of the repository and its wiki. The remote of the repository is removed last so
+// a repository can still be synced as a mirror if any step fails.
+func CleanUpMigrateGitInfo(repo *models.Repository) error {
 	repoPath := repo.RepoPath()
 	if err := createDelegateHooks(repoPath); err != nil {
-		return repo, fmt.Errorf("createDelegateHooks: %v", err)
+		return fmt.Errorf("createDelegateHooks: %v", err)
 	}
 	if repo.HasWiki() {
 		if err := createDelegateHooks(repo.WikiPath()); err != nil {
-			return repo, fmt.Errorf("createDelegateHooks.(wiki): %v", err)
+			return fmt.Errorf("createDelegateHooks.(wiki): %v", err)
 		}
 	}
 
-	_, err := git.NewCommand("remote", "rm", "origin").RunInDir(repoPath)
-	if err != nil && !strings.HasPrefix(err.Error(), "exit status 128 - fatal: No such remote ") {
-		return repo, fmt.Errorf("CleanUpMigrateInfo: %v", err)
-	}
-
 	if repo.HasWiki() {
 		if err := cleanUpMigrateGitConfig(path.Join(repo.WikiPath(), "config")); err != nil {
-			return repo, fmt.Errorf("cleanUpMigrateGitConfig (wiki): %v", err)
+			return fmt.Errorf("cleanUpMigrateGitConfig (wiki): %v", err)
 		}
 	}
 
-	return repo, models.UpdateRepository(repo, false)
+	_, err := git.NewCommand("remote", "rm", "origin").RunInDir(repoPath)
+	if err != nil && !strings.HasPrefix(err.Error(), "exit status 128 - fatal: No such remote ") {
+		return fmt.Errorf("CleanUpMigrateInfo: %v", err)
+	}
+	return nil
 }
 
 // SyncReleasesWithTags synchronizes release table with repository tags
@@ -0,0 +1,90 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// sizeQueue represents a queue of the IDs of the repositories whose size has to be recalculated
+var sizeQueue queue.UniqueQueue
+
+var (
+	delayedSizeUpdatesLock sync.Mutex
+	delayedSizeUpdates     = make(map[int64]struct{})
+)
+
+func handleSizeUpdate(data ...queue.Data) {
+	for _, datum := range data {
+		repoID := datum.(int64)
+		repo, err := models.GetRepositoryByID(repoID)
+		if err != nil {
+			if !models.IsErrRepoNotExist(err) {
+				log.Error("GetRepositoryByID[%d]: %v", repoID, err)
+			}
+			continue
+		}
+
+		// the size of a repository is calculated at most once per interval, walking large repositories is expensive
+		if wait := time.Until(repo.SizeUpdatedUnix.AsTime().Add(setting.Repository.SizeUpdateMinInterval)); wait > 0 {
+			delaySizeUpdate(repoID, wait)
+			continue
+		}
+		if err := repo.UpdateSize(db.DefaultContext); err != nil {
+			log.Error("Failed to update size for repository %-v: %v", repo, err)
+		}
+	}
+}
+
+// delaySizeUpdate queues the update of the size of the repository once the wait is over, unless it is already delayed
+func delaySizeUpdate(repoID int64, wait time.Duration) {
+	delayedSizeUpdatesLock.Lock()
+	defer delayedSizeUpdatesLock.Unlock()
+	if _, ok := delayedSizeUpdates[repoID]; ok {
+		return
+	}
+	delayedSizeUpdates[repoID] = struct{}{}
+
+	time.AfterFunc(wait, func() {
+		delayedSizeUpdatesLock.Lock()
+		delete(delayedSizeUpdates, repoID)
+		delayedSizeUpdatesLock.Unlock()
+		if err := pushSizeUpdate(repoID); err != nil {
+			log.Error("Unable to queue the size update of repository %d: %v", repoID, err)
+		}
+	})
+}
+
+// InitSizeQueue creates the queue recalculating the sizes of repositories
+func InitSizeQueue() error {
+	sizeQueue = queue.CreateUniqueQueue("repo_size_update", handleSizeUpdate, int64(0))
+	if sizeQueue == nil {
+		return errors.New("unable to create repo_size_update Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(sizeQueue.Run)
+	return nil
+}
+
+func pushSizeUpdate(repoID int64) error {
+	if err := sizeQueue.Push(repoID); err != nil && err != queue.ErrAlreadyInQueue {
+		return err
+	}
+	return nil
+}
+
+// QueueUpdateSize queues the recalculation of the size of the repository, a repository is only queued once
+func QueueUpdateSize(repo *models.Repository) error {
+	return pushSizeUpdate(repo.ID)
+}
@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandleSizeUpdate(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	defer func(interval time.Duration) {
+		setting.Repository.SizeUpdateMinInterval = interval
+	}(setting.Repository.SizeUpdateMinInterval)
+	setting.Repository.SizeUpdateMinInterval = time.Hour
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.Zero(t, repo.SizeUpdatedUnix)
+	expected, err := repo.ComputeSize()
+	assert.NoError(t, err)
+
+	handleSizeUpdate(repo.ID)
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	assert.Equal(t, expected, repo.Size)
+	assert.NotZero(t, repo.SizeUpdatedUnix)
+
+	// a repository whose size has just been calculated is delayed
+	repo.Size = 1
+	repo.SizeUpdatedUnix = timeutil.TimeStampNow()
+	assert.NoError(t, models.UpdateRepositoryCols(repo, "size", "size_updated_unix"))
+	handleSizeUpdate(repo.ID)
+	db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1, Size: 1})
+	delayedSizeUpdatesLock.Lock()
+	_, delayed := delayedSizeUpdates[repo.ID]
+	delete(delayedSizeUpdates, repo.ID)
+	delayedSizeUpdatesLock.Unlock()
+	assert.True(t, delayed)
+
+	// removed repositories are skipped
+	handleSizeUpdate(db.NonexistentID)
+}
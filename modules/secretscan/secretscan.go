@@ -0,0 +1,239 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package secretscan
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/options"
+
+	"github.com/gobwas/glob"
+)
+
+// IgnoreFile is the path of the file listing the files which are not scanned, read from the target branch of a push
+const IgnoreFile = ".gitea/secret-scan-ignore"
+
+// maxLineLength is the length of the beginning of a line which is scanned, the rest of longer lines is skipped
+const maxLineLength = 64 * 1024
+
+// Rule is a pattern of secrets
+type Rule struct {
+	ID      string
+	Pattern *regexp.Regexp
+}
+
+// ParseRules parses a rule set. Every line is the identifier of a rule followed by its regular expression,
+// empty lines and lines starting with # are skipped.
+func ParseRules(content []byte) ([]*Rule, error) {
+	var rules []*Rule
+	for i, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.SplitN(line, " ", 2)
+		if len(fields) != 2 || strings.TrimSpace(fields[1]) == "" {
+			return nil, fmt.Errorf("line %d: a rule needs an identifier and a pattern", i+1)
+		}
+		pattern, err := regexp.Compile(strings.TrimSpace(fields[1]))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern of rule %s: %v", i+1, fields[0], err)
+		}
+		rules = append(rules, &Rule{ID: fields[0], Pattern: pattern})
+	}
+	return rules, nil
+}
+
+var (
+	rules     []*Rule
+	rulesErr  error
+	rulesOnce sync.Once
+)
+
+// Rules returns the rules of all the rule sets in the secretscan directory of the options,
+// a rule set of the custom path replaces the shipped one with the same name
+func Rules() ([]*Rule, error) {
+	rulesOnce.Do(func() {
+		names, err := options.Dir("secretscan")
+		if err != nil {
+			rulesErr = fmt.Errorf("unable to list the secret scan rule sets: %v", err)
+			return
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			data, err := options.SecretScan(name)
+			if err != nil {
+				rulesErr = fmt.Errorf("unable to read the secret scan rule set %s: %v", name, err)
+				return
+			}
+			set, err := ParseRules(data)
+			if err != nil {
+				rulesErr = fmt.Errorf("invalid secret scan rule set %s: %v", name, err)
+				return
+			}
+			rules = append(rules, set...)
+		}
+		log.Trace("Loaded %d secret scan rules", len(rules))
+	})
+	return rules, rulesErr
+}
+
+// Ignore is the list of glob patterns of the files which are not scanned, patterns without a / are matched
+// against the file name and the others against the full path
+type Ignore struct {
+	patterns []string
+	globs    []glob.Glob
+}
+
+// ParseIgnore parses the content of an ignore file, every line is a pattern. Empty lines, lines starting
+// with # and invalid patterns are skipped.
+func ParseIgnore(content []byte) *Ignore {
+	ignore := &Ignore{}
+	for _, line := range strings.Split(string(content), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		g, err := glob.Compile(line, '/')
+		if err != nil {
+			log.Debug("Invalid pattern %q in %s: %v", line, IgnoreFile, err)
+			continue
+		}
+		ignore.patterns = append(ignore.patterns, line)
+		ignore.globs = append(ignore.globs, g)
+	}
+	return ignore
+}
+
+// IsIgnored returns true if the file is not scanned, nothing is ignored by a nil Ignore
+func (ignore *Ignore) IsIgnored(treePath string) bool {
+	if ignore == nil {
+		return false
+	}
+	for i, g := range ignore.globs {
+		if strings.Contains(ignore.patterns[i], "/") {
+			if g.Match(treePath) {
+				return true
+			}
+		} else if g.Match(path.Base(treePath)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Finding is a line added by a commit which matches a rule
+type Finding struct {
+	CommitID string
+	Path     string
+	Line     int
+	RuleID   string
+}
+
+func (f *Finding) String() string {
+	return fmt.Sprintf("%s:%d in commit %s matches rule %s", f.Path, f.Line, f.CommitID, f.RuleID)
+}
+
+var hunkHeaderPattern = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// ScanPatch scans the lines added by the commits of the output of `git log -p -U0 --format="commit %H"`,
+// every line is reported with the first rule it matches. The secrets themselves are not reported.
+func ScanPatch(r io.Reader, rules []*Rule, ignore *Ignore) ([]*Finding, error) {
+	var (
+		findings []*Finding
+		commitID string
+		treePath string
+		// the added lines are only in hunks, the headers of files can look alike
+		inHunk  bool
+		skipped bool
+		lineNum int
+	)
+
+	rd := bufio.NewReader(r)
+	for {
+		line, err := readLine(rd)
+		if err == io.EOF {
+			return findings, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case bytes.HasPrefix(line, []byte("commit ")):
+			commitID = string(line[len("commit "):])
+			treePath, inHunk = "", false
+		case bytes.HasPrefix(line, []byte("diff --git ")):
+			treePath, inHunk = "", false
+		case !inHunk && bytes.HasPrefix(line, []byte("+++ ")):
+			treePath = parsePatchPath(string(line[len("+++ "):]))
+			skipped = treePath == "" || ignore.IsIgnored(treePath)
+		case bytes.HasPrefix(line, []byte("@@ ")):
+			m := hunkHeaderPattern.FindSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("unexpected hunk header in commit %s: %q", commitID, line)
+			}
+			lineNum, _ = strconv.Atoi(string(m[1]))
+			inHunk = true
+		case inHunk && len(line) > 0 && line[0] == '+':
+			if !skipped {
+				for _, rule := range rules {
+					if rule.Pattern.Match(line[1:]) {
+						findings = append(findings, &Finding{CommitID: commitID, Path: treePath, Line: lineNum, RuleID: rule.ID})
+						break
+					}
+				}
+			}
+			lineNum++
+		}
+	}
+}
+
+// readLine reads a line without its line break, only the first maxLineLength bytes of longer lines are returned
+func readLine(rd *bufio.Reader) ([]byte, error) {
+	var line []byte
+	for {
+		part, isPrefix, err := rd.ReadLine()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return line, nil
+			}
+			return nil, err
+		}
+		if len(line) < maxLineLength {
+			line = append(line, part...)
+		}
+		if !isPrefix {
+			if len(line) > maxLineLength {
+				line = line[:maxLineLength]
+			}
+			return line, nil
+		}
+	}
+}
+
+// parsePatchPath returns the path of the new file of the +++ header of a patch, empty for a deleted file
+func parsePatchPath(name string) string {
+	if name == "/dev/null" {
+		return ""
+	}
+	if strings.HasPrefix(name, `"`) {
+		// git quotes paths with special characters like C strings
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+	}
+	return strings.TrimPrefix(name, "b/")
+}
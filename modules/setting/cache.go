@@ -26,9 +26,11 @@ var (
 		Cache `ini:"cache"`
 
 		LastCommit struct {
-			Enabled      bool
-			TTL          time.Duration `ini:"ITEM_TTL"`
-			CommitsCount int64
+			Enabled                 bool
+			TTL                     time.Duration `ini:"ITEM_TTL"`
+			CommitsCount            int64
+			Size                    int
+			PrecomputeDefaultBranch bool
 		} `ini:"cache.last_commit"`
 	}{
 		Cache: Cache{
@@ -38,9 +40,11 @@ var (
 			TTL:      16 * time.Hour,
 		},
 		LastCommit: struct {
-			Enabled      bool
-			TTL          time.Duration `ini:"ITEM_TTL"`
-			CommitsCount int64
+			Enabled                 bool
+			TTL                     time.Duration `ini:"ITEM_TTL"`
+			CommitsCount            int64
+			Size                    int
+			PrecomputeDefaultBranch bool
 		}{
 			Enabled:      true,
 			TTL:          8760 * time.Hour,
@@ -88,6 +92,8 @@ func newCacheService() {
 	}
 
 	CacheService.LastCommit.CommitsCount = sec.Key("COMMITS_COUNT").MustInt64(1000)
+	CacheService.LastCommit.Size = sec.Key("SIZE").MustInt(0)
+	CacheService.LastCommit.PrecomputeDefaultBranch = sec.Key("PRECOMPUTE_DEFAULT_BRANCH").MustBool(false)
 
 	if CacheService.LastCommit.Enabled {
 		log.Info("Last Commit Cache Service Enabled")
@@ -27,7 +27,11 @@ var (
 		PullRequestPushMessage    bool
 		LargeObjectThreshold      int64
 		DisableCoreProtectNTFS    bool
-		Timeout                   struct {
+		// RepoConfig is applied to the git config of new repositories and of all repositories by the sync of their git config
+		RepoConfig map[string]string `ini:"-"`
+		// RepoConfigUnset lists the keys removed from the git config of all repositories by the sync of their git config
+		RepoConfigUnset []string
+		Timeout         struct {
 			Default int
 			Migrate int
 			Mirror  int
@@ -70,4 +74,11 @@ func newGit() {
 	if err := Cfg.Section("git").MapTo(&Git); err != nil {
 		log.Fatal("Failed to map Git settings: %v", err)
 	}
+
+	Git.RepoConfig = Cfg.Section("git.repo_config").KeysHash()
+	for _, key := range Git.RepoConfigUnset {
+		if _, ok := Git.RepoConfig[key]; ok {
+			log.Fatal("The git config key %s is both set in [git.repo_config] and unset by REPO_CONFIG_UNSET", key)
+		}
+	}
 }
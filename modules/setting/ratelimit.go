@@ -0,0 +1,62 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+var (
+	// APIRateLimit settings
+	APIRateLimit = struct {
+		Enabled bool
+		// Period is the time in which a full bucket of requests is refilled
+		Period              time.Duration
+		ReadLimit           int
+		WriteLimit          int
+		AnonymousReadLimit  int
+		AnonymousWriteLimit int
+		ExemptUsers         []string // lower names of the users without limits
+		ExemptIPRanges      []*net.IPNet
+	}{
+		Enabled:             false,
+		Period:              time.Hour,
+		ReadLimit:           5000,
+		WriteLimit:          1000,
+		AnonymousReadLimit:  60,
+		AnonymousWriteLimit: 60,
+	}
+)
+
+func newAPIRateLimit() {
+	sec := Cfg.Section("api.rate_limit")
+	APIRateLimit.Enabled = sec.Key("ENABLED").MustBool(APIRateLimit.Enabled)
+	APIRateLimit.Period = sec.Key("PERIOD").MustDuration(APIRateLimit.Period)
+	if APIRateLimit.Period <= 0 {
+		APIRateLimit.Period = time.Hour
+	}
+	APIRateLimit.ReadLimit = sec.Key("READ_LIMIT").MustInt(APIRateLimit.ReadLimit)
+	APIRateLimit.WriteLimit = sec.Key("WRITE_LIMIT").MustInt(APIRateLimit.WriteLimit)
+	APIRateLimit.AnonymousReadLimit = sec.Key("ANONYMOUS_READ_LIMIT").MustInt(APIRateLimit.AnonymousReadLimit)
+	APIRateLimit.AnonymousWriteLimit = sec.Key("ANONYMOUS_WRITE_LIMIT").MustInt(APIRateLimit.AnonymousWriteLimit)
+
+	APIRateLimit.ExemptUsers = APIRateLimit.ExemptUsers[:0]
+	for _, name := range sec.Key("EXEMPT_USERS").Strings(",") {
+		APIRateLimit.ExemptUsers = append(APIRateLimit.ExemptUsers, strings.ToLower(name))
+	}
+	APIRateLimit.ExemptIPRanges = APIRateLimit.ExemptIPRanges[:0]
+	for _, cidr := range sec.Key("EXEMPT_IP_RANGES").Strings(",") {
+		_, block, err := net.ParseCIDR(cidr)
+		if err != nil {
+			log.Error("Invalid IP range %q in [api.rate_limit] EXEMPT_IP_RANGES: %v", cidr, err)
+			continue
+		}
+		APIRateLimit.ExemptIPRanges = append(APIRateLimit.ExemptIPRanges, block)
+	}
+}
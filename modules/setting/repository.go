@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -29,8 +30,12 @@ var (
 		DefaultPrivate                          string
 		DefaultPushCreatePrivate                bool
 		MaxCreationLimit                        int
+		MaxNameLength                           int
 		PreferredLicenses                       []string
 		DisableHTTPGit                          bool
+		DisableDumbHTTP                         bool   `ini:"DISABLE_DUMB_HTTP"`
+		DefaultCloneFilter                      string `ini:"DEFAULT_CLONE_FILTER"`
+		FullCloneMaxSize                        int64  `ini:"FULL_CLONE_MAX_SIZE"`
 		AccessControlAllowOrigin                string
 		UseCompatSSHURI                         bool
 		DefaultCloseIssuesViaCommitsInAnyBranch bool
@@ -44,6 +49,10 @@ var (
 		DefaultBranch                           string
 		AllowAdoptionOfUnadoptedRepositories    bool
 		AllowDeleteOfUnadoptedRepositories      bool
+		ServeRenamedOwnerGit                    bool `ini:"SERVE_RENAMED_OWNER_GIT"`
+		HealthCheckTimeout                      time.Duration
+		SizeUpdateMinInterval                   time.Duration
+		CloneTokenExpiration                    time.Duration
 
 		// Repository editor settings
 		Editor struct {
@@ -70,24 +79,54 @@ var (
 			WorkInProgressPrefixes                   []string
 			CloseKeywords                            []string
 			ReopenKeywords                           []string
+			AdditionalCloseKeywords                  []string
+			AdditionalReopenKeywords                 []string
 			DefaultMergeMessageCommitsLimit          int
 			DefaultMergeMessageSize                  int
 			DefaultMergeMessageAllAuthors            bool
 			DefaultMergeMessageMaxApprovers          int
 			DefaultMergeMessageOfficialApproversOnly bool
 			PopulateSquashCommentWithCommitMessages  bool
+			MarkApprovalsStaleOnRetarget             bool
 		} `ini:"repository.pull-request"`
 
 		// Issue Setting
 		Issue struct {
-			LockReasons []string
+			LockReasons           []string
+			DefaultSortType       string
+			PrivateReferenceStubs bool
 		} `ini:"repository.issue"`
 
 		Release struct {
 			AllowedTypes     string
 			DefaultPagingNum int
+			MaxFetchSize     int64
 		} `ini:"repository.release"`
 
+		// Push rules every repository has to follow in addition to its own
+		PushRules struct {
+			ForbiddenPaths           []string
+			MaxFileSize              int64
+			MaxPathLength            int
+			ForbiddenCommitterEmails []string
+			ScanSecrets              bool
+		} `ini:"repository.push-rules"`
+
+		// Quotas of the key-value metadata of every repository
+		Metadata struct {
+			MaxKeys      int
+			MaxTotalSize int64
+			AllowWriters bool
+		} `ini:"repository.metadata"`
+
+		// Clones and views of the repositories
+		Traffic struct {
+			Enabled            bool
+			FlushInterval      time.Duration
+			DailyRetentionDays int
+			RetentionDays      int
+		} `ini:"repository.traffic"`
+
 		Signing struct {
 			SigningKey        string
 			SigningName       string
@@ -140,6 +179,7 @@ var (
 		DefaultPrivate:                          RepoCreatingLastUserVisibility,
 		DefaultPushCreatePrivate:                true,
 		MaxCreationLimit:                        -1,
+		MaxNameLength:                           100,
 		PreferredLicenses:                       []string{"Apache License 2.0", "MIT License"},
 		DisableHTTPGit:                          false,
 		AccessControlAllowOrigin:                "",
@@ -153,6 +193,10 @@ var (
 		DisableMigrations:                       false,
 		DisableStars:                            false,
 		DefaultBranch:                           "master",
+		ServeRenamedOwnerGit:                    true,
+		HealthCheckTimeout:                      5 * time.Minute,
+		SizeUpdateMinInterval:                   time.Minute,
+		CloneTokenExpiration:                    8 * time.Hour,
 
 		// Repository editor settings
 		Editor: struct {
@@ -190,39 +234,90 @@ var (
 			WorkInProgressPrefixes                   []string
 			CloseKeywords                            []string
 			ReopenKeywords                           []string
+			AdditionalCloseKeywords                  []string
+			AdditionalReopenKeywords                 []string
 			DefaultMergeMessageCommitsLimit          int
 			DefaultMergeMessageSize                  int
 			DefaultMergeMessageAllAuthors            bool
 			DefaultMergeMessageMaxApprovers          int
 			DefaultMergeMessageOfficialApproversOnly bool
 			PopulateSquashCommentWithCommitMessages  bool
+			MarkApprovalsStaleOnRetarget             bool
 		}{
 			WorkInProgressPrefixes: []string{"WIP:", "[WIP]"},
 			// Same as GitHub. See
 			// https://help.github.com/articles/closing-issues-via-commit-messages
 			CloseKeywords:                            strings.Split("close,closes,closed,fix,fixes,fixed,resolve,resolves,resolved", ","),
 			ReopenKeywords:                           strings.Split("reopen,reopens,reopened", ","),
+			AdditionalCloseKeywords:                  []string{},
+			AdditionalReopenKeywords:                 []string{},
 			DefaultMergeMessageCommitsLimit:          50,
 			DefaultMergeMessageSize:                  5 * 1024,
 			DefaultMergeMessageAllAuthors:            false,
 			DefaultMergeMessageMaxApprovers:          10,
 			DefaultMergeMessageOfficialApproversOnly: true,
 			PopulateSquashCommentWithCommitMessages:  false,
+			MarkApprovalsStaleOnRetarget:             true,
 		},
 
 		// Issue settings
 		Issue: struct {
-			LockReasons []string
+			LockReasons           []string
+			DefaultSortType       string
+			PrivateReferenceStubs bool
 		}{
-			LockReasons: strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			LockReasons:           strings.Split("Too heated,Off-topic,Spam,Resolved", ","),
+			DefaultSortType:       "latest",
+			PrivateReferenceStubs: false,
 		},
 
 		Release: struct {
 			AllowedTypes     string
 			DefaultPagingNum int
+			MaxFetchSize     int64
 		}{
 			AllowedTypes:     "",
 			DefaultPagingNum: 10,
+			MaxFetchSize:     2048,
+		},
+
+		// Push rules settings
+		PushRules: struct {
+			ForbiddenPaths           []string
+			MaxFileSize              int64
+			MaxPathLength            int
+			ForbiddenCommitterEmails []string
+			ScanSecrets              bool
+		}{
+			ForbiddenPaths:           []string{},
+			MaxFileSize:              0,
+			MaxPathLength:            0,
+			ForbiddenCommitterEmails: []string{},
+			ScanSecrets:              false,
+		},
+
+		// Metadata settings
+		Metadata: struct {
+			MaxKeys      int
+			MaxTotalSize int64
+			AllowWriters bool
+		}{
+			MaxKeys:      100,
+			MaxTotalSize: 1024 * 1024,
+			AllowWriters: false,
+		},
+
+		// Traffic settings
+		Traffic: struct {
+			Enabled            bool
+			FlushInterval      time.Duration
+			DailyRetentionDays int
+			RetentionDays      int
+		}{
+			Enabled:            true,
+			FlushInterval:      time.Minute,
+			DailyRetentionDays: 14,
+			RetentionDays:      365,
 		},
 
 		// Signing settings
@@ -252,6 +347,13 @@ var (
 	RepoArchive = struct {
 		Storage
 	}{}
+
+	RepoBackup = struct {
+		Storage
+		Retention int
+	}{
+		Retention: 7,
+	}
 )
 
 func newRepository() {
@@ -261,6 +363,7 @@ func newRepository() {
 	Repository.DisableHTTPGit = sec.Key("DISABLE_HTTP_GIT").MustBool()
 	Repository.UseCompatSSHURI = sec.Key("USE_COMPAT_SSH_URI").MustBool()
 	Repository.MaxCreationLimit = sec.Key("MAX_CREATION_LIMIT").MustInt(-1)
+	Repository.MaxNameLength = sec.Key("MAX_NAME_LENGTH").MustInt(100)
 	Repository.DefaultBranch = sec.Key("DEFAULT_BRANCH").MustString(Repository.DefaultBranch)
 	RepoRootPath = sec.Key("ROOT").MustString(path.Join(AppDataPath, "gitea-repositories"))
 	forcePathSeparator(RepoRootPath)
@@ -287,6 +390,14 @@ func newRepository() {
 		log.Fatal("Failed to map Repository.PullRequest settings: %v", err)
 	}
 
+	// the traffic of the last 14 days is always served per day
+	if Repository.Traffic.DailyRetentionDays < 14 {
+		Repository.Traffic.DailyRetentionDays = 14
+	}
+	if Repository.Traffic.FlushInterval <= 0 {
+		Repository.Traffic.FlushInterval = time.Minute
+	}
+
 	// Handle default trustmodel settings
 	Repository.Signing.DefaultTrustModel = strings.ToLower(strings.TrimSpace(Repository.Signing.DefaultTrustModel))
 	if Repository.Signing.DefaultTrustModel == "default" {
@@ -331,4 +442,6 @@ func newRepository() {
 	}
 
 	RepoArchive.Storage = getStorage("repo-archive", "", nil)
+	RepoBackup.Storage = getStorage("repo-backup", "", nil)
+	RepoBackup.Retention = Cfg.Section("repo-backup").Key("RETENTION").MustInt(RepoBackup.Retention)
 }
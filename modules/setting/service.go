@@ -59,7 +59,11 @@ var Service = struct {
 	EnableUserHeatmap                       bool
 	AutoWatchNewRepos                       bool
 	AutoWatchOnChanges                      bool
+	DefaultWatchedRepos                     []string
 	DefaultOrgMemberVisible                 bool
+	MaxUserNameLength                       int
+	MaxSavedReplies                         int
+	MaxSavedReplyLength                     int
 	UserDeleteWithCommentsMaxTime           time.Duration
 	ValidSiteURLSchemes                     []string
 
@@ -76,6 +80,9 @@ var Service = struct {
 	} `ini:"service.explore"`
 }{
 	AllowedUserVisibilityModesSlice: []bool{true, true, true},
+	MaxUserNameLength:               100,
+	MaxSavedReplies:                 100,
+	MaxSavedReplyLength:             65535,
 }
 
 // AllowedVisibility store in a 3 item bool array what is allowed
@@ -147,6 +154,13 @@ func newService() {
 	Service.EnableUserHeatmap = sec.Key("ENABLE_USER_HEATMAP").MustBool(true)
 	Service.AutoWatchNewRepos = sec.Key("AUTO_WATCH_NEW_REPOS").MustBool(true)
 	Service.AutoWatchOnChanges = sec.Key("AUTO_WATCH_ON_CHANGES").MustBool(false)
+	for _, fullName := range sec.Key("DEFAULT_WATCHED_REPOS").Strings(",") {
+		if names := strings.Split(fullName, "/"); len(names) != 2 || names[0] == "" || names[1] == "" {
+			log.Error("Invalid repository %q in DEFAULT_WATCHED_REPOS, it must be given as owner/name", fullName)
+			continue
+		}
+		Service.DefaultWatchedRepos = append(Service.DefaultWatchedRepos, fullName)
+	}
 	Service.DefaultUserVisibility = sec.Key("DEFAULT_USER_VISIBILITY").In("public", structs.ExtractKeysFromMapString(structs.VisibilityModes))
 	Service.DefaultUserVisibilityMode = structs.VisibilityModes[Service.DefaultUserVisibility]
 	Service.AllowedUserVisibilityModes = sec.Key("ALLOWED_USER_VISIBILITY_MODES").Strings(",")
@@ -159,6 +173,9 @@ func newService() {
 	Service.DefaultOrgVisibility = sec.Key("DEFAULT_ORG_VISIBILITY").In("public", structs.ExtractKeysFromMapString(structs.VisibilityModes))
 	Service.DefaultOrgVisibilityMode = structs.VisibilityModes[Service.DefaultOrgVisibility]
 	Service.DefaultOrgMemberVisible = sec.Key("DEFAULT_ORG_MEMBER_VISIBLE").MustBool()
+	Service.MaxUserNameLength = sec.Key("MAX_USER_NAME_LENGTH").MustInt(100)
+	Service.MaxSavedReplies = sec.Key("MAX_SAVED_REPLIES").MustInt(100)
+	Service.MaxSavedReplyLength = sec.Key("MAX_SAVED_REPLY_LENGTH").MustInt(65535)
 	Service.UserDeleteWithCommentsMaxTime = sec.Key("USER_DELETE_WITH_COMMENTS_MAX_TIME").MustDuration(0)
 	sec.Key("VALID_SITE_URL_SCHEMES").MustString("http,https")
 	Service.ValidSiteURLSchemes = sec.Key("VALID_SITE_URL_SCHEMES").Strings(",")
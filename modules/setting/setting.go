@@ -143,6 +143,7 @@ var (
 		AuthorizedKeysCommandTemplateTemplate *template.Template `ini:"-"`
 		MinimumKeySizeCheck                   bool               `ini:"-"`
 		MinimumKeySizes                       map[string]int     `ini:"-"`
+		MinimumKeySizeCheckOnAuth             bool               `ini:"-"`
 		CreateAuthorizedKeysFile              bool               `ini:"SSH_CREATE_AUTHORIZED_KEYS_FILE"`
 		CreateAuthorizedPrincipalsFile        bool               `ini:"SSH_CREATE_AUTHORIZED_PRINCIPALS_FILE"`
 		ExposeAnonymous                       bool               `ini:"SSH_EXPOSE_ANONYMOUS"`
@@ -189,6 +190,7 @@ var (
 	PasswordHashAlgo                   string
 	PasswordCheckPwn                   bool
 	SuccessfulTokensCacheSize          int
+	RecordTokenLastUsedIP              bool
 
 	// UI settings
 	UI = struct {
@@ -324,6 +326,7 @@ var (
 	Admin struct {
 		DisableRegularOrgCreation bool
 		DefaultEmailNotification  string
+		NoticeDeduplicationWindow time.Duration
 	}
 
 	// Log settings
@@ -775,6 +778,7 @@ func NewContext() {
 	}
 
 	SSH.MinimumKeySizeCheck = sec.Key("MINIMUM_KEY_SIZE_CHECK").MustBool(SSH.MinimumKeySizeCheck)
+	SSH.MinimumKeySizeCheckOnAuth = SSH.MinimumKeySizeCheck && sec.Key("MINIMUM_KEY_SIZE_CHECK_ON_AUTH").MustBool(false)
 	minimumKeySizes := Cfg.Section("ssh.minimum_key_sizes").Keys()
 	for _, key := range minimumKeySizes {
 		if key.MustInt() != -1 {
@@ -813,6 +817,7 @@ func NewContext() {
 
 	sec = Cfg.Section("admin")
 	Admin.DefaultEmailNotification = sec.Key("DEFAULT_EMAIL_NOTIFICATIONS").MustString("enabled")
+	Admin.NoticeDeduplicationWindow = sec.Key("NOTICE_DEDUPLICATION_WINDOW").MustDuration(time.Hour)
 
 	sec = Cfg.Section("security")
 	InstallLock = sec.Key("INSTALL_LOCK").MustBool(false)
@@ -839,6 +844,7 @@ func NewContext() {
 	CSRFCookieHTTPOnly = sec.Key("CSRF_COOKIE_HTTP_ONLY").MustBool(true)
 	PasswordCheckPwn = sec.Key("PASSWORD_CHECK_PWN").MustBool(false)
 	SuccessfulTokensCacheSize = sec.Key("SUCCESSFUL_TOKENS_CACHE_SIZE").MustInt(20)
+	RecordTokenLastUsedIP = sec.Key("RECORD_TOKEN_LAST_USED_IP").MustBool(false)
 
 	InternalToken = loadInternalToken(sec)
 
@@ -856,6 +862,7 @@ func NewContext() {
 
 	newAttachmentService()
 	newLFSService()
+	newUserExportService()
 
 	timeFormatKey := Cfg.Section("time").Key("FORMAT").MustString("")
 	if timeFormatKey != "" {
@@ -944,6 +951,7 @@ func NewContext() {
 	u := *appURL
 	u.Path = path.Join(u.Path, "api", "swagger")
 	API.SwaggerURL = u.String()
+	newAPIRateLimit()
 
 	newGit()
 
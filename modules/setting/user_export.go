@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package setting
+
+import "time"
+
+var (
+	// UserExport settings
+	UserExport = struct {
+		Storage
+		DownloadLinkExpiry time.Duration
+	}{
+		DownloadLinkExpiry: 24 * time.Hour,
+	}
+)
+
+func newUserExportService() {
+	sec := Cfg.Section("user-export")
+	UserExport.Storage = getStorage("user-export", "", nil)
+	UserExport.DownloadLinkExpiry = sec.Key("DOWNLOAD_LINK_EXPIRY").MustDuration(UserExport.DownloadLinkExpiry)
+}
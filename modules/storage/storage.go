@@ -125,6 +125,12 @@ var (
 
 	// RepoArchives represents repository archives storage
 	RepoArchives ObjectStorage
+
+	// RepoBackups represents the storage of the scheduled repository backups
+	RepoBackups ObjectStorage
+
+	// UserExports represents the storage of the data exports requested by users
+	UserExports ObjectStorage
 )
 
 // Init init the stoarge
@@ -145,7 +151,15 @@ func Init() error {
 		return err
 	}
 
-	return initRepoArchives()
+	if err := initRepoArchives(); err != nil {
+		return err
+	}
+
+	if err := initRepoBackups(); err != nil {
+		return err
+	}
+
+	return initUserExports()
 }
 
 // NewStorage takes a storage type and some config and returns an ObjectStorage or an error
@@ -190,3 +204,15 @@ func initRepoArchives() (err error) {
 	RepoArchives, err = NewStorage(setting.RepoArchive.Storage.Type, &setting.RepoArchive.Storage)
 	return
 }
+
+func initRepoBackups() (err error) {
+	log.Info("Initialising Repository Backup storage with type: %s", setting.RepoBackup.Storage.Type)
+	RepoBackups, err = NewStorage(setting.RepoBackup.Storage.Type, &setting.RepoBackup.Storage)
+	return
+}
+
+func initUserExports() (err error) {
+	log.Info("Initialising User Export storage with type: %s", setting.UserExport.Storage.Type)
+	UserExports, err = NewStorage(setting.UserExport.Storage.Type, &setting.UserExport.Storage)
+	return
+}
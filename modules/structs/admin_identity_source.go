@@ -0,0 +1,147 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// The types of identity sources
+const (
+	IdentitySourceLDAP   = "ldap"
+	IdentitySourceDLDAP  = "dldap"
+	IdentitySourceSMTP   = "smtp"
+	IdentitySourcePAM    = "pam"
+	IdentitySourceOAuth2 = "oauth2"
+	IdentitySourceSSPI   = "sspi"
+)
+
+// IdentitySource represents an external way for authenticating users.
+// Only the configuration matching the type is set, secrets are never returned.
+type IdentitySource struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// enum: ldap,dldap,smtp,pam,oauth2,sspi
+	Type          string                      `json:"type"`
+	IsActive      bool                        `json:"is_active"`
+	IsSyncEnabled bool                        `json:"is_sync_enabled"`
+	LDAP          *IdentitySourceLDAPConfig   `json:"ldap,omitempty"`
+	SMTP          *IdentitySourceSMTPConfig   `json:"smtp,omitempty"`
+	PAM           *IdentitySourcePAMConfig    `json:"pam,omitempty"`
+	OAuth2        *IdentitySourceOAuth2Config `json:"oauth2,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// IdentitySourceLDAPConfig is the configuration of an LDAP identity source, used by the types ldap (via BindDN)
+// and dldap (simple auth)
+type IdentitySourceLDAPConfig struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// enum: unencrypted,ldaps,starttls
+	SecurityProtocol string `json:"security_protocol"`
+	SkipVerify       bool   `json:"skip_verify"`
+	BindDN           string `json:"bind_dn"`
+	// write-only, an empty password keeps the current one when editing
+	BindPassword          string `json:"bind_password,omitempty"`
+	UserBase              string `json:"user_base"`
+	UserDN                string `json:"user_dn"`
+	AttributeUsername     string `json:"attribute_username"`
+	AttributeName         string `json:"attribute_name"`
+	AttributeSurname      string `json:"attribute_surname"`
+	AttributeMail         string `json:"attribute_mail"`
+	AttributeSSHPublicKey string `json:"attribute_ssh_public_key"`
+	AttributeAvatar       string `json:"attribute_avatar"`
+	AttributesInBind      bool   `json:"attributes_in_bind"`
+	// page size of searches, 0 disables paged searches
+	SearchPageSize     int    `json:"search_page_size"`
+	Filter             string `json:"filter"`
+	AdminFilter        string `json:"admin_filter"`
+	RestrictedFilter   string `json:"restricted_filter"`
+	GroupsEnabled      bool   `json:"groups_enabled"`
+	GroupDN            string `json:"group_dn"`
+	GroupFilter        string `json:"group_filter"`
+	GroupMemberUID     string `json:"group_member_uid"`
+	UserUID            string `json:"user_uid"`
+	AllowDeactivateAll bool   `json:"allow_deactivate_all"`
+	SkipLocalTwoFA     bool   `json:"skip_local_2fa"`
+	// map of group DNs to organization names to team names, the group members are added to the teams on synchronization
+	GroupTeamMap map[string]map[string][]string `json:"group_team_map,omitempty"`
+	// remove users from the mapped teams when they are not a member of the group anymore
+	GroupTeamMapRemoval bool `json:"group_team_map_removal"`
+}
+
+// IdentitySourceSMTPConfig is the configuration of an SMTP identity source
+type IdentitySourceSMTPConfig struct {
+	// enum: PLAIN,LOGIN,CRAM-MD5
+	Auth           string `json:"auth"`
+	Host           string `json:"host"`
+	Port           int    `json:"port"`
+	AllowedDomains string `json:"allowed_domains"`
+	ForceSMTPS     bool   `json:"force_smtps"`
+	SkipVerify     bool   `json:"skip_verify"`
+	HeloHostname   string `json:"helo_hostname"`
+	DisableHelo    bool   `json:"disable_helo"`
+	SkipLocalTwoFA bool   `json:"skip_local_2fa"`
+}
+
+// IdentitySourcePAMConfig is the configuration of a PAM identity source
+type IdentitySourcePAMConfig struct {
+	ServiceName    string `json:"service_name"`
+	EmailDomain    string `json:"email_domain"`
+	SkipLocalTwoFA bool   `json:"skip_local_2fa"`
+}
+
+// IdentitySourceOAuth2Config is the configuration of an OAuth2 identity source
+type IdentitySourceOAuth2Config struct {
+	Provider string `json:"provider"`
+	ClientID string `json:"client_id"`
+	// write-only, an empty secret keeps the current one when editing
+	ClientSecret                  string                    `json:"client_secret,omitempty"`
+	OpenIDConnectAutoDiscoveryURL string                    `json:"open_id_connect_auto_discovery_url"`
+	CustomURLMapping              *IdentitySourceOAuth2URLs `json:"custom_url_mapping"`
+	IconURL                       string                    `json:"icon_url"`
+	SkipLocalTwoFA                bool                      `json:"skip_local_2fa"`
+}
+
+// IdentitySourceOAuth2URLs are the custom URLs of an OAuth2 provider
+type IdentitySourceOAuth2URLs struct {
+	AuthURL    string `json:"auth_url"`
+	TokenURL   string `json:"token_url"`
+	ProfileURL string `json:"profile_url"`
+	EmailURL   string `json:"email_url"`
+	Tenant     string `json:"tenant"`
+}
+
+// CreateIdentitySourceOption options for creating an identity source.
+// The configuration matching the type is required.
+type CreateIdentitySourceOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(30)"`
+	// required: true
+	// enum: ldap,dldap,smtp,pam,oauth2
+	Type string `json:"type" binding:"Required;In(ldap,dldap,smtp,pam,oauth2)"`
+	// defaults to true
+	IsActive      *bool                       `json:"is_active"`
+	IsSyncEnabled bool                        `json:"is_sync_enabled"`
+	LDAP          *IdentitySourceLDAPConfig   `json:"ldap"`
+	SMTP          *IdentitySourceSMTPConfig   `json:"smtp"`
+	PAM           *IdentitySourcePAMConfig    `json:"pam"`
+	OAuth2        *IdentitySourceOAuth2Config `json:"oauth2"`
+}
+
+// EditIdentitySourceOption options for editing an identity source. The type can't be changed,
+// a given configuration has to match it and replaces the current one.
+type EditIdentitySourceOption struct {
+	Name          *string                     `json:"name" binding:"MaxSize(30)"`
+	IsActive      *bool                       `json:"is_active"`
+	IsSyncEnabled *bool                       `json:"is_sync_enabled"`
+	LDAP          *IdentitySourceLDAPConfig   `json:"ldap"`
+	SMTP          *IdentitySourceSMTPConfig   `json:"smtp"`
+	PAM           *IdentitySourcePAMConfig    `json:"pam"`
+	OAuth2        *IdentitySourceOAuth2Config `json:"oauth2"`
+}
@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// Notice represents a system notice for admins
+type Notice struct {
+	ID int64 `json:"id"`
+	// enum: repository,task,security
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// number of times an identical notice was created within the deduplication window
+	Count int64 `json:"count"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	LastSeen time.Time `json:"last_seen_at"`
+}
+
+// DeleteNoticesOption options for deleting system notices, at least one of them is required
+type DeleteNoticesOption struct {
+	// IDs of the notices to delete
+	IDs []int64 `json:"ids"`
+	// delete only the notices of this type
+	// enum: repository,task,security
+	Type string `json:"type"`
+	// delete only the notices whose descriptions contain this text
+	Query string `json:"q"`
+	// delete only the notices last seen before this time
+	// swagger:strfmt date-time
+	Before *time.Time `json:"before"`
+}
@@ -44,5 +44,44 @@ type EditUserOption struct {
 	ProhibitLogin           *bool   `json:"prohibit_login"`
 	AllowCreateOrganization *bool   `json:"allow_create_organization"`
 	Restricted              *bool   `json:"restricted"`
-	Visibility              string  `json:"visibility" binding:"In(,public,limited,private)"`
+	// service accounts are never deactivated or deleted for not signing in
+	ServiceAccount *bool  `json:"service_account"`
+	Visibility     string `json:"visibility" binding:"In(,public,limited,private)"`
+}
+
+// CreateUsersOption options for creating users in bulk
+type CreateUsersOption struct {
+	// required: true
+	Users []*CreateUsersEntry `json:"users" binding:"Required"`
+	// send the registration notification mails to the created users in the background
+	SendNotify bool `json:"send_notify"`
+}
+
+// CreateUsersEntry a user to create in bulk, users without a login source need an initial password
+type CreateUsersEntry struct {
+	SourceID  int64  `json:"source_id"`
+	LoginName string `json:"login_name"`
+	// required: true
+	Username string `json:"username"`
+	FullName string `json:"full_name"`
+	// required: true
+	// swagger:strfmt email
+	Email              string `json:"email"`
+	Password           string `json:"password"`
+	MustChangePassword *bool  `json:"must_change_password"`
+	// enum: public,limited,private
+	Visibility string `json:"visibility"`
+	// SSH public keys of the user
+	SSHKeys []string `json:"ssh_keys"`
+}
+
+// CreateUsersResult the result of creating a user in bulk
+type CreateUsersResult struct {
+	Username string `json:"username"`
+	// created if the user was created, conflict if the name or email address of the user are used already,
+	// invalid if the user can't be created as given or failed
+	// enum: created,conflict,invalid,failed
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	User   *User  `json:"user,omitempty"`
 }
@@ -26,3 +26,29 @@ type Attachment struct {
 type EditAttachmentOptions struct {
 	Name string `json:"name"`
 }
+
+// ReleaseAttachmentTask represents the fetching of a release attachment from a URL by the server
+type ReleaseAttachmentTask struct {
+	ID int64 `json:"id"`
+	// enum: queued,running,stopped,failed,finished
+	Status string `json:"status"`
+	// the reason of the failure if the status is failed
+	Message string `json:"message,omitempty"`
+	// the URL the attachment is fetched from, stripped of credentials
+	SourceURL string `json:"source_url"`
+	Name      string `json:"name"`
+	// the content type sent by the server the attachment is fetched from
+	ContentType string `json:"content_type,omitempty"`
+	// the number of bytes fetched so far
+	FetchedSize int64 `json:"fetched_size"`
+	// the size announced by the server the attachment is fetched from, -1 if it is unknown
+	TotalSize int64 `json:"total_size"`
+	// the created attachment once the status is finished
+	Attachment *Attachment `json:"attachment,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at,omitempty"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
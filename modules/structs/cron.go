@@ -14,3 +14,22 @@ type Cron struct {
 	Prev      time.Time `json:"prev"`
 	ExecTimes int64     `json:"exec_times"`
 }
+
+// InactiveUser represents an inactive user handled by the delete_inactive_accounts cron task
+type InactiveUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created"`
+	// why the user is kept, empty if the user is deleted
+	SkipReason string `json:"skip_reason,omitempty"`
+}
+
+// DeleteInactiveUsersReport represents the result of deleting the inactive users
+type DeleteInactiveUsersReport struct {
+	DryRun bool `json:"dry_run"`
+	// number of users deleted, or to be deleted in dry run mode
+	Deleted int             `json:"deleted"`
+	Skipped int             `json:"skipped"`
+	Users   []*InactiveUser `json:"users"`
+}
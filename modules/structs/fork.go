@@ -8,4 +8,22 @@ package structs
 type CreateForkOption struct {
 	// organization name, if forking into an organization
 	Organization *string `json:"organization"`
+	// name of the forked repository, the name of the repository to fork if empty
+	Name *string `json:"name" binding:"OmitEmpty;AlphaDashDot;MaxSize(100)"`
+	// whether a site admin forks a public repository into an organization which requires private repositories
+	OverrideVisibilityPolicy bool `json:"override_visibility_policy"`
+}
+
+// ForkSyncStatus represents how a branch of a fork relates to its base repository
+type ForkSyncStatus struct {
+	Branch string `json:"branch"`
+	// full name of the base repository
+	BaseRepository string `json:"base_repository"`
+	BaseBranch     string `json:"base_branch"`
+	// the commit the branch of the fork points at
+	Commit string `json:"commit"`
+	// number of commits the branch has that the base branch does not
+	Ahead int `json:"ahead"`
+	// number of commits the base branch has that the branch does not
+	Behind int `json:"behind"`
 }
@@ -0,0 +1,13 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// GitConfigTemplate is the git config applied to new repositories and by the sync of the git config of all repositories
+type GitConfigTemplate struct {
+	// the keys set to their values
+	Config map[string]string `json:"config"`
+	// the keys removed by the sync
+	Unset []string `json:"unset"`
+}
@@ -26,10 +26,19 @@ type Hook struct {
 	Config map[string]string `json:"config"`
 	Events []string          `json:"events"`
 	Active bool              `json:"active"`
+	// globs of the branches of the events, those starting with "!" exclude the branches they match
+	BranchFilters []string `json:"branch_filters"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
+	// time of the last secret rotation, not set if the secret was never rotated
+	// swagger:strfmt date-time
+	SecretRotated *time.Time `json:"secret_rotated_at,omitempty"`
+	// number of days summarized by each digest
+	DigestWindowDays int `json:"digest_window_days"`
+	// whether digests are also sent if there was no activity
+	DigestSendEmpty bool `json:"digest_send_empty"`
 }
 
 // HookList represents a list of API hook.
@@ -37,6 +46,7 @@ type HookList []*Hook
 
 // CreateHookOptionConfig has all config options in it
 // required are "content_type" and "url" Required
+// gitea and gogs hooks also accept "signature_algorithm" (sha256 or sha1)
 type CreateHookOptionConfig map[string]string
 
 // CreateHookOption options when create a hook
@@ -45,19 +55,49 @@ type CreateHookOption struct {
 	// enum: dingtalk,discord,gitea,gogs,msteams,slack,telegram,feishu,wechatwork
 	Type string `json:"type" binding:"Required"`
 	// required: true
-	Config       CreateHookOptionConfig `json:"config" binding:"Required"`
-	Events       []string               `json:"events"`
-	BranchFilter string                 `json:"branch_filter" binding:"GlobPattern"`
+	Config CreateHookOptionConfig `json:"config" binding:"Required"`
+	Events []string               `json:"events"`
+	// deprecated: a single glob, only used if branch_filters is not set
+	BranchFilter string `json:"branch_filter" binding:"GlobPattern"`
+	// globs of the branches of the push, branch creation and deletion, pull request (base branch)
+	// and release (target branch) events, those starting with "!" exclude the branches they match
+	BranchFilters []string `json:"branch_filters" binding:"GlobPatternList"`
+	// number of days summarized by each digest, defaults to 7
+	DigestWindowDays int `json:"digest_window_days"`
+	// send digests also if there was no activity
+	DigestSendEmpty bool `json:"digest_send_empty"`
 	// default: false
 	Active bool `json:"active"`
 }
 
+// RotateHookSecretOption options when rotating the secret of a hook
+type RotateHookSecretOption struct {
+	// number of seconds during which deliveries are signed with both the previous and the new secret
+	GracePeriod int64 `json:"grace_period"`
+}
+
+// HookSecret represents a newly generated hook secret, it is only returned once
+type HookSecret struct {
+	Secret string `json:"secret"`
+	// swagger:strfmt date-time
+	SecretRotated time.Time `json:"secret_rotated_at"`
+	// time until deliveries are also signed with the previous secret
+	// swagger:strfmt date-time
+	PreviousSecretExpires *time.Time `json:"previous_secret_expires_at,omitempty"`
+}
+
 // EditHookOption options when modify one hook
 type EditHookOption struct {
-	Config       map[string]string `json:"config"`
-	Events       []string          `json:"events"`
-	BranchFilter string            `json:"branch_filter" binding:"GlobPattern"`
-	Active       *bool             `json:"active"`
+	Config map[string]string `json:"config"`
+	Events []string          `json:"events"`
+	// deprecated: a single glob, only used if branch_filters is not set
+	BranchFilter string `json:"branch_filter" binding:"GlobPattern"`
+	// globs of the branches of the push, branch creation and deletion, pull request (base branch)
+	// and release (target branch) events, those starting with "!" exclude the branches they match
+	BranchFilters    []string `json:"branch_filters" binding:"GlobPatternList"`
+	DigestWindowDays *int     `json:"digest_window_days"`
+	DigestSendEmpty  *bool    `json:"digest_send_empty"`
+	Active           *bool    `json:"active"`
 }
 
 // Payloader payload is some part of one hook
@@ -100,6 +140,8 @@ type PayloadCommitVerification struct {
 	Signature string       `json:"signature"`
 	Signer    *PayloadUser `json:"signer"`
 	Payload   string       `json:"payload"`
+	// whether the signer is trusted under the trust model of the repository, only given for tags
+	TrustStatus string `json:"trust_status,omitempty"`
 }
 
 var (
@@ -112,6 +154,9 @@ var (
 	_ Payloader = &PullRequestPayload{}
 	_ Payloader = &RepositoryPayload{}
 	_ Payloader = &ReleasePayload{}
+	_ Payloader = &DigestPayload{}
+	_ Payloader = &DeployKeyPayload{}
+	_ Payloader = &PublicKeyPayload{}
 )
 
 // _________                        __
@@ -340,6 +385,10 @@ const (
 	HookIssueDemilestoned HookIssueAction = "demilestoned"
 	// HookIssueReviewed is an issue action for when a pull request is reviewed
 	HookIssueReviewed HookIssueAction = "reviewed"
+	// HookIssueReviewRequested is an issue action for when a team is requested to review a pull request
+	HookIssueReviewRequested HookIssueAction = "review_requested"
+	// HookIssueReadyForReview is an issue action for when a draft pull request is marked as ready for review
+	HookIssueReadyForReview HookIssueAction = "ready_for_review"
 )
 
 // IssuePayload represents the payload information that is sent along with an issue event.
@@ -385,6 +434,8 @@ type PullRequestPayload struct {
 	Repository  *Repository     `json:"repository"`
 	Sender      *User           `json:"sender"`
 	Review      *ReviewPayload  `json:"review"`
+	// RequestedTeam is only set for review_requested actions sent to a team
+	RequestedTeam *Team `json:"requested_team,omitempty"`
 }
 
 // JSONPayload FIXME
@@ -427,3 +478,74 @@ type RepositoryPayload struct {
 func (p *RepositoryPayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", " ")
 }
+
+// DigestContributor is a user who was active in a repository during the period of a digest
+type DigestContributor struct {
+	User *User `json:"user"`
+	// number of actions of the user shown in the activity feed of the repository
+	Actions int64 `json:"actions"`
+}
+
+// DigestPayload summarizes the activity of a repository during a period, it is sent periodically
+type DigestPayload struct {
+	// swagger:strfmt date-time
+	Since time.Time `json:"since"`
+	// swagger:strfmt date-time
+	Until              time.Time            `json:"until"`
+	IssuesOpened       int64                `json:"issues_opened"`
+	IssuesClosed       int64                `json:"issues_closed"`
+	PullRequestsOpened int64                `json:"pull_requests_opened"`
+	PullRequestsMerged int64                `json:"pull_requests_merged"`
+	Contributors       []*DigestContributor `json:"contributors"`
+	Repository         *Repository          `json:"repository"`
+}
+
+// JSONPayload implements Payload
+func (p *DigestPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookKeyAction an action that happens to a deploy key or a public key
+type HookKeyAction string
+
+const (
+	// HookKeyAdded added
+	HookKeyAdded HookKeyAction = "added"
+	// HookKeyRemoved removed
+	HookKeyRemoved HookKeyAction = "removed"
+)
+
+// PayloadKey represents an SSH key in webhook payloads, its content is never sent
+type PayloadKey struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Fingerprint string `json:"fingerprint"`
+}
+
+// DeployKeyPayload payload for deploy key webhooks
+type DeployKeyPayload struct {
+	Action     HookKeyAction `json:"action"`
+	Key        *PayloadKey   `json:"key"`
+	ReadOnly   bool          `json:"read_only"`
+	Repository *Repository   `json:"repository"`
+	Sender     *User         `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *DeployKeyPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// PublicKeyPayload payload for the webhooks of the SSH keys of users, only sent to system webhooks
+type PublicKeyPayload struct {
+	Action HookKeyAction `json:"action"`
+	Key    *PayloadKey   `json:"key"`
+	// the user the key belongs to
+	Owner  *User `json:"owner"`
+	Sender *User `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *PublicKeyPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
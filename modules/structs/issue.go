@@ -59,7 +59,9 @@ type Issue struct {
 	// enum: open,closed
 	State    StateType `json:"state"`
 	IsLocked bool      `json:"is_locked"`
-	Comments int       `json:"comments"`
+	// position of the issue among the pinned issues of its repository, 0 if it is not pinned
+	PinOrder int `json:"pin_order"`
+	Comments int `json:"comments"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -71,6 +73,18 @@ type Issue struct {
 
 	PullRequest *PullRequestMeta `json:"pull_request"`
 	Repo        *RepositoryMeta  `json:"repository"`
+	// labels and assignees of the issue template which could not be applied, only set when the issue is created
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// ExportedIssue represents an issue or a pull request in an export of a repository's issues
+type ExportedIssue struct {
+	*Issue
+	// total tracked time in seconds
+	TotalTrackedTime int64         `json:"total_tracked_time"`
+	Attachments      []*Attachment `json:"assets"`
+	// only filled if the comments have been requested
+	CommentList []*Comment `json:"comment_list,omitempty"`
 }
 
 // CreateIssueOption options to create one issue
@@ -89,6 +103,11 @@ type CreateIssueOption struct {
 	// list of label ids
 	Labels []int64 `json:"labels"`
 	Closed bool    `json:"closed"`
+	// file name of the issue template whose title, labels and assignees are applied, e.g. `bug_report.md`,
+	// the values of an issue form are validated against it
+	Template string `json:"template"`
+	// values of the issue form fields by field id, body is ignored when a form is used
+	Fields map[string][]string `json:"fields"`
 }
 
 // EditIssueOption options for editing an issue
@@ -129,6 +148,73 @@ type IssueTemplate struct {
 	Labels   []string `json:"labels" yaml:"labels"`
 	Content  string   `json:"content" yaml:"-"`
 	FileName string   `json:"file_name" yaml:"-"`
+	// names of the users assigned to the issues created from the template
+	Assignees []string `json:"assignees" yaml:"assignees"`
+	// fields of an issue form, only set for YAML templates
+	Fields []*IssueFormField `json:"body,omitempty" yaml:"body"`
+}
+
+// IsForm returns whether the template is a YAML issue form
+func (it IssueTemplate) IsForm() bool {
+	return len(it.Fields) > 0
+}
+
+// IssueFormFieldType defines issue form field type, can be "markdown", "textarea", "input", "dropdown" or "checkboxes"
+type IssueFormFieldType string
+
+const (
+	// IssueFormFieldTypeMarkdown is a static markdown block which is not submitted
+	IssueFormFieldTypeMarkdown IssueFormFieldType = "markdown"
+	// IssueFormFieldTypeTextarea is a multi-line text input
+	IssueFormFieldTypeTextarea IssueFormFieldType = "textarea"
+	// IssueFormFieldTypeInput is a single-line text input
+	IssueFormFieldTypeInput IssueFormFieldType = "input"
+	// IssueFormFieldTypeDropdown is a selection of one or more options
+	IssueFormFieldTypeDropdown IssueFormFieldType = "dropdown"
+	// IssueFormFieldTypeCheckboxes is a set of checkboxes
+	IssueFormFieldTypeCheckboxes IssueFormFieldType = "checkboxes"
+)
+
+// IssueFormField represents a form field of an issue form
+// swagger:model
+type IssueFormField struct {
+	Type        IssueFormFieldType        `json:"type" yaml:"type"`
+	ID          string                    `json:"id" yaml:"id"`
+	Attributes  IssueFormFieldAttributes  `json:"attributes" yaml:"attributes"`
+	Validations IssueFormFieldValidations `json:"validations" yaml:"validations"`
+}
+
+// IssueFormFieldAttributes represents the attributes of an issue form field
+type IssueFormFieldAttributes struct {
+	Label       string                  `json:"label,omitempty" yaml:"label"`
+	Description string                  `json:"description,omitempty" yaml:"description"`
+	Placeholder string                  `json:"placeholder,omitempty" yaml:"placeholder"`
+	Value       string                  `json:"value,omitempty" yaml:"value"`
+	Render      string                  `json:"render,omitempty" yaml:"render"`
+	Multiple    bool                    `json:"multiple,omitempty" yaml:"multiple"`
+	Options     []*IssueFormFieldOption `json:"options,omitempty" yaml:"options"`
+}
+
+// IssueFormFieldValidations represents the validations of an issue form field
+type IssueFormFieldValidations struct {
+	Required bool `json:"required" yaml:"required"`
+}
+
+// IssueFormFieldOption represents an option of a dropdown or checkboxes field
+type IssueFormFieldOption struct {
+	Label    string `json:"label" yaml:"label"`
+	Required bool   `json:"required,omitempty" yaml:"required"`
+}
+
+// UnmarshalYAML accepts both plain string options (dropdown) and mappings (checkboxes)
+func (o *IssueFormFieldOption) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var label string
+	if err := unmarshal(&label); err == nil {
+		o.Label = label
+		return nil
+	}
+	type option IssueFormFieldOption
+	return unmarshal((*option)(o))
 }
 
 // Valid checks whether an IssueTemplate is considered valid, e.g. at least name and about
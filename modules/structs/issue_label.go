@@ -13,7 +13,9 @@ type Label struct {
 	// example: 00aabb
 	Color       string `json:"color"`
 	Description string `json:"description"`
-	URL         string `json:"url"`
+	// whether an issue can only have one label of the scope of the label, the part of its name before the last "/"
+	Exclusive bool   `json:"exclusive"`
+	URL       string `json:"url"`
 }
 
 // CreateLabelOption options for creating a label
@@ -24,6 +26,7 @@ type CreateLabelOption struct {
 	// example: #00aabb
 	Color       string `json:"color" binding:"Required"`
 	Description string `json:"description"`
+	Exclusive   bool   `json:"exclusive"`
 }
 
 // EditLabelOption options for editing a label
@@ -31,6 +34,7 @@ type EditLabelOption struct {
 	Name        *string `json:"name"`
 	Color       *string `json:"color"`
 	Description *string `json:"description"`
+	Exclusive   *bool   `json:"exclusive"`
 }
 
 // IssueLabelsOption a collection of labels
@@ -38,3 +42,19 @@ type IssueLabelsOption struct {
 	// list of label IDs
 	Labels []int64 `json:"labels"`
 }
+
+// CopyLabelsOption options for copying the labels of another repository
+type CopyLabelsOption struct {
+	// full name of the repository whose labels are copied
+	// required:true
+	// example: owner/repo
+	Source string `json:"source" binding:"Required"`
+}
+
+// CopiedLabels the result of copying the labels of another repository
+type CopiedLabels struct {
+	// the labels created in the repository
+	Created []*Label `json:"created"`
+	// the labels of the source repository not copied since the repository already has labels of their names
+	Skipped []*Label `json:"skipped"`
+}
@@ -24,6 +24,8 @@ type Milestone struct {
 	Closed *time.Time `json:"closed_at"`
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_on"`
+	// whether an open milestone is past its due date or a closed one was closed after it
+	IsOverdue bool `json:"is_overdue"`
 }
 
 // CreateMilestoneOption options for creating a milestone
@@ -43,3 +45,19 @@ type EditMilestoneOption struct {
 	State       *string    `json:"state"`
 	Deadline    *time.Time `json:"due_on"`
 }
+
+// CopyMilestonesOption options for copying the open milestones of another repository
+type CopyMilestonesOption struct {
+	// full name of the repository whose milestones are copied
+	// required:true
+	// example: owner/repo
+	Source string `json:"source" binding:"Required"`
+}
+
+// CopiedMilestones the result of copying the open milestones of another repository
+type CopiedMilestones struct {
+	// the milestones created in the repository
+	Created []*Milestone `json:"created"`
+	// the milestones of the source repository not copied since the repository already has milestones of their titles
+	Skipped []*Milestone `json:"skipped"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// IssueReference is an issue, pull request or commit referencing an issue or referenced by it
+type IssueReference struct {
+	// enum: issue,pull,commit
+	Type string `json:"type"`
+	// the reference is from a repository the user can't read, no details of it are given
+	Private    bool            `json:"private"`
+	Repository *RepositoryMeta `json:"repository,omitempty"`
+	// index of the issue or pull request
+	Index int64     `json:"number,omitempty"`
+	Title string    `json:"title,omitempty"`
+	State StateType `json:"state,omitempty"`
+	// SHA of the commit
+	SHA     string `json:"sha,omitempty"`
+	HTMLURL string `json:"html_url,omitempty"`
+	// the reference closes the referenced issue
+	Closes bool `json:"closes"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -0,0 +1,12 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// IssueSubscription represents an issue or pull request a user is subscribed to
+type IssueSubscription struct {
+	Issue *Issue `json:"issue"`
+	// false if the user is subscribed because they posted or commented on the issue
+	Explicit bool `json:"explicit"`
+}
@@ -15,6 +15,8 @@ type Organization struct {
 	Location                  string `json:"location"`
 	Visibility                string `json:"visibility"`
 	RepoAdminChangeTeamAccess bool   `json:"repo_admin_change_team_access"`
+	// whether the organization can have public repositories
+	AllowPublicRepos bool `json:"allow_public_repos"`
 }
 
 // OrganizationPermissions list differents users permissions on an organization
@@ -52,4 +54,6 @@ type EditOrgOption struct {
 	// enum: public,limited,private
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess *bool  `json:"repo_admin_change_team_access"`
+	// whether the organization can have public repositories, existing public repositories are kept
+	AllowPublicRepos *bool `json:"allow_public_repos"`
 }
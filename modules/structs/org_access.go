@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RepositoryAccessPath represents a team or a collaboration by which a team or a user has access to a repository
+type RepositoryAccessPath struct {
+	// the team giving the access, 0 for a collaboration
+	TeamID                  int64  `json:"team_id"`
+	TeamName                string `json:"team_name"`
+	IncludesAllRepositories bool   `json:"includes_all_repositories"`
+	Collaboration           bool   `json:"collaboration"`
+	// enum: read,write,admin,owner
+	Permission string `json:"permission"`
+}
+
+// AccessibleRepository represents a repository of an organization a team or a user has access to
+type AccessibleRepository struct {
+	Repository *Repository `json:"repository"`
+	// the highest access given by all the paths
+	// enum: read,write,admin,owner
+	Permission string                  `json:"permission"`
+	Paths      []*RepositoryAccessPath `json:"paths"`
+}
@@ -15,8 +15,14 @@ type Team struct {
 	// enum: none,read,write,admin,owner
 	Permission string `json:"permission"`
 	// example: ["repo.code","repo.issues","repo.ext_issues","repo.wiki","repo.pulls","repo.releases","repo.projects","repo.ext_wiki"]
-	Units            []string `json:"units"`
-	CanCreateOrgRepo bool     `json:"can_create_org_repo"`
+	Units []string `json:"units"`
+	// the permission of the team to each of its units
+	// example: {"repo.code":"read","repo.wiki":"write"}
+	UnitsMap         map[string]string `json:"units_map"`
+	CanCreateOrgRepo bool              `json:"can_create_org_repo"`
+	// how the team is notified when it is requested to review a pull request
+	// enum: email_all,email_none,webhook_only
+	ReviewNotification string `json:"review_notification"`
 }
 
 // CreateTeamOption options for creating a team
@@ -28,8 +34,15 @@ type CreateTeamOption struct {
 	// enum: read,write,admin
 	Permission string `json:"permission"`
 	// example: ["repo.code","repo.issues","repo.ext_issues","repo.wiki","repo.pulls","repo.releases","repo.projects","repo.ext_wiki"]
-	Units            []string `json:"units"`
-	CanCreateOrgRepo bool     `json:"can_create_org_repo"`
+	Units []string `json:"units"`
+	// read or write permission to single units of read and write teams, overriding the permission of the team,
+	// the units are enabled even if they aren't listed in units
+	// example: {"repo.code":"read","repo.wiki":"write"}
+	UnitsMap         map[string]string `json:"units_map"`
+	CanCreateOrgRepo bool              `json:"can_create_org_repo"`
+	// how the team is notified when it is requested to review a pull request
+	// enum: email_all,email_none,webhook_only
+	ReviewNotification string `json:"review_notification" binding:"In(,email_all,email_none,webhook_only)"`
 }
 
 // EditTeamOption options for editing a team
@@ -41,6 +54,13 @@ type EditTeamOption struct {
 	// enum: read,write,admin
 	Permission string `json:"permission"`
 	// example: ["repo.code","repo.issues","repo.ext_issues","repo.wiki","repo.pulls","repo.releases","repo.projects","repo.ext_wiki"]
-	Units            []string `json:"units"`
-	CanCreateOrgRepo *bool    `json:"can_create_org_repo"`
+	Units []string `json:"units"`
+	// read or write permission to single units of read and write teams, overriding the permission of the team,
+	// the units are enabled even if they aren't listed in units
+	// example: {"repo.code":"read","repo.wiki":"write"}
+	UnitsMap         map[string]string `json:"units_map"`
+	CanCreateOrgRepo *bool             `json:"can_create_org_repo"`
+	// how the team is notified when it is requested to review a pull request
+	// enum: email_all,email_none,webhook_only
+	ReviewNotification *string `json:"review_notification"`
 }
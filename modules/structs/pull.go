@@ -23,13 +23,17 @@ type PullRequest struct {
 	State     StateType  `json:"state"`
 	IsLocked  bool       `json:"is_locked"`
 	Comments  int        `json:"comments"`
+	// whether the pull request is a draft, either flagged or by a work in progress title prefix
+	Draft bool `json:"draft"`
 
 	HTMLURL  string `json:"html_url"`
 	DiffURL  string `json:"diff_url"`
 	PatchURL string `json:"patch_url"`
 
 	Mergeable bool `json:"mergeable"`
-	HasMerged bool `json:"merged"`
+	// why the pull request can't be merged yet, e.g. "blocked: branch out of date"
+	MergeBlockedReason string `json:"merge_blocked_reason,omitempty"`
+	HasMerged          bool   `json:"merged"`
 	// swagger:strfmt date-time
 	Merged         *time.Time `json:"merged_at"`
 	MergedCommitID *string    `json:"merge_commit_sha"`
@@ -50,6 +54,10 @@ type PullRequest struct {
 	Closed *time.Time `json:"closed_at"`
 }
 
+// MergeBlockedByOutdatedBranch is the merge blocked reason of a pull request whose head branch has to be
+// updated with its base branch before it can be merged
+const MergeBlockedByOutdatedBranch = "blocked: branch out of date"
+
 // PRBranchInfo information about a branch
 type PRBranchInfo struct {
 	Name       string      `json:"label"`
@@ -77,6 +85,7 @@ type CreatePullRequestOption struct {
 	Labels    []int64  `json:"labels"`
 	// swagger:strfmt date-time
 	Deadline *time.Time `json:"due_date"`
+	Draft    bool       `json:"draft"`
 }
 
 // EditPullRequestOption options when modify pull request
@@ -92,4 +101,29 @@ type EditPullRequestOption struct {
 	// swagger:strfmt date-time
 	Deadline       *time.Time `json:"due_date"`
 	RemoveDeadline *bool      `json:"unset_due_date"`
+	// false marks a draft as ready for review, which also removes a work in progress prefix from the title
+	Draft *bool `json:"draft"`
+}
+
+// PullRequestRefsCleanup result of deleting the head refs of closed pull requests
+type PullRequestRefsCleanup struct {
+	// number of deleted head refs
+	Deleted int `json:"deleted"`
+}
+
+// MergeQueueEntry represents a pull request waiting in the merge queue of its base branch
+type MergeQueueEntry struct {
+	// position in the merge queue of the base branch, the first one is merged next
+	Position   int64  `json:"position"`
+	BaseBranch string `json:"base_branch"`
+	// enum: merge,rebase,rebase-merge,squash
+	MergeStyle string `json:"merge_style"`
+	// the user who added the pull request to the merge queue
+	Enqueuer *User `json:"enqueuer"`
+	// the ref the merge with the base branch is tested on, empty until the test merge is created
+	TestRef string `json:"test_ref"`
+	// the commit of the test merge the required status checks run on
+	TestCommit string `json:"test_commit"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
 }
@@ -0,0 +1,41 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// ChangedFile represents a file changed by a pull request
+type ChangedFile struct {
+	Filename string `json:"filename"`
+	// whether the authenticated user marked the file as viewed at the current head commit or before it changed
+	Viewed bool `json:"viewed"`
+	// whether the file changed after the authenticated user marked it as viewed
+	ChangedSinceViewed bool `json:"changed_since_viewed"`
+}
+
+// PullViewedFile represents a file of a pull request the authenticated user marked as viewed
+type PullViewedFile struct {
+	Path string `json:"path"`
+	// false if the file changed after it was marked as viewed
+	Viewed             bool `json:"viewed"`
+	ChangedSinceViewed bool `json:"changed_since_viewed"`
+	// head commit of the pull request when the file was marked as viewed
+	CommitID string `json:"commit_id"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// PullViewedFileOption marks a file of a pull request as viewed or not viewed
+type PullViewedFileOption struct {
+	// required: true
+	Path   string `json:"path" binding:"Required"`
+	Viewed bool   `json:"viewed"`
+}
+
+// UpdatePullViewedFilesOption options for marking files of a pull request as viewed
+type UpdatePullViewedFilesOption struct {
+	// required: true
+	Files []PullViewedFileOption `json:"files" binding:"Required"`
+}
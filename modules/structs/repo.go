@@ -25,6 +25,12 @@ type InternalTracker struct {
 	AllowOnlyContributorsToTrackTime bool `json:"allow_only_contributors_to_track_time"`
 	// Enable dependencies for issues and pull requests (Built-in issue tracker)
 	EnableIssueDependencies bool `json:"enable_issue_dependencies"`
+	// Sort type of the issue and pull request lists if none is given, empty for the default of the instance (Built-in issue tracker)
+	DefaultSortType string `json:"default_sort_type"`
+	// Keywords closing referenced issues, which replace the additional keywords of the instance if not empty (Built-in issue tracker)
+	CloseKeywords []string `json:"close_keywords"`
+	// Keywords reopening referenced issues, which replace the additional keywords of the instance if not empty (Built-in issue tracker)
+	ReopenKeywords []string `json:"reopen_keywords"`
 }
 
 // ExternalTracker represents settings for external tracker
@@ -34,8 +40,10 @@ type ExternalTracker struct {
 	ExternalTrackerURL string `json:"external_tracker_url"`
 	// External Issue Tracker URL Format. Use the placeholders {user}, {repo} and {index} for the username, repository name and issue index.
 	ExternalTrackerFormat string `json:"external_tracker_format"`
-	// External Issue Tracker Number Format, either `numeric` or `alphanumeric`
+	// External Issue Tracker Number Format, either `numeric`, `alphanumeric` or `regexp`
 	ExternalTrackerStyle string `json:"external_tracker_style"`
+	// Pattern of the references of the `regexp` style, its first group is the issue index
+	ExternalTrackerRegexpPattern string `json:"external_tracker_regexp_pattern"`
 }
 
 // ExternalWiki represents setting for external wiki
@@ -62,6 +70,8 @@ type Repository struct {
 	HTMLURL       string      `json:"html_url"`
 	SSHURL        string      `json:"ssh_url"`
 	CloneURL      string      `json:"clone_url"`
+	CloneFilter   string      `json:"clone_filter,omitempty"`
+	CloneTokenURL string      `json:"clone_token_url,omitempty"`
 	OriginalURL   string      `json:"original_url"`
 	Website       string      `json:"website"`
 	Stars         int         `json:"stars_count"`
@@ -75,7 +85,10 @@ type Repository struct {
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
-	Updated                   time.Time        `json:"updated_at"`
+	Updated time.Time `json:"updated_at"`
+	// time of the last push to a branch or tag, null if the repository has never been pushed to
+	// swagger:strfmt date-time
+	LastPushedAt              *time.Time       `json:"last_pushed_at"`
 	Permissions               *Permission      `json:"permissions,omitempty"`
 	HasIssues                 bool             `json:"has_issues"`
 	InternalTracker           *InternalTracker `json:"internal_tracker,omitempty"`
@@ -90,9 +103,37 @@ type Repository struct {
 	AllowRebaseMerge          bool             `json:"allow_rebase_explicit"`
 	AllowSquash               bool             `json:"allow_squash_merge"`
 	DefaultMergeStyle         string           `json:"default_merge_style"`
+	EnableMergeQueue          bool             `json:"enable_merge_queue"`
 	AvatarURL                 string           `json:"avatar_url"`
 	Internal                  bool             `json:"internal"`
 	MirrorInterval            string           `json:"mirror_interval"`
+	// who authors the commits created by merging pull requests: "merger", "pr-author" or empty
+	// for the merger and the pull request author for squash merges
+	MergeCommitAuthorStyle string `json:"merge_commit_author_style"`
+	// whether a Reviewed-by trailer is added for each approving reviewer to the messages of merge commits
+	AddReviewedByTrailers bool `json:"add_reviewed_by_trailers"`
+	// whether a Co-authored-by trailer is added for each author of the squashed commits to the messages of squash merges
+	AddCoAuthorTrailers bool `json:"add_co_author_trailers"`
+	// enum: sha1,sha256
+	ObjectFormat string `json:"object_format"`
+	// how the repository came to exist, its origin is given by parent, template_id or original_url
+	// enum: created,migrated,forked,generated,adopted,pushed-to-create
+	CreatedFrom string `json:"created_from"`
+	// the template repository a generated repository was generated from
+	TemplateID int64 `json:"template_id,omitempty"`
+	// the service a migrated repository was migrated from
+	OriginalServiceType string `json:"original_service_type,omitempty"`
+	// SPDX identifiers of the licenses detected in the license files of the default branch,
+	// "other" for license files without a known license
+	Licenses []string `json:"licenses"`
+	// whether the repository has been pushed to, or its issues or pull requests updated, since the latest visit
+	// of the signed-in user, only set in search results
+	NewActivity bool `json:"new_activity,omitempty"`
+	// the description as plain text with the emoji shortcodes replaced by the emoji
+	DescriptionText string `json:"description_text"`
+	// time the size was last calculated, null if it has not been calculated yet
+	// swagger:strfmt date-time
+	SizeUpdatedAt *time.Time `json:"size_updated_at"`
 }
 
 // CreateRepoOption options when creating repository
@@ -124,6 +165,8 @@ type CreateRepoOption struct {
 	// TrustModel of the repository
 	// enum: default,collaborator,committer,collaboratorcommitter
 	TrustModel string `json:"trust_model"`
+	// Whether a site admin creates a public repository in an organization which requires private repositories
+	OverrideVisibilityPolicy bool `json:"override_visibility_policy"`
 }
 
 // EditRepoOption options when editing a repository's properties
@@ -140,6 +183,8 @@ type EditRepoOption struct {
 	// Note: you will get a 422 error if the organization restricts changing repository visibility to organization
 	// owners and a non-owner tries to change the value of private.
 	Private *bool `json:"private,omitempty"`
+	// `true` if a site admin makes a repository of an organization which requires private repositories public
+	OverrideVisibilityPolicy bool `json:"override_visibility_policy,omitempty"`
 	// either `true` to make this repository a template or `false` to make it a normal repository
 	Template *bool `json:"template,omitempty"`
 	// either `true` to enable issues for this repository or `false` to disable them.
@@ -176,6 +221,14 @@ type EditRepoOption struct {
 	DefaultDeleteBranchAfterMerge *bool `json:"default_delete_branch_after_merge,omitempty"`
 	// set to a merge style to be used by this repository: "merge", "rebase", "rebase-merge", or "squash". `has_pull_requests` must be `true`.
 	DefaultMergeStyle *string `json:"default_merge_style,omitempty"`
+	// set to `true` to allow adding pull requests to a merge queue. `has_pull_requests` must be `true`.
+	EnableMergeQueue *bool `json:"enable_merge_queue,omitempty"`
+	// set to "merger" or "pr-author" to have the commits created by merging pull requests authored by the merger or the pull request author, the other one is the committer. Set to an empty string to have merge commits authored by the merger and squashed commits by the pull request author. `has_pull_requests` must be `true`.
+	MergeCommitAuthorStyle *string `json:"merge_commit_author_style,omitempty"`
+	// set to `true` to add a `Reviewed-by` trailer for each approving reviewer to the messages of merge commits. `has_pull_requests` must be `true`.
+	AddReviewedByTrailers *bool `json:"add_reviewed_by_trailers,omitempty"`
+	// set to `true` to add a `Co-authored-by` trailer for each author of the squashed commits to the messages of squash merges. `has_pull_requests` must be `true`.
+	AddCoAuthorTrailers *bool `json:"add_co_author_trailers,omitempty"`
 	// set to `true` to archive this repository.
 	Archived *bool `json:"archived,omitempty"`
 	// set to a string like `8h30m0s` to set the mirror interval time
@@ -237,6 +290,21 @@ type TransferRepoOption struct {
 	TeamIDs *[]int64 `json:"team_ids"`
 }
 
+// ConvertRepoOption options when converting a repository into a mirror or a normal repository
+// swagger:model
+type ConvertRepoOption struct {
+	// what to convert the repository into
+	// required: true
+	// enum: mirror,normal
+	To string `json:"to" binding:"Required;In(mirror,normal)"`
+	// URL of the repository to mirror, required when converting into a mirror
+	CloneAddr    string `json:"clone_addr"`
+	AuthUsername string `json:"auth_username"`
+	AuthPassword string `json:"auth_password"`
+	// interval of the mirror, e.g. `8h`, defaults to the default interval of the instance
+	MirrorInterval string `json:"mirror_interval"`
+}
+
 // GitServiceType represents a git service
 type GitServiceType int
 
@@ -306,6 +374,40 @@ type MigrateRepoOptions struct {
 	PullRequests   bool   `json:"pull_requests"`
 	Releases       bool   `json:"releases"`
 	MirrorInterval string `json:"mirror_interval"`
+	// Whether a site admin migrates into a public repository of an organization which requires private repositories
+	OverrideVisibilityPolicy bool `json:"override_visibility_policy"`
+}
+
+// SyncMigrationOptions options for resuming or synchronizing the migration of a repository,
+// the credentials replace the ones given to migrate it if set
+type SyncMigrationOptions struct {
+	AuthUsername string `json:"auth_username"`
+	AuthPassword string `json:"auth_password"`
+	AuthToken    string `json:"auth_token"`
+}
+
+// MigrationTask represents the migration of a repository from another service by the server
+type MigrationTask struct {
+	ID int64 `json:"id"`
+	// enum: queued,running,stopped,failed,finished
+	Status string `json:"status"`
+	// the reason of the failure if the status is failed
+	Message string `json:"message,omitempty"`
+	// the index of the issue migrated last
+	MigratedIssueIndex int64 `json:"migrated_issue_index"`
+	// the index of the pull request migrated last
+	MigratedPullIndex int64 `json:"migrated_pull_index"`
+	// the tag of the release migrated last
+	MigratedReleaseTag string `json:"migrated_release_tag"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at,omitempty"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+	// start of the last migration which finished, only entities updated since then are synchronized
+	// swagger:strfmt date-time
+	LastSync *time.Time `json:"last_sync_at,omitempty"`
 }
 
 // TokenAuth represents whether a service type supports token-based auth
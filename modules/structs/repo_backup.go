@@ -0,0 +1,40 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// RepoBackup represents a run of the scheduled backup of a repository
+type RepoBackup struct {
+	ID     int64 `json:"id"`
+	RepoID int64 `json:"repo_id"`
+	// full name of the repository, empty if it has been deleted
+	RepoFullName string `json:"repo_full_name"`
+	// enum: pending,running,succeeded,failed
+	Status string `json:"status"`
+	// path of the files of the backup in the repository backup storage
+	Path      string `json:"path"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error"`
+	// total size of the stored files in bytes
+	Size int64 `json:"size"`
+	// duration of the last attempt in milliseconds
+	Duration int64 `json:"duration"`
+	// time of the next attempt of a pending backup
+	// swagger:strfmt date-time
+	NextAttempt *time.Time `json:"next_attempt_at,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateRepoBackupOption options for queuing the backup of a repository
+type CreateRepoBackupOption struct {
+	// required: true
+	Owner string `json:"owner" binding:"Required"`
+	// required: true
+	Repo string `json:"repo" binding:"Required"`
+}
@@ -41,7 +41,10 @@ type BranchProtection struct {
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	AutoUpdateOnMerge             bool     `json:"auto_update_on_merge"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
+	DismissOnlyOnContentChange    bool     `json:"dismiss_only_on_content_change"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       string   `json:"unprotected_file_patterns"`
@@ -71,7 +74,10 @@ type CreateBranchProtectionOption struct {
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	AutoUpdateOnMerge             bool     `json:"auto_update_on_merge"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
+	DismissOnlyOnContentChange    bool     `json:"dismiss_only_on_content_change"`
+	RequireCodeOwnerApproval      bool     `json:"require_code_owner_approval"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       string   `json:"unprotected_file_patterns"`
@@ -96,8 +102,44 @@ type EditBranchProtectionOption struct {
 	BlockOnRejectedReviews        *bool    `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests *bool    `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         *bool    `json:"block_on_outdated_branch"`
+	AutoUpdateOnMerge             *bool    `json:"auto_update_on_merge"`
 	DismissStaleApprovals         *bool    `json:"dismiss_stale_approvals"`
+	DismissOnlyOnContentChange    *bool    `json:"dismiss_only_on_content_change"`
+	RequireCodeOwnerApproval      *bool    `json:"require_code_owner_approval"`
 	RequireSignedCommits          *bool    `json:"require_signed_commits"`
 	ProtectedFilePatterns         *string  `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       *string  `json:"unprotected_file_patterns"`
 }
+
+// StaleBranch represents a branch which is merged into the default branch or has not been updated for a while
+type StaleBranch struct {
+	Name   string         `json:"name"`
+	Commit *PayloadCommit `json:"commit"`
+	// whether the branch is merged into the default branch
+	Merged bool `json:"merged"`
+}
+
+// StaleBranchCleanup represents the scheduled deletion of the stale branches of a repository
+type StaleBranchCleanup struct {
+	Enabled bool `json:"enabled"`
+	// only delete branches merged into the default branch
+	Merged bool `json:"merged"`
+	// only delete branches whose last commit is older, 0 means any age
+	OlderThanDays int `json:"older_than_days"`
+	// the next run only records the branches it would delete
+	DryRun bool `json:"dry_run"`
+	// swagger:strfmt date-time
+	LastRun *time.Time `json:"last_run,omitempty"`
+	// whether the last run was a dry run
+	LastRunDryRun bool `json:"last_run_dry_run"`
+	// the branches the last run deleted, or would have deleted if it was a dry run
+	LastRunBranches []string `json:"last_run_branches"`
+}
+
+// EditStaleBranchCleanupOption options for editing the scheduled deletion of the stale branches of a repository
+type EditStaleBranchCleanupOption struct {
+	Enabled       *bool `json:"enabled"`
+	Merged        *bool `json:"merged"`
+	OlderThanDays *int  `json:"older_than_days"`
+	DryRun        *bool `json:"dry_run"`
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// CreateRepoCloneTokenOption options for creating a clone token
+type CreateRepoCloneTokenOption struct {
+	// the access the token grants, write requires the permission to write to the code of the repository
+	// enum: read,write
+	Permission string `json:"permission" binding:"In(,read,write)"`
+}
+
+// RepoCloneToken represents a short-lived token to clone, and push to, a repository over HTTP(S)
+type RepoCloneToken struct {
+	ID int64 `json:"id"`
+	// the token, only returned when it is created
+	Token          string `json:"token,omitempty"`
+	TokenLastEight string `json:"token_last_eight"`
+	// enum: read,write
+	Permission string `json:"permission"`
+	// the HTTPS clone URL authenticating with the token, only returned when it is created
+	CloneURL string `json:"clone_url,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Expires time.Time `json:"expires_at"`
+}
@@ -0,0 +1,25 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// CodeOwnersError is an invalid entry of a CODEOWNERS file
+type CodeOwnersError struct {
+	// line of the entry, starting at 1
+	Line int `json:"line"`
+	// column of the invalid part of the entry, starting at 1
+	Column int `json:"column"`
+	// one of "Invalid pattern", "Invalid owner", "Unknown owner" or "Inaccessible owner"
+	Kind string `json:"kind"`
+	// the content of the line
+	Source  string `json:"source"`
+	Message string `json:"message"`
+	// path of the CODEOWNERS file
+	Path string `json:"path"`
+}
+
+// CodeOwnersErrors lists the invalid entries of a CODEOWNERS file
+type CodeOwnersErrors struct {
+	Errors []*CodeOwnersError `json:"errors"`
+}
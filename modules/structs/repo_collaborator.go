@@ -7,4 +7,7 @@ package structs
 // AddCollaboratorOption options when adding a user as a collaborator of a repository
 type AddCollaboratorOption struct {
 	Permission *string `json:"permission"`
+	// write to allow the collaborator to write to the wiki with a lower permission, none to reset it
+	// enum: none,write
+	WikiPermission *string `json:"wiki_permission"`
 }
@@ -62,3 +62,26 @@ type CommitDateOptions struct {
 type CommitAffectedFiles struct {
 	Filename string `json:"filename"`
 }
+
+// ApplyCommitOption options for cherry-picking or reverting a commit onto a branch
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used),
+// a cherry-picked commit keeps its original author if no author is given
+type ApplyCommitOption struct {
+	// message (optional) of the new commit. if not supplied, a default message referencing the commit will be used
+	Message string `json:"message"`
+	// branch (optional) to apply the commit onto. if not given, the default branch is used
+	BranchName string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+	// new_branch (optional) will make a new branch from `branch` for the new commit
+	NewBranchName string            `json:"new_branch" binding:"GitRefName;MaxSize(100)"`
+	Author        Identity          `json:"author"`
+	Committer     Identity          `json:"committer"`
+	Dates         CommitDateOptions `json:"dates"`
+	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
+	Signoff bool `json:"signoff"`
+}
+
+// ApplyCommitConflict lists the files which keep a commit from being applied cleanly
+type ApplyCommitConflict struct {
+	Message string   `json:"message"`
+	Files   []string `json:"files"`
+}
@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// Compare represents the comparison between two revisions
+type Compare struct {
+	BaseCommitID      string `json:"base_commit_id"`
+	HeadCommitID      string `json:"head_commit_id"`
+	MergeBaseCommitID string `json:"merge_base_commit_id"`
+	// number of commits in head that are not in base, the commits list may be shorter
+	TotalCommits int            `json:"total_commits"`
+	Commits      []*Commit      `json:"commits"`
+	Files        []*CompareFile `json:"files"`
+}
+
+// CompareFile represents the changes made to a single file in a comparison
+type CompareFile struct {
+	Filename         string `json:"filename"`
+	PreviousFilename string `json:"previous_filename,omitempty"`
+	// enum: added,copied,deleted,modified,renamed,changed
+	Status    string `json:"status"`
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
+	Binary    bool   `json:"binary"`
+	// unified diff of the file, only set if requested
+	Patch string `json:"patch,omitempty"`
+	// true if the patch was cut off because it was longer than the limit
+	PatchTruncated bool `json:"patch_truncated,omitempty"`
+}
@@ -0,0 +1,37 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// DetachForkOption options for detaching a fork from its base repository
+type DetachForkOption struct {
+	// detach the fork even if pull requests from it to its base repository are open
+	Force bool `json:"force"`
+}
+
+// RepoDetachFork represents the detachment of a fork from its base repository run by the server
+type RepoDetachFork struct {
+	ID int64 `json:"id"`
+	// enum: queued,running,stopped,failed,finished
+	Status string `json:"status"`
+	// the reason of the failure if the status is failed
+	Message string `json:"message,omitempty"`
+	// the progress of the detachment
+	// enum: queued,repacking,converting,finished
+	Stage string `json:"stage"`
+	// the full name of the base repository the fork is detached from, empty if it has been deleted
+	FormerBase string `json:"former_base"`
+	// the API URL of the status of the detachment
+	URL string `json:"url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at,omitempty"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
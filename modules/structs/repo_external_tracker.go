@@ -0,0 +1,31 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ExternalTrackerPreviewOption options for previewing the issue references of an external tracker,
+// an empty format previews the saved settings of the repository
+type ExternalTrackerPreviewOption struct {
+	// commit message to render
+	// required: true
+	Message string `json:"message" binding:"Required"`
+	// External Issue Tracker URL Format. Use the placeholders {user}, {repo} and {index} for the username, repository name and issue index.
+	ExternalTrackerFormat string `json:"external_tracker_format"`
+	// External Issue Tracker Number Format, either `numeric`, `alphanumeric` or `regexp`
+	ExternalTrackerStyle string `json:"external_tracker_style"`
+	// Pattern of the references of the `regexp` style, its first group is the issue index
+	ExternalTrackerRegexpPattern string `json:"external_tracker_regexp_pattern"`
+}
+
+// ExternalTrackerPreviewLink represents an issue reference linked to an external tracker
+type ExternalTrackerPreviewLink struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+// ExternalTrackerPreview represents a commit message rendered with the settings of an external tracker
+type ExternalTrackerPreview struct {
+	HTML  string                        `json:"html"`
+	Links []*ExternalTrackerPreviewLink `json:"links"`
+}
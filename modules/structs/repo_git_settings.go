@@ -0,0 +1,28 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RepoGitSettings represents the git protocol settings in effect for a repository
+type RepoGitSettings struct {
+	// only serve the smart HTTP protocol
+	DisableDumbHTTP bool `json:"disable_dumb_http"`
+	// filter spec recommended to clone the repository, e.g. blob:none, empty for none
+	DefaultCloneFilter string `json:"default_clone_filter"`
+	// size in MB above which clones over HTTP must be partial or shallow, 0 means no limit
+	FullCloneMaxSize int64 `json:"full_clone_max_size"`
+}
+
+// EditRepoGitSettingsOption options for editing the git protocol settings of a repository,
+// settings which are not set keep their current value
+type EditRepoGitSettingsOption struct {
+	// follow the defaults of the instance again, the other settings of the request still override them
+	ResetToDefaults bool `json:"reset_to_defaults"`
+	// only serve the smart HTTP protocol
+	DisableDumbHTTP *bool `json:"disable_dumb_http"`
+	// filter spec recommended to clone the repository, e.g. blob:none, empty for none
+	DefaultCloneFilter *string `json:"default_clone_filter"`
+	// size in MB above which clones over HTTP must be partial or shallow, 0 means no limit
+	FullCloneMaxSize *int64 `json:"full_clone_max_size"`
+}
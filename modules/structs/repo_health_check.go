@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// RepoHealthCheckResult represents the result of one of the checks of a repository health check
+type RepoHealthCheckResult struct {
+	// enum: fsck,hooks,daemon_export,size
+	Name string `json:"name"`
+	OK   bool   `json:"ok"`
+	// what is wrong if the check failed
+	Message string `json:"message,omitempty"`
+}
+
+// RepoHealthCheck represents a health check of a repository run by the server
+type RepoHealthCheck struct {
+	ID int64 `json:"id"`
+	// enum: queued,running,stopped,failed,finished
+	Status string `json:"status"`
+	// the reason of the failure if the status is failed
+	Message string `json:"message,omitempty"`
+	// whether all checks passed, only set once the status is finished
+	Healthy bool `json:"healthy"`
+	// the results of the checks once the status is finished
+	Results []*RepoHealthCheckResult `json:"results"`
+	// the API URL of the status of the health check
+	URL string `json:"url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at,omitempty"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
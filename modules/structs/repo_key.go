@@ -20,6 +20,10 @@ type DeployKey struct {
 	Created    time.Time   `json:"created_at"`
 	ReadOnly   bool        `json:"read_only"`
 	Repository *Repository `json:"repository,omitempty"`
+	// the key may post commit statuses through the API with its statuses token
+	Statuses bool `json:"statuses"`
+	// token to post commit statuses, only returned when the key is created
+	StatusesToken string `json:"statuses_token,omitempty"`
 }
 
 // CreateKeyOption options when creating a key
@@ -38,4 +42,9 @@ type CreateKeyOption struct {
 	//
 	// required: false
 	ReadOnly bool `json:"read_only"`
+	// Allow to post commit statuses through the API with the statuses token of the created key,
+	// only applies to deploy keys
+	//
+	// required: false
+	Statuses bool `json:"statuses"`
 }
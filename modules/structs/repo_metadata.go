@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// RepoMetadata represents an opaque value stored under a key for a repository
+type RepoMetadata struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	// entity tag of the value, to be sent as If-Match header for conditional updates
+	ETag      string `json:"etag"`
+	UpdatedBy *User  `json:"updated_by"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SetRepoMetadataOption options for setting the value of a repository metadata key
+type SetRepoMetadataOption struct {
+	Value string `json:"value"`
+}
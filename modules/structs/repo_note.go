@@ -9,3 +9,9 @@ type Note struct {
 	Message string  `json:"message"`
 	Commit  *Commit `json:"commit"`
 }
+
+// SetNoteOption options for setting the git note of a commit
+type SetNoteOption struct {
+	// required: true
+	Message string `json:"message" binding:"Required"`
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// RepoPermissionSource represents one of the reasons why a user has access to a repository
+type RepoPermissionSource struct {
+	// enum: owner,collaborator,team,org_owner,site_admin
+	Type string `json:"type"`
+	// enum: read,write,admin,owner
+	Permission string `json:"permission"`
+	// the permission to the wiki, only set for collaborators which may write to the wiki with a lower permission
+	// enum: write
+	WikiPermission string `json:"wiki_permission,omitempty"`
+	// the team granting the access, only set for team and org_owner sources
+	Team *Team `json:"team,omitempty"`
+}
+
+// RepoUserPermission represents the access of a user to a repository
+type RepoUserPermission struct {
+	User *User `json:"user"`
+	// the effective permission of the user
+	// enum: none,read,write,admin,owner
+	Permission string `json:"permission"`
+	// whether the user is restricted to the repositories they are given explicit access to
+	Restricted bool                    `json:"restricted"`
+	Sources    []*RepoPermissionSource `json:"sources"`
+}
+
+// RepoDeployKeyPermission represents the access of a deploy key to a repository
+type RepoDeployKeyPermission struct {
+	ID          int64  `json:"id"`
+	Title       string `json:"title"`
+	Fingerprint string `json:"fingerprint"`
+	// enum: read,write
+	Permission string `json:"permission"`
+}
+
+// RepoPermissions represents everyone with explicit access to a repository
+type RepoPermissions struct {
+	Users      []*RepoUserPermission      `json:"users"`
+	DeployKeys []*RepoDeployKeyPermission `json:"deploy_keys"`
+}
+
+// RepoCollaboratorPermission represents the effective permission of a user to a repository
+type RepoCollaboratorPermission struct {
+	User *User `json:"user"`
+	// the effective role of the user, the owners of a repository are admins
+	// enum: none,read,write,admin
+	Permission string `json:"permission"`
+	// the effective permission to each unit of the repository
+	Units map[string]string `json:"units"`
+	// whether the user is restricted to the repositories they are given explicit access to
+	Restricted bool `json:"restricted"`
+	// the sources of the access, empty if the user can only read a public repository
+	Sources []*RepoPermissionSource `json:"sources"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// PushRules represents the rules every push to a repository has to follow
+type PushRules struct {
+	// glob patterns of paths which must not be pushed, patterns without a `/` match the file name
+	ForbiddenPaths []string `json:"forbidden_paths"`
+	// maximum size in bytes of a pushed file, 0 means no limit
+	MaxFileSize int64 `json:"max_file_size"`
+	// maximum length of the path of a pushed file, 0 means no limit
+	MaxPathLength int `json:"max_path_length"`
+	// glob patterns of committer emails which must not be pushed
+	ForbiddenCommitterEmails []string `json:"forbidden_committer_emails"`
+	// reject pushes adding lines which look like secrets, e.g. access keys or private keys
+	ScanSecrets bool `json:"scan_secrets"`
+}
+
+// EditPushRulesOption options for replacing the push rules of a repository
+type EditPushRulesOption struct {
+	// glob patterns of paths which must not be pushed, patterns without a `/` match the file name
+	ForbiddenPaths []string `json:"forbidden_paths"`
+	// maximum size in bytes of a pushed file, 0 means no limit
+	MaxFileSize int64 `json:"max_file_size"`
+	// maximum length of the path of a pushed file, 0 means no limit
+	MaxPathLength int `json:"max_path_length"`
+	// glob patterns of committer emails which must not be pushed
+	ForbiddenCommitterEmails []string `json:"forbidden_committer_emails"`
+	// reject pushes adding lines which look like secrets, e.g. access keys or private keys
+	ScanSecrets bool `json:"scan_secrets"`
+}
@@ -12,6 +12,9 @@ type Tag struct {
 	Commit     *CommitMeta `json:"commit"`
 	ZipballURL string      `json:"zipball_url"`
 	TarballURL string      `json:"tarball_url"`
+	// the tagger and the signature verification are only given for annotated tags
+	Tagger       *CommitUser                `json:"tagger,omitempty"`
+	Verification *PayloadCommitVerification `json:"verification,omitempty"`
 }
 
 // AnnotatedTag represents an annotated tag
@@ -27,3 +27,16 @@ type RepoTopicOptions struct {
 	// list of topic names
 	Topics []string `json:"topics"`
 }
+
+// RenameTopicOption options for renaming a topic of all repositories
+type RenameTopicOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required"`
+}
+
+// MergeTopicOption options for merging a topic into another one
+type MergeTopicOption struct {
+	// name of the topic which the repositories of the merged topic are moved to
+	// required: true
+	Target string `json:"target" binding:"Required"`
+}
@@ -0,0 +1,30 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// RepoTrafficPeriod represents the traffic of a repository during a day or a week
+type RepoTrafficPeriod struct {
+	// swagger:strfmt date-time
+	Timestamp time.Time `json:"timestamp"`
+	Count     int64     `json:"count"`
+	// approximate number of distinct users or addresses
+	Uniques int64 `json:"uniques"`
+}
+
+// RepoCloneTraffic represents the clones and fetches of a repository over HTTP and SSH
+type RepoCloneTraffic struct {
+	Count   int64                `json:"count"`
+	Uniques int64                `json:"uniques"`
+	Clones  []*RepoTrafficPeriod `json:"clones"`
+}
+
+// RepoViewTraffic represents the views of the home page of a repository
+type RepoViewTraffic struct {
+	Count   int64                `json:"count"`
+	Uniques int64                `json:"uniques"`
+	Views   []*RepoTrafficPeriod `json:"views"`
+}
@@ -36,3 +36,14 @@ type GeneralAttachmentSettings struct {
 	MaxSize      int64  `json:"max_size"`
 	MaxFiles     int    `json:"max_files"`
 }
+
+// GeneralSSHSettings contains global SSH settings exposed by API
+type GeneralSSHSettings struct {
+	Disabled bool `json:"disabled"`
+	// whether the key types and sizes are checked when keys are added
+	MinimumKeySizeCheck bool `json:"minimum_key_size_check"`
+	// whether keys violating the policy are also refused when used to authenticate
+	MinimumKeySizeCheckOnAuth bool `json:"minimum_key_size_check_on_auth"`
+	// the allowed key types and their minimum size in bits
+	MinimumKeySizes map[string]int `json:"minimum_key_sizes"`
+}
@@ -0,0 +1,38 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import "time"
+
+// Statistics represents the aggregate counts of the instance
+type Statistics struct {
+	Users                 int64 `json:"users"`
+	Organizations         int64 `json:"organizations"`
+	PublicKeys            int64 `json:"public_keys"`
+	Repositories          int64 `json:"repositories"`
+	Watches               int64 `json:"watches"`
+	Stars                 int64 `json:"stars"`
+	Actions               int64 `json:"actions"`
+	Accesses              int64 `json:"accesses"`
+	Issues                int64 `json:"issues"`
+	IssuesOpen            int64 `json:"issues_open"`
+	IssuesClosed          int64 `json:"issues_closed"`
+	Comments              int64 `json:"comments"`
+	Follows               int64 `json:"follows"`
+	Mirrors               int64 `json:"mirrors"`
+	Releases              int64 `json:"releases"`
+	AuthenticationSources int64 `json:"authentication_sources"`
+	Webhooks              int64 `json:"webhooks"`
+	Milestones            int64 `json:"milestones"`
+	Labels                int64 `json:"labels"`
+	HookTasks             int64 `json:"hook_tasks"`
+	Teams                 int64 `json:"teams"`
+	Projects              int64 `json:"projects"`
+	ProjectBoards         int64 `json:"project_boards"`
+	Attachments           int64 `json:"attachments"`
+	// the time at which the statistics were counted
+	// swagger:strfmt date-time
+	AsOf time.Time `json:"as_of"`
+}
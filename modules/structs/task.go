@@ -9,7 +9,11 @@ type TaskType int
 
 // all kinds of task types
 const (
-	TaskTypeMigrateRepo TaskType = iota // migrate repository from external or local disk
+	TaskTypeMigrateRepo            TaskType = iota // migrate repository from external or local disk
+	TaskTypeFetchReleaseAttachment                 // fetch a release attachment from a URL
+	TaskTypeRepoHealthCheck                        // check the health of a repository
+	TaskTypeUserExport                             // export the data of a user
+	TaskTypeRepoDetachFork                         // detach a fork from its base repository
 )
 
 // Name returns the task type name
@@ -17,6 +21,14 @@ func (taskType TaskType) Name() string {
 	switch taskType {
 	case TaskTypeMigrateRepo:
 		return "Migrate Repository"
+	case TaskTypeFetchReleaseAttachment:
+		return "Fetch Release Attachment"
+	case TaskTypeRepoHealthCheck:
+		return "Repository Health Check"
+	case TaskTypeUserExport:
+		return "User Export"
+	case TaskTypeRepoDetachFork:
+		return "Repository Detach Fork"
 	}
 	return ""
 }
@@ -32,3 +44,20 @@ const (
 	TaskStatusFailed                     // 3 task is failed
 	TaskStatusFinished                   // 4 task is finished
 )
+
+// Name returns the task status name
+func (status TaskStatus) Name() string {
+	switch status {
+	case TaskStatusQueue:
+		return "queued"
+	case TaskStatusRunning:
+		return "running"
+	case TaskStatusStopped:
+		return "stopped"
+	case TaskStatusFailed:
+		return "failed"
+	case TaskStatusFinished:
+		return "finished"
+	}
+	return ""
+}
@@ -37,6 +37,8 @@ type User struct {
 	IsActive bool `json:"active"`
 	// Is user login prohibited
 	ProhibitLogin bool `json:"prohibit_login"`
+	// Is user a service account, exempt from the deactivation of inactive users
+	IsServiceAccount bool `json:"service_account"`
 	// the user's location
 	Location string `json:"location"`
 	// the user's website
@@ -50,6 +52,11 @@ type User struct {
 	Followers    int `json:"followers_count"`
 	Following    int `json:"following_count"`
 	StarredRepos int `json:"starred_repos_count"`
+
+	// whether the authenticated user follows the user, only set in lists of followers and followings
+	IsFollowing *bool `json:"is_following,omitempty"`
+	// whether the user follows the authenticated user, only set in lists of followers and followings
+	FollowsYou *bool `json:"follows_you,omitempty"`
 }
 
 // MarshalJSON implements the json.Marshaler interface for User, adding field(s) for backward compatibility
@@ -75,6 +82,13 @@ type UserSettings struct {
 	// Privacy
 	HideEmail    bool `json:"hide_email"`
 	HideActivity bool `json:"hide_activity"`
+	// when repositories are watched automatically, one of "default", "never",
+	// "contribution" (on push and pull request creation) or "participation" (also on comments)
+	AutoWatch string `json:"auto_watch"`
+	// sort type of issue and pull request lists if none is given, empty for the default of the repository
+	IssueSortType string `json:"issue_sort_type"`
+	// don't remind about the due dates of milestones with open issues assigned to the user
+	MuteMilestoneReminders bool `json:"mute_milestone_reminders"`
 }
 
 // UserSettingsOptions represents options to change user settings
@@ -90,4 +104,13 @@ type UserSettingsOptions struct {
 	// Privacy
 	HideEmail    *bool `json:"hide_email"`
 	HideActivity *bool `json:"hide_activity"`
+	// when repositories are watched automatically, one of "default", "never",
+	// "contribution" (on push and pull request creation) or "participation" (also on comments)
+	AutoWatch *string `json:"auto_watch"`
+	// sort type of issue and pull request lists if none is given, empty for the default of the repository
+	IssueSortType *string `json:"issue_sort_type"`
+	// don't remind about the due dates of milestones with open issues assigned to the user
+	MuteMilestoneReminders *bool `json:"mute_milestone_reminders"`
+	// remove all watches which were added automatically
+	RemoveAutoWatches bool `json:"remove_auto_watches"`
 }
@@ -16,6 +16,14 @@ type AccessToken struct {
 	Name           string `json:"name"`
 	Token          string `json:"sha1"`
 	TokenLastEight string `json:"token_last_eight"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	LastUsed *time.Time `json:"last_used_at"`
+	// only recorded if enabled by the administrator
+	LastUsedIP string `json:"last_used_ip,omitempty"`
+	// only set in admin reports
+	Owner *User `json:"owner,omitempty"`
 }
 
 // AccessTokenList represents a list of API access token.
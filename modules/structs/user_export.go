@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// UserExport represents an export of the data of a user
+type UserExport struct {
+	ID int64 `json:"id"`
+	// enum: queued,running,stopped,failed,finished
+	Status string `json:"status"`
+	// the reason of the failure if the status is failed
+	Message string `json:"message,omitempty"`
+	// size of the archive in bytes
+	Size int64 `json:"size"`
+	// whether the archive has been deleted after the retention period
+	Purged bool `json:"purged"`
+	// signed link to download the archive, only set while it can be downloaded
+	DownloadURL string `json:"download_url,omitempty"`
+	// swagger:strfmt date-time
+	DownloadURLExpires *time.Time `json:"download_url_expires_at,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Started *time.Time `json:"started_at,omitempty"`
+	// swagger:strfmt date-time
+	Finished *time.Time `json:"finished_at,omitempty"`
+}
+
+// UserExportActivity represents an activity of a user in the export of their data
+type UserExportActivity struct {
+	ID int64 `json:"id"`
+	// the type of the activity as stored by the server, e.g. 1 for a created repository
+	OpType int `json:"op_type"`
+	// full name of the repository of the activity
+	Repo string `json:"repo"`
+	// name of the branch or the tag of the activity
+	RefName string `json:"ref_name"`
+	Content string `json:"content"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
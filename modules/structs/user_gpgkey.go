@@ -27,6 +27,22 @@ type GPGKey struct {
 	Expires time.Time `json:"expires_at,omitempty"`
 }
 
+// GPGKeyStatus represents the expiry and the verification status of a GPG key
+type GPGKeyStatus struct {
+	ID    int64  `json:"id"`
+	KeyID string `json:"key_id"`
+	// the IDs of the key and its subkeys that can sign commits
+	SigningKeyIDs []string `json:"signing_key_ids"`
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at,omitempty"`
+	Expired bool       `json:"expired"`
+	// number of days until the key expires, absent if it does not expire or has expired
+	ExpiresInDays *int64 `json:"expires_in_days,omitempty"`
+	Verified      bool   `json:"verified"`
+	// the email addresses of the user whose signed commits can be verified with the key
+	VerifiableEmails []string `json:"verifiable_emails"`
+}
+
 // GPGKeyEmail an email attached to a GPGKey
 // swagger:model GPGKeyEmail
 type GPGKeyEmail struct {
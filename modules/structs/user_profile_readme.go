@@ -0,0 +1,18 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+// ProfileReadme the README shown on the profile of a user or an organization
+type ProfileReadme struct {
+	// name of the repository containing the README
+	Repository string `json:"repository"`
+	FileName   string `json:"file_name"`
+	// commit of the default branch the README was read from
+	CommitID string `json:"commit_id"`
+	// raw content of the README
+	Content string `json:"content"`
+	// rendered content of the README
+	HTML string `json:"html"`
+}
@@ -0,0 +1,39 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package structs
+
+import (
+	"time"
+)
+
+// SavedReply a canned comment of a user or an organization
+type SavedReply struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// markdown source of the reply, rendered when it is used
+	Body     string `json:"body"`
+	Position int    `json:"position"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateSavedReplyOption options when creating a saved reply
+type CreateSavedReplyOption struct {
+	// required: true
+	Title string `json:"title" binding:"Required;MaxSize(255)"`
+	// required: true
+	Body string `json:"body" binding:"Required"`
+	// position of the reply in the list, appended after all existing replies if not set
+	Position int `json:"position"`
+}
+
+// EditSavedReplyOption options when editing a saved reply
+type EditSavedReplyOption struct {
+	Title    *string `json:"title" binding:"MaxSize(255)"`
+	Body     *string `json:"body"`
+	Position *int    `json:"position"`
+}
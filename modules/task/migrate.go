@@ -39,6 +39,8 @@ func handleCreateError(owner *models.User, err error) error {
 }
 
 func runMigrateTask(t *models.Task) (err error) {
+	// the repository is kept on failure once it contains migrated data, so the migration can be resumed
+	var keepRepo bool
 	defer func() {
 		if e := recover(); e != nil {
 			err = fmt.Errorf("PANIC whilst trying to do migrate task: %v", e)
@@ -58,12 +60,14 @@ func runMigrateTask(t *models.Task) (err error) {
 		t.EndTime = timeutil.TimeStampNow()
 		t.Status = structs.TaskStatusFailed
 		t.Message = err.Error()
-		t.RepoID = 0
-		if err := t.UpdateCols("status", "errors", "repo_id", "end_time"); err != nil {
+		if !keepRepo {
+			t.RepoID = 0
+		}
+		if err := t.UpdateCols("status", "message", "repo_id", "end_time"); err != nil {
 			log.Error("Task UpdateCols failed: %v", err)
 		}
 
-		if t.Repo != nil {
+		if t.Repo != nil && !keepRepo {
 			if errDelete := models.DeleteRepository(t.Doer, t.OwnerID, t.Repo.ID); errDelete != nil {
 				log.Error("DeleteRepository: %v", errDelete)
 			}
@@ -74,10 +78,12 @@ func runMigrateTask(t *models.Task) (err error) {
 		return
 	}
 
-	// if repository is ready, then just finish the task
-	if t.Repo.Status == models.RepositoryReady {
+	// if repository is ready, then just finish the task unless it is synchronized with its original
+	if t.Repo.Status == models.RepositoryReady && t.LastSyncUnix.IsZero() {
 		return nil
 	}
+	// a repository which was ready or which the migration was started for before is resumed
+	keepRepo = t.Repo.Status == models.RepositoryReady || !t.StartTime.IsZero()
 
 	if err = t.LoadDoer(); err != nil {
 		return
@@ -93,6 +99,11 @@ func runMigrateTask(t *models.Task) (err error) {
 	}
 
 	opts.MigrateToRepoID = t.RepoID
+	if t.Repo.Status == models.RepositoryReady {
+		opts.SyncSince = t.LastSyncUnix.AsTime()
+	} else {
+		opts.Resume = keepRepo
+	}
 
 	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
 	defer cancel()
@@ -114,6 +125,17 @@ func runMigrateTask(t *models.Task) (err error) {
 		bs, _ := json.Marshal(message)
 		t.Message = string(bs)
 		_ = t.UpdateCols("message")
+	}, func(progress migration.MigrateProgress) {
+		if progress.IssueIndex > 0 {
+			t.MigratedIssueIndex = progress.IssueIndex
+		}
+		if progress.PullRequestIndex > 0 {
+			t.MigratedPullIndex = progress.PullRequestIndex
+		}
+		if len(progress.ReleaseTag) > 0 {
+			t.MigratedReleaseTag = progress.ReleaseTag
+		}
+		_ = t.UpdateCols("migrated_issue_index", "migrated_pull_index", "migrated_release_tag")
 	})
 	if err == nil {
 		log.Trace("Repository migrated [%d]: %s/%s", t.Repo.ID, t.Owner.Name, t.Repo.Name)
@@ -125,6 +147,10 @@ func runMigrateTask(t *models.Task) (err error) {
 		return
 	}
 
+	if migrations.IsErrMigrationIncomplete(err) {
+		keepRepo = true
+	}
+
 	// remoteAddr may contain credentials, so we sanitize it
 	err = util.NewStringURLSanitizedError(err, opts.CloneAddr, true)
 	if strings.Contains(err.Error(), "Authentication failed") ||
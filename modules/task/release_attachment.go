@@ -0,0 +1,327 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"syscall"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/upload"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/google/uuid"
+)
+
+// fetchProgressInterval is the minimum interval between two updates of the progress of a fetch task
+const fetchProgressInterval = time.Second
+
+// ErrReleaseAttachmentNameUnknown is returned if no name is given and the URL doesn't end with a file name
+var ErrReleaseAttachmentNameUnknown = errors.New("the name of the attachment can't be derived from the URL")
+
+// errFetchThroughProxy is returned if a release attachment would be fetched through a proxy while local
+// networks are not allowed, as the addresses the proxy connects to can't be checked
+var errFetchThroughProxy = errors.New("release attachments can't be fetched through a proxy unless local networks are allowed for migrations")
+
+// ErrFetchSizeExceeded represents a release attachment which is larger than MAX_FETCH_SIZE
+type ErrFetchSizeExceeded struct {
+	MaxSize int64
+}
+
+// IsErrFetchSizeExceeded checks if an error is a ErrFetchSizeExceeded.
+func IsErrFetchSizeExceeded(err error) bool {
+	_, ok := err.(ErrFetchSizeExceeded)
+	return ok
+}
+
+func (err ErrFetchSizeExceeded) Error() string {
+	return fmt.Sprintf("the attachment is larger than %d MB", err.MaxSize/1024/1024)
+}
+
+// CheckReleaseAttachmentURL checks if the server may fetch a release attachment from the URL.
+// Only HTTP(S) URLs passing the allowed and blocked domains of migrations are allowed.
+func CheckReleaseAttachmentURL(sourceURL string, doer *models.User) error {
+	u, err := url.Parse(sourceURL)
+	if err != nil {
+		return &models.ErrInvalidCloneAddr{IsURLError: true}
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return &models.ErrInvalidCloneAddr{Host: u.Host, IsProtocolInvalid: true, IsPermissionDenied: true, IsURLError: true}
+	}
+	return migrations.IsMigrateURLAllowed(sourceURL, doer)
+}
+
+// FetchReleaseAttachment adds a task fetching a release attachment from the URL.
+// If name is empty, the last element of the path of the URL is used.
+func FetchReleaseAttachment(doer *models.User, release *models.Release, sourceURL, name string) (*models.Task, error) {
+	task, err := CreateFetchReleaseAttachmentTask(doer, release, sourceURL, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return task, taskQueue.Push(task)
+}
+
+// CreateFetchReleaseAttachmentTask creates a task fetching a release attachment from the URL
+func CreateFetchReleaseAttachmentTask(doer *models.User, release *models.Release, sourceURL, name string) (*models.Task, error) {
+	if err := CheckReleaseAttachmentURL(sourceURL, doer); err != nil {
+		return nil, err
+	}
+	if name == "" {
+		u, _ := url.Parse(sourceURL)
+		name = path.Base(u.Path)
+		if name == "." || name == "/" {
+			return nil, ErrReleaseAttachmentNameUnknown
+		}
+	}
+
+	if release.Repo == nil {
+		repo, err := models.GetRepositoryByID(release.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		release.Repo = repo
+	}
+
+	sourceURLEncrypted, err := secret.EncryptSecret(setting.SecretKey, sourceURL)
+	if err != nil {
+		return nil, err
+	}
+	task := &models.Task{
+		DoerID:  doer.ID,
+		OwnerID: release.Repo.OwnerID,
+		RepoID:  release.RepoID,
+		Type:    structs.TaskTypeFetchReleaseAttachment,
+		Status:  structs.TaskStatusQueue,
+	}
+	if err := task.SetReleaseAttachmentFetch(&models.ReleaseAttachmentFetch{
+		ReleaseID:          release.ID,
+		Name:               name,
+		SourceURL:          util.NewStringURLSanitizer(sourceURL, true).Replace(sourceURL),
+		SourceURLEncrypted: sourceURLEncrypted,
+		TotalSize:          -1,
+	}); err != nil {
+		return nil, err
+	}
+	if err := models.CreateTask(task); err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+func runFetchReleaseAttachmentTask(t *models.Task) (err error) {
+	var fetch *models.ReleaseAttachmentFetch
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("PANIC whilst trying to fetch release attachment: %v", e)
+			log.Critical("PANIC during runFetchReleaseAttachmentTask[%d] by DoerID[%d] to RepoID[%d]: %v\nStacktrace: %v", t.ID, t.DoerID, t.RepoID, e, log.Stack(2))
+		}
+
+		t.EndTime = timeutil.TimeStampNow()
+		t.Status = structs.TaskStatusFinished
+		cols := []string{"status", "end_time"}
+		if err != nil {
+			t.Status = structs.TaskStatusFailed
+			t.Message = err.Error()
+			cols = append(cols, "message")
+		}
+		if fetch != nil {
+			fetch.SourceURLEncrypted = ""
+			if err := t.SetReleaseAttachmentFetch(fetch); err != nil {
+				log.Error("SetReleaseAttachmentFetch: %v", err)
+			} else {
+				cols = append(cols, "payload_content")
+			}
+		}
+		if err := t.UpdateCols(cols...); err != nil {
+			log.Error("Task UpdateCols failed: %v", err)
+		}
+	}()
+
+	if err = t.LoadDoer(); err != nil {
+		return
+	}
+	if fetch, err = t.ReleaseAttachmentFetch(); err != nil {
+		return
+	}
+	var release *models.Release
+	if release, err = models.GetReleaseByID(fetch.ReleaseID); err != nil {
+		return
+	}
+	var sourceURL string
+	if sourceURL, err = secret.DecryptSecret(setting.SecretKey, fetch.SourceURLEncrypted); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
+	defer cancel()
+	pm := process.GetManager()
+	pid := pm.Add(fmt.Sprintf("FetchReleaseAttachmentTask: %s", fetch.SourceURL), cancel)
+	defer pm.Remove(pid)
+
+	t.StartTime = timeutil.TimeStampNow()
+	t.Status = structs.TaskStatusRunning
+	if err = t.UpdateCols("start_time", "status"); err != nil {
+		return
+	}
+
+	var attach *models.Attachment
+	attach, err = fetchReleaseAttachment(ctx, t, release, fetch, sourceURL)
+	if err != nil {
+		// the URL may contain credentials, so we sanitize the error
+		err = util.NewStringURLSanitizedError(err, sourceURL, true)
+		return
+	}
+	fetch.AttachmentID = attach.ID
+	log.Trace("Release attachment fetched [%d]: %s", attach.ID, fetch.SourceURL)
+	return nil
+}
+
+// newFetchClient returns a client for fetching release attachments. Every address it connects to is
+// checked when connecting, as a host name can resolve to another address than it did when the URL was checked.
+func newFetchClient(doer *models.User) *http.Client {
+	systemProxy := proxy.Proxy()
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy: func(req *http.Request) (*url.URL, error) {
+				proxyURL, err := systemProxy(req)
+				if err != nil || proxyURL == nil || setting.Migrations.AllowLocalNetworks {
+					return proxyURL, err
+				}
+				return nil, errFetchThroughProxy
+			},
+			DialContext: (&net.Dialer{
+				Timeout: 30 * time.Second,
+				Control: func(network, address string, c syscall.RawConn) error {
+					host, _, err := net.SplitHostPort(address)
+					if err != nil {
+						return err
+					}
+					if ip := net.ParseIP(host); ip == nil || !migrations.IsIPAllowed(ip) {
+						return &models.ErrInvalidCloneAddr{Host: host, PrivateNet: host, IsPermissionDenied: true}
+					}
+					return nil
+				},
+			}).DialContext,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return errors.New("stopped after 10 redirects")
+			}
+			return CheckReleaseAttachmentURL(req.URL.String(), doer)
+		},
+	}
+}
+
+// fetchReleaseAttachment streams the response to the URL into the attachment storage and creates the attachment.
+// The stored file is removed again if the attachment can't be created.
+func fetchReleaseAttachment(ctx context.Context, t *models.Task, release *models.Release, fetch *models.ReleaseAttachmentFetch, sourceURL string) (*models.Attachment, error) {
+	// The URL is checked again, what it resolves to might have changed since the task was queued
+	if err := CheckReleaseAttachmentURL(sourceURL, t.Doer); err != nil {
+		return nil, err
+	}
+
+	client := newFetchClient(t.Doer)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, sourceURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the server responded with %s", resp.Status)
+	}
+
+	maxSize := setting.Repository.Release.MaxFetchSize * 1024 * 1024
+	if resp.ContentLength > maxSize {
+		return nil, ErrFetchSizeExceeded{MaxSize: maxSize}
+	}
+	fetch.ContentType = resp.Header.Get("Content-Type")
+	fetch.TotalSize = resp.ContentLength
+
+	body := bufio.NewReader(resp.Body)
+	buf, err := body.Peek(1024)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if err := upload.Verify(buf, fetch.Name, setting.Repository.Release.AllowedTypes); err != nil {
+		return nil, err
+	}
+
+	attach := &models.Attachment{
+		UUID:       uuid.New().String(),
+		RepoID:     release.RepoID,
+		ReleaseID:  release.ID,
+		UploaderID: t.DoerID,
+		Name:       fetch.Name,
+	}
+	reader := &fetchProgressReader{
+		reader:  body,
+		task:    t,
+		fetch:   fetch,
+		maxSize: maxSize,
+	}
+	attach.Size, err = storage.Attachments.Save(attach.RelativePath(), reader, -1)
+	if err == nil {
+		err = db.Insert(db.DefaultContext, attach)
+	}
+	if err != nil {
+		if errDelete := storage.Attachments.Delete(attach.RelativePath()); errDelete != nil {
+			log.Error("Unable to remove the partially fetched release attachment %s: %v", attach.RelativePath(), errDelete)
+		}
+		return nil, err
+	}
+	return attach, nil
+}
+
+// fetchProgressReader counts the bytes fetched into the payload of the task, saves it now and then
+// and fails once more than maxSize bytes are read
+type fetchProgressReader struct {
+	reader     io.Reader
+	task       *models.Task
+	fetch      *models.ReleaseAttachmentFetch
+	maxSize    int64
+	lastUpdate time.Time
+}
+
+func (r *fetchProgressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.fetch.FetchedSize += int64(n)
+	if r.fetch.FetchedSize > r.maxSize {
+		return n, ErrFetchSizeExceeded{MaxSize: r.maxSize}
+	}
+	if time.Since(r.lastUpdate) >= fetchProgressInterval {
+		r.lastUpdate = time.Now()
+		if err := r.task.SetReleaseAttachmentFetch(r.fetch); err == nil {
+			if err := r.task.UpdateCols("payload_content"); err != nil {
+				log.Error("Unable to update the progress of task %d: %v", r.task.ID, err)
+			}
+		}
+	}
+	return n, err
+}
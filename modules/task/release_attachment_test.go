@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchClientRejectsLocalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("attachment"))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NoError(t, err)
+	// the host name is only resolved to the loopback address when connecting
+	attachmentURL := "http://localhost:" + serverURL.Port() + "/attachment.zip"
+
+	defer func(allow bool) {
+		setting.Migrations.AllowLocalNetworks = allow
+	}(setting.Migrations.AllowLocalNetworks)
+
+	setting.Migrations.AllowLocalNetworks = false
+	_, err = newFetchClient(nil).Get(attachmentURL)
+	var addrErr *models.ErrInvalidCloneAddr
+	assert.True(t, errors.As(err, &addrErr), "unexpected error: %v", err)
+
+	setting.Migrations.AllowLocalNetworks = true
+	resp, err := newFetchClient(nil).Get(attachmentURL)
+	assert.NoError(t, err)
+	if err == nil {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/process"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// DetachFork adds a task detaching a fork from its base repository. If the fork shares the object storage of its
+// base repository the task is queued since copying the objects takes long for large repositories, otherwise it is
+// run at once. A queued or running task detaching the fork is returned instead of adding another one.
+func DetachFork(doer *models.User, repo *models.Repository) (*models.Task, error) {
+	task, err := models.GetPendingRepoDetachForkTask(repo.ID)
+	if err == nil {
+		return task, nil
+	} else if !models.IsErrTaskDoesNotExist(err) {
+		return nil, err
+	}
+
+	detach := &models.RepoDetachFork{
+		FormerBaseID: repo.ForkID,
+		Stage:        models.RepoDetachForkStageQueued,
+	}
+	if err := repo.GetBaseRepo(); err != nil {
+		if !models.IsErrRepoNotExist(err) {
+			return nil, err
+		}
+	} else if repo.BaseRepo != nil {
+		detach.FormerBase = repo.BaseRepo.FullName()
+	}
+
+	task = &models.Task{
+		DoerID:  doer.ID,
+		OwnerID: repo.OwnerID,
+		RepoID:  repo.ID,
+		Type:    structs.TaskTypeRepoDetachFork,
+		Status:  structs.TaskStatusQueue,
+	}
+	if err := task.SetRepoDetachFork(detach); err != nil {
+		return nil, err
+	}
+	if err := models.CreateTask(task); err != nil {
+		return nil, err
+	}
+
+	hasAlternates, err := repo_module.HasAlternates(repo)
+	if err != nil {
+		return nil, err
+	}
+	if hasAlternates {
+		return task, taskQueue.Push(task)
+	}
+	// the failures are recorded by the task
+	_ = runRepoDetachForkTask(task)
+	return task, nil
+}
+
+func runRepoDetachForkTask(t *models.Task) (err error) {
+	detach, err := t.RepoDetachFork()
+	if err != nil {
+		return err
+	}
+	setStage := func(stage string) error {
+		detach.Stage = stage
+		if err := t.SetRepoDetachFork(detach); err != nil {
+			return err
+		}
+		return t.UpdateCols("payload_content")
+	}
+
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("PANIC whilst trying to detach a fork: %v", e)
+			log.Critical("PANIC during runRepoDetachForkTask[%d] by DoerID[%d] to RepoID[%d]: %v\nStacktrace: %v", t.ID, t.DoerID, t.RepoID, e, log.Stack(2))
+		}
+
+		t.EndTime = timeutil.TimeStampNow()
+		t.Status = structs.TaskStatusFinished
+		cols := []string{"status", "end_time"}
+		if err != nil {
+			t.Status = structs.TaskStatusFailed
+			t.Message = err.Error()
+			cols = append(cols, "message")
+		}
+		if err := t.UpdateCols(cols...); err != nil {
+			log.Error("Task UpdateCols failed: %v", err)
+		}
+	}()
+
+	if err = t.LoadRepo(); err != nil {
+		return
+	}
+	if err = t.LoadDoer(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
+	defer cancel()
+	pm := process.GetManager()
+	pid := pm.Add(fmt.Sprintf("RepoDetachForkTask: %s", t.Repo.FullName()), cancel)
+	defer pm.Remove(pid)
+
+	t.StartTime = timeutil.TimeStampNow()
+	t.Status = structs.TaskStatusRunning
+	if err = t.UpdateCols("start_time", "status"); err != nil {
+		return
+	}
+
+	// the objects are copied first, a fork whose conversion fails can be detached again
+	if err = setStage(models.RepoDetachForkStageRepacking); err != nil {
+		return
+	}
+	if err = repo_module.MaterializeAlternates(ctx, t.Repo); err != nil {
+		return
+	}
+
+	if err = setStage(models.RepoDetachForkStageConverting); err != nil {
+		return
+	}
+	if err = repo_module.ConvertForkToNormalRepository(t.Repo); err != nil {
+		return
+	}
+	if err = setStage(models.RepoDetachForkStageFinished); err != nil {
+		return
+	}
+
+	t.Repo.IsFork = false
+	t.Repo.ForkID = 0
+	notification.NotifyDetachFork(t.Doer, t.Repo, detach.FormerBase)
+	log.Trace("Repository detached from its base [%d]: %s former base: %s", t.ID, t.Repo.FullName(), detach.FormerBase)
+	return nil
+}
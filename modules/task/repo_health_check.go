@@ -0,0 +1,93 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// CheckRepositoryHealth adds a task checking the health of a repository.
+// A queued or running health check of the repository is returned instead of adding another one.
+func CheckRepositoryHealth(doer *models.User, repo *models.Repository) (*models.Task, error) {
+	task, err := models.GetPendingRepoHealthCheckTask(repo.ID)
+	if err == nil {
+		return task, nil
+	} else if !models.IsErrTaskDoesNotExist(err) {
+		return nil, err
+	}
+
+	task = &models.Task{
+		DoerID:  doer.ID,
+		OwnerID: repo.OwnerID,
+		RepoID:  repo.ID,
+		Type:    structs.TaskTypeRepoHealthCheck,
+		Status:  structs.TaskStatusQueue,
+	}
+	if err := models.CreateTask(task); err != nil {
+		return nil, err
+	}
+	return task, taskQueue.Push(task)
+}
+
+func runRepoHealthCheckTask(t *models.Task) (err error) {
+	check := &models.RepoHealthCheck{}
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("PANIC whilst trying to check the health of a repository: %v", e)
+			log.Critical("PANIC during runRepoHealthCheckTask[%d] by DoerID[%d] to RepoID[%d]: %v\nStacktrace: %v", t.ID, t.DoerID, t.RepoID, e, log.Stack(2))
+		}
+
+		t.EndTime = timeutil.TimeStampNow()
+		t.Status = structs.TaskStatusFinished
+		cols := []string{"status", "end_time", "payload_content"}
+		if err != nil {
+			t.Status = structs.TaskStatusFailed
+			t.Message = err.Error()
+			cols = append(cols, "message")
+		}
+		if err := t.SetRepoHealthCheck(check); err != nil {
+			log.Error("SetRepoHealthCheck: %v", err)
+		}
+		if err := t.UpdateCols(cols...); err != nil {
+			log.Error("Task UpdateCols failed: %v", err)
+		}
+	}()
+
+	if err = t.LoadRepo(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
+	defer cancel()
+	pm := process.GetManager()
+	pid := pm.Add(fmt.Sprintf("RepoHealthCheckTask: %s", t.Repo.FullName()), cancel)
+	defer pm.Remove(pid)
+
+	t.StartTime = timeutil.TimeStampNow()
+	t.Status = structs.TaskStatusRunning
+	if err = t.UpdateCols("start_time", "status"); err != nil {
+		return
+	}
+
+	if check.Results, err = repo_module.CheckRepositoryHealth(ctx, t.Repo, setting.Repository.HealthCheckTimeout); err != nil {
+		return
+	}
+	check.Healthy = true
+	for _, result := range check.Results {
+		check.Healthy = check.Healthy && result.OK
+	}
+	log.Trace("Repository health check finished [%d]: %s healthy: %t", t.ID, t.Repo.FullName(), check.Healthy)
+	return nil
+}
@@ -5,6 +5,7 @@
 package task
 
 import (
+	"errors"
 	"fmt"
 
 	"code.gitea.io/gitea/models"
@@ -29,6 +30,14 @@ func Run(t *models.Task) error {
 	switch t.Type {
 	case structs.TaskTypeMigrateRepo:
 		return runMigrateTask(t)
+	case structs.TaskTypeFetchReleaseAttachment:
+		return runFetchReleaseAttachmentTask(t)
+	case structs.TaskTypeRepoHealthCheck:
+		return runRepoHealthCheckTask(t)
+	case structs.TaskTypeUserExport:
+		return runUserExportTask(t)
+	case structs.TaskTypeRepoDetachFork:
+		return runRepoDetachForkTask(t)
 	default:
 		return fmt.Errorf("Unknown task type: %d", t.Type)
 	}
@@ -66,25 +75,32 @@ func MigrateRepository(doer, u *models.User, opts base.MigrateOptions) error {
 	return taskQueue.Push(task)
 }
 
-// CreateMigrateTask creates a migrate task
-func CreateMigrateTask(doer, u *models.User, opts base.MigrateOptions) (*models.Task, error) {
-	// encrypt credentials for persistence
-	var err error
+// encryptCredentials encrypts the credentials of opts for persistence
+func encryptCredentials(opts *base.MigrateOptions) (err error) {
 	opts.CloneAddrEncrypted, err = secret.EncryptSecret(setting.SecretKey, opts.CloneAddr)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	opts.CloneAddr = util.NewStringURLSanitizer(opts.CloneAddr, true).Replace(opts.CloneAddr)
 	opts.AuthPasswordEncrypted, err = secret.EncryptSecret(setting.SecretKey, opts.AuthPassword)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	opts.AuthPassword = ""
 	opts.AuthTokenEncrypted, err = secret.EncryptSecret(setting.SecretKey, opts.AuthToken)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	opts.AuthToken = ""
+	return nil
+}
+
+// CreateMigrateTask creates a migrate task
+func CreateMigrateTask(doer, u *models.User, opts base.MigrateOptions) (*models.Task, error) {
+	// encrypt credentials for persistence
+	if err := encryptCredentials(&opts); err != nil {
+		return nil, err
+	}
 	bs, err := json.Marshal(&opts)
 	if err != nil {
 		return nil, err
@@ -110,6 +126,7 @@ func CreateMigrateTask(doer, u *models.User, opts base.MigrateOptions) (*models.
 		IsPrivate:      opts.Private,
 		IsMirror:       opts.Mirror,
 		Status:         models.RepositoryBeingMigrated,
+		CreatedFrom:    models.RepoCreatedFromMigrated,
 	})
 	if err != nil {
 		task.EndTime = timeutil.TimeStampNow()
@@ -128,3 +145,78 @@ func CreateMigrateTask(doer, u *models.User, opts base.MigrateOptions) (*models.
 
 	return &task, nil
 }
+
+// ErrMigrationInProgress represents a migration which is queued or running already
+var ErrMigrationInProgress = errors.New("the migration of the repository is in progress")
+
+// SyncMigration queues the migration of a repository from another gitea instance again. A failed migration
+// is resumed, a finished one is synchronized with the issues, pull requests, comments and releases updated
+// since it was last run. The credentials are used if given, else the ones of a failed migration are kept.
+func SyncMigration(doer *models.User, repo *models.Repository, authUsername, authPassword, authToken string) (*models.Task, error) {
+	var opts *base.MigrateOptions
+	task, err := models.GetMigratingTask(repo.ID)
+	if err == nil {
+		if task.Status == structs.TaskStatusQueue || task.Status == structs.TaskStatusRunning {
+			return nil, ErrMigrationInProgress
+		}
+		if opts, err = task.MigrateConfig(); err != nil {
+			return nil, err
+		}
+	} else if models.IsErrTaskDoesNotExist(err) {
+		// repositories migrated through the API have no task
+		task = &models.Task{
+			OwnerID: repo.OwnerID,
+			RepoID:  repo.ID,
+			Type:    structs.TaskTypeMigrateRepo,
+		}
+		if repo.Status == models.RepositoryReady {
+			task.LastSyncUnix = repo.CreatedUnix
+		} else {
+			task.StartTime = repo.CreatedUnix
+		}
+		opts = &base.MigrateOptions{
+			CloneAddr:      repo.OriginalURL,
+			OriginalURL:    repo.OriginalURL,
+			GitServiceType: repo.OriginalServiceType,
+			RepoName:       repo.Name,
+			Private:        repo.IsPrivate,
+			Description:    repo.Description,
+			Issues:         repo.UnitEnabled(models.UnitTypeIssues),
+			Milestones:     repo.UnitEnabled(models.UnitTypeIssues),
+			Labels:         repo.UnitEnabled(models.UnitTypeIssues),
+			Comments:       repo.UnitEnabled(models.UnitTypeIssues),
+			PullRequests:   repo.UnitEnabled(models.UnitTypePullRequests),
+			Releases:       repo.UnitEnabled(models.UnitTypeReleases),
+		}
+	} else {
+		return nil, err
+	}
+
+	if len(authUsername) > 0 || len(authPassword) > 0 || len(authToken) > 0 {
+		opts.AuthUsername = authUsername
+		opts.AuthPassword = authPassword
+		opts.AuthToken = authToken
+	}
+	if err := encryptCredentials(opts); err != nil {
+		return nil, err
+	}
+	bs, err := json.Marshal(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	task.DoerID = doer.ID
+	task.Status = structs.TaskStatusQueue
+	task.Message = ""
+	task.PayloadContent = string(bs)
+	if task.ID == 0 {
+		err = models.CreateTask(task)
+	} else {
+		err = task.UpdateCols("doer_id", "status", "message", "payload_content")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return task, taskQueue.Push(task)
+}
@@ -0,0 +1,368 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"archive/zip"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// UserExportInterval is the minimum time between two exports of the data of a user
+const UserExportInterval = 24 * time.Hour
+
+// ErrUserExportTooFrequent represents an export of the data of a user requested within UserExportInterval
+// of the previous one
+type ErrUserExportTooFrequent struct {
+	Next time.Time
+}
+
+// IsErrUserExportTooFrequent checks if an error is a ErrUserExportTooFrequent.
+func IsErrUserExportTooFrequent(err error) bool {
+	_, ok := err.(ErrUserExportTooFrequent)
+	return ok
+}
+
+func (err ErrUserExportTooFrequent) Error() string {
+	return fmt.Sprintf("the data can be exported once per day, the next export is possible at %s", err.Next.Format(time.RFC3339))
+}
+
+// ExportUserData adds a task exporting the data of a user to a zip archive in the user export storage.
+// Only one export per user is allowed within UserExportInterval, failed exports don't count.
+func ExportUserData(u *models.User) (*models.Task, error) {
+	latest, err := models.GetLatestUserExportTask(u.ID, timeutil.TimeStamp(time.Now().Add(-UserExportInterval).Unix()))
+	if err == nil {
+		return nil, ErrUserExportTooFrequent{Next: latest.Created.AsTime().Add(UserExportInterval)}
+	} else if !models.IsErrTaskDoesNotExist(err) {
+		return nil, err
+	}
+
+	task := &models.Task{
+		DoerID:  u.ID,
+		OwnerID: u.ID,
+		Type:    api.TaskTypeUserExport,
+		Status:  api.TaskStatusQueue,
+	}
+	if err := models.CreateTask(task); err != nil {
+		return nil, err
+	}
+	return task, taskQueue.Push(task)
+}
+
+// userExportSignature returns the signature of the download link of an exported archive which expires at the given time
+func userExportSignature(t *models.Task, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "user-export:%d:%d:%d", t.OwnerID, t.ID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// UserExportDownloadURL returns a signed link to download the archive of an export which expires after
+// setting.UserExport.DownloadLinkExpiry
+func UserExportDownloadURL(t *models.Task) (string, time.Time) {
+	expires := time.Now().Add(setting.UserExport.DownloadLinkExpiry).Truncate(time.Second)
+	query := url.Values{
+		"expires":   {fmt.Sprint(expires.Unix())},
+		"signature": {userExportSignature(t, expires.Unix())},
+	}
+	return fmt.Sprintf("%sapi/v1/user/export/%d/download?%s", setting.AppURL, t.ID, query.Encode()), expires
+}
+
+// VerifyUserExportDownload checks the expiry and the signature of a download link of an exported archive
+func VerifyUserExportDownload(t *models.Task, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(userExportSignature(t, expires)))
+}
+
+func runUserExportTask(t *models.Task) (err error) {
+	export := &models.UserExport{}
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("PANIC whilst trying to export the data of a user: %v", e)
+			log.Critical("PANIC during runUserExportTask[%d] by DoerID[%d]: %v\nStacktrace: %v", t.ID, t.DoerID, e, log.Stack(2))
+		}
+
+		t.EndTime = timeutil.TimeStampNow()
+		t.Status = api.TaskStatusFinished
+		cols := []string{"status", "end_time", "payload_content"}
+		if err != nil {
+			t.Status = api.TaskStatusFailed
+			t.Message = err.Error()
+			cols = append(cols, "message")
+		}
+		if err := t.SetUserExport(export); err != nil {
+			log.Error("SetUserExport: %v", err)
+		}
+		if err := t.UpdateCols(cols...); err != nil {
+			log.Error("Task UpdateCols failed: %v", err)
+		}
+	}()
+
+	if err = t.LoadOwner(); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(graceful.GetManager().ShutdownContext())
+	defer cancel()
+	pm := process.GetManager()
+	pid := pm.Add(fmt.Sprintf("UserExportTask: %s", t.Owner.Name), cancel)
+	defer pm.Remove(pid)
+
+	t.StartTime = timeutil.TimeStampNow()
+	t.Status = api.TaskStatusRunning
+	if err = t.UpdateCols("start_time", "status"); err != nil {
+		return
+	}
+
+	p := fmt.Sprintf("%d/%d.zip", t.OwnerID, t.ID)
+	if err = storage.SaveFrom(storage.UserExports, p, func(w io.Writer) error {
+		return writeUserExport(ctx, w, t.Owner)
+	}); err != nil {
+		return fmt.Errorf("unable to store the archive: %v", err)
+	}
+	export.Path = p
+
+	fi, err := storage.UserExports.Stat(p)
+	if err != nil {
+		return
+	}
+	export.Size = fi.Size()
+	log.Trace("User export finished [%d]: %s %d bytes", t.ID, t.Owner.Name, export.Size)
+	return nil
+}
+
+// writeUserExport writes a zip archive with a JSON file for each kind of data of a user.
+// Only data of repositories the user can still access is included.
+func writeUserExport(ctx context.Context, w io.Writer, u *models.User) error {
+	zw := zip.NewWriter(w)
+	writeFile := func(name string, data interface{}) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		bs, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		fw, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		_, err = fw.Write(bs)
+		return err
+	}
+
+	if err := writeFile("profile.json", convert.ToUser(u, u)); err != nil {
+		return err
+	}
+
+	emails, err := models.GetEmailAddresses(u.ID)
+	if err != nil {
+		return err
+	}
+	apiEmails := make([]*api.Email, 0, len(emails))
+	for _, email := range emails {
+		apiEmails = append(apiEmails, convert.ToEmail(email))
+	}
+	if err := writeFile("emails.json", apiEmails); err != nil {
+		return err
+	}
+
+	keys, err := models.ListPublicKeys(u.ID, db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	apiKeys := make([]*api.PublicKey, 0, len(keys))
+	for _, key := range keys {
+		apiKeys = append(apiKeys, convert.ToPublicKey(setting.AppURL+"api/v1/user/keys/", key))
+	}
+	if err := writeFile("ssh_keys.json", apiKeys); err != nil {
+		return err
+	}
+
+	gpgKeys, err := models.ListGPGKeys(u.ID, db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	apiGPGKeys := make([]*api.GPGKey, 0, len(gpgKeys))
+	for _, key := range gpgKeys {
+		apiGPGKeys = append(apiGPGKeys, convert.ToGPGKey(key))
+	}
+	if err := writeFile("gpg_keys.json", apiGPGKeys); err != nil {
+		return err
+	}
+
+	apiRepos := make([]*api.Repository, 0, u.NumRepos)
+	for page := 1; ; page++ {
+		repos, count, err := models.GetUserRepositories(&models.SearchRepoOptions{
+			ListOptions: db.ListOptions{Page: page, PageSize: setting.API.MaxResponseItems},
+			Actor:       u,
+			Private:     true,
+			OrderBy:     models.SearchOrderByID,
+		})
+		if err != nil {
+			return err
+		}
+		for _, repo := range repos {
+			apiRepos = append(apiRepos, convert.ToRepo(repo, models.AccessModeOwner))
+		}
+		if len(repos) == 0 || int64(len(apiRepos)) >= count {
+			break
+		}
+	}
+	if err := writeFile("repositories.json", apiRepos); err != nil {
+		return err
+	}
+
+	issues, err := models.FindUserExportIssues(u)
+	if err != nil {
+		return err
+	}
+	apiIssues := make([]*api.Issue, 0, len(issues))
+	apiPulls := make([]*api.PullRequest, 0, 10)
+	for _, issue := range issues {
+		if !issue.IsPull {
+			apiIssues = append(apiIssues, convert.ToAPIIssue(issue))
+			continue
+		}
+		if err := issue.LoadPullRequest(); err != nil {
+			return err
+		}
+		if apiPull := convert.ToAPIPullRequest(issue.PullRequest, u); apiPull != nil {
+			apiPulls = append(apiPulls, apiPull)
+		}
+	}
+	if err := writeFile("issues.json", apiIssues); err != nil {
+		return err
+	}
+	if err := writeFile("pull_requests.json", apiPulls); err != nil {
+		return err
+	}
+
+	comments, err := models.FindUserExportComments(u)
+	if err != nil {
+		return err
+	}
+	apiComments := make([]*api.Comment, 0, len(comments))
+	for _, comment := range comments {
+		apiComments = append(apiComments, convert.ToComment(comment))
+	}
+	if err := writeFile("comments.json", apiComments); err != nil {
+		return err
+	}
+
+	starred, err := models.GetStarredRepos(u.ID, u, db.ListOptions{})
+	if err != nil {
+		return err
+	}
+	apiStarred := make([]*api.Repository, 0, len(starred))
+	for _, repo := range starred {
+		perm, err := models.GetUserRepoPermission(repo, u)
+		if err != nil {
+			return err
+		}
+		apiStarred = append(apiStarred, convert.ToRepo(repo, perm.AccessMode))
+	}
+	if err := writeFile("starred.json", apiStarred); err != nil {
+		return err
+	}
+
+	for _, follows := range []struct {
+		name string
+		list func(db.ListOptions) ([]*models.User, error)
+	}{
+		{"followers.json", u.GetFollowers},
+		{"following.json", u.GetFollowing},
+	} {
+		users, err := follows.list(db.ListOptions{})
+		if err != nil {
+			return err
+		}
+		apiUsers := make([]*api.User, 0, len(users))
+		for _, user := range users {
+			apiUsers = append(apiUsers, convert.ToUser(user, u))
+		}
+		if err := writeFile(follows.name, apiUsers); err != nil {
+			return err
+		}
+	}
+
+	actions, err := models.FindUserExportActions(u)
+	if err != nil {
+		return err
+	}
+	apiActions := make([]*api.UserExportActivity, 0, len(actions))
+	for _, action := range actions {
+		apiActions = append(apiActions, &api.UserExportActivity{
+			ID:      action.ID,
+			OpType:  int(action.OpType),
+			Repo:    action.GetRepoPath(),
+			RefName: action.RefName,
+			Content: action.Content,
+			Created: action.CreatedUnix.AsTime(),
+		})
+	}
+	if err := writeFile("activity.json", apiActions); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// DeleteOldUserExports deletes the archives of the exports of user data which finished more than olderThan ago.
+// The tasks are kept as the history of the exports.
+func DeleteOldUserExports(ctx context.Context, olderThan time.Duration) error {
+	tasks, err := models.FindFinishedUserExportTasks(timeutil.TimeStamp(time.Now().Add(-olderThan).Unix()))
+	if err != nil {
+		return err
+	}
+	for _, t := range tasks {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before deleting the archive of user export %d", t.ID)
+		default:
+		}
+
+		export, err := t.UserExport()
+		if err != nil {
+			return err
+		}
+		if export.Purged || export.Path == "" {
+			continue
+		}
+		if err := storage.UserExports.Delete(export.Path); err != nil {
+			log.Error("Unable to delete the archive of user export %d: %v", t.ID, err)
+			continue
+		}
+		export.Purged = true
+		if err := t.SetUserExport(export); err != nil {
+			return err
+		}
+		if err := t.UpdateCols("payload_content"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
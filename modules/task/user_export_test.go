@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package task
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyUserExportDownload(t *testing.T) {
+	task := &models.Task{ID: 3, OwnerID: 2}
+	link, expires := UserExportDownloadURL(task)
+	u, err := url.Parse(link)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(u.Path, "api/v1/user/export/3/download"), u.Path)
+	assert.Equal(t, strconv.FormatInt(expires.Unix(), 10), u.Query().Get("expires"))
+
+	signature := u.Query().Get("signature")
+	assert.True(t, VerifyUserExportDownload(task, expires.Unix(), signature))
+	// the expiry and the export are part of the signature
+	assert.False(t, VerifyUserExportDownload(task, expires.Unix()+1, signature))
+	assert.False(t, VerifyUserExportDownload(&models.Task{ID: 4, OwnerID: 2}, expires.Unix(), signature))
+	assert.False(t, VerifyUserExportDownload(task, expires.Unix(), ""))
+
+	expired := time.Now().Add(-time.Minute).Unix()
+	assert.False(t, VerifyUserExportDownload(task, expired, userExportSignature(task, expired)))
+}
@@ -779,9 +779,9 @@ type Actioner interface {
 // ActionIcon accepts an action operation type and returns an icon class name.
 func ActionIcon(opType models.ActionType) string {
 	switch opType {
-	case models.ActionCreateRepo, models.ActionTransferRepo, models.ActionRenameRepo:
+	case models.ActionCreateRepo, models.ActionTransferRepo, models.ActionRenameRepo, models.ActionDetachFork:
 		return "repo"
-	case models.ActionCommitRepo, models.ActionPushTag, models.ActionDeleteTag, models.ActionDeleteBranch:
+	case models.ActionCommitRepo, models.ActionPushTag, models.ActionDeleteTag, models.ActionDeleteBranch, models.ActionAuthorCommitRepo:
 		return "git-commit"
 	case models.ActionCreateIssue:
 		return "issue-opened"
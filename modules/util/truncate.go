@@ -4,7 +4,11 @@
 
 package util
 
-import "unicode/utf8"
+import (
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
 
 // SplitStringAtByteN splits a string at byte n accounting for rune boundaries. (Combining characters are not accounted for.)
 func SplitStringAtByteN(input string, n int) (left, right string) {
@@ -33,3 +37,23 @@ func SplitStringAtByteN(input string, n int) (left, right string) {
 	right = "…" + input[end:]
 	return
 }
+
+// TruncateGraphemes returns the longest beginning of the input of at most n runes which does not split a
+// grapheme cluster, like an emoji sequence or a letter followed by combining marks.
+func TruncateGraphemes(input string, n int) string {
+	if utf8.RuneCountInString(input) <= n {
+		return input
+	}
+
+	end, count := 0, 0
+	gr := uniseg.NewGraphemes(input)
+	for gr.Next() {
+		runes := gr.Runes()
+		if count+len(runes) > n {
+			break
+		}
+		count += len(runes)
+		_, end = gr.Positions()
+	}
+	return input[:end]
+}
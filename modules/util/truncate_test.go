@@ -0,0 +1,36 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package util
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncateGraphemes(t *testing.T) {
+	assert.Equal(t, "", TruncateGraphemes("", 5))
+	assert.Equal(t, "short", TruncateGraphemes("short", 5))
+	assert.Equal(t, "trunc", TruncateGraphemes("truncated", 5))
+
+	cjk := strings.Repeat("漢字", 150)
+	assert.Equal(t, strings.Repeat("漢字", 127)+"漢", TruncateGraphemes(cjk, 255))
+
+	// a letter with a combining accent is not split
+	assert.Equal(t, "abc", TruncateGraphemes("abce\u0301", 4))
+	// neither are emoji sequences joined by zero width joiners or flags
+	family := "\U0001F468\u200d\U0001F469\u200d\U0001F467"
+	assert.Equal(t, "ab", TruncateGraphemes("ab"+family, 6))
+	assert.Equal(t, "ab"+family, TruncateGraphemes("ab"+family+"c", 7))
+	assert.Equal(t, "a", TruncateGraphemes("a\U0001F1E9\U0001F1EA", 2))
+
+	// directionality marks are kept with the text they belong to
+	rtl := "\u202bשלום\u202c"
+	truncated := TruncateGraphemes(rtl+" world", 6)
+	assert.Equal(t, rtl, truncated)
+	assert.True(t, utf8.ValidString(truncated))
+}
@@ -57,6 +57,7 @@ func AddBindingRules() {
 	addValidURLBindingRule()
 	addValidSiteURLBindingRule()
 	addGlobPatternRule()
+	addGlobPatternListRule()
 	addRegexPatternRule()
 	addGlobOrRegexPatternRule()
 }
@@ -143,6 +144,31 @@ func globPatternValidator(errs binding.Errors, name string, val interface{}) (bo
 	return true, errs
 }
 
+func addGlobPatternListRule() {
+	binding.AddRule(&binding.Rule{
+		IsMatch: func(rule string) bool {
+			return rule == "GlobPatternList"
+		},
+		IsValid: func(errs binding.Errors, name string, val interface{}) (bool, binding.Errors) {
+			// a list of globs, or a string of globs separated by spaces, which may start with "!" to exclude
+			var patterns []string
+			switch v := val.(type) {
+			case []string:
+				patterns = v
+			default:
+				patterns = strings.Fields(fmt.Sprintf("%v", val))
+			}
+			for _, pattern := range patterns {
+				if _, err := glob.Compile(strings.TrimPrefix(pattern, "!")); err != nil {
+					errs.Add([]string{name}, ErrGlobPattern, err.Error())
+					return false, errs
+				}
+			}
+			return true, errs
+		},
+	})
+}
+
 func addRegexPatternRule() {
 	binding.AddRule(&binding.Rule{
 		IsMatch: func(rule string) bool {
@@ -26,10 +26,12 @@ type (
 	}
 
 	TestForm struct {
-		BranchName   string `form:"BranchName" binding:"GitRefName"`
-		URL          string `form:"ValidUrl" binding:"ValidUrl"`
-		GlobPattern  string `form:"GlobPattern" binding:"GlobPattern"`
-		RegexPattern string `form:"RegexPattern" binding:"RegexPattern"`
+		BranchName   string   `form:"BranchName" binding:"GitRefName"`
+		URL          string   `form:"ValidUrl" binding:"ValidUrl"`
+		GlobPattern  string   `form:"GlobPattern" binding:"GlobPattern"`
+		GlobPatterns []string `form:"GlobPatterns" binding:"GlobPatternList"`
+		GlobList     string   `form:"GlobList" binding:"GlobPatternList"`
+		RegexPattern string   `form:"RegexPattern" binding:"RegexPattern"`
 	}
 )
 
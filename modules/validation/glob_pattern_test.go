@@ -49,6 +49,40 @@ var globValidationTestCases = []validationTestCase{
 			},
 		},
 	},
+	{
+		description: "Valid glob list",
+		data: TestForm{
+			GlobPatterns: []string{"releases/*", "!releases/*-rc"},
+			GlobList:     "releases/*  !releases/*-rc",
+		},
+		expectedErrors: binding.Errors{},
+	},
+	{
+		description: "Invalid glob in list",
+		data: TestForm{
+			GlobPatterns: []string{"master", "![a-"},
+		},
+		expectedErrors: binding.Errors{
+			binding.Error{
+				FieldNames:     []string{"GlobPatterns"},
+				Classification: ErrGlobPattern,
+				Message:        getGlobPatternErrorString("[a-"),
+			},
+		},
+	},
+	{
+		description: "Invalid glob in space separated list",
+		data: TestForm{
+			GlobList: "master [a-",
+		},
+		expectedErrors: binding.Errors{
+			binding.Error{
+				FieldNames:     []string{"GlobList"},
+				Classification: ErrGlobPattern,
+				Message:        getGlobPatternErrorString("[a-"),
+			},
+		},
+	},
 }
 
 func Test_GlobPatternValidation(t *testing.T) {
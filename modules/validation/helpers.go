@@ -15,7 +15,10 @@ import (
 
 var loopbackIPBlocks []*net.IPNet
 
-var externalTrackerRegex = regexp.MustCompile(`({?)(?:user|repo|index)+?(}?)`)
+var (
+	externalTrackerRegex            = regexp.MustCompile(`({?)(?:user|repo|index)+?(}?)`)
+	externalTrackerPlaceholderRegex = regexp.MustCompile(`{([^{}]*)}`)
+)
 
 func init() {
 	for _, cidr := range []string{
@@ -111,5 +114,18 @@ func IsValidExternalTrackerURLFormat(uri string) bool {
 		}
 	}
 
+	// at least one placeholder is required and unknown ones like {idx} are typos too
+	placeholders := externalTrackerPlaceholderRegex.FindAllStringSubmatch(uri, -1)
+	if len(placeholders) == 0 {
+		return false
+	}
+	for _, placeholder := range placeholders {
+		switch placeholder[1] {
+		case "user", "repo", "index":
+		default:
+			return false
+		}
+	}
+
 	return true
 }
@@ -147,6 +147,21 @@ func Test_IsValidExternalTrackerURLFormat(t *testing.T) {
 			url:         "https://github.com/user/repo/issues/{index}",
 			valid:       true,
 		},
+		{
+			description: "External tracker URL without placeholders",
+			url:         "https://github.com/user/repo/issues/",
+			valid:       false,
+		},
+		{
+			description: "External tracker URL with unknown placeholder",
+			url:         "https://github.com/{user}/{repo}/issues/{idx}",
+			valid:       false,
+		},
+		{
+			description: "Relative external tracker URL",
+			url:         "/{user}/{repo}/issues/{index}",
+			valid:       false,
+		},
 	}
 
 	for _, testCase := range cases {
@@ -85,3 +85,53 @@ func PostCronTask(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// DeleteInactiveUsers api for deleting the inactive users or previewing the deletion
+func DeleteInactiveUsers(ctx *context.APIContext) {
+	// swagger:operation POST /admin/cron/delete-inactive-users admin adminDeleteInactiveUsers
+	// ---
+	// summary: Delete the inactive users older than configured for the delete_inactive_accounts task
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: dry_run
+	//   in: query
+	//   description: only report the users which would be deleted
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeleteInactiveUsersReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	dryRun := ctx.FormBool("dry_run")
+	result, err := cron.DeleteInactiveUsers(ctx.Req.Context(), dryRun)
+	if err != nil {
+		if err == cron.ErrTaskAlreadyRunning {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteInactiveUsers", err)
+		}
+		return
+	}
+	if !dryRun {
+		log.Trace("Admin(%s) deleted %d inactive users, skipped %d", ctx.User.Name, result.Deleted, result.Skipped)
+	}
+
+	report := &structs.DeleteInactiveUsersReport{
+		DryRun:  dryRun,
+		Deleted: result.Deleted,
+		Skipped: result.Skipped,
+		Users:   make([]*structs.InactiveUser, len(result.Users)),
+	}
+	for i, deletion := range result.Users {
+		report.Users[i] = &structs.InactiveUser{
+			Name:       deletion.User.Name,
+			Email:      deletion.User.Email,
+			Created:    deletion.User.CreatedUnix.AsTime(),
+			SkipReason: deletion.SkipReason,
+		}
+	}
+	ctx.JSON(http.StatusOK, report)
+}
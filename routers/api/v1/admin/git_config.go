@@ -0,0 +1,67 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/cron"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// GetGitConfigTemplate returns the git config applied to new repositories
+func GetGitConfigTemplate(ctx *context.APIContext) {
+	// swagger:operation GET /admin/git-config admin adminGetGitConfigTemplate
+	// ---
+	// summary: Get the git config applied to new repositories and by the sync of the git config of all repositories
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GitConfigTemplate"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	unset := setting.Git.RepoConfigUnset
+	if unset == nil {
+		unset = []string{}
+	}
+	ctx.JSON(http.StatusOK, &api.GitConfigTemplate{
+		Config: setting.Git.RepoConfig,
+		Unset:  unset,
+	})
+}
+
+// SyncGitConfig applies the git config template to all repositories in the background
+func SyncGitConfig(ctx *context.APIContext) {
+	// swagger:operation POST /admin/git-config/sync admin adminSyncGitConfig
+	// ---
+	// summary: Apply the git config template to all repositories
+	// description: The sync runs in the background as the sync_repo_git_config cron task, the result is reported in the system notices.
+	// produces:
+	// - application/json
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	if err := cron.SyncRepositoryGitConfig(ctx.User); err != nil {
+		if err == cron.ErrTaskAlreadyRunning {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SyncRepositoryGitConfig", err)
+		}
+		return
+	}
+	log.Trace("Admin(%s) started the sync of the git config of all repositories", ctx.User.Name)
+
+	ctx.Status(http.StatusAccepted)
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// RotateHookSecret replaces the secret of a system or default hook
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /admin/hooks/{id}/rotate_secret admin adminRotateHookSecret
+	// ---
+	// summary: Replace the secret of a system or default hook with a new random one
+	// description: The new secret is only returned in this response.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecret"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	hook, err := models.GetSystemOrDefaultWebhook(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSystemOrDefaultWebhook", err)
+		}
+		return
+	}
+	utils.RotateHookSecret(ctx, web.GetForm(ctx).(*api.RotateHookSecretOption), hook)
+}
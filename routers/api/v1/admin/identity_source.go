@@ -0,0 +1,559 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/login"
+	pam_module "code.gitea.io/gitea/modules/auth/pam"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	auth_service "code.gitea.io/gitea/services/auth"
+	"code.gitea.io/gitea/services/auth/source/ldap"
+	"code.gitea.io/gitea/services/auth/source/oauth2"
+	"code.gitea.io/gitea/services/auth/source/pam"
+	"code.gitea.io/gitea/services/auth/source/smtp"
+)
+
+// identitySourceConfigs holds the configurations of an option, at most the one matching the type may be set
+type identitySourceConfigs struct {
+	LDAP   *api.IdentitySourceLDAPConfig
+	SMTP   *api.IdentitySourceSMTPConfig
+	PAM    *api.IdentitySourcePAMConfig
+	OAuth2 *api.IdentitySourceOAuth2Config
+}
+
+// isValidPort returns whether port is a valid TCP port
+func isValidPort(port int) bool {
+	return port > 0 && port < 65536
+}
+
+// parseIdentitySourceConfig validates the configuration for the type like the forms of the admin panel and converts it.
+// It returns nil without configuration. Empty secrets are taken from the current configuration, which may be nil.
+func parseIdentitySourceConfig(name string, typ login.Type, configs identitySourceConfigs, current login.Config) (login.Config, error) {
+	given := 0
+	for _, set := range []bool{configs.LDAP != nil, configs.SMTP != nil, configs.PAM != nil, configs.OAuth2 != nil} {
+		if set {
+			given++
+		}
+	}
+	if given > 1 {
+		return nil, errors.New("only the configuration matching the type can be given")
+	}
+
+	switch typ {
+	case login.LDAP, login.DLDAP:
+		if given == 1 && configs.LDAP == nil {
+			return nil, errors.New("only the ldap configuration can be given")
+		}
+		if configs.LDAP == nil {
+			return nil, nil
+		}
+		return parseLDAPConfig(name, typ, configs.LDAP, current)
+	case login.SMTP:
+		if given == 1 && configs.SMTP == nil {
+			return nil, errors.New("only the smtp configuration can be given")
+		}
+		if configs.SMTP == nil {
+			return nil, nil
+		}
+		return parseSMTPConfig(configs.SMTP)
+	case login.PAM:
+		if given == 1 && configs.PAM == nil {
+			return nil, errors.New("only the pam configuration can be given")
+		}
+		if configs.PAM == nil {
+			return nil, nil
+		}
+		if !pam_module.Supported {
+			return nil, errors.New("PAM is not supported by this build")
+		}
+		if configs.PAM.ServiceName == "" {
+			return nil, errors.New("pam.service_name is required")
+		}
+		return &pam.Source{
+			ServiceName:    configs.PAM.ServiceName,
+			EmailDomain:    configs.PAM.EmailDomain,
+			SkipLocalTwoFA: configs.PAM.SkipLocalTwoFA,
+		}, nil
+	case login.OAuth2:
+		if given == 1 && configs.OAuth2 == nil {
+			return nil, errors.New("only the oauth2 configuration can be given")
+		}
+		if configs.OAuth2 == nil {
+			return nil, nil
+		}
+		return parseOAuth2Config(configs.OAuth2, current)
+	}
+	if given > 0 {
+		return nil, fmt.Errorf("the configuration of %s sources can't be changed through the API", typ)
+	}
+	return nil, nil
+}
+
+func parseLDAPConfig(name string, typ login.Type, opts *api.IdentitySourceLDAPConfig, current login.Config) (*ldap.Source, error) {
+	securityProtocol := ldap.SecurityProtocolUnencrypted
+	if opts.SecurityProtocol != "" {
+		var ok bool
+		if securityProtocol, ok = convert.LDAPSecurityProtocols[opts.SecurityProtocol]; !ok {
+			return nil, fmt.Errorf("ldap.security_protocol %q is invalid", opts.SecurityProtocol)
+		}
+	}
+	switch {
+	case opts.Host == "":
+		return nil, errors.New("ldap.host is required")
+	case !isValidPort(opts.Port):
+		return nil, errors.New("ldap.port must be between 1 and 65535")
+	case typ == login.LDAP && opts.UserBase == "":
+		return nil, errors.New("ldap.user_base is required")
+	case typ == login.DLDAP && opts.UserDN == "":
+		return nil, errors.New("ldap.user_dn is required")
+	case opts.Filter == "":
+		return nil, errors.New("ldap.filter is required")
+	case opts.AttributeMail == "":
+		return nil, errors.New("ldap.attribute_mail is required")
+	case opts.SearchPageSize < 0:
+		return nil, errors.New("ldap.search_page_size must not be negative")
+	}
+
+	bindPassword := opts.BindPassword
+	if cfg, ok := current.(*ldap.Source); ok && bindPassword == "" {
+		bindPassword = cfg.BindPassword
+	}
+	var groupTeamMap string
+	if len(opts.GroupTeamMap) > 0 {
+		bs, err := json.Marshal(opts.GroupTeamMap)
+		if err != nil {
+			return nil, err
+		}
+		groupTeamMap = string(bs)
+	}
+	return &ldap.Source{
+		Name:                  name,
+		Host:                  opts.Host,
+		Port:                  opts.Port,
+		SecurityProtocol:      securityProtocol,
+		SkipVerify:            opts.SkipVerify,
+		BindDN:                opts.BindDN,
+		UserDN:                opts.UserDN,
+		BindPassword:          bindPassword,
+		UserBase:              opts.UserBase,
+		AttributeUsername:     opts.AttributeUsername,
+		AttributeName:         opts.AttributeName,
+		AttributeSurname:      opts.AttributeSurname,
+		AttributeMail:         opts.AttributeMail,
+		AttributesInBind:      opts.AttributesInBind,
+		AttributeSSHPublicKey: opts.AttributeSSHPublicKey,
+		AttributeAvatar:       opts.AttributeAvatar,
+		SearchPageSize:        uint32(opts.SearchPageSize),
+		Filter:                opts.Filter,
+		GroupsEnabled:         opts.GroupsEnabled,
+		GroupDN:               opts.GroupDN,
+		GroupFilter:           opts.GroupFilter,
+		GroupMemberUID:        opts.GroupMemberUID,
+		UserUID:               opts.UserUID,
+		AdminFilter:           opts.AdminFilter,
+		RestrictedFilter:      opts.RestrictedFilter,
+		AllowDeactivateAll:    opts.AllowDeactivateAll,
+		Enabled:               true,
+		SkipLocalTwoFA:        opts.SkipLocalTwoFA,
+		GroupTeamMap:          groupTeamMap,
+		GroupTeamMapRemoval:   opts.GroupTeamMapRemoval,
+	}, nil
+}
+
+func parseSMTPConfig(opts *api.IdentitySourceSMTPConfig) (*smtp.Source, error) {
+	auth := opts.Auth
+	if auth == "" {
+		auth = smtp.PlainAuthentication
+	}
+	switch {
+	case !util.IsStringInSlice(auth, smtp.Authenticators):
+		return nil, fmt.Errorf("smtp.auth %q is invalid", opts.Auth)
+	case opts.Host == "":
+		return nil, errors.New("smtp.host is required")
+	case !isValidPort(opts.Port):
+		return nil, errors.New("smtp.port must be between 1 and 65535")
+	}
+	return &smtp.Source{
+		Auth:           auth,
+		Host:           opts.Host,
+		Port:           opts.Port,
+		AllowedDomains: opts.AllowedDomains,
+		ForceSMTPS:     opts.ForceSMTPS,
+		SkipVerify:     opts.SkipVerify,
+		HeloHostname:   opts.HeloHostname,
+		DisableHelo:    opts.DisableHelo,
+		SkipLocalTwoFA: opts.SkipLocalTwoFA,
+	}, nil
+}
+
+func parseOAuth2Config(opts *api.IdentitySourceOAuth2Config, current login.Config) (*oauth2.Source, error) {
+	var provider oauth2.Provider
+	for _, p := range oauth2.GetOAuth2Providers() {
+		if p.Name() == opts.Provider {
+			provider = p
+			break
+		}
+	}
+	clientSecret := opts.ClientSecret
+	if cfg, ok := current.(*oauth2.Source); ok && clientSecret == "" {
+		clientSecret = cfg.ClientSecret
+	}
+	switch {
+	case provider == nil:
+		return nil, fmt.Errorf("oauth2.provider %q is unknown", opts.Provider)
+	case opts.ClientID == "":
+		return nil, errors.New("oauth2.client_id is required")
+	case clientSecret == "":
+		return nil, errors.New("oauth2.client_secret is required")
+	case opts.Provider == "openidConnect" && opts.OpenIDConnectAutoDiscoveryURL == "":
+		return nil, errors.New("oauth2.open_id_connect_auto_discovery_url is required")
+	case opts.CustomURLMapping == nil && provider.CustomURLSettings().Required():
+		return nil, fmt.Errorf("oauth2.custom_url_mapping is required by %s", opts.Provider)
+	}
+
+	var customURLMapping *oauth2.CustomURLMapping
+	if opts.CustomURLMapping != nil {
+		customURLMapping = &oauth2.CustomURLMapping{
+			AuthURL:    opts.CustomURLMapping.AuthURL,
+			TokenURL:   opts.CustomURLMapping.TokenURL,
+			ProfileURL: opts.CustomURLMapping.ProfileURL,
+			EmailURL:   opts.CustomURLMapping.EmailURL,
+			Tenant:     opts.CustomURLMapping.Tenant,
+		}
+	}
+	return &oauth2.Source{
+		Provider:                      opts.Provider,
+		ClientID:                      opts.ClientID,
+		ClientSecret:                  clientSecret,
+		OpenIDConnectAutoDiscoveryURL: opts.OpenIDConnectAutoDiscoveryURL,
+		CustomURLMapping:              customURLMapping,
+		IconURL:                       opts.IconURL,
+		SkipLocalTwoFA:                opts.SkipLocalTwoFA,
+	}, nil
+}
+
+// getIdentitySourceByParams returns the identity source of the id in the path, or writes an error
+func getIdentitySourceByParams(ctx *context.APIContext) *login.Source {
+	source, err := login.GetSourceByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if login.IsErrSourceNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSourceByID", err)
+		}
+		return nil
+	}
+	return source
+}
+
+// ListIdentitySources api for listing the identity sources
+func ListIdentitySources(ctx *context.APIContext) {
+	// swagger:operation GET /admin/identity-sources admin adminListIdentitySources
+	// ---
+	// summary: List the identity sources
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IdentitySourceList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	sources, err := login.Sources()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Sources", err)
+		return
+	}
+	count := len(sources)
+
+	listOpts := utils.GetListOptions(ctx)
+	sources = util.PaginateSlice(sources, listOpts.Page, listOpts.PageSize).([]*login.Source)
+
+	res := make([]*api.IdentitySource, len(sources))
+	for i, source := range sources {
+		res[i] = convert.ToIdentitySource(source)
+	}
+
+	ctx.SetTotalCountHeader(int64(count))
+	ctx.JSON(http.StatusOK, res)
+}
+
+// GetIdentitySource api for getting an identity source
+func GetIdentitySource(ctx *context.APIContext) {
+	// swagger:operation GET /admin/identity-sources/{id} admin adminGetIdentitySource
+	// ---
+	// summary: Get an identity source
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the identity source
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IdentitySource"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	source := getIdentitySourceByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIdentitySource(source))
+}
+
+// CreateIdentitySource api for creating an identity source
+func CreateIdentitySource(ctx *context.APIContext) {
+	// swagger:operation POST /admin/identity-sources admin adminCreateIdentitySource
+	// ---
+	// summary: Create an identity source
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIdentitySourceOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IdentitySource"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIdentitySourceOption)
+
+	typ := convert.IdentitySourceTypes[form.Type]
+	config, err := parseIdentitySourceConfig(form.Name, typ, identitySourceConfigs{
+		LDAP:   form.LDAP,
+		SMTP:   form.SMTP,
+		PAM:    form.PAM,
+		OAuth2: form.OAuth2,
+	}, nil)
+	if err == nil && config == nil {
+		err = fmt.Errorf("the %s configuration is required", form.Type)
+	}
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	source := &login.Source{
+		Type:          typ,
+		Name:          form.Name,
+		IsActive:      true,
+		IsSyncEnabled: form.IsSyncEnabled,
+		Cfg:           config,
+	}
+	if form.IsActive != nil {
+		source.IsActive = *form.IsActive
+	}
+	if err := login.CreateSource(source); err != nil {
+		if login.IsErrSourceAlreadyExist(err) || models.IsErrOpenIDConnectInitialize(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateSource", err)
+		}
+		return
+	}
+	log.Trace("Authentication created by admin(%s): %s", ctx.User.Name, source.Name)
+
+	ctx.JSON(http.StatusCreated, convert.ToIdentitySource(source))
+}
+
+// EditIdentitySource api for editing an identity source
+func EditIdentitySource(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/identity-sources/{id} admin adminEditIdentitySource
+	// ---
+	// summary: Edit an identity source, the changes take effect immediately
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the identity source
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditIdentitySourceOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IdentitySource"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIdentitySourceOption)
+	source := getIdentitySourceByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Name != nil && *form.Name != source.Name {
+		if *form.Name == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", errors.New("name must not be empty"))
+			return
+		}
+		has, err := login.IsSourceNameUsed(*form.Name)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsSourceNameUsed", err)
+			return
+		} else if has {
+			ctx.Error(http.StatusUnprocessableEntity, "", login.ErrSourceAlreadyExist{Name: *form.Name})
+			return
+		}
+		source.Name = *form.Name
+		if cfg, ok := source.Cfg.(*ldap.Source); ok {
+			cfg.Name = source.Name
+		}
+	}
+	config, err := parseIdentitySourceConfig(source.Name, source.Type, identitySourceConfigs{
+		LDAP:   form.LDAP,
+		SMTP:   form.SMTP,
+		PAM:    form.PAM,
+		OAuth2: form.OAuth2,
+	}, source.Cfg)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	if config != nil {
+		source.Cfg = config
+	}
+
+	if form.IsActive != nil {
+		source.IsActive = *form.IsActive
+	}
+	if form.IsSyncEnabled != nil {
+		source.IsSyncEnabled = *form.IsSyncEnabled && source.IsLDAP()
+	}
+
+	if err := login.UpdateSource(source); err != nil {
+		if models.IsErrOpenIDConnectInitialize(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateSource", err)
+		}
+		return
+	}
+	log.Trace("Authentication changed by admin(%s): %d", ctx.User.Name, source.ID)
+
+	ctx.JSON(http.StatusOK, convert.ToIdentitySource(source))
+}
+
+// DeleteIdentitySource api for deleting an identity source
+func DeleteIdentitySource(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/identity-sources/{id} admin adminDeleteIdentitySource
+	// ---
+	// summary: Delete an identity source
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the identity source
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: force
+	//   in: query
+	//   description: delete the source even if it is used, its users are moved to the source given by migrate_to
+	//     and the accounts linked through it are unlinked
+	//   type: boolean
+	// - name: migrate_to
+	//   in: query
+	//   description: id of the identity source the users are moved to, required with force
+	//   type: integer
+	//   format: int64
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     description: The identity source is still used, the message lists the number of users and linked accounts.
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	source := getIdentitySourceByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !ctx.FormBool("force") {
+		if err := auth_service.DeleteLoginSource(source); err != nil {
+			if login.IsErrSourceInUse(err) {
+				ctx.Error(http.StatusConflict, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "DeleteLoginSource", err)
+			}
+			return
+		}
+		log.Trace("Authentication deleted by admin(%s): %d", ctx.User.Name, source.ID)
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	targetID := ctx.FormInt64("migrate_to")
+	if targetID <= 0 || targetID == source.ID {
+		ctx.Error(http.StatusUnprocessableEntity, "", errors.New("migrate_to must be the id of another identity source"))
+		return
+	}
+	target, err := login.GetSourceByID(targetID)
+	if err != nil {
+		if login.IsErrSourceNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSourceByID", err)
+		}
+		return
+	}
+	if err := auth_service.ForceDeleteLoginSource(source, target); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ForceDeleteLoginSource", err)
+		return
+	}
+	log.Trace("Authentication deleted by admin(%s): %d, users moved to %d", ctx.User.Name, source.ID, target.ID)
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// noticeType parses the type of notices to filter by, an empty name matches all types
+func noticeType(ctx *context.APIContext, name string) (models.NoticeType, bool) {
+	if name == "" {
+		return 0, true
+	}
+	tp := models.NoticeTypeFromString(name)
+	if tp == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "unknown notice type "+name)
+		return 0, false
+	}
+	return tp, true
+}
+
+// ListNotices lists the system notices
+func ListNotices(ctx *context.APIContext) {
+	// swagger:operation GET /admin/notices admin adminListNotices
+	// ---
+	// summary: List the system notices, the most recently seen first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: list only the notices of this type
+	//   type: string
+	//   enum: [repository, task, security]
+	// - name: q
+	//   in: query
+	//   description: list only the notices whose descriptions contain this text
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: list only the notices last seen since this time
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: list only the notices last seen before this time
+	//   type: string
+	//   format: date-time
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/NoticeList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	tp, ok := noticeType(ctx, ctx.FormTrim("type"))
+	if !ok {
+		return
+	}
+	before, since, err := utils.GetQueryBeforeSince(ctx)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+
+	notices, count, err := models.FindNotices(&models.FindNoticesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Type:        tp,
+		Keyword:     ctx.FormTrim("q"),
+		SinceUnix:   timeutil.TimeStamp(since),
+		BeforeUnix:  timeutil.TimeStamp(before),
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindNotices", err)
+		return
+	}
+
+	apiNotices := make([]*api.Notice, len(notices))
+	for i, n := range notices {
+		apiNotices[i] = convert.ToNotice(n)
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiNotices)
+}
+
+// DeleteNotices deletes the system notices with the given IDs or matching the given filters
+func DeleteNotices(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/notices admin adminDeleteNotices
+	// ---
+	// summary: Delete the system notices with the given IDs or matching the given filters
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/DeleteNoticesOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.DeleteNoticesOption)
+	tp, ok := noticeType(ctx, form.Type)
+	if !ok {
+		return
+	}
+	opts := &models.FindNoticesOptions{
+		IDs:     form.IDs,
+		Type:    tp,
+		Keyword: form.Query,
+	}
+	if form.Before != nil {
+		opts.BeforeUnix = timeutil.TimeStamp(form.Before.Unix())
+	}
+	if len(opts.IDs) == 0 && opts.Type == 0 && opts.Keyword == "" && opts.BeforeUnix <= 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the IDs or a filter of the notices to delete are required")
+		return
+	}
+
+	count, err := models.DeleteNoticesByOptions(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteNoticesByOptions", err)
+		return
+	}
+	log.Trace("System notices deleted by admin %s: %d", ctx.User.Name, count)
+
+	ctx.Status(http.StatusNoContent)
+}
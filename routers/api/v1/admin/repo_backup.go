@@ -0,0 +1,225 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListRepoBackups api for listing the runs of the scheduled repository backups
+func ListRepoBackups(ctx *context.APIContext) {
+	// swagger:operation GET /admin/repo-backups admin adminListRepoBackups
+	// ---
+	// summary: List repository backups, the newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: only list the backups with this status
+	//   type: string
+	//   enum: [pending, running, succeeded, failed]
+	// - name: repo_id
+	//   in: query
+	//   description: only list the backups of this repository
+	//   type: integer
+	//   format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoBackupList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	opts := models.FindRepoBackupsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.FormInt64("repo_id"),
+		Status:      -1,
+	}
+	if statusName := ctx.FormTrim("status"); len(statusName) > 0 {
+		status, ok := models.ToRepoBackupStatus(statusName)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid status: %s", statusName))
+			return
+		}
+		opts.Status = status
+	}
+
+	backups, count, err := models.FindRepoBackups(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoBackups", err)
+		return
+	}
+	if err := backups.LoadRepos(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadRepos", err)
+		return
+	}
+
+	apiBackups := make([]*api.RepoBackup, 0, len(backups))
+	for _, b := range backups {
+		apiBackups = append(apiBackups, convert.ToRepoBackup(b))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiBackups)
+}
+
+// CreateRepoBackup api for backing up a repository outside of the schedule
+func CreateRepoBackup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/repo-backups admin adminCreateRepoBackup
+	// ---
+	// summary: Back up a repository now
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoBackupOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/RepoBackup"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.CreateRepoBackupOption)
+
+	repo, err := models.GetRepositoryByOwnerAndName(form.Owner, form.Repo)
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+
+	backup, err := repo_service.QueueRepoBackup(repo)
+	if err != nil {
+		if models.IsErrRepoBackupAlreadyQueued(err) {
+			ctx.Error(http.StatusConflict, "", "a backup of the repository is already pending or running")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "QueueRepoBackup", err)
+		}
+		return
+	}
+	repo_service.StartProcessingRepoBackups()
+
+	backup.Repo = repo
+	ctx.JSON(http.StatusAccepted, convert.ToRepoBackup(backup))
+}
+
+func getRepoBackupByParams(ctx *context.APIContext) *models.RepoBackup {
+	backup, err := models.GetRepoBackupByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if models.IsErrRepoBackupNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoBackupByID", err)
+		}
+		return nil
+	}
+	return backup
+}
+
+// RetryRepoBackup api for retrying a failed repository backup
+func RetryRepoBackup(ctx *context.APIContext) {
+	// swagger:operation POST /admin/repo-backups/{id}/retry admin adminRetryRepoBackup
+	// ---
+	// summary: Retry a failed repository backup
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the backup to retry
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	backup := getRepoBackupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_service.RetryRepoBackup(backup); err != nil {
+		if models.IsErrRepoBackupNotFailed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "only failed backups can be retried")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RetryRepoBackup", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteRepoBackup api for deleting a repository backup and its files
+func DeleteRepoBackup(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/repo-backups/{id} admin adminDeleteRepoBackup
+	// ---
+	// summary: Delete a repository backup and its files
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the backup to delete
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	backup := getRepoBackupByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if backup.Status == models.RepoBackupRunning {
+		ctx.Error(http.StatusConflict, "", "the backup is running")
+		return
+	}
+	if err := repo_service.DeleteRepoBackup(backup); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteRepoBackup", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	stats_service "code.gitea.io/gitea/services/stats"
+)
+
+func toAPIStatistics(stats *stats_service.Statistics) *api.Statistics {
+	c := stats.Counter
+	return &api.Statistics{
+		Users:                 c.User,
+		Organizations:         c.Org,
+		PublicKeys:            c.PublicKey,
+		Repositories:          c.Repo,
+		Watches:               c.Watch,
+		Stars:                 c.Star,
+		Actions:               c.Action,
+		Accesses:              c.Access,
+		Issues:                c.Issue,
+		IssuesOpen:            c.IssueOpen,
+		IssuesClosed:          c.IssueClosed,
+		Comments:              c.Comment,
+		Follows:               c.Follow,
+		Mirrors:               c.Mirror,
+		Releases:              c.Release,
+		AuthenticationSources: c.LoginSource,
+		Webhooks:              c.Webhook,
+		Milestones:            c.Milestone,
+		Labels:                c.Label,
+		HookTasks:             c.HookTask,
+		Teams:                 c.Team,
+		Projects:              c.Project,
+		ProjectBoards:         c.ProjectBoard,
+		Attachments:           c.Attachment,
+		AsOf:                  stats.AsOf,
+	}
+}
+
+// GetStatistics returns the latest statistics of the instance
+func GetStatistics(ctx *context.APIContext) {
+	// swagger:operation GET /admin/stats admin adminGetStatistics
+	// ---
+	// summary: Get the latest statistics of the instance
+	// description: The statistics are counted periodically, as_of tells when they were counted.
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Statistics"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	ctx.JSON(http.StatusOK, toAPIStatistics(stats_service.Get()))
+}
+
+// RefreshStatistics counts the statistics of the instance again
+func RefreshStatistics(ctx *context.APIContext) {
+	// swagger:operation POST /admin/stats/refresh admin adminRefreshStatistics
+	// ---
+	// summary: Count the statistics of the instance again
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Statistics"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	ctx.JSON(http.StatusOK, toAPIStatistics(stats_service.Refresh()))
+}
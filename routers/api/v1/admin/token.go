@@ -0,0 +1,136 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// defaultUnusedTokenDays is the default number of days after which a token is reported as unused
+const defaultUnusedTokenDays = 90
+
+func unusedSince(ctx *context.APIContext) (timeutil.TimeStamp, bool) {
+	days := ctx.FormInt("days")
+	if days == 0 {
+		days = defaultUnusedTokenDays
+	}
+	if days < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "days must be a positive number")
+		return 0, false
+	}
+	return timeutil.TimeStampNow().AddDuration(-time.Duration(days) * 24 * time.Hour), true
+}
+
+// ListUnusedAccessTokens lists the access tokens of all users which have not been used for a number of days
+func ListUnusedAccessTokens(ctx *context.APIContext) {
+	// swagger:operation GET /admin/tokens/unused admin adminListUnusedAccessTokens
+	// ---
+	// summary: List the access tokens of all users which have not been used for a number of days
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: days
+	//   in: query
+	//   description: number of days the tokens have not been used for, defaults to 90
+	//   type: integer
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AccessTokenList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since, ok := unusedSince(ctx)
+	if !ok {
+		return
+	}
+
+	tokens, count, err := models.FindUnusedAccessTokens(models.FindUnusedAccessTokensOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		UnusedSince: since,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUnusedAccessTokens", err)
+		return
+	}
+
+	userIDs := make([]int64, 0, len(tokens))
+	for _, t := range tokens {
+		userIDs = append(userIDs, t.UID)
+	}
+	users, err := models.GetUsersByIDs(userIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsersByIDs", err)
+		return
+	}
+	owners := make(map[int64]*models.User, len(users))
+	for _, u := range users {
+		owners[u.ID] = u
+	}
+
+	apiTokens := make([]*api.AccessToken, len(tokens))
+	for i, t := range tokens {
+		apiTokens[i] = convert.ToAccessToken(t)
+		if owner, ok := owners[t.UID]; ok {
+			apiTokens[i].Owner = convert.ToUser(owner, ctx.User)
+		}
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiTokens)
+}
+
+// DeleteUnusedAccessTokens revokes the access tokens of all users which have not been used for a number of days
+func DeleteUnusedAccessTokens(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/tokens/unused admin adminDeleteUnusedAccessTokens
+	// ---
+	// summary: Revoke the access tokens of all users which have not been used for a number of days
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: days
+	//   in: query
+	//   description: number of days the tokens have not been used for, defaults to 90
+	//   type: integer
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since, ok := unusedSince(ctx)
+	if !ok {
+		return
+	}
+
+	count, err := models.DeleteUnusedAccessTokens(since)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteUnusedAccessTokens", err)
+		return
+	}
+	log.Trace("Access tokens unused since %s revoked by admin %s: %d", since.FormatLong(), ctx.User.Name, count)
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// RenameTopic api for renaming a topic of all repositories
+func RenameTopic(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/topics/{topic} admin adminRenameTopic
+	// ---
+	// summary: Rename a topic of all repositories
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: topic
+	//   in: path
+	//   description: name of the topic to rename
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RenameTopicOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Topic"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.RenameTopicOption)
+
+	name := strings.TrimSpace(strings.ToLower(form.Name))
+	if !models.ValidateTopic(name) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid topic name: %s", form.Name))
+		return
+	}
+
+	if err := models.RenameTopic(ctx.Params(":topic"), name); err != nil {
+		if models.IsErrTopicNotExist(err) {
+			ctx.NotFound()
+		} else if models.IsErrTopicAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", fmt.Sprintf("topic %s already exists, merge the topic instead", name))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RenameTopic", err)
+		}
+		return
+	}
+
+	topic, err := models.GetTopicByName(name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTopicByName", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTopicResponse(topic))
+}
+
+// MergeTopic api for merging a topic into another one
+func MergeTopic(ctx *context.APIContext) {
+	// swagger:operation POST /admin/topics/{topic}/merge admin adminMergeTopic
+	// ---
+	// summary: Merge a topic into another one, the merged topic is deleted
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: topic
+	//   in: path
+	//   description: name of the topic to merge
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MergeTopicOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Topic"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.MergeTopicOption)
+
+	topic, err := models.MergeTopics(ctx.Params(":topic"), strings.TrimSpace(strings.ToLower(form.Target)))
+	if err != nil {
+		if models.IsErrTopicNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "MergeTopics", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTopicResponse(topic))
+}
@@ -16,11 +16,13 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/password"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/user"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/mailer"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 func parseLoginSource(ctx *context.APIContext, u *models.User, sourceID int64, loginName string) {
@@ -112,6 +114,7 @@ func CreateUser(ctx *context.APIContext) {
 			models.IsErrEmailAlreadyUsed(err) ||
 			models.IsErrNameReserved(err) ||
 			models.IsErrNameCharsNotAllowed(err) ||
+			models.IsErrNameTooLong(err) ||
 			models.IsErrEmailInvalid(err) ||
 			models.IsErrNamePatternNotAllowed(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
@@ -129,6 +132,167 @@ func CreateUser(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToUser(u, ctx.User))
 }
 
+// maxCreateUsers is the maximum number of users created by one bulk request
+const maxCreateUsers = 1000
+
+// CreateUsers creates users in bulk
+func CreateUsers(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/bulk admin adminCreateUsers
+	// ---
+	// summary: Create users in bulk
+	// description: The users are created in batches, the users which are invalid or conflict with existing users are skipped.
+	//   The authorized_keys file is rewritten once after the SSH keys of all users were added.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateUsersOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CreateUsersResultList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.CreateUsersOption)
+	if len(form.Users) > maxCreateUsers {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("at most %d users can be created at once", maxCreateUsers))
+		return
+	}
+
+	results := make([]*api.CreateUsersResult, len(form.Users))
+	newUsers := make([]*user_service.NewUser, 0, len(form.Users))
+	indexes := make([]int, 0, len(form.Users))
+	sources := make(map[int64]*login.Source)
+	for i, entry := range form.Users {
+		results[i] = &api.CreateUsersResult{Username: entry.Username}
+		newUser, err := toNewUser(ctx, entry, sources)
+		if err != nil {
+			if ctx.Written() {
+				return
+			}
+			results[i].Status = "invalid"
+			results[i].Error = err.Error()
+			continue
+		}
+		newUsers = append(newUsers, newUser)
+		indexes = append(indexes, i)
+	}
+
+	errs := user_service.CreateUsers(newUsers, form.SendNotify)
+	for j, err := range errs {
+		result := results[indexes[j]]
+		switch {
+		case err == nil:
+			result.Status = "created"
+			result.User = convert.ToUser(newUsers[j].User, ctx.User)
+			log.Trace("Account created in bulk by admin (%s): %s", ctx.User.Name, newUsers[j].User.Name)
+		case models.IsErrUserAlreadyExist(err) || models.IsErrEmailAlreadyUsed(err):
+			result.Status = "conflict"
+			result.Error = err.Error()
+		case models.IsErrNameReserved(err) ||
+			models.IsErrNameCharsNotAllowed(err) ||
+			models.IsErrNameTooLong(err) ||
+			models.IsErrEmailInvalid(err) ||
+			models.IsErrNamePatternNotAllowed(err):
+			result.Status = "invalid"
+			result.Error = err.Error()
+		default:
+			result.Status = "failed"
+			result.Error = err.Error()
+		}
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
+
+// toNewUser checks an entry of a bulk request and converts it to a new user,
+// the errors of invalid entries are returned without writing a response
+func toNewUser(ctx *context.APIContext, entry *api.CreateUsersEntry, sources map[int64]*login.Source) (*user_service.NewUser, error) {
+	if entry == nil {
+		return nil, errors.New("the user is missing")
+	}
+	switch {
+	case entry.Username == "":
+		return nil, errors.New("the username is required")
+	case len(entry.Username) > 40:
+		return nil, errors.New("the username is too long")
+	case entry.Email == "":
+		return nil, errors.New("the email address is required")
+	case len(entry.Email) > 254:
+		return nil, errors.New("the email address is too long")
+	case len(entry.FullName) > 100:
+		return nil, errors.New("the full name is too long")
+	case len(entry.Password) > 255:
+		return nil, errors.New("the password is too long")
+	}
+
+	u := &models.User{
+		Name:               entry.Username,
+		FullName:           entry.FullName,
+		Email:              entry.Email,
+		Passwd:             entry.Password,
+		MustChangePassword: true,
+		IsActive:           true,
+		LoginType:          login.Plain,
+	}
+	if entry.MustChangePassword != nil {
+		u.MustChangePassword = *entry.MustChangePassword
+	}
+
+	if entry.SourceID != 0 {
+		source, ok := sources[entry.SourceID]
+		if !ok {
+			var err error
+			if source, err = login.GetSourceByID(entry.SourceID); err != nil {
+				if !login.IsErrSourceNotExist(err) {
+					ctx.Error(http.StatusInternalServerError, "login.GetSourceByID", err)
+				}
+				return nil, err
+			}
+			sources[entry.SourceID] = source
+		}
+		u.LoginType = source.Type
+		u.LoginSource = source.ID
+		u.LoginName = entry.LoginName
+	} else {
+		// users signing in with a login source don't need a password
+		if !password.IsComplexEnough(entry.Password) {
+			return nil, errors.New("the password is not complex enough")
+		}
+		if pwned, err := password.IsPwned(ctx, entry.Password); pwned {
+			if err != nil {
+				log.Error(err.Error())
+			}
+			return nil, errors.New("the password has been pwned")
+		}
+	}
+
+	var overwriteDefault *models.CreateUserOverwriteOptions
+	if entry.Visibility != "" {
+		visibility, ok := api.VisibilityModes[entry.Visibility]
+		if !ok || !setting.Service.AllowedUserVisibilityModesSlice.IsAllowedVisibility(visibility) {
+			return nil, fmt.Errorf("the visibility %s is not allowed", entry.Visibility)
+		}
+		overwriteDefault = &models.CreateUserOverwriteOptions{Visibility: visibility}
+	}
+
+	keys := make([]string, 0, len(entry.SSHKeys))
+	for _, key := range entry.SSHKeys {
+		content, err := models.CheckPublicKeyString(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SSH key: %v", err)
+		}
+		keys = append(keys, content)
+	}
+
+	return &user_service.NewUser{User: u, OverwriteDefault: overwriteDefault, PublicKeys: keys}, nil
+}
+
 // EditUser api for modifying a user's information
 func EditUser(ctx *context.APIContext) {
 	// swagger:operation PATCH /admin/users/{username} admin adminEditUser
@@ -161,6 +325,11 @@ func EditUser(ctx *context.APIContext) {
 		return
 	}
 
+	if u.IsAnonymized() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is anonymized and can't be edited", u.Name))
+		return
+	}
+
 	parseLoginSource(ctx, u, form.SourceID, form.LoginName)
 	if ctx.Written() {
 		return
@@ -243,6 +412,9 @@ func EditUser(ctx *context.APIContext) {
 	if form.Restricted != nil {
 		u.IsRestricted = *form.Restricted
 	}
+	if form.ServiceAccount != nil {
+		u.IsServiceAccount = *form.ServiceAccount
+	}
 
 	if err := models.UpdateUser(u); err != nil {
 		if models.IsErrEmailAlreadyUsed(err) || models.IsErrEmailInvalid(err) {
@@ -270,6 +442,10 @@ func DeleteUser(ctx *context.APIContext) {
 	//   description: username of user to delete
 	//   type: string
 	//   required: true
+	// - name: purge
+	//   in: query
+	//   description: also delete the organizations the user is the only member of, including their repositories
+	//   type: boolean
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
@@ -288,7 +464,7 @@ func DeleteUser(ctx *context.APIContext) {
 		return
 	}
 
-	if err := models.DeleteUser(u); err != nil {
+	if err := user_service.DeleteUser(ctx.Req.Context(), u, ctx.FormBool("purge")); err != nil {
 		if models.IsErrUserOwnRepos(err) ||
 			models.IsErrUserHasOrgs(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
@@ -333,7 +509,61 @@ func CreatePublicKey(ctx *context.APIContext) {
 	if ctx.Written() {
 		return
 	}
-	user.CreateUserPublicKey(ctx, *form, u.ID)
+	user.CreateUserPublicKey(ctx, *form, u)
+}
+
+// AnonymizeUser api for anonymizing a user instead of deleting it
+func AnonymizeUser(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/{username}/anonymize admin adminAnonymizeUser
+	// ---
+	// summary: Anonymize a user
+	// description: The user is renamed to former-user-<id>, its email addresses, full name, avatar, SSH and GPG keys
+	//   and external logins are removed and it can't sign in anymore. Unlike deleting, its issues, pull requests and
+	//   comments stay attributed to the pseudonym.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of user to anonymize
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/User"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if u.IsOrganization() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is an organization not a user", u.Name))
+		return
+	}
+	if u.IsAnonymized() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("%s is already anonymized", u.Name))
+		return
+	}
+
+	name := u.Name
+	if err := models.AnonymizeUser(u); err != nil {
+		if models.IsErrUserOwnRepos(err) ||
+			models.IsErrUserHasOrgs(err) ||
+			models.IsErrUserAlreadyExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AnonymizeUser", err)
+		}
+		return
+	}
+	log.Trace("Account anonymized by admin(%s): %s", ctx.User.Name, name)
+
+	ctx.JSON(http.StatusOK, convert.ToUser(u, ctx.User))
 }
 
 // DeleteUserPublicKey api for deleting a user's public key
@@ -368,16 +598,24 @@ func DeleteUserPublicKey(ctx *context.APIContext) {
 		return
 	}
 
-	if err := models.DeletePublicKey(u, ctx.ParamsInt64(":id")); err != nil {
+	key, err := models.GetPublicKeyByID(ctx.ParamsInt64(":id"))
+	if err != nil {
 		if models.IsErrKeyNotExist(err) {
 			ctx.NotFound()
-		} else if models.IsErrKeyAccessDenied(err) {
-			ctx.Error(http.StatusForbidden, "", "You do not have access to this key")
 		} else {
-			ctx.Error(http.StatusInternalServerError, "DeleteUserPublicKey", err)
+			ctx.Error(http.StatusInternalServerError, "GetPublicKeyByID", err)
 		}
 		return
 	}
+	if key.OwnerID != u.ID {
+		ctx.Error(http.StatusForbidden, "", "You do not have access to this key")
+		return
+	}
+
+	if err := user_service.DeletePublicKey(ctx.User, key.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteUserPublicKey", err)
+		return
+	}
 	log.Trace("Key deleted by admin(%s): %s", ctx.User.Name, u.Name)
 
 	ctx.Status(http.StatusNoContent)
@@ -87,6 +87,7 @@ import (
 	"code.gitea.io/gitea/services/forms"
 
 	"gitea.com/go-chi/binding"
+	chi "github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
 )
 
@@ -137,7 +138,11 @@ func repoAssignment() func(ctx *context.APIContext) {
 			owner, err = models.GetUserByName(userName)
 			if err != nil {
 				if models.IsErrUserNotExist(err) {
-					if redirectUserID, err := models.LookupUserRedirect(userName); err == nil {
+					if repo, redirected, err := models.LookupRepositoryByOwnerAndName(userName, repoName); err == nil && redirected {
+						context.RedirectToRenamedOwnerRepo(ctx.Context, repo)
+					} else if err != nil && !models.IsErrRepoNotExist(err) {
+						ctx.Error(http.StatusInternalServerError, "LookupRepositoryByOwnerAndName", err)
+					} else if redirectUserID, err := models.LookupUserRedirect(userName); err == nil {
 						context.RedirectToUser(ctx.Context, userName, redirectUserID)
 					} else if models.IsErrUserRedirectNotExist(err) {
 						ctx.NotFound("GetUserByName", err)
@@ -173,10 +178,18 @@ func repoAssignment() func(ctx *context.APIContext) {
 		repo.Owner = owner
 		ctx.Repo.Repository = repo
 
-		ctx.Repo.Permission, err = models.GetUserRepoPermission(repo, ctx.User)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
-			return
+		if key, ok := ctx.Data["DeployKey"].(*models.DeployKey); ok {
+			ctx.Repo.Permission, err = models.GetDeployKeyRepoPermission(repo, key)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetDeployKeyRepoPermission", err)
+				return
+			}
+		} else {
+			ctx.Repo.Permission, err = models.GetUserRepoPermission(repo, ctx.User)
+			if err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return
+			}
 		}
 
 		if !ctx.Repo.HasAccess() {
@@ -262,6 +275,19 @@ func reqRepoWriter(unitTypes ...models.UnitType) func(ctx *context.APIContext) {
 	}
 }
 
+// reqRepoMetadataWriter user should be a repo admin or a site admin, or have write permission to the code if writers are allowed to change metadata
+func reqRepoMetadataWriter() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if setting.Repository.Metadata.AllowWriters && ctx.IsUserRepoWriter([]models.UnitType{models.UnitTypeCode}) {
+			return
+		}
+		if !ctx.IsUserRepoAdmin() && !ctx.IsUserSiteAdmin() {
+			ctx.Error(http.StatusForbidden, "reqRepoMetadataWriter", "user should be allowed to change the metadata of a repo")
+			return
+		}
+	}
+}
+
 // reqRepoReader user should have specific read permission or be a repo admin or a site admin
 func reqRepoReader(unitType models.UnitType) func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
@@ -522,12 +548,82 @@ func mustEnableIssuesOrPulls(ctx *context.APIContext) {
 }
 
 func mustNotBeArchived(ctx *context.APIContext) {
-	if ctx.Repo.Repository.IsArchived {
-		ctx.NotFound()
+	if err := ctx.Repo.Repository.CheckNotArchived(); err != nil {
+		ctx.Error(http.StatusForbidden, "RepoIsArchived", err)
 		return
 	}
 }
 
+// archivedRepoWritableRoutes lists the routes below /repos/{username}/{reponame}
+// which may still change state when the repository is archived, by method.
+var archivedRepoWritableRoutes = map[string][]string{
+	"":                                     {http.MethodPatch, http.MethodDelete}, // unarchive or delete the repository
+	"/forks":                               {http.MethodPost},
+	"/generate":                            {http.MethodPost},
+	"/markdown":                            {http.MethodPost},
+	"/markdown/raw":                        {http.MethodPost},
+	"/notifications":                       {http.MethodPut},
+	"/subscription":                        {http.MethodPut, http.MethodDelete},
+	"/issues/{index}/subscriptions/{user}": {http.MethodPut, http.MethodDelete},
+}
+
+// repoArchivedGuard rejects every request that would change an archived
+// repository, except for those listed in archivedRepoWritableRoutes.
+func repoArchivedGuard(ctx *context.APIContext) {
+	if !ctx.Repo.Repository.IsArchived {
+		return
+	}
+	switch ctx.Req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+
+	pattern := chi.RouteContext(ctx.Req.Context()).RoutePattern()
+	if idx := strings.Index(pattern, "/{reponame}"); idx >= 0 {
+		pattern = pattern[idx+len("/{reponame}"):]
+	}
+	for _, method := range archivedRepoWritableRoutes[pattern] {
+		if method == ctx.Req.Method {
+			return
+		}
+	}
+	mustNotBeArchived(ctx)
+}
+
+// deployKeyWritableRoutes lists the routes below /repos/{username}/{reponame}
+// which deploy keys may call to change their repository, by method.
+var deployKeyWritableRoutes = map[string][]string{
+	"/statuses/{sha}": {http.MethodPost},
+}
+
+// deployKeyGuard restricts the requests authenticated with the statuses token of a
+// deploy key to reading the repositories and the routes in deployKeyWritableRoutes.
+func deployKeyGuard(ctx *context.APIContext) {
+	key, ok := ctx.Data["DeployKey"].(*models.DeployKey)
+	if !ok {
+		return
+	}
+
+	pattern := chi.RouteContext(ctx.Req.Context()).RoutePattern()
+	idx := strings.Index(pattern, "/repos/{username}/{reponame}")
+	if idx < 0 {
+		ctx.Error(http.StatusForbidden, "DeployKey", "deploy keys may only access their repository")
+		return
+	}
+	switch ctx.Req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return
+	}
+
+	pattern = pattern[idx+len("/repos/{username}/{reponame}"):]
+	for _, method := range deployKeyWritableRoutes[pattern] {
+		if method == ctx.Req.Method && key.CanWriteStatuses {
+			return
+		}
+	}
+	ctx.Error(http.StatusForbidden, "DeployKey", "deploy keys may only post commit statuses")
+}
+
 // bind binding an obj to a func(ctx *context.APIContext)
 func bind(obj interface{}) http.HandlerFunc {
 	var tp = reflect.TypeOf(obj)
@@ -568,6 +664,9 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 	// Get user from session if logged in.
 	m.Use(context.APIAuth(auth.NewGroup(auth.Methods()...)))
 
+	// Limit the requests per token, user or IP.
+	m.Use(rateLimit())
+
 	m.Use(context.ToggleAPI(&context.ToggleOptions{
 		SignInRequired: setting.Service.RequireSignInView,
 	}))
@@ -586,11 +685,16 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 		m.Get("/signing-key.gpg", misc.SigningKey)
 		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
 		m.Post("/markdown/raw", misc.MarkdownRaw)
+		m.Group("/options", func() {
+			m.Get("/license/{name}", misc.GetLicenseTemplate)
+			m.Get("/{type}", misc.ListRepoInitOptions)
+		})
 		m.Group("/settings", func() {
 			m.Get("/ui", settings.GetGeneralUISettings)
 			m.Get("/api", settings.GetGeneralAPISettings)
 			m.Get("/attachment", settings.GetGeneralAttachmentSettings)
 			m.Get("/repository", settings.GetGeneralRepoSettings)
+			m.Get("/ssh", settings.GetGeneralSSHSettings)
 		})
 
 		// Notifications
@@ -616,6 +720,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				}
 
 				m.Get("/repos", reqExploreSignIn(), user.ListUserRepos)
+				m.Get("/profile_readme", reqExploreSignIn(), user.GetProfileReadme)
 				m.Group("/tokens", func() {
 					m.Combo("").Get(user.ListAccessTokens).
 						Post(bind(api.CreateAccessTokenOption{}), user.CreateAccessToken)
@@ -641,6 +746,8 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 			})
 		}, reqToken())
 
+		// the signed link of the archive is enough to download it
+		m.Get("/user/export/{id}/download", user.DownloadExport)
 		m.Group("/user", func() {
 			m.Get("", user.GetAuthenticatedUser)
 			m.Group("/settings", func() {
@@ -681,6 +788,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 			})
 
 			m.Get("/gpg_key_token", user.GetVerificationToken)
+			m.Get("/gpg_key_status", user.GetMyGPGKeyStatus)
 			m.Post("/gpg_key_verify", bind(api.VerifyGPGKeyOption{}), user.VerifyUserGPGKey)
 
 			m.Combo("/repos").Get(user.ListMyRepos).
@@ -699,8 +807,20 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 			m.Get("/stopwatches", repo.GetStopwatches)
 
 			m.Get("/subscriptions", user.GetMyWatchedRepos)
+			m.Combo("/subscriptions/issues").Get(user.ListMyIssueSubscriptions).
+				Delete(user.UnsubscribeMyIssues)
 
 			m.Get("/teams", org.ListUserTeams)
+
+			m.Combo("/export").Get(user.ListMyExports).Post(user.ExportMyData)
+
+			m.Group("/saved_replies", func() {
+				m.Combo("").Get(user.ListMySavedReplies).
+					Post(bind(api.CreateSavedReplyOption{}), user.CreateMySavedReply)
+				m.Combo("/{id}").Get(user.GetMySavedReply).
+					Patch(bind(api.EditSavedReplyOption{}), user.EditMySavedReply).
+					Delete(user.DeleteMySavedReply)
+			})
 		}, reqToken())
 
 		// Repositories
@@ -740,14 +860,40 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							Patch(bind(api.EditHookOption{}), repo.EditHook).
 							Delete(repo.DeleteHook)
 						m.Post("/tests", context.RepoRefForAPI, repo.TestHook)
+						m.Post("/rotate_secret", bind(api.RotateHookSecretOption{}), repo.RotateHookSecret)
 					})
 				}, reqToken(), reqAdmin(), reqWebhooksEnabled())
+				m.Combo("/push_rules", reqToken(), reqAdmin()).Get(repo.GetPushRules).
+					Put(bind(api.EditPushRulesOption{}), repo.EditPushRules)
+				m.Combo("/git_settings", reqToken(), reqAdmin()).Get(repo.GetGitSettings).
+					Patch(bind(api.EditRepoGitSettingsOption{}), repo.EditGitSettings)
+				m.Post("/settings/external_tracker/preview", reqToken(), reqAdmin(),
+					bind(api.ExternalTrackerPreviewOption{}), repo.PreviewExternalTracker)
+				m.Group("/health-check", func() {
+					m.Post("", repo.CheckHealth)
+					m.Get("/{task_id}", repo.GetHealthCheck)
+				}, reqToken(), reqAdmin())
+				m.Group("/detach-fork", func() {
+					m.Post("", bind(api.DetachForkOption{}), repo.DetachFork)
+					m.Get("/{task_id}", repo.GetDetachFork)
+				}, reqToken(), reqAdmin())
+				m.Group("/clone-token", func() {
+					m.Combo("").Get(repo.ListCloneTokens).
+						Post(bind(api.CreateRepoCloneTokenOption{}), repo.CreateCloneToken)
+					m.Delete("/{id}", repo.DeleteCloneToken)
+				}, reqToken(), reqRepoReader(models.UnitTypeCode))
+				m.Group("/traffic", func() {
+					m.Get("/clones", repo.GetCloneTraffic)
+					m.Get("/views", repo.GetViewTraffic)
+				}, reqToken(), reqAdmin())
 				m.Group("/collaborators", func() {
 					m.Get("", reqAnyRepoReader(), repo.ListCollaborators)
 					m.Combo("/{collaborator}").Get(reqAnyRepoReader(), repo.IsCollaborator).
 						Put(reqAdmin(), bind(api.AddCollaboratorOption{}), repo.AddCollaborator).
 						Delete(reqAdmin(), repo.DeleteCollaborator)
+					m.Get("/{collaborator}/permission", repo.GetRepoPermissions)
 				}, reqToken())
+				m.Get("/permissions", reqToken(), reqAdmin(), repo.ListPermissions)
 				m.Get("/assignees", reqToken(), reqAnyRepoReader(), repo.GetAssignees)
 				m.Get("/reviewers", reqToken(), reqAnyRepoReader(), repo.GetReviewers)
 				m.Group("/teams", func() {
@@ -760,12 +906,17 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				m.Get("/archive/*", reqRepoReader(models.UnitTypeCode), repo.GetArchive)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(), reqRepoReader(models.UnitTypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+				m.Combo("/sync_fork", reqRepoReader(models.UnitTypeCode)).Get(repo.GetForkSyncStatus).
+					Post(reqToken(), reqRepoWriter(models.UnitTypeCode), repo.SyncFork)
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
+					m.Get("/stale", context.ReferencesGitRepo(false), repo.ListStaleBranches)
 					m.Get("/*", repo.GetBranch)
 					m.Delete("/*", context.ReferencesGitRepo(false), reqRepoWriter(models.UnitTypeCode), repo.DeleteBranch)
 					m.Post("", reqRepoWriter(models.UnitTypeCode), bind(api.CreateBranchRepoOption{}), repo.CreateBranch)
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Combo("/stale_branch_cleanup", reqToken(), reqAdmin()).Get(repo.GetStaleBranchCleanup).
+					Patch(bind(api.EditStaleBranchCleanupOption{}), repo.EditStaleBranchCleanup)
 				m.Group("/branch_protections", func() {
 					m.Get("", repo.ListBranchProtections)
 					m.Post("", bind(api.CreateBranchProtectionOption{}), repo.CreateBranchProtection)
@@ -793,13 +944,14 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				}, mustEnableIssues, reqToken())
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
-						Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+						Post(reqToken(), context.ReferencesGitRepo(true), bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Get("/export", repo.ExportIssues)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/{id}", func() {
 							m.Combo("").
 								Get(repo.GetIssueComment).
-								Patch(mustNotBeArchived, reqToken(), bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
+								Patch(reqToken(), bind(api.EditIssueCommentOption{}), repo.EditIssueComment).
 								Delete(reqToken(), repo.DeleteIssueComment)
 							m.Combo("/reactions").
 								Get(repo.GetIssueCommentReactions).
@@ -812,7 +964,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							Patch(reqToken(), bind(api.EditIssueOption{}), repo.EditIssue)
 						m.Group("/comments", func() {
 							m.Combo("").Get(repo.ListIssueComments).
-								Post(reqToken(), mustNotBeArchived, bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
+								Post(reqToken(), bind(api.CreateIssueCommentOption{}), repo.CreateIssueComment)
 							m.Combo("/{id}", reqToken()).Patch(bind(api.EditIssueCommentOption{}), repo.EditIssueCommentDeprecated).
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
@@ -831,6 +983,9 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							m.Delete("/{id}", repo.DeleteTime)
 						}, reqToken())
 						m.Combo("/deadline").Post(reqToken(), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Post("/pin", reqToken(), repo.PinIssue)
+						m.Patch("/pin/{position}", reqToken(), repo.MoveIssuePin)
+						m.Post("/unpin", reqToken(), repo.UnpinIssue)
 						m.Group("/stopwatch", func() {
 							m.Post("/start", reqToken(), repo.StartIssueStopwatch)
 							m.Post("/stop", reqToken(), repo.StopIssueStopwatch)
@@ -846,11 +1001,14 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							Get(repo.GetIssueReactions).
 							Post(reqToken(), bind(api.EditReactionOption{}), repo.PostIssueReaction).
 							Delete(reqToken(), bind(api.EditReactionOption{}), repo.DeleteIssueReaction)
+						m.Get("/references", repo.ListIssueReferences)
+						m.Get("/referencing", repo.ListIssuesReferencedBy)
 					})
 				}, mustEnableIssuesOrPulls)
 				m.Group("/labels", func() {
 					m.Combo("").Get(repo.ListLabels).
 						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateLabelOption{}), repo.CreateLabel)
+					m.Post("/copy", reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CopyLabelsOption{}), repo.CopyLabels)
 					m.Combo("/{id}").Get(repo.GetLabel).
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditLabelOption{}), repo.EditLabel).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteLabel)
@@ -860,6 +1018,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				m.Group("/milestones", func() {
 					m.Combo("").Get(repo.ListMilestones).
 						Post(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CreateMilestoneOption{}), repo.CreateMilestone)
+					m.Post("/copy", reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.CopyMilestonesOption{}), repo.CopyMilestones)
 					m.Combo("/{id}").Get(repo.GetMilestone).
 						Patch(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), bind(api.EditMilestoneOption{}), repo.EditMilestone).
 						Delete(reqToken(), reqRepoWriter(models.UnitTypeIssues, models.UnitTypePullRequests), repo.DeleteMilestone)
@@ -881,6 +1040,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 						m.Group("/assets", func() {
 							m.Combo("").Get(repo.ListReleaseAttachments).
 								Post(reqToken(), reqRepoWriter(models.UnitTypeReleases), repo.CreateReleaseAttachment)
+							m.Get("/tasks/{task_id}", reqToken(), reqRepoWriter(models.UnitTypeReleases), repo.GetReleaseAttachmentTask)
 							m.Combo("/{asset}").Get(repo.GetReleaseAttachment).
 								Patch(reqToken(), reqRepoWriter(models.UnitTypeReleases), bind(api.EditAttachmentOptions{}), repo.EditReleaseAttachment).
 								Delete(reqToken(), reqRepoWriter(models.UnitTypeReleases), repo.DeleteReleaseAttachment)
@@ -893,18 +1053,27 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					})
 				}, reqRepoReader(models.UnitTypeReleases))
 				m.Post("/mirror-sync", reqToken(), reqRepoWriter(models.UnitTypeCode), repo.MirrorSync)
+				m.Post("/migrate/sync", reqToken(), reqAdmin(), bind(api.SyncMigrationOptions{}), repo.SyncMigration)
+				m.Post("/convert", reqToken(), reqOwner(), bind(api.ConvertRepoOption{}), repo.Convert)
 				m.Get("/editorconfig/{filename}", context.RepoRefForAPI, reqRepoReader(models.UnitTypeCode), repo.GetEditorconfig)
 				m.Group("/pulls", func() {
 					m.Combo("").Get(repo.ListPullRequests).
-						Post(reqToken(), mustNotBeArchived, bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
+						Post(reqToken(), bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
+					m.Post("/cleanup-refs", reqToken(), reqAdmin(), repo.CleanupPullRequestRefs)
 					m.Group("/{index}", func() {
 						m.Combo("").Get(repo.GetPullRequest).
 							Patch(reqToken(), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
 						m.Get(".{diffType:diff|patch}", repo.DownloadPullDiffOrPatch)
 						m.Post("/update", reqToken(), repo.UpdatePullRequest)
 						m.Get("/commits", repo.GetPullRequestCommits)
+						m.Get("/files", repo.GetPullRequestFiles)
+						m.Combo("/viewed-files", reqToken()).Get(repo.ListPullViewedFiles).
+							Put(bind(api.UpdatePullViewedFilesOption{}), repo.UpdatePullViewedFiles)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
-							Post(reqToken(), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
+							Post(reqToken(), bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
+						m.Combo("/enqueue").Get(repo.GetPullRequestMergeQueueEntry).
+							Post(reqToken(), bind(forms.MergePullRequestForm{}), repo.EnqueuePullRequest).
+							Delete(reqToken(), repo.DequeuePullRequest)
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -929,6 +1098,7 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Combo("/{sha}").Get(repo.GetCommitStatuses).
 						Post(reqToken(), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
 				}, reqRepoReader(models.UnitTypeCode))
+				m.Get("/compare/*", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.CompareDiff)
 				m.Group("/commits", func() {
 					m.Get("", repo.GetAllCommits)
 					m.Group("/{ref}", func() {
@@ -940,13 +1110,19 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Group("/commits", func() {
 						m.Get("/{sha}", repo.GetSingleCommit)
 						m.Get("/{sha}.{diffType:diff|patch}", repo.DownloadCommitDiffOrPatch)
+						m.Group("/{sha}", func() {
+							m.Post("/cherry-pick", bind(api.ApplyCommitOption{}), repo.CherryPickCommit)
+							m.Post("/revert", bind(api.ApplyCommitOption{}), repo.RevertCommit)
+						}, reqToken(), reqRepoWriter(models.UnitTypeCode))
 					})
 					m.Get("/refs", repo.GetGitAllRefs)
 					m.Get("/refs/*", repo.GetGitRefs)
 					m.Get("/trees/{sha}", context.RepoRefForAPI, repo.GetTree)
 					m.Get("/blobs/{sha}", context.RepoRefForAPI, repo.GetBlob)
 					m.Get("/tags/{sha}", context.RepoRefForAPI, repo.GetAnnotatedTag)
-					m.Get("/notes/{sha}", repo.GetNote)
+					m.Combo("/notes/{sha}").Get(repo.GetNote).
+						Post(reqToken(), reqRepoWriter(models.UnitTypeCode), bind(api.SetNoteOption{}), repo.SetNote).
+						Delete(reqToken(), reqRepoWriter(models.UnitTypeCode), repo.DeleteNote)
 				}, reqRepoReader(models.UnitTypeCode))
 				m.Group("/contents", func() {
 					m.Get("", repo.GetContentsList)
@@ -966,9 +1142,17 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 							Delete(reqToken(), repo.DeleteTopic)
 					}, reqAdmin())
 				}, reqAnyRepoReader())
+				m.Group("/metadata", func() {
+					m.Get("", repo.ListMetadata)
+					m.Combo("/{key}").Get(repo.GetMetadata).
+						Put(reqToken(), reqRepoMetadataWriter(), bind(api.SetRepoMetadataOption{}), repo.SetMetadata).
+						Delete(reqToken(), reqRepoMetadataWriter(), repo.DeleteMetadata)
+				}, reqRepoReader(models.UnitTypeCode))
 				m.Get("/issue_templates", context.ReferencesGitRepo(false), repo.GetIssueTemplates)
+				m.Get("/issue_forms/{name}/schema", context.ReferencesGitRepo(false), repo.GetIssueFormSchema)
 				m.Get("/languages", reqRepoReader(models.UnitTypeCode), repo.GetLanguages)
-			}, repoAssignment())
+				m.Get("/codeowners/errors", reqRepoReader(models.UnitTypeCode), context.ReferencesGitRepo(false), repo.GetCodeOwnersErrors)
+			}, repoAssignment(), repoArchivedGuard)
 		})
 
 		// Organizations
@@ -985,6 +1169,8 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				Delete(reqToken(), reqOrgOwnership(), org.Delete)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			m.Get("/repos/accessible", reqToken(), reqOrgOwnership(), org.ListAccessibleRepos)
+			m.Get("/visibility_violations", reqToken(), reqOrgOwnership(), org.ListVisibilityViolations)
 			m.Group("/members", func() {
 				m.Get("", org.ListMembers)
 				m.Combo("/{username}").Get(org.IsMember).
@@ -1014,12 +1200,23 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 				m.Combo("/{id}").Get(org.GetHook).
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
+				m.Post("/{id}/rotate_secret", bind(api.RotateHookSecretOption{}), org.RotateHookSecret)
 			}, reqToken(), reqOrgOwnership(), reqWebhooksEnabled())
+			m.Group("/saved_replies", func() {
+				m.Combo("").Get(org.ListSavedReplies).
+					Post(reqOrgOwnership(), bind(api.CreateSavedReplyOption{}), org.CreateSavedReply)
+				m.Combo("/{id}").Get(org.GetSavedReply).
+					Patch(reqOrgOwnership(), bind(api.EditSavedReplyOption{}), org.EditSavedReply).
+					Delete(reqOrgOwnership(), org.DeleteSavedReply)
+			}, reqToken(), reqOrgMembership())
 		}, orgAssignment(true))
 		m.Group("/teams/{teamid}", func() {
 			m.Combo("").Get(org.GetTeam).
 				Patch(reqOrgOwnership(), bind(api.EditTeamOption{}), org.EditTeam).
 				Delete(reqOrgOwnership(), org.DeleteTeam)
+			m.Combo("/review_hook", reqOrgOwnership()).Get(org.GetTeamReviewHook).
+				Put(bind(api.CreateHookOption{}), org.SetTeamReviewHook).
+				Delete(org.DeleteTeamReviewHook)
 			m.Group("/members", func() {
 				m.Get("", org.GetTeamMembers)
 				m.Combo("/{username}").
@@ -1038,15 +1235,39 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 		m.Group("/admin", func() {
 			m.Group("/cron", func() {
 				m.Get("", admin.ListCronTasks)
+				m.Post("/delete-inactive-users", admin.DeleteInactiveUsers)
 				m.Post("/{task}", admin.PostCronTask)
 			})
+			m.Group("/identity-sources", func() {
+				m.Get("", admin.ListIdentitySources)
+				m.Post("", bind(api.CreateIdentitySourceOption{}), admin.CreateIdentitySource)
+				m.Combo("/{id}").Get(admin.GetIdentitySource).
+					Patch(bind(api.EditIdentitySourceOption{}), admin.EditIdentitySource).
+					Delete(admin.DeleteIdentitySource)
+			})
+			m.Group("/git-config", func() {
+				m.Get("", admin.GetGitConfigTemplate)
+				m.Post("/sync", admin.SyncGitConfig)
+			})
+			m.Combo("/notices").Get(admin.ListNotices).
+				Delete(bind(api.DeleteNoticesOption{}), admin.DeleteNotices)
 			m.Get("/orgs", admin.GetAllOrgs)
+			m.Group("/repo-backups", func() {
+				m.Combo("").Get(admin.ListRepoBackups).
+					Post(bind(api.CreateRepoBackupOption{}), admin.CreateRepoBackup)
+				m.Delete("/{id}", admin.DeleteRepoBackup)
+				m.Post("/{id}/retry", admin.RetryRepoBackup)
+			})
+			m.Get("/stats", admin.GetStatistics)
+			m.Post("/stats/refresh", admin.RefreshStatistics)
 			m.Group("/users", func() {
 				m.Get("", admin.GetAllUsers)
 				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
+				m.Post("/bulk", bind(api.CreateUsersOption{}), admin.CreateUsers)
 				m.Group("/{username}", func() {
 					m.Combo("").Patch(bind(api.EditUserOption{}), admin.EditUser).
 						Delete(admin.DeleteUser)
+					m.Post("/anonymize", admin.AnonymizeUser)
 					m.Group("/keys", func() {
 						m.Post("", bind(api.CreateKeyOption{}), admin.CreatePublicKey)
 						m.Delete("/{id}", admin.DeleteUserPublicKey)
@@ -1056,17 +1277,25 @@ func Routes(sessioner func(http.Handler) http.Handler) *web.Route {
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 				})
 			})
+			m.Combo("/tokens/unused").Get(admin.ListUnusedAccessTokens).
+				Delete(admin.DeleteUnusedAccessTokens)
+			m.Group("/topics/{topic}", func() {
+				m.Patch("", bind(api.RenameTopicOption{}), admin.RenameTopic)
+				m.Post("/merge", bind(api.MergeTopicOption{}), admin.MergeTopic)
+			})
 			m.Group("/unadopted", func() {
 				m.Get("", admin.ListUnadoptedRepositories)
 				m.Post("/{username}/{reponame}", admin.AdoptRepository)
 				m.Delete("/{username}/{reponame}", admin.DeleteUnadoptedRepository)
 			})
+			m.Post("/hooks/{id}/rotate_secret", reqWebhooksEnabled(), bind(api.RotateHookSecretOption{}), admin.RotateHookSecret)
 		}, reqToken(), reqSiteAdmin())
 
 		m.Group("/topics", func() {
 			m.Get("/search", repo.TopicSearch)
+			m.Get("/{topic}/repos", repo.ListTopicRepos)
 		})
-	}, sudo())
+	}, sudo(), deployKeyGuard)
 
 	return m
 }
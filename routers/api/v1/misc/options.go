@@ -0,0 +1,79 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package misc
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// ListRepoInitOptions lists the names of the repository init files of a type
+func ListRepoInitOptions(ctx *context.APIContext) {
+	// swagger:operation GET /options/{type} miscellaneous listRepoInitOptions
+	// ---
+	// summary: List the gitignores, licenses, readmes or label templates which can be used to create a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: path
+	//   description: type of the init files
+	//   type: string
+	//   enum: [gitignore, license, readme, label]
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StringSlice"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	names := models.RepoInitFileNames(ctx.Params("type"))
+	if names == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, names)
+}
+
+// GetLicenseTemplate returns the text of a license which can be used to create a repository
+func GetLicenseTemplate(ctx *context.APIContext) {
+	// swagger:operation GET /options/license/{name} miscellaneous getLicenseTemplate
+	// ---
+	// summary: Get the text of a license which can be used to create a repository
+	// produces:
+	// - text/plain
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the license, case-insensitive
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: "the text of the license"
+	//     schema:
+	//       type: string
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	name, err := models.ResolveRepoInitFileName("license", ctx.Params("name"))
+	if err != nil {
+		if models.IsErrInitFileNotExist(err) {
+			ctx.Error(http.StatusNotFound, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ResolveRepoInitFileName", err)
+		}
+		return
+	}
+
+	data, err := models.GetRepoInitFile("license", name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoInitFile", err)
+		return
+	}
+	ctx.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	_, _ = ctx.Resp.Write(data)
+}
@@ -201,3 +201,44 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// RotateHookSecret replaces the secret of a hook in an organization
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/{id}/rotate_secret organization orgRotateHookSecret
+	// ---
+	// summary: Replace the secret of a hook with a new random one
+	// description: The new secret is only returned in this response.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecret"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	hook, err := utils.GetOrgHook(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.RotateHookSecret(ctx, web.GetForm(ctx).(*api.RotateHookSecretOption), hook)
+}
@@ -98,6 +98,7 @@ func CreateLabel(ctx *context.APIContext) {
 		Color:       form.Color,
 		OrgID:       ctx.Org.Organization.ID,
 		Description: form.Description,
+		Exclusive:   form.Exclusive,
 	}
 	if err := models.NewLabel(label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "NewLabel", err)
@@ -209,6 +210,9 @@ func EditLabel(ctx *context.APIContext) {
 	if form.Description != nil {
 		label.Description = *form.Description
 	}
+	if form.Exclusive != nil {
+		label.Exclusive = *form.Exclusive
+	}
 	if err := models.UpdateLabel(label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
 		return
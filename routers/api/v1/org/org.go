@@ -266,6 +266,7 @@ func Create(ctx *context.APIContext) {
 		if models.IsErrUserAlreadyExist(err) ||
 			models.IsErrNameReserved(err) ||
 			models.IsErrNameCharsNotAllowed(err) ||
+			models.IsErrNameTooLong(err) ||
 			models.IsErrNamePatternNotAllowed(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
@@ -336,9 +337,12 @@ func Edit(ctx *context.APIContext) {
 	if form.RepoAdminChangeTeamAccess != nil {
 		org.RepoAdminChangeTeamAccess = *form.RepoAdminChangeTeamAccess
 	}
+	if form.AllowPublicRepos != nil {
+		org.RequirePrivateRepos = !*form.AllowPublicRepos
+	}
 	if err := models.UpdateUserCols(org,
 		"full_name", "description", "website", "location",
-		"visibility", "repo_admin_change_team_access",
+		"visibility", "repo_admin_change_team_access", "require_private_repos",
 	); err != nil {
 		ctx.Error(http.StatusInternalServerError, "EditOrganization", err)
 		return
@@ -347,6 +351,153 @@ func Edit(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, convert.ToOrganization(org))
 }
 
+// ListVisibilityViolations lists the public repositories of an organization which requires private repositories
+func ListVisibilityViolations(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/visibility_violations organization orgListVisibilityViolations
+	// ---
+	// summary: List the public repositories of an organization which doesn't allow public repositories
+	// description: Public repositories which existed before the organization required private repositories are
+	//   kept public, they are listed to be reviewed. The list is empty if the organization allows public repositories.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	org := ctx.Org.Organization
+	apiRepos := make([]*api.Repository, 0, 10)
+	if !org.RequirePrivateRepos {
+		ctx.SetTotalCountHeader(0)
+		ctx.JSON(http.StatusOK, &apiRepos)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	repos, count, err := models.GetPublicOrgRepositories(org.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPublicOrgRepositories", err)
+		return
+	}
+	for _, repo := range repos {
+		repo.Owner = org
+		apiRepos = append(apiRepos, convert.ToRepo(repo, models.AccessModeOwner))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiRepos)
+}
+
+// ListAccessibleRepos lists the repositories of an organization a team or a user has access to, and how
+func ListAccessibleRepos(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/repos/accessible organization orgListAccessibleRepos
+	// ---
+	// summary: List the repositories of an organization a team or a user has access to
+	// description: Each repository is listed with the access of the team or the user, and the teams and the
+	//   collaboration giving it. Exactly one of team and user must be given.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: team
+	//   in: query
+	//   description: name of the team of the organization
+	//   type: string
+	// - name: user
+	//   in: query
+	//   description: username of the user
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AccessibleRepositoryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	org := ctx.Org.Organization
+	teamName, username := ctx.FormTrim("team"), ctx.FormTrim("user")
+	if (teamName == "") == (username == "") {
+		ctx.Error(http.StatusUnprocessableEntity, "", "exactly one of team and user must be given")
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	var (
+		repos []*models.AccessibleRepo
+		count int64
+	)
+	if teamName != "" {
+		team, err := models.GetTeam(org.ID, teamName)
+		if err != nil {
+			if models.IsErrTeamNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetTeam", err)
+			}
+			return
+		}
+		repos, count, err = models.GetTeamAccessibleRepos(team, listOptions)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetTeamAccessibleRepos", err)
+			return
+		}
+	} else {
+		u, err := models.GetUserByName(username)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		repos, count, err = models.GetUserAccessibleOrgRepos(org.ID, u.ID, listOptions)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserAccessibleOrgRepos", err)
+			return
+		}
+	}
+
+	apiRepos := make([]*api.AccessibleRepository, 0, len(repos))
+	for _, repo := range repos {
+		repo.Owner = org
+		apiRepos = append(apiRepos, convert.ToAccessibleRepo(repo))
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiRepos)
+}
+
 //Delete an organization
 func Delete(ctx *context.APIContext) {
 	// swagger:operation DELETE /orgs/{org} organization orgDelete
@@ -0,0 +1,159 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListSavedReplies lists the saved replies of an organization
+func ListSavedReplies(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/saved_replies organization orgListSavedReplies
+	// ---
+	// summary: List the saved replies shared with the members of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReplyList"
+
+	utils.ListSavedReplies(ctx, ctx.Org.Organization.ID)
+}
+
+// GetSavedReply gets a saved reply of an organization
+func GetSavedReply(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/saved_replies/{id} organization orgGetSavedReply
+	// ---
+	// summary: Get a saved reply of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	reply, err := utils.GetSavedReply(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToSavedReply(reply))
+}
+
+// CreateSavedReply creates a saved reply for an organization
+func CreateSavedReply(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/saved_replies organization orgCreateSavedReply
+	// ---
+	// summary: Create a saved reply for an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateSavedReplyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/SavedReply"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateSavedReply(ctx, ctx.Org.Organization.ID, web.GetForm(ctx).(*api.CreateSavedReplyOption))
+}
+
+// EditSavedReply edits a saved reply of an organization
+func EditSavedReply(ctx *context.APIContext) {
+	// swagger:operation PATCH /orgs/{org}/saved_replies/{id} organization orgEditSavedReply
+	// ---
+	// summary: Edit a saved reply of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditSavedReplyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditSavedReply(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"), web.GetForm(ctx).(*api.EditSavedReplyOption))
+}
+
+// DeleteSavedReply deletes a saved reply of an organization
+func DeleteSavedReply(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/saved_replies/{id} organization orgDeleteSavedReply
+	// ---
+	// summary: Delete a saved reply of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteSavedReply(ctx, ctx.Org.Organization.ID, ctx.ParamsInt64(":id"))
+}
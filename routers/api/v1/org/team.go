@@ -6,7 +6,9 @@
 package org
 
 import (
+	"fmt"
 	"net/http"
+	"sort"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -170,17 +172,14 @@ func CreateTeam(ctx *context.APIContext) {
 		IncludesAllRepositories: form.IncludesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
 		Authorize:               models.ParseAccessMode(form.Permission),
+		ReviewNotification:      form.ReviewNotification,
 	}
 
-	unitTypes := models.FindUnitTypes(form.Units...)
-
 	if team.Authorize < models.AccessModeOwner {
-		var units = make([]*models.TeamUnit, 0, len(form.Units))
-		for _, tp := range unitTypes {
-			units = append(units, &models.TeamUnit{
-				OrgID: ctx.Org.Organization.ID,
-				Type:  tp,
-			})
+		units, err := teamUnitsFromOptions(ctx.Org.Organization.ID, form.Units, form.UnitsMap)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
 		}
 		team.Units = units
 	}
@@ -197,6 +196,41 @@ func CreateTeam(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToTeam(team))
 }
 
+// teamUnitsFromOptions returns the team units enabled by the units and the units map of the options,
+// the units map may only override the access mode of units with read or write access
+func teamUnitsFromOptions(orgID int64, unitNames []string, unitsMap map[string]string) ([]*models.TeamUnit, error) {
+	units := make([]*models.TeamUnit, 0, len(unitNames)+len(unitsMap))
+	unitMap := make(map[models.UnitType]*models.TeamUnit, cap(units))
+	for _, tp := range models.FindUnitTypes(unitNames...) {
+		if _, ok := unitMap[tp]; !ok {
+			unitMap[tp] = &models.TeamUnit{OrgID: orgID, Type: tp}
+			units = append(units, unitMap[tp])
+		}
+	}
+	names := make([]string, 0, len(unitsMap))
+	for name := range unitsMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		permission := unitsMap[name]
+		unitTypes := models.FindUnitTypes(name)
+		if len(unitTypes) == 0 {
+			return nil, fmt.Errorf("unknown unit: %s", name)
+		}
+		if permission != "read" && permission != "write" {
+			return nil, fmt.Errorf("invalid permission of unit %s: %s", name, permission)
+		}
+		tp := unitTypes[0]
+		if _, ok := unitMap[tp]; !ok {
+			unitMap[tp] = &models.TeamUnit{OrgID: orgID, Type: tp}
+			units = append(units, unitMap[tp])
+		}
+		unitMap[tp].AccessMode = models.ParseAccessMode(permission)
+	}
+	return units, nil
+}
+
 // EditTeam api for edit a team
 func EditTeam(ctx *context.APIContext) {
 	// swagger:operation PATCH /teams/{id} organization orgEditTeam
@@ -219,6 +253,8 @@ func EditTeam(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Team"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditTeamOption)
 
@@ -240,6 +276,14 @@ func EditTeam(ctx *context.APIContext) {
 		team.Description = *form.Description
 	}
 
+	if form.ReviewNotification != nil {
+		if !models.IsValidTeamReviewNotification(*form.ReviewNotification) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("Invalid review notification: %s", *form.ReviewNotification))
+			return
+		}
+		team.ReviewNotification = *form.ReviewNotification
+	}
+
 	isAuthChanged := false
 	isIncludeAllChanged := false
 	if !team.IsOwnerTeam() && len(form.Permission) != 0 {
@@ -258,14 +302,11 @@ func EditTeam(ctx *context.APIContext) {
 	}
 
 	if team.Authorize < models.AccessModeOwner {
-		if len(form.Units) > 0 {
-			var units = make([]*models.TeamUnit, 0, len(form.Units))
-			unitTypes := models.FindUnitTypes(form.Units...)
-			for _, tp := range unitTypes {
-				units = append(units, &models.TeamUnit{
-					OrgID: ctx.Org.Team.OrgID,
-					Type:  tp,
-				})
+		if len(form.Units) > 0 || len(form.UnitsMap) > 0 {
+			units, err := teamUnitsFromOptions(ctx.Org.Team.OrgID, form.Units, form.UnitsMap)
+			if err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+				return
 			}
 			team.Units = units
 		}
@@ -704,3 +745,117 @@ func SearchTeam(ctx *context.APIContext) {
 	})
 
 }
+
+// GetTeamReviewHook api for get the review request hook of a team
+func GetTeamReviewHook(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/review_hook organization orgGetTeamReviewHook
+	// ---
+	// summary: Get the hook called when a team is requested to review a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Hook"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	org, err := models.GetUserByID(ctx.Org.Team.OrgID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+		return
+	}
+
+	hook, err := models.GetWebhookByTeamID(ctx.Org.Team.ID)
+	if err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetWebhookByTeamID", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToHook(org.HomeLink(), hook))
+}
+
+// SetTeamReviewHook api for set the review request hook of a team
+func SetTeamReviewHook(ctx *context.APIContext) {
+	// swagger:operation PUT /teams/{id}/review_hook organization orgSetTeamReviewHook
+	// ---
+	// summary: Set the hook called when a team is requested to review a pull request
+	// description: The hook replaces any existing review hook of the team. It only receives
+	//   pull request review requests, so the events and branch filter options are ignored.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateHookOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Hook"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateHookOption)
+	if !utils.CheckCreateHookOption(ctx, form) {
+		return
+	}
+
+	org, err := models.GetUserByID(ctx.Org.Team.OrgID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserByID", err)
+		return
+	}
+
+	utils.SetTeamHook(ctx, form, org.HomeLink())
+}
+
+// DeleteTeamReviewHook api for delete the review request hook of a team
+func DeleteTeamReviewHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /teams/{id}/review_hook organization orgDeleteTeamReviewHook
+	// ---
+	// summary: Delete the hook called when a team is requested to review a pull request
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := models.GetWebhookByTeamID(ctx.Org.Team.ID)
+	if err == nil {
+		err = models.DeleteWebhookByTeamID(ctx.Org.Team.ID, hook.ID)
+	}
+	if err != nil {
+		if models.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteWebhookByTeamID", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,110 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package v1
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/ratelimit"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// rateLimitBucket returns the key of the bucket of the request and whether it is made by an anonymous client.
+// Requests with an access token or a deploy key token share a bucket per token, other authenticated requests
+// a bucket per user and anonymous requests a bucket per IP.
+func rateLimitBucket(ctx *context.APIContext, ip string) (string, bool) {
+	if key, ok := ctx.Data["DeployKey"].(*models.DeployKey); ok {
+		return fmt.Sprintf("deploy_key:%d", key.ID), false
+	}
+	if tokenID, ok := ctx.Data["ApiTokenID"].(int64); ok {
+		return fmt.Sprintf("token:%d", tokenID), false
+	}
+	if ctx.IsSigned {
+		return fmt.Sprintf("user:%d", ctx.User.ID), false
+	}
+	return "ip:" + ip, true
+}
+
+// isRateLimitExempt checks if the request is made by a user or from an IP without limits
+func isRateLimitExempt(ctx *context.APIContext, ip string) bool {
+	if ctx.IsSigned && util.IsStringInSlice(ctx.User.LowerName, setting.APIRateLimit.ExemptUsers) {
+		return true
+	}
+	if pip := net.ParseIP(ip); pip != nil {
+		for _, block := range setting.APIRateLimit.ExemptIPRanges {
+			if block.Contains(pip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rateLimit limits the requests per token, user or IP with separate budgets for reading and writing
+// and adds the X-RateLimit headers to the responses
+func rateLimit() func(ctx *context.APIContext) {
+	conn := cache.GetCache()
+	if !setting.APIRateLimit.Enabled || conn == nil {
+		if setting.APIRateLimit.Enabled {
+			log.Warn("API rate limiting is disabled as it needs the cache to be enabled")
+		}
+		return func(*context.APIContext) {}
+	}
+	limiter := ratelimit.NewLimiter(conn, "api_rate_limit:")
+
+	return func(ctx *context.APIContext) {
+		ip, _, err := net.SplitHostPort(ctx.RemoteAddr())
+		if err != nil {
+			ip = ctx.RemoteAddr()
+		}
+		if isRateLimitExempt(ctx, ip) {
+			return
+		}
+
+		key, anonymous := rateLimitBucket(ctx, ip)
+		var limit int
+		switch ctx.Req.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			key += ":read"
+			limit = setting.APIRateLimit.ReadLimit
+			if anonymous {
+				limit = setting.APIRateLimit.AnonymousReadLimit
+			}
+		default:
+			key += ":write"
+			limit = setting.APIRateLimit.WriteLimit
+			if anonymous {
+				limit = setting.APIRateLimit.AnonymousWriteLimit
+			}
+		}
+		if limit <= 0 {
+			return
+		}
+
+		res, err := limiter.Take(key, limit, setting.APIRateLimit.Period)
+		if err != nil {
+			// don't refuse requests because of an unavailable cache
+			log.Error("Unable to take request from rate limit bucket %s: %v", key, err)
+			return
+		}
+		header := ctx.Resp.Header()
+		header.Set("X-RateLimit-Limit", strconv.Itoa(res.Limit))
+		header.Set("X-RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		header.Set("X-RateLimit-Reset", strconv.FormatInt(res.Reset.Unix(), 10))
+		if !res.Allowed {
+			header.Set("Retry-After", strconv.FormatInt(int64(math.Ceil(res.RetryAfter.Seconds())), 10))
+			ctx.Error(http.StatusTooManyRequests, "", "API rate limit exceeded")
+		}
+	}
+}
@@ -9,6 +9,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -496,10 +499,13 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: form.BlockOnOfficialReviewRequests,
 		DismissStaleApprovals:         form.DismissStaleApprovals,
+		DismissOnlyOnContentChange:    form.DismissOnlyOnContentChange,
+		RequireCodeOwnerApproval:      form.RequireCodeOwnerApproval,
 		RequireSignedCommits:          form.RequireSignedCommits,
 		ProtectedFilePatterns:         form.ProtectedFilePatterns,
 		UnprotectedFilePatterns:       form.UnprotectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		AutoUpdateOnMerge:             form.AutoUpdateOnMerge,
 	}
 
 	err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
@@ -636,6 +642,14 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.DismissStaleApprovals = *form.DismissStaleApprovals
 	}
 
+	if form.DismissOnlyOnContentChange != nil {
+		protectBranch.DismissOnlyOnContentChange = *form.DismissOnlyOnContentChange
+	}
+
+	if form.RequireCodeOwnerApproval != nil {
+		protectBranch.RequireCodeOwnerApproval = *form.RequireCodeOwnerApproval
+	}
+
 	if form.RequireSignedCommits != nil {
 		protectBranch.RequireSignedCommits = *form.RequireSignedCommits
 	}
@@ -652,6 +666,10 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
 
+	if form.AutoUpdateOnMerge != nil {
+		protectBranch.AutoUpdateOnMerge = *form.AutoUpdateOnMerge
+	}
+
 	var whitelistUsers []int64
 	if form.PushWhitelistUsernames != nil {
 		whitelistUsers, err = models.GetUserIDsByNames(form.PushWhitelistUsernames, false)
@@ -818,3 +836,199 @@ func DeleteBranchProtection(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// ListStaleBranches lists the stale branches of a repository
+func ListStaleBranches(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/branches/stale repository repoListStaleBranches
+	// ---
+	// summary: List the branches of a repository which are merged into the default branch or were not updated for a while
+	// description: The default branch, protected branches and branches of open pull requests are never listed.
+	//   If both merged and older_than are given, branches have to meet both.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: merged
+	//   in: query
+	//   description: only list branches merged into the default branch
+	//   type: boolean
+	// - name: older_than
+	//   in: query
+	//   description: only list branches whose last commit is older, e.g. `90d` or `48h`
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StaleBranchList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := repo_service.StaleBranchOptions{
+		Merged: ctx.FormBool("merged"),
+	}
+	if olderThan := ctx.FormString("older_than"); olderThan != "" {
+		var err error
+		if opts.OlderThan, err = parseBranchAge(olderThan); err != nil || opts.OlderThan <= 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid older_than: %s", olderThan))
+			return
+		}
+	}
+	if !opts.Merged && opts.OlderThan == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "merged or older_than is required")
+		return
+	}
+
+	branches, err := repo_service.FindStaleBranches(ctx.Repo.Repository, ctx.Repo.GitRepo, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindStaleBranches", err)
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	start, end := listOptions.GetStartEnd()
+	if start > len(branches) {
+		start = len(branches)
+	}
+	if end > len(branches) {
+		end = len(branches)
+	}
+
+	apiBranches := make([]*api.StaleBranch, 0, end-start)
+	for _, branch := range branches[start:end] {
+		apiBranches = append(apiBranches, &api.StaleBranch{
+			Name:   branch.Name,
+			Commit: convert.ToPayloadCommit(ctx.Repo.Repository, branch.Commit),
+			Merged: branch.Merged,
+		})
+	}
+
+	ctx.SetLinkHeader(len(branches), listOptions.PageSize)
+	ctx.SetTotalCountHeader(int64(len(branches)))
+	ctx.JSON(http.StatusOK, &apiBranches)
+}
+
+// parseBranchAge parses a duration which may also be given in days, e.g. 90d
+func parseBranchAge(s string) (time.Duration, error) {
+	if days := strings.TrimSuffix(s, "d"); days != s {
+		n, err := strconv.Atoi(days)
+		return time.Duration(n) * 24 * time.Hour, err
+	}
+	return time.ParseDuration(s)
+}
+
+// GetStaleBranchCleanup gets the scheduled deletion of the stale branches of a repository
+func GetStaleBranchCleanup(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stale_branch_cleanup repository repoGetStaleBranchCleanup
+	// ---
+	// summary: Get the scheduled deletion of the stale branches of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StaleBranchCleanup"
+
+	cleanup, err := models.GetStaleBranchCleanup(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStaleBranchCleanup", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStaleBranchCleanup(cleanup))
+}
+
+// EditStaleBranchCleanup edits the scheduled deletion of the stale branches of a repository
+func EditStaleBranchCleanup(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/stale_branch_cleanup repository repoEditStaleBranchCleanup
+	// ---
+	// summary: Edit the scheduled deletion of the stale branches of a repository
+	// description: Branches are deleted in the name of the user who edited the cleanup last and can be restored
+	//   until deleted branches are cleaned up. Enabling the cleanup makes its next run a dry run unless dry_run is set to false.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditStaleBranchCleanupOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StaleBranchCleanup"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditStaleBranchCleanupOption)
+	cleanup, err := models.GetStaleBranchCleanup(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetStaleBranchCleanup", err)
+		return
+	}
+
+	if form.Enabled != nil {
+		if *form.Enabled && !cleanup.Enabled {
+			cleanup.DryRun = true
+		}
+		cleanup.Enabled = *form.Enabled
+	}
+	if form.Merged != nil {
+		cleanup.Merged = *form.Merged
+	}
+	if form.OlderThanDays != nil {
+		if *form.OlderThanDays < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "older_than_days must not be negative")
+			return
+		}
+		cleanup.OlderThanDays = *form.OlderThanDays
+	}
+	if form.DryRun != nil {
+		cleanup.DryRun = *form.DryRun
+	}
+	if cleanup.Enabled && !cleanup.HasCriteria() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "merged or older_than_days is required")
+		return
+	}
+	cleanup.DoerID = ctx.User.ID
+
+	if err := models.UpdateStaleBranchCleanup(cleanup); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateStaleBranchCleanup", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStaleBranchCleanup(cleanup))
+}
@@ -0,0 +1,171 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/repofiles"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// CherryPickCommit applies the changes of a commit onto a branch
+func CherryPickCommit(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits/{sha}/cherry-pick repository repoCherryPickCommit
+	// ---
+	// summary: Cherry-pick a commit onto a branch
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: the commit to cherry-pick
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyCommitOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Commit"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/ApplyCommitConflict"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	applyCommit(ctx, false)
+}
+
+// RevertCommit applies the reverse of the changes of a commit onto a branch
+func RevertCommit(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/commits/{sha}/revert repository repoRevertCommit
+	// ---
+	// summary: Revert a commit on a branch
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: the commit to revert
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ApplyCommitOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Commit"
+	//   "403":
+	//     "$ref": "#/responses/error"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/ApplyCommitConflict"
+	//   "422":
+	//     "$ref": "#/responses/error"
+
+	applyCommit(ctx, true)
+}
+
+func applyCommit(ctx *context.APIContext, revert bool) {
+	apiOpts := web.GetForm(ctx).(*api.ApplyCommitOption)
+
+	if !canWriteFiles(ctx.Repo) {
+		ctx.Error(http.StatusForbidden, "ApplyCommit", models.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.User.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "RepoIsEmpty", fmt.Errorf("repo is empty"))
+		return
+	}
+
+	opts := &repofiles.ApplyCommitOptions{
+		CommitID:  ctx.Params(":sha"),
+		Revert:    revert,
+		Message:   apiOpts.Message,
+		OldBranch: apiOpts.BranchName,
+		NewBranch: apiOpts.NewBranchName,
+		Committer: &repofiles.IdentityOptions{
+			Name:  apiOpts.Committer.Name,
+			Email: apiOpts.Committer.Email,
+		},
+		Author: &repofiles.IdentityOptions{
+			Name:  apiOpts.Author.Name,
+			Email: apiOpts.Author.Email,
+		},
+		Dates: &repofiles.CommitDateOptions{
+			Author:    apiOpts.Dates.Author,
+			Committer: apiOpts.Dates.Committer,
+		},
+		Signoff: apiOpts.Signoff,
+	}
+
+	commit, err := repofiles.ApplyCommit(ctx.Repo.Repository, ctx.User, opts)
+	if err != nil {
+		if models.IsErrCommitApplyConflict(err) {
+			ctx.JSON(http.StatusConflict, api.ApplyCommitConflict{
+				Message: err.Error(),
+				Files:   err.(models.ErrCommitApplyConflict).Files,
+			})
+		} else if git.IsErrBranchNotExist(err) || git.IsErrNotExist(err) {
+			ctx.Error(http.StatusNotFound, "ApplyCommit", err)
+		} else if models.IsErrUserCannotCommit(err) || models.IsErrFilePathProtected(err) || git.IsErrPushRejected(err) {
+			ctx.Error(http.StatusForbidden, "ApplyCommit", err)
+		} else if models.IsErrBranchAlreadyExists(err) || models.IsErrCommitApplyMergeCommit(err) || git.IsErrPushOutOfDate(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "ApplyCommit", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ApplyCommit", err)
+		}
+		return
+	}
+
+	apiCommit, err := convert.ToCommit(ctx.Repo.Repository, commit, nil, true)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiCommit)
+}
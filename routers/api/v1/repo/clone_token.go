@@ -0,0 +1,155 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// CreateCloneToken creates a short-lived token to clone a repository over HTTP(S)
+func CreateCloneToken(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/clone-token repository repoCreateCloneToken
+	// ---
+	// summary: Create a short-lived token to clone a repository over HTTP(S)
+	// description: The token authenticates git over HTTP(S) as the user to this repository only, it expires after the time configured by the administrator and can be revoked before. It grants reading, or reading and writing if requested and the user may write to the code of the repository. The clone URL of the response includes the token, it can be used where SSH is blocked instead of storing the password of the user in a remote.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateRepoCloneTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/RepoCloneToken"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if setting.Repository.DisableHTTPGit {
+		ctx.Error(http.StatusForbidden, "", "Git over HTTP(S) is disabled.")
+		return
+	}
+
+	form := web.GetForm(ctx).(*api.CreateRepoCloneTokenOption)
+	mode := models.AccessModeRead
+	if form.Permission == "write" {
+		if !ctx.Repo.CanWrite(models.UnitTypeCode) {
+			ctx.Error(http.StatusForbidden, "", "You may not write to the code of the repository.")
+			return
+		}
+		mode = models.AccessModeWrite
+	}
+
+	token, err := models.NewRepoCloneToken(ctx.Repo.Repository.ID, ctx.User.ID, mode)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "NewRepoCloneToken", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToRepoCloneToken(ctx.Repo.Repository, ctx.User, token))
+}
+
+// ListCloneTokens lists the clone tokens of the user to a repository
+func ListCloneTokens(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/clone-token repository repoListCloneTokens
+	// ---
+	// summary: List the unexpired clone tokens of the authenticated user to a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoCloneTokenList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	tokens, err := models.GetRepoCloneTokens(ctx.Repo.Repository.ID, ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoCloneTokens", err)
+		return
+	}
+	apiTokens := make([]*api.RepoCloneToken, len(tokens))
+	for i := range tokens {
+		apiTokens[i] = convert.ToRepoCloneToken(ctx.Repo.Repository, ctx.User, tokens[i])
+	}
+	ctx.JSON(http.StatusOK, apiTokens)
+}
+
+// DeleteCloneToken revokes a clone token of the user to a repository
+func DeleteCloneToken(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/clone-token/{id} repository repoDeleteCloneToken
+	// ---
+	// summary: Revoke a clone token of the authenticated user to a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the token
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := models.DeleteRepoCloneToken(ctx.Repo.Repository.ID, ctx.User.ID, ctx.ParamsInt64(":id")); err != nil {
+		if models.IsErrRepoCloneTokenNotExist(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "DeleteRepoCloneToken", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,86 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// GetCodeOwnersErrors lists the invalid entries of the CODEOWNERS file of a repository
+func GetCodeOwnersErrors(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/codeowners/errors repository repoGetCodeOwnersErrors
+	// ---
+	// summary: List the invalid entries of the CODEOWNERS file of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeOwnersErrors"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.NotFound()
+		return
+	}
+
+	ref := ctx.FormTrim("ref")
+	if len(ref) == 0 {
+		ref = ctx.Repo.Repository.DefaultBranch
+	}
+	commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		}
+		return
+	}
+
+	codeOwners, err := pull_service.GetCodeOwners(ctx.Repo.Repository, commit)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCodeOwners", err)
+		return
+	}
+	if codeOwners == nil {
+		ctx.NotFound()
+		return
+	}
+
+	apiErrors := make([]*api.CodeOwnersError, 0, len(codeOwners.Errors))
+	for _, codeOwnersErr := range codeOwners.Errors {
+		apiErrors = append(apiErrors, &api.CodeOwnersError{
+			Line:    codeOwnersErr.Line,
+			Column:  codeOwnersErr.Column,
+			Kind:    string(codeOwnersErr.Kind),
+			Source:  codeOwnersErr.Source,
+			Message: codeOwnersErr.Message,
+			Path:    codeOwners.Path,
+		})
+	}
+	ctx.JSON(http.StatusOK, &api.CodeOwnersErrors{Errors: apiErrors})
+}
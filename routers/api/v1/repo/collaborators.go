@@ -7,7 +7,9 @@ package repo
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -155,6 +157,18 @@ func AddCollaborator(ctx *context.APIContext) {
 
 	form := web.GetForm(ctx).(*api.AddCollaboratorOption)
 
+	wikiMode := models.AccessModeNone
+	if form.WikiPermission != nil {
+		switch *form.WikiPermission {
+		case "none":
+		case "write":
+			wikiMode = models.AccessModeWrite
+		default:
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid wiki permission: %s", *form.WikiPermission))
+			return
+		}
+	}
+
 	collaborator, err := models.GetUserByName(ctx.Params(":collaborator"))
 	if err != nil {
 		if models.IsErrUserNotExist(err) {
@@ -182,6 +196,13 @@ func AddCollaborator(ctx *context.APIContext) {
 		}
 	}
 
+	if form.WikiPermission != nil {
+		if err := ctx.Repo.Repository.ChangeCollaborationWikiAccessMode(collaborator.ID, wikiMode); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ChangeCollaborationWikiAccessMode", err)
+			return
+		}
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -231,6 +252,66 @@ func DeleteCollaborator(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// GetRepoPermissions returns the effective permission of a user to a repository
+func GetRepoPermissions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/collaborators/{collaborator}/permission repository repoGetRepoPermissions
+	// ---
+	// summary: Get the effective permission of a user to a repository
+	// description: Repository admins may get the permission of any user, other users only their own.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: collaborator
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoCollaboratorPermission"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !ctx.IsUserRepoAdmin() && !ctx.IsUserSiteAdmin() && ctx.User.LowerName != strings.ToLower(ctx.Params(":collaborator")) {
+		ctx.Error(http.StatusForbidden, "", "only repository admins may get the permission of other users")
+		return
+	}
+
+	u, err := models.GetUserByName(ctx.Params(":collaborator"))
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	perm, err := models.GetUserRepoPermission(ctx.Repo.Repository, u)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return
+	}
+	sources, err := ctx.Repo.Repository.GetUserAccessSources(u)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserAccessSources", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoCollaboratorPermission(ctx.User, u, ctx.Repo.Repository, perm, sources))
+}
+
 // GetReviewers return all users that can be requested to review in this repo
 func GetReviewers(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/reviewers repository repoGetReviewers
@@ -10,6 +10,8 @@ import (
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -77,7 +79,7 @@ func getCommit(ctx *context.APIContext, identifier string) {
 		return
 	}
 
-	json, err := convert.ToCommit(ctx.Repo.Repository, commit, nil)
+	json, err := convert.ToCommit(ctx.Repo.Repository, commit, nil, true)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "toCommit", err)
 		return
@@ -107,6 +109,28 @@ func GetAllCommits(ctx *context.APIContext) {
 	//   in: query
 	//   description: SHA or branch to start listing commits from (usually 'master')
 	//   type: string
+	// - name: author
+	//   in: query
+	//   description: only list the commits authored with this email, or with one of the activated emails of the user with this name
+	//   type: string
+	// - name: path
+	//   in: query
+	//   description: only list the commits touching this path
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: only list the commits authored after this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: until
+	//   in: query
+	//   description: only list the commits authored before this time, in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: stat
+	//   in: query
+	//   description: list the files affected by each commit, the listing is faster without (default true)
+	//   type: boolean
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -122,6 +146,8 @@ func GetAllCommits(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	//   "409":
 	//     "$ref": "#/responses/EmptyRepository"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	if ctx.Repo.Repository.IsEmpty {
 		ctx.JSON(http.StatusConflict, api.APIError{
@@ -147,6 +173,12 @@ func GetAllCommits(ctx *context.APIContext) {
 		listOptions.PageSize = setting.Git.CommitsRangeSize
 	}
 
+	filter, ok := getCommitsFilter(ctx)
+	if !ok {
+		return
+	}
+	stat := ctx.FormString("stat") == "" || ctx.FormBool("stat")
+
 	sha := ctx.FormString("sha")
 
 	var baseCommit *git.Commit
@@ -173,18 +205,18 @@ func GetAllCommits(ctx *context.APIContext) {
 	}
 
 	// Total commit count
-	commitsCountTotal, err := baseCommit.CommitsCount()
+	commitsCountTotal, err := baseCommit.FilteredCommitsCount(filter)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "GetCommitsCount", err)
+		ctx.Error(http.StatusInternalServerError, "FilteredCommitsCount", err)
 		return
 	}
 
 	pageCount := int(math.Ceil(float64(commitsCountTotal) / float64(listOptions.PageSize)))
 
 	// Query commits
-	commits, err := baseCommit.CommitsByRange(listOptions.Page, listOptions.PageSize)
+	commits, err := baseCommit.FilteredCommitsByRange(filter, listOptions.Page, listOptions.PageSize)
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "CommitsByRange", err)
+		ctx.Error(http.StatusInternalServerError, "FilteredCommitsByRange", err)
 		return
 	}
 
@@ -193,7 +225,7 @@ func GetAllCommits(ctx *context.APIContext) {
 	apiCommits := make([]*api.Commit, len(commits))
 	for i, commit := range commits {
 		// Create json struct
-		apiCommits[i], err = convert.ToCommit(ctx.Repo.Repository, commit, userCache)
+		apiCommits[i], err = convert.ToCommit(ctx.Repo.Repository, commit, userCache, stat)
 		if err != nil {
 			ctx.Error(http.StatusInternalServerError, "toCommit", err)
 			return
@@ -214,6 +246,62 @@ func GetAllCommits(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, &apiCommits)
 }
 
+// getCommitsFilter returns the filter of the commits listed by the query, an author without @ is
+// matched against the name of commit authors if no user with this name exists
+func getCommitsFilter(ctx *context.APIContext) (git.CommitsFilter, bool) {
+	filter := git.CommitsFilter{
+		Path: strings.Trim(ctx.FormString("path"), "/"),
+	}
+
+	for _, name := range []string{"since", "until"} {
+		value := ctx.FormString(name)
+		if len(value) == 0 {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("%s is not in RFC 3339 format", name))
+			return filter, false
+		}
+		if name == "since" {
+			filter.Since = t.Format(time.RFC3339)
+		} else {
+			filter.Until = t.Format(time.RFC3339)
+		}
+	}
+
+	author := strings.TrimSpace(ctx.FormString("author"))
+	if len(author) == 0 {
+		return filter, true
+	}
+	if strings.Contains(author, "@") {
+		filter.Authors = []string{"<" + author + ">"}
+		return filter, true
+	}
+
+	user, err := models.GetUserByName(author)
+	if err != nil {
+		if !models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			return filter, false
+		}
+		filter.Authors = []string{author}
+		return filter, true
+	}
+	emails, err := models.GetEmailAddresses(user.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetEmailAddresses", err)
+		return filter, false
+	}
+	filter.Authors = []string{"<" + user.Email + ">"}
+	for _, email := range emails {
+		if email.IsActivated && !strings.EqualFold(email.Email, user.Email) {
+			filter.Authors = append(filter.Authors, "<"+email.Email+">")
+		}
+	}
+	return filter, true
+}
+
 // DownloadCommitDiffOrPatch render a commit's raw diff or patch
 func DownloadCommitDiffOrPatch(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/git/commits/{sha}.{diffType} repository repoDownloadCommitDiffOrPatch
@@ -0,0 +1,256 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+const (
+	// defaultComparePatchLimit is the default maximum size in bytes of the patch of a single file
+	defaultComparePatchLimit = 64 * 1024
+	// maxComparePatchLimit is the maximum size in bytes of the patch of a single file a caller may ask for
+	maxComparePatchLimit = 1024 * 1024
+)
+
+// CompareDiff compares two revisions of a repository or of a repository and one of its forks
+func CompareDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead} repository repoCompareDiff
+	// ---
+	// summary: Get the commits and changed files between two revisions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: revisions to compare in the form `base...head`, the head may be prefixed with
+	//     `owner:` to compare against a fork of the repository
+	//   type: string
+	//   required: true
+	// - name: files
+	//   in: query
+	//   description: comma separated list of paths to limit the comparison to
+	//   type: string
+	// - name: patch
+	//   in: query
+	//   description: include the unified diff of every changed file
+	//   type: boolean
+	// - name: limit
+	//   in: query
+	//   description: maximum size in bytes of the patch of a single file (default 65536, max 1048576)
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Compare"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	infos := strings.SplitN(ctx.Params("*"), "...", 2)
+	if len(infos) != 2 || infos[0] == "" || infos[1] == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "revisions must be given in the form base...head")
+		return
+	}
+	baseRef := infos[0]
+
+	headRepo, headGitRepo, headRef := parseCompareHead(ctx, infos[1])
+	if ctx.Written() {
+		return
+	}
+	if headGitRepo != ctx.Repo.GitRepo {
+		defer headGitRepo.Close()
+	}
+
+	if !isExistingRevision(ctx.Repo.GitRepo, baseRef) || !isExistingRevision(headGitRepo, headRef) {
+		ctx.NotFound()
+		return
+	}
+
+	compareInfo, err := headGitRepo.GetCompareInfo(ctx.Repo.Repository.RepoPath(), baseRef, headRef, false, false)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCompareInfo", err)
+		return
+	}
+
+	var paths []string
+	if files := ctx.FormTrim("files"); files != "" {
+		for _, path := range strings.Split(files, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	stats, err := headGitRepo.GetDiffFileStats(compareInfo.MergeBase, compareInfo.HeadCommitID, paths...)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiffFileStats", err)
+		return
+	}
+
+	limit := ctx.FormInt("limit")
+	if limit <= 0 {
+		limit = defaultComparePatchLimit
+	} else if limit > maxComparePatchLimit {
+		limit = maxComparePatchLimit
+	}
+
+	withPatch := ctx.FormBool("patch")
+	files := make([]*api.CompareFile, 0, len(stats))
+	for i, stat := range stats {
+		file := &api.CompareFile{
+			Filename:         stat.Name,
+			PreviousFilename: stat.OldName,
+			Status:           stat.Status,
+			Additions:        stat.Additions,
+			Deletions:        stat.Deletions,
+			Binary:           stat.IsBinary,
+		}
+		if withPatch {
+			if i >= setting.Git.MaxGitDiffFiles {
+				file.PatchTruncated = true
+			} else {
+				filePaths := []string{stat.Name}
+				if stat.OldName != "" {
+					filePaths = append(filePaths, stat.OldName)
+				}
+				w := &limitedBuffer{limit: limit}
+				if err := headGitRepo.GetDiffForPaths(compareInfo.MergeBase, compareInfo.HeadCommitID, w, filePaths...); err != nil {
+					ctx.Error(http.StatusInternalServerError, "GetDiffForPaths", err)
+					return
+				}
+				file.Patch = w.String()
+				file.PatchTruncated = w.truncated
+			}
+		}
+		files = append(files, file)
+	}
+
+	totalCommits := len(compareInfo.Commits)
+	if len(compareInfo.Commits) > setting.API.MaxResponseItems {
+		compareInfo.Commits = compareInfo.Commits[:setting.API.MaxResponseItems]
+	}
+	userCache := make(map[string]*models.User)
+	commits := make([]*api.Commit, 0, len(compareInfo.Commits))
+	for _, commit := range compareInfo.Commits {
+		apiCommit, err := convert.ToCommit(headRepo, commit, userCache, true)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+			return
+		}
+		commits = append(commits, apiCommit)
+	}
+
+	ctx.JSON(http.StatusOK, &api.Compare{
+		BaseCommitID:      compareInfo.BaseCommitID,
+		HeadCommitID:      compareInfo.HeadCommitID,
+		MergeBaseCommitID: compareInfo.MergeBase,
+		TotalCommits:      totalCommits,
+		Commits:           commits,
+		Files:             files,
+	})
+}
+
+// parseCompareHead returns the repository, its git repository and the revision of the head of a comparison.
+// The head is either a revision of the current repository or `owner:revision` for a fork, in which case
+// the caller must also be able to read the code of the fork.
+func parseCompareHead(ctx *context.APIContext, head string) (*models.Repository, *git.Repository, string) {
+	baseRepo := ctx.Repo.Repository
+
+	headInfos := strings.SplitN(head, ":", 2)
+	if len(headInfos) == 1 || strings.EqualFold(headInfos[0], baseRepo.OwnerName) {
+		return baseRepo, ctx.Repo.GitRepo, headInfos[len(headInfos)-1]
+	}
+
+	headUser, err := models.GetUserByName(headInfos[0])
+	if err != nil {
+		if models.IsErrUserNotExist(err) {
+			ctx.NotFound("GetUserByName")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return nil, nil, ""
+	}
+
+	headRepo, has := models.HasForkedRepo(headUser.ID, baseRepo.ID)
+	if !has && baseRepo.IsFork {
+		if err := baseRepo.GetBaseRepo(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetBaseRepo", err)
+			return nil, nil, ""
+		}
+		if baseRepo.BaseRepo.OwnerID == headUser.ID {
+			headRepo, has = baseRepo.BaseRepo, true
+		}
+	}
+	if !has {
+		ctx.NotFound("HasForkedRepo")
+		return nil, nil, ""
+	}
+
+	perm, err := models.GetUserRepoPermission(headRepo, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return nil, nil, ""
+	}
+	if !perm.CanRead(models.UnitTypeCode) {
+		if log.IsTrace() {
+			log.Trace("Permission Denied: User: %-v cannot read code in Repo: %-v\nUser in headRepo has Permissions: %-+v",
+				ctx.User,
+				headRepo,
+				perm)
+		}
+		ctx.NotFound("Can't read headRepo UnitTypeCode")
+		return nil, nil, ""
+	}
+
+	headGitRepo, err := git.OpenRepository(headRepo.RepoPath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+		return nil, nil, ""
+	}
+	return headRepo, headGitRepo, headInfos[1]
+}
+
+func isExistingRevision(gitRepo *git.Repository, rev string) bool {
+	return gitRepo.IsBranchExist(rev) || gitRepo.IsTagExist(rev) || gitRepo.IsCommitExist(rev)
+}
+
+// limitedBuffer is a buffer that silently drops everything written beyond its limit
+type limitedBuffer struct {
+	bytes.Buffer
+	limit     int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if remaining := b.limit - b.Len(); len(p) > remaining {
+		b.truncated = true
+		if remaining > 0 {
+			b.Buffer.Write(p[:remaining])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
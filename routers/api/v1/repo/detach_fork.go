@@ -0,0 +1,141 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/task"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// DetachFork detaches a fork from its base repository
+func DetachFork(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/detach-fork repository repoDetachFork
+	// ---
+	// summary: Detach a fork from its base repository
+	// description: Turns a fork into a normal repository. The objects the fork shares with its base repository are copied into the fork first, in the background since this takes long for large repositories. A queued or running detachment is returned instead of starting another one.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/DetachForkOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoDetachFork"
+	//   "202":
+	//     "$ref": "#/responses/RepoDetachFork"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.DetachForkOption)
+	repo := ctx.Repo.Repository
+	if !repo.IsFork {
+		ctx.Error(http.StatusUnprocessableEntity, "", "The repository is not a fork.")
+		return
+	}
+
+	if !form.Force {
+		count, err := models.CountUnmergedPullRequestsFromFork(repo.ID, repo.ForkID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "CountUnmergedPullRequestsFromFork", err)
+			return
+		}
+		if count > 0 {
+			ctx.Error(http.StatusConflict, "", fmt.Sprintf("%d pull requests from the fork to its base repository are open.", count))
+			return
+		}
+	}
+
+	t, err := task.DetachFork(ctx.User, repo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "DetachFork", err)
+		return
+	}
+	detach, err := t.RepoDetachFork()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepoDetachFork", err)
+		return
+	}
+	status := http.StatusAccepted
+	if t.Status == api.TaskStatusFinished || t.Status == api.TaskStatusFailed {
+		status = http.StatusOK
+	}
+	ctx.JSON(status, convert.ToRepoDetachFork(repo, t, detach))
+}
+
+// GetDetachFork gets the status of the detachment of a fork from its base repository
+func GetDetachFork(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/detach-fork/{task_id} repository repoGetDetachFork
+	// ---
+	// summary: Get the status of the detachment of a fork from its base repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: task_id
+	//   in: path
+	//   description: id of the detachment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoDetachFork"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := models.GetRepoDetachForkTask(ctx.Repo.Repository.ID, ctx.ParamsInt64(":task_id"))
+	if err != nil {
+		if models.IsErrTaskDoesNotExist(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetRepoDetachForkTask", err)
+		return
+	}
+	detach, err := t.RepoDetachFork()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepoDetachFork", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoDetachFork(ctx.Repo.Repository, t, detach))
+}
@@ -0,0 +1,152 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/markup"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+
+	"golang.org/x/net/html"
+)
+
+// PreviewExternalTracker renders a commit message with the settings of an external issue tracker
+func PreviewExternalTracker(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/settings/external_tracker/preview repository repoPreviewExternalTracker
+	// ---
+	// summary: Preview the links to an external issue tracker in a commit message
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ExternalTrackerPreviewOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ExternalTrackerPreview"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ExternalTrackerPreviewOption)
+	repo := ctx.Repo.Repository
+
+	config := &models.ExternalTrackerConfig{
+		ExternalTrackerURL:           repo.HTMLURL(),
+		ExternalTrackerFormat:        form.ExternalTrackerFormat,
+		ExternalTrackerStyle:         form.ExternalTrackerStyle,
+		ExternalTrackerRegexpPattern: form.ExternalTrackerRegexpPattern,
+	}
+	if unit, err := repo.GetUnit(models.UnitTypeExternalTracker); err == nil {
+		saved := unit.ExternalTrackerConfig()
+		config.ExternalTrackerURL = saved.ExternalTrackerURL
+		if len(form.ExternalTrackerFormat) == 0 {
+			config = saved
+		}
+	}
+	if len(config.ExternalTrackerFormat) == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "external_tracker_format is required when no external tracker is configured")
+		return
+	}
+	if err := config.Validate(); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	metas := make(map[string]string)
+	for k, v := range repo.ComposeMetas() {
+		metas[k] = v
+	}
+	metas["format"] = config.ExternalTrackerFormat
+	switch config.ExternalTrackerStyle {
+	case markup.IssueNameStyleAlphanumeric:
+		metas["style"] = markup.IssueNameStyleAlphanumeric
+	case markup.IssueNameStyleRegexp:
+		metas["style"] = markup.IssueNameStyleRegexp
+		metas["regexp"] = config.ExternalTrackerRegexpPattern
+	default:
+		metas["style"] = markup.IssueNameStyleNumeric
+	}
+
+	rendered, err := markup.RenderCommitMessage(&markup.RenderContext{
+		Ctx:       ctx,
+		URLPrefix: repo.Link(),
+		Metas:     metas,
+	}, form.Message)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RenderCommitMessage", err)
+		return
+	}
+
+	links, err := externalTrackerLinks(rendered)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "externalTrackerLinks", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.ExternalTrackerPreview{
+		HTML:  rendered,
+		Links: links,
+	})
+}
+
+// externalTrackerLinks returns the links to the external tracker in rendered html
+func externalTrackerLinks(rendered string) ([]*api.ExternalTrackerPreviewLink, error) {
+	nodes, err := html.ParseFragment(strings.NewReader(rendered), &html.Node{
+		Type: html.ElementNode,
+		Data: "body",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]*api.ExternalTrackerPreviewLink, 0, 5)
+	var visit func(*html.Node)
+	visit = func(node *html.Node) {
+		if node.Type == html.ElementNode && node.Data == "a" {
+			var href, class string
+			for _, attr := range node.Attr {
+				switch attr.Key {
+				case "href":
+					href = attr.Val
+				case "class":
+					class = attr.Val
+				}
+			}
+			if strings.Contains(" "+class+" ", " ref-external-issue ") {
+				var text strings.Builder
+				for child := node.FirstChild; child != nil; child = child.NextSibling {
+					if child.Type == html.TextNode {
+						text.WriteString(child.Data)
+					}
+				}
+				links = append(links, &api.ExternalTrackerPreviewLink{Text: text.String(), URL: href})
+			}
+		}
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			visit(child)
+		}
+	}
+	for _, node := range nodes {
+		visit(node)
+	}
+	return links, nil
+}
@@ -112,9 +112,15 @@ func GetArchive(ctx *context.APIContext) {
 	//   description: the git reference for download with attached archive format (e.g. master.zip)
 	//   type: string
 	//   required: true
+	// - name: path
+	//   in: query
+	//   description: directory to archive instead of the whole repository, not supported by bundles
+	//   type: string
 	// responses:
 	//   200:
 	//     description: success
+	//   "400":
+	//     "$ref": "#/responses/error"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
 
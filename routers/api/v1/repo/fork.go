@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -94,6 +95,8 @@ func CreateFork(ctx *context.APIContext) {
 	//     "$ref": "#/responses/Repository"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     description: The repository with the same name already exists, or the owner already has a fork of the repository.
 	//   "422":
 	//     "$ref": "#/responses/validationError"
 
@@ -123,16 +126,170 @@ func CreateFork(ctx *context.APIContext) {
 		forker = org
 	}
 
+	name := repo.Name
+	if form.Name != nil && len(*form.Name) > 0 {
+		name = *form.Name
+	}
+
 	fork, err := repo_service.ForkRepository(ctx.User, forker, models.ForkRepoOptions{
 		BaseRepo:    repo,
-		Name:        repo.Name,
+		Name:        name,
 		Description: repo.Description,
+
+		OverrideVisibilityPolicy: form.OverrideVisibilityPolicy,
 	})
 	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "ForkRepository", err)
+		switch {
+		case models.IsErrRepoAlreadyExist(err), models.IsErrForkAlreadyExist(err):
+			ctx.Error(http.StatusConflict, "", err)
+		case models.IsErrPublicRepoNotAllowed(err),
+			models.IsErrNameReserved(err),
+			models.IsErrNamePatternNotAllowed(err),
+			models.IsErrNameCharsNotAllowed(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "ForkRepository", err)
+		}
 		return
 	}
 
 	//TODO change back to 201
 	ctx.JSON(http.StatusAccepted, convert.ToRepo(fork, models.AccessModeOwner))
 }
+
+func toForkSyncStatus(status *repo_service.ForkSyncStatus) *api.ForkSyncStatus {
+	return &api.ForkSyncStatus{
+		Branch:         status.Branch,
+		BaseRepository: status.BaseRepo.FullName(),
+		BaseBranch:     status.BaseBranch,
+		Commit:         status.CommitID,
+		Ahead:          status.Ahead,
+		Behind:         status.Behind,
+	}
+}
+
+func forkSyncBranch(ctx *context.APIContext) (string, bool) {
+	if !ctx.Repo.Repository.IsFork {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a fork")
+		return "", false
+	}
+	branch := ctx.FormTrim("branch")
+	if len(branch) == 0 {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+	return branch, true
+}
+
+func handleForkSyncError(ctx *context.APIContext, name string, err error) {
+	if models.IsErrRepoNotExist(err) {
+		ctx.Error(http.StatusNotFound, "", "base repository does not exist")
+	} else if models.IsErrBranchDoesNotExist(err) {
+		ctx.NotFound(err)
+	} else if git.IsErrPushRejected(err) {
+		ctx.Error(http.StatusForbidden, "", err.(*git.ErrPushRejected).Message)
+	} else {
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+// GetForkSyncStatus reports how a branch of a fork relates to its base repository
+func GetForkSyncStatus(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/sync_fork repository repoGetForkSyncStatus
+	// ---
+	// summary: Get how far a branch of a fork is ahead of and behind its base repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the fork
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the fork
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch of the fork, defaults to the default branch
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkSyncStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	branch, ok := forkSyncBranch(ctx)
+	if !ok {
+		return
+	}
+	status, err := repo_service.GetForkSyncStatus(ctx.Repo.Repository, branch)
+	if err != nil {
+		handleForkSyncError(ctx, "GetForkSyncStatus", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toForkSyncStatus(status))
+}
+
+// SyncFork fast-forwards a branch of a fork from its base repository
+func SyncFork(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/sync_fork repository repoSyncFork
+	// ---
+	// summary: Update a branch of a fork from its base repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the fork
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the fork
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch of the fork, defaults to the default branch
+	//   type: string
+	// - name: force
+	//   in: query
+	//   description: reset a diverged branch to the base repository, discarding its own commits. Repository admins only.
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkSyncStatus"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/ForkSyncStatus"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	branch, ok := forkSyncBranch(ctx)
+	if !ok {
+		return
+	}
+	force := ctx.FormBool("force")
+	if force && !ctx.Repo.IsAdmin() {
+		ctx.Error(http.StatusForbidden, "", "only repository admins can force a fork update")
+		return
+	}
+
+	status, err := repo_service.SyncFork(ctx.User, ctx.Repo.Repository, branch, force)
+	if err != nil {
+		if models.IsErrForkHasDiverged(err) {
+			ctx.JSON(http.StatusConflict, toForkSyncStatus(status))
+			return
+		}
+		handleForkSyncError(ctx, "SyncFork", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toForkSyncStatus(status))
+}
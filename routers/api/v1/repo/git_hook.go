@@ -69,8 +69,9 @@ func GetGitHook(ctx *context.APIContext) {
 	//   required: true
 	// - name: id
 	//   in: path
-	//   description: id of the hook to get
+	//   description: name of the hook
 	//   type: string
+	//   enum: [pre-receive, update, post-receive]
 	//   required: true
 	// responses:
 	//   "200":
@@ -111,8 +112,9 @@ func EditGitHook(ctx *context.APIContext) {
 	//   required: true
 	// - name: id
 	//   in: path
-	//   description: id of the hook to get
+	//   description: name of the hook
 	//   type: string
+	//   enum: [pre-receive, update, post-receive]
 	//   required: true
 	// - name: body
 	//   in: body
@@ -165,8 +167,9 @@ func DeleteGitHook(ctx *context.APIContext) {
 	//   required: true
 	// - name: id
 	//   in: path
-	//   description: id of the hook to get
+	//   description: name of the hook
 	//   type: string
+	//   enum: [pre-receive, update, post-receive]
 	//   required: true
 	// responses:
 	//   "204":
@@ -0,0 +1,113 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// GetGitSettings gets the git protocol settings of a repository
+func GetGitSettings(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git_settings repository repoGetGitSettings
+	// ---
+	// summary: Get the git protocol settings in effect for a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoGitSettings"
+
+	settings, err := models.GetRepoGitSettings(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoGitSettings", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoGitSettings(settings))
+}
+
+// EditGitSettings overrides the git protocol settings of the instance for a repository
+func EditGitSettings(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/git_settings repository repoEditGitSettings
+	// ---
+	// summary: Override the git protocol settings of the instance for a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditRepoGitSettingsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoGitSettings"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditRepoGitSettingsOption)
+	settings, err := models.GetRepoGitSettings(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepoGitSettings", err)
+		return
+	}
+
+	if form.ResetToDefaults {
+		settings.DisableDumbHTTP = nil
+		settings.DefaultCloneFilter = nil
+		settings.FullCloneMaxSize = nil
+	}
+	if form.DisableDumbHTTP != nil {
+		settings.DisableDumbHTTP = form.DisableDumbHTTP
+	}
+	if form.DefaultCloneFilter != nil {
+		if *form.DefaultCloneFilter != "" && !git.IsValidFilterSpec(*form.DefaultCloneFilter) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "default_clone_filter is not a supported filter spec")
+			return
+		}
+		settings.DefaultCloneFilter = form.DefaultCloneFilter
+	}
+	if form.FullCloneMaxSize != nil {
+		if *form.FullCloneMaxSize < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "full_clone_max_size must not be negative")
+			return
+		}
+		settings.FullCloneMaxSize = form.FullCloneMaxSize
+	}
+
+	if err := models.UpdateRepoGitSettings(settings); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateRepoGitSettings", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoGitSettings(settings))
+}
@@ -0,0 +1,103 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/task"
+)
+
+// CheckHealth queues a health check of a repository
+func CheckHealth(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/health-check repository repoCheckHealth
+	// ---
+	// summary: Check the health of a repository
+	// description: Runs git fsck and checks the hooks, the git-daemon-export-ok file and the recorded size of the repository in the background. A queued or running health check is returned instead of starting another one. Failed checks are recorded as system notices.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/RepoHealthCheck"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := task.CheckRepositoryHealth(ctx.User, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CheckRepositoryHealth", err)
+		return
+	}
+	check, err := t.RepoHealthCheck()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepoHealthCheck", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToRepoHealthCheck(ctx.Repo.Repository, t, check))
+}
+
+// GetHealthCheck gets the status and the results of a health check of a repository
+func GetHealthCheck(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/health-check/{task_id} repository repoGetHealthCheck
+	// ---
+	// summary: Get the status and the results of a health check of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: task_id
+	//   in: path
+	//   description: id of the health check
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoHealthCheck"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := models.GetRepoHealthCheckTask(ctx.Repo.Repository.ID, ctx.ParamsInt64(":task_id"))
+	if err != nil {
+		if models.IsErrTaskDoesNotExist(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetRepoHealthCheckTask", err)
+		return
+	}
+	check, err := t.RepoHealthCheck()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepoHealthCheck", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoHealthCheck(ctx.Repo.Repository, t, check))
+}
@@ -282,3 +282,49 @@ func DeleteHook(ctx *context.APIContext) {
 	}
 	ctx.Status(http.StatusNoContent)
 }
+
+// RotateHookSecret replaces the secret of a hook in a repository
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/{id}/rotate_secret repository repoRotateHookSecret
+	// ---
+	// summary: Replace the secret of a hook with a new random one
+	// description: The new secret is only returned in this response.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecret"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	hook, err := utils.GetRepoHook(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	utils.RotateHookSecret(ctx, web.GetForm(ctx).(*api.RotateHookSecretOption), hook)
+}
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -41,7 +42,7 @@ func SearchIssues(ctx *context.APIContext) {
 	//   type: string
 	// - name: labels
 	//   in: query
-	//   description: comma separated list of labels. Fetch only issues that have any of this labels. Non existent labels are discarded
+	//   description: comma separated list of labels. Fetch only issues that have any of this labels. Non existent labels are discarded. "scope/*" matches any label of the scope
 	//   type: string
 	// - name: milestones
 	//   in: query
@@ -301,7 +302,7 @@ func ListIssues(ctx *context.APIContext) {
 	//   enum: [closed, open, all]
 	// - name: labels
 	//   in: query
-	//   description: comma separated list of labels. Fetch only issues that have any of this labels. Non existent labels are discarded
+	//   description: comma separated list of labels. Fetch only issues that have any of this labels. Non existent labels are discarded. "scope/*" matches any label of the scope
 	//   type: string
 	// - name: q
 	//   in: query
@@ -328,6 +329,10 @@ func ListIssues(ctx *context.APIContext) {
 	//   type: string
 	//   format: date-time
 	//   required: false
+	// - name: changed_since_visit
+	//   in: query
+	//   description: Only show items updated since the last visit of the signed-in user to the repository, visits less than an hour apart count as one
+	//   type: boolean
 	// - name: created_by
 	//   in: query
 	//   description: Only show items which were created by the the given user
@@ -340,6 +345,11 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: Only show items in which the given user was mentioned
 	//   type: string
+	// - name: sort
+	//   in: query
+	//   description: "Type of sort, defaults to the preference of the user or the repository"
+	//   type: string
+	//   enum: [latest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, priority, nearduedate, farduedate]
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -351,10 +361,72 @@ func ListIssues(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/IssueList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issuesOpt, hasResults := getIssuesOptionsFromQuery(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if ctx.FormBool("changed_since_visit") {
+		if !ctx.IsSigned {
+			ctx.Error(http.StatusUnprocessableEntity, "", "changed_since_visit is only available to signed-in users")
+			return
+		}
+		lastVisit, err := models.GetRepoLastVisit(ctx.User.ID, ctx.Repo.Repository.ID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetRepoLastVisit", err)
+			return
+		}
+		if after := int64(lastVisit) + 1; after > issuesOpt.UpdatedAfterUnix {
+			issuesOpt.UpdatedAfterUnix = after
+		}
+	}
+
+	issuesOpt.SortType = ctx.FormTrim("sort")
+	if len(issuesOpt.SortType) == 0 {
+		issuesOpt.SortType = models.DefaultIssueSortType(ctx.Repo.Repository, ctx.User)
+	} else if !models.IsValidIssueSortType(issuesOpt.SortType) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid sort type: \"%s\"", issuesOpt.SortType))
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+
+	var issues []*models.Issue
+	var filteredCount int64
+	var err error
+
+	// Only fetch the issues if we either don't have a keyword or the search returned issues
+	// This would otherwise return all issues if no issues were found by the search.
+	if hasResults {
+		issuesOpt.ListOptions = listOptions
+		if issues, err = models.Issues(issuesOpt); err != nil {
+			ctx.Error(http.StatusInternalServerError, "Issues", err)
+			return
+		}
+
+		issuesOpt.ListOptions = db.ListOptions{
+			Page: -1,
+		}
+		if filteredCount, err = models.CountIssues(issuesOpt); err != nil {
+			ctx.Error(http.StatusInternalServerError, "CountIssues", err)
+			return
+		}
+	}
+
+	ctx.SetLinkHeader(int(filteredCount), listOptions.PageSize)
+	ctx.SetTotalCountHeader(filteredCount)
+	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+}
+
+// getIssuesOptionsFromQuery parses the filters of a repository's issue list,
+// hasResults is false if the keyword search found no issues and nothing has to be fetched.
+func getIssuesOptionsFromQuery(ctx *context.APIContext) (issuesOpt *models.IssuesOptions, hasResults bool) {
 	before, since, err := utils.GetQueryBeforeSince(ctx)
 	if err != nil {
 		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
-		return
+		return nil, false
 	}
 
 	var isClosed util.OptionalBool
@@ -367,28 +439,29 @@ func ListIssues(ctx *context.APIContext) {
 		isClosed = util.OptionalBoolFalse
 	}
 
-	var issues []*models.Issue
-	var filteredCount int64
-
 	keyword := ctx.FormTrim("q")
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
 	var issueIDs []int64
 	var labelIDs []int64
+	var labelScopes []string
 	if len(keyword) > 0 {
 		issueIDs, err = issue_indexer.SearchIssuesByKeyword([]int64{ctx.Repo.Repository.ID}, keyword)
 		if err != nil {
 			ctx.Error(http.StatusInternalServerError, "SearchIssuesByKeyword", err)
-			return
+			return nil, false
 		}
 	}
 
 	if splitted := strings.Split(ctx.FormString("labels"), ","); len(splitted) > 0 {
-		labelIDs, err = models.GetLabelIDsInRepoByNames(ctx.Repo.Repository.ID, splitted)
+		var labelNames []string
+		// "scope/*" filters by any label of the scope
+		labelNames, labelScopes = models.SplitLabelScopes(splitted)
+		labelIDs, err = models.GetLabelIDsInRepoByNames(ctx.Repo.Repository.ID, labelNames)
 		if err != nil {
 			ctx.Error(http.StatusInternalServerError, "GetLabelIDsInRepoByNames", err)
-			return
+			return nil, false
 		}
 	}
 
@@ -404,7 +477,7 @@ func ListIssues(ctx *context.APIContext) {
 			}
 			if !models.IsErrMilestoneNotExist(err) {
 				ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoIDANDName", err)
-				return
+				return nil, false
 			}
 			id, err := strconv.ParseInt(part[i], 10, 64)
 			if err != nil {
@@ -419,11 +492,10 @@ func ListIssues(ctx *context.APIContext) {
 				continue
 			}
 			ctx.Error(http.StatusInternalServerError, "GetMilestoneByRepoID", err)
+			return nil, false
 		}
 	}
 
-	listOptions := utils.GetListOptions(ctx)
-
 	var isPull util.OptionalBool
 	switch ctx.FormString("type") {
 	case "pulls":
@@ -437,52 +509,31 @@ func ListIssues(ctx *context.APIContext) {
 	// FIXME: we should be more efficient here
 	createdByID := getUserIDForFilter(ctx, "created_by")
 	if ctx.Written() {
-		return
+		return nil, false
 	}
 	assignedByID := getUserIDForFilter(ctx, "assigned_by")
 	if ctx.Written() {
-		return
+		return nil, false
 	}
 	mentionedByID := getUserIDForFilter(ctx, "mentioned_by")
 	if ctx.Written() {
-		return
-	}
-
-	// Only fetch the issues if we either don't have a keyword or the search returned issues
-	// This would otherwise return all issues if no issues were found by the search.
-	if len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0 {
-		issuesOpt := &models.IssuesOptions{
-			ListOptions:       listOptions,
-			RepoIDs:           []int64{ctx.Repo.Repository.ID},
-			IsClosed:          isClosed,
-			IssueIDs:          issueIDs,
-			LabelIDs:          labelIDs,
-			MilestoneIDs:      mileIDs,
-			IsPull:            isPull,
-			UpdatedBeforeUnix: before,
-			UpdatedAfterUnix:  since,
-			PosterID:          createdByID,
-			AssigneeID:        assignedByID,
-			MentionedID:       mentionedByID,
-		}
-
-		if issues, err = models.Issues(issuesOpt); err != nil {
-			ctx.Error(http.StatusInternalServerError, "Issues", err)
-			return
-		}
-
-		issuesOpt.ListOptions = db.ListOptions{
-			Page: -1,
-		}
-		if filteredCount, err = models.CountIssues(issuesOpt); err != nil {
-			ctx.Error(http.StatusInternalServerError, "CountIssues", err)
-			return
-		}
-	}
-
-	ctx.SetLinkHeader(int(filteredCount), listOptions.PageSize)
-	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(issues))
+		return nil, false
+	}
+
+	return &models.IssuesOptions{
+		RepoIDs:           []int64{ctx.Repo.Repository.ID},
+		IsClosed:          isClosed,
+		IssueIDs:          issueIDs,
+		LabelIDs:          labelIDs,
+		LabelScopes:       labelScopes,
+		MilestoneIDs:      mileIDs,
+		IsPull:            isPull,
+		UpdatedBeforeUnix: before,
+		UpdatedAfterUnix:  since,
+		PosterID:          createdByID,
+		AssigneeID:        assignedByID,
+		MentionedID:       mentionedByID,
+	}, len(keyword) == 0 || len(issueIDs) > 0 || len(labelIDs) > 0
 }
 
 func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
@@ -581,6 +632,28 @@ func CreateIssue(ctx *context.APIContext) {
 	//   "422":
 	//     "$ref": "#/responses/validationError"
 	form := web.GetForm(ctx).(*api.CreateIssueOption)
+	var it *api.IssueTemplate
+	if form.Template != "" {
+		var err error
+		it, err = ctx.IssueTemplateFromDefaultBranch(form.Template)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "IssueTemplateFromDefaultBranch", err)
+			return
+		}
+		if it == nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("issue template does not exist: %s", form.Template))
+			return
+		}
+		if it.IsForm() {
+			if err := issue_template.ValidateValues(it, form.Fields); err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "ValidateValues", err)
+				return
+			}
+			form.Body = issue_template.RenderToMarkdown(it, form.Fields)
+		}
+		form.Title = issue_service.TemplateTitle(it, form.Title)
+	}
+
 	var deadlineUnix timeutil.TimeStamp
 	if form.Deadline != nil && ctx.Repo.CanWrite(models.UnitTypeIssues) {
 		deadlineUnix = timeutil.TimeStamp(form.Deadline.Unix())
@@ -634,6 +707,16 @@ func CreateIssue(ctx *context.APIContext) {
 		form.Labels = make([]int64, 0)
 	}
 
+	// the labels and assignees of the template are applied whoever creates the issue
+	var warnings []string
+	if it != nil {
+		form.Labels, assigneeIDs, warnings, err = issue_service.TemplateMetas(ctx.Repo.Repository, it, form.Labels, assigneeIDs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "TemplateMetas", err)
+			return
+		}
+	}
+
 	if err := issue_service.NewIssue(ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs); err != nil {
 		if models.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
@@ -660,7 +743,9 @@ func CreateIssue(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "GetIssueByID", err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(issue))
+	apiIssue := convert.ToAPIIssue(issue)
+	apiIssue.Warnings = warnings
+	ctx.JSON(http.StatusCreated, apiIssue)
 }
 
 // EditIssue modify an issue of a repository
@@ -702,6 +787,8 @@ func EditIssue(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	//   "412":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/error"
 
 	form := web.GetForm(ctx).(*api.EditIssueOption)
 	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
@@ -797,6 +884,15 @@ func EditIssue(ctx *context.APIContext) {
 			} else if pr.HasMerged {
 				ctx.Error(http.StatusPreconditionFailed, "MergedPRState", "cannot change state of this pull request, it was already merged")
 				return
+			} else if issue.IsClosed && api.StateType(*form.State) == api.StateOpen {
+				if err := issue_service.RestoreHeadRef(pr); err != nil {
+					if models.IsErrPullRequestHeadRefMissing(err) {
+						ctx.Error(http.StatusUnprocessableEntity, "RestoreHeadRef", "cannot reopen this pull request, its head branch doesn't exist anymore")
+					} else {
+						ctx.Error(http.StatusInternalServerError, "RestoreHeadRef", err)
+					}
+					return
+				}
 			}
 		}
 		issue.IsClosed = api.StateClosed == api.StateType(*form.State)
@@ -0,0 +1,354 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// issueExportCSVHeader are the columns of an issue export in the csv format
+var issueExportCSVHeader = []string{
+	"number", "type", "title", "state", "author", "labels", "assignees", "milestone",
+	"created_at", "updated_at", "closed_at", "due_date", "comments", "total_tracked_time", "assets", "html_url", "body",
+}
+
+// issueExportWriter writes the issues of an export one after another
+type issueExportWriter interface {
+	Write(issue *api.ExportedIssue) error
+	Close() error
+}
+
+type issueExportJSONWriter struct {
+	w     io.Writer
+	count int
+}
+
+func (w *issueExportJSONWriter) Write(issue *api.ExportedIssue) error {
+	sep := ","
+	if w.count == 0 {
+		sep = "["
+	}
+	w.count++
+	if _, err := io.WriteString(w.w, sep); err != nil {
+		return err
+	}
+	bs, err := json.Marshal(issue)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(bs)
+	return err
+}
+
+func (w *issueExportJSONWriter) Close() error {
+	end := "]"
+	if w.count == 0 {
+		end = "[]"
+	}
+	_, err := io.WriteString(w.w, end)
+	return err
+}
+
+type issueExportCSVWriter struct {
+	w *csv.Writer
+}
+
+func newIssueExportCSVWriter(w io.Writer) (*issueExportCSVWriter, error) {
+	cw := &issueExportCSVWriter{w: csv.NewWriter(w)}
+	return cw, cw.w.Write(issueExportCSVHeader)
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func (w *issueExportCSVWriter) Write(issue *api.ExportedIssue) error {
+	issueType := "issue"
+	if issue.PullRequest != nil {
+		issueType = "pull"
+	}
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	assignees := make([]string, 0, len(issue.Assignees))
+	for _, assignee := range issue.Assignees {
+		assignees = append(assignees, assignee.UserName)
+	}
+	var milestone string
+	if issue.Milestone != nil {
+		milestone = issue.Milestone.Title
+	}
+	var author string
+	if issue.Poster != nil {
+		author = issue.Poster.UserName
+	}
+	assets := make([]string, 0, len(issue.Attachments))
+	for _, attachment := range issue.Attachments {
+		assets = append(assets, attachment.DownloadURL)
+	}
+
+	if err := w.w.Write([]string{
+		strconv.FormatInt(issue.Index, 10),
+		issueType,
+		issue.Title,
+		string(issue.State),
+		author,
+		strings.Join(labels, ","),
+		strings.Join(assignees, ","),
+		milestone,
+		formatExportTime(&issue.Created),
+		formatExportTime(&issue.Updated),
+		formatExportTime(issue.Closed),
+		formatExportTime(issue.Deadline),
+		strconv.Itoa(issue.Comments),
+		strconv.FormatInt(issue.TotalTrackedTime, 10),
+		strings.Join(assets, " "),
+		issue.HTMLURL,
+		issue.Body,
+	}); err != nil {
+		return err
+	}
+	// Don't keep the rows in the buffer of the csv writer, the response is streamed
+	w.w.Flush()
+	return w.w.Error()
+}
+
+func (w *issueExportCSVWriter) Close() error {
+	w.w.Flush()
+	return w.w.Error()
+}
+
+// toExportedIssues converts a page of issues whose attributes have been loaded
+func toExportedIssues(repo *models.Repository, issues models.IssueList, includeComments bool) []*api.ExportedIssue {
+	exported := make([]*api.ExportedIssue, 0, len(issues))
+	for _, issue := range issues {
+		issue.Repo = repo
+		apiIssue := &api.ExportedIssue{
+			Issue:            convert.ToAPIIssue(issue),
+			TotalTrackedTime: issue.TotalTrackedTime,
+			Attachments:      make([]*api.Attachment, 0, len(issue.Attachments)),
+		}
+		// Attachments are only linked, their download requires the same permissions as the repository
+		for _, attachment := range issue.Attachments {
+			apiIssue.Attachments = append(apiIssue.Attachments, convert.ToReleaseAttachment(attachment))
+		}
+		if includeComments {
+			apiIssue.CommentList = make([]*api.Comment, 0, len(issue.Comments))
+			for _, comment := range issue.Comments {
+				comment.Issue = issue
+				apiIssue.CommentList = append(apiIssue.CommentList, convert.ToComment(comment))
+			}
+		}
+		exported = append(exported, apiIssue)
+	}
+	return exported
+}
+
+// loadExportedIssuesPage loads a page of the issues to export with all their attributes
+func loadExportedIssuesPage(opts *models.IssuesOptions, includeComments bool) (models.IssueList, error) {
+	issues, err := models.Issues(opts)
+	if err != nil {
+		return nil, fmt.Errorf("Issues: %v", err)
+	}
+	issueList := models.IssueList(issues)
+	if err := issueList.LoadAttributes(); err != nil {
+		return nil, fmt.Errorf("LoadAttributes: %v", err)
+	}
+	if err := issueList.LoadAttachments(); err != nil {
+		return nil, fmt.Errorf("LoadAttachments: %v", err)
+	}
+	if includeComments {
+		if err := issueList.LoadDiscussComments(); err != nil {
+			return nil, fmt.Errorf("LoadDiscussComments: %v", err)
+		}
+		var comments models.CommentList
+		for _, issue := range issueList {
+			comments = append(comments, issue.Comments...)
+		}
+		if err := comments.LoadPosters(); err != nil {
+			return nil, fmt.Errorf("LoadPosters: %v", err)
+		}
+	}
+	return issueList, nil
+}
+
+// ExportIssues exports all issues of a repository matching the filters
+func ExportIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/export issue issueExportIssues
+	// ---
+	// summary: Export a repository's issues with their labels, assignees, milestone and tracked time
+	// produces:
+	// - application/json
+	// - text/csv
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: format
+	//   in: query
+	//   description: format of the export
+	//   type: string
+	//   enum: [json, csv]
+	// - name: include_comments
+	//   in: query
+	//   description: include the comments of the issues, only supported by the json format
+	//   type: boolean
+	// - name: state
+	//   in: query
+	//   description: whether issue is open or closed
+	//   type: string
+	//   enum: [closed, open, all]
+	// - name: labels
+	//   in: query
+	//   description: comma separated list of labels. Fetch only issues that have any of this labels. Non existent labels are discarded. "scope/*" matches any label of the scope
+	//   type: string
+	// - name: q
+	//   in: query
+	//   description: search string
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: filter by type (issues / pulls) if set
+	//   type: string
+	//   enum: [issues, pulls]
+	// - name: milestones
+	//   in: query
+	//   description: comma separated list of milestone names or ids. It uses names and fall back to ids. Fetch only issues that have any of this milestones. Non existent milestones are discarded
+	//   type: string
+	// - name: since
+	//   in: query
+	//   description: Only show items updated after the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	//   required: false
+	// - name: before
+	//   in: query
+	//   description: Only show items updated before the given time. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	//   required: false
+	// - name: created_by
+	//   in: query
+	//   description: Only show items which were created by the the given user
+	//   type: string
+	// - name: assigned_by
+	//   in: query
+	//   description: Only show items for which the given user is assigned
+	//   type: string
+	// - name: mentioned_by
+	//   in: query
+	//   description: Only show items in which the given user was mentioned
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ExportedIssueList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	format := ctx.FormString("format")
+	if format == "" {
+		format = "json"
+	}
+	if format != "json" && format != "csv" {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unsupported export format: %s", format))
+		return
+	}
+	// Comments can't be represented by the csv format and are omitted there
+	includeComments := format == "json" && ctx.FormBool("include_comments")
+
+	issuesOpt, hasResults := getIssuesOptionsFromQuery(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	// Only export the kinds of issues the user is allowed to read
+	canReadIssues := ctx.Repo.CanRead(models.UnitTypeIssues)
+	canReadPulls := ctx.Repo.Repository.CanEnablePulls() && ctx.Repo.CanRead(models.UnitTypePullRequests)
+	switch {
+	case !canReadIssues && issuesOpt.IsPull.IsFalse(), !canReadPulls && issuesOpt.IsPull.IsTrue():
+		ctx.Error(http.StatusForbidden, "", "user is not allowed to read the requested issues")
+		return
+	case !canReadIssues:
+		issuesOpt.IsPull = util.OptionalBoolTrue
+	case !canReadPulls:
+		issuesOpt.IsPull = util.OptionalBoolFalse
+	}
+
+	filename := fmt.Sprintf("%s-%s-issues.%s", ctx.Repo.Repository.OwnerName, ctx.Repo.Repository.Name, format)
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Resp.Header().Set("Access-Control-Expose-Headers", "Content-Disposition")
+
+	var writer issueExportWriter
+	if format == "csv" {
+		ctx.Resp.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		ctx.Resp.WriteHeader(http.StatusOK)
+		csvWriter, err := newIssueExportCSVWriter(ctx.Resp)
+		if err != nil {
+			log.Error("Unable to write the issue export of %-v: %v", ctx.Repo.Repository, err)
+			return
+		}
+		writer = csvWriter
+	} else {
+		ctx.Resp.Header().Set("Content-Type", "application/json;charset=utf-8")
+		ctx.Resp.WriteHeader(http.StatusOK)
+		writer = &issueExportJSONWriter{w: ctx.Resp}
+	}
+
+	// The response has already been started, so errors can only be logged from here on
+	if hasResults {
+		issuesOpt.SortType = "oldest"
+		issuesOpt.ListOptions = db.ListOptions{PageSize: setting.API.MaxResponseItems}
+		for page := 1; ; page++ {
+			issuesOpt.Page = page
+			issues, err := loadExportedIssuesPage(issuesOpt, includeComments)
+			if err != nil {
+				log.Error("Unable to load the issues to export of %-v: %v", ctx.Repo.Repository, err)
+				return
+			}
+			for _, issue := range toExportedIssues(ctx.Repo.Repository, issues, includeComments) {
+				if err := writer.Write(issue); err != nil {
+					log.Error("Unable to write the issue export of %-v: %v", ctx.Repo.Repository, err)
+					return
+				}
+			}
+			ctx.Resp.Flush()
+			if len(issues) < issuesOpt.PageSize {
+				break
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		log.Error("Unable to write the issue export of %-v: %v", ctx.Repo.Repository, err)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadExportedIssues(t *testing.T, includeComments bool) []*api.ExportedIssue {
+	defer func(enabled bool) {
+		setting.Service.EnableTimetracking = enabled
+	}(setting.Service.EnableTimetracking)
+	setting.Service.EnableTimetracking = true
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	issues, err := loadExportedIssuesPage(&models.IssuesOptions{
+		RepoIDs:  []int64{repo.ID},
+		IsClosed: util.OptionalBoolNone,
+		SortType: "oldest",
+	}, includeComments)
+	assert.NoError(t, err)
+	assert.Len(t, issues, db.GetCount(t, &models.Issue{RepoID: repo.ID}))
+	return toExportedIssues(repo, issues, includeComments)
+}
+
+func TestExportIssuesCSV(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	exported := loadExportedIssues(t, false)
+
+	var buf bytes.Buffer
+	writer, err := newIssueExportCSVWriter(&buf)
+	assert.NoError(t, err)
+	for _, issue := range exported {
+		assert.NoError(t, writer.Write(issue))
+	}
+	assert.NoError(t, writer.Close())
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	assert.NoError(t, err)
+	if assert.Len(t, records, len(exported)+1) {
+		assert.Equal(t, issueExportCSVHeader, records[0])
+		// issue 1 has label 1, is assigned to user1 and user1 has tracked time on it
+		assert.Equal(t, "1", records[1][0])
+		assert.Equal(t, "issue", records[1][1])
+		assert.Equal(t, "label1", records[1][5])
+		assert.Equal(t, "user1", records[1][6])
+		assert.Equal(t, "400", records[1][13])
+	}
+}
+
+func TestExportIssuesJSON(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	exported := loadExportedIssues(t, true)
+
+	var buf bytes.Buffer
+	writer := &issueExportJSONWriter{w: &buf}
+	for _, issue := range exported {
+		assert.NoError(t, writer.Write(issue))
+	}
+	assert.NoError(t, writer.Close())
+
+	var issues []*api.ExportedIssue
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &issues))
+	if assert.Len(t, issues, len(exported)) {
+		assert.EqualValues(t, 1, issues[0].Index)
+		assert.EqualValues(t, 400, issues[0].TotalTrackedTime)
+		assert.NotEmpty(t, issues[0].CommentList)
+		for _, comment := range issues[0].CommentList {
+			assert.NotNil(t, comment.Poster)
+		}
+	}
+
+	// An export without any issue is still a valid list
+	buf.Reset()
+	assert.NoError(t, (&issueExportJSONWriter{w: &buf}).Close())
+	assert.Equal(t, "[]", buf.String())
+}
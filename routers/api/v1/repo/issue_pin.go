@@ -0,0 +1,178 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+)
+
+// PinIssue pins an issue to the top of the issue list of its repository
+func PinIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/pin issue issuePinIssue
+	// ---
+	// summary: Pin an issue or a pull request, up to 3 issues and 3 pull requests can be pinned per repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue to pin
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := prepareIssuePin(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue.Pin(); err != nil {
+		if models.IsErrIssueMaxPinReached(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("at most %d issues and %d pull requests can be pinned", models.MaxPinnedIssues, models.MaxPinnedIssues))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "Pin", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UnpinIssue unpins an issue
+func UnpinIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/unpin issue issueUnpinIssue
+	// ---
+	// summary: Unpin an issue or a pull request
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue to unpin
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	issue := prepareIssuePin(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := issue.Unpin(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "Unpin", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// MoveIssuePin moves a pinned issue to another position
+func MoveIssuePin(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/issues/{index}/pin/{position} issue issueMoveIssuePin
+	// ---
+	// summary: Move a pinned issue or pull request to another position
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pinned issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: position
+	//   in: path
+	//   description: new position of the issue among the pinned issues, starting at 1
+	//   type: integer
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	issue := prepareIssuePin(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	position := int(ctx.ParamsInt64(":position"))
+	if position < 1 || position > models.MaxPinnedIssues {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("position must be between 1 and %d", models.MaxPinnedIssues))
+		return
+	}
+
+	if err := issue.MovePin(position); err != nil {
+		if models.IsErrIssueNotPinned(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the issue is not pinned")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "MovePin", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// prepareIssuePin returns the issue of the request if the doer can change its pin
+func prepareIssuePin(ctx *context.APIContext) *models.Issue {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+
+	if !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "Not repo writer")
+		return nil
+	}
+	return issue
+}
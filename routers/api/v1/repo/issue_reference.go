@@ -0,0 +1,135 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListIssueReferences list the issues, pull requests and commits referencing an issue
+func ListIssueReferences(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/references issue issueListReferences
+	// ---
+	// summary: List the issues, pull requests and commits referencing an issue
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueReferenceList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listIssueReferences(ctx, models.FindIssueReferences)
+}
+
+// ListIssuesReferencedBy list the issues and pull requests referenced by an issue
+func ListIssuesReferencedBy(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/{index}/referencing issue issueListReferencing
+	// ---
+	// summary: List the issues and pull requests referenced by an issue or its comments
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueReferenceList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	listIssueReferences(ctx, models.FindIssuesReferencedBy)
+}
+
+// listIssueReferences writes the references found for the issue of the request. References from or to repositories
+// the user can't read are omitted, or listed as private references if setting.Repository.Issue.PrivateReferenceStubs is set.
+func listIssueReferences(ctx *context.APIContext, find func(*models.Issue) ([]*models.IssueReference, error)) {
+	issue, err := models.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return
+	}
+	if !ctx.Repo.CanReadIssuesOrPulls(issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", errors.New("no permission to read the references of the issue"))
+		return
+	}
+
+	refs, err := find(issue)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindIssueReferences", err)
+		return
+	}
+
+	perms := make(map[int64]models.Permission)
+	apiRefs := make([]*api.IssueReference, 0, len(refs))
+	for _, ref := range refs {
+		perm, ok := perms[ref.Repo.ID]
+		if !ok {
+			if perm, err = models.GetUserRepoPermission(ref.Repo, ctx.User); err != nil {
+				ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+				return
+			}
+			perms[ref.Repo.ID] = perm
+		}
+
+		var canRead bool
+		if ref.Issue != nil {
+			canRead = perm.CanReadIssuesOrPulls(ref.Issue.IsPull)
+		} else {
+			canRead = perm.CanRead(models.UnitTypeCode)
+		}
+		if !canRead && !setting.Repository.Issue.PrivateReferenceStubs {
+			continue
+		}
+		apiRefs = append(apiRefs, convert.ToIssueReference(ref, !canRead))
+	}
+	ctx.JSON(http.StatusOK, apiRefs)
+}
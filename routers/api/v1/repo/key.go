@@ -16,6 +16,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 // appendPrivateInformation appends the owner and key type information to api.PublicKey
@@ -168,6 +169,8 @@ func HandleCheckKeyStringError(ctx *context.APIContext, err error) {
 		ctx.Error(http.StatusUnprocessableEntity, "", "SSH is disabled")
 	} else if models.IsErrKeyUnableVerify(err) {
 		ctx.Error(http.StatusUnprocessableEntity, "", "Unable to verify key content")
+	} else if models.IsErrKeyPolicyViolation(err) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Key is not allowed: %v", err))
 	} else {
 		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid key content: %v", err))
 	}
@@ -226,7 +229,7 @@ func CreateDeployKey(ctx *context.APIContext) {
 		return
 	}
 
-	key, err := models.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, form.ReadOnly)
+	key, err := repo_service.AddDeployKey(ctx.User, ctx.Repo.Repository, form.Title, content, form.ReadOnly, form.Statuses)
 	if err != nil {
 		HandleAddKeyError(ctx, err)
 		return
@@ -265,7 +268,7 @@ func DeleteDeploykey(ctx *context.APIContext) {
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
 
-	if err := models.DeleteDeployKey(ctx.User, ctx.ParamsInt64(":id")); err != nil {
+	if err := repo_service.DeleteDeployKey(ctx.User, ctx.ParamsInt64(":id")); err != nil {
 		if models.IsErrKeyAccessDenied(err) {
 			ctx.Error(http.StatusForbidden, "", "You do not have access to this key")
 		} else {
@@ -17,6 +17,7 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // ListLabels list all the labels of a repository
@@ -160,6 +161,7 @@ func CreateLabel(ctx *context.APIContext) {
 		Color:       form.Color,
 		RepoID:      ctx.Repo.Repository.ID,
 		Description: form.Description,
+		Exclusive:   form.Exclusive,
 	}
 	if err := models.NewLabel(label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "NewLabel", err)
@@ -232,6 +234,9 @@ func EditLabel(ctx *context.APIContext) {
 	if form.Description != nil {
 		label.Description = *form.Description
 	}
+	if form.Exclusive != nil {
+		label.Exclusive = *form.Exclusive
+	}
 	if err := models.UpdateLabel(label); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
 		return
@@ -273,3 +278,84 @@ func DeleteLabel(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// CopyLabels copy the labels of another repository to a repository
+func CopyLabels(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/labels/copy issue issueCopyLabels
+	// ---
+	// summary: Copy the labels of another repository, except the ones whose names are already used
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CopyLabelsOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CopiedLabels"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CopyLabelsOption)
+	source := getCopySource(ctx, form.Source)
+	if ctx.Written() {
+		return
+	}
+
+	created, skipped, err := issue_service.CopyLabels(source, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CopyLabels", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.CopiedLabels{
+		Created: convert.ToLabelList(created, ctx.Repo.Repository, nil),
+		Skipped: convert.ToLabelList(skipped, source, nil),
+	})
+}
+
+// getCopySource returns the repository of the full name whose labels or milestones are copied,
+// the issues or pull requests of which the doer must be able to read
+func getCopySource(ctx *context.APIContext, fullName string) *models.Repository {
+	fields := strings.Split(fullName, "/")
+	if len(fields) != 2 || fields[0] == "" || fields[1] == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "Source", fmt.Errorf("invalid source repository: %s", fullName))
+		return nil
+	}
+
+	source, err := models.GetRepositoryByOwnerAndName(fields[0], fields[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByOwnerAndName")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+		}
+		return nil
+	}
+
+	perm, err := models.GetUserRepoPermission(source, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return nil
+	}
+	if !perm.CanReadAny(models.UnitTypeIssues, models.UnitTypePullRequests) {
+		ctx.NotFound("CanReadAny")
+		return nil
+	}
+	return source
+}
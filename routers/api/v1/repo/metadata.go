@@ -0,0 +1,229 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListMetadata lists the metadata of a repository
+func ListMetadata(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/metadata repository repoListMetadata
+	// ---
+	// summary: List the metadata of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoMetadataList"
+
+	listOptions := utils.GetListOptions(ctx)
+	entries, count, err := models.ListRepoMetadata(ctx.Repo.Repository.ID, listOptions)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListRepoMetadata", err)
+		return
+	}
+
+	apiEntries := make([]*api.RepoMetadata, len(entries))
+	for i, m := range entries {
+		if err := m.LoadUpdatedBy(); err != nil {
+			ctx.Error(http.StatusInternalServerError, "LoadUpdatedBy", err)
+			return
+		}
+		apiEntries[i] = convert.ToRepoMetadata(m, ctx.User)
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiEntries)
+}
+
+// GetMetadata gets a metadata value of a repository
+func GetMetadata(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/metadata/{key} repository repoGetMetadata
+	// ---
+	// summary: Get a metadata value of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: key
+	//   in: path
+	//   description: key of the metadata
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoMetadata"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	m, err := models.GetRepoMetadata(ctx.Repo.Repository.ID, ctx.Params(":key"))
+	if err != nil {
+		if models.IsErrRepoMetadataNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoMetadata", err)
+		}
+		return
+	}
+	writeRepoMetadata(ctx, m)
+}
+
+// SetMetadata creates or replaces a metadata value of a repository
+func SetMetadata(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/metadata/{key} repository repoSetMetadata
+	// ---
+	// summary: Create or replace a metadata value of a repository
+	// description: Send the entity tag of the current value as If-Match header to only replace the value
+	//   if it was not changed in the meantime.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: key
+	//   in: path
+	//   description: key of the metadata
+	//   type: string
+	//   required: true
+	// - name: If-Match
+	//   in: header
+	//   description: entity tag the current value must match
+	//   type: string
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetRepoMetadataOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoMetadata"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "412":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetRepoMetadataOption)
+	m, err := models.SetRepoMetadata(ctx.Repo.Repository.ID, ctx.Params(":key"), form.Value, ctx.User, ctx.Req.Header.Get("If-Match"))
+	if err != nil {
+		switch {
+		case models.IsErrRepoMetadataPreconditionFailed(err):
+			ctx.Error(http.StatusPreconditionFailed, "", err)
+		case models.IsErrRepoMetadataInvalidKey(err), models.IsErrRepoMetadataQuotaExceeded(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "SetRepoMetadata", err)
+		}
+		return
+	}
+	writeRepoMetadata(ctx, m)
+}
+
+// DeleteMetadata deletes a metadata value of a repository
+func DeleteMetadata(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/metadata/{key} repository repoDeleteMetadata
+	// ---
+	// summary: Delete a metadata value of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: key
+	//   in: path
+	//   description: key of the metadata
+	//   type: string
+	//   required: true
+	// - name: If-Match
+	//   in: header
+	//   description: entity tag the current value must match
+	//   type: string
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "412":
+	//     "$ref": "#/responses/error"
+
+	if err := models.DeleteRepoMetadata(ctx.Repo.Repository.ID, ctx.Params(":key"), ctx.Req.Header.Get("If-Match")); err != nil {
+		switch {
+		case models.IsErrRepoMetadataNotExist(err):
+			ctx.NotFound()
+		case models.IsErrRepoMetadataPreconditionFailed(err):
+			ctx.Error(http.StatusPreconditionFailed, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "DeleteRepoMetadata", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func writeRepoMetadata(ctx *context.APIContext, m *models.RepoMetadata) {
+	if err := m.LoadUpdatedBy(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadUpdatedBy", err)
+		return
+	}
+	ctx.Resp.Header().Set("ETag", m.ETag())
+	ctx.JSON(http.StatusOK, convert.ToRepoMetadata(m, ctx.User))
+}
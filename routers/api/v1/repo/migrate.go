@@ -23,6 +23,7 @@ import (
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
@@ -171,6 +172,9 @@ func Migrate(ctx *context.APIContext) {
 		IsPrivate:      opts.Private,
 		IsMirror:       opts.Mirror,
 		Status:         models.RepositoryBeingMigrated,
+		CreatedFrom:    models.RepoCreatedFromMigrated,
+
+		OverrideVisibilityPolicy: form.OverrideVisibilityPolicy,
 	})
 	if err != nil {
 		handleMigrateError(ctx, repoOwner, remoteAddr, err)
@@ -192,14 +196,15 @@ func Migrate(ctx *context.APIContext) {
 			return
 		}
 
-		if repo != nil {
+		// an incomplete migration is kept to be resumed
+		if repo != nil && !migrations.IsErrMigrationIncomplete(err) {
 			if errDelete := models.DeleteRepository(ctx.User, repoOwner.ID, repo.ID); errDelete != nil {
 				log.Error("DeleteRepository: %v", errDelete)
 			}
 		}
 	}()
 
-	if _, err = migrations.MigrateRepository(graceful.GetManager().HammerContext(), ctx.User, repoOwner.Name, opts, nil); err != nil {
+	if _, err = migrations.MigrateRepository(graceful.GetManager().HammerContext(), ctx.User, repoOwner.Name, opts, nil, nil); err != nil {
 		handleMigrateError(ctx, repoOwner, remoteAddr, err)
 		return
 	}
@@ -208,6 +213,76 @@ func Migrate(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToRepo(repo, models.AccessModeAdmin))
 }
 
+// SyncMigration resumes a failed migration of a repository or synchronizes a migrated repository with its original
+func SyncMigration(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/migrate/sync repository repoSyncMigration
+	// ---
+	// summary: Resume the failed migration of a repository from another gitea instance or synchronize it with its original
+	// description: Only issues, pull requests, comments and releases updated since the last migration are synchronized,
+	//   entities migrated before are updated instead of duplicated. The migration runs asynchronously.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SyncMigrationOptions"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/MigrationTask"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SyncMigrationOptions)
+	repo := ctx.Repo.Repository
+
+	if setting.Repository.DisableMigrations {
+		ctx.Error(http.StatusForbidden, "MigrationsGlobalDisabled", fmt.Errorf("the site administrator has disabled migrations"))
+		return
+	}
+
+	if repo.IsMirror {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Mirrors are synchronized by mirroring.")
+		return
+	}
+	if len(repo.OriginalURL) == 0 || repo.OriginalServiceType != api.GiteaService {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Only repositories migrated from gitea can be synchronized.")
+		return
+	}
+	if err := migrations.IsMigrateURLAllowed(repo.OriginalURL, ctx.User); err != nil {
+		handleRemoteAddrError(ctx, err)
+		return
+	}
+
+	t, err := task.SyncMigration(ctx.User, repo, form.AuthUsername, form.AuthPassword, form.AuthToken)
+	if err != nil {
+		if err == task.ErrMigrationInProgress {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SyncMigration", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, convert.ToMigrationTask(t))
+}
+
 func handleMigrateError(ctx *context.APIContext, repoOwner *models.User, remoteAddr string, err error) {
 	switch {
 	case models.IsErrRepoAlreadyExist(err):
@@ -220,12 +295,17 @@ func handleMigrateError(ctx *context.APIContext, repoOwner *models.User, remoteA
 		ctx.Error(http.StatusUnprocessableEntity, "", "Remote visit required two factors authentication.")
 	case models.IsErrReachLimitOfRepo(err):
 		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("You have already reached your limit of %d repositories.", repoOwner.MaxCreationLimit()))
+	case models.IsErrPublicRepoNotAllowed(err):
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("The organization '%s' requires its repositories to be private.", err.(models.ErrPublicRepoNotAllowed).OrgName))
 	case models.IsErrNameReserved(err):
 		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("The username '%s' is reserved.", err.(models.ErrNameReserved).Name))
 	case models.IsErrNameCharsNotAllowed(err):
 		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("The username '%s' contains invalid characters.", err.(models.ErrNameCharsNotAllowed).Name))
 	case models.IsErrNamePatternNotAllowed(err):
 		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("The pattern '%s' is not allowed in a username.", err.(models.ErrNamePatternNotAllowed).Pattern))
+	case models.IsErrNameTooLong(err):
+		errNameTooLong := err.(models.ErrNameTooLong)
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("The %s must not be longer than %d characters.", errNameTooLong.Kind, errNameTooLong.MaxLength))
 	case models.IsErrInvalidCloneAddr(err):
 		ctx.Error(http.StatusUnprocessableEntity, "", err)
 	case base.IsErrNotSupported(err):
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	issue_service "code.gitea.io/gitea/services/issue"
 )
 
 // ListMilestones list milestones for a repository
@@ -45,6 +46,10 @@ func ListMilestones(ctx *context.APIContext) {
 	//   in: query
 	//   description: filter by milestone name
 	//   type: string
+	// - name: overdue
+	//   in: query
+	//   description: filter by whether the milestone is past its due date
+	//   type: boolean
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -62,6 +67,7 @@ func ListMilestones(ctx *context.APIContext) {
 		RepoID:      ctx.Repo.Repository.ID,
 		State:       api.StateType(ctx.FormString("state")),
 		Name:        ctx.FormString("name"),
+		Overdue:     ctx.FormOptionalBool("overdue"),
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetMilestones", err)
@@ -289,3 +295,60 @@ func getMilestoneByIDOrName(ctx *context.APIContext) *models.Milestone {
 
 	return milestone
 }
+
+// CopyMilestones copy the open milestones of another repository to a repository
+func CopyMilestones(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/milestones/copy issue issueCopyMilestones
+	// ---
+	// summary: Copy the open milestones of another repository, except the ones whose titles are already used
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CopyMilestonesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CopiedMilestones"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CopyMilestonesOption)
+	source := getCopySource(ctx, form.Source)
+	if ctx.Written() {
+		return
+	}
+
+	created, skipped, err := issue_service.CopyMilestones(source, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CopyMilestones", err)
+		return
+	}
+
+	result := &api.CopiedMilestones{
+		Created: make([]*api.Milestone, len(created)),
+		Skipped: make([]*api.Milestone, len(skipped)),
+	}
+	for i := range created {
+		result.Created[i] = convert.ToAPIMilestone(created[i])
+	}
+	for i := range skipped {
+		result.Skipped[i] = convert.ToAPIMilestone(skipped[i])
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -9,7 +9,13 @@ import (
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/migrations"
 	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
 	mirror_service "code.gitea.io/gitea/services/mirror"
 )
 
@@ -52,3 +58,91 @@ func MirrorSync(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusOK)
 }
+
+// Convert converts a repository into a pull mirror or a pull mirror into a normal repository
+func Convert(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/convert repository repoConvert
+	// ---
+	// summary: Convert a repository into a mirror or a mirror into a normal repository
+	// description: Converting a repository into a mirror replaces all of its branches and tags with the ones
+	//   of the mirrored repository on the first sync.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo to convert
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo to convert
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ConvertRepoOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ConvertRepoOption)
+	repo := ctx.Repo.Repository
+
+	if form.To == "normal" {
+		if !repo.IsMirror {
+			ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a mirror")
+			return
+		}
+		if err := mirror_service.ConvertToNormal(repo); err != nil {
+			ctx.Error(http.StatusInternalServerError, "ConvertToNormal", err)
+			return
+		}
+		log.Trace("Repository converted from mirror to regular: %s", repo.FullName())
+		ctx.JSON(http.StatusOK, convert.ToRepo(repo, ctx.Repo.AccessMode))
+		return
+	}
+
+	if setting.Mirror.DisableNewPull {
+		ctx.Error(http.StatusForbidden, "MirrorsGlobalDisabled", "the site administrator has disabled the creation of new pull mirrors")
+		return
+	}
+	if repo.IsMirror {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is already a mirror")
+		return
+	}
+	if form.CloneAddr == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "clone_addr is required to convert a repository into a mirror")
+		return
+	}
+
+	remoteAddr, err := forms.ParseRemoteAddr(form.CloneAddr, form.AuthUsername, form.AuthPassword)
+	if err == nil {
+		err = migrations.IsMigrateURLAllowed(remoteAddr, ctx.User)
+	}
+	if err != nil {
+		handleRemoteAddrError(ctx, err)
+		return
+	}
+
+	interval, err := mirror_service.ParseMirrorInterval(form.MirrorInterval)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	if err := mirror_service.ConvertToMirror(repo, remoteAddr, form.CloneAddr, interval); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ConvertToMirror", err)
+		return
+	}
+	log.Trace("Repository converted from regular to mirror: %s", repo.FullName())
+	ctx.JSON(http.StatusOK, convert.ToRepo(repo, ctx.Repo.AccessMode))
+}
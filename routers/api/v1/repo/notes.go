@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/validation"
+	"code.gitea.io/gitea/modules/web"
 )
 
 // GetNote Get a note corresponding to a single commit from a repository
@@ -47,14 +48,21 @@ func GetNote(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 
 	sha := ctx.Params(":sha")
+	if !isValidNoteIdentifier(ctx, sha) {
+		return
+	}
+	getNote(ctx, sha, http.StatusOK)
+}
+
+func isValidNoteIdentifier(ctx *context.APIContext, sha string) bool {
 	if (validation.GitRefNamePatternInvalid.MatchString(sha) || !validation.CheckGitRefAdditionalRulesValid(sha)) && !git.SHAPattern.MatchString(sha) {
 		ctx.Error(http.StatusUnprocessableEntity, "no valid ref or sha", fmt.Sprintf("no valid ref or sha: %s", sha))
-		return
+		return false
 	}
-	getNote(ctx, sha)
+	return true
 }
 
-func getNote(ctx *context.APIContext, identifier string) {
+func getNote(ctx *context.APIContext, identifier string, status int) {
 	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
@@ -72,11 +80,151 @@ func getNote(ctx *context.APIContext, identifier string) {
 		return
 	}
 
-	cmt, err := convert.ToCommit(ctx.Repo.Repository, note.Commit, nil)
+	cmt, err := convert.ToCommit(ctx.Repo.Repository, note.Commit, nil, true)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "ToCommit", err)
 		return
 	}
 	apiNote := api.Note{Message: string(note.Message), Commit: cmt}
-	ctx.JSON(http.StatusOK, apiNote)
+	ctx.JSON(status, apiNote)
+}
+
+// SetNote Create or replace the note corresponding to a single commit
+func SetNote(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/git/notes/{sha} repository repoSetNote
+	// ---
+	// summary: Create or replace the note corresponding to a single commit
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetNoteOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Note"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetNoteOption)
+	sha := ctx.Params(":sha")
+	if !isValidNoteIdentifier(ctx, sha) {
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+		return
+	}
+	defer gitRepo.Close()
+
+	commitID, ok := resolveNoteCommitID(ctx, gitRepo, sha)
+	if !ok {
+		return
+	}
+
+	if err := git.SetNote(ctx, gitRepo, commitID, []byte(form.Message), ctx.User.NewGitSig()); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetNote", err)
+		return
+	}
+	getNote(ctx, commitID, http.StatusCreated)
+}
+
+// DeleteNote Remove the note corresponding to a single commit
+func DeleteNote(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/git/notes/{sha} repository repoDeleteNote
+	// ---
+	// summary: Remove the note corresponding to a single commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: a git ref or commit sha
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	sha := ctx.Params(":sha")
+	if !isValidNoteIdentifier(ctx, sha) {
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(ctx.Repo.Repository.RepoPath())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+		return
+	}
+	defer gitRepo.Close()
+
+	commitID, ok := resolveNoteCommitID(ctx, gitRepo, sha)
+	if !ok {
+		return
+	}
+
+	if err := git.RemoveNote(ctx, gitRepo, commitID, ctx.User.NewGitSig()); err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(commitID)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "RemoveNote", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// resolveNoteCommitID resolves the :sha parameter to a full commit ID so notes
+// are always attached to the commit rather than to a moving ref
+func resolveNoteCommitID(ctx *context.APIContext, gitRepo *git.Repository, sha string) (string, bool) {
+	commit, err := gitRepo.GetCommit(sha)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound(sha)
+			return "", false
+		}
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return "", false
+	}
+	return commit.ID.String(), true
 }
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+)
+
+// ListPermissions lists everyone with access to a repository and where their access comes from
+func ListPermissions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/permissions repository repoListPermissions
+	// ---
+	// summary: List the users and deploy keys with access to a repository
+	// description: Lists the owner, the collaborators and the members of the teams with access to the repository together with the sources of their access, and the deploy keys of the repository. Site administrators and users which can only read a public repository are not listed.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoPermissions"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	accesses, err := ctx.Repo.Repository.GetUserAccesses()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserAccesses", err)
+		return
+	}
+	keys, err := models.ListDeployKeys(&models.ListDeployKeysOptions{RepoID: ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListDeployKeys", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoPermissions(ctx.User, accesses, keys))
+}
@@ -54,9 +54,9 @@ func ListPullRequests(ctx *context.APIContext) {
 	//   enum: [closed, open, all]
 	// - name: sort
 	//   in: query
-	//   description: "Type of sort"
+	//   description: "Type of sort, defaults to the preference of the user or the repository"
 	//   type: string
-	//   enum: [oldest, recentupdate, leastupdate, mostcomment, leastcomment, priority]
+	//   enum: [latest, oldest, recentupdate, leastupdate, mostcomment, leastcomment, priority, nearduedate, farduedate]
 	// - name: milestone
 	//   in: query
 	//   description: "ID of the milestone"
@@ -70,6 +70,10 @@ func ListPullRequests(ctx *context.APIContext) {
 	//   items:
 	//     type: integer
 	//     format: int64
+	// - name: draft
+	//   in: query
+	//   description: "If true, only draft pull requests are returned, if false only pull requests ready for review"
+	//   type: boolean
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -81,15 +85,26 @@ func ListPullRequests(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/PullRequestList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	listOptions := utils.GetListOptions(ctx)
 
+	sortType := ctx.FormTrim("sort")
+	if len(sortType) == 0 {
+		sortType = models.DefaultIssueSortType(ctx.Repo.Repository, ctx.User)
+	} else if !models.IsValidIssueSortType(sortType) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid sort type: \"%s\"", sortType))
+		return
+	}
+
 	prs, maxResults, err := models.PullRequests(ctx.Repo.Repository.ID, &models.PullRequestsOptions{
 		ListOptions: listOptions,
 		State:       ctx.FormTrim("state"),
-		SortType:    ctx.FormTrim("sort"),
+		SortType:    sortType,
 		Labels:      ctx.FormStrings("labels"),
 		MilestoneID: ctx.FormInt64("milestone"),
+		IsDraft:     ctx.FormOptionalBool("draft"),
 	})
 
 	if err != nil {
@@ -376,6 +391,7 @@ func CreatePullRequest(ctx *context.APIContext) {
 		BaseRepo:   repo,
 		MergeBase:  compareInfo.MergeBase,
 		Type:       models.PullRequestGitea,
+		IsDraft:    form.Draft,
 	}
 
 	// Get all assignee IDs
@@ -455,6 +471,8 @@ func EditPullRequest(ctx *context.APIContext) {
 	//     "$ref": "#/responses/PullRequest"
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
 	//   "409":
 	//     "$ref": "#/responses/error"
 	//   "412":
@@ -480,6 +498,7 @@ func EditPullRequest(ctx *context.APIContext) {
 	}
 	issue := pr.Issue
 	issue.Repo = ctx.Repo.Repository
+	issue.PullRequest = pr
 
 	if !issue.IsPoster(ctx.User.ID) && !ctx.Repo.CanWrite(models.UnitTypePullRequests) {
 		ctx.Status(http.StatusForbidden)
@@ -568,6 +587,16 @@ func EditPullRequest(ctx *context.APIContext) {
 			ctx.Error(http.StatusPreconditionFailed, "MergedPRState", "cannot change state of this pull request, it was already merged")
 			return
 		}
+		if issue.IsClosed && api.StateType(*form.State) == api.StateOpen {
+			if err := issue_service.RestoreHeadRef(pr); err != nil {
+				if models.IsErrPullRequestHeadRefMissing(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "RestoreHeadRef", "cannot reopen this pull request, its head branch doesn't exist anymore")
+				} else {
+					ctx.Error(http.StatusInternalServerError, "RestoreHeadRef", err)
+				}
+				return
+			}
+		}
 		issue.IsClosed = api.StateClosed == api.StateType(*form.State)
 	}
 	statusChangeComment, titleChanged, err := models.UpdateIssueByAPI(issue, ctx.User)
@@ -594,6 +623,7 @@ func EditPullRequest(ctx *context.APIContext) {
 			ctx.Error(http.StatusNotFound, "NewBaseBranchNotExist", fmt.Errorf("new base '%s' not exist", form.Base))
 			return
 		}
+		oldBranch := pr.BaseBranch
 		if err := pull_service.ChangeTargetBranch(pr, ctx.User, form.Base); err != nil {
 			if models.IsErrPullRequestAlreadyExists(err) {
 				ctx.Error(http.StatusConflict, "IsErrPullRequestAlreadyExists", err)
@@ -604,12 +634,33 @@ func EditPullRequest(ctx *context.APIContext) {
 			} else if models.IsErrPullRequestHasMerged(err) {
 				ctx.Error(http.StatusConflict, "IsErrPullRequestHasMerged", err)
 				return
+			} else if models.IsErrBranchesEqual(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "IsErrBranchesEqual", err)
+				return
+			} else if models.IsErrBranchDoesNotExist(err) {
+				ctx.Error(http.StatusNotFound, "NewBaseBranchNotExist", err)
+				return
+			} else if models.IsErrNotAllowedToMerge(err) {
+				ctx.Error(http.StatusForbidden, "IsErrNotAllowedToMerge", err)
+				return
 			} else {
 				ctx.InternalServerError(err)
 			}
 			return
 		}
-		notification.NotifyPullRequestChangeTargetBranch(ctx.User, pr, form.Base)
+		notification.NotifyPullRequestChangeTargetBranch(ctx.User, pr, oldBranch)
+	}
+
+	// mark the pull request as a draft or as ready for review
+	if form.Draft != nil {
+		if err := pull_service.ChangeDraft(pr, ctx.User, *form.Draft); err != nil {
+			if models.IsErrPullRequestHasMerged(err) {
+				ctx.Error(http.StatusConflict, "IsErrPullRequestHasMerged", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "ChangeDraft", err)
+			}
+			return
+		}
 	}
 
 	// Refetch from database
@@ -784,12 +835,16 @@ func MergePullRequest(ctx *context.APIContext) {
 	}
 
 	if pr.IsWorkInProgress() {
-		ctx.Error(http.StatusMethodNotAllowed, "PR is a work in progress", "Work in progress PRs cannot be merged")
+		ctx.Error(http.StatusMethodNotAllowed, "PR is a draft", models.ErrPullRequestIsDraft{ID: pr.ID, Index: pr.Index})
 		return
 	}
 
-	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
-		if !models.IsErrNotAllowedToMerge(err) {
+	if err := pull_service.CheckPRReadyToMergeWithUpdate(pr, ctx.User); err != nil {
+		if models.IsErrMergeConflicts(err) || models.IsErrRebaseConflicts(err) {
+			ctx.Error(http.StatusConflict, "Update", "the outdated head branch can't be updated because of conflicts")
+			return
+		}
+		if !models.IsErrNotAllowedToMerge(err) && !models.IsErrPullRequestOutdated(err) {
 			ctx.Error(http.StatusInternalServerError, "CheckPRReadyToMerge", err)
 			return
 		}
@@ -838,6 +893,9 @@ func MergePullRequest(ctx *context.APIContext) {
 		if models.IsErrInvalidMergeStyle(err) {
 			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", models.MergeStyle(form.Do)))
 			return
+		} else if models.IsErrPullRequestIsDraft(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is a draft", err)
+			return
 		} else if models.IsErrMergeConflicts(err) {
 			conflictError := err.(models.ErrMergeConflicts)
 			ctx.JSON(http.StatusConflict, conflictError)
@@ -899,6 +957,263 @@ func MergePullRequest(ctx *context.APIContext) {
 	ctx.Status(http.StatusOK)
 }
 
+// GetPullRequestMergeQueueEntry returns the position of a pull request in the merge queue
+func GetPullRequestMergeQueueEntry(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/enqueue repository repoGetPullRequestMergeQueueEntry
+	// ---
+	// summary: Get the position of a pull request in the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeQueueEntry"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntryByPullID", err)
+		}
+		return
+	}
+	writeMergeQueueEntry(ctx, http.StatusOK, entry)
+}
+
+func writeMergeQueueEntry(ctx *context.APIContext, status int, entry *models.MergeQueueEntry) {
+	if err := entry.LoadDoer(); err != nil {
+		if !models.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "LoadDoer", err)
+			return
+		}
+		entry.Doer = models.NewGhostUser()
+	}
+	position, err := entry.Position()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Position", err)
+		return
+	}
+	ctx.JSON(status, convert.ToMergeQueueEntry(entry, position, ctx.User))
+}
+
+// EnqueuePullRequest adds a pull request to the merge queue of its base branch
+func EnqueuePullRequest(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/enqueue repository repoEnqueuePullRequest
+	// ---
+	// summary: Add a pull request to the merge queue of its base branch
+	// description: The pull request is merged once the merge of it with the base branch, including all
+	//   pull requests ahead of it in the queue, passed the required status checks. It is removed from
+	//   the queue with a notification if that fails.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request to merge
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     $ref: "#/definitions/MergePullRequestOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/MergeQueueEntry"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "405":
+	//     "$ref": "#/responses/error"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*forms.MergePullRequestForm)
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound("GetPullRequestByIndex", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+	if err = pr.LoadIssue(); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	if pr.Issue.IsClosed {
+		ctx.NotFound()
+		return
+	}
+
+	allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "IsUserAllowedToMerge", err)
+		return
+	}
+	if !allowedMerge {
+		ctx.Error(http.StatusMethodNotAllowed, "Merge", "User not allowed to merge PR")
+		return
+	}
+
+	if len(form.Do) == 0 {
+		form.Do = string(models.MergeStyleMerge)
+	}
+
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
+			message = pr.GetDefaultMergeMessage()
+		}
+		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
+			message = pr.GetDefaultSquashMessage()
+		}
+	}
+
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	entry, err := pull_service.AddToMergeQueue(pr, ctx.User, models.MergeStyle(form.Do), message)
+	if err != nil {
+		if models.IsErrMergeQueueDisabled(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "Merge queue disabled", "The merge queue is not enabled for this repository")
+		} else if models.IsErrInvalidMergeStyle(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not allowed an allowed merge style for this repository", models.MergeStyle(form.Do)))
+		} else if models.IsErrPullRequestIsDraft(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is a draft", err)
+		} else if models.IsErrNotAllowedToMerge(err) || models.IsErrPullRequestOutdated(err) {
+			ctx.Error(http.StatusMethodNotAllowed, "PR is not ready to be merged", err)
+		} else if models.IsErrPullRequestAlreadyQueued(err) {
+			ctx.Error(http.StatusConflict, "PR is already queued", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "AddToMergeQueue", err)
+		}
+		return
+	}
+
+	writeMergeQueueEntry(ctx, http.StatusCreated, entry)
+}
+
+// DequeuePullRequest removes a pull request from the merge queue of its base branch
+func DequeuePullRequest(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/enqueue repository repoDequeuePullRequest
+	// ---
+	// summary: Remove a pull request from the merge queue of its base branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMergeQueueEntryByPullID", err)
+		}
+		return
+	}
+
+	// the user who added the pull request can always remove it again
+	if entry.DoerID != ctx.User.ID {
+		allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsUserAllowedToMerge", err)
+			return
+		}
+		if !allowedMerge {
+			ctx.Error(http.StatusForbidden, "Dequeue", "User not allowed to merge PR")
+			return
+		}
+	}
+
+	if err := pull_service.RemoveFromMergeQueue(pr, ctx.User); err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RemoveFromMergeQueue", err)
+		}
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
 func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption) (*models.User, *models.Repository, *git.Repository, *git.CompareInfo, string, string) {
 	baseRepo := ctx.Repo.Repository
 
@@ -964,6 +1279,10 @@ func parseCompareInfo(ctx *context.APIContext, form api.CreatePullRequestOption)
 		headRepo = ctx.Repo.Repository
 		headGitRepo = ctx.Repo.GitRepo
 	} else {
+		if err := models.CheckSameObjectFormat(baseRepo, headRepo); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "CheckSameObjectFormat", err)
+			return nil, nil, nil, nil, "", ""
+		}
 		headGitRepo, err = git.OpenRepository(models.RepoPath(headUser.Name, headRepo.Name))
 		if err != nil {
 			ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
@@ -1218,7 +1537,7 @@ func GetPullRequestCommits(ctx *context.APIContext) {
 
 	apiCommits := make([]*api.Commit, 0, end-start)
 	for i := start; i < end; i++ {
-		apiCommit, err := convert.ToCommit(ctx.Repo.Repository, commits[i], userCache)
+		apiCommit, err := convert.ToCommit(ctx.Repo.Repository, commits[i], userCache, true)
 		if err != nil {
 			ctx.ServerError("toCommit", err)
 			return
@@ -1237,3 +1556,50 @@ func GetPullRequestCommits(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, &apiCommits)
 }
+
+// CleanupPullRequestRefs deletes the head refs of closed pull requests
+func CleanupPullRequestRefs(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/cleanup-refs repository repoCleanupPullRequestRefs
+	// ---
+	// summary: Delete the head refs of pull requests which have been closed or merged for a while
+	// description: The `refs/pull/{index}/head` refs are deleted, agit flow pull requests are skipped.
+	//              Reopening a pull request restores its head ref from the head branch if it still exists.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: older_than
+	//   in: query
+	//   description: only delete the refs of pull requests closed longer ago than this duration, e.g. "720h" (defaults to 0)
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestRefsCleanup"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	var olderThan time.Duration
+	if value := ctx.FormString("older_than"); value != "" {
+		var err error
+		if olderThan, err = time.ParseDuration(value); err != nil || olderThan < 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid older_than: %q", value))
+			return
+		}
+	}
+
+	deleted, err := pull_service.CleanupHeadRefs(ctx, ctx.Repo.Repository, olderThan)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CleanupHeadRefs", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.PullRequestRefsCleanup{Deleted: deleted})
+}
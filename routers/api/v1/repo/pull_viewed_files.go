@@ -0,0 +1,255 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// getPullRequestByIndex loads the pull request of the index in the path, it writes the response on failure
+func getPullRequestByIndex(ctx *context.APIContext) *models.PullRequest {
+	pr, err := models.GetPullRequestByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+	return pr
+}
+
+// getViewedFiles returns the files of the pull request the authenticated user marked as viewed by path
+func getViewedFiles(ctx *context.APIContext, pr *models.PullRequest) (map[string]*pull_service.ViewedFile, bool) {
+	viewed := make(map[string]*pull_service.ViewedFile)
+	if ctx.User == nil {
+		return viewed, true
+	}
+	files, err := pull_service.GetViewedFiles(ctx.Repo.GitRepo, pr, ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetViewedFiles", err)
+		return nil, false
+	}
+	for _, file := range files {
+		viewed[file.TreePath] = file
+	}
+	return viewed, true
+}
+
+func toPullViewedFiles(files []*pull_service.ViewedFile) []*api.PullViewedFile {
+	apiFiles := make([]*api.PullViewedFile, len(files))
+	for i, file := range files {
+		apiFiles[i] = &api.PullViewedFile{
+			Path:               file.TreePath,
+			Viewed:             file.Viewed(),
+			ChangedSinceViewed: file.ChangedSinceViewed,
+			CommitID:           file.CommitSHA,
+			Updated:            file.UpdatedUnix.AsTime(),
+		}
+	}
+	return apiFiles
+}
+
+// GetPullRequestFiles lists the files changed by a pull request
+func GetPullRequestFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/files repository repoGetPullRequestFiles
+	// ---
+	// summary: Get the files changed by a pull request
+	// description: The viewed state of the files is the one of the authenticated user.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChangedFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if pr == nil {
+		return
+	}
+
+	names, err := pull_service.GetChangedFiles(ctx.Repo.GitRepo, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetChangedFiles", err)
+		return
+	}
+	viewed, ok := getViewedFiles(ctx, pr)
+	if !ok {
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	start, end := listOptions.GetStartEnd()
+	if end > len(names) {
+		end = len(names)
+	}
+	if start > end {
+		start = end
+	}
+
+	files := make([]*api.ChangedFile, 0, end-start)
+	for _, name := range names[start:end] {
+		file := viewed[name]
+		files = append(files, &api.ChangedFile{
+			Filename:           name,
+			Viewed:             file.Viewed(),
+			ChangedSinceViewed: file != nil && file.ChangedSinceViewed,
+		})
+	}
+
+	ctx.SetLinkHeader(len(names), listOptions.PageSize)
+	ctx.SetTotalCountHeader(int64(len(names)))
+	ctx.JSON(http.StatusOK, files)
+}
+
+// ListPullViewedFiles lists the files of a pull request the authenticated user marked as viewed
+func ListPullViewedFiles(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/viewed-files repository repoListPullViewedFiles
+	// ---
+	// summary: List the files of a pull request the authenticated user marked as viewed
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullViewedFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestByIndex(ctx)
+	if pr == nil {
+		return
+	}
+
+	files, err := pull_service.GetViewedFiles(ctx.Repo.GitRepo, pr, ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetViewedFiles", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toPullViewedFiles(files))
+}
+
+// UpdatePullViewedFiles marks files of a pull request as viewed or not viewed by the authenticated user
+func UpdatePullViewedFiles(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/pulls/{index}/viewed-files repository repoUpdatePullViewedFiles
+	// ---
+	// summary: Mark files of a pull request as viewed or not viewed by the authenticated user
+	// description: Files are marked as viewed at the current head commit of the pull request.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/UpdatePullViewedFilesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullViewedFileList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UpdatePullViewedFilesOption)
+	pr := getPullRequestByIndex(ctx)
+	if pr == nil {
+		return
+	}
+
+	viewed := make(map[string]bool, len(form.Files))
+	for _, file := range form.Files {
+		if file.Path == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("path is required"))
+			return
+		}
+		viewed[file.Path] = file.Viewed
+	}
+
+	if err := pull_service.UpdateViewedFiles(ctx.Repo.GitRepo, pr, ctx.User.ID, viewed); err != nil {
+		if pull_service.IsErrFileNotInPullRequest(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateViewedFiles", err)
+		}
+		return
+	}
+
+	files, err := pull_service.GetViewedFiles(ctx.Repo.GitRepo, pr, ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetViewedFiles", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toPullViewedFiles(files))
+}
@@ -0,0 +1,115 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// GetPushRules gets the push rules of a repository
+func GetPushRules(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/push_rules repository repoGetPushRules
+	// ---
+	// summary: Get the push rules of a repository
+	// description: The instance wide push rules are not included and always apply in addition.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRules"
+
+	rule, err := models.GetPushRule(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPushRule", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPushRules(rule))
+}
+
+// EditPushRules replaces the push rules of a repository
+func EditPushRules(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/push_rules repository repoEditPushRules
+	// ---
+	// summary: Replace the push rules of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPushRulesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PushRules"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPushRulesOption)
+	if form.MaxFileSize < 0 || form.MaxPathLength < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "max_file_size and max_path_length must not be negative")
+		return
+	}
+
+	rule, err := models.GetPushRule(ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPushRule", err)
+		return
+	}
+	rule.ForbiddenPaths = trimPushRulePatterns(form.ForbiddenPaths)
+	rule.MaxFileSize = form.MaxFileSize
+	rule.MaxPathLength = form.MaxPathLength
+	rule.ForbiddenCommitterEmails = trimPushRulePatterns(form.ForbiddenCommitterEmails)
+	rule.ScanSecrets = form.ScanSecrets
+
+	if err := models.UpdatePushRule(rule); err != nil {
+		if models.IsErrInvalidPushRulePattern(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdatePushRule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPushRules(rule))
+}
+
+func trimPushRulePatterns(patterns []string) []string {
+	trimmed := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			trimmed = append(trimmed, pattern)
+		}
+	}
+	return trimmed
+}
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/task"
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/attachment"
@@ -144,16 +145,25 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	//   description: name of the attachment
 	//   type: string
 	//   required: false
+	// - name: source_url
+	//   in: query
+	//   description: URL the server fetches the attachment from instead of uploading it, the fetch is asynchronous
+	//   type: string
+	//   required: false
 	// - name: attachment
 	//   in: formData
-	//   description: attachment to upload
+	//   description: attachment to upload, required unless source_url is given
 	//   type: file
-	//   required: true
+	//   required: false
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Attachment"
+	//   "202":
+	//     "$ref": "#/responses/ReleaseAttachmentTask"
 	//   "400":
 	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	// Check if attachments are enabled
 	if !setting.Attachment.Enabled {
@@ -169,6 +179,11 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 		return
 	}
 
+	if sourceURL := ctx.FormString("source_url"); sourceURL != "" {
+		fetchReleaseAttachment(ctx, release, sourceURL)
+		return
+	}
+
 	// Get uploaded file from request
 	file, header, err := ctx.Req.FormFile("attachment")
 	if err != nil {
@@ -196,6 +211,115 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 	ctx.JSON(http.StatusCreated, convert.ToReleaseAttachment(attach))
 }
 
+// fetchReleaseAttachment queues the fetching of a release attachment from the URL
+func fetchReleaseAttachment(ctx *context.APIContext, release *models.Release, sourceURL string) {
+	if setting.Repository.Release.MaxFetchSize <= 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Fetching attachments from URLs is disabled")
+		return
+	}
+	if release.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+	release.Repo = ctx.Repo.Repository
+
+	t, err := task.FetchReleaseAttachment(ctx.User, release, sourceURL, ctx.FormString("name"))
+	if err != nil {
+		if models.IsErrInvalidCloneAddr(err) {
+			addrErr := err.(*models.ErrInvalidCloneAddr)
+			switch {
+			case addrErr.IsURLError:
+				ctx.Error(http.StatusUnprocessableEntity, "", "Invalid source URL, only HTTP(S) URLs are supported")
+			case addrErr.NotResolvedIP:
+				ctx.Error(http.StatusUnprocessableEntity, "", "Unknown host of the source URL")
+			case len(addrErr.PrivateNet) != 0:
+				ctx.Error(http.StatusUnprocessableEntity, "", "You are not allowed to fetch from private IPs")
+			default:
+				ctx.Error(http.StatusUnprocessableEntity, "", "You are not allowed to fetch from blocked hosts")
+			}
+			return
+		} else if err == task.ErrReleaseAttachmentNameUnknown {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "FetchReleaseAttachment", err)
+		return
+	}
+
+	fetch, err := t.ReleaseAttachmentFetch()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReleaseAttachmentFetch", err)
+		return
+	}
+	ctx.JSON(http.StatusAccepted, convert.ToReleaseAttachmentTask(t, fetch, nil))
+}
+
+// GetReleaseAttachmentTask gets the status of the fetching of a release attachment from a URL
+func GetReleaseAttachmentTask(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/releases/{id}/assets/tasks/{task_id} repository repoGetReleaseAttachmentTask
+	// ---
+	// summary: Get the status of the fetching of a release attachment from a URL
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the release
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: task_id
+	//   in: path
+	//   description: id of the task returned when the attachment was created
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReleaseAttachmentTask"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := models.GetReleaseAttachmentTask(ctx.Repo.Repository.ID, ctx.ParamsInt64(":task_id"))
+	if err != nil {
+		if models.IsErrTaskDoesNotExist(err) {
+			ctx.NotFound()
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetReleaseAttachmentTask", err)
+		return
+	}
+	fetch, err := t.ReleaseAttachmentFetch()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReleaseAttachmentFetch", err)
+		return
+	}
+	if fetch.ReleaseID != ctx.ParamsInt64(":id") {
+		ctx.NotFound()
+		return
+	}
+
+	var attach *models.Attachment
+	if fetch.AttachmentID != 0 {
+		attach, err = models.GetAttachmentByID(fetch.AttachmentID)
+		if err != nil && !models.IsErrAttachmentNotExist(err) {
+			ctx.Error(http.StatusInternalServerError, "GetAttachmentByID", err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToReleaseAttachmentTask(t, fetch, attach))
+}
+
 // EditReleaseAttachment updates the given attachment
 func EditReleaseAttachment(ctx *context.APIContext) {
 	// swagger:operation PATCH /repos/{owner}/{repo}/releases/{id}/assets/{attachment_id} repository repoEditReleaseAttachment
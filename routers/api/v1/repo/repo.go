@@ -16,7 +16,6 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
@@ -32,6 +31,9 @@ var searchOrderByMap = map[string]map[string]models.SearchOrderBy{
 		"updated": models.SearchOrderByLeastUpdated,
 		"size":    models.SearchOrderBySize,
 		"id":      models.SearchOrderByID,
+
+		"mirror_next_update": models.SearchOrderByMirrorNextUpdate,
+		"mirror_updated":     models.SearchOrderByMirrorFailingFirst,
 	},
 	"desc": {
 		"alpha":   models.SearchOrderByAlphabeticallyReverse,
@@ -39,6 +41,9 @@ var searchOrderByMap = map[string]map[string]models.SearchOrderBy{
 		"updated": models.SearchOrderByRecentUpdated,
 		"size":    models.SearchOrderBySizeReverse,
 		"id":      models.SearchOrderByIDReverse,
+
+		"mirror_next_update": models.SearchOrderByMirrorNextUpdateReverse,
+		"mirror_updated":     models.SearchOrderByMirrorRecentUpdated,
 	},
 }
 
@@ -103,6 +108,16 @@ func Search(ctx *context.APIContext) {
 	//   description: type of repository to search for. Supported values are
 	//                "fork", "source", "mirror" and "collaborative"
 	//   type: string
+	// - name: created_from
+	//   in: query
+	//   description: search only for repos which came to exist this way. Supported values are
+	//                "created", "migrated", "forked", "generated", "adopted" and "pushed-to-create"
+	//   type: string
+	// - name: license
+	//   in: query
+	//   description: search only for repos with this license detected, given by its SPDX identifier
+	//                or "other" for license files without a known license
+	//   type: string
 	// - name: exclusive
 	//   in: query
 	//   description: if `uid` is given, search only for repos that the user owns
@@ -111,6 +126,8 @@ func Search(ctx *context.APIContext) {
 	//   in: query
 	//   description: sort repos by attribute. Supported values are
 	//                "alpha", "created", "updated", "size", and "id".
+	//                Mirrors can also be sorted by "mirror_next_update" and "mirror_updated",
+	//                the time of the last successful sync, which only returns mirrors.
 	//                Default is "alpha"
 	//   type: string
 	// - name: order
@@ -145,6 +162,7 @@ func Search(ctx *context.APIContext) {
 		Template:           util.OptionalBoolNone,
 		StarredByID:        ctx.FormInt64("starredBy"),
 		IncludeDescription: ctx.FormBool("includeDesc"),
+		License:            ctx.FormTrim("license"),
 	}
 
 	if ctx.FormString("template") != "" {
@@ -181,6 +199,14 @@ func Search(ctx *context.APIContext) {
 		opts.IsPrivate = util.OptionalBoolOf(ctx.FormBool("is_private"))
 	}
 
+	if createdFrom := ctx.FormString("created_from"); createdFrom != "" {
+		var ok bool
+		if opts.CreatedFrom, ok = models.ToRepoCreatedFrom(createdFrom); !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid created from: \"%s\"", createdFrom))
+			return
+		}
+	}
+
 	var sortMode = ctx.FormString("sort")
 	if len(sortMode) > 0 {
 		var sortOrder = ctx.FormString("order")
@@ -229,6 +255,20 @@ func Search(ctx *context.APIContext) {
 		results[i] = convert.ToRepo(repo, accessMode)
 	}
 
+	if ctx.IsSigned {
+		newActivity, err := models.GetReposWithNewActivity(ctx.User.ID, repos)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, api.SearchError{
+				OK:    false,
+				Error: err.Error(),
+			})
+			return
+		}
+		for i, repo := range repos {
+			results[i].NewActivity = newActivity[repo.ID]
+		}
+	}
+
 	ctx.SetLinkHeader(int(count), opts.PageSize)
 	ctx.SetTotalCountHeader(count)
 	ctx.JSON(http.StatusOK, api.SearchResults{
@@ -254,12 +294,18 @@ func CreateUserRepo(ctx *context.APIContext, owner *models.User, opt api.CreateR
 		DefaultBranch: opt.DefaultBranch,
 		TrustModel:    models.ToTrustModel(opt.TrustModel),
 		IsTemplate:    opt.Template,
+
+		OverrideVisibilityPolicy: opt.OverrideVisibilityPolicy,
 	})
 	if err != nil {
 		if models.IsErrRepoAlreadyExist(err) {
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if models.IsErrNameReserved(err) ||
-			models.IsErrNamePatternNotAllowed(err) {
+			models.IsErrNameTooLong(err) ||
+			models.IsErrNamePatternNotAllowed(err) ||
+			models.IsErrObjectFormatNotAllowed(err) ||
+			models.IsErrInitFileNotExist(err) ||
+			models.IsErrPublicRepoNotAllowed(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -408,7 +454,9 @@ func Generate(ctx *context.APIContext) {
 		if models.IsErrRepoAlreadyExist(err) {
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if models.IsErrNameReserved(err) ||
-			models.IsErrNamePatternNotAllowed(err) {
+			models.IsErrNameTooLong(err) ||
+			models.IsErrNamePatternNotAllowed(err) ||
+			models.IsErrPublicRepoNotAllowed(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -604,6 +652,14 @@ func Edit(ctx *context.APIContext) {
 
 	opts := *web.GetForm(ctx).(*api.EditRepoOption)
 
+	// An archived repository must be unarchived before anything else is changed.
+	if opts.Archived == nil || *opts.Archived {
+		if err := ctx.Repo.Repository.CheckNotArchived(); err != nil {
+			ctx.Error(http.StatusForbidden, "RepoIsArchived", err)
+			return
+		}
+	}
+
 	if err := updateBasicProperties(ctx, opts); err != nil {
 		return
 	}
@@ -651,6 +707,8 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 				ctx.Error(http.StatusUnprocessableEntity, fmt.Sprintf("repo name is reserved [name: %s]", newRepoName), err)
 			case models.IsErrNamePatternNotAllowed(err):
 				ctx.Error(http.StatusUnprocessableEntity, fmt.Sprintf("repo name's pattern is not allowed [name: %s, pattern: %s]", newRepoName, err.(models.ErrNamePatternNotAllowed).Pattern), err)
+			case models.IsErrNameTooLong(err):
+				ctx.Error(http.StatusUnprocessableEntity, fmt.Sprintf("repo name is too long [name: %s, max length: %d]", newRepoName, err.(models.ErrNameTooLong).MaxLength), err)
 			default:
 				ctx.Error(http.StatusUnprocessableEntity, "ChangeRepositoryName", err)
 			}
@@ -684,10 +742,11 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 
 		visibilityChanged = repo.IsPrivate != *opts.Private
 		// when ForcePrivate enabled, you could change public repo to private, but only admin users can change private to public
-		if visibilityChanged && setting.Repository.ForcePrivate && !*opts.Private && !ctx.User.IsAdmin {
-			err := fmt.Errorf("cannot change private repository to public")
-			ctx.Error(http.StatusUnprocessableEntity, "Force Private enabled", err)
-			return err
+		if visibilityChanged {
+			if err := repo_service.CheckVisibilityChange(ctx.User, *opts.Private); err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "Force Private enabled", err)
+				return err
+			}
 		}
 
 		repo.IsPrivate = *opts.Private
@@ -720,8 +779,18 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		repo.DefaultBranch = *opts.DefaultBranch
 	}
 
-	if err := models.UpdateRepository(repo, visibilityChanged); err != nil {
-		ctx.Error(http.StatusInternalServerError, "UpdateRepository", err)
+	var err error
+	if opts.OverrideVisibilityPolicy && ctx.User.IsAdmin {
+		err = models.UpdateRepositoryOverridingVisibilityPolicy(repo, visibilityChanged)
+	} else {
+		err = models.UpdateRepository(repo, visibilityChanged)
+	}
+	if err != nil {
+		if models.IsErrPublicRepoNotAllowed(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "UpdateRepository", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateRepository", err)
+		}
 		return err
 	}
 
@@ -739,26 +808,22 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 
 	if opts.HasIssues != nil {
 		if *opts.HasIssues && opts.ExternalTracker != nil && !models.UnitTypeExternalTracker.UnitGlobalDisabled() {
-			// Check that values are valid
-			if !validation.IsValidExternalURL(opts.ExternalTracker.ExternalTrackerURL) {
-				err := fmt.Errorf("External tracker URL not valid")
-				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL", err)
-				return err
+			config := &models.ExternalTrackerConfig{
+				ExternalTrackerURL:           opts.ExternalTracker.ExternalTrackerURL,
+				ExternalTrackerFormat:        opts.ExternalTracker.ExternalTrackerFormat,
+				ExternalTrackerStyle:         opts.ExternalTracker.ExternalTrackerStyle,
+				ExternalTrackerRegexpPattern: opts.ExternalTracker.ExternalTrackerRegexpPattern,
 			}
-			if len(opts.ExternalTracker.ExternalTrackerFormat) != 0 && !validation.IsValidExternalTrackerURLFormat(opts.ExternalTracker.ExternalTrackerFormat) {
-				err := fmt.Errorf("External tracker URL format not valid")
-				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker URL format", err)
+			// Check that values are valid
+			if err := config.Validate(); err != nil {
+				ctx.Error(http.StatusUnprocessableEntity, "Invalid external tracker", err)
 				return err
 			}
 
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeExternalTracker,
-				Config: &models.ExternalTrackerConfig{
-					ExternalTrackerURL:    opts.ExternalTracker.ExternalTrackerURL,
-					ExternalTrackerFormat: opts.ExternalTracker.ExternalTrackerFormat,
-					ExternalTrackerStyle:  opts.ExternalTracker.ExternalTrackerStyle,
-				},
+				Config: config,
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
 		} else if *opts.HasIssues && opts.ExternalTracker == nil && !models.UnitTypeIssues.UnitGlobalDisabled() {
@@ -766,10 +831,18 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			var config *models.IssuesConfig
 
 			if opts.InternalTracker != nil {
+				if len(opts.InternalTracker.DefaultSortType) > 0 && !models.IsValidIssueSortType(opts.InternalTracker.DefaultSortType) {
+					err := fmt.Errorf("Default sort type not valid")
+					ctx.Error(http.StatusUnprocessableEntity, "Invalid default sort type", err)
+					return err
+				}
 				config = &models.IssuesConfig{
 					EnableTimetracker:                opts.InternalTracker.EnableTimeTracker,
 					AllowOnlyContributorsToTrackTime: opts.InternalTracker.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               opts.InternalTracker.EnableIssueDependencies,
+					DefaultSortType:                  opts.InternalTracker.DefaultSortType,
+					CloseKeywords:                    opts.InternalTracker.CloseKeywords,
+					ReopenKeywords:                   opts.InternalTracker.ReopenKeywords,
 				}
 			} else if unit, err := repo.GetUnit(models.UnitTypeIssues); err != nil {
 				// Unit type doesn't exist so we make a new config file with default values
@@ -884,6 +957,24 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			if opts.DefaultMergeStyle != nil {
 				config.DefaultMergeStyle = models.MergeStyle(*opts.DefaultMergeStyle)
 			}
+			if opts.EnableMergeQueue != nil {
+				config.EnableMergeQueue = *opts.EnableMergeQueue
+			}
+			if opts.MergeCommitAuthorStyle != nil {
+				style := models.MergeCommitAuthorStyle(*opts.MergeCommitAuthorStyle)
+				if !style.IsValid() {
+					err := fmt.Errorf("Merge commit author style not valid")
+					ctx.Error(http.StatusUnprocessableEntity, "Invalid merge commit author style", err)
+					return err
+				}
+				config.MergeCommitAuthorStyle = style
+			}
+			if opts.AddReviewedByTrailers != nil {
+				config.AddReviewedByTrailers = *opts.AddReviewedByTrailers
+			}
+			if opts.AddCoAuthorTrailers != nil {
+				config.AddCoAuthorTrailers = *opts.AddCoAuthorTrailers
+			}
 
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
@@ -1049,3 +1140,46 @@ func GetIssueTemplates(ctx *context.APIContext) {
 
 	ctx.JSON(http.StatusOK, ctx.IssueTemplatesFromDefaultBranch())
 }
+
+// GetIssueFormSchema returns the parsed schema of an issue form
+func GetIssueFormSchema(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issue_forms/{name}/schema repository repoGetIssueFormSchema
+	// ---
+	// summary: Get the schema of an issue form of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: file name of the issue form, the extension may be omitted
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueTemplate"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	it, err := ctx.IssueTemplateFromDefaultBranch(ctx.Params("name"))
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "IssueTemplateFromDefaultBranch", err)
+		return
+	}
+	if it == nil || !it.IsForm() {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, it)
+}
@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -36,6 +37,11 @@ func ListTags(ctx *context.APIContext) {
 	//   description: name of the repo
 	//   type: string
 	//   required: true
+	// - name: sort
+	//   in: query
+	//   description: "order of the tags: newest first (default) or highest version first"
+	//   type: string
+	//   enum: [date, semver]
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -47,10 +53,20 @@ func ListTags(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/TagList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	listOpts := utils.GetListOptions(ctx)
 
-	tags, total, err := ctx.Repo.GitRepo.GetTagInfos(listOpts.Page, listOpts.PageSize)
+	sortType := git.TagSortType(ctx.FormTrim("sort"))
+	if sortType == "" {
+		sortType = git.TagSortDate
+	} else if sortType != git.TagSortDate && sortType != git.TagSortSemver {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown sort: %s", sortType))
+		return
+	}
+
+	tags, total, err := ctx.Repo.GitRepo.GetTagInfos(listOpts.Page, listOpts.PageSize, sortType)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetTags", err)
 		return
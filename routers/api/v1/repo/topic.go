@@ -5,6 +5,7 @@
 package repo
 
 import (
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -101,7 +102,7 @@ func UpdateTopics(ctx *context.APIContext) {
 	topicNames := form.Topics
 	validTopics, invalidTopics := models.SanitizeAndValidateTopics(topicNames)
 
-	if len(validTopics) > 25 {
+	if len(validTopics) > models.MaxTopicsPerRepo {
 		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
 			"invalidTopics": nil,
 			"message":       "Exceeding maximum number of topics per repo",
@@ -175,7 +176,7 @@ func AddTopic(ctx *context.APIContext) {
 		ctx.InternalServerError(err)
 		return
 	}
-	if count >= 25 {
+	if count >= models.MaxTopicsPerRepo {
 		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
 			"message": "Exceeding maximum allowed topics per repo.",
 		})
@@ -293,3 +294,89 @@ func TopicSearch(ctx *context.APIContext) {
 		"topics": topicResponses,
 	})
 }
+
+// ListTopicRepos list the repositories with a topic
+func ListTopicRepos(ctx *context.APIContext) {
+	// swagger:operation GET /topics/{topic}/repos repository topicListRepos
+	// ---
+	// summary: List the repositories with a topic which are accessible to the user
+	// produces:
+	//   - application/json
+	// parameters:
+	//   - name: topic
+	//     in: path
+	//     description: name of the topic
+	//     type: string
+	//     required: true
+	//   - name: sort
+	//     in: query
+	//     description: "order of the repositories: most stars first (default) or recently updated first"
+	//     type: string
+	//     enum: [stars, updated]
+	//   - name: page
+	//     in: query
+	//     description: page number of results to return (1-based)
+	//     type: integer
+	//   - name: limit
+	//     in: query
+	//     description: page size of results
+	//     type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepositoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	topicName := ctx.Params(":topic")
+	if !models.ValidateTopic(topicName) {
+		ctx.NotFound()
+		return
+	}
+	if _, err := models.GetTopicByName(topicName); err != nil {
+		if models.IsErrTopicNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	opts := &models.SearchRepoOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Actor:       ctx.User,
+		Keyword:     topicName,
+		TopicOnly:   true,
+		Private:     ctx.IsSigned,
+	}
+	switch ctx.FormTrim("sort") {
+	case "", "stars":
+		opts.OrderBy = models.SearchOrderByStarsReverse
+	case "updated":
+		opts.OrderBy = models.SearchOrderByRecentUpdated
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid sort: %s", ctx.FormTrim("sort")))
+		return
+	}
+
+	repos, count, err := models.SearchRepository(opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	results := make([]*api.Repository, len(repos))
+	for i, repo := range repos {
+		accessMode, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		results[i] = convert.ToRepo(repo, accessMode)
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, results)
+}
@@ -0,0 +1,118 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	traffic_service "code.gitea.io/gitea/services/traffic"
+)
+
+// getTrafficHistory returns the traffic of the last 14 days per the period requested by the "per" parameter
+func getTrafficHistory(ctx *context.APIContext, kind models.RepoTrafficKind) ([]*models.RepoTraffic, bool) {
+	var period models.RepoTrafficPeriod
+	switch ctx.FormString("per") {
+	case "", "day":
+		period = models.RepoTrafficDay
+	case "week":
+		period = models.RepoTrafficWeek
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", "per must be day or week")
+		return nil, false
+	}
+
+	traffic, err := traffic_service.History(ctx.Repo.Repository.ID, kind, period)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "History", err)
+		return nil, false
+	}
+	return traffic, true
+}
+
+// GetCloneTraffic gets the clones of a repository of the last 14 days
+func GetCloneTraffic(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/traffic/clones repository repoGetCloneTraffic
+	// ---
+	// summary: Get the clones and fetches of a repository over HTTP and SSH of the last 14 days
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: per
+	//   in: query
+	//   description: count the clones per day or per week
+	//   type: string
+	//   enum: [day, week]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoCloneTraffic"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	traffic, ok := getTrafficHistory(ctx, models.RepoTrafficClone)
+	if !ok {
+		return
+	}
+	clones, count, uniques := convert.ToRepoTrafficPeriods(traffic)
+	ctx.JSON(http.StatusOK, &api.RepoCloneTraffic{
+		Count:   count,
+		Uniques: uniques,
+		Clones:  clones,
+	})
+}
+
+// GetViewTraffic gets the views of a repository of the last 14 days
+func GetViewTraffic(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/traffic/views repository repoGetViewTraffic
+	// ---
+	// summary: Get the views of the home page of a repository of the last 14 days
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: per
+	//   in: query
+	//   description: count the views per day or per week
+	//   type: string
+	//   enum: [day, week]
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoViewTraffic"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	traffic, ok := getTrafficHistory(ctx, models.RepoTrafficView)
+	if !ok {
+		return
+	}
+	views, count, uniques := convert.ToRepoTrafficPeriods(traffic)
+	ctx.JSON(http.StatusOK, &api.RepoViewTraffic{
+		Count:   count,
+		Uniques: uniques,
+		Views:   views,
+	})
+}
@@ -84,3 +84,21 @@ func GetGeneralAttachmentSettings(ctx *context.APIContext) {
 		MaxSize:      setting.Attachment.MaxSize,
 	})
 }
+
+// GetGeneralSSHSettings returns instance's global settings for SSH
+func GetGeneralSSHSettings(ctx *context.APIContext) {
+	// swagger:operation GET /settings/ssh settings getGeneralSSHSettings
+	// ---
+	// summary: Get instance's global settings for SSH, including the policy for the types and sizes of keys
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GeneralSSHSettings"
+	ctx.JSON(http.StatusOK, api.GeneralSSHSettings{
+		Disabled:                  setting.SSH.Disabled,
+		MinimumKeySizeCheck:       setting.SSH.MinimumKeySizeCheck,
+		MinimumKeySizeCheckOnAuth: setting.SSH.MinimumKeySizeCheckOnAuth,
+		MinimumKeySizes:           setting.SSH.MinimumKeySizes,
+	})
+}
@@ -14,3 +14,24 @@ type swaggerResponseCronList struct {
 	// in:body
 	Body []api.Cron `json:"body"`
 }
+
+// DeleteInactiveUsersReport
+// swagger:response DeleteInactiveUsersReport
+type swaggerResponseDeleteInactiveUsersReport struct {
+	// in:body
+	Body api.DeleteInactiveUsersReport `json:"body"`
+}
+
+// Statistics
+// swagger:response Statistics
+type swaggerResponseStatistics struct {
+	// in:body
+	Body api.Statistics `json:"body"`
+}
+
+// GitConfigTemplate
+// swagger:response GitConfigTemplate
+type swaggerResponseGitConfigTemplate struct {
+	// in:body
+	Body api.GitConfigTemplate `json:"body"`
+}
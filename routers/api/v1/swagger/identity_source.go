@@ -0,0 +1,23 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// IdentitySource
+// swagger:response IdentitySource
+type swaggerResponseIdentitySource struct {
+	// in:body
+	Body api.IdentitySource `json:"body"`
+}
+
+// IdentitySourceList
+// swagger:response IdentitySourceList
+type swaggerResponseIdentitySourceList struct {
+	// in:body
+	Body []api.IdentitySource `json:"body"`
+}
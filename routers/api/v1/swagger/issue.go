@@ -15,6 +15,13 @@ type swaggerResponseIssue struct {
 	Body api.Issue `json:"body"`
 }
 
+// IssueReferenceList
+// swagger:response IssueReferenceList
+type swaggerResponseIssueReferenceList struct {
+	// in:body
+	Body []api.IssueReference `json:"body"`
+}
+
 // IssueList
 // swagger:response IssueList
 type swaggerResponseIssueList struct {
@@ -22,6 +29,13 @@ type swaggerResponseIssueList struct {
 	Body []api.Issue `json:"body"`
 }
 
+// ExportedIssueList
+// swagger:response ExportedIssueList
+type swaggerResponseExportedIssueList struct {
+	// in:body
+	Body []api.ExportedIssue `json:"body"`
+}
+
 // Comment
 // swagger:response Comment
 type swaggerResponseComment struct {
@@ -50,6 +64,13 @@ type swaggerResponseLabelList struct {
 	Body []api.Label `json:"body"`
 }
 
+// CopiedLabels
+// swagger:response CopiedLabels
+type swaggerResponseCopiedLabels struct {
+	// in:body
+	Body api.CopiedLabels `json:"body"`
+}
+
 // Milestone
 // swagger:response Milestone
 type swaggerResponseMilestone struct {
@@ -64,6 +85,13 @@ type swaggerResponseMilestoneList struct {
 	Body []api.Milestone `json:"body"`
 }
 
+// CopiedMilestones
+// swagger:response CopiedMilestones
+type swaggerResponseCopiedMilestones struct {
+	// in:body
+	Body api.CopiedMilestones `json:"body"`
+}
+
 // TrackedTime
 // swagger:response TrackedTime
 type swaggerResponseTrackedTime struct {
@@ -92,6 +120,13 @@ type swaggerIssueTemplates struct {
 	Body []api.IssueTemplate `json:"body"`
 }
 
+// IssueTemplate
+// swagger:response IssueTemplate
+type swaggerIssueTemplate struct {
+	// in:body
+	Body api.IssueTemplate `json:"body"`
+}
+
 // StopWatch
 // swagger:response StopWatch
 type swaggerResponseStopWatch struct {
@@ -106,6 +141,13 @@ type swaggerResponseStopWatchList struct {
 	Body []api.StopWatch `json:"body"`
 }
 
+// IssueSubscriptionList
+// swagger:response IssueSubscriptionList
+type swaggerResponseIssueSubscriptionList struct {
+	// in:body
+	Body []api.IssueSubscription `json:"body"`
+}
+
 // Reaction
 // swagger:response Reaction
 type swaggerReaction struct {
@@ -36,6 +36,13 @@ type swaggerResponseGPGKeyList struct {
 	Body []api.GPGKey `json:"body"`
 }
 
+// GPGKeyStatusList
+// swagger:response GPGKeyStatusList
+type swaggerResponseGPGKeyStatusList struct {
+	// in:body
+	Body []api.GPGKeyStatus `json:"body"`
+}
+
 // DeployKey
 // swagger:response DeployKey
 type swaggerResponseDeployKey struct {
@@ -53,6 +53,8 @@ type swaggerParameterBodies struct {
 	CreateLabelOption api.CreateLabelOption
 	// in:body
 	EditLabelOption api.EditLabelOption
+	// in:body
+	CopyLabelsOption api.CopyLabelsOption
 
 	// in:body
 	MarkdownOption api.MarkdownOption
@@ -61,6 +63,8 @@ type swaggerParameterBodies struct {
 	CreateMilestoneOption api.CreateMilestoneOption
 	// in:body
 	EditMilestoneOption api.EditMilestoneOption
+	// in:body
+	CopyMilestonesOption api.CopyMilestonesOption
 
 	// in:body
 	CreateOrgOption api.CreateOrgOption
@@ -104,6 +108,9 @@ type swaggerParameterBodies struct {
 	// in:body
 	CreateUserOption api.CreateUserOption
 
+	// in:body
+	CreateUsersOption api.CreateUsersOption
+
 	// in:body
 	EditUserOption api.EditUserOption
 
@@ -125,9 +132,18 @@ type swaggerParameterBodies struct {
 	// in:body
 	CommitDateOptions api.CommitDateOptions
 
+	// in:body
+	ApplyCommitOption api.ApplyCommitOption
+
 	// in:body
 	RepoTopicOptions api.RepoTopicOptions
 
+	// in:body
+	RenameTopicOption api.RenameTopicOption
+
+	// in:body
+	MergeTopicOption api.MergeTopicOption
+
 	// in:body
 	EditReactionOption api.EditReactionOption
 
@@ -158,9 +174,15 @@ type swaggerParameterBodies struct {
 	// in:body
 	MigrateRepoOptions api.MigrateRepoOptions
 
+	// in:body
+	SyncMigrationOptions api.SyncMigrationOptions
+
 	// in:body
 	PullReviewRequestOptions api.PullReviewRequestOptions
 
+	// in:body
+	UpdatePullViewedFilesOption api.UpdatePullViewedFilesOption
+
 	// in:body
 	CreateTagOption api.CreateTagOption
 
@@ -169,4 +191,47 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	UserSettingsOptions api.UserSettingsOptions
+
+	// in:body
+	SetNoteOption api.SetNoteOption
+
+	// in:body
+	RotateHookSecretOption api.RotateHookSecretOption
+
+	// in:body
+	EditPushRulesOption api.EditPushRulesOption
+
+	// in:body
+	EditRepoGitSettingsOption api.EditRepoGitSettingsOption
+
+	// in:body
+	ExternalTrackerPreviewOption api.ExternalTrackerPreviewOption
+
+	// in:body
+	SetRepoMetadataOption api.SetRepoMetadataOption
+
+	// in:body
+	EditStaleBranchCleanupOption api.EditStaleBranchCleanupOption
+
+	// in:body
+	CreateSavedReplyOption api.CreateSavedReplyOption
+	// in:body
+	EditSavedReplyOption api.EditSavedReplyOption
+
+	// in:body
+	CreateIdentitySourceOption api.CreateIdentitySourceOption
+	// in:body
+	EditIdentitySourceOption api.EditIdentitySourceOption
+
+	// in:body
+	CreateRepoBackupOption api.CreateRepoBackupOption
+
+	// in:body
+	DeleteNoticesOption api.DeleteNoticesOption
+
+	// in:body
+	DetachForkOption api.DetachForkOption
+
+	// in:body
+	CreateRepoCloneTokenOption api.CreateRepoCloneTokenOption
 }
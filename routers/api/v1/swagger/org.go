@@ -42,3 +42,10 @@ type swaggerResponseOrganizationPermissions struct {
 	// in:body
 	Body api.OrganizationPermissions `json:"body"`
 }
+
+// AccessibleRepositoryList
+// swagger:response AccessibleRepositoryList
+type swaggerResponseAccessibleRepositoryList struct {
+	// in:body
+	Body []api.AccessibleRepository `json:"body"`
+}
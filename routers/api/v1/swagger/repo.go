@@ -36,6 +36,27 @@ type swaggerResponseBranchList struct {
 	Body []api.Branch `json:"body"`
 }
 
+// StaleBranchList
+// swagger:response StaleBranchList
+type swaggerStaleBranchList struct {
+	// in:body
+	Body []api.StaleBranch `json:"body"`
+}
+
+// ForkSyncStatus
+// swagger:response ForkSyncStatus
+type swaggerForkSyncStatus struct {
+	// in:body
+	Body api.ForkSyncStatus `json:"body"`
+}
+
+// StaleBranchCleanup
+// swagger:response StaleBranchCleanup
+type swaggerStaleBranchCleanup struct {
+	// in:body
+	Body api.StaleBranchCleanup `json:"body"`
+}
+
 // BranchProtection
 // swagger:response BranchProtection
 type swaggerResponseBranchProtection struct {
@@ -92,6 +113,13 @@ type swaggerResponseHook struct {
 	Body api.Hook `json:"body"`
 }
 
+// HookSecret
+// swagger:response HookSecret
+type swaggerResponseHookSecret struct {
+	// in:body
+	Body api.HookSecret `json:"body"`
+}
+
 // HookList
 // swagger:response HookList
 type swaggerResponseHookList struct {
@@ -141,6 +169,13 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// MergeQueueEntry
+// swagger:response MergeQueueEntry
+type swaggerResponseMergeQueueEntry struct {
+	// in:body
+	Body api.MergeQueueEntry `json:"body"`
+}
+
 // PullReview
 // swagger:response PullReview
 type swaggerResponsePullReview struct {
@@ -155,6 +190,20 @@ type swaggerResponsePullReviewList struct {
 	Body []api.PullReview `json:"body"`
 }
 
+// ChangedFileList
+// swagger:response ChangedFileList
+type swaggerResponseChangedFileList struct {
+	// in:body
+	Body []api.ChangedFile `json:"body"`
+}
+
+// PullViewedFileList
+// swagger:response PullViewedFileList
+type swaggerResponsePullViewedFileList struct {
+	// in:body
+	Body []api.PullViewedFile `json:"body"`
+}
+
 // PullComment
 // swagger:response PullReviewComment
 type swaggerPullReviewComment struct {
@@ -211,6 +260,62 @@ type swaggerResponseAttachment struct {
 	Body api.Attachment `json:"body"`
 }
 
+// ReleaseAttachmentTask
+// swagger:response ReleaseAttachmentTask
+type swaggerResponseReleaseAttachmentTask struct {
+	// in: body
+	Body api.ReleaseAttachmentTask `json:"body"`
+}
+
+// RepoHealthCheck
+// swagger:response RepoHealthCheck
+type swaggerResponseRepoHealthCheck struct {
+	// in: body
+	Body api.RepoHealthCheck `json:"body"`
+}
+
+// RepoPermissions
+// swagger:response RepoPermissions
+type swaggerResponseRepoPermissions struct {
+	// in: body
+	Body api.RepoPermissions `json:"body"`
+}
+
+// RepoCollaboratorPermission
+// swagger:response RepoCollaboratorPermission
+type swaggerResponseRepoCollaboratorPermission struct {
+	// in: body
+	Body api.RepoCollaboratorPermission `json:"body"`
+}
+
+// RepoDetachFork
+// swagger:response RepoDetachFork
+type swaggerResponseRepoDetachFork struct {
+	// in: body
+	Body api.RepoDetachFork `json:"body"`
+}
+
+// RepoCloneToken
+// swagger:response RepoCloneToken
+type swaggerResponseRepoCloneToken struct {
+	// in: body
+	Body api.RepoCloneToken `json:"body"`
+}
+
+// RepoCloneTokenList
+// swagger:response RepoCloneTokenList
+type swaggerResponseRepoCloneTokenList struct {
+	// in: body
+	Body []api.RepoCloneToken `json:"body"`
+}
+
+// MigrationTask
+// swagger:response MigrationTask
+type swaggerResponseMigrationTask struct {
+	// in: body
+	Body api.MigrationTask `json:"body"`
+}
+
 // GitTreeResponse
 // swagger:response GitTreeResponse
 type swaggerGitTreeResponse struct {
@@ -232,6 +337,13 @@ type swaggerCommit struct {
 	Body api.Commit `json:"body"`
 }
 
+// ApplyCommitConflict
+// swagger:response ApplyCommitConflict
+type swaggerApplyCommitConflict struct {
+	// in: body
+	Body api.ApplyCommitConflict `json:"body"`
+}
+
 // CommitList
 // swagger:response CommitList
 type swaggerCommitList struct {
@@ -261,6 +373,13 @@ type swaggerNote struct {
 	Body api.Note `json:"body"`
 }
 
+// Compare
+// swagger:response Compare
+type swaggerCompare struct {
+	// in: body
+	Body api.Compare `json:"body"`
+}
+
 // EmptyRepository
 // swagger:response EmptyRepository
 type swaggerEmptyRepository struct {
@@ -303,6 +422,13 @@ type swaggerTopicListResponse struct {
 	Body []api.TopicResponse `json:"body"`
 }
 
+// Topic
+// swagger:response Topic
+type swaggerTopic struct {
+	// in: body
+	Body api.TopicResponse `json:"body"`
+}
+
 // TopicNames
 // swagger:response TopicNames
 type swaggerTopicNames struct {
@@ -317,9 +443,89 @@ type swaggerLanguageStatistics struct {
 	Body map[string]int64 `json:"body"`
 }
 
+// CodeOwnersErrors
+// swagger:response CodeOwnersErrors
+type swaggerCodeOwnersErrors struct {
+	// in: body
+	Body api.CodeOwnersErrors `json:"body"`
+}
+
 // CombinedStatus
 // swagger:response CombinedStatus
 type swaggerCombinedStatus struct {
 	// in: body
 	Body api.CombinedStatus `json:"body"`
 }
+
+// PushRules
+// swagger:response PushRules
+type swaggerPushRules struct {
+	// in: body
+	Body api.PushRules `json:"body"`
+}
+
+// RepoGitSettings
+// swagger:response RepoGitSettings
+type swaggerRepoGitSettings struct {
+	// in: body
+	Body api.RepoGitSettings `json:"body"`
+}
+
+// ExternalTrackerPreview
+// swagger:response ExternalTrackerPreview
+type swaggerExternalTrackerPreview struct {
+	// in: body
+	Body api.ExternalTrackerPreview `json:"body"`
+}
+
+// RepoMetadata
+// swagger:response RepoMetadata
+type swaggerRepoMetadata struct {
+	// entity tag of the value
+	ETag string `json:"ETag"`
+
+	// in: body
+	Body api.RepoMetadata `json:"body"`
+}
+
+// RepoMetadataList
+// swagger:response RepoMetadataList
+type swaggerRepoMetadataList struct {
+	// in: body
+	Body []api.RepoMetadata `json:"body"`
+}
+
+// PullRequestRefsCleanup
+// swagger:response PullRequestRefsCleanup
+type swaggerResponsePullRequestRefsCleanup struct {
+	// in:body
+	Body api.PullRequestRefsCleanup `json:"body"`
+}
+
+// RepoBackup
+// swagger:response RepoBackup
+type swaggerRepoBackup struct {
+	// in: body
+	Body api.RepoBackup `json:"body"`
+}
+
+// RepoBackupList
+// swagger:response RepoBackupList
+type swaggerRepoBackupList struct {
+	// in: body
+	Body []api.RepoBackup `json:"body"`
+}
+
+// RepoCloneTraffic
+// swagger:response RepoCloneTraffic
+type swaggerRepoCloneTraffic struct {
+	// in: body
+	Body api.RepoCloneTraffic `json:"body"`
+}
+
+// RepoViewTraffic
+// swagger:response RepoViewTraffic
+type swaggerRepoViewTraffic struct {
+	// in: body
+	Body api.RepoViewTraffic `json:"body"`
+}
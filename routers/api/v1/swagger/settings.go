@@ -33,3 +33,10 @@ type swaggerResponseGeneralAttachmentSettings struct {
 	// in:body
 	Body api.GeneralAttachmentSettings `json:"body"`
 }
+
+// GeneralSSHSettings
+// swagger:response GeneralSSHSettings
+type swaggerResponseGeneralSSHSettings struct {
+	// in:body
+	Body api.GeneralSSHSettings `json:"body"`
+}
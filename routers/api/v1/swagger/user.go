@@ -23,6 +23,13 @@ type swaggerResponseUserList struct {
 	Body []api.User `json:"body"`
 }
 
+// CreateUsersResultList
+// swagger:response CreateUsersResultList
+type swaggerResponseCreateUsersResultList struct {
+	// in:body
+	Body []api.CreateUsersResult `json:"body"`
+}
+
 // EmailList
 // swagger:response EmailList
 type swaggerResponseEmailList struct {
@@ -49,3 +56,38 @@ type swaggerResponseUserSettings struct {
 	// in:body
 	Body []api.UserSettings `json:"body"`
 }
+
+// SavedReply
+// swagger:response SavedReply
+type swaggerResponseSavedReply struct {
+	// in:body
+	Body api.SavedReply `json:"body"`
+}
+
+// SavedReplyList
+// swagger:response SavedReplyList
+type swaggerResponseSavedReplyList struct {
+	// in:body
+	Body []api.SavedReply `json:"body"`
+}
+
+// ProfileReadme
+// swagger:response ProfileReadme
+type swaggerResponseProfileReadme struct {
+	// in:body
+	Body api.ProfileReadme `json:"body"`
+}
+
+// UserExport
+// swagger:response UserExport
+type swaggerResponseUserExport struct {
+	// in: body
+	Body api.UserExport `json:"body"`
+}
+
+// UserExportList
+// swagger:response UserExportList
+type swaggerResponseUserExportList struct {
+	// in: body
+	Body []api.UserExport `json:"body"`
+}
@@ -60,11 +60,7 @@ func ListAccessTokens(ctx *context.APIContext) {
 
 	apiTokens := make([]*api.AccessToken, len(tokens))
 	for i := range tokens {
-		apiTokens[i] = &api.AccessToken{
-			ID:             tokens[i].ID,
-			Name:           tokens[i].Name,
-			TokenLastEight: tokens[i].TokenLastEight,
-		}
+		apiTokens[i] = convert.ToAccessToken(tokens[i])
 	}
 
 	ctx.SetTotalCountHeader(count)
@@ -117,12 +113,9 @@ func CreateAccessToken(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "NewAccessToken", err)
 		return
 	}
-	ctx.JSON(http.StatusCreated, &api.AccessToken{
-		Name:           t.Name,
-		Token:          t.Token,
-		ID:             t.ID,
-		TokenLastEight: t.TokenLastEight,
-	})
+	apiToken := convert.ToAccessToken(t)
+	apiToken.Token = t.Token
+	ctx.JSON(http.StatusCreated, apiToken)
 }
 
 // DeleteAccessToken delete access tokens
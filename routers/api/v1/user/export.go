@@ -0,0 +1,168 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/task"
+	"code.gitea.io/gitea/routers/common"
+)
+
+// toUserExport converts a task exporting the data of a user and adds a signed download link if the archive is available
+func toUserExport(ctx *context.APIContext, t *models.Task) *api.UserExport {
+	export, err := t.UserExport()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "UserExport", err)
+		return nil
+	}
+	apiExport := convert.ToUserExport(t, export)
+	if t.Status == api.TaskStatusFinished && !export.Purged {
+		var expires time.Time
+		apiExport.DownloadURL, expires = task.UserExportDownloadURL(t)
+		apiExport.DownloadURLExpires = &expires
+	}
+	return apiExport
+}
+
+// ExportMyData queues an export of the data of the authenticated user
+func ExportMyData(ctx *context.APIContext) {
+	// swagger:operation POST /user/export user userExportData
+	// ---
+	// summary: Export the data of the authenticated user
+	// description: Builds a zip archive of JSON files with the profile, emails, SSH and GPG keys, repositories, authored issues, pull requests and comments, starred repositories, follows and activity of the user in the background. Data of repositories the user can no longer access is left out. The data can be exported once per day.
+	// produces:
+	// - application/json
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/UserExport"
+	//   "429":
+	//     "$ref": "#/responses/error"
+
+	t, err := task.ExportUserData(ctx.User)
+	if err != nil {
+		if task.IsErrUserExportTooFrequent(err) {
+			ctx.Resp.Header().Set("Retry-After", fmt.Sprint(int64(time.Until(err.(task.ErrUserExportTooFrequent).Next).Seconds())+1))
+			ctx.Error(http.StatusTooManyRequests, "", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "ExportUserData", err)
+		return
+	}
+	apiExport := toUserExport(ctx, t)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusAccepted, apiExport)
+}
+
+// ListMyExports lists the exports of the data of the authenticated user
+func ListMyExports(ctx *context.APIContext) {
+	// swagger:operation GET /user/export user userListExports
+	// ---
+	// summary: List the exports of the data of the authenticated user, the newest first
+	// description: Finished exports which are not purged yet have a signed link to download their archive.
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserExportList"
+
+	tasks, err := models.FindUserExportTasks(ctx.User.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindUserExportTasks", err)
+		return
+	}
+	apiExports := make([]*api.UserExport, 0, len(tasks))
+	for _, t := range tasks {
+		apiExport := toUserExport(ctx, t)
+		if ctx.Written() {
+			return
+		}
+		apiExports = append(apiExports, apiExport)
+	}
+	ctx.JSON(http.StatusOK, apiExports)
+}
+
+// DownloadExport downloads the archive of an export of the data of a user through a signed link
+func DownloadExport(ctx *context.APIContext) {
+	// swagger:operation GET /user/export/{id}/download user userDownloadExport
+	// ---
+	// summary: Download the archive of an export of user data
+	// description: The signed link is returned by the list of exports, no further authentication is needed.
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the export
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: expires
+	//   in: query
+	//   description: expiry of the link as a unix timestamp
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: signature
+	//   in: query
+	//   description: signature of the link
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     description: the zip archive
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := models.GetTaskByID(ctx.ParamsInt64(":id"))
+	if err != nil || t.Type != api.TaskTypeUserExport || t.Status != api.TaskStatusFinished {
+		ctx.NotFound()
+		return
+	}
+	if !task.VerifyUserExportDownload(t, ctx.FormInt64("expires"), ctx.FormString("signature")) {
+		ctx.NotFound()
+		return
+	}
+	export, err := t.UserExport()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "UserExport", err)
+		return
+	}
+	if export.Purged || export.Path == "" {
+		ctx.NotFound()
+		return
+	}
+
+	name := fmt.Sprintf("export-%d.zip", t.ID)
+	if setting.UserExport.ServeDirect {
+		// If we have a signed url (S3, object storage), redirect to this directly.
+		u, err := storage.UserExports.URL(export.Path, name)
+		if u != nil && err == nil {
+			ctx.Redirect(u.String())
+			return
+		}
+	}
+
+	fr, err := storage.UserExports.Open(export.Path)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Open", err)
+		return
+	}
+	defer fr.Close()
+
+	if err := common.ServeData(ctx.Context, name, export.Size, fr); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ServeData", err)
+	}
+}
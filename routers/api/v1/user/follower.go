@@ -20,6 +20,24 @@ func responseAPIUsers(ctx *context.APIContext, users []*models.User) {
 	for i := range users {
 		apiUsers[i] = convert.ToUser(users[i], ctx.User)
 	}
+
+	// Tell the signed in user how they relate to the users of the page
+	if ctx.User != nil {
+		userIDs := make([]int64, len(users))
+		for i := range users {
+			userIDs[i] = users[i].ID
+		}
+		isFollowing, followsYou, err := models.GetFollowRelations(ctx.User.ID, userIDs)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetFollowRelations", err)
+			return
+		}
+		for i := range apiUsers {
+			following, follows := isFollowing[apiUsers[i].ID], followsYou[apiUsers[i].ID]
+			apiUsers[i].IsFollowing = &following
+			apiUsers[i].FollowsYou = &follows
+		}
+	}
 	ctx.JSON(http.StatusOK, &apiUsers)
 }
 
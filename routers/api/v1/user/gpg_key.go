@@ -160,6 +160,35 @@ func GetVerificationToken(ctx *context.APIContext) {
 	ctx.PlainText(http.StatusOK, []byte(token))
 }
 
+// GetMyGPGKeyStatus returns the expiry and verification status of the GPG keys of the authenticated user
+func GetMyGPGKeyStatus(ctx *context.APIContext) {
+	// swagger:operation GET /user/gpg_key_status user userCurrentGetGPGKeyStatus
+	// ---
+	// summary: Get the expiry and verification status of the authenticated user's GPG keys
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GPGKeyStatusList"
+
+	keys, err := models.ListGPGKeys(ctx.User.ID, db.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListGPGKeys", err)
+		return
+	}
+
+	statuses := make([]*api.GPGKeyStatus, len(keys))
+	for i, key := range keys {
+		emails, err := key.VerifiableEmails()
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "VerifiableEmails", err)
+			return
+		}
+		statuses[i] = convert.ToGPGKeyStatus(key, emails)
+	}
+	ctx.JSON(http.StatusOK, &statuses)
+}
+
 // VerifyUserGPGKey creates new GPG key to given user by ID.
 func VerifyUserGPGKey(ctx *context.APIContext) {
 	// swagger:operation POST /user/gpg_key_verify user userVerifyGPGKey
@@ -0,0 +1,198 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// getSubscriptionKind parses the kind query parameter of the issue subscription endpoints
+func getSubscriptionKind(ctx *context.APIContext) (models.IssueSubscriptionKind, bool) {
+	switch ctx.FormString("kind") {
+	case "", "all":
+		return models.IssueSubscriptionAll, true
+	case "explicit":
+		return models.IssueSubscriptionExplicit, true
+	case "implicit":
+		return models.IssueSubscriptionImplicit, true
+	}
+	ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("unknown subscription kind %q", ctx.FormString("kind")))
+	return 0, false
+}
+
+// getSubscriptionRepo looks up the repository given by the repo query parameter as owner/name,
+// it returns nil without writing a response if the parameter is empty
+func getSubscriptionRepo(ctx *context.APIContext) (*models.Repository, bool) {
+	fullName := ctx.FormString("repo")
+	if fullName == "" {
+		return nil, true
+	}
+	parts := strings.SplitN(fullName, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("repo must be given as owner/name"))
+		return nil, false
+	}
+
+	repo, err := models.GetRepositoryByOwnerAndName(parts[0], parts[1])
+	if err != nil {
+		if models.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+		}
+		return nil, false
+	}
+	perm, err := models.GetUserRepoPermission(repo, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return nil, false
+	}
+	if !perm.HasAccess() {
+		ctx.NotFound()
+		return nil, false
+	}
+	return repo, true
+}
+
+// ListMyIssueSubscriptions lists the issues and pull requests the authenticated user is subscribed to
+func ListMyIssueSubscriptions(ctx *context.APIContext) {
+	// swagger:operation GET /user/subscriptions/issues user userListIssueSubscriptions
+	// ---
+	// summary: List the issues and pull requests the authenticated user is subscribed to
+	// description: Subscriptions by watching a repository are not included.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: repo
+	//   in: query
+	//   description: only list the subscriptions in this repository, given as owner/name
+	//   type: string
+	// - name: state
+	//   in: query
+	//   description: whether issue is open or closed
+	//   type: string
+	//   enum: [closed, open, all]
+	// - name: kind
+	//   in: query
+	//   description: only list the explicit subscriptions or the implicit ones from posting or commenting
+	//   type: string
+	//   enum: [explicit, implicit, all]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSubscriptionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	kind, ok := getSubscriptionKind(ctx)
+	if !ok {
+		return
+	}
+	repo, ok := getSubscriptionRepo(ctx)
+	if !ok {
+		return
+	}
+
+	var isClosed util.OptionalBool
+	switch ctx.FormString("state") {
+	case "closed":
+		isClosed = util.OptionalBoolTrue
+	case "all":
+		isClosed = util.OptionalBoolNone
+	default:
+		isClosed = util.OptionalBoolFalse
+	}
+
+	opts := &models.FindIssueSubscriptionsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		UserID:      ctx.User.ID,
+		IsClosed:    isClosed,
+		Kind:        kind,
+	}
+	if repo != nil {
+		opts.RepoID = repo.ID
+	}
+
+	subscriptions, count, err := models.FindIssueSubscriptions(ctx.User, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindIssueSubscriptions", err)
+		return
+	}
+
+	apiSubscriptions := make([]*api.IssueSubscription, len(subscriptions))
+	for i, subscription := range subscriptions {
+		apiSubscriptions[i] = &api.IssueSubscription{
+			Issue:    convert.ToAPIIssue(subscription.Issue),
+			Explicit: subscription.Explicit,
+		}
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiSubscriptions)
+}
+
+// UnsubscribeMyIssues unsubscribes the authenticated user from the issues and pull requests of a repository
+func UnsubscribeMyIssues(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/subscriptions/issues user userUnsubscribeIssues
+	// ---
+	// summary: Unsubscribe the authenticated user from the issues and pull requests of a repository
+	// description: The user stays unsubscribed when posting or commenting on the issues later.
+	// parameters:
+	// - name: repo
+	//   in: query
+	//   description: repository of the issues, given as owner/name
+	//   type: string
+	//   required: true
+	// - name: kind
+	//   in: query
+	//   description: only remove the explicit subscriptions or the implicit ones from posting or commenting
+	//   type: string
+	//   enum: [explicit, implicit, all]
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	kind, ok := getSubscriptionKind(ctx)
+	if !ok {
+		return
+	}
+	if ctx.FormString("repo") == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("repo is required"))
+		return
+	}
+	repo, ok := getSubscriptionRepo(ctx)
+	if !ok {
+		return
+	}
+
+	if err := models.UnsubscribeIssues(ctx.User.ID, repo.ID, kind); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnsubscribeIssues", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
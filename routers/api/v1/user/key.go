@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 // appendPrivateInformation appends the owner and key type information to api.PublicKey
@@ -193,15 +194,15 @@ func GetPublicKey(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, apiKey)
 }
 
-// CreateUserPublicKey creates new public key to given user by ID.
-func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid int64) {
+// CreateUserPublicKey creates new public key to given user.
+func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, owner *models.User) {
 	content, err := models.CheckPublicKeyString(form.Key)
 	if err != nil {
 		repo.HandleCheckKeyStringError(ctx, err)
 		return
 	}
 
-	key, err := models.AddPublicKey(uid, form.Title, content, 0)
+	key, err := user_service.AddPublicKey(ctx.User, owner, form.Title, content)
 	if err != nil {
 		repo.HandleAddKeyError(ctx, err)
 		return
@@ -235,7 +236,7 @@ func CreatePublicKey(ctx *context.APIContext) {
 	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.CreateKeyOption)
-	CreateUserPublicKey(ctx, *form, ctx.User.ID)
+	CreateUserPublicKey(ctx, *form, ctx.User)
 }
 
 // DeletePublicKey delete one public key
@@ -269,7 +270,7 @@ func DeletePublicKey(ctx *context.APIContext) {
 		ctx.Error(http.StatusForbidden, "", "SSH Key is externally managed for this user")
 	}
 
-	if err := models.DeletePublicKey(ctx.User, id); err != nil {
+	if err := user_service.DeletePublicKey(ctx.User, id); err != nil {
 		if models.IsErrKeyNotExist(err) {
 			ctx.NotFound()
 		} else if models.IsErrKeyAccessDenied(err) {
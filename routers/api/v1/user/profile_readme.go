@@ -0,0 +1,65 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetProfileReadme get the README shown on the profile of a user or an organization
+func GetProfileReadme(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/profile_readme user userGetProfileReadme
+	// ---
+	// summary: Get the README shown on the profile of a user or an organization
+	// description: The README is read from the default branch of the repository named `.profile`,
+	//              or of the repository named like the user if it doesn't exist.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ProfileReadme"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	u := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if !u.IsVisibleToUser(ctx.User) {
+		// fake ErrUserNotExist error message to not leak information about existence
+		ctx.NotFound("GetUserByName", models.ErrUserNotExist{Name: ctx.Params(":username")})
+		return
+	}
+
+	readme, err := repo_service.GetProfileReadme(ctx, u, ctx.User)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProfileReadme", err)
+		return
+	}
+	if readme == nil {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &api.ProfileReadme{
+		Repository: readme.Repo.Name,
+		FileName:   readme.FileName,
+		CommitID:   readme.CommitID,
+		Content:    readme.Content,
+		HTML:       readme.HTML,
+	})
+}
@@ -5,15 +5,32 @@
 package user
 
 import (
+	"fmt"
 	"net/http"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/convert"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
+var orgRepoOrderByMap = map[string]map[string]models.SearchOrderBy{
+	"asc": {
+		"size":        models.SearchOrderBySize,
+		"updated":     models.SearchOrderByLeastUpdated,
+		"open_issues": models.SearchOrderByOpenIssues,
+		"open_pulls":  models.SearchOrderByOpenPulls,
+	},
+	"desc": {
+		"size":        models.SearchOrderBySizeReverse,
+		"updated":     models.SearchOrderByRecentUpdated,
+		"open_issues": models.SearchOrderByOpenIssuesReverse,
+		"open_pulls":  models.SearchOrderByOpenPullsReverse,
+	},
+}
+
 // listUserRepos - List the repositories owned by the given user.
 func listUserRepos(ctx *context.APIContext, u *models.User, private bool) {
 	opts := utils.GetListOptions(ctx)
@@ -145,6 +162,17 @@ func ListOrgRepos(ctx *context.APIContext) {
 	//   description: name of the organization
 	//   type: string
 	//   required: true
+	// - name: sort
+	//   in: query
+	//   description: sort repos by attribute. Supported values are
+	//                "size", "updated", "open_issues" and "open_pulls".
+	//                Default is by id
+	//   type: string
+	// - name: order
+	//   in: query
+	//   description: sort order, either "asc" (ascending) or "desc" (descending).
+	//                Default is "asc", ignored if "sort" is not specified.
+	//   type: string
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -156,6 +184,51 @@ func ListOrgRepos(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := &models.SearchRepoOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Actor:       ctx.User,
+		OwnerID:     ctx.Org.Organization.ID,
+		Collaborate: util.OptionalBoolFalse,
+		Private:     ctx.IsSigned,
+		OrderBy:     models.SearchOrderByID,
+	}
+	if sortMode := ctx.FormString("sort"); len(sortMode) > 0 {
+		sortOrder := ctx.FormString("order")
+		if len(sortOrder) == 0 {
+			sortOrder = "asc"
+		}
+		orderByMap, ok := orgRepoOrderByMap[sortOrder]
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid sort order: \"%s\"", sortOrder))
+			return
+		}
+		if opts.OrderBy, ok = orderByMap[sortMode]; !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("Invalid sort mode: \"%s\"", sortMode))
+			return
+		}
+	}
 
-	listUserRepos(ctx, ctx.Org.Organization, ctx.IsSigned)
+	// the repositories the user cannot access are excluded by the query to keep the counts right
+	repos, count, err := models.SearchRepository(opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchRepository", err)
+		return
+	}
+
+	apiRepos := make([]*api.Repository, len(repos))
+	for i, repo := range repos {
+		access, err := models.AccessLevel(ctx.User, repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiRepos[i] = convert.ToRepo(repo, access)
+	}
+
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiRepos)
 }
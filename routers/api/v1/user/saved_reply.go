@@ -0,0 +1,133 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListMySavedReplies lists the saved replies of the authenticated user
+func ListMySavedReplies(ctx *context.APIContext) {
+	// swagger:operation GET /user/saved_replies user userListSavedReplies
+	// ---
+	// summary: List the authenticated user's saved replies
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReplyList"
+
+	utils.ListSavedReplies(ctx, ctx.User.ID)
+}
+
+// GetMySavedReply gets a saved reply of the authenticated user
+func GetMySavedReply(ctx *context.APIContext) {
+	// swagger:operation GET /user/saved_replies/{id} user userGetSavedReply
+	// ---
+	// summary: Get a saved reply of the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	reply, err := utils.GetSavedReply(ctx, ctx.User.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToSavedReply(reply))
+}
+
+// CreateMySavedReply creates a saved reply for the authenticated user
+func CreateMySavedReply(ctx *context.APIContext) {
+	// swagger:operation POST /user/saved_replies user userCreateSavedReply
+	// ---
+	// summary: Create a saved reply for the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateSavedReplyOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/SavedReply"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.CreateSavedReply(ctx, ctx.User.ID, web.GetForm(ctx).(*api.CreateSavedReplyOption))
+}
+
+// EditMySavedReply edits a saved reply of the authenticated user
+func EditMySavedReply(ctx *context.APIContext) {
+	// swagger:operation PATCH /user/saved_replies/{id} user userEditSavedReply
+	// ---
+	// summary: Edit a saved reply of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditSavedReplyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/SavedReply"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditSavedReply(ctx, ctx.User.ID, ctx.ParamsInt64(":id"), web.GetForm(ctx).(*api.EditSavedReplyOption))
+}
+
+// DeleteMySavedReply deletes a saved reply of the authenticated user
+func DeleteMySavedReply(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/saved_replies/{id} user userDeleteSavedReply
+	// ---
+	// summary: Delete a saved reply of the authenticated user
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the saved reply
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteSavedReply(ctx, ctx.User.ID, ctx.ParamsInt64(":id"))
+}
@@ -5,6 +5,7 @@
 package user
 
 import (
+	"fmt"
 	"net/http"
 
 	"code.gitea.io/gitea/models"
@@ -42,6 +43,8 @@ func UpdateUserSettings(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/UserSettings"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.UserSettingsOptions)
 
@@ -73,11 +76,36 @@ func UpdateUserSettings(ctx *context.APIContext) {
 	if form.HideActivity != nil {
 		ctx.User.KeepActivityPrivate = *form.HideActivity
 	}
+	if form.AutoWatch != nil {
+		autoWatch, ok := models.ParseAutoWatchPreference(*form.AutoWatch)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid auto_watch: %q", *form.AutoWatch))
+			return
+		}
+		ctx.User.AutoWatch = autoWatch
+	}
+	if form.IssueSortType != nil {
+		if len(*form.IssueSortType) > 0 && !models.IsValidIssueSortType(*form.IssueSortType) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("invalid issue_sort_type: %q", *form.IssueSortType))
+			return
+		}
+		ctx.User.IssueSortType = *form.IssueSortType
+	}
+	if form.MuteMilestoneReminders != nil {
+		ctx.User.MuteMilestoneReminders = *form.MuteMilestoneReminders
+	}
 
 	if err := models.UpdateUser(ctx.User); err != nil {
 		ctx.InternalServerError(err)
 		return
 	}
 
+	if form.RemoveAutoWatches {
+		if err := models.RemoveAutoWatches(ctx.User.ID); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+
 	ctx.JSON(http.StatusOK, convert.User2UserSettings(ctx.User))
 }
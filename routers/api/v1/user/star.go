@@ -16,17 +16,17 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
-// getStarredRepos returns the repos that the user with the specified userID has
-// starred
-func getStarredRepos(user *models.User, private bool, listOptions db.ListOptions) ([]*api.Repository, error) {
-	starredRepos, err := models.GetStarredRepos(user.ID, private, listOptions)
+// getStarredRepos returns the repos that the user has starred and the doer
+// is allowed to see
+func getStarredRepos(user, doer *models.User, listOptions db.ListOptions) ([]*api.Repository, error) {
+	starredRepos, err := models.GetStarredRepos(user.ID, doer, listOptions)
 	if err != nil {
 		return nil, err
 	}
 
 	repos := make([]*api.Repository, len(starredRepos))
 	for i, starred := range starredRepos {
-		access, err := models.AccessLevel(user, starred)
+		access, err := models.AccessLevel(doer, starred)
 		if err != nil {
 			return nil, err
 		}
@@ -61,10 +61,10 @@ func GetStarredRepos(ctx *context.APIContext) {
 	//     "$ref": "#/responses/RepositoryList"
 
 	user := GetUserByParams(ctx)
-	private := user.ID == ctx.User.ID
-	repos, err := getStarredRepos(user, private, utils.GetListOptions(ctx))
+	repos, err := getStarredRepos(user, ctx.User, utils.GetListOptions(ctx))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "getStarredRepos", err)
+		return
 	}
 	ctx.JSON(http.StatusOK, &repos)
 }
@@ -89,9 +89,10 @@ func GetMyStarredRepos(ctx *context.APIContext) {
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
 
-	repos, err := getStarredRepos(ctx.User, true, utils.GetListOptions(ctx))
+	repos, err := getStarredRepos(ctx.User, ctx.User, utils.GetListOptions(ctx))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "getStarredRepos", err)
+		return
 	}
 
 	ctx.SetTotalCountHeader(int64(ctx.User.NumStars))
@@ -15,16 +15,16 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 )
 
-// getWatchedRepos returns the repos that the user with the specified userID is watching
-func getWatchedRepos(user *models.User, private bool, listOptions db.ListOptions) ([]*api.Repository, int64, error) {
-	watchedRepos, total, err := models.GetWatchedRepos(user.ID, private, listOptions)
+// getWatchedRepos returns the repos that the user is watching and the doer is allowed to see
+func getWatchedRepos(user, doer *models.User, listOptions db.ListOptions) ([]*api.Repository, int64, error) {
+	watchedRepos, total, err := models.GetWatchedRepos(user.ID, doer, listOptions)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	repos := make([]*api.Repository, len(watchedRepos))
 	for i, watched := range watchedRepos {
-		access, err := models.AccessLevel(user, watched)
+		access, err := models.AccessLevel(doer, watched)
 		if err != nil {
 			return nil, 0, err
 		}
@@ -59,10 +59,10 @@ func GetWatchedRepos(ctx *context.APIContext) {
 	//     "$ref": "#/responses/RepositoryList"
 
 	user := GetUserByParams(ctx)
-	private := user.ID == ctx.User.ID
-	repos, total, err := getWatchedRepos(user, private, utils.GetListOptions(ctx))
+	repos, total, err := getWatchedRepos(user, ctx.User, utils.GetListOptions(ctx))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "getWatchedRepos", err)
+		return
 	}
 
 	ctx.SetTotalCountHeader(total)
@@ -89,9 +89,10 @@ func GetMyWatchedRepos(ctx *context.APIContext) {
 	//   "200":
 	//     "$ref": "#/responses/RepositoryList"
 
-	repos, total, err := getWatchedRepos(ctx.User, true, utils.GetListOptions(ctx))
+	repos, total, err := getWatchedRepos(ctx.User, ctx.User, utils.GetListOptions(ctx))
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "getWatchedRepos", err)
+		return
 	}
 
 	ctx.SetTotalCountHeader(total)
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/context"
@@ -66,13 +67,28 @@ func CheckCreateHookOption(ctx *context.APIContext, form *api.CreateHookOption)
 		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid content type")
 		return false
 	}
+	if algorithm, ok := form.Config["signature_algorithm"]; ok && !models.IsValidHookSignatureAlgorithm(algorithm) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "Invalid signature algorithm")
+		return false
+	}
+	if !isValidDigestWindowDays(form.DigestWindowDays) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "digest_window_days must be between 0 and 365")
+		return false
+	}
 	return true
 }
 
+// maxDigestWindowDays is the maximum number of days summarized by a digest
+const maxDigestWindowDays = 365
+
+func isValidDigestWindowDays(days int) bool {
+	return days >= 0 && days <= maxDigestWindowDays
+}
+
 // AddOrgHook add a hook to an organization. Writes to `ctx` accordingly
 func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	org := ctx.Org.Organization
-	hook, ok := addHook(ctx, form, org.ID, 0)
+	hook, ok := addHook(ctx, form, org.ID, 0, 0)
 	if ok {
 		ctx.JSON(http.StatusCreated, convert.ToHook(org.HomeLink(), hook))
 	}
@@ -81,12 +97,38 @@ func AddOrgHook(ctx *context.APIContext, form *api.CreateHookOption) {
 // AddRepoHook add a hook to a repo. Writes to `ctx` accordingly
 func AddRepoHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	repo := ctx.Repo
-	hook, ok := addHook(ctx, form, 0, repo.Repository.ID)
+	hook, ok := addHook(ctx, form, 0, repo.Repository.ID, 0)
 	if ok {
 		ctx.JSON(http.StatusCreated, convert.ToHook(repo.RepoLink, hook))
 	}
 }
 
+// SetTeamHook set the review request hook of a team, replacing the existing one. Writes to `ctx` accordingly
+func SetTeamHook(ctx *context.APIContext, form *api.CreateHookOption, orgLink string) {
+	team := ctx.Org.Team
+	old, err := models.GetWebhookByTeamID(team.ID)
+	if err != nil && !models.IsErrWebhookNotExist(err) {
+		ctx.Error(http.StatusInternalServerError, "GetWebhookByTeamID", err)
+		return
+	}
+
+	// a team hook is only ever called for review requests
+	form.Events = []string{"pull_request_only"}
+	form.BranchFilter, form.BranchFilters = "", nil
+	hook, ok := addHook(ctx, form, 0, 0, team.ID)
+	if !ok {
+		return
+	}
+
+	if old != nil {
+		if err := models.DeleteWebhookByTeamID(team.ID, old.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "DeleteWebhookByTeamID", err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusCreated, convert.ToHook(orgLink, hook))
+}
+
 func issuesHook(events []string, event string) bool {
 	return util.IsStringInSlice(event, events, true) || util.IsStringInSlice(string(models.HookEventIssues), events, true)
 }
@@ -95,15 +137,27 @@ func pullHook(events []string, event string) bool {
 	return util.IsStringInSlice(event, events, true) || util.IsStringInSlice(string(models.HookEventPullRequest), events, true)
 }
 
-// addHook add the hook specified by `form`, `orgID` and `repoID`. If there is
+// branchFilters returns the branch filters of the options, the deprecated single glob is used if there are none
+func branchFilters(filter string, filters []string) []string {
+	if len(filters) > 0 {
+		return filters
+	}
+	if filter != "" {
+		return []string{filter}
+	}
+	return nil
+}
+
+// addHook add the hook specified by `form`, `orgID`, `repoID` and `teamID`. If there is
 // an error, write to `ctx` accordingly. Return (webhook, ok)
-func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID int64) (*models.Webhook, bool) {
+func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID, teamID int64) (*models.Webhook, bool) {
 	if len(form.Events) == 0 {
 		form.Events = []string{"push"}
 	}
 	w := &models.Webhook{
 		OrgID:       orgID,
 		RepoID:      repoID,
+		TeamID:      teamID,
 		URL:         form.Config["url"],
 		ContentType: models.ToHookContentType(form.Config["content_type"]),
 		Secret:      form.Config["secret"],
@@ -129,12 +183,20 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 				PullRequestSync:      pullHook(form.Events, string(models.HookEventPullRequestSync)),
 				Repository:           util.IsStringInSlice(string(models.HookEventRepository), form.Events, true),
 				Release:              util.IsStringInSlice(string(models.HookEventRelease), form.Events, true),
+				Digest:               util.IsStringInSlice(string(models.HookEventDigest), form.Events, true),
+				DeployKey:            util.IsStringInSlice(string(models.HookEventDeployKey), form.Events, true),
 			},
-			BranchFilter: form.BranchFilter,
+			BranchFilters:    branchFilters(form.BranchFilter, form.BranchFilters),
+			DigestWindowDays: form.DigestWindowDays,
+			DigestSendEmpty:  form.DigestSendEmpty,
 		},
 		IsActive: form.Active,
 		Type:     models.HookType(form.Type),
 	}
+	w.SignatureAlgorithm = models.HookSignatureSHA256
+	if algorithm, ok := form.Config["signature_algorithm"]; ok {
+		w.SignatureAlgorithm = models.HookSignatureAlgorithm(algorithm)
+	}
 	if w.Type == models.SLACK {
 		channel, ok := form.Config["channel"]
 		if !ok {
@@ -170,6 +232,30 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, orgID, repoID
 	return w, true
 }
 
+// RotateHookSecret replaces the secret of webhook `w` with a new random one. Writes the new secret to `ctx`
+func RotateHookSecret(ctx *context.APIContext, form *api.RotateHookSecretOption, w *models.Webhook) {
+	if form.GracePeriod < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "grace_period must not be negative")
+		return
+	}
+
+	secret, err := models.RotateWebhookSecret(w, time.Duration(form.GracePeriod)*time.Second)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RotateWebhookSecret", err)
+		return
+	}
+
+	hookSecret := &api.HookSecret{
+		Secret:        secret,
+		SecretRotated: w.SecretRotatedUnix.AsTime(),
+	}
+	if w.PreviousSecretExpiresUnix > 0 {
+		expires := w.PreviousSecretExpiresUnix.AsTime()
+		hookSecret.PreviousSecretExpires = &expires
+	}
+	ctx.JSON(http.StatusOK, hookSecret)
+}
+
 // EditOrgHook edit webhook `w` according to `form`. Writes to `ctx` accordingly
 func EditOrgHook(ctx *context.APIContext, form *api.EditHookOption, hookID int64) {
 	org := ctx.Org.Organization
@@ -218,6 +304,13 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 			}
 			w.ContentType = models.ToHookContentType(ct)
 		}
+		if algorithm, ok := form.Config["signature_algorithm"]; ok {
+			if !models.IsValidHookSignatureAlgorithm(algorithm) {
+				ctx.Error(http.StatusUnprocessableEntity, "", "Invalid signature algorithm")
+				return false
+			}
+			w.SignatureAlgorithm = models.HookSignatureAlgorithm(algorithm)
+		}
 
 		if w.Type == models.SLACK {
 			if channel, ok := form.Config["channel"]; ok {
@@ -255,7 +348,19 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *models.Webho
 	w.PullRequest = util.IsStringInSlice(string(models.HookEventPullRequest), form.Events, true)
 	w.Repository = util.IsStringInSlice(string(models.HookEventRepository), form.Events, true)
 	w.Release = util.IsStringInSlice(string(models.HookEventRelease), form.Events, true)
-	w.BranchFilter = form.BranchFilter
+	w.Digest = util.IsStringInSlice(string(models.HookEventDigest), form.Events, true)
+	w.DeployKey = util.IsStringInSlice(string(models.HookEventDeployKey), form.Events, true)
+	w.BranchFilters = branchFilters(form.BranchFilter, form.BranchFilters)
+	if form.DigestWindowDays != nil {
+		if !isValidDigestWindowDays(*form.DigestWindowDays) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "digest_window_days must be between 0 and 365")
+			return false
+		}
+		w.DigestWindowDays = *form.DigestWindowDays
+	}
+	if form.DigestSendEmpty != nil {
+		w.DigestSendEmpty = *form.DigestSendEmpty
+	}
 
 	if err := w.UpdateEvent(); err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateEvent", err)
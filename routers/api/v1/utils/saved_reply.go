@@ -0,0 +1,114 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package utils
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListSavedReplies writes the saved replies of a user or an organization to `ctx`
+func ListSavedReplies(ctx *context.APIContext, ownerID int64) {
+	replies, err := models.GetSavedReplies(ownerID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetSavedReplies", err)
+		return
+	}
+
+	apiReplies := make([]*api.SavedReply, len(replies))
+	for i := range replies {
+		apiReplies[i] = convert.ToSavedReply(replies[i])
+	}
+	ctx.SetTotalCountHeader(int64(len(apiReplies)))
+	ctx.JSON(http.StatusOK, &apiReplies)
+}
+
+// GetSavedReply get a saved reply of a user or an organization. If there is an error,
+// write to `ctx` accordingly and return the error
+func GetSavedReply(ctx *context.APIContext, ownerID, id int64) (*models.SavedReply, error) {
+	reply, err := models.GetSavedReplyByID(ownerID, id)
+	if err != nil {
+		if models.IsErrSavedReplyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSavedReplyByID", err)
+		}
+		return nil, err
+	}
+	return reply, nil
+}
+
+// CreateSavedReply creates a saved reply of a user or an organization, writing the result to `ctx`
+func CreateSavedReply(ctx *context.APIContext, ownerID int64, form *api.CreateSavedReplyOption) {
+	reply := &models.SavedReply{
+		OwnerID:  ownerID,
+		Title:    form.Title,
+		Body:     form.Body,
+		Position: form.Position,
+	}
+	if err := models.CreateSavedReply(reply); err != nil {
+		handleSavedReplyError(ctx, "CreateSavedReply", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToSavedReply(reply))
+}
+
+// EditSavedReply edits a saved reply of a user or an organization, writing the result to `ctx`
+func EditSavedReply(ctx *context.APIContext, ownerID, id int64, form *api.EditSavedReplyOption) {
+	reply, err := GetSavedReply(ctx, ownerID, id)
+	if err != nil {
+		return
+	}
+
+	if form.Title != nil {
+		if *form.Title == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "title must not be empty")
+			return
+		}
+		reply.Title = *form.Title
+	}
+	if form.Body != nil {
+		if *form.Body == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "body must not be empty")
+			return
+		}
+		reply.Body = *form.Body
+	}
+	if form.Position != nil {
+		reply.Position = *form.Position
+	}
+
+	if err := models.UpdateSavedReply(reply); err != nil {
+		handleSavedReplyError(ctx, "UpdateSavedReply", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToSavedReply(reply))
+}
+
+// DeleteSavedReply deletes a saved reply of a user or an organization, writing the result to `ctx`
+func DeleteSavedReply(ctx *context.APIContext, ownerID, id int64) {
+	if err := models.DeleteSavedReply(ownerID, id); err != nil {
+		if models.IsErrSavedReplyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteSavedReply", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func handleSavedReplyError(ctx *context.APIContext, title string, err error) {
+	switch {
+	case models.IsErrSavedReplyLimitReached(err), models.IsErrSavedReplyTooLong(err):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	default:
+		ctx.Error(http.StatusInternalServerError, title, err)
+	}
+}
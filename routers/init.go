@@ -40,6 +40,8 @@ import (
 	mirror_service "code.gitea.io/gitea/services/mirror"
 	pull_service "code.gitea.io/gitea/services/pull"
 	"code.gitea.io/gitea/services/repository"
+	traffic_service "code.gitea.io/gitea/services/traffic"
+	visit_service "code.gitea.io/gitea/services/visit"
 	"code.gitea.io/gitea/services/webhook"
 
 	"gitea.com/go-chi/session"
@@ -129,6 +131,8 @@ func GlobalInit(ctx context.Context) {
 		log.Fatal("Failed to initialize repository migrations: %v", err)
 	}
 	eventsource.GetManager().Init()
+	traffic_service.Init()
+	visit_service.Init()
 
 	if setting.SSH.StartBuiltinServer {
 		ssh.Listen(setting.SSH.ListenHost, setting.SSH.ListenPort, setting.SSH.ServerCiphers, setting.SSH.ServerKeyExchanges, setting.SSH.ServerMACs)
@@ -90,8 +90,9 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
-	// Handle Push Options
-	if len(opts.GitPushOptions) > 0 {
+	// Handle Push Options, the options of AGit flow pushes are about the pull request instead
+	var messages []string
+	if len(opts.GitPushOptions) > 0 && !isAGitPush(opts.RefFullNames) {
 		// load the repository
 		if repo == nil {
 			repo = loadRepository(ctx, ownerName, repoName)
@@ -102,13 +103,25 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 			wasEmpty = repo.IsEmpty
 		}
 
-		repo.IsPrivate = opts.GitPushOptions.Bool(private.GitPushOptionRepoPrivate, repo.IsPrivate)
-		repo.IsTemplate = opts.GitPushOptions.Bool(private.GitPushOptionRepoTemplate, repo.IsTemplate)
-		if err := models.UpdateRepositoryCols(repo, "is_private", "is_template"); err != nil {
-			log.Error("Failed to Update: %s/%s Error: %v", ownerName, repoName, err)
+		var pusher *models.User
+		if !opts.IsDeployKey {
+			var err error
+			if pusher, err = models.GetUserByID(opts.UserID); err != nil {
+				log.Error("Failed to get pusher: %d Error: %v", opts.UserID, err)
+				ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
+					Err: fmt.Sprintf("Failed to get pusher: %d Error: %v", opts.UserID, err),
+				})
+				return
+			}
+		}
+
+		var err error
+		if messages, err = repo_service.ApplyPushOptions(pusher, repo, opts.GitPushOptions); err != nil {
+			log.Error("Failed to apply push options: %s/%s Error: %v", ownerName, repoName, err)
 			ctx.JSON(http.StatusInternalServerError, private.HookPostReceiveResult{
-				Err: fmt.Sprintf("Failed to Update: %s/%s Error: %v", ownerName, repoName, err),
+				Err: fmt.Sprintf("Failed to apply push options: %s/%s Error: %v", ownerName, repoName, err),
 			})
+			return
 		}
 	}
 
@@ -126,7 +139,7 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		branch := git.RefEndName(opts.RefFullNames[i])
 
 		// If we've pushed a branch (and not deleted it)
-		if newCommitID != git.EmptySHA && strings.HasPrefix(refFullName, git.BranchPrefix) {
+		if !git.IsEmptyCommitID(newCommitID) && strings.HasPrefix(refFullName, git.BranchPrefix) {
 
 			// First ensure we have the repository loaded, we're allowed pulls requests and we can get the base repo
 			if repo == nil {
@@ -139,6 +152,7 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 					// We can stop there's no need to go any further
 					ctx.JSON(http.StatusOK, private.HookPostReceiveResult{
 						RepoWasEmpty: wasEmpty,
+						Messages:     messages,
 					})
 					return
 				}
@@ -197,5 +211,17 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 	ctx.JSON(http.StatusOK, private.HookPostReceiveResult{
 		Results:      results,
 		RepoWasEmpty: wasEmpty,
+		Messages:     messages,
 	})
 }
+
+// isAGitPush returns whether the refs were pushed by the AGit flow,
+// proc-receive reports the head refs of the pull requests it creates or updates instead of refs/for/
+func isAGitPush(refFullNames []string) bool {
+	for _, refFullName := range refFullNames {
+		if strings.HasPrefix(refFullName, "refs/pull/") || strings.HasPrefix(refFullName, git.PullRequestPrefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -7,6 +7,7 @@ package private
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"strings"
@@ -16,8 +17,10 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/secretscan"
 	"code.gitea.io/gitea/modules/web"
 	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 type preReceiveContext struct {
@@ -34,6 +37,8 @@ type preReceiveContext struct {
 	protectedTags    []*models.ProtectedTag
 	gotProtectedTags bool
 
+	pushRule *models.PushRule
+
 	env []string
 
 	opts *private.HookOptions
@@ -118,6 +123,12 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		refFullName := opts.RefFullNames[i]
 
 		switch {
+		case opts.IsWiki:
+			preReceiveWiki(ourCtx, refFullName)
+			if ctx.Written() {
+				return
+			}
+			continue
 		case strings.HasPrefix(refFullName, git.BranchPrefix):
 			preReceiveBranch(ourCtx, oldCommitID, newCommitID, refFullName)
 		case strings.HasPrefix(refFullName, git.TagPrefix):
@@ -130,11 +141,171 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		if ctx.Written() {
 			return
 		}
+
+		preReceivePushRule(ourCtx, newCommitID, refFullName)
+		if ctx.Written() {
+			return
+		}
+
+		preReceiveSecretScan(ourCtx, oldCommitID, newCommitID, refFullName)
+		if ctx.Written() {
+			return
+		}
 	}
 
 	ctx.PlainText(http.StatusOK, []byte("ok"))
 }
 
+func preReceivePushRule(ctx *preReceiveContext, newCommitID, refFullName string) {
+	if git.IsEmptyCommitID(newCommitID) {
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	if ctx.pushRule == nil {
+		pushRule, err := models.GetEffectivePushRule(repo.ID)
+		if err != nil {
+			log.Error("Unable to get push rule for %-v: %v", repo, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: err.Error(),
+			})
+			return
+		}
+		ctx.pushRule = pushRule
+	}
+
+	violations, err := repo_service.CheckPushRule(repo.RepoPath(), newCommitID, ctx.pushRule, ctx.env)
+	if err != nil {
+		log.Error("Unable to check push rule for %s in %-v: %v", refFullName, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to check push rule for %s: %v", refFullName, err),
+		})
+		return
+	}
+	if len(violations) > 0 {
+		log.Warn("Forbidden: Push of %s to %-v violates the push rules: %s", refFullName, repo, strings.Join(violations, "; "))
+		ctx.JSON(http.StatusForbidden, private.Response{
+			Err: fmt.Sprintf("push of %s rejected by push rules:\n%s", refFullName, strings.Join(violations, "\n")),
+		})
+	}
+}
+
+// maxReportedSecrets is the maximum number of lines which look like secrets listed when rejecting a push
+const maxReportedSecrets = 20
+
+// preReceiveSecretScan rejects the push of a ref adding lines which look like secrets if the push rules scan
+// for secrets, unless the pusher skips the scan with a reason
+func preReceiveSecretScan(ctx *preReceiveContext, oldCommitID, newCommitID, refFullName string) {
+	// the push rule is loaded by preReceivePushRule for every pushed commit
+	if git.IsEmptyCommitID(newCommitID) || !ctx.pushRule.ScanSecrets {
+		return
+	}
+
+	repo := ctx.Repo.Repository
+	if reason := strings.TrimSpace(ctx.opts.GitPushOptions[private.GitPushOptionSecretsSkip]); reason != "" {
+		log.Warn("User %d skipped the secret scan of the push of %s to %-v: %s", ctx.opts.UserID, refFullName, repo, reason)
+		return
+	}
+
+	ignore, err := getSecretScanIgnore(ctx, oldCommitID, refFullName)
+	if err != nil {
+		log.Error("Unable to read %s for %s in %-v: %v", secretscan.IgnoreFile, refFullName, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to read %s: %v", secretscan.IgnoreFile, err),
+		})
+		return
+	}
+
+	findings, err := repo_service.ScanPushedSecrets(repo.RepoPath(), newCommitID, ignore, ctx.env)
+	if err != nil {
+		log.Error("Unable to scan %s for secrets in %-v: %v", refFullName, repo, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to scan %s for secrets: %v", refFullName, err),
+		})
+		return
+	}
+	if len(findings) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, maxReportedSecrets+1)
+	for i, finding := range findings {
+		if i == maxReportedSecrets {
+			lines = append(lines, fmt.Sprintf("and %d more", len(findings)-i))
+			break
+		}
+		lines = append(lines, finding.String())
+	}
+	log.Warn("Forbidden: Push of %s to %-v adds %d lines which look like secrets", refFullName, repo, len(findings))
+	ctx.JSON(http.StatusForbidden, private.Response{
+		Err: fmt.Sprintf("push of %s rejected, it adds lines which look like secrets:\n%s\n"+
+			"Remove them from the commits, add the files to %s in the target branch or push with -o %s=<reason>",
+			refFullName, strings.Join(lines, "\n"), secretscan.IgnoreFile, private.GitPushOptionSecretsSkip),
+	})
+}
+
+// getSecretScanIgnore reads the files ignored by the secret scan from the target branch of the push, which is
+// the default branch for new branches and tags. Nothing is ignored if the file does not exist.
+func getSecretScanIgnore(ctx *preReceiveContext, oldCommitID, refFullName string) (*secretscan.Ignore, error) {
+	repo := ctx.Repo.Repository
+	gitRepo := ctx.Repo.GitRepo
+	commitID := oldCommitID
+	if git.IsEmptyCommitID(oldCommitID) || !strings.HasPrefix(refFullName, git.BranchPrefix) {
+		if repo.IsEmpty || !gitRepo.IsBranchExist(repo.DefaultBranch) {
+			return nil, nil
+		}
+		var err error
+		if commitID, err = gitRepo.GetBranchCommitID(repo.DefaultBranch); err != nil {
+			return nil, err
+		}
+	}
+
+	commit, err := gitRepo.GetCommit(commitID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(secretscan.IgnoreFile)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !entry.IsRegular() {
+		return nil, nil
+	}
+	rd, err := entry.Blob().DataAsync()
+	if err != nil {
+		return nil, err
+	}
+	defer rd.Close()
+	content, err := io.ReadAll(io.LimitReader(rd, 64*1024))
+	if err != nil {
+		return nil, err
+	}
+	return secretscan.ParseIgnore(content), nil
+}
+
+// preReceiveWiki checks a push to the wiki, which only needs write access to the wiki.
+// The branch protections, protected tags and push rules of the repository only apply to its code.
+func preReceiveWiki(ctx *preReceiveContext, refFullName string) {
+	if strings.HasPrefix(refFullName, git.PullRequestPrefix) {
+		ctx.JSON(http.StatusForbidden, private.Response{
+			Err: "Pull requests are not suppported on the wiki.",
+		})
+		return
+	}
+
+	if !ctx.Perm().CanWrite(models.UnitTypeWiki) {
+		if ctx.Written() {
+			return
+		}
+		ctx.JSON(http.StatusForbidden, private.Response{
+			Err: "User permission denied.",
+		})
+	}
+}
+
 func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullName string) {
 	if !ctx.AssertCanWriteCode() {
 		return
@@ -144,7 +315,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 	gitRepo := ctx.Repo.GitRepo
 	branchName := strings.TrimPrefix(refFullName, git.BranchPrefix)
 
-	if branchName == repo.DefaultBranch && newCommitID == git.EmptySHA {
+	if branchName == repo.DefaultBranch && git.IsEmptyCommitID(newCommitID) {
 		log.Warn("Forbidden: Branch: %s is the default branch in %-v and cannot be deleted", branchName, repo)
 		ctx.JSON(http.StatusForbidden, private.Response{
 			Err: fmt.Sprintf("branch %s is the default branch and cannot be deleted", branchName),
@@ -171,7 +342,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 	// First of all we need to enforce absolutely:
 	//
 	// 1. Detect and prevent deletion of the branch
-	if newCommitID == git.EmptySHA {
+	if git.IsEmptyCommitID(newCommitID) {
 		log.Warn("Forbidden: Branch: %s in %-v is protected from deletion", branchName, repo)
 		ctx.JSON(http.StatusForbidden, private.Response{
 			Err: fmt.Sprintf("branch %s is protected from deletion", branchName),
@@ -180,7 +351,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 	}
 
 	// 2. Disallow force pushes to protected branches
-	if git.EmptySHA != oldCommitID {
+	if !git.IsEmptyCommitID(oldCommitID) {
 		output, err := git.NewCommand("rev-list", "--max-count=1", oldCommitID, "^"+newCommitID).RunInDirWithEnv(repo.RepoPath(), ctx.env)
 		if err != nil {
 			log.Error("Unable to detect force push between: %s and %s in %-v Error: %v", oldCommitID, newCommitID, repo, err)
@@ -336,7 +507,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 
 		// Check all status checks and reviews are ok
 		if err := pull_service.CheckPRReadyToMerge(pr, true); err != nil {
-			if models.IsErrNotAllowedToMerge(err) {
+			if models.IsErrNotAllowedToMerge(err) || models.IsErrPullRequestOutdated(err) {
 				log.Warn("Forbidden: User %d is not allowed push to protected branch %s in %-v and pr #%d is not ready to be merged: %s", ctx.opts.UserID, branchName, repo, pr.Index, err.Error())
 				ctx.JSON(http.StatusForbidden, private.Response{
 					Err: fmt.Sprintf("Not allowed to push to protected branch %s and pr #%d is not ready to be merged: %s", branchName, ctx.opts.PullRequestID, err.Error()),
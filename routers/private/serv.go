@@ -8,6 +8,7 @@ package private
 import (
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"code.gitea.io/gitea/models"
@@ -17,9 +18,27 @@ import (
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/setting"
 	repo_service "code.gitea.io/gitea/services/repository"
+	traffic_service "code.gitea.io/gitea/services/traffic"
 	wiki_service "code.gitea.io/gitea/services/wiki"
 )
 
+// checkKeyPolicyOnAuth refuses keys violating [ssh.minimum_key_sizes] if MINIMUM_KEY_SIZE_CHECK_ON_AUTH is enabled.
+// Keys which can't be checked are refused as well, principals are not keys and are never refused.
+func checkKeyPolicyOnAuth(key *models.PublicKey) error {
+	if !setting.SSH.MinimumKeySizeCheckOnAuth || key.Type == models.KeyTypePrincipal {
+		return nil
+	}
+	if err := models.CheckPublicKeyPolicy(key.Content); err != nil {
+		if models.IsErrKeyPolicyViolation(err) {
+			log.Warn("Refused public key: %d:%s: %v", key.ID, key.Name, err)
+			return fmt.Errorf("Public key %d:%s is refused: %v", key.ID, key.Name, err)
+		}
+		log.Error("Unable to check the policy of public key: %d Error: %v", key.ID, err)
+		return fmt.Errorf("Unable to check public key %d:%s: %v", key.ID, key.Name, err)
+	}
+	return nil
+}
+
 // ServNoCommand returns information about the provided keyid
 func ServNoCommand(ctx *context.PrivateContext) {
 	keyID := ctx.ParamsInt64(":keyid")
@@ -46,6 +65,13 @@ func ServNoCommand(ctx *context.PrivateContext) {
 	}
 	results.Key = key
 
+	if err := checkKeyPolicyOnAuth(key); err != nil {
+		ctx.JSON(http.StatusForbidden, private.Response{
+			Err: err.Error(),
+		})
+		return
+	}
+
 	if key.Type == models.KeyTypeUser || key.Type == models.KeyTypePrincipal {
 		user, err := models.GetUserByID(key.OwnerID)
 		if err != nil {
@@ -105,6 +131,21 @@ func ServCommand(ctx *context.PrivateContext) {
 	}
 
 	owner, err := models.GetUserByName(results.OwnerName)
+	if models.IsErrUserNotExist(err) {
+		if repo, redirected, err2 := models.LookupRepositoryByOwnerAndName(results.OwnerName, results.RepoName); err2 == nil && redirected {
+			if !setting.Repository.ServeRenamedOwnerGit {
+				ctx.JSON(http.StatusNotFound, private.ErrServCommand{
+					Results: results,
+					Err:     fmt.Sprintf("Repository has moved to %s, please update the remote", repo.FullName()),
+				})
+				return
+			}
+			// Serve the repository by the old name of its owner, git remotes aren't updated on a rename
+			owner, err = models.GetUserByID(repo.OwnerID)
+			ownerName = repo.OwnerName
+			results.OwnerName = repo.OwnerName
+		}
+	}
 	if err != nil {
 		log.Error("Unable to get repository owner: %s/%s Error: %v", results.OwnerName, results.RepoName, err)
 		ctx.JSON(http.StatusInternalServerError, private.ErrServCommand{
@@ -192,6 +233,14 @@ func ServCommand(ctx *context.PrivateContext) {
 	results.KeyID = key.ID
 	results.UserID = key.OwnerID
 
+	if err := checkKeyPolicyOnAuth(key); err != nil {
+		ctx.JSON(http.StatusForbidden, private.ErrServCommand{
+			Results: results,
+			Err:     err.Error(),
+		})
+		return
+	}
+
 	// If repo doesn't exist, deploy key doesn't make sense
 	if !repoExist && key.Type == models.KeyTypeDeploy {
 		ctx.JSON(http.StatusNotFound, private.ErrServCommand{
@@ -345,6 +394,13 @@ func ServCommand(ctx *context.PrivateContext) {
 
 		repo, err = repo_service.PushCreateRepo(user, owner, results.RepoName)
 		if err != nil {
+			if repo_service.IsErrPushCreateRepoRejected(err) {
+				ctx.JSON(http.StatusForbidden, private.ErrServCommand{
+					Results: results,
+					Err:     fmt.Sprintf("Unable to create repository %s/%s: %s", results.OwnerName, results.RepoName, repo_service.PushCreateRepoRejectedReason(err)),
+				})
+				return
+			}
 			log.Error("pushCreateRepo: %v", err)
 			ctx.JSON(http.StatusNotFound, private.ErrServCommand{
 				Results: results,
@@ -394,6 +450,18 @@ func ServCommand(ctx *context.PrivateContext) {
 		results.RepoName,
 		results.RepoID)
 
+	if repoExist && !results.IsWiki {
+		for _, verb := range ctx.FormStrings("verb") {
+			if verb == "git-upload-pack" {
+				visitor := "key:" + strconv.FormatInt(results.KeyID, 10)
+				if results.UserID > 0 && !results.IsDeployKey {
+					visitor = "user:" + strconv.FormatInt(results.UserID, 10)
+				}
+				traffic_service.Record(results.RepoID, models.RepoTrafficClone, visitor)
+			}
+		}
+	}
+
 	ctx.JSON(http.StatusOK, results)
 	// We will update the keys in a different call.
 }
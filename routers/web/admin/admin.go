@@ -29,6 +29,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/mailer"
+	stats_service "code.gitea.io/gitea/services/stats"
 
 	"gitea.com/go-chi/session"
 )
@@ -124,7 +125,7 @@ func Dashboard(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("admin.dashboard")
 	ctx.Data["PageIsAdmin"] = true
 	ctx.Data["PageIsAdminDashboard"] = true
-	ctx.Data["Stats"] = models.GetStatistic()
+	ctx.Data["Stats"] = stats_service.Get()
 	ctx.Data["NeedUpdate"] = models.GetNeedUpdate()
 	ctx.Data["RemoteVersion"] = models.GetRemoteVersion()
 	// FIXME: update periodically
@@ -134,13 +135,19 @@ func Dashboard(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplDashboard)
 }
 
+// RefreshStatistics counts the statistics of the dashboard again
+func RefreshStatistics(ctx *context.Context) {
+	stats_service.Refresh()
+	ctx.Redirect(setting.AppSubURL + "/admin")
+}
+
 // DashboardPost run an admin operation
 func DashboardPost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.AdminDashboardForm)
 	ctx.Data["Title"] = ctx.Tr("admin.dashboard")
 	ctx.Data["PageIsAdmin"] = true
 	ctx.Data["PageIsAdminDashboard"] = true
-	ctx.Data["Stats"] = models.GetStatistic()
+	ctx.Data["Stats"] = stats_service.Get()
 	updateSystemStatus()
 	ctx.Data["SysStatus"] = sysStatus
 
@@ -149,6 +149,8 @@ func parseLDAPConfig(form forms.AuthenticationForm) *ldap.Source {
 		AllowDeactivateAll:    form.AllowDeactivateAll,
 		Enabled:               true,
 		SkipLocalTwoFA:        form.SkipLocalTwoFA,
+		GroupTeamMap:          form.GroupTeamMap,
+		GroupTeamMapRemoval:   form.GroupTeamMapRemoval,
 	}
 }
 
@@ -239,6 +241,11 @@ func NewAuthSourcePost(ctx *context.Context) {
 	var config convert.Conversion
 	switch login.Type(form.Type) {
 	case login.LDAP, login.DLDAP:
+		if _, err := ldap.ParseGroupTeamMap(form.GroupTeamMap); err != nil {
+			ctx.Data["Err_GroupTeamMap"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.invalid_group_team_map", err), tplAuthNew, form)
+			return
+		}
 		config = parseLDAPConfig(form)
 		hasTLS = ldap.SecurityProtocol(form.SecurityProtocol) > ldap.SecurityProtocolUnencrypted
 	case login.SMTP:
@@ -360,6 +367,11 @@ func EditAuthSourcePost(ctx *context.Context) {
 	var config convert.Conversion
 	switch login.Type(form.Type) {
 	case login.LDAP, login.DLDAP:
+		if _, err := ldap.ParseGroupTeamMap(form.GroupTeamMap); err != nil {
+			ctx.Data["Err_GroupTeamMap"] = true
+			ctx.RenderWithErr(ctx.Tr("admin.auths.invalid_group_team_map", err), tplAuthEdit, form)
+			return
+		}
 		config = parseLDAPConfig(form)
 	case login.SMTP:
 		config = parseSMTPConfig(form)
@@ -24,6 +24,7 @@ import (
 const (
 	tplRepos          base.TplName = "admin/repo/list"
 	tplUnadoptedRepos base.TplName = "admin/repo/unadopted"
+	tplCleanupTasks   base.TplName = "admin/repo/cleanup_tasks"
 )
 
 // Repos show all the repositories
@@ -32,10 +33,21 @@ func Repos(ctx *context.Context) {
 	ctx.Data["PageIsAdmin"] = true
 	ctx.Data["PageIsAdminRepositories"] = true
 
+	createdFrom, _ := models.ToRepoCreatedFrom(ctx.FormString("created_from"))
+	ctx.Data["RepoCreatedFromKinds"] = []models.RepoCreatedFrom{
+		models.RepoCreatedFromCreated,
+		models.RepoCreatedFromMigrated,
+		models.RepoCreatedFromForked,
+		models.RepoCreatedFromGenerated,
+		models.RepoCreatedFromAdopted,
+		models.RepoCreatedFromPushed,
+	}
+
 	explore.RenderRepoSearch(ctx, &explore.RepoSearchOptions{
-		Private:  true,
-		PageSize: setting.UI.Admin.RepoPagingNum,
-		TplName:  tplRepos,
+		Private:     true,
+		PageSize:    setting.UI.Admin.RepoPagingNum,
+		TplName:     tplRepos,
+		CreatedFrom: createdFrom,
 	})
 }
 
@@ -164,3 +176,73 @@ func AdoptOrDeleteRepository(ctx *context.Context) {
 	}
 	ctx.Redirect(setting.AppSubURL + "/admin/repos/unadopted?search=true&q=" + url.QueryEscape(q) + "&page=" + page)
 }
+
+// RepoCleanupTasks lists the pending and failed removals of the files of deleted repositories
+func RepoCleanupTasks(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("admin.repos.cleanup_tasks")
+	ctx.Data["PageIsAdmin"] = true
+	ctx.Data["PageIsAdminRepositories"] = true
+
+	page := ctx.FormInt("page")
+	if page <= 0 {
+		page = 1
+	}
+	opts := models.FindRepoCleanupTasksOptions{
+		ListOptions: db.ListOptions{
+			PageSize: setting.UI.Admin.NoticePagingNum,
+			Page:     page,
+		},
+		Status: -1,
+	}
+	state := ctx.FormString("state")
+	switch state {
+	case "pending":
+		opts.Status = models.RepoCleanupTaskPending
+	case "failed":
+		opts.Status = models.RepoCleanupTaskFailed
+	default:
+		state = ""
+	}
+	ctx.Data["State"] = state
+
+	tasks, count, err := models.FindRepoCleanupTasks(opts)
+	if err != nil {
+		ctx.ServerError("FindRepoCleanupTasks", err)
+		return
+	}
+	ctx.Data["Tasks"] = tasks
+	ctx.Data["Total"] = count
+	ctx.Data["MaxAttempts"] = models.RepoCleanupTaskMaxAttempts
+
+	pager := context.NewPagination(int(count), opts.PageSize, opts.Page, 5)
+	pager.AddParam(ctx, "state", "State")
+	ctx.Data["Page"] = pager
+	ctx.HTML(http.StatusOK, tplCleanupTasks)
+}
+
+// RepoCleanupTasksPost retries or drops removals of the files of deleted repositories
+func RepoCleanupTasksPost(ctx *context.Context) {
+	id := ctx.FormInt64("id")
+	switch ctx.FormString("action") {
+	case "retry":
+		if err := models.RetryRepoCleanupTask(id); err != nil {
+			ctx.ServerError("RetryRepoCleanupTask", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("admin.repos.cleanup_tasks.retry_success"))
+	case "retry-failed":
+		if _, err := models.RetryFailedRepoCleanupTasks(); err != nil {
+			ctx.ServerError("RetryFailedRepoCleanupTasks", err)
+			return
+		}
+		ctx.Flash.Success(ctx.Tr("admin.repos.cleanup_tasks.retry_success"))
+	case "delete":
+		if err := models.DeleteRepoCleanupTask(id); err != nil {
+			ctx.ServerError("DeleteRepoCleanupTask", err)
+			return
+		}
+		log.Trace("Repository cleanup task %d dropped by admin (%s)", id, ctx.User.Name)
+		ctx.Flash.Success(ctx.Tr("admin.repos.cleanup_tasks.delete_success"))
+	}
+	ctx.Redirect(setting.AppSubURL + "/admin/repos/cleanup-tasks?state=" + url.QueryEscape(ctx.FormString("state")) + "&page=" + url.QueryEscape(ctx.FormString("page")))
+}
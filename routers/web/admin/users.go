@@ -174,6 +174,9 @@ func NewUserPost(ctx *context.Context) {
 		case models.IsErrNameCharsNotAllowed(err):
 			ctx.Data["Err_UserName"] = true
 			ctx.RenderWithErr(ctx.Tr("user.form.name_chars_not_allowed", err.(models.ErrNameCharsNotAllowed).Name), tplUserNew, &form)
+		case models.IsErrNameTooLong(err):
+			ctx.Data["Err_UserName"] = true
+			ctx.RenderWithErr(ctx.Tr("user.form.name_too_long", err.(models.ErrNameTooLong).MaxLength), tplUserNew, &form)
 		default:
 			ctx.ServerError("CreateUser", err)
 		}
@@ -263,6 +266,12 @@ func EditUserPost(ctx *context.Context) {
 		return
 	}
 
+	if u.IsAnonymized() {
+		ctx.Flash.Error(ctx.Tr("admin.users.anonymized_not_editable"))
+		ctx.Redirect(setting.AppSubURL + "/admin/users/" + ctx.Params(":userid"))
+		return
+	}
+
 	fields := strings.Split(form.LoginType, "-")
 	if len(fields) == 2 {
 		loginType, _ := strconv.ParseInt(fields[0], 10, 0)
@@ -337,6 +346,7 @@ func EditUserPost(ctx *context.Context) {
 	u.IsActive = form.Active
 	u.IsAdmin = form.Admin
 	u.IsRestricted = form.Restricted
+	u.IsServiceAccount = form.ServiceAccount
 	u.AllowGitHook = form.AllowGitHook
 	u.AllowImportLocal = form.AllowImportLocal
 	u.AllowCreateOrganization = form.AllowCreateOrganization
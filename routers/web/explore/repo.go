@@ -26,6 +26,8 @@ type RepoSearchOptions struct {
 	Restricted bool
 	PageSize   int
 	TplName    base.TplName
+	// only list repositories which came to exist this way, empty for any
+	CreatedFrom models.RepoCreatedFrom
 }
 
 // RenderRepoSearch render repositories search page
@@ -68,6 +70,12 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		orderBy = models.SearchOrderByForksReverse
 	case "fewestforks":
 		orderBy = models.SearchOrderByForks
+	case "mirrornextupdate":
+		orderBy = models.SearchOrderByMirrorNextUpdate
+	case "mirrorrecentupdate":
+		orderBy = models.SearchOrderByMirrorRecentUpdated
+	case "mirrorfailingfirst":
+		orderBy = models.SearchOrderByMirrorFailingFirst
 	default:
 		ctx.Data["SortType"] = "recentupdate"
 		orderBy = models.SearchOrderByRecentUpdated
@@ -91,6 +99,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		AllLimited:         true,
 		TopicOnly:          topicOnly,
 		IncludeDescription: setting.UI.SearchRepoDescription,
+		CreatedFrom:        opts.CreatedFrom,
 	})
 	if err != nil {
 		ctx.ServerError("SearchRepository", err)
@@ -104,6 +113,10 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 	pager := context.NewPagination(int(count), opts.PageSize, page, 5)
 	pager.SetDefaultParams(ctx)
 	pager.AddParam(ctx, "topic", "TopicOnly")
+	ctx.Data["CreatedFrom"] = string(opts.CreatedFrom)
+	if opts.CreatedFrom != "" {
+		pager.AddParam(ctx, "created_from", "CreatedFrom")
+	}
 	ctx.Data["Page"] = pager
 
 	ctx.HTML(http.StatusOK, opts.TplName)
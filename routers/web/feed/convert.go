@@ -42,12 +42,16 @@ func feedActionsToFeedItems(ctx *context.Context, actions []*models.Action) (ite
 			} else {
 				title += ctx.Tr("action.create_branch", act.GetRepoLink(), branchLink, act.GetBranch(), act.ShortRepoPath())
 			}
+		case models.ActionAuthorCommitRepo:
+			title += ctx.Tr("action.author_commit_repo", act.GetRepoLink(), act.GetBranch(), act.GetBranch(), act.ShortRepoPath())
 		case models.ActionCreateIssue:
 			title += ctx.Tr("action.create_issue", act.GetRepoLink(), act.GetIssueInfos()[0], act.ShortRepoPath())
 		case models.ActionCreatePullRequest:
 			title += ctx.Tr("action.create_pull_request", act.GetRepoLink(), act.GetIssueInfos()[0], act.ShortRepoPath())
 		case models.ActionTransferRepo:
 			title += ctx.Tr("action.transfer_repo", act.GetContent(), act.GetRepoLink(), act.ShortRepoPath())
+		case models.ActionDetachFork:
+			title += ctx.Tr("action.detach_fork", act.GetRepoLink(), act.ShortRepoPath(), act.GetContent())
 		case models.ActionPushTag:
 			title += ctx.Tr("action.push_tag", act.GetRepoLink(), url.QueryEscape(act.GetTag()), act.ShortRepoPath())
 		case models.ActionCommentIssue:
@@ -95,7 +99,7 @@ func feedActionsToFeedItems(ctx *context.Context, actions []*models.Action) (ite
 		// description & content
 		{
 			switch act.OpType {
-			case models.ActionCommitRepo, models.ActionMirrorSyncPush:
+			case models.ActionCommitRepo, models.ActionMirrorSyncPush, models.ActionAuthorCommitRepo:
 				push := templates.ActionContent2Commits(act)
 				repoLink := act.GetRepoLink()
 
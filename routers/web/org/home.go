@@ -11,9 +11,11 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 const (
@@ -86,6 +88,14 @@ func Home(ctx *context.Context) {
 		page = 1
 	}
 
+	if page == 1 && keyword == "" {
+		profileReadme, err := repo_service.GetProfileReadme(ctx, org, ctx.User)
+		if err != nil {
+			log.Error("GetProfileReadme [%s]: %v", org.Name, err)
+		}
+		ctx.Data["ProfileReadme"] = profileReadme
+	}
+
 	var (
 		repos []*models.Repository
 		count int64
@@ -6,6 +6,7 @@
 package org
 
 import (
+	"fmt"
 	"net/http"
 	"path"
 	"strings"
@@ -242,8 +243,9 @@ func NewTeamPost(ctx *context.Context) {
 		var units = make([]*models.TeamUnit, 0, len(form.Units))
 		for _, tp := range form.Units {
 			units = append(units, &models.TeamUnit{
-				OrgID: ctx.Org.Organization.ID,
-				Type:  tp,
+				OrgID:      ctx.Org.Organization.ID,
+				Type:       tp,
+				AccessMode: unitAccessModeFromForm(ctx, tp),
 			})
 		}
 		t.Units = units
@@ -275,6 +277,16 @@ func NewTeamPost(ctx *context.Context) {
 	ctx.Redirect(ctx.Org.OrgLink + "/teams/" + t.LowerName)
 }
 
+// unitAccessModeFromForm returns the access mode chosen for the unit in the team form,
+// only read and write access can be chosen besides the team's access mode
+func unitAccessModeFromForm(ctx *context.Context, tp models.UnitType) models.AccessMode {
+	mode := models.AccessMode(ctx.FormInt(fmt.Sprintf("unit_mode_%d", tp)))
+	if mode != models.AccessModeRead && mode != models.AccessModeWrite {
+		return models.AccessModeNone
+	}
+	return mode
+}
+
 // TeamMembers render team members page
 func TeamMembers(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Org.Team.Name
@@ -341,9 +353,10 @@ func EditTeamPost(ctx *context.Context) {
 		var units = make([]models.TeamUnit, 0, len(form.Units))
 		for _, tp := range form.Units {
 			units = append(units, models.TeamUnit{
-				OrgID:  t.OrgID,
-				TeamID: t.ID,
-				Type:   tp,
+				OrgID:      t.OrgID,
+				TeamID:     t.ID,
+				Type:       tp,
+				AccessMode: unitAccessModeFromForm(ctx, tp),
 			})
 		}
 		err := models.UpdateTeamUnits(t, units)
@@ -286,7 +286,7 @@ func Diff(ctx *context.Context) {
 		}
 		return
 	}
-	if len(commitID) != 40 {
+	if !git.IsFullCommitID(commitID) {
 		commitID = commit.ID.String()
 	}
 
@@ -358,6 +358,10 @@ func ParseCompareInfo(ctx *context.Context) *CompareInfo {
 		ci.HeadRepo = ctx.Repo.Repository
 		ci.HeadGitRepo = ctx.Repo.GitRepo
 	} else if has {
+		if err := models.CheckSameObjectFormat(baseRepo, ci.HeadRepo); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, ctx.Tr("repo.pulls.object_format_mismatch", ci.HeadRepo.FullName(), ci.HeadRepo.ObjectFormat.Name(), baseRepo.ObjectFormat.Name()))
+			return nil
+		}
 		ci.HeadGitRepo, err = git.OpenRepository(ci.HeadRepo.RepoPath())
 		if err != nil {
 			ctx.ServerError("OpenRepository", err)
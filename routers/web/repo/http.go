@@ -10,6 +10,7 @@ import (
 	"compress/gzip"
 	gocontext "context"
 	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/exec"
@@ -30,6 +31,7 @@ import (
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
 	repo_service "code.gitea.io/gitea/services/repository"
+	traffic_service "code.gitea.io/gitea/services/traffic"
 )
 
 // httpBase implementation git smart HTTP protocol
@@ -109,6 +111,13 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 	}
 
 	owner, err := models.GetUserByName(username)
+	if models.IsErrUserNotExist(err) && setting.Repository.ServeRenamedOwnerGit {
+		// Serve the repository by the old name of its owner, git remotes aren't updated on a rename
+		if repo, redirected, err2 := models.LookupRepositoryByOwnerAndName(username, reponame); err2 == nil && redirected {
+			owner, err = models.GetUserByID(repo.OwnerID)
+			username = repo.OwnerName
+		}
+	}
 	if err != nil {
 		if models.IsErrUserNotExist(err) {
 			if redirectUserID, err := models.LookupUserRedirect(username); err == nil {
@@ -191,6 +200,14 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 			return
 		}
 
+		// clone tokens grant access to the code of their repository only, and writing only if they were created to
+		if token, ok := ctx.Data["RepoCloneToken"].(*models.RepoCloneToken); ok {
+			if !repoExist || isWiki || token.RepoID != repo.ID || token.Mode < accessMode {
+				ctx.HandleText(http.StatusForbidden, "The clone token does not grant this access")
+				return
+			}
+		}
+
 		if repoExist {
 			perm, err := models.GetUserRepoPermission(repo, ctx.User)
 			if err != nil {
@@ -262,6 +279,10 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 
 		repo, err = repo_service.PushCreateRepo(ctx.User, owner, reponame)
 		if err != nil {
+			if repo_service.IsErrPushCreateRepoRejected(err) {
+				ctx.HandleText(http.StatusForbidden, fmt.Sprintf("Unable to create repository %s/%s: %s", owner.Name, reponame, repo_service.PushCreateRepoRejectedReason(err)))
+				return
+			}
 			log.Error("pushCreateRepo: %v", err)
 			ctx.Status(http.StatusNotFound)
 			return
@@ -283,6 +304,11 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 
 	environ = append(environ, models.EnvRepoID+fmt.Sprintf("=%d", repo.ID))
 
+	if err := repo.LoadGitSettings(); err != nil {
+		ctx.ServerError("LoadGitSettings", err)
+		return
+	}
+
 	w := ctx.Resp
 	r := ctx.Req
 	cfg := &serviceConfig{
@@ -298,7 +324,7 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 		dir = models.RepoPath(username, wikiRepoName)
 	}
 
-	return &serviceHandler{cfg, w, r, dir, cfg.Env}
+	return &serviceHandler{cfg, w, r, dir, cfg.Env, repo, isWiki}
 }
 
 var (
@@ -355,6 +381,8 @@ type serviceHandler struct {
 	r       *http.Request
 	dir     string
 	environ []string
+	repo    *models.Repository
+	isWiki  bool
 }
 
 func (h *serviceHandler) setHeaderNoCache() {
@@ -385,7 +413,36 @@ func containsParentDirectorySeparator(v string) bool {
 
 func isSlashRune(r rune) bool { return r == '/' || r == '\\' }
 
+// denyDumbHTTP responds with forbidden if the repository is only served by the smart HTTP protocol
+func (h *serviceHandler) denyDumbHTTP() bool {
+	if !h.repo.GitSettings.IsDumbHTTPDisabled() {
+		return false
+	}
+	h.w.WriteHeader(http.StatusForbidden)
+	_, _ = h.w.Write([]byte("The dumb HTTP protocol is disabled for this repository, please use a git client supporting the smart HTTP protocol"))
+	return true
+}
+
+// exceedsFullCloneMaxSize returns true if the repository is too large to be cloned without a filter or a depth
+func (h *serviceHandler) exceedsFullCloneMaxSize() bool {
+	maxSize := h.repo.GitSettings.FullCloneMaxBytes()
+	return !h.isWiki && maxSize > 0 && h.repo.Size > maxSize
+}
+
+// isFullClone returns true if the upload-pack request fetches all objects of the repository
+func (h *serviceHandler) isFullClone(body []byte) bool {
+	req, err := git.ParseUploadPackRequest(bytes.NewReader(body))
+	if err != nil {
+		log.Debug("Unable to parse upload-pack request for %s: %v", h.dir, err)
+		return false
+	}
+	return req.IsFullClone()
+}
+
 func (h *serviceHandler) sendFile(contentType, file string) {
+	if h.denyDumbHTTP() {
+		return
+	}
 	if containsParentDirectorySeparator(file) {
 		log.Error("request file path contains invalid path: %v", file)
 		h.w.WriteHeader(http.StatusBadRequest)
@@ -475,6 +532,25 @@ func serviceRPC(h serviceHandler, service string) {
 		}
 	}
 
+	if service == "upload-pack" && h.exceedsFullCloneMaxSize() {
+		body, err := io.ReadAll(reqBody)
+		if err != nil {
+			log.Error("Fail to read upload-pack request: %v", err)
+			h.w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if h.isFullClone(body) {
+			filter := h.repo.GitSettings.CloneFilter()
+			if filter == "" {
+				filter = "blob:none"
+			}
+			h.w.WriteHeader(http.StatusOK)
+			_, _ = h.w.Write(packetWrite(fmt.Sprintf("ERR This repository is too large for a full clone, please use a partial clone: git clone --filter=%s\n", filter)))
+			return
+		}
+		reqBody = io.NopCloser(bytes.NewReader(body))
+	}
+
 	// set this for allow pre-receive and post-receive execute
 	h.environ = append(h.environ, "SSH_ORIGINAL_COMMAND="+service)
 
@@ -556,6 +632,11 @@ func GetInfoRefs(ctx *context.Context) {
 		}
 		h.environ = append(os.Environ(), h.environ...)
 
+		// every clone or fetch starts by requesting the refs
+		if service == "upload-pack" && !h.isWiki {
+			traffic_service.Record(h.repo.ID, models.RepoTrafficClone, traffic_service.Visitor(ctx.User, ctx.RemoteAddr()))
+		}
+
 		refs, err := git.NewCommand(service, "--stateless-rpc", "--advertise-refs", ".").RunInDirTimeoutEnv(h.environ, -1, h.dir)
 		if err != nil {
 			log.Error(fmt.Sprintf("%v - %s", err, string(refs)))
@@ -566,7 +647,7 @@ func GetInfoRefs(ctx *context.Context) {
 		_, _ = h.w.Write(packetWrite("# service=git-" + service + "\n"))
 		_, _ = h.w.Write([]byte("0000"))
 		_, _ = h.w.Write(refs)
-	} else {
+	} else if !h.denyDumbHTTP() {
 		updateServerInfo(h.dir)
 		h.sendFile("text/plain; charset=utf-8", "info/refs")
 	}
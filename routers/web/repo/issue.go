@@ -22,6 +22,7 @@ import (
 	"code.gitea.io/gitea/modules/convert"
 	"code.gitea.io/gitea/modules/git"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	issue_template "code.gitea.io/gitea/modules/issue/template"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
@@ -113,6 +114,9 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	var err error
 	viewType := ctx.FormString("type")
 	sortType := ctx.FormString("sort")
+	if len(sortType) == 0 {
+		sortType = models.DefaultIssueSortType(ctx.Repo.Repository, ctx.User)
+	}
 	types := []string{"all", "your_repositories", "assigned", "created_by", "mentioned", "review_requested"}
 	if !util.IsStringInSlice(viewType, types, true) {
 		viewType = "all"
@@ -233,6 +237,7 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 			LabelIDs:          labelIDs,
 			SortType:          sortType,
 			IssueIDs:          issueIDs,
+			PinnedFirst:       len(ctx.FormString("sort")) == 0,
 		})
 		if err != nil {
 			ctx.ServerError("Issues", err)
@@ -737,7 +742,7 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleDirs [
 		}
 	}
 	templateCandidates = append(templateCandidates, possibleFiles...) // Append files to the end because they should be fallback
-	for _, filename := range templateCandidates {
+	for i, filename := range templateCandidates {
 		templateContent, found := getFileContentFromDefaultBranch(ctx, filename)
 		if found {
 			var meta api.IssueTemplate
@@ -747,33 +752,95 @@ func setTemplateIfExists(ctx *context.Context, ctxDataKey string, possibleDirs [
 				ctx.Data[ctxDataKey] = templateContent
 				return
 			}
+			if i < len(templateCandidates)-len(possibleFiles) {
+				// the template selected by name is posted with the issue to apply its assignees
+				ctx.Data["IssueTemplateFileName"] = ctx.FormString("template")
+			}
 			ctx.Data[issueTemplateTitleKey] = meta.Title
 			ctx.Data[ctxDataKey] = templateBody
-			labelIDs := make([]string, 0, len(meta.Labels))
-			if repoLabels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", db.ListOptions{}); err == nil {
-				ctx.Data["Labels"] = repoLabels
-				if ctx.Repo.Owner.IsOrganization() {
-					if orgLabels, err := models.GetLabelsByOrgID(ctx.Repo.Owner.ID, ctx.FormString("sort"), db.ListOptions{}); err == nil {
-						ctx.Data["OrgLabels"] = orgLabels
-						repoLabels = append(repoLabels, orgLabels...)
-					}
-				}
+			setTemplateLabels(ctx, meta.Labels)
+			return
+		}
+	}
+}
 
-				for _, metaLabel := range meta.Labels {
-					for _, repoLabel := range repoLabels {
-						if strings.EqualFold(repoLabel.Name, metaLabel) {
-							repoLabel.IsChecked = true
-							labelIDs = append(labelIDs, fmt.Sprintf("%d", repoLabel.ID))
-							break
-						}
-					}
+// setTemplateLabels preselects the repository and organization labels named by an issue template
+func setTemplateLabels(ctx *context.Context, labels []string) {
+	labelIDs := make([]string, 0, len(labels))
+	if repoLabels, err := models.GetLabelsByRepoID(ctx.Repo.Repository.ID, "", db.ListOptions{}); err == nil {
+		ctx.Data["Labels"] = repoLabels
+		if ctx.Repo.Owner.IsOrganization() {
+			if orgLabels, err := models.GetLabelsByOrgID(ctx.Repo.Owner.ID, ctx.FormString("sort"), db.ListOptions{}); err == nil {
+				ctx.Data["OrgLabels"] = orgLabels
+				repoLabels = append(repoLabels, orgLabels...)
+			}
+		}
+
+		for _, metaLabel := range labels {
+			for _, repoLabel := range repoLabels {
+				if strings.EqualFold(repoLabel.Name, metaLabel) {
+					repoLabel.IsChecked = true
+					labelIDs = append(labelIDs, fmt.Sprintf("%d", repoLabel.ID))
+					break
 				}
 			}
-			ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
-			ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
-			return
 		}
 	}
+	ctx.Data["HasSelectedLabel"] = len(labelIDs) > 0
+	ctx.Data["label_ids"] = strings.Join(labelIDs, ",")
+}
+
+// setIssueFormIfExists prepares the YAML issue form selected by the "template" parameter,
+// it returns false if no issue form has been selected
+func setIssueFormIfExists(ctx *context.Context) bool {
+	name := ctx.FormString("template")
+	if !issue_template.IsFormFile(name) {
+		return false
+	}
+	it, err := ctx.IssueTemplateFromDefaultBranch(name)
+	if err != nil {
+		log.Debug("could not load issue form %s [%s]: %v", name, ctx.Repo.Repository.FullName(), err)
+		return false
+	}
+	if it == nil || !it.IsForm() {
+		return false
+	}
+
+	renderedMarkdown := make(map[int]string)
+	for i, field := range it.Fields {
+		if field.Type != api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		renderedMarkdown[i], err = markdown.RenderString(&markup.RenderContext{
+			URLPrefix: ctx.Repo.RepoLink,
+			Metas:     ctx.Repo.Repository.ComposeMetas(),
+			GitRepo:   ctx.Repo.GitRepo,
+			Ctx:       ctx,
+		}, field.Attributes.Value)
+		if err != nil {
+			ctx.ServerError("RenderString", err)
+			return true
+		}
+	}
+	ctx.Data["IssueForm"] = it
+	ctx.Data["IssueFormMarkdown"] = renderedMarkdown
+	ctx.Data[issueTemplateTitleKey] = it.Title
+	setTemplateLabels(ctx, it.Labels)
+	return true
+}
+
+// issueFormValues collects the submitted values of the issue form fields, which are posted as "form-field-<id>"
+func issueFormValues(ctx *context.Context, it *api.IssueTemplate) map[string][]string {
+	values := make(map[string][]string, len(it.Fields))
+	for _, field := range it.Fields {
+		if field.Type == api.IssueFormFieldTypeMarkdown {
+			continue
+		}
+		if submitted := ctx.Req.Form["form-field-"+field.ID]; len(submitted) > 0 {
+			values[field.ID] = submitted
+		}
+	}
+	return values
 }
 
 // NewIssue render creating issue page
@@ -823,7 +890,9 @@ func NewIssue(ctx *context.Context) {
 	}
 
 	RetrieveRepoMetas(ctx, ctx.Repo.Repository, false)
-	setTemplateIfExists(ctx, issueTemplateKey, context.IssueTemplateDirCandidates, IssueTemplateCandidates)
+	if !setIssueFormIfExists(ctx) {
+		setTemplateIfExists(ctx, issueTemplateKey, context.IssueTemplateDirCandidates, IssueTemplateCandidates)
+	}
 	if ctx.Written() {
 		return
 	}
@@ -980,6 +1049,54 @@ func NewIssuePost(ctx *context.Context) {
 		return
 	}
 
+	if form.Template != "" {
+		it, err := ctx.IssueTemplateFromDefaultBranch(form.Template)
+		if err != nil {
+			ctx.ServerError("IssueTemplateFromDefaultBranch", err)
+			return
+		}
+		if it != nil && it.IsForm() {
+			values := issueFormValues(ctx, it)
+			if err := issue_template.ValidateValues(it, values); err != nil {
+				if !issue_template.IsErrInvalidFormValues(err) {
+					ctx.ServerError("ValidateValues", err)
+					return
+				}
+				ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWrite(models.UnitTypeIssues)
+				if setIssueFormIfExists(ctx); ctx.Written() {
+					return
+				}
+				// keep what has been typed into the text fields
+				if issueForm, ok := ctx.Data["IssueForm"].(*api.IssueTemplate); ok {
+					for _, field := range issueForm.Fields {
+						if submitted := values[field.ID]; len(submitted) > 0 && (field.Type == api.IssueFormFieldTypeInput || field.Type == api.IssueFormFieldTypeTextarea) {
+							field.Attributes.Value = submitted[0]
+						}
+					}
+				}
+				ctx.RenderWithErr(ctx.Tr("repo.issues.new.invalid_form_values", err.Error()), tplIssueNew, form)
+				return
+			}
+			form.Content = issue_template.RenderToMarkdown(it, values)
+		}
+		if it != nil {
+			templateLabelIDs, templateAssigneeIDs, warnings, err := issue_service.TemplateMetas(repo, it, nil, assigneeIDs)
+			if err != nil {
+				ctx.ServerError("TemplateMetas", err)
+				return
+			}
+			// writers have been shown the labels of the template preselected and may have removed some,
+			// the others can't choose labels so the labels of the template are applied
+			if !ctx.Repo.CanWrite(models.UnitTypeIssues) {
+				labelIDs = templateLabelIDs
+			}
+			assigneeIDs = templateAssigneeIDs
+			if len(warnings) > 0 {
+				ctx.Flash.Warning(ctx.Tr("repo.issues.new.template_warnings", strings.Join(warnings, ", ")))
+			}
+		}
+	}
+
 	issue := &models.Issue{
 		RepoID:      repo.ID,
 		Title:       form.Title,
@@ -1550,6 +1667,14 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["IsBlockedByRejection"] = pull.ProtectedBranch.MergeBlockedByRejectedReview(pull)
 			ctx.Data["IsBlockedByOfficialReviewRequests"] = pull.ProtectedBranch.MergeBlockedByOfficialReviewRequests(pull)
 			ctx.Data["IsBlockedByOutdatedBranch"] = pull.ProtectedBranch.MergeBlockedByOutdatedBranch(pull)
+			if pull.ProtectedBranch.RequireCodeOwnerApproval {
+				approved, err := pull_service.HasCodeOwnerApproval(pull)
+				if err != nil {
+					ctx.ServerError("HasCodeOwnerApproval", err)
+					return
+				}
+				ctx.Data["IsBlockedByCodeOwners"] = !approved
+			}
 			ctx.Data["GrantedApprovals"] = cnt
 			ctx.Data["RequireSigned"] = pull.ProtectedBranch.RequireSignedCommits
 			ctx.Data["ChangedProtectedFiles"] = pull.ChangedProtectedFiles
@@ -1593,6 +1718,21 @@ func ViewIssue(ctx *context.Context) {
 		}
 
 		ctx.Data["StillCanManualMerge"] = stillCanManualMerge()
+		ctx.Data["EnableMergeQueue"] = prConfig.EnableMergeQueue
+
+		if entry, err := models.GetMergeQueueEntryByPullID(pull.ID); err == nil {
+			position, err := entry.Position()
+			if err != nil {
+				ctx.ServerError("Position", err)
+				return
+			}
+			ctx.Data["MergeQueueEntry"] = entry
+			ctx.Data["MergeQueuePosition"] = position
+			ctx.Data["CanDequeue"] = ctx.IsSigned && (entry.DoerID == ctx.User.ID || ctx.Data["AllowMerge"] == true)
+		} else if !models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.ServerError("GetMergeQueueEntryByPullID", err)
+			return
+		}
 	}
 
 	// Get Dependencies
@@ -2001,6 +2141,11 @@ func UpdateIssueStatus(ctx *context.Context) {
 						"error": "cannot close this issue because it still has open dependencies",
 					})
 					return
+				} else if models.IsErrPullRequestHeadRefMissing(err) {
+					ctx.JSON(http.StatusPreconditionFailed, map[string]interface{}{
+						"error": "cannot reopen this pull request, its head branch doesn't exist anymore",
+					})
+					return
 				}
 				ctx.ServerError("ChangeStatus", err)
 				return
@@ -2095,7 +2240,11 @@ func NewComment(ctx *context.Context) {
 				if err := issue_service.ChangeStatus(issue, ctx.User, isClosed); err != nil {
 					log.Error("ChangeStatus: %v", err)
 
-					if models.IsErrDependenciesLeft(err) {
+					if models.IsErrPullRequestHeadRefMissing(err) {
+						ctx.Flash.Error(ctx.Tr("repo.pulls.reopen_failed.head_ref_missing"))
+						ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index), http.StatusSeeOther)
+						return
+					} else if models.IsErrDependenciesLeft(err) {
 						if issue.IsPull {
 							ctx.Flash.Error(ctx.Tr("repo.issues.dependency.pr_close_blocked"))
 							ctx.Redirect(fmt.Sprintf("%s/pulls/%d", ctx.Repo.RepoLink, issue.Index), http.StatusSeeOther)
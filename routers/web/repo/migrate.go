@@ -78,6 +78,8 @@ func handleMigrateError(ctx *context.Context, owner *models.User, err error, nam
 		ctx.RenderWithErr(ctx.Tr("form.2fa_auth_required"), tpl, form)
 	case models.IsErrReachLimitOfRepo(err):
 		ctx.RenderWithErr(ctx.Tr("repo.form.reach_limit_of_creation", owner.MaxCreationLimit()), tpl, form)
+	case models.IsErrPublicRepoNotAllowed(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.public_repo_not_allowed", err.(models.ErrPublicRepoNotAllowed).OrgName), tpl, form)
 	case models.IsErrRepoAlreadyExist(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("form.repo_name_been_taken"), tpl, form)
@@ -230,6 +232,9 @@ func MigratePost(ctx *context.Context) {
 	}
 
 	err = models.CheckCreateRepository(ctx.User, ctxUser, opts.RepoName, false)
+	if err == nil && !opts.Private {
+		err = models.CheckPublicRepoAllowed(ctx.User, ctxUser, false)
+	}
 	if err != nil {
 		handleMigrateError(ctx, ctxUser, err, "MigratePost", tpl, form)
 		return
@@ -240,6 +240,8 @@ func ForkPost(ctx *context.Context) {
 			ctx.RenderWithErr(ctx.Tr("repo.form.name_reserved", err.(models.ErrNameReserved).Name), tplFork, &form)
 		case models.IsErrNamePatternNotAllowed(err):
 			ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(models.ErrNamePatternNotAllowed).Pattern), tplFork, &form)
+		case models.IsErrPublicRepoNotAllowed(err):
+			ctx.RenderWithErr(ctx.Tr("repo.form.public_repo_not_allowed", err.(models.ErrPublicRepoNotAllowed).OrgName), tplFork, &form)
 		default:
 			ctx.ServerError("ForkPost", err)
 		}
@@ -310,6 +312,31 @@ func setMergeTarget(ctx *context.Context, pull *models.PullRequest) {
 	ctx.Data["BaseBranchHTMLURL"] = pull.GetBaseBranchHTMLURL()
 }
 
+// getPullHeadCommitID returns the head commit of a pull request. If its head ref has been cleaned up,
+// the commit recorded at that time is used, or for merge commits the merged head parent. A git.ErrNotExist
+// is returned if the head commit isn't available anymore, e.g. because it has been garbage collected.
+func getPullHeadCommitID(gitRepo *git.Repository, pull *models.PullRequest) (string, error) {
+	commitID, err := gitRepo.GetRefCommitID(pull.GetGitRefName())
+	if err == nil || !git.IsErrNotExist(err) {
+		return commitID, err
+	}
+	if pull.HeadRefCommitID != "" {
+		// nothing keeps the recorded commit from being garbage collected
+		if _, err := gitRepo.GetCommit(pull.HeadRefCommitID); err != nil {
+			return "", err
+		}
+		return pull.HeadRefCommitID, nil
+	}
+	if pull.HasMerged && pull.MergedCommitID != "" {
+		if merged, mergedErr := gitRepo.GetCommit(pull.MergedCommitID); mergedErr == nil && merged.ParentCount() == 2 {
+			if parentID, parentErr := merged.ParentID(1); parentErr == nil {
+				return parentID.String(), nil
+			}
+		}
+	}
+	return "", err
+}
+
 // PrepareMergedViewPullInfo show meta information for a merged pull request view page
 func PrepareMergedViewPullInfo(ctx *context.Context, issue *models.Issue) *git.CompareInfo {
 	pull := issue.PullRequest
@@ -317,8 +344,24 @@ func PrepareMergedViewPullInfo(ctx *context.Context, issue *models.Issue) *git.C
 	setMergeTarget(ctx, pull)
 	ctx.Data["HasMerged"] = true
 
+	headCommitID, err := getPullHeadCommitID(ctx.Repo.GitRepo, pull)
+	if err != nil {
+		if git.IsErrNotExist(err) && pull.HeadRefCommitID != "" {
+			// the head ref has been cleaned up and its commits are gone
+			ctx.Data["IsHeadCommitGone"] = true
+			ctx.Data["NumCommits"] = 0
+			ctx.Data["NumFiles"] = 0
+			return nil
+		}
+		if !git.IsErrNotExist(err) {
+			ctx.ServerError("GetRefCommitID", err)
+			return nil
+		}
+		headCommitID = pull.GetGitRefName()
+	}
+
 	compareInfo, err := ctx.Repo.GitRepo.GetCompareInfo(ctx.Repo.Repository.RepoPath(),
-		pull.MergeBase, pull.GetGitRefName(), true, false)
+		pull.MergeBase, headCommitID, true, false)
 	if err != nil {
 		if strings.Contains(err.Error(), "fatal: Not a valid object name") || strings.Contains(err.Error(), "unknown revision or path not in the working tree") {
 			ctx.Data["IsPullRequestBroken"] = true
@@ -385,8 +428,15 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		ctx.Data["BaseTarget"] = pull.BaseBranch
 		ctx.Data["HeadTarget"] = pull.HeadBranch
 
-		sha, err := baseGitRepo.GetRefCommitID(pull.GetGitRefName())
+		sha, err := getPullHeadCommitID(baseGitRepo, pull)
 		if err != nil {
+			if git.IsErrNotExist(err) {
+				// the head ref has been cleaned up as well
+				ctx.Data["IsHeadCommitGone"] = pull.HeadRefCommitID != ""
+				ctx.Data["NumCommits"] = 0
+				ctx.Data["NumFiles"] = 0
+				return nil
+			}
 			ctx.ServerError(fmt.Sprintf("GetRefCommitID(%s)", pull.GetGitRefName()), err)
 			return nil
 		}
@@ -401,7 +451,7 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		}
 
 		compareInfo, err := baseGitRepo.GetCompareInfo(pull.BaseRepo.RepoPath(),
-			pull.MergeBase, pull.GetGitRefName(), true, false)
+			pull.MergeBase, sha, true, false)
 		if err != nil {
 			if strings.Contains(err.Error(), "fatal: Not a valid object name") {
 				ctx.Data["IsPullRequestBroken"] = true
@@ -459,10 +509,11 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 		ctx.Data["GetCommitMessages"] = pull_service.GetSquashMergeCommitMessages(pull)
 	}
 
-	sha, err := baseGitRepo.GetRefCommitID(pull.GetGitRefName())
+	sha, err := getPullHeadCommitID(baseGitRepo, pull)
 	if err != nil {
 		if git.IsErrNotExist(err) {
 			ctx.Data["IsPullRequestBroken"] = true
+			ctx.Data["IsHeadCommitGone"] = pull.HeadRefCommitID != ""
 			if pull.IsSameRepo() {
 				ctx.Data["HeadTarget"] = pull.HeadBranch
 			} else if pull.HeadRepo == nil {
@@ -517,7 +568,7 @@ func PrepareViewPullInfo(ctx *context.Context, issue *models.Issue) *git.Compare
 	}
 
 	compareInfo, err := baseGitRepo.GetCompareInfo(pull.BaseRepo.RepoPath(),
-		git.BranchPrefix+pull.BaseBranch, pull.GetGitRefName(), true, false)
+		git.BranchPrefix+pull.BaseBranch, sha, true, false)
 	if err != nil {
 		if strings.Contains(err.Error(), "fatal: Not a valid object name") {
 			ctx.Data["IsPullRequestBroken"] = true
@@ -573,6 +624,11 @@ func ViewPullCommits(ctx *context.Context) {
 	if ctx.Written() {
 		return
 	} else if prInfo == nil {
+		if ctx.Data["IsHeadCommitGone"] == true {
+			ctx.Flash.Info(ctx.Tr("repo.pulls.head_commits_gone"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(issue.Index))
+			return
+		}
 		ctx.NotFound("ViewPullCommits", nil)
 		return
 	}
@@ -615,11 +671,16 @@ func ViewPullFiles(ctx *context.Context) {
 	if ctx.Written() {
 		return
 	} else if prInfo == nil {
+		if ctx.Data["IsHeadCommitGone"] == true {
+			ctx.Flash.Info(ctx.Tr("repo.pulls.head_commits_gone"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(issue.Index))
+			return
+		}
 		ctx.NotFound("ViewPullFiles", nil)
 		return
 	}
 
-	headCommitID, err := gitRepo.GetRefCommitID(pull.GetGitRefName())
+	headCommitID, err := getPullHeadCommitID(gitRepo, pull)
 	if err != nil {
 		ctx.ServerError("GetRefCommitID", err)
 		return
@@ -864,8 +925,13 @@ func MergePullRequest(ctx *context.Context) {
 		return
 	}
 
-	if err := pull_service.CheckPRReadyToMerge(pr, false); err != nil {
-		if !models.IsErrNotAllowedToMerge(err) {
+	if err := pull_service.CheckPRReadyToMergeWithUpdate(pr, ctx.User); err != nil {
+		if models.IsErrMergeConflicts(err) || models.IsErrRebaseConflicts(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.update_on_merge_conflict"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+			return
+		}
+		if !models.IsErrNotAllowedToMerge(err) && !models.IsErrPullRequestOutdated(err) {
 			ctx.ServerError("Merge PR status", err)
 			return
 		}
@@ -922,6 +988,10 @@ func MergePullRequest(ctx *context.Context) {
 			ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
 			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
 			return
+		} else if models.IsErrPullRequestIsDraft(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_wip"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+			return
 		} else if models.IsErrMergeConflicts(err) {
 			conflictError := err.(models.ErrMergeConflicts)
 			flashError, err := ctx.HTMLString(string(tplAlertDetails), map[string]interface{}{
@@ -1010,6 +1080,128 @@ func MergePullRequest(ctx *context.Context) {
 	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
 }
 
+// EnqueuePullRequest response for adding a pull request to the merge queue
+func EnqueuePullRequest(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.MergePullRequestForm)
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if issue.IsClosed {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.is_closed"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(issue.Index))
+		return
+	}
+
+	pr := issue.PullRequest
+
+	allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+	if err != nil {
+		ctx.ServerError("IsUserAllowedToMerge", err)
+		return
+	}
+	if !allowedMerge {
+		ctx.Flash.Error(ctx.Tr("repo.pulls.update_not_allowed"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(issue.Index))
+		return
+	}
+
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.Data["ErrorMsg"].(string))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+		return
+	}
+
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		if models.MergeStyle(form.Do) == models.MergeStyleMerge {
+			message = pr.GetDefaultMergeMessage()
+		}
+		if models.MergeStyle(form.Do) == models.MergeStyleRebaseMerge {
+			message = pr.GetDefaultMergeMessage()
+		}
+		if models.MergeStyle(form.Do) == models.MergeStyleSquash {
+			message = pr.GetDefaultSquashMessage()
+		}
+	}
+
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	pr.Issue = issue
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	if _, err = pull_service.AddToMergeQueue(pr, ctx.User, models.MergeStyle(form.Do), message); err != nil {
+		if models.IsErrMergeQueueDisabled(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.merge_queue_disabled"))
+		} else if models.IsErrInvalidMergeStyle(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.invalid_merge_option"))
+		} else if models.IsErrPullRequestIsDraft(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_wip"))
+		} else if models.IsErrNotAllowedToMerge(err) || models.IsErrPullRequestOutdated(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.no_merge_not_ready"))
+		} else if models.IsErrPullRequestAlreadyQueued(err) {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.merge_queue_already_queued"))
+		} else {
+			ctx.ServerError("AddToMergeQueue", err)
+			return
+		}
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+		return
+	}
+
+	log.Trace("Pull request added to merge queue: %d", pr.ID)
+
+	ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue_added"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+}
+
+// DequeuePullRequest response for removing a pull request from the merge queue
+func DequeuePullRequest(ctx *context.Context) {
+	issue := checkPullInfo(ctx)
+	if ctx.Written() {
+		return
+	}
+	pr := issue.PullRequest
+
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		if models.IsErrMergeQueueEntryNotExist(err) {
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+		} else {
+			ctx.ServerError("GetMergeQueueEntryByPullID", err)
+		}
+		return
+	}
+
+	// the user who added the pull request can always remove it again
+	if entry.DoerID != ctx.User.ID {
+		allowedMerge, err := pull_service.IsUserAllowedToMerge(pr, ctx.Repo.Permission, ctx.User)
+		if err != nil {
+			ctx.ServerError("IsUserAllowedToMerge", err)
+			return
+		}
+		if !allowedMerge {
+			ctx.Flash.Error(ctx.Tr("repo.pulls.update_not_allowed"))
+			ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+			return
+		}
+	}
+
+	pr.Issue = issue
+	pr.Issue.Repo = ctx.Repo.Repository
+
+	if err := pull_service.RemoveFromMergeQueue(pr, ctx.User); err != nil && !models.IsErrMergeQueueEntryNotExist(err) {
+		ctx.ServerError("RemoveFromMergeQueue", err)
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.pulls.merge_queue_removed"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/pulls/" + fmt.Sprint(pr.Index))
+}
+
 func stopTimerIfAvailable(user *models.User, issue *models.Issue) error {
 
 	if models.StopwatchExists(user.ID, issue.ID) {
@@ -1363,6 +1555,7 @@ func UpdatePullRequestTarget(ctx *context.Context) {
 		return
 	}
 
+	oldBranch := pr.BaseBranch
 	if err := pull_service.ChangeTargetBranch(pr, ctx.User, targetBranch); err != nil {
 		if models.IsErrPullRequestAlreadyExists(err) {
 			err := err.(models.ErrPullRequestAlreadyExists)
@@ -1399,12 +1592,28 @@ func UpdatePullRequestTarget(ctx *context.Context) {
 				"error":      err.Error(),
 				"user_error": errorMessage,
 			})
+		} else if models.IsErrBranchDoesNotExist(err) {
+			errorMessage := ctx.Tr("repo.pulls.target_branch_not_exist")
+
+			ctx.Flash.Error(errorMessage)
+			ctx.JSON(http.StatusNotFound, map[string]interface{}{
+				"error":      err.Error(),
+				"user_error": errorMessage,
+			})
+		} else if models.IsErrNotAllowedToMerge(err) {
+			errorMessage := ctx.Tr("repo.pulls.target_branch_protected", targetBranch)
+
+			ctx.Flash.Error(errorMessage)
+			ctx.JSON(http.StatusForbidden, map[string]interface{}{
+				"error":      err.Error(),
+				"user_error": errorMessage,
+			})
 		} else {
 			ctx.ServerError("UpdatePullRequestTarget", err)
 		}
 		return
 	}
-	notification.NotifyPullRequestChangeTargetBranch(ctx.User, pr, targetBranch)
+	notification.NotifyPullRequestChangeTargetBranch(ctx.User, pr, oldBranch)
 
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"base_branch": pr.BaseBranch,
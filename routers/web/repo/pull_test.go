@@ -0,0 +1,70 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	pull_service "code.gitea.io/gitea/services/pull"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPullHeadCommitIDAfterCleanup(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	pull := db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 1}).(*models.PullRequest)
+	_, err := db.GetEngine(db.DefaultContext).ID(pull.IssueID).Cols("is_closed", "closed_unix").
+		Update(&models.Issue{IsClosed: true, ClosedUnix: 1})
+	assert.NoError(t, err)
+
+	// a head commit which is only reachable from the head ref of the pull request
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME=user", "GIT_AUTHOR_EMAIL=user@localhost",
+		"GIT_COMMITTER_NAME=user", "GIT_COMMITTER_EMAIL=user@localhost")
+	run := func(args ...string) string {
+		stdout, err := git.NewCommand(args...).RunInDirWithEnv(repo.RepoPath(), env)
+		assert.NoError(t, err)
+		return strings.TrimSpace(stdout)
+	}
+	headCommitID := run("commit-tree", "-p", "HEAD", "-m", "head", "HEAD^{tree}")
+	run("update-ref", pull.GetGitRefName(), headCommitID)
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	commitID, err := getPullHeadCommitID(gitRepo, pull)
+	assert.NoError(t, err)
+	assert.Equal(t, headCommitID, commitID)
+
+	count, err := pull_service.CleanupHeadRefs(context.Background(), repo, time.Hour)
+	assert.NoError(t, err)
+	assert.Greater(t, count, 0)
+	assert.False(t, git.IsReferenceExist(repo.RepoPath(), pull.GetGitRefName()))
+
+	pull = db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 1}).(*models.PullRequest)
+	assert.Equal(t, headCommitID, pull.HeadRefCommitID)
+	commitID, err = getPullHeadCommitID(gitRepo, pull)
+	assert.NoError(t, err)
+	assert.Equal(t, headCommitID, commitID)
+
+	// once the unreachable head commit has been garbage collected it is reported as missing
+	run("reflog", "expire", "--expire=now", "--all")
+	run("gc", "--prune=now")
+	gitRepo.Close()
+	gitRepo, err = git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	_, err = getPullHeadCommitID(gitRepo, pull)
+	assert.True(t, git.IsErrNotExist(err))
+}
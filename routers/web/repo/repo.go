@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -181,6 +182,15 @@ func handleCreateError(ctx *context.Context, owner *models.User, err error, name
 	case models.IsErrNamePatternNotAllowed(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(models.ErrNamePatternNotAllowed).Pattern), tpl, form)
+	case models.IsErrNameTooLong(err):
+		ctx.Data["Err_RepoName"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.form.name_too_long", err.(models.ErrNameTooLong).MaxLength), tpl, form)
+	case models.IsErrObjectFormatNotAllowed(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.object_format_not_allowed", err.(models.ErrObjectFormatNotAllowed).ObjectFormat), tpl, form)
+	case models.IsErrPublicRepoNotAllowed(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.public_repo_not_allowed", err.(models.ErrPublicRepoNotAllowed).OrgName), tpl, form)
+	case models.IsErrInitFileNotExist(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.init_file_not_exist", err.(models.ErrInitFileNotExist).Name), tpl, form)
 	default:
 		ctx.ServerError(name, err)
 	}
@@ -367,12 +377,26 @@ func RedirectDownload(ctx *context.Context) {
 	ctx.Error(http.StatusNotFound)
 }
 
+// newArchiveRequest creates the request of the archive of the repository, or of its directory given by the path parameter
+func newArchiveRequest(ctx *context.Context) *archiver_service.ArchiveRequest {
+	aReq, err := archiver_service.NewRequestForPath(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, ctx.Params("*"), ctx.FormString("path"))
+	if err != nil {
+		if archiver_service.IsErrInvalidArchivePath(err) {
+			ctx.Error(http.StatusBadRequest, err.Error())
+		} else if git.IsErrNotExist(err) {
+			ctx.Error(http.StatusNotFound)
+		} else {
+			ctx.ServerError("archiver_service.NewRequestForPath", err)
+		}
+		return nil
+	}
+	return aReq
+}
+
 // Download an archive of a repository
 func Download(ctx *context.Context) {
-	uri := ctx.Params("*")
-	aReq, err := archiver_service.NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri)
-	if err != nil {
-		ctx.ServerError("archiver_service.NewRequest", err)
+	aReq := newArchiveRequest(ctx)
+	if ctx.Written() {
 		return
 	}
 	if aReq == nil {
@@ -380,7 +404,7 @@ func Download(ctx *context.Context) {
 		return
 	}
 
-	archiver, err := models.GetRepoArchiver(db.DefaultContext, aReq.RepoID, aReq.Type, aReq.CommitID)
+	archiver, err := models.GetRepoArchiver(db.DefaultContext, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Path)
 	if err != nil {
 		ctx.ServerError("models.GetRepoArchiver", err)
 		return
@@ -410,7 +434,7 @@ func Download(ctx *context.Context) {
 				return
 			}
 			times++
-			archiver, err = models.GetRepoArchiver(db.DefaultContext, aReq.RepoID, aReq.Type, aReq.CommitID)
+			archiver, err = models.GetRepoArchiver(db.DefaultContext, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Path)
 			if err != nil {
 				ctx.ServerError("archiver_service.StartArchive", err)
 				return
@@ -455,10 +479,8 @@ func download(ctx *context.Context, archiveName string, archiver *models.RepoArc
 // a request that's already in-progress, but the archiver service will just
 // kind of drop it on the floor if this is the case.
 func InitiateDownload(ctx *context.Context) {
-	uri := ctx.Params("*")
-	aReq, err := archiver_service.NewRequest(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri)
-	if err != nil {
-		ctx.ServerError("archiver_service.NewRequest", err)
+	aReq := newArchiveRequest(ctx)
+	if ctx.Written() {
 		return
 	}
 	if aReq == nil {
@@ -466,7 +488,7 @@ func InitiateDownload(ctx *context.Context) {
 		return
 	}
 
-	archiver, err := models.GetRepoArchiver(db.DefaultContext, aReq.RepoID, aReq.Type, aReq.CommitID)
+	archiver, err := models.GetRepoArchiver(db.DefaultContext, aReq.RepoID, aReq.Type, aReq.CommitID, aReq.Path)
 	if err != nil {
 		ctx.ServerError("archiver_service.StartArchive", err)
 		return
@@ -13,6 +13,7 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
@@ -62,9 +63,20 @@ func Settings(ctx *context.Context) {
 	ctx.Data["SigningKeyAvailable"] = len(signing) > 0
 	ctx.Data["SigningSettings"] = setting.Repository.Signing
 
+	closeKeywords, reopenKeywords := ctx.Repo.Repository.IssueKeywords()
+	ctx.Data["IssueCloseKeywords"] = strings.Join(closeKeywords, ", ")
+	ctx.Data["IssueReopenKeywords"] = strings.Join(reopenKeywords, ", ")
+
 	ctx.HTML(http.StatusOK, tplSettingsOptions)
 }
 
+// splitIssueKeywords splits a comma or space separated list of keywords
+func splitIssueKeywords(keywords string) []string {
+	return strings.FieldsFunc(keywords, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
 // SettingsPost response for changes of a repository
 func SettingsPost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.RepoSettingForm)
@@ -73,7 +85,16 @@ func SettingsPost(ctx *context.Context) {
 
 	repo := ctx.Repo.Repository
 
-	switch ctx.FormString("action") {
+	// An archived repository can only be unarchived or deleted.
+	action := ctx.FormString("action")
+	if action != "unarchive" && action != "delete" {
+		if err := repo.CheckNotArchived(); err != nil {
+			ctx.Error(http.StatusForbidden, err.Error())
+			return
+		}
+	}
+
+	switch action {
 	case "update":
 		if ctx.HasError() {
 			ctx.HTML(http.StatusOK, tplSettingsOptions)
@@ -109,6 +130,8 @@ func SettingsPost(ctx *context.Context) {
 					}
 				case models.IsErrNamePatternNotAllowed(err):
 					ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(models.ErrNamePatternNotAllowed).Pattern), tplSettingsOptions, &form)
+				case models.IsErrNameTooLong(err):
+					ctx.RenderWithErr(ctx.Tr("repo.form.name_too_long", err.(models.ErrNameTooLong).MaxLength), tplSettingsOptions, &form)
 				default:
 					ctx.ServerError("ChangeRepositoryName", err)
 				}
@@ -138,6 +161,10 @@ func SettingsPost(ctx *context.Context) {
 
 		repo.IsPrivate = form.Private
 		if err := models.UpdateRepository(repo, visibilityChanged); err != nil {
+			if models.IsErrPublicRepoNotAllowed(err) {
+				ctx.RenderWithErr(ctx.Tr("repo.form.public_repo_not_allowed", err.(models.ErrPublicRepoNotAllowed).OrgName), tplSettingsOptions, &form)
+				return
+			}
 			ctx.ServerError("UpdateRepository", err)
 			return
 		}
@@ -380,27 +407,36 @@ func SettingsPost(ctx *context.Context) {
 		}
 
 		if form.EnableIssues && form.EnableExternalTracker && !models.UnitTypeExternalTracker.UnitGlobalDisabled() {
-			if !validation.IsValidExternalURL(form.ExternalTrackerURL) {
-				ctx.Flash.Error(ctx.Tr("repo.settings.external_tracker_url_error"))
-				ctx.Redirect(repo.Link() + "/settings")
-				return
+			config := &models.ExternalTrackerConfig{
+				ExternalTrackerURL:           form.ExternalTrackerURL,
+				ExternalTrackerFormat:        form.TrackerURLFormat,
+				ExternalTrackerStyle:         form.TrackerIssueStyle,
+				ExternalTrackerRegexpPattern: form.ExternalTrackerRegexpPattern,
 			}
-			if len(form.TrackerURLFormat) != 0 && !validation.IsValidExternalTrackerURLFormat(form.TrackerURLFormat) {
-				ctx.Flash.Error(ctx.Tr("repo.settings.tracker_url_format_error"))
+			if err := config.Validate(); err != nil {
+				switch err.(models.ErrInvalidExternalTracker).Field {
+				case "url":
+					ctx.Flash.Error(ctx.Tr("repo.settings.external_tracker_url_error"))
+				case "format":
+					ctx.Flash.Error(ctx.Tr("repo.settings.tracker_url_format_error"))
+				case "style":
+					ctx.Flash.Error(ctx.Tr("repo.settings.tracker_issue_style_error"))
+				default:
+					ctx.Flash.Error(ctx.Tr("repo.settings.tracker_issue_style.regexp_pattern_error", err.(models.ErrInvalidExternalTracker).Reason))
+				}
 				ctx.Redirect(repo.Link() + "/settings")
 				return
 			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeExternalTracker,
-				Config: &models.ExternalTrackerConfig{
-					ExternalTrackerURL:    form.ExternalTrackerURL,
-					ExternalTrackerFormat: form.TrackerURLFormat,
-					ExternalTrackerStyle:  form.TrackerIssueStyle,
-				},
+				Config: config,
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeIssues)
 		} else if form.EnableIssues && !form.EnableExternalTracker && !models.UnitTypeIssues.UnitGlobalDisabled() {
+			if !models.IsValidIssueSortType(form.DefaultIssueSortType) {
+				form.DefaultIssueSortType = ""
+			}
 			units = append(units, models.RepoUnit{
 				RepoID: repo.ID,
 				Type:   models.UnitTypeIssues,
@@ -408,6 +444,9 @@ func SettingsPost(ctx *context.Context) {
 					EnableTimetracker:                form.EnableTimetracker,
 					AllowOnlyContributorsToTrackTime: form.AllowOnlyContributorsToTrackTime,
 					EnableDependencies:               form.EnableIssueDependencies,
+					DefaultSortType:                  form.DefaultIssueSortType,
+					CloseKeywords:                    splitIssueKeywords(form.IssueCloseKeywords),
+					ReopenKeywords:                   splitIssueKeywords(form.IssueReopenKeywords),
 				},
 			})
 			deleteUnitTypes = append(deleteUnitTypes, models.UnitTypeExternalTracker)
@@ -443,6 +482,10 @@ func SettingsPost(ctx *context.Context) {
 					AutodetectManualMerge:         form.EnableAutodetectManualMerge,
 					DefaultDeleteBranchAfterMerge: form.DefaultDeleteBranchAfterMerge,
 					DefaultMergeStyle:             models.MergeStyle(form.PullsDefaultMergeStyle),
+					EnableMergeQueue:              form.EnableMergeQueue,
+					MergeCommitAuthorStyle:        models.MergeCommitAuthorStyle(form.PullsMergeCommitAuthorStyle),
+					AddReviewedByTrailers:         form.PullsAddReviewedByTrailers,
+					AddCoAuthorTrailers:           form.PullsAddCoAuthorTrailers,
 				},
 			})
 		} else if !models.UnitTypePullRequests.UnitGlobalDisabled() {
@@ -518,13 +561,9 @@ func SettingsPost(ctx *context.Context) {
 			ctx.Error(http.StatusNotFound)
 			return
 		}
-		repo.IsMirror = false
 
-		if _, err := repository.CleanUpMigrateInfo(repo); err != nil {
-			ctx.ServerError("CleanUpMigrateInfo", err)
-			return
-		} else if err = models.DeleteMirrorByRepoID(ctx.Repo.Repository.ID); err != nil {
-			ctx.ServerError("DeleteMirrorByRepoID", err)
+		if err := mirror_service.ConvertToNormal(repo); err != nil {
+			ctx.ServerError("ConvertToNormal", err)
 			return
 		}
 		log.Trace("Repository converted from mirror to regular: %s", repo.FullName())
@@ -851,6 +890,15 @@ func ChangeCollaborationAccessMode(ctx *context.Context) {
 	}
 }
 
+// ChangeCollaborationWikiAccessMode response for changing the wiki access mode of a collaboration
+func ChangeCollaborationWikiAccessMode(ctx *context.Context) {
+	if err := ctx.Repo.Repository.ChangeCollaborationWikiAccessMode(
+		ctx.FormInt64("uid"),
+		models.AccessMode(ctx.FormInt("mode"))); err != nil {
+		log.Error("ChangeCollaborationWikiAccessMode: %v", err)
+	}
+}
+
 // DeleteCollaboration delete a collaboration for a repository
 func DeleteCollaboration(ctx *context.Context) {
 	if err := ctx.Repo.Repository.DeleteCollaboration(ctx.FormInt64("id")); err != nil {
@@ -1054,6 +1102,10 @@ func DeployKeysPost(ctx *context.Context) {
 			ctx.Flash.Info(ctx.Tr("settings.ssh_disabled"))
 		} else if models.IsErrKeyUnableVerify(err) {
 			ctx.Flash.Info(ctx.Tr("form.unable_verify_ssh_key"))
+		} else if models.IsErrKeyPolicyViolation(err) {
+			ctx.Data["HasError"] = true
+			ctx.Data["Err_Content"] = true
+			ctx.Flash.Error(ctx.Tr("form.ssh_key_policy_violation", err.Error()))
 		} else {
 			ctx.Data["HasError"] = true
 			ctx.Data["Err_Content"] = true
@@ -1063,7 +1115,7 @@ func DeployKeysPost(ctx *context.Context) {
 		return
 	}
 
-	key, err := models.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, !form.IsWritable)
+	key, err := repo_service.AddDeployKey(ctx.User, ctx.Repo.Repository, form.Title, content, !form.IsWritable, form.CanWriteStatuses)
 	if err != nil {
 		ctx.Data["HasError"] = true
 		switch {
@@ -1086,13 +1138,18 @@ func DeployKeysPost(ctx *context.Context) {
 	}
 
 	log.Trace("Deploy key added: %d", ctx.Repo.Repository.ID)
-	ctx.Flash.Success(ctx.Tr("repo.settings.add_key_success", key.Name))
+	if key.CanWriteStatuses {
+		ctx.Flash.Success(ctx.Tr("repo.settings.add_key_statuses_token_success", key.Name))
+		ctx.Flash.Info(key.StatusesToken)
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.add_key_success", key.Name))
+	}
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/keys")
 }
 
 // DeleteDeployKey response for deleting a deploy key
 func DeleteDeployKey(ctx *context.Context) {
-	if err := models.DeleteDeployKey(ctx.User, ctx.FormInt64("id")); err != nil {
+	if err := repo_service.DeleteDeployKey(ctx.User, ctx.FormInt64("id")); err != nil {
 		ctx.Flash.Error("DeleteDeployKey: " + err.Error())
 	} else {
 		ctx.Flash.Success(ctx.Tr("repo.settings.deploy_key_deletion_success"))
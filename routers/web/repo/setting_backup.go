@@ -0,0 +1,57 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplBackups base.TplName = "repo/settings/backups"
+
+	// backupsPagingNum is the number of the latest backups shown on the settings page
+	backupsPagingNum = 20
+)
+
+// Backups shows the scheduled backups of a repository
+func Backups(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.settings.backups")
+	ctx.Data["PageIsSettingsBackups"] = true
+	ctx.Data["Retention"] = setting.RepoBackup.Retention
+
+	backups, _, err := models.FindRepoBackups(models.FindRepoBackupsOptions{
+		ListOptions: db.ListOptions{Page: 1, PageSize: backupsPagingNum},
+		RepoID:      ctx.Repo.Repository.ID,
+		Status:      -1,
+	})
+	if err != nil {
+		ctx.ServerError("FindRepoBackups", err)
+		return
+	}
+	ctx.Data["Backups"] = backups
+
+	ctx.HTML(http.StatusOK, tplBackups)
+}
+
+// BackupsPost enables or disables the scheduled backups of a repository
+func BackupsPost(ctx *context.Context) {
+	repo := ctx.Repo.Repository
+	repo.IsBackupEnabled = ctx.FormBool("enable_backup")
+	if err := models.UpdateRepositoryCols(repo, "is_backup_enabled"); err != nil {
+		ctx.ServerError("UpdateRepositoryCols", err)
+		return
+	}
+	log.Trace("Repository backups of %-v enabled: %t", repo, repo.IsBackupEnabled)
+
+	ctx.Flash.Success(ctx.Tr("repo.settings.update_settings_success"))
+	ctx.Redirect(ctx.Repo.RepoLink + "/settings/backups")
+}
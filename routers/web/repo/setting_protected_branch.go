@@ -252,10 +252,13 @@ func SettingsProtectedBranchPost(ctx *context.Context) {
 		protectBranch.BlockOnRejectedReviews = f.BlockOnRejectedReviews
 		protectBranch.BlockOnOfficialReviewRequests = f.BlockOnOfficialReviewRequests
 		protectBranch.DismissStaleApprovals = f.DismissStaleApprovals
+		protectBranch.DismissOnlyOnContentChange = f.DismissOnlyOnContentChange
+		protectBranch.RequireCodeOwnerApproval = f.RequireCodeOwnerApproval
 		protectBranch.RequireSignedCommits = f.RequireSignedCommits
 		protectBranch.ProtectedFilePatterns = f.ProtectedFilePatterns
 		protectBranch.UnprotectedFilePatterns = f.UnprotectedFilePatterns
 		protectBranch.BlockOnOutdatedBranch = f.BlockOnOutdatedBranch
+		protectBranch.AutoUpdateOnMerge = f.AutoUpdateOnMerge
 
 		err = models.UpdateProtectBranch(ctx.Repo.Repository, protectBranch, models.WhitelistOptions{
 			UserIDs:          whitelistUsers,
@@ -30,7 +30,7 @@ func TopicsPost(ctx *context.Context) {
 
 	validTopics, invalidTopics := models.SanitizeAndValidateTopics(topics)
 
-	if len(validTopics) > 25 {
+	if len(validTopics) > models.MaxTopicsPerRepo {
 		ctx.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
 			"invalidTopics": nil,
 			"message":       ctx.Tr("repo.topic.count_prompt"),
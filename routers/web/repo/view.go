@@ -30,9 +30,12 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/typesniffer"
+	traffic_service "code.gitea.io/gitea/services/traffic"
+	visit_service "code.gitea.io/gitea/services/visit"
 )
 
 const (
@@ -636,6 +639,8 @@ func Home(ctx *context.Context) {
 		return
 	}
 
+	traffic_service.Record(ctx.Repo.Repository.ID, models.RepoTrafficView, traffic_service.Visitor(ctx.User, ctx.RemoteAddr()))
+	visit_service.Record(ctx.User, ctx.Repo.Repository.ID)
 	renderCode(ctx)
 }
 
@@ -706,8 +711,12 @@ func renderDirectoryFiles(ctx *context.Context, timeout time.Duration) git.Entri
 	}
 
 	var c *git.LastCommitCache
-	if setting.CacheService.LastCommit.Enabled && ctx.Repo.CommitsCount >= setting.CacheService.LastCommit.CommitsCount {
-		c = git.NewLastCommitCache(ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, setting.LastCommitCacheTTLSeconds, cache.GetCache())
+	var branchName string
+	if ctx.Repo.IsViewBranch {
+		branchName = ctx.Repo.BranchName
+	}
+	if repo_module.UseLastCommitCache(ctx.Repo.Repository, branchName, ctx.Repo.CommitsCount) {
+		c = git.NewLastCommitCache(ctx.Repo.Repository.FullName(), ctx.Repo.GitRepo, setting.LastCommitCacheTTLSeconds, cache.GetLastCommitCache())
 	}
 
 	selected := map[string]bool{}
@@ -778,6 +787,25 @@ func renderLanguageStats(ctx *context.Context) {
 	ctx.Data["LanguageStats"] = langs
 }
 
+func renderLicenses(ctx *context.Context) {
+	licenses, err := ctx.Repo.Repository.GetLicenses()
+	if err != nil {
+		ctx.ServerError("Repo.GetLicenses", err)
+		return
+	}
+
+	// show each license once, linked to the first file it was detected in
+	seen := make(map[string]bool, len(licenses))
+	distinct := make(models.RepoLicenseList, 0, len(licenses))
+	for _, l := range licenses {
+		if !seen[l.License] {
+			seen[l.License] = true
+			distinct = append(distinct, l)
+		}
+	}
+	ctx.Data["RepoLicenses"] = distinct
+}
+
 func renderRepoTopics(ctx *context.Context) {
 	topics, _, err := models.FindTopics(&models.FindTopicOptions{
 		RepoID: ctx.Repo.Repository.ID,
@@ -829,6 +857,11 @@ func renderCode(ctx *context.Context) {
 		return
 	}
 
+	renderLicenses(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	if entry.IsDir() {
 		renderDirectory(ctx, treeLink)
 	} else {
@@ -152,6 +152,14 @@ func WebhooksNew(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, orCtx.NewTemplate)
 }
 
+// toHookSignatureAlgorithm returns the signature algorithm chosen in the form, defaulting to SHA256
+func toHookSignatureAlgorithm(name string) models.HookSignatureAlgorithm {
+	if models.IsValidHookSignatureAlgorithm(name) {
+		return models.HookSignatureAlgorithm(name)
+	}
+	return models.HookSignatureSHA256
+}
+
 // ParseHookEvent convert web form content to models.HookEvent
 func ParseHookEvent(form forms.WebhookForm) *models.HookEvent {
 	return &models.HookEvent{
@@ -177,8 +185,13 @@ func ParseHookEvent(form forms.WebhookForm) *models.HookEvent {
 			PullRequestReview:    form.PullRequestReview,
 			PullRequestSync:      form.PullRequestSync,
 			Repository:           form.Repository,
+			Digest:               form.Digest,
+			DeployKey:            form.DeployKey,
+			PublicKey:            form.PublicKey,
 		},
-		BranchFilter: form.BranchFilter,
+		BranchFilters:    models.ParseBranchFilters(form.BranchFilter),
+		DigestWindowDays: form.DigestWindowDays,
+		DigestSendEmpty:  form.DigestSendEmpty,
 	}
 }
 
@@ -209,16 +222,17 @@ func GiteaHooksNewPost(ctx *context.Context) {
 	}
 
 	w := &models.Webhook{
-		RepoID:          orCtx.RepoID,
-		URL:             form.PayloadURL,
-		HTTPMethod:      form.HTTPMethod,
-		ContentType:     contentType,
-		Secret:          form.Secret,
-		HookEvent:       ParseHookEvent(form.WebhookForm),
-		IsActive:        form.Active,
-		Type:            models.GITEA,
-		OrgID:           orCtx.OrgID,
-		IsSystemWebhook: orCtx.IsSystemWebhook,
+		RepoID:             orCtx.RepoID,
+		URL:                form.PayloadURL,
+		HTTPMethod:         form.HTTPMethod,
+		ContentType:        contentType,
+		Secret:             form.Secret,
+		SignatureAlgorithm: toHookSignatureAlgorithm(form.SignatureAlgorithm),
+		HookEvent:          ParseHookEvent(form.WebhookForm),
+		IsActive:           form.Active,
+		Type:               models.GITEA,
+		OrgID:              orCtx.OrgID,
+		IsSystemWebhook:    orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
@@ -264,15 +278,16 @@ func newGogsWebhookPost(ctx *context.Context, form forms.NewGogshookForm, kind m
 	}
 
 	w := &models.Webhook{
-		RepoID:          orCtx.RepoID,
-		URL:             form.PayloadURL,
-		ContentType:     contentType,
-		Secret:          form.Secret,
-		HookEvent:       ParseHookEvent(form.WebhookForm),
-		IsActive:        form.Active,
-		Type:            kind,
-		OrgID:           orCtx.OrgID,
-		IsSystemWebhook: orCtx.IsSystemWebhook,
+		RepoID:             orCtx.RepoID,
+		URL:                form.PayloadURL,
+		ContentType:        contentType,
+		Secret:             form.Secret,
+		SignatureAlgorithm: toHookSignatureAlgorithm(form.SignatureAlgorithm),
+		HookEvent:          ParseHookEvent(form.WebhookForm),
+		IsActive:           form.Active,
+		Type:               kind,
+		OrgID:              orCtx.OrgID,
+		IsSystemWebhook:    orCtx.IsSystemWebhook,
 	}
 	if err := w.UpdateEvent(); err != nil {
 		ctx.ServerError("UpdateEvent", err)
@@ -765,6 +780,7 @@ func WebHooksEditPost(ctx *context.Context) {
 	w.URL = form.PayloadURL
 	w.ContentType = contentType
 	w.Secret = form.Secret
+	w.SignatureAlgorithm = toHookSignatureAlgorithm(form.SignatureAlgorithm)
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	w.HTTPMethod = form.HTTPMethod
@@ -806,6 +822,7 @@ func GogsHooksEditPost(ctx *context.Context) {
 	w.URL = form.PayloadURL
 	w.ContentType = contentType
 	w.Secret = form.Secret
+	w.SignatureAlgorithm = toHookSignatureAlgorithm(form.SignatureAlgorithm)
 	w.HookEvent = ParseHookEvent(form.WebhookForm)
 	w.IsActive = form.Active
 	if err := w.UpdateEvent(); err != nil {
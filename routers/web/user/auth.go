@@ -1297,6 +1297,9 @@ func createUserInContext(ctx *context.Context, tpl base.TplName, form interface{
 		case models.IsErrNameCharsNotAllowed(err):
 			ctx.Data["Err_UserName"] = true
 			ctx.RenderWithErr(ctx.Tr("user.form.name_chars_not_allowed", err.(models.ErrNameCharsNotAllowed).Name), tpl, form)
+		case models.IsErrNameTooLong(err):
+			ctx.Data["Err_UserName"] = true
+			ctx.RenderWithErr(ctx.Tr("user.form.name_too_long", err.(models.ErrNameTooLong).MaxLength), tpl, form)
 		default:
 			ctx.ServerError("CreateUser", err)
 		}
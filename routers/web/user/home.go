@@ -92,7 +92,10 @@ func Dashboard(ctx *context.Context) {
 	}
 
 	var err error
-	var mirrors []*models.Repository
+	var (
+		mirrors     []*models.Repository
+		mirrorCount int64
+	)
 	if ctxUser.IsOrganization() {
 		var env models.AccessibleReposEnvironment
 		if ctx.Org.Team != nil {
@@ -104,13 +107,21 @@ func Dashboard(ctx *context.Context) {
 				return
 			}
 		}
-		mirrors, err = env.MirrorRepos()
+		mirrors, err = env.MirrorRepos(1, setting.UI.User.RepoPagingNum)
 		if err != nil {
 			ctx.ServerError("env.MirrorRepos", err)
 			return
 		}
+		mirrorCount, err = env.CountMirrorRepos()
+		if err != nil {
+			ctx.ServerError("env.CountMirrorRepos", err)
+			return
+		}
 	} else {
-		mirrors, err = ctxUser.GetMirrorRepositories()
+		mirrors, mirrorCount, err = ctxUser.GetMirrorRepositories(db.ListOptions{
+			Page:     1,
+			PageSize: setting.UI.User.RepoPagingNum,
+		}, models.SearchOrderByRecentUpdated)
 		if err != nil {
 			ctx.ServerError("GetMirrorRepositories", err)
 			return
@@ -122,7 +133,7 @@ func Dashboard(ctx *context.Context) {
 		ctx.ServerError("MirrorRepositoryList.LoadAttributes", err)
 		return
 	}
-	ctx.Data["MirrorCount"] = len(mirrors)
+	ctx.Data["MirrorCount"] = mirrorCount
 	ctx.Data["Mirrors"] = mirrors
 
 	ctx.Data["Feeds"] = feed.RetrieveFeeds(ctx, models.GetFeedsOptions{
@@ -14,12 +14,14 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/feed"
 	"code.gitea.io/gitea/routers/web/org"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 // GetUserByName get user by name
@@ -311,6 +313,14 @@ func Profile(ctx *context.Context) {
 
 		total = int(count)
 	default:
+		if page == 1 && keyword == "" {
+			profileReadme, err := repo_service.GetProfileReadme(ctx, ctxUser, ctx.User)
+			if err != nil {
+				log.Error("GetProfileReadme [%s]: %v", ctxUser.Name, err)
+			}
+			ctx.Data["ProfileReadme"] = profileReadme
+		}
+
 		repos, count, err = models.SearchRepository(&models.SearchRepoOptions{
 			ListOptions: db.ListOptions{
 				PageSize: setting.UI.User.RepoPagingNum,
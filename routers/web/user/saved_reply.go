@@ -0,0 +1,49 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/convert"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+type savedReply struct {
+	*api.SavedReply
+	Owner string `json:"owner"`
+}
+
+// SavedReplies returns the saved replies of the signed in user and of their organizations
+// to be inserted into a comment while composing it
+func SavedReplies(ctx *context.Context) {
+	replies, err := models.GetAvailableSavedReplies(ctx.User)
+	if err != nil {
+		ctx.ServerError("GetAvailableSavedReplies", err)
+		return
+	}
+
+	owners := map[int64]string{ctx.User.ID: ctx.User.Name}
+	result := make([]*savedReply, 0, len(replies))
+	for _, reply := range replies {
+		owner, ok := owners[reply.OwnerID]
+		if !ok {
+			u, err := models.GetUserByID(reply.OwnerID)
+			if err != nil {
+				ctx.ServerError("GetUserByID", err)
+				return
+			}
+			owner = u.Name
+			owners[reply.OwnerID] = owner
+		}
+		result = append(result, &savedReply{
+			SavedReply: convert.ToSavedReply(reply),
+			Owner:      owner,
+		})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
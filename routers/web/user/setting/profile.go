@@ -53,30 +53,25 @@ func HandleUsernameChange(ctx *context.Context, user *models.User, newName strin
 		return fmt.Errorf(ctx.Tr("form.username_change_not_local_user"))
 	}
 
-	// Check if user name has been changed
-	if user.LowerName != strings.ToLower(newName) {
-		if err := models.ChangeUserName(user, newName); err != nil {
-			switch {
-			case models.IsErrUserAlreadyExist(err):
-				ctx.Flash.Error(ctx.Tr("form.username_been_taken"))
-			case models.IsErrEmailAlreadyUsed(err):
-				ctx.Flash.Error(ctx.Tr("form.email_been_used"))
-			case models.IsErrNameReserved(err):
-				ctx.Flash.Error(ctx.Tr("user.form.name_reserved", newName))
-			case models.IsErrNamePatternNotAllowed(err):
-				ctx.Flash.Error(ctx.Tr("user.form.name_pattern_not_allowed", newName))
-			case models.IsErrNameCharsNotAllowed(err):
-				ctx.Flash.Error(ctx.Tr("user.form.name_chars_not_allowed", newName))
-			default:
-				ctx.ServerError("ChangeUserName", err)
-			}
-			return err
-		}
-	} else {
-		if err := models.UpdateRepositoryOwnerNames(user.ID, newName); err != nil {
-			ctx.ServerError("UpdateRepository", err)
-			return err
+	// A change of the case only is handled by ChangeUserName as well
+	if err := models.ChangeUserName(user, newName); err != nil {
+		switch {
+		case models.IsErrUserAlreadyExist(err):
+			ctx.Flash.Error(ctx.Tr("form.username_been_taken"))
+		case models.IsErrEmailAlreadyUsed(err):
+			ctx.Flash.Error(ctx.Tr("form.email_been_used"))
+		case models.IsErrNameReserved(err):
+			ctx.Flash.Error(ctx.Tr("user.form.name_reserved", newName))
+		case models.IsErrNamePatternNotAllowed(err):
+			ctx.Flash.Error(ctx.Tr("user.form.name_pattern_not_allowed", newName))
+		case models.IsErrNameCharsNotAllowed(err):
+			ctx.Flash.Error(ctx.Tr("user.form.name_chars_not_allowed", newName))
+		case models.IsErrNameTooLong(err):
+			ctx.Flash.Error(ctx.Tr("user.form.name_too_long", err.(models.ErrNameTooLong).MaxLength))
+		default:
+			ctx.ServerError("ChangeUserName", err)
 		}
+		return err
 	}
 
 	// update all agit flow pull request header
@@ -317,6 +317,7 @@ func RegisterRoutes(m *web.Route) {
 			m.Post("/email", bindIgnErr(forms.AddEmailForm{}), userSetting.EmailPost)
 			m.Post("/email/delete", userSetting.DeleteEmail)
 			m.Post("/delete", userSetting.DeleteAccount)
+			m.Post("/anonymize", userSetting.AnonymizeAccount)
 			m.Post("/theme", bindIgnErr(forms.UpdateThemeForm{}), userSetting.UpdateUIThemePost)
 		})
 		m.Group("/security", func() {
@@ -374,6 +375,7 @@ func RegisterRoutes(m *web.Route) {
 		m.Post("/forgot_password", user.ForgotPasswdPost)
 		m.Post("/logout", user.SignOut)
 		m.Get("/task/{task}", user.TaskStatus)
+		m.Get("/saved_replies", reqSignIn, user.SavedReplies)
 	})
 	// ***** END: User *****
 
@@ -385,6 +387,7 @@ func RegisterRoutes(m *web.Route) {
 	m.Group("/admin", func() {
 		m.Get("", adminReq, admin.Dashboard)
 		m.Post("", adminReq, bindIgnErr(forms.AdminDashboardForm{}), admin.DashboardPost)
+		m.Post("/stats/refresh", admin.RefreshStatistics)
 		m.Get("/config", admin.Config)
 		m.Post("/config/test_mail", admin.SendTestMail)
 		m.Group("/monitor", func() {
@@ -418,6 +421,7 @@ func RegisterRoutes(m *web.Route) {
 		m.Group("/repos", func() {
 			m.Get("", admin.Repos)
 			m.Combo("/unadopted").Get(admin.UnadoptedRepos).Post(admin.AdoptOrDeleteRepository)
+			m.Combo("/cleanup-tasks").Get(admin.RepoCleanupTasks).Post(admin.RepoCleanupTasksPost)
 			m.Post("/delete", admin.DeleteRepo)
 		})
 
@@ -594,26 +598,28 @@ func RegisterRoutes(m *web.Route) {
 		m.Group("/settings", func() {
 			m.Combo("").Get(repo.Settings).
 				Post(bindIgnErr(forms.RepoSettingForm{}), repo.SettingsPost)
-			m.Post("/avatar", bindIgnErr(forms.AvatarForm{}), repo.SettingsAvatar)
-			m.Post("/avatar/delete", repo.SettingsDeleteAvatar)
+			m.Post("/avatar", bindIgnErr(forms.AvatarForm{}), context.RepoMustNotBeArchived(), repo.SettingsAvatar)
+			m.Post("/avatar/delete", context.RepoMustNotBeArchived(), repo.SettingsDeleteAvatar)
 
 			m.Group("/collaboration", func() {
 				m.Combo("").Get(repo.Collaboration).Post(repo.CollaborationPost)
 				m.Post("/access_mode", repo.ChangeCollaborationAccessMode)
+				m.Post("/wiki_mode", repo.ChangeCollaborationWikiAccessMode)
 				m.Post("/delete", repo.DeleteCollaboration)
 				m.Group("/team", func() {
 					m.Post("", repo.AddTeamPost)
 					m.Post("/delete", repo.DeleteTeam)
 				})
-			})
+			}, context.RepoMustNotBeArchivedForWrite())
 
 			m.Group("/branches", func() {
-				m.Combo("").Get(repo.ProtectedBranch).Post(repo.ProtectedBranchPost)
+				m.Combo("").Get(repo.ProtectedBranch).Post(context.RepoMustNotBeArchived(), repo.ProtectedBranchPost)
 				m.Combo("/*").Get(repo.SettingsProtectedBranch).
 					Post(bindIgnErr(forms.ProtectBranchForm{}), context.RepoMustNotBeArchived(), repo.SettingsProtectedBranchPost)
 			}, repo.MustBeNotEmpty)
 			m.Post("/rename_branch", bindIgnErr(forms.RenameBranchForm{}), context.RepoMustNotBeArchived(), repo.RenameBranchPost)
 
+			m.Combo("/backups").Get(repo.Backups).Post(repo.BackupsPost)
 			m.Group("/tags", func() {
 				m.Get("", repo.Tags)
 				m.Post("", bindIgnErr(forms.ProtectTagForm{}), context.RepoMustNotBeArchived(), repo.NewProtectedTagPost)
@@ -626,7 +632,7 @@ func RegisterRoutes(m *web.Route) {
 				m.Get("", repo.GitHooks)
 				m.Combo("/{name}").Get(repo.GitHooksEdit).
 					Post(repo.GitHooksEditPost)
-			}, context.GitHookService())
+			}, context.GitHookService(), context.RepoMustNotBeArchivedForWrite())
 
 			m.Group("/hooks", func() {
 				m.Get("", repo.Webhooks)
@@ -654,13 +660,13 @@ func RegisterRoutes(m *web.Route) {
 				m.Post("/msteams/{id}", bindIgnErr(forms.NewMSTeamsHookForm{}), repo.MSTeamsHooksEditPost)
 				m.Post("/feishu/{id}", bindIgnErr(forms.NewFeishuHookForm{}), repo.FeishuHooksEditPost)
 				m.Post("/wechatwork/{id}", bindIgnErr(forms.NewWechatWorkHookForm{}), repo.WechatworkHooksEditPost)
-			}, webhooksEnabled)
+			}, webhooksEnabled, context.RepoMustNotBeArchivedForWrite())
 
 			m.Group("/keys", func() {
 				m.Combo("").Get(repo.DeployKeys).
 					Post(bindIgnErr(forms.AddKeyForm{}), repo.DeployKeysPost)
 				m.Post("/delete", repo.DeleteDeployKey)
-			})
+			}, context.RepoMustNotBeArchivedForWrite())
 
 			m.Group("/lfs", func() {
 				m.Get("/", repo.LFSFiles)
@@ -674,7 +680,7 @@ func RegisterRoutes(m *web.Route) {
 					m.Post("/", repo.LFSLockFile)
 					m.Post("/{lid}/unlock", repo.LFSUnlock)
 				})
-			})
+			}, context.RepoMustNotBeArchivedForWrite())
 
 		}, func(ctx *context.Context) {
 			ctx.Data["PageIsSettings"] = true
@@ -941,7 +947,9 @@ func RegisterRoutes(m *web.Route) {
 			m.Get(".patch", repo.DownloadPullPatch)
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Post("/merge", context.RepoMustNotBeArchived(), bindIgnErr(forms.MergePullRequestForm{}), repo.MergePullRequest)
-			m.Post("/update", repo.UpdatePullRequest)
+			m.Post("/enqueue", context.RepoMustNotBeArchived(), bindIgnErr(forms.MergePullRequestForm{}), repo.EnqueuePullRequest)
+			m.Post("/dequeue", context.RepoMustNotBeArchived(), repo.DequeuePullRequest)
+			m.Post("/update", context.RepoMustNotBeArchived(), repo.UpdatePullRequest)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
 			m.Group("/files", func() {
 				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
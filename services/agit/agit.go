@@ -39,7 +39,7 @@ func ProcRecive(ctx *context.PrivateContext, opts *private.HookOptions) []privat
 	_, forcePush = opts.GitPushOptions["force-push"]
 
 	for i := range opts.OldCommitIDs {
-		if opts.NewCommitIDs[i] == git.EmptySHA {
+		if git.IsEmptyCommitID(opts.NewCommitIDs[i]) {
 			results = append(results, private.HookProcReceiveRefResult{
 				OriginalRef: opts.RefFullNames[i],
 				OldOID:      opts.OldCommitIDs[i],
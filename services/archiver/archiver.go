@@ -33,6 +33,44 @@ type ArchiveRequest struct {
 	refName  string
 	Type     git.ArchiveType
 	CommitID string
+	// Path is the directory being archived, empty for the whole repository
+	Path string
+}
+
+// ErrInvalidArchivePath is returned for paths of directories to archive which are not clean
+type ErrInvalidArchivePath struct {
+	Path string
+}
+
+// IsErrInvalidArchivePath checks if an error is a ErrInvalidArchivePath.
+func IsErrInvalidArchivePath(err error) bool {
+	_, ok := err.(ErrInvalidArchivePath)
+	return ok
+}
+
+func (err ErrInvalidArchivePath) Error() string {
+	return fmt.Sprintf("invalid archive path: %s", err.Path)
+}
+
+// maxArchivePathLength is the size of the path column of the archivers
+const maxArchivePathLength = 255
+
+// cleanArchivePath returns the path of the directory to archive without leading and trailing slashes,
+// the paths with empty, "." or ".." segments are rejected rather than resolved
+func cleanArchivePath(treePath string) (string, error) {
+	cleaned := strings.Trim(treePath, "/")
+	if cleaned == "" {
+		return "", nil
+	}
+	if len(cleaned) > maxArchivePathLength || strings.ContainsAny(cleaned, "\\\x00") {
+		return "", ErrInvalidArchivePath{Path: treePath}
+	}
+	for _, segment := range strings.Split(cleaned, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrInvalidArchivePath{Path: treePath}
+		}
+	}
+	return cleaned, nil
 }
 
 // SHA1 hashes will only go up to 40 characters, but SHA256 hashes will go all
@@ -91,10 +129,45 @@ func NewRequest(repoID int64, repo *git.Repository, uri string) (*ArchiveRequest
 	return r, nil
 }
 
+// NewRequestForPath creates an archival request of a directory of the repository,
+// the whole repository is archived if the path is empty. A path which is not a
+// directory of the archived commit is a git.ErrNotExist.
+func NewRequestForPath(repoID int64, repo *git.Repository, uri, treePath string) (*ArchiveRequest, error) {
+	treePath, err := cleanArchivePath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewRequest(repoID, repo, uri)
+	if err != nil || treePath == "" {
+		return r, err
+	}
+	if r.Type == git.BUNDLE {
+		return nil, ErrInvalidArchivePath{Path: treePath}
+	}
+
+	commit, err := repo.GetCommit(r.CommitID)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := commit.GetTreeEntryByPath(treePath)
+	if err != nil {
+		return nil, err
+	}
+	if !entry.IsDir() {
+		return nil, git.ErrNotExist{ID: r.CommitID, RelPath: treePath}
+	}
+	r.Path = treePath
+	return r, nil
+}
+
 // GetArchiveName returns the name of the caller, based on the ref used by the
 // caller to create this request.
 func (aReq *ArchiveRequest) GetArchiveName() string {
-	return strings.ReplaceAll(aReq.refName, "/", "-") + "." + aReq.Type.String()
+	name := strings.ReplaceAll(aReq.refName, "/", "-")
+	if aReq.Path != "" {
+		name += "-" + strings.ReplaceAll(aReq.Path, "/", "-")
+	}
+	return name + "." + aReq.Type.String()
 }
 
 func doArchive(r *ArchiveRequest) (*models.RepoArchiver, error) {
@@ -104,7 +177,7 @@ func doArchive(r *ArchiveRequest) (*models.RepoArchiver, error) {
 	}
 	defer committer.Close()
 
-	archiver, err := models.GetRepoArchiver(ctx, r.RepoID, r.Type, r.CommitID)
+	archiver, err := models.GetRepoArchiver(ctx, r.RepoID, r.Type, r.CommitID, r.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +193,7 @@ func doArchive(r *ArchiveRequest) (*models.RepoArchiver, error) {
 			RepoID:   r.RepoID,
 			Type:     r.Type,
 			CommitID: r.CommitID,
+			Path:     r.Path,
 			Status:   models.RepoArchiverGenerating,
 		}
 		if err := models.AddRepoArchiver(ctx, archiver); err != nil {
@@ -178,12 +252,16 @@ func doArchive(r *ArchiveRequest) (*models.RepoArchiver, error) {
 				w,
 			)
 		} else {
+			treeish := archiver.CommitID
+			if archiver.Path != "" {
+				treeish += ":" + archiver.Path
+			}
 			err = gitRepo.CreateArchive(
 				graceful.GetManager().ShutdownContext(),
 				archiver.Type,
 				w,
 				setting.Repository.PrefixArchiveFiles,
-				archiver.CommitID,
+				treeish,
 			)
 		}
 		_ = w.CloseWithError(err)
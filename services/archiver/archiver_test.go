@@ -5,11 +5,17 @@
 package archiver
 
 import (
+	"errors"
+	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/test"
 
 	"github.com/stretchr/testify/assert"
@@ -132,3 +138,62 @@ func TestArchive_Basic(t *testing.T) {
 	assert.NotEqual(t, zipReq.GetArchiveName(), tgzReq.GetArchiveName())
 	assert.NotEqual(t, zipReq.GetArchiveName(), secondReq.GetArchiveName())
 }
+
+func TestArchive_Path(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	ctx := test.MockContext(t, "user27/repo49")
+	firstCommit, secondCommit := "51f84af23134", "aacbdfe9e1c4"
+
+	test.LoadRepo(t, ctx, 49)
+	test.LoadGitRepo(t, ctx)
+	defer ctx.Repo.GitRepo.Close()
+
+	req, err := NewRequestForPath(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "master.zip", "/test/")
+	assert.NoError(t, err)
+	assert.EqualValues(t, "test", req.Path)
+	assert.EqualValues(t, "master-test.zip", req.GetArchiveName())
+
+	req, err = NewRequestForPath(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "master.zip", "")
+	assert.NoError(t, err)
+	assert.Empty(t, req.Path)
+
+	// the directory does not exist in the first commit, and files are not directories
+	for uri, treePath := range map[string]string{
+		firstCommit + ".zip":  "test",
+		secondCommit + ".zip": "test/test.txt",
+		"master.tar.gz":       "missing",
+	} {
+		_, err = NewRequestForPath(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, uri, treePath)
+		assert.True(t, git.IsErrNotExist(err), treePath)
+	}
+
+	for _, treePath := range []string{"../user2", "test/../..", "test//test", "./test", "test\\..", strings.Repeat("a", 256)} {
+		_, err = NewRequestForPath(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "master.zip", treePath)
+		assert.True(t, IsErrInvalidArchivePath(err), treePath)
+	}
+	_, err = NewRequestForPath(ctx.Repo.Repository.ID, ctx.Repo.GitRepo, "master.bundle", "test")
+	assert.True(t, IsErrInvalidArchivePath(err))
+
+	// the archives of the repository and of its directory are cached apart
+	full, err := ArchiveRepository(&ArchiveRequest{RepoID: 49, Type: git.ZIP, CommitID: secondCommit})
+	assert.NoError(t, err)
+	dir, err := ArchiveRepository(&ArchiveRequest{RepoID: 49, Type: git.ZIP, CommitID: secondCommit, Path: "test"})
+	assert.NoError(t, err)
+	assert.NotEqual(t, full.ID, dir.ID)
+	fullPath, err := full.RelativePath()
+	assert.NoError(t, err)
+	dirPath, err := dir.RelativePath()
+	assert.NoError(t, err)
+	assert.NotEqual(t, fullPath, dirPath)
+
+	archiver, err := models.GetRepoArchiver(db.DefaultContext, 49, git.ZIP, secondCommit, "test")
+	assert.NoError(t, err)
+	assert.EqualValues(t, dir.ID, archiver.ID)
+
+	// the archives of directories are cleaned up like the others
+	assert.NoError(t, models.DeleteOldRepositoryArchives(db.DefaultContext, -time.Hour))
+	db.AssertNotExistsBean(t, &models.RepoArchiver{ID: dir.ID})
+	_, err = storage.RepoArchives.Stat(dirPath)
+	assert.True(t, errors.Is(err, os.ErrNotExist))
+}
@@ -7,6 +7,7 @@ package auth
 
 import (
 	"fmt"
+	"net"
 	"net/http"
 	"reflect"
 	"regexp"
@@ -91,6 +92,7 @@ func isAttachmentDownload(req *http.Request) bool {
 }
 
 var gitRawReleasePathRe = regexp.MustCompile(`^/[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+/(?:(?:git-(?:(?:upload)|(?:receive))-pack$)|(?:info/refs$)|(?:HEAD$)|(?:objects/)|(?:raw/)|(?:releases/download/))`)
+var gitPathRe = regexp.MustCompile(`^/[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+/(?:(?:git-(?:(?:upload)|(?:receive))-pack$)|(?:info/refs$)|(?:HEAD$)|(?:objects/))`)
 var lfsPathRe = regexp.MustCompile(`^/[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+/info/lfs/`)
 
 func isGitRawReleaseOrLFSPath(req *http.Request) bool {
@@ -103,6 +105,22 @@ func isGitRawReleaseOrLFSPath(req *http.Request) bool {
 	return false
 }
 
+// isGitPath checks if the request is a git clone, fetch or push over HTTP(S)
+func isGitPath(req *http.Request) bool {
+	return gitPathRe.MatchString(req.URL.Path)
+}
+
+// updateAccessTokenLastUsed records that the access token has been used by the request
+func updateAccessTokenLastUsed(req *http.Request, token *models.AccessToken) {
+	ip, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		ip = req.RemoteAddr
+	}
+	if err := models.UpdateAccessTokenLastUsed(token, ip); err != nil {
+		log.Error("UpdateAccessTokenLastUsed: %v", err)
+	}
+}
+
 // handleSignIn clears existing session variables and stores new ones for the specified user object
 func handleSignIn(resp http.ResponseWriter, req *http.Request, sess SessionStore, user *models.User) {
 	_ = sess.Delete("openid_verified_uri")
@@ -13,7 +13,6 @@ import (
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web/middleware"
 )
 
@@ -68,6 +67,30 @@ func (b *Basic) Verify(req *http.Request, w http.ResponseWriter, store DataStore
 		log.Trace("Basic Authorization: Attempting login with username as token")
 	}
 
+	// clone tokens are only valid to clone and push the repository they were created for
+	if models.IsRepoCloneToken(authToken) {
+		if !isGitPath(req) {
+			return nil
+		}
+		token, err := models.GetRepoCloneToken(authToken)
+		if err != nil {
+			if !models.IsErrRepoCloneTokenNotExist(err) {
+				log.Error("GetRepoCloneToken: %v", err)
+			}
+			return nil
+		}
+		log.Trace("Basic Authorization: Valid RepoCloneToken for user[%d]", token.UID)
+		u, err := models.GetUserByID(token.UID)
+		if err != nil {
+			log.Error("GetUserByID:  %v", err)
+			return nil
+		}
+
+		store.GetData()["IsApiToken"] = true
+		store.GetData()["RepoCloneToken"] = token
+		return u
+	}
+
 	uid := CheckOAuthAccessToken(authToken)
 	if uid != 0 {
 		log.Trace("Basic Authorization: Valid OAuthAccessToken for user[%d]", uid)
@@ -91,12 +114,10 @@ func (b *Basic) Verify(req *http.Request, w http.ResponseWriter, store DataStore
 			return nil
 		}
 
-		token.UpdatedUnix = timeutil.TimeStampNow()
-		if err = models.UpdateAccessToken(token); err != nil {
-			log.Error("UpdateAccessToken:  %v", err)
-		}
+		updateAccessTokenLastUsed(req, token)
 
 		store.GetData()["IsApiToken"] = true
+		store.GetData()["ApiTokenID"] = token.ID
 		return u
 	} else if !models.IsErrAccessTokenNotExist(err) && !models.IsErrAccessTokenEmpty(err) {
 		log.Error("GetAccessTokenBySha: %v", err)
@@ -5,28 +5,29 @@
 package auth
 
 import (
+	"fmt"
+
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/login"
 )
 
 // DeleteLoginSource deletes a LoginSource record in DB.
+// It returns ErrSourceInUse if the source still authenticates users or links external accounts.
 func DeleteLoginSource(source *login.Source) error {
-	count, err := db.GetEngine(db.DefaultContext).Count(&models.User{LoginSource: source.ID})
+	users, err := db.GetEngine(db.DefaultContext).Count(&models.User{LoginSource: source.ID})
 	if err != nil {
 		return err
-	} else if count > 0 {
-		return login.ErrSourceInUse{
-			ID: source.ID,
-		}
 	}
-
-	count, err = db.GetEngine(db.DefaultContext).Count(&models.ExternalLoginUser{LoginSourceID: source.ID})
+	externalLogins, err := db.GetEngine(db.DefaultContext).Count(&models.ExternalLoginUser{LoginSourceID: source.ID})
 	if err != nil {
 		return err
-	} else if count > 0 {
+	}
+	if users > 0 || externalLogins > 0 {
 		return login.ErrSourceInUse{
-			ID: source.ID,
+			ID:             source.ID,
+			Users:          users,
+			ExternalLogins: externalLogins,
 		}
 	}
 
@@ -39,3 +40,37 @@ func DeleteLoginSource(source *login.Source) error {
 	_, err = db.GetEngine(db.DefaultContext).ID(source.ID).Delete(new(login.Source))
 	return err
 }
+
+// ForceDeleteLoginSource deletes a LoginSource record in DB after moving its users to the target source.
+// The external accounts linked through the source are unlinked, they can't be used with another source.
+func ForceDeleteLoginSource(source, target *login.Source) error {
+	if source.ID == target.ID {
+		return fmt.Errorf("login source %d can't be migrated to itself", source.ID)
+	}
+
+	sess := db.NewSession(db.DefaultContext)
+	defer sess.Close()
+	if err := sess.Begin(); err != nil {
+		return err
+	}
+
+	if _, err := sess.Where("login_source = ?", source.ID).
+		Cols("login_type", "login_source").
+		Update(&models.User{LoginType: target.Type, LoginSource: target.ID}); err != nil {
+		return fmt.Errorf("migrate users: %v", err)
+	}
+	if _, err := sess.Delete(&models.ExternalLoginUser{LoginSourceID: source.ID}); err != nil {
+		return fmt.Errorf("unlink external accounts: %v", err)
+	}
+	if _, err := sess.ID(source.ID).Delete(new(login.Source)); err != nil {
+		return err
+	}
+	if err := sess.Commit(); err != nil {
+		return err
+	}
+
+	if registerableSource, ok := source.Cfg.(login.RegisterableSource); ok {
+		return registerableSource.UnregisterSource()
+	}
+	return nil
+}
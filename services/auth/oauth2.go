@@ -14,7 +14,6 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/login"
 	"code.gitea.io/gitea/modules/log"
-	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/services/auth/source/oauth2"
 )
@@ -92,20 +91,42 @@ func (o *OAuth2) userIDFromToken(req *http.Request, store DataStore) int64 {
 		return uid
 	}
 	t, err := models.GetAccessTokenBySHA(tokenSHA)
-	if err != nil {
-		if !models.IsErrAccessTokenNotExist(err) && !models.IsErrAccessTokenEmpty(err) {
+	if models.IsErrAccessTokenNotExist(err) {
+		return o.userIDFromDeployKeyToken(req, tokenSHA, store)
+	} else if err != nil {
+		if !models.IsErrAccessTokenEmpty(err) {
 			log.Error("GetAccessTokenBySHA: %v", err)
 		}
 		return 0
 	}
-	t.UpdatedUnix = timeutil.TimeStampNow()
-	if err = models.UpdateAccessToken(t); err != nil {
-		log.Error("UpdateAccessToken: %v", err)
-	}
+	updateAccessTokenLastUsed(req, t)
 	store.GetData()["IsApiToken"] = true
+	store.GetData()["ApiTokenID"] = t.ID
 	return t.UID
 }
 
+// userIDFromDeployKeyToken returns the ID of the fake user acting for the deploy key
+// which may post commit statuses with the token, the key is put in the data store.
+func (o *OAuth2) userIDFromDeployKeyToken(req *http.Request, tokenSHA string, store DataStore) int64 {
+	// the keys may only be used with the API
+	if !middleware.IsAPIPath(req) {
+		return 0
+	}
+	key, err := models.GetDeployKeyByStatusesToken(tokenSHA)
+	if err != nil {
+		if !models.IsErrDeployKeyNotExist(err) {
+			log.Error("GetDeployKeyByStatusesToken: %v", err)
+		}
+		return 0
+	}
+	if err := models.UpdateDeployKeyCols(key, "updated_unix"); err != nil {
+		log.Error("UpdateDeployKeyCols: %v", err)
+	}
+	store.GetData()["IsApiToken"] = true
+	store.GetData()["DeployKey"] = key
+	return models.DeployKeyUserID
+}
+
 // Verify extracts the user ID from the OAuth token in the query parameters
 // or the "Authorization" header and returns the corresponding user object for that ID.
 // If verification is successful returns an existing user object.
@@ -120,6 +141,10 @@ func (o *OAuth2) Verify(req *http.Request, w http.ResponseWriter, store DataStor
 	}
 
 	id := o.userIDFromToken(req, store)
+	if key, ok := store.GetData()["DeployKey"].(*models.DeployKey); ok && id == models.DeployKeyUserID {
+		log.Trace("OAuth2 Authorization: Found token for deploy key[%d]", key.ID)
+		return models.NewDeployKeyUser(key.Name)
+	}
 	if id <= 0 {
 		return nil
 	}
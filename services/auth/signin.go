@@ -72,7 +72,8 @@ func UserSignIn(username, password string) (*models.User, *login.Source, error)
 
 		// WARN: DON'T check user.IsActive, that will be checked on reqSign so that
 		// user could be hint to resend confirm email.
-		if user.ProhibitLogin {
+		// anonymized users can never sign in again
+		if user.ProhibitLogin || user.IsAnonymized() {
 			return nil, nil, models.ErrUserProhibitLogin{UID: user.ID, Name: user.Name}
 		}
 
@@ -55,6 +55,8 @@ type Source struct {
 	GroupMemberUID        string // Group Attribute containing array of UserUID
 	UserUID               string // User Attribute listed in Group
 	SkipLocalTwoFA        bool   `json:",omitempty"` // Skip Local 2fa for users authenticated with this source
+	GroupTeamMap          string `json:",omitempty"` // JSON map of group DNs to organization teams, synchronized with the users
+	GroupTeamMapRemoval   bool   `json:",omitempty"` // Remove users from the mapped teams when they are not in the groups anymore
 
 	// reference to the loginSource
 	loginSource *login.Source
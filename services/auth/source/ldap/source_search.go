@@ -28,6 +28,7 @@ type SearchResult struct {
 	IsRestricted bool     // if user is restricted
 	LowerName    string   // Lowername
 	Avatar       []byte
+	MemberUID    string // value listing the user in the member attribute of groups
 }
 
 func (ls *Source) sanitizedUserQuery(username string) (string, bool) {
@@ -417,6 +418,8 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 	isAttributeSSHPublicKeySet := len(strings.TrimSpace(ls.AttributeSSHPublicKey)) > 0
 	isAtributeAvatarSet := len(strings.TrimSpace(ls.AttributeAvatar)) > 0
 
+	isUserUIDSet := len(ls.GroupTeamMap) > 0 && len(strings.TrimSpace(ls.UserUID)) > 0 && !strings.EqualFold(ls.UserUID, "dn")
+
 	attribs := []string{ls.AttributeUsername, ls.AttributeName, ls.AttributeSurname, ls.AttributeMail}
 	if isAttributeSSHPublicKeySet {
 		attribs = append(attribs, ls.AttributeSSHPublicKey)
@@ -424,6 +427,9 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 	if isAtributeAvatarSet {
 		attribs = append(attribs, ls.AttributeAvatar)
 	}
+	if isUserUIDSet {
+		attribs = append(attribs, ls.UserUID)
+	}
 
 	log.Trace("Fetching attributes '%v', '%v', '%v', '%v', '%v', '%v' with filter %s and base %s", ls.AttributeUsername, ls.AttributeName, ls.AttributeSurname, ls.AttributeMail, ls.AttributeSSHPublicKey, ls.AttributeAvatar, userFilter, ls.UserBase)
 	search := ldap.NewSearchRequest(
@@ -460,8 +466,51 @@ func (ls *Source) SearchEntries() ([]*SearchResult, error) {
 		if isAtributeAvatarSet {
 			result[i].Avatar = v.GetRawAttributeValue(ls.AttributeAvatar)
 		}
+		if isUserUIDSet {
+			result[i].MemberUID = v.GetAttributeValue(ls.UserUID)
+		} else {
+			result[i].MemberUID = v.DN
+		}
 		result[i].LowerName = strings.ToLower(result[i].Username)
 	}
 
 	return result, nil
 }
+
+// SearchGroupMembers looks up the members of the given groups. Groups which cannot be found
+// are left out of the result, so that their members are not taken as having been removed.
+func (ls *Source) SearchGroupMembers(groupDNs []string) (map[string][]string, error) {
+	l, err := dial(ls)
+	if err != nil {
+		log.Error("LDAP Connect error, %s:%v", ls.Host, err)
+		return nil, err
+	}
+	defer l.Close()
+
+	if ls.BindDN != "" && ls.BindPassword != "" {
+		err := l.Bind(ls.BindDN, ls.BindPassword)
+		if err != nil {
+			log.Debug("Failed to bind as BindDN[%s]: %v", ls.BindDN, err)
+			return nil, err
+		}
+		log.Trace("Bound as BindDN %s", ls.BindDN)
+	}
+
+	members := make(map[string][]string, len(groupDNs))
+	for _, groupDN := range groupDNs {
+		log.Trace("Fetching members '%v' of group %s", ls.GroupMemberUID, groupDN)
+		search := ldap.NewSearchRequest(
+			groupDN, ldap.ScopeBaseObject, ldap.NeverDerefAliases, 0, 0, false, "(objectClass=*)",
+			[]string{ls.GroupMemberUID}, nil)
+		sr, err := l.Search(search)
+		if err != nil {
+			log.Warn("LDAP search for group %s failed: %v", groupDN, err)
+			continue
+		} else if len(sr.Entries) < 1 {
+			log.Warn("LDAP search for group %s found no entries", groupDN)
+			continue
+		}
+		members[groupDN] = sr.Entries[0].GetAttributeValues(ls.GroupMemberUID)
+	}
+	return members, nil
+}
@@ -58,6 +58,7 @@ func (source *Source) Sync(ctx context.Context, updateExisting bool) error {
 	})
 
 	userPos := 0
+	usersByMemberUID := make(map[string]*models.User, len(sr))
 
 	for _, su := range sr {
 		select {
@@ -164,6 +165,10 @@ func (source *Source) Sync(ctx context.Context, updateExisting bool) error {
 
 			}
 		}
+
+		if usr.ID > 0 && len(su.MemberUID) > 0 {
+			usersByMemberUID[strings.ToLower(su.MemberUID)] = usr
+		}
 	}
 
 	// Rewrite authorized_keys file if LDAP Public SSH Key attribute is set and any key was added or removed
@@ -199,5 +204,15 @@ func (source *Source) Sync(ctx context.Context, updateExisting bool) error {
 			}
 		}
 	}
+
+	if len(source.GroupTeamMap) > 0 {
+		select {
+		case <-ctx.Done():
+			log.Warn("SyncExternalUsers: Cancelled during update of %s before team synchronization", source.loginSource.Name)
+			return models.ErrCancelledf("During update of %s before team synchronization", source.loginSource.Name)
+		default:
+		}
+		source.syncGroupTeams(usersByMemberUID)
+	}
 	return nil
 }
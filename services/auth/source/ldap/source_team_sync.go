@@ -0,0 +1,173 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package ldap
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// GroupTeamMapping maps group DNs to the teams of organizations: group DN -> organization name -> team names
+type GroupTeamMapping map[string]map[string][]string
+
+// ParseGroupTeamMap parses the JSON group to team mapping of a source
+func ParseGroupTeamMap(groupTeamMap string) (GroupTeamMapping, error) {
+	mapping := make(GroupTeamMapping)
+	if len(strings.TrimSpace(groupTeamMap)) == 0 {
+		return mapping, nil
+	}
+	if err := json.Unmarshal([]byte(groupTeamMap), &mapping); err != nil {
+		return nil, fmt.Errorf("invalid group team map: %v", err)
+	}
+	return mapping, nil
+}
+
+// teamMembers collects the users which should be members of a mapped team
+type teamMembers struct {
+	userIDs map[int64]bool
+	// whether all groups mapped to the team could be looked up, members are only removed if so
+	complete bool
+}
+
+// syncGroupTeams reconciles the members of the mapped teams with the members of the groups.
+// usersByMemberUID contains the users of this source by the (lower cased) value listing them in groups.
+func (source *Source) syncGroupTeams(usersByMemberUID map[string]*models.User) {
+	mapping, err := ParseGroupTeamMap(source.GroupTeamMap)
+	if err != nil {
+		log.Error("SyncExternalUsers[%s]: %v", source.loginSource.Name, err)
+		return
+	}
+	if len(mapping) == 0 {
+		return
+	}
+
+	groupDNs := make([]string, 0, len(mapping))
+	for groupDN := range mapping {
+		groupDNs = append(groupDNs, groupDN)
+	}
+	groupMembers, err := source.SearchGroupMembers(groupDNs)
+	if err != nil {
+		log.Error("SyncExternalUsers[%s]: LDAP group search failure, team synchronization skipped: %v", source.loginSource.Name, err)
+		return
+	}
+
+	// organization name -> team name -> members
+	desired := make(map[string]map[string]*teamMembers)
+	for groupDN, orgs := range mapping {
+		members, found := groupMembers[groupDN]
+		for orgName, teamNames := range orgs {
+			if desired[orgName] == nil {
+				desired[orgName] = make(map[string]*teamMembers)
+			}
+			for _, teamName := range teamNames {
+				team := desired[orgName][teamName]
+				if team == nil {
+					team = &teamMembers{userIDs: make(map[int64]bool), complete: true}
+					desired[orgName][teamName] = team
+				}
+				if !found {
+					team.complete = false
+					continue
+				}
+				for _, member := range members {
+					if u, ok := usersByMemberUID[strings.ToLower(member)]; ok {
+						team.userIDs[u.ID] = true
+					}
+				}
+			}
+		}
+	}
+
+	orgNames := make([]string, 0, len(desired))
+	for orgName := range desired {
+		orgNames = append(orgNames, orgName)
+	}
+	sort.Strings(orgNames)
+
+	for _, orgName := range orgNames {
+		org, err := models.GetOrgByName(orgName)
+		if err != nil {
+			if models.IsErrOrgNotExist(err) {
+				source.noticeGroupTeamMap("organization %s does not exist", orgName)
+			} else {
+				log.Error("SyncExternalUsers[%s]: GetOrgByName[%s]: %v", source.loginSource.Name, orgName, err)
+			}
+			continue
+		}
+
+		teamNames := make([]string, 0, len(desired[orgName]))
+		for teamName := range desired[orgName] {
+			teamNames = append(teamNames, teamName)
+		}
+		sort.Strings(teamNames)
+
+		for _, teamName := range teamNames {
+			team, err := models.GetTeam(org.ID, teamName)
+			if err != nil {
+				if models.IsErrTeamNotExist(err) {
+					source.noticeGroupTeamMap("team %s of organization %s does not exist", teamName, orgName)
+				} else {
+					log.Error("SyncExternalUsers[%s]: GetTeam[%s/%s]: %v", source.loginSource.Name, orgName, teamName, err)
+				}
+				continue
+			}
+			source.syncTeamMembers(team, desired[orgName][teamName])
+		}
+	}
+}
+
+// syncTeamMembers adds the missing members to the team and, if enabled, removes the
+// members of this source which should not be in the team anymore
+func (source *Source) syncTeamMembers(team *models.Team, members *teamMembers) {
+	if err := team.GetMembers(&models.SearchMembersOptions{}); err != nil {
+		log.Error("SyncExternalUsers[%s]: GetMembers[%d]: %v", source.loginSource.Name, team.ID, err)
+		return
+	}
+
+	current := make(map[int64]bool, len(team.Members))
+	toRemove := make([]int64, 0)
+	for _, u := range team.Members {
+		current[u.ID] = true
+		if !members.userIDs[u.ID] && u.LoginSource == source.loginSource.ID {
+			toRemove = append(toRemove, u.ID)
+		}
+	}
+
+	toAdd := make([]int64, 0, len(members.userIDs))
+	for userID := range members.userIDs {
+		if !current[userID] {
+			toAdd = append(toAdd, userID)
+		}
+	}
+	sort.Slice(toAdd, func(i, j int) bool { return toAdd[i] < toAdd[j] })
+
+	if len(toAdd) > 0 {
+		log.Trace("SyncExternalUsers[%s]: Adding %d users to team %s", source.loginSource.Name, len(toAdd), team.Name)
+		if err := models.AddTeamMembers(team, toAdd); err != nil {
+			log.Error("SyncExternalUsers[%s]: AddTeamMembers[%d]: %v", source.loginSource.Name, team.ID, err)
+		}
+	}
+
+	if !source.GroupTeamMapRemoval || !members.complete || len(toRemove) == 0 {
+		return
+	}
+	log.Trace("SyncExternalUsers[%s]: Removing %d users from team %s", source.loginSource.Name, len(toRemove), team.Name)
+	if err := models.RemoveTeamMembers(team, toRemove); err != nil {
+		log.Error("SyncExternalUsers[%s]: RemoveTeamMembers[%d]: %v", source.loginSource.Name, team.ID, err)
+	}
+}
+
+func (source *Source) noticeGroupTeamMap(format string, args ...interface{}) {
+	desc := fmt.Sprintf("LDAP source %s: group team map: ", source.loginSource.Name) + fmt.Sprintf(format, args...)
+	log.Warn("SyncExternalUsers: %s", desc)
+	if err := models.CreateNotice(models.NoticeTask, desc); err != nil {
+		log.Error("CreateNotice: %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/timeutil"
 )
@@ -30,6 +31,10 @@ func CreateIssueComment(doer *models.User, repo *models.Repository, issue *model
 		return nil, err
 	}
 
+	if err := models.WatchIfAuto(doer.ID, repo.ID, false); err != nil {
+		log.Warn("Fail to perform auto watch on user %v for repo %v: %v", doer.ID, repo.ID, err)
+	}
+
 	mentions, err := issue.FindAndUpdateIssueMentions(db.DefaultContext, doer, comment.Content)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,128 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package digest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// orgRepoBatchSize is the number of repositories of an organization loaded at once to send their digests
+const orgRepoBatchSize = 50
+
+// SendDigests sends the digests of the repository and organization webhooks subscribed to the digest event
+// whose period has passed since their last digest, or since their creation if they never sent one.
+// An organization webhook gets the digest of every repository of the organization.
+func SendDigests(ctx context.Context) error {
+	hooks, err := models.GetDigestWebhooks()
+	if err != nil {
+		return fmt.Errorf("GetDigestWebhooks: %v", err)
+	}
+
+	now := time.Now()
+	for _, w := range hooks {
+		last := w.LastDigestUnix
+		if last == 0 {
+			last = w.CreatedUnix
+		}
+		if last.AsTime().Add(w.DigestWindow()).After(now) {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before sending the digest of webhook %d", w.ID)
+		default:
+		}
+
+		// The digest is recorded first, so a failing webhook isn't retried every time the digests are sent
+		w.LastDigestUnix = timeutil.TimeStamp(now.Unix())
+		if err := models.UpdateWebhookLastDigest(w); err != nil {
+			return fmt.Errorf("UpdateWebhookLastDigest: %v", err)
+		}
+		if err := sendDigest(ctx, w, now.Add(-w.DigestWindow()), now); err != nil {
+			log.Error("Unable to send the digest of webhook %d: %v", w.ID, err)
+		}
+	}
+	return nil
+}
+
+// sendDigest queues the digests of the webhook for the period from since until before until
+func sendDigest(ctx context.Context, w *models.Webhook, since, until time.Time) error {
+	if w.RepoID > 0 {
+		repo, err := models.GetRepositoryByID(w.RepoID)
+		if err != nil {
+			return fmt.Errorf("GetRepositoryByID: %v", err)
+		}
+		return sendRepoDigest(w, repo, since, until)
+	}
+
+	org, err := models.GetUserByID(w.OrgID)
+	if err != nil {
+		return fmt.Errorf("GetUserByID: %v", err)
+	}
+	for page := 1; ; page++ {
+		repos, _, err := models.GetUserRepositories(&models.SearchRepoOptions{
+			ListOptions: db.ListOptions{Page: page, PageSize: orgRepoBatchSize},
+			Actor:       org,
+			Private:     true,
+		})
+		if err != nil {
+			return fmt.Errorf("GetUserRepositories: %v", err)
+		}
+		for _, repo := range repos {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before sending the digest of %s", repo.FullName())
+			default:
+			}
+			if err := sendRepoDigest(w, repo, since, until); err != nil {
+				return err
+			}
+		}
+		if len(repos) < orgRepoBatchSize {
+			return nil
+		}
+	}
+}
+
+// sendRepoDigest queues the digest of a repository, unless it had no activity and the webhook skips empty digests
+func sendRepoDigest(w *models.Webhook, repo *models.Repository, since, until time.Time) error {
+	digest, err := models.GetActivityDigest(repo.ID, since, until)
+	if err != nil {
+		return fmt.Errorf("GetActivityDigest %s: %v", repo.FullName(), err)
+	}
+	if digest.IsEmpty() && !w.DigestSendEmpty {
+		log.Trace("Skipping the empty digest of %s for webhook %d", repo.FullName(), w.ID)
+		return nil
+	}
+
+	payload := &api.DigestPayload{
+		Since:              since,
+		Until:              until,
+		IssuesOpened:       digest.IssuesOpened,
+		IssuesClosed:       digest.IssuesClosed,
+		PullRequestsOpened: digest.PullRequestsOpened,
+		PullRequestsMerged: digest.PullRequestsMerged,
+		Contributors:       make([]*api.DigestContributor, 0, len(digest.Contributors)),
+		Repository:         convert.ToRepo(repo, models.AccessModeOwner),
+	}
+	for _, contributor := range digest.Contributors {
+		payload.Contributors = append(payload.Contributors, &api.DigestContributor{
+			User:    convert.ToUser(contributor.User, nil),
+			Actions: contributor.Actions,
+		})
+	}
+	return webhook_service.PrepareWebhook(w, repo, models.HookEventDigest, payload)
+}
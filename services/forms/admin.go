@@ -46,6 +46,7 @@ type AdminEditUserForm struct {
 	Active                  bool
 	Admin                   bool
 	Restricted              bool
+	ServiceAccount          bool
 	AllowGitHook            bool
 	AllowImportLocal        bool
 	AllowCreateOrganization bool
@@ -40,6 +40,8 @@ type AuthenticationForm struct {
 	GroupFilter                   string
 	GroupMemberUID                string
 	UserUID                       string
+	GroupTeamMap                  string
+	GroupTeamMapRemoval           bool
 	RestrictedFilter              string
 	AllowDeactivateAll            bool
 	IsActive                      bool
@@ -140,6 +140,7 @@ type RepoSettingForm struct {
 	ExternalTrackerURL                    string
 	TrackerURLFormat                      string
 	TrackerIssueStyle                     string
+	ExternalTrackerRegexpPattern          string
 	EnableCloseIssuesViaCommitInAnyBranch bool
 	EnableProjects                        bool
 	EnablePulls                           bool
@@ -152,9 +153,16 @@ type RepoSettingForm struct {
 	PullsDefaultMergeStyle                string
 	EnableAutodetectManualMerge           bool
 	DefaultDeleteBranchAfterMerge         bool
+	EnableMergeQueue                      bool
+	PullsMergeCommitAuthorStyle           string
+	PullsAddReviewedByTrailers            bool
+	PullsAddCoAuthorTrailers              bool
 	EnableTimetracker                     bool
 	AllowOnlyContributorsToTrackTime      bool
 	EnableIssueDependencies               bool
+	DefaultIssueSortType                  string
+	IssueCloseKeywords                    string
+	IssueReopenKeywords                   string
 	IsArchived                            bool
 
 	// Signing Settings
@@ -196,7 +204,10 @@ type ProtectBranchForm struct {
 	BlockOnRejectedReviews        bool
 	BlockOnOfficialReviewRequests bool
 	BlockOnOutdatedBranch         bool
+	AutoUpdateOnMerge             bool
 	DismissStaleApprovals         bool
+	DismissOnlyOnContentChange    bool
+	RequireCodeOwnerApproval      bool
 	RequireSignedCommits          bool
 	ProtectedFilePatterns         string
 	UnprotectedFilePatterns       string
@@ -236,8 +247,13 @@ type WebhookForm struct {
 	PullRequestReview    bool
 	PullRequestSync      bool
 	Repository           bool
+	Digest               bool
+	DeployKey            bool
+	PublicKey            bool
+	DigestWindowDays     int `binding:"Range(0,365)"`
+	DigestSendEmpty      bool
 	Active               bool
-	BranchFilter         string `binding:"GlobPattern"`
+	BranchFilter         string `binding:"GlobPatternList"`
 }
 
 // PushOnly if the hook will be triggered when push
@@ -257,10 +273,11 @@ func (f WebhookForm) ChooseEvents() bool {
 
 // NewWebhookForm form for creating web hook
 type NewWebhookForm struct {
-	PayloadURL  string `binding:"Required;ValidUrl"`
-	HTTPMethod  string `binding:"Required;In(POST,GET)"`
-	ContentType int    `binding:"Required"`
-	Secret      string
+	PayloadURL         string `binding:"Required;ValidUrl"`
+	HTTPMethod         string `binding:"Required;In(POST,GET)"`
+	ContentType        int    `binding:"Required"`
+	Secret             string
+	SignatureAlgorithm string
 	WebhookForm
 }
 
@@ -272,9 +289,10 @@ func (f *NewWebhookForm) Validate(req *http.Request, errs binding.Errors) bindin
 
 // NewGogshookForm form for creating gogs hook
 type NewGogshookForm struct {
-	PayloadURL  string `binding:"Required;ValidUrl"`
-	ContentType int    `binding:"Required"`
-	Secret      string
+	PayloadURL         string `binding:"Required;ValidUrl"`
+	ContentType        int    `binding:"Required"`
+	Secret             string
+	SignatureAlgorithm string
 	WebhookForm
 }
 
@@ -413,6 +431,7 @@ type CreateIssueForm struct {
 	AssigneeID  int64
 	Content     string
 	Files       []string
+	Template    string `form:"template"`
 }
 
 // Validate validates the fields
@@ -340,6 +340,8 @@ type AddKeyForm struct {
 	Signature  string `binding:"OmitEmpty"`
 	KeyID      string `binding:"OmitEmpty"`
 	IsWritable bool
+	// CanWriteStatuses only applies to deploy keys
+	CanWriteStatuses bool
 }
 
 // Validate validates the fields
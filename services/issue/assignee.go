@@ -241,7 +241,7 @@ func TeamReviewRequest(issue *models.Issue, doer *models.User, reviewer *models.
 		return
 	}
 
-	// notify all user in this team
+	// notify the team according to its review notification preference
 	if err = comment.LoadIssue(); err != nil {
 		return
 	}
@@ -250,13 +250,7 @@ func TeamReviewRequest(issue *models.Issue, doer *models.User, reviewer *models.
 		return
 	}
 
-	for _, member := range reviewer.Members {
-		if member.ID == comment.Issue.PosterID {
-			continue
-		}
-		comment.AssigneeID = member.ID
-		notification.NotifyPullReviewRequest(doer, issue, member, isAdd, comment)
-	}
+	notification.NotifyPullTeamReviewRequest(doer, issue, reviewer, isAdd, comment)
 
 	return
 }
@@ -6,6 +6,7 @@ package issue
 
 import (
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/notification"
 )
 
@@ -79,3 +80,54 @@ func ReplaceLabels(issue *models.Issue, doer *models.User, labels []*models.Labe
 	notification.NotifyIssueChangeLabels(doer, issue, labels, old)
 	return nil
 }
+
+// CopyLabels creates the labels of the source repository in the target repository, except the ones whose names
+// are already used by a label of the target repository or of its organization. The labels of the organization
+// of the source repository are not copied, they are inherited instead by the repositories of that organization.
+// The created labels and the skipped labels of the source repository are returned.
+func CopyLabels(source, target *models.Repository) (created, skipped []*models.Label, err error) {
+	labels, err := models.GetLabelsByRepoID(source.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	existing, err := models.GetLabelsByRepoID(target.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := target.GetOwner(); err != nil {
+		return nil, nil, err
+	}
+	if target.Owner.IsOrganization() {
+		orgLabels, err := models.GetLabelsByOrgID(target.OwnerID, "", db.ListOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		existing = append(existing, orgLabels...)
+	}
+	names := make(map[string]bool, len(existing))
+	for _, label := range existing {
+		names[label.Name] = true
+	}
+
+	for _, label := range labels {
+		if names[label.Name] {
+			skipped = append(skipped, label)
+			continue
+		}
+		names[label.Name] = true
+		created = append(created, &models.Label{
+			RepoID:      target.ID,
+			Name:        label.Name,
+			Color:       label.Color,
+			Description: label.Description,
+			Exclusive:   label.Exclusive,
+		})
+	}
+	if len(created) > 0 {
+		if err := models.NewLabels(created...); err != nil {
+			return nil, nil, err
+		}
+	}
+	return created, skipped, nil
+}
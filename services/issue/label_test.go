@@ -58,3 +58,32 @@ func TestIssue_AddLabel(t *testing.T) {
 		db.AssertExistsAndLoadBean(t, &models.IssueLabel{IssueID: test.issueID, LabelID: test.labelID})
 	}
 }
+
+func TestCopyLabels(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	source := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	// repository 3 belongs to organization 3 which has the labels orglabel3 and orglabel4
+	target := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	assert.NoError(t, models.NewLabels(
+		&models.Label{RepoID: source.ID, Name: "orglabel3", Color: "#123456"},
+		&models.Label{RepoID: target.ID, Name: "label2", Color: "#ffffff"},
+	))
+
+	created, skipped, err := CopyLabels(source, target)
+	assert.NoError(t, err)
+	if assert.Len(t, created, 1) {
+		assert.Equal(t, "label1", created[0].Name)
+		assert.Equal(t, "#abcdef", created[0].Color)
+		db.AssertExistsAndLoadBean(t, &models.Label{ID: created[0].ID, RepoID: target.ID, Name: "label1"})
+	}
+	if assert.Len(t, skipped, 2) {
+		assert.Equal(t, "label2", skipped[0].Name)
+		assert.Equal(t, "orglabel3", skipped[1].Name)
+	}
+
+	// copying again creates nothing
+	created, skipped, err = CopyLabels(source, target)
+	assert.NoError(t, err)
+	assert.Empty(t, created)
+	assert.Len(t, skipped, 3)
+}
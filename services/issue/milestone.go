@@ -5,8 +5,17 @@
 package issue
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
 )
 
 // ChangeMilestoneAssign changes assignment of milestone for issue.
@@ -19,3 +28,147 @@ func ChangeMilestoneAssign(issue *models.Issue, doer *models.User, oldMilestoneI
 
 	return nil
 }
+
+// SendMilestoneReminders reminds the users assigned to open issues of open milestones about the due dates
+// of the milestones, once when a milestone is due within notifyBefore and once when it is due within a day.
+// The due date of a milestone set in the UI is the end of the day, so the latter is the due date.
+// The users get a notification for each of their issues and a mail listing them.
+func SendMilestoneReminders(ctx context.Context, notifyBefore time.Duration) error {
+	now := time.Now()
+	dueDay := now.Add(24 * time.Hour)
+	for _, trigger := range []struct {
+		tp       models.MilestoneReminderType
+		from, to time.Time
+	}{
+		{models.MilestoneReminderDue, now, dueDay},
+		{models.MilestoneReminderDueSoon, dueDay, now.Add(notifyBefore)},
+	} {
+		milestones, err := models.FindOpenMilestonesDueBetween(timeutil.TimeStamp(trigger.from.Unix()), timeutil.TimeStamp(trigger.to.Unix()))
+		if err != nil {
+			return fmt.Errorf("FindOpenMilestonesDueBetween: %v", err)
+		}
+		for _, m := range milestones {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before sending the reminders of milestone %d", m.ID)
+			default:
+			}
+
+			if err := sendMilestoneReminders(m, trigger.tp); err != nil {
+				log.Error("Unable to send the reminders of milestone %d: %v", m.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func sendMilestoneReminders(m *models.Milestone, tp models.MilestoneReminderType) error {
+	repo, err := models.GetRepositoryByID(m.RepoID)
+	if err != nil {
+		return fmt.Errorf("GetRepositoryByID: %v", err)
+	}
+	if repo.IsArchived {
+		return nil
+	}
+	m.Repo = repo
+
+	byAssignee, err := m.GetOpenIssuesByAssignee()
+	if err != nil {
+		return fmt.Errorf("GetOpenIssuesByAssignee: %v", err)
+	}
+	for uid, issues := range byAssignee {
+		sent, err := models.IsMilestoneReminderSent(m, uid, tp)
+		if err != nil {
+			return fmt.Errorf("IsMilestoneReminderSent: %v", err)
+		} else if sent {
+			continue
+		}
+
+		u, err := models.GetUserByID(uid)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("GetUserByID: %v", err)
+		}
+		if !u.IsActive || u.ProhibitLogin || u.MuteMilestoneReminders {
+			continue
+		}
+
+		perm, err := models.GetUserRepoPermission(repo, u)
+		if err != nil {
+			return fmt.Errorf("GetUserRepoPermission: %v", err)
+		}
+		readable := make(models.IssueList, 0, len(issues))
+		for _, issue := range issues {
+			if perm.CanReadIssuesOrPulls(issue.IsPull) {
+				issue.Repo = repo
+				readable = append(readable, issue)
+			}
+		}
+		if len(readable) == 0 {
+			continue
+		}
+
+		for _, issue := range readable {
+			if err := models.CreateOrUpdateIssueNotifications(issue.ID, 0, 0, u.ID); err != nil {
+				return fmt.Errorf("CreateOrUpdateIssueNotifications: %v", err)
+			}
+		}
+		if u.EmailNotifications() != models.EmailNotificationsDisabled {
+			if err := mailer.SendMilestoneReminderMail(u, m, readable, tp); err != nil {
+				log.Error("SendMilestoneReminderMail[%d]: %v", u.ID, err)
+			}
+		}
+		if err := models.SetMilestoneReminderSent(m, u.ID, tp); err != nil {
+			return fmt.Errorf("SetMilestoneReminderSent: %v", err)
+		}
+	}
+	return nil
+}
+
+// CopyMilestones creates the open milestones of the source repository in the target repository, except the ones
+// whose names are already used by a milestone of the target repository. The created milestones and the skipped
+// milestones of the source repository are returned.
+func CopyMilestones(source, target *models.Repository) (created, skipped []*models.Milestone, err error) {
+	milestones, _, err := models.GetMilestones(models.GetMilestonesOption{
+		RepoID:   source.ID,
+		State:    api.StateOpen,
+		SortType: "id",
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetMilestones: %v", err)
+	}
+	existing, _, err := models.GetMilestones(models.GetMilestonesOption{
+		RepoID: target.ID,
+		State:  api.StateAll,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetMilestones: %v", err)
+	}
+	names := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		names[m.Name] = true
+	}
+
+	for _, m := range milestones {
+		name := strings.TrimSpace(m.Name)
+		if names[name] {
+			skipped = append(skipped, m)
+			continue
+		}
+		names[name] = true
+
+		copied := &models.Milestone{
+			RepoID:       target.ID,
+			Name:         name,
+			Content:      m.Content,
+			DeadlineUnix: m.DeadlineUnix,
+		}
+		if err := models.NewMilestone(copied); err != nil {
+			return nil, nil, fmt.Errorf("NewMilestone: %v", err)
+		}
+		created = append(created, copied)
+	}
+	return created, skipped, nil
+}
@@ -0,0 +1,65 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSendMilestoneReminders(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	m := db.AssertExistsAndLoadBean(t, &models.Milestone{ID: 1}).(*models.Milestone)
+	m.DeadlineUnix = timeutil.TimeStampNow().AddDuration(2 * time.Hour)
+	assert.NoError(t, models.UpdateMilestone(m, m.IsClosed))
+
+	// issue 2 is the open issue of milestone1, user4 muted the reminders
+	_, err := db.GetEngine(db.DefaultContext).Insert(&models.IssueAssignees{AssigneeID: 2, IssueID: 2}, &models.IssueAssignees{AssigneeID: 4, IssueID: 2})
+	assert.NoError(t, err)
+	user4 := db.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	user4.MuteMilestoneReminders = true
+	assert.NoError(t, models.UpdateUserCols(user4, "mute_milestone_reminders"))
+
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, SendMilestoneReminders(context.Background(), 72*time.Hour))
+	}
+
+	db.AssertExistsAndLoadBean(t, &models.Notification{UserID: 2, IssueID: 2, Status: models.NotificationStatusUnread})
+	db.AssertExistsAndLoadBean(t, &models.MilestoneReminder{MilestoneID: 1, UserID: 2, Type: models.MilestoneReminderDue})
+	db.AssertNotExistsBean(t, &models.MilestoneReminder{MilestoneID: 1, UserID: 2, Type: models.MilestoneReminderDueSoon})
+	db.AssertNotExistsBean(t, &models.Notification{UserID: 4, IssueID: 2})
+	db.AssertNotExistsBean(t, &models.MilestoneReminder{UserID: 4})
+}
+
+func TestCopyMilestones(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	source := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	target := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	deadline := timeutil.TimeStampNow().AddDuration(72 * time.Hour)
+	db.AssertSuccessfulInsert(t, &models.Milestone{ID: 10, RepoID: source.ID, Name: "due", Content: "with a due date", DeadlineUnix: deadline})
+	assert.NoError(t, models.NewMilestone(&models.Milestone{RepoID: target.ID, Name: "milestone2", IsClosed: true}))
+
+	created, skipped, err := CopyMilestones(source, target)
+	assert.NoError(t, err)
+	if assert.Len(t, created, 2) {
+		assert.Equal(t, "milestone1", created[0].Name)
+		assert.Equal(t, "content1", created[0].Content)
+		assert.Equal(t, "due", created[1].Name)
+		db.AssertExistsAndLoadBean(t, &models.Milestone{ID: created[1].ID, RepoID: target.ID, DeadlineUnix: deadline})
+	}
+	// the closed milestone3 is not copied
+	if assert.Len(t, skipped, 1) {
+		assert.Equal(t, "milestone2", skipped[0].Name)
+	}
+	db.AssertExistsAndLoadBean(t, &models.Repository{ID: target.ID, NumMilestones: 3})
+}
@@ -5,12 +5,25 @@
 package issue
 
 import (
+	"fmt"
+
 	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/notification"
 )
 
 // ChangeStatus changes issue status to open or closed.
+// Reopening a pull request restores its head ref if it has been cleaned up.
 func ChangeStatus(issue *models.Issue, doer *models.User, isClosed bool) (err error) {
+	if issue.IsPull && issue.IsClosed && !isClosed {
+		if err = issue.LoadPullRequest(); err != nil {
+			return
+		}
+		if err = RestoreHeadRef(issue.PullRequest); err != nil {
+			return
+		}
+	}
+
 	comment, err := issue.ChangeStatus(doer, isClosed)
 	if err != nil {
 		return
@@ -19,3 +32,34 @@ func ChangeStatus(issue *models.Issue, doer *models.User, isClosed bool) (err er
 	notification.NotifyIssueChangeStatus(doer, issue, comment, isClosed)
 	return nil
 }
+
+// RestoreHeadRef restores the head ref of a pull request which has been cleaned up from the head branch,
+// ErrPullRequestHeadRefMissing is returned if the head branch doesn't exist anymore.
+func RestoreHeadRef(pr *models.PullRequest) error {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	if git.IsReferenceExist(pr.BaseRepo.RepoPath(), pr.GetGitRefName()) {
+		return nil
+	}
+
+	errMissing := models.ErrPullRequestHeadRefMissing{ID: pr.ID, BaseRepoID: pr.BaseRepoID, Index: pr.Index}
+	if pr.Flow != models.PullRequestFlowGithub {
+		return errMissing
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			return errMissing
+		}
+		return err
+	}
+	if pr.HeadRepo == nil || !git.IsBranchExist(pr.HeadRepo.RepoPath(), pr.HeadBranch) {
+		return errMissing
+	}
+	// fetching the head branch into the base repository doesn't run any hooks
+	if _, err := git.NewCommand("fetch", "--no-tags", pr.HeadRepo.RepoPath(),
+		"+"+git.BranchPrefix+pr.HeadBranch+":"+pr.GetGitRefName()).RunInDir(pr.BaseRepo.RepoPath()); err != nil {
+		return fmt.Errorf("fetch head branch %s: %v", pr.HeadBranch, err)
+	}
+	return nil
+}
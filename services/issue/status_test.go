@@ -0,0 +1,43 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChangeStatusRestoresHeadRef(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	issue := db.AssertExistsAndLoadBean(t, &models.Issue{ID: 3}).(*models.Issue)
+	assert.NoError(t, issue.LoadPullRequest())
+	assert.NoError(t, issue.PullRequest.LoadBaseRepo())
+	repoPath := issue.PullRequest.BaseRepo.RepoPath()
+	refName := issue.PullRequest.GetGitRefName()
+
+	// the head ref is restored from the head branch on reopening
+	assert.NoError(t, ChangeStatus(issue, doer, true))
+	_, err := git.NewCommand("update-ref", "-d", refName).RunInDir(repoPath)
+	assert.NoError(t, err)
+	assert.NoError(t, ChangeStatus(issue, doer, false))
+	assert.False(t, issue.IsClosed)
+	assert.True(t, git.IsReferenceExist(repoPath, refName))
+
+	// the pull request stays closed if the head branch is gone as well
+	assert.NoError(t, ChangeStatus(issue, doer, true))
+	_, err = git.NewCommand("update-ref", "-d", refName).RunInDir(repoPath)
+	assert.NoError(t, err)
+	issue.PullRequest.HeadBranch = "deleted-branch"
+	err = ChangeStatus(issue, doer, false)
+	assert.True(t, models.IsErrPullRequestHeadRefMissing(err))
+	assert.True(t, issue.IsClosed)
+}
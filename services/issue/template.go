@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// TemplateMetas returns the IDs of the labels and assignees named by an issue template, added to the given ones.
+// Labels are matched case-insensitively against the labels of the repository and of its organization, assignees
+// against the users who can be assigned to its issues. The names which don't match are skipped with a warning.
+func TemplateMetas(repo *models.Repository, it *api.IssueTemplate, labelIDs, assigneeIDs []int64) (_, _ []int64, warnings []string, err error) {
+	if len(it.Labels) > 0 {
+		labels, err := models.GetLabelsByRepoID(repo.ID, "", db.ListOptions{})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("GetLabelsByRepoID: %v", err)
+		}
+		if err := repo.GetOwner(); err != nil {
+			return nil, nil, nil, fmt.Errorf("GetOwner: %v", err)
+		}
+		if repo.Owner.IsOrganization() {
+			orgLabels, err := models.GetLabelsByOrgID(repo.OwnerID, "", db.ListOptions{})
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("GetLabelsByOrgID: %v", err)
+			}
+			labels = append(labels, orgLabels...)
+		}
+
+	nextLabel:
+		for _, name := range it.Labels {
+			for _, label := range labels {
+				if strings.EqualFold(label.Name, name) {
+					if !util.IsInt64InSlice(label.ID, labelIDs) {
+						labelIDs = append(labelIDs, label.ID)
+					}
+					continue nextLabel
+				}
+			}
+			warnings = append(warnings, fmt.Sprintf("label of the issue template does not exist: %s", name))
+		}
+	}
+
+	if len(it.Assignees) > 0 {
+		assignees, err := repo.GetAssignees()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("GetAssignees: %v", err)
+		}
+
+	nextAssignee:
+		for _, name := range it.Assignees {
+			for _, assignee := range assignees {
+				if strings.EqualFold(assignee.Name, name) {
+					if !util.IsInt64InSlice(assignee.ID, assigneeIDs) {
+						assigneeIDs = append(assigneeIDs, assignee.ID)
+					}
+					continue nextAssignee
+				}
+			}
+			warnings = append(warnings, fmt.Sprintf("assignee of the issue template cannot be assigned: %s", name))
+		}
+	}
+	return labelIDs, assigneeIDs, warnings, nil
+}
+
+// TemplateTitle prefixes the title of an issue with the title of its template, unless it starts with it already
+func TemplateTitle(it *api.IssueTemplate, title string) string {
+	prefix := strings.TrimSpace(it.Title)
+	if prefix == "" || strings.HasPrefix(title, prefix) {
+		return title
+	}
+	return prefix + " " + strings.TrimSpace(title)
+}
@@ -0,0 +1,47 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package issue
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateMetas(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+
+	it := &api.IssueTemplate{
+		Labels:    []string{"LABEL1", "missing"},
+		Assignees: []string{"user2", "user8"},
+	}
+	labelIDs, assigneeIDs, warnings, err := TemplateMetas(repo, it, []int64{2}, []int64{2})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 1}, labelIDs)
+	assert.Equal(t, []int64{2}, assigneeIDs)
+	assert.Equal(t, []string{
+		"label of the issue template does not exist: missing",
+		"assignee of the issue template cannot be assigned: user8",
+	}, warnings)
+
+	// organization labels are applied to the repositories of the organization
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: 3}).(*models.Repository)
+	labelIDs, _, warnings, err = TemplateMetas(repo, &api.IssueTemplate{Labels: []string{"orglabel3"}}, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{3}, labelIDs)
+	assert.Empty(t, warnings)
+}
+
+func TestTemplateTitle(t *testing.T) {
+	it := &api.IssueTemplate{Title: "[BUG] "}
+	assert.Equal(t, "[BUG] crash", TemplateTitle(it, "crash"))
+	assert.Equal(t, "[BUG] crash", TemplateTitle(it, "[BUG] crash"))
+	assert.Equal(t, "crash", TemplateTitle(&api.IssueTemplate{}, "crash"))
+}
@@ -35,7 +35,10 @@ const (
 	mailAuthResetPassword  base.TplName = "auth/reset_passwd"
 	mailAuthRegisterNotify base.TplName = "auth/register_notify"
 
-	mailNotifyCollaborator base.TplName = "notify/collaborator"
+	mailNotifyCollaborator      base.TplName = "notify/collaborator"
+	mailNotifyGPGKeyExpiry      base.TplName = "notify/gpg_key_expiry"
+	mailNotifyInactiveUser      base.TplName = "notify/inactive_user"
+	mailNotifyMilestoneReminder base.TplName = "notify/milestone_reminder"
 
 	mailRepoTransferNotify base.TplName = "notify/repo_transfer"
 
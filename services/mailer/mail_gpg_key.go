@@ -0,0 +1,92 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendGPGKeyExpiryMails warns the owners of GPG keys able to sign commits that expire within the given duration
+func SendGPGKeyExpiryMails(ctx context.Context, notifyBefore time.Duration) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	keys, err := models.FindGPGKeysExpiringBefore(time.Now().Add(notifyBefore))
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+		}
+
+		if !key.CanSignCommits() {
+			continue
+		}
+
+		owner, err := models.GetUserByID(key.OwnerID)
+		if err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if !owner.IsActive || owner.ProhibitLogin {
+			continue
+		}
+
+		if err := sendGPGKeyExpiryMail(owner, key); err != nil {
+			log.Error("sendGPGKeyExpiryMail[%d]: %v", key.ID, err)
+			continue
+		}
+		if err := models.SetGPGKeyExpiryNotified(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sendGPGKeyExpiryMail(u *models.User, key *models.GPGKey) error {
+	locale := translation.NewLocale(u.Language)
+	subject := locale.Tr("mail.gpg_key.expiry.subject", key.KeyID)
+
+	data := map[string]interface{}{
+		"Subject":     subject,
+		"DisplayName": u.DisplayName(),
+		"KeyID":       key.KeyID,
+		"Expires":     key.ExpiredUnix.AsTime().Format(time.RFC1123),
+		"Link":        setting.AppURL + "user/settings/keys",
+		"Language":    locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyGPGKeyExpiry), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, gpg key expiry warning", u.ID)
+
+	SendAsync(msg)
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendInactiveUserWarningMail warns an inactive user that the account will be deactivated unless the user signs in
+func SendInactiveUserWarningMail(u *models.User, deactivateAt time.Time) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+	subject := locale.Tr("mail.inactive_user.subject", setting.AppName)
+
+	data := map[string]interface{}{
+		"Subject":      subject,
+		"DisplayName":  u.DisplayName(),
+		"LastActivity": u.LastActivityUnix().AsTime().Format(time.RFC1123),
+		"DeactivateAt": deactivateAt.Format(time.RFC1123),
+		"Link":         setting.AppURL + "user/login",
+		"Language":     locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyInactiveUser), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, inactive user warning", u.ID)
+
+	SendAsync(msg)
+	return nil
+}
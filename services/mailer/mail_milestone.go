@@ -0,0 +1,60 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mailer
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendMilestoneReminderMail reminds a user about the due date of a milestone with open issues assigned to the user
+func SendMilestoneReminderMail(u *models.User, m *models.Milestone, issues models.IssueList, tp models.MilestoneReminderType) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	locale := translation.NewLocale(u.Language)
+	subjectKey := "mail.milestone.due_soon.subject"
+	textKey := "mail.milestone.due_soon.text"
+	if tp == models.MilestoneReminderDue {
+		subjectKey = "mail.milestone.due.subject"
+		textKey = "mail.milestone.due.text"
+	}
+	subject := locale.Tr(subjectKey, m.Name, m.Repo.FullName())
+
+	data := map[string]interface{}{
+		"Subject":     subject,
+		"DisplayName": u.DisplayName(),
+		"TextKey":     textKey,
+		"Milestone":   m.Name,
+		"Repo":        m.Repo.FullName(),
+		"Deadline":    m.DeadlineUnix.AsTime().Format(time.RFC1123),
+		"Issues":      issues,
+		"Link":        fmt.Sprintf("%s/milestone/%d", m.Repo.HTMLURL(), m.ID),
+		"Language":    locale.Language(),
+		// helper
+		"i18n":     locale,
+		"Str2html": templates.Str2html,
+		"TrN":      templates.TrN,
+	}
+
+	var content bytes.Buffer
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyMilestoneReminder), data); err != nil {
+		return err
+	}
+
+	msg := NewMessage([]string{u.Email}, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, milestone %d reminder", u.ID, m.ID)
+
+	SendAsync(msg)
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mirror
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ConvertToMirror converts a normal repository into a pull mirror of the remote address and schedules
+// its first sync. The remote address may contain credentials, the original URL must not.
+// An interval of 0 disables the periodic sync.
+func ConvertToMirror(repo *models.Repository, remoteAddr, originalURL string, interval time.Duration) error {
+	if repo.IsMirror {
+		return fmt.Errorf("repository %s is already a mirror", repo.FullName())
+	}
+
+	m := &models.Mirror{
+		RepoID:      repo.ID,
+		Repo:        repo,
+		Interval:    interval,
+		EnablePrune: true,
+	}
+	oldOriginalURL := repo.OriginalURL
+	repo.OriginalURL = originalURL
+
+	// The remote is added last so the database changes are rolled back if it can't be added
+	err := db.WithTx(func(ctx context.Context) error {
+		if err := models.ConvertRepositoryToMirror(ctx, m); err != nil {
+			return err
+		}
+		return setMirrorRemote(repo, m.GetRemoteName(), remoteAddr)
+	})
+	if err != nil {
+		repo.IsMirror = false
+		repo.OriginalURL = oldOriginalURL
+		if errCleanUp := repo_module.CleanUpMigrateGitInfo(repo); errCleanUp != nil {
+			log.Error("Unable to remove the remotes of %-v after failed conversion to mirror: %v", repo, errCleanUp)
+		}
+		return err
+	}
+
+	StartToMirror(repo.ID)
+	return nil
+}
+
+// ConvertToNormal converts a pull mirror into a normal repository, removing the mirror and its remotes
+func ConvertToNormal(repo *models.Repository) error {
+	if !repo.IsMirror {
+		return fmt.Errorf("repository %s is not a mirror", repo.FullName())
+	}
+
+	// The remotes are removed last so the database changes are rolled back if they can't be removed
+	if err := db.WithTx(func(ctx context.Context) error {
+		if err := models.ConvertMirrorToRepository(ctx, repo); err != nil {
+			return err
+		}
+		return repo_module.CleanUpMigrateGitInfo(repo)
+	}); err != nil {
+		repo.IsMirror = true
+		return err
+	}
+	return nil
+}
+
+// ParseMirrorInterval parses the interval of a pull mirror, an empty string means the default interval
+func ParseMirrorInterval(interval string) (time.Duration, error) {
+	if interval == "" {
+		return setting.Mirror.DefaultInterval, nil
+	}
+	parsed, err := time.ParseDuration(interval)
+	if err != nil {
+		return 0, err
+	}
+	if parsed != 0 && parsed < setting.Mirror.MinInterval {
+		return 0, fmt.Errorf("interval %s is below the minimum interval of %s", parsed, setting.Mirror.MinInterval)
+	}
+	return parsed, nil
+}
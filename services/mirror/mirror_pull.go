@@ -11,7 +11,6 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
-	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/lfs"
@@ -28,8 +27,17 @@ const gitShortEmptySha = "0000000"
 
 // UpdateAddress writes new address to Git repository and database
 func UpdateAddress(m *models.Mirror, addr string) error {
-	remoteName := m.GetRemoteName()
-	repoPath := m.Repo.RepoPath()
+	if err := setMirrorRemote(m.Repo, m.GetRemoteName(), addr); err != nil {
+		return err
+	}
+
+	m.Repo.OriginalURL = addr
+	return models.UpdateRepositoryCols(m.Repo, "original_url")
+}
+
+// setMirrorRemote replaces the remote of the repository and its wiki with a fetch mirror of the address
+func setMirrorRemote(repo *models.Repository, remoteName, addr string) error {
+	repoPath := repo.RepoPath()
 	// Remove old remote
 	_, err := git.NewCommand("remote", "rm", remoteName).RunInDir(repoPath)
 	if err != nil && !strings.HasPrefix(err.Error(), "exit status 128 - fatal: No such remote ") {
@@ -41,8 +49,8 @@ func UpdateAddress(m *models.Mirror, addr string) error {
 		return err
 	}
 
-	if m.Repo.HasWiki() {
-		wikiPath := m.Repo.WikiPath()
+	if repo.HasWiki() {
+		wikiPath := repo.WikiPath()
 		wikiRemotePath := repo_module.WikiRemoteURL(addr)
 		// Remove old remote of wiki
 		_, err := git.NewCommand("remote", "rm", remoteName).RunInDir(wikiPath)
@@ -55,9 +63,7 @@ func UpdateAddress(m *models.Mirror, addr string) error {
 			return err
 		}
 	}
-
-	m.Repo.OriginalURL = addr
-	return models.UpdateRepositoryCols(m.Repo, "original_url")
+	return nil
 }
 
 // mirrorSyncResult contains information of a updated reference.
@@ -203,9 +209,9 @@ func runSync(ctx context.Context, m *models.Mirror) ([]*mirrorSyncResult, bool)
 	}
 	gitRepo.Close()
 
-	log.Trace("SyncMirrors [repo: %-v]: updating size of repository", m.Repo)
-	if err := m.Repo.UpdateSize(db.DefaultContext); err != nil {
-		log.Error("Failed to update size for mirror repository: %v", err)
+	log.Trace("SyncMirrors [repo: %-v]: queueing the size update of repository", m.Repo)
+	if err := repo_module.QueueUpdateSize(m.Repo); err != nil {
+		log.Error("Failed to queue the size update of mirror repository: %v", err)
 	}
 
 	if m.Repo.HasWiki() {
@@ -261,5 +261,5 @@ func Init() error {
 
 	go graceful.GetManager().RunWithShutdownFns(prQueue.Run)
 	go graceful.GetManager().RunWithShutdownContext(InitializePullRequests)
-	return nil
+	return initMergeQueue()
 }
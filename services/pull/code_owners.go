@@ -0,0 +1,149 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"io"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// codeOwnersPaths are the paths a CODEOWNERS file is looked up at, the first existing one is used
+var codeOwnersPaths = []string{"CODEOWNERS", ".gitea/CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeOwnersMaxSize is the maximum size of a CODEOWNERS file, larger files are ignored
+const codeOwnersMaxSize = 3 * 1024 * 1024
+
+// GetCodeOwners returns the code owners defined by the CODEOWNERS file of the commit, or nil if there is none
+func GetCodeOwners(repo *models.Repository, commit *git.Commit) (*models.CodeOwners, error) {
+	for _, path := range codeOwnersPaths {
+		blob, err := commit.GetBlobByPath(path)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if blob.Size() > codeOwnersMaxSize {
+			log.Warn("%s of %-v is too large (%d bytes) and is ignored", path, repo, blob.Size())
+			return nil, nil
+		}
+
+		dataRc, err := blob.DataAsync()
+		if err != nil {
+			return nil, err
+		}
+		content, err := io.ReadAll(dataRc)
+		dataRc.Close()
+		if err != nil {
+			return nil, err
+		}
+		return models.ParseCodeOwners(repo, path, string(content))
+	}
+	return nil, nil
+}
+
+// getPullRequestCodeOwners returns the owners of the files changed by the pull request. They are defined by the
+// CODEOWNERS file of the base branch, so a pull request can't change its own code owners.
+func getPullRequestCodeOwners(pr *models.PullRequest) ([]*models.User, []*models.Team, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(pr.BaseBranch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetBranchCommit: %v", err)
+	}
+	codeOwners, err := GetCodeOwners(pr.BaseRepo, commit)
+	if err != nil || codeOwners == nil {
+		return nil, nil, err
+	}
+
+	files, err := gitRepo.GetChangedFiles(git.BranchPrefix+pr.BaseBranch, pr.GetGitRefName())
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetChangedFiles: %v", err)
+	}
+	users, teams := codeOwners.OwnersOf(files)
+	return users, teams, nil
+}
+
+// RequestCodeOwnerReviews requests reviews of the pull request from the owners of the files it changes.
+// Owners who already reviewed the pull request or have been requested to review it are left alone.
+// Nobody is requested while the pull request is a draft.
+func RequestCodeOwnerReviews(pr *models.PullRequest, doer *models.User) error {
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.IsWorkInProgress() {
+		return nil
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		return err
+	}
+
+	users, teams, err := getPullRequestCodeOwners(pr)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if user.ID == pr.Issue.PosterID || user.ID == doer.ID {
+			continue
+		}
+		if _, err := models.GetReviewByIssueIDAndUserID(pr.IssueID, user.ID); err == nil {
+			continue
+		} else if !models.IsErrReviewNotExist(err) {
+			return err
+		}
+		if _, err := issue_service.ReviewRequest(pr.Issue, doer, user, true); err != nil {
+			return fmt.Errorf("ReviewRequest: %v", err)
+		}
+	}
+	for _, team := range teams {
+		if _, err := issue_service.TeamReviewRequest(pr.Issue, doer, team, true); err != nil {
+			return fmt.Errorf("TeamReviewRequest: %v", err)
+		}
+	}
+	return nil
+}
+
+// HasCodeOwnerApproval returns whether an owner of the files changed by the pull request approved it,
+// either directly or as a member of an owning team. A pull request which doesn't change owned files needs no approval.
+func HasCodeOwnerApproval(pr *models.PullRequest) (bool, error) {
+	users, teams, err := getPullRequestCodeOwners(pr)
+	if err != nil {
+		return false, err
+	}
+	if len(users) == 0 && len(teams) == 0 {
+		return true, nil
+	}
+
+	userIDs := make([]int64, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+	for _, team := range teams {
+		members, err := models.GetTeamMembers(team.ID)
+		if err != nil {
+			return false, err
+		}
+		for _, member := range members {
+			userIDs = append(userIDs, member.ID)
+		}
+	}
+
+	excludeStale := pr.ProtectedBranch != nil && pr.ProtectedBranch.DismissStaleApprovals
+	return models.HasApprovalByAnyOf(pr.IssueID, userIDs, excludeStale)
+}
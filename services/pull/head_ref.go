@@ -0,0 +1,94 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// CleanupHeadRefs deletes the head refs of the pull requests of a repository which have been closed or merged
+// for longer than olderThan and returns the number of deleted refs. The commits the refs pointed at are recorded
+// on the pull requests, so that they stay viewable as long as these commits are kept in the repository.
+// Refs of agit flow pull requests are kept as they are the only copy of their head.
+func CleanupHeadRefs(ctx context.Context, repo *models.Repository, olderThan time.Duration) (int, error) {
+	indexes, err := models.GetClosedPullRequestIndexes(repo.ID, timeutil.TimeStamp(time.Now().Add(-olderThan).Unix()))
+	if err != nil {
+		return 0, fmt.Errorf("GetClosedPullRequestIndexes: %v", err)
+	}
+	if len(indexes) == 0 {
+		return 0, nil
+	}
+
+	stdout, err := git.NewCommandContext(ctx, "for-each-ref", "--format=%(refname) %(objectname)", "refs/pull/").RunInDir(repo.RepoPath())
+	if err != nil {
+		return 0, fmt.Errorf("for-each-ref: %v", err)
+	}
+	existing := make(map[string]string)
+	for _, line := range strings.Split(stdout, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			existing[fields[0]] = fields[1]
+		}
+	}
+
+	var stdin strings.Builder
+	commitIDs := make(map[int64]string)
+	for _, index := range indexes {
+		ref := fmt.Sprintf("refs/pull/%d/head", index)
+		if commitID, ok := existing[ref]; ok {
+			stdin.WriteString("delete " + ref + " " + commitID + "\n")
+			commitIDs[index] = commitID
+		}
+	}
+	count := len(commitIDs)
+	if count == 0 {
+		return 0, nil
+	}
+
+	// keep the head commits so that the changes of the pull requests can still be shown
+	if err := models.SetPullRequestHeadRefCommitIDs(repo.ID, commitIDs); err != nil {
+		return 0, fmt.Errorf("SetPullRequestHeadRefCommitIDs: %v", err)
+	}
+
+	stderr := new(strings.Builder)
+	if err := git.NewCommandContext(ctx, "update-ref", "--stdin").
+		RunInDirFullPipeline(repo.RepoPath(), nil, stderr, strings.NewReader(stdin.String())); err != nil {
+		return 0, fmt.Errorf("update-ref: %v - %s", err, stderr)
+	}
+	log.Trace("Deleted %d stale pull request head refs of %-v", count, repo)
+	return count, nil
+}
+
+// CleanupAllHeadRefs deletes the head refs of pull requests closed or merged for longer than olderThan in all repositories
+func CleanupAllHeadRefs(ctx context.Context, olderThan time.Duration) error {
+	return db.Iterate(
+		db.DefaultContext,
+		new(models.Repository),
+		builder.Gt{"num_closed_pulls": 0},
+		func(idx int, bean interface{}) error {
+			repo := bean.(*models.Repository)
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before cleaning up the pull request head refs of %s", repo.FullName())
+			default:
+			}
+			if _, err := CleanupHeadRefs(ctx, repo, olderThan); err != nil {
+				log.Error("CleanupHeadRefs of %-v: %v", repo, err)
+			}
+			return nil
+		},
+	)
+}
@@ -45,6 +45,10 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 	}
 	prConfig := prUnit.PullRequestsConfig()
 
+	if pr.IsWorkInProgress() {
+		return models.ErrPullRequestIsDraft{ID: pr.ID, Index: pr.Index}
+	}
+
 	// Check if merge style is correct and allowed
 	if !prConfig.IsMergeStyleAllowed(mergeStyle) {
 		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
@@ -54,7 +58,17 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false, "", "")
 	}()
 
-	pr.MergedCommitID, err = rawMerge(pr, doer, mergeStyle, message)
+	author, err := getMergeCommitAuthor(pr, prConfig, doer, mergeStyle)
+	if err != nil {
+		log.Error("getMergeCommitAuthor: %v", err)
+		return err
+	}
+	message, err = addMergeMessageTrailers(pr, prConfig, author, mergeStyle, message)
+	if err != nil {
+		log.Error("addMergeMessageTrailers: %v", err)
+		return err
+	}
+	pr.MergedCommitID, err = rawMerge(pr, doer, author, mergeStyle, message)
 	if err != nil {
 		return err
 	}
@@ -108,8 +122,24 @@ func Merge(pr *models.PullRequest, doer *models.User, baseGitRepo *git.Repositor
 	return nil
 }
 
-// rawMerge perform the merge operation without changing any pull information in database
-func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.MergeStyle, message string) (string, error) {
+// getMergeCommitAuthor returns the author of the commit created by merging a pull request
+// as configured by the base repository, the doer is the committer
+func getMergeCommitAuthor(pr *models.PullRequest, prConfig *models.PullRequestsConfig, doer *models.User, mergeStyle models.MergeStyle) (*models.User, error) {
+	if !prConfig.MergeCommitAuthorStyle.IsPRAuthor(mergeStyle) {
+		return doer, nil
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return nil, fmt.Errorf("LoadIssue: %v", err)
+	}
+	if err := pr.Issue.LoadPoster(); err != nil {
+		return nil, fmt.Errorf("LoadPoster: %v", err)
+	}
+	return pr.Issue.Poster, nil
+}
+
+// rawMerge perform the merge operation without changing any pull information in database,
+// the commit created by the merge is authored by author and committed by doer
+func rawMerge(pr *models.PullRequest, doer, author *models.User, mergeStyle models.MergeStyle, message string) (string, error) {
 	err := git.LoadGitVersion()
 	if err != nil {
 		log.Error("git.LoadGitVersion: %v", err)
@@ -132,84 +162,14 @@ func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.Merge
 	trackingBranch := "tracking"
 	stagingBranch := "staging"
 
-	var outbuf, errbuf strings.Builder
-
-	// Enable sparse-checkout
-	sparseCheckoutList, err := getDiffTree(tmpBasePath, baseBranch, trackingBranch)
-	if err != nil {
-		log.Error("getDiffTree(%s, %s, %s): %v", tmpBasePath, baseBranch, trackingBranch, err)
-		return "", fmt.Errorf("getDiffTree: %v", err)
-	}
-
-	infoPath := filepath.Join(tmpBasePath, ".git", "info")
-	if err := os.MkdirAll(infoPath, 0700); err != nil {
-		log.Error("Unable to create .git/info in %s: %v", tmpBasePath, err)
-		return "", fmt.Errorf("Unable to create .git/info in tmpBasePath: %v", err)
-	}
-
-	sparseCheckoutListPath := filepath.Join(infoPath, "sparse-checkout")
-	if err := os.WriteFile(sparseCheckoutListPath, []byte(sparseCheckoutList), 0600); err != nil {
-		log.Error("Unable to write .git/info/sparse-checkout file in %s: %v", tmpBasePath, err)
-		return "", fmt.Errorf("Unable to write .git/info/sparse-checkout file in tmpBasePath: %v", err)
-	}
-
-	var gitConfigCommand func() *git.Command
-	if git.CheckGitVersionAtLeast("1.8.0") == nil {
-		gitConfigCommand = func() *git.Command {
-			return git.NewCommand("config", "--local")
-		}
-	} else {
-		gitConfigCommand = func() *git.Command {
-			return git.NewCommand("config")
-		}
-	}
-
-	// Switch off LFS process (set required, clean and smudge here also)
-	if err := gitConfigCommand().AddArguments("filter.lfs.process", "").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("git config [filter.lfs.process -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("git config [filter.lfs.process -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-	}
-	outbuf.Reset()
-	errbuf.Reset()
-
-	if err := gitConfigCommand().AddArguments("filter.lfs.required", "false").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("git config [filter.lfs.required -> <false> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("git config [filter.lfs.required -> <false> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-	}
-	outbuf.Reset()
-	errbuf.Reset()
-
-	if err := gitConfigCommand().AddArguments("filter.lfs.clean", "").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("git config [filter.lfs.clean -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("git config [filter.lfs.clean -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-	}
-	outbuf.Reset()
-	errbuf.Reset()
-
-	if err := gitConfigCommand().AddArguments("filter.lfs.smudge", "").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("git config [filter.lfs.smudge -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("git config [filter.lfs.smudge -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-	}
-	outbuf.Reset()
-	errbuf.Reset()
-
-	if err := gitConfigCommand().AddArguments("core.sparseCheckout", "true").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("git config [core.sparseCheckout -> true ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("git config [core.sparsecheckout -> true]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	if err := prepareTemporaryRepoForMerge(tmpBasePath, baseBranch, trackingBranch); err != nil {
+		return "", err
 	}
-	outbuf.Reset()
-	errbuf.Reset()
 
-	// Read base branch index
-	if err := git.NewCommand("read-tree", "HEAD").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
-		log.Error("git read-tree HEAD: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-		return "", fmt.Errorf("Unable to read base branch in to the index: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
-	}
-	outbuf.Reset()
-	errbuf.Reset()
+	var outbuf, errbuf strings.Builder
 
-	sig := doer.NewGitSig()
-	committer := sig
+	sig := author.NewGitSig()
+	committer := doer.NewGitSig()
 
 	// Determine if we should sign
 	signArg := ""
@@ -346,18 +306,13 @@ func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.Merge
 			return "", err
 		}
 
-		if err = pr.Issue.LoadPoster(); err != nil {
-			log.Error("LoadPoster: %v", err)
-			return "", fmt.Errorf("LoadPoster: %v", err)
-		}
-		sig := pr.Issue.Poster.NewGitSig()
 		if signArg == "" {
 			if err := git.NewCommand("commit", fmt.Sprintf("--author='%s <%s>'", sig.Name, sig.Email), "-m", message).RunInDirTimeoutEnvPipeline(env, -1, tmpBasePath, &outbuf, &errbuf); err != nil {
 				log.Error("git commit [%s:%s -> %s:%s]: %v\n%s\n%s", pr.HeadRepo.FullName(), pr.HeadBranch, pr.BaseRepo.FullName(), pr.BaseBranch, err, outbuf.String(), errbuf.String())
 				return "", fmt.Errorf("git commit [%s:%s -> %s:%s]: %v\n%s\n%s", pr.HeadRepo.FullName(), pr.HeadBranch, pr.BaseRepo.FullName(), pr.BaseBranch, err, outbuf.String(), errbuf.String())
 			}
 		} else {
-			if committer != sig {
+			if committer.Name != sig.Name || committer.Email != sig.Email {
 				// add trailer
 				message += fmt.Sprintf("\nCo-authored-by: %s\nCo-committed-by: %s\n", sig.String(), sig.String())
 			}
@@ -449,6 +404,87 @@ func rawMerge(pr *models.PullRequest, doer *models.User, mergeStyle models.Merge
 	return mergeCommitID, nil
 }
 
+// prepareTemporaryRepoForMerge configures a temporary repo created by createTemporaryRepo to merge the tracking
+// branch into the base branch, only the files changed between them are checked out
+func prepareTemporaryRepoForMerge(tmpBasePath, baseBranch, trackingBranch string) error {
+	// Enable sparse-checkout
+	sparseCheckoutList, err := getDiffTree(tmpBasePath, baseBranch, trackingBranch)
+	if err != nil {
+		log.Error("getDiffTree(%s, %s, %s): %v", tmpBasePath, baseBranch, trackingBranch, err)
+		return fmt.Errorf("getDiffTree: %v", err)
+	}
+
+	infoPath := filepath.Join(tmpBasePath, ".git", "info")
+	if err := os.MkdirAll(infoPath, 0700); err != nil {
+		log.Error("Unable to create .git/info in %s: %v", tmpBasePath, err)
+		return fmt.Errorf("Unable to create .git/info in tmpBasePath: %v", err)
+	}
+
+	sparseCheckoutListPath := filepath.Join(infoPath, "sparse-checkout")
+	if err := os.WriteFile(sparseCheckoutListPath, []byte(sparseCheckoutList), 0600); err != nil {
+		log.Error("Unable to write .git/info/sparse-checkout file in %s: %v", tmpBasePath, err)
+		return fmt.Errorf("Unable to write .git/info/sparse-checkout file in tmpBasePath: %v", err)
+	}
+
+	var outbuf, errbuf strings.Builder
+	var gitConfigCommand func() *git.Command
+	if git.CheckGitVersionAtLeast("1.8.0") == nil {
+		gitConfigCommand = func() *git.Command {
+			return git.NewCommand("config", "--local")
+		}
+	} else {
+		gitConfigCommand = func() *git.Command {
+			return git.NewCommand("config")
+		}
+	}
+
+	// Switch off LFS process (set required, clean and smudge here also)
+	if err := gitConfigCommand().AddArguments("filter.lfs.process", "").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+		log.Error("git config [filter.lfs.process -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+		return fmt.Errorf("git config [filter.lfs.process -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	}
+	outbuf.Reset()
+	errbuf.Reset()
+
+	if err := gitConfigCommand().AddArguments("filter.lfs.required", "false").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+		log.Error("git config [filter.lfs.required -> <false> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+		return fmt.Errorf("git config [filter.lfs.required -> <false> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	}
+	outbuf.Reset()
+	errbuf.Reset()
+
+	if err := gitConfigCommand().AddArguments("filter.lfs.clean", "").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+		log.Error("git config [filter.lfs.clean -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+		return fmt.Errorf("git config [filter.lfs.clean -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	}
+	outbuf.Reset()
+	errbuf.Reset()
+
+	if err := gitConfigCommand().AddArguments("filter.lfs.smudge", "").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+		log.Error("git config [filter.lfs.smudge -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+		return fmt.Errorf("git config [filter.lfs.smudge -> <> ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	}
+	outbuf.Reset()
+	errbuf.Reset()
+
+	if err := gitConfigCommand().AddArguments("core.sparseCheckout", "true").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+		log.Error("git config [core.sparseCheckout -> true ]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+		return fmt.Errorf("git config [core.sparsecheckout -> true]: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	}
+	outbuf.Reset()
+	errbuf.Reset()
+
+	// Read base branch index
+	if err := git.NewCommand("read-tree", "HEAD").RunInDirPipeline(tmpBasePath, &outbuf, &errbuf); err != nil {
+		log.Error("git read-tree HEAD: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+		return fmt.Errorf("Unable to read base branch in to the index: %v\n%s\n%s", err, outbuf.String(), errbuf.String())
+	}
+	outbuf.Reset()
+	errbuf.Reset()
+
+	return nil
+}
+
 func commitAndSignNoAuthor(pr *models.PullRequest, message, signArg, tmpBasePath string, env []string) error {
 	var outbuf, errbuf strings.Builder
 	if signArg == "" {
@@ -572,6 +608,23 @@ func IsUserAllowedToMerge(pr *models.PullRequest, p models.Permission, user *mod
 	return false, nil
 }
 
+// CheckPRReadyToMergeWithUpdate checks whether the PR is ready to be merged like CheckPRReadyToMerge.
+// If its head branch is outdated and the protected base branch updates outdated pull requests on merge,
+// the head branch is updated if the doer is allowed to and the check is repeated once.
+func CheckPRReadyToMergeWithUpdate(pr *models.PullRequest, doer *models.User) error {
+	err := CheckPRReadyToMerge(pr, false)
+	if !models.IsErrPullRequestOutdated(err) || !pr.ProtectedBranch.AutoUpdateOnMerge {
+		return err
+	}
+
+	if updated, errUpdate := updateOutdatedForMerge(pr, doer); errUpdate != nil {
+		return errUpdate
+	} else if !updated {
+		return err
+	}
+	return CheckPRReadyToMerge(pr, false)
+}
+
 // CheckPRReadyToMerge checks whether the PR is ready to be merged (reviews and status checks)
 func CheckPRReadyToMerge(pr *models.PullRequest, skipProtectedFilesCheck bool) (err error) {
 	if err = pr.LoadBaseRepo(); err != nil {
@@ -611,9 +664,26 @@ func CheckPRReadyToMerge(pr *models.PullRequest, skipProtectedFilesCheck bool) (
 		}
 	}
 
-	if pr.ProtectedBranch.MergeBlockedByOutdatedBranch(pr) {
-		return models.ErrNotAllowedToMerge{
-			Reason: "The head branch is behind the base branch",
+	if pr.ProtectedBranch.BlockOnOutdatedBranch {
+		// The number of commits behind stored with the pull request is only refreshed by the pull request checks
+		divergence, err := GetDiverging(pr)
+		if err != nil {
+			return fmt.Errorf("GetDiverging: %v", err)
+		}
+		if divergence.Behind > 0 {
+			return models.ErrPullRequestOutdated{ID: pr.ID, Behind: divergence.Behind}
+		}
+	}
+
+	if pr.ProtectedBranch.RequireCodeOwnerApproval {
+		approved, err := HasCodeOwnerApproval(pr)
+		if err != nil {
+			return fmt.Errorf("HasCodeOwnerApproval: %v", err)
+		}
+		if !approved {
+			return models.ErrNotAllowedToMerge{
+				Reason: "Does not have the approval of a code owner",
+			}
 		}
 	}
 
@@ -0,0 +1,444 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+)
+
+var (
+	// mergeQueue represents a queue of the base branches whose merge queue has to be processed
+	mergeQueue queue.UniqueQueue
+	// mergeQueueWorkingPool makes sure the merge queue of a branch is only processed by one worker at a time
+	mergeQueueWorkingPool = sync.NewExclusivePool()
+)
+
+func mergeQueueKey(repoID int64, baseBranch string) string {
+	return strconv.FormatInt(repoID, 10) + ":" + baseBranch
+}
+
+// addMergeQueueTask adds the merge queue of a base branch to the queue of merge queues to process
+func addMergeQueueTask(repoID int64, baseBranch string) {
+	if err := mergeQueue.Push(mergeQueueKey(repoID, baseBranch)); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding the merge queue of %d:%s to the queue: %v", repoID, baseBranch, err)
+	}
+}
+
+// AddToMergeQueue adds a pull request to the end of the merge queue of its base branch.
+// The caller has to check that the doer is allowed to merge the pull request.
+func AddToMergeQueue(pr *models.PullRequest, doer *models.User, mergeStyle models.MergeStyle, message string) (*models.MergeQueueEntry, error) {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return nil, fmt.Errorf("LoadBaseRepo: %v", err)
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return nil, fmt.Errorf("LoadIssue: %v", err)
+	}
+
+	prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return nil, err
+	}
+	prConfig := prUnit.PullRequestsConfig()
+	if !prConfig.EnableMergeQueue {
+		return nil, models.ErrMergeQueueDisabled{RepoID: pr.BaseRepoID}
+	}
+
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return nil, models.ErrNotAllowedToMerge{Reason: "The pull request is closed"}
+	}
+	if pr.IsWorkInProgress() {
+		return nil, models.ErrPullRequestIsDraft{ID: pr.ID, Index: pr.Index}
+	}
+	if mergeStyle == models.MergeStyleManuallyMerged || !prConfig.IsMergeStyleAllowed(mergeStyle) {
+		return nil, models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
+	}
+	if !pr.CanAutoMerge() {
+		return nil, models.ErrNotAllowedToMerge{Reason: "The pull request is not mergeable"}
+	}
+	if err := CheckPRReadyToMerge(pr, false); err != nil {
+		return nil, err
+	}
+
+	entry := &models.MergeQueueEntry{
+		RepoID:     pr.BaseRepoID,
+		BaseBranch: pr.BaseBranch,
+		PullID:     pr.ID,
+		Pull:       pr,
+		DoerID:     doer.ID,
+		Doer:       doer,
+		MergeStyle: mergeStyle,
+		Message:    message,
+	}
+	if err := models.InsertMergeQueueEntry(entry); err != nil {
+		return nil, err
+	}
+
+	if _, err := createMergeQueueComment(pr, doer, models.CommentTypeMergeQueueAdd, ""); err != nil {
+		log.Error("createMergeQueueComment: %v", err)
+	}
+
+	addMergeQueueTask(pr.BaseRepoID, pr.BaseBranch)
+	return entry, nil
+}
+
+// RemoveFromMergeQueue removes a pull request from the merge queue of its base branch
+func RemoveFromMergeQueue(pr *models.PullRequest, doer *models.User) error {
+	entry, err := models.GetMergeQueueEntryByPullID(pr.ID)
+	if err != nil {
+		return err
+	}
+	entry.Pull = pr
+	if err := pr.LoadBaseRepo(); err != nil {
+		return fmt.Errorf("LoadBaseRepo: %v", err)
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return fmt.Errorf("LoadIssue: %v", err)
+	}
+
+	if err := removeMergeQueueEntry(entry); err != nil {
+		return err
+	}
+	if _, err := createMergeQueueComment(pr, doer, models.CommentTypeMergeQueueRemove, ""); err != nil {
+		log.Error("createMergeQueueComment: %v", err)
+	}
+
+	// the next pull request might have been waiting for this one
+	addMergeQueueTask(entry.RepoID, entry.BaseBranch)
+	return nil
+}
+
+func createMergeQueueComment(pr *models.PullRequest, doer *models.User, tp models.CommentType, reason string) (*models.Comment, error) {
+	return models.CreateComment(&models.CreateCommentOptions{
+		Type:    tp,
+		Doer:    doer,
+		Repo:    pr.BaseRepo,
+		Issue:   pr.Issue,
+		Content: reason,
+	})
+}
+
+// removeMergeQueueEntry deletes an entry and the ref of its test merge
+func removeMergeQueueEntry(entry *models.MergeQueueEntry) error {
+	if err := models.DeleteMergeQueueEntry(entry); err != nil {
+		return err
+	}
+	if !entry.IsTesting() {
+		return nil
+	}
+
+	repo, err := models.GetRepositoryByID(entry.RepoID)
+	if err != nil {
+		return fmt.Errorf("GetRepositoryByID: %v", err)
+	}
+	if _, err := git.NewCommand("update-ref", "-d", entry.TestRef()).RunInDir(repo.RepoPath()); err != nil {
+		log.Error("Unable to delete %s of %-v: %v", entry.TestRef(), repo, err)
+	}
+	return nil
+}
+
+// evictFromMergeQueue removes an entry which can't be merged and notifies about the reason
+func evictFromMergeQueue(entry *models.MergeQueueEntry, reason string) error {
+	log.Trace("Evicting pull request %d from the merge queue of %d:%s: %s", entry.PullID, entry.RepoID, entry.BaseBranch, reason)
+	if err := removeMergeQueueEntry(entry); err != nil {
+		return err
+	}
+
+	comment, err := createMergeQueueComment(entry.Pull, entry.Doer, models.CommentTypeMergeQueueRemove, reason)
+	if err != nil {
+		return fmt.Errorf("createMergeQueueComment: %v", err)
+	}
+	notification.NotifyPullRequestMergeQueueEvicted(entry.Doer, entry.Pull, comment)
+	return nil
+}
+
+// getHeadCommitID returns the commit the head of a pull request currently points to
+func getHeadCommitID(pr *models.PullRequest) (string, error) {
+	if pr.Flow == models.PullRequestFlowAGit {
+		return git.GetFullCommitID(pr.BaseRepo.RepoPath(), pr.GetGitRefName())
+	}
+
+	if err := pr.LoadHeadRepo(); err != nil {
+		return "", fmt.Errorf("LoadHeadRepo: %v", err)
+	} else if pr.HeadRepo == nil {
+		return "", models.ErrRepoNotExist{ID: pr.HeadRepoID}
+	}
+	return git.GetFullCommitID(pr.HeadRepo.RepoPath(), git.BranchPrefix+pr.HeadBranch)
+}
+
+// createTestMerge merges the head of the pull request of an entry into the current base branch
+// and pushes the result to the test ref of the entry, so that the status checks run on it.
+func createTestMerge(entry *models.MergeQueueEntry) error {
+	pr := entry.Pull
+
+	tmpBasePath, err := createTemporaryRepo(pr)
+	if err != nil {
+		log.Error("CreateTemporaryPath: %v", err)
+		return err
+	}
+	defer func() {
+		if err := models.RemoveTemporaryPath(tmpBasePath); err != nil {
+			log.Error("createTestMerge: RemoveTemporaryPath: %s", err)
+		}
+	}()
+
+	baseBranch := "base"
+	trackingBranch := "tracking"
+
+	if err := prepareTemporaryRepoForMerge(tmpBasePath, baseBranch, trackingBranch); err != nil {
+		return err
+	}
+
+	cmd := git.NewCommand("merge", "--no-ff", "--no-commit", trackingBranch)
+	if err := runMergeCommand(pr, models.MergeStyleMerge, cmd, tmpBasePath); err != nil {
+		return err
+	}
+
+	sig := entry.Doer.NewGitSig()
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME="+sig.Name,
+		"GIT_AUTHOR_EMAIL="+sig.Email,
+		"GIT_COMMITTER_NAME="+sig.Name,
+		"GIT_COMMITTER_EMAIL="+sig.Email,
+	)
+	message := fmt.Sprintf("Test merge of pull request #%d into %s", pr.Index, pr.BaseBranch)
+	if err := commitAndSignNoAuthor(pr, message, "", tmpBasePath, env); err != nil {
+		return err
+	}
+
+	if entry.BaseCommitID, err = git.GetFullCommitID(tmpBasePath, "original_"+baseBranch); err != nil {
+		return fmt.Errorf("Failed to get full commit id for origin/%s: %v", pr.BaseBranch, err)
+	}
+	if entry.HeadCommitID, err = git.GetFullCommitID(tmpBasePath, trackingBranch); err != nil {
+		return fmt.Errorf("Failed to get full commit id for the head: %v", err)
+	}
+	if entry.TestCommitID, err = git.GetFullCommitID(tmpBasePath, "HEAD"); err != nil {
+		return fmt.Errorf("Failed to get full commit id for the test merge: %v", err)
+	}
+
+	if setting.LFS.StartServer {
+		if err := LFSPush(tmpBasePath, entry.TestCommitID, entry.BaseCommitID, pr); err != nil {
+			return err
+		}
+	}
+
+	var outbuf, errbuf strings.Builder
+	if err := git.NewCommand("push", "-f", "origin", "HEAD:"+entry.TestRef()).
+		RunInDirTimeoutEnvPipeline(models.PushingEnvironment(entry.Doer, pr.BaseRepo), -1, tmpBasePath, &outbuf, &errbuf); err != nil {
+		return fmt.Errorf("git push [%s -> %s]: %v\n%s\n%s", pr.BaseRepo.FullName(), entry.TestRef(), err, outbuf.String(), errbuf.String())
+	}
+
+	return entry.UpdateCols("base_commit_id", "head_commit_id", "test_commit_id")
+}
+
+// getTestMergeState returns the state of the required status checks of the test merge of an entry
+func getTestMergeState(entry *models.MergeQueueEntry) (structs.CommitStatusState, error) {
+	pr := entry.Pull
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return "", fmt.Errorf("LoadProtectedBranch: %v", err)
+	}
+	if pr.ProtectedBranch == nil || !pr.ProtectedBranch.EnableStatusCheck {
+		return structs.CommitStatusSuccess, nil
+	}
+
+	commitStatuses, err := models.GetLatestCommitStatus(pr.BaseRepoID, entry.TestCommitID, db.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("GetLatestCommitStatus: %v", err)
+	}
+	// without required contexts any status is enough, but there has to be one
+	if len(commitStatuses) == 0 {
+		return structs.CommitStatusPending, nil
+	}
+	return MergeRequiredContextsCommitStatus(commitStatuses, pr.ProtectedBranch.StatusCheckContexts), nil
+}
+
+// processMergeQueueHead advances the first entry of a merge queue. It returns true if the entry
+// left the queue, so that the next one has to be processed.
+func processMergeQueueHead(entry *models.MergeQueueEntry) (bool, error) {
+	if err := entry.LoadPullRequest(); err != nil {
+		if models.IsErrPullRequestNotExist(err) {
+			return true, removeMergeQueueEntry(entry)
+		}
+		return false, fmt.Errorf("LoadPullRequest: %v", err)
+	}
+	pr := entry.Pull
+	if err := pr.LoadIssue(); err != nil {
+		return false, fmt.Errorf("LoadIssue: %v", err)
+	}
+	if err := pr.LoadBaseRepo(); err != nil {
+		return false, fmt.Errorf("LoadBaseRepo: %v", err)
+	}
+	if pr.HasMerged || pr.Issue.IsClosed {
+		return true, removeMergeQueueEntry(entry)
+	}
+
+	if err := entry.LoadDoer(); err != nil {
+		if !models.IsErrUserNotExist(err) {
+			return false, fmt.Errorf("LoadDoer: %v", err)
+		}
+		entry.Doer = models.NewGhostUser()
+		return true, evictFromMergeQueue(entry, "The user who added the pull request to the merge queue does not exist anymore.")
+	}
+
+	baseCommitID, err := git.GetFullCommitID(pr.BaseRepo.RepoPath(), git.BranchPrefix+pr.BaseBranch)
+	if err != nil {
+		return false, fmt.Errorf("GetFullCommitID: %v", err)
+	}
+	headCommitID, err := getHeadCommitID(pr)
+	if err != nil {
+		if git.IsErrNotExist(err) || models.IsErrRepoNotExist(err) {
+			return true, evictFromMergeQueue(entry, "The head branch of the pull request does not exist anymore.")
+		}
+		return false, fmt.Errorf("getHeadCommitID: %v", err)
+	}
+
+	// (re-)create the test merge if it is missing or outdated
+	if !entry.IsTesting() || entry.BaseCommitID != baseCommitID || entry.HeadCommitID != headCommitID {
+		if err := createTestMerge(entry); err != nil {
+			if models.IsErrMergeConflicts(err) || models.IsErrMergeUnrelatedHistories(err) {
+				return true, evictFromMergeQueue(entry, "The pull request can not be merged into the base branch without conflicts.")
+			}
+			return false, fmt.Errorf("createTestMerge: %v", err)
+		}
+	}
+
+	state, err := getTestMergeState(entry)
+	if err != nil {
+		return false, err
+	}
+	if state.IsPending() {
+		return false, nil
+	} else if !state.IsSuccess() {
+		return true, evictFromMergeQueue(entry, fmt.Sprintf("The required status checks of the test merge %s did not succeed.", entry.TestCommitID))
+	}
+
+	perm, err := models.GetUserRepoPermission(pr.BaseRepo, entry.Doer)
+	if err != nil {
+		return false, fmt.Errorf("GetUserRepoPermission: %v", err)
+	}
+	if allowed, err := IsUserAllowedToMerge(pr, perm, entry.Doer); err != nil {
+		return false, fmt.Errorf("IsUserAllowedToMerge: %v", err)
+	} else if !allowed {
+		return true, evictFromMergeQueue(entry, "The user who added the pull request to the merge queue is not allowed to merge it anymore.")
+	}
+	if err := CheckPRReadyToMerge(pr, false); err != nil {
+		if models.IsErrNotAllowedToMerge(err) {
+			return true, evictFromMergeQueue(entry, err.(models.ErrNotAllowedToMerge).Reason+".")
+		} else if models.IsErrPullRequestOutdated(err) {
+			return true, evictFromMergeQueue(entry, "The head branch is outdated.")
+		}
+		return false, fmt.Errorf("CheckPRReadyToMerge: %v", err)
+	}
+
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return false, fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer gitRepo.Close()
+
+	if err := Merge(pr, entry.Doer, gitRepo, entry.MergeStyle, entry.Message); err != nil {
+		if git.IsErrPushOutOfDate(err) {
+			// the base branch was pushed to in the meantime, which triggers processing the queue again
+			return false, nil
+		} else if models.IsErrInvalidMergeStyle(err) || models.IsErrPullRequestIsDraft(err) ||
+			models.IsErrMergeConflicts(err) || models.IsErrRebaseConflicts(err) ||
+			models.IsErrMergeUnrelatedHistories(err) || git.IsErrPushRejected(err) {
+			return true, evictFromMergeQueue(entry, fmt.Sprintf("The pull request could not be merged: %v", err))
+		}
+		return false, fmt.Errorf("Merge: %v", err)
+	}
+
+	return true, removeMergeQueueEntry(entry)
+}
+
+// processMergeQueue advances the merge queue of a base branch until its first entry has to wait
+func processMergeQueue(repoID int64, baseBranch string) {
+	key := mergeQueueKey(repoID, baseBranch)
+	mergeQueueWorkingPool.CheckIn(key)
+	defer mergeQueueWorkingPool.CheckOut(key)
+
+	for {
+		entry, err := models.GetMergeQueueHead(repoID, baseBranch)
+		if err != nil {
+			log.Error("GetMergeQueueHead[%d:%s]: %v", repoID, baseBranch, err)
+			return
+		} else if entry == nil {
+			return
+		}
+
+		if left, err := processMergeQueueHead(entry); err != nil {
+			log.Error("Unable to process the merge queue of %d:%s at pull request %d: %v", repoID, baseBranch, entry.PullID, err)
+			return
+		} else if !left {
+			return
+		}
+	}
+}
+
+// handleMergeQueue handles the passed base branches and processes their merge queues
+func handleMergeQueue(data ...queue.Data) {
+	for _, datum := range data {
+		parts := strings.SplitN(datum.(string), ":", 2)
+		if len(parts) != 2 {
+			log.Error("Invalid merge queue key: %s", datum)
+			continue
+		}
+		repoID, _ := strconv.ParseInt(parts[0], 10, 64)
+		processMergeQueue(repoID, parts[1])
+	}
+}
+
+// AddMergeQueueStatusCheckTask processes the merge queue whose test merge got a new commit status, if there is one
+func AddMergeQueueStatusCheckTask(repoID int64, commitID string) {
+	entry, err := models.GetMergeQueueEntryByTestCommit(repoID, commitID)
+	if err != nil {
+		log.Error("GetMergeQueueEntryByTestCommit[%d:%s]: %v", repoID, commitID, err)
+		return
+	} else if entry == nil {
+		return
+	}
+	addMergeQueueTask(entry.RepoID, entry.BaseBranch)
+}
+
+// ProcessMergeQueues processes all non-empty merge queues
+func ProcessMergeQueues(ctx context.Context) error {
+	entries, err := models.GetMergeQueueBranches()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("Aborted due to shutdown")
+		default:
+			addMergeQueueTask(entry.RepoID, entry.BaseBranch)
+		}
+	}
+	return nil
+}
+
+func initMergeQueue() error {
+	mergeQueue = queue.CreateUniqueQueue("pr_merge_queue", handleMergeQueue, "")
+	if mergeQueue == nil {
+		return fmt.Errorf("Unable to create pr_merge_queue Queue")
+	}
+
+	go graceful.GetManager().RunWithShutdownFns(mergeQueue.Run)
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// prepareMergeQueueTest enables the merge queue of repo1 and returns its pull request #3
+func prepareMergeQueueTest(t *testing.T) (*models.PullRequest, *models.User) {
+	db.PrepareTestEnv(t)
+
+	// the tasks are not run, the tests process the merge queue themselves
+	for _, q := range []*queue.UniqueQueue{&mergeQueue, &prQueue} {
+		uq, err := queue.NewChannelUniqueQueue(func(data ...queue.Data) {}, queue.ChannelUniqueQueueConfiguration{
+			WorkerPoolConfiguration: queue.WorkerPoolConfiguration{
+				QueueLength: 10,
+				BatchLength: 1,
+			},
+			Workers: 0,
+			Name:    "temporary-queue",
+		}, "")
+		assert.NoError(t, err)
+		*q = uq.(queue.UniqueQueue)
+	}
+
+	pr := db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	assert.NoError(t, pr.LoadBaseRepo())
+	unit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	assert.NoError(t, err)
+	unit.Config = &models.PullRequestsConfig{AllowMerge: true, EnableMergeQueue: true}
+	assert.NoError(t, models.UpdateRepoUnit(unit))
+	pr.BaseRepo.Units = nil
+
+	// git needs an identity for the temporary merges, gitea itself sets it in its own git config
+	for _, key := range []string{"GIT_AUTHOR", "GIT_COMMITTER"} {
+		t.Setenv(key+"_NAME", "Gitea")
+		t.Setenv(key+"_EMAIL", "gitea@fake.local")
+	}
+
+	// the hooks of the test repositories need a gitea binary
+	_, err = git.NewCommand("config", "core.hooksPath", t.TempDir()).RunInDir(pr.BaseRepo.RepoPath())
+	assert.NoError(t, err)
+
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	return pr, doer
+}
+
+func TestMergeQueueMergesTestedPullRequest(t *testing.T) {
+	pr, doer := prepareMergeQueueTest(t)
+	repoPath := pr.BaseRepo.RepoPath()
+
+	entry, err := AddToMergeQueue(pr, doer, models.MergeStyleMerge, pr.GetDefaultMergeMessage())
+	assert.NoError(t, err)
+	_, err = AddToMergeQueue(pr, doer, models.MergeStyleMerge, pr.GetDefaultMergeMessage())
+	assert.True(t, models.IsErrPullRequestAlreadyQueued(err))
+
+	// without required status checks the test merge is merged right away
+	left, err := processMergeQueueHead(entry)
+	assert.NoError(t, err)
+	assert.True(t, left)
+	assert.True(t, entry.IsTesting())
+
+	pr = db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	assert.True(t, pr.HasMerged)
+	db.AssertNotExistsBean(t, &models.MergeQueueEntry{PullID: pr.ID})
+	assert.False(t, git.IsReferenceExist(repoPath, entry.TestRef()))
+}
+
+func TestMergeQueueEvictsFailedPullRequest(t *testing.T) {
+	pr, doer := prepareMergeQueueTest(t)
+	repoPath := pr.BaseRepo.RepoPath()
+
+	entry, err := AddToMergeQueue(pr, doer, models.MergeStyleMerge, pr.GetDefaultMergeMessage())
+	assert.NoError(t, err)
+	assert.NoError(t, db.Insert(db.DefaultContext, &models.ProtectedBranch{
+		RepoID:              pr.BaseRepoID,
+		BranchName:          pr.BaseBranch,
+		EnableStatusCheck:   true,
+		StatusCheckContexts: []string{"ci"},
+	}))
+
+	// the test merge waits for its status checks in a hidden ref
+	left, err := processMergeQueueHead(entry)
+	assert.NoError(t, err)
+	assert.False(t, left)
+	assert.True(t, entry.IsTesting())
+	assert.True(t, git.IsReferenceExist(repoPath, entry.TestRef()))
+
+	assert.NoError(t, models.NewCommitStatus(models.NewCommitStatusOptions{
+		Repo:    pr.BaseRepo,
+		Creator: doer,
+		SHA:     entry.TestCommitID,
+		CommitStatus: &models.CommitStatus{
+			State:   structs.CommitStatusFailure,
+			Context: "ci",
+		},
+	}))
+
+	entry, err = models.GetMergeQueueEntryByPullID(pr.ID)
+	assert.NoError(t, err)
+	left, err = processMergeQueueHead(entry)
+	assert.NoError(t, err)
+	assert.True(t, left)
+
+	pr = db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	assert.False(t, pr.HasMerged)
+	db.AssertNotExistsBean(t, &models.MergeQueueEntry{PullID: pr.ID})
+	db.AssertExistsAndLoadBean(t, &models.Comment{IssueID: pr.IssueID, Type: models.CommentTypeMergeQueueRemove})
+	assert.False(t, git.IsReferenceExist(repoPath, entry.TestRef()))
+}
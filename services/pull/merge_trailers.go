@@ -0,0 +1,179 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// addMergeMessageTrailers appends the trailers configured by the base repository to the message of the commit
+// created by merging a pull request: a Reviewed-by trailer for each approving reviewer and, for squash merges,
+// a Co-authored-by trailer for each author of the squashed commits other than the author of the merge commit.
+// The trailers already in the message are not repeated.
+func addMergeMessageTrailers(pr *models.PullRequest, prConfig *models.PullRequestsConfig, author *models.User, mergeStyle models.MergeStyle, message string) (string, error) {
+	if mergeStyle != models.MergeStyleMerge && mergeStyle != models.MergeStyleRebaseMerge && mergeStyle != models.MergeStyleSquash {
+		// no commit is created
+		return message, nil
+	}
+
+	trailers := make([]string, 0, 4)
+	if prConfig.AddReviewedByTrailers {
+		reviewers, err := getApprovingReviewers(pr)
+		if err != nil {
+			return "", err
+		}
+		for _, reviewer := range reviewers {
+			trailers = append(trailers, "Reviewed-by: "+reviewer.NewGitSig().String())
+		}
+	}
+	if prConfig.AddCoAuthorTrailers && mergeStyle == models.MergeStyleSquash {
+		coAuthors, err := getSquashedCommitAuthors(pr, author)
+		if err != nil {
+			return "", err
+		}
+		for _, a := range coAuthors {
+			trailer := "Co-authored-by: " + a.sig.String()
+			if raw := "Co-authored-by: " + a.raw.String(); raw != trailer {
+				// the default message of squash merges names the authors as in the commits
+				message = replaceLine(message, raw, trailer)
+			}
+			trailers = append(trailers, trailer)
+		}
+	}
+	return appendTrailers(message, trailers), nil
+}
+
+type coAuthor struct {
+	// raw is the author of the commits
+	raw *git.Signature
+	// sig is the git signature of the user with the email of the author, the author otherwise
+	sig *git.Signature
+}
+
+// getApprovingReviewers returns the users whose latest review of the pull request is an approval which has
+// not been dismissed
+func getApprovingReviewers(pr *models.PullRequest) ([]*models.User, error) {
+	reviews, err := models.GetReviewersByIssueID(pr.IssueID)
+	if err != nil {
+		return nil, fmt.Errorf("GetReviewersByIssueID: %v", err)
+	}
+	reviewers := make([]*models.User, 0, len(reviews))
+	for _, review := range reviews {
+		if review.Type != models.ReviewTypeApprove || review.ReviewerID <= 0 {
+			continue
+		}
+		if err := review.LoadReviewer(); err != nil {
+			if models.IsErrUserNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("LoadReviewer: %v", err)
+		}
+		reviewers = append(reviewers, review.Reviewer)
+	}
+	return reviewers, nil
+}
+
+// getSquashedCommitAuthors returns the authors of the commits of the pull request except the author of the
+// merge commit, oldest first. The authors who are users are identified by their git signature, which uses
+// their no-reply address if they keep their email private.
+func getSquashedCommitAuthors(pr *models.PullRequest, author *models.User) ([]coAuthor, error) {
+	gitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer gitRepo.Close()
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetRefCommitID: %v", err)
+	}
+	headCommit, err := gitRepo.GetCommit(headCommitID)
+	if err != nil {
+		return nil, fmt.Errorf("GetCommit: %v", err)
+	}
+	mergeBase, err := gitRepo.GetCommit(pr.MergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("GetCommit: %v", err)
+	}
+	commits, err := gitRepo.CommitsBetween(headCommit, mergeBase)
+	if err != nil {
+		return nil, fmt.Errorf("CommitsBetween: %v", err)
+	}
+
+	seen := map[string]bool{strings.ToLower(author.GetEmail()): true, strings.ToLower(author.Email): true}
+	users := make(map[string]*models.User)
+	coAuthors := make([]coAuthor, 0, len(commits))
+	// commits are in reverse chronological order
+	for i := len(commits) - 1; i >= 0; i-- {
+		email := strings.ToLower(commits[i].Author.Email)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		raw := commits[i].Author
+		sig := raw
+		u, ok := users[email]
+		if !ok {
+			u, err = models.GetUserByEmail(email)
+			if err != nil && !models.IsErrUserNotExist(err) {
+				return nil, fmt.Errorf("GetUserByEmail: %v", err)
+			}
+			users[email] = u
+		}
+		if u != nil {
+			if u.ID == author.ID {
+				continue
+			}
+			sig = u.NewGitSig()
+		}
+		coAuthors = append(coAuthors, coAuthor{raw: raw, sig: sig})
+	}
+	return coAuthors, nil
+}
+
+// appendTrailers appends the trailers which are not in the message yet to its trailers, or after a blank line
+// if it has none
+func appendTrailers(message string, trailers []string) string {
+	existing := make(map[string]bool)
+	for _, line := range strings.Split(message, "\n") {
+		existing[strings.ToLower(strings.TrimSpace(line))] = true
+	}
+	added := make([]string, 0, len(trailers))
+	for _, trailer := range trailers {
+		if key := strings.ToLower(trailer); !existing[key] {
+			existing[key] = true
+			added = append(added, trailer)
+		}
+	}
+	if len(added) == 0 {
+		return message
+	}
+
+	message = strings.TrimRight(message, "\n")
+	if message == "" {
+		return strings.Join(added, "\n") + "\n"
+	}
+	// the trailers of the message are its last paragraph, the subject line is never a trailer
+	if i := strings.LastIndex(message, "\n\n"); i < 0 || !commitMessageTrailersPattern.MatchString(message[i:]) {
+		message += "\n"
+	}
+	return message + "\n" + strings.Join(added, "\n") + "\n"
+}
+
+// replaceLine replaces the lines of the message equal to old once trimmed
+func replaceLine(message, old, replacement string) string {
+	lines := strings.Split(message, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == old {
+			lines[i] = replacement
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,53 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendTrailers(t *testing.T) {
+	trailers := []string{"Reviewed-by: Alice <alice@example.com>", "Reviewed-by: Alice <alice@example.com>"}
+	assert.Equal(t, "Reviewed-by: Alice <alice@example.com>\n", appendTrailers("", trailers))
+	assert.Equal(t, "fix: subject\n\nReviewed-by: Alice <alice@example.com>\n", appendTrailers("fix: subject", trailers))
+	assert.Equal(t, "Subject\n\nBody\n\nReviewed-by: Alice <alice@example.com>\n", appendTrailers("Subject\n\nBody\n", trailers))
+	assert.Equal(t, "Subject\n\nSigned-off-by: Bob <bob@example.com>\nReviewed-by: Alice <alice@example.com>\n",
+		appendTrailers("Subject\n\nSigned-off-by: Bob <bob@example.com>", trailers))
+
+	// the trailers already in the message are not repeated
+	message := "Subject\n\nReviewed-by: Alice <alice@example.com>\n"
+	assert.Equal(t, message, appendTrailers(message, trailers))
+}
+
+func TestAddMergeMessageTrailers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	pr := db.AssertExistsAndLoadBean(t, &models.PullRequest{ID: 2}).(*models.PullRequest)
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 1}).(*models.User)
+	config := &models.PullRequestsConfig{AddReviewedByTrailers: true}
+
+	// user4 is the only reviewer who approved the pull request, its no-reply address is used if it keeps its email private
+	reviewer := db.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	reviewer.KeepEmailPrivate = true
+	assert.NoError(t, models.UpdateUserCols(reviewer, "keep_email_private"))
+
+	message, err := addMergeMessageTrailers(pr, config, doer, models.MergeStyleMerge, "Merge pull request")
+	assert.NoError(t, err)
+	assert.Equal(t, "Merge pull request\n\nReviewed-by: "+reviewer.NewGitSig().String()+"\n", message)
+	assert.NotContains(t, message, reviewer.Email)
+
+	// fast-forward rebases create no commit
+	message, err = addMergeMessageTrailers(pr, config, doer, models.MergeStyleRebase, "")
+	assert.NoError(t, err)
+	assert.Empty(t, message)
+
+	message, err = addMergeMessageTrailers(pr, &models.PullRequestsConfig{}, doer, models.MergeStyleMerge, "Merge pull request")
+	assert.NoError(t, err)
+	assert.Equal(t, "Merge pull request", message)
+}
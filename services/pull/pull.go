@@ -5,8 +5,6 @@
 package pull
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"fmt"
 	"regexp"
@@ -26,6 +24,16 @@ import (
 
 // NewPullRequest creates new pull request with labels for repository.
 func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int64, uuids []string, pr *models.PullRequest, assigneeIDs []int64) error {
+	if err := pr.LoadBaseRepo(); err != nil {
+		return err
+	}
+	if err := pr.LoadHeadRepo(); err != nil {
+		return err
+	}
+	if err := models.CheckSameObjectFormat(pr.BaseRepo, pr.HeadRepo); err != nil {
+		return err
+	}
+
 	if err := TestPatch(pr); err != nil {
 		return err
 	}
@@ -59,6 +67,10 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 		return err
 	}
 
+	if err := models.WatchIfAuto(pull.PosterID, repo.ID, true); err != nil {
+		log.Warn("Fail to perform auto watch on user %v for repo %v: %v", pull.PosterID, repo.ID, err)
+	}
+
 	mentions, err := pull.FindAndUpdateIssueMentions(db.DefaultContext, pull.Poster, pull.Content)
 	if err != nil {
 		return err
@@ -72,6 +84,10 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 		notification.NotifyIssueChangeMilestone(pull.Poster, pull, 0)
 	}
 
+	if err := RequestCodeOwnerReviews(pr, pull.Poster); err != nil {
+		log.Error("RequestCodeOwnerReviews[%d]: %v", pr.ID, err)
+	}
+
 	// add first push codes comment
 	baseGitRepo, err := git.OpenRepository(pr.BaseRepo.RepoPath())
 	if err != nil {
@@ -113,6 +129,8 @@ func NewPullRequest(repo *models.Repository, pull *models.Issue, labelIDs []int6
 }
 
 // ChangeTargetBranch changes the target branch of this pull request, as the given user.
+// The doer has to be allowed to merge into the target branch if it is protected. The pull request leaves
+// the merge queue of its old target branch and its approvals are marked as stale if configured.
 func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch string) (err error) {
 	// Current target branch is already the same
 	if pr.BaseBranch == targetBranch {
@@ -138,6 +156,29 @@ func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch
 		}
 	}
 
+	if err := pr.LoadBaseRepo(); err != nil {
+		return fmt.Errorf("LoadBaseRepo: %v", err)
+	}
+	if !git.IsBranchExist(pr.BaseRepo.RepoPath(), targetBranch) {
+		return models.ErrBranchDoesNotExist{BranchName: targetBranch}
+	}
+
+	protectedBranch, err := models.GetProtectedBranchBy(pr.BaseRepoID, targetBranch)
+	if err != nil {
+		return fmt.Errorf("GetProtectedBranchBy: %v", err)
+	}
+	if protectedBranch != nil {
+		perm, err := models.GetUserRepoPermission(pr.BaseRepo, doer)
+		if err != nil {
+			return fmt.Errorf("GetUserRepoPermission: %v", err)
+		}
+		if !protectedBranch.IsUserMergeWhitelisted(doer.ID, perm) {
+			return models.ErrNotAllowedToMerge{
+				Reason: fmt.Sprintf("not allowed to merge into the protected branch %s", targetBranch),
+			}
+		}
+	}
+
 	// Check if branches are equal
 	branchesEqual, err := IsHeadEqualWithBranch(pr, targetBranch)
 	if err != nil {
@@ -166,9 +207,19 @@ func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch
 		return err
 	}
 
-	// Set new target branch
+	// The pull request can't stay in the merge queue of its old target branch
+	if _, err := models.GetMergeQueueEntryByPullID(pr.ID); err == nil {
+		if err := RemoveFromMergeQueue(pr, doer); err != nil {
+			return fmt.Errorf("RemoveFromMergeQueue: %v", err)
+		}
+	} else if !models.IsErrMergeQueueEntryNotExist(err) {
+		return fmt.Errorf("GetMergeQueueEntryByPullID: %v", err)
+	}
+
+	// Set new target branch, the required status checks and approvals are those of its protection
 	oldBranch := pr.BaseBranch
 	pr.BaseBranch = targetBranch
+	pr.ProtectedBranch = protectedBranch
 
 	// Refresh patch
 	if err := TestPatch(pr); err != nil {
@@ -206,6 +257,59 @@ func ChangeTargetBranch(pr *models.PullRequest, doer *models.User, targetBranch
 		return fmt.Errorf("CreateChangeTargetBranchComment: %v", err)
 	}
 
+	// The approvals were given for the changes to the old target branch
+	if setting.Repository.PullRequest.MarkApprovalsStaleOnRetarget {
+		if err := models.MarkApprovalsAsStale(pr.IssueID); err != nil {
+			return fmt.Errorf("MarkApprovalsAsStale: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ChangeDraft marks the pull request as a draft or as ready for review, as the given user.
+// A pull request is only ready for review once its title has no work in progress prefix anymore,
+// so the prefix is removed when the pull request is marked as ready.
+func ChangeDraft(pr *models.PullRequest, doer *models.User, isDraft bool) error {
+	if err := pr.LoadIssue(); err != nil {
+		return err
+	}
+	if pr.HasMerged {
+		return models.ErrPullRequestHasMerged{
+			ID:         pr.ID,
+			IssueID:    pr.Index,
+			HeadRepoID: pr.HeadRepoID,
+			BaseRepoID: pr.BaseRepoID,
+			HeadBranch: pr.HeadBranch,
+			BaseBranch: pr.BaseBranch,
+		}
+	}
+	if pr.IsWorkInProgress() == isDraft {
+		return nil
+	}
+	pr.Issue.PullRequest = pr
+
+	if !isDraft && models.HasWorkInProgressPrefix(pr.Issue.Title) {
+		// changing the title syncs the flag and notifies that the pull request is ready for review
+		title := strings.TrimSpace(pr.Issue.Title[len(pr.GetWorkInProgressPrefix()):])
+		if err := issue_service.ChangeTitle(pr.Issue, doer, title); err != nil {
+			return err
+		}
+	} else {
+		pr.IsDraft = isDraft
+		if err := pr.UpdateColsIfNotMerged("is_draft"); err != nil {
+			return err
+		}
+		if !isDraft {
+			notification.NotifyPullRequestReadyForReview(doer, pr)
+		}
+	}
+
+	if !isDraft {
+		if err := RequestCodeOwnerReviews(pr, doer); err != nil {
+			log.Error("RequestCodeOwnerReviews[%d]: %v", pr.ID, err)
+		}
+	}
 	return nil
 }
 
@@ -254,19 +358,9 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			}
 			if err == nil {
 				for _, pr := range prs {
-					if newCommitID != "" && newCommitID != git.EmptySHA {
-						changed, err := checkIfPRContentChanged(pr, oldCommitID, newCommitID)
-						if err != nil {
-							log.Error("checkIfPRContentChanged: %v", err)
-						}
-						if changed {
-							// Mark old reviews as stale if diff to mergebase has changed
-							if err := models.MarkReviewsAsStale(pr.IssueID); err != nil {
-								log.Error("MarkReviewsAsStale: %v", err)
-							}
-						}
-						if err := models.MarkReviewsAsNotStale(pr.IssueID, newCommitID); err != nil {
-							log.Error("MarkReviewsAsNotStale: %v", err)
+					if !git.IsEmptyCommitID(newCommitID) {
+						if err := markReviewsAsStale(pr, doer, oldCommitID, newCommitID); err != nil {
+							log.Error("markReviewsAsStale: %v", err)
 						}
 						divergence, err := GetDiverging(pr)
 						if err != nil {
@@ -297,10 +391,17 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			}
 
 			AddToTaskQueue(pr)
+			// the test merge of a queued pull request is outdated now
+			addMergeQueueTask(pr.BaseRepoID, pr.BaseBranch)
 			comment, err := models.CreatePushPullComment(doer, pr, oldCommitID, newCommitID)
 			if err == nil && comment != nil {
 				notification.NotifyPullRequestPushCommits(doer, pr, comment)
 			}
+			if isSync {
+				if err := RequestCodeOwnerReviews(pr, doer); err != nil {
+					log.Error("RequestCodeOwnerReviews[%d]: %v", pr.ID, err)
+				}
+			}
 		}
 
 		log.Trace("AddTestPullRequestTask [base_repo_id: %d, base_branch: %s]: finding pull requests", repoID, branch)
@@ -325,6 +426,9 @@ func AddTestPullRequestTask(doer *models.User, repoID int64, branch string, isSy
 			}
 			AddToTaskQueue(pr)
 		}
+
+		// the test merge of the first pull request in the merge queue is outdated now
+		addMergeQueueTask(repoID, branch)
 	})
 }
 
@@ -364,39 +468,88 @@ func checkIfPRContentChanged(pr *models.PullRequest, oldCommitID, newCommitID st
 		return false, fmt.Errorf("GetMergeBase: %v", err)
 	}
 
-	diffBefore := &bytes.Buffer{}
-	diffAfter := &bytes.Buffer{}
-	if err := headGitRepo.GetDiffFromMergeBase(base, oldCommitID, diffBefore); err != nil {
+	// file hashes and the location of the differences may change without the diff changing,
+	// which the patch-id ignores
+	patchIDBefore, err := headGitRepo.GetPatchID(base, oldCommitID)
+	if err != nil {
 		// If old commit not found, assume changed.
-		log.Debug("GetDiffFromMergeBase: %v", err)
+		log.Debug("GetPatchID: %v", err)
 		return true, nil
 	}
-	if err := headGitRepo.GetDiffFromMergeBase(base, newCommitID, diffAfter); err != nil {
+	patchIDAfter, err := headGitRepo.GetPatchID(base, newCommitID)
+	if err != nil {
 		// New commit should be found
-		return false, fmt.Errorf("GetDiffFromMergeBase: %v", err)
+		return false, fmt.Errorf("GetPatchID: %v", err)
 	}
 
-	diffBeforeLines := bufio.NewScanner(diffBefore)
-	diffAfterLines := bufio.NewScanner(diffAfter)
+	return patchIDBefore != patchIDAfter, nil
+}
 
-	for diffBeforeLines.Scan() && diffAfterLines.Scan() {
-		if strings.HasPrefix(diffBeforeLines.Text(), "index") && strings.HasPrefix(diffAfterLines.Text(), "index") {
-			// file hashes can change without the diff changing
-			continue
-		} else if strings.HasPrefix(diffBeforeLines.Text(), "@@") && strings.HasPrefix(diffAfterLines.Text(), "@@") {
-			// the location of the difference may change
-			continue
-		} else if !bytes.Equal(diffBeforeLines.Bytes(), diffAfterLines.Bytes()) {
-			return true, nil
+// markReviewsAsStale marks the reviews of the pull request as stale after its head moved from oldCommitID to newCommitID.
+// If the base branch dismisses stale approvals, any new commit makes the reviews stale unless the branch only
+// dismisses them on content changes, and the approvers are asked to review the pull request again.
+// Otherwise reviews only become stale if the diff to the merge base changed.
+func markReviewsAsStale(pr *models.PullRequest, doer *models.User, oldCommitID, newCommitID string) error {
+	if err := pr.LoadProtectedBranch(); err != nil {
+		return fmt.Errorf("LoadProtectedBranch: %v", err)
+	}
+	dismissApprovals := pr.ProtectedBranch != nil && pr.ProtectedBranch.DismissStaleApprovals
+
+	changed := true
+	if !dismissApprovals || pr.ProtectedBranch.DismissOnlyOnContentChange {
+		var err error
+		if changed, err = checkIfPRContentChanged(pr, oldCommitID, newCommitID); err != nil {
+			log.Error("checkIfPRContentChanged: %v", err)
 		}
 	}
 
-	if diffBeforeLines.Scan() || diffAfterLines.Scan() {
-		// Diffs not of equal length
-		return true, nil
+	var approvals []*models.Review
+	if changed {
+		if dismissApprovals {
+			var err error
+			approvals, err = models.FindReviews(models.FindReviewOptions{
+				Type:         models.ReviewTypeApprove,
+				IssueID:      pr.IssueID,
+				OfficialOnly: true,
+			})
+			if err != nil {
+				return fmt.Errorf("FindReviews: %v", err)
+			}
+		}
+
+		// Mark old reviews as stale if diff to mergebase has changed
+		if err := models.MarkReviewsAsStale(pr.IssueID); err != nil {
+			return fmt.Errorf("MarkReviewsAsStale: %v", err)
+		}
+	}
+	if err := models.MarkReviewsAsNotStale(pr.IssueID, newCommitID); err != nil {
+		return fmt.Errorf("MarkReviewsAsNotStale: %v", err)
 	}
 
-	return false, nil
+	if len(approvals) == 0 {
+		return nil
+	}
+	if err := pr.LoadIssue(); err != nil {
+		return fmt.Errorf("LoadIssue: %v", err)
+	}
+	if err := pr.Issue.LoadRepo(); err != nil {
+		return fmt.Errorf("LoadRepo: %v", err)
+	}
+	for _, review := range approvals {
+		if review.Stale || review.Dismissed || review.CommitID == newCommitID || review.ReviewerID == doer.ID {
+			continue
+		}
+		if err := review.LoadReviewer(); err != nil {
+			return fmt.Errorf("LoadReviewer: %v", err)
+		}
+		if review.Reviewer == nil {
+			continue
+		}
+		if _, err := issue_service.ReviewRequest(pr.Issue, doer, review.Reviewer, true); err != nil {
+			return fmt.Errorf("ReviewRequest: %v", err)
+		}
+	}
+	return nil
 }
 
 // PushToBaseRepo pushes commits from branches of head repository to
@@ -143,7 +143,7 @@ func createTemporaryRepo(pr *models.PullRequest) (string, error) {
 	var headBranch string
 	if pr.Flow == models.PullRequestFlowGithub {
 		headBranch = git.BranchPrefix + pr.HeadBranch
-	} else if len(pr.HeadCommitID) == 40 { // for not created pull request
+	} else if git.IsFullCommitID(pr.HeadCommitID) { // for not created pull request
 		headBranch = pr.HeadCommitID
 	} else {
 		headBranch = pr.GetGitRefName()
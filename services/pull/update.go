@@ -53,7 +53,7 @@ func Update(pull *models.PullRequest, doer *models.User, message string, rebase
 		return fmt.Errorf("HeadBranch of PR %d is up to date", pull.Index)
 	}
 
-	_, err = rawMerge(pr, doer, style, message)
+	_, err = rawMerge(pr, doer, doer, style, message)
 
 	defer func() {
 		if rebase {
@@ -66,6 +66,36 @@ func Update(pull *models.PullRequest, doer *models.User, message string, rebase
 	return err
 }
 
+// updateOutdatedForMerge updates the head branch of an outdated pull request before it is merged.
+// It is rebased if rebase is the default merge style of the repository and the doer may rebase it,
+// otherwise the base branch is merged into it. It returns false if the doer may not update the pull request.
+func updateOutdatedForMerge(pr *models.PullRequest, doer *models.User) (bool, error) {
+	if err := pr.LoadHeadRepo(); err != nil {
+		return false, err
+	}
+	allowedUpdateByMerge, allowedUpdateByRebase, err := IsUserAllowedToUpdate(pr, doer)
+	if err != nil {
+		return false, err
+	}
+
+	prUnit, err := pr.BaseRepo.GetUnit(models.UnitTypePullRequests)
+	if err != nil {
+		return false, err
+	}
+	mergeStyle := prUnit.PullRequestsConfig().GetDefaultMergeStyle()
+	rebase := allowedUpdateByRebase && (mergeStyle == models.MergeStyleRebase || mergeStyle == models.MergeStyleRebaseMerge)
+	if !rebase && !allowedUpdateByMerge {
+		return false, nil
+	}
+
+	log.Trace("Updating outdated PR[%d] before merging it (rebase: %t)", pr.ID, rebase)
+	message := fmt.Sprintf("Merge branch '%s' into %s", pr.BaseBranch, pr.HeadBranch)
+	if err := Update(pr, doer, message, rebase); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // IsUserAllowedToUpdate check if user is allowed to update PR with given permissions and branch protections
 func IsUserAllowedToUpdate(pull *models.PullRequest, user *models.User) (mergeAllowed, rebaseAllowed bool, err error) {
 	if pull.Flow == models.PullRequestFlowAGit {
@@ -0,0 +1,117 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pull
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ErrFileNotInPullRequest is returned if a file marked as viewed isn't changed by the pull request
+type ErrFileNotInPullRequest struct {
+	Path string
+}
+
+// IsErrFileNotInPullRequest checks if an error is a ErrFileNotInPullRequest
+func IsErrFileNotInPullRequest(err error) bool {
+	_, ok := err.(ErrFileNotInPullRequest)
+	return ok
+}
+
+func (err ErrFileNotInPullRequest) Error() string {
+	return fmt.Sprintf("file is not changed by the pull request [path: %s]", err.Path)
+}
+
+// ViewedFile is a file of a pull request a user marked as viewed
+type ViewedFile struct {
+	*models.PullReviewFileState
+	// ChangedSinceViewed is true if the file changed after it was marked as viewed
+	ChangedSinceViewed bool
+}
+
+// Viewed returns if the file is viewed at the current head commit
+func (f *ViewedFile) Viewed() bool {
+	return f != nil && !f.ChangedSinceViewed
+}
+
+// GetChangedFiles returns the names of the files changed by the pull request
+func GetChangedFiles(gitRepo *git.Repository, pr *models.PullRequest) ([]string, error) {
+	base := git.BranchPrefix + pr.BaseBranch
+	if pr.HasMerged {
+		base = pr.MergeBase
+	}
+	return gitRepo.GetChangedFiles(base, pr.GetGitRefName())
+}
+
+// GetViewedFiles returns the files of the pull request the user marked as viewed. Files which changed
+// between the head commit they were viewed at and the current head commit are flagged.
+func GetViewedFiles(gitRepo *git.Repository, pr *models.PullRequest, userID int64) ([]*ViewedFile, error) {
+	states, err := models.GetPullReviewFileStates(pr.ID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(states) == 0 {
+		return []*ViewedFile{}, nil
+	}
+
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetRefCommitID: %v", err)
+	}
+
+	// the files changed since each commit the files were viewed at,
+	// nil if the commit is gone after a force push and all its files count as changed
+	changedSince := make(map[string]map[string]bool)
+	files := make([]*ViewedFile, len(states))
+	for i, state := range states {
+		files[i] = &ViewedFile{PullReviewFileState: state}
+		if state.CommitSHA == headCommitID {
+			continue
+		}
+		changed, ok := changedSince[state.CommitSHA]
+		if !ok {
+			names, err := gitRepo.GetFilesChangedBetween(state.CommitSHA, headCommitID)
+			if err != nil {
+				log.Debug("GetFilesChangedBetween(%s, %s): %v", state.CommitSHA, headCommitID, err)
+			} else {
+				changed = make(map[string]bool, len(names))
+				for _, name := range names {
+					changed[name] = true
+				}
+			}
+			changedSince[state.CommitSHA] = changed
+		}
+		files[i].ChangedSinceViewed = changed == nil || changed[state.TreePath]
+	}
+	return files, nil
+}
+
+// UpdateViewedFiles marks the files of the pull request as viewed at its current head commit or unmarks them.
+// Only files changed by the pull request can be marked.
+func UpdateViewedFiles(gitRepo *git.Repository, pr *models.PullRequest, userID int64, viewed map[string]bool) error {
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return fmt.Errorf("GetRefCommitID: %v", err)
+	}
+
+	changedFiles, err := GetChangedFiles(gitRepo, pr)
+	if err != nil {
+		return fmt.Errorf("GetChangedFiles: %v", err)
+	}
+	changed := make(map[string]bool, len(changedFiles))
+	for _, name := range changedFiles {
+		changed[name] = true
+	}
+	for name, isViewed := range viewed {
+		if isViewed && !changed[name] {
+			return ErrFileNotInPullRequest{Path: name}
+		}
+	}
+
+	return models.UpdatePullReviewFileStates(pr, userID, headCommitID, viewed)
+}
@@ -0,0 +1,312 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/convert"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"xorm.io/builder"
+)
+
+// The files of a repository backup run, stored below the path of the run
+const (
+	RepoBackupBundleFile   = "repo.bundle"
+	RepoBackupWikiFile     = "wiki.bundle"
+	RepoBackupMetadataFile = "metadata.json"
+)
+
+// repoBackupLock makes sure the backup runs are only processed by one task at a time
+var repoBackupLock sync.Mutex
+
+// RepoBackupMetadata is the JSON dump of the metadata of a repository stored with its bundles
+type RepoBackupMetadata struct {
+	Repository    *api.Repository           `json:"repository"`
+	Collaborators []*RepoBackupCollaborator `json:"collaborators"`
+	Labels        []*api.Label              `json:"labels"`
+	Milestones    []*api.Milestone          `json:"milestones"`
+	Created       time.Time                 `json:"created_at"`
+}
+
+// RepoBackupCollaborator represents a collaborator of a backed up repository
+type RepoBackupCollaborator struct {
+	Login      string `json:"login"`
+	Permission string `json:"permission"`
+}
+
+func getRepoBackupMetadata(repo *models.Repository) (*RepoBackupMetadata, error) {
+	if err := repo.GetOwner(); err != nil {
+		return nil, fmt.Errorf("GetOwner: %v", err)
+	}
+	metadata := &RepoBackupMetadata{
+		Repository: convert.ToRepo(repo, models.AccessModeOwner),
+		Created:    time.Now().UTC(),
+	}
+
+	collaborators, err := repo.GetCollaborators(db.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("GetCollaborators: %v", err)
+	}
+	metadata.Collaborators = make([]*RepoBackupCollaborator, 0, len(collaborators))
+	for _, c := range collaborators {
+		metadata.Collaborators = append(metadata.Collaborators, &RepoBackupCollaborator{
+			Login:      c.User.Name,
+			Permission: c.Collaboration.Mode.String(),
+		})
+	}
+
+	labels, err := models.GetLabelsByRepoID(repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("GetLabelsByRepoID: %v", err)
+	}
+	metadata.Labels = convert.ToLabelList(labels, repo, nil)
+
+	milestones, _, err := models.GetMilestones(models.GetMilestonesOption{RepoID: repo.ID, State: api.StateAll, SortType: "id"})
+	if err != nil {
+		return nil, fmt.Errorf("GetMilestones: %v", err)
+	}
+	metadata.Milestones = make([]*api.Milestone, 0, len(milestones))
+	for _, m := range milestones {
+		metadata.Milestones = append(metadata.Milestones, convert.ToAPIMilestone(m))
+	}
+	return metadata, nil
+}
+
+// saveRepoBackupFile stores a local file as a file of a backup run and returns its size
+func saveRepoBackupFile(b *models.RepoBackup, name, localPath string) (int64, error) {
+	f, err := os.Open(localPath)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return 0, err
+	}
+	return storage.RepoBackups.Save(path.Join(b.Path, name), f, fi.Size())
+}
+
+// createRepoBackupFiles bundles the repository and its wiki and dumps its metadata to the storage,
+// it returns the total size of the stored files
+func createRepoBackupFiles(ctx context.Context, b *models.RepoBackup) (int64, error) {
+	repo := b.Repo
+	tmpPath, err := models.CreateTemporaryPath("repo-backup")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err := models.RemoveTemporaryPath(tmpPath); err != nil {
+			log.Error("createRepoBackupFiles: RemoveTemporaryPath: %s", err)
+		}
+	}()
+
+	bundles := []struct {
+		name, repoPath string
+	}{}
+	if !repo.IsEmpty {
+		bundles = append(bundles, struct{ name, repoPath string }{RepoBackupBundleFile, repo.RepoPath()})
+	}
+	if repo.HasWiki() {
+		bundles = append(bundles, struct{ name, repoPath string }{RepoBackupWikiFile, repo.WikiPath()})
+	}
+
+	var size int64
+	for _, bundle := range bundles {
+		bundlePath := filepath.Join(tmpPath, bundle.name)
+		if _, err := git.NewCommandContext(ctx, "bundle", "create", bundlePath, "--all").RunInDir(bundle.repoPath); err != nil {
+			return size, fmt.Errorf("git bundle %s: %v", bundle.name, err)
+		}
+		written, err := saveRepoBackupFile(b, bundle.name, bundlePath)
+		if err != nil {
+			return size, fmt.Errorf("save %s: %v", bundle.name, err)
+		}
+		size += written
+	}
+
+	metadata, err := getRepoBackupMetadata(repo)
+	if err != nil {
+		return size, err
+	}
+	metadataPath := filepath.Join(tmpPath, RepoBackupMetadataFile)
+	content, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return size, err
+	}
+	if err := os.WriteFile(metadataPath, content, 0o600); err != nil {
+		return size, err
+	}
+	written, err := saveRepoBackupFile(b, RepoBackupMetadataFile, metadataPath)
+	if err != nil {
+		return size, fmt.Errorf("save %s: %v", RepoBackupMetadataFile, err)
+	}
+	return size + written, nil
+}
+
+// removeRepoBackupFiles removes the files of a backup run from the storage
+func removeRepoBackupFiles(b *models.RepoBackup) error {
+	for _, name := range []string{RepoBackupBundleFile, RepoBackupWikiFile, RepoBackupMetadataFile} {
+		if err := storage.RepoBackups.Delete(path.Join(b.Path, name)); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("delete %s of repository backup %d: %v", name, b.ID, err)
+		}
+	}
+	return nil
+}
+
+// DeleteRepoBackup removes the files and the record of a backup run
+func DeleteRepoBackup(b *models.RepoBackup) error {
+	if err := removeRepoBackupFiles(b); err != nil {
+		return err
+	}
+	return models.DeleteRepoBackup(b.ID)
+}
+
+// deleteExpiredRepoBackups removes the backup runs of a repository beyond the configured retention
+func deleteExpiredRepoBackups(repoID int64) error {
+	if setting.RepoBackup.Retention <= 0 {
+		return nil
+	}
+	expired, err := models.GetExpiredRepoBackups(repoID, setting.RepoBackup.Retention)
+	if err != nil {
+		return err
+	}
+	for _, b := range expired {
+		if err := DeleteRepoBackup(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runRepoBackup makes an attempt of a backup run, a failed attempt is rescheduled with an increasing backoff
+func runRepoBackup(ctx context.Context, b *models.RepoBackup) error {
+	if err := b.LoadRepo(); err != nil {
+		if models.IsErrRepoNotExist(err) {
+			// the repository was deleted before its backup started
+			return models.DeleteRepoBackup(b.ID)
+		}
+		return err
+	}
+	if err := b.MarkRunning(); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	size, err := createRepoBackupFiles(ctx, b)
+	if err != nil {
+		log.Warn("Backup of repository %-v failed (attempt %d): %v", b.Repo, b.Attempts, err)
+		if err := removeRepoBackupFiles(b); err != nil {
+			log.Error("removeRepoBackupFiles: %v", err)
+		}
+		return b.MarkFailed(err, time.Since(start))
+	}
+	if err := b.MarkSucceeded(size, time.Since(start)); err != nil {
+		return err
+	}
+	return deleteExpiredRepoBackups(b.RepoID)
+}
+
+// ProcessRepoBackups runs all pending repository backup runs which are due
+func ProcessRepoBackups(ctx context.Context) error {
+	repoBackupLock.Lock()
+	defer repoBackupLock.Unlock()
+
+	// runs still marked as running have been interrupted, e.g. by a restart
+	if err := models.ResetRunningRepoBackups(); err != nil {
+		return err
+	}
+
+	const batchSize = 50
+	for {
+		backups, err := models.GetDueRepoBackups(batchSize)
+		if err != nil {
+			return err
+		}
+		for _, b := range backups {
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before backup %d of repository %d", b.ID, b.RepoID)
+			default:
+			}
+			// failed runs are rescheduled into the future, so they are not found again by the next batch
+			if err := runRepoBackup(ctx, b); err != nil {
+				return fmt.Errorf("repository backup %d: %v", b.ID, err)
+			}
+		}
+		if len(backups) < batchSize {
+			return nil
+		}
+	}
+}
+
+// QueueRepoBackup adds a backup run for a repository unless one is already pending or running
+func QueueRepoBackup(repo *models.Repository) (*models.RepoBackup, error) {
+	if has, err := models.HasUnfinishedRepoBackup(repo.ID); err != nil {
+		return nil, err
+	} else if has {
+		return nil, models.ErrRepoBackupAlreadyQueued{RepoID: repo.ID}
+	}
+	return models.CreateRepoBackup(repo.ID)
+}
+
+// StartProcessingRepoBackups processes the due repository backup runs in the background
+func StartProcessingRepoBackups() {
+	go graceful.GetManager().RunWithShutdownContext(func(ctx context.Context) {
+		if err := ProcessRepoBackups(ctx); err != nil {
+			log.Error("ProcessRepoBackups: %v", err)
+		}
+	})
+}
+
+// BackupRepositories queues a backup run for every repository which has not opted out and processes them
+func BackupRepositories(ctx context.Context) error {
+	if err := db.Iterate(
+		db.DefaultContext,
+		new(models.Repository),
+		builder.Eq{"is_backup_enabled": true},
+		func(idx int, bean interface{}) error {
+			repo := bean.(*models.Repository)
+			select {
+			case <-ctx.Done():
+				return models.ErrCancelledf("before queuing the backup of %s", repo.FullName())
+			default:
+			}
+			if _, err := QueueRepoBackup(repo); err != nil && !models.IsErrRepoBackupAlreadyQueued(err) {
+				return err
+			}
+			return nil
+		},
+	); err != nil {
+		return err
+	}
+	return ProcessRepoBackups(ctx)
+}
+
+// RetryRepoBackup schedules a failed backup run again and processes it in the background
+func RetryRepoBackup(b *models.RepoBackup) error {
+	if b.Status != models.RepoBackupFailed {
+		return models.ErrRepoBackupNotFailed{ID: b.ID}
+	}
+	if err := models.RetryRepoBackup(b); err != nil {
+		return err
+	}
+	StartProcessingRepoBackups()
+	return nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"path"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessRepoBackups(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	var err error
+	oldStorage, oldRetention := storage.RepoBackups, setting.RepoBackup.Retention
+	defer func() {
+		storage.RepoBackups, setting.RepoBackup.Retention = oldStorage, oldRetention
+	}()
+	storage.RepoBackups, err = storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+	setting.RepoBackup.Retention = 1
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	first, err := QueueRepoBackup(repo)
+	assert.NoError(t, err)
+	_, err = QueueRepoBackup(repo)
+	assert.True(t, models.IsErrRepoBackupAlreadyQueued(err))
+
+	assert.NoError(t, ProcessRepoBackups(context.Background()))
+	first = db.AssertExistsAndLoadBean(t, &models.RepoBackup{ID: first.ID}).(*models.RepoBackup)
+	assert.Equal(t, models.RepoBackupSucceeded, first.Status)
+	assert.Positive(t, first.Size)
+
+	for _, name := range []string{RepoBackupBundleFile, RepoBackupWikiFile, RepoBackupMetadataFile} {
+		_, err := storage.RepoBackups.Stat(path.Join(first.Path, name))
+		assert.NoError(t, err, name)
+	}
+	f, err := storage.RepoBackups.Open(path.Join(first.Path, RepoBackupMetadataFile))
+	assert.NoError(t, err)
+	content, err := io.ReadAll(f)
+	f.Close()
+	assert.NoError(t, err)
+	var metadata RepoBackupMetadata
+	assert.NoError(t, json.Unmarshal(content, &metadata))
+	assert.Equal(t, repo.FullName(), metadata.Repository.FullName)
+	assert.NotEmpty(t, metadata.Labels)
+
+	// the second backup replaces the first one which is beyond the retention
+	second, err := QueueRepoBackup(repo)
+	assert.NoError(t, err)
+	assert.NoError(t, ProcessRepoBackups(context.Background()))
+	db.AssertExistsAndLoadBean(t, &models.RepoBackup{ID: second.ID, Status: models.RepoBackupSucceeded})
+	db.AssertNotExistsBean(t, &models.RepoBackup{ID: first.ID})
+	_, err = storage.RepoBackups.Stat(path.Join(first.Path, RepoBackupBundleFile))
+	assert.Error(t, err)
+}
+
+func TestBackupRepositoriesSkipsOptedOut(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	var err error
+	oldStorage := storage.RepoBackups
+	defer func() {
+		storage.RepoBackups = oldStorage
+	}()
+	storage.RepoBackups, err = storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	repo.IsBackupEnabled = false
+	assert.NoError(t, models.UpdateRepositoryCols(repo, "is_backup_enabled"))
+
+	assert.NoError(t, BackupRepositories(context.Background()))
+	db.AssertNotExistsBean(t, &models.RepoBackup{RepoID: 1})
+	db.AssertExistsAndLoadBean(t, &models.RepoBackup{RepoID: 2, Status: models.RepoBackupSucceeded})
+}
@@ -0,0 +1,58 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// AddDeployKey adds a deploy key to a repository and notifies the change
+func AddDeployKey(doer *models.User, repo *models.Repository, name, content string, readOnly, canWriteStatuses bool) (*models.DeployKey, error) {
+	key, err := models.AddDeployKey(repo.ID, name, content, readOnly, canWriteStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	noticeDeployKey(doer, repo, key, "added to")
+	notification.NotifyAddDeployKey(doer, repo, key)
+	return key, nil
+}
+
+// DeleteDeployKey deletes a deploy key and notifies the change
+func DeleteDeployKey(doer *models.User, id int64) error {
+	key, err := models.GetDeployKeyByID(id)
+	if err != nil {
+		if models.IsErrDeployKeyNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := models.DeleteDeployKey(doer, id); err != nil {
+		return err
+	}
+
+	repo, err := models.GetRepositoryByID(key.RepoID)
+	if err != nil {
+		log.Error("GetRepositoryByID [%d]: %v", key.RepoID, err)
+		return nil
+	}
+	noticeDeployKey(doer, repo, key, "removed from")
+	notification.NotifyDeleteDeployKey(doer, repo, key)
+	return nil
+}
+
+// noticeDeployKey records the change of a deploy key as a system notice, without the content of the key
+func noticeDeployKey(doer *models.User, repo *models.Repository, key *models.DeployKey, change string) {
+	access := "read-only"
+	if !key.IsReadOnly() {
+		access = "read-write"
+	}
+	if err := models.CreateNotice(models.NoticeSecurity, "%s deploy key %q (%s) %s repository %s by %s",
+		access, key.Name, key.Fingerprint, change, repo.FullName(), doer.Name); err != nil {
+		log.Error("CreateNotice: %v", err)
+	}
+}
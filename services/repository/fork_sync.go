@@ -0,0 +1,159 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// ForkSyncStatus represents how a branch of a fork relates to the matching
+// branch of its base repository
+type ForkSyncStatus struct {
+	Branch     string
+	BaseRepo   *models.Repository
+	BaseBranch string
+	CommitID   string
+	Ahead      int
+	Behind     int
+}
+
+// forkSyncBaseBranch returns the branch of the base repository a branch of the fork follows:
+// the branch of the same name, or the default branch for the default branch of the fork.
+func forkSyncBaseBranch(repo *models.Repository, branch string) (string, error) {
+	if git.IsBranchExist(repo.BaseRepo.RepoPath(), branch) {
+		return branch, nil
+	}
+	if branch == repo.DefaultBranch && git.IsBranchExist(repo.BaseRepo.RepoPath(), repo.BaseRepo.DefaultBranch) {
+		return repo.BaseRepo.DefaultBranch, nil
+	}
+	return "", models.ErrBranchDoesNotExist{BranchName: branch}
+}
+
+// prepareForkSync creates a temporary repository holding the branch of the fork as "fork"
+// and the branch it follows in the base repository as "upstream" and compares both.
+func prepareForkSync(repo *models.Repository, branch string) (string, *ForkSyncStatus, error) {
+	if !repo.IsFork {
+		return "", nil, fmt.Errorf("%s is not a fork", repo.FullName())
+	}
+	if err := repo.GetBaseRepo(); err != nil {
+		return "", nil, err
+	}
+	if !git.IsBranchExist(repo.RepoPath(), branch) {
+		return "", nil, models.ErrBranchDoesNotExist{BranchName: branch}
+	}
+	baseBranch, err := forkSyncBaseBranch(repo, branch)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpPath, err := models.CreateTemporaryPath("fork-sync")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() {
+		if err := models.RemoveTemporaryPath(tmpPath); err != nil {
+			log.Error("prepareForkSync: RemoveTemporaryPath: %s", err)
+		}
+	}
+	if err := git.InitRepository(tmpPath, true); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("git init: %v", err)
+	}
+
+	// Borrow the objects of both repositories instead of copying them
+	alternates := filepath.Join(repo.RepoPath(), "objects") + "\n" + filepath.Join(repo.BaseRepo.RepoPath(), "objects") + "\n"
+	if err := os.WriteFile(filepath.Join(tmpPath, "objects", "info", "alternates"), []byte(alternates), 0600); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("write alternates: %v", err)
+	}
+
+	if _, err := git.NewCommand("fetch", "--no-tags", repo.RepoPath(), git.BranchPrefix+branch+":fork").RunInDir(tmpPath); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("fetch fork branch %s: %v", branch, err)
+	}
+	if _, err := git.NewCommand("fetch", "--no-tags", repo.BaseRepo.RepoPath(), git.BranchPrefix+baseBranch+":upstream").RunInDir(tmpPath); err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("fetch base branch %s: %v", baseBranch, err)
+	}
+
+	diverging, err := git.GetDivergingCommits(tmpPath, "upstream", "fork")
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("GetDivergingCommits: %v", err)
+	}
+	commitID, err := git.NewCommand("rev-parse", "fork").RunInDir(tmpPath)
+	if err != nil {
+		cleanup()
+		return "", nil, fmt.Errorf("rev-parse: %v", err)
+	}
+
+	return tmpPath, &ForkSyncStatus{
+		Branch:     branch,
+		BaseRepo:   repo.BaseRepo,
+		BaseBranch: baseBranch,
+		CommitID:   strings.TrimSpace(commitID),
+		Ahead:      diverging.Ahead,
+		Behind:     diverging.Behind,
+	}, nil
+}
+
+// GetForkSyncStatus returns how far a branch of a fork is ahead of and behind its base repository
+func GetForkSyncStatus(repo *models.Repository, branch string) (*ForkSyncStatus, error) {
+	tmpPath, status, err := prepareForkSync(repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	if err := models.RemoveTemporaryPath(tmpPath); err != nil {
+		log.Error("GetForkSyncStatus: RemoveTemporaryPath: %s", err)
+	}
+	return status, nil
+}
+
+// SyncFork fast-forwards a branch of a fork to the matching branch of its base repository.
+// A branch with commits of its own is only reset to the base repository when force is set,
+// otherwise an ErrForkHasDiverged is returned.
+func SyncFork(doer *models.User, repo *models.Repository, branch string, force bool) (*ForkSyncStatus, error) {
+	tmpPath, status, err := prepareForkSync(repo, branch)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := models.RemoveTemporaryPath(tmpPath); err != nil {
+			log.Error("SyncFork: RemoveTemporaryPath: %s", err)
+		}
+	}()
+
+	if status.Behind == 0 {
+		// nothing to fetch, the branch is up to date or only ahead
+		return status, nil
+	}
+	if status.Ahead > 0 && !force {
+		return status, models.ErrForkHasDiverged{Branch: branch, Ahead: status.Ahead, Behind: status.Behind}
+	}
+
+	if err := git.Push(tmpPath, git.PushOptions{
+		Remote: repo.RepoPath(),
+		Branch: "upstream:" + git.BranchPrefix + branch,
+		Force:  force,
+		Env:    models.PushingEnvironment(doer, repo),
+	}); err != nil {
+		return nil, err
+	}
+
+	commitID, err := git.NewCommand("rev-parse", "upstream").RunInDir(tmpPath)
+	if err != nil {
+		return nil, fmt.Errorf("rev-parse: %v", err)
+	}
+	status.CommitID = strings.TrimSpace(commitID)
+	status.Ahead, status.Behind = 0, 0
+	return status, nil
+}
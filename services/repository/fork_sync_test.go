@@ -0,0 +1,49 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkSync(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	doer := db.AssertExistsAndLoadBean(t, &models.User{ID: 13}).(*models.User)
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 11}).(*models.Repository)
+	repo.IsFork = true
+
+	status, err := GetForkSyncStatus(repo, "master")
+	assert.NoError(t, err)
+	assert.Equal(t, "user12/repo10", status.BaseRepo.FullName())
+	assert.Equal(t, "master", status.BaseBranch)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", status.CommitID)
+	assert.Zero(t, status.Ahead)
+	assert.Zero(t, status.Behind)
+
+	// nothing to do for an up to date branch
+	status, err = SyncFork(doer, repo, "master", false)
+	assert.NoError(t, err)
+	assert.Equal(t, "65f1bf27bc3bf70f64657658635e66094edbcb4d", status.CommitID)
+
+	// branch2 only exists in the fork
+	_, err = GetForkSyncStatus(repo, "branch2")
+	assert.True(t, models.IsErrBranchDoesNotExist(err))
+
+	// pretend to be a fork of user2/repo16 which does not share any history
+	repo.ForkID, repo.BaseRepo = 16, nil
+	status, err = GetForkSyncStatus(repo, "master")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, status.Ahead)
+	assert.Positive(t, status.Behind)
+
+	_, err = SyncFork(doer, repo, "master", false)
+	assert.True(t, models.IsErrForkHasDiverged(err))
+}
@@ -21,6 +21,11 @@ func GenerateRepository(doer, owner *models.User, templateRepo *models.Repositor
 			Limit: owner.MaxRepoCreation,
 		}
 	}
+	if !opts.Private {
+		if err := models.CheckPublicRepoAllowed(doer, owner, false); err != nil {
+			return nil, err
+		}
+	}
 
 	var generateRepo *models.Repository
 	if err = db.WithTx(func(ctx context.Context) error {
@@ -0,0 +1,136 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/charset"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ProfileRepoName is the name of the repository whose README is shown on the profile of its owner,
+// a repository named like its owner is used if it doesn't exist
+const ProfileRepoName = ".profile"
+
+// ProfileReadme is the README shown on the profile page of a user or an organization
+type ProfileReadme struct {
+	Repo       *models.Repository
+	FileName   string
+	MarkupType string
+	CommitID   string
+	Content    string
+	HTML       string
+}
+
+// GetProfileReadme returns the README of the profile repository of a user or an organization,
+// nil is returned if there is no such README or the doer can't read it.
+func GetProfileReadme(ctx context.Context, owner, doer *models.User) (*ProfileReadme, error) {
+	repo, err := getProfileRepository(owner, doer)
+	if err != nil || repo == nil {
+		return nil, err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("OpenRepository: %v", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("GetBranchCommit: %v", err)
+	}
+
+	entries, err := commit.ListEntries()
+	if err != nil {
+		return nil, fmt.Errorf("ListEntries: %v", err)
+	}
+	var readme *git.TreeEntry
+	for _, entry := range entries {
+		if entry.IsRegular() && markup.IsReadmeFile(entry.Name()) && markup.Type(entry.Name()) != "" {
+			readme = entry
+			break
+		}
+	}
+	if readme == nil || readme.Size() >= setting.UI.MaxDisplayFileSize {
+		return nil, nil
+	}
+
+	dataRc, err := readme.Blob().DataAsync()
+	if err != nil {
+		return nil, fmt.Errorf("DataAsync: %v", err)
+	}
+	defer dataRc.Close()
+	data, err := io.ReadAll(charset.ToUTF8WithFallbackReader(dataRc))
+	if err != nil {
+		return nil, fmt.Errorf("ReadAll: %v", err)
+	}
+
+	profileReadme := &ProfileReadme{
+		Repo:       repo,
+		FileName:   readme.Name(),
+		MarkupType: markup.Type(readme.Name()),
+		CommitID:   commit.ID.String(),
+		Content:    string(data),
+	}
+
+	// The rendered README only changes with the head commit of the default branch
+	cacheKey := fmt.Sprintf("profile_readme_%d_%s_%s", repo.ID, profileReadme.CommitID, readme.Name())
+	profileReadme.HTML, err = cache.GetString(cacheKey, func() (string, error) {
+		var result strings.Builder
+		if err := markup.Render(&markup.RenderContext{
+			Ctx:       ctx,
+			Filename:  readme.Name(),
+			URLPrefix: repo.HTMLURL() + "/src/branch/" + util.PathEscapeSegments(repo.DefaultBranch),
+			Metas:     repo.ComposeDocumentMetas(),
+			GitRepo:   gitRepo,
+		}, strings.NewReader(profileReadme.Content), &result); err != nil {
+			return "", err
+		}
+		return result.String(), nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Render: %v", err)
+	}
+	return profileReadme, nil
+}
+
+// getProfileRepository returns the profile repository of owner if doer can read its code
+func getProfileRepository(owner, doer *models.User) (*models.Repository, error) {
+	for _, name := range []string{ProfileRepoName, owner.Name} {
+		repo, err := models.GetRepositoryByName(owner.ID, name)
+		if err != nil {
+			if models.IsErrRepoNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if repo.IsEmpty || repo.IsBeingCreated() {
+			return nil, nil
+		}
+
+		perm, err := models.GetUserRepoPermission(repo, doer)
+		if err != nil {
+			return nil, err
+		}
+		if !perm.CanRead(models.UnitTypeCode) {
+			return nil, nil
+		}
+		return repo, nil
+	}
+	return nil, nil
+}
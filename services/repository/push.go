@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"code.gitea.io/gitea/models"
-	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/graceful"
@@ -84,8 +83,8 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 	}
 	defer gitRepo.Close()
 
-	if err = repo.UpdateSize(db.DefaultContext); err != nil {
-		log.Error("Failed to update size for repository: %v", err)
+	if err = repo_module.QueueUpdateSize(repo); err != nil {
+		log.Error("Failed to queue the size update of repository: %v", err)
 	}
 
 	addTags := make([]string, 0, len(optsList))
@@ -233,9 +232,9 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 		return fmt.Errorf("PushUpdateAddDeleteTags: %v", err)
 	}
 
-	// Change repository last updated time.
-	if err := models.UpdateRepositoryUpdatedTime(repo.ID, time.Now()); err != nil {
-		return fmt.Errorf("UpdateRepositoryUpdatedTime: %v", err)
+	// Change repository last pushed and updated time.
+	if err := models.UpdateRepositoryPushedTime(repo.ID, time.Now()); err != nil {
+		return fmt.Errorf("UpdateRepositoryPushedTime: %v", err)
 	}
 
 	return nil
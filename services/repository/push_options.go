@@ -0,0 +1,165 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ErrForcePrivate is returned if a user who is no site admin makes a repository public while FORCE_PRIVATE is enabled
+var ErrForcePrivate = errors.New("cannot change private repository to public")
+
+// CheckVisibilityChange checks if the doer may change the visibility of a repository to private or public
+func CheckVisibilityChange(doer *models.User, private bool) error {
+	// when ForcePrivate enabled, you could change public repo to private, but only admin users can change private to public
+	if setting.Repository.ForcePrivate && !private && !doer.IsAdmin {
+		return ErrForcePrivate
+	}
+	return nil
+}
+
+// ApplyPushOptions changes the settings of a repository as requested by the push options of a push.
+// The pusher has to be an admin of the repository, invalid and unknown options are skipped.
+// The returned messages tell the pusher what was changed or skipped.
+func ApplyPushOptions(doer *models.User, repo *models.Repository, opts private.GitPushOptions) ([]string, error) {
+	if len(opts) == 0 {
+		return nil, nil
+	}
+	if doer == nil {
+		return []string{"Push options ignored: only repository admins can change the repository settings"}, nil
+	}
+	perm, err := models.GetUserRepoPermission(repo, doer)
+	if err != nil {
+		return nil, fmt.Errorf("GetUserRepoPermission: %v", err)
+	}
+	if !perm.IsAdmin() {
+		return []string{"Push options ignored: only repository admins can change the repository settings"}, nil
+	}
+
+	keys := make([]string, 0, len(opts))
+	for key := range opts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var messages, changes, topics []string
+	changed, visibilityChanged := false, false
+	for _, key := range keys {
+		value := opts[key]
+		switch key {
+		case private.GitPushOptionRepoPrivate, private.GitPushOptionRepoTemplate:
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				messages = append(messages, fmt.Sprintf("Push option %s ignored: %q is not a boolean", key, value))
+				continue
+			}
+			if key == private.GitPushOptionRepoTemplate {
+				if repo.IsTemplate != b {
+					repo.IsTemplate = b
+					changed = true
+				}
+				changes = append(changes, fmt.Sprintf("template=%t", b))
+				continue
+			}
+			if repo.IsPrivate == b {
+				changes = append(changes, fmt.Sprintf("private=%t", b))
+				continue
+			}
+			if repo.IsFork {
+				messages = append(messages, fmt.Sprintf("Push option %s ignored: the visibility of a fork follows its base repository", key))
+				continue
+			}
+			if err := CheckVisibilityChange(doer, b); err != nil {
+				messages = append(messages, fmt.Sprintf("Push option %s ignored: %v", key, err))
+				continue
+			}
+			repo.IsPrivate = b
+			changed, visibilityChanged = true, true
+			changes = append(changes, fmt.Sprintf("private=%t", b))
+		case private.GitPushOptionRepoDescription:
+			// as limited by the settings API
+			if utf8.RuneCountInString(value) > 255 {
+				messages = append(messages, fmt.Sprintf("Push option %s ignored: the description is longer than 255 characters", key))
+				continue
+			}
+			if repo.Description != value {
+				repo.Description = value
+				changed = true
+			}
+			changes = append(changes, "description")
+		case private.GitPushOptionTopic:
+			validTopics, invalidTopics := models.SanitizeAndValidateTopics(strings.Split(value, ","))
+			if len(invalidTopics) > 0 {
+				messages = append(messages, fmt.Sprintf("Push option %s ignored: invalid topics %s", key, strings.Join(invalidTopics, ", ")))
+				continue
+			}
+			topics = validTopics
+		default:
+			messages = append(messages, fmt.Sprintf("Push option %s ignored: unknown option", key))
+		}
+	}
+
+	if changed {
+		if err := models.UpdateRepository(repo, visibilityChanged); err != nil {
+			return nil, fmt.Errorf("UpdateRepository: %v", err)
+		}
+	}
+
+	// after updating the repository, which would overwrite the topics cached in it
+	if len(topics) > 0 {
+		added, err := addTopics(repo, topics)
+		if err != nil {
+			if !errors.Is(err, errTooManyTopics) {
+				return nil, err
+			}
+			messages = append(messages, fmt.Sprintf("Push option %s ignored: a repository can't have more than %d topics", private.GitPushOptionTopic, models.MaxTopicsPerRepo))
+		} else if added > 0 {
+			changes = append(changes, "topics "+strings.Join(topics, ","))
+		}
+	}
+
+	if len(changes) > 0 {
+		messages = append(messages, "Repository settings applied from push options: "+strings.Join(changes, ", "))
+	}
+	return messages, nil
+}
+
+var errTooManyTopics = errors.New("too many topics")
+
+// addTopics adds the topics the repository doesn't have yet, unless it would have more than MaxTopicsPerRepo topics
+func addTopics(repo *models.Repository, topics []string) (int, error) {
+	existing, _, err := models.FindTopics(&models.FindTopicOptions{RepoID: repo.ID})
+	if err != nil {
+		return 0, fmt.Errorf("FindTopics: %v", err)
+	}
+	has := make(map[string]bool, len(existing))
+	for _, topic := range existing {
+		has[topic.Name] = true
+	}
+	newTopics := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		if !has[topic] {
+			newTopics = append(newTopics, topic)
+		}
+	}
+	if len(existing)+len(newTopics) > models.MaxTopicsPerRepo {
+		return 0, errTooManyTopics
+	}
+	for _, topic := range newTopics {
+		if _, err := models.AddTopic(repo.ID, topic); err != nil {
+			return 0, fmt.Errorf("AddTopic: %v", err)
+		}
+	}
+	return len(newTopics), nil
+}
@@ -0,0 +1,77 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPushOptions(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	owner := db.AssertExistsAndLoadBean(t, &models.User{ID: repo.OwnerID}).(*models.User)
+	reader := db.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	opts := private.GitPushOptions{
+		private.GitPushOptionRepoPrivate:     "true",
+		private.GitPushOptionRepoDescription: "pushed description",
+		private.GitPushOptionTopic:           "Pushed-Topic,golang",
+		"ci.skip":                            "true",
+	}
+
+	// only repository admins may change the settings, deploy keys have no pusher
+	for _, doer := range []*models.User{reader, nil} {
+		messages, err := ApplyPushOptions(doer, repo, opts)
+		assert.NoError(t, err)
+		if assert.Len(t, messages, 1) {
+			assert.Contains(t, messages[0], "only repository admins")
+		}
+	}
+	db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID, IsPrivate: false})
+
+	messages, err := ApplyPushOptions(owner, repo, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"Push option ci.skip ignored: unknown option",
+		"Repository settings applied from push options: description, private=true, topics pushed-topic,golang",
+	}, messages)
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository)
+	assert.True(t, repo.IsPrivate)
+	assert.Equal(t, "pushed description", repo.Description)
+	assert.Contains(t, repo.Topics, "pushed-topic")
+	db.AssertExistsAndLoadBean(t, &models.Topic{Name: "golang"})
+
+	// invalid values are skipped
+	messages, err = ApplyPushOptions(owner, repo, private.GitPushOptions{
+		private.GitPushOptionRepoPrivate:     "maybe",
+		private.GitPushOptionRepoDescription: strings.Repeat("a", 256),
+		private.GitPushOptionTopic:           "not a topic",
+	})
+	assert.NoError(t, err)
+	assert.Len(t, messages, 3)
+	repo = db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID}).(*models.Repository)
+	assert.True(t, repo.IsPrivate)
+	assert.Equal(t, "pushed description", repo.Description)
+
+	// only site admins may make repositories public when they are forced to be private
+	defer func(forcePrivate bool) {
+		setting.Repository.ForcePrivate = forcePrivate
+	}(setting.Repository.ForcePrivate)
+	setting.Repository.ForcePrivate = true
+	messages, err = ApplyPushOptions(owner, repo, private.GitPushOptions{private.GitPushOptionRepoPrivate: "false"})
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 1) {
+		assert.Contains(t, messages[0], ErrForcePrivate.Error())
+	}
+	db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID, IsPrivate: true})
+}
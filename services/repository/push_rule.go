@@ -0,0 +1,144 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+)
+
+// CheckPushRule checks the commits introduced by a push against the push rule and returns
+// a description of every violation found. Only commits which are not yet reachable from
+// any ref of the repository are inspected.
+func CheckPushRule(repoPath, newCommitID string, rule *models.PushRule, env []string) ([]string, error) {
+	if rule.IsEmpty() || git.IsEmptyCommitID(newCommitID) {
+		return nil, nil
+	}
+
+	stdout, err := git.NewCommand("rev-list", "--format=%ce", newCommitID, "--not", "--all").RunInDirWithEnv(repoPath, env)
+	if err != nil {
+		return nil, fmt.Errorf("rev-list: %v", err)
+	}
+
+	var violations []string
+	var commitIDs []string
+	lines := strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+	for i := 0; i+1 < len(lines); i += 2 {
+		commitID := strings.TrimPrefix(lines[i], "commit ")
+		commitIDs = append(commitIDs, commitID)
+		if email := lines[i+1]; rule.IsForbiddenCommitterEmail(email) {
+			violations = append(violations, fmt.Sprintf("commit %s has forbidden committer email %s", commitID, email))
+		}
+	}
+	if len(commitIDs) == 0 || (len(rule.ForbiddenPaths) == 0 && rule.MaxFileSize <= 0 && rule.MaxPathLength <= 0) {
+		return violations, nil
+	}
+
+	files, err := getPushedFiles(repoPath, commitIDs, env)
+	if err != nil {
+		return nil, err
+	}
+
+	var blobIDs []string
+	for _, file := range files {
+		if rule.IsForbiddenPath(file.path) {
+			violations = append(violations, fmt.Sprintf("file %s matches a forbidden path", file.path))
+		}
+		if rule.MaxPathLength > 0 && utf8.RuneCountInString(file.path) > rule.MaxPathLength {
+			violations = append(violations, fmt.Sprintf("file %s has a path longer than %d characters", file.path, rule.MaxPathLength))
+		}
+		if rule.MaxFileSize > 0 {
+			blobIDs = append(blobIDs, file.blobID)
+		}
+	}
+	if len(blobIDs) == 0 {
+		return violations, nil
+	}
+
+	sizes, err := getBlobSizes(repoPath, blobIDs, env)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		if size := sizes[file.blobID]; size > rule.MaxFileSize {
+			violations = append(violations, fmt.Sprintf("file %s is larger than %d bytes", file.path, rule.MaxFileSize))
+		}
+	}
+	return violations, nil
+}
+
+type pushedFile struct {
+	path   string
+	blobID string
+}
+
+// getPushedFiles returns the files added or modified by the commits, every path is only returned once
+func getPushedFiles(repoPath string, commitIDs []string, env []string) ([]pushedFile, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	stdin := strings.NewReader(strings.Join(commitIDs, "\n") + "\n")
+	// --cc also lists the files of merge commits which differ from all parents, e.g. conflict
+	// resolutions, the other files of a merge were already checked in the merged commits
+	if err := git.NewCommand("diff-tree", "--stdin", "-r", "-z", "--root", "--no-commit-id", "--cc", "--raw").
+		RunInDirTimeoutEnvFullPipeline(env, -1, repoPath, stdout, stderr, stdin); err != nil {
+		return nil, fmt.Errorf("diff-tree: %v - %s", err, stderr)
+	}
+
+	// Every entry is ":<old mode> <new mode> <old sha> <new sha> <status>\0<path>\0",
+	// merge commits have one colon, old mode and old sha per parent
+	fields := strings.Split(stdout.String(), "\x00")
+	seen := make(map[string]bool)
+	var files []pushedFile
+	for i := 0; i+1 < len(fields); i += 2 {
+		parents := len(fields[i]) - len(strings.TrimLeft(fields[i], ":"))
+		info := strings.Fields(fields[i][parents:])
+		if parents == 0 || len(info) != 2*parents+3 {
+			return nil, fmt.Errorf("unexpected diff-tree output: %q", fields[i])
+		}
+		newMode, newSha := info[parents], info[2*parents+1]
+		// Skip deleted files and submodules
+		if newMode == "000000" || newMode == "160000" {
+			continue
+		}
+		key := fields[i+1] + "\x00" + newSha
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		files = append(files, pushedFile{path: fields[i+1], blobID: newSha})
+	}
+	return files, nil
+}
+
+// getBlobSizes returns the size in bytes of every blob
+func getBlobSizes(repoPath string, blobIDs []string, env []string) (map[string]int64, error) {
+	stdout := new(bytes.Buffer)
+	stderr := new(bytes.Buffer)
+	stdin := strings.NewReader(strings.Join(blobIDs, "\n") + "\n")
+	if err := git.NewCommand("cat-file", "--batch-check=%(objectname) %(objectsize)").
+		RunInDirTimeoutEnvFullPipeline(env, -1, repoPath, stdout, stderr, stdin); err != nil {
+		return nil, fmt.Errorf("cat-file: %v - %s", err, stderr)
+	}
+
+	sizes := make(map[string]int64, len(blobIDs))
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected cat-file output: %q", line)
+		}
+		sizes[fields[0]] = size
+	}
+	return sizes, nil
+}
@@ -0,0 +1,106 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPushRule(t *testing.T) {
+	repoPath := t.TempDir()
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME=user", "GIT_AUTHOR_EMAIL=user@localhost",
+		"GIT_COMMITTER_NAME=user", "GIT_COMMITTER_EMAIL=user@localhost")
+	run := func(args ...string) string {
+		stdout, err := git.NewCommand(args...).RunInDirWithEnv(repoPath, env)
+		assert.NoError(t, err)
+		return strings.TrimSpace(stdout)
+	}
+
+	run("init")
+	assert.NoError(t, os.MkdirAll(filepath.Join(repoPath, "certs"), os.ModePerm))
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("readme"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, "certs", "server.pem"), []byte(strings.Repeat("x", 100)), 0o644))
+	run("add", ".")
+	run("commit", "-m", "initial")
+	run("rm", "certs/server.pem")
+	run("commit", "-m", "remove certificate")
+	commitID := run("rev-parse", "HEAD")
+
+	// The commits are not reachable from any ref anymore, like in a pre-receive hook
+	run("update-ref", "-d", "HEAD")
+
+	violations, err := CheckPushRule(repoPath, commitID, &models.PushRule{}, env)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+
+	violations, err = CheckPushRule(repoPath, commitID, &models.PushRule{
+		ForbiddenPaths:           []string{"*.pem"},
+		MaxFileSize:              10,
+		MaxPathLength:            10,
+		ForbiddenCommitterEmails: []string{"*@localhost"},
+	}, env)
+	assert.NoError(t, err)
+	assert.Len(t, violations, 5)
+	assert.Contains(t, violations, "file certs/server.pem matches a forbidden path")
+	assert.Contains(t, violations, "file certs/server.pem has a path longer than 10 characters")
+	assert.Contains(t, violations, "file certs/server.pem is larger than 10 bytes")
+
+	violations, err = CheckPushRule(repoPath, commitID, &models.PushRule{MaxFileSize: 10}, env)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"file certs/server.pem is larger than 10 bytes"}, violations)
+
+	run("update-ref", "refs/heads/master", commitID)
+	violations, err = CheckPushRule(repoPath, commitID, &models.PushRule{ForbiddenPaths: []string{"*.pem"}}, env)
+	assert.NoError(t, err)
+	assert.Empty(t, violations)
+}
+
+func TestCheckPushRuleMergeCommit(t *testing.T) {
+	repoPath := t.TempDir()
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME=user", "GIT_AUTHOR_EMAIL=user@localhost",
+		"GIT_COMMITTER_NAME=user", "GIT_COMMITTER_EMAIL=user@localhost")
+	run := func(args ...string) string {
+		stdout, err := git.NewCommand(args...).RunInDirWithEnv(repoPath, env)
+		assert.NoError(t, err)
+		return strings.TrimSpace(stdout)
+	}
+
+	run("init")
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, "README.md"), []byte("readme"), 0o644))
+	run("add", ".")
+	run("commit", "-m", "initial")
+	base := run("rev-parse", "HEAD")
+	run("checkout", "-b", "side")
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, "side.txt"), []byte("side"), 0o644))
+	run("add", ".")
+	run("commit", "-m", "side")
+	run("checkout", base)
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, "main.txt"), []byte("main"), 0o644))
+	run("add", ".")
+	run("commit", "-m", "main")
+	run("update-ref", "refs/heads/master", "HEAD")
+
+	// the merge commit adds a file which is in neither parent
+	run("merge", "--no-commit", "side")
+	assert.NoError(t, os.WriteFile(filepath.Join(repoPath, "server.pem"), []byte("secret"), 0o644))
+	run("add", ".")
+	run("commit", "-m", "merge")
+	commitID := run("rev-parse", "HEAD")
+	run("checkout", "master")
+
+	violations, err := CheckPushRule(repoPath, commitID, &models.PushRule{ForbiddenPaths: []string{"*.pem", "side.txt", "main.txt"}}, env)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"file server.pem matches a forbidden path"}, violations)
+}
@@ -5,8 +5,6 @@
 package repository
 
 import (
-	"fmt"
-
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/notification"
@@ -78,16 +76,32 @@ func PushCreateRepo(authUser, owner *models.User, repoName string) (*models.Repo
 			if ok, err := owner.CanCreateOrgRepo(authUser.ID); err != nil {
 				return nil, err
 			} else if !ok {
-				return nil, fmt.Errorf("cannot push-create repository for org")
+				return nil, models.ErrPushCreateRepoNotAllowed{OwnerName: owner.Name, RepoName: repoName, Reason: "user is not allowed to create repositories in the organization"}
 			}
 		} else if authUser.ID != owner.ID {
-			return nil, fmt.Errorf("cannot push-create repository for another user")
+			return nil, models.ErrPushCreateRepoNotAllowed{OwnerName: owner.Name, RepoName: repoName, Reason: "user is not allowed to create repositories for another user"}
+		}
+
+		if !owner.CanCreateRepo() {
+			return nil, models.ErrReachLimitOfRepo{Limit: owner.MaxCreationLimit()}
 		}
 	}
 
+	if err := models.IsUsableRepoName(repoName); err != nil {
+		return nil, err
+	}
+
+	// Pushes to the old name of a renamed or transferred repository must not silently create a new one
+	if _, err := models.LookupRepoRedirect(owner.ID, repoName); err == nil {
+		return nil, models.ErrPushCreateRepoNotAllowed{OwnerName: owner.Name, RepoName: repoName, Reason: "the name redirects to another repository"}
+	} else if !models.IsErrRepoRedirectNotExist(err) {
+		return nil, err
+	}
+
 	repo, err := CreateRepository(authUser, owner, models.CreateRepoOptions{
-		Name:      repoName,
-		IsPrivate: cfg.Repository.DefaultPushCreatePrivate,
+		Name:        repoName,
+		IsPrivate:   cfg.Repository.DefaultPushCreatePrivate || owner.RequirePrivateRepos,
+		CreatedFrom: models.RepoCreatedFromPushed,
 	})
 	if err != nil {
 		return nil, err
@@ -96,7 +110,30 @@ func PushCreateRepo(authUser, owner *models.User, repoName string) (*models.Repo
 	return repo, nil
 }
 
+// IsErrPushCreateRepoRejected returns true if err explains why PushCreateRepo refused to create the repository
+func IsErrPushCreateRepoRejected(err error) bool {
+	return models.IsErrPushCreateRepoNotAllowed(err) ||
+		models.IsErrReachLimitOfRepo(err) ||
+		models.IsErrRepoAlreadyExist(err) ||
+		models.IsErrNameReserved(err) ||
+		models.IsErrNamePatternNotAllowed(err) ||
+		models.IsErrNameCharsNotAllowed(err) ||
+		models.IsErrNameTooLong(err)
+}
+
+// PushCreateRepoRejectedReason returns why PushCreateRepo refused to create the repository,
+// without repeating the repository name of an ErrPushCreateRepoNotAllowed
+func PushCreateRepoRejectedReason(err error) string {
+	if errNotAllowed, ok := err.(models.ErrPushCreateRepoNotAllowed); ok {
+		return errNotAllowed.Reason
+	}
+	return err.Error()
+}
+
 // NewContext start repository service
 func NewContext() error {
+	if err := repo_module.InitSizeQueue(); err != nil {
+		return err
+	}
 	return initPushQueue()
 }
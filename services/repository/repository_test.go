@@ -0,0 +1,73 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPushCreateRepo(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user2 := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+	user4 := db.AssertExistsAndLoadBean(t, &models.User{ID: 4}).(*models.User)
+	org3 := db.AssertExistsAndLoadBean(t, &models.User{ID: 3}).(*models.User)
+
+	// Another user's namespace
+	_, err := PushCreateRepo(user4, user2, "push-created")
+	assert.True(t, models.IsErrPushCreateRepoNotAllowed(err))
+
+	// Organization the user can't create repositories in
+	_, err = PushCreateRepo(user4, org3, "push-created")
+	assert.True(t, models.IsErrPushCreateRepoNotAllowed(err))
+
+	// Old name of a renamed repository
+	_, err = PushCreateRepo(user2, user2, "oldrepo1")
+	assert.True(t, models.IsErrPushCreateRepoNotAllowed(err))
+	assert.NotContains(t, PushCreateRepoRejectedReason(err), "oldrepo1")
+
+	// Reserved names
+	_, err = PushCreateRepo(user2, user2, "push-created.git")
+	assert.True(t, models.IsErrNamePatternNotAllowed(err))
+
+	// Too long names
+	_, err = PushCreateRepo(user2, user2, strings.Repeat("a", setting.Repository.MaxNameLength+1))
+	assert.True(t, IsErrPushCreateRepoRejected(err))
+
+	// Repository limit
+	limited := *user2
+	limited.MaxRepoCreation = 0
+	_, err = PushCreateRepo(&limited, &limited, "push-created")
+	assert.True(t, models.IsErrReachLimitOfRepo(err))
+
+	for _, err := range []error{
+		models.ErrPushCreateRepoNotAllowed{},
+		models.ErrReachLimitOfRepo{},
+		models.ErrNameReserved{},
+		models.ErrNameTooLong{},
+	} {
+		assert.True(t, IsErrPushCreateRepoRejected(err))
+	}
+
+	defer func(private bool) {
+		setting.Repository.DefaultPushCreatePrivate = private
+	}(setting.Repository.DefaultPushCreatePrivate)
+	setting.Repository.DefaultPushCreatePrivate = true
+
+	repo, err := PushCreateRepo(user2, user2, "push-created")
+	assert.NoError(t, err)
+	assert.True(t, repo.IsPrivate)
+	db.AssertExistsAndLoadBean(t, &models.Repository{OwnerID: user2.ID, LowerName: "push-created", CreatedFrom: models.RepoCreatedFromPushed})
+
+	_, err = PushCreateRepo(user2, user2, "push-created")
+	assert.True(t, models.IsErrRepoAlreadyExist(err))
+}
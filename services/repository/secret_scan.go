@@ -0,0 +1,46 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/secretscan"
+)
+
+// ScanPushedSecrets scans the lines added by the commits of a push against the secret scan rules and returns
+// the lines which look like secrets. Like for push rules, only the commits which are not yet reachable from
+// any ref of the repository are inspected, the others were scanned when they were pushed. The files matched
+// by ignore are skipped.
+func ScanPushedSecrets(repoPath, newCommitID string, ignore *secretscan.Ignore, env []string) ([]*secretscan.Finding, error) {
+	if git.IsEmptyCommitID(newCommitID) {
+		return nil, nil
+	}
+	rules, err := secretscan.Rules()
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	stdoutReader, stdoutWriter := io.Pipe()
+	defer stdoutReader.Close()
+	go func() {
+		stderr := new(bytes.Buffer)
+		// merge commits have no patch, the files they change were scanned in the merged commits
+		err := git.NewCommand("log", "-p", "-U0", "--no-color", "--no-ext-diff", "--format=commit %H", newCommitID, "--not", "--all").
+			RunInDirTimeoutEnvFullPipeline(env, -1, repoPath, stdoutWriter, stderr, nil)
+		if err != nil {
+			err = fmt.Errorf("log: %v - %s", err, stderr)
+		}
+		_ = stdoutWriter.CloseWithError(err)
+	}()
+
+	return secretscan.ScanPatch(stdoutReader, rules, ignore)
+}
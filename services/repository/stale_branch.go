@@ -0,0 +1,168 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// StaleBranchOptions are the criteria a stale branch has to meet
+type StaleBranchOptions struct {
+	// only branches merged into the default branch
+	Merged bool
+	// only branches whose tip is older, 0 means any age
+	OlderThan time.Duration
+}
+
+// StaleBranch represents a branch meeting the StaleBranchOptions
+type StaleBranch struct {
+	Name   string
+	Commit *git.Commit
+	Merged bool
+}
+
+// FindStaleBranches returns the branches of a repository meeting the options.
+// The default branch, protected branches and branches of open pull requests are never stale.
+func FindStaleBranches(repo *models.Repository, gitRepo *git.Repository, opts StaleBranchOptions) ([]*StaleBranch, error) {
+	names, _, err := gitRepo.GetBranches(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("GetBranches: %v", err)
+	}
+	mergedNames, err := gitRepo.GetMergedBranches(repo.DefaultBranch)
+	if err != nil {
+		return nil, fmt.Errorf("GetMergedBranches: %v", err)
+	}
+	merged := make(map[string]bool, len(mergedNames))
+	for _, name := range mergedNames {
+		merged[name] = true
+	}
+	protectedBranches, err := repo.GetProtectedBranches()
+	if err != nil {
+		return nil, fmt.Errorf("GetProtectedBranches: %v", err)
+	}
+	protected := make(map[string]bool, len(protectedBranches))
+	for _, pb := range protectedBranches {
+		protected[pb.BranchName] = true
+	}
+
+	deadline := time.Now().Add(-opts.OlderThan)
+	stale := make([]*StaleBranch, 0, 10)
+	for _, name := range names {
+		if name == repo.DefaultBranch || protected[name] || (opts.Merged && !merged[name]) {
+			continue
+		}
+
+		commit, err := gitRepo.GetBranchCommit(name)
+		if err != nil {
+			return nil, fmt.Errorf("GetBranchCommit[%s]: %v", name, err)
+		}
+		if opts.OlderThan > 0 && !commit.Committer.When.Before(deadline) {
+			continue
+		}
+
+		if hasPulls, err := hasUnmergedPullRequests(repo.ID, name); err != nil {
+			return nil, err
+		} else if hasPulls {
+			continue
+		}
+
+		stale = append(stale, &StaleBranch{
+			Name:   name,
+			Commit: commit,
+			Merged: merged[name],
+		})
+	}
+	return stale, nil
+}
+
+func hasUnmergedPullRequests(repoID int64, branch string) (bool, error) {
+	prs, err := models.GetUnmergedPullRequestsByHeadInfo(repoID, branch)
+	if err != nil {
+		return false, fmt.Errorf("GetUnmergedPullRequestsByHeadInfo: %v", err)
+	} else if len(prs) > 0 {
+		return true, nil
+	}
+	prs, err = models.GetUnmergedPullRequestsByBaseInfo(repoID, branch)
+	if err != nil {
+		return false, fmt.Errorf("GetUnmergedPullRequestsByBaseInfo: %v", err)
+	}
+	return len(prs) > 0, nil
+}
+
+// CleanupStaleBranches deletes the stale branches of all repositories which enabled the scheduled cleanup
+func CleanupStaleBranches(ctx context.Context) error {
+	cleanups, err := models.FindEnabledStaleBranchCleanups()
+	if err != nil {
+		return err
+	}
+
+	for _, cleanup := range cleanups {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before cleaning up stale branches of repository %d", cleanup.RepoID)
+		default:
+		}
+		if err := cleanupStaleBranches(cleanup); err != nil {
+			log.Error("Failed to clean up stale branches of repository %d: %v", cleanup.RepoID, err)
+		}
+	}
+	return nil
+}
+
+func cleanupStaleBranches(cleanup *models.StaleBranchCleanup) error {
+	if !cleanup.HasCriteria() {
+		return nil
+	}
+
+	repo, err := models.GetRepositoryByID(cleanup.RepoID)
+	if err != nil {
+		return err
+	}
+	if repo.IsArchived || repo.IsMirror || repo.IsEmpty {
+		return nil
+	}
+	if err := repo.GetOwner(); err != nil {
+		return err
+	}
+
+	doer, err := models.GetUserByID(cleanup.DoerID)
+	if models.IsErrUserNotExist(err) {
+		doer = repo.Owner
+	} else if err != nil {
+		return err
+	}
+
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	if err != nil {
+		return err
+	}
+	defer gitRepo.Close()
+
+	branches, err := FindStaleBranches(repo, gitRepo, StaleBranchOptions{
+		Merged:    cleanup.Merged,
+		OlderThan: cleanup.OlderThan(),
+	})
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(branches))
+	for _, branch := range branches {
+		if !cleanup.DryRun {
+			if err := DeleteBranch(doer, repo, gitRepo, branch.Name); err != nil {
+				log.Error("DeleteBranch[%s] of repository %d: %v", branch.Name, repo.ID, err)
+				continue
+			}
+		}
+		names = append(names, branch.Name)
+	}
+	return models.UpdateStaleBranchCleanupRun(cleanup, names)
+}
@@ -0,0 +1,72 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindStaleBranches(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	repo := db.AssertExistsAndLoadBean(t, &models.Repository{ID: 1}).(*models.Repository)
+	gitRepo, err := git.OpenRepository(repo.RepoPath())
+	assert.NoError(t, err)
+	defer gitRepo.Close()
+
+	names := func(branches []*StaleBranch) []string {
+		result := make([]string, 0, len(branches))
+		for _, branch := range branches {
+			result = append(result, branch.Name)
+		}
+		return result
+	}
+
+	// branch2 and pr-to-update have open pull requests
+	branches, err := FindStaleBranches(repo, gitRepo, StaleBranchOptions{Merged: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DefaultBranch", "develop", "feature/1"}, names(branches))
+
+	branches, err = FindStaleBranches(repo, gitRepo, StaleBranchOptions{OlderThan: 90 * 24 * time.Hour})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DefaultBranch", "develop", "feature/1"}, names(branches))
+
+	branches, err = FindStaleBranches(repo, gitRepo, StaleBranchOptions{OlderThan: 100 * 365 * 24 * time.Hour})
+	assert.NoError(t, err)
+	assert.Empty(t, branches)
+
+	assert.NoError(t, db.Insert(db.DefaultContext, &models.ProtectedBranch{RepoID: repo.ID, BranchName: "develop"}))
+	branches, err = FindStaleBranches(repo, gitRepo, StaleBranchOptions{Merged: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"DefaultBranch", "feature/1"}, names(branches))
+}
+
+func TestCleanupStaleBranches(t *testing.T) {
+	db.PrepareTestEnv(t)
+
+	cleanup, err := models.GetStaleBranchCleanup(1)
+	assert.NoError(t, err)
+	assert.True(t, cleanup.DryRun)
+	cleanup.Enabled = true
+	cleanup.Merged = true
+	cleanup.DoerID = 2
+	assert.NoError(t, models.UpdateStaleBranchCleanup(cleanup))
+
+	// the first run only records the branches it would delete
+	assert.NoError(t, CleanupStaleBranches(db.DefaultContext))
+	cleanup, err = models.GetStaleBranchCleanup(1)
+	assert.NoError(t, err)
+	assert.True(t, cleanup.LastRunDryRun)
+	assert.False(t, cleanup.DryRun)
+	assert.Equal(t, []string{"DefaultBranch", "develop", "feature/1"}, cleanup.LastRunBranches)
+	assert.True(t, git.IsBranchExist(models.RepoPath("user2", "repo1"), "develop"))
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// cacheKey is the key of the latest statistics in the cache,
+// so all instances sharing the cache serve the same statistics
+const cacheKey = "statistics"
+
+// Statistics are the aggregate counts of the database as of a point in time
+type Statistics struct {
+	models.Statistic
+	AsOf time.Time
+}
+
+var (
+	// refreshLock makes concurrent refreshes count the statistics only once
+	refreshLock sync.Mutex
+
+	// latest are the statistics of the last refresh of this instance, in case there is no cache
+	latest     *Statistics
+	latestLock sync.RWMutex
+)
+
+// Refresh counts the statistics and stores them until the next refresh
+func Refresh() *Statistics {
+	refreshLock.Lock()
+	defer refreshLock.Unlock()
+
+	stats := &Statistics{
+		Statistic: models.GetStatistic(),
+		AsOf:      time.Now(),
+	}
+
+	latestLock.Lock()
+	latest = stats
+	latestLock.Unlock()
+
+	if conn := cache.GetCache(); conn != nil {
+		data, err := json.Marshal(stats)
+		if err != nil {
+			log.Error("Unable to marshal statistics: %v", err)
+		} else if err := conn.Put(cacheKey, string(data), 0); err != nil {
+			log.Error("Unable to cache statistics: %v", err)
+		}
+	}
+	return stats
+}
+
+// Get returns the latest statistics, they are counted first if they have never been refreshed
+func Get() *Statistics {
+	if conn := cache.GetCache(); conn != nil {
+		if data, ok := conn.Get(cacheKey).(string); ok {
+			stats := &Statistics{}
+			if err := json.Unmarshal([]byte(data), stats); err == nil {
+				return stats
+			}
+			log.Error("Unable to unmarshal cached statistics: %s", data)
+		}
+	}
+
+	latestLock.RLock()
+	stats := latest
+	latestLock.RUnlock()
+	if stats != nil {
+		return stats
+	}
+	return Refresh()
+}
@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package stats
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatistics(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	stats := Get()
+	assert.False(t, stats.AsOf.IsZero())
+	assert.EqualValues(t, db.GetCount(t, &models.Repository{}), stats.Counter.Repo)
+
+	// the cached counts are served until the next refresh
+	_, err := db.GetEngine(db.DefaultContext).ID(1).Delete(&models.Repository{})
+	assert.NoError(t, err)
+	assert.Equal(t, stats.Counter.Repo, Get().Counter.Repo)
+
+	refreshed := Refresh()
+	assert.EqualValues(t, stats.Counter.Repo-1, refreshed.Counter.Repo)
+	assert.False(t, refreshed.AsOf.Before(stats.AsOf))
+	assert.Equal(t, refreshed.Counter.Repo, Get().Counter.Repo)
+}
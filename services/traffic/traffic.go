@@ -0,0 +1,211 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package traffic
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// HistoryDays is the number of days of traffic History returns
+const HistoryDays = 14
+
+// maxVisitors limits the visitors remembered per repository, kind and day,
+// the uniques of busier repositories are underestimated
+const maxVisitors = 10000
+
+type counterKey struct {
+	repoID int64
+	kind   models.RepoTrafficKind
+	day    timeutil.TimeStamp
+}
+
+type counter struct {
+	// count is the number of hits since the last flush
+	count int64
+	// visitors are all visitors of the day seen by this instance
+	visitors map[string]struct{}
+}
+
+var (
+	countersLock sync.Mutex
+	counters     = make(map[counterKey]*counter)
+)
+
+// Init starts writing the counted traffic to the database periodically
+func Init() {
+	if !setting.Repository.Traffic.Enabled {
+		return
+	}
+	go graceful.GetManager().RunWithShutdownContext(run)
+}
+
+func run(ctx context.Context) {
+	ticker := time.NewTicker(setting.Repository.Traffic.FlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			Flush()
+			return
+		case <-ticker.C:
+			Flush()
+		}
+	}
+}
+
+// Visitor identifies the visitor of a repository by its user or its remote address
+func Visitor(user *models.User, remoteAddr string) string {
+	if user != nil {
+		return "user:" + strconv.FormatInt(user.ID, 10)
+	}
+	return "addr:" + remoteAddr
+}
+
+// Record counts a clone or a view of a repository by the visitor, which identifies
+// the user or the remote address. It is only kept in memory until the next flush.
+func Record(repoID int64, kind models.RepoTrafficKind, visitor string) {
+	if !setting.Repository.Traffic.Enabled {
+		return
+	}
+
+	key := counterKey{repoID: repoID, kind: kind, day: models.RepoTrafficDayStart(time.Now())}
+
+	countersLock.Lock()
+	defer countersLock.Unlock()
+
+	c, ok := counters[key]
+	if !ok {
+		c = &counter{visitors: make(map[string]struct{})}
+		counters[key] = c
+	}
+	c.count++
+	if len(c.visitors) < maxVisitors {
+		c.visitors[visitor] = struct{}{}
+	}
+}
+
+// Flush writes the traffic counted since the last flush to the database
+func Flush() {
+	today := models.RepoTrafficDayStart(time.Now())
+
+	countersLock.Lock()
+	traffic := make([]*models.RepoTraffic, 0, len(counters))
+	for key, c := range counters {
+		if c.count > 0 {
+			traffic = append(traffic, &models.RepoTraffic{
+				RepoID:    key.repoID,
+				Kind:      key.kind,
+				Period:    models.RepoTrafficDay,
+				StartUnix: key.day,
+				Count:     c.count,
+				Uniques:   int64(len(c.visitors)),
+			})
+			c.count = 0
+		}
+		if key.day < today {
+			// the visitors of past days are not needed anymore
+			delete(counters, key)
+		}
+	}
+	countersLock.Unlock()
+
+	if len(traffic) == 0 {
+		return
+	}
+	if err := models.AddRepoTraffic(traffic); err != nil {
+		log.Error("Unable to store the traffic of %d repositories: %v", len(traffic), err)
+	}
+}
+
+// History returns the traffic of a kind of a repository of the last HistoryDays days including today,
+// per day or per week, oldest first. The traffic which has not been flushed yet is included.
+func History(repoID int64, kind models.RepoTrafficKind, period models.RepoTrafficPeriod) ([]*models.RepoTraffic, error) {
+	now := time.Now().UTC()
+	since := models.RepoTrafficDayStart(now.AddDate(0, 0, 1-HistoryDays))
+	stored, err := models.GetRepoTraffic(repoID, kind, since)
+	if err != nil {
+		return nil, err
+	}
+
+	days := make([]*models.RepoTraffic, HistoryDays)
+	for i := range days {
+		days[i] = &models.RepoTraffic{
+			RepoID:    repoID,
+			Kind:      kind,
+			Period:    models.RepoTrafficDay,
+			StartUnix: models.RepoTrafficDayStart(now.AddDate(0, 0, i+1-HistoryDays)),
+		}
+	}
+	dayIndex := func(day timeutil.TimeStamp) int {
+		for i := range days {
+			if days[i].StartUnix == day {
+				return i
+			}
+		}
+		return -1
+	}
+	for _, t := range stored {
+		if i := dayIndex(t.StartUnix); i >= 0 {
+			days[i].Count = t.Count
+			days[i].Uniques = t.Uniques
+		}
+	}
+
+	countersLock.Lock()
+	for key, c := range counters {
+		if key.repoID != repoID || key.kind != kind {
+			continue
+		}
+		if i := dayIndex(key.day); i >= 0 {
+			days[i].Count += c.count
+			if uniques := int64(len(c.visitors)); uniques > days[i].Uniques {
+				days[i].Uniques = uniques
+			}
+		}
+	}
+	countersLock.Unlock()
+
+	if period == models.RepoTrafficDay {
+		return days, nil
+	}
+
+	weeks := make([]*models.RepoTraffic, 0, HistoryDays/7+1)
+	for _, day := range days {
+		start := models.RepoTrafficWeekStart(day.StartUnix.AsTime())
+		if len(weeks) == 0 || weeks[len(weeks)-1].StartUnix != start {
+			weeks = append(weeks, &models.RepoTraffic{
+				RepoID:    repoID,
+				Kind:      kind,
+				Period:    models.RepoTrafficWeek,
+				StartUnix: start,
+			})
+		}
+		weeks[len(weeks)-1].Count += day.Count
+		weeks[len(weeks)-1].Uniques += day.Uniques
+	}
+	return weeks, nil
+}
+
+// Aggregate rolls the daily traffic up per week after the daily retention
+// and deletes the traffic after the retention
+func Aggregate(ctx context.Context) error {
+	now := time.Now().UTC()
+	if err := models.RollUpRepoTraffic(models.RepoTrafficDayStart(now.AddDate(0, 0, -setting.Repository.Traffic.DailyRetentionDays))); err != nil {
+		return err
+	}
+	if setting.Repository.Traffic.RetentionDays <= 0 {
+		return nil
+	}
+	return models.DeleteRepoTrafficBefore(models.RepoTrafficWeekStart(now.AddDate(0, 0, -setting.Repository.Traffic.RetentionDays)))
+}
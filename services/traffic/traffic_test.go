@@ -0,0 +1,50 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package traffic
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistory(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	Record(1, models.RepoTrafficClone, "user:2")
+	Record(1, models.RepoTrafficClone, "user:2")
+	Record(1, models.RepoTrafficClone, "addr:127.0.0.1")
+	Record(1, models.RepoTrafficView, "user:2")
+
+	// the traffic which is not flushed yet is included
+	days, err := History(1, models.RepoTrafficClone, models.RepoTrafficDay)
+	assert.NoError(t, err)
+	assert.Len(t, days, HistoryDays)
+	today := days[len(days)-1]
+	assert.EqualValues(t, models.RepoTrafficDayStart(time.Now()), today.StartUnix)
+	assert.EqualValues(t, 3, today.Count)
+	assert.EqualValues(t, 2, today.Uniques)
+
+	Flush()
+	db.AssertExistsAndLoadBean(t, &models.RepoTraffic{RepoID: 1, Kind: models.RepoTrafficClone, StartUnix: today.StartUnix, Count: 3, Uniques: 2})
+	db.AssertExistsAndLoadBean(t, &models.RepoTraffic{RepoID: 1, Kind: models.RepoTrafficView, StartUnix: today.StartUnix, Count: 1, Uniques: 1})
+
+	// the visitors of the day are still remembered after the flush
+	Record(1, models.RepoTrafficClone, "user:2")
+	days, err = History(1, models.RepoTrafficClone, models.RepoTrafficDay)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, days[len(days)-1].Count)
+	assert.EqualValues(t, 2, days[len(days)-1].Uniques)
+
+	weeks, err := History(1, models.RepoTrafficClone, models.RepoTrafficWeek)
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, len(weeks), 2)
+	assert.EqualValues(t, 4, weeks[len(weeks)-1].Count)
+	assert.EqualValues(t, models.RepoTrafficWeekStart(time.Now()), weeks[len(weeks)-1].StartUnix)
+}
@@ -0,0 +1,66 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// NewUser is a user created by CreateUsers
+type NewUser struct {
+	User             *models.User
+	OverwriteDefault *models.CreateUserOverwriteOptions
+	// checked SSH public keys of the user
+	PublicKeys []string
+}
+
+// CreateUsers creates users in bulk and adds their SSH public keys, rewriting the authorized_keys file only once
+// at the end. It returns an error for each user which could not be created, the keys which can't be added are
+// skipped. If sendNotify is set, the registration notification mails are sent to the created users in the background.
+func CreateUsers(users []*NewUser, sendNotify bool) []error {
+	us := make([]*models.User, len(users))
+	overwriteDefaults := make([]*models.CreateUserOverwriteOptions, len(users))
+	for i, u := range users {
+		us[i] = u.User
+		overwriteDefaults[i] = u.OverwriteDefault
+	}
+	errs := models.CreateUsers(us, overwriteDefaults)
+
+	created := make([]*models.User, 0, len(users))
+	var keysAdded bool
+	for i, u := range users {
+		if errs[i] != nil {
+			continue
+		}
+		created = append(created, u.User)
+		for j, content := range u.PublicKeys {
+			name := fmt.Sprintf("imported-key-%d", j+1)
+			if _, err := models.AddPublicKeyWithoutAuthorizedKeys(u.User.ID, name, content, 0); err != nil {
+				log.Warn("Unable to add the SSH public key %s of the imported user %s: %v", name, u.User.Name, err)
+				continue
+			}
+			keysAdded = true
+		}
+	}
+
+	if keysAdded {
+		if err := models.RewriteAllPublicKeys(); err != nil {
+			log.Error("RewriteAllPublicKeys: %v", err)
+		}
+	}
+
+	if sendNotify && len(created) > 0 {
+		go func() {
+			for _, u := range created {
+				mailer.SendRegisterNotifyMail(u)
+			}
+		}()
+	}
+	return errs
+}
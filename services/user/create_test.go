@@ -0,0 +1,32 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateUsers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	key, err := models.CheckPublicKeyString("ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQDMZXh+1OBUwSH9D45wTaxErQIN9IoC9xl7MKJkqvTvv6O5RR9YW/IK9FbfjXgXsppYGhsCZo1hFOOsXHMnfOORqu/xMDx4yPuyvKpw4LePEcg4TDipaDFuxbWOqc/BUZRZcXu41QAWfDLrInwsltWZHSeG7hjhpacl4FrVv9V1pS6Oc5Q1NxxEzTzuNLS/8diZrTm/YAQQ/+B+mzWI3zEtF4miZjjAljWd1LTBPvU23d29DcBmmFahcZ441XZsTeAwGxG/Q6j8NgNXj9WxMeWwxXV2jeAX/EBSpZrCVlCQ1yJswT6xCp8TuBnTiGWYMBNTbOZvPC4e0WI2/yZW/s5F nocomment")
+	assert.NoError(t, err)
+
+	users := []*NewUser{
+		{User: &models.User{Name: "imported1", Email: "imported1@example.com", Passwd: ".//.;1;;//.,-=_"}, PublicKeys: []string{key}},
+		{User: &models.User{Name: "imported2", Email: "user2@example.com", Passwd: ".//.;1;;//.,-=_"}, PublicKeys: []string{key}},
+	}
+	errs := CreateUsers(users, false)
+	assert.NoError(t, errs[0])
+	assert.True(t, models.IsErrEmailAlreadyUsed(errs[1]))
+
+	db.AssertExistsAndLoadBean(t, &models.PublicKey{OwnerID: users[0].User.ID, Name: "imported-key-1"})
+	assert.Zero(t, users[1].User.ID)
+}
@@ -0,0 +1,128 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// DeactivateInactiveUsers warns the users who have not signed in for inactiveFor less notifyBefore that their
+// accounts are about to be deactivated, and prohibits the login of those who have not signed in for inactiveFor
+// once they have been warned for notifyBefore. If deleteAfter is set, the users who have been deactivated as
+// inactive for deleteAfter and were not reactivated by an admin are deleted.
+// Admins, service accounts and the only owners of organizations are exempt.
+func DeactivateInactiveUsers(ctx context.Context, inactiveFor, notifyBefore, deleteAfter time.Duration) error {
+	if inactiveFor <= 0 {
+		return fmt.Errorf("the inactivity period must be positive")
+	}
+	if notifyBefore > inactiveFor {
+		notifyBefore = inactiveFor
+	}
+	now := time.Now()
+
+	users, err := models.FindUsersInactiveSince(timeutil.TimeStamp(now.Add(notifyBefore - inactiveFor).Unix()))
+	if err != nil {
+		return fmt.Errorf("FindUsersInactiveSince: %v", err)
+	}
+	for _, u := range users {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before checking the inactivity of user %s", u.Name)
+		default:
+		}
+
+		if err := deactivateInactiveUser(u, now, inactiveFor, notifyBefore); err != nil {
+			return err
+		}
+	}
+
+	if deleteAfter <= 0 {
+		return nil
+	}
+	users, err = models.FindUsersDeactivatedAsInactiveBefore(timeutil.TimeStamp(now.Add(-deleteAfter).Unix()))
+	if err != nil {
+		return fmt.Errorf("FindUsersDeactivatedAsInactiveBefore: %v", err)
+	}
+	for _, u := range users {
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before deleting inactive user %s", u.Name)
+		default:
+		}
+
+		if err := deleteInactiveUser(u); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deactivateInactiveUser warns the user or deactivates the account of a user who has already been warned
+// for notifyBefore since the last activity
+func deactivateInactiveUser(u *models.User, now time.Time, inactiveFor, notifyBefore time.Duration) error {
+	if soleOwner, err := u.IsSoleOwnerOfOrgs(); err != nil {
+		return fmt.Errorf("IsSoleOwnerOfOrgs: %v", err)
+	} else if soleOwner {
+		return nil
+	}
+
+	lastActivity := u.LastActivityUnix().AsTime()
+	warned := u.InactivityWarnedUnix > 0 && u.InactivityWarnedUnix >= u.LastActivityUnix()
+	if !warned {
+		// users are warned for at least notifyBefore, whenever the warning is sent
+		deactivateAt := lastActivity.Add(inactiveFor)
+		if minDeactivateAt := now.Add(notifyBefore); deactivateAt.Before(minDeactivateAt) {
+			deactivateAt = minDeactivateAt
+		}
+		if err := mailer.SendInactiveUserWarningMail(u, deactivateAt); err != nil {
+			log.Error("SendInactiveUserWarningMail[%d]: %v", u.ID, err)
+		}
+		u.InactivityWarnedUnix = timeutil.TimeStamp(now.Unix())
+		if err := models.UpdateUserCols(u, "inactivity_warned_unix"); err != nil {
+			return fmt.Errorf("UpdateUserCols: %v", err)
+		}
+		log.Info("Warned user %s about the deactivation of inactive users", u.Name)
+		return nil
+	}
+
+	if now.Before(lastActivity.Add(inactiveFor)) || now.Before(u.InactivityWarnedUnix.AsTime().Add(notifyBefore)) {
+		return nil
+	}
+	u.ProhibitLogin = true
+	u.InactivityDeactivatedUnix = timeutil.TimeStamp(now.Unix())
+	if err := models.UpdateUserCols(u, "prohibit_login", "inactivity_deactivated_unix"); err != nil {
+		return fmt.Errorf("UpdateUserCols: %v", err)
+	}
+	noticeInactiveUser("User %s has been deactivated as inactive since %s, an admin can allow the login again", u.Name, lastActivity.Format(time.RFC1123))
+	return nil
+}
+
+// deleteInactiveUser deletes a user deactivated as inactive, unless it owns repositories or belongs to organizations
+func deleteInactiveUser(u *models.User) error {
+	if err := models.DeleteUser(u); err != nil {
+		if models.IsErrUserOwnRepos(err) || models.IsErrUserHasOrgs(err) {
+			log.Info("Inactive user %s is not deleted: %v", u.Name, err)
+			return nil
+		}
+		return fmt.Errorf("DeleteUser: %v", err)
+	}
+	noticeInactiveUser("User %s has been deleted after being deactivated as inactive on %s", u.Name, u.InactivityDeactivatedUnix.AsTime().Format(time.RFC1123))
+	return nil
+}
+
+func noticeInactiveUser(format string, args ...interface{}) {
+	desc := fmt.Sprintf(format, args...)
+	log.Info("DeactivateInactiveUsers: %s", desc)
+	if err := models.CreateNotice(models.NoticeTask, desc); err != nil {
+		log.Error("CreateNotice: %v", err)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+	"xorm.io/builder"
+)
+
+func TestDeactivateInactiveUsers(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	loadUser := func(id int64) *models.User {
+		return db.AssertExistsAndLoadBean(t, &models.User{ID: id}).(*models.User)
+	}
+	run := func(deleteAfter time.Duration) {
+		assert.NoError(t, DeactivateInactiveUsers(context.Background(), time.Hour, 30*time.Minute, deleteAfter))
+	}
+
+	// the fixture users have never signed in, they are warned first
+	run(0)
+	user := loadUser(8)
+	assert.NotZero(t, user.InactivityWarnedUnix)
+	assert.False(t, user.ProhibitLogin)
+
+	// admins and the only owners of organizations are exempt
+	assert.Zero(t, loadUser(1).InactivityWarnedUnix)
+	assert.Zero(t, loadUser(2).InactivityWarnedUnix)
+
+	// users are deactivated once they have been warned long enough
+	run(0)
+	assert.False(t, loadUser(8).ProhibitLogin)
+	user.InactivityWarnedUnix = timeutil.TimeStamp(time.Now().Add(-time.Hour).Unix())
+	assert.NoError(t, models.UpdateUserCols(user, "inactivity_warned_unix"))
+	run(0)
+	user = loadUser(8)
+	assert.True(t, user.ProhibitLogin)
+	assert.NotZero(t, user.InactivityDeactivatedUnix)
+	db.AssertExistsAndLoadBean(t, &models.Notice{Type: models.NoticeTask}, builder.Like{"description", "User user8 has been deactivated"})
+
+	// users reactivated by an admin are not deactivated again right away
+	user.ProhibitLogin = false
+	assert.NoError(t, models.UpdateUserCols(user, "prohibit_login"))
+	run(time.Minute)
+	assert.False(t, loadUser(8).ProhibitLogin)
+
+	// service accounts are exempt
+	user = loadUser(4)
+	assert.NotZero(t, user.InactivityWarnedUnix)
+	user.IsServiceAccount = true
+	user.InactivityWarnedUnix = 0
+	assert.NoError(t, models.UpdateUserCols(user, "is_service_account", "inactivity_warned_unix"))
+	run(0)
+	assert.Zero(t, loadUser(4).InactivityWarnedUnix)
+
+	// deactivated users are deleted after deleteAfter, unless they own repositories
+	for _, id := range []int64{5, 8} {
+		user = loadUser(id)
+		user.ProhibitLogin = true
+		user.InactivityDeactivatedUnix = timeutil.TimeStamp(time.Now().Add(-2 * time.Hour).Unix())
+		assert.NoError(t, models.UpdateUserCols(user, "prohibit_login", "inactivity_deactivated_unix"))
+	}
+	run(3 * time.Hour)
+	loadUser(8)
+	run(time.Hour)
+	db.AssertNotExistsBean(t, &models.User{ID: 8})
+	loadUser(5)
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+)
+
+// AddPublicKey adds an SSH key to a user and notifies the change
+func AddPublicKey(doer, owner *models.User, name, content string) (*models.PublicKey, error) {
+	key, err := models.AddPublicKey(owner.ID, name, content, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	noticePublicKey(doer, owner, key, "added to")
+	notification.NotifyAddPublicKey(doer, owner, key)
+	return key, nil
+}
+
+// DeletePublicKey deletes an SSH key of a user and notifies the change
+func DeletePublicKey(doer *models.User, id int64) error {
+	key, err := models.GetPublicKeyByID(id)
+	if err != nil {
+		return err
+	}
+	if err := models.DeletePublicKey(doer, id); err != nil {
+		return err
+	}
+
+	owner, err := models.GetUserByID(key.OwnerID)
+	if err != nil {
+		log.Error("GetUserByID [%d]: %v", key.OwnerID, err)
+		return nil
+	}
+	noticePublicKey(doer, owner, key, "removed from")
+	notification.NotifyDeletePublicKey(doer, owner, key)
+	return nil
+}
+
+// noticePublicKey records the change of an SSH key as a system notice, without the content of the key
+func noticePublicKey(doer, owner *models.User, key *models.PublicKey, change string) {
+	if err := models.CreateNotice(models.NoticeSecurity, "SSH key %q (%s) %s user %s by %s",
+		key.Name, key.Fingerprint, change, owner.Name, doer.Name); err != nil {
+		log.Error("CreateNotice: %v", err)
+	}
+}
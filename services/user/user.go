@@ -0,0 +1,113 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// purgeBatchSize is the number of repositories of an organization loaded at once when it is purged
+const purgeBatchSize = 50
+
+// DeleteUser completely and permanently deletes everything of a user.
+// If purge is true, the organizations the user is the only member and owner of are deleted first,
+// together with their repositories. Deleting the user is refused if any other organization remains.
+func DeleteUser(ctx context.Context, u *models.User, purge bool) error {
+	if u.IsOrganization() {
+		return fmt.Errorf("%s is an organization not a user", u.Name)
+	}
+
+	if purge {
+		if err := deleteSoleOwnedOrgs(ctx, u); err != nil {
+			return err
+		}
+	}
+
+	return models.DeleteUser(u)
+}
+
+// soleOwnedOrgs returns the organizations of the user, or ErrUserHasOrgs if any of them has another member
+// or isn't owned by the user
+func soleOwnedOrgs(u *models.User) ([]*models.User, error) {
+	orgs, err := models.GetOrgsByUserID(u.ID, true)
+	if err != nil {
+		return nil, fmt.Errorf("GetOrgsByUserID: %v", err)
+	}
+	for _, org := range orgs {
+		count, err := models.CountOrgMembers(&models.FindOrgMembersOpts{OrgID: org.ID})
+		if err != nil {
+			return nil, fmt.Errorf("CountOrgMembers: %v", err)
+		}
+		if count > 1 {
+			return nil, models.ErrUserHasOrgs{UID: u.ID}
+		}
+		isOwner, err := models.IsOrganizationOwner(org.ID, u.ID)
+		if err != nil {
+			return nil, fmt.Errorf("IsOrganizationOwner: %v", err)
+		}
+		if !isOwner {
+			return nil, models.ErrUserHasOrgs{UID: u.ID}
+		}
+	}
+	return orgs, nil
+}
+
+// deleteSoleOwnedOrgs deletes the organizations of the user and their repositories one after another.
+// Nothing is deleted if any organization has another member.
+func deleteSoleOwnedOrgs(ctx context.Context, u *models.User) error {
+	orgs, err := soleOwnedOrgs(u)
+	if err != nil {
+		return err
+	}
+
+	for _, org := range orgs {
+		log.Info("Purging organization %s of user %s", org.Name, u.Name)
+
+		// Deleted repositories drop out of the listing, so the first page is fetched until it is empty
+		for {
+			repos, count, err := models.GetUserRepositories(&models.SearchRepoOptions{
+				ListOptions: db.ListOptions{Page: 1, PageSize: purgeBatchSize},
+				Actor:       org,
+				Private:     true,
+			})
+			if err != nil {
+				return fmt.Errorf("GetUserRepositories: %v", err)
+			}
+			if len(repos) == 0 {
+				break
+			}
+			log.Info("Deleting %d remaining repositories of organization %s", count, org.Name)
+
+			for _, repo := range repos {
+				select {
+				case <-ctx.Done():
+					return models.ErrCancelledf("before deleting repository %s", repo.FullName())
+				default:
+				}
+				if err := repo_service.DeleteRepository(u, repo); err != nil {
+					return fmt.Errorf("DeleteRepository %s: %v", repo.FullName(), err)
+				}
+				log.Info("Deleted repository %s", repo.FullName())
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return models.ErrCancelledf("before deleting organization %s", org.Name)
+		default:
+		}
+		if err := models.DeleteOrganization(org); err != nil {
+			return fmt.Errorf("DeleteOrganization %s: %v", org.Name, err)
+		}
+		log.Info("Deleted organization %s of user %s", org.Name, u.Name)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package user
+
+import (
+	"context"
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	repo_module "code.gitea.io/gitea/modules/repository"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeleteUserPurge(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	user := &models.User{Name: "purged-user", Email: "purged-user@example.com", Passwd: "password", IsAdmin: true}
+	assert.NoError(t, models.CreateUser(user))
+	org := &models.User{Name: "purged-org", Type: models.UserTypeOrganization}
+	assert.NoError(t, models.CreateOrganization(org, user))
+	repo, err := repo_module.CreateRepository(user, org, models.CreateRepoOptions{Name: "purged-repo"})
+	assert.NoError(t, err)
+
+	// without purging, the organization keeps the user from being deleted
+	err = DeleteUser(context.Background(), user, false)
+	assert.True(t, models.IsErrUserHasOrgs(err))
+
+	// another member keeps the organization from being purged
+	assert.NoError(t, org.AddMember(28))
+	err = DeleteUser(context.Background(), user, true)
+	assert.True(t, models.IsErrUserHasOrgs(err))
+	db.AssertExistsAndLoadBean(t, &models.User{ID: org.ID})
+	db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID})
+	assert.NoError(t, models.RemoveOrgUser(org.ID, 28))
+
+	// purging stops once cancelled
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = DeleteUser(ctx, user, true)
+	assert.True(t, models.IsErrCancelled(err))
+	db.AssertExistsAndLoadBean(t, &models.Repository{ID: repo.ID})
+
+	assert.NoError(t, DeleteUser(context.Background(), user, true))
+	db.AssertNotExistsBean(t, &models.User{ID: user.ID})
+	db.AssertNotExistsBean(t, &models.User{ID: org.ID})
+	db.AssertNotExistsBean(t, &models.Repository{ID: repo.ID})
+}
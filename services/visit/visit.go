@@ -0,0 +1,79 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package visit
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// flushInterval is the interval at which the visits are written to the database,
+// at most one row per user and repository is written by each flush
+const flushInterval = time.Minute
+
+type visitKey struct {
+	userID int64
+	repoID int64
+}
+
+var (
+	visitsLock sync.Mutex
+	visits     = make(map[visitKey]timeutil.TimeStamp)
+)
+
+// Init starts writing the recorded visits to the database periodically
+func Init() {
+	go graceful.GetManager().RunWithShutdownContext(run)
+}
+
+func run(ctx context.Context) {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			Flush()
+			return
+		case <-ticker.C:
+			Flush()
+		}
+	}
+}
+
+// Record remembers the visit of a signed-in user to the home page of a repository until the next flush,
+// the visits of anonymous users are not recorded
+func Record(user *models.User, repoID int64) {
+	if user == nil {
+		return
+	}
+
+	visitsLock.Lock()
+	visits[visitKey{userID: user.ID, repoID: repoID}] = timeutil.TimeStampNow()
+	visitsLock.Unlock()
+}
+
+// Flush writes the latest visits recorded since the last flush to the database
+func Flush() {
+	visitsLock.Lock()
+	pending := make([]*models.RepoVisit, 0, len(visits))
+	for key, visited := range visits {
+		pending = append(pending, &models.RepoVisit{UserID: key.userID, RepoID: key.repoID, VisitedUnix: visited})
+	}
+	visits = make(map[visitKey]timeutil.TimeStamp)
+	visitsLock.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	if err := models.AddRepoVisits(pending); err != nil {
+		log.Error("Unable to store %d repository visits: %v", len(pending), err)
+	}
+}
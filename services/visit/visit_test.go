@@ -0,0 +1,35 @@
+// Copyright 2021 The Gitea Authors. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package visit
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecord(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+	user := db.AssertExistsAndLoadBean(t, &models.User{ID: 2}).(*models.User)
+
+	Record(user, 1)
+	Record(user, 1)
+	Record(nil, 2)
+
+	// the visits are only written when flushed
+	db.AssertNotExistsBean(t, &models.RepoVisit{UserID: 2, RepoID: 1})
+	Flush()
+	assert.EqualValues(t, 1, db.GetCount(t, &models.RepoVisit{}))
+	v := db.AssertExistsAndLoadBean(t, &models.RepoVisit{UserID: 2, RepoID: 1}).(*models.RepoVisit)
+	assert.NotZero(t, v.VisitedUnix)
+
+	// the current session does not count as the last visit
+	lastVisit, err := models.GetRepoLastVisit(2, 1)
+	assert.NoError(t, err)
+	assert.Zero(t, lastVisit)
+}
@@ -29,6 +29,46 @@ import (
 	"github.com/gobwas/glob"
 )
 
+// signPayload returns the hex encoded HMAC-SHA1 and HMAC-SHA256 signatures of the payload
+func signPayload(secret, payload string) (signatureSHA1, signatureSHA256 string) {
+	if len(secret) == 0 {
+		return "", ""
+	}
+	sig1 := hmac.New(sha1.New, []byte(secret))
+	sig256 := hmac.New(sha256.New, []byte(secret))
+	if _, err := io.MultiWriter(sig1, sig256).Write([]byte(payload)); err != nil {
+		log.Error("prepareWebhooks.sigWrite: %v", err)
+	}
+	return hex.EncodeToString(sig1.Sum(nil)), hex.EncodeToString(sig256.Sum(nil))
+}
+
+// selectSignature returns the signature sent in the X-Gitea-Signature and X-Gogs-Signature headers.
+// Webhooks without an algorithm default to SHA256.
+func selectSignature(algorithm models.HookSignatureAlgorithm, signatureSHA1, signatureSHA256 string) string {
+	if algorithm == models.HookSignatureSHA1 {
+		return signatureSHA1
+	}
+	return signatureSHA256
+}
+
+// addSignatureHeaders adds the signatures of the payload made with the secrets of the webhook to req
+func addSignatureHeaders(req *http.Request, w *models.Webhook, payload string) {
+	signatureSHA1, signatureSHA256 := signPayload(w.Secret, payload)
+	signature := selectSignature(w.SignatureAlgorithm, signatureSHA1, signatureSHA256)
+	req.Header.Add("X-Gitea-Signature", signature)
+	req.Header.Add("X-Gogs-Signature", signature)
+	req.Header.Add("X-Hub-Signature", "sha1="+signatureSHA1)
+	req.Header.Add("X-Hub-Signature-256", "sha256="+signatureSHA256)
+
+	// While a rotated secret is in its grace period also sign with the previous secret,
+	// so receivers can accept either until they have switched to the new one.
+	if previousSecret := w.ActivePreviousSecret(); len(previousSecret) > 0 {
+		previousSignatureSHA1, previousSignatureSHA256 := signPayload(previousSecret, payload)
+		req.Header.Add("X-Gitea-Signature-Previous", selectSignature(w.SignatureAlgorithm, previousSignatureSHA1, previousSignatureSHA256))
+		req.Header.Add("X-Hub-Signature-256-Previous", "sha256="+previousSignatureSHA256)
+	}
+}
+
 // Deliver deliver hook task
 func Deliver(t *models.HookTask) error {
 	w, err := models.GetWebhookByID(t.HookID)
@@ -100,35 +140,22 @@ func Deliver(t *models.HookTask) error {
 		return fmt.Errorf("Invalid http method for webhook: [%d] %v", t.ID, w.HTTPMethod)
 	}
 
-	var signatureSHA1 string
-	var signatureSHA256 string
-	if len(w.Secret) > 0 {
-		sig1 := hmac.New(sha1.New, []byte(w.Secret))
-		sig256 := hmac.New(sha256.New, []byte(w.Secret))
-		_, err = io.MultiWriter(sig1, sig256).Write([]byte(t.PayloadContent))
-		if err != nil {
-			log.Error("prepareWebhooks.sigWrite: %v", err)
-		}
-		signatureSHA1 = hex.EncodeToString(sig1.Sum(nil))
-		signatureSHA256 = hex.EncodeToString(sig256.Sum(nil))
-	}
-
 	event := t.EventType.Event()
 	eventType := string(t.EventType)
 	req.Header.Add("X-Gitea-Delivery", t.UUID)
 	req.Header.Add("X-Gitea-Event", event)
 	req.Header.Add("X-Gitea-Event-Type", eventType)
-	req.Header.Add("X-Gitea-Signature", signatureSHA256)
 	req.Header.Add("X-Gogs-Delivery", t.UUID)
 	req.Header.Add("X-Gogs-Event", event)
 	req.Header.Add("X-Gogs-Event-Type", eventType)
-	req.Header.Add("X-Gogs-Signature", signatureSHA256)
-	req.Header.Add("X-Hub-Signature", "sha1="+signatureSHA1)
-	req.Header.Add("X-Hub-Signature-256", "sha256="+signatureSHA256)
 	req.Header["X-GitHub-Delivery"] = []string{t.UUID}
 	req.Header["X-GitHub-Event"] = []string{event}
 	req.Header["X-GitHub-Event-Type"] = []string{eventType}
 
+	// Sign at delivery time rather than when the task is queued, so that a changed secret
+	// or algorithm also applies to tasks which have not been delivered yet.
+	addSignatureHeaders(req, w, t.PayloadContent)
+
 	// Record delivery information.
 	t.RequestInfo = &models.HookRequest{
 		URL:        req.URL.String(),
@@ -9,7 +9,9 @@ import (
 	"net/url"
 	"testing"
 
+	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -37,3 +39,55 @@ func TestWebhookProxy(t *testing.T) {
 		}
 	}
 }
+
+func TestSignPayload(t *testing.T) {
+	sig1, sig256 := signPayload("", "payload")
+	assert.Empty(t, sig1)
+	assert.Empty(t, sig256)
+
+	sig1, sig256 = signPayload("secret", "payload")
+	assert.Len(t, sig1, 40)
+	assert.Equal(t, "b82fcb791acec57859b989b430a826488ce2e479fdf92326bd0a2e8375a42ba4", sig256)
+
+	other1, other256 := signPayload("other", "payload")
+	assert.NotEqual(t, sig1, other1)
+	assert.NotEqual(t, sig256, other256)
+}
+
+func TestSignPayloadReference(t *testing.T) {
+	// RFC 2202 and RFC 4231 test case 2
+	sig1, sig256 := signPayload("Jefe", "what do ya want for nothing?")
+	assert.Equal(t, "effcdf6ae5eb2fa2d27416d5f184df9c259a7c79", sig1)
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", sig256)
+
+	// example from GitHub's documentation on validating webhook deliveries
+	_, sig256 = signPayload("It's a Secret to Everybody", "Hello, World!")
+	assert.Equal(t, "757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17", sig256)
+}
+
+func TestAddSignatureHeaders(t *testing.T) {
+	w := &models.Webhook{Secret: "Jefe"}
+	req, err := http.NewRequest("POST", "http://localhost", nil)
+	assert.NoError(t, err)
+	addSignatureHeaders(req, w, "what do ya want for nothing?")
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", req.Header.Get("X-Gitea-Signature"))
+	assert.Equal(t, "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", req.Header.Get("X-Gogs-Signature"))
+	assert.Equal(t, "sha1=effcdf6ae5eb2fa2d27416d5f184df9c259a7c79", req.Header.Get("X-Hub-Signature"))
+	assert.Equal(t, "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", req.Header.Get("X-Hub-Signature-256"))
+	assert.Empty(t, req.Header.Get("X-Gitea-Signature-Previous"))
+
+	w.SignatureAlgorithm = models.HookSignatureSHA1
+	w.PreviousSecret = "Jefe"
+	w.PreviousSecretExpiresUnix = timeutil.TimeStampNow().Add(60)
+	w.Secret = "new secret"
+	req, err = http.NewRequest("POST", "http://localhost", nil)
+	assert.NoError(t, err)
+	addSignatureHeaders(req, w, "what do ya want for nothing?")
+	sig1, sig256 := signPayload("new secret", "what do ya want for nothing?")
+	assert.Equal(t, sig1, req.Header.Get("X-Gitea-Signature"))
+	assert.Equal(t, sig1, req.Header.Get("X-Gogs-Signature"))
+	assert.Equal(t, "sha1="+sig1, req.Header.Get("X-Hub-Signature"))
+	assert.Equal(t, "sha256="+sig256, req.Header.Get("X-Hub-Signature-256"))
+	assert.Equal(t, "effcdf6ae5eb2fa2d27416d5f184df9c259a7c79", req.Header.Get("X-Gitea-Signature-Previous"))
+	assert.Equal(t, "sha256=5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843", req.Header.Get("X-Hub-Signature-256-Previous"))
+}
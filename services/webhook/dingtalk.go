@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 
 	dingtalk "github.com/lunny/dingtalk_webhook"
@@ -168,6 +169,27 @@ func (d *DingtalkPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return createDingtalkPayload(text, text, "view release", p.Release.URL), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (d *DingtalkPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return createDingtalkPayload(text, text, "view activity", p.Repository.HTMLURL+"/activity"), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (d *DingtalkPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view deploy keys", p.Repository.HTMLURL+"/settings/keys"), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (d *DingtalkPayload) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	text, _ := getPublicKeyPayloadInfo(p, noneLinkFormatter, true)
+
+	return createDingtalkPayload(text, text, "view user", setting.AppURL+p.Owner.UserName), nil
+}
+
 func createDingtalkPayload(title, text, singleTitle, singleURL string) *DingtalkPayload {
 	return &DingtalkPayload{
 		MsgType: "actionCard",
@@ -243,6 +243,27 @@ func (d *DiscordPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return d.createPayload(p.Sender, text, p.Release.Note, p.Release.URL, color), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (d *DiscordPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return d.createPayload(p.Repository.Owner, text, "", p.Repository.HTMLURL+"/activity", greenColor), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (d *DiscordPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, color := getDeployKeyPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", p.Repository.HTMLURL+"/settings/keys", color), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (d *DiscordPayload) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	text, color := getPublicKeyPayloadInfo(p, noneLinkFormatter, false)
+
+	return d.createPayload(p.Sender, text, "", setting.AppURL+p.Owner.UserName, color), nil
+}
+
 // GetDiscordPayload converts a discord webhook into a DiscordPayload
 func GetDiscordPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	s := new(DiscordPayload)
@@ -154,6 +154,27 @@ func (f *FeishuPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	return newFeishuTextPayload(text), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (f *FeishuPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	text := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (f *FeishuPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (f *FeishuPayload) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	text, _ := getPublicKeyPayloadInfo(p, noneLinkFormatter, true)
+
+	return newFeishuTextPayload(text), nil
+}
+
 // GetFeishuPayload converts a ding talk webhook into a FeishuPayload
 func GetFeishuPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(FeishuPayload), p, event)
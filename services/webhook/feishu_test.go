@@ -156,6 +156,18 @@ func TestFeishuPayload(t *testing.T) {
 
 		assert.Equal(t, "[test/repo] Release created: v1.0 by user1", pl.(*FeishuPayload).Content.Text)
 	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(FeishuPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &FeishuPayload{}, pl)
+
+		assert.Equal(t, "[test/repo] Activity from 2021-09-01 to 2021-09-08: 3 issues opened, 1 issues closed, 2 pull requests opened, 1 pull requests merged, 1 contributors", pl.(*FeishuPayload).Content.Text)
+	})
 }
 
 func TestFeishuJSONPayload(t *testing.T) {
@@ -124,6 +124,14 @@ func getPullRequestPayloadInfo(p *api.PullRequestPayload, linkFormatter linkForm
 		text = fmt.Sprintf("[%s] Pull request milestone cleared: %s", repoLink, titleLink)
 	case api.HookIssueReviewed:
 		text = fmt.Sprintf("[%s] Pull request reviewed: %s", repoLink, titleLink)
+	case api.HookIssueReviewRequested:
+		if p.RequestedTeam != nil {
+			text = fmt.Sprintf("[%s] Pull request review requested from %s: %s", repoLink, p.RequestedTeam.Name, titleLink)
+		} else {
+			text = fmt.Sprintf("[%s] Pull request review requested: %s", repoLink, titleLink)
+		}
+	case api.HookIssueReadyForReview:
+		text = fmt.Sprintf("[%s] Pull request ready for review: %s", repoLink, titleLink)
 	}
 	if withSender {
 		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
@@ -159,6 +167,53 @@ func getReleasePayloadInfo(p *api.ReleasePayload, linkFormatter linkFormatter, w
 	return text, color
 }
 
+func getDigestPayloadInfo(p *api.DigestPayload, linkFormatter linkFormatter) string {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	return fmt.Sprintf("[%s] Activity from %s to %s: %d issues opened, %d issues closed, %d pull requests opened, %d pull requests merged, %d contributors",
+		repoLink, p.Since.Format("2006-01-02"), p.Until.Format("2006-01-02"),
+		p.IssuesOpened, p.IssuesClosed, p.PullRequestsOpened, p.PullRequestsMerged, len(p.Contributors))
+}
+
+func getDeployKeyPayloadInfo(p *api.DeployKeyPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
+	access := "read-only"
+	if !p.ReadOnly {
+		access = "read-write"
+	}
+
+	switch p.Action {
+	case api.HookKeyAdded:
+		text = fmt.Sprintf("[%s] %s deploy key added: %s (%s)", repoLink, access, p.Key.Title, p.Key.Fingerprint)
+		color = greenColor
+	case api.HookKeyRemoved:
+		text = fmt.Sprintf("[%s] %s deploy key removed: %s (%s)", repoLink, access, p.Key.Title, p.Key.Fingerprint)
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
+
+func getPublicKeyPayloadInfo(p *api.PublicKeyPayload, linkFormatter linkFormatter, withSender bool) (text string, color int) {
+	ownerLink := linkFormatter(setting.AppURL+p.Owner.UserName, p.Owner.UserName)
+
+	switch p.Action {
+	case api.HookKeyAdded:
+		text = fmt.Sprintf("[%s] SSH key added: %s (%s)", ownerLink, p.Key.Title, p.Key.Fingerprint)
+		color = greenColor
+	case api.HookKeyRemoved:
+		text = fmt.Sprintf("[%s] SSH key removed: %s (%s)", ownerLink, p.Key.Title, p.Key.Fingerprint)
+		color = redColor
+	}
+	if withSender {
+		text += fmt.Sprintf(" by %s", linkFormatter(setting.AppURL+p.Sender.UserName, p.Sender.UserName))
+	}
+
+	return text, color
+}
+
 func getIssueCommentPayloadInfo(p *api.IssueCommentPayload, linkFormatter linkFormatter, withSender bool) (string, string, int) {
 	repoLink := linkFormatter(p.Repository.HTMLURL, p.Repository.FullName)
 	issueTitle := fmt.Sprintf("#%d %s", p.Issue.Index, p.Issue.Title)
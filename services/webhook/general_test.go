@@ -6,6 +6,7 @@ package webhook
 
 import (
 	"testing"
+	"time"
 
 	api "code.gitea.io/gitea/modules/structs"
 
@@ -271,6 +272,75 @@ func repositoryTestPayload() *api.RepositoryPayload {
 	}
 }
 
+func digestTestPayload() *api.DigestPayload {
+	return &api.DigestPayload{
+		Since:              time.Date(2021, time.September, 1, 0, 0, 0, 0, time.UTC),
+		Until:              time.Date(2021, time.September, 8, 0, 0, 0, 0, time.UTC),
+		IssuesOpened:       3,
+		IssuesClosed:       1,
+		PullRequestsOpened: 2,
+		PullRequestsMerged: 1,
+		Contributors: []*api.DigestContributor{
+			{
+				User: &api.User{
+					UserName:  "user1",
+					AvatarURL: "http://localhost:3000/user1/avatar",
+				},
+				Actions: 5,
+			},
+		},
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+			Owner: &api.User{
+				UserName:  "test",
+				AvatarURL: "http://localhost:3000/test/avatar",
+			},
+		},
+	}
+}
+
+func deployKeyTestPayload() *api.DeployKeyPayload {
+	return &api.DeployKeyPayload{
+		Action: api.HookKeyAdded,
+		Key: &api.PayloadKey{
+			ID:          1,
+			Title:       "deploy",
+			Fingerprint: "SHA256:UU4xvdN5t1n2wG6k2JjiXNhmGgGZGt5ERqTaEygjvIU",
+		},
+		ReadOnly: true,
+		Repository: &api.Repository{
+			HTMLURL:  "http://localhost:3000/test/repo",
+			Name:     "repo",
+			FullName: "test/repo",
+		},
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+	}
+}
+
+func publicKeyTestPayload() *api.PublicKeyPayload {
+	return &api.PublicKeyPayload{
+		Action: api.HookKeyAdded,
+		Key: &api.PayloadKey{
+			ID:          1,
+			Title:       "laptop",
+			Fingerprint: "SHA256:UU4xvdN5t1n2wG6k2JjiXNhmGgGZGt5ERqTaEygjvIU",
+		},
+		Owner: &api.User{
+			UserName:  "user2",
+			AvatarURL: "http://localhost:3000/user2/avatar",
+		},
+		Sender: &api.User{
+			UserName:  "user1",
+			AvatarURL: "http://localhost:3000/user1/avatar",
+		},
+	}
+}
+
 func TestGetIssuesPayloadInfo(t *testing.T) {
 	p := issueTestPayload()
 
@@ -457,6 +527,13 @@ func TestGetPullRequestPayloadInfo(t *testing.T) {
 			"",
 			yellowColor,
 		},
+		{
+			api.HookIssueReadyForReview,
+			"[test/repo] Pull request ready for review: #12 Fix bug by user1",
+			"#12 Fix bug",
+			"",
+			yellowColor,
+		},
 	}
 
 	for i, c := range cases {
@@ -502,6 +579,33 @@ func TestGetReleasePayloadInfo(t *testing.T) {
 	}
 }
 
+func TestGetDeployKeyPayloadInfo(t *testing.T) {
+	p := deployKeyTestPayload()
+
+	text, color := getDeployKeyPayloadInfo(p, noneLinkFormatter, true)
+	assert.Equal(t, "[test/repo] read-only deploy key added: deploy (SHA256:UU4xvdN5t1n2wG6k2JjiXNhmGgGZGt5ERqTaEygjvIU) by user1", text)
+	assert.Equal(t, greenColor, color)
+
+	p.Action = api.HookKeyRemoved
+	p.ReadOnly = false
+	text, color = getDeployKeyPayloadInfo(p, noneLinkFormatter, false)
+	assert.Equal(t, "[test/repo] read-write deploy key removed: deploy (SHA256:UU4xvdN5t1n2wG6k2JjiXNhmGgGZGt5ERqTaEygjvIU)", text)
+	assert.Equal(t, redColor, color)
+}
+
+func TestGetPublicKeyPayloadInfo(t *testing.T) {
+	p := publicKeyTestPayload()
+
+	text, color := getPublicKeyPayloadInfo(p, noneLinkFormatter, true)
+	assert.Equal(t, "[user2] SSH key added: laptop (SHA256:UU4xvdN5t1n2wG6k2JjiXNhmGgGZGt5ERqTaEygjvIU) by user1", text)
+	assert.Equal(t, greenColor, color)
+
+	p.Action = api.HookKeyRemoved
+	text, color = getPublicKeyPayloadInfo(p, noneLinkFormatter, false)
+	assert.Equal(t, "[user2] SSH key removed: laptop (SHA256:UU4xvdN5t1n2wG6k2JjiXNhmGgGZGt5ERqTaEygjvIU)", text)
+	assert.Equal(t, redColor, color)
+}
+
 func TestGetIssueCommentPayloadInfo(t *testing.T) {
 	p := pullRequestCommentTestPayload()
 
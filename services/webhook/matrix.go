@@ -150,6 +150,27 @@ func (m *MatrixPayloadUnsafe) Release(p *api.ReleasePayload) (api.Payloader, err
 	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (m *MatrixPayloadUnsafe) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	text := getDigestPayloadInfo(p, MatrixLinkFormatter)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (m *MatrixPayloadUnsafe) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (m *MatrixPayloadUnsafe) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	text, _ := getPublicKeyPayloadInfo(p, MatrixLinkFormatter, true)
+
+	return getMatrixPayloadUnsafe(text, nil, m.AccessToken, m.MsgType), nil
+}
+
 // Push implements PayloadConvertor Push method
 func (m *MatrixPayloadUnsafe) Push(p *api.PushPayload) (api.Payloader, error) {
 	var commitDesc string
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -282,17 +283,64 @@ func (m *MSTeamsPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (m *MSTeamsPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	title := getDigestPayloadInfo(p, noneLinkFormatter)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Repository.Owner,
+		title,
+		"",
+		p.Repository.HTMLURL+"/activity",
+		greenColor,
+		nil,
+	), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (m *MSTeamsPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	title, color := getDeployKeyPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		p.Repository,
+		p.Sender,
+		title,
+		"",
+		p.Repository.HTMLURL+"/settings/keys",
+		color,
+		&MSTeamsFact{"Fingerprint:", p.Key.Fingerprint},
+	), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (m *MSTeamsPayload) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	title, color := getPublicKeyPayloadInfo(p, noneLinkFormatter, false)
+
+	return createMSTeamsPayload(
+		nil,
+		p.Sender,
+		title,
+		"",
+		setting.AppURL+p.Owner.UserName,
+		color,
+		&MSTeamsFact{"Fingerprint:", p.Key.Fingerprint},
+	), nil
+}
+
 // GetMSTeamsPayload converts a MSTeams webhook into a MSTeamsPayload
 func GetMSTeamsPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(MSTeamsPayload), p, event)
 }
 
 func createMSTeamsPayload(r *api.Repository, s *api.User, title, text, actionTarget string, color int, fact *MSTeamsFact) *MSTeamsPayload {
-	facts := []MSTeamsFact{
-		{
+	facts := make([]MSTeamsFact, 0, 2)
+	// the events of the SSH keys of users are not related to a repository
+	if r != nil {
+		facts = append(facts, MSTeamsFact{
 			Name:  "Repository:",
 			Value: r.FullName,
-		},
+		})
 	}
 	if fact != nil {
 		facts = append(facts, *fact)
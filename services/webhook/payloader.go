@@ -22,6 +22,9 @@ type PayloadConvertor interface {
 	Review(*api.PullRequestPayload, models.HookEventType) (api.Payloader, error)
 	Repository(*api.RepositoryPayload) (api.Payloader, error)
 	Release(*api.ReleasePayload) (api.Payloader, error)
+	Digest(*api.DigestPayload) (api.Payloader, error)
+	DeployKey(*api.DeployKeyPayload) (api.Payloader, error)
+	PublicKey(*api.PublicKeyPayload) (api.Payloader, error)
 }
 
 func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEventType) (api.Payloader, error) {
@@ -51,6 +54,12 @@ func convertPayloader(s PayloadConvertor, p api.Payloader, event models.HookEven
 		return s.Repository(p.(*api.RepositoryPayload))
 	case models.HookEventRelease:
 		return s.Release(p.(*api.ReleasePayload))
+	case models.HookEventDigest:
+		return s.Digest(p.(*api.DigestPayload))
+	case models.HookEventDeployKey:
+		return s.DeployKey(p.(*api.DeployKeyPayload))
+	case models.HookEventPublicKey:
+		return s.PublicKey(p.(*api.PublicKeyPayload))
 	}
 	return s, nil
 }
@@ -158,6 +158,27 @@ func (s *SlackPayload) IssueComment(p *api.IssueCommentPayload) (api.Payloader,
 	}}), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (s *SlackPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	text := getDigestPayloadInfo(p, SlackLinkFormatter)
+
+	return s.createPayload(text, nil), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (s *SlackPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (s *SlackPayload) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	text, _ := getPublicKeyPayloadInfo(p, SlackLinkFormatter, true)
+
+	return s.createPayload(text, nil), nil
+}
+
 // Release implements PayloadConvertor Release method
 func (s *SlackPayload) Release(p *api.ReleasePayload) (api.Payloader, error) {
 	text, _ := getReleasePayloadInfo(p, SlackLinkFormatter, true)
@@ -156,6 +156,18 @@ func TestSlackPayload(t *testing.T) {
 
 		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Release created: <http://localhost:3000/test/repo/src/v1.0|v1.0> by <https://try.gitea.io/user1|user1>", pl.(*SlackPayload).Text)
 	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(SlackPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &SlackPayload{}, pl)
+
+		assert.Equal(t, "[<http://localhost:3000/test/repo|test/repo>] Activity from 2021-09-01 to 2021-09-08: 3 issues opened, 1 issues closed, 2 pull requests opened, 1 pull requests merged, 1 contributors", pl.(*SlackPayload).Text)
+	})
 }
 
 func TestSlackJSONPayload(t *testing.T) {
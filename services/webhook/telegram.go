@@ -180,6 +180,27 @@ func (t *TelegramPayload) Release(p *api.ReleasePayload) (api.Payloader, error)
 	return createTelegramPayload(text), nil
 }
 
+// Digest implements PayloadConvertor Digest method
+func (t *TelegramPayload) Digest(p *api.DigestPayload) (api.Payloader, error) {
+	text := getDigestPayloadInfo(p, htmlLinkFormatter)
+
+	return createTelegramPayload(text), nil
+}
+
+// DeployKey implements PayloadConvertor DeployKey method
+func (t *TelegramPayload) DeployKey(p *api.DeployKeyPayload) (api.Payloader, error) {
+	text, _ := getDeployKeyPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
+// PublicKey implements PayloadConvertor PublicKey method
+func (t *TelegramPayload) PublicKey(p *api.PublicKeyPayload) (api.Payloader, error) {
+	text, _ := getPublicKeyPayloadInfo(p, htmlLinkFormatter, true)
+
+	return createTelegramPayload(text), nil
+}
+
 // GetTelegramPayload converts a telegram webhook into a TelegramPayload
 func GetTelegramPayload(p api.Payloader, event models.HookEventType, meta string) (api.Payloader, error) {
 	return convertPayloader(new(TelegramPayload), p, event)
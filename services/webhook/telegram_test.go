@@ -156,6 +156,18 @@ func TestTelegramPayload(t *testing.T) {
 
 		assert.Equal(t, `[<a href="http://localhost:3000/test/repo">test/repo</a>] Release created: <a href="http://localhost:3000/test/repo/src/v1.0">v1.0</a> by <a href="https://try.gitea.io/user1">user1</a>`, pl.(*TelegramPayload).Message)
 	})
+
+	t.Run("Digest", func(t *testing.T) {
+		p := digestTestPayload()
+
+		d := new(TelegramPayload)
+		pl, err := d.Digest(p)
+		require.NoError(t, err)
+		require.NotNil(t, pl)
+		require.IsType(t, &TelegramPayload{}, pl)
+
+		assert.Equal(t, `[<a href="http://localhost:3000/test/repo">test/repo</a>] Activity from 2021-09-01 to 2021-09-08: 3 issues opened, 1 issues closed, 2 pull requests opened, 1 pull requests merged, 1 contributors`, pl.(*TelegramPayload).Message)
+	})
 }
 
 func TestTelegramJSONPayload(t *testing.T) {
@@ -15,8 +15,6 @@ import (
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/sync"
 	"code.gitea.io/gitea/modules/util"
-
-	"github.com/gobwas/glob"
 )
 
 type webhook struct {
@@ -78,7 +76,8 @@ func IsValidHookTaskType(name string) bool {
 // hookQueue is a global queue of web hooks
 var hookQueue = sync.NewUniqueQueue(setting.Webhook.QueueLength)
 
-// getPayloadBranch returns branch for hook event, if applicable.
+// getPayloadBranch returns branch for hook event, if applicable: the base branch of pull requests
+// and the target branch of releases.
 func getPayloadBranch(p api.Payloader) string {
 	switch pp := p.(type) {
 	case *api.CreatePayload:
@@ -93,13 +92,21 @@ func getPayloadBranch(p api.Payloader) string {
 		if strings.HasPrefix(pp.Ref, git.BranchPrefix) {
 			return pp.Ref[len(git.BranchPrefix):]
 		}
+	case *api.PullRequestPayload:
+		if pp.PullRequest != nil && pp.PullRequest.Base != nil {
+			return pp.PullRequest.Base.Ref
+		}
+	case *api.ReleasePayload:
+		if pp.Release != nil {
+			return pp.Release.Target
+		}
 	}
 	return ""
 }
 
 // PrepareWebhook adds special webhook to task queue for given payload.
 func PrepareWebhook(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
-	if err := prepareWebhook(w, repo, event, p); err != nil {
+	if err := prepareWebhook(w, repo.ID, event, p); err != nil {
 		return err
 	}
 
@@ -107,22 +114,7 @@ func PrepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 	return nil
 }
 
-func checkBranch(w *models.Webhook, branch string) bool {
-	if w.BranchFilter == "" || w.BranchFilter == "*" {
-		return true
-	}
-
-	g, err := glob.Compile(w.BranchFilter)
-	if err != nil {
-		// should not really happen as BranchFilter is validated
-		log.Error("CheckBranch failed: %s", err)
-		return false
-	}
-
-	return g.Match(branch)
-}
-
-func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.HookEventType, p api.Payloader) error {
+func prepareWebhook(w *models.Webhook, repoID int64, event models.HookEventType, p api.Payloader) error {
 	// Skip sending if webhooks are disabled.
 	if setting.DisableWebhooks {
 		return nil
@@ -149,8 +141,8 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 	// If payload has no associated branch (e.g. it's a new tag, issue, etc.),
 	// branch filter has no effect.
 	if branch := getPayloadBranch(p); branch != "" {
-		if !checkBranch(w, branch) {
-			log.Info("Branch %q doesn't match branch filter %q, skipping", branch, w.BranchFilter)
+		if !w.MatchBranch(branch) {
+			log.Info("Branch %q doesn't match branch filters %q, skipping", branch, w.BranchFilters)
 			return nil
 		}
 	}
@@ -168,7 +160,7 @@ func prepareWebhook(w *models.Webhook, repo *models.Repository, event models.Hoo
 	}
 
 	if err = models.CreateHookTask(&models.HookTask{
-		RepoID:    repo.ID,
+		RepoID:    repoID,
 		HookID:    w.ID,
 		Payloader: payloader,
 		EventType: event,
@@ -222,9 +214,31 @@ func prepareWebhooks(repo *models.Repository, event models.HookEventType, p api.
 	}
 
 	for _, w := range ws {
-		if err = prepareWebhook(w, repo, event, p); err != nil {
+		if err = prepareWebhook(w, repo.ID, event, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PrepareSystemWebhooks adds the admin-defined system webhooks to task queue for given payload of an event
+// which is not related to a repository.
+func PrepareSystemWebhooks(event models.HookEventType, p api.Payloader) error {
+	systemHooks, err := models.GetSystemWebhooks()
+	if err != nil {
+		return fmt.Errorf("GetSystemWebhooks: %v", err)
+	}
+
+	for _, w := range systemHooks {
+		if !w.IsActive {
+			continue
+		}
+		if err = prepareWebhook(w, 0, event, p); err != nil {
 			return err
 		}
 	}
+
+	// the tasks of these events are delivered with the tasks of repository ID 0
+	go hookQueue.Add(int64(0))
 	return nil
 }
@@ -75,6 +75,41 @@ func TestPrepareWebhooksBranchFilterNoMatch(t *testing.T) {
 	}
 }
 
+func TestPrepareWebhookBranchFilters(t *testing.T) {
+	assert.NoError(t, db.PrepareTestDatabase())
+
+	w := db.AssertExistsAndLoadBean(t, &models.Webhook{ID: 4}).(*models.Webhook)
+	w.HookEvent = &models.HookEvent{
+		ChooseEvents:  true,
+		BranchFilters: []string{"releases/*", "!releases/*-rc"},
+		HookEvents: models.HookEvents{
+			Create:      true,
+			PullRequest: true,
+			Release:     true,
+		},
+	}
+
+	for _, c := range []struct {
+		event   models.HookEventType
+		payload api.Payloader
+		sent    bool
+	}{
+		{models.HookEventCreate, &api.CreatePayload{Ref: "releases/1.16", RefType: "branch"}, true},
+		{models.HookEventCreate, &api.CreatePayload{Ref: "releases/1.16-rc", RefType: "branch"}, false},
+		// the filters don't apply to tags
+		{models.HookEventCreate, &api.CreatePayload{Ref: "v1.16.0-rc", RefType: "tag"}, true},
+		{models.HookEventPullRequest, &api.PullRequestPayload{PullRequest: &api.PullRequest{Base: &api.PRBranchInfo{Ref: "releases/1.16"}}}, true},
+		{models.HookEventPullRequest, &api.PullRequestPayload{PullRequest: &api.PullRequest{Base: &api.PRBranchInfo{Ref: "master"}}}, false},
+		{models.HookEventRelease, &api.ReleasePayload{Release: &api.Release{Target: "releases/1.16"}}, true},
+		{models.HookEventRelease, &api.ReleasePayload{Release: &api.Release{Target: "releases/1.16-rc"}}, false},
+	} {
+		before := db.GetCount(t, &models.HookTask{HookID: w.ID})
+		assert.NoError(t, prepareWebhook(w, 2, c.event, c.payload))
+		sent := db.GetCount(t, &models.HookTask{HookID: w.ID}) > before
+		assert.Equal(t, c.sent, sent, "%s %#v", c.event, c.payload)
+	}
+}
+
 // TODO TestHookTask_deliver
 
 // TODO TestDeliverHooks